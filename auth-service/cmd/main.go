@@ -11,13 +11,18 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/redis/go-redis/v9"
 
 	"augustberries/auth-service/internal/app/auth/config"
 	"augustberries/auth-service/internal/app/auth/handler"
+	"augustberries/auth-service/internal/app/auth/infrastructure"
 	"augustberries/auth-service/internal/app/auth/repository"
 	"augustberries/auth-service/internal/app/auth/service"
 	"augustberries/auth-service/internal/app/auth/util"
+	"augustberries/pkg/authcookie"
+	"augustberries/pkg/kafka"
+	"augustberries/pkg/openapi"
+	"augustberries/pkg/recovery"
+	"augustberries/pkg/redis"
 )
 
 func main() {
@@ -27,6 +32,22 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Новые пароли хэшируются алгоритмом из конфигурации, старые хэши пересчитываются при входе
+	if err := util.ConfigurePasswordHashing(cfg.Passwords.Policy()); err != nil {
+		log.Fatalf("Failed to configure password hashing: %v", err)
+	}
+
+	// Паники в обработчиках и фоновых задачах отправляются в Sentry, если задан SENTRY_DSN
+	if err := recovery.ConfigureFromEnv(); err != nil {
+		log.Fatalf("Failed to configure panic reporting: %v", err)
+	}
+
+	// Проверка запросов и ответов по OpenAPI спецификации, только для dev и staging (OPENAPI_VALIDATION)
+	contract, err := openapi.FromEnv("auth-service")
+	if err != nil {
+		log.Fatalf("Failed to configure OpenAPI validation: %v", err)
+	}
+
 	// Подключаемся к базе данных PostgreSQL
 	db, err := connectDB(context.Background(), cfg.Database)
 	if err != nil {
@@ -37,7 +58,10 @@ func main() {
 	log.Println("Successfully connected to PostgreSQL database")
 
 	// Подключаемся к Redis
-	redisClient := connectRedis(cfg.Redis)
+	redisClient, err := connectRedis(cfg.Redis)
+	if err != nil {
+		log.Fatalf("Failed to create Redis client: %v", err)
+	}
 	defer redisClient.Close()
 
 	// Проверяем соединение с Redis
@@ -56,28 +80,109 @@ func main() {
 		cfg.JWT.AccessTokenDuration,
 		cfg.JWT.RefreshTokenDuration,
 	)
+	jwtManager.SetRememberMePolicy(util.RememberMePolicy{
+		TokenDuration: cfg.JWT.RememberMeDuration,
+		MaxSessionAge: cfg.JWT.RememberMeMaxAge,
+	})
+	jwtManager.SetGuestTokenDuration(cfg.JWT.GuestTokenDuration)
+	jwtManager.SetImpersonationTokenDuration(cfg.JWT.ImpersonationTokenDuration)
+	// iss и aud отличают токены этого окружения; остальные сервисы настраиваются теми же JWT_ISSUER и JWT_AUDIENCE
+	jwtManager.SetIssuerAudience(cfg.JWT.Issuer, cfg.JWT.Audience)
+
+	// Kafka producer отправляет события пользователей в топик user_events
+	kafkaProducer, err := kafka.NewProducer(kafka.ProducerConfig{
+		Brokers:     cfg.Kafka.Brokers,
+		Topic:       cfg.Kafka.Topic,
+		Service:     "auth-service",
+		Compression: cfg.Kafka.Compression,
+		BatchSize:   cfg.Kafka.BatchSize,
+		BatchBytes:  cfg.Kafka.BatchBytes,
+		Linger:      cfg.Kafka.Linger,
+		Acks:        cfg.Kafka.Acks,
+		Idempotent:  cfg.Kafka.Idempotent,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create Kafka producer: %v", err)
+	}
+	defer kafkaProducer.Close()
+
+	log.Println("Successfully initialized Kafka producer")
 
 	// Инициализируем репозитории
 	userRepo := repository.NewUserRepository(db)
 	roleRepo := repository.NewRoleRepository(db)
 
+	// Базовые роли и разрешения создаются при первом запуске; существующие роли не меняются
+	if cfg.SeedRoles {
+		if err := service.NewRoleSeeder(roleRepo).Seed(context.Background()); err != nil {
+			log.Fatalf("Failed to seed roles: %v", err)
+		}
+	}
+
 	// Используем Redis для хранения токенов вместо PostgreSQL
 	tokenRepo := repository.NewRedisTokenRepository(redisClient)
 
+	// Проверка входа: устройства в PostgreSQL, коды подтверждения и неудачные входы в Redis
+	loginAttemptRepo := repository.NewRedisLoginAttemptRepository(redisClient)
+	geoLocator, err := util.LoadGeoLocator(cfg.Security.GeoIPPath)
+	if err != nil {
+		log.Fatalf("Failed to load GeoIP database: %v", err)
+	}
+	loginSecurity := service.NewLoginSecurity(
+		repository.NewDeviceRepository(db),
+		repository.NewRedisLoginChallengeRepository(redisClient),
+		loginAttemptRepo,
+		geoLocator,
+		infrastructure.NewLogEmailSender(),
+		service.LoginSecurityConfig{
+			ChallengeTTL:      cfg.Security.ChallengeTTL,
+			MaxAttempts:       cfg.Security.MaxAttempts,
+			MaxTravelSpeedKmh: cfg.Security.MaxTravelSpeedKmh,
+			LockoutThreshold:  cfg.Security.LockoutThreshold,
+			LockoutWindow:     cfg.Security.LockoutWindow,
+			LockoutDuration:   cfg.Security.LockoutDuration,
+		},
+	)
+
 	// Инициализируем сервисы
-	authService := service.NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager)
+	authService := service.NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager, kafkaProducer, loginSecurity)
+	// Политика новых паролей: длина, стойкость, личные данные и база утечек (PASSWORD_BREACH_API_URL)
+	var breachedPasswords infrastructure.BreachedPasswordChecker
+	if cfg.Passwords.BreachAPIURL != "" {
+		breachedPasswords = infrastructure.NewHIBPClient(cfg.Passwords.BreachAPIURL, cfg.Passwords.BreachTimeout)
+	}
+	authService.SetPasswordPolicy(service.NewPasswordPolicy(service.PasswordPolicyConfig{
+		MinLength:          cfg.Passwords.MinLength,
+		MinScore:           cfg.Passwords.MinScore,
+		ForbidPersonalInfo: cfg.Passwords.ForbidPersonalInfo,
+	}, breachedPasswords))
+	// Каждая выдача токена имперсонации записывается в журнал impersonation_audit
+	authService.SetImpersonationAudit(repository.NewImpersonationAuditRepository(db))
+	securityService := service.NewSecurityService(userRepo, tokenRepo, loginAttemptRepo)
+	provisioningService := service.NewProvisioningService(userRepo, roleRepo, tokenRepo, kafkaProducer)
 
 	// Инициализируем обработчики
 	authHandler := handler.NewAuthHandler(authService)
+	securityHandler := handler.NewSecurityHandler(securityService)
+	provisioningHandler := handler.NewProvisioningHandler(provisioningService)
+	roleHandler := handler.NewRoleHandler(service.NewRoleService(roleRepo))
+	introspectionHandler := handler.NewIntrospectionHandler(authService, cfg.Introspection.Clients)
 	authMiddleware := handler.NewAuthMiddleware(authService)
+	// Токен из httpOnly cookie для SPA (AUTH_COOKIES); изменяющие запросы с cookie требуют X-CSRF-Token
+	cookies, err := authcookie.FromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure auth cookies: %v", err)
+	}
+	authMiddleware.SetCookies(cookies)
+	authHandler.SetCookies(cookies)
 
 	// Настраиваем маршруты с Gin router
-	router := handler.SetupRoutes(authHandler, authMiddleware)
+	router := handler.SetupRoutes(authHandler, securityHandler, provisioningHandler, roleHandler, introspectionHandler, authMiddleware, cfg.CORS)
 
 	// Создаем HTTP сервер
 	server := &http.Server{
 		Addr:         cfg.Server.Address(),
-		Handler:      router,
+		Handler:      contract.Wrap(router),
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
@@ -153,17 +258,14 @@ func connectDB(ctx context.Context, cfg config.DatabaseConfig) (*pgxpool.Pool, e
 }
 
 // connectRedis создает и настраивает Redis клиент
-func connectRedis(cfg config.RedisConfig) *redis.Client {
-	client := redis.NewClient(&redis.Options{
-		Addr:         cfg.Address(),
-		Password:     cfg.Password,
-		DB:           cfg.DB,
-		DialTimeout:  5 * time.Second,
-		ReadTimeout:  3 * time.Second,
-		WriteTimeout: 3 * time.Second,
-		PoolSize:     10,
-		MinIdleConns: 5,
-	})
+// Режим подключения (standalone, sentinel, cluster) и TLS задаются конфигурацией
+func connectRedis(cfg config.RedisConfig) (redis.Client, error) {
+	clientCfg := cfg.ClientConfig()
+	clientCfg.DialTimeout = 5 * time.Second
+	clientCfg.ReadTimeout = 3 * time.Second
+	clientCfg.WriteTimeout = 3 * time.Second
+	clientCfg.PoolSize = 10
+	clientCfg.MinIdleConns = 5
 
-	return client
+	return redis.NewClient(clientCfg)
 }
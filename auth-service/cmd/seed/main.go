@@ -0,0 +1,102 @@
+// Команда seed создает синтетических пользователей для нагрузочного тестирования
+// Пользователи пишутся напрямую через репозиторий с ролью user и общим паролем seed.Password;
+// их ID совпадают с user_id заказов, созданных orders-service/cmd/seed с тем же seed
+//
+//	go run ./auth-service/cmd/seed -seed 42 -scale medium
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"augustberries/auth-service/internal/app/auth/config"
+	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/auth-service/internal/app/auth/repository"
+	"augustberries/auth-service/internal/app/auth/util"
+	"augustberries/pkg/seed"
+)
+
+func main() {
+	opts, err := seed.ParseFlags()
+	if err != nil {
+		log.Fatalf("Invalid arguments: %v", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	if err := util.ConfigurePasswordHashing(cfg.Passwords.Policy()); err != nil {
+		log.Fatalf("Failed to configure password hashing: %v", err)
+	}
+
+	ctx := context.Background()
+	db, err := connectDB(ctx, cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	role, err := repository.NewRoleRepository(db).GetByName(ctx, "user")
+	if err != nil {
+		log.Fatalf("Failed to get default role: %v", err)
+	}
+
+	// Хэширование паролей медленное, поэтому хеш общего пароля считается один раз
+	passwordHash, err := util.HashPassword(seed.Password)
+	if err != nil {
+		log.Fatalf("Failed to hash password: %v", err)
+	}
+
+	userRepo := repository.NewUserRepository(db)
+	gen := opts.Generator()
+	progress := seed.NewProgress("users", gen.Scale().Users)
+	for i := 0; i < gen.Scale().Users; i++ {
+		u := gen.User(i)
+		_, err := userRepo.GetByID(ctx, u.ID)
+		if err == nil {
+			progress.Skipped()
+			continue
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			log.Fatalf("Failed to check user %s: %v", u.Email, err)
+		}
+
+		user := &entity.User{
+			ID:           u.ID,
+			Email:        u.Email,
+			PasswordHash: passwordHash,
+			Name:         u.Name,
+			RoleID:       role.ID,
+			TenantID:     opts.Tenant,
+			CreatedAt:    u.CreatedAt,
+		}
+		if err := userRepo.Create(ctx, user); err != nil {
+			log.Fatalf("Failed to create user %s: %v", u.Email, err)
+		}
+		progress.Created()
+	}
+	progress.Done()
+}
+
+// connectDB подключается к PostgreSQL сервиса авторизации
+func connectDB(ctx context.Context, cfg config.DatabaseConfig) (*pgxpool.Pool, error) {
+	connString := fmt.Sprintf(
+		"postgres://%s:%s@%s:%s/%s?sslmode=%s",
+		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.DBName, cfg.SSLMode,
+	)
+	pool, err := pgxpool.New(ctx, connString)
+	if err != nil {
+		return nil, err
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return pool, nil
+}
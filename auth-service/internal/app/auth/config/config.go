@@ -1,18 +1,33 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"os"
 	"strconv"
+	"strings"
 	"time"
+
+	"augustberries/auth-service/internal/app/auth/util"
+	"augustberries/pkg/httpmw"
+	"augustberries/pkg/redis"
 )
 
 // Config содержит все настройки приложения
 type Config struct {
 	Server   ServerConfig
+	CORS     httpmw.CORSConfig
 	Database DatabaseConfig
 	Redis    RedisConfig
 	JWT      JWTConfig
+	Kafka    KafkaConfig
+	Security SecurityConfig
+
+	Introspection IntrospectionConfig
+	Passwords     PasswordsConfig
+
+	SeedRoles bool // Создавать базовые роли и разрешения при запуске (SEED_ROLES)
 }
 
 // ServerConfig - настройки HTTP сервера
@@ -33,10 +48,14 @@ type DatabaseConfig struct {
 
 // RedisConfig - настройки подключения к Redis
 type RedisConfig struct {
-	Host     string
-	Port     string
-	Password string
-	DB       int
+	Host       string
+	Port       string
+	Password   string
+	DB         int
+	Mode       string   // Режим подключения: standalone, sentinel или cluster
+	Addrs      []string // Адреса Sentinel или узлов кластера (по умолчанию Host:Port)
+	MasterName string   // Имя master для Sentinel
+	TLS        bool     // Подключаться по TLS
 }
 
 // JWTConfig - настройки для JWT токенов
@@ -44,6 +63,71 @@ type JWTConfig struct {
 	SecretKey            string
 	AccessTokenDuration  time.Duration
 	RefreshTokenDuration time.Duration
+
+	// "Запомнить меня": скользящий срок refresh токена и абсолютный предел сессии
+	RememberMeDuration time.Duration
+	RememberMeMaxAge   time.Duration
+
+	GuestTokenDuration time.Duration // Срок жизни токена гостевого оформления заказа
+
+	ImpersonationTokenDuration time.Duration // Срок жизни токена сотрудника поддержки от имени пользователя
+
+	// Issuer и Audience записываются в iss и aud токенов; сервисы принимают только токены со своими значениями
+	Issuer   string
+	Audience string
+}
+
+// KafkaConfig - настройки Kafka для отправки событий пользователей
+// USER_REGISTERED, USER_LOGGED_IN, PASSWORD_CHANGED, ROLE_CHANGED
+type KafkaConfig struct {
+	Brokers     []string      // Список брокеров Kafka (формат: host:port)
+	Topic       string        // Топик событий пользователей
+	Compression string        // Кодек сжатия: none, gzip, snappy, lz4, zstd
+	BatchSize   int           // Максимум сообщений в батче
+	BatchBytes  int64         // Максимальный размер батча в байтах
+	Linger      time.Duration // Время ожидания заполнения батча перед отправкой
+	Acks        string        // Уровень подтверждения записи: none, one, all
+	Idempotent  bool          // Идемпотентный режим (acks=all, порядок по ключу)
+}
+
+// SecurityConfig - настройки проверки входа с новых устройств и из необычных мест
+type SecurityConfig struct {
+	ChallengeTTL      time.Duration // Срок действия кода подтверждения входа
+	MaxAttempts       int           // Попыток ввода кода
+	MaxTravelSpeedKmh float64       // Скорость перемещения между входами, выше которой вход подозрителен
+	GeoIPPath         string        // CSV с диапазонами IP (cidr,country,city,lat,lon), пусто - без геолокации
+
+	LockoutThreshold int           // Неудачных входов до блокировки аккаунта, 0 - без блокировки
+	LockoutWindow    time.Duration // Окно подсчета неудачных входов
+	LockoutDuration  time.Duration // Срок автоматической блокировки
+}
+
+// IntrospectionConfig - клиенты, которым разрешена интроспекция токенов (RFC 7662)
+type IntrospectionConfig struct {
+	Clients map[string]string // client_id -> client_secret, пусто - интроспекция отключена
+}
+
+// PasswordsConfig - алгоритм и параметры хэширования паролей
+// При смене алгоритма или параметров хэш пересчитывается при следующем входе пользователя
+type PasswordsConfig struct {
+	Algorithm string // bcrypt, argon2id или scrypt
+
+	BcryptCost int
+
+	Argon2MemoryKB    int
+	Argon2Iterations  int
+	Argon2Parallelism int
+
+	ScryptN int
+	ScryptR int
+	ScryptP int
+
+	// Требования к новым паролям при регистрации и смене пароля
+	MinLength          int
+	MinScore           int           // Оценка стойкости 0-4, 0 - без проверки
+	ForbidPersonalInfo bool          // Запрет частей email и имени
+	BreachAPIURL       string        // API диапазонов Have I Been Pwned или его зеркало, пусто - без проверки
+	BreachTimeout      time.Duration // Таймаут запроса к BreachAPIURL
 }
 
 // Load загружает конфигурацию из переменных окружения
@@ -59,11 +143,152 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid JWT_REFRESH_DURATION: %w", err)
 	}
 
+	rememberMeDuration, err := time.ParseDuration(getEnv("JWT_REMEMBER_ME_DURATION", "720h")) // 30 дней
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_REMEMBER_ME_DURATION: %w", err)
+	}
+
+	rememberMeMaxAge, err := time.ParseDuration(getEnv("JWT_REMEMBER_ME_MAX_AGE", "2160h")) // 90 дней
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_REMEMBER_ME_MAX_AGE: %w", err)
+	}
+
+	guestDuration, err := time.ParseDuration(getEnv("JWT_GUEST_DURATION", "24h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_GUEST_DURATION: %w", err)
+	}
+
+	impersonationDuration, err := time.ParseDuration(getEnv("JWT_IMPERSONATION_DURATION", "15m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_IMPERSONATION_DURATION: %w", err)
+	}
+
+	// Настройки Kafka producer: по умолчанию snappy, небольшие батчи и подтверждение всеми репликами
+	kafkaBatchSize, err := strconv.Atoi(getEnv("KAFKA_BATCH_SIZE", "100"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAFKA_BATCH_SIZE value: %w", err)
+	}
+
+	kafkaBatchBytes, err := strconv.ParseInt(getEnv("KAFKA_BATCH_BYTES", "1048576"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAFKA_BATCH_BYTES value: %w", err)
+	}
+
+	kafkaLinger, err := time.ParseDuration(getEnv("KAFKA_LINGER", "10ms"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAFKA_LINGER value: %w", err)
+	}
+
+	kafkaIdempotent, err := strconv.ParseBool(getEnv("KAFKA_IDEMPOTENT", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAFKA_IDEMPOTENT value: %w", err)
+	}
+
+	// Настройки проверки входа: 900 км/ч - скорость пассажирского самолета
+	challengeTTL, err := time.ParseDuration(getEnv("LOGIN_CHALLENGE_TTL", "10m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOGIN_CHALLENGE_TTL: %w", err)
+	}
+
+	maxTravelSpeed, err := strconv.ParseFloat(getEnv("LOGIN_MAX_TRAVEL_SPEED_KMH", "900"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOGIN_MAX_TRAVEL_SPEED_KMH value: %w", err)
+	}
+
+	lockoutWindow, err := time.ParseDuration(getEnv("LOGIN_LOCKOUT_WINDOW", "15m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOGIN_LOCKOUT_WINDOW: %w", err)
+	}
+
+	lockoutDuration, err := time.ParseDuration(getEnv("LOGIN_LOCKOUT_DURATION", "30m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOGIN_LOCKOUT_DURATION: %w", err)
+	}
+
+	// Клиенты интроспекции: INTROSPECTION_CLIENTS="gateway:secret1,billing:secret2"
+	introspectionClients, err := parseClients(getEnv("INTROSPECTION_CLIENTS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid INTROSPECTION_CLIENTS: %w", err)
+	}
+
+	breachTimeout, err := time.ParseDuration(getEnv("PASSWORD_BREACH_TIMEOUT", "2s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PASSWORD_BREACH_TIMEOUT: %w", err)
+	}
+
+	seedRoles, err := strconv.ParseBool(getEnv("SEED_ROLES", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SEED_ROLES value: %w", err)
+	}
+
+	forbidPersonalInfo, err := strconv.ParseBool(getEnv("PASSWORD_FORBID_PERSONAL_INFO", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PASSWORD_FORBID_PERSONAL_INFO value: %w", err)
+	}
+
+	// Пароли: bcrypt по умолчанию, параметры argon2id и scrypt - рекомендации OWASP
+	passwords := PasswordsConfig{
+		Algorithm:         getEnv("PASSWORD_HASH_ALGORITHM", util.PasswordAlgorithmBcrypt),
+		BcryptCost:        getEnvInt("PASSWORD_BCRYPT_COST", 10),
+		Argon2MemoryKB:    getEnvInt("PASSWORD_ARGON2_MEMORY_KB", 65536),
+		Argon2Iterations:  getEnvInt("PASSWORD_ARGON2_ITERATIONS", 3),
+		Argon2Parallelism: getEnvInt("PASSWORD_ARGON2_PARALLELISM", 2),
+		ScryptN:           getEnvInt("PASSWORD_SCRYPT_N", 32768),
+		ScryptR:           getEnvInt("PASSWORD_SCRYPT_R", 8),
+		ScryptP:           getEnvInt("PASSWORD_SCRYPT_P", 1),
+
+		MinLength:          getEnvInt("PASSWORD_MIN_LENGTH", 8),
+		MinScore:           getEnvInt("PASSWORD_MIN_SCORE", 2),
+		ForbidPersonalInfo: forbidPersonalInfo,
+		BreachAPIURL:       getEnv("PASSWORD_BREACH_API_URL", ""),
+		BreachTimeout:      breachTimeout,
+	}
+	if passwords.MinScore < 0 || passwords.MinScore > 4 {
+		return nil, fmt.Errorf("invalid PASSWORD_MIN_SCORE value: must be between 0 and 4")
+	}
+	// Параметры argon2id передаются как uint32/uint8: значения вне диапазона отклоняются, а не обрезаются
+	if passwords.Argon2MemoryKB < 0 || int64(passwords.Argon2MemoryKB) > math.MaxUint32 {
+		return nil, fmt.Errorf("invalid PASSWORD_ARGON2_MEMORY_KB value: must be between 0 and %d", uint32(math.MaxUint32))
+	}
+	if passwords.Argon2Iterations < 0 || int64(passwords.Argon2Iterations) > math.MaxUint32 {
+		return nil, fmt.Errorf("invalid PASSWORD_ARGON2_ITERATIONS value: must be between 0 and %d", uint32(math.MaxUint32))
+	}
+	if passwords.Argon2Parallelism < 0 || passwords.Argon2Parallelism > math.MaxUint8 {
+		return nil, fmt.Errorf("invalid PASSWORD_ARGON2_PARALLELISM value: must be between 0 and %d", math.MaxUint8)
+	}
+	if _, err := util.NewPasswordHashing(passwords.Policy()); err != nil {
+		return nil, fmt.Errorf("invalid password hashing settings: %w", err)
+	}
+
+	// CORS для браузерных клиентов; credentials нужны SPA с токенами в cookie и требуют явного списка origin
+	corsMaxAge, err := time.ParseDuration(getEnv("CORS_MAX_AGE", "5m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CORS_MAX_AGE value: %w", err)
+	}
+
+	corsCredentials, err := strconv.ParseBool(getEnv("CORS_ALLOW_CREDENTIALS", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CORS_ALLOW_CREDENTIALS value: %w", err)
+	}
+
+	cors := httpmw.CORSConfig{
+		AllowedOrigins:   httpmw.ParseList(getEnv("CORS_ALLOWED_ORIGINS", "*")),
+		AllowedMethods:   httpmw.ParseList(getEnv("CORS_ALLOWED_METHODS", "")),
+		AllowedHeaders:   httpmw.ParseList(getEnv("CORS_ALLOWED_HEADERS", "")),
+		ExposedHeaders:   httpmw.ParseList(getEnv("CORS_EXPOSED_HEADERS", "")),
+		AllowCredentials: corsCredentials,
+		MaxAge:           corsMaxAge,
+	}
+	if err := cors.Validate(); err != nil {
+		return nil, err
+	}
+
 	return &Config{
 		Server: ServerConfig{
 			Host: getEnv("SERVER_HOST", "0.0.0.0"),
 			Port: getEnv("SERVER_PORT", "8080"),
 		},
+		CORS: cors,
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
 			Port:     getEnv("DB_PORT", "5432"),
@@ -73,16 +298,52 @@ func Load() (*Config, error) {
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		},
 		Redis: RedisConfig{
-			Host:     getEnv("REDIS_HOST", "localhost"),
-			Port:     getEnv("REDIS_PORT", "6379"),
-			Password: getEnv("REDIS_PASSWORD", ""),
-			DB:       getEnvInt("REDIS_DB", 0),
+			Host:       getEnv("REDIS_HOST", "localhost"),
+			Port:       getEnv("REDIS_PORT", "6379"),
+			Password:   getEnv("REDIS_PASSWORD", ""),
+			DB:         getEnvInt("REDIS_DB", 0),
+			Mode:       getEnv("REDIS_MODE", redis.ModeStandalone),
+			Addrs:      redis.ParseAddrs(getEnv("REDIS_ADDRS", ""), getEnv("REDIS_HOST", "localhost")+":"+getEnv("REDIS_PORT", "6379")),
+			MasterName: getEnv("REDIS_MASTER_NAME", ""),
+			TLS:        getEnv("REDIS_TLS", "false") == "true",
 		},
 		JWT: JWTConfig{
 			SecretKey:            getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
 			AccessTokenDuration:  accessDuration,
 			RefreshTokenDuration: refreshDuration,
+			RememberMeDuration:   rememberMeDuration,
+			RememberMeMaxAge:     rememberMeMaxAge,
+			GuestTokenDuration:   guestDuration,
+
+			ImpersonationTokenDuration: impersonationDuration,
+
+			Issuer:   getEnv("JWT_ISSUER", ""),
+			Audience: getEnv("JWT_AUDIENCE", ""),
+		},
+		Kafka: KafkaConfig{
+			Brokers:     []string{getEnv("KAFKA_BROKERS", "localhost:9092")},
+			Topic:       getEnv("KAFKA_TOPIC", "user_events"),
+			Compression: getEnv("KAFKA_COMPRESSION", "snappy"),
+			BatchSize:   kafkaBatchSize,
+			BatchBytes:  kafkaBatchBytes,
+			Linger:      kafkaLinger,
+			Acks:        getEnv("KAFKA_ACKS", "all"),
+			Idempotent:  kafkaIdempotent,
+		},
+		Security: SecurityConfig{
+			ChallengeTTL:      challengeTTL,
+			MaxAttempts:       getEnvInt("LOGIN_CHALLENGE_MAX_ATTEMPTS", 5),
+			MaxTravelSpeedKmh: maxTravelSpeed,
+			GeoIPPath:         getEnv("GEOIP_CSV_PATH", ""),
+			LockoutThreshold:  getEnvInt("LOGIN_LOCKOUT_THRESHOLD", 5),
+			LockoutWindow:     lockoutWindow,
+			LockoutDuration:   lockoutDuration,
 		},
+		Introspection: IntrospectionConfig{
+			Clients: introspectionClients,
+		},
+		Passwords: passwords,
+		SeedRoles: seedRoles,
 	}, nil
 }
 
@@ -99,11 +360,60 @@ func (c *RedisConfig) Address() string {
 	return c.Host + ":" + c.Port
 }
 
+// ClientConfig возвращает настройки клиента для pkg/redis
+func (c *RedisConfig) ClientConfig() redis.Config {
+	return redis.Config{
+		Mode:       c.Mode,
+		Addrs:      c.Addrs,
+		Password:   c.Password,
+		DB:         c.DB,
+		MasterName: c.MasterName,
+		TLS:        c.TLS,
+		Service:    "auth-service",
+	}
+}
+
+// Policy возвращает политику хэширования для util.ConfigurePasswordHashing
+// Диапазоны параметров argon2id проверяет Load, остальные значения отклоняет util.NewPasswordHashing
+func (c *PasswordsConfig) Policy() util.PasswordPolicy {
+	return util.PasswordPolicy{
+		Algorithm:         c.Algorithm,
+		BcryptCost:        c.BcryptCost,
+		Argon2Memory:      uint32(c.Argon2MemoryKB),
+		Argon2Iterations:  uint32(c.Argon2Iterations),
+		Argon2Parallelism: uint8(c.Argon2Parallelism),
+		ScryptN:           c.ScryptN,
+		ScryptR:           c.ScryptR,
+		ScryptP:           c.ScryptP,
+	}
+}
+
 // Address возвращает адрес сервера в формате host:port
 func (c *ServerConfig) Address() string {
 	return c.Host + ":" + c.Port
 }
 
+// parseClients разбирает список "id:secret" через запятую
+func parseClients(value string) (map[string]string, error) {
+	clients := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, secret, ok := strings.Cut(pair, ":")
+		if !ok || id == "" || secret == "" {
+			// Значение не выводим: в нем может оказаться секрет
+			return nil, errors.New("clients must be in id:secret format")
+		}
+		if _, exists := clients[id]; exists {
+			return nil, fmt.Errorf("duplicate client %q", id)
+		}
+		clients[id] = secret
+	}
+	return clients, nil
+}
+
 // getEnv получает значение переменной окружения или возвращает значение по умолчанию
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
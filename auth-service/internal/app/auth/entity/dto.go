@@ -1,5 +1,12 @@
 package entity
 
+import (
+	"time"
+
+	"augustberries/pkg/impersonation"
+	"augustberries/pkg/patch"
+)
+
 // RegisterRequest - запрос на регистрацию
 type RegisterRequest struct {
 	Email    string `json:"email" validate:"required,email"`
@@ -11,6 +18,42 @@ type RegisterRequest struct {
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
+	DeviceID string `json:"device_id,omitempty" validate:"omitempty,max=200"` // Постоянный идентификатор установки клиента
+
+	// RememberMe выдает долгоживущий refresh токен со скользящим сроком вместо обычного
+	RememberMe bool `json:"remember_me,omitempty"`
+
+	// Заполняются handler из запроса для проверки входа
+	IP        string `json:"-"`
+	UserAgent string `json:"-"`
+}
+
+// VerifyLoginRequest - подтверждение подозрительного входа кодом из письма
+type VerifyLoginRequest struct {
+	ChallengeID string `json:"challenge_id" validate:"required"`
+	Code        string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// LoginChallengeResponse - ответ на вход, требующий подтверждения по email
+type LoginChallengeResponse struct {
+	VerificationRequired bool   `json:"verification_required"`
+	ChallengeID          string `json:"challenge_id"`
+	Reason               string `json:"reason"`     // new_device, impossible_travel
+	ExpiresIn            int64  `json:"expires_in"` // Срок действия кода в секундах
+}
+
+// GuestSessionRequest - запрос гостевой сессии для оформления заказа без регистрации
+type GuestSessionRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// GuestSessionResponse - гостевой токен
+// Refresh токен не выдается: по истечении срока гость запрашивает новую сессию
+type GuestSessionResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	GuestID     string `json:"guest_id"`
+	ExpiresIn   int64  `json:"expires_in"` // Срок действия токена в секундах
 }
 
 // RefreshRequest - запрос на обновление токена
@@ -24,6 +67,33 @@ type AuthResponse struct {
 	Tokens TokenPair    `json:"tokens"`
 }
 
+// ImpersonationResponse - токен сотрудника поддержки от имени пользователя
+// Refresh токена нет: по истечении срока токен выпускается заново
+type ImpersonationResponse struct {
+	AccessToken  string                     `json:"access_token"`
+	ExpiresIn    int64                      `json:"expires_in"` // время жизни токена в секундах
+	ExpiresAt    time.Time                  `json:"expires_at"`
+	User         UserWithRole               `json:"user"`
+	Impersonator impersonation.Impersonator `json:"impersonator"`
+}
+
+// IntrospectionResponse - ответ интроспекции токена в формате RFC 7662
+// Для неактивного токена заполняется только Active, чтобы не раскрывать причину
+type IntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"` // Разрешения через пробел
+	Username  string `json:"username,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+	Iat       int64  `json:"iat,omitempty"`
+	Nbf       int64  `json:"nbf,omitempty"`
+	Sub       string `json:"sub,omitempty"`
+
+	// Расширения: данные, по которым сервисы проверяют доступ
+	Role     string `json:"role,omitempty"`
+	TenantID string `json:"tenant_id,omitempty"`
+}
+
 // ErrorResponse - стандартный ответ об ошибке
 type ErrorResponse struct {
 	Error   string `json:"error"`
@@ -36,11 +106,19 @@ type SuccessResponse struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
-// UpdateUserRequest - запрос на обновление пользователя
+// UpdateUserRequest - частичное обновление пользователя (JSON Merge Patch)
 type UpdateUserRequest struct {
-	Name   string `json:"name,omitempty"`
-	Email  string `json:"email,omitempty" validate:"omitempty,email"`
-	RoleID int    `json:"role_id,omitempty"`
+	Name   patch.Field[string] `json:"name,omitzero" validate:"omitempty,min=2"`
+	Email  patch.Field[string] `json:"email,omitzero" validate:"omitempty,email"`
+	RoleID patch.Field[int]    `json:"role_id,omitzero"`
+}
+
+// UpdateProfileRequest - изменение публичного профиля (PATCH /auth/me)
+// Отсутствующие поля не меняются, пустые avatar_url и preferred_currency удаляют значение
+type UpdateProfileRequest struct {
+	Name              *string `json:"name" validate:"omitempty,min=2,max=100"`
+	AvatarURL         *string `json:"avatar_url" validate:"omitempty,max=500"`
+	PreferredCurrency *string `json:"preferred_currency" validate:"omitempty,currency"` // Код ISO 4217
 }
 
 // UpdatePasswordRequest - запрос на обновление пароля
@@ -49,16 +127,36 @@ type UpdatePasswordRequest struct {
 	NewPassword string `json:"new_password" validate:"required,min=8"`
 }
 
+// Коды нарушений политики паролей
+const (
+	PasswordViolationTooShort     = "too_short"
+	PasswordViolationTooWeak      = "too_weak"
+	PasswordViolationPersonalInfo = "contains_personal_info"
+	PasswordViolationBreached     = "breached"
+)
+
+// PasswordViolation - нарушение политики паролей в ответе регистрации и смены пароля
+type PasswordViolation struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
 // CreateRoleRequest - запрос на создание роли
 type CreateRoleRequest struct {
 	Name        string `json:"name" validate:"required"`
 	Description string `json:"description"`
 }
 
-// UpdateRoleRequest - запрос на обновление роли
+// UpdateRoleRequest - частичное обновление роли (JSON Merge Patch), null удаляет описание
 type UpdateRoleRequest struct {
-	Name        string `json:"name,omitempty"`
-	Description string `json:"description,omitempty"`
+	Name        patch.Field[string]    `json:"name,omitzero" validate:"omitempty,min=1"`
+	Description patch.Nullable[string] `json:"description,omitzero"`
+}
+
+// CloneRoleTemplateRequest - создание роли из шаблона (POST /admin/role-templates/:template/clone)
+type CloneRoleTemplateRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description"` // Пусто - описание шаблона
 }
 
 // AssignPermissionsRequest - запрос на назначение разрешений
@@ -71,3 +169,53 @@ type CreatePermissionRequest struct {
 	Code        string `json:"code" validate:"required"`
 	Description string `json:"description"`
 }
+
+// LockAccountRequest - ручная блокировка аккаунта администратором
+type LockAccountRequest struct {
+	Duration string `json:"duration" validate:"required"` // Go duration: "30m", "24h"
+}
+
+// ProvisionUsersRequest - пакетная синхронизация аккаунтов сотрудников (POST /admin/users/bulk)
+// Строки обрабатываются независимо: ошибка в одной строке не отменяет остальные
+type ProvisionUsersRequest struct {
+	Users []ProvisionUser `json:"users"`
+}
+
+// ProvisionUser - строка провижининга, пользователь определяется по email
+// Отсутствующие поля существующего пользователя не меняются
+type ProvisionUser struct {
+	Email    string `json:"email"`
+	Name     string `json:"name,omitempty"`     // Обязательно при создании
+	Role     string `json:"role,omitempty"`     // Имя роли; новым пользователям по умолчанию "user"
+	Password string `json:"password,omitempty"` // Без пароля вход невозможен до его установки
+	Active   *bool  `json:"active,omitempty"`   // false отключает аккаунт, true включает обратно
+}
+
+// AssignTenantRoleRequest - роль пользователя в магазине администратора (PUT /admin/users/:user_id/tenant-role)
+type AssignTenantRoleRequest struct {
+	Role string `json:"role" validate:"required"` // Имя роли
+}
+
+// Статусы строк провижининга
+const (
+	ProvisionCreated     = "created"
+	ProvisionUpdated     = "updated"
+	ProvisionDeactivated = "deactivated"
+	ProvisionUnchanged   = "unchanged"
+	ProvisionFailed      = "error"
+)
+
+// ProvisionResult - результат обработки одной строки
+type ProvisionResult struct {
+	Index  int    `json:"index"` // Позиция строки в запросе
+	Email  string `json:"email"`
+	UserID string `json:"user_id,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ProvisionUsersResponse - построчные результаты и сводка по статусам
+type ProvisionUsersResponse struct {
+	Results []ProvisionResult `json:"results"`
+	Summary map[string]int    `json:"summary"`
+}
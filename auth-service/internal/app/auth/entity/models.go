@@ -8,12 +8,22 @@ import (
 
 // User представляет пользователя в системе
 type User struct {
-	ID           uuid.UUID `json:"id" db:"id"`
-	Email        string    `json:"email" db:"email"`
-	PasswordHash string    `json:"-" db:"password_hash"` // не возвращаем в JSON
-	Name         string    `json:"name" db:"name"`
-	RoleID       int       `json:"role_id" db:"role_id"`
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	ID            uuid.UUID  `json:"id" db:"id"`
+	Email         string     `json:"email" db:"email"`
+	PasswordHash  string     `json:"-" db:"password_hash"` // не возвращаем в JSON
+	Name          string     `json:"name" db:"name"`
+	AvatarURL     string     `json:"avatar_url,omitempty" db:"avatar_url"` // Публичная ссылка на аватар
+	RoleID        int        `json:"role_id" db:"role_id"`
+	TenantID      string     `json:"tenant_id" db:"tenant_id"` // Магазин, в котором зарегистрирован пользователь
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty" db:"deactivated_at"` // Отключен провижинингом; nil - активен
+	// PreferredCurrency - валюта заказов по умолчанию (ISO 4217), пусто - валюта магазина
+	PreferredCurrency string `json:"preferred_currency,omitempty" db:"preferred_currency"`
+}
+
+// Active сообщает, может ли пользователь входить в систему
+func (u *User) Active() bool {
+	return u.DeactivatedAt == nil
 }
 
 // Role представляет роль пользователя (user, manager, admin)
@@ -23,6 +33,28 @@ type Role struct {
 	Description string `json:"description,omitempty" db:"description"`
 }
 
+// RoleWithPermissions - роль вместе с ее разрешениями
+type RoleWithPermissions struct {
+	Role
+	Permissions []Permission `json:"permissions"`
+}
+
+// RoleTemplate - заготовка роли с набором разрешений (support, warehouse), из которой администратор создает роль
+type RoleTemplate struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"` // Коды разрешений
+}
+
+// TenantRole - роль пользователя в магазине, отличном от магазина регистрации
+type TenantRole struct {
+	UserID      uuid.UUID `json:"user_id" db:"user_id"`
+	TenantID    string    `json:"tenant_id" db:"tenant_id"`
+	RoleID      int       `json:"role_id" db:"role_id"`
+	RoleName    string    `json:"role_name" db:"role_name"`
+	Permissions []string  `json:"permissions" db:"-"` // Коды разрешений роли
+}
+
 // Permission представляет разрешение (например, product.create)
 type Permission struct {
 	ID          int    `json:"id" db:"id"`
@@ -39,6 +71,15 @@ type RefreshToken struct {
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
 
+// RememberToken - refresh токен "запомнить меня" с продлеваемым сроком
+// Сессия не может длиться дольше максимального возраста с момента входа
+type RememberToken struct {
+	Token            string    `json:"-" db:"token"`
+	UserID           uuid.UUID `json:"user_id" db:"user_id"`
+	SessionStartedAt time.Time `json:"session_started_at" db:"session_started_at"`
+	ExpiresAt        time.Time `json:"expires_at" db:"expires_at"`
+}
+
 // BlacklistedToken хранит токены, которые были отозваны
 type BlacklistedToken struct {
 	ID        int       `json:"id" db:"id"`
@@ -52,6 +93,8 @@ type TokenPair struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
 	ExpiresIn    int64  `json:"expires_in"` // время жизни access token в секундах
+
+	RefreshExpiresIn int64 `json:"refresh_expires_in"` // время жизни refresh token в секундах
 }
 
 // UserWithRole содержит информацию о пользователе с его ролью
@@ -60,3 +103,149 @@ type UserWithRole struct {
 	Role        Role         `json:"role"`
 	Permissions []Permission `json:"permissions"`
 }
+
+// Типы событий пользователей в Kafka
+const (
+	EventUserRegistered   = "USER_REGISTERED"
+	EventUserLoggedIn     = "USER_LOGGED_IN"
+	EventPasswordChanged  = "PASSWORD_CHANGED"
+	EventRoleChanged      = "ROLE_CHANGED"
+	EventUserUpdated      = "USER_UPDATED" // Изменен публичный профиль (имя, аватар)
+	EventUserDeactivated  = "USER_DEACTIVATED"
+	EventUserReactivated  = "USER_REACTIVATED"
+	EventUserImpersonated = "USER_IMPERSONATED" // Сотрудник поддержки получил токен от имени пользователя (actor_id)
+)
+
+// PermissionImpersonate - разрешение на вход от имени пользователя (сотрудники поддержки)
+const PermissionImpersonate = "user.impersonate"
+
+// EventSchemaVersion - версия схемы UserEvent (заголовок schema_version)
+const EventSchemaVersion = 1
+
+// UserEvent представляет событие пользователя для Kafka (топик user_events)
+// Ключ сообщения - ID пользователя, поэтому события одного пользователя упорядочены
+type UserEvent struct {
+	EventType      string     `json:"event_type"` // USER_REGISTERED, USER_LOGGED_IN, PASSWORD_CHANGED, ROLE_CHANGED, USER_UPDATED, USER_DEACTIVATED, USER_REACTIVATED, USER_IMPERSONATED
+	TenantID       string     `json:"tenant_id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	Email          string     `json:"email"`
+	Name           string     `json:"name,omitempty"` // Публичный профиль на момент события
+	AvatarURL      string     `json:"avatar_url,omitempty"`
+	RoleID         int        `json:"role_id"`
+	PreviousRoleID int        `json:"previous_role_id,omitempty"` // Только для ROLE_CHANGED
+	ActorID        *uuid.UUID `json:"actor_id,omitempty"`         // Администратор, выполнивший изменение (аудит провижининга)
+	Timestamp      time.Time  `json:"timestamp"`
+}
+
+// ImpersonationAudit - запись журнала о выдаче токена имперсонации
+type ImpersonationAudit struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	ActorID    uuid.UUID `json:"actor_id" db:"actor_id"` // Сотрудник поддержки
+	ActorEmail string    `json:"actor_email" db:"actor_email"`
+	UserID     uuid.UUID `json:"user_id" db:"user_id"` // Пользователь, от имени которого выдан токен
+	TenantID   string    `json:"tenant_id" db:"tenant_id"`
+	ExpiresAt  time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// Device - устройство, с которого пользователь входил в аккаунт
+// Устройство определяется отпечатком (device_id клиента или User-Agent)
+type Device struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	UserID      uuid.UUID `json:"-" db:"user_id"`
+	Fingerprint string    `json:"-" db:"fingerprint"`
+	UserAgent   string    `json:"user_agent" db:"user_agent"`
+	IP          string    `json:"ip" db:"ip"`
+	Country     string    `json:"country,omitempty" db:"country"`
+	City        string    `json:"city,omitempty" db:"city"`
+	Latitude    *float64  `json:"-" db:"latitude"` // Координаты по IP; nil - местоположение неизвестно
+	Longitude   *float64  `json:"-" db:"longitude"`
+	FirstSeenAt time.Time `json:"first_seen_at" db:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at" db:"last_seen_at"`
+}
+
+// GeoLocation - местоположение IP адреса
+type GeoLocation struct {
+	Country   string
+	City      string
+	Latitude  float64
+	Longitude float64
+}
+
+// LoginChallenge - незавершенный подозрительный вход, ожидающий код из письма
+// Хранится в Redis до истечения срока
+type LoginChallenge struct {
+	ID         string    `json:"id"`
+	UserID     uuid.UUID `json:"user_id"`
+	CodeHash   string    `json:"code_hash"` // SHA-256 кода, сам код хранится только в письме
+	Reason     string    `json:"reason"`
+	Device     Device    `json:"-"` // Устройство станет доверенным после подтверждения
+	Attempts   int       `json:"attempts"`
+	RememberMe bool      `json:"remember_me"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Причины блокировки аккаунта
+const (
+	LockReasonFailedLogins = "too_many_failed_logins"
+	LockReasonAdmin        = "admin"
+)
+
+// FailedLoginStat - неудачные попытки входа в аккаунт за период
+type FailedLoginStat struct {
+	Email        string     `json:"email"`
+	UserID       *uuid.UUID `json:"user_id,omitempty"` // nil - аккаунта с таким email нет (перебор адресов)
+	Failures     int64      `json:"failures"`
+	LastFailedAt time.Time  `json:"last_failed_at"`
+	LastIP       string     `json:"last_ip"`
+	Locked       bool       `json:"locked"`
+}
+
+// AccountLock - временная блокировка входа в аккаунт
+// Хранится в Redis до истечения срока
+type AccountLock struct {
+	UserID    uuid.UUID  `json:"user_id"`
+	Email     string     `json:"email"`
+	Reason    string     `json:"reason"`
+	LockedBy  *uuid.UUID `json:"locked_by,omitempty"` // Администратор, заблокировавший аккаунт вручную
+	LockedAt  time.Time  `json:"locked_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+}
+
+// TokenStats - состояние токенов для панели безопасности
+type TokenStats struct {
+	BlacklistedTokens      int64               `json:"blacklisted_tokens"`
+	ActiveRefreshTokens    int64               `json:"active_refresh_tokens"`
+	RememberMeTokens       int64               `json:"remember_me_tokens"`
+	UsersWithRefreshTokens int64               `json:"users_with_refresh_tokens"`
+	RefreshUsage           []RefreshTokenUsage `json:"refresh_usage"` // По дням, начиная с сегодняшнего
+}
+
+// RefreshTokenUsage - использование refresh токенов за день (UTC)
+type RefreshTokenUsage struct {
+	Date     string `json:"date"`
+	Issued   int64  `json:"issued"`   // Выдано при входе и обновлении
+	Rotated  int64  `json:"rotated"`  // Обменяно на новую пару
+	Rejected int64  `json:"rejected"` // Предъявлено неизвестных или истекших
+	Reused   int64  `json:"reused"`   // Предъявлено уже обмененных, сессии пользователя отозваны
+}
+
+// DashboardSummary - сводка auth-service для панели администратора
+type DashboardSummary struct {
+	Signups        SignupStats  `json:"signups"`
+	ActiveSessions SessionStats `json:"active_sessions"`
+	GeneratedAt    time.Time    `json:"generated_at"`
+}
+
+// SignupStats - регистрации с начала дня и за последние 7 дней (UTC)
+type SignupStats struct {
+	Today     int64 `json:"today"`
+	Last7Days int64 `json:"last_7_days"`
+}
+
+// SessionStats - действующие сессии по неистекшим refresh токенам
+type SessionStats struct {
+	Sessions   int64 `json:"sessions"`    // Обычные сессии и сессии "запомнить меня"
+	Users      int64 `json:"users"`       // Пользователи хотя бы с одной обычной сессией
+	RememberMe int64 `json:"remember_me"` // Из них сессии "запомнить меня"
+}
@@ -2,33 +2,62 @@ package handler
 
 import (
 	"errors"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 
 	"augustberries/auth-service/internal/app/auth/entity"
 	"augustberries/auth-service/internal/app/auth/service"
 	"augustberries/auth-service/internal/app/auth/util"
+	"augustberries/pkg/authcookie"
 	"augustberries/pkg/metrics"
+	"augustberries/pkg/validate"
 )
 
 // AuthHandler обрабатывает HTTP запросы для аутентификации
 type AuthHandler struct {
 	authService *service.AuthService
-	validator   *validator.Validate
+	validator   *validate.Validator
+	cookies     *authcookie.Cookies // nil - токены только в теле ответа
 }
 
 // NewAuthHandler создает новый обработчик аутентификации
 func NewAuthHandler(authService *service.AuthService) *AuthHandler {
 	return &AuthHandler{
 		authService: authService,
-		validator:   validator.New(),
+		validator:   validate.New(),
 	}
 }
 
+// SetCookies включает выдачу токенов в httpOnly cookie для SPA
+func (h *AuthHandler) SetCookies(cookies *authcookie.Cookies) {
+	h.cookies = cookies
+}
+
+// setTokenCookies кладет выданные токены в cookie, если они включены; в теле ответа токены остаются
+// При ошибке отвечает 500 и возвращает false
+func (h *AuthHandler) setTokenCookies(c *gin.Context, tokens *entity.TokenPair) bool {
+	if h.cookies == nil {
+		return true
+	}
+	err := h.cookies.SetTokens(c.Writer,
+		tokens.AccessToken, time.Duration(tokens.ExpiresIn)*time.Second,
+		tokens.RefreshToken, time.Duration(tokens.RefreshExpiresIn)*time.Second)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to issue auth cookies",
+		})
+		return false
+	}
+	return true
+}
+
 // Register обрабатывает POST /auth/register
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req entity.RegisterRequest
@@ -43,16 +72,24 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 	// Валидация с помощью validator
 	if err := h.validator.Struct(req); err != nil {
-		validationErrors := err.(validator.ValidationErrors)
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Bad Request",
-			"message": formatValidationErrors(validationErrors),
+			"message": validate.Message(err),
 		})
 		return
 	}
 
 	resp, err := h.authService.Register(c.Request.Context(), &req)
 	if err != nil {
+		var policy *service.PasswordPolicyError
+		if errors.As(err, &policy) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      "Bad Request",
+				"message":    "Password does not meet policy",
+				"violations": policy.Violations,
+			})
+			return
+		}
 		if errors.Is(err, service.ErrUserExists) {
 			c.JSON(http.StatusConflict, gin.H{
 				"error":   "Conflict",
@@ -70,6 +107,41 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	// Записываем метрику успешной регистрации
 	metrics.AuthRegistrations.Inc()
 
+	if !h.setTokenCookies(c, &resp.Tokens) {
+		return
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+// CreateGuestSession обрабатывает POST /auth/guest
+func (h *AuthHandler) CreateGuestSession(c *gin.Context) {
+	var req entity.GuestSessionRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": validate.Message(err),
+		})
+		return
+	}
+
+	resp, err := h.authService.CreateGuestSession(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to create guest session",
+		})
+		return
+	}
+
 	c.JSON(http.StatusCreated, resp)
 }
 
@@ -87,16 +159,48 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	// Валидация
 	if err := h.validator.Struct(req); err != nil {
-		validationErrors := err.(validator.ValidationErrors)
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Bad Request",
-			"message": formatValidationErrors(validationErrors),
+			"message": validate.Message(err),
 		})
 		return
 	}
 
+	req.IP = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
 	resp, err := h.authService.Login(c.Request.Context(), &req)
 	if err != nil {
+		// Подозрительный вход: токены будут выданы после подтверждения кодом из письма
+		var verification *service.VerificationRequiredError
+		if errors.As(err, &verification) {
+			metrics.AuthLogins.WithLabelValues("challenged").Inc()
+			c.JSON(http.StatusAccepted, entity.LoginChallengeResponse{
+				VerificationRequired: true,
+				ChallengeID:          verification.ChallengeID,
+				Reason:               verification.Reason,
+				ExpiresIn:            int64(time.Until(verification.ExpiresAt).Seconds()),
+			})
+			return
+		}
+		var locked *service.AccountLockedError
+		if errors.As(err, &locked) {
+			metrics.AuthLogins.WithLabelValues("locked").Inc()
+			c.Header("Retry-After", strconv.Itoa(int(time.Until(locked.ExpiresAt).Seconds())+1))
+			c.JSON(http.StatusLocked, gin.H{
+				"error":   "Locked",
+				"message": "Account is temporarily locked",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrAccountDeactivated) {
+			metrics.AuthLogins.WithLabelValues("deactivated").Inc()
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "Forbidden",
+				"message": "Account is deactivated",
+			})
+			return
+		}
 		if errors.Is(err, service.ErrInvalidCredentials) {
 			// Записываем неудачную попытку входа
 			metrics.AuthLogins.WithLabelValues("failed").Inc()
@@ -117,16 +221,66 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	metrics.AuthLogins.WithLabelValues("success").Inc()
 	// Также записываем выдачу токенов
 	metrics.AuthTokensIssued.WithLabelValues("access").Inc()
-	metrics.AuthTokensIssued.WithLabelValues("refresh").Inc()
 
+	if !h.setTokenCookies(c, &resp.Tokens) {
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// VerifyLogin обрабатывает POST /auth/login/verify
+func (h *AuthHandler) VerifyLogin(c *gin.Context) {
+	var req entity.VerifyLoginRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	// Валидация
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": validate.Message(err),
+		})
+		return
+	}
+
+	resp, err := h.authService.VerifyLogin(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidVerificationCode) {
+			metrics.AuthLogins.WithLabelValues("failed").Inc()
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   "Unauthorized",
+				"message": "Invalid or expired verification code",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to verify login",
+		})
+		return
+	}
+
+	metrics.AuthLogins.WithLabelValues("success").Inc()
+	metrics.AuthTokensIssued.WithLabelValues("access").Inc()
+
+	if !h.setTokenCookies(c, &resp.Tokens) {
+		return
+	}
 	c.JSON(http.StatusOK, resp)
 }
 
 // RefreshToken обрабатывает POST /auth/refresh
+// С включенными cookie refresh токен можно не передавать в теле: он берется из httpOnly cookie
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	var req entity.RefreshRequest
 
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := c.ShouldBindJSON(&req); err != nil && !(h.cookies != nil && errors.Is(err, io.EOF)) {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Bad Request",
 			"message": "Invalid request body",
@@ -134,19 +288,30 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
+	if req.RefreshToken == "" && h.cookies != nil {
+		token, err := h.cookies.RefreshToken(c.Request)
+		if errors.Is(err, authcookie.ErrCSRF) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "Forbidden",
+				"message": "Invalid CSRF token",
+			})
+			return
+		}
+		req.RefreshToken = token
+	}
+
 	// Валидация
 	if err := h.validator.Struct(req); err != nil {
-		validationErrors := err.(validator.ValidationErrors)
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Bad Request",
-			"message": formatValidationErrors(validationErrors),
+			"message": validate.Message(err),
 		})
 		return
 	}
 
 	tokens, err := h.authService.RefreshTokens(c.Request.Context(), req.RefreshToken)
 	if err != nil {
-		if errors.Is(err, service.ErrInvalidRefreshToken) {
+		if errors.Is(err, service.ErrInvalidRefreshToken) || errors.Is(err, service.ErrAccountDeactivated) {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"error":   "Unauthorized",
 				"message": "Invalid or expired refresh token",
@@ -159,7 +324,11 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		})
 		return
 	}
+	metrics.AuthTokensIssued.WithLabelValues("access").Inc()
 
+	if !h.setTokenCookies(c, tokens) {
+		return
+	}
 	c.JSON(http.StatusOK, tokens)
 }
 
@@ -196,6 +365,61 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 	c.JSON(http.StatusOK, user)
 }
 
+// UpdateMe обрабатывает PATCH /auth/me
+// Меняет публичный профиль: имя и аватар
+func (h *AuthHandler) UpdateMe(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	userUUID, isUUID := userID.(uuid.UUID)
+	if !ok || !isUUID {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	var req entity.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": validate.Message(err),
+		})
+		return
+	}
+
+	user, err := h.authService.UpdateProfile(c.Request.Context(), userUUID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidAvatarURL):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+		case errors.Is(err, service.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "User not found",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal Server Error",
+				"message": "Failed to update profile",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
 // Logout обрабатывает POST /auth/logout
 func (h *AuthHandler) Logout(c *gin.Context) {
 	// Получаем userID из контекста
@@ -217,9 +441,9 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		return
 	}
 
-	// Извлекаем access токен из заголовка
-	authHeader := c.GetHeader("Authorization")
-	if authHeader == "" {
+	// Access токен из заголовка или cookie, уже проверенный Authenticate
+	token, err := h.cookies.Token(c.Request)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Bad Request",
 			"message": "Authorization header required",
@@ -227,25 +451,84 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		return
 	}
 
-	token := strings.TrimPrefix(authHeader, "Bearer ")
-	if token == authHeader {
+	if err := h.authService.Logout(c.Request.Context(), userID, token); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to logout",
+		})
+		return
+	}
+
+	if h.cookies != nil {
+		h.cookies.Clear(c.Writer)
+	}
+
+	c.JSON(http.StatusOK, entity.SuccessResponse{
+		Message: "Successfully logged out",
+	})
+}
+
+// ListSessions обрабатывает GET /auth/sessions
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	devices, err := h.authService.ListSessions(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to get sessions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"devices": devices})
+}
+
+// RemoveSession обрабатывает DELETE /auth/sessions/:id
+// Следующий вход с удаленного устройства потребует подтверждения
+func (h *AuthHandler) RemoveSession(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Bad Request",
-			"message": "Invalid authorization header format",
+			"message": "Invalid device ID",
 		})
 		return
 	}
 
-	if err := h.authService.Logout(c.Request.Context(), userID, token); err != nil {
+	if err := h.authService.RemoveSession(c.Request.Context(), userID, deviceID); err != nil {
+		if errors.Is(err, service.ErrDeviceNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Device not found",
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Internal Server Error",
-			"message": "Failed to logout",
+			"message": "Failed to remove session",
 		})
 		return
 	}
 
 	c.JSON(http.StatusOK, entity.SuccessResponse{
-		Message: "Successfully logged out",
+		Message: "Session removed",
 	})
 }
 
@@ -282,7 +565,7 @@ func (h *AuthHandler) ValidateToken(c *gin.Context) {
 		if errors.Is(err, util.ErrInvalidToken) {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"error":   "Unauthorized",
-				"message": "Invalid token",
+				"message": invalidTokenMessage(err),
 			})
 			return
 		}
@@ -296,20 +579,69 @@ func (h *AuthHandler) ValidateToken(c *gin.Context) {
 	c.JSON(http.StatusOK, claims)
 }
 
-// formatValidationErrors форматирует ошибки валидации в читаемый формат
-func formatValidationErrors(errs validator.ValidationErrors) string {
-	messages := make([]string, 0, len(errs))
-	for _, err := range errs {
-		switch err.Tag() {
-		case "required":
-			messages = append(messages, err.Field()+" is required")
-		case "email":
-			messages = append(messages, err.Field()+" must be a valid email")
-		case "min":
-			messages = append(messages, err.Field()+" must be at least "+err.Param()+" characters")
+// Impersonate обрабатывает POST /admin/users/:user_id/impersonate
+// Выдает сотруднику поддержки короткоживущий токен от имени пользователя активного магазина
+func (h *AuthHandler) Impersonate(c *gin.Context) {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid user ID",
+		})
+		return
+	}
+
+	// Права сотрудника в активном магазине: пользователь с большими правами недоступен
+	response, err := h.authService.Impersonate(c.Request.Context(), actorID, c.GetString("email"), c.GetStringSlice("permissions"), userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "User not found",
+			})
+		case errors.Is(err, service.ErrImpersonationForbidden):
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "Forbidden",
+				"message": "User cannot be impersonated",
+			})
+		case errors.Is(err, service.ErrImpersonationUnavailable):
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error":   "Service Unavailable",
+				"message": "Impersonation is disabled",
+			})
+		case errors.Is(err, service.ErrAccountDeactivated):
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Conflict",
+				"message": "Account is deactivated",
+			})
 		default:
-			messages = append(messages, err.Field()+" is invalid")
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal Server Error",
+				"message": "Failed to impersonate user",
+			})
 		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// currentUserID возвращает ID пользователя, установленный middleware
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	value, exists := c.Get("user_id")
+	if !exists {
+		return uuid.Nil, false
 	}
-	return strings.Join(messages, ", ")
+	userID, ok := value.(uuid.UUID)
+	return userID, ok
 }
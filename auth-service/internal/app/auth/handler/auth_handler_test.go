@@ -33,7 +33,7 @@ func newTestAuthHandler() (*AuthHandler, *mocks.MockUserRepository, *mocks.MockR
 	tokenRepo := new(mocks.MockTokenRepository)
 	jwtManager := util.NewJWTManager("test-secret-key", 15*time.Minute, 7*24*time.Hour)
 
-	authService := service.NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager)
+	authService := service.NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager, mocks.NewMockMessagePublisher(), nil)
 	handler := NewAuthHandler(authService)
 
 	return handler, userRepo, roleRepo, tokenRepo, jwtManager
@@ -85,6 +85,7 @@ func TestAuthHandler_Register_Success(t *testing.T) {
 	roleRepo.On("GetByName", mock.Anything, "user").Return(role, nil)
 	roleRepo.On("GetByID", mock.Anything, 1).Return(role, nil)
 	roleRepo.On("GetPermissionsByRoleID", mock.Anything, 1).Return(permissions, nil)
+	userRepo.On("ListTenantRoles", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(nil, nil)
 	tokenRepo.On("SaveRefreshToken", mock.Anything, mock.AnythingOfType("uuid.UUID"), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
 
 	reqBody := entity.RegisterRequest{
@@ -235,6 +236,7 @@ func TestAuthHandler_Login_Success(t *testing.T) {
 	userRepo.On("GetByEmail", mock.Anything, "test@example.com").Return(user, nil)
 	roleRepo.On("GetByID", mock.Anything, 1).Return(role, nil)
 	roleRepo.On("GetPermissionsByRoleID", mock.Anything, 1).Return(permissions, nil)
+	userRepo.On("ListTenantRoles", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(nil, nil)
 	tokenRepo.On("SaveRefreshToken", mock.Anything, user.ID, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
 
 	reqBody := entity.LoginRequest{
@@ -335,15 +337,15 @@ func TestAuthHandler_RefreshToken_Success(t *testing.T) {
 	role := newTestRole()
 	permissions := newTestPermissions()
 
-	tokenRepo.On("GetRefreshToken", mock.Anything, refreshToken).Return(&entity.RefreshToken{
+	tokenRepo.On("ConsumeRefreshToken", mock.Anything, refreshToken).Return(&entity.RefreshToken{
 		UserID:    userID,
 		Token:     refreshToken,
 		ExpiresAt: time.Now().Add(7 * 24 * time.Hour),
 	}, nil)
-	tokenRepo.On("DeleteRefreshToken", mock.Anything, refreshToken).Return(nil)
 	userRepo.On("GetByID", mock.Anything, userID).Return(user, nil)
 	roleRepo.On("GetByID", mock.Anything, 1).Return(role, nil)
 	roleRepo.On("GetPermissionsByRoleID", mock.Anything, 1).Return(permissions, nil)
+	userRepo.On("ListTenantRoles", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(nil, nil)
 	tokenRepo.On("SaveRefreshToken", mock.Anything, userID, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
 
 	reqBody := entity.RefreshRequest{
@@ -374,7 +376,7 @@ func TestAuthHandler_RefreshToken_InvalidToken(t *testing.T) {
 	// Arrange
 	handler, _, _, tokenRepo, _ := newTestAuthHandler()
 
-	tokenRepo.On("GetRefreshToken", mock.Anything, "invalid-token").Return(nil, pgx.ErrNoRows)
+	tokenRepo.On("ConsumeRefreshToken", mock.Anything, "invalid-token").Return(nil, pgx.ErrNoRows)
 
 	reqBody := entity.RefreshRequest{
 		RefreshToken: "invalid-token",
@@ -459,7 +461,7 @@ func TestAuthHandler_Logout_Success(t *testing.T) {
 	handler, _, _, tokenRepo, jwtManager := newTestAuthHandler()
 
 	userID := uuid.New()
-	accessToken, _ := jwtManager.GenerateAccessToken(userID, "test@example.com", 1, "user", []string{})
+	accessToken, _ := jwtManager.GenerateAccessToken(userID, "test@example.com", 1, "user", []string{}, "default", "", nil)
 
 	tokenRepo.On("AddToBlacklist", mock.Anything, accessToken, mock.AnythingOfType("time.Time")).Return(nil)
 	tokenRepo.On("DeleteUserRefreshTokens", mock.Anything, userID).Return(nil)
@@ -534,7 +536,7 @@ func TestAuthHandler_ValidateToken_Success(t *testing.T) {
 	handler, _, _, tokenRepo, jwtManager := newTestAuthHandler()
 
 	userID := uuid.New()
-	accessToken, _ := jwtManager.GenerateAccessToken(userID, "test@example.com", 1, "user", []string{"product.read"})
+	accessToken, _ := jwtManager.GenerateAccessToken(userID, "test@example.com", 1, "user", []string{"product.read"}, "default", "", nil)
 
 	tokenRepo.On("IsBlacklisted", mock.Anything, accessToken).Return(false, nil)
 
@@ -577,7 +579,7 @@ func TestAuthHandler_ValidateToken_BlacklistedToken(t *testing.T) {
 	handler, _, _, tokenRepo, jwtManager := newTestAuthHandler()
 
 	userID := uuid.New()
-	accessToken, _ := jwtManager.GenerateAccessToken(userID, "test@example.com", 1, "user", []string{})
+	accessToken, _ := jwtManager.GenerateAccessToken(userID, "test@example.com", 1, "user", []string{}, "default", "", nil)
 
 	tokenRepo.On("IsBlacklisted", mock.Anything, accessToken).Return(true, nil)
 
@@ -600,7 +602,7 @@ func TestAuthHandler_ValidateToken_ExpiredToken(t *testing.T) {
 	// Создаём JWT manager с очень коротким временем жизни
 	shortJWTManager := util.NewJWTManager("test-secret-key", 1*time.Nanosecond, 7*24*time.Hour)
 	userID := uuid.New()
-	accessToken, _ := shortJWTManager.GenerateAccessToken(userID, "test@example.com", 1, "user", []string{})
+	accessToken, _ := shortJWTManager.GenerateAccessToken(userID, "test@example.com", 1, "user", []string{}, "default", "", nil)
 
 	time.Sleep(10 * time.Millisecond) // Ждём пока токен истечёт
 
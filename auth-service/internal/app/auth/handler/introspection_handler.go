@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"augustberries/auth-service/internal/app/auth/service"
+)
+
+// IntrospectionHandler обрабатывает интроспекцию токенов по RFC 7662 для доверенных клиентов
+type IntrospectionHandler struct {
+	authService *service.AuthService
+	clients     map[string]string // client_id -> client_secret
+}
+
+// NewIntrospectionHandler создает обработчик интроспекции
+// Без настроенных клиентов все запросы отклоняются
+func NewIntrospectionHandler(authService *service.AuthService, clients map[string]string) *IntrospectionHandler {
+	return &IntrospectionHandler{
+		authService: authService,
+		clients:     clients,
+	}
+}
+
+// Introspect обрабатывает POST /auth/introspect
+// Запрос application/x-www-form-urlencoded с параметром token, клиент аутентифицируется
+// через HTTP Basic или параметры client_id и client_secret
+func (h *IntrospectionHandler) Introspect(c *gin.Context) {
+	if !h.authenticateClient(c) {
+		c.Header("WWW-Authenticate", `Basic realm="auth-service"`)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":             "invalid_client",
+			"error_description": "Client authentication failed",
+		})
+		return
+	}
+
+	token := c.PostForm("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":             "invalid_request",
+			"error_description": "token is required",
+		})
+		return
+	}
+
+	response, err := h.authService.Introspect(c.Request.Context(), token)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":             "server_error",
+			"error_description": "Failed to introspect token",
+		})
+		return
+	}
+
+	// Ответ интроспекции не должен кешироваться промежуточными прокси
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, response)
+}
+
+// authenticateClient проверяет учетные данные клиента за постоянное время
+func (h *IntrospectionHandler) authenticateClient(c *gin.Context) bool {
+	clientID, clientSecret, ok := c.Request.BasicAuth()
+	if !ok {
+		clientID, clientSecret = c.PostForm("client_id"), c.PostForm("client_secret")
+	}
+	if clientID == "" || clientSecret == "" {
+		return false
+	}
+
+	expected, exists := h.clients[clientID]
+	if !exists {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(clientSecret), []byte(expected)) == 1
+}
@@ -3,17 +3,20 @@ package handler
 import (
 	"errors"
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 
 	"augustberries/auth-service/internal/app/auth/service"
 	"augustberries/auth-service/internal/app/auth/util"
+	"augustberries/pkg/authcookie"
+	"augustberries/pkg/impersonation"
+	"augustberries/pkg/tenant"
 )
 
 // AuthMiddleware проверяет JWT токен в запросах
 type AuthMiddleware struct {
 	authService *service.AuthService
+	cookies     *authcookie.Cookies // nil - токен только в заголовке Authorization
 }
 
 // NewAuthMiddleware создает новый middleware для аутентификации
@@ -23,33 +26,21 @@ func NewAuthMiddleware(authService *service.AuthService) *AuthMiddleware {
 	}
 }
 
+// SetCookies включает токен из httpOnly cookie с проверкой CSRF токена у изменяющих запросов
+func (m *AuthMiddleware) SetCookies(cookies *authcookie.Cookies) {
+	m.cookies = cookies
+}
+
 // Authenticate проверяет JWT токен и добавляет данные пользователя в контекст
 func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Извлекаем токен из заголовка Authorization
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "Unauthorized",
-				"message": "Authorization header required",
-			})
-			c.Abort()
-			return
-		}
-
-		// Проверяем формат "Bearer <token>"
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "Unauthorized",
-				"message": "Invalid authorization header format",
-			})
-			c.Abort()
+		// Токен из заголовка Authorization или из cookie
+		token, err := m.cookies.Token(c.Request)
+		if err != nil {
+			abortTokenError(c, err)
 			return
 		}
 
-		token := parts[1]
-
 		// Валидируем токен
 		claims, err := m.authService.ValidateToken(c.Request.Context(), token)
 		if err != nil {
@@ -64,7 +55,7 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 			if errors.Is(err, util.ErrInvalidToken) {
 				c.JSON(http.StatusUnauthorized, gin.H{
 					"error":   "Unauthorized",
-					"message": "Invalid token",
+					"message": invalidTokenMessage(err),
 				})
 				c.Abort()
 				return
@@ -77,18 +68,85 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 			return
 		}
 
+		// Гостевой токен годится только для оформления заказа, не для аккаунта
+		if claims.IsGuest() {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "Forbidden",
+				"message": "Guest token is not allowed here",
+			})
+			c.Abort()
+			return
+		}
+
 		// Добавляем данные пользователя в контекст Gin
 		c.Set("user_id", claims.UserID)
 		c.Set("email", claims.Email)
 		c.Set("role_id", claims.RoleID)
 		c.Set("role_name", claims.RoleName)
 		c.Set("permissions", claims.Permissions)
+		c.Set(tenant.ContextKey, claims.TenantID)
+		c.Set(tenant.RolesKey, claims.TenantRoles)
+
+		// Токен сотрудника поддержки от имени пользователя: отметка в ответе и журнале запросов
+		impersonation.Set(c, claims.Impersonator)
+
+		c.Next()
+	}
+}
+
+// invalidTokenMessage поясняет, почему токен отклонен: чужой iss или aud означает токен другого окружения
+func invalidTokenMessage(err error) string {
+	switch {
+	case errors.Is(err, util.ErrInvalidIssuer):
+		return "Token was issued by an untrusted issuer"
+	case errors.Is(err, util.ErrInvalidAudience):
+		return "Token is not intended for this environment"
+	default:
+		return "Invalid token"
+	}
+}
+
+// abortTokenError отвечает на ошибку извлечения токена: 403 для CSRF, иначе 401
+func abortTokenError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, authcookie.ErrCSRF):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Forbidden",
+			"message": "Invalid CSRF token",
+		})
+	case errors.Is(err, authcookie.ErrInvalidHeader):
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "Invalid authorization header format",
+		})
+	default:
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "Authorization header required",
+		})
+	}
+	c.Abort()
+}
+
+// ForbidImpersonation отклоняет запросы с токеном имперсонации
+// Профиль, выход и сессии пользователь меняет только сам, сотрудник поддержки их не трогает
+func (m *AuthMiddleware) ForbidImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if impersonation.IsImpersonated(c) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "Forbidden",
+				"message": "Not allowed with an impersonation token",
+			})
+			c.Abort()
+			return
+		}
 
 		c.Next()
 	}
 }
 
-// RequireRole проверяет, что у пользователя есть требуемая роль
+// RequireRole проверяет, что у пользователя есть требуемая роль в активном магазине
+// Роль другого магазина подставляет tenant.Middleware, поэтому он должен стоять раньше
 func (m *AuthMiddleware) RequireRole(roles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		roleName, exists := c.Get("role_name")
@@ -133,7 +191,7 @@ func (m *AuthMiddleware) RequireRole(roles ...string) gin.HandlerFunc {
 	}
 }
 
-// RequirePermission проверяет, что у пользователя есть требуемое разрешение
+// RequirePermission проверяет, что у пользователя есть требуемое разрешение в активном магазине
 func (m *AuthMiddleware) RequirePermission(permission string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		perms, exists := c.Get("permissions")
@@ -10,6 +10,7 @@ import (
 	"augustberries/auth-service/internal/app/auth/repository/mocks"
 	"augustberries/auth-service/internal/app/auth/service"
 	"augustberries/auth-service/internal/app/auth/util"
+	"augustberries/pkg/impersonation"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -28,7 +29,7 @@ func newTestAuthMiddleware() (*AuthMiddleware, *mocks.MockTokenRepository, *util
 	tokenRepo := new(mocks.MockTokenRepository)
 	jwtManager := util.NewJWTManager("test-secret-key", 15*time.Minute, 7*24*time.Hour)
 
-	authService := service.NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager)
+	authService := service.NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager, mocks.NewMockMessagePublisher(), nil)
 	middleware := NewAuthMiddleware(authService)
 
 	return middleware, tokenRepo, jwtManager
@@ -42,7 +43,7 @@ func TestAuthMiddleware_Authenticate_Success(t *testing.T) {
 
 	userID := uuid.New()
 	permissions := []string{"product.read", "order.create"}
-	accessToken, _ := jwtManager.GenerateAccessToken(userID, "test@example.com", 1, "user", permissions)
+	accessToken, _ := jwtManager.GenerateAccessToken(userID, "test@example.com", 1, "user", permissions, "default", "", nil)
 
 	tokenRepo.On("IsBlacklisted", mock.Anything, accessToken).Return(false, nil)
 
@@ -155,7 +156,7 @@ func TestAuthMiddleware_Authenticate_ExpiredToken(t *testing.T) {
 	// Создаём JWT manager с коротким временем жизни
 	shortJWTManager := util.NewJWTManager("test-secret-key", 1*time.Nanosecond, 7*24*time.Hour)
 	userID := uuid.New()
-	accessToken, _ := shortJWTManager.GenerateAccessToken(userID, "test@example.com", 1, "user", []string{})
+	accessToken, _ := shortJWTManager.GenerateAccessToken(userID, "test@example.com", 1, "user", []string{}, "default", "", nil)
 
 	time.Sleep(10 * time.Millisecond) // Ждём пока токен истечёт
 
@@ -186,7 +187,7 @@ func TestAuthMiddleware_Authenticate_BlacklistedToken(t *testing.T) {
 	middleware, tokenRepo, jwtManager := newTestAuthMiddleware()
 
 	userID := uuid.New()
-	accessToken, _ := jwtManager.GenerateAccessToken(userID, "test@example.com", 1, "user", []string{})
+	accessToken, _ := jwtManager.GenerateAccessToken(userID, "test@example.com", 1, "user", []string{}, "default", "", nil)
 
 	tokenRepo.On("IsBlacklisted", mock.Anything, accessToken).Return(true, nil)
 
@@ -216,7 +217,7 @@ func TestAuthMiddleware_Authenticate_ContextValues(t *testing.T) {
 	roleName := "admin"
 	permissions := []string{"product.create", "product.delete"}
 
-	accessToken, _ := jwtManager.GenerateAccessToken(userID, email, roleID, roleName, permissions)
+	accessToken, _ := jwtManager.GenerateAccessToken(userID, email, roleID, roleName, permissions, "default", "", nil)
 
 	tokenRepo.On("IsBlacklisted", mock.Anything, accessToken).Return(false, nil)
 
@@ -248,6 +249,43 @@ func TestAuthMiddleware_Authenticate_ContextValues(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
 
+// ==================== ForbidImpersonation Tests ====================
+
+func TestAuthMiddleware_ForbidImpersonation(t *testing.T) {
+	tests := []struct {
+		name     string
+		imp      *impersonation.Impersonator
+		expected int
+	}{
+		{"impersonation token rejected", &impersonation.Impersonator{ID: "support-1"}, http.StatusForbidden},
+		{"regular token allowed", nil, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			middleware, _, _ := newTestAuthMiddleware()
+
+			router := gin.New()
+			router.PATCH("/auth/me", func(c *gin.Context) {
+				impersonation.Set(c, tt.imp)
+				c.Next()
+			}, middleware.ForbidImpersonation(), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPatch, "/auth/me", nil)
+			rec := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, tt.expected, rec.Code)
+		})
+	}
+}
+
 // ==================== RequireRole Tests ====================
 
 func TestAuthMiddleware_RequireRole_Success(t *testing.T) {
@@ -439,7 +477,7 @@ func TestAuthMiddleware_ChainedMiddlewares(t *testing.T) {
 
 	userID := uuid.New()
 	permissions := []string{"product.create", "product.read"}
-	accessToken, _ := jwtManager.GenerateAccessToken(userID, "admin@example.com", 2, "admin", permissions)
+	accessToken, _ := jwtManager.GenerateAccessToken(userID, "admin@example.com", 2, "admin", permissions, "default", "", nil)
 
 	tokenRepo.On("IsBlacklisted", mock.Anything, accessToken).Return(false, nil)
 
@@ -472,7 +510,7 @@ func TestAuthMiddleware_ChainedMiddlewares_FailsAtRole(t *testing.T) {
 
 	userID := uuid.New()
 	permissions := []string{"product.create"}
-	accessToken, _ := jwtManager.GenerateAccessToken(userID, "user@example.com", 1, "user", permissions)
+	accessToken, _ := jwtManager.GenerateAccessToken(userID, "user@example.com", 1, "user", permissions, "default", "", nil)
 
 	tokenRepo.On("IsBlacklisted", mock.Anything, accessToken).Return(false, nil)
 
@@ -496,3 +534,26 @@ func TestAuthMiddleware_ChainedMiddlewares_FailsAtRole(t *testing.T) {
 	// Assert
 	assert.Equal(t, http.StatusForbidden, rec.Code)
 }
+
+func TestAuthMiddleware_Authenticate_RejectsGuestToken(t *testing.T) {
+	// Arrange
+	middleware, tokenRepo, jwtManager := newTestAuthMiddleware()
+
+	guestToken, _ := jwtManager.GenerateGuestToken(uuid.New(), "guest@example.com", "default")
+	tokenRepo.On("IsBlacklisted", mock.Anything, guestToken).Return(false, nil)
+
+	router := gin.New()
+	router.GET("/protected", middleware.Authenticate(), func(c *gin.Context) {
+		t.Error("Handler should not be called")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+guestToken)
+	rec := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(rec, req)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/auth-service/internal/app/auth/service"
+)
+
+// ProvisioningHandler обрабатывает пакетную синхронизацию пользователей (только для администраторов)
+type ProvisioningHandler struct {
+	provisioningService *service.ProvisioningService
+}
+
+// NewProvisioningHandler создает обработчик провижининга
+func NewProvisioningHandler(provisioningService *service.ProvisioningService) *ProvisioningHandler {
+	return &ProvisioningHandler{provisioningService: provisioningService}
+}
+
+// ProvisionUsers обрабатывает POST /admin/users/bulk
+// Всегда отвечает 200 с результатом по каждой строке, если пакет принят целиком
+func (h *ProvisioningHandler) ProvisionUsers(c *gin.Context) {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	var req entity.ProvisionUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	response, err := h.provisioningService.Provision(c.Request.Context(), adminID, req.Users)
+	if err != nil {
+		if errors.Is(err, service.ErrProvisioningBatchEmpty) || errors.Is(err, service.ErrProvisioningBatchTooLarge) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": fmt.Sprintf("users must contain between 1 and %d rows", service.MaxProvisionBatch),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to provision users",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// AssignTenantRole обрабатывает PUT /admin/users/:user_id/tenant-role
+// Выдает пользователю другого магазина роль в магазине администратора
+func (h *ProvisioningHandler) AssignTenantRole(c *gin.Context) {
+	adminID, userID, ok := tenantRoleParams(c)
+	if !ok {
+		return
+	}
+
+	var req entity.AssignTenantRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Role == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "role is required",
+		})
+		return
+	}
+
+	role, err := h.provisioningService.AssignTenantRole(c.Request.Context(), adminID, userID, req.Role)
+	if err != nil {
+		respondTenantRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, role)
+}
+
+// RevokeTenantRole обрабатывает DELETE /admin/users/:user_id/tenant-role
+func (h *ProvisioningHandler) RevokeTenantRole(c *gin.Context) {
+	adminID, userID, ok := tenantRoleParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.provisioningService.RevokeTenantRole(c.Request.Context(), adminID, userID); err != nil {
+		respondTenantRoleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// tenantRoleParams возвращает администратора и пользователя из пути; при ошибке ответ уже отправлен
+func tenantRoleParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "Unauthorized",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid user ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return adminID, userID, true
+}
+
+func respondTenantRoleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not Found", "message": "User not found"})
+	case errors.Is(err, service.ErrTenantRoleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not Found", "message": "User has no role in this tenant"})
+	case errors.Is(err, service.ErrRoleNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Bad Request", "message": "Role not found"})
+	case errors.Is(err, service.ErrHomeTenantRole):
+		c.JSON(http.StatusConflict, gin.H{"error": "Conflict", "message": "Role in user's home tenant is changed via /admin/users/bulk"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error", "message": "Failed to update tenant role"})
+	}
+}
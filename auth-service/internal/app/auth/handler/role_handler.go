@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/auth-service/internal/app/auth/service"
+	"augustberries/pkg/validate"
+)
+
+// RoleHandler обрабатывает HTTP запросы шаблонов ролей (только для администраторов)
+type RoleHandler struct {
+	roleService *service.RoleService
+	validator   *validate.Validator
+}
+
+// NewRoleHandler создает обработчик шаблонов ролей
+func NewRoleHandler(roleService *service.RoleService) *RoleHandler {
+	return &RoleHandler{
+		roleService: roleService,
+		validator:   validate.New(),
+	}
+}
+
+// ListTemplates обрабатывает GET /admin/role-templates
+func (h *RoleHandler) ListTemplates(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"templates": h.roleService.Templates()})
+}
+
+// CloneTemplate обрабатывает POST /admin/role-templates/:template/clone
+// Создает роль с разрешениями шаблона; имя роли задается в запросе
+func (h *RoleHandler) CloneTemplate(c *gin.Context) {
+	var req entity.CloneRoleTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": validate.Message(err),
+		})
+		return
+	}
+
+	role, err := h.roleService.CloneTemplate(c.Request.Context(), c.Param("template"), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrRoleTemplateNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Role template not found",
+			})
+		case errors.Is(err, service.ErrRoleExists):
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Conflict",
+				"message": "Role with this name already exists",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal Server Error",
+				"message": "Failed to create role from template",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, role)
+}
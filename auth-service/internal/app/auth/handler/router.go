@@ -3,61 +3,75 @@ package handler
 import (
 	"net/http"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/pkg/buildinfo"
+	"augustberries/pkg/httpmw"
+	"augustberries/pkg/impersonation"
 	"augustberries/pkg/metrics"
+	"augustberries/pkg/recovery"
+	"augustberries/pkg/tenant"
 )
 
 // SetupRoutes настраивает все маршруты приложения с использованием Gin
-func SetupRoutes(authHandler *AuthHandler, authMiddleware *AuthMiddleware) *gin.Engine {
-	router := gin.Default()
+func SetupRoutes(authHandler *AuthHandler, securityHandler *SecurityHandler, provisioningHandler *ProvisioningHandler, roleHandler *RoleHandler, introspectionHandler *IntrospectionHandler, authMiddleware *AuthMiddleware, cors httpmw.CORSConfig) *gin.Engine {
+	router := gin.New()
+	router.Use(gin.Logger(), recovery.Middleware("auth-service"), impersonation.AuditMiddleware("auth-service"))
 
 	// Prometheus metrics middleware
 	router.Use(metrics.GinPrometheusMiddleware("auth-service"))
 
-	// CORS настройки
-	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"https://*", "http://*"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposeHeaders:    []string{"Link"},
-		AllowCredentials: true,
-		MaxAge:           300,
-	}))
+	// CORS для браузерных клиентов (CORS_ALLOWED_ORIGINS и др.), preflight запросы не доходят до маршрутов
+	router.Use(httpmw.CORS(cors))
 
 	// Health check endpoint
+	build := buildinfo.Get("auth-service")
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
 			"status":  "ok",
 			"service": "auth-service",
+			"build":   build,
 		})
 	})
 
+	// Сведения о сборке (коммит, время сборки, версия Go) - публичный
+	router.GET("/version", gin.WrapF(buildinfo.Handler("auth-service")))
+
 	// Prometheus metrics endpoint
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
 	// Публичные эндпоинты (без аутентификации)
 	auth := router.Group("/auth")
 	{
-		auth.POST("/register", authHandler.Register)
+		auth.POST("/register", tenant.Middleware(), authHandler.Register)        // Магазин пользователя берется из X-Tenant-ID
+		auth.POST("/guest", tenant.Middleware(), authHandler.CreateGuestSession) // Гостевое оформление заказа
 		auth.POST("/login", authHandler.Login)
+		auth.POST("/login/verify", authHandler.VerifyLogin)
 		auth.POST("/refresh", authHandler.RefreshToken)
 		auth.POST("/validate", authHandler.ValidateToken)
 
+		// Интроспекция токенов по RFC 7662 для доверенных сервисов (client_id и client_secret)
+		auth.POST("/introspect", introspectionHandler.Introspect)
+
 		// Защищенные эндпоинты (требуют аутентификации)
 		protected := auth.Group("")
 		protected.Use(authMiddleware.Authenticate())
 		{
 			protected.GET("/me", authHandler.GetMe)
-			protected.POST("/logout", authHandler.Logout)
+			// Изменения аккаунта недоступны с токеном имперсонации
+			protected.PATCH("/me", authMiddleware.ForbidImpersonation(), authHandler.UpdateMe) // Имя и аватар, рассылается событием USER_UPDATED
+			protected.POST("/logout", authMiddleware.ForbidImpersonation(), authHandler.Logout)
+			protected.GET("/sessions", authHandler.ListSessions)
+			protected.DELETE("/sessions/:id", authMiddleware.ForbidImpersonation(), authHandler.RemoveSession)
 		}
 	}
 
 	// Admin эндпоинты - только для администраторов
+	// Роль проверяется в активном магазине: tenant.Middleware подставляет роль из tenant_roles по X-Tenant-ID
 	admin := router.Group("/admin")
-	admin.Use(authMiddleware.Authenticate())
+	admin.Use(authMiddleware.Authenticate(), tenant.Middleware())
 	admin.Use(authMiddleware.RequireRole("admin"))
 	{
 		admin.GET("/users", func(c *gin.Context) {
@@ -65,11 +79,43 @@ func SetupRoutes(authHandler *AuthHandler, authMiddleware *AuthMiddleware) *gin.
 				"message": "Admin only endpoint - list users",
 			})
 		})
+
+		// Сводка для панели администратора: регистрации и действующие сессии
+		admin.GET("/summary", securityHandler.Summary)
+
+		// Провижининг сотрудников из внешних каталогов: создание, изменение ролей и отключение пакетами
+		admin.POST("/users/bulk", provisioningHandler.ProvisionUsers)
+
+		// Роли пользователей других магазинов в магазине администратора
+		admin.PUT("/users/:user_id/tenant-role", provisioningHandler.AssignTenantRole)
+		admin.DELETE("/users/:user_id/tenant-role", provisioningHandler.RevokeTenantRole)
+
+		// Шаблоны ролей (support, warehouse): создание роли с готовым набором разрешений
+		admin.GET("/role-templates", roleHandler.ListTemplates)
+		admin.POST("/role-templates/:template/clone", roleHandler.CloneTemplate)
+
+		// Панель безопасности: неудачные входы, блокировки и статистика токенов
+		security := admin.Group("/security")
+		{
+			security.GET("/failed-logins", securityHandler.FailedLogins)
+			security.GET("/locked-accounts", securityHandler.LockedAccounts)
+			security.POST("/locked-accounts/:user_id", securityHandler.LockAccount)
+			security.DELETE("/locked-accounts/:user_id", securityHandler.UnlockAccount)
+			security.GET("/tokens", securityHandler.TokenStats)
+		}
+	}
+
+	// Вход от имени пользователя для сотрудников поддержки (разрешение user.impersonate, есть у admin и support)
+	// Отдельная группа: остальные /admin маршруты доступны только admin
+	impersonate := router.Group("/admin/users")
+	impersonate.Use(authMiddleware.Authenticate(), tenant.Middleware(), authMiddleware.RequirePermission(entity.PermissionImpersonate))
+	{
+		impersonate.POST("/:user_id/impersonate", authHandler.Impersonate)
 	}
 
 	// API эндпоинты с проверкой разрешений
 	api := router.Group("/api/products")
-	api.Use(authMiddleware.Authenticate())
+	api.Use(authMiddleware.Authenticate(), tenant.Middleware())
 	{
 		// Любой авторизованный пользователь может читать
 		api.GET("", func(c *gin.Context) {
@@ -0,0 +1,238 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/auth-service/internal/app/auth/service"
+	"augustberries/pkg/validate"
+)
+
+// Ограничения запросов панели безопасности
+const (
+	defaultFailedLoginsPeriod = time.Hour
+	maxFailedLoginsPeriod     = 24 * time.Hour // История неудачных входов хранится сутки
+	defaultFailedLoginsLimit  = 100
+	maxFailedLoginsLimit      = 500
+	defaultTokenStatsDays     = 7
+	maxTokenStatsDays         = 30
+)
+
+// SecurityHandler обрабатывает HTTP запросы панели безопасности (только для администраторов)
+type SecurityHandler struct {
+	securityService *service.SecurityService
+	validator       *validate.Validator
+}
+
+// NewSecurityHandler создает обработчик панели безопасности
+func NewSecurityHandler(securityService *service.SecurityService) *SecurityHandler {
+	return &SecurityHandler{
+		securityService: securityService,
+		validator:       validate.New(),
+	}
+}
+
+// FailedLogins обрабатывает GET /admin/security/failed-logins?period=1h&limit=100
+func (h *SecurityHandler) FailedLogins(c *gin.Context) {
+	period := defaultFailedLoginsPeriod
+	if value := c.Query("period"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 || parsed > maxFailedLoginsPeriod {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": "period must be a duration up to 24h",
+			})
+			return
+		}
+		period = parsed
+	}
+
+	limit, ok := queryInt(c, "limit", defaultFailedLoginsLimit, maxFailedLoginsLimit)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "limit must be between 1 and 500",
+		})
+		return
+	}
+
+	stats, err := h.securityService.FailedLogins(c.Request.Context(), period, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to get failed logins",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"period":   period.String(),
+		"accounts": stats,
+	})
+}
+
+// LockedAccounts обрабатывает GET /admin/security/locked-accounts
+func (h *SecurityHandler) LockedAccounts(c *gin.Context) {
+	locks, err := h.securityService.LockedAccounts(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to get locked accounts",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"accounts": locks})
+}
+
+// LockAccount обрабатывает POST /admin/security/locked-accounts/:user_id
+func (h *SecurityHandler) LockAccount(c *gin.Context) {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid user ID",
+		})
+		return
+	}
+
+	var req entity.LockAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": validate.Message(err),
+		})
+		return
+	}
+
+	duration, err := time.ParseDuration(req.Duration)
+	if err != nil || duration <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "duration must be a positive duration like 30m or 24h",
+		})
+		return
+	}
+
+	lock, err := h.securityService.LockAccount(c.Request.Context(), adminID, userID, duration)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "User not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to lock account",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, lock)
+}
+
+// UnlockAccount обрабатывает DELETE /admin/security/locked-accounts/:user_id
+func (h *SecurityHandler) UnlockAccount(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid user ID",
+		})
+		return
+	}
+
+	if err := h.securityService.UnlockAccount(c.Request.Context(), userID); err != nil {
+		if errors.Is(err, service.ErrAccountNotLocked) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Account is not locked",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to unlock account",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.SuccessResponse{
+		Message: "Account unlocked",
+	})
+}
+
+// TokenStats обрабатывает GET /admin/security/tokens?days=7
+func (h *SecurityHandler) TokenStats(c *gin.Context) {
+	days, ok := queryInt(c, "days", defaultTokenStatsDays, maxTokenStatsDays)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "days must be between 1 and 30",
+		})
+		return
+	}
+
+	stats, err := h.securityService.TokenStats(c.Request.Context(), days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to get token stats",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// Summary обрабатывает GET /admin/summary
+func (h *SecurityHandler) Summary(c *gin.Context) {
+	summary, err := h.securityService.DashboardSummary(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to get dashboard summary",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// queryInt читает положительный целый query параметр не больше max
+func queryInt(c *gin.Context, name string, defaultValue, max int) (int, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return defaultValue, true
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > max {
+		return 0, false
+	}
+	return n, true
+}
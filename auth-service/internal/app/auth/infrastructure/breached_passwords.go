@@ -0,0 +1,67 @@
+package infrastructure
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// hibpClient проверяет пароль по API диапазонов Have I Been Pwned с k-anonymity:
+// в запрос уходят только первые 5 символов SHA-1 пароля, суффикс сравнивается локально
+// Совместим с api.pwnedpasswords.com и локальными зеркалами с тем же API (/range/{prefix})
+type hibpClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewHIBPClient создает клиент проверки утекших паролей
+func NewHIBPClient(baseURL string, timeout time.Duration) BreachedPasswordChecker {
+	return &hibpClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *hibpClient) IsBreached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/range/"+prefix, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	// Дополнение ответа фиктивными суффиксами скрывает по размеру ответа, был ли пароль найден
+	req.Header.Set("Add-Padding", "true")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return false, fmt.Errorf("breached passwords API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	// Строки ответа: SUFFIX:COUNT, у фиктивных суффиксов COUNT = 0
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		candidate, count, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(candidate, suffix) {
+			return strings.TrimSpace(count) != "0", nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("failed to read response: %w", err)
+	}
+	return false, nil
+}
@@ -0,0 +1,70 @@
+package infrastructure
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// SHA-1("password") = 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+const passwordHashSuffix = "1E4C9B93F3F0682250B6CF8331B7EE68FD8"
+
+func newTestHIBPServer(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// В запрос уходит только префикс хэша
+		assert.Equal(t, "/range/5BAA6", r.URL.Path)
+		assert.Equal(t, "true", r.Header.Get("Add-Padding"))
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHIBPClient_Breached(t *testing.T) {
+	server := newTestHIBPServer(t, "0018A45C4D1DEF81644B54AB7F969B88D65:3\r\n"+passwordHashSuffix+":9659365\r\n")
+	client := NewHIBPClient(server.URL+"/", time.Second)
+
+	breached, err := client.IsBreached(context.Background(), "password")
+
+	require.NoError(t, err)
+	assert.True(t, breached)
+}
+
+func TestHIBPClient_NotBreached(t *testing.T) {
+	server := newTestHIBPServer(t, "0018A45C4D1DEF81644B54AB7F969B88D65:3\r\n")
+	client := NewHIBPClient(server.URL, time.Second)
+
+	breached, err := client.IsBreached(context.Background(), "password")
+
+	require.NoError(t, err)
+	assert.False(t, breached)
+}
+
+func TestHIBPClient_PaddingEntryIsNotBreach(t *testing.T) {
+	// Фиктивные суффиксы дополнения приходят с числом 0
+	server := newTestHIBPServer(t, passwordHashSuffix+":0\r\n")
+	client := NewHIBPClient(server.URL, time.Second)
+
+	breached, err := client.IsBreached(context.Background(), "password")
+
+	require.NoError(t, err)
+	assert.False(t, breached)
+}
+
+func TestHIBPClient_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	client := NewHIBPClient(server.URL, time.Second)
+
+	_, err := client.IsBreached(context.Background(), "password")
+
+	assert.Error(t, err)
+}
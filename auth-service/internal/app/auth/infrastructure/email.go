@@ -0,0 +1,20 @@
+package infrastructure
+
+import (
+	"context"
+	"log"
+)
+
+// logEmailSender пишет письма в лог вместо отправки
+// Используется, пока к сервису не подключен почтовый провайдер
+type logEmailSender struct{}
+
+// NewLogEmailSender создает EmailSender, который только логирует письма
+func NewLogEmailSender() EmailSender {
+	return &logEmailSender{}
+}
+
+func (s *logEmailSender) Send(ctx context.Context, to, subject, body string) error {
+	log.Printf("Email to %s: %s\n%s", to, subject, body)
+	return nil
+}
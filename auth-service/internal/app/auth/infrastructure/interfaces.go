@@ -0,0 +1,20 @@
+package infrastructure
+
+import "context"
+
+// MessagePublisher интерфейс для отправки сообщений в очередь (Kafka)
+// Используется для dependency injection и упрощения тестирования
+type MessagePublisher interface {
+	PublishMessage(ctx context.Context, key string, value []byte) error
+	Close() error
+}
+
+// EmailSender отправляет письма пользователям (коды подтверждения входа)
+type EmailSender interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// BreachedPasswordChecker проверяет пароль по базе утекших паролей
+type BreachedPasswordChecker interface {
+	IsBreached(ctx context.Context, password string) (bool, error)
+}
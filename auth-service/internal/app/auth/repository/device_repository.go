@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type deviceRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewDeviceRepository(db *pgxpool.Pool) DeviceRepository {
+	return &deviceRepository{db: db}
+}
+
+func (r *deviceRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]entity.Device, error) {
+	query := `
+		SELECT id, user_id, fingerprint, user_agent, ip, country, city, latitude, longitude, first_seen_at, last_seen_at
+		FROM user_devices
+		WHERE user_id = $1
+		ORDER BY last_seen_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list devices: %w", err)
+	}
+	defer rows.Close()
+
+	var devices []entity.Device
+	for rows.Next() {
+		var device entity.Device
+		if err := rows.Scan(
+			&device.ID,
+			&device.UserID,
+			&device.Fingerprint,
+			&device.UserAgent,
+			&device.IP,
+			&device.Country,
+			&device.City,
+			&device.Latitude,
+			&device.Longitude,
+			&device.FirstSeenAt,
+			&device.LastSeenAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan device: %w", err)
+		}
+		devices = append(devices, device)
+	}
+
+	return devices, rows.Err()
+}
+
+func (r *deviceRepository) Upsert(ctx context.Context, device *entity.Device) error {
+	query := `
+		INSERT INTO user_devices (id, user_id, fingerprint, user_agent, ip, country, city, latitude, longitude, first_seen_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (user_id, fingerprint) DO UPDATE SET
+			user_agent = EXCLUDED.user_agent,
+			ip = EXCLUDED.ip,
+			country = EXCLUDED.country,
+			city = EXCLUDED.city,
+			latitude = EXCLUDED.latitude,
+			longitude = EXCLUDED.longitude,
+			last_seen_at = EXCLUDED.last_seen_at
+		RETURNING id, first_seen_at
+	`
+
+	err := r.db.QueryRow(
+		ctx, query,
+		device.ID, device.UserID, device.Fingerprint, device.UserAgent, device.IP,
+		device.Country, device.City, device.Latitude, device.Longitude,
+		device.FirstSeenAt, device.LastSeenAt,
+	).Scan(&device.ID, &device.FirstSeenAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to upsert device: %w", err)
+	}
+
+	return nil
+}
+
+func (r *deviceRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	query := `DELETE FROM user_devices WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete device: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
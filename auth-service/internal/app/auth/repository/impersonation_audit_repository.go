@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type impersonationAuditRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewImpersonationAuditRepository(db *pgxpool.Pool) ImpersonationAuditRepository {
+	return &impersonationAuditRepository{db: db}
+}
+
+func (r *impersonationAuditRepository) Create(ctx context.Context, record *entity.ImpersonationAudit) error {
+	query := `
+		INSERT INTO impersonation_audit (id, actor_id, actor_email, user_id, tenant_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		record.ID,
+		record.ActorID,
+		record.ActorEmail,
+		record.UserID,
+		record.TenantID,
+		record.ExpiresAt,
+		record.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save impersonation audit record: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
+)
+
+// failedLoginRetention - сколько хранится история неудачных входов для панели безопасности
+const failedLoginRetention = 24 * time.Hour
+
+const (
+	failedLoginIndexKey = "failed_logins:index" // ZSET: email -> время последней неудачи
+	accountLocksKey     = "account_locks"       // SET: ID заблокированных пользователей
+)
+
+type redisLoginAttemptRepository struct {
+	client redis.UniversalClient
+}
+
+func NewRedisLoginAttemptRepository(client redis.UniversalClient) LoginAttemptRepository {
+	return &redisLoginAttemptRepository{client: client}
+}
+
+func failedLoginsKey(email string) string {
+	return fmt.Sprintf("failed_logins:%s", strings.ToLower(email))
+}
+
+func accountLockKey(userID uuid.UUID) string {
+	return fmt.Sprintf("account_lock:%s", userID)
+}
+
+func (r *redisLoginAttemptRepository) RecordFailure(ctx context.Context, email, ip string, at, since time.Time) (int64, error) {
+	email = strings.ToLower(email)
+	key := failedLoginsKey(email)
+	score := float64(at.UnixNano()) / 1e9
+	oldest := unixScore(at.Add(-failedLoginRetention))
+
+	pipe := r.client.TxPipeline()
+	// Элемент уникален по времени, IP хранится для панели безопасности
+	pipe.ZAdd(ctx, key, redis.Z{Score: score, Member: fmt.Sprintf("%d|%s", at.UnixNano(), ip)})
+	pipe.ZRemRangeByScore(ctx, key, "-inf", "("+oldest)
+	pipe.Expire(ctx, key, failedLoginRetention)
+	pipe.ZAdd(ctx, failedLoginIndexKey, redis.Z{Score: score, Member: email})
+	pipe.ZRemRangeByScore(ctx, failedLoginIndexKey, "-inf", "("+oldest)
+	count := pipe.ZCount(ctx, key, unixScore(since), "+inf")
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to record failed login: %w", err)
+	}
+
+	return count.Val(), nil
+}
+
+func (r *redisLoginAttemptRepository) ClearFailures(ctx context.Context, email string) error {
+	email = strings.ToLower(email)
+
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, failedLoginsKey(email))
+	pipe.ZRem(ctx, failedLoginIndexKey, email)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to clear failed logins: %w", err)
+	}
+	return nil
+}
+
+func (r *redisLoginAttemptRepository) ListFailures(ctx context.Context, since time.Time, limit int) ([]entity.FailedLoginStat, error) {
+	emails, err := r.client.ZRevRangeByScore(ctx, failedLoginIndexKey, &redis.ZRangeBy{
+		Min:   unixScore(since),
+		Max:   "+inf",
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list failed logins: %w", err)
+	}
+
+	stats := make([]entity.FailedLoginStat, 0, len(emails))
+	for _, email := range emails {
+		key := failedLoginsKey(email)
+
+		pipe := r.client.Pipeline()
+		count := pipe.ZCount(ctx, key, unixScore(since), "+inf")
+		last := pipe.ZRevRangeWithScores(ctx, key, 0, 0)
+		if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+			return nil, fmt.Errorf("failed to get failed logins for %s: %w", email, err)
+		}
+		if count.Val() == 0 || len(last.Val()) == 0 {
+			continue
+		}
+
+		stat := entity.FailedLoginStat{
+			Email:        email,
+			Failures:     count.Val(),
+			LastFailedAt: time.Unix(0, int64(last.Val()[0].Score*1e9)).UTC(),
+		}
+		if member, ok := last.Val()[0].Member.(string); ok {
+			if _, ip, found := strings.Cut(member, "|"); found {
+				stat.LastIP = ip
+			}
+		}
+		stats = append(stats, stat)
+	}
+
+	return stats, nil
+}
+
+func (r *redisLoginAttemptRepository) Lock(ctx context.Context, lock *entity.AccountLock) error {
+	ttl := time.Until(lock.ExpiresAt)
+	if ttl <= 0 {
+		return fmt.Errorf("account lock already expired")
+	}
+
+	data, err := json.Marshal(lock)
+	if err != nil {
+		return fmt.Errorf("failed to marshal account lock: %w", err)
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.Set(ctx, accountLockKey(lock.UserID), data, ttl)
+	pipe.SAdd(ctx, accountLocksKey, lock.UserID.String())
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save account lock to Redis: %w", err)
+	}
+	return nil
+}
+
+// GetLock возвращает pgx.ErrNoRows, если аккаунт не заблокирован
+func (r *redisLoginAttemptRepository) GetLock(ctx context.Context, userID uuid.UUID) (*entity.AccountLock, error) {
+	data, err := r.client.Get(ctx, accountLockKey(userID)).Bytes()
+	if err == redis.Nil {
+		return nil, pgx.ErrNoRows
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account lock from Redis: %w", err)
+	}
+
+	var lock entity.AccountLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal account lock: %w", err)
+	}
+	return &lock, nil
+}
+
+// Unlock возвращает pgx.ErrNoRows, если аккаунт не был заблокирован
+func (r *redisLoginAttemptRepository) Unlock(ctx context.Context, userID uuid.UUID) error {
+	pipe := r.client.TxPipeline()
+	deleted := pipe.Del(ctx, accountLockKey(userID))
+	pipe.SRem(ctx, accountLocksKey, userID.String())
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to delete account lock: %w", err)
+	}
+	if deleted.Val() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// ListLocks возвращает действующие блокировки
+// Истекшие по TTL блокировки попутно удаляются из индекса
+func (r *redisLoginAttemptRepository) ListLocks(ctx context.Context) ([]entity.AccountLock, error) {
+	ids, err := r.client.SMembers(ctx, accountLocksKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list account locks: %w", err)
+	}
+
+	locks := make([]entity.AccountLock, 0, len(ids))
+	for _, id := range ids {
+		userID, err := uuid.Parse(id)
+		if err != nil {
+			r.client.SRem(ctx, accountLocksKey, id)
+			continue
+		}
+
+		lock, err := r.GetLock(ctx, userID)
+		if err == pgx.ErrNoRows {
+			r.client.SRem(ctx, accountLocksKey, id)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		locks = append(locks, *lock)
+	}
+
+	return locks, nil
+}
+
+// unixScore форматирует время как score ZSET (секунды с долями)
+func unixScore(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixNano())/1e9, 'f', -1, 64)
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ===== LoginAttemptRepository Tests =====
+
+func TestLoginAttemptRepository_RecordAndListFailures(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewRedisLoginAttemptRepository(newTestRedis(t))
+	now := time.Now()
+
+	// Act: старая неудача вне окна, две свежие
+	_, err := repo.RecordFailure(ctx, "User@Example.com", "10.0.0.1", now.Add(-time.Hour), now.Add(-time.Hour))
+	require.NoError(t, err)
+	_, err = repo.RecordFailure(ctx, "user@example.com", "10.0.0.2", now.Add(-time.Minute), now.Add(-15*time.Minute))
+	require.NoError(t, err)
+	count, err := repo.RecordFailure(ctx, "user@example.com", "10.0.0.3", now, now.Add(-15*time.Minute))
+	require.NoError(t, err)
+
+	stats, err := repo.ListFailures(ctx, now.Add(-15*time.Minute), 10)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+	require.Len(t, stats, 1)
+	assert.Equal(t, "user@example.com", stats[0].Email)
+	assert.Equal(t, int64(2), stats[0].Failures)
+	assert.Equal(t, "10.0.0.3", stats[0].LastIP)
+
+	// Сброс удаляет аккаунт из списка
+	require.NoError(t, repo.ClearFailures(ctx, "user@example.com"))
+	stats, err = repo.ListFailures(ctx, now.Add(-15*time.Minute), 10)
+	require.NoError(t, err)
+	assert.Empty(t, stats)
+}
+
+func TestLoginAttemptRepository_Locks(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewRedisLoginAttemptRepository(newTestRedis(t))
+	userID := uuid.New()
+
+	// Act
+	require.NoError(t, repo.Lock(ctx, &entity.AccountLock{
+		UserID:    userID,
+		Email:     "user@example.com",
+		Reason:    entity.LockReasonAdmin,
+		LockedAt:  time.Now(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}))
+	locks, err := repo.ListLocks(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, locks, 1)
+	assert.Equal(t, userID, locks[0].UserID)
+
+	require.NoError(t, repo.Unlock(ctx, userID))
+	assert.ErrorIs(t, repo.Unlock(ctx, userID), pgx.ErrNoRows)
+	_, err = repo.GetLock(ctx, userID)
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+}
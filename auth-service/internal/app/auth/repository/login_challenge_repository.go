@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
+)
+
+// challengeRecord - представление проверки в Redis
+// Отпечаток и координаты устройства скрыты из JSON API, поэтому устройство сериализуется отдельно
+type challengeRecord struct {
+	ID        string       `json:"id"`
+	UserID    uuid.UUID    `json:"user_id"`
+	CodeHash  string       `json:"code_hash"`
+	Reason    string       `json:"reason"`
+	Device    deviceRecord `json:"device"`
+	Attempts  int          `json:"attempts"`
+	ExpiresAt time.Time    `json:"expires_at"`
+
+	RememberMe bool `json:"remember_me"`
+}
+
+type deviceRecord struct {
+	ID          uuid.UUID `json:"id"`
+	Fingerprint string    `json:"fingerprint"`
+	UserAgent   string    `json:"user_agent"`
+	IP          string    `json:"ip"`
+	Country     string    `json:"country"`
+	City        string    `json:"city"`
+	Latitude    *float64  `json:"latitude"`
+	Longitude   *float64  `json:"longitude"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+}
+
+func toChallengeRecord(c *entity.LoginChallenge) challengeRecord {
+	return challengeRecord{
+		ID:       c.ID,
+		UserID:   c.UserID,
+		CodeHash: c.CodeHash,
+		Reason:   c.Reason,
+		Device: deviceRecord{
+			ID:          c.Device.ID,
+			Fingerprint: c.Device.Fingerprint,
+			UserAgent:   c.Device.UserAgent,
+			IP:          c.Device.IP,
+			Country:     c.Device.Country,
+			City:        c.Device.City,
+			Latitude:    c.Device.Latitude,
+			Longitude:   c.Device.Longitude,
+			FirstSeenAt: c.Device.FirstSeenAt,
+		},
+		Attempts:   c.Attempts,
+		ExpiresAt:  c.ExpiresAt,
+		RememberMe: c.RememberMe,
+	}
+}
+
+func (r challengeRecord) toEntity() *entity.LoginChallenge {
+	return &entity.LoginChallenge{
+		ID:       r.ID,
+		UserID:   r.UserID,
+		CodeHash: r.CodeHash,
+		Reason:   r.Reason,
+		Device: entity.Device{
+			ID:          r.Device.ID,
+			UserID:      r.UserID,
+			Fingerprint: r.Device.Fingerprint,
+			UserAgent:   r.Device.UserAgent,
+			IP:          r.Device.IP,
+			Country:     r.Device.Country,
+			City:        r.Device.City,
+			Latitude:    r.Device.Latitude,
+			Longitude:   r.Device.Longitude,
+			FirstSeenAt: r.Device.FirstSeenAt,
+			LastSeenAt:  r.Device.FirstSeenAt,
+		},
+		Attempts:   r.Attempts,
+		ExpiresAt:  r.ExpiresAt,
+		RememberMe: r.RememberMe,
+	}
+}
+
+type redisLoginChallengeRepository struct {
+	client redis.UniversalClient
+}
+
+func NewRedisLoginChallengeRepository(client redis.UniversalClient) LoginChallengeRepository {
+	return &redisLoginChallengeRepository{client: client}
+}
+
+func challengeKey(id string) string {
+	return fmt.Sprintf("login_challenge:%s", id)
+}
+
+// Save сохраняет проверку до ее истечения; повторное сохранение не продлевает срок
+func (r *redisLoginChallengeRepository) Save(ctx context.Context, challenge *entity.LoginChallenge) error {
+	ttl := time.Until(challenge.ExpiresAt)
+	if ttl <= 0 {
+		return fmt.Errorf("login challenge already expired")
+	}
+
+	data, err := json.Marshal(toChallengeRecord(challenge))
+	if err != nil {
+		return fmt.Errorf("failed to marshal login challenge: %w", err)
+	}
+
+	if err := r.client.Set(ctx, challengeKey(challenge.ID), data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save login challenge to Redis: %w", err)
+	}
+
+	return nil
+}
+
+// Get возвращает pgx.ErrNoRows для отсутствующей или истекшей проверки,
+// чтобы сервисный слой обрабатывал "не найдено" одинаково для всех хранилищ
+func (r *redisLoginChallengeRepository) Get(ctx context.Context, id string) (*entity.LoginChallenge, error) {
+	data, err := r.client.Get(ctx, challengeKey(id)).Bytes()
+	if err == redis.Nil {
+		return nil, pgx.ErrNoRows
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login challenge from Redis: %w", err)
+	}
+
+	var record challengeRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal login challenge: %w", err)
+	}
+
+	return record.toEntity(), nil
+}
+
+func (r *redisLoginChallengeRepository) Delete(ctx context.Context, id string) error {
+	if err := r.client.Del(ctx, challengeKey(id)).Err(); err != nil {
+		return fmt.Errorf("failed to delete login challenge from Redis: %w", err)
+	}
+	return nil
+}
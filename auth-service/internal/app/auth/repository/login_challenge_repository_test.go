@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedis(t *testing.T) *redis.Client {
+	mr := miniredis.RunT(t)
+	return redis.NewClient(&redis.Options{Addr: mr.Addr()})
+}
+
+// ===== LoginChallengeRepository Tests =====
+
+func TestLoginChallengeRepository_RoundTripKeepsDevice(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewRedisLoginChallengeRepository(newTestRedis(t))
+
+	lat, lon := 55.75, 37.62
+	userID := uuid.New()
+	challenge := &entity.LoginChallenge{
+		ID:       "challenge-1",
+		UserID:   userID,
+		CodeHash: "hash",
+		Reason:   "new_device",
+		Device: entity.Device{
+			ID:          uuid.New(),
+			UserID:      userID,
+			Fingerprint: "fingerprint",
+			IP:          "10.0.0.1",
+			Latitude:    &lat,
+			Longitude:   &lon,
+		},
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+
+	// Act
+	require.NoError(t, repo.Save(ctx, challenge))
+	got, err := repo.Get(ctx, "challenge-1")
+
+	// Assert: отпечаток и координаты скрыты из API, но должны пережить хранение
+	require.NoError(t, err)
+	assert.Equal(t, userID, got.Device.UserID)
+	assert.Equal(t, "fingerprint", got.Device.Fingerprint)
+	require.NotNil(t, got.Device.Latitude)
+	assert.Equal(t, lat, *got.Device.Latitude)
+}
+
+func TestLoginChallengeRepository_GetMissing(t *testing.T) {
+	repo := NewRedisLoginChallengeRepository(newTestRedis(t))
+
+	_, err := repo.Get(context.Background(), "missing")
+
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+}
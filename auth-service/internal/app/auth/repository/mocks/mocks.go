@@ -54,6 +54,37 @@ func (m *MockUserRepository) List(ctx context.Context) ([]entity.User, error) {
 	return args.Get(0).([]entity.User), args.Error(1)
 }
 
+func (m *MockUserRepository) CountCreatedSince(ctx context.Context, since time.Time) (int64, error) {
+	args := m.Called(ctx, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUserRepository) ListTenantUserIDs(ctx context.Context) ([]uuid.UUID, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockUserRepository) ListTenantRoles(ctx context.Context, userID uuid.UUID) ([]entity.TenantRole, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.TenantRole), args.Error(1)
+}
+
+func (m *MockUserRepository) SetTenantRole(ctx context.Context, userID uuid.UUID, tenantID string, roleID int) error {
+	args := m.Called(ctx, userID, tenantID, roleID)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) DeleteTenantRole(ctx context.Context, userID uuid.UUID, tenantID string) error {
+	args := m.Called(ctx, userID, tenantID)
+	return args.Error(0)
+}
+
 // MockRoleRepository мок для RoleRepository
 type MockRoleRepository struct {
 	mock.Mock
@@ -134,6 +165,21 @@ func (m *MockRoleRepository) RemovePermissions(ctx context.Context, roleID int,
 	return args.Error(0)
 }
 
+func (m *MockRoleRepository) EnsurePermissions(ctx context.Context, permissions []entity.Permission) error {
+	args := m.Called(ctx, permissions)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) CreateWithPermissions(ctx context.Context, role *entity.Role, permissions []entity.Permission) (bool, error) {
+	args := m.Called(ctx, role, permissions)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRoleRepository) GrantAllPermissions(ctx context.Context, roleName string) error {
+	args := m.Called(ctx, roleName)
+	return args.Error(0)
+}
+
 // MockTokenRepository мок для TokenRepository
 type MockTokenRepository struct {
 	mock.Mock
@@ -162,6 +208,24 @@ func (m *MockTokenRepository) DeleteUserRefreshTokens(ctx context.Context, userI
 	return args.Error(0)
 }
 
+func (m *MockTokenRepository) SaveRememberToken(ctx context.Context, token *entity.RememberToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) GetRememberToken(ctx context.Context, token string) (*entity.RememberToken, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.RememberToken), args.Error(1)
+}
+
+func (m *MockTokenRepository) DeleteRememberToken(ctx context.Context, token string) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
 func (m *MockTokenRepository) AddToBlacklist(ctx context.Context, token string, expiresAt time.Time) error {
 	args := m.Called(ctx, token, expiresAt)
 	return args.Error(0)
@@ -176,3 +240,184 @@ func (m *MockTokenRepository) CleanupExpiredTokens(ctx context.Context) error {
 	args := m.Called(ctx)
 	return args.Error(0)
 }
+
+func (m *MockTokenRepository) ConsumeRefreshToken(ctx context.Context, token string) (*entity.RefreshToken, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.RefreshToken), args.Error(1)
+}
+
+func (m *MockTokenRepository) ConsumeRememberToken(ctx context.Context, token string) (*entity.RememberToken, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.RememberToken), args.Error(1)
+}
+
+func (m *MockTokenRepository) SessionStats(ctx context.Context, userIDs []uuid.UUID) (*entity.SessionStats, error) {
+	args := m.Called(ctx, userIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.SessionStats), args.Error(1)
+}
+
+func (m *MockTokenRepository) Stats(ctx context.Context, days int) (*entity.TokenStats, error) {
+	args := m.Called(ctx, days)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.TokenStats), args.Error(1)
+}
+
+// MockMessagePublisher мок для MessagePublisher (Kafka)
+type MockMessagePublisher struct {
+	mock.Mock
+	Messages [][]byte
+}
+
+// NewMockMessagePublisher создает мок, принимающий любые сообщения
+// Тесты событий проверяют отправленные сообщения через Messages
+func NewMockMessagePublisher() *MockMessagePublisher {
+	m := &MockMessagePublisher{}
+	m.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return m
+}
+
+func (m *MockMessagePublisher) PublishMessage(ctx context.Context, key string, value []byte) error {
+	m.Messages = append(m.Messages, value)
+	args := m.Called(ctx, key, value)
+	return args.Error(0)
+}
+
+func (m *MockMessagePublisher) Close() error {
+	return nil
+}
+
+// MockDeviceRepository мок для DeviceRepository
+type MockDeviceRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeviceRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]entity.Device, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Device), args.Error(1)
+}
+
+func (m *MockDeviceRepository) Upsert(ctx context.Context, device *entity.Device) error {
+	args := m.Called(ctx, device)
+	return args.Error(0)
+}
+
+func (m *MockDeviceRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+// MockLoginChallengeRepository мок для LoginChallengeRepository
+type MockLoginChallengeRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginChallengeRepository) Save(ctx context.Context, challenge *entity.LoginChallenge) error {
+	args := m.Called(ctx, challenge)
+	return args.Error(0)
+}
+
+func (m *MockLoginChallengeRepository) Get(ctx context.Context, id string) (*entity.LoginChallenge, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.LoginChallenge), args.Error(1)
+}
+
+func (m *MockLoginChallengeRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockEmailSender запоминает отправленные письма
+type MockEmailSender struct {
+	Bodies []string
+}
+
+func (m *MockEmailSender) Send(ctx context.Context, to, subject, body string) error {
+	m.Bodies = append(m.Bodies, body)
+	return nil
+}
+
+// MockLoginAttemptRepository мок для LoginAttemptRepository
+type MockLoginAttemptRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginAttemptRepository) RecordFailure(ctx context.Context, email, ip string, at, since time.Time) (int64, error) {
+	args := m.Called(ctx, email, ip, at, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockLoginAttemptRepository) ClearFailures(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+func (m *MockLoginAttemptRepository) ListFailures(ctx context.Context, since time.Time, limit int) ([]entity.FailedLoginStat, error) {
+	args := m.Called(ctx, since, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.FailedLoginStat), args.Error(1)
+}
+
+func (m *MockLoginAttemptRepository) Lock(ctx context.Context, lock *entity.AccountLock) error {
+	args := m.Called(ctx, lock)
+	return args.Error(0)
+}
+
+func (m *MockLoginAttemptRepository) GetLock(ctx context.Context, userID uuid.UUID) (*entity.AccountLock, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.AccountLock), args.Error(1)
+}
+
+func (m *MockLoginAttemptRepository) Unlock(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockLoginAttemptRepository) ListLocks(ctx context.Context) ([]entity.AccountLock, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.AccountLock), args.Error(1)
+}
+
+// MockBreachedPasswordChecker мок для BreachedPasswordChecker
+type MockBreachedPasswordChecker struct {
+	mock.Mock
+}
+
+func (m *MockBreachedPasswordChecker) IsBreached(ctx context.Context, password string) (bool, error) {
+	args := m.Called(ctx, password)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockImpersonationAuditRepository мок для ImpersonationAuditRepository
+type MockImpersonationAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockImpersonationAuditRepository) Create(ctx context.Context, record *entity.ImpersonationAudit) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
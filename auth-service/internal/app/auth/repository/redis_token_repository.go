@@ -2,20 +2,64 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/pkg/metrics"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/redis/go-redis/v9"
 )
 
+// sessionStatsBatch - сколько пользователей обрабатывается одним pipeline при подсчете сессий
+const sessionStatsBatch = 500
+
+// consumeTokenScript атомарно удаляет токен и запоминает его как обмененный до истечения срока
+// KEYS: ключ токена, ключ отметки обмененного токена; ARGV[1] = 'json' - значение токена в JSON с полем user_id,
+// иначе значение - ID пользователя. Возвращает {значение, пользователь из отметки, оставшийся срок в мс}:
+// пустое значение - токена нет, непустая отметка - он уже обменен
+var consumeTokenScript = redis.NewScript(`
+local value = redis.call('GET', KEYS[1])
+if not value then
+	return {'', redis.call('GET', KEYS[2]) or '', '0'}
+end
+local userID = value
+if ARGV[1] == 'json' then
+	userID = cjson.decode(value).user_id
+end
+local ttl = redis.call('PTTL', KEYS[1])
+redis.call('DEL', KEYS[1])
+if ttl > 0 then
+	redis.call('SET', KEYS[2], userID, 'PX', ttl)
+end
+return {value, '', tostring(ttl)}
+`)
+
+// tokenStatsRetention - сколько дней хранятся счетчики использования refresh токенов
+const tokenStatsRetention = 31 * 24 * time.Hour
+
+// Тип токена в метриках обмена и выдачи
+const (
+	tokenTypeRefresh  = "refresh"
+	tokenTypeRemember = "remember"
+)
+
+// refreshOutcomes - исход обмена в метрике AuthRefreshTokens для поля дневной статистики
+var refreshOutcomes = map[string]string{
+	"rotated":  "success",
+	"rejected": "failure",
+	"reused":   "reuse_detected",
+}
+
 type redisTokenRepository struct {
-	client *redis.Client
+	client redis.UniversalClient
 }
 
-func NewRedisTokenRepository(client *redis.Client) TokenRepository {
+func NewRedisTokenRepository(client redis.UniversalClient) TokenRepository {
 	return &redisTokenRepository{client: client}
 }
 
@@ -39,6 +83,7 @@ func (r *redisTokenRepository) SaveRefreshToken(ctx context.Context, userID uuid
 	}
 
 	r.client.Expire(ctx, userTokensKey, ttl)
+	r.countRefreshUsage(ctx, tokenTypeRefresh, "issued")
 
 	return nil
 }
@@ -48,7 +93,12 @@ func (r *redisTokenRepository) GetRefreshToken(ctx context.Context, token string
 
 	userIDStr, err := r.client.Get(ctx, key).Result()
 	if err == redis.Nil {
-		return nil, fmt.Errorf("refresh token not found")
+		if reuse := r.detectReuse(ctx, usedRefreshTokenKey(token)); reuse != nil {
+			r.countRefreshUsage(ctx, tokenTypeRefresh, "reused")
+			return nil, reuse
+		}
+		r.countRefreshUsage(ctx, tokenTypeRefresh, "rejected")
+		return nil, fmt.Errorf("refresh token not found: %w", pgx.ErrNoRows)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get refresh token from Redis: %w", err)
@@ -79,6 +129,7 @@ func (r *redisTokenRepository) DeleteRefreshToken(ctx context.Context, token str
 	if err != nil && err != redis.Nil {
 		return fmt.Errorf("failed to get user ID for token: %w", err)
 	}
+	ttl, _ := r.client.TTL(ctx, key).Result()
 
 	err = r.client.Del(ctx, key).Err()
 	if err != nil {
@@ -88,6 +139,8 @@ func (r *redisTokenRepository) DeleteRefreshToken(ctx context.Context, token str
 	if userIDStr != "" {
 		userTokensKey := fmt.Sprintf("user_tokens:%s", userIDStr)
 		r.client.SRem(ctx, userTokensKey, token)
+		r.markUsed(ctx, usedRefreshTokenKey(token), userIDStr, ttl)
+		r.countRefreshUsage(ctx, tokenTypeRefresh, "rotated")
 	}
 
 	return nil
@@ -111,9 +164,210 @@ func (r *redisTokenRepository) DeleteUserRefreshTokens(ctx context.Context, user
 		return fmt.Errorf("failed to delete user tokens set: %w", err)
 	}
 
+	// Токены "запомнить меня" живут в отдельном пространстве ключей
+	userRememberKey := userRememberTokensKey(userID.String())
+	rememberTokens, err := r.client.SMembers(ctx, userRememberKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get user remember tokens: %w", err)
+	}
+	for _, token := range rememberTokens {
+		r.client.Del(ctx, rememberTokenKey(token))
+	}
+	if err := r.client.Del(ctx, userRememberKey).Err(); err != nil {
+		return fmt.Errorf("failed to delete user remember tokens set: %w", err)
+	}
+
+	return nil
+}
+
+func (r *redisTokenRepository) SaveRememberToken(ctx context.Context, token *entity.RememberToken) error {
+	ttl := time.Until(token.ExpiresAt)
+	if ttl <= 0 {
+		return fmt.Errorf("token already expired")
+	}
+
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to marshal remember token: %w", err)
+	}
+
+	if err := r.client.Set(ctx, rememberTokenKey(token.Token), data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save remember token to Redis: %w", err)
+	}
+
+	// Набор живет не меньше самого свежего токена пользователя
+	userRememberKey := userRememberTokensKey(token.UserID.String())
+	if err := r.client.SAdd(ctx, userRememberKey, token.Token).Err(); err != nil {
+		return fmt.Errorf("failed to add token to user remember tokens set: %w", err)
+	}
+	if current, err := r.client.TTL(ctx, userRememberKey).Result(); err == nil && current < ttl {
+		r.client.Expire(ctx, userRememberKey, ttl)
+	}
+	r.countRefreshUsage(ctx, tokenTypeRemember, "issued")
+
+	return nil
+}
+
+// GetRememberToken возвращает pgx.ErrNoRows для отсутствующего или истекшего токена
+// и *TokenReuseError для уже обмененного
+func (r *redisTokenRepository) GetRememberToken(ctx context.Context, token string) (*entity.RememberToken, error) {
+	data, err := r.client.Get(ctx, rememberTokenKey(token)).Bytes()
+	if err == redis.Nil {
+		if reuse := r.detectReuse(ctx, usedRememberTokenKey(token)); reuse != nil {
+			r.countRefreshUsage(ctx, tokenTypeRemember, "reused")
+			return nil, reuse
+		}
+		r.countRefreshUsage(ctx, tokenTypeRemember, "rejected")
+		return nil, pgx.ErrNoRows
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remember token from Redis: %w", err)
+	}
+
+	var rt entity.RememberToken
+	if err := json.Unmarshal(data, &rt); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal remember token: %w", err)
+	}
+	rt.Token = token
+
+	return &rt, nil
+}
+
+func (r *redisTokenRepository) DeleteRememberToken(ctx context.Context, token string) error {
+	// Ключ читается напрямую: удаление отсутствующего токена не отклонение и не повторное предъявление
+	data, err := r.client.Get(ctx, rememberTokenKey(token)).Bytes()
+	if err != nil && err != redis.Nil {
+		return fmt.Errorf("failed to get remember token from Redis: %w", err)
+	}
+
+	var rt *entity.RememberToken
+	if err == nil {
+		rt = &entity.RememberToken{}
+		if err := json.Unmarshal(data, rt); err != nil {
+			return fmt.Errorf("failed to unmarshal remember token: %w", err)
+		}
+	}
+
+	if err := r.client.Del(ctx, rememberTokenKey(token)).Err(); err != nil {
+		return fmt.Errorf("failed to delete remember token from Redis: %w", err)
+	}
+
+	if rt != nil {
+		r.client.SRem(ctx, userRememberTokensKey(rt.UserID.String()), token)
+		r.markUsed(ctx, usedRememberTokenKey(token), rt.UserID.String(), time.Until(rt.ExpiresAt))
+		r.countRefreshUsage(ctx, tokenTypeRemember, "rotated")
+	}
+
 	return nil
 }
 
+// ConsumeRefreshToken атомарно обменивает refresh токен: из параллельных обменов одного токена проходит один,
+// остальные получают *TokenReuseError
+func (r *redisTokenRepository) ConsumeRefreshToken(ctx context.Context, token string) (*entity.RefreshToken, error) {
+	userIDStr, ttl, err := r.consume(ctx, tokenTypeRefresh, fmt.Sprintf("refresh_token:%s", token), usedRefreshTokenKey(token), false)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("refresh token not found: %w", err)
+		}
+		return nil, err
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID in Redis: %w", err)
+	}
+	r.client.SRem(ctx, fmt.Sprintf("user_tokens:%s", userIDStr), token)
+
+	return &entity.RefreshToken{
+		UserID:    userID,
+		Token:     token,
+		ExpiresAt: time.Now().Add(ttl),
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// ConsumeRememberToken атомарно обменивает токен "запомнить меня", как ConsumeRefreshToken
+func (r *redisTokenRepository) ConsumeRememberToken(ctx context.Context, token string) (*entity.RememberToken, error) {
+	data, _, err := r.consume(ctx, tokenTypeRemember, rememberTokenKey(token), usedRememberTokenKey(token), true)
+	if err != nil {
+		return nil, err
+	}
+
+	var rt entity.RememberToken
+	if err := json.Unmarshal([]byte(data), &rt); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal remember token: %w", err)
+	}
+	rt.Token = token
+	r.client.SRem(ctx, userRememberTokensKey(rt.UserID.String()), token)
+
+	return &rt, nil
+}
+
+// consume забирает токен скриптом consumeTokenScript и возвращает его значение и оставшийся срок
+// pgx.ErrNoRows - токена нет, *TokenReuseError - он уже обменен
+func (r *redisTokenRepository) consume(ctx context.Context, tokenType, key, usedKey string, jsonValue bool) (string, time.Duration, error) {
+	format := ""
+	if jsonValue {
+		format = "json"
+	}
+
+	result, err := consumeTokenScript.Run(ctx, r.client, []string{key, usedKey}, format).StringSlice()
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to consume %s token: %w", tokenType, err)
+	}
+
+	value, usedBy, ttl := result[0], result[1], result[2]
+	if value == "" {
+		if userID, err := uuid.Parse(usedBy); err == nil {
+			r.countRefreshUsage(ctx, tokenType, "reused")
+			return "", 0, &TokenReuseError{UserID: userID}
+		}
+		r.countRefreshUsage(ctx, tokenType, "rejected")
+		return "", 0, pgx.ErrNoRows
+	}
+
+	r.countRefreshUsage(ctx, tokenType, "rotated")
+	ms, _ := strconv.ParseInt(ttl, 10, 64)
+	return value, time.Duration(ms) * time.Millisecond, nil
+}
+
+// markUsed запоминает обмененный токен до истечения его срока, чтобы распознать повторное предъявление
+func (r *redisTokenRepository) markUsed(ctx context.Context, key, userID string, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	r.client.Set(ctx, key, userID, ttl)
+}
+
+// detectReuse возвращает *TokenReuseError, если токен уже был обменен
+func (r *redisTokenRepository) detectReuse(ctx context.Context, key string) *TokenReuseError {
+	userIDStr, err := r.client.Get(ctx, key).Result()
+	if err != nil {
+		return nil
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return nil
+	}
+	return &TokenReuseError{UserID: userID}
+}
+
+func usedRefreshTokenKey(token string) string {
+	return fmt.Sprintf("used_refresh_token:%s", token)
+}
+
+func usedRememberTokenKey(token string) string {
+	return fmt.Sprintf("used_remember_token:%s", token)
+}
+
+func rememberTokenKey(token string) string {
+	return fmt.Sprintf("remember_token:%s", token)
+}
+
+func userRememberTokensKey(userID string) string {
+	return fmt.Sprintf("user_remember_tokens:%s", userID)
+}
+
 func (r *redisTokenRepository) AddToBlacklist(ctx context.Context, token string, expiresAt time.Time) error {
 	key := fmt.Sprintf("blacklist:%s", token)
 
@@ -137,6 +391,9 @@ func (r *redisTokenRepository) IsBlacklisted(ctx context.Context, token string)
 	if err != nil {
 		return false, fmt.Errorf("failed to check if token is blacklisted: %w", err)
 	}
+	if exists > 0 {
+		metrics.AuthBlacklistHits.Inc()
+	}
 
 	return exists > 0, nil
 }
@@ -145,3 +402,130 @@ func (r *redisTokenRepository) IsBlacklisted(ctx context.Context, token string)
 func (r *redisTokenRepository) CleanupExpiredTokens(ctx context.Context) error {
 	return nil
 }
+
+// Stats считает ключи токенов и читает дневные счетчики refresh токенов
+func (r *redisTokenRepository) Stats(ctx context.Context, days int) (*entity.TokenStats, error) {
+	stats := &entity.TokenStats{}
+
+	var err error
+	if stats.BlacklistedTokens, err = r.countKeys(ctx, "blacklist:*"); err != nil {
+		return nil, err
+	}
+	if stats.ActiveRefreshTokens, err = r.countKeys(ctx, "refresh_token:*"); err != nil {
+		return nil, err
+	}
+	if stats.UsersWithRefreshTokens, err = r.countKeys(ctx, "user_tokens:*"); err != nil {
+		return nil, err
+	}
+	if stats.RememberMeTokens, err = r.countKeys(ctx, "remember_token:*"); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	for i := 0; i < days; i++ {
+		date := now.AddDate(0, 0, -i).Format(time.DateOnly)
+		counters, err := r.client.HGetAll(ctx, tokenStatsKey(date)).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get refresh token usage: %w", err)
+		}
+
+		usage := entity.RefreshTokenUsage{Date: date}
+		usage.Issued, _ = strconv.ParseInt(counters["issued"], 10, 64)
+		usage.Rotated, _ = strconv.ParseInt(counters["rotated"], 10, 64)
+		usage.Rejected, _ = strconv.ParseInt(counters["rejected"], 10, 64)
+		usage.Reused, _ = strconv.ParseInt(counters["reused"], 10, 64)
+		stats.RefreshUsage = append(stats.RefreshUsage, usage)
+	}
+
+	return stats, nil
+}
+
+// SessionStats считает сессии по наборам токенов пользователей
+// В наборах остаются и истекшие токены, поэтому учитываются только токены с живым ключом
+func (r *redisTokenRepository) SessionStats(ctx context.Context, userIDs []uuid.UUID) (*entity.SessionStats, error) {
+	stats := &entity.SessionStats{}
+
+	for start := 0; start < len(userIDs); start += sessionStatsBatch {
+		batch := userIDs[start:min(start+sessionStatsBatch, len(userIDs))]
+
+		pipe := r.client.Pipeline()
+		refresh := make([]*redis.StringSliceCmd, len(batch))
+		remember := make([]*redis.StringSliceCmd, len(batch))
+		for i, userID := range batch {
+			refresh[i] = pipe.SMembers(ctx, fmt.Sprintf("user_tokens:%s", userID))
+			remember[i] = pipe.SMembers(ctx, userRememberTokensKey(userID.String()))
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get user sessions: %w", err)
+		}
+
+		pipe = r.client.Pipeline()
+		liveRefresh := make([]*redis.IntCmd, len(batch))
+		liveRemember := make([]*redis.IntCmd, len(batch))
+		for i := range batch {
+			if tokens := refresh[i].Val(); len(tokens) > 0 {
+				keys := make([]string, len(tokens))
+				for j, token := range tokens {
+					keys[j] = fmt.Sprintf("refresh_token:%s", token)
+				}
+				liveRefresh[i] = pipe.Exists(ctx, keys...)
+			}
+			if tokens := remember[i].Val(); len(tokens) > 0 {
+				keys := make([]string, len(tokens))
+				for j, token := range tokens {
+					keys[j] = rememberTokenKey(token)
+				}
+				liveRemember[i] = pipe.Exists(ctx, keys...)
+			}
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("failed to check user sessions: %w", err)
+		}
+
+		for i := range batch {
+			if liveRefresh[i] != nil && liveRefresh[i].Val() > 0 {
+				stats.Users++
+				stats.Sessions += liveRefresh[i].Val()
+			}
+			if liveRemember[i] != nil {
+				stats.RememberMe += liveRemember[i].Val()
+				stats.Sessions += liveRemember[i].Val()
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// countKeys считает ключи по шаблону через SCAN, не блокируя Redis как KEYS
+func (r *redisTokenRepository) countKeys(ctx context.Context, pattern string) (int64, error) {
+	var count int64
+	iter := r.client.Scan(ctx, 0, pattern, 1000).Iterator()
+	for iter.Next(ctx) {
+		count++
+	}
+	if err := iter.Err(); err != nil {
+		return 0, fmt.Errorf("failed to count %s keys: %w", pattern, err)
+	}
+	return count, nil
+}
+
+// countRefreshUsage увеличивает дневной счетчик refresh токенов и метрику Prometheus
+// Ошибка счетчика не должна ломать выдачу токенов, поэтому игнорируется
+func (r *redisTokenRepository) countRefreshUsage(ctx context.Context, tokenType, field string) {
+	if field == "issued" {
+		metrics.AuthTokensIssued.WithLabelValues(tokenType).Inc()
+	} else {
+		metrics.AuthRefreshTokens.WithLabelValues(tokenType, refreshOutcomes[field]).Inc()
+	}
+
+	key := tokenStatsKey(time.Now().UTC().Format(time.DateOnly))
+	pipe := r.client.Pipeline()
+	pipe.HIncrBy(ctx, key, field, 1)
+	pipe.Expire(ctx, key, tokenStatsRetention)
+	pipe.Exec(ctx)
+}
+
+func tokenStatsKey(date string) string {
+	return fmt.Sprintf("token_stats:%s", date)
+}
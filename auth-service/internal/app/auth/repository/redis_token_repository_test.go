@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/pkg/metrics"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ===== Refresh Token Tests =====
+
+func TestRedisTokenRepository_DetectsRefreshTokenReuse(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewRedisTokenRepository(newTestRedis(t))
+	userID := uuid.New()
+	reused := testutil.ToFloat64(metrics.AuthRefreshTokens.WithLabelValues(tokenTypeRefresh, "reuse_detected"))
+	failed := testutil.ToFloat64(metrics.AuthRefreshTokens.WithLabelValues(tokenTypeRefresh, "failure"))
+
+	require.NoError(t, repo.SaveRefreshToken(ctx, userID, "token", time.Now().Add(time.Hour)))
+	require.NoError(t, repo.DeleteRefreshToken(ctx, "token"))
+
+	// Act
+	_, reuseErr := repo.GetRefreshToken(ctx, "token")
+	_, unknownErr := repo.GetRefreshToken(ctx, "unknown")
+
+	// Assert
+	var reuse *TokenReuseError
+	require.ErrorAs(t, reuseErr, &reuse)
+	assert.Equal(t, userID, reuse.UserID)
+	assert.ErrorIs(t, unknownErr, pgx.ErrNoRows)
+
+	assert.Equal(t, reused+1, testutil.ToFloat64(metrics.AuthRefreshTokens.WithLabelValues(tokenTypeRefresh, "reuse_detected")))
+	assert.Equal(t, failed+1, testutil.ToFloat64(metrics.AuthRefreshTokens.WithLabelValues(tokenTypeRefresh, "failure")))
+
+	stats, err := repo.Stats(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.RefreshUsage[0].Reused)
+}
+
+func TestRedisTokenRepository_ConsumeTokenOnce(t *testing.T) {
+	tests := []struct {
+		name    string
+		save    func(ctx context.Context, repo TokenRepository, userID uuid.UUID) error
+		consume func(ctx context.Context, repo TokenRepository) (uuid.UUID, error)
+	}{
+		{
+			name: "refresh token",
+			save: func(ctx context.Context, repo TokenRepository, userID uuid.UUID) error {
+				return repo.SaveRefreshToken(ctx, userID, "token", time.Now().Add(time.Hour))
+			},
+			consume: func(ctx context.Context, repo TokenRepository) (uuid.UUID, error) {
+				rt, err := repo.ConsumeRefreshToken(ctx, "token")
+				if err != nil {
+					return uuid.Nil, err
+				}
+				return rt.UserID, nil
+			},
+		},
+		{
+			name: "remember token",
+			save: func(ctx context.Context, repo TokenRepository, userID uuid.UUID) error {
+				return repo.SaveRememberToken(ctx, &entity.RememberToken{
+					Token: "token", UserID: userID, SessionStartedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour),
+				})
+			},
+			consume: func(ctx context.Context, repo TokenRepository) (uuid.UUID, error) {
+				rt, err := repo.ConsumeRememberToken(ctx, "token")
+				if err != nil {
+					return uuid.Nil, err
+				}
+				return rt.UserID, nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			repo := NewRedisTokenRepository(newTestRedis(t))
+			userID := uuid.New()
+			require.NoError(t, tt.save(ctx, repo, userID))
+
+			// Act: параллельные обмены одного токена
+			const attempts = 5
+			results := make(chan error, attempts)
+			var wg sync.WaitGroup
+			for i := 0; i < attempts; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					got, err := tt.consume(ctx, repo)
+					if err == nil && got != userID {
+						err = fmt.Errorf("unexpected user %s", got)
+					}
+					results <- err
+				}()
+			}
+			wg.Wait()
+			close(results)
+
+			// Assert: новую пару получает один обмен, остальные распознаются как повторное предъявление
+			var succeeded int
+			for err := range results {
+				if err == nil {
+					succeeded++
+					continue
+				}
+				var reuse *TokenReuseError
+				require.ErrorAs(t, err, &reuse)
+				assert.Equal(t, userID, reuse.UserID)
+			}
+			assert.Equal(t, 1, succeeded)
+
+			_, err := tt.consume(ctx, repo)
+			var reuse *TokenReuseError
+			assert.ErrorAs(t, err, &reuse)
+		})
+	}
+}
+
+func TestRedisTokenRepository_ConsumeUnknownToken(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewRedisTokenRepository(newTestRedis(t))
+
+	// Act
+	_, refreshErr := repo.ConsumeRefreshToken(ctx, "unknown")
+	_, rememberErr := repo.ConsumeRememberToken(ctx, "unknown")
+
+	// Assert
+	assert.ErrorIs(t, refreshErr, pgx.ErrNoRows)
+	assert.ErrorIs(t, rememberErr, pgx.ErrNoRows)
+}
+
+func TestRedisTokenRepository_BlacklistHits(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewRedisTokenRepository(newTestRedis(t))
+	hits := testutil.ToFloat64(metrics.AuthBlacklistHits)
+	require.NoError(t, repo.AddToBlacklist(ctx, "revoked", time.Now().Add(time.Hour)))
+
+	// Act
+	revoked, err := repo.IsBlacklisted(ctx, "revoked")
+	require.NoError(t, err)
+	active, err := repo.IsBlacklisted(ctx, "active")
+	require.NoError(t, err)
+
+	// Assert
+	assert.True(t, revoked)
+	assert.False(t, active)
+	assert.Equal(t, hits+1, testutil.ToFloat64(metrics.AuthBlacklistHits))
+}
+
+func TestRedisTokenRepository_SessionStats(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewRedisTokenRepository(newTestRedis(t))
+	tenantUser, rememberUser, otherUser, idleUser := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	require.NoError(t, repo.SaveRefreshToken(ctx, tenantUser, "first", time.Now().Add(time.Hour)))
+	require.NoError(t, repo.SaveRefreshToken(ctx, tenantUser, "second", time.Now().Add(time.Hour)))
+	require.NoError(t, repo.SaveRefreshToken(ctx, tenantUser, "rotated", time.Now().Add(time.Hour)))
+	require.NoError(t, repo.DeleteRefreshToken(ctx, "rotated"))
+	require.NoError(t, repo.SaveRememberToken(ctx, &entity.RememberToken{
+		Token: "rm_token", UserID: rememberUser, SessionStartedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour),
+	}))
+	require.NoError(t, repo.SaveRefreshToken(ctx, otherUser, "other", time.Now().Add(time.Hour)))
+
+	// Act
+	stats, err := repo.SessionStats(ctx, []uuid.UUID{tenantUser, rememberUser, idleUser})
+
+	// Assert: токены пользователей вне списка и обмененные токены не учитываются
+	require.NoError(t, err)
+	assert.Equal(t, &entity.SessionStats{Sessions: 3, Users: 1, RememberMe: 1}, stats)
+}
+
+// ===== Remember Token Tests =====
+
+func TestRedisTokenRepository_RememberTokens(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewRedisTokenRepository(newTestRedis(t))
+	userID := uuid.New()
+	started := time.Now().Add(-time.Hour).UTC()
+
+	// Act
+	require.NoError(t, repo.SaveRememberToken(ctx, &entity.RememberToken{
+		Token:            "rm_token",
+		UserID:           userID,
+		SessionStartedAt: started,
+		ExpiresAt:        time.Now().Add(24 * time.Hour),
+	}))
+	got, err := repo.GetRememberToken(ctx, "rm_token")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, userID, got.UserID)
+	assert.True(t, started.Equal(got.SessionStartedAt))
+
+	// Обычный refresh токен с тем же значением не существует: пространства ключей раздельные
+	_, err = repo.GetRefreshToken(ctx, "rm_token")
+	assert.Error(t, err)
+
+	stats, err := repo.Stats(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.RememberMeTokens)
+	assert.Equal(t, int64(1), stats.RefreshUsage[0].Issued)
+}
+
+func TestRedisTokenRepository_DeleteUserRefreshTokensRemovesRememberTokens(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewRedisTokenRepository(newTestRedis(t))
+	userID := uuid.New()
+
+	require.NoError(t, repo.SaveRememberToken(ctx, &entity.RememberToken{
+		Token: "rm_token", UserID: userID, SessionStartedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour),
+	}))
+
+	// Act
+	require.NoError(t, repo.DeleteUserRefreshTokens(ctx, userID))
+
+	// Assert
+	_, err := repo.GetRememberToken(ctx, "rm_token")
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func TestRedisTokenRepository_DeleteRememberTokenDoesNotCountRejections(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewRedisTokenRepository(newTestRedis(t))
+	rejected := testutil.ToFloat64(metrics.AuthRefreshTokens.WithLabelValues(tokenTypeRemember, "failure"))
+	reused := testutil.ToFloat64(metrics.AuthRefreshTokens.WithLabelValues(tokenTypeRemember, "reuse_detected"))
+	rotated := testutil.ToFloat64(metrics.AuthRefreshTokens.WithLabelValues(tokenTypeRemember, "success"))
+
+	require.NoError(t, repo.SaveRememberToken(ctx, &entity.RememberToken{
+		Token: "rm_token", UserID: uuid.New(), SessionStartedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour),
+	}))
+
+	// Act
+	require.NoError(t, repo.DeleteRememberToken(ctx, "rm_token"))
+	// Повторное удаление уже обмененного и удаление неизвестного токена ничего не считают
+	require.NoError(t, repo.DeleteRememberToken(ctx, "rm_token"))
+	require.NoError(t, repo.DeleteRememberToken(ctx, "unknown"))
+
+	// Assert
+	assert.Equal(t, rejected, testutil.ToFloat64(metrics.AuthRefreshTokens.WithLabelValues(tokenTypeRemember, "failure")))
+	assert.Equal(t, reused, testutil.ToFloat64(metrics.AuthRefreshTokens.WithLabelValues(tokenTypeRemember, "reuse_detected")))
+	assert.Equal(t, rotated+1, testutil.ToFloat64(metrics.AuthRefreshTokens.WithLabelValues(tokenTypeRemember, "success")))
+
+	stats, err := repo.Stats(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stats.RefreshUsage[0].Rejected)
+	assert.Equal(t, int64(0), stats.RefreshUsage[0].Reused)
+	assert.Equal(t, int64(1), stats.RefreshUsage[0].Rotated)
+
+	_, err = repo.GetRememberToken(ctx, "rm_token")
+	var reuse *TokenReuseError
+	assert.ErrorAs(t, err, &reuse)
+}
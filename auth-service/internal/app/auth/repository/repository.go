@@ -16,6 +16,17 @@ type UserRepository interface {
 	Update(ctx context.Context, user *entity.User) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	List(ctx context.Context) ([]entity.User, error)
+	// CountCreatedSince возвращает число пользователей магазина из контекста, зарегистрированных начиная с since
+	CountCreatedSince(ctx context.Context, since time.Time) (int64, error)
+	// ListTenantUserIDs возвращает ID пользователей, зарегистрированных в магазине из контекста
+	ListTenantUserIDs(ctx context.Context) ([]uuid.UUID, error)
+
+	// Роли пользователя в других магазинах; роль в магазине регистрации хранится в users.role_id
+	ListTenantRoles(ctx context.Context, userID uuid.UUID) ([]entity.TenantRole, error)
+	// SetTenantRole выдает роль в магазине или заменяет выданную ранее
+	SetTenantRole(ctx context.Context, userID uuid.UUID, tenantID string, roleID int) error
+	// DeleteTenantRole отзывает роль в магазине, pgx.ErrNoRows - роли не было
+	DeleteTenantRole(ctx context.Context, userID uuid.UUID, tenantID string) error
 }
 
 type RoleRepository interface {
@@ -33,15 +44,78 @@ type RoleRepository interface {
 
 	AssignPermissions(ctx context.Context, roleID int, permissionIDs []int) error
 	RemovePermissions(ctx context.Context, roleID int, permissionIDs []int) error
+
+	// Начальное заполнение и шаблоны ролей: существующие роли и разрешения не изменяются
+	EnsurePermissions(ctx context.Context, permissions []entity.Permission) error
+	CreateWithPermissions(ctx context.Context, role *entity.Role, permissions []entity.Permission) (bool, error)
+	GrantAllPermissions(ctx context.Context, roleName string) error
+}
+
+// TokenReuseError - предъявлен уже обмененный refresh токен: его, вероятно, украли
+// Семейство токенов - все сессии пользователя UserID; их следует отозвать
+type TokenReuseError struct {
+	UserID uuid.UUID
+}
+
+func (e *TokenReuseError) Error() string {
+	return "refresh token reuse detected"
 }
 
 type TokenRepository interface {
 	SaveRefreshToken(ctx context.Context, userID uuid.UUID, token string, expiresAt time.Time) error
 	GetRefreshToken(ctx context.Context, token string) (*entity.RefreshToken, error)
 	DeleteRefreshToken(ctx context.Context, token string) error
+	// ConsumeRefreshToken атомарно удаляет и возвращает refresh токен для обмена: параллельный обмен того же токена
+	// его уже не получит. pgx.ErrNoRows - токена нет, *TokenReuseError - он уже обменен
+	ConsumeRefreshToken(ctx context.Context, token string) (*entity.RefreshToken, error)
+	// DeleteUserRefreshTokens удаляет все refresh токены пользователя, включая "запомнить меня"
 	DeleteUserRefreshTokens(ctx context.Context, userID uuid.UUID) error
 
+	// Токены "запомнить меня" хранятся отдельно от обычных refresh токенов
+	SaveRememberToken(ctx context.Context, token *entity.RememberToken) error
+	GetRememberToken(ctx context.Context, token string) (*entity.RememberToken, error)
+	DeleteRememberToken(ctx context.Context, token string) error
+	// ConsumeRememberToken атомарно удаляет и возвращает токен "запомнить меня", как ConsumeRefreshToken
+	ConsumeRememberToken(ctx context.Context, token string) (*entity.RememberToken, error)
+
 	AddToBlacklist(ctx context.Context, token string, expiresAt time.Time) error
 	IsBlacklisted(ctx context.Context, token string) (bool, error)
 	CleanupExpiredTokens(ctx context.Context) error
+
+	// Stats возвращает число токенов и использование refresh токенов за последние days дней
+	Stats(ctx context.Context, days int) (*entity.TokenStats, error)
+	// SessionStats возвращает действующие сессии пользователей userIDs
+	SessionStats(ctx context.Context, userIDs []uuid.UUID) (*entity.SessionStats, error)
+}
+
+type DeviceRepository interface {
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]entity.Device, error)
+	// Upsert запоминает устройство или обновляет время и место последнего входа с него
+	Upsert(ctx context.Context, device *entity.Device) error
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+}
+
+type LoginChallengeRepository interface {
+	Save(ctx context.Context, challenge *entity.LoginChallenge) error
+	Get(ctx context.Context, id string) (*entity.LoginChallenge, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// LoginAttemptRepository хранит неудачные входы и блокировки аккаунтов
+type LoginAttemptRepository interface {
+	// RecordFailure запоминает неудачный вход и возвращает число неудач по email начиная с since
+	RecordFailure(ctx context.Context, email, ip string, at, since time.Time) (int64, error)
+	ClearFailures(ctx context.Context, email string) error
+	// ListFailures возвращает аккаунты с неудачными входами после since, свежие первыми
+	ListFailures(ctx context.Context, since time.Time, limit int) ([]entity.FailedLoginStat, error)
+
+	Lock(ctx context.Context, lock *entity.AccountLock) error
+	GetLock(ctx context.Context, userID uuid.UUID) (*entity.AccountLock, error)
+	Unlock(ctx context.Context, userID uuid.UUID) error
+	ListLocks(ctx context.Context) ([]entity.AccountLock, error)
+}
+
+// ImpersonationAuditRepository - журнал выдачи токенов имперсонации
+type ImpersonationAuditRepository interface {
+	Create(ctx context.Context, record *entity.ImpersonationAudit) error
 }
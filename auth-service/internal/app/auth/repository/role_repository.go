@@ -8,6 +8,7 @@ import (
 	"augustberries/auth-service/internal/app/auth/entity"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -250,3 +251,93 @@ func (r *roleRepository) RemovePermissions(ctx context.Context, roleID int, perm
 
 	return nil
 }
+
+// EnsurePermissions создает отсутствующие разрешения; у существующих разрешений описание не меняется
+func (r *roleRepository) EnsurePermissions(ctx context.Context, permissions []entity.Permission) error {
+	return ensurePermissions(ctx, r.db, permissions)
+}
+
+// CreateWithPermissions в одной транзакции создает роль, недостающие разрешения и связывает их с ролью
+// Если роль с таким именем уже есть, ничего не меняется и возвращается false
+func (r *roleRepository) CreateWithPermissions(ctx context.Context, role *entity.Role, permissions []entity.Permission) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := ensurePermissions(ctx, tx, permissions); err != nil {
+		return false, err
+	}
+
+	insertRole := `
+		INSERT INTO roles (name, description)
+		VALUES ($1, $2)
+		ON CONFLICT (name) DO NOTHING
+		RETURNING id
+	`
+	err = tx.QueryRow(ctx, insertRole, role.Name, role.Description).Scan(&role.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to create role: %w", err)
+	}
+
+	codes := make([]string, len(permissions))
+	for i, p := range permissions {
+		codes[i] = p.Code
+	}
+	linkQuery := `
+		INSERT INTO roles_permissions (role_id, permission_id)
+		SELECT $1, id FROM permissions WHERE code = ANY($2)
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := tx.Exec(ctx, linkQuery, role.ID, codes); err != nil {
+		return false, fmt.Errorf("failed to assign permissions: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// GrantAllPermissions добавляет роли все существующие разрешения, уже назначенные не трогает
+func (r *roleRepository) GrantAllPermissions(ctx context.Context, roleName string) error {
+	query := `
+		INSERT INTO roles_permissions (role_id, permission_id)
+		SELECT r.id, p.id
+		FROM roles r, permissions p
+		WHERE r.name = $1
+		ON CONFLICT DO NOTHING
+	`
+
+	if _, err := r.db.Exec(ctx, query, roleName); err != nil {
+		return fmt.Errorf("failed to grant permissions: %w", err)
+	}
+
+	return nil
+}
+
+// execer - пул соединений или транзакция
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+func ensurePermissions(ctx context.Context, db execer, permissions []entity.Permission) error {
+	query := `
+		INSERT INTO permissions (code, description)
+		VALUES ($1, $2)
+		ON CONFLICT (code) DO NOTHING
+	`
+
+	for _, p := range permissions {
+		if _, err := db.Exec(ctx, query, p.Code, p.Description); err != nil {
+			return fmt.Errorf("failed to create permission %s: %w", p.Code, err)
+		}
+	}
+
+	return nil
+}
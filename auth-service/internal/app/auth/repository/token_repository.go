@@ -2,12 +2,15 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/pkg/metrics"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -29,6 +32,7 @@ func (r *tokenRepository) SaveRefreshToken(ctx context.Context, userID uuid.UUID
 	if err != nil {
 		return fmt.Errorf("failed to save refresh token: %w", err)
 	}
+	metrics.AuthTokensIssued.WithLabelValues(tokenTypeRefresh).Inc()
 
 	return nil
 }
@@ -50,6 +54,10 @@ func (r *tokenRepository) GetRefreshToken(ctx context.Context, token string) (*e
 	)
 
 	if err != nil {
+		// Обмененные токены удаляются, поэтому повторное предъявление здесь не отличить от неизвестного токена
+		if errors.Is(err, pgx.ErrNoRows) {
+			metrics.AuthRefreshTokens.WithLabelValues(tokenTypeRefresh, "failure").Inc()
+		}
 		return nil, fmt.Errorf("failed to get refresh token: %w", err)
 	}
 
@@ -63,6 +71,7 @@ func (r *tokenRepository) DeleteRefreshToken(ctx context.Context, token string)
 	if err != nil {
 		return fmt.Errorf("failed to delete refresh token: %w", err)
 	}
+	metrics.AuthRefreshTokens.WithLabelValues(tokenTypeRefresh, "success").Inc()
 
 	return nil
 }
@@ -75,9 +84,118 @@ func (r *tokenRepository) DeleteUserRefreshTokens(ctx context.Context, userID uu
 		return fmt.Errorf("failed to delete user refresh tokens: %w", err)
 	}
 
+	if _, err := r.db.Exec(ctx, `DELETE FROM remember_tokens WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete user remember tokens: %w", err)
+	}
+
+	return nil
+}
+
+func (r *tokenRepository) SaveRememberToken(ctx context.Context, token *entity.RememberToken) error {
+	query := `
+		INSERT INTO remember_tokens (token, user_id, session_started_at, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.Exec(ctx, query, token.Token, token.UserID, token.SessionStartedAt, token.ExpiresAt, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save remember token: %w", err)
+	}
+	metrics.AuthTokensIssued.WithLabelValues(tokenTypeRemember).Inc()
+
+	return nil
+}
+
+func (r *tokenRepository) GetRememberToken(ctx context.Context, token string) (*entity.RememberToken, error) {
+	query := `
+		SELECT token, user_id, session_started_at, expires_at
+		FROM remember_tokens
+		WHERE token = $1 AND expires_at > $2
+	`
+
+	var rt entity.RememberToken
+	err := r.db.QueryRow(ctx, query, token, time.Now()).Scan(
+		&rt.Token,
+		&rt.UserID,
+		&rt.SessionStartedAt,
+		&rt.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			metrics.AuthRefreshTokens.WithLabelValues(tokenTypeRemember, "failure").Inc()
+		}
+		return nil, err
+	}
+
+	return &rt, nil
+}
+
+func (r *tokenRepository) DeleteRememberToken(ctx context.Context, token string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM remember_tokens WHERE token = $1`, token)
+	if err != nil {
+		return fmt.Errorf("failed to delete remember token: %w", err)
+	}
+	metrics.AuthRefreshTokens.WithLabelValues(tokenTypeRemember, "success").Inc()
+
 	return nil
 }
 
+// ConsumeRefreshToken удаляет действующий refresh токен и возвращает его одним запросом:
+// из параллельных обменов одного токена строку получает только один
+func (r *tokenRepository) ConsumeRefreshToken(ctx context.Context, token string) (*entity.RefreshToken, error) {
+	query := `
+		DELETE FROM refresh_tokens
+		WHERE token = $1 AND expires_at > $2
+		RETURNING id, user_id, token, expires_at, created_at
+	`
+
+	var refreshToken entity.RefreshToken
+	err := r.db.QueryRow(ctx, query, token, time.Now()).Scan(
+		&refreshToken.ID,
+		&refreshToken.UserID,
+		&refreshToken.Token,
+		&refreshToken.ExpiresAt,
+		&refreshToken.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			metrics.AuthRefreshTokens.WithLabelValues(tokenTypeRefresh, "failure").Inc()
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to consume refresh token: %w", err)
+	}
+	metrics.AuthRefreshTokens.WithLabelValues(tokenTypeRefresh, "success").Inc()
+
+	return &refreshToken, nil
+}
+
+// ConsumeRememberToken удаляет действующий токен "запомнить меня" и возвращает его одним запросом
+func (r *tokenRepository) ConsumeRememberToken(ctx context.Context, token string) (*entity.RememberToken, error) {
+	query := `
+		DELETE FROM remember_tokens
+		WHERE token = $1 AND expires_at > $2
+		RETURNING token, user_id, session_started_at, expires_at
+	`
+
+	var rt entity.RememberToken
+	err := r.db.QueryRow(ctx, query, token, time.Now()).Scan(
+		&rt.Token,
+		&rt.UserID,
+		&rt.SessionStartedAt,
+		&rt.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			metrics.AuthRefreshTokens.WithLabelValues(tokenTypeRemember, "failure").Inc()
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to consume remember token: %w", err)
+	}
+	metrics.AuthRefreshTokens.WithLabelValues(tokenTypeRemember, "success").Inc()
+
+	return &rt, nil
+}
+
 func (r *tokenRepository) AddToBlacklist(ctx context.Context, token string, expiresAt time.Time) error {
 	query := `
 		INSERT INTO blacklisted_tokens (token, expires_at, created_at)
@@ -101,6 +219,9 @@ func (r *tokenRepository) IsBlacklisted(ctx context.Context, token string) (bool
 	if err != nil {
 		return false, fmt.Errorf("failed to check if token is blacklisted: %w", err)
 	}
+	if exists {
+		metrics.AuthBlacklistHits.Inc()
+	}
 
 	return exists, nil
 }
@@ -116,5 +237,87 @@ func (r *tokenRepository) CleanupExpiredTokens(ctx context.Context) error {
 		return fmt.Errorf("failed to cleanup expired blacklisted tokens: %w", err)
 	}
 
+	query3 := `DELETE FROM remember_tokens WHERE expires_at < $1`
+	if _, err := r.db.Exec(ctx, query3, time.Now()); err != nil {
+		return fmt.Errorf("failed to cleanup expired remember tokens: %w", err)
+	}
+
 	return nil
 }
+
+// Stats считает действующие токены в PostgreSQL
+// Обмен и отклонение refresh токенов здесь не отслеживаются: по дням считаются только выданные
+func (r *tokenRepository) Stats(ctx context.Context, days int) (*entity.TokenStats, error) {
+	now := time.Now()
+	stats := &entity.TokenStats{}
+
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM blacklisted_tokens WHERE expires_at > $1),
+			(SELECT COUNT(*) FROM refresh_tokens WHERE expires_at > $1),
+			(SELECT COUNT(DISTINCT user_id) FROM refresh_tokens WHERE expires_at > $1),
+			(SELECT COUNT(*) FROM remember_tokens WHERE expires_at > $1)
+	`
+	if err := r.db.QueryRow(ctx, query, now).Scan(
+		&stats.BlacklistedTokens,
+		&stats.ActiveRefreshTokens,
+		&stats.UsersWithRefreshTokens,
+		&stats.RememberMeTokens,
+	); err != nil {
+		return nil, fmt.Errorf("failed to count tokens: %w", err)
+	}
+
+	issued := make(map[string]int64, days)
+	rows, err := r.db.Query(ctx, `
+		SELECT to_char(created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD'), COUNT(*)
+		FROM refresh_tokens
+		WHERE created_at >= $1
+		GROUP BY 1
+	`, statsDay(now, days-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count issued refresh tokens: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var date string
+		var count int64
+		if err := rows.Scan(&date, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan refresh token usage: %w", err)
+		}
+		issued[date] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < days; i++ {
+		date := statsDay(now, i).Format(time.DateOnly)
+		stats.RefreshUsage = append(stats.RefreshUsage, entity.RefreshTokenUsage{Date: date, Issued: issued[date]})
+	}
+
+	return stats, nil
+}
+
+func (r *tokenRepository) SessionStats(ctx context.Context, userIDs []uuid.UUID) (*entity.SessionStats, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM refresh_tokens WHERE expires_at > $1 AND user_id = ANY($2)),
+			(SELECT COUNT(DISTINCT user_id) FROM refresh_tokens WHERE expires_at > $1 AND user_id = ANY($2)),
+			(SELECT COUNT(*) FROM remember_tokens WHERE expires_at > $1 AND user_id = ANY($2))
+	`
+
+	var refresh int64
+	stats := &entity.SessionStats{}
+	if err := r.db.QueryRow(ctx, query, time.Now(), userIDs).Scan(&refresh, &stats.Users, &stats.RememberMe); err != nil {
+		return nil, fmt.Errorf("failed to count sessions: %w", err)
+	}
+	stats.Sessions = refresh + stats.RememberMe
+
+	return stats, nil
+}
+
+// statsDay возвращает начало дня (UTC), отстоящего от now на daysAgo дней
+func statsDay(now time.Time, daysAgo int) time.Time {
+	return now.UTC().Truncate(24*time.Hour).AddDate(0, 0, -daysAgo)
+}
@@ -3,8 +3,10 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/pkg/tenant"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -21,13 +23,13 @@ func NewUserRepository(db *pgxpool.Pool) UserRepository {
 
 func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
 	query := `
-		INSERT INTO users (id, email, password_hash, name, role_id, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO users (id, email, password_hash, name, avatar_url, role_id, tenant_id, created_at, deactivated_at, preferred_currency)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 
 	_, err := r.db.Exec(
 		ctx, query,
-		user.ID, user.Email, user.PasswordHash, user.Name, user.RoleID, user.CreatedAt,
+		user.ID, user.Email, user.PasswordHash, user.Name, user.AvatarURL, user.RoleID, user.TenantID, user.CreatedAt, user.DeactivatedAt, user.PreferredCurrency,
 	)
 
 	if err != nil {
@@ -38,7 +40,7 @@ func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
 }
 
 func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
-	query := `SELECT id, email, password_hash, name, role_id, created_at FROM users WHERE id = $1`
+	query := `SELECT id, email, password_hash, name, avatar_url, role_id, tenant_id, created_at, deactivated_at, preferred_currency FROM users WHERE id = $1`
 
 	var user entity.User
 	err := r.db.QueryRow(ctx, query, id).Scan(
@@ -46,8 +48,12 @@ func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Use
 		&user.Email,
 		&user.PasswordHash,
 		&user.Name,
+		&user.AvatarURL,
 		&user.RoleID,
+		&user.TenantID,
 		&user.CreatedAt,
+		&user.DeactivatedAt,
+		&user.PreferredCurrency,
 	)
 
 	if err != nil {
@@ -58,7 +64,7 @@ func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Use
 }
 
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
-	query := `SELECT id, email, password_hash, name, role_id, created_at FROM users WHERE email = $1`
+	query := `SELECT id, email, password_hash, name, avatar_url, role_id, tenant_id, created_at, deactivated_at, preferred_currency FROM users WHERE email = $1`
 
 	var user entity.User
 	err := r.db.QueryRow(ctx, query, email).Scan(
@@ -66,8 +72,12 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entity.
 		&user.Email,
 		&user.PasswordHash,
 		&user.Name,
+		&user.AvatarURL,
 		&user.RoleID,
+		&user.TenantID,
 		&user.CreatedAt,
+		&user.DeactivatedAt,
+		&user.PreferredCurrency,
 	)
 
 	if err != nil {
@@ -80,13 +90,13 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entity.
 func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
 	query := `
 		UPDATE users 
-		SET email = $1, password_hash = $2, name = $3, role_id = $4
-		WHERE id = $5
+		SET email = $1, password_hash = $2, name = $3, avatar_url = $4, role_id = $5, deactivated_at = $6, preferred_currency = $7
+		WHERE id = $8
 	`
 
 	result, err := r.db.Exec(
 		ctx, query,
-		user.Email, user.PasswordHash, user.Name, user.RoleID, user.ID,
+		user.Email, user.PasswordHash, user.Name, user.AvatarURL, user.RoleID, user.DeactivatedAt, user.PreferredCurrency, user.ID,
 	)
 
 	if err != nil {
@@ -117,7 +127,7 @@ func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
 
 func (r *userRepository) List(ctx context.Context) ([]entity.User, error) {
 	query := `
-		SELECT id, email, password_hash, name, role_id, created_at 
+		SELECT id, email, password_hash, name, avatar_url, role_id, tenant_id, created_at, deactivated_at, preferred_currency 
 		FROM users 
 		ORDER BY created_at DESC
 	`
@@ -136,8 +146,12 @@ func (r *userRepository) List(ctx context.Context) ([]entity.User, error) {
 			&user.Email,
 			&user.PasswordHash,
 			&user.Name,
+			&user.AvatarURL,
 			&user.RoleID,
+			&user.TenantID,
 			&user.CreatedAt,
+			&user.DeactivatedAt,
+			&user.PreferredCurrency,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
@@ -151,3 +165,95 @@ func (r *userRepository) List(ctx context.Context) ([]entity.User, error) {
 
 	return users, nil
 }
+
+func (r *userRepository) CountCreatedSince(ctx context.Context, since time.Time) (int64, error) {
+	var count int64
+	query := `SELECT COUNT(*) FROM users WHERE created_at >= $1 AND tenant_id = $2`
+	if err := r.db.QueryRow(ctx, query, since, tenant.FromContext(ctx)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return count, nil
+}
+
+func (r *userRepository) ListTenantUserIDs(ctx context.Context) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(ctx, `SELECT id FROM users WHERE tenant_id = $1`, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenant users: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan tenant user: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tenant users: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (r *userRepository) ListTenantRoles(ctx context.Context, userID uuid.UUID) ([]entity.TenantRole, error) {
+	query := `
+		SELECT utr.user_id, utr.tenant_id, r.id, r.name,
+			COALESCE(array_agg(p.code ORDER BY p.code) FILTER (WHERE p.code IS NOT NULL), '{}')
+		FROM user_tenant_roles utr
+		INNER JOIN roles r ON r.id = utr.role_id
+		LEFT JOIN roles_permissions rp ON rp.role_id = r.id
+		LEFT JOIN permissions p ON p.id = rp.permission_id
+		WHERE utr.user_id = $1
+		GROUP BY utr.user_id, utr.tenant_id, r.id, r.name
+		ORDER BY utr.tenant_id
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenant roles: %w", err)
+	}
+	defer rows.Close()
+
+	var roles []entity.TenantRole
+	for rows.Next() {
+		var role entity.TenantRole
+		if err := rows.Scan(&role.UserID, &role.TenantID, &role.RoleID, &role.RoleName, &role.Permissions); err != nil {
+			return nil, fmt.Errorf("failed to scan tenant role: %w", err)
+		}
+		roles = append(roles, role)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tenant roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+func (r *userRepository) SetTenantRole(ctx context.Context, userID uuid.UUID, tenantID string, roleID int) error {
+	query := `
+		INSERT INTO user_tenant_roles (user_id, tenant_id, role_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, tenant_id) DO UPDATE SET role_id = EXCLUDED.role_id
+	`
+
+	if _, err := r.db.Exec(ctx, query, userID, tenantID, roleID); err != nil {
+		return fmt.Errorf("failed to set tenant role: %w", err)
+	}
+	return nil
+}
+
+func (r *userRepository) DeleteTenantRole(ctx context.Context, userID uuid.UUID, tenantID string) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM user_tenant_roles WHERE user_id = $1 AND tenant_id = $2`, userID, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete tenant role: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
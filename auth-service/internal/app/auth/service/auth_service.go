@@ -4,11 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/auth-service/internal/app/auth/infrastructure"
 	"augustberries/auth-service/internal/app/auth/repository"
 	"augustberries/auth-service/internal/app/auth/util"
+	"augustberries/pkg/tenant"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -20,6 +24,11 @@ type AuthService struct {
 	roleRepo   repository.RoleRepository
 	tokenRepo  repository.TokenRepository
 	jwtManager *util.JWTManager
+	events     infrastructure.MessagePublisher // Kafka producer топика user_events
+	security   *LoginSecurity                  // Проверка устройств и местоположения входа, nil - отключена
+
+	passwordPolicy     *PasswordPolicy                         // Проверка пароля при регистрации, nil - отключена
+	impersonationAudit repository.ImpersonationAuditRepository // Журнал имперсонации, nil - имперсонация отключена
 }
 
 // NewAuthService создает новый сервис аутентификации
@@ -28,17 +37,25 @@ func NewAuthService(
 	roleRepo repository.RoleRepository,
 	tokenRepo repository.TokenRepository,
 	jwtManager *util.JWTManager,
+	events infrastructure.MessagePublisher,
+	security *LoginSecurity,
 ) *AuthService {
 	return &AuthService{
 		userRepo:   userRepo,
 		roleRepo:   roleRepo,
 		tokenRepo:  tokenRepo,
 		jwtManager: jwtManager,
+		events:     events,
+		security:   security,
 	}
 }
 
 // Register регистрирует нового пользователя
 func (s *AuthService) Register(ctx context.Context, req *entity.RegisterRequest) (*entity.AuthResponse, error) {
+	if err := s.passwordPolicy.Validate(ctx, req.Password, req.Email, req.Name); err != nil {
+		return nil, err
+	}
+
 	// Проверяем, существует ли пользователь с таким email
 	existingUser, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
@@ -70,6 +87,7 @@ func (s *AuthService) Register(ctx context.Context, req *entity.RegisterRequest)
 		PasswordHash: passwordHash,
 		Name:         req.Name,
 		RoleID:       userRole.ID,
+		TenantID:     tenant.FromContext(ctx), // Магазин определяется Tenant middleware по заголовку X-Tenant-ID
 		CreatedAt:    time.Now(),
 	}
 
@@ -77,8 +95,31 @@ func (s *AuthService) Register(ctx context.Context, req *entity.RegisterRequest)
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
+	if err := publishUserEvent(ctx, s.events, newUserEvent(entity.EventUserRegistered, user)); err != nil {
+		fmt.Printf("failed to publish user registered event: %v\n", err)
+	}
+
 	// Генерируем токены
-	return s.generateAuthResponse(ctx, user)
+	return s.generateAuthResponse(ctx, user, false)
+}
+
+// CreateGuestSession выдает гостевой токен для оформления заказа без аккаунта
+// Гость получает новый идентификатор, после регистрации его заказы привязываются к аккаунту
+func (s *AuthService) CreateGuestSession(ctx context.Context, req *entity.GuestSessionRequest) (*entity.GuestSessionResponse, error) {
+	guestID := uuid.New()
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	token, err := s.jwtManager.GenerateGuestToken(guestID, email, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate guest token: %w", err)
+	}
+
+	return &entity.GuestSessionResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		GuestID:     guestID.String(),
+		ExpiresIn:   int64(s.jwtManager.GetGuestTokenDuration().Seconds()),
+	}, nil
 }
 
 // Login выполняет вход пользователя
@@ -87,38 +128,205 @@ func (s *AuthService) Login(ctx context.Context, req *entity.LoginRequest) (*ent
 	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
+			s.recordFailedLogin(ctx, req, nil)
 			return nil, ErrInvalidCredentials
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
+	if s.security != nil {
+		if err := s.security.checkLock(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Проверяем пароль
-	if !util.CheckPassword(req.Password, user.PasswordHash) {
+	ok, rehash := util.VerifyPassword(req.Password, user.PasswordHash)
+	if !ok {
+		s.recordFailedLogin(ctx, req, user)
 		return nil, ErrInvalidCredentials
 	}
 
+	// Отключенный аккаунт сообщаем только после проверки пароля, чтобы не раскрывать статус
+	if !user.Active() {
+		return nil, ErrAccountDeactivated
+	}
+
+	// Хэш создан по прежней политике - пересчитываем, пока известен пароль
+	if rehash {
+		s.rehashPassword(ctx, user, req.Password)
+	}
+
+	if s.security == nil {
+		return s.completeLogin(ctx, user, nil, req.RememberMe)
+	}
+
+	// Пароль верный - серия неудач прервана
+	if err := s.security.clearFailures(ctx, user.Email); err != nil {
+		fmt.Printf("failed to clear failed logins: %v\n", err)
+	}
+
+	// Вход с нового устройства или из невозможного места требует кода из письма
+	device := s.security.deviceFor(user.ID, req)
+	reason, err := s.security.assess(ctx, device)
+	if err != nil {
+		return nil, err
+	}
+	if reason != "" {
+		return nil, s.security.challenge(ctx, user, device, reason, req.RememberMe)
+	}
+
+	return s.completeLogin(ctx, user, device, req.RememberMe)
+}
+
+// rehashPassword сохраняет хэш пароля по текущей политике
+// Ошибка не прерывает вход: хэш будет пересчитан при следующем входе
+func (s *AuthService) rehashPassword(ctx context.Context, user *entity.User, password string) {
+	passwordHash, err := util.HashPassword(password)
+	if err != nil {
+		fmt.Printf("failed to rehash password: %v\n", err)
+		return
+	}
+
+	previous := user.PasswordHash
+	user.PasswordHash = passwordHash
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		user.PasswordHash = previous
+		fmt.Printf("failed to save rehashed password: %v\n", err)
+	}
+}
+
+// VerifyLogin завершает подозрительный вход кодом из письма
+// Устройство после подтверждения становится доверенным
+func (s *AuthService) VerifyLogin(ctx context.Context, req *entity.VerifyLoginRequest) (*entity.AuthResponse, error) {
+	if s.security == nil {
+		return nil, ErrInvalidVerificationCode
+	}
+
+	challenge, err := s.security.verify(ctx, req.ChallengeID, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, challenge.UserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return s.completeLogin(ctx, user, &challenge.Device, challenge.RememberMe)
+}
+
+// ListSessions возвращает устройства, с которых входил пользователь
+func (s *AuthService) ListSessions(ctx context.Context, userID uuid.UUID) ([]entity.Device, error) {
+	if s.security == nil {
+		return []entity.Device{}, nil
+	}
+	return s.security.Devices(ctx, userID)
+}
+
+// RemoveSession удаляет устройство пользователя
+func (s *AuthService) RemoveSession(ctx context.Context, userID, deviceID uuid.UUID) error {
+	if s.security == nil {
+		return ErrDeviceNotFound
+	}
+	return s.security.ForgetDevice(ctx, userID, deviceID)
+}
+
+// recordFailedLogin учитывает неудачный вход; ошибка учета не мешает ответу пользователю
+func (s *AuthService) recordFailedLogin(ctx context.Context, req *entity.LoginRequest, user *entity.User) {
+	if s.security == nil {
+		return
+	}
+	if err := s.security.recordFailure(ctx, req, user); err != nil {
+		fmt.Printf("failed to record failed login: %v\n", err)
+	}
+}
+
+// completeLogin выдает токены, запоминает устройство и публикует событие входа
+func (s *AuthService) completeLogin(ctx context.Context, user *entity.User, device *entity.Device, rememberMe bool) (*entity.AuthResponse, error) {
 	// Генерируем токены
-	return s.generateAuthResponse(ctx, user)
+	response, err := s.generateAuthResponse(ctx, user, rememberMe)
+	if err != nil {
+		return nil, err
+	}
+
+	if device != nil {
+		if err := s.security.recordLogin(ctx, device); err != nil {
+			fmt.Printf("failed to record login device: %v\n", err)
+		}
+	}
+
+	if err := publishUserEvent(ctx, s.events, newUserEvent(entity.EventUserLoggedIn, user)); err != nil {
+		fmt.Printf("failed to publish user logged in event: %v\n", err)
+	}
+
+	return response, nil
 }
 
 // RefreshTokens обновляет access и refresh токены
 func (s *AuthService) RefreshTokens(ctx context.Context, refreshToken string) (*entity.TokenPair, error) {
-	// Проверяем refresh токен в БД
-	storedToken, err := s.tokenRepo.GetRefreshToken(ctx, refreshToken)
+	if util.IsRememberToken(refreshToken) {
+		return s.refreshRememberToken(ctx, refreshToken)
+	}
+
+	// Забираем refresh токен одной операцией: из параллельных обменов одного токена новую пару получает только один,
+	// остальные считаются повторным предъявлением
+	storedToken, err := s.tokenRepo.ConsumeRefreshToken(ctx, refreshToken)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvalidRefreshToken
+		}
+		if s.revokeReusedFamily(ctx, err) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("failed to consume refresh token: %w", err)
+	}
+
+	return s.issueTokenPair(ctx, storedToken.UserID, nil)
+}
+
+// refreshRememberToken обменивает токен "запомнить меня" на новую пару
+// Срок нового токена отсчитывается заново, но сессия не переживает максимальный возраст
+func (s *AuthService) refreshRememberToken(ctx context.Context, refreshToken string) (*entity.TokenPair, error) {
+	storedToken, err := s.tokenRepo.ConsumeRememberToken(ctx, refreshToken)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrInvalidRefreshToken
 		}
-		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+		if s.revokeReusedFamily(ctx, err) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("failed to consume remember token: %w", err)
+	}
+
+	if _, ok := s.jwtManager.RememberTokenExpiry(storedToken.SessionStartedAt, time.Now()); !ok {
+		return nil, ErrInvalidRefreshToken
 	}
 
-	// Удаляем использованный refresh токен
-	if err := s.tokenRepo.DeleteRefreshToken(ctx, refreshToken); err != nil {
-		return nil, fmt.Errorf("failed to delete refresh token: %w", err)
+	return s.issueTokenPair(ctx, storedToken.UserID, &storedToken.SessionStartedAt)
+}
+
+// revokeReusedFamily отзывает все сессии пользователя, если предъявлен уже обмененный токен
+// Обменять его мог только владелец или тот, кто его украл; какая из сессий законная, не известно
+func (s *AuthService) revokeReusedFamily(ctx context.Context, err error) bool {
+	var reuse *repository.TokenReuseError
+	if !errors.As(err, &reuse) {
+		return false
+	}
+	if err := s.tokenRepo.DeleteUserRefreshTokens(ctx, reuse.UserID); err != nil {
+		fmt.Printf("failed to revoke sessions after refresh token reuse: %v\n", err)
 	}
+	return true
+}
 
+// issueTokenPair выдает новую пару токенов пользователю при обновлении
+// sessionStartedAt != nil - сессия "запомнить меня", начатая в указанное время
+func (s *AuthService) issueTokenPair(ctx context.Context, userID uuid.UUID, sessionStartedAt *time.Time) (*entity.TokenPair, error) {
 	// Получаем пользователя
-	user, err := s.userRepo.GetByID(ctx, storedToken.UserID)
+	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrUserNotFound
@@ -126,6 +334,11 @@ func (s *AuthService) RefreshTokens(ctx context.Context, refreshToken string) (*
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
+	// Сессии отключенного пользователя не продлеваются
+	if !user.Active() {
+		return nil, ErrAccountDeactivated
+	}
+
 	// Получаем роль и разрешения
 	role, err := s.roleRepo.GetByID(ctx, user.RoleID)
 	if err != nil {
@@ -141,7 +354,7 @@ func (s *AuthService) RefreshTokens(ctx context.Context, refreshToken string) (*
 	}
 
 	// Генерируем новую пару токенов
-	return s.generateTokenPair(ctx, user, role, permissions)
+	return s.generateTokenPair(ctx, user, role, permissions, sessionStartedAt)
 }
 
 // GetCurrentUser получает информацию о текущем пользователе
@@ -218,8 +431,42 @@ func (s *AuthService) ValidateToken(ctx context.Context, token string) (*util.JW
 	return claims, nil
 }
 
+// Introspect проверяет токен тем же путем, что и ValidateToken, и возвращает ответ RFC 7662
+// Просроченный, отозванный или поддельный токен - не ошибка, а active: false
+// Интроспекция поддерживает только access токены: refresh токены непрозрачны и всегда неактивны
+func (s *AuthService) Introspect(ctx context.Context, token string) (*entity.IntrospectionResponse, error) {
+	claims, err := s.ValidateToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidToken) || errors.Is(err, util.ErrExpiredToken) {
+			return &entity.IntrospectionResponse{Active: false}, nil
+		}
+		return nil, err
+	}
+
+	response := &entity.IntrospectionResponse{
+		Active:    true,
+		Scope:     strings.Join(claims.Permissions, " "),
+		Username:  claims.Email,
+		TokenType: "access_token",
+		Sub:       claims.Subject,
+		Role:      claims.RoleName,
+		TenantID:  claims.TenantID,
+	}
+	if claims.ExpiresAt != nil {
+		response.Exp = claims.ExpiresAt.Unix()
+	}
+	if claims.IssuedAt != nil {
+		response.Iat = claims.IssuedAt.Unix()
+	}
+	if claims.NotBefore != nil {
+		response.Nbf = claims.NotBefore.Unix()
+	}
+	return response, nil
+}
+
 // generateAuthResponse создает полный ответ с пользователем и токенами
-func (s *AuthService) generateAuthResponse(ctx context.Context, user *entity.User) (*entity.AuthResponse, error) {
+// rememberMe начинает сессию "запомнить меня", если она включена в JWTManager
+func (s *AuthService) generateAuthResponse(ctx context.Context, user *entity.User, rememberMe bool) (*entity.AuthResponse, error) {
 	// Получаем роль
 	role, err := s.roleRepo.GetByID(ctx, user.RoleID)
 	if err != nil {
@@ -235,8 +482,14 @@ func (s *AuthService) generateAuthResponse(ctx context.Context, user *entity.Use
 		return nil, fmt.Errorf("failed to get permissions: %w", err)
 	}
 
+	var sessionStartedAt *time.Time
+	if rememberMe && s.jwtManager.RememberMeEnabled() {
+		now := time.Now()
+		sessionStartedAt = &now
+	}
+
 	// Генерируем токены
-	tokenPair, err := s.generateTokenPair(ctx, user, role, permissions)
+	tokenPair, err := s.generateTokenPair(ctx, user, role, permissions, sessionStartedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -257,6 +510,7 @@ func (s *AuthService) generateTokenPair(
 	user *entity.User,
 	role *entity.Role,
 	permissions []entity.Permission,
+	sessionStartedAt *time.Time,
 ) (*entity.TokenPair, error) {
 	// Создаем список кодов разрешений
 	permissionCodes := make([]string, len(permissions))
@@ -264,6 +518,11 @@ func (s *AuthService) generateTokenPair(
 		permissionCodes[i] = p.Code
 	}
 
+	tenantRoles, err := s.tenantRoles(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Генерируем access токен
 	accessToken, err := s.jwtManager.GenerateAccessToken(
 		user.ID,
@@ -271,11 +530,18 @@ func (s *AuthService) generateTokenPair(
 		user.RoleID,
 		role.Name,
 		permissionCodes,
+		user.TenantID,
+		user.PreferredCurrency,
+		tenantRoles,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
+	if sessionStartedAt != nil {
+		return s.generateRememberPair(ctx, user, accessToken, *sessionStartedAt)
+	}
+
 	// Генерируем refresh токен
 	refreshToken, err := s.jwtManager.GenerateRefreshToken()
 	if err != nil {
@@ -289,8 +555,99 @@ func (s *AuthService) generateTokenPair(
 	}
 
 	return &entity.TokenPair{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresIn:    int64(s.jwtManager.GetAccessTokenDuration().Seconds()),
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresIn:        int64(s.jwtManager.GetAccessTokenDuration().Seconds()),
+		RefreshExpiresIn: int64(s.jwtManager.GetRefreshTokenDuration().Seconds()),
 	}, nil
 }
+
+// tenantRoles возвращает роли пользователя в других магазинах для JWT, nil - ролей нет
+func (s *AuthService) tenantRoles(ctx context.Context, userID uuid.UUID) (tenant.Roles, error) {
+	roles, err := s.userRepo.ListTenantRoles(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant roles: %w", err)
+	}
+	if len(roles) == 0 {
+		return nil, nil
+	}
+
+	result := make(tenant.Roles, len(roles))
+	for _, role := range roles {
+		result[role.TenantID] = tenant.Role{ID: role.RoleID, Name: role.RoleName, Permissions: role.Permissions}
+	}
+	return result, nil
+}
+
+// generateRememberPair дополняет access токен токеном "запомнить меня"
+func (s *AuthService) generateRememberPair(ctx context.Context, user *entity.User, accessToken string, sessionStartedAt time.Time) (*entity.TokenPair, error) {
+	now := time.Now()
+	expiresAt, ok := s.jwtManager.RememberTokenExpiry(sessionStartedAt, now)
+	if !ok {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	refreshToken, err := s.jwtManager.GenerateRememberToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	if err := s.tokenRepo.SaveRememberToken(ctx, &entity.RememberToken{
+		Token:            refreshToken,
+		UserID:           user.ID,
+		SessionStartedAt: sessionStartedAt,
+		ExpiresAt:        expiresAt,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to save remember token: %w", err)
+	}
+
+	return &entity.TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresIn:        int64(s.jwtManager.GetAccessTokenDuration().Seconds()),
+		RefreshExpiresIn: int64(expiresAt.Sub(now).Seconds()),
+	}, nil
+}
+
+// UpdateProfile изменяет публичный профиль пользователя и рассылает USER_UPDATED
+// Другие сервисы (например, отзывы) строят по этому событию свою копию профиля без email
+func (s *AuthService) UpdateProfile(ctx context.Context, userID uuid.UUID, req *entity.UpdateProfileRequest) (*entity.User, error) {
+	if req.AvatarURL != nil && *req.AvatarURL != "" && !validAvatarURL(*req.AvatarURL) {
+		return nil, ErrInvalidAvatarURL
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if req.Name != nil {
+		user.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.AvatarURL != nil {
+		user.AvatarURL = *req.AvatarURL
+	}
+	// Новая валюта попадает в access токен при следующем входе или обновлении токена
+	if req.PreferredCurrency != nil {
+		user.PreferredCurrency = *req.PreferredCurrency
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update user profile: %w", err)
+	}
+
+	if err := publishUserEvent(ctx, s.events, newUserEvent(entity.EventUserUpdated, user)); err != nil {
+		fmt.Printf("failed to publish user updated event: %v\n", err)
+	}
+
+	return user, nil
+}
+
+// validAvatarURL проверяет, что аватар - абсолютная http(s) ссылка
+func validAvatarURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
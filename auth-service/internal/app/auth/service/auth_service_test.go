@@ -2,12 +2,15 @@ package service
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
 	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/auth-service/internal/app/auth/repository"
 	"augustberries/auth-service/internal/app/auth/repository/mocks"
 	"augustberries/auth-service/internal/app/auth/util"
+	"augustberries/pkg/tenant"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -67,9 +70,10 @@ func TestAuthService_Register_Success(t *testing.T) {
 	roleRepo.On("GetByName", ctx, "user").Return(role, nil)
 	roleRepo.On("GetByID", ctx, 1).Return(role, nil)
 	roleRepo.On("GetPermissionsByRoleID", ctx, 1).Return(permissions, nil)
+	userRepo.On("ListTenantRoles", ctx, mock.AnythingOfType("uuid.UUID")).Return(nil, nil)
 	tokenRepo.On("SaveRefreshToken", ctx, mock.AnythingOfType("uuid.UUID"), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
 
-	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager)
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager, mocks.NewMockMessagePublisher(), nil)
 
 	req := &entity.RegisterRequest{
 		Email:    "newuser@example.com",
@@ -94,6 +98,45 @@ func TestAuthService_Register_Success(t *testing.T) {
 	tokenRepo.AssertExpectations(t)
 }
 
+func TestAuthService_Register_TenantFromContext(t *testing.T) {
+	// Arrange
+	ctx := tenant.WithID(context.Background(), "shop-a")
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	tokenRepo := new(mocks.MockTokenRepository)
+	jwtManager := newTestJWTManager()
+
+	role := newTestRole()
+
+	userRepo.On("GetByEmail", ctx, "newuser@example.com").Return(nil, pgx.ErrNoRows)
+	userRepo.On("Create", ctx, mock.MatchedBy(func(u *entity.User) bool {
+		return u.TenantID == "shop-a"
+	})).Return(nil)
+	roleRepo.On("GetByName", ctx, "user").Return(role, nil)
+	roleRepo.On("GetByID", ctx, 1).Return(role, nil)
+	roleRepo.On("GetPermissionsByRoleID", ctx, 1).Return(newTestPermissions(), nil)
+	userRepo.On("ListTenantRoles", ctx, mock.AnythingOfType("uuid.UUID")).Return(nil, nil)
+	tokenRepo.On("SaveRefreshToken", ctx, mock.AnythingOfType("uuid.UUID"), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
+
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager, mocks.NewMockMessagePublisher(), nil)
+
+	req := &entity.RegisterRequest{
+		Email:    "newuser@example.com",
+		Password: "password123",
+		Name:     "New User",
+	}
+
+	// Act
+	response, err := service.Register(ctx, req)
+
+	// Assert
+	require.NoError(t, err)
+	claims, err := jwtManager.ValidateToken(response.Tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "shop-a", claims.TenantID)
+	userRepo.AssertExpectations(t)
+}
+
 func TestAuthService_Register_UserAlreadyExists(t *testing.T) {
 	// Arrange
 	ctx := context.Background()
@@ -105,7 +148,7 @@ func TestAuthService_Register_UserAlreadyExists(t *testing.T) {
 	existingUser := newTestUser()
 	userRepo.On("GetByEmail", ctx, "existing@example.com").Return(existingUser, nil)
 
-	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager)
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager, mocks.NewMockMessagePublisher(), nil)
 
 	req := &entity.RegisterRequest{
 		Email:    "existing@example.com",
@@ -134,7 +177,7 @@ func TestAuthService_Register_RoleNotFound(t *testing.T) {
 	userRepo.On("GetByEmail", ctx, "test@example.com").Return(nil, pgx.ErrNoRows)
 	roleRepo.On("GetByName", ctx, "user").Return(nil, pgx.ErrNoRows)
 
-	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager)
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager, mocks.NewMockMessagePublisher(), nil)
 
 	req := &entity.RegisterRequest{
 		Email:    "test@example.com",
@@ -167,9 +210,10 @@ func TestAuthService_Login_Success(t *testing.T) {
 	userRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
 	roleRepo.On("GetByID", ctx, user.RoleID).Return(role, nil)
 	roleRepo.On("GetPermissionsByRoleID", ctx, user.RoleID).Return(permissions, nil)
+	userRepo.On("ListTenantRoles", ctx, mock.AnythingOfType("uuid.UUID")).Return(nil, nil)
 	tokenRepo.On("SaveRefreshToken", ctx, user.ID, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
 
-	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager)
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager, mocks.NewMockMessagePublisher(), nil)
 
 	req := &entity.LoginRequest{
 		Email:    user.Email,
@@ -191,6 +235,39 @@ func TestAuthService_Login_Success(t *testing.T) {
 	tokenRepo.AssertExpectations(t)
 }
 
+func TestAuthService_Login_IncludesTenantRoles(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	tokenRepo := new(mocks.MockTokenRepository)
+	jwtManager := newTestJWTManager()
+
+	user := newTestUser()
+	user.TenantID = "shop-a"
+
+	userRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+	roleRepo.On("GetByID", ctx, user.RoleID).Return(newTestRole(), nil)
+	roleRepo.On("GetPermissionsByRoleID", ctx, user.RoleID).Return(newTestPermissions(), nil)
+	userRepo.On("ListTenantRoles", ctx, user.ID).Return([]entity.TenantRole{
+		{UserID: user.ID, TenantID: "shop-b", RoleID: 3, RoleName: "admin", Permissions: []string{"product.create"}},
+	}, nil)
+	tokenRepo.On("SaveRefreshToken", ctx, user.ID, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
+
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager, mocks.NewMockMessagePublisher(), nil)
+
+	// Act
+	response, err := service.Login(ctx, &entity.LoginRequest{Email: user.Email, Password: "password123"})
+	require.NoError(t, err)
+	claims, err := jwtManager.ValidateToken(response.Tokens.AccessToken)
+
+	// Assert: роль магазина регистрации в основных claims, роль в shop-b - в tenant_roles
+	require.NoError(t, err)
+	assert.Equal(t, "shop-a", claims.TenantID)
+	assert.Equal(t, "user", claims.RoleName)
+	assert.Equal(t, tenant.Roles{"shop-b": {ID: 3, Name: "admin", Permissions: []string{"product.create"}}}, claims.TenantRoles)
+}
+
 func TestAuthService_Login_UserNotFound(t *testing.T) {
 	// Arrange
 	ctx := context.Background()
@@ -201,7 +278,7 @@ func TestAuthService_Login_UserNotFound(t *testing.T) {
 
 	userRepo.On("GetByEmail", ctx, "notfound@example.com").Return(nil, pgx.ErrNoRows)
 
-	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager)
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager, mocks.NewMockMessagePublisher(), nil)
 
 	req := &entity.LoginRequest{
 		Email:    "notfound@example.com",
@@ -227,7 +304,7 @@ func TestAuthService_Login_WrongPassword(t *testing.T) {
 	user := newTestUser()
 	userRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
 
-	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager)
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager, mocks.NewMockMessagePublisher(), nil)
 
 	req := &entity.LoginRequest{
 		Email:    user.Email,
@@ -242,6 +319,107 @@ func TestAuthService_Login_WrongPassword(t *testing.T) {
 	assert.ErrorIs(t, err, ErrInvalidCredentials)
 }
 
+func TestAuthService_Login_Deactivated(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	tokenRepo := new(mocks.MockTokenRepository)
+
+	user := newTestUser()
+	deactivatedAt := time.Now()
+	user.DeactivatedAt = &deactivatedAt
+	userRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, newTestJWTManager(), mocks.NewMockMessagePublisher(), nil)
+
+	// Act
+	response, err := service.Login(ctx, &entity.LoginRequest{Email: user.Email, Password: "password123"})
+
+	// Assert
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, ErrAccountDeactivated)
+	tokenRepo.AssertNotCalled(t, "SaveRefreshToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Login_RehashesPasswordOnPolicyChange(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	tokenRepo := new(mocks.MockTokenRepository)
+
+	user := newTestUser() // bcrypt хэш по политике по умолчанию
+	legacyHash := user.PasswordHash
+
+	policy := util.DefaultPasswordPolicy()
+	policy.Algorithm = util.PasswordAlgorithmArgon2id
+	policy.Argon2Memory = 1024
+	policy.Argon2Iterations = 1
+	policy.Argon2Parallelism = 1
+	require.NoError(t, util.ConfigurePasswordHashing(policy))
+	t.Cleanup(func() {
+		_ = util.ConfigurePasswordHashing(util.DefaultPasswordPolicy())
+	})
+
+	userRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+	userRepo.On("Update", ctx, user).Return(nil)
+	roleRepo.On("GetByID", ctx, user.RoleID).Return(newTestRole(), nil)
+	roleRepo.On("GetPermissionsByRoleID", ctx, user.RoleID).Return(newTestPermissions(), nil)
+	userRepo.On("ListTenantRoles", ctx, mock.AnythingOfType("uuid.UUID")).Return(nil, nil)
+	tokenRepo.On("SaveRefreshToken", ctx, user.ID, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
+
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, newTestJWTManager(), mocks.NewMockMessagePublisher(), nil)
+
+	// Act
+	response, err := service.Login(ctx, &entity.LoginRequest{Email: user.Email, Password: "password123"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.NotNil(t, response)
+	assert.NotEqual(t, legacyHash, user.PasswordHash)
+	assert.True(t, strings.HasPrefix(user.PasswordHash, "$argon2id$"))
+	ok, rehash := util.VerifyPassword("password123", user.PasswordHash)
+	assert.True(t, ok)
+	assert.False(t, rehash)
+	userRepo.AssertExpectations(t)
+}
+
+func TestAuthService_Login_RehashFailureDoesNotBlockLogin(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	tokenRepo := new(mocks.MockTokenRepository)
+
+	user := newTestUser()
+	legacyHash := user.PasswordHash
+
+	policy := util.DefaultPasswordPolicy()
+	policy.BcryptCost = 4
+	require.NoError(t, util.ConfigurePasswordHashing(policy))
+	t.Cleanup(func() {
+		_ = util.ConfigurePasswordHashing(util.DefaultPasswordPolicy())
+	})
+
+	userRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+	userRepo.On("Update", ctx, user).Return(assert.AnError)
+	roleRepo.On("GetByID", ctx, user.RoleID).Return(newTestRole(), nil)
+	roleRepo.On("GetPermissionsByRoleID", ctx, user.RoleID).Return(newTestPermissions(), nil)
+	userRepo.On("ListTenantRoles", ctx, mock.AnythingOfType("uuid.UUID")).Return(nil, nil)
+	tokenRepo.On("SaveRefreshToken", ctx, user.ID, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
+
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, newTestJWTManager(), mocks.NewMockMessagePublisher(), nil)
+
+	// Act
+	response, err := service.Login(ctx, &entity.LoginRequest{Email: user.Email, Password: "password123"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.NotNil(t, response)
+	assert.Equal(t, legacyHash, user.PasswordHash)
+}
+
 // ==================== RefreshTokens Tests ====================
 
 func TestAuthService_RefreshTokens_Success(t *testing.T) {
@@ -265,14 +443,14 @@ func TestAuthService_RefreshTokens_Success(t *testing.T) {
 		CreatedAt: time.Now(),
 	}
 
-	tokenRepo.On("GetRefreshToken", ctx, refreshToken).Return(storedToken, nil)
-	tokenRepo.On("DeleteRefreshToken", ctx, refreshToken).Return(nil)
+	tokenRepo.On("ConsumeRefreshToken", ctx, refreshToken).Return(storedToken, nil)
 	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
 	roleRepo.On("GetByID", ctx, user.RoleID).Return(role, nil)
 	roleRepo.On("GetPermissionsByRoleID", ctx, user.RoleID).Return(permissions, nil)
+	userRepo.On("ListTenantRoles", ctx, mock.AnythingOfType("uuid.UUID")).Return(nil, nil)
 	tokenRepo.On("SaveRefreshToken", ctx, user.ID, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
 
-	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager)
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager, mocks.NewMockMessagePublisher(), nil)
 
 	// Act
 	tokenPair, err := service.RefreshTokens(ctx, refreshToken)
@@ -297,9 +475,9 @@ func TestAuthService_RefreshTokens_InvalidToken(t *testing.T) {
 	tokenRepo := new(mocks.MockTokenRepository)
 	jwtManager := newTestJWTManager()
 
-	tokenRepo.On("GetRefreshToken", ctx, "invalid-token").Return(nil, pgx.ErrNoRows)
+	tokenRepo.On("ConsumeRefreshToken", ctx, "invalid-token").Return(nil, pgx.ErrNoRows)
 
-	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager)
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager, mocks.NewMockMessagePublisher(), nil)
 
 	// Act
 	tokenPair, err := service.RefreshTokens(ctx, "invalid-token")
@@ -309,6 +487,54 @@ func TestAuthService_RefreshTokens_InvalidToken(t *testing.T) {
 	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
 }
 
+func TestAuthService_RefreshTokens_ReuseRevokesSessions(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	tokenRepo := new(mocks.MockTokenRepository)
+	userID := uuid.New()
+
+	tokenRepo.On("ConsumeRefreshToken", ctx, "rotated-token").Return(nil, &repository.TokenReuseError{UserID: userID})
+	tokenRepo.On("DeleteUserRefreshTokens", ctx, userID).Return(nil)
+
+	service := NewAuthService(new(mocks.MockUserRepository), new(mocks.MockRoleRepository), tokenRepo, newTestJWTManager(), mocks.NewMockMessagePublisher(), nil)
+
+	// Act
+	tokenPair, err := service.RefreshTokens(ctx, "rotated-token")
+
+	// Assert
+	assert.Nil(t, tokenPair)
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestAuthService_RefreshTokens_DeactivatedUser(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	tokenRepo := new(mocks.MockTokenRepository)
+
+	user := newTestUser()
+	deactivatedAt := time.Now()
+	user.DeactivatedAt = &deactivatedAt
+
+	tokenRepo.On("ConsumeRefreshToken", ctx, "refresh").Return(&entity.RefreshToken{
+		UserID:    user.ID,
+		Token:     "refresh",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, newTestJWTManager(), mocks.NewMockMessagePublisher(), nil)
+
+	// Act
+	tokens, err := service.RefreshTokens(ctx, "refresh")
+
+	// Assert
+	assert.Nil(t, tokens)
+	assert.ErrorIs(t, err, ErrAccountDeactivated)
+}
+
 func TestAuthService_RefreshTokens_UserNotFound(t *testing.T) {
 	// Arrange
 	ctx := context.Background()
@@ -327,11 +553,10 @@ func TestAuthService_RefreshTokens_UserNotFound(t *testing.T) {
 		ExpiresAt: time.Now().Add(24 * time.Hour),
 	}
 
-	tokenRepo.On("GetRefreshToken", ctx, refreshToken).Return(storedToken, nil)
-	tokenRepo.On("DeleteRefreshToken", ctx, refreshToken).Return(nil)
+	tokenRepo.On("ConsumeRefreshToken", ctx, refreshToken).Return(storedToken, nil)
 	userRepo.On("GetByID", ctx, userID).Return(nil, pgx.ErrNoRows)
 
-	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager)
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager, mocks.NewMockMessagePublisher(), nil)
 
 	// Act
 	tokenPair, err := service.RefreshTokens(ctx, refreshToken)
@@ -359,7 +584,7 @@ func TestAuthService_GetCurrentUser_Success(t *testing.T) {
 	roleRepo.On("GetByID", ctx, user.RoleID).Return(role, nil)
 	roleRepo.On("GetPermissionsByRoleID", ctx, user.RoleID).Return(permissions, nil)
 
-	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager)
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager, mocks.NewMockMessagePublisher(), nil)
 
 	// Act
 	result, err := service.GetCurrentUser(ctx, user.ID)
@@ -387,7 +612,7 @@ func TestAuthService_GetCurrentUser_UserNotFound(t *testing.T) {
 	userID := uuid.New()
 	userRepo.On("GetByID", ctx, userID).Return(nil, pgx.ErrNoRows)
 
-	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager)
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager, mocks.NewMockMessagePublisher(), nil)
 
 	// Act
 	result, err := service.GetCurrentUser(ctx, userID)
@@ -410,12 +635,12 @@ func TestAuthService_Logout_Success(t *testing.T) {
 	user := newTestUser()
 
 	// Генерируем валидный access токен
-	accessToken, _ := jwtManager.GenerateAccessToken(user.ID, user.Email, user.RoleID, "user", []string{"product.read"})
+	accessToken, _ := jwtManager.GenerateAccessToken(user.ID, user.Email, user.RoleID, "user", []string{"product.read"}, "default", "", nil)
 
 	tokenRepo.On("AddToBlacklist", ctx, accessToken, mock.AnythingOfType("time.Time")).Return(nil)
 	tokenRepo.On("DeleteUserRefreshTokens", ctx, user.ID).Return(nil)
 
-	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager)
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager, mocks.NewMockMessagePublisher(), nil)
 
 	// Act
 	err := service.Logout(ctx, user.ID, accessToken)
@@ -436,7 +661,7 @@ func TestAuthService_Logout_InvalidToken_StillSucceeds(t *testing.T) {
 	userID := uuid.New()
 
 	// При невалидном токене Logout не должен падать
-	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager)
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager, mocks.NewMockMessagePublisher(), nil)
 
 	// Act
 	err := service.Logout(ctx, userID, "invalid-token")
@@ -459,11 +684,11 @@ func TestAuthService_ValidateToken_Success(t *testing.T) {
 	permissions := []string{"product.read", "order.create"}
 
 	// Генерируем валидный токен
-	accessToken, _ := jwtManager.GenerateAccessToken(user.ID, user.Email, user.RoleID, "user", permissions)
+	accessToken, _ := jwtManager.GenerateAccessToken(user.ID, user.Email, user.RoleID, "user", permissions, "default", "", nil)
 
 	tokenRepo.On("IsBlacklisted", ctx, accessToken).Return(false, nil)
 
-	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager)
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager, mocks.NewMockMessagePublisher(), nil)
 
 	// Act
 	claims, err := service.ValidateToken(ctx, accessToken)
@@ -489,11 +714,11 @@ func TestAuthService_ValidateToken_Blacklisted(t *testing.T) {
 	jwtManager := newTestJWTManager()
 
 	user := newTestUser()
-	accessToken, _ := jwtManager.GenerateAccessToken(user.ID, user.Email, user.RoleID, "user", []string{})
+	accessToken, _ := jwtManager.GenerateAccessToken(user.ID, user.Email, user.RoleID, "user", []string{}, "default", "", nil)
 
 	tokenRepo.On("IsBlacklisted", ctx, accessToken).Return(true, nil)
 
-	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager)
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager, mocks.NewMockMessagePublisher(), nil)
 
 	// Act
 	claims, err := service.ValidateToken(ctx, accessToken)
@@ -513,7 +738,7 @@ func TestAuthService_ValidateToken_InvalidToken(t *testing.T) {
 
 	tokenRepo.On("IsBlacklisted", ctx, "invalid-token").Return(false, nil)
 
-	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager)
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager, mocks.NewMockMessagePublisher(), nil)
 
 	// Act
 	claims, err := service.ValidateToken(ctx, "invalid-token")
@@ -533,14 +758,14 @@ func TestAuthService_ValidateToken_ExpiredToken(t *testing.T) {
 	jwtManager := util.NewJWTManager("test-secret", 1*time.Nanosecond, 1*time.Hour)
 
 	user := newTestUser()
-	accessToken, _ := jwtManager.GenerateAccessToken(user.ID, user.Email, user.RoleID, "user", []string{})
+	accessToken, _ := jwtManager.GenerateAccessToken(user.ID, user.Email, user.RoleID, "user", []string{}, "default", "", nil)
 
 	// Ждём чтобы токен истёк
 	time.Sleep(10 * time.Millisecond)
 
 	tokenRepo.On("IsBlacklisted", ctx, accessToken).Return(false, nil)
 
-	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager)
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager, mocks.NewMockMessagePublisher(), nil)
 
 	// Act
 	claims, err := service.ValidateToken(ctx, accessToken)
@@ -549,3 +774,71 @@ func TestAuthService_ValidateToken_ExpiredToken(t *testing.T) {
 	assert.Nil(t, claims)
 	assert.ErrorIs(t, err, util.ErrExpiredToken)
 }
+
+// ==================== Introspect Tests ====================
+
+func TestAuthService_Introspect_Active(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	tokenRepo := new(mocks.MockTokenRepository)
+	jwtManager := newTestJWTManager()
+
+	user := newTestUser()
+	accessToken, _ := jwtManager.GenerateAccessToken(user.ID, user.Email, user.RoleID, "manager", []string{"product.read", "order.create"}, "shop-1", "", nil)
+
+	tokenRepo.On("IsBlacklisted", ctx, accessToken).Return(false, nil)
+
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, jwtManager, mocks.NewMockMessagePublisher(), nil)
+
+	// Act
+	response, err := service.Introspect(ctx, accessToken)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, response.Active)
+	assert.Equal(t, user.ID.String(), response.Sub)
+	assert.Equal(t, user.Email, response.Username)
+	assert.Equal(t, "access_token", response.TokenType)
+	assert.Equal(t, "product.read order.create", response.Scope)
+	assert.Equal(t, "manager", response.Role)
+	assert.Equal(t, "shop-1", response.TenantID)
+	assert.Greater(t, response.Exp, response.Iat)
+}
+
+func TestAuthService_Introspect_InactiveTokens(t *testing.T) {
+	user := newTestUser()
+	expiredManager := util.NewJWTManager("test-secret-key", 1*time.Nanosecond, 1*time.Hour)
+	expired, _ := expiredManager.GenerateAccessToken(user.ID, user.Email, user.RoleID, "user", []string{}, "default", "", nil)
+	revoked, _ := newTestJWTManager().GenerateAccessToken(user.ID, user.Email, user.RoleID, "user", []string{}, "default", "", nil)
+	time.Sleep(10 * time.Millisecond)
+
+	tests := []struct {
+		name        string
+		token       string
+		blacklisted bool
+	}{
+		{name: "expired", token: expired},
+		{name: "revoked", token: revoked, blacklisted: true},
+		{name: "malformed", token: "not-a-jwt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			tokenRepo := new(mocks.MockTokenRepository)
+			tokenRepo.On("IsBlacklisted", ctx, tt.token).Return(tt.blacklisted, nil)
+
+			service := NewAuthService(new(mocks.MockUserRepository), new(mocks.MockRoleRepository), tokenRepo, newTestJWTManager(), mocks.NewMockMessagePublisher(), nil)
+
+			// Act
+			response, err := service.Introspect(ctx, tt.token)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, &entity.IntrospectionResponse{Active: false}, response)
+		})
+	}
+}
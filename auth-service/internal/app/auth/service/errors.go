@@ -8,12 +8,40 @@ var (
 	ErrInvalidCredentials  = errors.New("invalid email or password")
 	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
 
+	// Ошибки проверки входа
+	ErrVerificationRequired    = errors.New("login verification required")
+	ErrInvalidVerificationCode = errors.New("invalid or expired verification code")
+	ErrDeviceNotFound          = errors.New("device not found")
+	ErrAccountLocked           = errors.New("account is locked")
+	ErrAccountNotLocked        = errors.New("account is not locked")
+	ErrAccountDeactivated      = errors.New("account is deactivated")
+
 	// Ошибки пользователей
 	ErrUserExists   = errors.New("user with this email already exists")
 	ErrUserNotFound = errors.New("user not found")
 
+	// Ошибки политики паролей
+	ErrPasswordPolicy = errors.New("password does not meet policy")
+
+	// Ошибки провижининга
+	ErrProvisioningBatchEmpty    = errors.New("provisioning batch is empty")
+	ErrProvisioningBatchTooLarge = errors.New("provisioning batch is too large")
+
+	// Ошибки профиля
+	ErrInvalidAvatarURL = errors.New("avatar_url must be an absolute http(s) URL")
+
 	// Ошибки ролей
-	ErrRoleNotFound = errors.New("role not found")
+	ErrRoleNotFound         = errors.New("role not found")
+	ErrRoleExists           = errors.New("role with this name already exists")
+	ErrRoleTemplateNotFound = errors.New("role template not found")
+
+	// Ошибки ролей в магазинах
+	ErrHomeTenantRole     = errors.New("role in user's home tenant is changed via provisioning")
+	ErrTenantRoleNotFound = errors.New("user has no role in this tenant")
+
+	// Ошибки имперсонации
+	ErrImpersonationForbidden   = errors.New("user cannot be impersonated")
+	ErrImpersonationUnavailable = errors.New("impersonation audit is not configured")
 
 	// Ошибки разрешений
 	ErrPermissionNotFound = errors.New("permission not found")
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/auth-service/internal/app/auth/infrastructure"
+	"augustberries/pkg/kafka"
+)
+
+// newUserEvent формирует событие по текущему состоянию пользователя
+func newUserEvent(eventType string, user *entity.User) entity.UserEvent {
+	return entity.UserEvent{
+		EventType: eventType,
+		TenantID:  user.TenantID,
+		UserID:    user.ID,
+		Email:     user.Email,
+		Name:      user.Name,
+		AvatarURL: user.AvatarURL,
+		RoleID:    user.RoleID,
+		Timestamp: time.Now(),
+	}
+}
+
+// publishUserEvent отправляет событие пользователя в Kafka с ключом по ID пользователя
+func publishUserEvent(ctx context.Context, producer infrastructure.MessagePublisher, event entity.UserEvent) error {
+	eventData, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user event: %w", err)
+	}
+
+	ctx = kafka.WithEvent(ctx, event.EventType, entity.EventSchemaVersion)
+	if err := producer.PublishMessage(ctx, event.UserID.String(), eventData); err != nil {
+		return fmt.Errorf("failed to publish to kafka: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/auth-service/internal/app/auth/repository/mocks"
+	"augustberries/pkg/patch"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeUserEvents разбирает события, отправленные в мок Kafka
+func decodeUserEvents(t *testing.T, publisher *mocks.MockMessagePublisher) []entity.UserEvent {
+	events := make([]entity.UserEvent, len(publisher.Messages))
+	for i, msg := range publisher.Messages {
+		require.NoError(t, json.Unmarshal(msg, &events[i]))
+	}
+	return events
+}
+
+// ==================== User Events Tests ====================
+
+func TestAuthService_Register_PublishesUserRegistered(t *testing.T) {
+	// Arrange
+	ctx := tenant.WithID(context.Background(), "shop-a")
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	tokenRepo := new(mocks.MockTokenRepository)
+	publisher := mocks.NewMockMessagePublisher()
+
+	role := newTestRole()
+	userRepo.On("GetByEmail", ctx, "newuser@example.com").Return(nil, pgx.ErrNoRows)
+	userRepo.On("Create", ctx, mock.AnythingOfType("*entity.User")).Return(nil)
+	roleRepo.On("GetByName", ctx, "user").Return(role, nil)
+	roleRepo.On("GetByID", ctx, 1).Return(role, nil)
+	roleRepo.On("GetPermissionsByRoleID", ctx, 1).Return(newTestPermissions(), nil)
+	userRepo.On("ListTenantRoles", ctx, mock.AnythingOfType("uuid.UUID")).Return(nil, nil)
+	tokenRepo.On("SaveRefreshToken", ctx, mock.AnythingOfType("uuid.UUID"), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
+
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, newTestJWTManager(), publisher, nil)
+
+	// Act
+	response, err := service.Register(ctx, &entity.RegisterRequest{Email: "newuser@example.com", Password: "password123", Name: "New User"})
+
+	// Assert
+	require.NoError(t, err)
+	events := decodeUserEvents(t, publisher)
+	require.Len(t, events, 1)
+	assert.Equal(t, entity.EventUserRegistered, events[0].EventType)
+	assert.Equal(t, response.User.ID, events[0].UserID)
+	assert.Equal(t, "shop-a", events[0].TenantID)
+	publisher.AssertCalled(t, "PublishMessage", mock.Anything, response.User.ID.String(), mock.Anything)
+}
+
+func TestAuthService_Login_PublishesUserLoggedIn(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	tokenRepo := new(mocks.MockTokenRepository)
+	publisher := mocks.NewMockMessagePublisher()
+
+	user := newTestUser()
+	userRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+	roleRepo.On("GetByID", ctx, user.RoleID).Return(newTestRole(), nil)
+	roleRepo.On("GetPermissionsByRoleID", ctx, user.RoleID).Return(newTestPermissions(), nil)
+	userRepo.On("ListTenantRoles", ctx, mock.AnythingOfType("uuid.UUID")).Return(nil, nil)
+	tokenRepo.On("SaveRefreshToken", ctx, user.ID, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
+
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, newTestJWTManager(), publisher, nil)
+
+	// Act
+	_, err := service.Login(ctx, &entity.LoginRequest{Email: user.Email, Password: "password123"})
+
+	// Assert
+	require.NoError(t, err)
+	events := decodeUserEvents(t, publisher)
+	require.Len(t, events, 1)
+	assert.Equal(t, entity.EventUserLoggedIn, events[0].EventType)
+	assert.Equal(t, user.ID, events[0].UserID)
+}
+
+func TestAuthService_Login_WrongPassword_NoEvent(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	publisher := mocks.NewMockMessagePublisher()
+
+	user := newTestUser()
+	userRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+
+	service := NewAuthService(userRepo, new(mocks.MockRoleRepository), new(mocks.MockTokenRepository), newTestJWTManager(), publisher, nil)
+
+	// Act
+	_, err := service.Login(ctx, &entity.LoginRequest{Email: user.Email, Password: "wrongpassword"})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.Empty(t, publisher.Messages)
+}
+
+func TestUserService_UpdatePassword_PublishesPasswordChanged(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	publisher := mocks.NewMockMessagePublisher()
+
+	user := newTestUser()
+	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	userRepo.On("Update", ctx, user).Return(nil)
+
+	service := NewUserService(userRepo, new(mocks.MockRoleRepository), publisher)
+
+	// Act
+	err := service.UpdatePassword(ctx, user.ID, "password123", "newpassword456")
+
+	// Assert
+	require.NoError(t, err)
+	events := decodeUserEvents(t, publisher)
+	require.Len(t, events, 1)
+	assert.Equal(t, entity.EventPasswordChanged, events[0].EventType)
+}
+
+func TestUserService_Update_PublishesRoleChanged(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	publisher := mocks.NewMockMessagePublisher()
+
+	user := newTestUser()
+	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	roleRepo.On("GetByID", ctx, 2).Return(&entity.Role{ID: 2, Name: "manager"}, nil)
+	userRepo.On("Update", ctx, user).Return(nil)
+
+	service := NewUserService(userRepo, roleRepo, publisher)
+
+	// Act
+	_, err := service.Update(ctx, user.ID, &entity.UpdateUserRequest{RoleID: patch.Of(2)})
+
+	// Assert
+	require.NoError(t, err)
+	events := decodeUserEvents(t, publisher)
+	require.Len(t, events, 1)
+	assert.Equal(t, entity.EventRoleChanged, events[0].EventType)
+	assert.Equal(t, 2, events[0].RoleID)
+	assert.Equal(t, 1, events[0].PreviousRoleID)
+}
+
+func TestUserService_Update_SameRole_NoEvent(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	publisher := mocks.NewMockMessagePublisher()
+
+	user := newTestUser()
+	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	userRepo.On("Update", ctx, user).Return(nil)
+
+	service := NewUserService(userRepo, new(mocks.MockRoleRepository), publisher)
+
+	// Act
+	_, err := service.Update(ctx, user.ID, &entity.UpdateUserRequest{Name: patch.Of("Renamed")})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Empty(t, publisher.Messages)
+}
+
+func TestAuthService_UpdateProfile_PublishesUserUpdated(t *testing.T) {
+	// Arrange
+	ctx := tenant.WithID(context.Background(), "shop-a")
+	userRepo := new(mocks.MockUserRepository)
+	publisher := mocks.NewMockMessagePublisher()
+
+	user := newTestUser()
+	user.TenantID = "shop-a"
+	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	userRepo.On("Update", ctx, user).Return(nil)
+
+	service := NewAuthService(userRepo, new(mocks.MockRoleRepository), new(mocks.MockTokenRepository), newTestJWTManager(), publisher, nil)
+	name := "  Иван Петров "
+	avatar := "https://cdn.example.com/a.png"
+
+	// Act
+	updated, err := service.UpdateProfile(ctx, user.ID, &entity.UpdateProfileRequest{Name: &name, AvatarURL: &avatar})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Иван Петров", updated.Name)
+	events := decodeUserEvents(t, publisher)
+	require.Len(t, events, 1)
+	assert.Equal(t, entity.EventUserUpdated, events[0].EventType)
+	assert.Equal(t, "Иван Петров", events[0].Name)
+	assert.Equal(t, avatar, events[0].AvatarURL)
+	assert.Equal(t, "shop-a", events[0].TenantID)
+}
+
+func TestAuthService_UpdateProfile_PreferredCurrency(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+
+	user := newTestUser()
+	user.PreferredCurrency = "USD"
+	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	userRepo.On("Update", ctx, user).Return(nil)
+
+	service := NewAuthService(userRepo, new(mocks.MockRoleRepository), new(mocks.MockTokenRepository), newTestJWTManager(), mocks.NewMockMessagePublisher(), nil)
+	currency := "EUR"
+
+	// Act
+	updated, err := service.UpdateProfile(ctx, user.ID, &entity.UpdateProfileRequest{PreferredCurrency: &currency})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", updated.PreferredCurrency)
+	assert.Equal(t, "Test User", updated.Name)
+}
+
+func TestAuthService_UpdateProfile_InvalidAvatar(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	publisher := mocks.NewMockMessagePublisher()
+
+	service := NewAuthService(userRepo, new(mocks.MockRoleRepository), new(mocks.MockTokenRepository), newTestJWTManager(), publisher, nil)
+	avatar := "javascript:alert(1)"
+
+	// Act
+	_, err := service.UpdateProfile(ctx, uuid.New(), &entity.UpdateProfileRequest{AvatarURL: &avatar})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidAvatarURL)
+	userRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	assert.Empty(t, publisher.Messages)
+}
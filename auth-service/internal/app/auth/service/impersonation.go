@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/auth-service/internal/app/auth/repository"
+	"augustberries/pkg/impersonation"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// SetImpersonationAudit задает журнал выдачи токенов имперсонации; без журнала имперсонация отключена
+func (s *AuthService) SetImpersonationAudit(repo repository.ImpersonationAuditRepository) {
+	s.impersonationAudit = repo
+}
+
+// Impersonate выдает сотруднику поддержки короткоживущий токен от имени пользователя активного магазина
+// Нельзя войти от имени себя, отключенного пользователя, администратора, другого сотрудника поддержки
+// или пользователя, у которого есть разрешения, отсутствующие у сотрудника (actorPermissions - права в активном магазине).
+// Выдача записывается в журнал impersonation_audit до возврата токена и публикуется событием USER_IMPERSONATED
+func (s *AuthService) Impersonate(ctx context.Context, actorID uuid.UUID, actorEmail string, actorPermissions []string, userID uuid.UUID) (*entity.ImpersonationResponse, error) {
+	if s.impersonationAudit == nil {
+		return nil, ErrImpersonationUnavailable
+	}
+	if actorID == userID {
+		return nil, ErrImpersonationForbidden
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	// Пользователи других магазинов для сотрудника не существуют
+	if user.TenantID != tenant.FromContext(ctx) {
+		return nil, ErrUserNotFound
+	}
+	if !user.Active() {
+		return nil, ErrAccountDeactivated
+	}
+
+	role, err := s.roleRepo.GetByID(ctx, user.RoleID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, fmt.Errorf("failed to get user role: %w", err)
+	}
+
+	permissions, err := s.roleRepo.GetPermissionsByRoleID(ctx, user.RoleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get permissions: %w", err)
+	}
+
+	// Имперсонация не должна расширять права сотрудника: все разрешения пользователя должны быть у сотрудника
+	actorCodes := make(map[string]bool, len(actorPermissions))
+	for _, code := range actorPermissions {
+		actorCodes[code] = true
+	}
+	permissionCodes := make([]string, len(permissions))
+	for i, p := range permissions {
+		if p.Code == entity.PermissionImpersonate || !actorCodes[p.Code] {
+			return nil, ErrImpersonationForbidden
+		}
+		permissionCodes[i] = p.Code
+	}
+	if role.Name == "admin" {
+		return nil, ErrImpersonationForbidden
+	}
+
+	impersonator := impersonation.Impersonator{ID: actorID.String(), Email: actorEmail}
+	accessToken, expiresAt, err := s.jwtManager.GenerateImpersonationToken(
+		user.ID,
+		user.Email,
+		user.RoleID,
+		role.Name,
+		permissionCodes,
+		user.TenantID,
+		user.PreferredCurrency,
+		impersonator,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate impersonation token: %w", err)
+	}
+
+	record := &entity.ImpersonationAudit{
+		ID:         uuid.New(),
+		ActorID:    actorID,
+		ActorEmail: actorEmail,
+		UserID:     user.ID,
+		TenantID:   user.TenantID,
+		ExpiresAt:  expiresAt,
+		CreatedAt:  time.Now(),
+	}
+	// Токен без записи в журнале не выдается
+	if err := s.impersonationAudit.Create(ctx, record); err != nil {
+		return nil, fmt.Errorf("failed to audit impersonation: %w", err)
+	}
+
+	event := newUserEvent(entity.EventUserImpersonated, user)
+	event.ActorID = &actorID
+	if err := publishUserEvent(ctx, s.events, event); err != nil {
+		fmt.Printf("failed to publish %s event: %v\n", event.EventType, err)
+	}
+
+	return &entity.ImpersonationResponse{
+		AccessToken:  accessToken,
+		ExpiresIn:    int64(time.Until(expiresAt).Seconds()),
+		ExpiresAt:    expiresAt,
+		User:         entity.UserWithRole{User: *user, Role: *role, Permissions: permissions},
+		Impersonator: impersonator,
+	}, nil
+}
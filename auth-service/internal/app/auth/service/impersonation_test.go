@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/auth-service/internal/app/auth/repository/mocks"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// supportPermissions - разрешения сотрудника поддержки в тестах имперсонации
+var supportPermissions = []string{"product.read", "order.create", "order.read", entity.PermissionImpersonate}
+
+// ==================== Impersonate Tests ====================
+
+func TestAuthService_Impersonate_Success(t *testing.T) {
+	// Arrange
+	ctx := tenant.WithID(context.Background(), "shop-1")
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	events := mocks.NewMockMessagePublisher()
+	jwtManager := newTestJWTManager()
+	jwtManager.SetImpersonationTokenDuration(10 * time.Minute)
+
+	user := newTestUser()
+	user.TenantID = "shop-1"
+	supportID := uuid.New()
+
+	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	roleRepo.On("GetByID", ctx, user.RoleID).Return(newTestRole(), nil)
+	roleRepo.On("GetPermissionsByRoleID", ctx, user.RoleID).Return(newTestPermissions(), nil)
+
+	audit := new(mocks.MockImpersonationAuditRepository)
+	audit.On("Create", ctx, mock.MatchedBy(func(record *entity.ImpersonationAudit) bool {
+		return record.ActorID == supportID && record.ActorEmail == "support@example.com" &&
+			record.UserID == user.ID && record.TenantID == "shop-1" && !record.ExpiresAt.IsZero()
+	})).Return(nil)
+
+	service := NewAuthService(userRepo, roleRepo, new(mocks.MockTokenRepository), jwtManager, events, nil)
+	service.SetImpersonationAudit(audit)
+
+	// Act
+	response, err := service.Impersonate(ctx, supportID, "support@example.com", supportPermissions, user.ID)
+
+	// Assert
+	require.NoError(t, err)
+	audit.AssertExpectations(t)
+	assert.InDelta(t, 600, response.ExpiresIn, 1)
+	assert.Equal(t, supportID.String(), response.Impersonator.ID)
+
+	claims, err := jwtManager.ValidateToken(response.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, claims.UserID)
+	assert.Equal(t, "shop-1", claims.TenantID)
+	require.True(t, claims.IsImpersonated())
+	assert.Equal(t, "support@example.com", claims.Impersonator.Email)
+
+	require.Len(t, events.Messages, 1)
+	var event entity.UserEvent
+	require.NoError(t, json.Unmarshal(events.Messages[0], &event))
+	assert.Equal(t, entity.EventUserImpersonated, event.EventType)
+	require.NotNil(t, event.ActorID)
+	assert.Equal(t, supportID, *event.ActorID)
+}
+
+func TestAuthService_Impersonate_OtherTenantUserNotFound(t *testing.T) {
+	// Arrange
+	ctx := tenant.WithID(context.Background(), "shop-1")
+	userRepo := new(mocks.MockUserRepository)
+
+	user := newTestUser()
+	user.TenantID = "shop-2"
+	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+
+	service := NewAuthService(userRepo, new(mocks.MockRoleRepository), new(mocks.MockTokenRepository), newTestJWTManager(), mocks.NewMockMessagePublisher(), nil)
+	service.SetImpersonationAudit(new(mocks.MockImpersonationAuditRepository))
+
+	// Act
+	response, err := service.Impersonate(ctx, uuid.New(), "support@example.com", supportPermissions, user.ID)
+
+	// Assert
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestAuthService_Impersonate_Forbidden(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), "shop-1")
+	user := newTestUser()
+	user.TenantID = "shop-1"
+
+	tests := []struct {
+		name        string
+		actorID     uuid.UUID
+		role        *entity.Role
+		permissions []entity.Permission
+	}{
+		{name: "self", actorID: user.ID},
+		{name: "admin", actorID: uuid.New(), role: &entity.Role{ID: user.RoleID, Name: "admin"}},
+		{
+			name:        "support",
+			actorID:     uuid.New(),
+			role:        &entity.Role{ID: user.RoleID, Name: "support"},
+			permissions: []entity.Permission{{ID: 1, Code: entity.PermissionImpersonate}},
+		},
+		{
+			// У пользователя есть разрешение, которого нет у сотрудника
+			name:        "permissions not subset",
+			actorID:     uuid.New(),
+			role:        &entity.Role{ID: user.RoleID, Name: "manager"},
+			permissions: []entity.Permission{{ID: 1, Code: "product.read"}, {ID: 3, Code: "product.update"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			userRepo := new(mocks.MockUserRepository)
+			roleRepo := new(mocks.MockRoleRepository)
+			userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+			if tt.role != nil {
+				roleRepo.On("GetByID", ctx, user.RoleID).Return(tt.role, nil)
+				roleRepo.On("GetPermissionsByRoleID", ctx, user.RoleID).Return(tt.permissions, nil)
+			}
+
+			events := mocks.NewMockMessagePublisher()
+			audit := new(mocks.MockImpersonationAuditRepository)
+			service := NewAuthService(userRepo, roleRepo, new(mocks.MockTokenRepository), newTestJWTManager(), events, nil)
+			service.SetImpersonationAudit(audit)
+
+			// Act
+			response, err := service.Impersonate(ctx, tt.actorID, "support@example.com", supportPermissions, user.ID)
+
+			// Assert
+			assert.Nil(t, response)
+			assert.ErrorIs(t, err, ErrImpersonationForbidden)
+			assert.Empty(t, events.Messages)
+			audit.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAuthService_Impersonate_AuditFailed(t *testing.T) {
+	// Arrange
+	ctx := tenant.WithID(context.Background(), "shop-1")
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	events := mocks.NewMockMessagePublisher()
+
+	user := newTestUser()
+	user.TenantID = "shop-1"
+	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	roleRepo.On("GetByID", ctx, user.RoleID).Return(newTestRole(), nil)
+	roleRepo.On("GetPermissionsByRoleID", ctx, user.RoleID).Return(newTestPermissions(), nil)
+
+	audit := new(mocks.MockImpersonationAuditRepository)
+	audit.On("Create", ctx, mock.AnythingOfType("*entity.ImpersonationAudit")).Return(errors.New("db unavailable"))
+
+	service := NewAuthService(userRepo, roleRepo, new(mocks.MockTokenRepository), newTestJWTManager(), events, nil)
+	service.SetImpersonationAudit(audit)
+
+	// Act
+	response, err := service.Impersonate(ctx, uuid.New(), "support@example.com", supportPermissions, user.ID)
+
+	// Assert
+	// Без записи в журнале токен не выдается
+	assert.Nil(t, response)
+	assert.Error(t, err)
+	assert.Empty(t, events.Messages)
+}
+
+func TestAuthService_Impersonate_WithoutAudit(t *testing.T) {
+	// Arrange
+	userRepo := new(mocks.MockUserRepository)
+	service := NewAuthService(userRepo, new(mocks.MockRoleRepository), new(mocks.MockTokenRepository), newTestJWTManager(), mocks.NewMockMessagePublisher(), nil)
+
+	// Act
+	response, err := service.Impersonate(context.Background(), uuid.New(), "support@example.com", supportPermissions, uuid.New())
+
+	// Assert
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, ErrImpersonationUnavailable)
+	userRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
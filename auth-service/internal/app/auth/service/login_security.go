@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/auth-service/internal/app/auth/infrastructure"
+	"augustberries/auth-service/internal/app/auth/repository"
+	"augustberries/auth-service/internal/app/auth/util"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Причины, по которым вход требует подтверждения
+const (
+	LoginReasonNewDevice        = "new_device"
+	LoginReasonImpossibleTravel = "impossible_travel"
+)
+
+// minTravelDistanceKm - расстояние, ниже которого скорость перемещения не проверяется:
+// геолокация по IP неточна, и соседние города не должны считаться аномалией
+const minTravelDistanceKm = 100
+
+// VerificationRequiredError возвращается Login вместо токенов для подозрительного входа
+// errors.Is(err, ErrVerificationRequired) == true
+type VerificationRequiredError struct {
+	ChallengeID string
+	Reason      string
+	ExpiresAt   time.Time
+}
+
+func (e *VerificationRequiredError) Error() string {
+	return fmt.Sprintf("login verification required: %s", e.Reason)
+}
+
+func (e *VerificationRequiredError) Is(target error) bool {
+	return target == ErrVerificationRequired
+}
+
+// AccountLockedError возвращается Login для заблокированного аккаунта
+// errors.Is(err, ErrAccountLocked) == true
+type AccountLockedError struct {
+	ExpiresAt time.Time
+}
+
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("account locked until %s", e.ExpiresAt.Format(time.RFC3339))
+}
+
+func (e *AccountLockedError) Is(target error) bool {
+	return target == ErrAccountLocked
+}
+
+// LoginSecurityConfig - параметры проверки входа
+type LoginSecurityConfig struct {
+	ChallengeTTL      time.Duration // Срок действия кода подтверждения
+	MaxAttempts       int           // Попыток ввода кода на одну проверку
+	MaxTravelSpeedKmh float64       // Скорость перемещения между входами, выше которой вход подозрителен
+
+	LockoutThreshold int           // Неудачных входов за LockoutWindow до блокировки, 0 - без блокировки
+	LockoutWindow    time.Duration // Окно подсчета неудачных входов
+	LockoutDuration  time.Duration // Срок автоматической блокировки
+}
+
+// LoginSecurity отслеживает устройства и местоположение входов
+// Вход с нового устройства или "невозможное перемещение" требует подтверждения кодом из письма
+// Первый вход пользователя (устройств еще нет) считается доверенным
+// Серия неудачных входов временно блокирует аккаунт
+type LoginSecurity struct {
+	devices    repository.DeviceRepository
+	challenges repository.LoginChallengeRepository
+	attempts   repository.LoginAttemptRepository
+	geo        util.GeoLocator
+	mailer     infrastructure.EmailSender
+	cfg        LoginSecurityConfig
+	now        func() time.Time
+}
+
+// NewLoginSecurity создает проверку входа
+func NewLoginSecurity(
+	devices repository.DeviceRepository,
+	challenges repository.LoginChallengeRepository,
+	attempts repository.LoginAttemptRepository,
+	geo util.GeoLocator,
+	mailer infrastructure.EmailSender,
+	cfg LoginSecurityConfig,
+) *LoginSecurity {
+	return &LoginSecurity{
+		devices:    devices,
+		challenges: challenges,
+		attempts:   attempts,
+		geo:        geo,
+		mailer:     mailer,
+		cfg:        cfg,
+		now:        time.Now,
+	}
+}
+
+// deviceFor описывает устройство текущего входа
+func (s *LoginSecurity) deviceFor(userID uuid.UUID, req *entity.LoginRequest) *entity.Device {
+	now := s.now()
+	device := &entity.Device{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Fingerprint: fingerprint(req.DeviceID, req.UserAgent),
+		UserAgent:   req.UserAgent,
+		IP:          req.IP,
+		FirstSeenAt: now,
+		LastSeenAt:  now,
+	}
+
+	if location := s.geo.Locate(req.IP); location != nil {
+		device.Country = location.Country
+		device.City = location.City
+		device.Latitude = &location.Latitude
+		device.Longitude = &location.Longitude
+	}
+
+	return device
+}
+
+// assess возвращает причину, по которой вход требует подтверждения, или пустую строку
+func (s *LoginSecurity) assess(ctx context.Context, device *entity.Device) (string, error) {
+	devices, err := s.devices.ListByUser(ctx, device.UserID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user devices: %w", err)
+	}
+	if len(devices) == 0 {
+		return "", nil
+	}
+
+	known := false
+	for _, d := range devices {
+		if d.Fingerprint == device.Fingerprint {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return LoginReasonNewDevice, nil
+	}
+
+	// Устройства отсортированы по времени последнего входа
+	if s.impossibleTravel(&devices[0], device) {
+		return LoginReasonImpossibleTravel, nil
+	}
+
+	return "", nil
+}
+
+// impossibleTravel сравнивает место текущего входа с местом предыдущего
+func (s *LoginSecurity) impossibleTravel(last, current *entity.Device) bool {
+	if last.Latitude == nil || last.Longitude == nil || current.Latitude == nil || current.Longitude == nil {
+		return false
+	}
+
+	distance := util.DistanceKm(*last.Latitude, *last.Longitude, *current.Latitude, *current.Longitude)
+	if distance < minTravelDistanceKm {
+		return false
+	}
+
+	hours := current.LastSeenAt.Sub(last.LastSeenAt).Hours()
+	if hours <= 0 {
+		return true
+	}
+	return distance/hours > s.cfg.MaxTravelSpeedKmh
+}
+
+// challenge создает проверку и отправляет код подтверждения на email пользователя
+// rememberMe переносится в проверку, чтобы VerifyLogin выдал такой же токен, как запрошенный при входе
+func (s *LoginSecurity) challenge(ctx context.Context, user *entity.User, device *entity.Device, reason string, rememberMe bool) error {
+	code, err := verificationCode()
+	if err != nil {
+		return fmt.Errorf("failed to generate verification code: %w", err)
+	}
+
+	challenge := &entity.LoginChallenge{
+		ID:         uuid.NewString(),
+		UserID:     user.ID,
+		CodeHash:   hashCode(code),
+		Reason:     reason,
+		Device:     *device,
+		RememberMe: rememberMe,
+		ExpiresAt:  s.now().Add(s.cfg.ChallengeTTL),
+	}
+
+	if err := s.challenges.Save(ctx, challenge); err != nil {
+		return fmt.Errorf("failed to save login challenge: %w", err)
+	}
+
+	body := fmt.Sprintf("Код подтверждения входа: %s\nУстройство: %s\nIP: %s\nЕсли это были не вы, смените пароль.",
+		code, device.UserAgent, device.IP)
+	if err := s.mailer.Send(ctx, user.Email, "Подтверждение входа", body); err != nil {
+		return fmt.Errorf("failed to send verification code: %w", err)
+	}
+
+	return &VerificationRequiredError{ChallengeID: challenge.ID, Reason: reason, ExpiresAt: challenge.ExpiresAt}
+}
+
+// verify проверяет код и возвращает подтвержденную проверку
+// После исчерпания попыток проверка удаляется и нужно войти заново
+func (s *LoginSecurity) verify(ctx context.Context, challengeID, code string) (*entity.LoginChallenge, error) {
+	challenge, err := s.challenges.Get(ctx, challengeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvalidVerificationCode
+		}
+		return nil, fmt.Errorf("failed to get login challenge: %w", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashCode(code)), []byte(challenge.CodeHash)) == 1 {
+		if err := s.challenges.Delete(ctx, challengeID); err != nil {
+			return nil, err
+		}
+		return challenge, nil
+	}
+
+	challenge.Attempts++
+	if challenge.Attempts >= s.cfg.MaxAttempts {
+		if err := s.challenges.Delete(ctx, challengeID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidVerificationCode
+	}
+	if err := s.challenges.Save(ctx, challenge); err != nil {
+		return nil, fmt.Errorf("failed to save login challenge: %w", err)
+	}
+	return nil, ErrInvalidVerificationCode
+}
+
+// checkLock возвращает AccountLockedError для заблокированного аккаунта
+func (s *LoginSecurity) checkLock(ctx context.Context, userID uuid.UUID) error {
+	lock, err := s.attempts.GetLock(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("failed to check account lock: %w", err)
+	}
+	return &AccountLockedError{ExpiresAt: lock.ExpiresAt}
+}
+
+// recordFailure запоминает неудачный вход и блокирует аккаунт после LockoutThreshold неудач
+// Неудачи для несуществующих email (user == nil) только учитываются для панели безопасности
+func (s *LoginSecurity) recordFailure(ctx context.Context, req *entity.LoginRequest, user *entity.User) error {
+	now := s.now()
+	failures, err := s.attempts.RecordFailure(ctx, req.Email, req.IP, now, now.Add(-s.cfg.LockoutWindow))
+	if err != nil {
+		return err
+	}
+
+	if user == nil || s.cfg.LockoutThreshold <= 0 || failures < int64(s.cfg.LockoutThreshold) {
+		return nil
+	}
+
+	return s.attempts.Lock(ctx, &entity.AccountLock{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Reason:    entity.LockReasonFailedLogins,
+		LockedAt:  now,
+		ExpiresAt: now.Add(s.cfg.LockoutDuration),
+	})
+}
+
+// clearFailures сбрасывает счетчик неудачных входов после верного пароля
+func (s *LoginSecurity) clearFailures(ctx context.Context, email string) error {
+	return s.attempts.ClearFailures(ctx, email)
+}
+
+// recordLogin запоминает устройство доверенным
+func (s *LoginSecurity) recordLogin(ctx context.Context, device *entity.Device) error {
+	device.LastSeenAt = s.now()
+	if err := s.devices.Upsert(ctx, device); err != nil {
+		return fmt.Errorf("failed to record login device: %w", err)
+	}
+	return nil
+}
+
+// Devices возвращает устройства пользователя, новые входы первыми
+func (s *LoginSecurity) Devices(ctx context.Context, userID uuid.UUID) ([]entity.Device, error) {
+	devices, err := s.devices.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user devices: %w", err)
+	}
+	if devices == nil {
+		devices = []entity.Device{}
+	}
+	return devices, nil
+}
+
+// ForgetDevice удаляет устройство: следующий вход с него потребует подтверждения
+func (s *LoginSecurity) ForgetDevice(ctx context.Context, userID, deviceID uuid.UUID) error {
+	if err := s.devices.Delete(ctx, userID, deviceID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrDeviceNotFound
+		}
+		return fmt.Errorf("failed to delete device: %w", err)
+	}
+	return nil
+}
+
+// fingerprint - отпечаток устройства: device_id клиента, а без него User-Agent
+func fingerprint(deviceID, userAgent string) string {
+	source := "ua:" + userAgent
+	if deviceID != "" {
+		source = "id:" + deviceID
+	}
+	sum := sha256.Sum256([]byte(source))
+	return hex.EncodeToString(sum[:])
+}
+
+// verificationCode генерирует случайный шестизначный код
+func verificationCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+func hashCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/auth-service/internal/app/auth/repository/mocks"
+	"augustberries/auth-service/internal/app/auth/util"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Тестовая таблица геолокации: 10.0.0.0/8 - Москва, 20.0.0.0/8 - Нью-Йорк
+const testGeoCSV = `10.0.0.0/8,RU,Moscow,55.7558,37.6173
+20.0.0.0/8,US,New York,40.7128,-74.0060
+`
+
+type loginSecurityFixture struct {
+	userRepo   *mocks.MockUserRepository
+	roleRepo   *mocks.MockRoleRepository
+	tokenRepo  *mocks.MockTokenRepository
+	devices    *mocks.MockDeviceRepository
+	challenges *mocks.MockLoginChallengeRepository
+	attempts   *mocks.MockLoginAttemptRepository
+	mailer     *mocks.MockEmailSender
+	service    *AuthService
+}
+
+func newLoginSecurityFixture(t *testing.T) *loginSecurityFixture {
+	geo, err := util.NewCIDRGeoLocator(strings.NewReader(testGeoCSV))
+	require.NoError(t, err)
+
+	f := &loginSecurityFixture{
+		userRepo:   new(mocks.MockUserRepository),
+		roleRepo:   new(mocks.MockRoleRepository),
+		tokenRepo:  new(mocks.MockTokenRepository),
+		devices:    new(mocks.MockDeviceRepository),
+		challenges: new(mocks.MockLoginChallengeRepository),
+		attempts:   new(mocks.MockLoginAttemptRepository),
+		mailer:     new(mocks.MockEmailSender),
+	}
+	security := NewLoginSecurity(f.devices, f.challenges, f.attempts, geo, f.mailer, LoginSecurityConfig{
+		ChallengeTTL:      10 * time.Minute,
+		MaxAttempts:       3,
+		MaxTravelSpeedKmh: 900,
+		LockoutThreshold:  3,
+		LockoutWindow:     15 * time.Minute,
+		LockoutDuration:   30 * time.Minute,
+	})
+	// По умолчанию аккаунты не заблокированы
+	f.attempts.On("GetLock", mock.Anything, mock.Anything).Return(nil, pgx.ErrNoRows).Maybe()
+	f.attempts.On("ClearFailures", mock.Anything, mock.Anything).Return(nil).Maybe()
+	f.service = NewAuthService(f.userRepo, f.roleRepo, f.tokenRepo, newTestJWTManager(), mocks.NewMockMessagePublisher(), security)
+	return f
+}
+
+// expectTokens настраивает моки для выдачи токенов пользователю
+func (f *loginSecurityFixture) expectTokens(ctx context.Context, user *entity.User) {
+	f.roleRepo.On("GetByID", ctx, user.RoleID).Return(newTestRole(), nil)
+	f.roleRepo.On("GetPermissionsByRoleID", ctx, user.RoleID).Return(newTestPermissions(), nil)
+	f.userRepo.On("ListTenantRoles", ctx, mock.AnythingOfType("uuid.UUID")).Return(nil, nil)
+	f.tokenRepo.On("SaveRefreshToken", ctx, user.ID, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
+}
+
+func knownDevice(userID uuid.UUID, deviceID string, lat, lon float64, lastSeen time.Time) entity.Device {
+	return entity.Device{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Fingerprint: fingerprint(deviceID, ""),
+		Latitude:    &lat,
+		Longitude:   &lon,
+		FirstSeenAt: lastSeen,
+		LastSeenAt:  lastSeen,
+	}
+}
+
+// ==================== Login Security Tests ====================
+
+func TestAuthService_Login_FirstDeviceTrusted(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	f := newLoginSecurityFixture(t)
+	user := newTestUser()
+
+	f.userRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+	f.devices.On("ListByUser", ctx, user.ID).Return([]entity.Device{}, nil)
+	f.devices.On("Upsert", ctx, mock.MatchedBy(func(d *entity.Device) bool {
+		return d.UserID == user.ID && d.Country == "RU" && d.City == "Moscow"
+	})).Return(nil)
+	f.expectTokens(ctx, user)
+
+	// Act
+	response, err := f.service.Login(ctx, &entity.LoginRequest{
+		Email: user.Email, Password: "password123", DeviceID: "laptop", IP: "10.1.2.3",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.NotEmpty(t, response.Tokens.AccessToken)
+	f.devices.AssertExpectations(t)
+	assert.Empty(t, f.mailer.Bodies)
+}
+
+func TestAuthService_Login_NewDeviceRequiresVerification(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	f := newLoginSecurityFixture(t)
+	user := newTestUser()
+
+	f.userRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+	f.devices.On("ListByUser", ctx, user.ID).Return([]entity.Device{
+		knownDevice(user.ID, "laptop", 55.7558, 37.6173, time.Now().Add(-time.Hour)),
+	}, nil)
+	f.challenges.On("Save", ctx, mock.MatchedBy(func(c *entity.LoginChallenge) bool {
+		return c.UserID == user.ID && c.Reason == LoginReasonNewDevice && c.CodeHash != ""
+	})).Return(nil)
+
+	// Act
+	response, err := f.service.Login(ctx, &entity.LoginRequest{
+		Email: user.Email, Password: "password123", DeviceID: "phone", IP: "10.1.2.3",
+	})
+
+	// Assert
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, ErrVerificationRequired)
+	var verification *VerificationRequiredError
+	require.ErrorAs(t, err, &verification)
+	assert.Equal(t, LoginReasonNewDevice, verification.Reason)
+	assert.NotEmpty(t, verification.ChallengeID)
+	require.Len(t, f.mailer.Bodies, 1)
+	f.tokenRepo.AssertNotCalled(t, "SaveRefreshToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	f.devices.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Login_ImpossibleTravelRequiresVerification(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	f := newLoginSecurityFixture(t)
+	user := newTestUser()
+
+	// Час назад вход из Москвы, теперь из Нью-Йорка (~7500 км)
+	f.userRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+	f.devices.On("ListByUser", ctx, user.ID).Return([]entity.Device{
+		knownDevice(user.ID, "laptop", 55.7558, 37.6173, time.Now().Add(-time.Hour)),
+	}, nil)
+	f.challenges.On("Save", ctx, mock.AnythingOfType("*entity.LoginChallenge")).Return(nil)
+
+	// Act
+	_, err := f.service.Login(ctx, &entity.LoginRequest{
+		Email: user.Email, Password: "password123", DeviceID: "laptop", IP: "20.1.2.3",
+	})
+
+	// Assert
+	var verification *VerificationRequiredError
+	require.ErrorAs(t, err, &verification)
+	assert.Equal(t, LoginReasonImpossibleTravel, verification.Reason)
+}
+
+func TestAuthService_Login_KnownDeviceAfterLongTimeAllowed(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	f := newLoginSecurityFixture(t)
+	user := newTestUser()
+
+	// Москва -> Нью-Йорк за сутки - реальное перемещение
+	f.userRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+	f.devices.On("ListByUser", ctx, user.ID).Return([]entity.Device{
+		knownDevice(user.ID, "laptop", 55.7558, 37.6173, time.Now().Add(-24*time.Hour)),
+	}, nil)
+	f.devices.On("Upsert", ctx, mock.AnythingOfType("*entity.Device")).Return(nil)
+	f.expectTokens(ctx, user)
+
+	// Act
+	response, err := f.service.Login(ctx, &entity.LoginRequest{
+		Email: user.Email, Password: "password123", DeviceID: "laptop", IP: "20.1.2.3",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.NotEmpty(t, response.Tokens.AccessToken)
+}
+
+func TestAuthService_VerifyLogin_Success(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	f := newLoginSecurityFixture(t)
+	user := newTestUser()
+
+	challenge := &entity.LoginChallenge{
+		ID:        "challenge-1",
+		UserID:    user.ID,
+		CodeHash:  hashCode("123456"),
+		Reason:    LoginReasonNewDevice,
+		Device:    entity.Device{ID: uuid.New(), UserID: user.ID, Fingerprint: fingerprint("phone", "")},
+		ExpiresAt: time.Now().Add(5 * time.Minute),
+	}
+	f.challenges.On("Get", ctx, "challenge-1").Return(challenge, nil)
+	f.challenges.On("Delete", ctx, "challenge-1").Return(nil)
+	f.userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	f.devices.On("Upsert", ctx, mock.MatchedBy(func(d *entity.Device) bool {
+		return d.Fingerprint == challenge.Device.Fingerprint
+	})).Return(nil)
+	f.expectTokens(ctx, user)
+
+	// Act
+	response, err := f.service.VerifyLogin(ctx, &entity.VerifyLoginRequest{ChallengeID: "challenge-1", Code: "123456"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, response.User.ID)
+	f.challenges.AssertExpectations(t)
+	f.devices.AssertExpectations(t)
+}
+
+func TestAuthService_VerifyLogin_WrongCodeExhaustsAttempts(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	f := newLoginSecurityFixture(t)
+
+	challenge := &entity.LoginChallenge{
+		ID:        "challenge-1",
+		UserID:    uuid.New(),
+		CodeHash:  hashCode("123456"),
+		Attempts:  1,
+		ExpiresAt: time.Now().Add(5 * time.Minute),
+	}
+	f.challenges.On("Get", ctx, "challenge-1").Return(challenge, nil)
+	f.challenges.On("Save", ctx, challenge).Return(nil).Once()
+	f.challenges.On("Delete", ctx, "challenge-1").Return(nil).Once()
+
+	// Act: вторая неудачная попытка сохраняется, третья удаляет проверку
+	_, err1 := f.service.VerifyLogin(ctx, &entity.VerifyLoginRequest{ChallengeID: "challenge-1", Code: "000000"})
+	_, err2 := f.service.VerifyLogin(ctx, &entity.VerifyLoginRequest{ChallengeID: "challenge-1", Code: "000000"})
+
+	// Assert
+	assert.ErrorIs(t, err1, ErrInvalidVerificationCode)
+	assert.ErrorIs(t, err2, ErrInvalidVerificationCode)
+	assert.Equal(t, 3, challenge.Attempts)
+	f.challenges.AssertExpectations(t)
+	f.userRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+// ==================== Account Lockout Tests ====================
+
+func TestAuthService_Login_LocksAccountAfterThreshold(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	f := newLoginSecurityFixture(t)
+	user := newTestUser()
+
+	f.userRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+	f.attempts.On("RecordFailure", ctx, user.Email, "10.1.2.3", mock.Anything, mock.Anything).Return(int64(3), nil)
+	f.attempts.On("Lock", ctx, mock.MatchedBy(func(l *entity.AccountLock) bool {
+		return l.UserID == user.ID && l.Reason == entity.LockReasonFailedLogins && l.ExpiresAt.After(time.Now().Add(29*time.Minute))
+	})).Return(nil)
+
+	// Act
+	_, err := f.service.Login(ctx, &entity.LoginRequest{Email: user.Email, Password: "wrong", IP: "10.1.2.3"})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	f.attempts.AssertExpectations(t)
+}
+
+func TestAuthService_Login_LockedAccountRejected(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	f := newLoginSecurityFixture(t)
+	user := newTestUser()
+
+	expiresAt := time.Now().Add(20 * time.Minute)
+	f.attempts.ExpectedCalls = nil
+	f.userRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+	f.attempts.On("GetLock", ctx, user.ID).Return(&entity.AccountLock{UserID: user.ID, ExpiresAt: expiresAt}, nil)
+
+	// Act: даже верный пароль не открывает заблокированный аккаунт
+	response, err := f.service.Login(ctx, &entity.LoginRequest{Email: user.Email, Password: "password123"})
+
+	// Assert
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, ErrAccountLocked)
+	var locked *AccountLockedError
+	require.ErrorAs(t, err, &locked)
+	assert.Equal(t, expiresAt, locked.ExpiresAt)
+	f.tokenRepo.AssertNotCalled(t, "SaveRefreshToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Login_UnknownEmailRecordedWithoutLock(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	f := newLoginSecurityFixture(t)
+
+	f.userRepo.On("GetByEmail", ctx, "ghost@example.com").Return(nil, pgx.ErrNoRows)
+	f.attempts.On("RecordFailure", ctx, "ghost@example.com", "", mock.Anything, mock.Anything).Return(int64(10), nil)
+
+	// Act
+	_, err := f.service.Login(ctx, &entity.LoginRequest{Email: "ghost@example.com", Password: "password123"})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	f.attempts.AssertNotCalled(t, "Lock", mock.Anything, mock.Anything)
+}
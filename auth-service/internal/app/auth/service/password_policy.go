@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/auth-service/internal/app/auth/infrastructure"
+	"augustberries/auth-service/internal/app/auth/util"
+)
+
+// PasswordPolicyError возвращается Register и UpdatePassword для пароля, не прошедшего политику
+// errors.Is(err, ErrPasswordPolicy) == true
+type PasswordPolicyError struct {
+	Violations []entity.PasswordViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	codes := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		codes = append(codes, v.Code)
+	}
+	return fmt.Sprintf("password does not meet policy: %s", strings.Join(codes, ", "))
+}
+
+func (e *PasswordPolicyError) Is(target error) bool {
+	return target == ErrPasswordPolicy
+}
+
+// PasswordPolicyConfig - требования к новым паролям
+type PasswordPolicyConfig struct {
+	MinLength          int  // Минимальная длина в символах
+	MinScore           int  // Минимальная оценка стойкости 0-4 (util.EstimatePasswordStrength), 0 - без проверки
+	ForbidPersonalInfo bool // Запрет частей email и имени в пароле
+}
+
+// PasswordPolicy проверяет новые пароли при регистрации и смене пароля
+type PasswordPolicy struct {
+	cfg      PasswordPolicyConfig
+	breached infrastructure.BreachedPasswordChecker // nil - без проверки по базе утечек
+}
+
+// NewPasswordPolicy создает проверку паролей; breached == nil отключает проверку по базе утечек
+func NewPasswordPolicy(cfg PasswordPolicyConfig, breached infrastructure.BreachedPasswordChecker) *PasswordPolicy {
+	return &PasswordPolicy{cfg: cfg, breached: breached}
+}
+
+// Validate возвращает *PasswordPolicyError со всеми нарушениями или nil
+// Недоступность базы утечек не блокирует пароль: ошибка пишется в лог
+func (p *PasswordPolicy) Validate(ctx context.Context, password, email, name string) error {
+	if p == nil {
+		return nil
+	}
+
+	var violations []entity.PasswordViolation
+	if len([]rune(password)) < p.cfg.MinLength {
+		violations = append(violations, entity.PasswordViolation{
+			Code:    entity.PasswordViolationTooShort,
+			Message: fmt.Sprintf("Password must be at least %d characters", p.cfg.MinLength),
+		})
+	}
+
+	if p.cfg.ForbidPersonalInfo && containsPersonalInfo(password, email, name) {
+		violations = append(violations, entity.PasswordViolation{
+			Code:    entity.PasswordViolationPersonalInfo,
+			Message: "Password must not contain your email or name",
+		})
+	}
+
+	if p.cfg.MinScore > 0 {
+		if strength := util.EstimatePasswordStrength(password, email, name); strength.Score < p.cfg.MinScore {
+			violations = append(violations, entity.PasswordViolation{
+				Code:    entity.PasswordViolationTooWeak,
+				Message: "Password is too easy to guess: avoid common words, sequences and repeated characters",
+			})
+		}
+	}
+
+	if p.breached != nil {
+		breached, err := p.breached.IsBreached(ctx, password)
+		if err != nil {
+			fmt.Printf("failed to check password against breaches: %v\n", err)
+		} else if breached {
+			violations = append(violations, entity.PasswordViolation{
+				Code:    entity.PasswordViolationBreached,
+				Message: "Password has appeared in a data breach, choose a different one",
+			})
+		}
+	}
+
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// containsPersonalInfo ищет в пароле части email и имени без учета регистра
+func containsPersonalInfo(password, email, name string) bool {
+	password = strings.ToLower(password)
+	for _, value := range []string{email, name} {
+		for _, part := range util.PersonalInfoParts(value) {
+			if strings.Contains(password, part) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SetPasswordPolicy включает проверку пароля при регистрации; nil - только проверка длины в запросе
+func (s *AuthService) SetPasswordPolicy(p *PasswordPolicy) {
+	s.passwordPolicy = p
+}
+
+// SetPasswordPolicy включает проверку нового пароля при смене; nil - только проверка длины в запросе
+func (s *UserService) SetPasswordPolicy(p *PasswordPolicy) {
+	s.passwordPolicy = p
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/auth-service/internal/app/auth/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPasswordPolicy(breached *mocks.MockBreachedPasswordChecker) *PasswordPolicy {
+	cfg := PasswordPolicyConfig{MinLength: 8, MinScore: 2, ForbidPersonalInfo: true}
+	if breached == nil {
+		return NewPasswordPolicy(cfg, nil)
+	}
+	return NewPasswordPolicy(cfg, breached)
+}
+
+// violationCodes возвращает коды нарушений из ошибки политики
+func violationCodes(t *testing.T, err error) []string {
+	t.Helper()
+	var policyErr *PasswordPolicyError
+	require.ErrorAs(t, err, &policyErr)
+	codes := make([]string, 0, len(policyErr.Violations))
+	for _, v := range policyErr.Violations {
+		codes = append(codes, v.Code)
+	}
+	return codes
+}
+
+// ==================== Password Policy Tests ====================
+
+func TestPasswordPolicy_AcceptsStrongPassword(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	breached := new(mocks.MockBreachedPasswordChecker)
+	breached.On("IsBreached", ctx, "berries-and-cream-42").Return(false, nil)
+	policy := newTestPasswordPolicy(breached)
+
+	// Act
+	err := policy.Validate(ctx, "berries-and-cream-42", "ivan.petrov@example.com", "Ivan Petrov")
+
+	// Assert
+	assert.NoError(t, err)
+	breached.AssertExpectations(t)
+}
+
+func TestPasswordPolicy_CollectsAllViolations(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	breached := new(mocks.MockBreachedPasswordChecker)
+	breached.On("IsBreached", ctx, "ivan1").Return(true, nil)
+	policy := newTestPasswordPolicy(breached)
+
+	// Act
+	err := policy.Validate(ctx, "ivan1", "ivan.petrov@example.com", "Ivan Petrov")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrPasswordPolicy)
+	assert.Equal(t, []string{
+		entity.PasswordViolationTooShort,
+		entity.PasswordViolationPersonalInfo,
+		entity.PasswordViolationTooWeak,
+		entity.PasswordViolationBreached,
+	}, violationCodes(t, err))
+}
+
+func TestPasswordPolicy_RejectsPersonalInfoCaseInsensitive(t *testing.T) {
+	policy := newTestPasswordPolicy(nil)
+
+	err := policy.Validate(context.Background(), "xK#9PETROVq2!", "ivan.petrov@example.com", "Ivan Petrov")
+
+	assert.Equal(t, []string{entity.PasswordViolationPersonalInfo}, violationCodes(t, err))
+}
+
+func TestPasswordPolicy_BreachCheckFailureDoesNotBlock(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	breached := new(mocks.MockBreachedPasswordChecker)
+	breached.On("IsBreached", ctx, mock.Anything).Return(false, errors.New("timeout"))
+	policy := newTestPasswordPolicy(breached)
+
+	// Act
+	err := policy.Validate(ctx, "berries-and-cream-42", "ivan.petrov@example.com", "Ivan Petrov")
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestPasswordPolicy_NilPolicyAcceptsAnything(t *testing.T) {
+	var policy *PasswordPolicy
+
+	assert.NoError(t, policy.Validate(context.Background(), "1", "a@b.c", "A"))
+}
+
+func TestAuthService_Register_PasswordPolicyViolation(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	tokenRepo := new(mocks.MockTokenRepository)
+
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, newTestJWTManager(), mocks.NewMockMessagePublisher(), nil)
+	service.SetPasswordPolicy(newTestPasswordPolicy(nil))
+
+	// Act
+	response, err := service.Register(ctx, &entity.RegisterRequest{
+		Email:    "newuser@example.com",
+		Password: "password123",
+		Name:     "New User",
+	})
+
+	// Assert
+	assert.Nil(t, response)
+	assert.Equal(t, []string{entity.PasswordViolationTooWeak}, violationCodes(t, err))
+	userRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestUserService_UpdatePassword_PasswordPolicyViolation(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+
+	user := newTestUser() // Пароль: password123, email test@example.com
+	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+
+	service := NewUserService(userRepo, roleRepo, mocks.NewMockMessagePublisher())
+	service.SetPasswordPolicy(newTestPasswordPolicy(nil))
+
+	// Act
+	err := service.UpdatePassword(ctx, user.ID, "password123", "my-test-berries-42")
+
+	// Assert
+	assert.Equal(t, []string{entity.PasswordViolationPersonalInfo}, violationCodes(t, err))
+	userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/auth-service/internal/app/auth/infrastructure"
+	"augustberries/auth-service/internal/app/auth/repository"
+	"augustberries/auth-service/internal/app/auth/util"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// MaxProvisionBatch - максимальное число строк в одном запросе провижининга
+	MaxProvisionBatch = 1000
+
+	defaultProvisionRole = "user"
+	minProvisionPassword = 8
+	minProvisionName     = 2
+
+	// unusablePasswordHash не является хэшем ни одного алгоритма: CheckPassword для него всегда false
+	unusablePasswordHash = "!"
+)
+
+// errRowInternal - текст ошибки строки при сбое хранилища, детали пишутся в лог
+const errRowInternal = "internal error"
+
+// ProvisioningService синхронизирует аккаунты сотрудников из внешних каталогов (SCIM-подобный bulk)
+// Создает, обновляет и отключает пользователей магазина администратора, каждое изменение публикуется
+// в user_events с actor_id для аудита
+type ProvisioningService struct {
+	userRepo  repository.UserRepository
+	roleRepo  repository.RoleRepository
+	tokenRepo repository.TokenRepository
+	events    infrastructure.MessagePublisher // Kafka producer топика user_events
+}
+
+// NewProvisioningService создает сервис провижининга пользователей
+func NewProvisioningService(
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	tokenRepo repository.TokenRepository,
+	events infrastructure.MessagePublisher,
+) *ProvisioningService {
+	return &ProvisioningService{
+		userRepo:  userRepo,
+		roleRepo:  roleRepo,
+		tokenRepo: tokenRepo,
+		events:    events,
+	}
+}
+
+// provisionBatch - состояние обработки одного запроса
+type provisionBatch struct {
+	actorID  uuid.UUID
+	tenantID string
+	roles    map[string]*entity.Role // Роли запрашиваются один раз на пакет
+	seen     map[string]bool         // Email, уже встречавшиеся в пакете
+}
+
+// Provision применяет строки пакета по порядку и возвращает результат каждой строки
+// Ошибка возвращается только для пакета целиком (пустой или слишком большой)
+func (s *ProvisioningService) Provision(ctx context.Context, actorID uuid.UUID, users []entity.ProvisionUser) (*entity.ProvisionUsersResponse, error) {
+	if len(users) == 0 {
+		return nil, ErrProvisioningBatchEmpty
+	}
+	if len(users) > MaxProvisionBatch {
+		return nil, ErrProvisioningBatchTooLarge
+	}
+
+	batch := &provisionBatch{
+		actorID:  actorID,
+		tenantID: tenant.FromContext(ctx),
+		roles:    make(map[string]*entity.Role),
+		seen:     make(map[string]bool, len(users)),
+	}
+
+	response := &entity.ProvisionUsersResponse{
+		Results: make([]entity.ProvisionResult, 0, len(users)),
+		Summary: make(map[string]int),
+	}
+	for i, row := range users {
+		result := s.provisionRow(ctx, batch, row)
+		result.Index = i
+		response.Results = append(response.Results, result)
+		response.Summary[result.Status]++
+	}
+
+	return response, nil
+}
+
+// provisionRow обрабатывает одну строку; ошибки строки попадают в результат
+func (s *ProvisioningService) provisionRow(ctx context.Context, batch *provisionBatch, row entity.ProvisionUser) entity.ProvisionResult {
+	row.Email = strings.TrimSpace(row.Email)
+	row.Name = strings.TrimSpace(row.Name)
+	result := entity.ProvisionResult{Email: row.Email}
+
+	fail := func(message string) entity.ProvisionResult {
+		result.Status = entity.ProvisionFailed
+		result.Error = message
+		return result
+	}
+
+	if err := validateProvisionRow(row); err != nil {
+		return fail(err.Error())
+	}
+	key := strings.ToLower(row.Email)
+	if batch.seen[key] {
+		return fail("duplicate email in batch")
+	}
+	batch.seen[key] = true
+
+	existing, err := s.userRepo.GetByEmail(ctx, row.Email)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		fmt.Printf("failed to get user for provisioning: %v\n", err)
+		return fail(errRowInternal)
+	}
+
+	var user *entity.User
+	var status string
+	if existing == nil {
+		user, err = s.createUser(ctx, batch, row)
+		status = entity.ProvisionCreated
+	} else {
+		if existing.TenantID != batch.tenantID {
+			return fail("user belongs to another tenant")
+		}
+		user = existing
+		status, err = s.updateUser(ctx, batch, existing, row)
+	}
+	if err != nil {
+		var rowErr provisionRowError
+		if errors.As(err, &rowErr) {
+			return fail(string(rowErr))
+		}
+		fmt.Printf("failed to provision user: %v\n", err)
+		return fail(errRowInternal)
+	}
+
+	result.UserID = user.ID.String()
+	result.Status = status
+	return result
+}
+
+// provisionRowError - ошибка данных строки, текст возвращается клиенту как есть
+type provisionRowError string
+
+func (e provisionRowError) Error() string { return string(e) }
+
+// validateProvisionRow проверяет формат полей строки
+func validateProvisionRow(row entity.ProvisionUser) error {
+	if row.Email == "" {
+		return provisionRowError("email is required")
+	}
+	if addr, err := mail.ParseAddress(row.Email); err != nil || addr.Address != row.Email {
+		return provisionRowError("email is invalid")
+	}
+	if row.Password != "" && utf8.RuneCountInString(row.Password) < minProvisionPassword {
+		return provisionRowError(fmt.Sprintf("password must be at least %d characters", minProvisionPassword))
+	}
+	return nil
+}
+
+// role возвращает роль по имени с кешированием в рамках пакета
+func (s *ProvisioningService) role(ctx context.Context, batch *provisionBatch, name string) (*entity.Role, error) {
+	if role, ok := batch.roles[name]; ok {
+		return role, nil
+	}
+
+	role, err := s.roleRepo.GetByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, provisionRowError(fmt.Sprintf("role %q not found", name))
+		}
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+
+	batch.roles[name] = role
+	return role, nil
+}
+
+// createUser создает пользователя в магазине администратора
+func (s *ProvisioningService) createUser(ctx context.Context, batch *provisionBatch, row entity.ProvisionUser) (*entity.User, error) {
+	if utf8.RuneCountInString(row.Name) < minProvisionName {
+		return nil, provisionRowError("name is required for new users")
+	}
+
+	roleName := row.Role
+	if roleName == "" {
+		roleName = defaultProvisionRole
+	}
+	role, err := s.role(ctx, batch, roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordHash := unusablePasswordHash
+	if row.Password != "" {
+		if passwordHash, err = util.HashPassword(row.Password); err != nil {
+			return nil, fmt.Errorf("failed to hash password: %w", err)
+		}
+	}
+
+	now := time.Now()
+	user := &entity.User{
+		ID:           uuid.New(),
+		Email:        row.Email,
+		PasswordHash: passwordHash,
+		Name:         row.Name,
+		RoleID:       role.ID,
+		TenantID:     batch.tenantID,
+		CreatedAt:    now,
+	}
+	if row.Active != nil && !*row.Active {
+		user.DeactivatedAt = &now
+	}
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	s.publish(ctx, batch, entity.EventUserRegistered, user, 0)
+	if !user.Active() {
+		s.publish(ctx, batch, entity.EventUserDeactivated, user, 0)
+	}
+
+	return user, nil
+}
+
+// updateUser применяет к существующему пользователю только заданные и отличающиеся поля
+func (s *ProvisioningService) updateUser(ctx context.Context, batch *provisionBatch, user *entity.User, row entity.ProvisionUser) (string, error) {
+	previousRoleID := user.RoleID
+	wasActive := user.Active()
+	profileChanged, passwordChanged := false, false
+
+	if row.Name != "" && row.Name != user.Name {
+		if utf8.RuneCountInString(row.Name) < minProvisionName {
+			return "", provisionRowError(fmt.Sprintf("name must be at least %d characters", minProvisionName))
+		}
+		user.Name = row.Name
+		profileChanged = true
+	}
+
+	if row.Role != "" {
+		role, err := s.role(ctx, batch, row.Role)
+		if err != nil {
+			return "", err
+		}
+		user.RoleID = role.ID
+	}
+
+	if row.Password != "" && !util.CheckPassword(row.Password, user.PasswordHash) {
+		passwordHash, err := util.HashPassword(row.Password)
+		if err != nil {
+			return "", fmt.Errorf("failed to hash password: %w", err)
+		}
+		user.PasswordHash = passwordHash
+		passwordChanged = true
+	}
+
+	if row.Active != nil && *row.Active != wasActive {
+		if *row.Active {
+			user.DeactivatedAt = nil
+		} else {
+			if user.ID == batch.actorID {
+				return "", provisionRowError("cannot deactivate yourself")
+			}
+			now := time.Now()
+			user.DeactivatedAt = &now
+		}
+	}
+
+	roleChanged := user.RoleID != previousRoleID
+	activeChanged := user.Active() != wasActive
+	if !profileChanged && !roleChanged && !passwordChanged && !activeChanged {
+		return entity.ProvisionUnchanged, nil
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return "", fmt.Errorf("failed to update user: %w", err)
+	}
+
+	// Отключенный пользователь теряет все сессии; access токены истекают сами
+	if activeChanged && !user.Active() {
+		if err := s.tokenRepo.DeleteUserRefreshTokens(ctx, user.ID); err != nil {
+			fmt.Printf("failed to revoke tokens of deactivated user: %v\n", err)
+		}
+	}
+
+	if profileChanged {
+		s.publish(ctx, batch, entity.EventUserUpdated, user, 0)
+	}
+	if roleChanged {
+		s.publish(ctx, batch, entity.EventRoleChanged, user, previousRoleID)
+	}
+	if passwordChanged {
+		s.publish(ctx, batch, entity.EventPasswordChanged, user, 0)
+	}
+	if activeChanged {
+		if user.Active() {
+			s.publish(ctx, batch, entity.EventUserReactivated, user, 0)
+		} else {
+			s.publish(ctx, batch, entity.EventUserDeactivated, user, 0)
+			return entity.ProvisionDeactivated, nil
+		}
+	}
+
+	return entity.ProvisionUpdated, nil
+}
+
+// publish отправляет событие аудита с автором изменения
+func (s *ProvisioningService) publish(ctx context.Context, batch *provisionBatch, eventType string, user *entity.User, previousRoleID int) {
+	event := newUserEvent(eventType, user)
+	event.PreviousRoleID = previousRoleID
+	event.ActorID = &batch.actorID
+	if err := publishUserEvent(ctx, s.events, event); err != nil {
+		fmt.Printf("failed to publish %s event: %v\n", eventType, err)
+	}
+}
@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/auth-service/internal/app/auth/repository/mocks"
+	"augustberries/auth-service/internal/app/auth/util"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func boolPtr(v bool) *bool { return &v }
+
+// ==================== Provision Tests ====================
+
+func TestProvisioningService_Provision_CreatesUser(t *testing.T) {
+	// Arrange
+	ctx := tenant.WithID(context.Background(), "shop-1")
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	tokenRepo := new(mocks.MockTokenRepository)
+	publisher := mocks.NewMockMessagePublisher()
+	actorID := uuid.New()
+
+	userRepo.On("GetByEmail", ctx, "new@example.com").Return(nil, pgx.ErrNoRows)
+	roleRepo.On("GetByName", ctx, "user").Return(newTestRole(), nil)
+	userRepo.On("Create", ctx, mock.AnythingOfType("*entity.User")).Return(nil)
+
+	service := NewProvisioningService(userRepo, roleRepo, tokenRepo, publisher)
+
+	// Act
+	response, err := service.Provision(ctx, actorID, []entity.ProvisionUser{
+		{Email: " new@example.com ", Name: "New User"},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, response.Results, 1)
+	assert.Equal(t, entity.ProvisionCreated, response.Results[0].Status)
+	assert.Equal(t, "new@example.com", response.Results[0].Email)
+	assert.NotEmpty(t, response.Results[0].UserID)
+	assert.Equal(t, 1, response.Summary[entity.ProvisionCreated])
+
+	created := userRepo.Calls[1].Arguments.Get(1).(*entity.User)
+	assert.Equal(t, "shop-1", created.TenantID)
+	assert.True(t, created.Active())
+	assert.False(t, util.CheckPassword("", created.PasswordHash), "without password the account must not be usable")
+
+	events := decodeUserEvents(t, publisher)
+	require.Len(t, events, 1)
+	assert.Equal(t, entity.EventUserRegistered, events[0].EventType)
+	require.NotNil(t, events[0].ActorID)
+	assert.Equal(t, actorID, *events[0].ActorID)
+}
+
+func TestProvisioningService_Provision_RowErrorsDoNotStopBatch(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	tokenRepo := new(mocks.MockTokenRepository)
+
+	foreign := newTestUser()
+	foreign.Email = "foreign@example.com"
+	foreign.TenantID = "other-shop"
+
+	userRepo.On("GetByEmail", ctx, "a@example.com").Return(nil, pgx.ErrNoRows)
+	userRepo.On("GetByEmail", ctx, "b@example.com").Return(nil, pgx.ErrNoRows)
+	userRepo.On("GetByEmail", ctx, foreign.Email).Return(foreign, nil)
+	roleRepo.On("GetByName", ctx, "user").Return(newTestRole(), nil).Once()
+	roleRepo.On("GetByName", ctx, "auditor").Return(nil, pgx.ErrNoRows)
+	userRepo.On("Create", ctx, mock.AnythingOfType("*entity.User")).Return(nil)
+
+	service := NewProvisioningService(userRepo, roleRepo, tokenRepo, mocks.NewMockMessagePublisher())
+
+	// Act
+	response, err := service.Provision(ctx, uuid.New(), []entity.ProvisionUser{
+		{Email: "a@example.com", Name: "User A"},
+		{Email: "not-an-email", Name: "Broken"},
+		{Email: "A@example.com", Name: "Duplicate"},
+		{Email: "b@example.com", Name: "User B", Role: "auditor"},
+		{Email: foreign.Email, Name: "Foreign"},
+		{Email: "b@example.com", Name: "User B", Password: "short"},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, response.Results, 6)
+	assert.Equal(t, entity.ProvisionCreated, response.Results[0].Status)
+	assert.Equal(t, "email is invalid", response.Results[1].Error)
+	assert.Equal(t, "duplicate email in batch", response.Results[2].Error)
+	assert.Equal(t, `role "auditor" not found`, response.Results[3].Error)
+	assert.Equal(t, "user belongs to another tenant", response.Results[4].Error)
+	assert.Contains(t, response.Results[5].Error, "password")
+	for i, result := range response.Results {
+		assert.Equal(t, i, result.Index)
+	}
+	assert.Equal(t, 1, response.Summary[entity.ProvisionCreated])
+	assert.Equal(t, 5, response.Summary[entity.ProvisionFailed])
+
+	userRepo.AssertNumberOfCalls(t, "Create", 1)
+	userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestProvisioningService_Provision_DeactivatesAndRevokesTokens(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	tokenRepo := new(mocks.MockTokenRepository)
+	publisher := mocks.NewMockMessagePublisher()
+
+	user := newTestUser()
+	user.TenantID = tenant.DefaultID
+
+	userRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+	userRepo.On("Update", ctx, user).Return(nil)
+	tokenRepo.On("DeleteUserRefreshTokens", ctx, user.ID).Return(nil)
+
+	service := NewProvisioningService(userRepo, roleRepo, tokenRepo, publisher)
+
+	// Act
+	response, err := service.Provision(ctx, uuid.New(), []entity.ProvisionUser{
+		{Email: user.Email, Active: boolPtr(false)},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, entity.ProvisionDeactivated, response.Results[0].Status)
+	assert.False(t, user.Active())
+	tokenRepo.AssertExpectations(t)
+
+	events := decodeUserEvents(t, publisher)
+	require.Len(t, events, 1)
+	assert.Equal(t, entity.EventUserDeactivated, events[0].EventType)
+}
+
+func TestProvisioningService_Provision_ChangesRole(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	tokenRepo := new(mocks.MockTokenRepository)
+	publisher := mocks.NewMockMessagePublisher()
+
+	user := newTestUser()
+	user.TenantID = tenant.DefaultID
+	manager := &entity.Role{ID: 2, Name: "manager"}
+
+	userRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+	roleRepo.On("GetByName", ctx, "manager").Return(manager, nil)
+	userRepo.On("Update", ctx, user).Return(nil)
+
+	service := NewProvisioningService(userRepo, roleRepo, tokenRepo, publisher)
+
+	// Act
+	response, err := service.Provision(ctx, uuid.New(), []entity.ProvisionUser{
+		{Email: user.Email, Name: user.Name, Role: "manager"},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, entity.ProvisionUpdated, response.Results[0].Status)
+	assert.Equal(t, manager.ID, user.RoleID)
+
+	events := decodeUserEvents(t, publisher)
+	require.Len(t, events, 1)
+	assert.Equal(t, entity.EventRoleChanged, events[0].EventType)
+	assert.Equal(t, 1, events[0].PreviousRoleID)
+}
+
+func TestProvisioningService_Provision_Unchanged(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	tokenRepo := new(mocks.MockTokenRepository)
+	publisher := mocks.NewMockMessagePublisher()
+
+	user := newTestUser()
+	user.TenantID = tenant.DefaultID
+
+	userRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+	roleRepo.On("GetByName", ctx, "user").Return(newTestRole(), nil)
+
+	service := NewProvisioningService(userRepo, roleRepo, tokenRepo, publisher)
+
+	// Act
+	response, err := service.Provision(ctx, uuid.New(), []entity.ProvisionUser{
+		{Email: user.Email, Name: user.Name, Role: "user", Active: boolPtr(true)},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, entity.ProvisionUnchanged, response.Results[0].Status)
+	userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	assert.Empty(t, publisher.Messages)
+}
+
+func TestProvisioningService_Provision_CannotDeactivateSelf(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	tokenRepo := new(mocks.MockTokenRepository)
+
+	admin := newTestUser()
+	admin.TenantID = tenant.DefaultID
+	userRepo.On("GetByEmail", ctx, admin.Email).Return(admin, nil)
+
+	service := NewProvisioningService(userRepo, roleRepo, tokenRepo, mocks.NewMockMessagePublisher())
+
+	// Act
+	response, err := service.Provision(ctx, admin.ID, []entity.ProvisionUser{
+		{Email: admin.Email, Active: boolPtr(false)},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "cannot deactivate yourself", response.Results[0].Error)
+	assert.True(t, admin.Active())
+}
+
+func TestProvisioningService_Provision_BatchLimits(t *testing.T) {
+	service := NewProvisioningService(nil, nil, nil, nil)
+
+	_, err := service.Provision(context.Background(), uuid.New(), nil)
+	assert.ErrorIs(t, err, ErrProvisioningBatchEmpty)
+
+	_, err = service.Provision(context.Background(), uuid.New(), make([]entity.ProvisionUser, MaxProvisionBatch+1))
+	assert.ErrorIs(t, err, ErrProvisioningBatchTooLarge)
+}
+
+// ==================== Tenant Roles Tests ====================
+
+func TestProvisioningService_AssignTenantRole(t *testing.T) {
+	// Arrange: администратор магазина shop-b делает менеджером пользователя магазина shop-a
+	ctx := tenant.WithID(context.Background(), "shop-b")
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	publisher := mocks.NewMockMessagePublisher()
+	actorID := uuid.New()
+
+	user := newTestUser()
+	user.TenantID = "shop-a"
+	manager := &entity.Role{ID: 2, Name: "manager"}
+
+	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	roleRepo.On("GetByName", ctx, "manager").Return(manager, nil)
+	userRepo.On("ListTenantRoles", ctx, user.ID).Return([]entity.TenantRole{
+		{UserID: user.ID, TenantID: "shop-b", RoleID: 1, RoleName: "user"},
+	}, nil)
+	userRepo.On("SetTenantRole", ctx, user.ID, "shop-b", manager.ID).Return(nil)
+	roleRepo.On("GetPermissionsByRoleID", ctx, manager.ID).Return(newTestPermissions(), nil)
+
+	service := NewProvisioningService(userRepo, roleRepo, new(mocks.MockTokenRepository), publisher)
+
+	// Act
+	role, err := service.AssignTenantRole(ctx, actorID, user.ID, "manager")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "shop-b", role.TenantID)
+	assert.Equal(t, "manager", role.RoleName)
+	assert.Equal(t, []string{"product.read", "order.create"}, role.Permissions)
+
+	events := decodeUserEvents(t, publisher)
+	require.Len(t, events, 1)
+	assert.Equal(t, entity.EventRoleChanged, events[0].EventType)
+	assert.Equal(t, "shop-b", events[0].TenantID)
+	assert.Equal(t, manager.ID, events[0].RoleID)
+	assert.Equal(t, 1, events[0].PreviousRoleID)
+	require.NotNil(t, events[0].ActorID)
+	assert.Equal(t, actorID, *events[0].ActorID)
+}
+
+func TestProvisioningService_AssignTenantRole_HomeTenant(t *testing.T) {
+	// Arrange
+	ctx := tenant.WithID(context.Background(), "shop-a")
+	userRepo := new(mocks.MockUserRepository)
+	user := newTestUser()
+	user.TenantID = "shop-a"
+	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+
+	service := NewProvisioningService(userRepo, new(mocks.MockRoleRepository), new(mocks.MockTokenRepository), mocks.NewMockMessagePublisher())
+
+	// Act
+	_, err := service.AssignTenantRole(ctx, uuid.New(), user.ID, "admin")
+
+	// Assert: роль в магазине регистрации меняется провижинингом
+	assert.ErrorIs(t, err, ErrHomeTenantRole)
+	userRepo.AssertNotCalled(t, "SetTenantRole", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProvisioningService_RevokeTenantRole(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), "shop-b")
+	user := newTestUser()
+	user.TenantID = "shop-a"
+
+	t.Run("revoked", func(t *testing.T) {
+		// Arrange
+		userRepo := new(mocks.MockUserRepository)
+		publisher := mocks.NewMockMessagePublisher()
+		userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+		userRepo.On("ListTenantRoles", ctx, user.ID).Return([]entity.TenantRole{
+			{UserID: user.ID, TenantID: "shop-b", RoleID: 2, RoleName: "manager"},
+		}, nil)
+		userRepo.On("DeleteTenantRole", ctx, user.ID, "shop-b").Return(nil)
+		service := NewProvisioningService(userRepo, new(mocks.MockRoleRepository), new(mocks.MockTokenRepository), publisher)
+
+		// Act
+		err := service.RevokeTenantRole(ctx, uuid.New(), user.ID)
+
+		// Assert
+		require.NoError(t, err)
+		events := decodeUserEvents(t, publisher)
+		require.Len(t, events, 1)
+		assert.Equal(t, 0, events[0].RoleID)
+		assert.Equal(t, 2, events[0].PreviousRoleID)
+	})
+
+	t.Run("no role", func(t *testing.T) {
+		// Arrange
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+		userRepo.On("ListTenantRoles", ctx, user.ID).Return(nil, nil)
+		service := NewProvisioningService(userRepo, new(mocks.MockRoleRepository), new(mocks.MockTokenRepository), mocks.NewMockMessagePublisher())
+
+		// Act
+		err := service.RevokeTenantRole(ctx, uuid.New(), user.ID)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrTenantRoleNotFound)
+	})
+}
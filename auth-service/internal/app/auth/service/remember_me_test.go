@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/auth-service/internal/app/auth/repository/mocks"
+	"augustberries/auth-service/internal/app/auth/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newRememberMeJWTManager() *util.JWTManager {
+	jwtManager := newTestJWTManager()
+	jwtManager.SetRememberMePolicy(util.RememberMePolicy{
+		TokenDuration: 30 * 24 * time.Hour,
+		MaxSessionAge: 90 * 24 * time.Hour,
+	})
+	return jwtManager
+}
+
+// ==================== Remember Me Tests ====================
+
+func TestAuthService_Login_RememberMeIssuesLongLivedToken(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	tokenRepo := new(mocks.MockTokenRepository)
+
+	user := newTestUser()
+	userRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+	roleRepo.On("GetByID", ctx, user.RoleID).Return(newTestRole(), nil)
+	roleRepo.On("GetPermissionsByRoleID", ctx, user.RoleID).Return(newTestPermissions(), nil)
+	userRepo.On("ListTenantRoles", ctx, mock.AnythingOfType("uuid.UUID")).Return(nil, nil)
+	tokenRepo.On("SaveRememberToken", ctx, mock.MatchedBy(func(rt *entity.RememberToken) bool {
+		lifetime := rt.ExpiresAt.Sub(rt.SessionStartedAt)
+		return rt.UserID == user.ID && lifetime >= 30*24*time.Hour && lifetime < 30*24*time.Hour+time.Second
+	})).Return(nil)
+
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, newRememberMeJWTManager(), mocks.NewMockMessagePublisher(), nil)
+
+	// Act
+	response, err := service.Login(ctx, &entity.LoginRequest{Email: user.Email, Password: "password123", RememberMe: true})
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, util.IsRememberToken(response.Tokens.RefreshToken))
+	assert.InDelta(t, (30 * 24 * time.Hour).Seconds(), response.Tokens.RefreshExpiresIn, 2)
+	tokenRepo.AssertNotCalled(t, "SaveRefreshToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Login_RememberMeDisabledFallsBackToRefreshToken(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	tokenRepo := new(mocks.MockTokenRepository)
+
+	user := newTestUser()
+	userRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+	roleRepo.On("GetByID", ctx, user.RoleID).Return(newTestRole(), nil)
+	roleRepo.On("GetPermissionsByRoleID", ctx, user.RoleID).Return(newTestPermissions(), nil)
+	userRepo.On("ListTenantRoles", ctx, mock.AnythingOfType("uuid.UUID")).Return(nil, nil)
+	tokenRepo.On("SaveRefreshToken", ctx, user.ID, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
+
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, newTestJWTManager(), mocks.NewMockMessagePublisher(), nil)
+
+	// Act
+	response, err := service.Login(ctx, &entity.LoginRequest{Email: user.Email, Password: "password123", RememberMe: true})
+
+	// Assert
+	require.NoError(t, err)
+	assert.False(t, util.IsRememberToken(response.Tokens.RefreshToken))
+}
+
+func TestAuthService_RefreshTokens_RememberMeSlidesWithinMaxAge(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	tokenRepo := new(mocks.MockTokenRepository)
+
+	user := newTestUser()
+	token := util.RememberTokenPrefix + "token"
+	started := time.Now().Add(-80 * 24 * time.Hour)
+	tokenRepo.On("ConsumeRememberToken", ctx, token).Return(&entity.RememberToken{
+		Token: token, UserID: user.ID, SessionStartedAt: started, ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	roleRepo.On("GetByID", ctx, user.RoleID).Return(newTestRole(), nil)
+	roleRepo.On("GetPermissionsByRoleID", ctx, user.RoleID).Return(newTestPermissions(), nil)
+	// Новый токен сохраняет начало сессии и не переживает 90 дней с входа
+	userRepo.On("ListTenantRoles", ctx, mock.AnythingOfType("uuid.UUID")).Return(nil, nil)
+	tokenRepo.On("SaveRememberToken", ctx, mock.MatchedBy(func(rt *entity.RememberToken) bool {
+		return rt.SessionStartedAt.Equal(started) && rt.ExpiresAt.Equal(started.Add(90*24*time.Hour))
+	})).Return(nil)
+
+	service := NewAuthService(userRepo, roleRepo, tokenRepo, newRememberMeJWTManager(), mocks.NewMockMessagePublisher(), nil)
+
+	// Act
+	pair, err := service.RefreshTokens(ctx, token)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, util.IsRememberToken(pair.RefreshToken))
+	assert.NotEqual(t, token, pair.RefreshToken)
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestAuthService_RefreshTokens_RememberMeSessionTooOld(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	tokenRepo := new(mocks.MockTokenRepository)
+
+	token := util.RememberTokenPrefix + "token"
+	tokenRepo.On("ConsumeRememberToken", ctx, token).Return(&entity.RememberToken{
+		Token: token, SessionStartedAt: time.Now().Add(-91 * 24 * time.Hour), ExpiresAt: time.Now().Add(time.Minute),
+	}, nil)
+
+	service := NewAuthService(new(mocks.MockUserRepository), new(mocks.MockRoleRepository), tokenRepo, newRememberMeJWTManager(), mocks.NewMockMessagePublisher(), nil)
+
+	// Act
+	pair, err := service.RefreshTokens(ctx, token)
+
+	// Assert
+	assert.Nil(t, pair)
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	tokenRepo.AssertNotCalled(t, "SaveRememberToken", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/auth-service/internal/app/auth/repository"
+)
+
+// RoleSeeder создает базовые роли и разрешения при запуске сервиса
+// Заполнение идемпотентно: отсутствующие роли и разрешения создаются, существующие роли не меняются,
+// поэтому разрешения, измененные администратором, сохраняются. Несколько реплик могут запускать его одновременно
+type RoleSeeder struct {
+	roleRepo repository.RoleRepository
+}
+
+// NewRoleSeeder создает заполнение ролей
+func NewRoleSeeder(roleRepo repository.RoleRepository) *RoleSeeder {
+	return &RoleSeeder{roleRepo: roleRepo}
+}
+
+// Seed создает разрешения knownPermissions и роли baseRoles; admin получает все разрешения
+func (s *RoleSeeder) Seed(ctx context.Context) error {
+	if err := s.roleRepo.EnsurePermissions(ctx, knownPermissions); err != nil {
+		return fmt.Errorf("failed to seed permissions: %w", err)
+	}
+
+	for _, base := range baseRoles {
+		role := &entity.Role{Name: base.Name, Description: base.Description}
+		created, err := s.roleRepo.CreateWithPermissions(ctx, role, permissionsByCode(base.Permissions))
+		if err != nil {
+			return fmt.Errorf("failed to seed role %s: %w", base.Name, err)
+		}
+		if created {
+			log.Printf("Seeded role %s with %d permissions", base.Name, len(base.Permissions))
+		}
+	}
+
+	if err := s.roleRepo.GrantAllPermissions(ctx, AdminRoleName); err != nil {
+		return fmt.Errorf("failed to grant permissions to %s: %w", AdminRoleName, err)
+	}
+
+	return nil
+}
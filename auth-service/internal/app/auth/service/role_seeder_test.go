@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/auth-service/internal/app/auth/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// ==================== RoleSeeder Tests ====================
+
+func TestRoleSeeder_Seed(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	roleRepo := new(mocks.MockRoleRepository)
+
+	var seeded []string
+	roleRepo.On("EnsurePermissions", ctx, knownPermissions).Return(nil)
+	roleRepo.On("CreateWithPermissions", ctx, mock.AnythingOfType("*entity.Role"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			seeded = append(seeded, args.Get(1).(*entity.Role).Name)
+		}).
+		// Повторный запуск: роль user уже есть и не меняется
+		Return(false, nil).Once()
+	roleRepo.On("CreateWithPermissions", ctx, mock.AnythingOfType("*entity.Role"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			seeded = append(seeded, args.Get(1).(*entity.Role).Name)
+		}).
+		Return(true, nil)
+	roleRepo.On("GrantAllPermissions", ctx, AdminRoleName).Return(nil)
+
+	seeder := NewRoleSeeder(roleRepo)
+
+	// Act
+	err := seeder.Seed(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user", "manager", "admin", "support"}, seeded)
+	roleRepo.AssertExpectations(t)
+}
+
+func TestRoleSeeder_Seed_PermissionsError(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	roleRepo := new(mocks.MockRoleRepository)
+	roleRepo.On("EnsurePermissions", ctx, mock.Anything).Return(errors.New("db down"))
+
+	seeder := NewRoleSeeder(roleRepo)
+
+	// Act
+	err := seeder.Seed(ctx)
+
+	// Assert
+	assert.Error(t, err)
+	roleRepo.AssertNotCalled(t, "CreateWithPermissions", mock.Anything, mock.Anything, mock.Anything)
+	roleRepo.AssertNotCalled(t, "GrantAllPermissions", mock.Anything, mock.Anything)
+}
+
+func TestBaseRoles_PermissionsAreKnown(t *testing.T) {
+	known := make(map[string]bool, len(knownPermissions))
+	for _, p := range knownPermissions {
+		known[p.Code] = true
+	}
+
+	roles := append([]entity.RoleTemplate{}, baseRoles...)
+	for _, template := range roleTemplates {
+		roles = append(roles, template)
+	}
+	for _, role := range roles {
+		for _, code := range role.Permissions {
+			assert.True(t, known[code], "role %s: unknown permission %s", role.Name, code)
+		}
+	}
+}
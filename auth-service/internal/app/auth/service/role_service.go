@@ -85,11 +85,11 @@ func (s *RoleService) Update(ctx context.Context, id int, req *entity.UpdateRole
 	}
 
 	// Обновляем поля
-	if req.Name != "" {
-		role.Name = req.Name
+	if req.Name.Set {
+		role.Name = req.Name.Value
 	}
-	if req.Description != "" {
-		role.Description = req.Description
+	if req.Description.Set {
+		role.Description = req.Description.OrZero()
 	}
 
 	if err := s.roleRepo.Update(ctx, role); err != nil {
@@ -7,6 +7,7 @@ import (
 
 	"augustberries/auth-service/internal/app/auth/entity"
 	"augustberries/auth-service/internal/app/auth/repository/mocks"
+	"augustberries/pkg/patch"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
@@ -230,8 +231,8 @@ func TestRoleService_Update_Success_AllFields(t *testing.T) {
 	service := NewRoleService(roleRepo)
 
 	req := &entity.UpdateRoleRequest{
-		Name:        "updated_user",
-		Description: "Updated regular user",
+		Name:        patch.Of("updated_user"),
+		Description: patch.Value("Updated regular user"),
 	}
 
 	// Act
@@ -258,7 +259,7 @@ func TestRoleService_Update_Success_PartialFields(t *testing.T) {
 
 	// Обновляем только описание
 	req := &entity.UpdateRoleRequest{
-		Description: "New description",
+		Description: patch.Value("New description"),
 	}
 
 	// Act
@@ -270,6 +271,26 @@ func TestRoleService_Update_Success_PartialFields(t *testing.T) {
 	assert.Equal(t, "New description", result.Description)
 }
 
+func TestRoleService_Update_NullClearsDescription(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	roleRepo := new(mocks.MockRoleRepository)
+
+	existingRole := &entity.Role{ID: 1, Name: "user", Description: "Regular user"}
+	roleRepo.On("GetByID", ctx, 1).Return(existingRole, nil)
+	roleRepo.On("Update", ctx, existingRole).Return(nil)
+
+	service := NewRoleService(roleRepo)
+
+	// Act
+	result, err := service.Update(ctx, 1, &entity.UpdateRoleRequest{Description: patch.Null[string]()})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "user", result.Name)
+	assert.Empty(t, result.Description)
+}
+
 func TestRoleService_Update_NotFound(t *testing.T) {
 	// Arrange
 	ctx := context.Background()
@@ -279,7 +300,7 @@ func TestRoleService_Update_NotFound(t *testing.T) {
 
 	service := NewRoleService(roleRepo)
 
-	req := &entity.UpdateRoleRequest{Name: "new_name"}
+	req := &entity.UpdateRoleRequest{Name: patch.Of("new_name")}
 
 	// Act
 	result, err := service.Update(ctx, 999, req)
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+)
+
+// AdminRoleName - роль администратора; при заполнении она получает все разрешения
+const AdminRoleName = "admin"
+
+// knownPermissions - разрешения, которые создаются при первом запуске (те же, что в миграциях 002 и 010)
+var knownPermissions = []entity.Permission{
+	{Code: "product.create", Description: "Создание продуктов"},
+	{Code: "product.read", Description: "Просмотр продуктов"},
+	{Code: "product.update", Description: "Обновление продуктов"},
+	{Code: "product.delete", Description: "Удаление продуктов"},
+	{Code: "user.create", Description: "Создание пользователей"},
+	{Code: "user.read", Description: "Просмотр пользователей"},
+	{Code: "user.update", Description: "Обновление пользователей"},
+	{Code: "user.delete", Description: "Удаление пользователей"},
+	{Code: "order.create", Description: "Создание заказов"},
+	{Code: "order.read", Description: "Просмотр заказов"},
+	{Code: "order.update", Description: "Обновление заказов"},
+	{Code: "order.delete", Description: "Удаление заказов"},
+	{Code: entity.PermissionImpersonate, Description: "Вход от имени пользователя"},
+}
+
+// baseRoles - роли, которые создаются при первом запуске; admin дополнительно получает все разрешения
+var baseRoles = []entity.RoleTemplate{
+	{
+		Name:        "user",
+		Description: "Обычный пользователь",
+		Permissions: []string{"product.read", "order.create", "order.read"},
+	},
+	{
+		Name:        "manager",
+		Description: "Менеджер с расширенными правами",
+		Permissions: []string{"product.create", "product.read", "product.update", "user.read", "order.create", "order.read", "order.update"},
+	},
+	{
+		Name:        AdminRoleName,
+		Description: "Администратор с полными правами",
+	},
+	supportTemplate,
+}
+
+var supportTemplate = entity.RoleTemplate{
+	Name:        "support",
+	Description: "Сотрудник поддержки",
+	Permissions: []string{"product.read", "user.read", "order.read", entity.PermissionImpersonate},
+}
+
+// roleTemplates - шаблоны, из которых администратор создает роли через POST /admin/role-templates/:template/clone
+var roleTemplates = map[string]entity.RoleTemplate{
+	"support": supportTemplate,
+	"warehouse": {
+		Name:        "warehouse",
+		Description: "Сотрудник склада",
+		Permissions: []string{"product.read", "product.update", "order.read", "order.update"},
+	},
+}
+
+// permissionsByCode возвращает разрешения с описаниями из knownPermissions
+func permissionsByCode(codes []string) []entity.Permission {
+	permissions := make([]entity.Permission, 0, len(codes))
+	for _, code := range codes {
+		permission := entity.Permission{Code: code}
+		for _, known := range knownPermissions {
+			if known.Code == code {
+				permission = known
+				break
+			}
+		}
+		permissions = append(permissions, permission)
+	}
+	return permissions
+}
+
+// Templates возвращает шаблоны ролей, упорядоченные по имени
+func (s *RoleService) Templates() []entity.RoleTemplate {
+	templates := make([]entity.RoleTemplate, 0, len(roleTemplates))
+	for _, template := range roleTemplates {
+		templates = append(templates, template)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates
+}
+
+// CloneTemplate создает роль с разрешениями шаблона; недостающие разрешения создаются
+// Роли общие для всех магазинов, имя роли должно быть свободно
+func (s *RoleService) CloneTemplate(ctx context.Context, templateName string, req *entity.CloneRoleTemplateRequest) (*entity.RoleWithPermissions, error) {
+	template, ok := roleTemplates[templateName]
+	if !ok {
+		return nil, ErrRoleTemplateNotFound
+	}
+
+	role := &entity.Role{Name: req.Name, Description: req.Description}
+	if role.Description == "" {
+		role.Description = template.Description
+	}
+
+	created, err := s.roleRepo.CreateWithPermissions(ctx, role, permissionsByCode(template.Permissions))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create role from template: %w", err)
+	}
+	if !created {
+		return nil, ErrRoleExists
+	}
+
+	permissions, err := s.roleRepo.GetPermissionsByRoleID(ctx, role.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get permissions: %w", err)
+	}
+
+	return &entity.RoleWithPermissions{Role: *role, Permissions: permissions}, nil
+}
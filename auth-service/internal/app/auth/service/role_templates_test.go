@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/auth-service/internal/app/auth/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// ==================== Role Templates Tests ====================
+
+func TestRoleService_Templates(t *testing.T) {
+	// Arrange
+	service := NewRoleService(new(mocks.MockRoleRepository))
+
+	// Act
+	templates := service.Templates()
+
+	// Assert
+	require.Len(t, templates, 2)
+	assert.Equal(t, "support", templates[0].Name)
+	assert.Equal(t, "warehouse", templates[1].Name)
+	assert.Contains(t, templates[0].Permissions, entity.PermissionImpersonate)
+}
+
+func TestRoleService_CloneTemplate_Success(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	roleRepo := new(mocks.MockRoleRepository)
+
+	var granted []entity.Permission
+	roleRepo.On("CreateWithPermissions", ctx, mock.AnythingOfType("*entity.Role"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			args.Get(1).(*entity.Role).ID = 7
+			granted = args.Get(2).([]entity.Permission)
+		}).
+		Return(true, nil)
+	roleRepo.On("GetPermissionsByRoleID", ctx, 7).Return([]entity.Permission{{ID: 1, Code: "order.read"}}, nil)
+
+	service := NewRoleService(roleRepo)
+
+	// Act
+	role, err := service.CloneTemplate(ctx, "warehouse", &entity.CloneRoleTemplateRequest{Name: "warehouse-north"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 7, role.ID)
+	assert.Equal(t, "warehouse-north", role.Name)
+	assert.Equal(t, "Сотрудник склада", role.Description) // Описание шаблона, если в запросе его нет
+	assert.Len(t, role.Permissions, 1)
+
+	codes := make([]string, len(granted))
+	for i, p := range granted {
+		codes[i] = p.Code
+		assert.NotEmpty(t, p.Description, "permission %s", p.Code)
+	}
+	assert.Equal(t, []string{"product.read", "product.update", "order.read", "order.update"}, codes)
+}
+
+func TestRoleService_CloneTemplate_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		created  bool
+		repoErr  error
+		wantErr  error
+	}{
+		{name: "unknown template", template: "accountant", wantErr: ErrRoleTemplateNotFound},
+		{name: "role exists", template: "support", created: false, wantErr: ErrRoleExists},
+		{name: "repository error", template: "support", repoErr: errors.New("db down")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			roleRepo := new(mocks.MockRoleRepository)
+			roleRepo.On("CreateWithPermissions", ctx, mock.Anything, mock.Anything).Return(tt.created, tt.repoErr)
+
+			service := NewRoleService(roleRepo)
+
+			// Act
+			role, err := service.CloneTemplate(ctx, tt.template, &entity.CloneRoleTemplateRequest{Name: "support-2"})
+
+			// Assert
+			assert.Nil(t, role)
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			roleRepo.AssertNotCalled(t, "GetPermissionsByRoleID", mock.Anything, mock.Anything)
+		})
+	}
+}
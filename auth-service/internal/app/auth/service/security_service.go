@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/auth-service/internal/app/auth/repository"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// SecurityService предоставляет администраторам сигналы безопасности:
+// неудачные входы, заблокированные аккаунты и статистику токенов
+type SecurityService struct {
+	userRepo  repository.UserRepository
+	tokenRepo repository.TokenRepository
+	attempts  repository.LoginAttemptRepository
+}
+
+// NewSecurityService создает сервис панели безопасности
+func NewSecurityService(
+	userRepo repository.UserRepository,
+	tokenRepo repository.TokenRepository,
+	attempts repository.LoginAttemptRepository,
+) *SecurityService {
+	return &SecurityService{
+		userRepo:  userRepo,
+		tokenRepo: tokenRepo,
+		attempts:  attempts,
+	}
+}
+
+// FailedLogins возвращает аккаунты магазина с неудачными входами за период, свежие первыми
+// Адреса без аккаунта и аккаунты других магазинов не показываются: их нельзя отнести к магазину
+func (s *SecurityService) FailedLogins(ctx context.Context, period time.Duration, limit int) ([]entity.FailedLoginStat, error) {
+	stats, err := s.attempts.ListFailures(ctx, time.Now().Add(-period), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantID := tenant.FromContext(ctx)
+	filtered := make([]entity.FailedLoginStat, 0, len(stats))
+	for _, stat := range stats {
+		user, err := s.userRepo.GetByEmail(ctx, stat.Email)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		if user.TenantID != tenantID {
+			continue
+		}
+		stat.UserID = &user.ID
+
+		if _, err := s.attempts.GetLock(ctx, user.ID); err == nil {
+			stat.Locked = true
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("failed to check account lock: %w", err)
+		}
+		filtered = append(filtered, stat)
+	}
+
+	return filtered, nil
+}
+
+// LockedAccounts возвращает действующие блокировки аккаунтов магазина, новые первыми
+func (s *SecurityService) LockedAccounts(ctx context.Context) ([]entity.AccountLock, error) {
+	locks, err := s.attempts.ListLocks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]entity.AccountLock, 0, len(locks))
+	for _, lock := range locks {
+		if _, err := s.tenantUser(ctx, lock.UserID); err != nil {
+			if errors.Is(err, ErrUserNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		filtered = append(filtered, lock)
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].LockedAt.After(filtered[j].LockedAt)
+	})
+	return filtered, nil
+}
+
+// LockAccount блокирует вход в аккаунт на duration и завершает его сессии
+func (s *SecurityService) LockAccount(ctx context.Context, adminID, userID uuid.UUID, duration time.Duration) (*entity.AccountLock, error) {
+	user, err := s.tenantUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	lock := &entity.AccountLock{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Reason:    entity.LockReasonAdmin,
+		LockedBy:  &adminID,
+		LockedAt:  now,
+		ExpiresAt: now.Add(duration),
+	}
+	if err := s.attempts.Lock(ctx, lock); err != nil {
+		return nil, err
+	}
+
+	// Без refresh токенов сессии завершатся с истечением access токенов
+	if err := s.tokenRepo.DeleteUserRefreshTokens(ctx, user.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete refresh tokens: %w", err)
+	}
+
+	return lock, nil
+}
+
+// UnlockAccount снимает блокировку и сбрасывает счетчик неудачных входов
+// Блокировки аккаунтов других магазинов для администратора не существуют
+func (s *SecurityService) UnlockAccount(ctx context.Context, userID uuid.UUID) error {
+	lock, err := s.attempts.GetLock(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrAccountNotLocked
+		}
+		return fmt.Errorf("failed to get account lock: %w", err)
+	}
+	if _, err := s.tenantUser(ctx, userID); err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return ErrAccountNotLocked
+		}
+		return err
+	}
+
+	if err := s.attempts.Unlock(ctx, userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrAccountNotLocked
+		}
+		return err
+	}
+
+	return s.attempts.ClearFailures(ctx, lock.Email)
+}
+
+// tenantUser возвращает пользователя магазина из контекста
+// Пользователи других магазинов для администратора не существуют
+func (s *SecurityService) tenantUser(ctx context.Context, userID uuid.UUID) (*entity.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.TenantID != tenant.FromContext(ctx) {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// TokenStats возвращает число токенов и использование refresh токенов за days дней
+func (s *SecurityService) TokenStats(ctx context.Context, days int) (*entity.TokenStats, error) {
+	stats, err := s.tokenRepo.Stats(ctx, days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token stats: %w", err)
+	}
+	return stats, nil
+}
+
+// DashboardSummary возвращает регистрации и действующие сессии магазина для панели администратора
+func (s *SecurityService) DashboardSummary(ctx context.Context) (*entity.DashboardSummary, error) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	summary := &entity.DashboardSummary{GeneratedAt: now}
+
+	var err error
+	if summary.Signups.Today, err = s.userRepo.CountCreatedSince(ctx, today); err != nil {
+		return nil, err
+	}
+	if summary.Signups.Last7Days, err = s.userRepo.CountCreatedSince(ctx, today.AddDate(0, 0, -6)); err != nil {
+		return nil, err
+	}
+
+	// Токены не привязаны к магазину: сессии считаются по пользователям магазина
+	userIDs, err := s.userRepo.ListTenantUserIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.tokenRepo.SessionStats(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session stats: %w", err)
+	}
+	summary.ActiveSessions = *sessions
+
+	return summary, nil
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/auth-service/internal/app/auth/repository/mocks"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// ==================== Security Dashboard Tests ====================
+
+func TestSecurityService_FailedLogins_EnrichesKnownAccounts(t *testing.T) {
+	// Arrange
+	ctx := tenant.WithID(context.Background(), "shop-1")
+	userRepo := new(mocks.MockUserRepository)
+	attempts := new(mocks.MockLoginAttemptRepository)
+	service := NewSecurityService(userRepo, new(mocks.MockTokenRepository), attempts)
+
+	user := newTestUser()
+	user.TenantID = "shop-1"
+	foreign := newTestUser()
+	foreign.Email = "other@example.com"
+	foreign.TenantID = "shop-2"
+	attempts.On("ListFailures", ctx, mock.AnythingOfType("time.Time"), 100).Return([]entity.FailedLoginStat{
+		{Email: user.Email, Failures: 4},
+		{Email: "ghost@example.com", Failures: 12},
+		{Email: foreign.Email, Failures: 7},
+	}, nil)
+	userRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+	userRepo.On("GetByEmail", ctx, "ghost@example.com").Return(nil, pgx.ErrNoRows)
+	userRepo.On("GetByEmail", ctx, foreign.Email).Return(foreign, nil)
+	attempts.On("GetLock", ctx, user.ID).Return(&entity.AccountLock{UserID: user.ID}, nil)
+
+	// Act
+	stats, err := service.FailedLogins(ctx, time.Hour, 100)
+
+	// Assert
+	require.NoError(t, err)
+	// Адреса без аккаунта и аккаунты других магазинов администратору не показываются
+	require.Len(t, stats, 1)
+	assert.Equal(t, user.ID, *stats[0].UserID)
+	assert.True(t, stats[0].Locked)
+	attempts.AssertNotCalled(t, "GetLock", ctx, foreign.ID)
+}
+
+func TestSecurityService_LockedAccounts_OnlyTenantUsers(t *testing.T) {
+	// Arrange
+	ctx := tenant.WithID(context.Background(), "shop-1")
+	userRepo := new(mocks.MockUserRepository)
+	attempts := new(mocks.MockLoginAttemptRepository)
+	service := NewSecurityService(userRepo, new(mocks.MockTokenRepository), attempts)
+
+	user := newTestUser()
+	user.TenantID = "shop-1"
+	foreign := newTestUser()
+	foreign.TenantID = "shop-2"
+	attempts.On("ListLocks", ctx).Return([]entity.AccountLock{
+		{UserID: foreign.ID, LockedAt: time.Now()},
+		{UserID: user.ID, LockedAt: time.Now().Add(-time.Minute)},
+	}, nil)
+	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	userRepo.On("GetByID", ctx, foreign.ID).Return(foreign, nil)
+
+	// Act
+	locks, err := service.LockedAccounts(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, locks, 1)
+	assert.Equal(t, user.ID, locks[0].UserID)
+}
+
+func TestSecurityService_LockAccount_RevokesRefreshTokens(t *testing.T) {
+	// Arrange
+	ctx := tenant.WithID(context.Background(), "shop-1")
+	userRepo := new(mocks.MockUserRepository)
+	tokenRepo := new(mocks.MockTokenRepository)
+	attempts := new(mocks.MockLoginAttemptRepository)
+	service := NewSecurityService(userRepo, tokenRepo, attempts)
+
+	user := newTestUser()
+	user.TenantID = "shop-1"
+	adminID := uuid.New()
+	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	attempts.On("Lock", ctx, mock.MatchedBy(func(l *entity.AccountLock) bool {
+		return l.Reason == entity.LockReasonAdmin && *l.LockedBy == adminID
+	})).Return(nil)
+	tokenRepo.On("DeleteUserRefreshTokens", ctx, user.ID).Return(nil)
+
+	// Act
+	lock, err := service.LockAccount(ctx, adminID, user.ID, time.Hour)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, user.Email, lock.Email)
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestSecurityService_CrossTenantDenied(t *testing.T) {
+	// Arrange
+	ctx := tenant.WithID(context.Background(), "shop-1")
+	userRepo := new(mocks.MockUserRepository)
+	tokenRepo := new(mocks.MockTokenRepository)
+	attempts := new(mocks.MockLoginAttemptRepository)
+	service := NewSecurityService(userRepo, tokenRepo, attempts)
+
+	foreign := newTestUser()
+	foreign.TenantID = "shop-2"
+	userRepo.On("GetByID", ctx, foreign.ID).Return(foreign, nil)
+	attempts.On("GetLock", ctx, foreign.ID).Return(&entity.AccountLock{UserID: foreign.ID, Email: foreign.Email}, nil)
+
+	// Act
+	_, lockErr := service.LockAccount(ctx, uuid.New(), foreign.ID, time.Hour)
+	unlockErr := service.UnlockAccount(ctx, foreign.ID)
+
+	// Assert
+	assert.ErrorIs(t, lockErr, ErrUserNotFound)
+	assert.ErrorIs(t, unlockErr, ErrAccountNotLocked)
+	attempts.AssertNotCalled(t, "Lock", mock.Anything, mock.Anything)
+	attempts.AssertNotCalled(t, "Unlock", mock.Anything, mock.Anything)
+	attempts.AssertNotCalled(t, "ClearFailures", mock.Anything, mock.Anything)
+	tokenRepo.AssertNotCalled(t, "DeleteUserRefreshTokens", mock.Anything, mock.Anything)
+}
+
+func TestSecurityService_UnlockAccount_NotLocked(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	attempts := new(mocks.MockLoginAttemptRepository)
+	service := NewSecurityService(new(mocks.MockUserRepository), new(mocks.MockTokenRepository), attempts)
+
+	userID := uuid.New()
+	attempts.On("GetLock", ctx, userID).Return(nil, pgx.ErrNoRows)
+
+	// Act
+	err := service.UnlockAccount(ctx, userID)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrAccountNotLocked)
+}
+
+func TestSecurityService_UnlockAccount_ClearsFailures(t *testing.T) {
+	// Arrange
+	ctx := tenant.WithID(context.Background(), "shop-1")
+	userRepo := new(mocks.MockUserRepository)
+	attempts := new(mocks.MockLoginAttemptRepository)
+	service := NewSecurityService(userRepo, new(mocks.MockTokenRepository), attempts)
+
+	user := newTestUser()
+	user.TenantID = "shop-1"
+	userID := user.ID
+	userRepo.On("GetByID", ctx, userID).Return(user, nil)
+	attempts.On("GetLock", ctx, userID).Return(&entity.AccountLock{UserID: userID, Email: "test@example.com"}, nil)
+	attempts.On("Unlock", ctx, userID).Return(nil)
+	attempts.On("ClearFailures", ctx, "test@example.com").Return(nil)
+
+	// Act
+	err := service.UnlockAccount(ctx, userID)
+
+	// Assert
+	require.NoError(t, err)
+	attempts.AssertExpectations(t)
+}
+
+// ==================== Dashboard Summary Tests ====================
+
+func TestSecurityService_DashboardSummary(t *testing.T) {
+	// Arrange
+	ctx := tenant.WithID(context.Background(), "shop-1")
+	userRepo := new(mocks.MockUserRepository)
+	tokenRepo := new(mocks.MockTokenRepository)
+	service := NewSecurityService(userRepo, tokenRepo, new(mocks.MockLoginAttemptRepository))
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	userIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	userRepo.On("CountCreatedSince", ctx, today).Return(int64(3), nil)
+	userRepo.On("CountCreatedSince", ctx, today.AddDate(0, 0, -6)).Return(int64(15), nil)
+	userRepo.On("ListTenantUserIDs", ctx).Return(userIDs, nil)
+	// Сессии считаются только по пользователям магазина, а не по всем токенам платформы
+	tokenRepo.On("SessionStats", ctx, userIDs).Return(&entity.SessionStats{Sessions: 45, Users: 32, RememberMe: 5}, nil)
+
+	// Act
+	summary, err := service.DashboardSummary(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, entity.SignupStats{Today: 3, Last7Days: 15}, summary.Signups)
+	assert.Equal(t, entity.SessionStats{Sessions: 45, Users: 32, RememberMe: 5}, summary.ActiveSessions)
+	tokenRepo.AssertNotCalled(t, "Stats", mock.Anything, mock.Anything)
+}
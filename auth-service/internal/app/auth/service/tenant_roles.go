@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// AssignTenantRole выдает пользователю роль в магазине администратора (активный магазин запроса)
+// Роль в магазине регистрации меняется провижинингом. Новая роль попадает в JWT при следующем входе
+// или обновлении токена; изменение публикуется как ROLE_CHANGED с tenant_id магазина
+func (s *ProvisioningService) AssignTenantRole(ctx context.Context, actorID, userID uuid.UUID, roleName string) (*entity.TenantRole, error) {
+	tenantID := tenant.FromContext(ctx)
+
+	user, err := s.tenantRoleUser(ctx, userID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.roleRepo.GetByName(ctx, roleName)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+
+	previous, err := s.currentTenantRole(ctx, userID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	assigned := &entity.TenantRole{UserID: userID, TenantID: tenantID, RoleID: role.ID, RoleName: role.Name}
+	if previous != nil && previous.RoleID == role.ID {
+		assigned.Permissions = previous.Permissions
+		return assigned, nil
+	}
+
+	if err := s.userRepo.SetTenantRole(ctx, userID, tenantID, role.ID); err != nil {
+		return nil, fmt.Errorf("failed to set tenant role: %w", err)
+	}
+
+	permissions, err := s.roleRepo.GetPermissionsByRoleID(ctx, role.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get permissions: %w", err)
+	}
+	assigned.Permissions = make([]string, len(permissions))
+	for i, p := range permissions {
+		assigned.Permissions[i] = p.Code
+	}
+
+	previousRoleID := 0
+	if previous != nil {
+		previousRoleID = previous.RoleID
+	}
+	s.publishTenantRole(ctx, actorID, user, tenantID, role.ID, previousRoleID)
+
+	return assigned, nil
+}
+
+// RevokeTenantRole отзывает роль пользователя в магазине администратора
+func (s *ProvisioningService) RevokeTenantRole(ctx context.Context, actorID, userID uuid.UUID) error {
+	tenantID := tenant.FromContext(ctx)
+
+	user, err := s.tenantRoleUser(ctx, userID, tenantID)
+	if err != nil {
+		return err
+	}
+
+	previous, err := s.currentTenantRole(ctx, userID, tenantID)
+	if err != nil {
+		return err
+	}
+	if previous == nil {
+		return ErrTenantRoleNotFound
+	}
+
+	if err := s.userRepo.DeleteTenantRole(ctx, userID, tenantID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTenantRoleNotFound
+		}
+		return fmt.Errorf("failed to delete tenant role: %w", err)
+	}
+
+	s.publishTenantRole(ctx, actorID, user, tenantID, 0, previous.RoleID)
+	return nil
+}
+
+// tenantRoleUser возвращает пользователя, которому можно выдать роль в магазине tenantID
+func (s *ProvisioningService) tenantRoleUser(ctx context.Context, userID uuid.UUID, tenantID string) (*entity.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.TenantID == tenantID {
+		return nil, ErrHomeTenantRole
+	}
+	return user, nil
+}
+
+// currentTenantRole возвращает роль пользователя в магазине, nil - роли нет
+func (s *ProvisioningService) currentTenantRole(ctx context.Context, userID uuid.UUID, tenantID string) (*entity.TenantRole, error) {
+	roles, err := s.userRepo.ListTenantRoles(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant roles: %w", err)
+	}
+	for i := range roles {
+		if roles[i].TenantID == tenantID {
+			return &roles[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// publishTenantRole отправляет ROLE_CHANGED для роли в магазине; role_id 0 - роль отозвана
+func (s *ProvisioningService) publishTenantRole(ctx context.Context, actorID uuid.UUID, user *entity.User, tenantID string, roleID, previousRoleID int) {
+	event := newUserEvent(entity.EventRoleChanged, user)
+	event.TenantID = tenantID
+	event.RoleID = roleID
+	event.PreviousRoleID = previousRoleID
+	event.ActorID = &actorID
+	if err := publishUserEvent(ctx, s.events, event); err != nil {
+		fmt.Printf("failed to publish %s event: %v\n", entity.EventRoleChanged, err)
+	}
+}
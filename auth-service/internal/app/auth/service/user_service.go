@@ -2,6 +2,7 @@ package service
 
 import (
 	"augustberries/auth-service/internal/app/auth/entity"
+	"augustberries/auth-service/internal/app/auth/infrastructure"
 	"augustberries/auth-service/internal/app/auth/repository"
 	"augustberries/auth-service/internal/app/auth/util"
 	"context"
@@ -16,16 +17,21 @@ import (
 type UserService struct {
 	userRepo repository.UserRepository
 	roleRepo repository.RoleRepository
+	events   infrastructure.MessagePublisher // Kafka producer топика user_events
+
+	passwordPolicy *PasswordPolicy // Проверка нового пароля, nil - отключена
 }
 
 // NewUserService создает новый сервис пользователей
 func NewUserService(
 	userRepo repository.UserRepository,
 	roleRepo repository.RoleRepository,
+	events infrastructure.MessagePublisher,
 ) *UserService {
 	return &UserService{
 		userRepo: userRepo,
 		roleRepo: roleRepo,
+		events:   events,
 	}
 }
 
@@ -101,22 +107,23 @@ func (s *UserService) Update(ctx context.Context, id uuid.UUID, req *entity.Upda
 	}
 
 	// Обновляем поля
-	if req.Name != "" {
-		user.Name = req.Name
+	previousRoleID := user.RoleID
+	if req.Name.Set {
+		user.Name = req.Name.Value
 	}
-	if req.Email != "" {
-		user.Email = req.Email
+	if req.Email.Set {
+		user.Email = req.Email.Value
 	}
-	if req.RoleID != 0 {
+	if req.RoleID.Set {
 		// Проверяем существование роли
-		_, err := s.roleRepo.GetByID(ctx, req.RoleID)
+		_, err := s.roleRepo.GetByID(ctx, req.RoleID.Value)
 		if err != nil {
 			if errors.Is(err, pgx.ErrNoRows) {
 				return nil, ErrRoleNotFound
 			}
 			return nil, fmt.Errorf("failed to verify role: %w", err)
 		}
-		user.RoleID = req.RoleID
+		user.RoleID = req.RoleID.Value
 	}
 
 	// Сохраняем изменения
@@ -124,6 +131,14 @@ func (s *UserService) Update(ctx context.Context, id uuid.UUID, req *entity.Upda
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
 
+	if user.RoleID != previousRoleID {
+		event := newUserEvent(entity.EventRoleChanged, user)
+		event.PreviousRoleID = previousRoleID
+		if err := publishUserEvent(ctx, s.events, event); err != nil {
+			fmt.Printf("failed to publish role changed event: %v\n", err)
+		}
+	}
+
 	return user, nil
 }
 
@@ -143,6 +158,10 @@ func (s *UserService) UpdatePassword(ctx context.Context, id uuid.UUID, oldPassw
 		return ErrInvalidCredentials
 	}
 
+	if err := s.passwordPolicy.Validate(ctx, newPassword, user.Email, user.Name); err != nil {
+		return err
+	}
+
 	// Хэшируем новый пароль
 	newPasswordHash, err := util.HashPassword(newPassword)
 	if err != nil {
@@ -155,6 +174,10 @@ func (s *UserService) UpdatePassword(ctx context.Context, id uuid.UUID, oldPassw
 		return fmt.Errorf("failed to update user password: %w", err)
 	}
 
+	if err := publishUserEvent(ctx, s.events, newUserEvent(entity.EventPasswordChanged, user)); err != nil {
+		fmt.Printf("failed to publish password changed event: %v\n", err)
+	}
+
 	return nil
 }
 
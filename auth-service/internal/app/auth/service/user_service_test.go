@@ -8,6 +8,7 @@ import (
 	"augustberries/auth-service/internal/app/auth/entity"
 	"augustberries/auth-service/internal/app/auth/repository/mocks"
 	"augustberries/auth-service/internal/app/auth/util"
+	"augustberries/pkg/patch"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -31,7 +32,7 @@ func TestUserService_GetByID_Success(t *testing.T) {
 	roleRepo.On("GetByID", ctx, user.RoleID).Return(role, nil)
 	roleRepo.On("GetPermissionsByRoleID", ctx, user.RoleID).Return(permissions, nil)
 
-	service := NewUserService(userRepo, roleRepo)
+	service := NewUserService(userRepo, roleRepo, mocks.NewMockMessagePublisher())
 
 	// Act
 	result, err := service.GetByID(ctx, user.ID)
@@ -57,7 +58,7 @@ func TestUserService_GetByID_UserNotFound(t *testing.T) {
 	userID := uuid.New()
 	userRepo.On("GetByID", ctx, userID).Return(nil, pgx.ErrNoRows)
 
-	service := NewUserService(userRepo, roleRepo)
+	service := NewUserService(userRepo, roleRepo, mocks.NewMockMessagePublisher())
 
 	// Act
 	result, err := service.GetByID(ctx, userID)
@@ -78,7 +79,7 @@ func TestUserService_GetByID_RoleNotFound(t *testing.T) {
 	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
 	roleRepo.On("GetByID", ctx, user.RoleID).Return(nil, pgx.ErrNoRows)
 
-	service := NewUserService(userRepo, roleRepo)
+	service := NewUserService(userRepo, roleRepo, mocks.NewMockMessagePublisher())
 
 	// Act
 	result, err := service.GetByID(ctx, user.ID)
@@ -104,7 +105,7 @@ func TestUserService_GetByEmail_Success(t *testing.T) {
 	roleRepo.On("GetByID", ctx, user.RoleID).Return(role, nil)
 	roleRepo.On("GetPermissionsByRoleID", ctx, user.RoleID).Return(permissions, nil)
 
-	service := NewUserService(userRepo, roleRepo)
+	service := NewUserService(userRepo, roleRepo, mocks.NewMockMessagePublisher())
 
 	// Act
 	result, err := service.GetByEmail(ctx, user.Email)
@@ -126,7 +127,7 @@ func TestUserService_GetByEmail_UserNotFound(t *testing.T) {
 
 	userRepo.On("GetByEmail", ctx, "notfound@example.com").Return(nil, pgx.ErrNoRows)
 
-	service := NewUserService(userRepo, roleRepo)
+	service := NewUserService(userRepo, roleRepo, mocks.NewMockMessagePublisher())
 
 	// Act
 	result, err := service.GetByEmail(ctx, "notfound@example.com")
@@ -151,12 +152,12 @@ func TestUserService_Update_Success_AllFields(t *testing.T) {
 	roleRepo.On("GetByID", ctx, 2).Return(newRole, nil)
 	userRepo.On("Update", ctx, user).Return(nil)
 
-	service := NewUserService(userRepo, roleRepo)
+	service := NewUserService(userRepo, roleRepo, mocks.NewMockMessagePublisher())
 
 	req := &entity.UpdateUserRequest{
-		Name:   "Updated Name",
-		Email:  "updated@example.com",
-		RoleID: 2,
+		Name:   patch.Of("Updated Name"),
+		Email:  patch.Of("updated@example.com"),
+		RoleID: patch.Of(2),
 	}
 
 	// Act
@@ -186,11 +187,11 @@ func TestUserService_Update_Success_PartialFields(t *testing.T) {
 	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
 	userRepo.On("Update", ctx, user).Return(nil)
 
-	service := NewUserService(userRepo, roleRepo)
+	service := NewUserService(userRepo, roleRepo, mocks.NewMockMessagePublisher())
 
 	// Обновляем только имя
 	req := &entity.UpdateUserRequest{
-		Name: "Only Name Updated",
+		Name: patch.Of("Only Name Updated"),
 	}
 
 	// Act
@@ -212,9 +213,9 @@ func TestUserService_Update_UserNotFound(t *testing.T) {
 	userID := uuid.New()
 	userRepo.On("GetByID", ctx, userID).Return(nil, pgx.ErrNoRows)
 
-	service := NewUserService(userRepo, roleRepo)
+	service := NewUserService(userRepo, roleRepo, mocks.NewMockMessagePublisher())
 
-	req := &entity.UpdateUserRequest{Name: "New Name"}
+	req := &entity.UpdateUserRequest{Name: patch.Of("New Name")}
 
 	// Act
 	result, err := service.Update(ctx, userID, req)
@@ -235,9 +236,9 @@ func TestUserService_Update_RoleNotFound(t *testing.T) {
 	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
 	roleRepo.On("GetByID", ctx, 999).Return(nil, pgx.ErrNoRows)
 
-	service := NewUserService(userRepo, roleRepo)
+	service := NewUserService(userRepo, roleRepo, mocks.NewMockMessagePublisher())
 
-	req := &entity.UpdateUserRequest{RoleID: 999}
+	req := &entity.UpdateUserRequest{RoleID: patch.Of(999)}
 
 	// Act
 	result, err := service.Update(ctx, user.ID, req)
@@ -260,7 +261,7 @@ func TestUserService_UpdatePassword_Success(t *testing.T) {
 	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
 	userRepo.On("Update", ctx, user).Return(nil)
 
-	service := NewUserService(userRepo, roleRepo)
+	service := NewUserService(userRepo, roleRepo, mocks.NewMockMessagePublisher())
 
 	// Act
 	err := service.UpdatePassword(ctx, user.ID, "password123", "newpassword456")
@@ -284,7 +285,7 @@ func TestUserService_UpdatePassword_WrongOldPassword(t *testing.T) {
 
 	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
 
-	service := NewUserService(userRepo, roleRepo)
+	service := NewUserService(userRepo, roleRepo, mocks.NewMockMessagePublisher())
 
 	// Act
 	err := service.UpdatePassword(ctx, user.ID, "wrongpassword", "newpassword456")
@@ -302,7 +303,7 @@ func TestUserService_UpdatePassword_UserNotFound(t *testing.T) {
 	userID := uuid.New()
 	userRepo.On("GetByID", ctx, userID).Return(nil, pgx.ErrNoRows)
 
-	service := NewUserService(userRepo, roleRepo)
+	service := NewUserService(userRepo, roleRepo, mocks.NewMockMessagePublisher())
 
 	// Act
 	err := service.UpdatePassword(ctx, userID, "old", "new")
@@ -322,7 +323,7 @@ func TestUserService_Delete_Success(t *testing.T) {
 	userID := uuid.New()
 	userRepo.On("Delete", ctx, userID).Return(nil)
 
-	service := NewUserService(userRepo, roleRepo)
+	service := NewUserService(userRepo, roleRepo, mocks.NewMockMessagePublisher())
 
 	// Act
 	err := service.Delete(ctx, userID)
@@ -341,7 +342,7 @@ func TestUserService_Delete_UserNotFound(t *testing.T) {
 	userID := uuid.New()
 	userRepo.On("Delete", ctx, userID).Return(pgx.ErrNoRows)
 
-	service := NewUserService(userRepo, roleRepo)
+	service := NewUserService(userRepo, roleRepo, mocks.NewMockMessagePublisher())
 
 	// Act
 	err := service.Delete(ctx, userID)
@@ -387,7 +388,7 @@ func TestUserService_List_Success(t *testing.T) {
 	roleRepo.On("GetByID", ctx, 2).Return(role2, nil)
 	roleRepo.On("GetPermissionsByRoleID", ctx, 2).Return(permissions, nil)
 
-	service := NewUserService(userRepo, roleRepo)
+	service := NewUserService(userRepo, roleRepo, mocks.NewMockMessagePublisher())
 
 	// Act
 	result, err := service.List(ctx)
@@ -412,7 +413,7 @@ func TestUserService_List_Empty(t *testing.T) {
 
 	userRepo.On("List", ctx).Return([]entity.User{}, nil)
 
-	service := NewUserService(userRepo, roleRepo)
+	service := NewUserService(userRepo, roleRepo, mocks.NewMockMessagePublisher())
 
 	// Act
 	result, err := service.List(ctx)
@@ -453,7 +454,7 @@ func TestUserService_List_SkipsUsersWithMissingRole(t *testing.T) {
 	roleRepo.On("GetPermissionsByRoleID", ctx, 1).Return(permissions, nil)
 	roleRepo.On("GetByID", ctx, 999).Return(nil, pgx.ErrNoRows)
 
-	service := NewUserService(userRepo, roleRepo)
+	service := NewUserService(userRepo, roleRepo, mocks.NewMockMessagePublisher())
 
 	// Act
 	result, err := service.List(ctx)
@@ -0,0 +1,114 @@
+package util
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"os"
+	"strconv"
+
+	"augustberries/auth-service/internal/app/auth/entity"
+)
+
+// GeoLocator определяет местоположение по IP адресу
+type GeoLocator interface {
+	// Locate возвращает nil, если местоположение неизвестно
+	Locate(ip string) *entity.GeoLocation
+}
+
+type geoRange struct {
+	network  *net.IPNet
+	location entity.GeoLocation
+}
+
+// cidrGeoLocator ищет IP в таблице диапазонов, загруженной из CSV
+type cidrGeoLocator struct {
+	ranges []geoRange
+}
+
+// NewCIDRGeoLocator читает таблицу диапазонов в формате CSV: cidr,country,city,latitude,longitude
+// Диапазоны проверяются по порядку, поэтому более узкие должны идти раньше
+func NewCIDRGeoLocator(r io.Reader) (GeoLocator, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 5
+	reader.Comment = '#'
+
+	locator := &cidrGeoLocator{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read geoip table: %w", err)
+		}
+
+		_, network, err := net.ParseCIDR(record[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid geoip range %q: %w", record[0], err)
+		}
+		lat, err := strconv.ParseFloat(record[3], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid latitude for %s: %w", record[0], err)
+		}
+		lon, err := strconv.ParseFloat(record[4], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid longitude for %s: %w", record[0], err)
+		}
+
+		locator.ranges = append(locator.ranges, geoRange{
+			network:  network,
+			location: entity.GeoLocation{Country: record[1], City: record[2], Latitude: lat, Longitude: lon},
+		})
+	}
+
+	return locator, nil
+}
+
+// LoadGeoLocator загружает таблицу из файла; без пути местоположение всегда неизвестно
+func LoadGeoLocator(path string) (GeoLocator, error) {
+	if path == "" {
+		return &cidrGeoLocator{}, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open geoip table: %w", err)
+	}
+	defer file.Close()
+
+	return NewCIDRGeoLocator(file)
+}
+
+func (l *cidrGeoLocator) Locate(ip string) *entity.GeoLocation {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil
+	}
+
+	for _, r := range l.ranges {
+		if r.network.Contains(parsed) {
+			location := r.location
+			return &location
+		}
+	}
+	return nil
+}
+
+// earthRadiusKm - средний радиус Земли
+const earthRadiusKm = 6371.0
+
+// DistanceKm возвращает расстояние между точками по поверхности Земли (формула гаверсинусов)
+func DistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
@@ -0,0 +1,41 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ===== GeoLocator Tests =====
+
+func TestCIDRGeoLocator_Locate(t *testing.T) {
+	// Arrange
+	locator, err := NewCIDRGeoLocator(strings.NewReader(`# cidr,country,city,lat,lon
+10.1.0.0/16,RU,Kazan,55.79,49.12
+10.0.0.0/8,RU,Moscow,55.75,37.62
+`))
+	require.NoError(t, err)
+
+	// Act & Assert: более узкий диапазон идет первым
+	assert.Equal(t, "Kazan", locator.Locate("10.1.2.3").City)
+	assert.Equal(t, "Moscow", locator.Locate("10.2.0.1").City)
+	assert.Nil(t, locator.Locate("192.168.0.1"))
+	assert.Nil(t, locator.Locate("not-an-ip"))
+}
+
+func TestLoadGeoLocator_EmptyPath(t *testing.T) {
+	locator, err := LoadGeoLocator("")
+
+	require.NoError(t, err)
+	assert.Nil(t, locator.Locate("10.0.0.1"))
+}
+
+func TestDistanceKm(t *testing.T) {
+	// Москва - Нью-Йорк около 7500 км
+	distance := DistanceKm(55.7558, 37.6173, 40.7128, -74.0060)
+
+	assert.InDelta(t, 7510, distance, 50)
+	assert.Zero(t, DistanceKm(55.75, 37.62, 55.75, 37.62))
+}
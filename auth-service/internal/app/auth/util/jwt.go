@@ -5,15 +5,23 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+
+	"augustberries/pkg/impersonation"
+	"augustberries/pkg/jwtclaims"
+	"augustberries/pkg/tenant"
 )
 
 var (
 	ErrInvalidToken = errors.New("invalid token")
 	ErrExpiredToken = errors.New("token has expired")
+	// ErrInvalidIssuer и ErrInvalidAudience - токен выпущен для другого окружения; errors.Is(err, ErrInvalidToken) тоже верно
+	ErrInvalidIssuer   = fmt.Errorf("%w: issuer is not accepted", ErrInvalidToken)
+	ErrInvalidAudience = fmt.Errorf("%w: audience is not accepted", ErrInvalidToken)
 )
 
 // JWTClaims содержит данные, хранящиеся в JWT токене
@@ -23,46 +31,171 @@ type JWTClaims struct {
 	RoleID      int       `json:"role_id"`
 	RoleName    string    `json:"role_name"`
 	Permissions []string  `json:"permissions"`
+	TenantID    string    `json:"tenant_id,omitempty"` // Магазин пользователя, по нему сервисы изолируют данные
+	// PreferredCurrency - валюта заказов пользователя по умолчанию, пусто - валюта магазина
+	PreferredCurrency string `json:"preferred_currency,omitempty"`
+	// TenantRoles - роли пользователя в других магазинах; tenant.Middleware применяет их по X-Tenant-ID
+	TenantRoles tenant.Roles `json:"tenant_roles,omitempty"`
+	// Impersonator - сотрудник поддержки, выпустивший токен от имени пользователя; nil - обычный токен
+	Impersonator *impersonation.Impersonator `json:"impersonator,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// RememberTokenPrefix отличает refresh токены "запомнить меня" от обычных
+const RememberTokenPrefix = "rm_"
+
+// RememberMePolicy - сроки жизни токенов "запомнить меня"
+type RememberMePolicy struct {
+	TokenDuration time.Duration // Скользящий срок: отсчитывается заново при каждом обновлении
+	MaxSessionAge time.Duration // Абсолютный предел сессии с момента входа
+}
+
+// GuestRoleName - роль в токене гостевой сессии покупателя без аккаунта
+const GuestRoleName = "guest"
+
+// DefaultGuestTokenDuration - срок жизни гостевого токена по умолчанию
+const DefaultGuestTokenDuration = 24 * time.Hour
+
+// DefaultImpersonationTokenDuration - срок жизни токена имперсонации по умолчанию
+const DefaultImpersonationTokenDuration = 15 * time.Minute
+
 // JWTManager управляет созданием и проверкой JWT токенов
 type JWTManager struct {
-	secretKey            string
-	accessTokenDuration  time.Duration
-	refreshTokenDuration time.Duration
+	secretKey                  string
+	accessTokenDuration        time.Duration
+	refreshTokenDuration       time.Duration
+	guestTokenDuration         time.Duration
+	impersonationTokenDuration time.Duration
+	rememberMe                 RememberMePolicy // Нулевая политика - "запомнить меня" отключено
+	issuer                     string           // iss выпускаемых токенов, пусто - не записывается и не проверяется
+	audience                   string           // aud выпускаемых токенов, пусто - не записывается и не проверяется
 }
 
 // NewJWTManager создает новый менеджер JWT
 func NewJWTManager(secretKey string, accessDuration, refreshDuration time.Duration) *JWTManager {
 	return &JWTManager{
-		secretKey:            secretKey,
-		accessTokenDuration:  accessDuration,
-		refreshTokenDuration: refreshDuration,
+		secretKey:                  secretKey,
+		accessTokenDuration:        accessDuration,
+		refreshTokenDuration:       refreshDuration,
+		guestTokenDuration:         DefaultGuestTokenDuration,
+		impersonationTokenDuration: DefaultImpersonationTokenDuration,
 	}
 }
 
-// GenerateAccessToken создает access токен с информацией о пользователе
-func (m *JWTManager) GenerateAccessToken(userID uuid.UUID, email string, roleID int, roleName string, permissions []string) (string, error) {
+// SetIssuerAudience задает iss и aud, которые записываются в токены и проверяются ValidateToken
+// Сервисы проверяют те же значения (JWT_ISSUER, JWT_AUDIENCE), поэтому токен другого окружения отклоняется
+func (m *JWTManager) SetIssuerAudience(issuer, audience string) {
+	m.issuer = issuer
+	m.audience = audience
+}
+
+// registeredClaims заполняет стандартные claims токена пользователя subject
+func (m *JWTManager) registeredClaims(subject uuid.UUID, now, expiresAt time.Time) jwt.RegisteredClaims {
+	claims := jwt.RegisteredClaims{
+		Issuer:    m.issuer,
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		Subject:   subject.String(),
+	}
+	if m.audience != "" {
+		claims.Audience = jwt.ClaimStrings{m.audience}
+	}
+	return claims
+}
+
+// GenerateAccessToken создает access токен с информацией о пользователе, его магазине и предпочитаемой валюте
+// tenantRoles - роли в других магазинах, nil - только магазин регистрации
+func (m *JWTManager) GenerateAccessToken(userID uuid.UUID, email string, roleID int, roleName string, permissions []string, tenantID, preferredCurrency string, tenantRoles tenant.Roles) (string, error) {
 	now := time.Now()
 	claims := JWTClaims{
-		UserID:      userID,
-		Email:       email,
-		RoleID:      roleID,
-		RoleName:    roleName,
-		Permissions: permissions,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(m.accessTokenDuration)),
-			IssuedAt:  jwt.NewNumericDate(now),
-			NotBefore: jwt.NewNumericDate(now),
-			Subject:   userID.String(),
-		},
+		UserID:            userID,
+		Email:             email,
+		RoleID:            roleID,
+		RoleName:          roleName,
+		Permissions:       permissions,
+		TenantID:          tenantID,
+		PreferredCurrency: preferredCurrency,
+		TenantRoles:       tenantRoles,
+		RegisteredClaims:  m.registeredClaims(userID, now, now.Add(m.accessTokenDuration)),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(m.secretKey))
+}
+
+// GenerateGuestToken создает токен гостевой сессии
+// В токене нет роли из БД и разрешений, а user_id - идентификатор гостя,
+// под которым Orders Service сохраняет его заказы до привязки к аккаунту
+func (m *JWTManager) GenerateGuestToken(guestID uuid.UUID, email, tenantID string) (string, error) {
+	now := time.Now()
+	claims := JWTClaims{
+		UserID:           guestID,
+		Email:            email,
+		RoleName:         GuestRoleName,
+		Permissions:      []string{},
+		TenantID:         tenantID,
+		RegisteredClaims: m.registeredClaims(guestID, now, now.Add(m.guestTokenDuration)),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(m.secretKey))
 }
 
+// SetGuestTokenDuration задает срок жизни гостевых токенов
+func (m *JWTManager) SetGuestTokenDuration(d time.Duration) {
+	if d > 0 {
+		m.guestTokenDuration = d
+	}
+}
+
+// GetGuestTokenDuration возвращает срок жизни гостевого токена
+func (m *JWTManager) GetGuestTokenDuration() time.Duration {
+	return m.guestTokenDuration
+}
+
+// GenerateImpersonationToken создает короткоживущий access токен пользователя для сотрудника поддержки
+// Токен помечен claim impersonator, роли в других магазинах не переносятся. Refresh токен не выдается
+func (m *JWTManager) GenerateImpersonationToken(userID uuid.UUID, email string, roleID int, roleName string, permissions []string, tenantID, preferredCurrency string, impersonator impersonation.Impersonator) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(m.impersonationTokenDuration)
+	claims := JWTClaims{
+		UserID:            userID,
+		Email:             email,
+		RoleID:            roleID,
+		RoleName:          roleName,
+		Permissions:       permissions,
+		TenantID:          tenantID,
+		PreferredCurrency: preferredCurrency,
+		Impersonator:      &impersonator,
+		RegisteredClaims:  m.registeredClaims(userID, now, expiresAt),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString([]byte(m.secretKey))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// SetImpersonationTokenDuration задает срок жизни токенов имперсонации
+func (m *JWTManager) SetImpersonationTokenDuration(d time.Duration) {
+	if d > 0 {
+		m.impersonationTokenDuration = d
+	}
+}
+
+// IsImpersonated сообщает, выдан ли токен сотруднику поддержки от имени пользователя
+func (c *JWTClaims) IsImpersonated() bool {
+	return c.Impersonator != nil
+}
+
+// IsGuest сообщает, выдан ли токен гостевой сессии
+func (c *JWTClaims) IsGuest() bool {
+	return c.RoleName == GuestRoleName
+}
+
 // GenerateRefreshToken создает уникальный refresh токен
 func (m *JWTManager) GenerateRefreshToken() (string, error) {
 	// Генерируем случайные 32 байта
@@ -73,8 +206,48 @@ func (m *JWTManager) GenerateRefreshToken() (string, error) {
 	return base64.URLEncoding.EncodeToString(b), nil
 }
 
+// SetRememberMePolicy включает долгоживущие сессии "запомнить меня"
+func (m *JWTManager) SetRememberMePolicy(policy RememberMePolicy) {
+	m.rememberMe = policy
+}
+
+// RememberMeEnabled сообщает, выдаются ли токены "запомнить меня"
+func (m *JWTManager) RememberMeEnabled() bool {
+	return m.rememberMe.TokenDuration > 0
+}
+
+// GenerateRememberToken создает refresh токен "запомнить меня"
+func (m *JWTManager) GenerateRememberToken() (string, error) {
+	token, err := m.GenerateRefreshToken()
+	if err != nil {
+		return "", err
+	}
+	return RememberTokenPrefix + token, nil
+}
+
+// RememberTokenExpiry возвращает срок действия токена "запомнить меня", выданного в now
+// Срок продлевается на TokenDuration, но не дальше MaxSessionAge от начала сессии
+// false - сессия исчерпала максимальный возраст
+func (m *JWTManager) RememberTokenExpiry(sessionStartedAt, now time.Time) (time.Time, bool) {
+	expiresAt := now.Add(m.rememberMe.TokenDuration)
+	if m.rememberMe.MaxSessionAge > 0 {
+		if limit := sessionStartedAt.Add(m.rememberMe.MaxSessionAge); limit.Before(expiresAt) {
+			expiresAt = limit
+		}
+	}
+	return expiresAt, expiresAt.After(now)
+}
+
+// IsRememberToken сообщает, является ли refresh токен токеном "запомнить меня"
+func IsRememberToken(token string) bool {
+	return strings.HasPrefix(token, RememberTokenPrefix)
+}
+
 // ValidateToken проверяет и парсит JWT токен
 func (m *JWTManager) ValidateToken(tokenString string) (*JWTClaims, error) {
+	// iss и aud проверяются так же, как в middleware остальных сервисов
+	opts := jwtclaims.Expected{Issuer: m.issuer, Audience: m.audience}.ParserOptions()
+
 	token, err := jwt.ParseWithClaims(
 		tokenString,
 		&JWTClaims{},
@@ -85,11 +258,23 @@ func (m *JWTManager) ValidateToken(tokenString string) (*JWTClaims, error) {
 			}
 			return []byte(m.secretKey), nil
 		},
+		opts...,
 	)
 
 	if err != nil {
-		if errors.Is(err, jwt.ErrTokenExpired) {
+		switch {
+		case errors.Is(err, jwt.ErrTokenExpired):
 			return nil, ErrExpiredToken
+		case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+			return nil, ErrInvalidIssuer
+		case errors.Is(err, jwt.ErrTokenInvalidAudience):
+			return nil, ErrInvalidAudience
+		case errors.Is(err, jwt.ErrTokenRequiredClaimMissing):
+			// Токен выпущен до настройки iss и aud
+			if claims, ok := token.Claims.(*JWTClaims); ok && m.issuer != "" && claims.Issuer == "" {
+				return nil, ErrInvalidIssuer
+			}
+			return nil, ErrInvalidAudience
 		}
 		return nil, ErrInvalidToken
 	}
@@ -7,6 +7,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"augustberries/pkg/tenant"
 )
 
 func TestJWTManager_GenerateAccessToken_Success(t *testing.T) {
@@ -19,7 +21,7 @@ func TestJWTManager_GenerateAccessToken_Success(t *testing.T) {
 	permissions := []string{"product.create", "product.read", "order.create"}
 
 	// Act
-	token, err := jwtManager.GenerateAccessToken(userID, email, roleID, roleName, permissions)
+	token, err := jwtManager.GenerateAccessToken(userID, email, roleID, roleName, permissions, "shop-a", "EUR", nil)
 
 	// Assert
 	require.NoError(t, err)
@@ -33,6 +35,26 @@ func TestJWTManager_GenerateAccessToken_Success(t *testing.T) {
 	assert.Equal(t, roleID, claims.RoleID)
 	assert.Equal(t, roleName, claims.RoleName)
 	assert.ElementsMatch(t, permissions, claims.Permissions)
+	assert.Equal(t, "shop-a", claims.TenantID)
+	assert.Equal(t, "EUR", claims.PreferredCurrency)
+}
+
+func TestJWTManager_GenerateAccessToken_TenantRoles(t *testing.T) {
+	// Arrange
+	jwtManager := NewJWTManager("test-secret-key", 15*time.Minute, 7*24*time.Hour)
+	roles := tenant.Roles{
+		"shop-b": {ID: 1, Name: "user", Permissions: []string{"order.create"}},
+	}
+
+	// Act
+	token, err := jwtManager.GenerateAccessToken(uuid.New(), "test@example.com", 3, "admin", []string{"product.create"}, "shop-a", "", roles)
+	require.NoError(t, err)
+	claims, err := jwtManager.ValidateToken(token)
+
+	// Assert: роль в магазине регистрации остается в основных claims
+	require.NoError(t, err)
+	assert.Equal(t, "admin", claims.RoleName)
+	assert.Equal(t, roles, claims.TenantRoles)
 }
 
 func TestJWTManager_GenerateRefreshToken_Success(t *testing.T) {
@@ -60,7 +82,7 @@ func TestJWTManager_ValidateToken_Success(t *testing.T) {
 	roleName := "user"
 	permissions := []string{"product.read"}
 
-	token, _ := jwtManager.GenerateAccessToken(userID, email, roleID, roleName, permissions)
+	token, _ := jwtManager.GenerateAccessToken(userID, email, roleID, roleName, permissions, "default", "", nil)
 
 	// Act
 	claims, err := jwtManager.ValidateToken(token)
@@ -94,7 +116,7 @@ func TestJWTManager_ValidateToken_WrongSecret(t *testing.T) {
 	jwtManager2 := NewJWTManager("secret-key-2", 15*time.Minute, 7*24*time.Hour)
 
 	userID := uuid.New()
-	token, _ := jwtManager1.GenerateAccessToken(userID, "test@example.com", 1, "user", []string{})
+	token, _ := jwtManager1.GenerateAccessToken(userID, "test@example.com", 1, "user", []string{}, "default", "", nil)
 
 	// Act
 	claims, err := jwtManager2.ValidateToken(token)
@@ -104,12 +126,40 @@ func TestJWTManager_ValidateToken_WrongSecret(t *testing.T) {
 	assert.ErrorIs(t, err, ErrInvalidToken)
 }
 
+func TestJWTManager_IssuerAndAudience(t *testing.T) {
+	// Arrange
+	prod := NewJWTManager("shared-secret", 15*time.Minute, 7*24*time.Hour)
+	prod.SetIssuerAudience("augustberries-auth", "augustberries-prod")
+	staging := NewJWTManager("shared-secret", 15*time.Minute, 7*24*time.Hour)
+	staging.SetIssuerAudience("augustberries-auth", "augustberries-staging")
+	legacy := NewJWTManager("shared-secret", 15*time.Minute, 7*24*time.Hour)
+
+	userID := uuid.New()
+	prodToken, err := prod.GenerateAccessToken(userID, "test@example.com", 1, "user", []string{}, "default", "", nil)
+	require.NoError(t, err)
+	legacyToken, err := legacy.GenerateGuestToken(userID, "guest@example.com", "default")
+	require.NoError(t, err)
+
+	// Act
+	claims, err := prod.ValidateToken(prodToken)
+	_, stagingErr := staging.ValidateToken(prodToken)
+	_, legacyErr := prod.ValidateToken(legacyToken)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "augustberries-auth", claims.Issuer)
+	assert.Equal(t, []string{"augustberries-prod"}, []string(claims.Audience))
+	assert.ErrorIs(t, stagingErr, ErrInvalidAudience)
+	assert.ErrorIs(t, stagingErr, ErrInvalidToken)
+	assert.ErrorIs(t, legacyErr, ErrInvalidIssuer)
+}
+
 func TestJWTManager_ValidateToken_ExpiredToken(t *testing.T) {
 	// Arrange
 	jwtManager := NewJWTManager("test-secret-key", 1*time.Nanosecond, 7*24*time.Hour)
 	userID := uuid.New()
 
-	token, _ := jwtManager.GenerateAccessToken(userID, "test@example.com", 1, "user", []string{})
+	token, _ := jwtManager.GenerateAccessToken(userID, "test@example.com", 1, "user", []string{}, "default", "", nil)
 
 	// Ждём пока токен истечёт
 	time.Sleep(10 * time.Millisecond)
@@ -191,7 +241,7 @@ func TestJWTManager_TokenContainsCorrectExpiration(t *testing.T) {
 	userID := uuid.New()
 
 	beforeGeneration := time.Now()
-	token, _ := jwtManager.GenerateAccessToken(userID, "test@example.com", 1, "user", []string{})
+	token, _ := jwtManager.GenerateAccessToken(userID, "test@example.com", 1, "user", []string{}, "default", "", nil)
 	afterGeneration := time.Now()
 
 	// Act
@@ -214,7 +264,7 @@ func TestJWTManager_EmptyPermissions(t *testing.T) {
 	userID := uuid.New()
 
 	// Act
-	token, err := jwtManager.GenerateAccessToken(userID, "test@example.com", 1, "user", []string{})
+	token, err := jwtManager.GenerateAccessToken(userID, "test@example.com", 1, "user", []string{}, "default", "", nil)
 
 	// Assert
 	require.NoError(t, err)
@@ -230,7 +280,7 @@ func TestJWTManager_NilPermissions(t *testing.T) {
 	userID := uuid.New()
 
 	// Act
-	token, err := jwtManager.GenerateAccessToken(userID, "test@example.com", 1, "user", nil)
+	token, err := jwtManager.GenerateAccessToken(userID, "test@example.com", 1, "user", nil, "default", "", nil)
 
 	// Assert
 	require.NoError(t, err)
@@ -239,3 +289,66 @@ func TestJWTManager_NilPermissions(t *testing.T) {
 	require.NoError(t, err)
 	assert.Nil(t, claims.Permissions)
 }
+
+func TestJWTManager_GenerateRememberToken(t *testing.T) {
+	// Arrange
+	jwtManager := NewJWTManager("test-secret-key", 15*time.Minute, 7*24*time.Hour)
+
+	// Act
+	token, err := jwtManager.GenerateRememberToken()
+	refreshToken, _ := jwtManager.GenerateRefreshToken()
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, IsRememberToken(token))
+	assert.False(t, IsRememberToken(refreshToken))
+}
+
+func TestJWTManager_RememberTokenExpiry(t *testing.T) {
+	// Arrange
+	jwtManager := NewJWTManager("test-secret-key", 15*time.Minute, 7*24*time.Hour)
+	jwtManager.SetRememberMePolicy(RememberMePolicy{
+		TokenDuration: 30 * 24 * time.Hour,
+		MaxSessionAge: 90 * 24 * time.Hour,
+	})
+	now := time.Now()
+
+	// Act & Assert: в начале сессии срок скользящий
+	expiresAt, ok := jwtManager.RememberTokenExpiry(now.Add(-time.Hour), now)
+	assert.True(t, ok)
+	assert.Equal(t, now.Add(30*24*time.Hour), expiresAt)
+
+	// Ближе к пределу срок ограничен максимальным возрастом сессии
+	started := now.Add(-80 * 24 * time.Hour)
+	expiresAt, ok = jwtManager.RememberTokenExpiry(started, now)
+	assert.True(t, ok)
+	assert.Equal(t, started.Add(90*24*time.Hour), expiresAt)
+
+	// Сессия старше предела не продлевается
+	_, ok = jwtManager.RememberTokenExpiry(now.Add(-91*24*time.Hour), now)
+	assert.False(t, ok)
+}
+
+// ==================== Guest Token Tests ====================
+
+func TestJWTManager_GenerateGuestToken(t *testing.T) {
+	// Arrange
+	jwtManager := NewJWTManager("test-secret-key", 15*time.Minute, 7*24*time.Hour)
+	jwtManager.SetGuestTokenDuration(2 * time.Hour)
+	guestID := uuid.New()
+
+	// Act
+	token, err := jwtManager.GenerateGuestToken(guestID, "guest@example.com", "shop-1")
+	require.NoError(t, err)
+	claims, err := jwtManager.ValidateToken(token)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, claims.IsGuest())
+	assert.Equal(t, guestID, claims.UserID)
+	assert.Equal(t, "guest@example.com", claims.Email)
+	assert.Equal(t, "shop-1", claims.TenantID)
+	assert.Zero(t, claims.RoleID)
+	assert.Empty(t, claims.Permissions)
+	assert.WithinDuration(t, time.Now().Add(2*time.Hour), claims.ExpiresAt.Time, 5*time.Second)
+}
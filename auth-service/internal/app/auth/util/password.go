@@ -1,20 +1,158 @@
 package util
 
 import (
-	"golang.org/x/crypto/bcrypt"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
 )
 
-// HashPassword хэширует пароль с использованием bcrypt
-func HashPassword(password string) (string, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+// ErrUnknownPasswordAlgorithm - алгоритм хэширования паролей не зарегистрирован
+var ErrUnknownPasswordAlgorithm = errors.New("unknown password hashing algorithm")
+
+// Алгоритмы хэширования паролей
+const (
+	PasswordAlgorithmBcrypt   = "bcrypt"
+	PasswordAlgorithmArgon2id = "argon2id"
+	PasswordAlgorithmScrypt   = "scrypt"
+)
+
+// PasswordPolicy - алгоритм и параметры хэширования новых паролей
+// Параметры записываются в сам хэш, поэтому старые хэши проверяются после смены политики
+type PasswordPolicy struct {
+	Algorithm string // bcrypt, argon2id или scrypt
+
+	BcryptCost int
+
+	Argon2Memory      uint32 // Память в KiB
+	Argon2Iterations  uint32
+	Argon2Parallelism uint8
+
+	ScryptN int // Степень двойки
+	ScryptR int
+	ScryptP int
+}
+
+// DefaultPasswordPolicy - bcrypt со стоимостью по умолчанию и рекомендованные параметры остальных алгоритмов
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		Algorithm:         PasswordAlgorithmBcrypt,
+		BcryptCost:        10,
+		Argon2Memory:      64 * 1024,
+		Argon2Iterations:  3,
+		Argon2Parallelism: 2,
+		ScryptN:           1 << 15,
+		ScryptR:           8,
+		ScryptP:           1,
+	}
+}
+
+// PasswordHasher - алгоритм хэширования паролей
+type PasswordHasher interface {
+	// Name - идентификатор алгоритма в хэше
+	Name() string
+	Hash(password string) (string, error)
+	// Verify проверяет пароль по параметрам, записанным в хэше
+	Verify(password, hash string) bool
+	// NeedsRehash - хэш создан с параметрами, отличными от текущих
+	NeedsRehash(hash string) bool
+}
+
+// PasswordHashing хэширует пароли алгоритмом политики и проверяет хэши всех зарегистрированных алгоритмов
+type PasswordHashing struct {
+	current PasswordHasher
+	hashers map[string]PasswordHasher
+}
+
+// NewPasswordHashing создает реестр алгоритмов по политике
+func NewPasswordHashing(policy PasswordPolicy) (*PasswordHashing, error) {
+	bcryptHasher, err := newBcryptHasher(policy.BcryptCost)
+	if err != nil {
+		return nil, err
+	}
+	argon2Hasher, err := newArgon2idHasher(policy.Argon2Memory, policy.Argon2Iterations, policy.Argon2Parallelism)
+	if err != nil {
+		return nil, err
+	}
+	scryptHasher, err := newScryptHasher(policy.ScryptN, policy.ScryptR, policy.ScryptP)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &PasswordHashing{hashers: map[string]PasswordHasher{
+		bcryptHasher.Name(): bcryptHasher,
+		argon2Hasher.Name(): argon2Hasher,
+		scryptHasher.Name(): scryptHasher,
+	}}
+
+	current, ok := h.hashers[strings.ToLower(policy.Algorithm)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPasswordAlgorithm, policy.Algorithm)
+	}
+	h.current = current
+	return h, nil
+}
+
+// Hash хэширует пароль текущим алгоритмом
+func (h *PasswordHashing) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+// Verify проверяет пароль алгоритмом из хэша
+// rehash - пароль верный, но хэш создан другим алгоритмом или с другими параметрами
+func (h *PasswordHashing) Verify(password, hash string) (ok, rehash bool) {
+	hasher, found := h.hashers[hashAlgorithm(hash)]
+	if !found || !hasher.Verify(password, hash) {
+		return false, false
+	}
+	return true, hasher != h.current || hasher.NeedsRehash(hash)
+}
+
+// hashAlgorithm определяет алгоритм по префиксу хэша: $2a$/$2b$/$2y$ - bcrypt, иначе $<алгоритм>$
+func hashAlgorithm(hash string) string {
+	if !strings.HasPrefix(hash, "$") {
+		return ""
+	}
+	if strings.HasPrefix(hash, "$2") {
+		return PasswordAlgorithmBcrypt
+	}
+	name, _, _ := strings.Cut(hash[1:], "$")
+	return name
+}
+
+// passwords - политика хэширования сервиса, задается при запуске (ConfigurePasswordHashing)
+var passwords atomic.Pointer[PasswordHashing]
+
+func init() {
+	h, err := NewPasswordHashing(DefaultPasswordPolicy())
+	if err != nil {
+		panic(err)
+	}
+	passwords.Store(h)
+}
+
+// ConfigurePasswordHashing задает алгоритм и параметры хэширования новых паролей
+func ConfigurePasswordHashing(policy PasswordPolicy) error {
+	h, err := NewPasswordHashing(policy)
 	if err != nil {
-		return "", err
+		return err
 	}
-	return string(hashedPassword), nil
+	passwords.Store(h)
+	return nil
+}
+
+// HashPassword хэширует пароль алгоритмом политики сервиса
+func HashPassword(password string) (string, error) {
+	return passwords.Load().Hash(password)
 }
 
 // CheckPassword проверяет, соответствует ли пароль хэшу
 func CheckPassword(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+	ok, _ := passwords.Load().Verify(password, hash)
+	return ok
+}
+
+// VerifyPassword проверяет пароль и сообщает, нужно ли пересчитать хэш по текущей политике
+func VerifyPassword(password, hash string) (ok, rehash bool) {
+	return passwords.Load().Verify(password, hash)
 }
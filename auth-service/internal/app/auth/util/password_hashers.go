@@ -0,0 +1,201 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"math/bits"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	passwordSaltLength = 16
+	passwordKeyLength  = 32
+)
+
+var passwordEncoding = base64.RawStdEncoding
+
+// bcryptHasher - bcrypt, стоимость записана в хэше ($2a$10$...)
+type bcryptHasher struct {
+	cost int
+}
+
+func newBcryptHasher(cost int) (*bcryptHasher, error) {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	return &bcryptHasher{cost: cost}, nil
+}
+
+func (h *bcryptHasher) Name() string { return PasswordAlgorithmBcrypt }
+
+func (h *bcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (h *bcryptHasher) Verify(password, hash string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+func (h *bcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.cost
+}
+
+// argon2idHasher - argon2id в формате PHC: $argon2id$v=19$m=65536,t=3,p=2$соль$ключ
+type argon2idHasher struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+func newArgon2idHasher(memory, iterations uint32, parallelism uint8) (*argon2idHasher, error) {
+	if memory < 8*uint32(parallelism) || iterations == 0 || parallelism == 0 {
+		return nil, fmt.Errorf("argon2id requires iterations > 0, parallelism > 0 and memory >= 8*parallelism KiB")
+	}
+	return &argon2idHasher{memory: memory, iterations: iterations, parallelism: parallelism}, nil
+}
+
+func (h *argon2idHasher) Name() string { return PasswordAlgorithmArgon2id }
+
+func (h *argon2idHasher) Hash(password string) (string, error) {
+	salt, err := passwordSalt()
+	if err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.iterations, h.memory, h.parallelism, passwordKeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, h.memory, h.iterations, h.parallelism,
+		passwordEncoding.EncodeToString(salt), passwordEncoding.EncodeToString(key)), nil
+}
+
+func (h *argon2idHasher) Verify(password, hash string) bool {
+	params, salt, key, ok := parseArgon2idHash(hash)
+	if !ok {
+		return false
+	}
+	actual := argon2.IDKey([]byte(password), salt, params.iterations, params.memory, params.parallelism, uint32(len(key)))
+	return subtle.ConstantTimeCompare(actual, key) == 1
+}
+
+func (h *argon2idHasher) NeedsRehash(hash string) bool {
+	params, _, _, ok := parseArgon2idHash(hash)
+	return !ok || *params != *h
+}
+
+// parseArgon2idHash разбирает параметры, соль и ключ хэша argon2id
+func parseArgon2idHash(hash string) (*argon2idHasher, []byte, []byte, bool) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != PasswordAlgorithmArgon2id {
+		return nil, nil, nil, false
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, nil, nil, false
+	}
+
+	params := &argon2idHasher{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations, &params.parallelism); err != nil {
+		return nil, nil, nil, false
+	}
+	if params.iterations == 0 || params.parallelism == 0 {
+		return nil, nil, nil, false
+	}
+
+	salt, err := passwordEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, false
+	}
+	key, err := passwordEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return nil, nil, nil, false
+	}
+	return params, salt, key, true
+}
+
+// scryptHasher - scrypt в формате $scrypt$ln=15,r=8,p=1$соль$ключ, ln - log2(N)
+type scryptHasher struct {
+	logN int
+	r    int
+	p    int
+}
+
+func newScryptHasher(n, r, p int) (*scryptHasher, error) {
+	if n <= 1 || n&(n-1) != 0 || r <= 0 || p <= 0 {
+		return nil, fmt.Errorf("scrypt requires N > 1 power of two, r > 0 and p > 0")
+	}
+	return &scryptHasher{logN: bits.TrailingZeros(uint(n)), r: r, p: p}, nil
+}
+
+func (h *scryptHasher) Name() string { return PasswordAlgorithmScrypt }
+
+func (h *scryptHasher) Hash(password string) (string, error) {
+	salt, err := passwordSalt()
+	if err != nil {
+		return "", err
+	}
+	key, err := scrypt.Key([]byte(password), salt, 1<<h.logN, h.r, h.p, passwordKeyLength)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("$scrypt$ln=%d,r=%d,p=%d$%s$%s", h.logN, h.r, h.p,
+		passwordEncoding.EncodeToString(salt), passwordEncoding.EncodeToString(key)), nil
+}
+
+func (h *scryptHasher) Verify(password, hash string) bool {
+	params, salt, key, ok := parseScryptHash(hash)
+	if !ok {
+		return false
+	}
+	actual, err := scrypt.Key([]byte(password), salt, 1<<params.logN, params.r, params.p, len(key))
+	return err == nil && subtle.ConstantTimeCompare(actual, key) == 1
+}
+
+func (h *scryptHasher) NeedsRehash(hash string) bool {
+	params, _, _, ok := parseScryptHash(hash)
+	return !ok || *params != *h
+}
+
+// parseScryptHash разбирает параметры, соль и ключ хэша scrypt
+func parseScryptHash(hash string) (*scryptHasher, []byte, []byte, bool) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 5 || parts[1] != PasswordAlgorithmScrypt {
+		return nil, nil, nil, false
+	}
+
+	params := &scryptHasher{}
+	if _, err := fmt.Sscanf(parts[2], "ln=%d,r=%d,p=%d", &params.logN, &params.r, &params.p); err != nil {
+		return nil, nil, nil, false
+	}
+	if params.logN < 1 || params.logN > 30 || params.r <= 0 || params.p <= 0 {
+		return nil, nil, nil, false
+	}
+
+	salt, err := passwordEncoding.DecodeString(parts[3])
+	if err != nil {
+		return nil, nil, nil, false
+	}
+	key, err := passwordEncoding.DecodeString(parts[4])
+	if err != nil || len(key) == 0 {
+		return nil, nil, nil, false
+	}
+	return params, salt, key, true
+}
+
+// passwordSalt возвращает случайную соль
+func passwordSalt() ([]byte, error) {
+	salt := make([]byte, passwordSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	return salt, nil
+}
@@ -0,0 +1,166 @@
+package util
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// PasswordStrength - оценка стойкости пароля
+type PasswordStrength struct {
+	// Score по шкале zxcvbn: 0 - угадывается мгновенно, 4 - очень стойкий
+	Score int
+	// Entropy - log2 оценки числа попыток подбора
+	Entropy float64
+}
+
+// passwordScoreBits - нижние границы Score 1..4 в битах: 10^3, 10^6, 10^8 и 10^10 попыток, как в zxcvbn
+var passwordScoreBits = [...]float64{10, 20, 26.6, 33.2}
+
+// commonPasswordWords - самые частые пароли и их основы; проверяются как подстроки без учета регистра и leet-замен
+var commonPasswordWords = []string{
+	"password", "qwerty", "letmein", "welcome", "admin", "login", "iloveyou", "monkey", "dragon", "master",
+	"sunshine", "princess", "football", "baseball", "soccer", "hockey", "shadow", "superman", "batman",
+	"trustno", "secret", "summer", "winter", "spring", "autumn", "freedom", "whatever", "starwars", "pokemon",
+	"killer", "hunter", "ranger", "charlie", "michael", "jordan", "jennifer", "thomas", "ashley", "nicole",
+	"parol", "privet", "lovely", "flower", "cookie", "cheese", "orange", "banana", "computer", "internet",
+	"google", "changeme", "default", "access", "test", "user", "guest", "root",
+}
+
+// keyboardRows - соседние клавиши раскладки: "qwerty", "asdf" и ряд цифр подбираются как последовательность
+var keyboardRows = []string{"1234567890", "qwertyuiop", "asdfghjkl", "zxcvbnm", "йцукенгшщзхъ", "фывапролджэ", "ячсмитьбю"}
+
+// leetReplacer приводит частые замены букв цифрами и символами к буквам
+var leetReplacer = strings.NewReplacer("@", "a", "4", "a", "0", "o", "1", "i", "!", "i", "3", "e", "$", "s", "5", "s", "7", "t", "+", "t")
+
+// EstimatePasswordStrength оценивает стойкость пароля упрощенным алгоритмом в духе zxcvbn
+// Словарные слова (частые пароли и userInputs - email, имя) стоят несколько бит, повторы, последовательности
+// и соседние клавиши - 1 бит на символ, остальные символы - перебор по алфавиту использованных классов
+func EstimatePasswordStrength(password string, userInputs ...string) PasswordStrength {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return PasswordStrength{}
+	}
+
+	dictionary := passwordDictionary(userInputs)
+	normalized := []rune(leetReplacer.Replace(strings.ToLower(password)))
+	if len(normalized) != len(runes) {
+		// Замена изменила длину (не должно случаться), сравниваем без нормализации
+		normalized = []rune(strings.ToLower(password))
+	}
+	bruteForceBits := math.Log2(float64(passwordAlphabetSize(runes)))
+	dictionaryBits := math.Log2(float64(len(dictionary))) + 1
+
+	var entropy float64
+	for i := 0; i < len(runes); {
+		if word := longestDictionaryMatch(normalized[i:], dictionary); word > 0 {
+			entropy += dictionaryBits
+			i += word
+			continue
+		}
+		if i > 0 && predictableAfter(runes[i-1], runes[i]) {
+			entropy++
+		} else {
+			entropy += bruteForceBits
+		}
+		i++
+	}
+
+	score := 0
+	for _, bits := range passwordScoreBits {
+		if entropy >= bits {
+			score++
+		}
+	}
+	return PasswordStrength{Score: score, Entropy: entropy}
+}
+
+// passwordDictionary - частые пароли и значимые части userInputs (от 3 символов)
+func passwordDictionary(userInputs []string) map[string]struct{} {
+	dictionary := make(map[string]struct{}, len(commonPasswordWords)+len(userInputs))
+	for _, word := range commonPasswordWords {
+		dictionary[word] = struct{}{}
+	}
+	for _, input := range userInputs {
+		for _, part := range PersonalInfoParts(input) {
+			dictionary[part] = struct{}{}
+		}
+	}
+	return dictionary
+}
+
+// PersonalInfoParts разбивает email или имя на слова от 3 символов в нижнем регистре
+// Для email берется только часть до @: домен обычно общий (gmail.com)
+func PersonalInfoParts(value string) []string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if local, _, ok := strings.Cut(value, "@"); ok {
+		value = local
+	}
+
+	var parts []string
+	for _, part := range strings.FieldsFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(part)) >= 3 {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// longestDictionaryMatch возвращает длину самого длинного словарного слова в начале s, 0 - совпадений нет
+func longestDictionaryMatch(s []rune, dictionary map[string]struct{}) int {
+	for length := len(s); length >= 3; length-- {
+		if _, ok := dictionary[string(s[:length])]; ok {
+			return length
+		}
+	}
+	return 0
+}
+
+// predictableAfter - символ повторяет предыдущий, продолжает последовательность (abc, 321) или соседнюю клавишу
+func predictableAfter(prev, cur rune) bool {
+	prev, cur = unicode.ToLower(prev), unicode.ToLower(cur)
+	if cur == prev || cur == prev+1 || cur == prev-1 {
+		return true
+	}
+	for _, row := range keyboardRows {
+		row := []rune(row)
+		for i := 0; i+1 < len(row); i++ {
+			if (row[i] == prev && row[i+1] == cur) || (row[i] == cur && row[i+1] == prev) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// passwordAlphabetSize - размер алфавита перебора по классам символов пароля
+func passwordAlphabetSize(runes []rune) int {
+	var lower, upper, digit, symbol, other bool
+	for _, r := range runes {
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r < unicode.MaxASCII:
+			symbol = true
+		default:
+			other = true
+		}
+	}
+
+	size := 0
+	for _, class := range []struct {
+		present bool
+		size    int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 66}} {
+		if class.present {
+			size += class.size
+		}
+	}
+	return size
+}
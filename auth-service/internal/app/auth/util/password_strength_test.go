@@ -0,0 +1,59 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimatePasswordStrength_WeakPasswords(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+	}{
+		{"common password with digits", "password123"},
+		{"leet common password", "P@ssw0rd!"},
+		{"keyboard row", "qwertyuiop"},
+		{"repeated character", "aaaaaaaaaaaa"},
+		{"digit sequence", "12345678"},
+		{"letter sequence", "abcdefgh"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strength := EstimatePasswordStrength(tt.password)
+			assert.LessOrEqual(t, strength.Score, 1, "entropy %.1f", strength.Entropy)
+		})
+	}
+}
+
+func TestEstimatePasswordStrength_StrongPasswords(t *testing.T) {
+	for _, password := range []string{"correct horse battery staple", "kX9#mQ2vLp", "berries-and-cream-42"} {
+		t.Run(password, func(t *testing.T) {
+			assert.Equal(t, 4, EstimatePasswordStrength(password).Score)
+		})
+	}
+}
+
+func TestEstimatePasswordStrength_UserInputsAreDictionaryWords(t *testing.T) {
+	// Arrange
+	password := "ivanpetrov1"
+
+	// Act
+	withoutInputs := EstimatePasswordStrength(password)
+	withInputs := EstimatePasswordStrength(password, "ivan.petrov@example.com", "Ivan Petrov")
+
+	// Assert
+	assert.Less(t, withInputs.Entropy, withoutInputs.Entropy)
+	assert.LessOrEqual(t, withInputs.Score, 1)
+}
+
+func TestEstimatePasswordStrength_Empty(t *testing.T) {
+	assert.Equal(t, PasswordStrength{}, EstimatePasswordStrength(""))
+}
+
+func TestPersonalInfoParts(t *testing.T) {
+	assert.Equal(t, []string{"ivan", "petrov"}, PersonalInfoParts("Ivan.Petrov@gmail.com"))
+	assert.Equal(t, []string{"anna", "smirnova"}, PersonalInfoParts(" Anna  Smirnova "))
+	assert.Empty(t, PersonalInfoParts("Li"))
+}
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -166,3 +167,131 @@ func TestHashPassword_Consistency(t *testing.T) {
 		assert.True(t, CheckPassword(password, hash))
 	}
 }
+
+// ==================== Password Hashing Policy Tests ====================
+
+// newTestPolicy - политика с минимальными параметрами, чтобы тесты выполнялись быстро
+func newTestPolicy(algorithm string) PasswordPolicy {
+	return PasswordPolicy{
+		Algorithm:         algorithm,
+		BcryptCost:        4,
+		Argon2Memory:      1024,
+		Argon2Iterations:  1,
+		Argon2Parallelism: 1,
+		ScryptN:           1024,
+		ScryptR:           8,
+		ScryptP:           1,
+	}
+}
+
+func TestPasswordHashing_RoundTrip(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		prefix    string
+	}{
+		{PasswordAlgorithmBcrypt, "$2a$04$"},
+		{PasswordAlgorithmArgon2id, "$argon2id$v=19$m=1024,t=1,p=1$"},
+		{PasswordAlgorithmScrypt, "$scrypt$ln=10,r=8,p=1$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			h, err := NewPasswordHashing(newTestPolicy(tt.algorithm))
+			require.NoError(t, err)
+
+			hash, err := h.Hash("secret123")
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(hash, tt.prefix), hash)
+
+			ok, rehash := h.Verify("secret123", hash)
+			assert.True(t, ok)
+			assert.False(t, rehash)
+
+			ok, rehash = h.Verify("secret124", hash)
+			assert.False(t, ok)
+			assert.False(t, rehash)
+		})
+	}
+}
+
+func TestPasswordHashing_VerifiesHashesOfOtherAlgorithms(t *testing.T) {
+	// Arrange
+	old, err := NewPasswordHashing(newTestPolicy(PasswordAlgorithmBcrypt))
+	require.NoError(t, err)
+	current, err := NewPasswordHashing(newTestPolicy(PasswordAlgorithmArgon2id))
+	require.NoError(t, err)
+
+	hash, err := old.Hash("secret123")
+	require.NoError(t, err)
+
+	// Act
+	ok, rehash := current.Verify("secret123", hash)
+
+	// Assert
+	assert.True(t, ok)
+	assert.True(t, rehash, "hash of previous algorithm must be migrated")
+}
+
+func TestPasswordHashing_NeedsRehashOnParameterChange(t *testing.T) {
+	for _, algorithm := range []string{PasswordAlgorithmBcrypt, PasswordAlgorithmArgon2id, PasswordAlgorithmScrypt} {
+		t.Run(algorithm, func(t *testing.T) {
+			old, err := NewPasswordHashing(newTestPolicy(algorithm))
+			require.NoError(t, err)
+			hash, err := old.Hash("secret123")
+			require.NoError(t, err)
+
+			policy := newTestPolicy(algorithm)
+			policy.BcryptCost = 5
+			policy.Argon2Iterations = 2
+			policy.ScryptN = 2048
+			current, err := NewPasswordHashing(policy)
+			require.NoError(t, err)
+
+			ok, rehash := current.Verify("secret123", hash)
+			assert.True(t, ok, "hash must be verified with parameters stored in it")
+			assert.True(t, rehash)
+		})
+	}
+}
+
+func TestPasswordHashing_RejectsMalformedHashes(t *testing.T) {
+	h, err := NewPasswordHashing(newTestPolicy(PasswordAlgorithmArgon2id))
+	require.NoError(t, err)
+
+	for _, hash := range []string{
+		"",
+		"!",
+		"$md5$abc",
+		"$argon2id$v=19$m=1024,t=1,p=1$c2FsdA",
+		"$argon2id$v=18$m=1024,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=0,p=1$c2FsdA$a2V5",
+		"$scrypt$ln=99,r=8,p=1$c2FsdA$a2V5",
+		"$scrypt$ln=10,r=8,p=1$not base64$a2V5",
+	} {
+		ok, rehash := h.Verify("secret123", hash)
+		assert.False(t, ok, hash)
+		assert.False(t, rehash, hash)
+	}
+}
+
+func TestNewPasswordHashing_InvalidPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(p *PasswordPolicy)
+	}{
+		{"unknown algorithm", func(p *PasswordPolicy) { p.Algorithm = "md5" }},
+		{"bcrypt cost too low", func(p *PasswordPolicy) { p.BcryptCost = 3 }},
+		{"argon2 zero iterations", func(p *PasswordPolicy) { p.Argon2Iterations = 0 }},
+		{"scrypt N not power of two", func(p *PasswordPolicy) { p.ScryptN = 1000 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := newTestPolicy(PasswordAlgorithmBcrypt)
+			tt.modify(&policy)
+
+			_, err := NewPasswordHashing(policy)
+			assert.Error(t, err)
+		})
+	}
+}
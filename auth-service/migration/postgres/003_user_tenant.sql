@@ -0,0 +1,6 @@
+-- Мультитенантность: пользователь зарегистрирован в одном магазине
+-- tenant_id попадает в JWT токен и определяет, данные какого магазина видит пользователь
+-- Существующие пользователи относятся к магазину по умолчанию
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+
+CREATE INDEX IF NOT EXISTS idx_users_tenant_id ON users(tenant_id);
@@ -0,0 +1,12 @@
+-- Refresh токены "запомнить меня" (используются, если токены хранятся в PostgreSQL)
+-- Срок продлевается при каждом обновлении, но не дальше максимального возраста сессии
+CREATE TABLE IF NOT EXISTS remember_tokens (
+    token TEXT PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    session_started_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_remember_tokens_user_id ON remember_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_remember_tokens_expires_at ON remember_tokens(expires_at);
@@ -0,0 +1,3 @@
+-- Публичный профиль пользователя: имя уже хранится в name, добавляется аватар
+-- Профиль (без email) рассылается другим сервисам событием USER_UPDATED
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_url TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+-- Провижининг пользователей: отключенные аккаунты сохраняются, но не могут входить
+-- Повторная активация очищает deactivated_at
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMP;
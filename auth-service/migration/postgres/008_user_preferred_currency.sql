@@ -0,0 +1,3 @@
+-- Предпочитаемая валюта заказов пользователя (ISO 4217), пусто - валюта магазина по умолчанию
+-- Передается в JWT (preferred_currency) и используется Orders Service и Background Worker
+ALTER TABLE users ADD COLUMN IF NOT EXISTS preferred_currency VARCHAR(3) NOT NULL DEFAULT '';
@@ -0,0 +1,12 @@
+-- Роли пользователя в других магазинах: администратор магазина A может быть покупателем магазина B
+-- Роль в магазине регистрации по-прежнему хранится в users.role_id
+-- Роли попадают в JWT (tenant_roles) и применяются, когда запрос выполняется в этом магазине (X-Tenant-ID)
+CREATE TABLE IF NOT EXISTS user_tenant_roles (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    tenant_id VARCHAR(64) NOT NULL,
+    role_id INTEGER NOT NULL REFERENCES roles(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, tenant_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_tenant_roles_tenant_id ON user_tenant_roles(tenant_id);
@@ -0,0 +1,27 @@
+-- Вход от имени пользователя для сотрудников поддержки: разрешение user.impersonate
+-- Роль support видит каталог и заказы, а разбирает обращения под токеном имперсонации
+INSERT INTO roles (name, description) VALUES
+    ('support', 'Сотрудник поддержки')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO permissions (code, description) VALUES
+    ('user.impersonate', 'Вход от имени пользователя')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO roles_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE r.name = 'support' AND p.code IN (
+    'product.read',
+    'user.read',
+    'order.read',
+    'user.impersonate'
+)
+ON CONFLICT DO NOTHING;
+
+-- Администратор получает все разрешения, включая новое
+INSERT INTO roles_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE r.name = 'admin' AND p.code = 'user.impersonate'
+ON CONFLICT DO NOTHING;
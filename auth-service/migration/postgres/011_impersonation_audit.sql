@@ -0,0 +1,14 @@
+-- Журнал выдачи токенов имперсонации: кто, от имени кого и в каком магазине получил токен
+-- Запись создается до выдачи токена; если журнал недоступен, токен не выдается
+CREATE TABLE IF NOT EXISTS impersonation_audit (
+    id UUID PRIMARY KEY,
+    actor_id UUID NOT NULL,
+    actor_email VARCHAR(255) NOT NULL,
+    user_id UUID NOT NULL,
+    tenant_id VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_impersonation_audit_actor_id ON impersonation_audit(actor_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_impersonation_audit_user_id ON impersonation_audit(user_id, created_at DESC);
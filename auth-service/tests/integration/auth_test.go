@@ -15,8 +15,10 @@ import (
 	"augustberries/auth-service/internal/app/auth/entity"
 	"augustberries/auth-service/internal/app/auth/handler"
 	"augustberries/auth-service/internal/app/auth/repository"
+	"augustberries/auth-service/internal/app/auth/repository/mocks"
 	"augustberries/auth-service/internal/app/auth/service"
 	"augustberries/auth-service/internal/app/auth/util"
+	"augustberries/pkg/httpmw"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
@@ -64,14 +66,18 @@ func (s *AuthIntegrationTestSuite) SetupSuite() {
 	tokenRepo := repository.NewRedisTokenRepository(s.redisClient)
 
 	// Инициализируем сервис
-	authService := service.NewAuthService(userRepo, roleRepo, tokenRepo, s.jwtManager)
+	authService := service.NewAuthService(userRepo, roleRepo, tokenRepo, s.jwtManager, mocks.NewMockMessagePublisher(), nil)
 
 	// Инициализируем handlers
 	authHandler := handler.NewAuthHandler(authService)
+	securityHandler := handler.NewSecurityHandler(service.NewSecurityService(userRepo, tokenRepo, repository.NewRedisLoginAttemptRepository(s.redisClient)))
+	provisioningHandler := handler.NewProvisioningHandler(service.NewProvisioningService(userRepo, roleRepo, tokenRepo, mocks.NewMockMessagePublisher()))
+	roleHandler := handler.NewRoleHandler(service.NewRoleService(roleRepo))
+	introspectionHandler := handler.NewIntrospectionHandler(authService, nil)
 	authMiddleware := handler.NewAuthMiddleware(authService)
 
 	// Настраиваем router
-	s.router = handler.SetupRoutes(authHandler, authMiddleware)
+	s.router = handler.SetupRoutes(authHandler, securityHandler, provisioningHandler, roleHandler, introspectionHandler, authMiddleware, httpmw.CORSConfig{})
 
 	// Применяем миграции и seed данные
 	s.setupDatabase(ctx)
@@ -127,22 +133,16 @@ func (s *AuthIntegrationTestSuite) setupDatabase(ctx context.Context) {
 			role_id INTEGER NOT NULL REFERENCES roles(id),
 			created_at TIMESTAMP NOT NULL DEFAULT NOW()
 		)`,
-		// Seed roles
-		`INSERT INTO roles (name, description) VALUES 
-			('user', 'Regular user'),
-			('admin', 'Administrator')
-		ON CONFLICT (name) DO NOTHING`,
-		// Seed permissions
-		`INSERT INTO permissions (code, description) VALUES 
-			('product.read', 'Read products'),
-			('order.create', 'Create orders')
-		ON CONFLICT (code) DO NOTHING`,
 	}
 
 	for _, query := range queries {
 		_, err := s.db.Exec(ctx, query)
 		require.NoError(s.T(), err)
 	}
+
+	// Роли и разрешения создаются тем же заполнением, что и при запуске сервиса
+	err := service.NewRoleSeeder(repository.NewRoleRepository(s.db)).Seed(ctx)
+	require.NoError(s.T(), err)
 }
 
 func (s *AuthIntegrationTestSuite) cleanupDatabase(ctx context.Context) {
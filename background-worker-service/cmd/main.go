@@ -7,17 +7,26 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"augustberries/background-worker-service/internal/app/background-worker/config"
+	"augustberries/background-worker-service/internal/app/background-worker/entity"
 	"augustberries/background-worker-service/internal/app/background-worker/handler"
+	"augustberries/background-worker-service/internal/app/background-worker/infrastructure"
 	"augustberries/background-worker-service/internal/app/background-worker/processor"
 	"augustberries/background-worker-service/internal/app/background-worker/repository"
 	"augustberries/background-worker-service/internal/app/background-worker/service"
+	"augustberries/pkg/authcookie"
+	"augustberries/pkg/jwtclaims"
+	"augustberries/pkg/kafka"
+	"augustberries/pkg/lock"
+	"augustberries/pkg/money"
+	"augustberries/pkg/recovery"
+	"augustberries/pkg/redis"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-	"github.com/redis/go-redis/v9"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -32,6 +41,16 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Паники в обработчиках и фоновых задачах отправляются в Sentry, если задан SENTRY_DSN
+	if err := recovery.ConfigureFromEnv(); err != nil {
+		log.Fatalf("Failed to configure panic reporting: %v", err)
+	}
+
+	// Точность валют применяется до любых конвертаций
+	if err := money.Currencies.Configure(cfg.ExchangeAPI.Precision); err != nil {
+		log.Fatalf("Failed to configure currency precision: %v", err)
+	}
+
 	// Создаем основной контекст приложения
 	ctx := context.Background()
 
@@ -55,6 +74,8 @@ func main() {
 	// === ИНИЦИАЛИЗАЦИЯ РЕПОЗИТОРИЕВ ===
 	orderRepo := repository.NewOrderRepository(db)
 	exchangeRateRepo := repository.NewExchangeRateRepository(redisClient, cfg.Redis.TTL)
+	// Закрепленные администратором курсы хранятся в той же БД Redis, что и курсы из API
+	ratePinRepo := repository.NewRatePinRepository(redisClient)
 	log.Println("Repositories initialized")
 
 	// === ИНИЦИАЛИЗАЦИЯ API КЛИЕНТА ===
@@ -62,40 +83,110 @@ func main() {
 	exchangeAPIClient := service.NewExchangeRateAPIClient(
 		cfg.ExchangeAPI.URL,
 		cfg.ExchangeAPI.Timeout,
+		cfg.ExchangeAPI.Currencies,
 	)
 	log.Println("Exchange Rate API Client initialized")
 
 	// === ИНИЦИАЛИЗАЦИЯ СЕРВИСОВ ===
-	// Exchange Rate Service использует API клиент для получения данных
+	// Exchange Rate Service использует API клиент для получения данных, закрепленные курсы важнее API
 	exchangeRateSvc := service.NewExchangeRateService(
 		exchangeRateRepo,
 		exchangeAPIClient,
+		ratePinRepo,
 	)
 
 	orderProcessingSvc := service.NewOrderProcessingService(
 		orderRepo,
 		exchangeRateSvc,
 	)
+	// Заказы без предпочитаемой валюты покупателя конвертируются в валюту по умолчанию
+	orderProcessingSvc.SetDefaultTargetCurrency(cfg.ExchangeAPI.DefaultCurrency)
+
+	// Письма о заказе отрисовываются из встроенных шаблонов; пока нет почтового провайдера, они пишутся в лог
+	emailRenderer, err := service.NewEmailRenderer(cfg.Email.DefaultLocale)
+	if err != nil {
+		log.Fatalf("Failed to load email templates: %v", err)
+	}
+	orderNotifier := service.NewOrderNotifier(orderRepo, emailRenderer, infrastructure.NewLogEmailSender())
+	log.Printf("Email templates loaded (locales: %s, default: %s, notifications: %t)",
+		strings.Join(emailRenderer.Locales(), ","), emailRenderer.DefaultLocale(), cfg.Email.Enabled)
 	log.Println("Services initialized")
 
 	// === ИНИЦИАЛИЗАЦИЯ KAFKA CONSUMER ===
+	// Пока PostgreSQL или Redis недоступны, consumer не читает и не коммитит сообщения
+	consumerGate := kafka.NewGate()
+	dependencyMonitor := processor.NewDependencyMonitor(consumerGate, cfg.Kafka.HealthCheckInterval,
+		processor.DependencyCheck{Name: "database", Check: func(ctx context.Context) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		}},
+		processor.DependencyCheck{Name: "redis", Check: func(ctx context.Context) error {
+			return redisClient.Ping(ctx).Err()
+		}},
+	)
+	dependencyMonitor.Start(ctx)
+	defer dependencyMonitor.Stop()
+
+	consumer := kafka.NewConsumer(kafka.ConsumerConfig{
+		Brokers:  cfg.Kafka.Brokers,
+		Topics:   cfg.Kafka.Topics,
+		GroupID:  cfg.Kafka.GroupID,
+		Service:  "background-worker",
+		MinBytes: cfg.Kafka.MinBytes,
+		MaxBytes: cfg.Kafka.MaxBytes,
+		Gate:     consumerGate,
+	})
+
+	// Сообщения, которые не удалось обработать после повторов, уходят в DLQ
+	var deadLetter kafka.Producer
+	if cfg.Kafka.DLQTopic != "" {
+		deadLetter, err = kafka.NewProducer(kafka.ProducerConfig{
+			Brokers: cfg.Kafka.Brokers,
+			Topic:   cfg.Kafka.DLQTopic,
+			Service: "background-worker",
+			Acks:    "all",
+		})
+		if err != nil {
+			log.Fatalf("Failed to create DLQ producer: %v", err)
+		}
+		defer deadLetter.Close()
+	}
+
 	kafkaConsumer := processor.NewKafkaConsumer(
-		cfg.Kafka.Brokers,
-		cfg.Kafka.Topic,
-		cfg.Kafka.GroupID,
-		cfg.Kafka.MinBytes,
-		cfg.Kafka.MaxBytes,
+		consumer,
+		deadLetter,
 		orderProcessingSvc,
 		exchangeRateSvc,
+		cfg.Kafka.BatchSize,
+		cfg.Kafka.FlushInterval,
+		dependencyMonitor,
 	)
 
+	if cfg.Email.Enabled {
+		kafkaConsumer.SetNotifier(orderNotifier)
+	}
+
+	// События заказов consumer обрабатывает сам; обработчики событий других топиков
+	// регистрируются здесь через kafkaConsumer.Handle до запуска
+
+	// Смены статуса заказов ведут проекцию для отчета о сроках выполнения (SLA)
+	statusAnalyticsSvc := service.NewStatusAnalyticsService(repository.NewOrderStatusRepository(db))
+	kafkaConsumer.Handle(entity.EventTypeOrderStatusChanged, statusAnalyticsSvc.HandleMessage)
+
 	// Запускаем Kafka consumer
 	kafkaConsumer.Start(ctx)
 	defer kafkaConsumer.Stop()
-	log.Printf("Kafka consumer started (topic: %s, group: %s)", cfg.Kafka.Topic, cfg.Kafka.GroupID)
+	log.Printf("Kafka consumer started (topics: %s, events: %s, group: %s, dlq: %s, batch: %d, flush: %s)",
+		strings.Join(cfg.Kafka.Topics, ","), strings.Join(kafkaConsumer.EventTypes(), ","),
+		cfg.Kafka.GroupID, cfg.Kafka.DLQTopic, cfg.Kafka.BatchSize, cfg.Kafka.FlushInterval)
 
 	// === ИНИЦИАЛИЗАЦИЯ CRON SCHEDULER ===
 	cronScheduler := processor.NewCronScheduler(exchangeRateSvc)
+	// При нескольких репликах worker задачу выполняет одна: блокировка в Redis
+	cronScheduler.SetLocker(lock.New(redisClient, "background-worker"), cfg.CronSchedule.LockTTL)
 
 	// Запускаем cron для периодического обновления курсов валют
 	if err := cronScheduler.Start(ctx, cfg.CronSchedule.UpdateRates); err != nil {
@@ -105,10 +196,31 @@ func main() {
 	log.Printf("Cron scheduler started (schedule: %s)", cfg.CronSchedule.UpdateRates)
 
 	// === ИНИЦИАЛИЗАЦИЯ HEALTHCHECK HTTP СЕРВЕРА ===
-	healthHandler := handler.NewHealthCheckHandler(db, redisClient, exchangeRateSvc)
+	healthHandler := handler.NewHealthCheckHandler(db, redisClient, exchangeRateSvc, dependencyMonitor.State)
+
+	// Admin endpoint'ы для ручной обработки (требуют JWT с ролью admin)
+	authMiddleware := handler.NewAuthMiddleware(cfg.JWT.Secret)
+	authMiddleware.SetExpectedClaims(jwtclaims.Expected{Issuer: cfg.JWT.Issuer, Audience: cfg.JWT.Audience})
+	// Токен из httpOnly cookie для SPA (AUTH_COOKIES); изменяющие запросы с cookie требуют X-CSRF-Token
+	cookies, err := authcookie.FromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure auth cookies: %v", err)
+	}
+	authMiddleware.SetCookies(cookies)
+	// Повтор событий читает топик вне consumer group и не сдвигает ее offset'ы
+	replaySvc := service.NewReplayService(kafka.NewReplayer(kafka.ReplayConfig{
+		Brokers:  cfg.Kafka.Brokers,
+		Topic:    cfg.Kafka.Topic,
+		MaxBytes: cfg.Kafka.MaxBytes,
+	}), orderProcessingSvc)
+	adminHandler := handler.NewAdminHandler(orderProcessingSvc, exchangeRateSvc, replaySvc, orderNotifier, authMiddleware)
 
 	mux := http.NewServeMux()
 	healthHandler.RegisterRoutes(mux)
+	adminHandler.RegisterRoutes(mux)
+	// Курсы валют для предпросмотра конвертации заказа в Orders Service
+	handler.NewRatesHandler(exchangeRateSvc).RegisterRoutes(mux)
+	handler.NewAnalyticsHandler(statusAnalyticsSvc, authMiddleware).RegisterRoutes(mux)
 
 	// Prometheus metrics endpoint
 	mux.Handle("/metrics", promhttp.Handler())
@@ -135,6 +247,15 @@ func main() {
 	log.Println("  - GET http://localhost:8080/health/readiness")
 	log.Println("  - GET http://localhost:8080/health/liveness")
 	log.Println("  - GET http://localhost:8080/metrics")
+	log.Println("  - POST http://localhost:8080/admin/reprocess/{orderID} (admin)")
+	log.Println("  - POST http://localhost:8080/admin/rates/refresh (admin)")
+	log.Println("  - GET/POST http://localhost:8080/admin/rates/pins (admin)")
+	log.Println("  - DELETE http://localhost:8080/admin/rates/pins/{from}/{to} (admin)")
+	log.Println("  - GET http://localhost:8080/admin/rates/pins/audit (admin)")
+	log.Println("  - POST http://localhost:8080/admin/events/replay (admin)")
+	log.Println("  - GET http://localhost:8080/admin/events/offsets (admin)")
+	log.Println("  - GET http://localhost:8080/admin/emails/{template}/preview (admin)")
+	log.Println("  - GET http://localhost:8080/admin/analytics/order-status (admin)")
 
 	// === ЗАПУСК ЗАВЕРШЕН ===
 	log.Println("Background Worker Service is running")
@@ -201,17 +322,19 @@ func connectDB(cfg config.DatabaseConfig) (*gorm.DB, error) {
 }
 
 // connectRedis устанавливает соединение с Redis
-func connectRedis(ctx context.Context, cfg config.RedisConfig) (*redis.Client, error) {
-	client := redis.NewClient(&redis.Options{
-		Addr:         cfg.Address(),
-		Password:     cfg.Password,
-		DB:           cfg.DB,
-		DialTimeout:  5 * time.Second,
-		ReadTimeout:  3 * time.Second,
-		WriteTimeout: 3 * time.Second,
-		PoolSize:     10,
-		MinIdleConns: 5,
-	})
+// Режим подключения (standalone, sentinel, cluster) и TLS задаются конфигурацией
+func connectRedis(ctx context.Context, cfg config.RedisConfig) (redis.Client, error) {
+	clientCfg := cfg.ClientConfig()
+	clientCfg.DialTimeout = 5 * time.Second
+	clientCfg.ReadTimeout = 3 * time.Second
+	clientCfg.WriteTimeout = 3 * time.Second
+	clientCfg.PoolSize = 10
+	clientCfg.MinIdleConns = 5
+
+	client, err := redis.NewClient(clientCfg)
+	if err != nil {
+		return nil, err
+	}
 
 	// Проверяем соединение с retry logic
 	for i := 0; i < 10; i++ {
@@ -3,8 +3,12 @@ package config
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
+
+	"augustberries/pkg/redis"
 )
 
 // Config содержит все настройки приложения Background Worker Service
@@ -15,6 +19,8 @@ type Config struct {
 	Kafka        KafkaConfig
 	ExchangeAPI  ExchangeAPIConfig
 	CronSchedule CronScheduleConfig
+	JWT          JWTConfig
+	Email        EmailConfig
 }
 
 // DatabaseConfig - настройки подключения к PostgreSQL Orders Service
@@ -31,21 +37,33 @@ type DatabaseConfig struct {
 // RedisConfig - настройки подключения к Redis
 // Используется для хранения курсов валют с TTL
 type RedisConfig struct {
-	Host     string        // Хост Redis
-	Port     string        // Порт Redis
-	Password string        // Пароль Redis
-	DB       int           // Номер БД Redis (обычно 0)
-	TTL      time.Duration // TTL для курсов валют (30-60 минут)
+	Host       string        // Хост Redis
+	Port       string        // Порт Redis
+	Password   string        // Пароль Redis
+	DB         int           // Номер БД Redis (обычно 0)
+	TTL        time.Duration // TTL для курсов валют (30-60 минут)
+	Mode       string        // Режим подключения: standalone, sentinel или cluster
+	Addrs      []string      // Адреса Sentinel или узлов кластера (по умолчанию Host:Port)
+	MasterName string        // Имя master для Sentinel
+	TLS        bool          // Подключаться по TLS
 }
 
 // KafkaConfig - настройки Kafka для подписки на события
-// Слушает топик order_events для обработки ORDER_CREATED
+// Слушает топик order_events для обработки ORDER_CREATED и топики других событий с обработчиками
 type KafkaConfig struct {
 	Brokers  []string // Список брокеров Kafka (формат: host:port)
-	Topic    string   // Топик для прослушивания (order_events)
+	Topic    string   // Топик событий заказов (order_events), из него же повторяются события
+	Topics   []string // Топик заказов и дополнительные топики (KAFKA_EXTRA_TOPICS); события без обработчика пропускаются
 	GroupID  string   // ID группы потребителей для распределения нагрузки
 	MinBytes int      // Минимум байт для fetch запроса
 	MaxBytes int      // Максимум байт для fetch запроса
+	DLQTopic string   // Топик для необработанных сообщений (пустой - DLQ отключен)
+	// BatchSize - сколько событий обрабатывать одной пачкой (1 - обработка по одному)
+	BatchSize int
+	// HealthCheckInterval - период проверки PostgreSQL и Redis; пока они недоступны, чтение на паузе
+	HealthCheckInterval time.Duration
+	// FlushInterval - сколько ждать заполнения пачки с момента первого сообщения
+	FlushInterval time.Duration
 }
 
 // ExchangeAPIConfig - настройки для внешнего API валют
@@ -54,11 +72,32 @@ type ExchangeAPIConfig struct {
 	URL     string // URL API для получения курсов (например, exchangerate-api.com)
 	APIKey  string // API ключ для аутентификации (если требуется)
 	Timeout int    // Таймаут запроса в секундах
+	// Currencies - валюты, курсы которых запрашиваются и хранятся (пустой список - все валюты из ответа)
+	Currencies []string
+	// Precision - точность и округление валют поверх встроенного реестра pkg/money ("JPY:0:half_even,RUB:2")
+	Precision string
+	// DefaultCurrency - валюта конвертации заказов, если покупатель не выбрал предпочитаемую
+	DefaultCurrency string
 }
 
 // CronScheduleConfig - настройки расписания cron задач
 type CronScheduleConfig struct {
-	UpdateRates string // Расписание обновления курсов валют (например, "0 */30 * * * *" каждые 30 минут)
+	UpdateRates string        // Расписание обновления курсов валют (например, "0 */30 * * * *" каждые 30 минут)
+	LockTTL     time.Duration // Время, на которое запуск задачи закрепляется за одной репликой (меньше интервала расписания)
+}
+
+// JWTConfig - настройки для проверки JWT токенов администраторов
+// Используется для защиты admin endpoint'ов HTTP сервера
+type JWTConfig struct {
+	Secret   string // Секретный ключ для проверки JWT токенов (должен совпадать с Auth Service)
+	Issuer   string // Ожидаемый iss (JWT_ISSUER Auth Service), пустой - не проверяется
+	Audience string // Ожидаемый aud (JWT_AUDIENCE Auth Service), пустой - не проверяется
+}
+
+// EmailConfig - настройки писем покупателям о заказе
+type EmailConfig struct {
+	Enabled       bool   // Отправлять письма по событиям заказов; предпросмотр доступен и без них
+	DefaultLocale string // Локаль писем и запасная локаль предпросмотра (ru, en)
 }
 
 // Load загружает конфигурацию из переменных окружения
@@ -77,28 +116,55 @@ func Load() (*Config, error) {
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		},
 		Redis: RedisConfig{
-			Host:     getEnv("REDIS_HOST", "localhost"),
-			Port:     getEnv("REDIS_PORT", "6379"),
-			Password: getEnv("REDIS_PASSWORD", ""),
-			DB:       getEnvInt("REDIS_DB", 2), // Отдельная БД для курсов валют
-			TTL:      time.Duration(ttlMinutes) * time.Minute,
+			Host:       getEnv("REDIS_HOST", "localhost"),
+			Port:       getEnv("REDIS_PORT", "6379"),
+			Password:   getEnv("REDIS_PASSWORD", ""),
+			DB:         getEnvInt("REDIS_DB", 2), // Отдельная БД для курсов валют
+			TTL:        time.Duration(ttlMinutes) * time.Minute,
+			Mode:       getEnv("REDIS_MODE", redis.ModeStandalone),
+			Addrs:      redis.ParseAddrs(getEnv("REDIS_ADDRS", ""), getEnv("REDIS_HOST", "localhost")+":"+getEnv("REDIS_PORT", "6379")),
+			MasterName: getEnv("REDIS_MASTER_NAME", ""),
+			TLS:        getEnv("REDIS_TLS", "false") == "true",
 		},
 		Kafka: KafkaConfig{
 			Brokers:  []string{getEnv("KAFKA_BROKERS", "localhost:9092")},
 			Topic:    getEnv("KAFKA_TOPIC", "order_events"),
+			Topics:   getEnvTopics("KAFKA_EXTRA_TOPICS", getEnv("KAFKA_TOPIC", "order_events")),
 			GroupID:  getEnv("KAFKA_GROUP_ID", "background-worker-group"),
 			MinBytes: getEnvInt("KAFKA_MIN_BYTES", 1),    // 1 byte minimum
 			MaxBytes: getEnvInt("KAFKA_MAX_BYTES", 10e6), // 10MB maximum
+			DLQTopic: getEnv("KAFKA_DLQ_TOPIC", "order_events_dlq"),
+			// Пакетная обработка сокращает число обращений к БД при всплесках нагрузки
+			BatchSize:     getEnvInt("KAFKA_BATCH_SIZE", 1),
+			FlushInterval: time.Duration(getEnvInt("KAFKA_FLUSH_INTERVAL_MS", 500)) * time.Millisecond,
+
+			HealthCheckInterval: time.Duration(getEnvInt("KAFKA_HEALTH_CHECK_INTERVAL_MS", 5000)) * time.Millisecond,
 		},
 		ExchangeAPI: ExchangeAPIConfig{
 			// Используем бесплатный API exchangerate-api.com
 			URL:     getEnv("EXCHANGE_API_URL", "https://api.exchangerate-api.com/v4/latest/USD"),
 			APIKey:  getEnv("EXCHANGE_API_KEY", ""), // Для бесплатной версии ключ не нужен
 			Timeout: getEnvInt("EXCHANGE_API_TIMEOUT", 10),
+			// Например USD,EUR,RUB - уменьшает ответ API и число ключей в Redis
+			Currencies: getEnvList("EXCHANGE_API_CURRENCIES"),
+			Precision:  getEnv("CURRENCY_PRECISION", ""),
+
+			DefaultCurrency: getEnv("DEFAULT_CURRENCY", "RUB"),
 		},
 		CronSchedule: CronScheduleConfig{
 			// По умолчанию обновляем курсы каждые 30 минут
 			UpdateRates: getEnv("CRON_UPDATE_RATES", "0 */30 * * * *"),
+			LockTTL:     time.Duration(getEnvInt("CRON_LOCK_TTL_SECONDS", 60)) * time.Second,
+		},
+		JWT: JWTConfig{
+			// JWT Secret должен совпадать с Auth Service для валидации токенов
+			Secret:   getEnv("JWT_SECRET", "your-secret-key-change-this-in-production"),
+			Issuer:   getEnv("JWT_ISSUER", ""),
+			Audience: getEnv("JWT_AUDIENCE", ""),
+		},
+		Email: EmailConfig{
+			Enabled:       getEnv("EMAIL_NOTIFICATIONS_ENABLED", "true") == "true",
+			DefaultLocale: getEnv("EMAIL_DEFAULT_LOCALE", "ru"),
 		},
 	}, nil
 }
@@ -116,6 +182,19 @@ func (c *RedisConfig) Address() string {
 	return c.Host + ":" + c.Port
 }
 
+// ClientConfig возвращает настройки клиента для pkg/redis
+func (c *RedisConfig) ClientConfig() redis.Config {
+	return redis.Config{
+		Mode:       c.Mode,
+		Addrs:      c.Addrs,
+		Password:   c.Password,
+		DB:         c.DB,
+		MasterName: c.MasterName,
+		TLS:        c.TLS,
+		Service:    "background-worker",
+	}
+}
+
 // getEnv получает значение переменной окружения или возвращает значение по умолчанию
 // Используется для гибкой конфигурации через environment variables
 func getEnv(key, defaultValue string) string {
@@ -125,6 +204,28 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvList получает список через запятую в верхнем регистре, пустые элементы пропускаются
+func getEnvList(key string) []string {
+	var items []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.ToUpper(strings.TrimSpace(item)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getEnvTopics возвращает основной топик и топики из списка через запятую без повторов
+func getEnvTopics(key, mainTopic string) []string {
+	topics := []string{mainTopic}
+	for _, topic := range strings.Split(os.Getenv(key), ",") {
+		if topic = strings.TrimSpace(topic); topic != "" && !slices.Contains(topics, topic) {
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
+
 // getEnvInt получает значение переменной окружения как int
 func getEnvInt(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderStatusTransition - смена статуса заказа из ORDER_STATUS_CHANGED
+// Хранится в БД orders_service (таблица order_status_transitions), заполняется только worker
+type OrderStatusTransition struct {
+	ID             uuid.UUID   `gorm:"type:uuid;primaryKey"`
+	OrderID        uuid.UUID   `gorm:"type:uuid;not null"`
+	TenantID       string      `gorm:"type:varchar(64);not null"`
+	FromStatus     OrderStatus `gorm:"type:varchar(50);not null"`
+	ToStatus       OrderStatus `gorm:"type:varchar(50);not null"`
+	ActorType      string      `gorm:"type:varchar(20);not null"`
+	ActorID        *uuid.UUID  `gorm:"type:uuid"`
+	ImpersonatorID string      `gorm:"type:varchar(64)"`
+	Reason         string      `gorm:"type:varchar(500)"`
+	ChangedAt      time.Time   `gorm:"not null"`
+	// DurationSeconds - сколько заказ пробыл в FromStatus; nil - начало статуса неизвестно
+	// (события до него пропущены), такой переход не входит в статистику
+	DurationSeconds *float64
+}
+
+// TableName указывает имя таблицы для GORM
+func (OrderStatusTransition) TableName() string {
+	return "order_status_transitions"
+}
+
+// OrderStatusProjection - текущий статус заказа по событиям ORDER_STATUS_CHANGED
+// По StatusSince считается длительность статуса при следующем переходе
+type OrderStatusProjection struct {
+	OrderID        uuid.UUID   `gorm:"type:uuid;primaryKey"`
+	TenantID       string      `gorm:"type:varchar(64);not null"`
+	Status         OrderStatus `gorm:"type:varchar(50);not null"`
+	StatusSince    time.Time   `gorm:"not null"`
+	OrderCreatedAt *time.Time
+	DeliveredAt    *time.Time
+}
+
+// TableName указывает имя таблицы для GORM
+func (OrderStatusProjection) TableName() string {
+	return "order_status_projection"
+}
+
+// StatusReportFilter - период и магазин отчета о сроках выполнения заказов
+type StatusReportFilter struct {
+	From     time.Time
+	To       time.Time
+	TenantID string // Пусто - все магазины
+}
+
+// DurationStats - статистика длительностей в секундах
+type DurationStats struct {
+	Count      int64   `json:"count"`
+	AvgSeconds float64 `json:"avg_seconds"`
+	P50Seconds float64 `json:"p50_seconds" gorm:"column:p50_seconds"`
+	P90Seconds float64 `json:"p90_seconds" gorm:"column:p90_seconds"`
+	MaxSeconds float64 `json:"max_seconds"`
+}
+
+// StatusDuration - сколько заказы пробыли в статусе до следующего перехода
+type StatusDuration struct {
+	Status OrderStatus `json:"status"`
+	DurationStats
+}
+
+// OrderStatusReport - отчет о сроках выполнения заказов для SLA (GET /admin/analytics/order-status)
+// TimeInStatus - по переходам из статуса за период, TimeToDeliver - от создания до доставки заказов, доставленных за период
+type OrderStatusReport struct {
+	From          time.Time        `json:"from"`
+	To            time.Time        `json:"to"`
+	TenantID      string           `json:"tenant_id,omitempty"`
+	TimeInStatus  []StatusDuration `json:"time_in_status"`
+	TimeToDeliver DurationStats    `json:"time_to_deliver"`
+}
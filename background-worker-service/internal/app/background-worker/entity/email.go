@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"time"
+
+	"augustberries/pkg/money"
+	"augustberries/pkg/units"
+
+	"github.com/google/uuid"
+)
+
+// EmailTemplate - шаблон письма покупателю о заказе
+type EmailTemplate string
+
+const (
+	EmailOrderConfirmation EmailTemplate = "order_confirmation" // Заказ оформлен (ORDER_CREATED)
+	EmailOrderShipped      EmailTemplate = "order_shipped"      // Заказ или его часть передан перевозчику
+	EmailOrderCancelled    EmailTemplate = "order_cancelled"    // Заказ отменен
+)
+
+// EmailTemplates - все шаблоны писем о заказе
+var EmailTemplates = []EmailTemplate{EmailOrderConfirmation, EmailOrderShipped, EmailOrderCancelled}
+
+// Valid сообщает, известен ли шаблон
+func (t EmailTemplate) Valid() bool {
+	for _, known := range EmailTemplates {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// OrderEmail - данные письма о заказе: позиции из снимков товаров и итоги в валюте заказа после конвертации
+type OrderEmail struct {
+	OrderID     uuid.UUID        `json:"order_id"`
+	OrderNumber string           `json:"order_number"`
+	Status      OrderStatus      `json:"status"`
+	CreatedAt   time.Time        `json:"created_at"`
+	Currency    string           `json:"currency"`
+	Items       []OrderEmailItem `json:"items"`
+	Subtotal    money.Amount     `json:"subtotal"` // Сумма позиций без налога
+	Tax         money.Amount     `json:"tax"`
+	Delivery    money.Amount     `json:"delivery"`
+	Total       money.Amount     `json:"total"`
+
+	// Окно ожидаемой доставки заказа
+	EstimatedDeliveryFrom *time.Time `json:"estimated_delivery_from,omitempty"`
+	EstimatedDeliveryTo   *time.Time `json:"estimated_delivery_to,omitempty"`
+}
+
+// OrderEmailItem - позиция заказа в письме
+type OrderEmailItem struct {
+	Name      string         `json:"name"`
+	Quantity  units.Quantity `json:"quantity"`
+	Unit      units.Unit     `json:"unit"`
+	UnitPrice money.Amount   `json:"unit_price"`
+	Total     money.Amount   `json:"total"` // Без налога
+}
+
+// EmailRecipient - получатель письма
+// Email пустой у зарегистрированного покупателя: адрес по UserID подставляет почтовый провайдер
+type EmailRecipient struct {
+	UserID uuid.UUID `json:"user_id"`
+	Email  string    `json:"email,omitempty"`
+}
+
+// RenderedEmail - готовое письмо в HTML и текстовом виде
+type RenderedEmail struct {
+	Template EmailTemplate `json:"template"`
+	Locale   string        `json:"locale"` // Локаль, в которой письмо отрисовано (с учетом запасной)
+	Subject  string        `json:"subject"`
+	HTML     string        `json:"html"`
+	Text     string        `json:"text"`
+}
@@ -3,19 +3,31 @@ package entity
 import (
 	"time"
 
+	"augustberries/pkg/money"
+	"augustberries/pkg/units"
+
 	"github.com/google/uuid"
 )
 
 // Order представляет заказ из Orders Service
 // Структура должна совпадать с orders-service/entity/Order
 type Order struct {
-	ID            uuid.UUID   `json:"id" gorm:"type:uuid;primaryKey"`
-	UserID        uuid.UUID   `json:"user_id" gorm:"type:uuid;not null"`
-	TotalPrice    float64     `json:"total_price" gorm:"type:decimal(10,2);not null"`
-	DeliveryPrice float64     `json:"delivery_price" gorm:"type:decimal(10,2);not null"`
-	Currency      string      `json:"currency" gorm:"type:varchar(10);not null;default:'RUB'"`
-	Status        OrderStatus `json:"status" gorm:"type:varchar(50);not null;default:'pending'"`
-	CreatedAt     time.Time   `json:"created_at" gorm:"autoCreateTime"`
+	ID            uuid.UUID    `json:"id" gorm:"type:uuid;primaryKey"`
+	Number        string       `json:"number,omitempty" gorm:"type:varchar(32)"`
+	UserID        uuid.UUID    `json:"user_id" gorm:"type:uuid;not null"`
+	GuestEmail    *string      `json:"guest_email,omitempty" gorm:"type:varchar(255)"` // Адрес гостевого заказа для писем
+	TotalPrice    money.Amount `json:"total_price" gorm:"type:decimal(10,2);not null"`
+	DeliveryPrice money.Amount `json:"delivery_price" gorm:"type:decimal(10,2);not null"`
+	TaxTotal      money.Amount `json:"tax_total" gorm:"type:decimal(10,2);not null;default:0"` // Входит в TotalPrice
+	Currency      string       `json:"currency" gorm:"type:varchar(10);not null;default:'RUB'"`
+	Status        OrderStatus  `json:"status" gorm:"type:varchar(50);not null;default:'pending'"`
+	CreatedAt     time.Time    `json:"created_at" gorm:"autoCreateTime"`
+	// ConvertedAt - время события, по которому заказ последний раз сконвертирован (nil - еще не конвертировался)
+	ConvertedAt *time.Time `json:"converted_at,omitempty" gorm:"column:converted_at"`
+
+	// Окно ожидаемой доставки для писем покупателю
+	EstimatedDeliveryFrom *time.Time `json:"estimated_delivery_from,omitempty" gorm:"type:date"`
+	EstimatedDeliveryTo   *time.Time `json:"estimated_delivery_to,omitempty" gorm:"type:date"`
 }
 
 // TableName указывает имя таблицы для GORM
@@ -23,28 +35,88 @@ func (Order) TableName() string {
 	return "orders"
 }
 
+// OrderItem представляет позицию заказа из Orders Service
+// Структура должна совпадать с orders-service/entity/OrderItem
+type OrderItem struct {
+	ID        uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey"`
+	OrderID   uuid.UUID      `json:"order_id" gorm:"type:uuid;not null"`
+	ProductID uuid.UUID      `json:"product_id" gorm:"type:uuid;not null"`
+	Quantity  units.Quantity `json:"quantity" gorm:"type:decimal(12,3);not null"`
+	UnitPrice money.Amount   `json:"unit_price" gorm:"type:decimal(10,2);not null"`
+	TaxAmount money.Amount   `json:"tax_amount" gorm:"type:decimal(10,2);not null;default:0"` // Налог на всю позицию
+
+	// Product - снимок товара на момент покупки, только для чтения (письма покупателю)
+	Product ProductSnapshot `json:"product" gorm:"embedded;embeddedPrefix:product_"`
+}
+
+// ProductSnapshot - снимок товара в позиции заказа
+// Структура должна совпадать с orders-service/entity/ProductSnapshot (используемые поля)
+type ProductSnapshot struct {
+	Name         string     `json:"name,omitempty"`
+	CategoryName string     `json:"category_name,omitempty"`
+	Unit         units.Unit `json:"unit,omitempty"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (OrderItem) TableName() string {
+	return "order_items"
+}
+
 // OrderStatus представляет статусы заказа
 type OrderStatus string
 
 const (
+	OrderStatusScheduled OrderStatus = "scheduled"
 	OrderStatusPending   OrderStatus = "pending"
 	OrderStatusConfirmed OrderStatus = "confirmed"
 	OrderStatusShipped   OrderStatus = "shipped"
 	OrderStatusDelivered OrderStatus = "delivered"
 	OrderStatusCancelled OrderStatus = "cancelled"
+
+	OrderStatusPartiallyShipped OrderStatus = "partially_shipped"
 )
 
 // OrderEvent представляет событие из Kafka топика order_events
 // Структура должна совпадать с orders-service/entity/OrderEvent
 type OrderEvent struct {
-	EventType  string      `json:"event_type"` // ORDER_CREATED, ORDER_UPDATED
-	OrderID    uuid.UUID   `json:"order_id"`
-	UserID     uuid.UUID   `json:"user_id"`
-	TotalPrice float64     `json:"total_price"`
-	Currency   string      `json:"currency"`
-	Status     OrderStatus `json:"status"`
-	ItemsCount int         `json:"items_count"`
-	Timestamp  time.Time   `json:"timestamp"`
+	EventType   string       `json:"event_type"` // ORDER_CREATED, ORDER_UPDATED, ORDER_STATUS_CHANGED
+	TenantID    string       `json:"tenant_id,omitempty"`
+	OrderID     uuid.UUID    `json:"order_id"`
+	OrderNumber string       `json:"order_number,omitempty"` // Человекочитаемый номер заказа
+	UserID      uuid.UUID    `json:"user_id"`
+	TotalPrice  money.Amount `json:"total_price"`
+	TaxTotal    money.Amount `json:"tax_total"`
+	Currency    string       `json:"currency"`
+	Status      OrderStatus  `json:"status"`
+	ItemsCount  int          `json:"items_count"`
+	Timestamp   time.Time    `json:"timestamp"`
+	// PreferredCurrency - предпочитаемая валюта покупателя, в нее конвертируется заказ
+	PreferredCurrency string `json:"preferred_currency,omitempty"`
+	// ItemChanges - измененные позиции заказа; заказ с новым составом конвертируется заново
+	ItemChanges []OrderItemChange `json:"item_changes,omitempty"`
+	// PreviousTotalPrice - итог заказа до изменения (включает доставку)
+	PreviousTotalPrice *money.Amount `json:"previous_total_price,omitempty"`
+	// Поля ORDER_STATUS_CHANGED: из какого статуса, кем и почему переведен заказ
+	PreviousStatus OrderStatus  `json:"previous_status,omitempty"`
+	Actor          *StatusActor `json:"actor,omitempty"`
+	Reason         string       `json:"reason,omitempty"`
+	OrderCreatedAt *time.Time   `json:"order_created_at,omitempty"`
+}
+
+// StatusActor - кто сменил статус заказа (customer, admin, system)
+// Структура должна совпадать с orders-service/entity/StatusActor
+type StatusActor struct {
+	Type           string     `json:"type"`
+	ID             *uuid.UUID `json:"id,omitempty"`
+	ImpersonatorID string     `json:"impersonator_id,omitempty"`
+}
+
+// OrderItemChange - изменение количества позиции заказа из ORDER_UPDATED
+type OrderItemChange struct {
+	ItemID      uuid.UUID      `json:"item_id"`
+	ProductID   uuid.UUID      `json:"product_id"`
+	OldQuantity units.Quantity `json:"old_quantity"`
+	NewQuantity units.Quantity `json:"new_quantity"`
 }
 
 // ExchangeRate представляет курс валюты
@@ -65,25 +137,32 @@ type ExchangeRatesResponse struct {
 
 // DeliveryCalculation представляет результат расчета доставки
 type DeliveryCalculation struct {
-	OrderID           uuid.UUID // ID заказа
-	OriginalDelivery  float64   // Исходная цена доставки
-	OriginalCurrency  string    // Исходная валюта
-	ConvertedDelivery float64   // Сконвертированная цена доставки
-	ConvertedCurrency string    // Целевая валюта (обычно USD или RUB)
-	ExchangeRate      float64   // Использованный курс
-	NewTotalPrice     float64   // Новая итоговая сумма заказа
-	CalculatedAt      time.Time // Время расчета
+	OrderID           uuid.UUID    // ID заказа
+	OriginalDelivery  money.Amount // Исходная цена доставки
+	OriginalCurrency  string       // Исходная валюта
+	ConvertedDelivery money.Amount // Сконвертированная цена доставки
+	ConvertedTax      money.Amount // Сконвертированная сумма налогов (сумма сконвертированных налогов позиций)
+	ConvertedCurrency string       // Целевая валюта (обычно USD или RUB)
+	ExchangeRate      float64      // Использованный курс
+	NewTotalPrice     money.Amount // Новая итоговая сумма заказа
+	ConvertedItems    []OrderItem  // Позиции с ценами в целевой валюте
+	CalculatedAt      time.Time    // Время события, по которому выполнен расчет (сохраняется в converted_at)
 }
 
 // Константы для типов событий
 const (
 	EventTypeOrderCreated = "ORDER_CREATED"
 	EventTypeOrderUpdated = "ORDER_UPDATED"
+
+	EventTypeOrderStatusChanged = "ORDER_STATUS_CHANGED"
 )
 
 // Константы для префиксов Redis ключей
 const (
 	RedisKeyPrefixRate = "rates:" // Префикс для хранения курсов валют: rates:USD, rates:EUR
+
+	RedisKeyRatePins     = "pinned_rates"       // Hash закрепленных курсов, поле FROM:TO
+	RedisKeyRatePinAudit = "pinned_rates:audit" // Список записей аудита закреплений, новые первыми
 )
 
 // Поддерживаемые валюты для конвертации
@@ -93,3 +172,83 @@ var SupportedCurrencies = []string{"USD", "EUR", "RUB", "GBP", "JPY", "CNY"}
 func GetRedisKeyForRate(currency string) string {
 	return RedisKeyPrefixRate + currency
 }
+
+// RatePin - курс валютной пары, закрепленный администратором вручную
+// Пока не истек, используется вместо курсов из внешнего API (в том числе для обратной пары)
+type RatePin struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Rate      float64   `json:"rate"` // Сколько единиц To за одну единицу From
+	Reason    string    `json:"reason"`
+	PinnedBy  string    `json:"pinned_by"` // ID администратора
+	PinnedAt  time.Time `json:"pinned_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// RatePinField возвращает поле hash закрепленных курсов для пары
+func RatePinField(from, to string) string {
+	return from + ":" + to
+}
+
+// Active проверяет, что закрепление еще действует
+func (p *RatePin) Active(now time.Time) bool {
+	return now.Before(p.ExpiresAt)
+}
+
+// PinRateRequest - запрос на закрепление курса (POST /admin/rates/pins)
+type PinRateRequest struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Rate      float64   `json:"rate"`
+	ExpiresAt time.Time `json:"expires_at"` // RFC3339, не позже чем через MaxRatePinDuration
+	Reason    string    `json:"reason"`
+}
+
+// RatePinAction - действие с закрепленным курсом
+type RatePinAction string
+
+const (
+	RatePinActionPin   RatePinAction = "pin"
+	RatePinActionUnpin RatePinAction = "unpin"
+)
+
+// RatePinAuditRecord - запись аудита: кто, когда и почему закрепил или снял курс
+type RatePinAuditRecord struct {
+	Action    RatePinAction `json:"action"`
+	From      string        `json:"from"`
+	To        string        `json:"to"`
+	Rate      float64       `json:"rate,omitempty"`
+	ExpiresAt *time.Time    `json:"expires_at,omitempty"`
+	Reason    string        `json:"reason"`
+	Actor     string        `json:"actor"`
+	At        time.Time     `json:"at"`
+}
+
+// ReplayRequest - параметры повторной обработки событий заказов из Kafka
+// Если Since не задан, чтение начинается с FromOffset в каждой партиции
+type ReplayRequest struct {
+	OrderIDs   []uuid.UUID `json:"order_ids"`       // Обрабатываются только события этих заказов
+	FromOffset int64       `json:"from_offset"`     // Начальный offset в каждой партиции
+	Since      *time.Time  `json:"since,omitempty"` // Начальный момент времени (RFC3339)
+	DryRun     bool        `json:"dry_run"`         // Только показать найденные события, не обрабатывая их
+}
+
+// ReplayedEvent - событие, найденное при повторном чтении топика
+type ReplayedEvent struct {
+	Partition int       `json:"partition"`
+	Offset    int64     `json:"offset"`
+	EventType string    `json:"event_type"`
+	OrderID   uuid.UUID `json:"order_id"`
+	Timestamp time.Time `json:"timestamp"`
+	Error     string    `json:"error,omitempty"` // Ошибка обработки (пусто при успехе и в dry-run)
+}
+
+// ReplayResult - итог повторной обработки
+type ReplayResult struct {
+	DryRun    bool            `json:"dry_run"`
+	Scanned   int             `json:"scanned"`   // Прочитано сообщений
+	Matched   int             `json:"matched"`   // Событий выбранных заказов
+	Processed int             `json:"processed"` // Успешно обработано
+	Failed    int             `json:"failed"`    // Завершилось ошибкой
+	Events    []ReplayedEvent `json:"events"`
+}
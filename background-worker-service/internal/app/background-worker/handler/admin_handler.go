@@ -0,0 +1,282 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"augustberries/background-worker-service/internal/app/background-worker/entity"
+	"augustberries/background-worker-service/internal/app/background-worker/service"
+
+	"github.com/google/uuid"
+)
+
+// AdminHandler обрабатывает ручные операции администратора
+// Позволяет повторить обработку заказа или обновить курсы без ожидания cron или Kafka
+type AdminHandler struct {
+	orderSvc    *service.OrderProcessingService
+	exchangeSvc *service.ExchangeRateService
+	replaySvc   *service.ReplayService
+	notifier    *service.OrderNotifier
+	auth        *AuthMiddleware
+}
+
+// NewAdminHandler создает новый admin handler
+// replaySvc может быть nil - тогда маршруты повтора событий из Kafka не регистрируются
+// notifier может быть nil - тогда не регистрируется предпросмотр писем
+func NewAdminHandler(
+	orderSvc *service.OrderProcessingService,
+	exchangeSvc *service.ExchangeRateService,
+	replaySvc *service.ReplayService,
+	notifier *service.OrderNotifier,
+	auth *AuthMiddleware,
+) *AdminHandler {
+	return &AdminHandler{
+		orderSvc:    orderSvc,
+		exchangeSvc: exchangeSvc,
+		replaySvc:   replaySvc,
+		notifier:    notifier,
+		auth:        auth,
+	}
+}
+
+// ReprocessOrder обрабатывает POST /admin/reprocess/{orderID}
+// Повторно конвертирует валюту заказа по текущим курсам
+func (h *AdminHandler) ReprocessOrder(w http.ResponseWriter, r *http.Request) {
+	orderID, err := uuid.Parse(r.PathValue("orderID"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid order ID")
+		return
+	}
+
+	if err := h.orderSvc.ReprocessOrder(r.Context(), orderID); err != nil {
+		if errors.Is(err, service.ErrOrderNotFound) {
+			writeError(w, http.StatusNotFound, "Order not found")
+			return
+		}
+		log.Printf("Manual reprocessing of order %s failed: %v", orderID, err)
+		writeError(w, http.StatusInternalServerError, "Failed to reprocess order")
+		return
+	}
+
+	log.Printf("Order %s reprocessed manually", orderID)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message":  "Order reprocessed successfully",
+		"order_id": orderID,
+	})
+}
+
+// RefreshRates обрабатывает POST /admin/rates/refresh
+// Принудительно загружает курсы валют из внешнего API
+func (h *AdminHandler) RefreshRates(w http.ResponseWriter, r *http.Request) {
+	count, err := h.exchangeSvc.RefreshRates(r.Context())
+	if err != nil {
+		log.Printf("Manual exchange rates refresh failed: %v", err)
+		if errors.Is(err, service.ErrRatesUnavailable) {
+			writeError(w, http.StatusBadGateway, "Exchange rates API unavailable")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "Failed to refresh exchange rates")
+		return
+	}
+
+	log.Printf("Exchange rates refreshed manually (%d rates)", count)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Exchange rates refreshed successfully",
+		"count":   count,
+	})
+}
+
+// ReplayEvents обрабатывает POST /admin/events/replay
+// Перечитывает топик событий заказов с offset или момента времени и обрабатывает события выбранных заказов
+// При dry_run=true только возвращает найденные события
+func (h *AdminHandler) ReplayEvents(w http.ResponseWriter, r *http.Request) {
+	var req entity.ReplayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.replaySvc.Replay(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, service.ErrReplayFilterRequired) || errors.Is(err, service.ErrReplayInvalidOffset) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("Replay of order events failed: %v", err)
+		writeError(w, http.StatusBadGateway, "Failed to read order events from Kafka")
+		return
+	}
+
+	log.Printf("Order events replayed manually (dry run: %t, scanned: %d, matched: %d, processed: %d, failed: %d)",
+		result.DryRun, result.Scanned, result.Matched, result.Processed, result.Failed)
+	writeJSON(w, http.StatusOK, result)
+}
+
+// GetOffsets обрабатывает GET /admin/events/offsets
+// Возвращает границы партиций топика событий для выбора начального offset повтора
+func (h *AdminHandler) GetOffsets(w http.ResponseWriter, r *http.Request) {
+	offsets, err := h.replaySvc.Offsets(r.Context())
+	if err != nil {
+		log.Printf("Failed to read order events offsets: %v", err)
+		writeError(w, http.StatusBadGateway, "Failed to read offsets from Kafka")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"partitions": offsets,
+	})
+}
+
+// ListPins обрабатывает GET /admin/rates/pins
+// Возвращает действующие закрепленные вручную курсы
+func (h *AdminHandler) ListPins(w http.ResponseWriter, r *http.Request) {
+	pins, err := h.exchangeSvc.ListPins(r.Context())
+	if err != nil {
+		h.writePinError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"pins": pins,
+	})
+}
+
+// PinRate обрабатывает POST /admin/rates/pins
+// Закрепляет курс пары до expires_at; пока закрепление действует, курс из внешнего API для пары не используется
+func (h *AdminHandler) PinRate(w http.ResponseWriter, r *http.Request) {
+	var req entity.PinRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	pin, err := h.exchangeSvc.PinRate(r.Context(), &req, actorFromRequest(r))
+	if err != nil {
+		h.writePinError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, pin)
+}
+
+// UnpinRate обрабатывает DELETE /admin/rates/pins/{from}/{to}?reason=
+// Досрочно снимает закрепление, конвертация снова идет по курсам из внешнего API
+func (h *AdminHandler) UnpinRate(w http.ResponseWriter, r *http.Request) {
+	err := h.exchangeSvc.UnpinRate(r.Context(), r.PathValue("from"), r.PathValue("to"), actorFromRequest(r), r.URL.Query().Get("reason"))
+	if err != nil {
+		h.writePinError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListPinAudit обрабатывает GET /admin/rates/pins/audit?limit=
+// Возвращает историю закреплений: кто, когда и почему менял курс
+func (h *AdminHandler) ListPinAudit(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 {
+			writeError(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+	}
+
+	records, err := h.exchangeSvc.ListPinAudit(r.Context(), limit)
+	if err != nil {
+		h.writePinError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"records": records,
+	})
+}
+
+// PreviewEmail обрабатывает GET /admin/emails/{template}/preview
+// Отрисовывает письмо без отправки: по заказу order_id или по демонстрационному заказу
+// locale - локаль письма (по умолчанию EMAIL_DEFAULT_LOCALE), format - html, text или json (по умолчанию)
+func (h *AdminHandler) PreviewEmail(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format != "" && format != "json" && format != "html" && format != "text" {
+		writeError(w, http.StatusBadRequest, "Invalid format: use html, text or json")
+		return
+	}
+
+	var orderID uuid.UUID
+	if raw := query.Get("order_id"); raw != "" {
+		var err error
+		if orderID, err = uuid.Parse(raw); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid order ID")
+			return
+		}
+	}
+
+	email, err := h.notifier.Preview(r.Context(), entity.EmailTemplate(r.PathValue("template")), query.Get("locale"), orderID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnknownEmailTemplate):
+			writeError(w, http.StatusNotFound, "Unknown email template")
+		case errors.Is(err, service.ErrOrderNotFound):
+			writeError(w, http.StatusNotFound, "Order not found")
+		default:
+			log.Printf("Email preview failed: %v", err)
+			writeError(w, http.StatusInternalServerError, "Failed to render email")
+		}
+		return
+	}
+
+	switch format {
+	case "html":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(email.HTML))
+	case "text":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(email.Subject + "\n\n" + email.Text))
+	default:
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"email":   email,
+			"locales": h.notifier.Locales(),
+		})
+	}
+}
+
+// writePinError переводит ошибки закрепления курсов в HTTP статусы
+func (h *AdminHandler) writePinError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidRatePin):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrRatePinNotFound):
+		writeError(w, http.StatusNotFound, "Rate pin not found")
+	case errors.Is(err, service.ErrRatePinsDisabled):
+		writeError(w, http.StatusServiceUnavailable, "Rate pinning is disabled")
+	default:
+		log.Printf("Rate pin operation failed: %v", err)
+		writeError(w, http.StatusInternalServerError, "Failed to process rate pin")
+	}
+}
+
+// RegisterRoutes регистрирует admin маршруты (только для роли admin)
+func (h *AdminHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /admin/reprocess/{orderID}", h.auth.RequireRole(h.ReprocessOrder, "admin"))
+	mux.HandleFunc("POST /admin/rates/refresh", h.auth.RequireRole(h.RefreshRates, "admin"))
+	mux.HandleFunc("GET /admin/rates/pins", h.auth.RequireRole(h.ListPins, "admin"))
+	mux.HandleFunc("POST /admin/rates/pins", h.auth.RequireRole(h.PinRate, "admin"))
+	mux.HandleFunc("DELETE /admin/rates/pins/{from}/{to}", h.auth.RequireRole(h.UnpinRate, "admin"))
+	mux.HandleFunc("GET /admin/rates/pins/audit", h.auth.RequireRole(h.ListPinAudit, "admin"))
+
+	if h.replaySvc != nil {
+		mux.HandleFunc("POST /admin/events/replay", h.auth.RequireRole(h.ReplayEvents, "admin"))
+		mux.HandleFunc("GET /admin/events/offsets", h.auth.RequireRole(h.GetOffsets, "admin"))
+	}
+	if h.notifier != nil {
+		mux.HandleFunc("GET /admin/emails/{template}/preview", h.auth.RequireRole(h.PreviewEmail, "admin"))
+	}
+}
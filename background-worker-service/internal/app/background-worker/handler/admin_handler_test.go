@@ -0,0 +1,297 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"augustberries/background-worker-service/internal/app/background-worker/entity"
+	"augustberries/background-worker-service/internal/app/background-worker/repository"
+	"augustberries/background-worker-service/internal/app/background-worker/repository/mocks"
+	"augustberries/background-worker-service/internal/app/background-worker/service"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testJWTSecret = "test-secret"
+
+type adminTestDeps struct {
+	orderRepo *mocks.MockOrderRepository
+	rateRepo  *mocks.MockExchangeRateRepository
+	apiClient *mocks.MockExchangeRateAPIClient
+	pinRepo   *mocks.MockRatePinRepository
+	mux       *http.ServeMux
+}
+
+func setupAdminHandler() *adminTestDeps {
+	deps := &adminTestDeps{
+		orderRepo: new(mocks.MockOrderRepository),
+		rateRepo:  new(mocks.MockExchangeRateRepository),
+		apiClient: new(mocks.MockExchangeRateAPIClient),
+		pinRepo:   new(mocks.MockRatePinRepository),
+		mux:       http.NewServeMux(),
+	}
+
+	exchangeSvc := service.NewExchangeRateService(deps.rateRepo, deps.apiClient, deps.pinRepo)
+	orderSvc := service.NewOrderProcessingService(deps.orderRepo, exchangeSvc)
+	renderer, err := service.NewEmailRenderer("ru")
+	if err != nil {
+		panic(err)
+	}
+	notifier := service.NewOrderNotifier(deps.orderRepo, renderer, nil)
+
+	NewAdminHandler(orderSvc, exchangeSvc, nil, notifier, NewAuthMiddleware(testJWTSecret)).RegisterRoutes(deps.mux)
+	return deps
+}
+
+func signTestToken(t *testing.T, role string) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &JWTClaims{
+		UserID:   uuid.New().String(),
+		RoleName: role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	signed, err := token.SignedString([]byte(testJWTSecret))
+	require.NoError(t, err)
+	return signed
+}
+
+func (d *adminTestDeps) do(method, path, token string) *httptest.ResponseRecorder {
+	return d.doBody(method, path, token, "")
+}
+
+func (d *adminTestDeps) doBody(method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	d.mux.ServeHTTP(w, req)
+	return w
+}
+
+// ==================== Auth Tests ====================
+
+func TestAdminHandler_NoToken(t *testing.T) {
+	deps := setupAdminHandler()
+
+	w := deps.do(http.MethodPost, "/admin/rates/refresh", "")
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	deps.apiClient.AssertNotCalled(t, "FetchRates", mock.Anything)
+}
+
+func TestAdminHandler_NonAdminForbidden(t *testing.T) {
+	deps := setupAdminHandler()
+
+	w := deps.do(http.MethodPost, "/admin/reprocess/"+uuid.New().String(), signTestToken(t, "user"))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	deps.orderRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestAdminHandler_GetNotAllowed(t *testing.T) {
+	deps := setupAdminHandler()
+
+	w := deps.do(http.MethodGet, "/admin/rates/refresh", signTestToken(t, "admin"))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+// ==================== Reprocess Tests ====================
+
+func TestAdminHandler_ReprocessOrder_InvalidID(t *testing.T) {
+	deps := setupAdminHandler()
+
+	w := deps.do(http.MethodPost, "/admin/reprocess/not-a-uuid", signTestToken(t, "admin"))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAdminHandler_ReprocessOrder_NotFound(t *testing.T) {
+	// Arrange
+	deps := setupAdminHandler()
+	orderID := uuid.New()
+	deps.orderRepo.On("GetByID", mock.Anything, orderID).
+		Return(nil, repository.ErrOrderNotFound)
+
+	// Act
+	w := deps.do(http.MethodPost, "/admin/reprocess/"+orderID.String(), signTestToken(t, "admin"))
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAdminHandler_ReprocessOrder_ProcessingError(t *testing.T) {
+	// Arrange
+	deps := setupAdminHandler()
+	orderID := uuid.New()
+	deps.orderRepo.On("GetByID", mock.Anything, orderID).
+		Return(nil, errors.New("connection refused"))
+
+	// Act
+	w := deps.do(http.MethodPost, "/admin/reprocess/"+orderID.String(), signTestToken(t, "admin"))
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+// ==================== Rates Refresh Tests ====================
+
+func TestAdminHandler_RefreshRates_Success(t *testing.T) {
+	// Arrange
+	deps := setupAdminHandler()
+	deps.apiClient.On("FetchRates", mock.Anything).
+		Return(map[string]float64{"USD": 1.0, "RUB": 91.23}, nil)
+	deps.rateRepo.On("SetMultiple", mock.Anything, mock.Anything).Return(nil)
+
+	// Act
+	w := deps.do(http.MethodPost, "/admin/rates/refresh", signTestToken(t, "admin"))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"count":2`)
+	deps.rateRepo.AssertExpectations(t)
+}
+
+func TestAdminHandler_RefreshRates_APIUnavailable(t *testing.T) {
+	// Arrange
+	deps := setupAdminHandler()
+	deps.apiClient.On("FetchRates", mock.Anything).
+		Return(nil, context.DeadlineExceeded)
+
+	// Act
+	w := deps.do(http.MethodPost, "/admin/rates/refresh", signTestToken(t, "admin"))
+
+	// Assert
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	deps.rateRepo.AssertNotCalled(t, "SetMultiple", mock.Anything, mock.Anything)
+}
+
+// ==================== Rate Pin Tests ====================
+
+func TestAdminHandler_PinRate_Success(t *testing.T) {
+	// Arrange
+	deps := setupAdminHandler()
+	expiresAt := time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)
+	deps.pinRepo.On("Set", mock.Anything, mock.MatchedBy(func(pin *entity.RatePin) bool {
+		return pin.From == "USD" && pin.To == "RUB" && pin.Rate == 90 && pin.PinnedBy != ""
+	})).Return(nil)
+	deps.pinRepo.On("AppendAudit", mock.Anything, mock.MatchedBy(func(record *entity.RatePinAuditRecord) bool {
+		return record.Action == entity.RatePinActionPin && record.Reason == "bad tick from provider"
+	})).Return(nil)
+
+	// Act
+	w := deps.doBody(http.MethodPost, "/admin/rates/pins", signTestToken(t, "admin"),
+		`{"from":"usd","to":"RUB","rate":90,"expires_at":"`+expiresAt+`","reason":"bad tick from provider"}`)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, w.Code)
+	deps.pinRepo.AssertExpectations(t)
+}
+
+func TestAdminHandler_PinRate_Invalid(t *testing.T) {
+	deps := setupAdminHandler()
+
+	w := deps.doBody(http.MethodPost, "/admin/rates/pins", signTestToken(t, "admin"),
+		`{"from":"USD","to":"RUB","rate":90,"expires_at":"2020-01-01T00:00:00Z","reason":"late"}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	deps.pinRepo.AssertNotCalled(t, "Set", mock.Anything, mock.Anything)
+}
+
+func TestAdminHandler_PinRate_NonAdminForbidden(t *testing.T) {
+	deps := setupAdminHandler()
+
+	w := deps.doBody(http.MethodPost, "/admin/rates/pins", signTestToken(t, "manager"), `{}`)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAdminHandler_UnpinRate_NotFound(t *testing.T) {
+	deps := setupAdminHandler()
+	deps.pinRepo.On("Delete", mock.Anything, "USD", "RUB").Return(false, nil)
+
+	w := deps.do(http.MethodDelete, "/admin/rates/pins/usd/rub?reason=provider+fixed", signTestToken(t, "admin"))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// ==================== Replay Tests ====================
+
+func TestAdminHandler_ReplayRoutesDisabledWithoutService(t *testing.T) {
+	deps := setupAdminHandler()
+
+	w := deps.do(http.MethodGet, "/admin/events/offsets", signTestToken(t, "admin"))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// ==================== Email Preview Tests ====================
+
+func TestAdminHandler_PreviewEmail_Sample(t *testing.T) {
+	deps := setupAdminHandler()
+
+	w := deps.do(http.MethodGet, "/admin/emails/order_confirmation/preview?locale=en-US", signTestToken(t, "admin"))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"locale":"en"`)
+	assert.Contains(t, w.Body.String(), "AB-20240115-000123")
+	deps.orderRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestAdminHandler_PreviewEmail_OrderHTML(t *testing.T) {
+	// Arrange
+	deps := setupAdminHandler()
+	orderID := uuid.New()
+	deps.orderRepo.On("GetByID", mock.Anything, orderID).Return(&entity.Order{
+		ID: orderID, Number: "AB-1", UserID: uuid.New(), Currency: "EUR", Status: entity.OrderStatusShipped,
+	}, nil)
+	deps.orderRepo.On("GetItems", mock.Anything, orderID).Return([]entity.OrderItem{}, nil)
+
+	// Act
+	w := deps.do(http.MethodGet, "/admin/emails/order_shipped/preview?format=html&order_id="+orderID.String(), signTestToken(t, "admin"))
+
+	// Assert
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, w.Body.String(), "Заказ AB-1 отправлен")
+}
+
+func TestAdminHandler_PreviewEmail_Errors(t *testing.T) {
+	deps := setupAdminHandler()
+	orderID := uuid.New()
+	deps.orderRepo.On("GetByID", mock.Anything, orderID).Return(nil, repository.ErrOrderNotFound)
+	token := signTestToken(t, "admin")
+
+	tests := []struct {
+		path string
+		code int
+	}{
+		{"/admin/emails/welcome/preview", http.StatusNotFound},
+		{"/admin/emails/order_confirmation/preview?order_id=" + orderID.String(), http.StatusNotFound},
+		{"/admin/emails/order_confirmation/preview?order_id=bad", http.StatusBadRequest},
+		{"/admin/emails/order_confirmation/preview?format=pdf", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := deps.do(http.MethodGet, tt.path, token)
+		assert.Equal(t, tt.code, w.Code, tt.path)
+	}
+}
+
+func TestAdminHandler_PreviewEmail_NonAdminForbidden(t *testing.T) {
+	deps := setupAdminHandler()
+
+	w := deps.do(http.MethodGet, "/admin/emails/order_confirmation/preview", signTestToken(t, "user"))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
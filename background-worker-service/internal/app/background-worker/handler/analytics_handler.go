@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"augustberries/background-worker-service/internal/app/background-worker/entity"
+	"augustberries/background-worker-service/internal/app/background-worker/service"
+)
+
+// defaultStatusReportPeriod - период отчета, если from не указан
+const defaultStatusReportPeriod = 30 * 24 * time.Hour
+
+// AnalyticsHandler отдает отчеты о сроках выполнения заказов для SLA-дашбордов (только для роли admin)
+type AnalyticsHandler struct {
+	analyticsSvc *service.StatusAnalyticsService
+	auth         *AuthMiddleware
+}
+
+// NewAnalyticsHandler создает handler аналитики заказов
+func NewAnalyticsHandler(analyticsSvc *service.StatusAnalyticsService, auth *AuthMiddleware) *AnalyticsHandler {
+	return &AnalyticsHandler{analyticsSvc: analyticsSvc, auth: auth}
+}
+
+// GetOrderStatusReport обрабатывает GET /admin/analytics/order-status?from=&to=&tenant_id=
+// from и to в RFC3339, по умолчанию последние 30 дней; tenant_id - отчет одного магазина
+func (h *AnalyticsHandler) GetOrderStatusReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := entity.StatusReportFilter{To: time.Now(), TenantID: query.Get("tenant_id")}
+
+	if value := query.Get("to"); value != "" {
+		to, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid to: use RFC3339")
+			return
+		}
+		filter.To = to
+	}
+	filter.From = filter.To.Add(-defaultStatusReportPeriod)
+	if value := query.Get("from"); value != "" {
+		from, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid from: use RFC3339")
+			return
+		}
+		filter.From = from
+	}
+
+	report, err := h.analyticsSvc.Report(r.Context(), filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidReportPeriod) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("Order status report failed: %v", err)
+		writeError(w, http.StatusInternalServerError, "Failed to build order status report")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+// RegisterRoutes регистрирует маршруты аналитики
+func (h *AnalyticsHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /admin/analytics/order-status", h.auth.RequireRole(h.GetOrderStatusReport, "admin"))
+}
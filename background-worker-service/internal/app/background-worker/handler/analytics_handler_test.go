@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"augustberries/background-worker-service/internal/app/background-worker/entity"
+	"augustberries/background-worker-service/internal/app/background-worker/repository/mocks"
+	"augustberries/background-worker-service/internal/app/background-worker/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ==================== Analytics Handler Tests ====================
+
+func setupAnalyticsHandler() (*mocks.MockOrderStatusRepository, *http.ServeMux) {
+	repo := new(mocks.MockOrderStatusRepository)
+	mux := http.NewServeMux()
+	NewAnalyticsHandler(service.NewStatusAnalyticsService(repo), NewAuthMiddleware(testJWTSecret)).RegisterRoutes(mux)
+	return repo, mux
+}
+
+func TestAnalyticsHandler_OrderStatusReport(t *testing.T) {
+	// Arrange
+	repo, mux := setupAnalyticsHandler()
+	filter := entity.StatusReportFilter{
+		From:     time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+		TenantID: "shop-a",
+	}
+	repo.On("StatusDurations", mock.Anything, filter).Return([]entity.StatusDuration{
+		{Status: entity.OrderStatusPending, DurationStats: entity.DurationStats{Count: 2, AvgSeconds: 600, P50Seconds: 600, P90Seconds: 900, MaxSeconds: 900}},
+	}, nil)
+	repo.On("DeliveryDurations", mock.Anything, filter).Return(entity.DurationStats{Count: 1, AvgSeconds: 86400, P50Seconds: 86400, P90Seconds: 86400, MaxSeconds: 86400}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/analytics/order-status?from=2026-03-01T00:00:00Z&to=2026-04-01T00:00:00Z&tenant_id=shop-a", nil)
+	req.Header.Set("Authorization", "Bearer "+signTestToken(t, "admin"))
+
+	// Act
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{
+		"from": "2026-03-01T00:00:00Z",
+		"to": "2026-04-01T00:00:00Z",
+		"tenant_id": "shop-a",
+		"time_in_status": [{"status": "pending", "count": 2, "avg_seconds": 600, "p50_seconds": 600, "p90_seconds": 900, "max_seconds": 900}],
+		"time_to_deliver": {"count": 1, "avg_seconds": 86400, "p50_seconds": 86400, "p90_seconds": 86400, "max_seconds": 86400}
+	}`, rec.Body.String())
+}
+
+func TestAnalyticsHandler_OrderStatusReport_BadRequest(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "invalid from", query: "?from=yesterday"},
+		{name: "from after to", query: "?from=2026-04-01T00:00:00Z&to=2026-03-01T00:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			_, mux := setupAnalyticsHandler()
+			req := httptest.NewRequest(http.MethodGet, "/admin/analytics/order-status"+tt.query, nil)
+			req.Header.Set("Authorization", "Bearer "+signTestToken(t, "admin"))
+
+			// Act
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		})
+	}
+}
+
+func TestAnalyticsHandler_OrderStatusReport_RequiresAdmin(t *testing.T) {
+	// Arrange
+	_, mux := setupAnalyticsHandler()
+	req := httptest.NewRequest(http.MethodGet, "/admin/analytics/order-status", nil)
+	req.Header.Set("Authorization", "Bearer "+signTestToken(t, "user"))
+
+	// Act
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
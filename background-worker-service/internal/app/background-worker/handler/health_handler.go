@@ -8,28 +8,36 @@ import (
 	"time"
 
 	"augustberries/background-worker-service/internal/app/background-worker/service"
+	"augustberries/pkg/buildinfo"
+	"augustberries/pkg/kafka"
 
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
+// serviceName - имя сервиса в сведениях о сборке
+const serviceName = "background-worker-service"
+
 // HealthCheckHandler управляет healthcheck endpoint'ами
 type HealthCheckHandler struct {
 	db          *gorm.DB
-	redisClient *redis.Client
+	redisClient redis.UniversalClient
 	exchangeSvc service.ExchangeRateServiceInterface
+	consumer    func() kafka.GateState // Состояние паузы Kafka consumer (nil - не отслеживается)
 }
 
 // NewHealthCheckHandler создает новый healthcheck handler
 func NewHealthCheckHandler(
 	db *gorm.DB,
-	redisClient *redis.Client,
+	redisClient redis.UniversalClient,
 	exchangeSvc service.ExchangeRateServiceInterface,
+	consumer func() kafka.GateState,
 ) *HealthCheckHandler {
 	return &HealthCheckHandler{
 		db:          db,
 		redisClient: redisClient,
 		exchangeSvc: exchangeSvc,
+		consumer:    consumer,
 	}
 }
 
@@ -37,6 +45,7 @@ func NewHealthCheckHandler(
 type HealthResponse struct {
 	Status    string            `json:"status"`
 	Checks    map[string]string `json:"checks"`
+	Build     buildinfo.Info    `json:"build"`
 	Timestamp time.Time         `json:"timestamp"`
 }
 
@@ -75,6 +84,7 @@ func (h *HealthCheckHandler) HealthCheck(w http.ResponseWriter, r *http.Request)
 	response := HealthResponse{
 		Status:    overallStatus,
 		Checks:    checks,
+		Build:     buildinfo.Get(serviceName),
 		Timestamp: time.Now(),
 	}
 
@@ -90,24 +100,50 @@ func (h *HealthCheckHandler) HealthCheck(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(response)
 }
 
-// Readiness проверяет готовность сервиса к обработке запросов
+// ReadinessResponse структура ответа readiness
+type ReadinessResponse struct {
+	Status   string            `json:"status"`
+	Checks   map[string]string `json:"checks"`
+	Consumer kafka.GateState   `json:"consumer"`
+}
+
+// Readiness проверяет готовность сервиса к обработке событий
+// Сервис не готов, пока недоступна БД или Redis либо Kafka consumer стоит на паузе
 func (h *HealthCheckHandler) Readiness(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
+	response := ReadinessResponse{Status: "ready", Checks: make(map[string]string)}
+
 	// Проверяем что все критические компоненты работают
 	if err := h.checkDatabase(ctx); err != nil {
-		http.Error(w, "database not ready", http.StatusServiceUnavailable)
-		return
+		response.Checks["database"] = "not ready: " + err.Error()
+		response.Status = "not_ready"
+	} else {
+		response.Checks["database"] = "ready"
 	}
 
 	if err := h.checkRedis(ctx); err != nil {
-		http.Error(w, "redis not ready", http.StatusServiceUnavailable)
-		return
+		response.Checks["redis"] = "not ready: " + err.Error()
+		response.Status = "not_ready"
+	} else {
+		response.Checks["redis"] = "ready"
 	}
 
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("ready"))
+	if h.consumer != nil {
+		response.Consumer = h.consumer()
+		if response.Consumer.Paused {
+			response.Status = "not_ready"
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if response.Status != "ready" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+	json.NewEncoder(w).Encode(response)
 }
 
 // Liveness простая проверка что приложение живо
@@ -152,4 +188,5 @@ func (h *HealthCheckHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/health", h.HealthCheck)
 	mux.HandleFunc("/health/readiness", h.Readiness)
 	mux.HandleFunc("/health/liveness", h.Liveness)
+	mux.HandleFunc("/version", buildinfo.Handler(serviceName))
 }
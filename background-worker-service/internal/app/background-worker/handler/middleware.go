@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"augustberries/pkg/authcookie"
+	"augustberries/pkg/jwtclaims"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTClaims структура claims для JWT токена
+type JWTClaims struct {
+	UserID      string   `json:"user_id"`
+	Email       string   `json:"email"`
+	RoleID      int      `json:"role_id"`
+	RoleName    string   `json:"role_name"`
+	Permissions []string `json:"permissions"`
+	jwt.RegisteredClaims
+}
+
+// AuthMiddleware проверяет JWT токен в запросах к admin endpoint'ам
+type AuthMiddleware struct {
+	jwtSecret string
+	cookies   *authcookie.Cookies // nil - токен только в заголовке Authorization
+	expected  jwtclaims.Expected  // Ожидаемые iss и aud токенов, пустые - не проверяются
+}
+
+// NewAuthMiddleware создает новый middleware для аутентификации
+func NewAuthMiddleware(jwtSecret string) *AuthMiddleware {
+	return &AuthMiddleware{
+		jwtSecret: jwtSecret,
+	}
+}
+
+// SetCookies включает токен из httpOnly cookie с проверкой CSRF токена у изменяющих запросов
+func (m *AuthMiddleware) SetCookies(cookies *authcookie.Cookies) {
+	m.cookies = cookies
+}
+
+// SetExpectedClaims включает проверку iss и aud: токены другого окружения отклоняются
+func (m *AuthMiddleware) SetExpectedClaims(expected jwtclaims.Expected) {
+	m.expected = expected
+}
+
+// RequireRole проверяет JWT токен и роль пользователя перед вызовом обработчика
+func (m *AuthMiddleware) RequireRole(next http.HandlerFunc, roles ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Токен из заголовка Authorization или из cookie
+		tokenString, err := m.cookies.Token(r)
+		if errors.Is(err, authcookie.ErrCSRF) {
+			writeError(w, http.StatusForbidden, "Invalid CSRF token")
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusUnauthorized, "Authorization header required")
+			return
+		}
+
+		// Парсим и валидируем токен
+		claims := &JWTClaims{}
+		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+			return []byte(m.jwtSecret), nil
+		}, m.expected.ParserOptions()...)
+		if err != nil || !token.Valid {
+			writeError(w, http.StatusUnauthorized, jwtclaims.Message(err))
+			return
+		}
+
+		// Проверяем, есть ли роль пользователя в списке разрешенных
+		for _, role := range roles {
+			if claims.RoleName == role {
+				next(w, r.WithContext(context.WithValue(r.Context(), claimsContextKey{}, claims)))
+				return
+			}
+		}
+
+		writeError(w, http.StatusForbidden, "Insufficient permissions")
+	}
+}
+
+// claimsContextKey - ключ claims проверенного токена в контексте запроса
+type claimsContextKey struct{}
+
+// actorFromRequest возвращает ID пользователя из токена, проверенного RequireRole
+func actorFromRequest(r *http.Request) string {
+	if claims, ok := r.Context().Value(claimsContextKey{}).(*JWTClaims); ok {
+		return claims.UserID
+	}
+	return ""
+}
+
+// writeJSON записывает JSON ответ с указанным статусом
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeError записывает ответ с ошибкой в формате {"error": "..."}
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
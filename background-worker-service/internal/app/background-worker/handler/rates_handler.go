@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"slices"
+
+	"augustberries/background-worker-service/internal/app/background-worker/entity"
+	"augustberries/background-worker-service/internal/app/background-worker/service"
+	"augustberries/pkg/money"
+)
+
+// RatesHandler отдает курсы валют другим сервисам (предпросмотр конвертации заказа в Orders Service)
+// Курсы не секретны, поэтому маршрут не требует токена
+type RatesHandler struct {
+	exchangeSvc service.ExchangeRateServiceInterface
+}
+
+// NewRatesHandler создает handler курсов валют
+func NewRatesHandler(exchangeSvc service.ExchangeRateServiceInterface) *RatesHandler {
+	return &RatesHandler{exchangeSvc: exchangeSvc}
+}
+
+// GetRate обрабатывает GET /rates/{from}/{to}
+// Курс тот же, по которому worker конвертирует заказы: закрепленный администратором или из внешнего API
+func (h *RatesHandler) GetRate(w http.ResponseWriter, r *http.Request) {
+	from, to := r.PathValue("from"), r.PathValue("to")
+	if !slices.Contains(entity.SupportedCurrencies, from) || !slices.Contains(entity.SupportedCurrencies, to) {
+		writeError(w, http.StatusBadRequest, "Unsupported currency")
+		return
+	}
+
+	// Сумма не важна: нужен только курс пары
+	_, rate, err := h.exchangeSvc.ConvertCurrency(r.Context(), money.Amount(0), from, to)
+	if err != nil {
+		log.Printf("Failed to get exchange rate %s/%s: %v", from, to, err)
+		writeError(w, http.StatusServiceUnavailable, "Exchange rate unavailable")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"from": from,
+		"to":   to,
+		"rate": rate,
+	})
+}
+
+// RegisterRoutes регистрирует маршруты курсов валют
+func (h *RatesHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /rates/{from}/{to}", h.GetRate)
+}
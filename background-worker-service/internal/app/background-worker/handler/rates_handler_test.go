@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"augustberries/background-worker-service/internal/app/background-worker/repository/mocks"
+	"augustberries/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ==================== Rates Handler Tests ====================
+
+func TestRatesHandler_GetRate(t *testing.T) {
+	// Arrange
+	exchangeSvc := new(mocks.MockExchangeRateService)
+	exchangeSvc.On("ConvertCurrency", mock.Anything, money.Amount(0), "USD", "EUR").Return(money.Amount(0), 0.92, nil)
+	mux := http.NewServeMux()
+	NewRatesHandler(exchangeSvc).RegisterRoutes(mux)
+
+	// Act
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rates/USD/EUR", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"from":"USD","to":"EUR","rate":0.92}`, rec.Body.String())
+}
+
+func TestRatesHandler_GetRate_UnsupportedCurrency(t *testing.T) {
+	// Arrange
+	mux := http.NewServeMux()
+	NewRatesHandler(new(mocks.MockExchangeRateService)).RegisterRoutes(mux)
+
+	// Act
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rates/USD/XYZ", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestRatesHandler_GetRate_Unavailable(t *testing.T) {
+	// Arrange
+	exchangeSvc := new(mocks.MockExchangeRateService)
+	exchangeSvc.On("ConvertCurrency", mock.Anything, money.Amount(0), "USD", "RUB").Return(money.Amount(0), 0.0, errors.New("rate for RUB not found"))
+	mux := http.NewServeMux()
+	NewRatesHandler(exchangeSvc).RegisterRoutes(mux)
+
+	// Act
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rates/USD/RUB", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
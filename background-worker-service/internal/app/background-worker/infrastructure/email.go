@@ -0,0 +1,26 @@
+package infrastructure
+
+import (
+	"context"
+	"log"
+
+	"augustberries/background-worker-service/internal/app/background-worker/entity"
+)
+
+// LogEmailSender пишет письма в лог вместо отправки
+// Используется, пока к сервису не подключен почтовый провайдер
+type LogEmailSender struct{}
+
+// NewLogEmailSender создает EmailSender, который только логирует письма
+func NewLogEmailSender() *LogEmailSender {
+	return &LogEmailSender{}
+}
+
+func (s *LogEmailSender) Send(ctx context.Context, recipient entity.EmailRecipient, email *entity.RenderedEmail) error {
+	to := recipient.Email
+	if to == "" {
+		to = "user " + recipient.UserID.String()
+	}
+	log.Printf("Email %s (%s) to %s: %s\n%s", email.Template, email.Locale, to, email.Subject, email.Text)
+	return nil
+}
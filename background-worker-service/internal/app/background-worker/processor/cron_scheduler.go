@@ -2,9 +2,13 @@ package processor
 
 import (
 	"context"
+	"errors"
 	"log"
+	"time"
 
 	"augustberries/background-worker-service/internal/app/background-worker/service"
+	"augustberries/pkg/lock"
+	"augustberries/pkg/recovery"
 
 	"github.com/robfig/cron/v3"
 )
@@ -13,6 +17,8 @@ import (
 type CronScheduler struct {
 	cron        *cron.Cron
 	exchangeSvc service.ExchangeRateServiceInterface
+	locker      *lock.Locker // nil - задачи выполняются на каждом экземпляре
+	lockTTL     time.Duration
 }
 
 // NewCronScheduler создает новый планировщик задач
@@ -26,20 +32,30 @@ func NewCronScheduler(exchangeSvc service.ExchangeRateServiceInterface) *CronSch
 	}
 }
 
+// SetLocker включает распределенную блокировку: при нескольких репликах каждый запуск задачи
+// выполняет только одна. ttl должен быть меньше интервала расписания
+func (s *CronScheduler) SetLocker(locker *lock.Locker, ttl time.Duration) {
+	s.locker = locker
+	s.lockTTL = ttl
+}
+
 // Start запускает планировщик задач
 func (s *CronScheduler) Start(ctx context.Context, schedule string) error {
 	log.Printf("Starting cron scheduler with schedule: %s", schedule)
 
 	// Добавляем задачу обновления курсов валют
-	_, err := s.cron.AddFunc(schedule, func() {
-		log.Println("Cron job triggered: updating exchange rates")
+	// Паника в задаче сообщается через pkg/recovery и не останавливает планировщик
+	_, err := s.cron.AddFunc(schedule, recovery.Wrap("background-worker", "update_rates", func() {
+		s.exclusive(ctx, "update_rates", func(ctx context.Context) {
+			log.Println("Cron job triggered: updating exchange rates")
 
-		if err := s.exchangeSvc.FetchAndStoreRates(ctx); err != nil {
-			log.Printf("ERROR: Failed to update exchange rates: %v", err)
-		} else {
-			log.Println("Cron job completed: exchange rates updated successfully")
-		}
-	})
+			if err := s.exchangeSvc.FetchAndStoreRates(ctx); err != nil {
+				log.Printf("ERROR: Failed to update exchange rates: %v", err)
+			} else {
+				log.Println("Cron job completed: exchange rates updated successfully")
+			}
+		})
+	}))
 
 	if err != nil {
 		return err
@@ -50,16 +66,30 @@ func (s *CronScheduler) Start(ctx context.Context, schedule string) error {
 	log.Println("Cron scheduler started")
 
 	// Выполняем первое обновление курсов сразу при старте
-	log.Println("Performing initial exchange rates update...")
-	if err := s.exchangeSvc.FetchAndStoreRates(ctx); err != nil {
-		log.Printf("WARNING: Failed initial exchange rates update: %v", err)
-	} else {
-		log.Println("Initial exchange rates update completed")
-	}
+	// При одновременном старте реплик курсы обновляет одна из них
+	s.exclusive(ctx, "update_rates", func(ctx context.Context) {
+		log.Println("Performing initial exchange rates update...")
+		if err := s.exchangeSvc.FetchAndStoreRates(ctx); err != nil {
+			log.Printf("WARNING: Failed initial exchange rates update: %v", err)
+		} else {
+			log.Println("Initial exchange rates update completed")
+		}
+	})
 
 	return nil
 }
 
+// exclusive выполняет задачу, если ее блокировку не держит другой экземпляр
+func (s *CronScheduler) exclusive(ctx context.Context, name string, job func(ctx context.Context)) {
+	if err := s.locker.Once(ctx, name, s.lockTTL, job); err != nil {
+		if errors.Is(err, lock.ErrNotAcquired) {
+			log.Printf("Cron job %s skipped: running on another instance", name)
+			return
+		}
+		log.Printf("ERROR: Cron job %s: %v", name, err)
+	}
+}
+
 // Stop останавливает планировщик задач
 func (s *CronScheduler) Stop() {
 	log.Println("Stopping cron scheduler...")
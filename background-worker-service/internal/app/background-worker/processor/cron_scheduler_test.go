@@ -7,9 +7,14 @@ import (
 	"time"
 
 	"augustberries/background-worker-service/internal/app/background-worker/entity"
+	"augustberries/pkg/lock"
+	"augustberries/pkg/money"
 
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockExchangeRateService мок для ExchangeRateServiceInterface
@@ -38,9 +43,9 @@ func (m *MockExchangeRateService) GetRates(ctx context.Context, currencies []str
 	return args.Get(0).(map[string]*entity.ExchangeRate), args.Error(1)
 }
 
-func (m *MockExchangeRateService) ConvertCurrency(ctx context.Context, amount float64, from, to string) (float64, float64, error) {
+func (m *MockExchangeRateService) ConvertCurrency(ctx context.Context, amount money.Amount, from, to string) (money.Amount, float64, error) {
 	args := m.Called(ctx, amount, from, to)
-	return args.Get(0).(float64), args.Get(1).(float64), args.Error(2)
+	return args.Get(0).(money.Amount), args.Get(1).(float64), args.Error(2)
 }
 
 func (m *MockExchangeRateService) EnsureRatesAvailable(ctx context.Context) error {
@@ -122,6 +127,32 @@ func TestCronScheduler_Start_InitialFetchError_ContinuesWork(t *testing.T) {
 	scheduler.Stop()
 }
 
+func TestCronScheduler_Start_LockedRunsOnce(t *testing.T) {
+	// Arrange: две реплики worker с общим Redis
+	server := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	mockSvc := new(MockExchangeRateService)
+	mockSvc.On("FetchAndStoreRates", mock.Anything).Return(nil)
+
+	first := NewCronScheduler(mockSvc)
+	first.SetLocker(lock.New(client, "background-worker"), time.Minute)
+	second := NewCronScheduler(mockSvc)
+	second.SetLocker(lock.New(client, "background-worker"), time.Minute)
+
+	ctx := context.Background()
+
+	// Act
+	require.NoError(t, first.Start(ctx, "*/5 * * * *"))
+	require.NoError(t, second.Start(ctx, "*/5 * * * *"))
+	first.Stop()
+	second.Stop()
+
+	// Assert: начальное обновление курсов выполнила одна реплика
+	mockSvc.AssertNumberOfCalls(t, "FetchAndStoreRates", 1)
+}
+
 // ===================== Stop Tests =====================
 
 func TestCronScheduler_Stop(t *testing.T) {
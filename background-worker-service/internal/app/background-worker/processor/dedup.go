@@ -0,0 +1,52 @@
+package processor
+
+import "sync"
+
+// processedEventsCapacity - сколько последних event_id помнит consumer
+// Повторная доставка обычно случается вскоре после исходной (перебалансировка, повтор после сбоя коммита)
+const processedEventsCapacity = 10000
+
+// processedEvents - ограниченное множество ID обработанных событий
+// При переполнении вытесняются самые старые. Nil-множество ничего не помнит
+type processedEvents struct {
+	mu    sync.Mutex
+	ids   map[string]struct{}
+	order []string
+	next  int
+}
+
+func newProcessedEvents(capacity int) *processedEvents {
+	return &processedEvents{
+		ids:   make(map[string]struct{}, capacity),
+		order: make([]string, capacity),
+	}
+}
+
+// Seen сообщает, было ли событие уже обработано
+func (p *processedEvents) Seen(id string) bool {
+	if p == nil || id == "" {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, ok := p.ids[id]
+	return ok
+}
+
+// Add запоминает обработанное событие
+func (p *processedEvents) Add(id string) {
+	if p == nil || id == "" {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.ids[id]; ok {
+		return
+	}
+	if old := p.order[p.next]; old != "" {
+		delete(p.ids, old)
+	}
+	p.order[p.next] = id
+	p.ids[id] = struct{}{}
+	p.next = (p.next + 1) % len(p.order)
+}
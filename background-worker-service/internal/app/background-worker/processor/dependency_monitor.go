@@ -0,0 +1,115 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"augustberries/pkg/kafka"
+	"augustberries/pkg/metrics"
+)
+
+// dependencyCheckTimeout - ограничение времени одной проверки зависимости
+const dependencyCheckTimeout = 3 * time.Second
+
+// DependencyCheck - проверка доступности зависимости (PostgreSQL, Redis)
+type DependencyCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// DependencyMonitor периодически проверяет зависимости обработчика событий
+// и ставит Kafka consumer на паузу, пока хотя бы одна из них недоступна
+type DependencyMonitor struct {
+	gate     *kafka.Gate
+	checks   []DependencyCheck
+	interval time.Duration
+	mu       sync.Mutex // Не дает параллельным проверкам перепутать порядок Pause/Resume
+	stopChan chan struct{}
+	doneChan chan struct{}
+}
+
+// NewDependencyMonitor создает монитор, управляющий паузой gate
+func NewDependencyMonitor(gate *kafka.Gate, interval time.Duration, checks ...DependencyCheck) *DependencyMonitor {
+	return &DependencyMonitor{
+		gate:     gate,
+		checks:   checks,
+		interval: interval,
+		stopChan: make(chan struct{}),
+		doneChan: make(chan struct{}),
+	}
+}
+
+// Start запускает периодические проверки
+func (m *DependencyMonitor) Start(ctx context.Context) {
+	go func() {
+		defer close(m.doneChan)
+
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-m.stopChan:
+				return
+			case <-ticker.C:
+				m.Check(ctx)
+			}
+		}
+	}()
+}
+
+// Stop останавливает проверки
+func (m *DependencyMonitor) Stop() {
+	close(m.stopChan)
+	<-m.doneChan
+}
+
+// Check проверяет все зависимости и ставит consumer на паузу или снимает ее
+// Возвращает ошибку первой недоступной зависимости
+func (m *DependencyMonitor) Check(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, dep := range m.checks {
+		checkCtx, cancel := context.WithTimeout(ctx, dependencyCheckTimeout)
+		err := dep.Check(checkCtx)
+		cancel()
+		if err != nil {
+			err = fmt.Errorf("%s: %w", dep.Name, err)
+			if m.gate.Pause(err.Error()) {
+				log.Printf("Kafka consumer paused: %v", err)
+				metrics.WorkerConsumerPaused.Set(1)
+			}
+			return err
+		}
+	}
+
+	if state := m.gate.State(); state.Paused && m.gate.Resume() {
+		log.Printf("Kafka consumer resumed after %s", time.Since(state.Since).Round(time.Second))
+		metrics.WorkerConsumerPaused.Set(0)
+	}
+	return nil
+}
+
+// State возвращает состояние паузы consumer
+func (m *DependencyMonitor) State() kafka.GateState {
+	return m.gate.State()
+}
+
+// classify помечает ошибку обработки как unavailable, если сейчас недоступна зависимость:
+// такое сообщение не уходит в DLQ и обрабатывается снова после снятия паузы
+func (m *DependencyMonitor) classify(ctx context.Context, err error) error {
+	if m == nil || err == nil || kafka.IsPermanent(err) {
+		return err
+	}
+	if depErr := m.Check(ctx); depErr != nil {
+		return kafka.Unavailable(errors.Join(err, depErr))
+	}
+	return err
+}
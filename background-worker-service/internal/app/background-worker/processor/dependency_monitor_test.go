@@ -0,0 +1,79 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"augustberries/background-worker-service/internal/app/background-worker/entity"
+	"augustberries/pkg/kafka"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// switchableCheck - проверка зависимости, результат которой задается в тесте
+type switchableCheck struct {
+	err error
+}
+
+func (c *switchableCheck) check(ctx context.Context) error {
+	return c.err
+}
+
+// ===================== DependencyMonitor Tests =====================
+
+func TestDependencyMonitor_PausesAndResumes(t *testing.T) {
+	// Arrange
+	database := &switchableCheck{}
+	redis := &switchableCheck{err: errors.New("connection refused")}
+	gate := kafka.NewGate()
+	monitor := NewDependencyMonitor(gate, 0,
+		DependencyCheck{Name: "database", Check: database.check},
+		DependencyCheck{Name: "redis", Check: redis.check},
+	)
+
+	// Act & Assert: Redis недоступен - пауза
+	err := monitor.Check(context.Background())
+	require.Error(t, err)
+	state := monitor.State()
+	assert.True(t, state.Paused)
+	assert.Equal(t, "redis: connection refused", state.Reason)
+
+	// Redis восстановился - пауза снята
+	redis.err = nil
+	require.NoError(t, monitor.Check(context.Background()))
+	assert.False(t, gate.Paused())
+}
+
+func TestKafkaConsumer_DependencyDownKeepsMessageOutOfDLQ(t *testing.T) {
+	// Arrange
+	orderSvc := new(MockOrderProcessingService)
+	exchangeSvc := new(MockExchangeRateService)
+	exchangeSvc.On("EnsureRatesAvailable", mock.Anything).Return(nil)
+
+	event := entity.OrderEvent{EventType: entity.EventTypeOrderCreated, OrderID: uuid.New()}
+	orderSvc.On("ProcessOrderEvent", mock.Anything, mock.Anything).Return(errors.New("dial tcp: connection refused"))
+
+	database := &switchableCheck{err: errors.New("connection refused")}
+	monitor := NewDependencyMonitor(kafka.NewGate(), 0, DependencyCheck{Name: "database", Check: database.check})
+
+	eventJSON, _ := json.Marshal(event)
+	reader := &fakeConsumer{messages: []kafka.Message{{Topic: "order_events", Value: eventJSON}}}
+	dlq := &fakeProducer{}
+	consumer := NewKafkaConsumer(reader, dlq, orderSvc, exchangeSvc, 1, 0, monitor)
+
+	// Act
+	consumer.Start(context.Background())
+	consumer.Stop()
+
+	// Assert: сообщение не закоммичено, не ушло в DLQ и не повторялось до восстановления БД
+	require.Len(t, reader.results, 1)
+	assert.True(t, kafka.IsUnavailable(reader.results[0]))
+	assert.Empty(t, dlq.published)
+	assert.True(t, monitor.State().Paused)
+	orderSvc.AssertNumberOfCalls(t, "ProcessOrderEvent", 1)
+}
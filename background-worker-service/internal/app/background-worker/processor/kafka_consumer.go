@@ -2,58 +2,82 @@ package processor
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
+	"slices"
 	"time"
 
 	"augustberries/background-worker-service/internal/app/background-worker/entity"
 	"augustberries/background-worker-service/internal/app/background-worker/service"
+	"augustberries/pkg/kafka"
 	"augustberries/pkg/metrics"
-
-	"github.com/segmentio/kafka-go"
 )
 
+// orderEventTypes - события заказов, которые consumer обрабатывает сам и умеет обрабатывать пачкой
+var orderEventTypes = []string{entity.EventTypeOrderCreated, entity.EventTypeOrderUpdated}
+
 type KafkaConsumer struct {
-	reader      *kafka.Reader
-	orderSvc    service.OrderProcessingServiceInterface
-	exchangeSvc service.ExchangeRateServiceInterface
-	topic       string
-	groupID     string
-	stopChan    chan struct{}
-	doneChan    chan struct{}
+	consumer      kafka.Consumer
+	deadLetter    kafka.Producer
+	orderSvc      service.OrderProcessingServiceInterface
+	exchangeSvc   service.ExchangeRateServiceInterface
+	notifier      service.OrderNotifierInterface // Письма покупателю после обработки события, nil - не отправляются
+	batchSize     int
+	flushInterval time.Duration
+	monitor       *DependencyMonitor
+	router        *EventRouter     // Обработчики по типу события: заказы и зарегистрированные через Handle
+	processed     *processedEvents // Недавно обработанные event_id для отсева повторных доставок
+	stopChan      chan struct{}
+	doneChan      chan struct{}
 }
 
+// NewKafkaConsumer создает обработчик событий заказов
+// deadLetter может быть nil - тогда необработанные сообщения не коммитятся и только логируются
+// batchSize > 1 включает пакетную обработку: до batchSize событий, но не дольше flushInterval
+// monitor может быть nil - тогда ошибки из-за недоступных зависимостей обрабатываются как обычные
 func NewKafkaConsumer(
-	brokers []string,
-	topic string,
-	groupID string,
-	minBytes int,
-	maxBytes int,
+	consumer kafka.Consumer,
+	deadLetter kafka.Producer,
 	orderSvc service.OrderProcessingServiceInterface,
 	exchangeSvc service.ExchangeRateServiceInterface,
+	batchSize int,
+	flushInterval time.Duration,
+	monitor *DependencyMonitor,
 ) *KafkaConsumer {
-	reader := kafka.NewReader(kafka.ReaderConfig{
-		Brokers:        brokers,
-		Topic:          topic,
-		GroupID:        groupID,
-		MinBytes:       minBytes,
-		MaxBytes:       maxBytes,
-		StartOffset:    kafka.LastOffset,
-		CommitInterval: time.Second,
-		ReadBackoffMin: 100 * time.Millisecond,
-		ReadBackoffMax: 1 * time.Second,
-	})
-
-	return &KafkaConsumer{
-		reader:      reader,
-		orderSvc:    orderSvc,
-		exchangeSvc: exchangeSvc,
-		topic:       topic,
-		groupID:     groupID,
-		stopChan:    make(chan struct{}),
-		doneChan:    make(chan struct{}),
+	c := &KafkaConsumer{
+		consumer:      consumer,
+		deadLetter:    deadLetter,
+		orderSvc:      orderSvc,
+		exchangeSvc:   exchangeSvc,
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		monitor:       monitor,
+		router:        NewEventRouter(),
+		processed:     newProcessedEvents(processedEventsCapacity),
+		stopChan:      make(chan struct{}),
+		doneChan:      make(chan struct{}),
 	}
+	for _, eventType := range orderEventTypes {
+		c.router.Handle(eventType, c.processOrderMessage)
+	}
+	return c
+}
+
+// Handle регистрирует обработчик событий другого типа, например из топика каталога или платежей
+// Вызывается до Start. Обработчик получает сообщение после отсева дублей, ошибка проходит повторы и DLQ
+func (c *KafkaConsumer) Handle(eventType string, handler kafka.Handler) {
+	c.router.Handle(eventType, c.instrument(eventType, handler))
+}
+
+// SetNotifier включает письма покупателю о заказе; вызывается до Start
+// Письмо отправляется после успешной обработки события, ошибка отправки не повторяет обработку
+func (c *KafkaConsumer) SetNotifier(notifier service.OrderNotifierInterface) {
+	c.notifier = notifier
+}
+
+// EventTypes возвращает типы событий, которые обрабатывает consumer
+func (c *KafkaConsumer) EventTypes() []string {
+	return c.router.EventTypes()
 }
 
 func (c *KafkaConsumer) Start(ctx context.Context) {
@@ -63,73 +87,207 @@ func (c *KafkaConsumer) Start(ctx context.Context) {
 		log.Printf("WARNING: Failed to ensure exchange rates available: %v", err)
 	}
 
-	go c.consume(ctx)
+	runCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		<-c.stopChan
+		cancel()
+	}()
+
+	go func() {
+		defer close(c.doneChan)
+
+		var err error
+		if c.batchSize > 1 {
+			err = c.consumer.RunBatch(runCtx, c.batchSize, c.flushInterval, c.processBatch)
+		} else {
+			err = c.consumer.Run(runCtx, c.handler())
+		}
+		if err != nil {
+			log.Printf("Kafka consumer stopped with error: %v", err)
+		}
+	}()
 }
 
 func (c *KafkaConsumer) Stop() {
 	log.Println("Stopping Kafka consumer...")
 	close(c.stopChan)
 	<-c.doneChan
-	c.reader.Close()
+	c.consumer.Close()
 	log.Println("Kafka consumer stopped")
 }
 
-func (c *KafkaConsumer) consume(ctx context.Context) {
-	defer close(c.doneChan)
-
-	for {
-		select {
-		case <-c.stopChan:
-			return
-		default:
-			readCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
-			message, err := c.reader.FetchMessage(readCtx)
-			cancel()
-
-			if err != nil {
-				if ctx.Err() != nil {
-					return
-				}
-				log.Printf("Error fetching message: %v", err)
-				time.Sleep(time.Second)
-				continue
-			}
-
-			if err := c.processMessage(ctx, message); err != nil {
-				log.Printf("Error processing message: %v", err)
-				metrics.KafkaErrors.WithLabelValues("background-worker", c.topic, "consume").Inc()
-			} else {
-				if err := c.reader.CommitMessages(ctx, message); err != nil {
-					log.Printf("Error committing message: %v", err)
-				}
-			}
-		}
+// handler собирает цепочку обработки: повторы при временных ошибках, затем DLQ
+func (c *KafkaConsumer) handler() kafka.Handler {
+	handler := kafka.Retry(c.processMessage, kafka.DefaultRetryPolicy)
+	if c.deadLetter != nil {
+		handler = kafka.DeadLetter(handler, c.deadLetter)
 	}
+	return handler
 }
 
+// skip отсеивает сообщения по заголовкам, не разбирая значение:
+// события, которые worker не обрабатывает, и повторные доставки уже обработанных событий.
+// Сообщения без заголовков (отправленные до их появления) не отсеиваются
+func (c *KafkaConsumer) skip(meta kafka.EventMeta) bool {
+	if _, ok := c.router.Route(meta.Type); meta.Type != "" && !ok {
+		metrics.WorkerEventsSkipped.WithLabelValues("unhandled_type").Inc()
+		return true
+	}
+	if c.processed.Seen(meta.ID) {
+		log.Printf("Skipping duplicate %s event %s from %s", meta.Type, meta.ID, meta.Producer)
+		metrics.WorkerEventsSkipped.WithLabelValues("duplicate").Inc()
+		return true
+	}
+	return false
+}
+
+// processMessage направляет сообщение обработчику по типу события
+// Сообщения без заголовков отправлены до их появления - это события заказов
 func (c *KafkaConsumer) processMessage(ctx context.Context, message kafka.Message) error {
+	meta := kafka.MetaFromHeaders(message.Headers)
+	if c.skip(meta) {
+		return nil
+	}
+
+	handler := c.processOrderMessage
+	if meta.Type != "" {
+		handler, _ = c.router.Route(meta.Type)
+	}
+	if err := handler(ctx, message); err != nil {
+		return err
+	}
+
+	c.processed.Add(meta.ID)
+	return nil
+}
+
+// instrument добавляет обработчику метрики длительности и классификацию ошибок недоступных зависимостей
+func (c *KafkaConsumer) instrument(eventType string, handler kafka.Handler) kafka.Handler {
+	return func(ctx context.Context, message kafka.Message) error {
+		start := time.Now()
+		if err := handler(ctx, message); err != nil {
+			metrics.WorkerEventProcessingDuration.WithLabelValues(eventType, "failed").Observe(time.Since(start).Seconds())
+			return c.monitor.classify(ctx, fmt.Errorf("failed to process %s event: %w", eventType, err))
+		}
+		metrics.WorkerEventProcessingDuration.WithLabelValues(eventType, "success").Observe(time.Since(start).Seconds())
+		return nil
+	}
+}
+
+// processOrderMessage обрабатывает событие заказа
+func (c *KafkaConsumer) processOrderMessage(ctx context.Context, message kafka.Message) error {
 	start := time.Now()
+	meta := kafka.MetaFromHeaders(message.Headers)
 
 	var event entity.OrderEvent
-	if err := json.Unmarshal(message.Value, &event); err != nil {
-		return fmt.Errorf("failed to unmarshal order event: %w", err)
+	if err := kafka.Decode(kafka.JSONCodec{}, message, &event); err != nil {
+		// Повтор не поможет: сообщение сразу уходит в DLQ
+		return kafka.Permanent(fmt.Errorf("failed to unmarshal order event: %w", err))
 	}
 
-	log.Printf("Received %s event for order %s", event.EventType, event.OrderID)
+	if meta.ID != "" {
+		log.Printf("Received %s event for order %s (event_id: %s, producer: %s, trace_id: %s)",
+			event.EventType, event.OrderID, meta.ID, meta.Producer, meta.TraceID())
+	} else {
+		log.Printf("Received %s event for order %s", event.EventType, event.OrderID)
+	}
 
 	if err := c.orderSvc.ProcessOrderEvent(ctx, &event); err != nil {
 		metrics.WorkerOrdersProcessed.WithLabelValues("failed").Inc()
-		return fmt.Errorf("failed to process order event: %w", err)
+		metrics.WorkerEventProcessingDuration.WithLabelValues(event.EventType, "failed").Observe(time.Since(start).Seconds())
+		// При недоступной БД или Redis consumer встает на паузу, а событие ждет восстановления
+		return c.monitor.classify(ctx, fmt.Errorf("failed to process order event: %w", err))
 	}
 
-	metrics.KafkaMessagesConsumed.WithLabelValues("background-worker", c.topic, c.groupID).Inc()
-	metrics.KafkaConsumeDuration.WithLabelValues("background-worker", c.topic).Observe(time.Since(start).Seconds())
 	metrics.WorkerOrdersProcessed.WithLabelValues("success").Inc()
 	metrics.WorkerProcessingDuration.Observe(time.Since(start).Seconds())
+	metrics.WorkerEventProcessingDuration.WithLabelValues(event.EventType, "success").Observe(time.Since(start).Seconds())
+
+	c.notify(ctx, &event)
+	return nil
+}
+
+// notify отправляет письмо по обработанному событию заказа
+// Повтор события ради письма сконвертировал бы заказ заново, поэтому ошибка только логируется
+func (c *KafkaConsumer) notify(ctx context.Context, event *entity.OrderEvent) {
+	if c.notifier == nil {
+		return
+	}
+	if err := c.notifier.NotifyOrderEvent(ctx, event); err != nil {
+		log.Printf("Failed to notify customer about %s event for order %s: %v", event.EventType, event.OrderID, err)
+	}
+}
+
+// processBatch обрабатывает пачку событий заказов одним проходом по БД
+// События других типов, а также сообщения, которые не удалось разобрать или обработать в пачке,
+// проходят обычную цепочку с повторами и DLQ по одному; ошибка любого из них оставляет пачку незакоммиченной
+func (c *KafkaConsumer) processBatch(ctx context.Context, messages []kafka.Message) error {
+	start := time.Now()
+
+	events := make([]*entity.OrderEvent, 0, len(messages))
+	decoded := make([]kafka.Message, 0, len(messages))
+	var fallback []kafka.Message
+
+	for _, message := range messages {
+		meta := kafka.MetaFromHeaders(message.Headers)
+		if c.skip(meta) {
+			continue
+		}
+		if meta.Type != "" && !slices.Contains(orderEventTypes, meta.Type) {
+			fallback = append(fallback, message)
+			continue
+		}
+		var event entity.OrderEvent
+		if err := kafka.Decode(kafka.JSONCodec{}, message, &event); err != nil {
+			fallback = append(fallback, message)
+			continue
+		}
+		events = append(events, &event)
+		decoded = append(decoded, message)
+	}
+
+	var errs []error
+	if len(events) > 0 {
+		errs = c.orderSvc.ProcessOrderEventsBatch(ctx, events)
+	}
+
+	// Длительность пачки делится поровну между ее событиями
+	perEvent := time.Since(start).Seconds() / float64(len(messages))
+	for i, event := range events {
+		if errs[i] != nil {
+			log.Printf("Batch processing of %s event for order %s failed, retrying individually: %v",
+				event.EventType, event.OrderID, errs[i])
+			fallback = append(fallback, decoded[i])
+			continue
+		}
+		c.processed.Add(decoded[i].Headers[kafka.HeaderEventID])
+		metrics.WorkerOrdersProcessed.WithLabelValues("success").Inc()
+		metrics.WorkerProcessingDuration.Observe(perEvent)
+		metrics.WorkerEventProcessingDuration.WithLabelValues(event.EventType, "success").Observe(perEvent)
+		c.notify(ctx, event)
+	}
+
+	handler := c.handler()
+	var failed int
+	redeliver := false
+	for _, message := range fallback {
+		if err := handler(ctx, message); err != nil {
+			failed++
+			redeliver = redeliver || kafka.IsRedeliver(err)
+		}
+	}
+	if failed > 0 {
+		err := fmt.Errorf("%d of %d messages in batch not processed", failed, len(messages))
+		if redeliver {
+			// Сообщение не удалось отложить в DLQ: пачка не коммитится и обрабатывается снова
+			return kafka.Redeliver(err)
+		}
+		return err
+	}
 
 	return nil
 }
 
-func (c *KafkaConsumer) GetStats() kafka.ReaderStats {
-	return c.reader.Stats()
+func (c *KafkaConsumer) GetStats() kafka.Stats {
+	return c.consumer.Stats()
 }
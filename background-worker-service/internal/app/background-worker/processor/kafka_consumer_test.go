@@ -8,11 +8,13 @@ import (
 	"time"
 
 	"augustberries/background-worker-service/internal/app/background-worker/entity"
+	"augustberries/pkg/kafka"
+	"augustberries/pkg/money"
 
 	"github.com/google/uuid"
-	"github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockOrderProcessingService мок для OrderProcessingServiceInterface
@@ -25,6 +27,67 @@ func (m *MockOrderProcessingService) ProcessOrderEvent(ctx context.Context, even
 	return args.Error(0)
 }
 
+func (m *MockOrderProcessingService) ProcessOrderEventsBatch(ctx context.Context, events []*entity.OrderEvent) []error {
+	args := m.Called(ctx, events)
+	return args.Get(0).([]error)
+}
+
+// fakeConsumer отдает заранее заданные сообщения и запоминает результат обработки
+type fakeConsumer struct {
+	messages []kafka.Message
+	results  []error
+	closed   bool
+}
+
+func (f *fakeConsumer) Run(ctx context.Context, handler kafka.Handler) error {
+	for _, m := range f.messages {
+		f.results = append(f.results, handler(ctx, m))
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func (f *fakeConsumer) RunBatch(ctx context.Context, size int, flushInterval time.Duration, handler kafka.BatchHandler) error {
+	for start := 0; start < len(f.messages); start += size {
+		end := min(start+size, len(f.messages))
+		err := handler(ctx, f.messages[start:end])
+		for range f.messages[start:end] {
+			f.results = append(f.results, err)
+		}
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func (f *fakeConsumer) Stats() kafka.Stats {
+	return kafka.Stats{Topic: "order_events"}
+}
+
+func (f *fakeConsumer) Close() error {
+	f.closed = true
+	return nil
+}
+
+// fakeProducer запоминает отправленные в DLQ сообщения
+type fakeProducer struct {
+	published []kafka.Message
+	err       error // DLQ недоступна
+}
+
+func (f *fakeProducer) PublishMessage(ctx context.Context, key string, value []byte) error {
+	return f.Publish(ctx, kafka.Message{Key: []byte(key), Value: value})
+}
+
+func (f *fakeProducer) Publish(ctx context.Context, messages ...kafka.Message) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.published = append(f.published, messages...)
+	return nil
+}
+
+func (f *fakeProducer) Close() error { return nil }
+
 // ===================== NewKafkaConsumer Tests =====================
 
 func TestNewKafkaConsumer(t *testing.T) {
@@ -32,42 +95,40 @@ func TestNewKafkaConsumer(t *testing.T) {
 	orderSvc := new(MockOrderProcessingService)
 	exchangeSvc := new(MockExchangeRateService)
 
-	brokers := []string{"localhost:9092"}
-	topic := "order_events"
-	groupID := "test-group"
-
 	// Act
-	consumer := NewKafkaConsumer(brokers, topic, groupID, 1, 10e6, orderSvc, exchangeSvc)
+	consumer := NewKafkaConsumer(&fakeConsumer{}, nil, orderSvc, exchangeSvc, 1, 0, nil)
 
 	// Assert
 	assert.NotNil(t, consumer)
-	assert.NotNil(t, consumer.reader)
+	assert.NotNil(t, consumer.consumer)
+	assert.Nil(t, consumer.deadLetter)
 	assert.NotNil(t, consumer.orderSvc)
 	assert.NotNil(t, consumer.exchangeSvc)
 	assert.NotNil(t, consumer.stopChan)
 	assert.NotNil(t, consumer.doneChan)
-
-	// Cleanup
-	consumer.reader.Close()
 }
 
-func TestNewKafkaConsumer_MultipleBrokers(t *testing.T) {
+func TestNewKafkaConsumer_RealReader(t *testing.T) {
 	// Arrange
 	orderSvc := new(MockOrderProcessingService)
 	exchangeSvc := new(MockExchangeRateService)
 
-	brokers := []string{"broker1:9092", "broker2:9092", "broker3:9092"}
-	topic := "order_events"
-	groupID := "test-group"
+	reader := kafka.NewConsumer(kafka.ConsumerConfig{
+		Brokers:  []string{"broker1:9092", "broker2:9092", "broker3:9092"},
+		Topic:    "order_events",
+		GroupID:  "test-group",
+		MinBytes: 1024,
+		MaxBytes: 10e6,
+	})
 
 	// Act
-	consumer := NewKafkaConsumer(brokers, topic, groupID, 1024, 10e6, orderSvc, exchangeSvc)
+	consumer := NewKafkaConsumer(reader, nil, orderSvc, exchangeSvc, 1, 0, nil)
 
 	// Assert
 	assert.NotNil(t, consumer)
 
 	// Cleanup
-	consumer.reader.Close()
+	reader.Close()
 }
 
 // ===================== processMessage Tests =====================
@@ -92,7 +153,7 @@ func TestKafkaConsumer_ProcessMessage_Success(t *testing.T) {
 		EventType:  entity.EventTypeOrderCreated,
 		OrderID:    orderID,
 		UserID:     userID,
-		TotalPrice: 100.0,
+		TotalPrice: money.MustParse("100.00"),
 		Currency:   "USD",
 		Timestamp:  time.Now(),
 	}
@@ -268,28 +329,60 @@ func TestKafkaConsumer_StartStop(t *testing.T) {
 // ===================== GetStats Tests =====================
 
 func TestKafkaConsumer_GetStats(t *testing.T) {
+	// Arrange
+	consumer := NewKafkaConsumer(&fakeConsumer{}, nil, new(MockOrderProcessingService), new(MockExchangeRateService), 1, 0, nil)
+
+	// Act
+	stats := consumer.GetStats()
+
+	// Assert
+	assert.Equal(t, "order_events", stats.Topic)
+}
+
+// ===================== Dead Letter Tests =====================
+
+func TestKafkaConsumer_InvalidMessageGoesToDLQ(t *testing.T) {
+	// Невалидное сообщение не повторяется и сразу уходит в DLQ
 	// Arrange
 	orderSvc := new(MockOrderProcessingService)
 	exchangeSvc := new(MockExchangeRateService)
+	exchangeSvc.On("EnsureRatesAvailable", mock.Anything).Return(nil)
 
-	consumer := NewKafkaConsumer(
-		[]string{"localhost:9092"},
-		"order_events",
-		"test-group",
-		1,
-		10e6,
-		orderSvc,
-		exchangeSvc,
-	)
+	reader := &fakeConsumer{messages: []kafka.Message{{Topic: "order_events", Offset: 7, Value: []byte("{{{")}}}
+	dlq := &fakeProducer{}
+
+	consumer := NewKafkaConsumer(reader, dlq, orderSvc, exchangeSvc, 1, 0, nil)
 
 	// Act
-	stats := consumer.GetStats()
+	consumer.Start(context.Background())
+	consumer.Stop()
 
 	// Assert
-	assert.Equal(t, "order_events", stats.Topic)
+	assert.Equal(t, []error{nil}, reader.results)
+	assert.True(t, reader.closed)
+	if assert.Len(t, dlq.published, 1) {
+		assert.Equal(t, "7", dlq.published[0].Headers[kafka.HeaderDLQOriginalOffset])
+		assert.Contains(t, dlq.published[0].Headers[kafka.HeaderDLQError], "failed to unmarshal")
+	}
+	orderSvc.AssertNotCalled(t, "ProcessOrderEvent", mock.Anything, mock.Anything)
+}
 
-	// Cleanup
-	consumer.reader.Close()
+func TestKafkaConsumer_WithoutDLQ_ErrorNotCommitted(t *testing.T) {
+	// Arrange
+	orderSvc := new(MockOrderProcessingService)
+	exchangeSvc := new(MockExchangeRateService)
+	exchangeSvc.On("EnsureRatesAvailable", mock.Anything).Return(nil)
+
+	reader := &fakeConsumer{messages: []kafka.Message{{Value: []byte("{{{")}}}
+	consumer := NewKafkaConsumer(reader, nil, orderSvc, exchangeSvc, 1, 0, nil)
+
+	// Act
+	consumer.Start(context.Background())
+	consumer.Stop()
+
+	// Assert
+	require.Len(t, reader.results, 1)
+	assert.Error(t, reader.results[0])
 }
 
 // ===================== Message Parsing Tests =====================
@@ -314,7 +407,7 @@ func TestKafkaConsumer_ProcessMessage_AllEventFields(t *testing.T) {
 		EventType:  entity.EventTypeOrderCreated,
 		OrderID:    orderID,
 		UserID:     userID,
-		TotalPrice: 150.50,
+		TotalPrice: money.MustParse("150.50"),
 		Currency:   "EUR",
 		Status:     entity.OrderStatusPending,
 		ItemsCount: 5,
@@ -337,7 +430,7 @@ func TestKafkaConsumer_ProcessMessage_AllEventFields(t *testing.T) {
 	assert.NotNil(t, capturedEvent)
 	assert.Equal(t, orderID, capturedEvent.OrderID)
 	assert.Equal(t, userID, capturedEvent.UserID)
-	assert.Equal(t, 150.50, capturedEvent.TotalPrice)
+	assert.Equal(t, money.MustParse("150.50"), capturedEvent.TotalPrice)
 	assert.Equal(t, "EUR", capturedEvent.Currency)
 	assert.Equal(t, entity.OrderStatusPending, capturedEvent.Status)
 	assert.Equal(t, 5, capturedEvent.ItemsCount)
@@ -372,3 +465,298 @@ func TestKafkaConsumer_ProcessMessage_UnknownEventType(t *testing.T) {
 	assert.NoError(t, err)
 	orderSvc.AssertExpectations(t)
 }
+
+// ===================== Event Header Tests =====================
+
+func TestKafkaConsumer_ProcessMessage_SkipsUnhandledTypeByHeader(t *testing.T) {
+	// Событие другого типа отсеивается по заголовку, значение не разбирается
+	// Arrange
+	orderSvc := new(MockOrderProcessingService)
+	consumer := NewKafkaConsumer(&fakeConsumer{}, nil, orderSvc, new(MockExchangeRateService), 1, 0, nil)
+
+	message := kafka.Message{
+		Value:   []byte("not json"),
+		Headers: map[string]string{kafka.HeaderEventType: "PRODUCT_UPDATED", kafka.HeaderEventID: uuid.NewString()},
+	}
+
+	// Act
+	err := consumer.processMessage(context.Background(), message)
+
+	// Assert
+	assert.NoError(t, err)
+	orderSvc.AssertNotCalled(t, "ProcessOrderEvent", mock.Anything, mock.Anything)
+}
+
+func TestKafkaConsumer_ProcessMessage_SkipsDuplicateEventID(t *testing.T) {
+	// Arrange
+	orderSvc := new(MockOrderProcessingService)
+	consumer := NewKafkaConsumer(&fakeConsumer{}, nil, orderSvc, new(MockExchangeRateService), 1, 0, nil)
+
+	eventJSON, _ := json.Marshal(entity.OrderEvent{EventType: entity.EventTypeOrderCreated, OrderID: uuid.New()})
+	message := kafka.Message{
+		Value:   eventJSON,
+		Headers: map[string]string{kafka.HeaderEventType: entity.EventTypeOrderCreated, kafka.HeaderEventID: uuid.NewString()},
+	}
+	orderSvc.On("ProcessOrderEvent", mock.Anything, mock.Anything).Return(nil)
+
+	// Act
+	require.NoError(t, consumer.processMessage(context.Background(), message))
+	require.NoError(t, consumer.processMessage(context.Background(), message))
+
+	// Assert
+	orderSvc.AssertNumberOfCalls(t, "ProcessOrderEvent", 1)
+}
+
+func TestKafkaConsumer_ProcessMessage_FailedEventNotRemembered(t *testing.T) {
+	// Необработанное событие не считается дубликатом при повторной доставке
+	// Arrange
+	orderSvc := new(MockOrderProcessingService)
+	consumer := NewKafkaConsumer(&fakeConsumer{}, nil, orderSvc, new(MockExchangeRateService), 1, 0, nil)
+
+	eventJSON, _ := json.Marshal(entity.OrderEvent{EventType: entity.EventTypeOrderCreated, OrderID: uuid.New()})
+	message := kafka.Message{Value: eventJSON, Headers: map[string]string{kafka.HeaderEventID: uuid.NewString()}}
+	orderSvc.On("ProcessOrderEvent", mock.Anything, mock.Anything).Return(errors.New("db error")).Once()
+	orderSvc.On("ProcessOrderEvent", mock.Anything, mock.Anything).Return(nil).Once()
+
+	// Act
+	firstErr := consumer.processMessage(context.Background(), message)
+	secondErr := consumer.processMessage(context.Background(), message)
+
+	// Assert
+	assert.Error(t, firstErr)
+	assert.NoError(t, secondErr)
+	orderSvc.AssertNumberOfCalls(t, "ProcessOrderEvent", 2)
+}
+
+func TestProcessedEvents_EvictsOldest(t *testing.T) {
+	processed := newProcessedEvents(2)
+
+	processed.Add("a")
+	processed.Add("b")
+	processed.Add("c")
+
+	assert.False(t, processed.Seen("a"))
+	assert.True(t, processed.Seen("b"))
+	assert.True(t, processed.Seen("c"))
+	assert.False(t, (*processedEvents)(nil).Seen("b"))
+}
+
+// ===================== Batch Tests =====================
+
+func TestKafkaConsumer_Batch_ProcessesEventsTogether(t *testing.T) {
+	// Arrange
+	orderSvc := new(MockOrderProcessingService)
+	exchangeSvc := new(MockExchangeRateService)
+	exchangeSvc.On("EnsureRatesAvailable", mock.Anything).Return(nil)
+
+	first, _ := json.Marshal(entity.OrderEvent{EventType: entity.EventTypeOrderCreated, OrderID: uuid.New()})
+	second, _ := json.Marshal(entity.OrderEvent{EventType: entity.EventTypeOrderCreated, OrderID: uuid.New()})
+	reader := &fakeConsumer{messages: []kafka.Message{{Value: first}, {Value: second}}}
+
+	orderSvc.On("ProcessOrderEventsBatch", mock.Anything, mock.MatchedBy(func(events []*entity.OrderEvent) bool {
+		return len(events) == 2
+	})).Return([]error{nil, nil})
+
+	consumer := NewKafkaConsumer(reader, nil, orderSvc, exchangeSvc, 10, time.Second, nil)
+
+	// Act
+	consumer.Start(context.Background())
+	consumer.Stop()
+
+	// Assert
+	assert.Equal(t, []error{nil, nil}, reader.results)
+	orderSvc.AssertNumberOfCalls(t, "ProcessOrderEventsBatch", 1)
+	orderSvc.AssertNotCalled(t, "ProcessOrderEvent", mock.Anything, mock.Anything)
+}
+
+func TestKafkaConsumer_Batch_FailedEventRetriedIndividually(t *testing.T) {
+	// Событие, не обработанное в пачке, проходит обычную цепочку с повторами и DLQ
+	// Arrange
+	orderSvc := new(MockOrderProcessingService)
+	exchangeSvc := new(MockExchangeRateService)
+	exchangeSvc.On("EnsureRatesAvailable", mock.Anything).Return(nil)
+
+	okID, failedID := uuid.New(), uuid.New()
+	ok, _ := json.Marshal(entity.OrderEvent{EventType: entity.EventTypeOrderCreated, OrderID: okID})
+	failed, _ := json.Marshal(entity.OrderEvent{EventType: entity.EventTypeOrderCreated, OrderID: failedID})
+	reader := &fakeConsumer{messages: []kafka.Message{{Value: ok}, {Value: failed}}}
+
+	orderSvc.On("ProcessOrderEventsBatch", mock.Anything, mock.Anything).
+		Return([]error{nil, errors.New("rate for GBP to RUB not found")})
+	orderSvc.On("ProcessOrderEvent", mock.Anything, mock.MatchedBy(func(event *entity.OrderEvent) bool {
+		return event.OrderID == failedID
+	})).Return(nil)
+
+	consumer := NewKafkaConsumer(reader, nil, orderSvc, exchangeSvc, 10, time.Second, nil)
+
+	// Act
+	consumer.Start(context.Background())
+	consumer.Stop()
+
+	// Assert
+	assert.Equal(t, []error{nil, nil}, reader.results)
+	orderSvc.AssertNumberOfCalls(t, "ProcessOrderEvent", 1)
+}
+
+func TestKafkaConsumer_Batch_DLQFailureRedeliversBatch(t *testing.T) {
+	// Невалидное сообщение пачки некуда отложить: пачка не должна быть закоммичена
+	// Arrange
+	consumer := NewKafkaConsumer(&fakeConsumer{}, &fakeProducer{err: errors.New("kafka down")},
+		new(MockOrderProcessingService), new(MockExchangeRateService), 10, time.Second, nil)
+
+	// Act
+	err := consumer.processBatch(context.Background(), []kafka.Message{{Topic: "order_events", Offset: 7, Value: []byte("{{{")}})
+
+	// Assert
+	require.Error(t, err)
+	assert.True(t, kafka.IsRedeliver(err))
+}
+
+// ===================== Event Router Tests =====================
+
+func TestKafkaConsumer_Handle_DispatchesByEventType(t *testing.T) {
+	// Arrange
+	orderSvc := new(MockOrderProcessingService)
+	consumer := NewKafkaConsumer(&fakeConsumer{}, nil, orderSvc, new(MockExchangeRateService), 1, 0, nil)
+
+	var received []kafka.Message
+	consumer.Handle("PRICE_CHANGED", func(ctx context.Context, message kafka.Message) error {
+		received = append(received, message)
+		return nil
+	})
+
+	message := kafka.Message{
+		Topic:   "product_events",
+		Value:   []byte(`{"event_type":"PRICE_CHANGED"}`),
+		Headers: map[string]string{kafka.HeaderEventType: "PRICE_CHANGED", kafka.HeaderEventID: uuid.NewString()},
+	}
+
+	// Act
+	require.NoError(t, consumer.processMessage(context.Background(), message))
+	require.NoError(t, consumer.processMessage(context.Background(), message))
+
+	// Assert
+	require.Len(t, received, 1, "повторная доставка отсеивается по event_id")
+	assert.Equal(t, "product_events", received[0].Topic)
+	orderSvc.AssertNotCalled(t, "ProcessOrderEvent", mock.Anything, mock.Anything)
+	assert.Equal(t, []string{"ORDER_CREATED", "ORDER_UPDATED", "PRICE_CHANGED"}, consumer.EventTypes())
+}
+
+func TestKafkaConsumer_Handle_ErrorIsReturned(t *testing.T) {
+	// Arrange
+	consumer := NewKafkaConsumer(&fakeConsumer{}, nil, new(MockOrderProcessingService), new(MockExchangeRateService), 1, 0, nil)
+	consumer.Handle("PAYMENT_CAPTURED", func(ctx context.Context, message kafka.Message) error {
+		return errors.New("payment not found")
+	})
+
+	message := kafka.Message{Headers: map[string]string{kafka.HeaderEventType: "PAYMENT_CAPTURED", kafka.HeaderEventID: uuid.NewString()}}
+
+	// Act
+	err := consumer.processMessage(context.Background(), message)
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to process PAYMENT_CAPTURED event")
+	assert.False(t, consumer.processed.Seen(message.Headers[kafka.HeaderEventID]))
+}
+
+func TestKafkaConsumer_Batch_OtherEventsHandledIndividually(t *testing.T) {
+	// События других топиков в пачке не попадают в пакетную обработку заказов
+	// Arrange
+	orderSvc := new(MockOrderProcessingService)
+	exchangeSvc := new(MockExchangeRateService)
+	exchangeSvc.On("EnsureRatesAvailable", mock.Anything).Return(nil)
+
+	order, _ := json.Marshal(entity.OrderEvent{EventType: entity.EventTypeOrderCreated, OrderID: uuid.New()})
+	reader := &fakeConsumer{messages: []kafka.Message{
+		{Topic: "order_events", Value: order, Headers: map[string]string{kafka.HeaderEventType: entity.EventTypeOrderCreated}},
+		{Topic: "product_events", Value: []byte(`{}`), Headers: map[string]string{kafka.HeaderEventType: "PRICE_CHANGED"}},
+	}}
+
+	orderSvc.On("ProcessOrderEventsBatch", mock.Anything, mock.MatchedBy(func(events []*entity.OrderEvent) bool {
+		return len(events) == 1
+	})).Return([]error{nil})
+
+	consumer := NewKafkaConsumer(reader, nil, orderSvc, exchangeSvc, 10, time.Second, nil)
+	var priceChanges int
+	consumer.Handle("PRICE_CHANGED", func(ctx context.Context, message kafka.Message) error {
+		priceChanges++
+		return nil
+	})
+
+	// Act
+	consumer.Start(context.Background())
+	consumer.Stop()
+
+	// Assert
+	assert.Equal(t, []error{nil, nil}, reader.results)
+	assert.Equal(t, 1, priceChanges)
+	orderSvc.AssertNumberOfCalls(t, "ProcessOrderEventsBatch", 1)
+}
+
+// ===================== Notifier Tests =====================
+
+// MockOrderNotifier мок для OrderNotifierInterface
+type MockOrderNotifier struct {
+	mock.Mock
+}
+
+func (m *MockOrderNotifier) NotifyOrderEvent(ctx context.Context, event *entity.OrderEvent) error {
+	return m.Called(ctx, event).Error(0)
+}
+
+func TestKafkaConsumer_NotifiesAfterProcessing(t *testing.T) {
+	// Arrange
+	orderSvc := new(MockOrderProcessingService)
+	exchangeSvc := new(MockExchangeRateService)
+	notifier := new(MockOrderNotifier)
+	consumer := NewKafkaConsumer(&fakeConsumer{}, nil, orderSvc, exchangeSvc, 1, time.Second, nil)
+	consumer.SetNotifier(notifier)
+
+	ctx := context.Background()
+	processed, _ := json.Marshal(entity.OrderEvent{EventType: entity.EventTypeOrderCreated, OrderID: uuid.New()})
+	failed, _ := json.Marshal(entity.OrderEvent{EventType: entity.EventTypeOrderCreated, OrderID: uuid.New()})
+
+	orderSvc.On("ProcessOrderEvent", ctx, mock.Anything).Return(nil).Once()
+	orderSvc.On("ProcessOrderEvent", ctx, mock.Anything).Return(errors.New("db error")).Once()
+	// Ошибка письма не делает событие необработанным
+	notifier.On("NotifyOrderEvent", ctx, mock.Anything).Return(errors.New("smtp unavailable"))
+
+	// Act
+	errProcessed := consumer.processMessage(ctx, kafka.Message{Value: processed})
+	errFailed := consumer.processMessage(ctx, kafka.Message{Value: failed})
+
+	// Assert
+	assert.NoError(t, errProcessed)
+	assert.Error(t, errFailed)
+	notifier.AssertNumberOfCalls(t, "NotifyOrderEvent", 1)
+}
+
+func TestKafkaConsumer_Batch_NotifiesProcessedEvents(t *testing.T) {
+	// Arrange
+	orderSvc := new(MockOrderProcessingService)
+	exchangeSvc := new(MockExchangeRateService)
+	exchangeSvc.On("EnsureRatesAvailable", mock.Anything).Return(nil)
+	notifier := new(MockOrderNotifier)
+
+	orderID := uuid.New()
+	first, _ := json.Marshal(entity.OrderEvent{EventType: entity.EventTypeOrderCreated, OrderID: orderID})
+	second, _ := json.Marshal(entity.OrderEvent{EventType: entity.EventTypeOrderCreated, OrderID: uuid.New()})
+	reader := &fakeConsumer{messages: []kafka.Message{{Value: first}, {Value: second}}}
+
+	orderSvc.On("ProcessOrderEventsBatch", mock.Anything, mock.Anything).Return([]error{nil, errors.New("rates unavailable")})
+	orderSvc.On("ProcessOrderEvent", mock.Anything, mock.Anything).Return(kafka.Permanent(errors.New("rates unavailable")))
+	notifier.On("NotifyOrderEvent", mock.Anything, mock.MatchedBy(func(event *entity.OrderEvent) bool {
+		return event.OrderID == orderID
+	})).Return(nil)
+
+	consumer := NewKafkaConsumer(reader, nil, orderSvc, exchangeSvc, 10, time.Second, nil)
+	consumer.SetNotifier(notifier)
+
+	// Act
+	consumer.Start(context.Background())
+	consumer.Stop()
+
+	// Assert
+	notifier.AssertNumberOfCalls(t, "NotifyOrderEvent", 1)
+}
@@ -0,0 +1,44 @@
+package processor
+
+import (
+	"sort"
+
+	"augustberries/pkg/kafka"
+)
+
+// EventRouter направляет сообщения из нескольких топиков обработчикам по типу события (заголовок event_type)
+// Новый вид событий подключается регистрацией обработчика, без отдельного consumer и бинарника
+type EventRouter struct {
+	handlers map[string]kafka.Handler
+}
+
+func NewEventRouter() *EventRouter {
+	return &EventRouter{handlers: make(map[string]kafka.Handler)}
+}
+
+// Handle регистрирует обработчик событий типа eventType; повторная регистрация заменяет обработчик
+func (r *EventRouter) Handle(eventType string, handler kafka.Handler) {
+	r.handlers[eventType] = handler
+}
+
+// Route возвращает обработчик события; nil-роутер не знает ни одного типа
+func (r *EventRouter) Route(eventType string) (kafka.Handler, bool) {
+	if r == nil {
+		return nil, false
+	}
+	handler, ok := r.handlers[eventType]
+	return handler, ok
+}
+
+// EventTypes возвращает зарегистрированные типы событий по алфавиту
+func (r *EventRouter) EventTypes() []string {
+	if r == nil {
+		return nil
+	}
+	types := make([]string, 0, len(r.handlers))
+	for eventType := range r.handlers {
+		types = append(types, eventType)
+	}
+	sort.Strings(types)
+	return types
+}
@@ -12,12 +12,12 @@ import (
 
 // exchangeRateRepository реализует ExchangeRateRepository для работы с Redis
 type exchangeRateRepository struct {
-	client *redis.Client
+	client redis.UniversalClient
 	ttl    time.Duration // TTL для курсов валют
 }
 
 // NewExchangeRateRepository создает новый репозиторий курсов валют
-func NewExchangeRateRepository(client *redis.Client, ttl time.Duration) ExchangeRateRepository {
+func NewExchangeRateRepository(client redis.UniversalClient, ttl time.Duration) ExchangeRateRepository {
 	return &exchangeRateRepository{
 		client: client,
 		ttl:    ttl,
@@ -139,3 +139,26 @@ func (r *exchangeRateRepository) Exists(ctx context.Context, currency string) (b
 
 	return exists > 0, nil
 }
+
+// Touch продлевает TTL курсов валют батчем
+// Используется, когда курсы в API не изменились и перезаписывать значения не нужно
+func (r *exchangeRateRepository) Touch(ctx context.Context, currencies []string) (int, error) {
+	pipe := r.client.Pipeline()
+
+	cmds := make([]*redis.BoolCmd, 0, len(currencies))
+	for _, currency := range currencies {
+		cmds = append(cmds, pipe.Expire(ctx, entity.GetRedisKeyForRate(currency), r.ttl))
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to extend exchange rates ttl: %w", err)
+	}
+
+	touched := 0
+	for _, cmd := range cmds {
+		if cmd.Val() {
+			touched++
+		}
+	}
+	return touched, nil
+}
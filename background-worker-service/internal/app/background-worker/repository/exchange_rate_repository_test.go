@@ -247,6 +247,24 @@ func (s *ExchangeRateRepositoryTestSuite) TestExists_False() {
 	s.False(exists)
 }
 
+// ===================== Touch Tests =====================
+
+func (s *ExchangeRateRepositoryTestSuite) TestTouch_ExtendsTTLOfExistingKeys() {
+	ctx := context.Background()
+
+	// Arrange
+	s.repo.Set(ctx, &entity.ExchangeRate{Currency: "USD", Rate: 1.0, UpdatedAt: time.Now()})
+	s.miniRedis.FastForward(20 * time.Minute)
+
+	// Act
+	touched, err := s.repo.Touch(ctx, []string{"USD", "XYZ"})
+
+	// Assert
+	s.NoError(err)
+	s.Equal(1, touched)
+	s.Equal(30*time.Minute, s.miniRedis.TTL(entity.GetRedisKeyForRate("USD")))
+}
+
 // ===================== TTL Tests =====================
 
 func (s *ExchangeRateRepositoryTestSuite) TestTTL_Expiration() {
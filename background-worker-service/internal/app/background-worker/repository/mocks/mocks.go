@@ -2,8 +2,10 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"augustberries/background-worker-service/internal/app/background-worker/entity"
+	"augustberries/pkg/money"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
@@ -27,13 +29,42 @@ func (m *MockOrderRepository) Update(ctx context.Context, order *entity.Order) e
 	return args.Error(0)
 }
 
-func (m *MockOrderRepository) UpdateDeliveryAndTotal(ctx context.Context, orderID uuid.UUID, deliveryPrice, totalPrice float64) error {
+func (m *MockOrderRepository) UpdateDeliveryAndTotal(ctx context.Context, orderID uuid.UUID, deliveryPrice, totalPrice money.Amount) error {
 	args := m.Called(ctx, orderID, deliveryPrice, totalPrice)
 	return args.Error(0)
 }
 
-func (m *MockOrderRepository) UpdateOrderWithCurrency(ctx context.Context, orderID uuid.UUID, deliveryPrice, totalPrice float64, currency string) error {
-	args := m.Called(ctx, orderID, deliveryPrice, totalPrice, currency)
+func (m *MockOrderRepository) GetItems(ctx context.Context, orderID uuid.UUID) ([]entity.OrderItem, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.OrderItem), args.Error(1)
+}
+
+func (m *MockOrderRepository) UpdateOrderWithCurrency(ctx context.Context, orderID uuid.UUID, deliveryPrice, taxTotal, totalPrice money.Amount, currency string, items []entity.OrderItem, convertedAt time.Time) error {
+	args := m.Called(ctx, orderID, deliveryPrice, taxTotal, totalPrice, currency, items, convertedAt)
+	return args.Error(0)
+}
+
+func (m *MockOrderRepository) GetByIDs(ctx context.Context, orderIDs []uuid.UUID) ([]entity.Order, error) {
+	args := m.Called(ctx, orderIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Order), args.Error(1)
+}
+
+func (m *MockOrderRepository) GetItemsByOrderIDs(ctx context.Context, orderIDs []uuid.UUID) (map[uuid.UUID][]entity.OrderItem, error) {
+	args := m.Called(ctx, orderIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID][]entity.OrderItem), args.Error(1)
+}
+
+func (m *MockOrderRepository) UpdateOrdersWithCurrency(ctx context.Context, calculations []*entity.DeliveryCalculation) error {
+	args := m.Called(ctx, calculations)
 	return args.Error(0)
 }
 
@@ -73,6 +104,55 @@ func (m *MockExchangeRateRepository) Exists(ctx context.Context, currency string
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockExchangeRateRepository) Touch(ctx context.Context, currencies []string) (int, error) {
+	args := m.Called(ctx, currencies)
+	return args.Int(0), args.Error(1)
+}
+
+// MockRatePinRepository мок для RatePinRepository
+type MockRatePinRepository struct {
+	mock.Mock
+}
+
+func (m *MockRatePinRepository) Set(ctx context.Context, pin *entity.RatePin) error {
+	args := m.Called(ctx, pin)
+	return args.Error(0)
+}
+
+func (m *MockRatePinRepository) Find(ctx context.Context, from, to string) (*entity.RatePin, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.RatePin), args.Error(1)
+}
+
+func (m *MockRatePinRepository) Delete(ctx context.Context, from, to string) (bool, error) {
+	args := m.Called(ctx, from, to)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRatePinRepository) List(ctx context.Context) ([]entity.RatePin, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.RatePin), args.Error(1)
+}
+
+func (m *MockRatePinRepository) AppendAudit(ctx context.Context, record *entity.RatePinAuditRecord) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *MockRatePinRepository) ListAudit(ctx context.Context, limit int) ([]entity.RatePinAuditRecord, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.RatePinAuditRecord), args.Error(1)
+}
+
 // MockExchangeRateAPIClient мок для ExchangeRateAPIClient
 type MockExchangeRateAPIClient struct {
 	mock.Mock
@@ -112,12 +192,43 @@ func (m *MockExchangeRateService) GetRates(ctx context.Context, currencies []str
 	return args.Get(0).(map[string]*entity.ExchangeRate), args.Error(1)
 }
 
-func (m *MockExchangeRateService) ConvertCurrency(ctx context.Context, amount float64, from, to string) (float64, float64, error) {
+func (m *MockExchangeRateService) ConvertCurrency(ctx context.Context, amount money.Amount, from, to string) (money.Amount, float64, error) {
 	args := m.Called(ctx, amount, from, to)
-	return args.Get(0).(float64), args.Get(1).(float64), args.Error(2)
+	return args.Get(0).(money.Amount), args.Get(1).(float64), args.Error(2)
 }
 
 func (m *MockExchangeRateService) EnsureRatesAvailable(ctx context.Context) error {
 	args := m.Called(ctx)
 	return args.Error(0)
 }
+
+// MockOrderStatusRepository мок для OrderStatusRepository
+type MockOrderStatusRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderStatusRepository) GetProjection(ctx context.Context, orderID uuid.UUID) (*entity.OrderStatusProjection, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.OrderStatusProjection), args.Error(1)
+}
+
+func (m *MockOrderStatusRepository) SaveTransition(ctx context.Context, transition *entity.OrderStatusTransition, projection *entity.OrderStatusProjection) error {
+	args := m.Called(ctx, transition, projection)
+	return args.Error(0)
+}
+
+func (m *MockOrderStatusRepository) StatusDurations(ctx context.Context, filter entity.StatusReportFilter) ([]entity.StatusDuration, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.StatusDuration), args.Error(1)
+}
+
+func (m *MockOrderStatusRepository) DeliveryDurations(ctx context.Context, filter entity.StatusReportFilter) (entity.DurationStats, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(entity.DurationStats), args.Error(1)
+}
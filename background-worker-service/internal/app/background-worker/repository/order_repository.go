@@ -4,13 +4,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"augustberries/background-worker-service/internal/app/background-worker/entity"
+	"augustberries/pkg/money"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// ErrOrderNotFound - заказ не найден в БД orders_service
+var ErrOrderNotFound = errors.New("order not found")
+
+// ErrStaleConversion - заказ уже сконвертирован по событию не старше текущего
+var ErrStaleConversion = errors.New("order already converted by a newer event")
+
 // orderRepository реализует OrderRepository для работы с PostgreSQL через GORM
 type orderRepository struct {
 	db *gorm.DB
@@ -30,7 +39,7 @@ func (r *orderRepository) GetByID(ctx context.Context, orderID uuid.UUID) (*enti
 
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("order not found: %w", result.Error)
+			return nil, fmt.Errorf("%w: %v", ErrOrderNotFound, result.Error)
 		}
 		return nil, fmt.Errorf("failed to get order: %w", result.Error)
 	}
@@ -56,7 +65,7 @@ func (r *orderRepository) Update(ctx context.Context, order *entity.Order) error
 
 // UpdateDeliveryAndTotal обновляет цену доставки и общую сумму заказа
 // Используется после расчета стоимости доставки с учетом курсов валют
-func (r *orderRepository) UpdateDeliveryAndTotal(ctx context.Context, orderID uuid.UUID, deliveryPrice, totalPrice float64) error {
+func (r *orderRepository) UpdateDeliveryAndTotal(ctx context.Context, orderID uuid.UUID, deliveryPrice, totalPrice money.Amount) error {
 	// Выполняем точечное обновление двух полей
 	result := r.db.WithContext(ctx).
 		Model(&entity.Order{}).
@@ -77,26 +86,140 @@ func (r *orderRepository) UpdateDeliveryAndTotal(ctx context.Context, orderID uu
 	return nil
 }
 
-// UpdateOrderWithCurrency обновляет цену доставки, общую сумму и валюту заказа
+// GetItems получает позиции заказа
+func (r *orderRepository) GetItems(ctx context.Context, orderID uuid.UUID) ([]entity.OrderItem, error) {
+	var items []entity.OrderItem
+
+	if err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("failed to get order items: %w", err)
+	}
+
+	return items, nil
+}
+
+// UpdateOrderWithCurrency обновляет цену доставки, налоги, общую сумму, валюту и цены позиций заказа
 // Используется после расчета стоимости доставки с конвертацией в RUB
-func (r *orderRepository) UpdateOrderWithCurrency(ctx context.Context, orderID uuid.UUID, deliveryPrice, totalPrice float64, currency string) error {
-	// Выполняем точечное обновление трех полей
-	result := r.db.WithContext(ctx).
-		Model(&entity.Order{}).
-		Where("id = ?", orderID).
-		Updates(map[string]interface{}{
-			"delivery_price": deliveryPrice,
-			"total_price":    totalPrice,
-			"currency":       currency,
-		})
+// Все изменения выполняются в одной транзакции, чтобы итог заказа не разошелся с позициями
+// convertedAt - время события расчета: заказ, уже сконвертированный по более новому событию, не перезаписывается
+func (r *orderRepository) UpdateOrderWithCurrency(ctx context.Context, orderID uuid.UUID, deliveryPrice, taxTotal, totalPrice money.Amount, currency string, items []entity.OrderItem, convertedAt time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Выполняем точечное обновление сумм и валюты
+		result := tx.Model(&entity.Order{}).
+			Where("id = ? AND (converted_at IS NULL OR converted_at < ?)", orderID, convertedAt).
+			Updates(map[string]interface{}{
+				"delivery_price": deliveryPrice,
+				"tax_total":      taxTotal,
+				"total_price":    totalPrice,
+				"currency":       currency,
+				"converted_at":   convertedAt,
+			})
+
+		if result.Error != nil {
+			return fmt.Errorf("failed to update delivery, total price and currency: %w", result.Error)
+		}
 
-	if result.Error != nil {
-		return fmt.Errorf("failed to update delivery, total price and currency: %w", result.Error)
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: order %s not found or converted by a newer event", ErrStaleConversion, orderID)
+		}
+
+		for _, item := range items {
+			if err := tx.Model(&entity.OrderItem{}).
+				Where("id = ? AND order_id = ?", item.ID, orderID).
+				Updates(map[string]interface{}{
+					"unit_price": item.UnitPrice,
+					"tax_amount": item.TaxAmount,
+				}).Error; err != nil {
+				return fmt.Errorf("failed to update order item %s price: %w", item.ID, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetByIDs получает заказы по списку ID одним запросом
+func (r *orderRepository) GetByIDs(ctx context.Context, orderIDs []uuid.UUID) ([]entity.Order, error) {
+	var orders []entity.Order
+	if len(orderIDs) == 0 {
+		return orders, nil
 	}
 
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("order %s not found", orderID)
+	if err := r.db.WithContext(ctx).Where("id IN ?", orderIDs).Find(&orders).Error; err != nil {
+		return nil, fmt.Errorf("failed to get orders: %w", err)
 	}
 
-	return nil
+	return orders, nil
+}
+
+// GetItemsByOrderIDs получает позиции нескольких заказов одним запросом
+func (r *orderRepository) GetItemsByOrderIDs(ctx context.Context, orderIDs []uuid.UUID) (map[uuid.UUID][]entity.OrderItem, error) {
+	itemsByOrder := make(map[uuid.UUID][]entity.OrderItem, len(orderIDs))
+	if len(orderIDs) == 0 {
+		return itemsByOrder, nil
+	}
+
+	var items []entity.OrderItem
+	if err := r.db.WithContext(ctx).Where("order_id IN ?", orderIDs).Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("failed to get order items: %w", err)
+	}
+
+	for _, item := range items {
+		itemsByOrder[item.OrderID] = append(itemsByOrder[item.OrderID], item)
+	}
+	return itemsByOrder, nil
+}
+
+// UpdateOrdersWithCurrency сохраняет результаты конвертации нескольких заказов
+// Заказы и позиции обновляются двумя UPDATE ... FROM (VALUES ...) в одной транзакции
+// Заказ, сконвертированный по более новому событию, чем CalculatedAt, не обновляется, и транзакция откатывается
+func (r *orderRepository) UpdateOrdersWithCurrency(ctx context.Context, calculations []*entity.DeliveryCalculation) error {
+	if len(calculations) == 0 {
+		return nil
+	}
+
+	orderRows := make([]string, 0, len(calculations))
+	orderArgs := make([]interface{}, 0, len(calculations)*6)
+	var itemRows []string
+	var itemArgs []interface{}
+
+	for _, calc := range calculations {
+		orderRows = append(orderRows, "(?::uuid, ?::decimal, ?::decimal, ?::decimal, ?, ?::timestamptz)")
+		orderArgs = append(orderArgs, calc.OrderID, calc.ConvertedDelivery, calc.ConvertedTax, calc.NewTotalPrice, calc.ConvertedCurrency, calc.CalculatedAt)
+
+		for _, item := range calc.ConvertedItems {
+			itemRows = append(itemRows, "(?::uuid, ?::uuid, ?::decimal, ?::decimal)")
+			itemArgs = append(itemArgs, item.ID, calc.OrderID, item.UnitPrice, item.TaxAmount)
+		}
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(
+			"UPDATE orders AS o SET delivery_price = v.delivery_price, tax_total = v.tax_total, "+
+				"total_price = v.total_price, currency = v.currency, converted_at = v.converted_at "+
+				"FROM (VALUES "+strings.Join(orderRows, ", ")+") AS v(id, delivery_price, tax_total, total_price, currency, converted_at) "+
+				"WHERE o.id = v.id AND (o.converted_at IS NULL OR o.converted_at < v.converted_at)",
+			orderArgs...,
+		)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update orders: %w", result.Error)
+		}
+		if result.RowsAffected != int64(len(calculations)) {
+			return fmt.Errorf("updated %d of %d orders", result.RowsAffected, len(calculations))
+		}
+
+		if len(itemRows) == 0 {
+			return nil
+		}
+
+		if err := tx.Exec(
+			"UPDATE order_items AS i SET unit_price = v.unit_price, tax_amount = v.tax_amount "+
+				"FROM (VALUES "+strings.Join(itemRows, ", ")+") AS v(id, order_id, unit_price, tax_amount) "+
+				"WHERE i.id = v.id AND i.order_id = v.order_id",
+			itemArgs...,
+		).Error; err != nil {
+			return fmt.Errorf("failed to update order items: %w", err)
+		}
+
+		return nil
+	})
 }
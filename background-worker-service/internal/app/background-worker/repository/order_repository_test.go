@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"augustberries/background-worker-service/internal/app/background-worker/entity"
+	"augustberries/pkg/money"
+	"augustberries/pkg/units"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
@@ -21,10 +23,10 @@ import (
 // OrderRepositoryTestSuite тестовый suite для PostgreSQL repository
 type OrderRepositoryTestSuite struct {
 	suite.Suite
-	db      *gorm.DB
-	mock    sqlmock.Sqlmock
-	repo    OrderRepository
-	sqlDB   *sql.DB
+	db    *gorm.DB
+	mock  sqlmock.Sqlmock
+	repo  OrderRepository
+	sqlDB *sql.DB
 }
 
 func TestOrderRepositorySuite(t *testing.T) {
@@ -62,8 +64,8 @@ func (s *OrderRepositoryTestSuite) TestGetByID_Success() {
 	rows := sqlmock.NewRows([]string{"id", "user_id", "total_price", "delivery_price", "currency", "status", "created_at"}).
 		AddRow(orderID, userID, 110.0, 10.0, "USD", "pending", createdAt)
 
-	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "orders" WHERE id = $1`)).
-		WithArgs(orderID).
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "orders" WHERE id = $1 ORDER BY "orders"."id" LIMIT $2`)).
+		WithArgs(orderID, 1).
 		WillReturnRows(rows)
 
 	// Act
@@ -74,8 +76,8 @@ func (s *OrderRepositoryTestSuite) TestGetByID_Success() {
 	s.NotNil(order)
 	s.Equal(orderID, order.ID)
 	s.Equal(userID, order.UserID)
-	s.Equal(110.0, order.TotalPrice)
-	s.Equal(10.0, order.DeliveryPrice)
+	s.Equal(money.MustParse("110.00"), order.TotalPrice)
+	s.Equal(money.MustParse("10.00"), order.DeliveryPrice)
 	s.Equal("USD", order.Currency)
 	s.Equal(entity.OrderStatusPending, order.Status)
 
@@ -86,8 +88,8 @@ func (s *OrderRepositoryTestSuite) TestGetByID_NotFound() {
 	ctx := context.Background()
 	orderID := uuid.New()
 
-	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "orders" WHERE id = $1`)).
-		WithArgs(orderID).
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "orders" WHERE id = $1 ORDER BY "orders"."id" LIMIT $2`)).
+		WithArgs(orderID, 1).
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	// Act
@@ -105,8 +107,8 @@ func (s *OrderRepositoryTestSuite) TestGetByID_DBError() {
 	ctx := context.Background()
 	orderID := uuid.New()
 
-	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "orders" WHERE id = $1`)).
-		WithArgs(orderID).
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "orders" WHERE id = $1 ORDER BY "orders"."id" LIMIT $2`)).
+		WithArgs(orderID, 1).
 		WillReturnError(sql.ErrConnDone)
 
 	// Act
@@ -130,8 +132,8 @@ func (s *OrderRepositoryTestSuite) TestUpdate_Success() {
 	order := &entity.Order{
 		ID:            orderID,
 		UserID:        userID,
-		TotalPrice:    200.0,
-		DeliveryPrice: 20.0,
+		TotalPrice:    money.MustParse("200.00"),
+		DeliveryPrice: money.MustParse("20.00"),
 		Currency:      "RUB",
 		Status:        entity.OrderStatusConfirmed,
 		CreatedAt:     time.Now(),
@@ -158,8 +160,8 @@ func (s *OrderRepositoryTestSuite) TestUpdate_NotFound() {
 	order := &entity.Order{
 		ID:            orderID,
 		UserID:        userID,
-		TotalPrice:    200.0,
-		DeliveryPrice: 20.0,
+		TotalPrice:    money.MustParse("200.00"),
+		DeliveryPrice: money.MustParse("20.00"),
 		Currency:      "RUB",
 		Status:        entity.OrderStatusConfirmed,
 	}
@@ -168,6 +170,11 @@ func (s *OrderRepositoryTestSuite) TestUpdate_NotFound() {
 	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "orders" SET`)).
 		WillReturnResult(sqlmock.NewResult(0, 0)) // 0 rows affected
 	s.mock.ExpectCommit()
+	// GORM Save при 0 затронутых строк пробует upsert
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "orders"`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectCommit()
 
 	// Act
 	err := s.repo.Update(ctx, order)
@@ -210,12 +217,12 @@ func (s *OrderRepositoryTestSuite) TestUpdateDeliveryAndTotal_Success() {
 
 	s.mock.ExpectBegin()
 	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "orders" SET`)).
-		WithArgs(10.0, 110.0, orderID). // delivery_price, total_price, id
+		WithArgs("10.00", "110.00", orderID). // delivery_price, total_price, id
 		WillReturnResult(sqlmock.NewResult(0, 1))
 	s.mock.ExpectCommit()
 
 	// Act
-	err := s.repo.UpdateDeliveryAndTotal(ctx, orderID, 10.0, 110.0)
+	err := s.repo.UpdateDeliveryAndTotal(ctx, orderID, money.MustParse("10.00"), money.MustParse("110.00"))
 
 	// Assert
 	s.NoError(err)
@@ -228,12 +235,12 @@ func (s *OrderRepositoryTestSuite) TestUpdateDeliveryAndTotal_NotFound() {
 
 	s.mock.ExpectBegin()
 	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "orders" SET`)).
-		WithArgs(10.0, 110.0, orderID).
+		WithArgs("10.00", "110.00", orderID).
 		WillReturnResult(sqlmock.NewResult(0, 0)) // 0 rows affected
 	s.mock.ExpectCommit()
 
 	// Act
-	err := s.repo.UpdateDeliveryAndTotal(ctx, orderID, 10.0, 110.0)
+	err := s.repo.UpdateDeliveryAndTotal(ctx, orderID, money.MustParse("10.00"), money.MustParse("110.00"))
 
 	// Assert
 	s.Error(err)
@@ -248,12 +255,12 @@ func (s *OrderRepositoryTestSuite) TestUpdateDeliveryAndTotal_DBError() {
 
 	s.mock.ExpectBegin()
 	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "orders" SET`)).
-		WithArgs(10.0, 110.0, orderID).
+		WithArgs("10.00", "110.00", orderID).
 		WillReturnError(sql.ErrConnDone)
 	s.mock.ExpectRollback()
 
 	// Act
-	err := s.repo.UpdateDeliveryAndTotal(ctx, orderID, 10.0, 110.0)
+	err := s.repo.UpdateDeliveryAndTotal(ctx, orderID, money.MustParse("10.00"), money.MustParse("110.00"))
 
 	// Assert
 	s.Error(err)
@@ -267,15 +274,16 @@ func (s *OrderRepositoryTestSuite) TestUpdateDeliveryAndTotal_DBError() {
 func (s *OrderRepositoryTestSuite) TestUpdateOrderWithCurrency_Success() {
 	ctx := context.Background()
 	orderID := uuid.New()
+	convertedAt := time.Now()
 
 	s.mock.ExpectBegin()
 	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "orders" SET`)).
-		WithArgs("RUB", 912.3, 10035.3, orderID). // currency, delivery_price, total_price, id
+		WithArgs(convertedAt, "RUB", "912.30", "0.00", "10035.30", orderID, convertedAt). // converted_at, currency, delivery_price, tax_total, total_price, id, converted_at
 		WillReturnResult(sqlmock.NewResult(0, 1))
 	s.mock.ExpectCommit()
 
 	// Act
-	err := s.repo.UpdateOrderWithCurrency(ctx, orderID, 912.3, 10035.3, "RUB")
+	err := s.repo.UpdateOrderWithCurrency(ctx, orderID, money.MustParse("912.30"), 0, money.MustParse("10035.30"), "RUB", nil, convertedAt)
 
 	// Assert
 	s.NoError(err)
@@ -285,18 +293,19 @@ func (s *OrderRepositoryTestSuite) TestUpdateOrderWithCurrency_Success() {
 func (s *OrderRepositoryTestSuite) TestUpdateOrderWithCurrency_NotFound() {
 	ctx := context.Background()
 	orderID := uuid.New()
+	convertedAt := time.Now()
 
 	s.mock.ExpectBegin()
 	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "orders" SET`)).
-		WithArgs("RUB", 912.3, 10035.3, orderID).
+		WithArgs(convertedAt, "RUB", "912.30", "0.00", "10035.30", orderID, convertedAt).
 		WillReturnResult(sqlmock.NewResult(0, 0)) // 0 rows affected
-	s.mock.ExpectCommit()
+	s.mock.ExpectRollback()
 
 	// Act
-	err := s.repo.UpdateOrderWithCurrency(ctx, orderID, 912.3, 10035.3, "RUB")
+	err := s.repo.UpdateOrderWithCurrency(ctx, orderID, money.MustParse("912.30"), 0, money.MustParse("10035.30"), "RUB", nil, convertedAt)
 
 	// Assert
-	s.Error(err)
+	s.ErrorIs(err, ErrStaleConversion)
 	s.Contains(err.Error(), "not found")
 
 	s.NoError(s.mock.ExpectationsWereMet())
@@ -305,15 +314,16 @@ func (s *OrderRepositoryTestSuite) TestUpdateOrderWithCurrency_NotFound() {
 func (s *OrderRepositoryTestSuite) TestUpdateOrderWithCurrency_DBError() {
 	ctx := context.Background()
 	orderID := uuid.New()
+	convertedAt := time.Now()
 
 	s.mock.ExpectBegin()
 	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "orders" SET`)).
-		WithArgs("RUB", 912.3, 10035.3, orderID).
+		WithArgs(convertedAt, "RUB", "912.30", "0.00", "10035.30", orderID, convertedAt).
 		WillReturnError(sql.ErrConnDone)
 	s.mock.ExpectRollback()
 
 	// Act
-	err := s.repo.UpdateOrderWithCurrency(ctx, orderID, 912.3, 10035.3, "RUB")
+	err := s.repo.UpdateOrderWithCurrency(ctx, orderID, money.MustParse("912.30"), 0, money.MustParse("10035.30"), "RUB", nil, convertedAt)
 
 	// Assert
 	s.Error(err)
@@ -322,6 +332,53 @@ func (s *OrderRepositoryTestSuite) TestUpdateOrderWithCurrency_DBError() {
 	s.NoError(s.mock.ExpectationsWereMet())
 }
 
+func (s *OrderRepositoryTestSuite) TestUpdateOrderWithCurrency_WithItems() {
+	ctx := context.Background()
+	orderID := uuid.New()
+	itemID := uuid.New()
+	items := []entity.OrderItem{{ID: itemID, OrderID: orderID, Quantity: units.Of(1), UnitPrice: money.MustParse("9123.00")}}
+	convertedAt := time.Now()
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "orders" SET`)).
+		WithArgs(convertedAt, "RUB", "912.30", "0.00", "10035.30", orderID, convertedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "order_items" SET "tax_amount"=$1,"unit_price"=$2 WHERE id = $3 AND order_id = $4`)).
+		WithArgs("0.00", "9123.00", itemID, orderID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	// Act
+	err := s.repo.UpdateOrderWithCurrency(ctx, orderID, money.MustParse("912.30"), 0, money.MustParse("10035.30"), "RUB", items, convertedAt)
+
+	// Assert
+	s.NoError(err)
+	s.NoError(s.mock.ExpectationsWereMet())
+}
+
+// ===================== GetItems Tests =====================
+
+func (s *OrderRepositoryTestSuite) TestGetItems_Success() {
+	ctx := context.Background()
+	orderID := uuid.New()
+
+	rows := sqlmock.NewRows([]string{"id", "order_id", "product_id", "quantity", "unit_price"}).
+		AddRow(uuid.New(), orderID, uuid.New(), 2, "99.99")
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_items" WHERE order_id = $1`)).
+		WithArgs(orderID).
+		WillReturnRows(rows)
+
+	// Act
+	items, err := s.repo.GetItems(ctx, orderID)
+
+	// Assert
+	s.NoError(err)
+	s.Len(items, 1)
+	s.Equal(money.MustParse("99.99"), items[0].UnitPrice)
+	s.NoError(s.mock.ExpectationsWereMet())
+}
+
 // ===================== NewOrderRepository Tests =====================
 
 func TestNewOrderRepository(t *testing.T) {
@@ -343,3 +400,60 @@ func TestNewOrderRepository(t *testing.T) {
 	// Assert
 	assert.NotNil(t, repo)
 }
+
+// ===================== UpdateOrdersWithCurrency Tests =====================
+
+func (s *OrderRepositoryTestSuite) TestUpdateOrdersWithCurrency_Success() {
+	ctx := context.Background()
+	orderID := uuid.New()
+	itemID := uuid.New()
+	calculatedAt := time.Now()
+
+	calculations := []*entity.DeliveryCalculation{{
+		OrderID:           orderID,
+		ConvertedDelivery: money.MustParse("912.30"),
+		ConvertedTax:      money.MustParse("182.46"),
+		NewTotalPrice:     money.MustParse("10217.76"),
+		ConvertedCurrency: "RUB",
+		ConvertedItems:    []entity.OrderItem{{ID: itemID, UnitPrice: money.MustParse("9123.00"), TaxAmount: money.MustParse("182.46")}},
+		CalculatedAt:      calculatedAt,
+	}}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE orders AS o SET`)).
+		WithArgs(orderID, "912.30", "182.46", "10217.76", "RUB", calculatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE order_items AS i SET`)).
+		WithArgs(itemID, orderID, "9123.00", "182.46").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	// Act
+	err := s.repo.UpdateOrdersWithCurrency(ctx, calculations)
+
+	// Assert
+	s.NoError(err)
+	s.NoError(s.mock.ExpectationsWereMet())
+}
+
+func (s *OrderRepositoryTestSuite) TestUpdateOrdersWithCurrency_MissingOrderRollsBack() {
+	ctx := context.Background()
+
+	calculations := []*entity.DeliveryCalculation{
+		{OrderID: uuid.New(), ConvertedCurrency: "RUB"},
+		{OrderID: uuid.New(), ConvertedCurrency: "RUB"},
+	}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE orders AS o SET`)).
+		WillReturnResult(sqlmock.NewResult(0, 1)) // один из двух заказов не найден или сконвертирован по более новому событию
+	s.mock.ExpectRollback()
+
+	// Act
+	err := s.repo.UpdateOrdersWithCurrency(ctx, calculations)
+
+	// Assert
+	s.Error(err)
+	s.Contains(err.Error(), "updated 1 of 2 orders")
+	s.NoError(s.mock.ExpectationsWereMet())
+}
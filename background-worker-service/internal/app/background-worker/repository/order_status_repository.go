@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"augustberries/background-worker-service/internal/app/background-worker/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// durationStatsColumns - агрегаты длительностей; пустая выборка дает нули
+const durationStatsColumns = "COUNT(*) AS count, " +
+	"COALESCE(AVG(%[1]s), 0) AS avg_seconds, " +
+	"COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY %[1]s), 0) AS p50_seconds, " +
+	"COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY %[1]s), 0) AS p90_seconds, " +
+	"COALESCE(MAX(%[1]s), 0) AS max_seconds"
+
+// orderStatusRepository реализует OrderStatusRepository через GORM
+type orderStatusRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderStatusRepository создает репозиторий проекции статусов заказов
+func NewOrderStatusRepository(db *gorm.DB) OrderStatusRepository {
+	return &orderStatusRepository{db: db}
+}
+
+// GetProjection получает текущий статус заказа по событиям
+func (r *orderStatusRepository) GetProjection(ctx context.Context, orderID uuid.UUID) (*entity.OrderStatusProjection, error) {
+	var projection entity.OrderStatusProjection
+	if err := r.db.WithContext(ctx).Where("order_id = ?", orderID).First(&projection).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get order status projection: %w", err)
+	}
+	return &projection, nil
+}
+
+// SaveTransition сохраняет переход и продвигает проекцию
+// Переход уникален по (order_id, to_status, changed_at): повторная доставка события ничего не меняет.
+// Проекция обновляется, только если переход новее ее статуса; время создания и доставки не затираются
+func (r *orderStatusRepository) SaveTransition(ctx context.Context, transition *entity.OrderStatusTransition, projection *entity.OrderStatusProjection) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(
+			"INSERT INTO order_status_transitions "+
+				"(id, order_id, tenant_id, from_status, to_status, actor_type, actor_id, impersonator_id, reason, changed_at, duration_seconds) "+
+				"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) "+
+				"ON CONFLICT (order_id, to_status, changed_at) DO NOTHING",
+			transition.ID, transition.OrderID, transition.TenantID, transition.FromStatus, transition.ToStatus,
+			transition.ActorType, transition.ActorID, transition.ImpersonatorID, transition.Reason,
+			transition.ChangedAt, transition.DurationSeconds,
+		)
+		if result.Error != nil {
+			return fmt.Errorf("failed to save order status transition: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Exec(
+			"INSERT INTO order_status_projection (order_id, tenant_id, status, status_since, order_created_at, delivered_at) "+
+				"VALUES (?, ?, ?, ?, ?, ?) "+
+				"ON CONFLICT (order_id) DO UPDATE SET status = EXCLUDED.status, status_since = EXCLUDED.status_since, "+
+				"order_created_at = COALESCE(order_status_projection.order_created_at, EXCLUDED.order_created_at), "+
+				"delivered_at = COALESCE(EXCLUDED.delivered_at, order_status_projection.delivered_at) "+
+				"WHERE order_status_projection.status_since < EXCLUDED.status_since",
+			projection.OrderID, projection.TenantID, projection.Status, projection.StatusSince,
+			projection.OrderCreatedAt, projection.DeliveredAt,
+		).Error; err != nil {
+			return fmt.Errorf("failed to update order status projection: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// StatusDurations группирует переходы за период по статусу, из которого вышел заказ
+// Переходы с неизвестной длительностью не учитываются
+func (r *orderStatusRepository) StatusDurations(ctx context.Context, filter entity.StatusReportFilter) ([]entity.StatusDuration, error) {
+	query := r.db.WithContext(ctx).
+		Table("order_status_transitions").
+		Select("from_status AS status, "+fmt.Sprintf(durationStatsColumns, "duration_seconds")).
+		Where("duration_seconds IS NOT NULL AND changed_at >= ? AND changed_at < ?", filter.From, filter.To)
+	if filter.TenantID != "" {
+		query = query.Where("tenant_id = ?", filter.TenantID)
+	}
+
+	var durations []entity.StatusDuration
+	if err := query.Group("from_status").Order("from_status").Scan(&durations).Error; err != nil {
+		return nil, fmt.Errorf("failed to get order status durations: %w", err)
+	}
+	return durations, nil
+}
+
+// DeliveryDurations считает время от создания до доставки заказов, доставленных за период
+func (r *orderStatusRepository) DeliveryDurations(ctx context.Context, filter entity.StatusReportFilter) (entity.DurationStats, error) {
+	query := r.db.WithContext(ctx).
+		Table("order_status_projection").
+		Select(fmt.Sprintf(durationStatsColumns, "EXTRACT(EPOCH FROM delivered_at - order_created_at)")).
+		Where("order_created_at IS NOT NULL AND delivered_at >= ? AND delivered_at < ?", filter.From, filter.To)
+	if filter.TenantID != "" {
+		query = query.Where("tenant_id = ?", filter.TenantID)
+	}
+
+	var stats entity.DurationStats
+	if err := query.Scan(&stats).Error; err != nil {
+		return entity.DurationStats{}, fmt.Errorf("failed to get order delivery durations: %w", err)
+	}
+	return stats, nil
+}
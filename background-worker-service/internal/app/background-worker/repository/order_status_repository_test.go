@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"augustberries/background-worker-service/internal/app/background-worker/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newOrderStatusRepository(t *testing.T) (OrderStatusRepository, sqlmock.Sqlmock) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB, DriverName: "postgres"}), &gorm.Config{})
+	require.NoError(t, err)
+	return NewOrderStatusRepository(db), mock
+}
+
+func newTransition() (*entity.OrderStatusTransition, *entity.OrderStatusProjection) {
+	orderID := uuid.New()
+	changedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	duration := 7200.0
+	transition := &entity.OrderStatusTransition{
+		ID: uuid.New(), OrderID: orderID, TenantID: "shop-a",
+		FromStatus: entity.OrderStatusPending, ToStatus: entity.OrderStatusConfirmed,
+		ActorType: "admin", ChangedAt: changedAt, DurationSeconds: &duration,
+	}
+	projection := &entity.OrderStatusProjection{
+		OrderID: orderID, TenantID: "shop-a", Status: entity.OrderStatusConfirmed, StatusSince: changedAt,
+	}
+	return transition, projection
+}
+
+// ===================== SaveTransition Tests =====================
+
+func TestSaveTransition_AdvancesProjection(t *testing.T) {
+	// Arrange
+	repo, mock := newOrderStatusRepository(t)
+	transition, projection := newTransition()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO order_status_transitions`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO order_status_projection`)).
+		WithArgs(projection.OrderID, "shop-a", entity.OrderStatusConfirmed, projection.StatusSince, nil, nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	err := repo.SaveTransition(context.Background(), transition, projection)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveTransition_DuplicateEventSkipsProjection(t *testing.T) {
+	// Arrange
+	repo, mock := newOrderStatusRepository(t)
+	transition, projection := newTransition()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`ON CONFLICT (order_id, to_status, changed_at) DO NOTHING`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	// Act
+	err := repo.SaveTransition(context.Background(), transition, projection)
+
+	// Assert: проекция не обновляется повторно
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
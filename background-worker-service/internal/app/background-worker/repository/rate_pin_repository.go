@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"augustberries/background-worker-service/internal/app/background-worker/entity"
+	"github.com/redis/go-redis/v9"
+)
+
+// maxRatePinAuditRecords - сколько последних записей аудита хранится в Redis
+const maxRatePinAuditRecords = 1000
+
+// ratePinRepository реализует RatePinRepository
+// Закрепления хранятся в одном hash, поэтому работают и в Redis Cluster
+type ratePinRepository struct {
+	client redis.UniversalClient
+}
+
+// NewRatePinRepository создает репозиторий закрепленных курсов
+func NewRatePinRepository(client redis.UniversalClient) RatePinRepository {
+	return &ratePinRepository{client: client}
+}
+
+// Set сохраняет закрепление пары
+func (r *ratePinRepository) Set(ctx context.Context, pin *entity.RatePin) error {
+	data, err := json.Marshal(pin)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rate pin: %w", err)
+	}
+
+	if err := r.client.HSet(ctx, entity.RedisKeyRatePins, entity.RatePinField(pin.From, pin.To), data).Err(); err != nil {
+		return fmt.Errorf("failed to set rate pin in redis: %w", err)
+	}
+	return nil
+}
+
+// Find ищет закрепление прямой и обратной пары одним запросом, прямая пара в приоритете
+func (r *ratePinRepository) Find(ctx context.Context, from, to string) (*entity.RatePin, error) {
+	values, err := r.client.HMGet(ctx, entity.RedisKeyRatePins, entity.RatePinField(from, to), entity.RatePinField(to, from)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rate pin from redis: %w", err)
+	}
+
+	for _, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var pin entity.RatePin
+		if err := json.Unmarshal([]byte(data), &pin); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal rate pin: %w", err)
+		}
+		return &pin, nil
+	}
+	return nil, nil
+}
+
+// Delete удаляет закрепление пары
+func (r *ratePinRepository) Delete(ctx context.Context, from, to string) (bool, error) {
+	deleted, err := r.client.HDel(ctx, entity.RedisKeyRatePins, entity.RatePinField(from, to)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete rate pin from redis: %w", err)
+	}
+	return deleted > 0, nil
+}
+
+// List возвращает все закрепления
+func (r *ratePinRepository) List(ctx context.Context) ([]entity.RatePin, error) {
+	values, err := r.client.HGetAll(ctx, entity.RedisKeyRatePins).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rate pins from redis: %w", err)
+	}
+
+	pins := make([]entity.RatePin, 0, len(values))
+	for field, data := range values {
+		var pin entity.RatePin
+		if err := json.Unmarshal([]byte(data), &pin); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal rate pin %s: %w", field, err)
+		}
+		pins = append(pins, pin)
+	}
+	return pins, nil
+}
+
+// AppendAudit добавляет запись в начало списка аудита и обрезает список
+func (r *ratePinRepository) AppendAudit(ctx context.Context, record *entity.RatePinAuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rate pin audit record: %w", err)
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.LPush(ctx, entity.RedisKeyRatePinAudit, data)
+	pipe.LTrim(ctx, entity.RedisKeyRatePinAudit, 0, maxRatePinAuditRecords-1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to append rate pin audit record: %w", err)
+	}
+	return nil
+}
+
+// ListAudit возвращает последние limit записей аудита
+func (r *ratePinRepository) ListAudit(ctx context.Context, limit int) ([]entity.RatePinAuditRecord, error) {
+	values, err := r.client.LRange(ctx, entity.RedisKeyRatePinAudit, 0, int64(limit)-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rate pin audit records: %w", err)
+	}
+
+	records := make([]entity.RatePinAuditRecord, 0, len(values))
+	for _, data := range values {
+		var record entity.RatePinAuditRecord
+		if err := json.Unmarshal([]byte(data), &record); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal rate pin audit record: %w", err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
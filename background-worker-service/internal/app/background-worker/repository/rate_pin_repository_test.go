@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"augustberries/background-worker-service/internal/app/background-worker/entity"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRatePinRepository(t *testing.T) RatePinRepository {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewRatePinRepository(client)
+}
+
+// ===================== RatePin Tests =====================
+
+func TestRatePinRepository_FindDirectAndInversePair(t *testing.T) {
+	// Arrange
+	repo := newTestRatePinRepository(t)
+	ctx := context.Background()
+	pin := &entity.RatePin{From: "USD", To: "RUB", Rate: 90, ExpiresAt: time.Now().Add(time.Hour).UTC()}
+	require.NoError(t, repo.Set(ctx, pin))
+
+	// Act
+	direct, err := repo.Find(ctx, "USD", "RUB")
+	require.NoError(t, err)
+	inverse, err := repo.Find(ctx, "RUB", "USD")
+	require.NoError(t, err)
+	missing, err := repo.Find(ctx, "EUR", "RUB")
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, 90.0, direct.Rate)
+	assert.Equal(t, "USD", inverse.From)
+	assert.Nil(t, missing)
+}
+
+func TestRatePinRepository_Delete(t *testing.T) {
+	repo := newTestRatePinRepository(t)
+	ctx := context.Background()
+	require.NoError(t, repo.Set(ctx, &entity.RatePin{From: "USD", To: "RUB", Rate: 90}))
+
+	deleted, err := repo.Delete(ctx, "USD", "RUB")
+	require.NoError(t, err)
+	again, err := repo.Delete(ctx, "USD", "RUB")
+	require.NoError(t, err)
+
+	assert.True(t, deleted)
+	assert.False(t, again)
+	pins, err := repo.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, pins)
+}
+
+func TestRatePinRepository_AuditNewestFirstAndCapped(t *testing.T) {
+	repo := newTestRatePinRepository(t)
+	ctx := context.Background()
+
+	for i := 0; i < maxRatePinAuditRecords+5; i++ {
+		require.NoError(t, repo.AppendAudit(ctx, &entity.RatePinAuditRecord{Action: entity.RatePinActionPin, Rate: float64(i + 1)}))
+	}
+
+	records, err := repo.ListAudit(ctx, maxRatePinAuditRecords+5)
+
+	require.NoError(t, err)
+	assert.Len(t, records, maxRatePinAuditRecords)
+	assert.Equal(t, float64(maxRatePinAuditRecords+5), records[0].Rate)
+}
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"augustberries/background-worker-service/internal/app/background-worker/entity"
+	"augustberries/pkg/money"
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -16,10 +18,39 @@ type OrderRepository interface {
 	Update(ctx context.Context, order *entity.Order) error
 
 	// UpdateDeliveryAndTotal обновляет цену доставки и общую сумму заказа
-	UpdateDeliveryAndTotal(ctx context.Context, orderID uuid.UUID, deliveryPrice, totalPrice float64) error
+	UpdateDeliveryAndTotal(ctx context.Context, orderID uuid.UUID, deliveryPrice, totalPrice money.Amount) error
 
-	// UpdateOrderWithCurrency обновляет цену доставки, общую сумму и валюту заказа
-	UpdateOrderWithCurrency(ctx context.Context, orderID uuid.UUID, deliveryPrice, totalPrice float64, currency string) error
+	// GetItems получает позиции заказа
+	GetItems(ctx context.Context, orderID uuid.UUID) ([]entity.OrderItem, error)
+
+	// UpdateOrderWithCurrency обновляет доставку, налоги, общую сумму, валюту и цены позиций заказа в одной транзакции
+	// Если заказ уже сконвертирован по событию не старше convertedAt, возвращает ErrStaleConversion
+	UpdateOrderWithCurrency(ctx context.Context, orderID uuid.UUID, deliveryPrice, taxTotal, totalPrice money.Amount, currency string, items []entity.OrderItem, convertedAt time.Time) error
+
+	// GetByIDs получает заказы по списку ID одним запросом (ненайденные заказы пропускаются)
+	GetByIDs(ctx context.Context, orderIDs []uuid.UUID) ([]entity.Order, error)
+
+	// GetItemsByOrderIDs получает позиции нескольких заказов одним запросом
+	GetItemsByOrderIDs(ctx context.Context, orderIDs []uuid.UUID) (map[uuid.UUID][]entity.OrderItem, error)
+
+	// UpdateOrdersWithCurrency сохраняет результаты конвертации нескольких заказов в одной транзакции
+	UpdateOrdersWithCurrency(ctx context.Context, calculations []*entity.DeliveryCalculation) error
+}
+
+// OrderStatusRepository интерфейс проекции статусов заказов для аналитики сроков (PostgreSQL)
+type OrderStatusRepository interface {
+	// GetProjection получает текущий статус заказа по событиям (nil - событий заказа еще не было)
+	GetProjection(ctx context.Context, orderID uuid.UUID) (*entity.OrderStatusProjection, error)
+
+	// SaveTransition сохраняет переход и продвигает проекцию в одной транзакции
+	// Повтор уже сохраненного перехода и переход старше проекции ничего не меняют
+	SaveTransition(ctx context.Context, transition *entity.OrderStatusTransition, projection *entity.OrderStatusProjection) error
+
+	// StatusDurations возвращает статистику времени в статусах по переходам за период
+	StatusDurations(ctx context.Context, filter entity.StatusReportFilter) ([]entity.StatusDuration, error)
+
+	// DeliveryDurations возвращает статистику времени от создания до доставки заказов, доставленных за период
+	DeliveryDurations(ctx context.Context, filter entity.StatusReportFilter) (entity.DurationStats, error)
 }
 
 // ExchangeRateRepository интерфейс для работы с курсами валют в Redis
@@ -38,4 +69,28 @@ type ExchangeRateRepository interface {
 
 	// Exists проверяет существование курса в Redis
 	Exists(ctx context.Context, currency string) (bool, error)
+
+	// Touch продлевает TTL курсов без перезаписи значений и возвращает число найденных ключей
+	Touch(ctx context.Context, currencies []string) (int, error)
+}
+
+// RatePinRepository интерфейс для работы с закрепленными вручную курсами в Redis
+type RatePinRepository interface {
+	// Set сохраняет закрепление пары, заменяя предыдущее
+	Set(ctx context.Context, pin *entity.RatePin) error
+
+	// Find возвращает закрепление пары from/to или обратной пары to/from (nil - закрепления нет)
+	Find(ctx context.Context, from, to string) (*entity.RatePin, error)
+
+	// Delete удаляет закрепление пары, false - закрепления не было
+	Delete(ctx context.Context, from, to string) (bool, error)
+
+	// List возвращает все закрепления, включая истекшие
+	List(ctx context.Context) ([]entity.RatePin, error)
+
+	// AppendAudit добавляет запись аудита, хранятся только последние записи
+	AppendAudit(ctx context.Context, record *entity.RatePinAuditRecord) error
+
+	// ListAudit возвращает последние записи аудита, новые первыми
+	ListAudit(ctx context.Context, limit int) ([]entity.RatePinAuditRecord, error)
 }
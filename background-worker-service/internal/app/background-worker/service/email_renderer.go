@@ -0,0 +1,162 @@
+package service
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+
+	"augustberries/background-worker-service/internal/app/background-worker/entity"
+	"augustberries/pkg/money"
+)
+
+// emailTemplatesFS - шаблоны писем: <locale>.html.tmpl и <locale>.txt.tmpl
+// Каждый файл определяет блок на каждый шаблон письма, текстовый файл - еще и тему <template>.subject
+//
+//go:embed templates/*.tmpl
+var emailTemplatesFS embed.FS
+
+// DefaultEmailLocale - локаль писем по умолчанию
+const DefaultEmailLocale = "ru"
+
+// ErrUnknownEmailTemplate - запрошен неизвестный шаблон письма
+var ErrUnknownEmailTemplate = errors.New("unknown email template")
+
+// EmailRenderer отрисовывает письма о заказе из шаблонов с вариантами по локалям
+// Локаль без своих шаблонов заменяется базовым языком ("en-us" -> "en"), затем локалью по умолчанию
+type EmailRenderer struct {
+	defaultLocale string
+	html          map[string]*htmltemplate.Template
+	text          map[string]*texttemplate.Template
+}
+
+// emailTemplateFuncs - функции шаблонов писем
+var emailTemplateFuncs = map[string]any{
+	// money форматирует сумму с числом знаков валюты и кодом валюты ("1250 JPY", "10.50 USD")
+	"money": func(amount money.Amount, currency string) string {
+		return money.LookupCurrency(currency).Format(amount) + " " + currency
+	},
+}
+
+// NewEmailRenderer загружает встроенные шаблоны
+// Ошибка, если у локали нет HTML или текстовой версии какого-либо письма или нет шаблонов локали по умолчанию
+func NewEmailRenderer(defaultLocale string) (*EmailRenderer, error) {
+	r := &EmailRenderer{
+		defaultLocale: normalizeEmailLocale(defaultLocale),
+		html:          make(map[string]*htmltemplate.Template),
+		text:          make(map[string]*texttemplate.Template),
+	}
+	if r.defaultLocale == "" {
+		r.defaultLocale = DefaultEmailLocale
+	}
+
+	files, err := fs.Glob(emailTemplatesFS, "templates/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		name := strings.TrimPrefix(file, "templates/")
+		switch {
+		case strings.HasSuffix(name, ".html.tmpl"):
+			tmpl, err := htmltemplate.New(name).Funcs(emailTemplateFuncs).ParseFS(emailTemplatesFS, file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse email template %s: %w", name, err)
+			}
+			r.html[strings.TrimSuffix(name, ".html.tmpl")] = tmpl
+		case strings.HasSuffix(name, ".txt.tmpl"):
+			tmpl, err := texttemplate.New(name).Funcs(emailTemplateFuncs).ParseFS(emailTemplatesFS, file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse email template %s: %w", name, err)
+			}
+			r.text[strings.TrimSuffix(name, ".txt.tmpl")] = tmpl
+		}
+	}
+
+	for locale, html := range r.html {
+		text, ok := r.text[locale]
+		if !ok {
+			return nil, fmt.Errorf("email locale %s has no text templates", locale)
+		}
+		for _, name := range entity.EmailTemplates {
+			if html.Lookup(string(name)) == nil || text.Lookup(string(name)) == nil || text.Lookup(string(name)+".subject") == nil {
+				return nil, fmt.Errorf("email locale %s has no complete %s template", locale, name)
+			}
+		}
+	}
+	for locale := range r.text {
+		if _, ok := r.html[locale]; !ok {
+			return nil, fmt.Errorf("email locale %s has no HTML templates", locale)
+		}
+	}
+	if _, ok := r.html[r.defaultLocale]; !ok {
+		return nil, fmt.Errorf("no email templates for default locale %s", r.defaultLocale)
+	}
+
+	return r, nil
+}
+
+// Locales возвращает локали, для которых есть шаблоны, по алфавиту
+func (r *EmailRenderer) Locales() []string {
+	locales := make([]string, 0, len(r.html))
+	for locale := range r.html {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// DefaultLocale возвращает локаль писем по умолчанию
+func (r *EmailRenderer) DefaultLocale() string {
+	return r.defaultLocale
+}
+
+// Render отрисовывает тему, HTML и текстовую версию письма в запрошенной локали или запасной
+func (r *EmailRenderer) Render(name entity.EmailTemplate, locale string, data *entity.OrderEmail) (*entity.RenderedEmail, error) {
+	if !name.Valid() {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEmailTemplate, name)
+	}
+	locale = r.resolveLocale(locale)
+
+	var subject, text, html bytes.Buffer
+	if err := r.text[locale].ExecuteTemplate(&subject, string(name)+".subject", data); err != nil {
+		return nil, fmt.Errorf("failed to render %s subject: %w", name, err)
+	}
+	if err := r.text[locale].ExecuteTemplate(&text, string(name), data); err != nil {
+		return nil, fmt.Errorf("failed to render %s text: %w", name, err)
+	}
+	if err := r.html[locale].ExecuteTemplate(&html, string(name), data); err != nil {
+		return nil, fmt.Errorf("failed to render %s html: %w", name, err)
+	}
+
+	return &entity.RenderedEmail{
+		Template: name,
+		Locale:   locale,
+		Subject:  strings.TrimSpace(subject.String()),
+		HTML:     strings.TrimSpace(html.String()),
+		Text:     strings.TrimSpace(text.String()),
+	}, nil
+}
+
+// resolveLocale выбирает локаль с шаблонами: запрошенную, ее базовый язык или локаль по умолчанию
+func (r *EmailRenderer) resolveLocale(locale string) string {
+	locale = normalizeEmailLocale(locale)
+	if _, ok := r.html[locale]; ok {
+		return locale
+	}
+	if base, _, found := strings.Cut(locale, "-"); found {
+		if _, ok := r.html[base]; ok {
+			return base
+		}
+	}
+	return r.defaultLocale
+}
+
+// normalizeEmailLocale приводит код языка к виду имен файлов шаблонов: нижний регистр, дефис вместо подчеркивания
+func normalizeEmailLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
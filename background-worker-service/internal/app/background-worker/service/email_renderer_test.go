@@ -0,0 +1,99 @@
+package service
+
+import (
+	"testing"
+
+	"augustberries/background-worker-service/internal/app/background-worker/entity"
+	"augustberries/pkg/money"
+	"augustberries/pkg/units"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ===================== EmailRenderer Tests =====================
+
+func TestEmailRenderer_AllTemplatesInAllLocales(t *testing.T) {
+	renderer, err := NewEmailRenderer("ru")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"en", "ru"}, renderer.Locales())
+
+	for _, locale := range renderer.Locales() {
+		for _, name := range entity.EmailTemplates {
+			email, err := renderer.Render(name, locale, SampleOrderEmail())
+
+			require.NoError(t, err, "%s/%s", locale, name)
+			assert.Equal(t, locale, email.Locale)
+			assert.NotEmpty(t, email.Subject, "%s/%s", locale, name)
+			assert.Contains(t, email.HTML, "AB-20240115-000123", "%s/%s", locale, name)
+			assert.Contains(t, email.Text, "AB-20240115-000123", "%s/%s", locale, name)
+		}
+	}
+}
+
+func TestEmailRenderer_LocaleFallback(t *testing.T) {
+	renderer, err := NewEmailRenderer("ru")
+	require.NoError(t, err)
+
+	tests := []struct {
+		locale   string
+		expected string
+	}{
+		{"en", "en"},
+		{"EN_us", "en"},
+		{"ru-RU", "ru"},
+		{"de", "ru"},
+		{"", "ru"},
+	}
+	for _, tt := range tests {
+		email, err := renderer.Render(entity.EmailOrderConfirmation, tt.locale, SampleOrderEmail())
+
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, email.Locale, tt.locale)
+	}
+}
+
+func TestEmailRenderer_ItemsAndConvertedTotals(t *testing.T) {
+	// Arrange
+	renderer, err := NewEmailRenderer("en")
+	require.NoError(t, err)
+	data := &entity.OrderEmail{
+		OrderNumber: "AB-42",
+		Status:      entity.OrderStatusConfirmed,
+		Currency:    "JPY",
+		Items: []entity.OrderEmailItem{
+			{Name: "Blueberries <fresh>", Quantity: units.MustParse("0.5"), Unit: units.UnitKilogram, UnitPrice: money.MustParse("3000"), Total: money.MustParse("1500")},
+		},
+		Subtotal: money.MustParse("1500"),
+		Delivery: money.MustParse("500"),
+		Total:    money.MustParse("2000"),
+	}
+
+	// Act
+	email, err := renderer.Render(entity.EmailOrderConfirmation, "en", data)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Order AB-42 confirmed", email.Subject)
+	assert.Contains(t, email.Text, "- Blueberries <fresh>: 0.5 kg x 3000 JPY = 1500 JPY")
+	assert.Contains(t, email.Text, "Total: 2000 JPY")
+	assert.NotContains(t, email.Text, "Tax:")
+	// HTML версия экранирует данные заказа
+	assert.Contains(t, email.HTML, "Blueberries &lt;fresh&gt;")
+	assert.Contains(t, email.HTML, "Total: 2000 JPY")
+}
+
+func TestEmailRenderer_UnknownTemplate(t *testing.T) {
+	renderer, err := NewEmailRenderer("ru")
+	require.NoError(t, err)
+
+	_, err = renderer.Render("welcome", "ru", SampleOrderEmail())
+
+	assert.ErrorIs(t, err, ErrUnknownEmailTemplate)
+}
+
+func TestEmailRenderer_UnknownDefaultLocale(t *testing.T) {
+	_, err := NewEmailRenderer("de")
+
+	assert.Error(t, err)
+}
@@ -5,23 +5,40 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 
 	"augustberries/background-worker-service/internal/app/background-worker/entity"
 )
 
+// symbolsParam - query-параметр со списком нужных валют, сокращает ответ API, которые его поддерживают
+const symbolsParam = "symbols"
+
 // ExchangeRateAPIClientImpl реализует интерфейс ExchangeRateAPIClient
 // Отвечает только за HTTP запросы к внешнему API
+// Запоминает ETag и Last-Modified последнего ответа и повторяет запрос условно:
+// при 304 Not Modified возвращает курсы из предыдущего ответа без загрузки тела
 type ExchangeRateAPIClientImpl struct {
 	apiURL     string
+	currencies []string // Нужные валюты, пустой список - все валюты из ответа
 	httpClient *http.Client
+
+	mu           sync.Mutex
+	etag         string
+	lastModified string
+	cached       map[string]float64 // Курсы из последнего ответа 200
 }
 
 // NewExchangeRateAPIClient создает новый HTTP клиент для API курсов валют
-func NewExchangeRateAPIClient(apiURL string, timeoutSec int) *ExchangeRateAPIClientImpl {
+// currencies ограничивает набор курсов; nil - сохраняются все валюты из ответа
+func NewExchangeRateAPIClient(apiURL string, timeoutSec int, currencies []string) *ExchangeRateAPIClientImpl {
 	return &ExchangeRateAPIClientImpl{
-		apiURL: apiURL,
+		apiURL:     apiURL,
+		currencies: currencies,
 		httpClient: &http.Client{
 			Timeout: time.Duration(timeoutSec) * time.Second,
 		},
@@ -31,11 +48,22 @@ func NewExchangeRateAPIClient(apiURL string, timeoutSec int) *ExchangeRateAPICli
 // FetchRates получает курсы валют из внешнего API
 func (c *ExchangeRateAPIClientImpl) FetchRates(ctx context.Context) (map[string]float64, error) {
 	// Создаем HTTP запрос с контекстом
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.requestURL(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
+	c.mu.Lock()
+	if c.cached != nil {
+		if c.etag != "" {
+			req.Header.Set("If-None-Match", c.etag)
+		}
+		if c.lastModified != "" {
+			req.Header.Set("If-Modified-Since", c.lastModified)
+		}
+	}
+	c.mu.Unlock()
+
 	// Выполняем запрос
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -43,6 +71,16 @@ func (c *ExchangeRateAPIClientImpl) FetchRates(ctx context.Context) (map[string]
 	}
 	defer resp.Body.Close()
 
+	// Курсы не изменились с прошлого запроса
+	if resp.StatusCode == http.StatusNotModified {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.cached == nil {
+			return nil, fmt.Errorf("API returned 304 Not Modified without a cached response")
+		}
+		return maps.Clone(c.cached), nil
+	}
+
 	// Проверяем статус код
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
@@ -61,6 +99,45 @@ func (c *ExchangeRateAPIClientImpl) FetchRates(ctx context.Context) (map[string]
 		return nil, fmt.Errorf("failed to unmarshal API response: %w", err)
 	}
 
-	// Возвращаем только курсы валют
-	return apiResponse.Rates, nil
+	// Возвращаем только курсы нужных валют
+	rates := c.filterRates(apiResponse.Rates)
+
+	c.mu.Lock()
+	c.etag = resp.Header.Get("ETag")
+	c.lastModified = resp.Header.Get("Last-Modified")
+	c.cached = maps.Clone(rates)
+	c.mu.Unlock()
+
+	return rates, nil
+}
+
+// requestURL добавляет к адресу API список нужных валют
+func (c *ExchangeRateAPIClientImpl) requestURL() string {
+	if len(c.currencies) == 0 {
+		return c.apiURL
+	}
+
+	u, err := url.Parse(c.apiURL)
+	if err != nil {
+		return c.apiURL
+	}
+	query := u.Query()
+	query.Set(symbolsParam, strings.Join(c.currencies, ","))
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// filterRates оставляет валюты из списка; API может не поддерживать symbols и вернуть все курсы
+func (c *ExchangeRateAPIClientImpl) filterRates(rates map[string]float64) map[string]float64 {
+	if len(c.currencies) == 0 || rates == nil {
+		return rates
+	}
+
+	filtered := make(map[string]float64, len(c.currencies))
+	for _, currency := range c.currencies {
+		if rate, ok := rates[currency]; ok {
+			filtered[currency] = rate
+		}
+	}
+	return filtered
 }
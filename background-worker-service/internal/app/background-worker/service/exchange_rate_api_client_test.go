@@ -37,7 +37,7 @@ func TestFetchRates_Success(t *testing.T) {
 	}))
 	defer server.Close()
 
-	client := NewExchangeRateAPIClient(server.URL, 10)
+	client := NewExchangeRateAPIClient(server.URL, 10, nil)
 	ctx := context.Background()
 
 	// Act
@@ -59,7 +59,7 @@ func TestFetchRates_HTTPError_500(t *testing.T) {
 	}))
 	defer server.Close()
 
-	client := NewExchangeRateAPIClient(server.URL, 10)
+	client := NewExchangeRateAPIClient(server.URL, 10, nil)
 	ctx := context.Background()
 
 	// Act
@@ -79,7 +79,7 @@ func TestFetchRates_HTTPError_404(t *testing.T) {
 	}))
 	defer server.Close()
 
-	client := NewExchangeRateAPIClient(server.URL, 10)
+	client := NewExchangeRateAPIClient(server.URL, 10, nil)
 	ctx := context.Background()
 
 	// Act
@@ -99,7 +99,7 @@ func TestFetchRates_HTTPError_401(t *testing.T) {
 	}))
 	defer server.Close()
 
-	client := NewExchangeRateAPIClient(server.URL, 10)
+	client := NewExchangeRateAPIClient(server.URL, 10, nil)
 	ctx := context.Background()
 
 	// Act
@@ -119,7 +119,7 @@ func TestFetchRates_InvalidJSON(t *testing.T) {
 	}))
 	defer server.Close()
 
-	client := NewExchangeRateAPIClient(server.URL, 10)
+	client := NewExchangeRateAPIClient(server.URL, 10, nil)
 	ctx := context.Background()
 
 	// Act
@@ -145,7 +145,7 @@ func TestFetchRates_EmptyResponse(t *testing.T) {
 	}))
 	defer server.Close()
 
-	client := NewExchangeRateAPIClient(server.URL, 10)
+	client := NewExchangeRateAPIClient(server.URL, 10, nil)
 	ctx := context.Background()
 
 	// Act
@@ -166,7 +166,7 @@ func TestFetchRates_ContextCanceled(t *testing.T) {
 	}))
 	defer server.Close()
 
-	client := NewExchangeRateAPIClient(server.URL, 10)
+	client := NewExchangeRateAPIClient(server.URL, 10, nil)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel() // Отменяем сразу
@@ -188,7 +188,7 @@ func TestFetchRates_Timeout(t *testing.T) {
 	defer server.Close()
 
 	// Таймаут 1 секунда
-	client := NewExchangeRateAPIClient(server.URL, 1)
+	client := NewExchangeRateAPIClient(server.URL, 1, nil)
 	ctx := context.Background()
 
 	// Act
@@ -203,7 +203,7 @@ func TestFetchRates_Timeout(t *testing.T) {
 func TestFetchRates_ConnectionRefused(t *testing.T) {
 	// Arrange
 	// Используем несуществующий адрес
-	client := NewExchangeRateAPIClient("http://localhost:59999/rates", 1)
+	client := NewExchangeRateAPIClient("http://localhost:59999/rates", 1, nil)
 	ctx := context.Background()
 
 	// Act
@@ -236,7 +236,7 @@ func TestFetchRates_PartialRates(t *testing.T) {
 	}))
 	defer server.Close()
 
-	client := NewExchangeRateAPIClient(server.URL, 10)
+	client := NewExchangeRateAPIClient(server.URL, 10, nil)
 	ctx := context.Background()
 
 	// Act
@@ -273,7 +273,7 @@ func TestFetchRates_LargeRates(t *testing.T) {
 	}))
 	defer server.Close()
 
-	client := NewExchangeRateAPIClient(server.URL, 10)
+	client := NewExchangeRateAPIClient(server.URL, 10, nil)
 	ctx := context.Background()
 
 	// Act
@@ -286,10 +286,66 @@ func TestFetchRates_LargeRates(t *testing.T) {
 	assert.InDelta(t, 24315.0, rates["VND"], 0.01)
 }
 
+func TestFetchRates_ConditionalRequestNotModified(t *testing.T) {
+	// Arrange
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			assert.Equal(t, "Mon, 15 Jan 2024 00:00:00 GMT", r.Header.Get("If-Modified-Since"))
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 15 Jan 2024 00:00:00 GMT")
+		json.NewEncoder(w).Encode(entity.ExchangeRatesResponse{Base: "USD", Rates: map[string]float64{"USD": 1.0, "RUB": 91.23}})
+	}))
+	defer server.Close()
+
+	client := NewExchangeRateAPIClient(server.URL, 10, nil)
+
+	// Act
+	first, err := client.FetchRates(context.Background())
+	assert.NoError(t, err)
+	second, err := client.FetchRates(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, first, second)
+}
+
+func TestFetchRates_NotModifiedWithoutCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	client := NewExchangeRateAPIClient(server.URL, 10, nil)
+	_, err := client.FetchRates(context.Background())
+
+	assert.Error(t, err)
+}
+
+func TestFetchRates_CurrencyAllowlist(t *testing.T) {
+	// API игнорирует symbols и возвращает все курсы - лишние валюты отбрасываются клиентом
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "USD,RUB", r.URL.Query().Get("symbols"))
+		json.NewEncoder(w).Encode(entity.ExchangeRatesResponse{Base: "USD", Rates: map[string]float64{"USD": 1.0, "RUB": 91.23, "EUR": 0.93, "KRW": 1320.45}})
+	}))
+	defer server.Close()
+
+	client := NewExchangeRateAPIClient(server.URL+"?base=USD", 10, []string{"USD", "RUB"})
+	rates, err := client.FetchRates(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{"USD": 1.0, "RUB": 91.23}, rates)
+}
+
 func TestNewExchangeRateAPIClient(t *testing.T) {
 	// Проверяем создание клиента
 	// Arrange & Act
-	client := NewExchangeRateAPIClient("https://api.example.com/rates", 30)
+	client := NewExchangeRateAPIClient("https://api.example.com/rates", 30, nil)
 
 	// Assert
 	assert.NotNil(t, client)
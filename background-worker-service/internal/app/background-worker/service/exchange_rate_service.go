@@ -2,38 +2,92 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"maps"
+	"slices"
+	"sync"
 	"time"
 
 	"augustberries/background-worker-service/internal/app/background-worker/entity"
 	"augustberries/background-worker-service/internal/app/background-worker/repository"
 	"augustberries/pkg/metrics"
+	"augustberries/pkg/money"
 )
 
 type ExchangeRateService struct {
 	rateRepo  repository.ExchangeRateRepository
 	apiClient ExchangeRateAPIClient
+	pinRepo   repository.RatePinRepository // nil - ручное закрепление курсов отключено
+
+	// Последние записанные в Redis курсы: неизменившиеся курсы не перезаписываются
+	mu        sync.Mutex
+	stored    map[string]float64
+	checkedAt time.Time // Время последней успешной проверки курсов в API
 }
 
+// NewExchangeRateService создает сервис курсов валют
+// pinRepo может быть nil - тогда курсы берутся только из внешнего API
 func NewExchangeRateService(
 	rateRepo repository.ExchangeRateRepository,
 	apiClient ExchangeRateAPIClient,
+	pinRepo repository.RatePinRepository,
 ) *ExchangeRateService {
 	return &ExchangeRateService{
 		rateRepo:  rateRepo,
 		apiClient: apiClient,
+		pinRepo:   pinRepo,
 	}
 }
 
+// ErrRatesUnavailable - внешний API курсов валют недоступен
+var ErrRatesUnavailable = errors.New("exchange rates API unavailable")
+
+// FetchAndStoreRates обновляет курсы по расписанию
+// Недоступность API не считается ошибкой: worker продолжает работу с кэшированными курсами
 func (s *ExchangeRateService) FetchAndStoreRates(ctx context.Context) error {
 	log.Println("Fetching exchange rates from API...")
 
+	count, err := s.RefreshRates(ctx)
+	if err != nil {
+		if errors.Is(err, ErrRatesUnavailable) {
+			log.Printf("WARNING: %v", err)
+			return nil
+		}
+		return err
+	}
+
+	log.Printf("Successfully stored %d exchange rates", count)
+	return nil
+}
+
+// RefreshRates получает курсы из внешнего API и сохраняет их в Redis
+// В отличие от FetchAndStoreRates возвращает ошибку API - используется для ручного обновления
+func (s *ExchangeRateService) RefreshRates(ctx context.Context) (int, error) {
 	rates, err := s.apiClient.FetchRates(ctx)
 	if err != nil {
-		log.Printf("WARNING: Failed to fetch rates from API: %v", err)
 		metrics.WorkerExchangeRateUpdates.WithLabelValues("failed").Inc()
-		return nil
+		return 0, fmt.Errorf("%w: %v", ErrRatesUnavailable, err)
+	}
+
+	// Одновременное ручное и плановое обновление не должны разойтись с s.stored
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if maps.Equal(rates, s.stored) {
+		touched, err := s.rateRepo.Touch(ctx, slices.Collect(maps.Keys(rates)))
+		if err == nil && touched == len(rates) {
+			s.checkedAt = time.Now()
+			metrics.WorkerExchangeRateUpdates.WithLabelValues("unchanged").Inc()
+			metrics.WorkerLastRateUpdate.SetToCurrentTime()
+			log.Println("Exchange rates unchanged, extended TTL only")
+			return len(rates), nil
+		}
+		// Часть ключей истекла или Redis очищен - записываем курсы заново
+		if err != nil {
+			log.Printf("WARNING: failed to extend exchange rates TTL, rewriting: %v", err)
+		}
 	}
 
 	exchangeRates := make([]*entity.ExchangeRate, 0, len(rates))
@@ -49,12 +103,15 @@ func (s *ExchangeRateService) FetchAndStoreRates(ctx context.Context) error {
 
 	if err := s.rateRepo.SetMultiple(ctx, exchangeRates); err != nil {
 		metrics.WorkerExchangeRateUpdates.WithLabelValues("failed").Inc()
-		return fmt.Errorf("failed to store rates in redis: %w", err)
+		s.stored = nil
+		return 0, fmt.Errorf("failed to store rates in redis: %w", err)
 	}
+	s.stored = rates
+	s.checkedAt = now
 
 	metrics.WorkerExchangeRateUpdates.WithLabelValues("success").Inc()
-	log.Printf("Successfully stored %d exchange rates", len(exchangeRates))
-	return nil
+	metrics.WorkerLastRateUpdate.SetToCurrentTime()
+	return len(exchangeRates), nil
 }
 
 func (s *ExchangeRateService) GetRate(ctx context.Context, currency string) (*entity.ExchangeRate, error) {
@@ -63,7 +120,16 @@ func (s *ExchangeRateService) GetRate(ctx context.Context, currency string) (*en
 		return nil, fmt.Errorf("failed to get rate for %s: %w", currency, err)
 	}
 
+	// UpdatedAt - время записи курса; неизменившиеся курсы не перезаписываются,
+	// поэтому актуальность считается от последней проверки API
+	s.mu.Lock()
+	checkedAt := s.checkedAt
+	s.mu.Unlock()
+
 	age := time.Since(rate.UpdatedAt)
+	if checkedAt.After(rate.UpdatedAt) {
+		age = time.Since(checkedAt)
+	}
 	if age > 2*time.Hour {
 		log.Printf("WARNING: Using outdated exchange rate for %s (age: %v)", currency, age)
 	}
@@ -79,9 +145,24 @@ func (s *ExchangeRateService) GetRates(ctx context.Context, currencies []string)
 	return rates, nil
 }
 
-func (s *ExchangeRateService) ConvertCurrency(ctx context.Context, amount float64, fromCurrency, toCurrency string) (float64, float64, error) {
+func (s *ExchangeRateService) ConvertCurrency(ctx context.Context, amount money.Amount, fromCurrency, toCurrency string) (money.Amount, float64, error) {
+	converted, rate, err := s.convert(ctx, amount, fromCurrency, toCurrency)
+	if err != nil {
+		metrics.WorkerConversionErrors.WithLabelValues(fromCurrency, toCurrency).Inc()
+	}
+	return converted, rate, err
+}
+
+// convert выполняет конвертацию по курсам из Redis
+func (s *ExchangeRateService) convert(ctx context.Context, amount money.Amount, fromCurrency, toCurrency string) (money.Amount, float64, error) {
+	target := money.LookupCurrency(toCurrency)
 	if fromCurrency == toCurrency {
-		return amount, 1.0, nil
+		return target.Round(amount), 1.0, nil
+	}
+
+	// Закрепленный администратором курс важнее курсов из внешнего API
+	if pinnedRate, ok := s.pinnedRate(ctx, fromCurrency, toCurrency); ok {
+		return target.MulRate(amount, pinnedRate), pinnedRate, nil
 	}
 
 	rates, err := s.GetRates(ctx, []string{fromCurrency, toCurrency})
@@ -100,7 +181,8 @@ func (s *ExchangeRateService) ConvertCurrency(ctx context.Context, amount float6
 	}
 
 	exchangeRate := toRate.Rate / fromRate.Rate
-	convertedAmount := amount * exchangeRate
+	// Результат округляется до минорной единицы целевой валюты по ее правилу округления
+	convertedAmount := target.MulRate(amount, exchangeRate)
 
 	return convertedAmount, exchangeRate, nil
 }
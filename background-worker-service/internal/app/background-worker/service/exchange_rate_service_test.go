@@ -8,9 +8,13 @@ import (
 
 	"augustberries/background-worker-service/internal/app/background-worker/entity"
 	"augustberries/background-worker-service/internal/app/background-worker/repository/mocks"
+	"augustberries/pkg/metrics"
+	"augustberries/pkg/money"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // ===================== FetchAndStoreRates Tests =====================
@@ -20,7 +24,7 @@ func TestFetchAndStoreRates_Success(t *testing.T) {
 	rateRepo := new(mocks.MockExchangeRateRepository)
 	apiClient := new(mocks.MockExchangeRateAPIClient)
 
-	service := NewExchangeRateService(rateRepo, apiClient)
+	service := NewExchangeRateService(rateRepo, apiClient, nil)
 
 	ctx := context.Background()
 
@@ -48,7 +52,7 @@ func TestFetchAndStoreRates_APIError_ContinuesWithCache(t *testing.T) {
 	rateRepo := new(mocks.MockExchangeRateRepository)
 	apiClient := new(mocks.MockExchangeRateAPIClient)
 
-	service := NewExchangeRateService(rateRepo, apiClient)
+	service := NewExchangeRateService(rateRepo, apiClient, nil)
 
 	ctx := context.Background()
 
@@ -68,7 +72,7 @@ func TestFetchAndStoreRates_RedisError(t *testing.T) {
 	rateRepo := new(mocks.MockExchangeRateRepository)
 	apiClient := new(mocks.MockExchangeRateAPIClient)
 
-	service := NewExchangeRateService(rateRepo, apiClient)
+	service := NewExchangeRateService(rateRepo, apiClient, nil)
 
 	ctx := context.Background()
 
@@ -85,6 +89,67 @@ func TestFetchAndStoreRates_RedisError(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to store rates")
 }
 
+func TestRefreshRates_UnchangedRatesExtendTTLOnly(t *testing.T) {
+	// Arrange
+	rateRepo := new(mocks.MockExchangeRateRepository)
+	apiClient := new(mocks.MockExchangeRateAPIClient)
+	service := NewExchangeRateService(rateRepo, apiClient, nil)
+	ctx := context.Background()
+
+	apiClient.On("FetchRates", ctx).Return(map[string]float64{"USD": 1.0, "RUB": 91.23}, nil).Twice()
+	rateRepo.On("SetMultiple", ctx, mock.Anything).Return(nil).Once()
+	rateRepo.On("Touch", ctx, mock.MatchedBy(func(currencies []string) bool {
+		return assert.ElementsMatch(t, []string{"USD", "RUB"}, currencies)
+	})).Return(2, nil).Once()
+
+	// Act
+	_, err := service.RefreshRates(ctx)
+	require.NoError(t, err)
+	count, err := service.RefreshRates(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	rateRepo.AssertNumberOfCalls(t, "SetMultiple", 1)
+	rateRepo.AssertExpectations(t)
+}
+
+func TestRefreshRates_UnchangedRatesRewrittenWhenKeysExpired(t *testing.T) {
+	// Redis очищен между запусками: продлевать нечего, курсы записываются заново
+	rateRepo := new(mocks.MockExchangeRateRepository)
+	apiClient := new(mocks.MockExchangeRateAPIClient)
+	service := NewExchangeRateService(rateRepo, apiClient, nil)
+	ctx := context.Background()
+
+	apiClient.On("FetchRates", ctx).Return(map[string]float64{"USD": 1.0, "RUB": 91.23}, nil)
+	rateRepo.On("SetMultiple", ctx, mock.Anything).Return(nil)
+	rateRepo.On("Touch", ctx, mock.Anything).Return(0, nil)
+
+	service.RefreshRates(ctx)
+	_, err := service.RefreshRates(ctx)
+
+	require.NoError(t, err)
+	rateRepo.AssertNumberOfCalls(t, "SetMultiple", 2)
+}
+
+func TestRefreshRates_ChangedRatesAreWritten(t *testing.T) {
+	rateRepo := new(mocks.MockExchangeRateRepository)
+	apiClient := new(mocks.MockExchangeRateAPIClient)
+	service := NewExchangeRateService(rateRepo, apiClient, nil)
+	ctx := context.Background()
+
+	apiClient.On("FetchRates", ctx).Return(map[string]float64{"USD": 1.0, "RUB": 91.23}, nil).Once()
+	apiClient.On("FetchRates", ctx).Return(map[string]float64{"USD": 1.0, "RUB": 92.10}, nil).Once()
+	rateRepo.On("SetMultiple", ctx, mock.Anything).Return(nil)
+
+	service.RefreshRates(ctx)
+	_, err := service.RefreshRates(ctx)
+
+	require.NoError(t, err)
+	rateRepo.AssertNumberOfCalls(t, "SetMultiple", 2)
+	rateRepo.AssertNotCalled(t, "Touch", mock.Anything, mock.Anything)
+}
+
 // ===================== GetRate Tests =====================
 
 func TestGetRate_Success(t *testing.T) {
@@ -92,7 +157,7 @@ func TestGetRate_Success(t *testing.T) {
 	rateRepo := new(mocks.MockExchangeRateRepository)
 	apiClient := new(mocks.MockExchangeRateAPIClient)
 
-	service := NewExchangeRateService(rateRepo, apiClient)
+	service := NewExchangeRateService(rateRepo, apiClient, nil)
 
 	ctx := context.Background()
 
@@ -118,7 +183,7 @@ func TestGetRate_NotFound(t *testing.T) {
 	rateRepo := new(mocks.MockExchangeRateRepository)
 	apiClient := new(mocks.MockExchangeRateAPIClient)
 
-	service := NewExchangeRateService(rateRepo, apiClient)
+	service := NewExchangeRateService(rateRepo, apiClient, nil)
 
 	ctx := context.Background()
 
@@ -140,7 +205,7 @@ func TestConvertCurrency_Success(t *testing.T) {
 	rateRepo := new(mocks.MockExchangeRateRepository)
 	apiClient := new(mocks.MockExchangeRateAPIClient)
 
-	service := NewExchangeRateService(rateRepo, apiClient)
+	service := NewExchangeRateService(rateRepo, apiClient, nil)
 
 	ctx := context.Background()
 
@@ -152,30 +217,47 @@ func TestConvertCurrency_Success(t *testing.T) {
 	rateRepo.On("GetMultiple", ctx, []string{"USD", "RUB"}).Return(rates, nil)
 
 	// Act
-	converted, exchangeRate, err := service.ConvertCurrency(ctx, 100.0, "USD", "RUB")
+	converted, exchangeRate, err := service.ConvertCurrency(ctx, money.MustParse("100.00"), "USD", "RUB")
 
 	// Assert
 	assert.NoError(t, err)
-	assert.InDelta(t, 9123.0, converted, 0.01) // 100 * 91.23 = 9123
+	assert.Equal(t, money.MustParse("9123.00"), converted) // 100 * 91.23 = 9123
 	assert.InDelta(t, 91.23, exchangeRate, 0.01)
 }
 
+func TestConvertCurrency_RoundsToTargetCurrencyPrecision(t *testing.T) {
+	// JPY без дробной части: 10.00 USD * 151.37 = 1513.70 -> 1514
+	rateRepo := new(mocks.MockExchangeRateRepository)
+	service := NewExchangeRateService(rateRepo, new(mocks.MockExchangeRateAPIClient), nil)
+	ctx := context.Background()
+
+	rateRepo.On("GetMultiple", ctx, []string{"USD", "JPY"}).Return(map[string]*entity.ExchangeRate{
+		"USD": {Currency: "USD", Rate: 1.0, UpdatedAt: time.Now()},
+		"JPY": {Currency: "JPY", Rate: 151.37, UpdatedAt: time.Now()},
+	}, nil)
+
+	converted, _, err := service.ConvertCurrency(ctx, money.MustParse("10.00"), "USD", "JPY")
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("1514"), converted)
+}
+
 func TestConvertCurrency_SameCurrency(t *testing.T) {
 	// Конвертация USD -> USD должна вернуть ту же сумму
 	// Arrange
 	rateRepo := new(mocks.MockExchangeRateRepository)
 	apiClient := new(mocks.MockExchangeRateAPIClient)
 
-	service := NewExchangeRateService(rateRepo, apiClient)
+	service := NewExchangeRateService(rateRepo, apiClient, nil)
 
 	ctx := context.Background()
 
 	// Act
-	converted, exchangeRate, err := service.ConvertCurrency(ctx, 100.0, "USD", "USD")
+	converted, exchangeRate, err := service.ConvertCurrency(ctx, money.MustParse("100.00"), "USD", "USD")
 
 	// Assert
 	assert.NoError(t, err)
-	assert.Equal(t, 100.0, converted)
+	assert.Equal(t, money.MustParse("100.00"), converted)
 	assert.Equal(t, 1.0, exchangeRate)
 	rateRepo.AssertNotCalled(t, "GetMultiple") // Репозиторий не должен вызываться
 }
@@ -186,7 +268,7 @@ func TestConvertCurrency_EURtoRUB(t *testing.T) {
 	rateRepo := new(mocks.MockExchangeRateRepository)
 	apiClient := new(mocks.MockExchangeRateAPIClient)
 
-	service := NewExchangeRateService(rateRepo, apiClient)
+	service := NewExchangeRateService(rateRepo, apiClient, nil)
 
 	ctx := context.Background()
 
@@ -198,13 +280,13 @@ func TestConvertCurrency_EURtoRUB(t *testing.T) {
 	rateRepo.On("GetMultiple", ctx, []string{"EUR", "RUB"}).Return(rates, nil)
 
 	// Act
-	converted, exchangeRate, err := service.ConvertCurrency(ctx, 100.0, "EUR", "RUB")
+	converted, exchangeRate, err := service.ConvertCurrency(ctx, money.MustParse("100.00"), "EUR", "RUB")
 
 	// Assert
 	assert.NoError(t, err)
 	// 100 EUR * (91.23 / 0.93) = 100 * 98.096... = 9809.67...
 	expectedRate := 91.23 / 0.93
-	assert.InDelta(t, 100.0*expectedRate, converted, 0.01)
+	assert.Equal(t, money.MustParse("9809.68"), converted) // округление до копеек
 	assert.InDelta(t, expectedRate, exchangeRate, 0.01)
 }
 
@@ -213,7 +295,7 @@ func TestConvertCurrency_FromCurrencyNotFound(t *testing.T) {
 	rateRepo := new(mocks.MockExchangeRateRepository)
 	apiClient := new(mocks.MockExchangeRateAPIClient)
 
-	service := NewExchangeRateService(rateRepo, apiClient)
+	service := NewExchangeRateService(rateRepo, apiClient, nil)
 
 	ctx := context.Background()
 
@@ -225,7 +307,7 @@ func TestConvertCurrency_FromCurrencyNotFound(t *testing.T) {
 	rateRepo.On("GetMultiple", ctx, []string{"USD", "RUB"}).Return(rates, nil)
 
 	// Act
-	_, _, err := service.ConvertCurrency(ctx, 100.0, "USD", "RUB")
+	_, _, err := service.ConvertCurrency(ctx, money.MustParse("100.00"), "USD", "RUB")
 
 	// Assert
 	assert.Error(t, err)
@@ -237,7 +319,7 @@ func TestConvertCurrency_ToCurrencyNotFound(t *testing.T) {
 	rateRepo := new(mocks.MockExchangeRateRepository)
 	apiClient := new(mocks.MockExchangeRateAPIClient)
 
-	service := NewExchangeRateService(rateRepo, apiClient)
+	service := NewExchangeRateService(rateRepo, apiClient, nil)
 
 	ctx := context.Background()
 
@@ -249,7 +331,7 @@ func TestConvertCurrency_ToCurrencyNotFound(t *testing.T) {
 	rateRepo.On("GetMultiple", ctx, []string{"USD", "RUB"}).Return(rates, nil)
 
 	// Act
-	_, _, err := service.ConvertCurrency(ctx, 100.0, "USD", "RUB")
+	_, _, err := service.ConvertCurrency(ctx, money.MustParse("100.00"), "USD", "RUB")
 
 	// Assert
 	assert.Error(t, err)
@@ -261,14 +343,14 @@ func TestConvertCurrency_RepoError(t *testing.T) {
 	rateRepo := new(mocks.MockExchangeRateRepository)
 	apiClient := new(mocks.MockExchangeRateAPIClient)
 
-	service := NewExchangeRateService(rateRepo, apiClient)
+	service := NewExchangeRateService(rateRepo, apiClient, nil)
 
 	ctx := context.Background()
 
 	rateRepo.On("GetMultiple", ctx, []string{"USD", "RUB"}).Return(nil, errors.New("redis error"))
 
 	// Act
-	_, _, err := service.ConvertCurrency(ctx, 100.0, "USD", "RUB")
+	_, _, err := service.ConvertCurrency(ctx, money.MustParse("100.00"), "USD", "RUB")
 
 	// Assert
 	assert.Error(t, err)
@@ -282,7 +364,7 @@ func TestEnsureRatesAvailable_AllExist(t *testing.T) {
 	rateRepo := new(mocks.MockExchangeRateRepository)
 	apiClient := new(mocks.MockExchangeRateAPIClient)
 
-	service := NewExchangeRateService(rateRepo, apiClient)
+	service := NewExchangeRateService(rateRepo, apiClient, nil)
 
 	ctx := context.Background()
 
@@ -304,7 +386,7 @@ func TestEnsureRatesAvailable_MissingRate_FetchesFromAPI(t *testing.T) {
 	rateRepo := new(mocks.MockExchangeRateRepository)
 	apiClient := new(mocks.MockExchangeRateAPIClient)
 
-	service := NewExchangeRateService(rateRepo, apiClient)
+	service := NewExchangeRateService(rateRepo, apiClient, nil)
 
 	ctx := context.Background()
 
@@ -322,3 +404,47 @@ func TestEnsureRatesAvailable_MissingRate_FetchesFromAPI(t *testing.T) {
 	assert.NoError(t, err)
 	apiClient.AssertExpectations(t)
 }
+
+// ===================== Metrics Tests =====================
+
+func TestConvertCurrency_ErrorIncrementsConversionErrors(t *testing.T) {
+	// Arrange
+	rateRepo := new(mocks.MockExchangeRateRepository)
+	apiClient := new(mocks.MockExchangeRateAPIClient)
+
+	service := NewExchangeRateService(rateRepo, apiClient, nil)
+
+	ctx := context.Background()
+	counter := metrics.WorkerConversionErrors.WithLabelValues("EUR", "GBP")
+	before := testutil.ToFloat64(counter)
+
+	rateRepo.On("GetMultiple", ctx, []string{"EUR", "GBP"}).Return(nil, errors.New("redis error"))
+
+	// Act
+	_, _, err := service.ConvertCurrency(ctx, money.MustParse("100.00"), "EUR", "GBP")
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, before+1, testutil.ToFloat64(counter))
+}
+
+func TestRefreshRates_SetsLastSuccessfulUpdate(t *testing.T) {
+	// Arrange
+	rateRepo := new(mocks.MockExchangeRateRepository)
+	apiClient := new(mocks.MockExchangeRateAPIClient)
+
+	service := NewExchangeRateService(rateRepo, apiClient, nil)
+
+	ctx := context.Background()
+	started := time.Now().Unix()
+
+	apiClient.On("FetchRates", ctx).Return(map[string]float64{"USD": 1.0, "RUB": 91.23}, nil)
+	rateRepo.On("SetMultiple", ctx, mock.Anything).Return(nil)
+
+	// Act
+	_, err := service.RefreshRates(ctx)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, testutil.ToFloat64(metrics.WorkerLastRateUpdate), float64(started))
+}
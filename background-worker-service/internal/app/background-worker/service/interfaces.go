@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"augustberries/background-worker-service/internal/app/background-worker/entity"
+	"augustberries/pkg/money"
 )
 
 // ExchangeRateServiceInterface определяет интерфейс для работы с курсами валют
@@ -15,7 +16,7 @@ type ExchangeRateServiceInterface interface {
 	// GetRates получает курсы нескольких валют из Redis
 	GetRates(ctx context.Context, currencies []string) (map[string]*entity.ExchangeRate, error)
 	// ConvertCurrency конвертирует сумму из одной валюты в другую
-	ConvertCurrency(ctx context.Context, amount float64, from, to string) (float64, float64, error)
+	ConvertCurrency(ctx context.Context, amount money.Amount, from, to string) (money.Amount, float64, error)
 	// EnsureRatesAvailable проверяет наличие курсов в Redis
 	EnsureRatesAvailable(ctx context.Context) error
 }
@@ -24,6 +25,14 @@ type ExchangeRateServiceInterface interface {
 type OrderProcessingServiceInterface interface {
 	// ProcessOrderEvent обрабатывает событие заказа из Kafka
 	ProcessOrderEvent(ctx context.Context, event *entity.OrderEvent) error
+	// ProcessOrderEventsBatch обрабатывает пачку событий и возвращает ошибки по индексам событий
+	ProcessOrderEventsBatch(ctx context.Context, events []*entity.OrderEvent) []error
+}
+
+// OrderNotifierInterface определяет интерфейс писем покупателю о заказе
+type OrderNotifierInterface interface {
+	// NotifyOrderEvent отправляет письмо по обработанному событию заказа, если событие его требует
+	NotifyOrderEvent(ctx context.Context, event *entity.OrderEvent) error
 }
 
 // ExchangeRateAPIClient определяет интерфейс для взаимодействия с внешним API курсов валют
@@ -31,3 +40,9 @@ type ExchangeRateAPIClient interface {
 	// FetchRates получает курсы валют из внешнего API
 	FetchRates(ctx context.Context) (map[string]float64, error)
 }
+
+// EmailSender определяет интерфейс отправки писем покупателям
+type EmailSender interface {
+	// Send отправляет готовое письмо получателю
+	Send(ctx context.Context, recipient entity.EmailRecipient, email *entity.RenderedEmail) error
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"augustberries/background-worker-service/internal/app/background-worker/entity"
+	"augustberries/background-worker-service/internal/app/background-worker/repository"
+	"augustberries/pkg/metrics"
+	"augustberries/pkg/money"
+	"augustberries/pkg/units"
+
+	"github.com/google/uuid"
+)
+
+// OrderNotifier отправляет покупателю письма о заказе: оформление, отправку и отмену
+// Письмо собирается из заказа в БД после конвертации, поэтому суммы в нем - в валюте покупателя
+type OrderNotifier struct {
+	orderRepo repository.OrderRepository
+	renderer  *EmailRenderer
+	sender    EmailSender
+}
+
+// NewOrderNotifier создает сервис писем о заказе
+// sender может быть nil - тогда доступен только предпросмотр писем
+func NewOrderNotifier(orderRepo repository.OrderRepository, renderer *EmailRenderer, sender EmailSender) *OrderNotifier {
+	return &OrderNotifier{
+		orderRepo: orderRepo,
+		renderer:  renderer,
+		sender:    sender,
+	}
+}
+
+// EmailTemplateFor возвращает шаблон письма для события заказа; false - событие не требует письма
+// ORDER_UPDATED требует письма только при смене статуса: изменение состава заказа письмо не отправляет
+func EmailTemplateFor(event *entity.OrderEvent) (entity.EmailTemplate, bool) {
+	switch event.EventType {
+	case entity.EventTypeOrderCreated:
+		return entity.EmailOrderConfirmation, true
+	case entity.EventTypeOrderUpdated:
+		if totalsChanged(event) {
+			return "", false
+		}
+		switch event.Status {
+		case entity.OrderStatusShipped, entity.OrderStatusPartiallyShipped:
+			return entity.EmailOrderShipped, true
+		case entity.OrderStatusCancelled:
+			return entity.EmailOrderCancelled, true
+		}
+	}
+	return "", false
+}
+
+// NotifyOrderEvent отправляет письмо по событию заказа в локали по умолчанию
+func (n *OrderNotifier) NotifyOrderEvent(ctx context.Context, event *entity.OrderEvent) error {
+	name, ok := EmailTemplateFor(event)
+	if !ok || n.sender == nil {
+		return nil
+	}
+
+	order, data, err := n.buildOrderEmail(ctx, event.OrderID)
+	if err != nil {
+		metrics.WorkerEmailsSent.WithLabelValues(string(name), "render_error").Inc()
+		return err
+	}
+	email, err := n.renderer.Render(name, n.renderer.DefaultLocale(), data)
+	if err != nil {
+		metrics.WorkerEmailsSent.WithLabelValues(string(name), "render_error").Inc()
+		return err
+	}
+
+	recipient := entity.EmailRecipient{UserID: order.UserID}
+	if order.GuestEmail != nil {
+		recipient.Email = *order.GuestEmail
+	}
+	if err := n.sender.Send(ctx, recipient, email); err != nil {
+		metrics.WorkerEmailsSent.WithLabelValues(string(name), "send_error").Inc()
+		return fmt.Errorf("failed to send %s email for order %s: %w", name, event.OrderID, err)
+	}
+
+	metrics.WorkerEmailsSent.WithLabelValues(string(name), "success").Inc()
+	log.Printf("Sent %s email for order %s", name, event.OrderID)
+	return nil
+}
+
+// Preview отрисовывает письмо без отправки: по заказу из БД или, если orderID не задан, по демонстрационному заказу
+func (n *OrderNotifier) Preview(ctx context.Context, name entity.EmailTemplate, locale string, orderID uuid.UUID) (*entity.RenderedEmail, error) {
+	if !name.Valid() {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEmailTemplate, name)
+	}
+
+	data := SampleOrderEmail()
+	if orderID != uuid.Nil {
+		var err error
+		if _, data, err = n.buildOrderEmail(ctx, orderID); err != nil {
+			return nil, err
+		}
+	}
+	return n.renderer.Render(name, locale, data)
+}
+
+// Locales возвращает локали, для которых есть шаблоны писем
+func (n *OrderNotifier) Locales() []string {
+	return n.renderer.Locales()
+}
+
+// buildOrderEmail собирает данные письма: позиции из снимков товаров и итоги заказа в его текущей валюте
+func (n *OrderNotifier) buildOrderEmail(ctx context.Context, orderID uuid.UUID) (*entity.Order, *entity.OrderEmail, error) {
+	order, err := n.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrderNotFound) {
+			return nil, nil, ErrOrderNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	items, err := n.orderRepo.GetItems(ctx, orderID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get order items: %w", err)
+	}
+
+	data := &entity.OrderEmail{
+		OrderID:               order.ID,
+		OrderNumber:           order.Number,
+		Status:                order.Status,
+		CreatedAt:             order.CreatedAt,
+		Currency:              sourceCurrency(order),
+		Items:                 make([]entity.OrderEmailItem, len(items)),
+		Tax:                   order.TaxTotal,
+		Delivery:              order.DeliveryPrice,
+		Total:                 order.TotalPrice,
+		EstimatedDeliveryFrom: order.EstimatedDeliveryFrom,
+		EstimatedDeliveryTo:   order.EstimatedDeliveryTo,
+	}
+	// Заказы, созданные до нумерации, называются по ID
+	if data.OrderNumber == "" {
+		data.OrderNumber = order.ID.String()
+	}
+	for i, item := range items {
+		name := item.Product.Name
+		if name == "" {
+			name = item.ProductID.String()
+		}
+		line := entity.OrderEmailItem{
+			Name:      name,
+			Quantity:  item.Quantity,
+			Unit:      item.Product.Unit.OrDefault(),
+			UnitPrice: item.UnitPrice,
+			Total:     item.UnitPrice.MulQuantity(item.Quantity),
+		}
+		data.Items[i] = line
+		data.Subtotal += line.Total
+	}
+	return order, data, nil
+}
+
+// SampleOrderEmail возвращает демонстрационный заказ для предпросмотра шаблонов
+func SampleOrderEmail() *entity.OrderEmail {
+	createdAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	deliveryFrom := createdAt.AddDate(0, 0, 3)
+	deliveryTo := createdAt.AddDate(0, 0, 5)
+
+	items := []entity.OrderEmailItem{
+		{Name: "Клубника садовая", Quantity: units.MustParse("1.5"), Unit: units.UnitKilogram, UnitPrice: money.MustParse("450.00")},
+		{Name: "Морс брусничный", Quantity: units.Of(2), Unit: units.UnitLiter, UnitPrice: money.MustParse("180.00")},
+		{Name: "Корзина подарочная", Quantity: units.Of(1), Unit: units.UnitPiece, UnitPrice: money.MustParse("990.00")},
+	}
+	var subtotal money.Amount
+	for i := range items {
+		items[i].Total = items[i].UnitPrice.MulQuantity(items[i].Quantity)
+		subtotal += items[i].Total
+	}
+	tax := money.MustParse("402.00")
+	delivery := money.MustParse("300.00")
+
+	return &entity.OrderEmail{
+		OrderID:               uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+		OrderNumber:           "AB-20240115-000123",
+		Status:                entity.OrderStatusConfirmed,
+		CreatedAt:             createdAt,
+		Currency:              "RUB",
+		Items:                 items,
+		Subtotal:              subtotal,
+		Tax:                   tax,
+		Delivery:              delivery,
+		Total:                 subtotal + tax + delivery,
+		EstimatedDeliveryFrom: &deliveryFrom,
+		EstimatedDeliveryTo:   &deliveryTo,
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"augustberries/background-worker-service/internal/app/background-worker/entity"
+	"augustberries/background-worker-service/internal/app/background-worker/repository/mocks"
+	"augustberries/pkg/money"
+	"augustberries/pkg/units"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockEmailSender запоминает отправленные письма
+type mockEmailSender struct {
+	mock.Mock
+}
+
+func (m *mockEmailSender) Send(ctx context.Context, recipient entity.EmailRecipient, email *entity.RenderedEmail) error {
+	return m.Called(ctx, recipient, email).Error(0)
+}
+
+func newTestNotifier(t *testing.T) (*OrderNotifier, *mocks.MockOrderRepository, *mockEmailSender) {
+	renderer, err := NewEmailRenderer("ru")
+	require.NoError(t, err)
+	orderRepo := new(mocks.MockOrderRepository)
+	sender := new(mockEmailSender)
+	return NewOrderNotifier(orderRepo, renderer, sender), orderRepo, sender
+}
+
+// ===================== EmailTemplateFor Tests =====================
+
+func TestEmailTemplateFor(t *testing.T) {
+	previous := money.MustParse("100.00")
+
+	tests := []struct {
+		name     string
+		event    entity.OrderEvent
+		expected entity.EmailTemplate
+		ok       bool
+	}{
+		{"created", entity.OrderEvent{EventType: entity.EventTypeOrderCreated, Status: entity.OrderStatusPending}, entity.EmailOrderConfirmation, true},
+		{"shipped", entity.OrderEvent{EventType: entity.EventTypeOrderUpdated, Status: entity.OrderStatusShipped}, entity.EmailOrderShipped, true},
+		{"partially shipped", entity.OrderEvent{EventType: entity.EventTypeOrderUpdated, Status: entity.OrderStatusPartiallyShipped}, entity.EmailOrderShipped, true},
+		{"cancelled", entity.OrderEvent{EventType: entity.EventTypeOrderUpdated, Status: entity.OrderStatusCancelled}, entity.EmailOrderCancelled, true},
+		{"confirmed", entity.OrderEvent{EventType: entity.EventTypeOrderUpdated, Status: entity.OrderStatusConfirmed}, "", false},
+		{"items changed", entity.OrderEvent{
+			EventType: entity.EventTypeOrderUpdated, Status: entity.OrderStatusShipped,
+			TotalPrice: money.MustParse("90.00"), PreviousTotalPrice: &previous,
+		}, "", false},
+		{"unknown", entity.OrderEvent{EventType: "ORDER_DELIVERED"}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, ok := EmailTemplateFor(&tt.event)
+
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, name)
+		})
+	}
+}
+
+// ===================== NotifyOrderEvent Tests =====================
+
+func TestNotifyOrderEvent_ConfirmationWithSnapshotsAndConvertedTotals(t *testing.T) {
+	// Arrange
+	notifier, orderRepo, sender := newTestNotifier(t)
+	ctx := context.Background()
+	orderID := uuid.New()
+	guestEmail := "guest@example.com"
+
+	// Заказ уже сконвертирован в RUB
+	orderRepo.On("GetByID", ctx, orderID).Return(&entity.Order{
+		ID: orderID, Number: "AB-20240115-000001", UserID: uuid.New(), GuestEmail: &guestEmail,
+		TotalPrice: money.MustParse("1150.00"), DeliveryPrice: money.MustParse("300.00"), TaxTotal: money.MustParse("100.00"),
+		Currency: "RUB", Status: entity.OrderStatusPending,
+	}, nil)
+	orderRepo.On("GetItems", ctx, orderID).Return([]entity.OrderItem{
+		{ProductID: uuid.New(), Quantity: units.MustParse("1.5"), UnitPrice: money.MustParse("500.00"),
+			Product: entity.ProductSnapshot{Name: "Клубника", Unit: units.UnitKilogram}},
+	}, nil)
+	sender.On("Send", ctx, mock.MatchedBy(func(r entity.EmailRecipient) bool { return r.Email == guestEmail }), mock.MatchedBy(func(email *entity.RenderedEmail) bool {
+		return email.Template == entity.EmailOrderConfirmation && email.Locale == "ru" &&
+			email.Subject == "Заказ AB-20240115-000001 оформлен"
+	})).Return(nil).Run(func(args mock.Arguments) {
+		email := args.Get(2).(*entity.RenderedEmail)
+		assert.Contains(t, email.Text, "- Клубника: 1.5 кг x 500.00 RUB = 750.00 RUB")
+		assert.Contains(t, email.Text, "Налог: 100.00 RUB")
+		assert.Contains(t, email.Text, "Итого: 1150.00 RUB")
+	})
+
+	// Act
+	err := notifier.NotifyOrderEvent(ctx, &entity.OrderEvent{EventType: entity.EventTypeOrderCreated, OrderID: orderID})
+
+	// Assert
+	require.NoError(t, err)
+	sender.AssertNumberOfCalls(t, "Send", 1)
+}
+
+func TestNotifyOrderEvent_RegisteredCustomerByUserID(t *testing.T) {
+	// Arrange
+	notifier, orderRepo, sender := newTestNotifier(t)
+	ctx := context.Background()
+	orderID, userID := uuid.New(), uuid.New()
+
+	orderRepo.On("GetByID", ctx, orderID).Return(&entity.Order{
+		ID: orderID, UserID: userID, Currency: "USD", Status: entity.OrderStatusCancelled,
+	}, nil)
+	orderRepo.On("GetItems", ctx, orderID).Return([]entity.OrderItem{}, nil)
+	sender.On("Send", ctx, entity.EmailRecipient{UserID: userID}, mock.MatchedBy(func(email *entity.RenderedEmail) bool {
+		// Заказ без номера называется по ID
+		return email.Template == entity.EmailOrderCancelled && email.Subject == "Заказ "+orderID.String()+" отменен"
+	})).Return(nil)
+
+	// Act
+	err := notifier.NotifyOrderEvent(ctx, &entity.OrderEvent{
+		EventType: entity.EventTypeOrderUpdated, OrderID: orderID, Status: entity.OrderStatusCancelled,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	sender.AssertExpectations(t)
+}
+
+func TestNotifyOrderEvent_NoEmailForEvent(t *testing.T) {
+	notifier, orderRepo, sender := newTestNotifier(t)
+
+	err := notifier.NotifyOrderEvent(context.Background(), &entity.OrderEvent{
+		EventType: entity.EventTypeOrderUpdated, OrderID: uuid.New(), Status: entity.OrderStatusConfirmed,
+	})
+
+	require.NoError(t, err)
+	orderRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	sender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNotifyOrderEvent_SendError(t *testing.T) {
+	// Arrange
+	notifier, orderRepo, sender := newTestNotifier(t)
+	ctx := context.Background()
+	orderID := uuid.New()
+
+	orderRepo.On("GetByID", ctx, orderID).Return(&entity.Order{ID: orderID, UserID: uuid.New(), Currency: "RUB"}, nil)
+	orderRepo.On("GetItems", ctx, orderID).Return([]entity.OrderItem{}, nil)
+	sender.On("Send", ctx, mock.Anything, mock.Anything).Return(errors.New("smtp unavailable"))
+
+	// Act
+	err := notifier.NotifyOrderEvent(ctx, &entity.OrderEvent{EventType: entity.EventTypeOrderCreated, OrderID: orderID})
+
+	// Assert
+	assert.ErrorContains(t, err, "smtp unavailable")
+}
@@ -2,11 +2,15 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"augustberries/background-worker-service/internal/app/background-worker/entity"
 	"augustberries/background-worker-service/internal/app/background-worker/repository"
+	"augustberries/pkg/metrics"
+	"augustberries/pkg/money"
 
 	"github.com/google/uuid"
 )
@@ -15,6 +19,9 @@ import (
 type OrderProcessingService struct {
 	orderRepo   repository.OrderRepository
 	exchangeSvc ExchangeRateServiceInterface
+	calculator  *money.OrderCalculator
+	// defaultTarget - валюта конвертации заказов без предпочитаемой валюты покупателя
+	defaultTarget string
 }
 
 // NewOrderProcessingService создает новый сервис обработки заказов
@@ -25,17 +32,50 @@ func NewOrderProcessingService(
 	return &OrderProcessingService{
 		orderRepo:   orderRepo,
 		exchangeSvc: exchangeSvc,
+		calculator:  money.NewOrderCalculator(),
+
+		defaultTarget: DefaultTargetCurrency,
+	}
+}
+
+// DefaultTargetCurrency - валюта конвертации заказов по умолчанию
+const DefaultTargetCurrency = "RUB"
+
+// SetDefaultTargetCurrency задает валюту конвертации заказов без предпочитаемой валюты покупателя
+// Неподдерживаемая валюта игнорируется
+func (s *OrderProcessingService) SetDefaultTargetCurrency(code string) {
+	if isSupportedCurrency(code) {
+		s.defaultTarget = code
+	}
+}
+
+// targetCurrency возвращает валюту, в которую конвертируется заказ из события:
+// предпочитаемую валюту покупателя или валюту по умолчанию
+func (s *OrderProcessingService) targetCurrency(event *entity.OrderEvent) string {
+	if isSupportedCurrency(event.PreferredCurrency) {
+		return event.PreferredCurrency
 	}
+	return s.defaultTarget
+}
+
+func isSupportedCurrency(code string) bool {
+	for _, supported := range entity.SupportedCurrencies {
+		if code == supported {
+			return true
+		}
+	}
+	return false
 }
 
 // ProcessOrderCreated обрабатывает событие ORDER_CREATED
 // ЛОГИКА:
 // 1. Получить цены товаров из заказа (они в USD согласно каталогу)
-// 2. Конвертировать в RUB
-// 3. Рассчитать доставку в RUB
-// 4. Сохранить заказ с currency = "RUB"
+// 2. Конвертировать в предпочитаемую валюту покупателя (по умолчанию RUB)
+// 3. Рассчитать доставку в этой валюте
+// 4. Сохранить заказ с новой валютой
+// Событие не старше последней конвертации заказа (converted_at) пропускается
 func (s *OrderProcessingService) ProcessOrderCreated(ctx context.Context, event *entity.OrderEvent) error {
-	log.Printf("Processing ORDER_CREATED for order %s (currency: %s)", event.OrderID, event.Currency)
+	log.Printf("Processing %s for order %s (currency: %s)", event.EventType, event.OrderID, event.Currency)
 
 	// Получаем заказ из БД
 	order, err := s.orderRepo.GetByID(ctx, event.OrderID)
@@ -48,30 +88,51 @@ func (s *OrderProcessingService) ProcessOrderCreated(ctx context.Context, event
 		return fmt.Errorf("order validation failed: %w", err)
 	}
 
+	eventTime := eventTimestamp(event)
+	if isStale(order, eventTime) {
+		log.Printf("Order %s already converted by a newer event (%s), skipping %s from %s",
+			order.ID, order.ConvertedAt.Format(time.RFC3339Nano), event.EventType, eventTime.Format(time.RFC3339Nano))
+		return nil
+	}
+
 	// Проверяем что у заказа есть стоимость доставки для обработки
 	if order.DeliveryPrice == 0 {
 		log.Printf("Order %s has zero delivery price, skipping processing", order.ID)
 		return nil
 	}
 
+	// Позиции нужны, чтобы итог в целевой валюте собирался из сконвертированных цен товаров
+	items, err := s.orderRepo.GetItems(ctx, order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get order items: %w", err)
+	}
+
 	// Рассчитываем доставку с учетом курса валюты
-	calculation, err := s.calculateDeliveryWithExchange(ctx, order)
+	calculation, err := s.calculateDeliveryWithExchange(ctx, order, items, s.targetCurrency(event))
 	if err != nil {
 		return fmt.Errorf("failed to calculate delivery: %w", err)
 	}
 
-	// Обновляем заказ в БД: доставка, итоговая цена и валюта RUB
+	// Обновляем заказ в БД: доставка, налоги, итоговая цена и целевая валюта
 	if err := s.orderRepo.UpdateOrderWithCurrency(
 		ctx,
 		order.ID,
 		calculation.ConvertedDelivery,
+		calculation.ConvertedTax,
 		calculation.NewTotalPrice,
-		"RUB", // Сохраняем заказ с currency = "RUB"
+		calculation.ConvertedCurrency,
+		calculation.ConvertedItems,
+		eventTime,
 	); err != nil {
+		// Заказ сконвертировали по более новому событию между чтением и записью
+		if errors.Is(err, repository.ErrStaleConversion) {
+			log.Printf("Order %s converted concurrently by a newer event, skipping %s", order.ID, event.EventType)
+			return nil
+		}
 		return fmt.Errorf("failed to update order: %w", err)
 	}
 
-	log.Printf("Successfully processed order %s: delivery %.2f %s -> %.2f %s (rate: %.4f), total: %.2f RUB",
+	log.Printf("Successfully processed order %s: delivery %s %s -> %s %s (rate: %.4f), total: %s %s",
 		order.ID,
 		calculation.OriginalDelivery,
 		calculation.OriginalCurrency,
@@ -79,6 +140,7 @@ func (s *OrderProcessingService) ProcessOrderCreated(ctx context.Context, event
 		calculation.ConvertedCurrency,
 		calculation.ExchangeRate,
 		calculation.NewTotalPrice,
+		calculation.ConvertedCurrency,
 	)
 
 	return nil
@@ -87,22 +149,21 @@ func (s *OrderProcessingService) ProcessOrderCreated(ctx context.Context, event
 // calculateDeliveryWithExchange рассчитывает стоимость доставки с учетом курса валюты
 // ЛОГИКА:
 // 1. Получить цены товаров из заказа (они в USD согласно каталогу)
-// 2. Конвертировать товары и доставку из USD в RUB
-// 3. Сохранить заказ с currency = "RUB"
+// 2. Конвертировать товары и доставку в целевую валюту
+// Итог всегда равен сумме сконвертированных позиций, их налогов и сконвертированной доставки
 func (s *OrderProcessingService) calculateDeliveryWithExchange(
 	ctx context.Context,
 	order *entity.Order,
+	items []entity.OrderItem,
+	targetCurrency string,
 ) (*entity.DeliveryCalculation, error) {
-	// Целевая валюта всегда RUB
-	targetCurrency := "RUB"
-
 	// Исходная валюта заказа (по умолчанию USD согласно каталогу)
 	sourceCurrency := order.Currency
 	if sourceCurrency == "" {
 		sourceCurrency = "USD"
 	}
 
-	// Конвертируем стоимость доставки из USD в RUB
+	// Конвертируем стоимость доставки в целевую валюту
 	convertedDelivery, exchangeRate, err := s.exchangeSvc.ConvertCurrency(
 		ctx,
 		order.DeliveryPrice,
@@ -113,49 +174,346 @@ func (s *OrderProcessingService) calculateDeliveryWithExchange(
 		return nil, fmt.Errorf("failed to convert delivery price from %s to %s: %w", sourceCurrency, targetCurrency, err)
 	}
 
-	// Конвертируем цену товаров (без доставки) из USD в RUB
-	priceWithoutDelivery := order.TotalPrice - order.DeliveryPrice
+	var newTotal, convertedTax money.Amount
+	var convertedItems []entity.OrderItem
 
-	convertedPrice, _, err := s.exchangeSvc.ConvertCurrency(
-		ctx,
-		priceWithoutDelivery,
-		sourceCurrency,
-		targetCurrency,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to convert total price from %s to %s: %w", sourceCurrency, targetCurrency, err)
-	}
+	if len(items) > 0 {
+		var totals money.Totals
+		convertedItems, totals, err = s.convertItems(order, items, exchangeRate, targetCurrency)
+		if err != nil {
+			return nil, err
+		}
+
+		convertedDelivery = totals.Delivery
+		convertedTax = totals.Tax
+		newTotal = totals.Total
+	} else {
+		// Заказ без позиций (старые данные): конвертируем цену товаров (без доставки и налогов) целиком
+		priceWithoutDelivery := order.TotalPrice - order.DeliveryPrice - order.TaxTotal
 
-	// Новая итоговая сумма в RUB = конвертированная цена товаров + конвертированная доставка
-	newTotal := convertedPrice + convertedDelivery
+		convertedPrice, _, err := s.exchangeSvc.ConvertCurrency(
+			ctx,
+			priceWithoutDelivery,
+			sourceCurrency,
+			targetCurrency,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert total price from %s to %s: %w", sourceCurrency, targetCurrency, err)
+		}
+
+		// Налоги конвертируются отдельно по тому же курсу
+		convertedTax = money.LookupCurrency(targetCurrency).MulRate(order.TaxTotal, exchangeRate)
+
+		// Новая итоговая сумма = конвертированная цена товаров + налоги + конвертированная доставка
+		newTotal = convertedPrice + convertedTax + convertedDelivery
+	}
 
 	return &entity.DeliveryCalculation{
 		OrderID:           order.ID,
 		OriginalDelivery:  order.DeliveryPrice,
 		OriginalCurrency:  sourceCurrency,
 		ConvertedDelivery: convertedDelivery,
-		ConvertedCurrency: targetCurrency, // Всегда RUB
+		ConvertedTax:      convertedTax,
+		ConvertedCurrency: targetCurrency,
 		ExchangeRate:      exchangeRate,
 		NewTotalPrice:     newTotal,
-		CalculatedAt:      order.CreatedAt,
+		ConvertedItems:    convertedItems,
 	}, nil
 }
 
+// convertItems проверяет, что итог заказа сходится с позициями, и конвертирует позиции, их налоги и доставку по курсу
+func (s *OrderProcessingService) convertItems(
+	order *entity.Order,
+	items []entity.OrderItem,
+	exchangeRate float64,
+	targetCurrency string,
+) ([]entity.OrderItem, money.Totals, error) {
+	// Проверяем инвариант исходного заказа: итог = сумма позиций + налоги + доставка
+	lines := make([]money.Line, len(items))
+	for i, item := range items {
+		lines[i] = money.Line{UnitPrice: item.UnitPrice, Quantity: item.Quantity, Tax: item.TaxAmount}
+	}
+
+	original, err := s.calculator.Calculate(lines, order.DeliveryPrice, 0)
+	if err != nil {
+		return nil, money.Totals{}, fmt.Errorf("invalid order items: %w", err)
+	}
+	if err := s.calculator.Verify(order.TotalPrice, original); err != nil {
+		return nil, money.Totals{}, fmt.Errorf("order %s total does not match its items: %w", order.ID, err)
+	}
+
+	// Конвертируем каждую позицию и ее налог по тому же курсу, что и доставку
+	convertedLines, totals, err := s.calculator.Convert(lines, order.DeliveryPrice, 0, exchangeRate, money.LookupCurrency(targetCurrency))
+	if err != nil {
+		return nil, money.Totals{}, fmt.Errorf("failed to convert order items: %w", err)
+	}
+
+	convertedItems := make([]entity.OrderItem, len(items))
+	for i, item := range items {
+		item.UnitPrice = convertedLines[i].UnitPrice
+		item.TaxAmount = convertedLines[i].Tax
+		convertedItems[i] = item
+	}
+
+	return convertedItems, totals, nil
+}
+
+// eventTimestamp возвращает время события; события без времени считаются текущими
+func eventTimestamp(event *entity.OrderEvent) time.Time {
+	if event.Timestamp.IsZero() {
+		return time.Now()
+	}
+	return event.Timestamp
+}
+
+// isStale сообщает, что заказ уже сконвертирован по событию не старше eventTime
+// Повторная доставка или событие, пришедшее не по порядку, не должны перезаписывать более новую конвертацию
+func isStale(order *entity.Order, eventTime time.Time) bool {
+	return order.ConvertedAt != nil && !eventTime.After(*order.ConvertedAt)
+}
+
+// ProcessOrderUpdated обрабатывает событие ORDER_UPDATED
+// Orders Service пересчитывает позиции и итог в исходной валюте заказа, поэтому при их изменении
+// заказ конвертируется заново. Итог включает доставку, так что изменение доставки тоже меняет итог.
+// Смена статуса без изменения сумм на конвертацию не влияет
+func (s *OrderProcessingService) ProcessOrderUpdated(ctx context.Context, event *entity.OrderEvent) error {
+	if !totalsChanged(event) {
+		log.Printf("Order %s updated without total changes (status: %s), skipping conversion", event.OrderID, event.Status)
+		return nil
+	}
+
+	log.Printf("Order %s totals changed (%d item changes), reprocessing conversion", event.OrderID, len(event.ItemChanges))
+	return s.ProcessOrderCreated(ctx, event)
+}
+
+// totalsChanged сообщает, изменились ли в ORDER_UPDATED позиции или итог заказа
+func totalsChanged(event *entity.OrderEvent) bool {
+	if len(event.ItemChanges) > 0 {
+		return true
+	}
+	return event.PreviousTotalPrice != nil && *event.PreviousTotalPrice != event.TotalPrice
+}
+
+// ErrOrderNotFound - заказ для обработки не найден
+var ErrOrderNotFound = errors.New("order not found")
+
+// ReprocessOrder повторно запускает конвертацию валюты для заказа
+// Используется администратором вместо повторной отправки события в Kafka
+func (s *OrderProcessingService) ReprocessOrder(ctx context.Context, orderID uuid.UUID) error {
+	event := &entity.OrderEvent{
+		EventType: entity.EventTypeOrderCreated,
+		OrderID:   orderID,
+		Timestamp: time.Now(),
+	}
+
+	if err := s.ProcessOrderCreated(ctx, event); err != nil {
+		if errors.Is(err, repository.ErrOrderNotFound) {
+			return ErrOrderNotFound
+		}
+		return err
+	}
+	return nil
+}
+
 // ProcessOrderEvent обрабатывает событие заказа из Kafka
 func (s *OrderProcessingService) ProcessOrderEvent(ctx context.Context, event *entity.OrderEvent) error {
 	switch event.EventType {
 	case entity.EventTypeOrderCreated:
 		return s.ProcessOrderCreated(ctx, event)
 	case entity.EventTypeOrderUpdated:
-		// Для ORDER_UPDATED пока не требуется обработка согласно ТЗ
-		log.Printf("Received ORDER_UPDATED event for order %s, skipping processing", event.OrderID)
-		return nil
+		return s.ProcessOrderUpdated(ctx, event)
 	default:
 		log.Printf("Unknown event type: %s for order %s", event.EventType, event.OrderID)
 		return nil
 	}
 }
 
+// ProcessOrderEventsBatch обрабатывает пачку событий заказов
+// Заказы и позиции читаются одним запросом, курсы валют - одним обращением к Redis,
+// результаты конвертации всех заказов сохраняются в одной транзакции
+// Возвращает ошибки по индексам событий: nil означает, что событие обработано
+func (s *OrderProcessingService) ProcessOrderEventsBatch(ctx context.Context, events []*entity.OrderEvent) []error {
+	errs := make([]error, len(events))
+
+	// Индексы событий ORDER_CREATED по заказам; прочие события обрабатываются по одному
+	pending := make(map[uuid.UUID][]int)
+	targets := make(map[uuid.UUID]string)
+	// Время самого нового события заказа в пачке: по нему проверяется и сохраняется converted_at
+	latest := make(map[uuid.UUID]time.Time)
+	var orderIDs []uuid.UUID
+	for i, event := range events {
+		if event.EventType != entity.EventTypeOrderCreated {
+			errs[i] = s.ProcessOrderEvent(ctx, event)
+			continue
+		}
+		if _, ok := pending[event.OrderID]; !ok {
+			orderIDs = append(orderIDs, event.OrderID)
+			targets[event.OrderID] = s.targetCurrency(event)
+		}
+		pending[event.OrderID] = append(pending[event.OrderID], i)
+		if eventTime := eventTimestamp(event); eventTime.After(latest[event.OrderID]) {
+			latest[event.OrderID] = eventTime
+		}
+	}
+	if len(orderIDs) == 0 {
+		return errs
+	}
+
+	fail := func(orderID uuid.UUID, err error) {
+		for _, i := range pending[orderID] {
+			errs[i] = err
+		}
+	}
+	failAll := func(err error) {
+		for _, orderID := range orderIDs {
+			fail(orderID, err)
+		}
+	}
+
+	orders, err := s.orderRepo.GetByIDs(ctx, orderIDs)
+	if err != nil {
+		failAll(fmt.Errorf("failed to get orders: %w", err))
+		return errs
+	}
+
+	itemsByOrder, err := s.orderRepo.GetItemsByOrderIDs(ctx, orderIDs)
+	if err != nil {
+		failAll(fmt.Errorf("failed to get order items: %w", err))
+		return errs
+	}
+
+	// Курсы всех исходных и целевых валют пачки запрашиваются один раз
+	var currencies []string
+	seen := make(map[string]bool)
+	addCurrency := func(currency string) {
+		if !seen[currency] {
+			seen[currency] = true
+			currencies = append(currencies, currency)
+		}
+	}
+	for _, orderID := range orderIDs {
+		addCurrency(targets[orderID])
+	}
+	for _, order := range orders {
+		addCurrency(sourceCurrency(&order))
+	}
+
+	rates, err := s.exchangeSvc.GetRates(ctx, currencies)
+	if err != nil {
+		failAll(fmt.Errorf("failed to get rates for conversion: %w", err))
+		return errs
+	}
+
+	found := make(map[uuid.UUID]bool, len(orders))
+	calculations := make([]*entity.DeliveryCalculation, 0, len(orders))
+	for i := range orders {
+		order := &orders[i]
+		found[order.ID] = true
+
+		if err := s.ValidateOrder(order); err != nil {
+			fail(order.ID, fmt.Errorf("order validation failed: %w", err))
+			continue
+		}
+		if isStale(order, latest[order.ID]) {
+			log.Printf("Order %s already converted by a newer event, skipping", order.ID)
+			continue
+		}
+		if order.DeliveryPrice == 0 {
+			log.Printf("Order %s has zero delivery price, skipping processing", order.ID)
+			continue
+		}
+
+		calculation, err := s.calculateWithRates(order, itemsByOrder[order.ID], rates, targets[order.ID])
+		if err != nil {
+			fail(order.ID, fmt.Errorf("failed to calculate delivery: %w", err))
+			continue
+		}
+		calculation.CalculatedAt = latest[order.ID]
+		calculations = append(calculations, calculation)
+	}
+
+	for _, orderID := range orderIDs {
+		if !found[orderID] {
+			fail(orderID, fmt.Errorf("failed to get order: %w", repository.ErrOrderNotFound))
+		}
+	}
+
+	if err := s.orderRepo.UpdateOrdersWithCurrency(ctx, calculations); err != nil {
+		for _, calculation := range calculations {
+			fail(calculation.OrderID, fmt.Errorf("failed to update order: %w", err))
+		}
+		return errs
+	}
+
+	log.Printf("Processed batch of %d events: %d orders converted", len(events), len(calculations))
+	return errs
+}
+
+// sourceCurrency возвращает исходную валюту заказа (по умолчанию USD согласно каталогу)
+func sourceCurrency(order *entity.Order) string {
+	if order.Currency == "" {
+		return "USD"
+	}
+	return order.Currency
+}
+
+// calculateWithRates рассчитывает конвертацию заказа по заранее полученным курсам
+// Результат совпадает с calculateDeliveryWithExchange: суммы умножаются на тот же курс с тем же округлением
+func (s *OrderProcessingService) calculateWithRates(
+	order *entity.Order,
+	items []entity.OrderItem,
+	rates map[string]*entity.ExchangeRate,
+	targetCurrency string,
+) (*entity.DeliveryCalculation, error) {
+	from := sourceCurrency(order)
+
+	exchangeRate := 1.0
+	if from != targetCurrency {
+		fromRate, ok := rates[from]
+		toRate, okTo := rates[targetCurrency]
+		if !ok || !okTo {
+			metrics.WorkerConversionErrors.WithLabelValues(from, targetCurrency).Inc()
+			return nil, fmt.Errorf("rate for %s to %s not found", from, targetCurrency)
+		}
+		exchangeRate = toRate.Rate / fromRate.Rate
+	}
+
+	target := money.LookupCurrency(targetCurrency)
+	convertedDelivery := target.MulRate(order.DeliveryPrice, exchangeRate)
+	var newTotal, convertedTax money.Amount
+	var convertedItems []entity.OrderItem
+
+	if len(items) > 0 {
+		var totals money.Totals
+		var err error
+		convertedItems, totals, err = s.convertItems(order, items, exchangeRate, targetCurrency)
+		if err != nil {
+			return nil, err
+		}
+
+		convertedDelivery = totals.Delivery
+		convertedTax = totals.Tax
+		newTotal = totals.Total
+	} else {
+		// Заказ без позиций (старые данные): цена товаров, налоги и доставка конвертируются по отдельности
+		priceWithoutDelivery := order.TotalPrice - order.DeliveryPrice - order.TaxTotal
+		convertedTax = target.MulRate(order.TaxTotal, exchangeRate)
+		newTotal = target.MulRate(priceWithoutDelivery, exchangeRate) + convertedTax + convertedDelivery
+	}
+
+	return &entity.DeliveryCalculation{
+		OrderID:           order.ID,
+		OriginalDelivery:  order.DeliveryPrice,
+		OriginalCurrency:  from,
+		ConvertedDelivery: convertedDelivery,
+		ConvertedTax:      convertedTax,
+		ConvertedCurrency: targetCurrency,
+		ExchangeRate:      exchangeRate,
+		NewTotalPrice:     newTotal,
+		ConvertedItems:    convertedItems,
+	}, nil
+}
+
 // ValidateOrder проверяет корректность данных заказа
 func (s *OrderProcessingService) ValidateOrder(order *entity.Order) error {
 	if order.ID == uuid.Nil {
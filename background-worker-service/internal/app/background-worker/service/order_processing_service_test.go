@@ -3,11 +3,15 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"augustberries/background-worker-service/internal/app/background-worker/entity"
+	"augustberries/background-worker-service/internal/app/background-worker/repository"
 	"augustberries/background-worker-service/internal/app/background-worker/repository/mocks"
+	"augustberries/pkg/money"
+	"augustberries/pkg/units"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -31,29 +35,30 @@ func TestProcessOrderCreated_Success(t *testing.T) {
 		EventType:  entity.EventTypeOrderCreated,
 		OrderID:    orderID,
 		UserID:     userID,
-		TotalPrice: 110.0, // 100 товары + 10 доставка
+		TotalPrice: money.MustParse("110.00"), // 100 товары + 10 доставка
 		Currency:   "USD",
 	}
 
 	order := &entity.Order{
 		ID:            orderID,
 		UserID:        userID,
-		TotalPrice:    110.0,
-		DeliveryPrice: 10.0,
+		TotalPrice:    money.MustParse("110.00"),
+		DeliveryPrice: money.MustParse("10.00"),
 		Currency:      "USD",
 		Status:        entity.OrderStatusPending,
 		CreatedAt:     time.Now(),
 	}
 
 	orderRepo.On("GetByID", ctx, orderID).Return(order, nil)
+	orderRepo.On("GetItems", ctx, orderID).Return([]entity.OrderItem{}, nil)
 
 	// Конвертация доставки: 10 USD -> RUB (курс 91.23)
-	exchangeSvc.On("ConvertCurrency", ctx, 10.0, "USD", "RUB").Return(912.3, 91.23, nil)
+	exchangeSvc.On("ConvertCurrency", ctx, money.MustParse("10.00"), "USD", "RUB").Return(money.MustParse("912.30"), 91.23, nil)
 	// Конвертация товаров: 100 USD -> RUB
-	exchangeSvc.On("ConvertCurrency", ctx, 100.0, "USD", "RUB").Return(9123.0, 91.23, nil)
+	exchangeSvc.On("ConvertCurrency", ctx, money.MustParse("100.00"), "USD", "RUB").Return(money.MustParse("9123.00"), 91.23, nil)
 
 	// Итого: 9123 + 912.3 = 10035.3 RUB
-	orderRepo.On("UpdateOrderWithCurrency", ctx, orderID, 912.3, 10035.3, "RUB").Return(nil)
+	orderRepo.On("UpdateOrderWithCurrency", ctx, orderID, money.MustParse("912.30"), money.Amount(0), money.MustParse("10035.30"), "RUB", []entity.OrderItem(nil), mock.AnythingOfType("time.Time")).Return(nil)
 
 	// Act
 	err := service.ProcessOrderCreated(ctx, event)
@@ -84,8 +89,8 @@ func TestProcessOrderCreated_ZeroDelivery_Skipped(t *testing.T) {
 	order := &entity.Order{
 		ID:            orderID,
 		UserID:        userID,
-		TotalPrice:    100.0,
-		DeliveryPrice: 0.0, // Нулевая доставка
+		TotalPrice:    money.MustParse("100.00"),
+		DeliveryPrice: 0, // Нулевая доставка
 		Currency:      "USD",
 	}
 
@@ -144,8 +149,8 @@ func TestProcessOrderCreated_ValidationFailed(t *testing.T) {
 	order := &entity.Order{
 		ID:            orderID,
 		UserID:        uuid.New(),
-		TotalPrice:    100.0,
-		DeliveryPrice: 10.0,
+		TotalPrice:    money.MustParse("100.00"),
+		DeliveryPrice: money.MustParse("10.00"),
 		Currency:      "", // Пустая валюта
 	}
 
@@ -178,13 +183,14 @@ func TestProcessOrderCreated_ConversionError(t *testing.T) {
 	order := &entity.Order{
 		ID:            orderID,
 		UserID:        userID,
-		TotalPrice:    110.0,
-		DeliveryPrice: 10.0,
+		TotalPrice:    money.MustParse("110.00"),
+		DeliveryPrice: money.MustParse("10.00"),
 		Currency:      "USD",
 	}
 
 	orderRepo.On("GetByID", ctx, orderID).Return(order, nil)
-	exchangeSvc.On("ConvertCurrency", ctx, 10.0, "USD", "RUB").Return(0.0, 0.0, errors.New("rate not found"))
+	orderRepo.On("GetItems", ctx, orderID).Return([]entity.OrderItem{}, nil)
+	exchangeSvc.On("ConvertCurrency", ctx, money.MustParse("10.00"), "USD", "RUB").Return(money.Amount(0), 0.0, errors.New("rate not found"))
 
 	// Act
 	err := service.ProcessOrderCreated(ctx, event)
@@ -213,15 +219,16 @@ func TestProcessOrderCreated_UpdateError(t *testing.T) {
 	order := &entity.Order{
 		ID:            orderID,
 		UserID:        userID,
-		TotalPrice:    110.0,
-		DeliveryPrice: 10.0,
+		TotalPrice:    money.MustParse("110.00"),
+		DeliveryPrice: money.MustParse("10.00"),
 		Currency:      "USD",
 	}
 
 	orderRepo.On("GetByID", ctx, orderID).Return(order, nil)
-	exchangeSvc.On("ConvertCurrency", ctx, 10.0, "USD", "RUB").Return(912.3, 91.23, nil)
-	exchangeSvc.On("ConvertCurrency", ctx, 100.0, "USD", "RUB").Return(9123.0, 91.23, nil)
-	orderRepo.On("UpdateOrderWithCurrency", ctx, orderID, mock.Anything, mock.Anything, "RUB").Return(errors.New("db error"))
+	orderRepo.On("GetItems", ctx, orderID).Return([]entity.OrderItem{}, nil)
+	exchangeSvc.On("ConvertCurrency", ctx, money.MustParse("10.00"), "USD", "RUB").Return(money.MustParse("912.30"), 91.23, nil)
+	exchangeSvc.On("ConvertCurrency", ctx, money.MustParse("100.00"), "USD", "RUB").Return(money.MustParse("9123.00"), 91.23, nil)
+	orderRepo.On("UpdateOrderWithCurrency", ctx, orderID, mock.Anything, mock.Anything, mock.Anything, "RUB", mock.Anything, mock.AnythingOfType("time.Time")).Return(errors.New("db error"))
 
 	// Act
 	err := service.ProcessOrderCreated(ctx, event)
@@ -252,8 +259,8 @@ func TestProcessOrderEvent_OrderCreated(t *testing.T) {
 	order := &entity.Order{
 		ID:            orderID,
 		UserID:        userID,
-		TotalPrice:    100.0,
-		DeliveryPrice: 0.0, // Нулевая доставка - пропускается
+		TotalPrice:    money.MustParse("100.00"),
+		DeliveryPrice: 0, // Нулевая доставка - пропускается
 		Currency:      "USD",
 	}
 
@@ -267,7 +274,7 @@ func TestProcessOrderEvent_OrderCreated(t *testing.T) {
 }
 
 func TestProcessOrderEvent_OrderUpdated_Skipped(t *testing.T) {
-	// ORDER_UPDATED пока не обрабатывается
+	// ORDER_UPDATED без изменения позиций и итога (смена статуса) не обрабатывается
 	// Arrange
 	orderRepo := new(mocks.MockOrderRepository)
 	exchangeSvc := new(mocks.MockExchangeRateService)
@@ -289,6 +296,187 @@ func TestProcessOrderEvent_OrderUpdated_Skipped(t *testing.T) {
 	orderRepo.AssertNotCalled(t, "GetByID") // Репозиторий не вызывается
 }
 
+func TestProcessOrderEvent_OrderUpdated_ItemsChangedReprocessed(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	exchangeSvc := new(mocks.MockExchangeRateService)
+
+	service := NewOrderProcessingService(orderRepo, exchangeSvc)
+
+	ctx := context.Background()
+	orderID := uuid.New()
+
+	event := &entity.OrderEvent{
+		EventType:   entity.EventTypeOrderUpdated,
+		OrderID:     orderID,
+		ItemChanges: []entity.OrderItemChange{{ItemID: uuid.New(), ProductID: uuid.New(), OldQuantity: units.Of(2), NewQuantity: units.Of(1)}},
+	}
+
+	order := &entity.Order{
+		ID:            orderID,
+		UserID:        uuid.New(),
+		TotalPrice:    money.MustParse("50.00"),
+		DeliveryPrice: 0, // Нулевая доставка - конвертация пропускается
+		Currency:      "USD",
+	}
+	orderRepo.On("GetByID", ctx, orderID).Return(order, nil)
+
+	// Act
+	err := service.ProcessOrderEvent(ctx, event)
+
+	// Assert
+	assert.NoError(t, err)
+	orderRepo.AssertExpectations(t)
+}
+
+func TestProcessOrderEvent_OrderUpdated_TotalChangedReprocessed(t *testing.T) {
+	// Итог изменился без изменения позиций (например, стоимость доставки): заказ конвертируется заново
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	exchangeSvc := new(mocks.MockExchangeRateService)
+
+	service := NewOrderProcessingService(orderRepo, exchangeSvc)
+
+	ctx := context.Background()
+	orderID := uuid.New()
+	previousTotal := money.MustParse("105.00")
+	timestamp := time.Now()
+
+	event := &entity.OrderEvent{
+		EventType:          entity.EventTypeOrderUpdated,
+		OrderID:            orderID,
+		TotalPrice:         money.MustParse("110.00"),
+		PreviousTotalPrice: &previousTotal,
+		Timestamp:          timestamp,
+	}
+
+	convertedAt := timestamp.Add(-time.Hour)
+	order := &entity.Order{
+		ID:            orderID,
+		UserID:        uuid.New(),
+		TotalPrice:    money.MustParse("110.00"),
+		DeliveryPrice: money.MustParse("10.00"),
+		Currency:      "USD",
+		ConvertedAt:   &convertedAt,
+	}
+
+	orderRepo.On("GetByID", ctx, orderID).Return(order, nil)
+	orderRepo.On("GetItems", ctx, orderID).Return([]entity.OrderItem{}, nil)
+	exchangeSvc.On("ConvertCurrency", ctx, money.MustParse("10.00"), "USD", "RUB").Return(money.MustParse("912.30"), 91.23, nil)
+	exchangeSvc.On("ConvertCurrency", ctx, money.MustParse("100.00"), "USD", "RUB").Return(money.MustParse("9123.00"), 91.23, nil)
+	// converted_at сохраняется по времени события
+	orderRepo.On("UpdateOrderWithCurrency", ctx, orderID, money.MustParse("912.30"), money.Amount(0), money.MustParse("10035.30"), "RUB", []entity.OrderItem(nil), timestamp).Return(nil)
+
+	// Act
+	err := service.ProcessOrderEvent(ctx, event)
+
+	// Assert
+	assert.NoError(t, err)
+	orderRepo.AssertExpectations(t)
+}
+
+func TestProcessOrderEvent_OrderUpdated_SameTotalSkipped(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	exchangeSvc := new(mocks.MockExchangeRateService)
+
+	service := NewOrderProcessingService(orderRepo, exchangeSvc)
+
+	total := money.MustParse("110.00")
+	event := &entity.OrderEvent{
+		EventType:          entity.EventTypeOrderUpdated,
+		OrderID:            uuid.New(),
+		TotalPrice:         total,
+		PreviousTotalPrice: &total,
+		Status:             entity.OrderStatusShipped,
+	}
+
+	// Act
+	err := service.ProcessOrderEvent(context.Background(), event)
+
+	// Assert
+	assert.NoError(t, err)
+	orderRepo.AssertNotCalled(t, "GetByID")
+}
+
+func TestProcessOrderEvent_OrderUpdated_StaleEventSkipped(t *testing.T) {
+	// Событие старше последней конвертации (пришло не по порядку) не перезаписывает заказ
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	exchangeSvc := new(mocks.MockExchangeRateService)
+
+	service := NewOrderProcessingService(orderRepo, exchangeSvc)
+
+	ctx := context.Background()
+	orderID := uuid.New()
+	convertedAt := time.Now()
+
+	event := &entity.OrderEvent{
+		EventType:   entity.EventTypeOrderUpdated,
+		OrderID:     orderID,
+		ItemChanges: []entity.OrderItemChange{{ItemID: uuid.New(), ProductID: uuid.New(), OldQuantity: units.Of(1), NewQuantity: units.Of(3)}},
+		Timestamp:   convertedAt.Add(-time.Minute),
+	}
+
+	order := &entity.Order{
+		ID:            orderID,
+		UserID:        uuid.New(),
+		TotalPrice:    money.MustParse("9123.00"),
+		DeliveryPrice: money.MustParse("912.30"),
+		Currency:      "RUB",
+		ConvertedAt:   &convertedAt,
+	}
+	orderRepo.On("GetByID", ctx, orderID).Return(order, nil)
+
+	// Act
+	err := service.ProcessOrderEvent(ctx, event)
+
+	// Assert
+	assert.NoError(t, err)
+	orderRepo.AssertNotCalled(t, "GetItems", mock.Anything, mock.Anything)
+	exchangeSvc.AssertNotCalled(t, "ConvertCurrency", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProcessOrderEvent_OrderUpdated_ConcurrentNewerConversion(t *testing.T) {
+	// Заказ сконвертировали по более новому событию между чтением и записью: событие считается обработанным
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	exchangeSvc := new(mocks.MockExchangeRateService)
+
+	service := NewOrderProcessingService(orderRepo, exchangeSvc)
+
+	ctx := context.Background()
+	orderID := uuid.New()
+
+	event := &entity.OrderEvent{
+		EventType:   entity.EventTypeOrderUpdated,
+		OrderID:     orderID,
+		ItemChanges: []entity.OrderItemChange{{ItemID: uuid.New(), ProductID: uuid.New(), OldQuantity: units.Of(1), NewQuantity: units.Of(2)}},
+		Timestamp:   time.Now(),
+	}
+
+	order := &entity.Order{
+		ID:            orderID,
+		UserID:        uuid.New(),
+		TotalPrice:    money.MustParse("110.00"),
+		DeliveryPrice: money.MustParse("10.00"),
+		Currency:      "USD",
+	}
+
+	orderRepo.On("GetByID", ctx, orderID).Return(order, nil)
+	orderRepo.On("GetItems", ctx, orderID).Return([]entity.OrderItem{}, nil)
+	exchangeSvc.On("ConvertCurrency", ctx, mock.Anything, "USD", "RUB").Return(money.MustParse("1.00"), 91.23, nil)
+	orderRepo.On("UpdateOrderWithCurrency", ctx, orderID, mock.Anything, mock.Anything, mock.Anything, "RUB", mock.Anything, event.Timestamp).
+		Return(fmt.Errorf("%w: order %s", repository.ErrStaleConversion, orderID))
+
+	// Act
+	err := service.ProcessOrderEvent(ctx, event)
+
+	// Assert
+	assert.NoError(t, err)
+	orderRepo.AssertExpectations(t)
+}
+
 func TestProcessOrderEvent_UnknownType_Skipped(t *testing.T) {
 	// Arrange
 	orderRepo := new(mocks.MockOrderRepository)
@@ -323,8 +511,8 @@ func TestValidateOrder_Success(t *testing.T) {
 	order := &entity.Order{
 		ID:            uuid.New(),
 		UserID:        uuid.New(),
-		TotalPrice:    100.0,
-		DeliveryPrice: 10.0,
+		TotalPrice:    money.MustParse("100.00"),
+		DeliveryPrice: money.MustParse("10.00"),
 		Currency:      "USD",
 	}
 
@@ -409,7 +597,7 @@ func TestValidateOrder_NegativeDeliveryPrice(t *testing.T) {
 		ID:            uuid.New(),
 		UserID:        uuid.New(),
 		Currency:      "USD",
-		DeliveryPrice: -10.0, // Отрицательная доставка
+		DeliveryPrice: money.MustParse("-10.00"), // Отрицательная доставка
 	}
 
 	// Act
@@ -431,7 +619,7 @@ func TestValidateOrder_NegativeTotalPrice(t *testing.T) {
 		ID:         uuid.New(),
 		UserID:     uuid.New(),
 		Currency:   "USD",
-		TotalPrice: -100.0, // Отрицательная сумма
+		TotalPrice: money.MustParse("-100.00"), // Отрицательная сумма
 	}
 
 	// Act
@@ -464,24 +652,156 @@ func TestProcessOrderCreated_EURtoRUB(t *testing.T) {
 	order := &entity.Order{
 		ID:            orderID,
 		UserID:        userID,
-		TotalPrice:    110.0,
-		DeliveryPrice: 10.0,
+		TotalPrice:    money.MustParse("110.00"),
+		DeliveryPrice: money.MustParse("10.00"),
 		Currency:      "EUR", // Заказ в EUR
 	}
 
 	orderRepo.On("GetByID", ctx, orderID).Return(order, nil)
+	orderRepo.On("GetItems", ctx, orderID).Return([]entity.OrderItem{}, nil)
 
 	// Конвертация из EUR в RUB (EUR=0.93, RUB=91.23, rate=98.096)
-	exchangeSvc.On("ConvertCurrency", ctx, 10.0, "EUR", "RUB").Return(980.96, 98.096, nil)
-	exchangeSvc.On("ConvertCurrency", ctx, 100.0, "EUR", "RUB").Return(9809.6, 98.096, nil)
+	exchangeSvc.On("ConvertCurrency", ctx, money.MustParse("10.00"), "EUR", "RUB").Return(money.MustParse("980.96"), 98.096, nil)
+	exchangeSvc.On("ConvertCurrency", ctx, money.MustParse("100.00"), "EUR", "RUB").Return(money.MustParse("9809.60"), 98.096, nil)
+
+	orderRepo.On("UpdateOrderWithCurrency", ctx, orderID, money.MustParse("980.96"), money.Amount(0), money.MustParse("10790.56"), "RUB", []entity.OrderItem(nil), mock.AnythingOfType("time.Time")).Return(nil)
+
+	// Act
+	err := service.ProcessOrderCreated(ctx, event)
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestProcessOrderCreated_WithItems_TotalEqualsConvertedParts(t *testing.T) {
+	// Итог в RUB собирается из сконвертированных позиций и доставки
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	exchangeSvc := new(mocks.MockExchangeRateService)
+
+	service := NewOrderProcessingService(orderRepo, exchangeSvc)
+
+	ctx := context.Background()
+	orderID := uuid.New()
 
-	orderRepo.On("UpdateOrderWithCurrency", ctx, orderID, 980.96, 10790.56, "RUB").Return(nil)
+	event := &entity.OrderEvent{
+		EventType: entity.EventTypeOrderCreated,
+		OrderID:   orderID,
+	}
+
+	order := &entity.Order{
+		ID:            orderID,
+		UserID:        uuid.New(),
+		TotalPrice:    money.MustParse("209.98"), // 99.99 * 2 + 10.00
+		DeliveryPrice: money.MustParse("10.00"),
+		Currency:      "EUR",
+	}
+	items := []entity.OrderItem{
+		{ID: uuid.New(), OrderID: orderID, ProductID: uuid.New(), Quantity: units.Of(2), UnitPrice: money.MustParse("99.99")},
+	}
+
+	rate := 91.23 / 0.93
+	orderRepo.On("GetByID", ctx, orderID).Return(order, nil)
+	orderRepo.On("GetItems", ctx, orderID).Return(items, nil)
+	exchangeSvc.On("ConvertCurrency", ctx, money.MustParse("10.00"), "EUR", "RUB").Return(money.MustParse("980.97"), rate, nil)
+
+	// 99.99 EUR -> 9808.70 RUB за единицу, доставка 980.97 RUB
+	expectedItems := []entity.OrderItem{items[0]}
+	expectedItems[0].UnitPrice = money.MustParse("9808.70")
+	orderRepo.On("UpdateOrderWithCurrency", ctx, orderID, money.MustParse("980.97"), money.Amount(0), money.MustParse("20598.37"), "RUB", expectedItems, mock.AnythingOfType("time.Time")).Return(nil)
 
 	// Act
 	err := service.ProcessOrderCreated(ctx, event)
 
 	// Assert
 	assert.NoError(t, err)
+	orderRepo.AssertExpectations(t)
+}
+
+func TestProcessOrderCreated_WithTax_ConvertsTaxPerItem(t *testing.T) {
+	// Налог каждой позиции конвертируется по тому же курсу, итог собирается из сконвертированных частей
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	exchangeSvc := new(mocks.MockExchangeRateService)
+
+	service := NewOrderProcessingService(orderRepo, exchangeSvc)
+
+	ctx := context.Background()
+	orderID := uuid.New()
+
+	event := &entity.OrderEvent{
+		EventType: entity.EventTypeOrderCreated,
+		OrderID:   orderID,
+	}
+
+	order := &entity.Order{
+		ID:            orderID,
+		UserID:        uuid.New(),
+		TotalPrice:    money.MustParse("130.00"), // 50.00 * 2 + 20.00 налог + 10.00 доставка
+		DeliveryPrice: money.MustParse("10.00"),
+		TaxTotal:      money.MustParse("20.00"),
+		Currency:      "USD",
+	}
+	items := []entity.OrderItem{
+		{ID: uuid.New(), OrderID: orderID, ProductID: uuid.New(), Quantity: units.Of(2), UnitPrice: money.MustParse("50.00"), TaxAmount: money.MustParse("20.00")},
+	}
+
+	orderRepo.On("GetByID", ctx, orderID).Return(order, nil)
+	orderRepo.On("GetItems", ctx, orderID).Return(items, nil)
+	exchangeSvc.On("ConvertCurrency", ctx, money.MustParse("10.00"), "USD", "RUB").Return(money.MustParse("912.30"), 91.23, nil)
+
+	expectedItems := []entity.OrderItem{items[0]}
+	expectedItems[0].UnitPrice = money.MustParse("4561.50")
+	expectedItems[0].TaxAmount = money.MustParse("1824.60")
+	// 4561.50 * 2 + 1824.60 + 912.30
+	orderRepo.On("UpdateOrderWithCurrency", ctx, orderID, money.MustParse("912.30"), money.MustParse("1824.60"), money.MustParse("11859.90"), "RUB", expectedItems, mock.AnythingOfType("time.Time")).Return(nil)
+
+	// Act
+	err := service.ProcessOrderCreated(ctx, event)
+
+	// Assert
+	assert.NoError(t, err)
+	orderRepo.AssertExpectations(t)
+}
+
+func TestProcessOrderCreated_TotalDoesNotMatchItems(t *testing.T) {
+	// Заказ, у которого итог не сходится с позициями, не обрабатывается
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	exchangeSvc := new(mocks.MockExchangeRateService)
+
+	service := NewOrderProcessingService(orderRepo, exchangeSvc)
+
+	ctx := context.Background()
+	orderID := uuid.New()
+
+	event := &entity.OrderEvent{
+		EventType: entity.EventTypeOrderCreated,
+		OrderID:   orderID,
+	}
+
+	order := &entity.Order{
+		ID:            orderID,
+		UserID:        uuid.New(),
+		TotalPrice:    money.MustParse("500.00"),
+		DeliveryPrice: money.MustParse("10.00"),
+		Currency:      "USD",
+	}
+	items := []entity.OrderItem{
+		{ID: uuid.New(), OrderID: orderID, Quantity: units.Of(1), UnitPrice: money.MustParse("100.00")},
+	}
+
+	orderRepo.On("GetByID", ctx, orderID).Return(order, nil)
+	orderRepo.On("GetItems", ctx, orderID).Return(items, nil)
+	exchangeSvc.On("ConvertCurrency", ctx, money.MustParse("10.00"), "USD", "RUB").Return(money.MustParse("912.30"), 91.23, nil)
+
+	// Act
+	err := service.ProcessOrderCreated(ctx, event)
+
+	// Assert
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, money.ErrTotalMismatch)
+	orderRepo.AssertNotCalled(t, "UpdateOrderWithCurrency")
 }
 
 func TestProcessOrderCreated_DefaultCurrencyUSD(t *testing.T) {
@@ -506,8 +826,8 @@ func TestProcessOrderCreated_DefaultCurrencyUSD(t *testing.T) {
 	order := &entity.Order{
 		ID:            orderID,
 		UserID:        userID,
-		TotalPrice:    110.0,
-		DeliveryPrice: 10.0,
+		TotalPrice:    money.MustParse("110.00"),
+		DeliveryPrice: money.MustParse("10.00"),
 		Currency:      "", // Пустая валюта
 	}
 
@@ -520,3 +840,316 @@ func TestProcessOrderCreated_DefaultCurrencyUSD(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "currency not specified")
 }
+
+// ===================== ReprocessOrder Tests =====================
+
+func TestProcessOrderCreated_PreferredCurrency(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	exchangeSvc := new(mocks.MockExchangeRateService)
+
+	service := NewOrderProcessingService(orderRepo, exchangeSvc)
+
+	ctx := context.Background()
+	order := &entity.Order{
+		ID:            uuid.New(),
+		UserID:        uuid.New(),
+		TotalPrice:    money.MustParse("110.00"),
+		DeliveryPrice: money.MustParse("10.00"),
+		Currency:      "USD",
+	}
+	event := &entity.OrderEvent{EventType: entity.EventTypeOrderCreated, OrderID: order.ID, PreferredCurrency: "EUR"}
+
+	orderRepo.On("GetByID", ctx, order.ID).Return(order, nil)
+	orderRepo.On("GetItems", ctx, order.ID).Return([]entity.OrderItem{}, nil)
+	exchangeSvc.On("ConvertCurrency", ctx, money.MustParse("10.00"), "USD", "EUR").Return(money.MustParse("9.00"), 0.9, nil)
+	exchangeSvc.On("ConvertCurrency", ctx, money.MustParse("100.00"), "USD", "EUR").Return(money.MustParse("90.00"), 0.9, nil)
+	orderRepo.On("UpdateOrderWithCurrency", ctx, order.ID, money.MustParse("9.00"), money.Amount(0), money.MustParse("99.00"), "EUR", []entity.OrderItem(nil), mock.AnythingOfType("time.Time")).Return(nil)
+
+	// Act
+	err := service.ProcessOrderCreated(ctx, event)
+
+	// Assert
+	assert.NoError(t, err)
+	orderRepo.AssertExpectations(t)
+	exchangeSvc.AssertExpectations(t)
+}
+
+func TestProcessOrderCreated_ConfiguredDefaultCurrency(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	exchangeSvc := new(mocks.MockExchangeRateService)
+
+	service := NewOrderProcessingService(orderRepo, exchangeSvc)
+	service.SetDefaultTargetCurrency("EUR")
+	service.SetDefaultTargetCurrency("XYZ") // Неподдерживаемая валюта игнорируется
+
+	ctx := context.Background()
+	order := &entity.Order{
+		ID:            uuid.New(),
+		UserID:        uuid.New(),
+		TotalPrice:    money.MustParse("10.00"),
+		DeliveryPrice: money.MustParse("10.00"),
+		Currency:      "EUR",
+	}
+	// Неподдерживаемая предпочитаемая валюта заменяется валютой по умолчанию
+	event := &entity.OrderEvent{EventType: entity.EventTypeOrderCreated, OrderID: order.ID, PreferredCurrency: "XYZ"}
+
+	orderRepo.On("GetByID", ctx, order.ID).Return(order, nil)
+	orderRepo.On("GetItems", ctx, order.ID).Return([]entity.OrderItem{}, nil)
+	exchangeSvc.On("ConvertCurrency", ctx, money.MustParse("10.00"), "EUR", "EUR").Return(money.MustParse("10.00"), 1.0, nil)
+	exchangeSvc.On("ConvertCurrency", ctx, money.Amount(0), "EUR", "EUR").Return(money.Amount(0), 1.0, nil)
+	orderRepo.On("UpdateOrderWithCurrency", ctx, order.ID, money.MustParse("10.00"), money.Amount(0), money.MustParse("10.00"), "EUR", []entity.OrderItem(nil), mock.AnythingOfType("time.Time")).Return(nil)
+
+	// Act
+	err := service.ProcessOrderCreated(ctx, event)
+
+	// Assert
+	assert.NoError(t, err)
+	orderRepo.AssertExpectations(t)
+}
+
+func TestReprocessOrder_NotFound(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	exchangeSvc := new(mocks.MockExchangeRateService)
+
+	service := NewOrderProcessingService(orderRepo, exchangeSvc)
+
+	ctx := context.Background()
+	orderID := uuid.New()
+
+	orderRepo.On("GetByID", ctx, orderID).Return(nil, fmt.Errorf("%w: record not found", repository.ErrOrderNotFound))
+
+	// Act
+	err := service.ReprocessOrder(ctx, orderID)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrOrderNotFound)
+	exchangeSvc.AssertNotCalled(t, "ConvertCurrency", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// ===================== ProcessOrderEventsBatch Tests =====================
+
+func TestProcessOrderEventsBatch_ConvertsOrdersWithSingleRatesLookup(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	exchangeSvc := new(mocks.MockExchangeRateService)
+
+	service := NewOrderProcessingService(orderRepo, exchangeSvc)
+
+	ctx := context.Background()
+	usdOrder := entity.Order{
+		ID:            uuid.New(),
+		UserID:        uuid.New(),
+		TotalPrice:    money.MustParse("110.00"),
+		DeliveryPrice: money.MustParse("10.00"),
+		Currency:      "USD",
+	}
+	eurOrder := entity.Order{
+		ID:            uuid.New(),
+		UserID:        uuid.New(),
+		TotalPrice:    money.MustParse("25.00"),
+		DeliveryPrice: money.MustParse("5.00"),
+		Currency:      "EUR",
+	}
+	orderIDs := []uuid.UUID{usdOrder.ID, eurOrder.ID}
+	item := entity.OrderItem{ID: uuid.New(), OrderID: eurOrder.ID, Quantity: units.Of(2), UnitPrice: money.MustParse("10.00")}
+
+	events := []*entity.OrderEvent{
+		{EventType: entity.EventTypeOrderCreated, OrderID: usdOrder.ID},
+		{EventType: entity.EventTypeOrderCreated, OrderID: eurOrder.ID},
+	}
+
+	orderRepo.On("GetByIDs", ctx, orderIDs).Return([]entity.Order{usdOrder, eurOrder}, nil)
+	orderRepo.On("GetItemsByOrderIDs", ctx, orderIDs).
+		Return(map[uuid.UUID][]entity.OrderItem{eurOrder.ID: {item}}, nil)
+	exchangeSvc.On("GetRates", ctx, []string{"RUB", "USD", "EUR"}).Return(map[string]*entity.ExchangeRate{
+		"RUB": {Currency: "RUB", Rate: 90},
+		"USD": {Currency: "USD", Rate: 1},
+		"EUR": {Currency: "EUR", Rate: 0.9},
+	}, nil)
+
+	var saved []*entity.DeliveryCalculation
+	orderRepo.On("UpdateOrdersWithCurrency", ctx, mock.Anything).
+		Run(func(args mock.Arguments) { saved = args.Get(1).([]*entity.DeliveryCalculation) }).
+		Return(nil)
+
+	// Act
+	errs := service.ProcessOrderEventsBatch(ctx, events)
+
+	// Assert
+	assert.Equal(t, []error{nil, nil}, errs)
+	if assert.Len(t, saved, 2) {
+		// USD заказ без позиций: 100 * 90 + 10 * 90
+		assert.Equal(t, money.MustParse("9900.00"), saved[0].NewTotalPrice)
+		// EUR заказ: курс 90 / 0.9 = 100, позиции конвертируются поштучно
+		assert.Equal(t, money.MustParse("500.00"), saved[1].ConvertedDelivery)
+		assert.Equal(t, money.MustParse("1000.00"), saved[1].ConvertedItems[0].UnitPrice)
+		assert.Equal(t, money.MustParse("2500.00"), saved[1].NewTotalPrice)
+	}
+	exchangeSvc.AssertNotCalled(t, "ConvertCurrency", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProcessOrderEventsBatch_PreferredCurrencyPerOrder(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	exchangeSvc := new(mocks.MockExchangeRateService)
+
+	service := NewOrderProcessingService(orderRepo, exchangeSvc)
+
+	ctx := context.Background()
+	rubOrder := entity.Order{ID: uuid.New(), UserID: uuid.New(), TotalPrice: money.MustParse("10.00"), DeliveryPrice: money.MustParse("10.00"), Currency: "USD"}
+	eurOrder := entity.Order{ID: uuid.New(), UserID: uuid.New(), TotalPrice: money.MustParse("10.00"), DeliveryPrice: money.MustParse("10.00"), Currency: "USD"}
+	orderIDs := []uuid.UUID{rubOrder.ID, eurOrder.ID}
+
+	events := []*entity.OrderEvent{
+		{EventType: entity.EventTypeOrderCreated, OrderID: rubOrder.ID},
+		{EventType: entity.EventTypeOrderCreated, OrderID: eurOrder.ID, PreferredCurrency: "EUR"},
+	}
+
+	orderRepo.On("GetByIDs", ctx, orderIDs).Return([]entity.Order{rubOrder, eurOrder}, nil)
+	orderRepo.On("GetItemsByOrderIDs", ctx, orderIDs).Return(map[uuid.UUID][]entity.OrderItem{}, nil)
+	exchangeSvc.On("GetRates", ctx, []string{"RUB", "EUR", "USD"}).Return(map[string]*entity.ExchangeRate{
+		"RUB": {Currency: "RUB", Rate: 90},
+		"USD": {Currency: "USD", Rate: 1},
+		"EUR": {Currency: "EUR", Rate: 0.9},
+	}, nil)
+
+	var saved []*entity.DeliveryCalculation
+	orderRepo.On("UpdateOrdersWithCurrency", ctx, mock.Anything).
+		Run(func(args mock.Arguments) { saved = args.Get(1).([]*entity.DeliveryCalculation) }).
+		Return(nil)
+
+	// Act
+	errs := service.ProcessOrderEventsBatch(ctx, events)
+
+	// Assert
+	assert.Equal(t, []error{nil, nil}, errs)
+	if assert.Len(t, saved, 2) {
+		assert.Equal(t, "RUB", saved[0].ConvertedCurrency)
+		assert.Equal(t, money.MustParse("900.00"), saved[0].NewTotalPrice)
+		assert.Equal(t, "EUR", saved[1].ConvertedCurrency)
+		assert.Equal(t, money.MustParse("9.00"), saved[1].NewTotalPrice)
+	}
+}
+
+func TestProcessOrderEventsBatch_ReportsPerEventErrors(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	exchangeSvc := new(mocks.MockExchangeRateService)
+
+	service := NewOrderProcessingService(orderRepo, exchangeSvc)
+
+	ctx := context.Background()
+	okOrder := entity.Order{
+		ID:            uuid.New(),
+		UserID:        uuid.New(),
+		TotalPrice:    money.MustParse("110.00"),
+		DeliveryPrice: money.MustParse("10.00"),
+		Currency:      "USD",
+	}
+	gbpOrder := okOrder
+	gbpOrder.ID = uuid.New()
+	gbpOrder.Currency = "GBP"
+	missingID := uuid.New()
+	orderIDs := []uuid.UUID{okOrder.ID, gbpOrder.ID, missingID}
+
+	events := []*entity.OrderEvent{
+		{EventType: entity.EventTypeOrderCreated, OrderID: okOrder.ID},
+		{EventType: entity.EventTypeOrderCreated, OrderID: gbpOrder.ID},
+		{EventType: entity.EventTypeOrderCreated, OrderID: missingID},
+	}
+
+	orderRepo.On("GetByIDs", ctx, orderIDs).Return([]entity.Order{okOrder, gbpOrder}, nil)
+	orderRepo.On("GetItemsByOrderIDs", ctx, orderIDs).Return(map[uuid.UUID][]entity.OrderItem{}, nil)
+	exchangeSvc.On("GetRates", ctx, []string{"RUB", "USD", "GBP"}).Return(map[string]*entity.ExchangeRate{
+		"RUB": {Currency: "RUB", Rate: 90},
+		"USD": {Currency: "USD", Rate: 1},
+	}, nil)
+	orderRepo.On("UpdateOrdersWithCurrency", ctx, mock.MatchedBy(func(calculations []*entity.DeliveryCalculation) bool {
+		return len(calculations) == 1 && calculations[0].OrderID == okOrder.ID
+	})).Return(nil)
+
+	// Act
+	errs := service.ProcessOrderEventsBatch(ctx, events)
+
+	// Assert
+	assert.NoError(t, errs[0])
+	assert.ErrorContains(t, errs[1], "rate for GBP to RUB not found")
+	assert.ErrorIs(t, errs[2], repository.ErrOrderNotFound)
+	orderRepo.AssertExpectations(t)
+}
+
+func TestProcessOrderEventsBatch_SkipsStaleEventsAndStoresLatestTimestamp(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	exchangeSvc := new(mocks.MockExchangeRateService)
+
+	service := NewOrderProcessingService(orderRepo, exchangeSvc)
+
+	ctx := context.Background()
+	now := time.Now()
+	convertedAt := now.Add(-time.Hour)
+	fresh := entity.Order{ID: uuid.New(), UserID: uuid.New(), TotalPrice: money.MustParse("10.00"), DeliveryPrice: money.MustParse("10.00"), Currency: "USD", ConvertedAt: &convertedAt}
+	stale := entity.Order{ID: uuid.New(), UserID: uuid.New(), TotalPrice: money.MustParse("10.00"), DeliveryPrice: money.MustParse("10.00"), Currency: "USD", ConvertedAt: &now}
+	orderIDs := []uuid.UUID{fresh.ID, stale.ID}
+
+	events := []*entity.OrderEvent{
+		{EventType: entity.EventTypeOrderCreated, OrderID: fresh.ID, Timestamp: now.Add(-2 * time.Hour)},
+		{EventType: entity.EventTypeOrderCreated, OrderID: fresh.ID, Timestamp: now},
+		{EventType: entity.EventTypeOrderCreated, OrderID: stale.ID, Timestamp: now.Add(-time.Minute)},
+	}
+
+	orderRepo.On("GetByIDs", ctx, orderIDs).Return([]entity.Order{fresh, stale}, nil)
+	orderRepo.On("GetItemsByOrderIDs", ctx, orderIDs).Return(map[uuid.UUID][]entity.OrderItem{}, nil)
+	exchangeSvc.On("GetRates", ctx, []string{"RUB", "USD"}).Return(map[string]*entity.ExchangeRate{
+		"RUB": {Currency: "RUB", Rate: 90},
+		"USD": {Currency: "USD", Rate: 1},
+	}, nil)
+
+	var saved []*entity.DeliveryCalculation
+	orderRepo.On("UpdateOrdersWithCurrency", ctx, mock.Anything).
+		Run(func(args mock.Arguments) { saved = args.Get(1).([]*entity.DeliveryCalculation) }).
+		Return(nil)
+
+	// Act
+	errs := service.ProcessOrderEventsBatch(ctx, events)
+
+	// Assert
+	assert.Equal(t, []error{nil, nil, nil}, errs)
+	if assert.Len(t, saved, 1) {
+		assert.Equal(t, fresh.ID, saved[0].OrderID)
+		// Сохраняется время самого нового события заказа в пачке
+		assert.Equal(t, now, saved[0].CalculatedAt)
+	}
+}
+
+func TestProcessOrderEventsBatch_UpdateErrorFailsAllOrders(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	exchangeSvc := new(mocks.MockExchangeRateService)
+
+	service := NewOrderProcessingService(orderRepo, exchangeSvc)
+
+	ctx := context.Background()
+	order := entity.Order{
+		ID:            uuid.New(),
+		UserID:        uuid.New(),
+		TotalPrice:    money.MustParse("110.00"),
+		DeliveryPrice: money.MustParse("10.00"),
+		Currency:      "RUB",
+	}
+	events := []*entity.OrderEvent{{EventType: entity.EventTypeOrderCreated, OrderID: order.ID}}
+
+	orderRepo.On("GetByIDs", ctx, []uuid.UUID{order.ID}).Return([]entity.Order{order}, nil)
+	orderRepo.On("GetItemsByOrderIDs", ctx, []uuid.UUID{order.ID}).Return(map[uuid.UUID][]entity.OrderItem{}, nil)
+	exchangeSvc.On("GetRates", ctx, []string{"RUB"}).Return(map[string]*entity.ExchangeRate{}, nil)
+	orderRepo.On("UpdateOrdersWithCurrency", ctx, mock.Anything).Return(errors.New("deadlock detected"))
+
+	// Act
+	errs := service.ProcessOrderEventsBatch(ctx, events)
+
+	// Assert
+	assert.ErrorContains(t, errs[0], "failed to update order")
+}
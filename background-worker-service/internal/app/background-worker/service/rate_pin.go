@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"augustberries/background-worker-service/internal/app/background-worker/entity"
+)
+
+// MaxRatePinDuration - максимальный срок закрепления курса
+// Закрепление - временная мера на время сбоя провайдера, а не замена внешнего API
+const MaxRatePinDuration = 7 * 24 * time.Hour
+
+// defaultPinAuditLimit - сколько записей аудита возвращается по умолчанию
+const defaultPinAuditLimit = 100
+
+var (
+	// ErrRatePinsDisabled - хранилище закреплений не настроено
+	ErrRatePinsDisabled = errors.New("rate pinning is disabled")
+	// ErrInvalidRatePin - некорректные параметры закрепления
+	ErrInvalidRatePin = errors.New("invalid rate pin")
+	// ErrRatePinNotFound - закрепления пары нет
+	ErrRatePinNotFound = errors.New("rate pin not found")
+)
+
+var currencyCodeRe = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// PinRate закрепляет курс пары до req.ExpiresAt и пишет запись аудита
+func (s *ExchangeRateService) PinRate(ctx context.Context, req *entity.PinRateRequest, actor string) (*entity.RatePin, error) {
+	if s.pinRepo == nil {
+		return nil, ErrRatePinsDisabled
+	}
+
+	now := time.Now()
+	pin := &entity.RatePin{
+		From:      strings.ToUpper(strings.TrimSpace(req.From)),
+		To:        strings.ToUpper(strings.TrimSpace(req.To)),
+		Rate:      req.Rate,
+		Reason:    strings.TrimSpace(req.Reason),
+		PinnedBy:  actor,
+		PinnedAt:  now,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := validateRatePin(pin, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.pinRepo.Set(ctx, pin); err != nil {
+		return nil, fmt.Errorf("failed to pin rate: %w", err)
+	}
+
+	expiresAt := pin.ExpiresAt
+	s.auditPin(ctx, &entity.RatePinAuditRecord{
+		Action:    entity.RatePinActionPin,
+		From:      pin.From,
+		To:        pin.To,
+		Rate:      pin.Rate,
+		ExpiresAt: &expiresAt,
+		Reason:    pin.Reason,
+		Actor:     actor,
+		At:        now,
+	})
+
+	log.Printf("Exchange rate %s/%s pinned to %v until %s by %s: %s", pin.From, pin.To, pin.Rate, pin.ExpiresAt.Format(time.RFC3339), actor, pin.Reason)
+	return pin, nil
+}
+
+// UnpinRate снимает закрепление пары досрочно и пишет запись аудита
+func (s *ExchangeRateService) UnpinRate(ctx context.Context, from, to, actor, reason string) error {
+	if s.pinRepo == nil {
+		return ErrRatePinsDisabled
+	}
+
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	deleted, err := s.pinRepo.Delete(ctx, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to unpin rate: %w", err)
+	}
+	if !deleted {
+		return ErrRatePinNotFound
+	}
+
+	s.auditPin(ctx, &entity.RatePinAuditRecord{
+		Action: entity.RatePinActionUnpin,
+		From:   from,
+		To:     to,
+		Reason: reason,
+		Actor:  actor,
+		At:     time.Now(),
+	})
+
+	log.Printf("Exchange rate %s/%s unpinned by %s", from, to, actor)
+	return nil
+}
+
+// ListPins возвращает действующие закрепления
+func (s *ExchangeRateService) ListPins(ctx context.Context) ([]entity.RatePin, error) {
+	if s.pinRepo == nil {
+		return nil, ErrRatePinsDisabled
+	}
+
+	pins, err := s.pinRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rate pins: %w", err)
+	}
+
+	now := time.Now()
+	active := make([]entity.RatePin, 0, len(pins))
+	for _, pin := range pins {
+		if pin.Active(now) {
+			active = append(active, pin)
+		}
+	}
+	return active, nil
+}
+
+// ListPinAudit возвращает последние записи аудита закреплений
+func (s *ExchangeRateService) ListPinAudit(ctx context.Context, limit int) ([]entity.RatePinAuditRecord, error) {
+	if s.pinRepo == nil {
+		return nil, ErrRatePinsDisabled
+	}
+	if limit <= 0 {
+		limit = defaultPinAuditLimit
+	}
+
+	records, err := s.pinRepo.ListAudit(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rate pin audit: %w", err)
+	}
+	return records, nil
+}
+
+// pinnedRate возвращает действующий закрепленный курс from → to
+// Закрепление обратной пары тоже применяется: курс to/from переворачивается
+// Ошибка Redis не мешает конвертации - используются курсы из внешнего API
+func (s *ExchangeRateService) pinnedRate(ctx context.Context, from, to string) (float64, bool) {
+	if s.pinRepo == nil {
+		return 0, false
+	}
+
+	pin, err := s.pinRepo.Find(ctx, from, to)
+	if err != nil {
+		log.Printf("WARNING: failed to check pinned rate %s/%s: %v", from, to, err)
+		return 0, false
+	}
+	if pin == nil || !pin.Active(time.Now()) {
+		return 0, false
+	}
+
+	if pin.From == from {
+		return pin.Rate, true
+	}
+	return 1 / pin.Rate, true
+}
+
+// auditPin сохраняет запись аудита; ошибка не отменяет уже примененное действие
+func (s *ExchangeRateService) auditPin(ctx context.Context, record *entity.RatePinAuditRecord) {
+	if err := s.pinRepo.AppendAudit(ctx, record); err != nil {
+		log.Printf("ERROR: failed to write rate pin audit record: %v", err)
+	}
+}
+
+func validateRatePin(pin *entity.RatePin, now time.Time) error {
+	switch {
+	case !currencyCodeRe.MatchString(pin.From) || !currencyCodeRe.MatchString(pin.To):
+		return fmt.Errorf("%w: currencies must be 3-letter ISO codes", ErrInvalidRatePin)
+	case pin.From == pin.To:
+		return fmt.Errorf("%w: currencies must differ", ErrInvalidRatePin)
+	case pin.Rate <= 0:
+		return fmt.Errorf("%w: rate must be positive", ErrInvalidRatePin)
+	case pin.Reason == "":
+		return fmt.Errorf("%w: reason is required", ErrInvalidRatePin)
+	case !pin.ExpiresAt.After(now):
+		return fmt.Errorf("%w: expires_at must be in the future", ErrInvalidRatePin)
+	case pin.ExpiresAt.After(now.Add(MaxRatePinDuration)):
+		return fmt.Errorf("%w: expires_at must be within %s", ErrInvalidRatePin, MaxRatePinDuration)
+	}
+	return nil
+}
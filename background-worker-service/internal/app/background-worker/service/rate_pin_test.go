@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"augustberries/background-worker-service/internal/app/background-worker/entity"
+	"augustberries/background-worker-service/internal/app/background-worker/repository/mocks"
+	"augustberries/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// ===================== PinRate Tests =====================
+
+func TestPinRate_SavesPinAndAudit(t *testing.T) {
+	// Arrange
+	pinRepo := new(mocks.MockRatePinRepository)
+	svc := NewExchangeRateService(new(mocks.MockExchangeRateRepository), new(mocks.MockExchangeRateAPIClient), pinRepo)
+	ctx := context.Background()
+	expiresAt := time.Now().Add(6 * time.Hour)
+
+	pinRepo.On("Set", ctx, mock.AnythingOfType("*entity.RatePin")).Return(nil)
+	pinRepo.On("AppendAudit", ctx, mock.MatchedBy(func(record *entity.RatePinAuditRecord) bool {
+		return record.Action == entity.RatePinActionPin && record.Actor == "admin-1" &&
+			record.Rate == 0.011 && record.ExpiresAt.Equal(expiresAt)
+	})).Return(nil)
+
+	// Act
+	pin, err := svc.PinRate(ctx, &entity.PinRateRequest{
+		From: "rub", To: "usd", Rate: 0.011, ExpiresAt: expiresAt, Reason: "provider spike",
+	}, "admin-1")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "RUB", pin.From)
+	assert.Equal(t, "USD", pin.To)
+	assert.Equal(t, "admin-1", pin.PinnedBy)
+	pinRepo.AssertExpectations(t)
+}
+
+func TestPinRate_Validation(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		req  entity.PinRateRequest
+	}{
+		{"same currency", entity.PinRateRequest{From: "USD", To: "USD", Rate: 1, ExpiresAt: now.Add(time.Hour), Reason: "r"}},
+		{"bad code", entity.PinRateRequest{From: "DOLLAR", To: "RUB", Rate: 1, ExpiresAt: now.Add(time.Hour), Reason: "r"}},
+		{"zero rate", entity.PinRateRequest{From: "USD", To: "RUB", Rate: 0, ExpiresAt: now.Add(time.Hour), Reason: "r"}},
+		{"no reason", entity.PinRateRequest{From: "USD", To: "RUB", Rate: 90, ExpiresAt: now.Add(time.Hour)}},
+		{"expired", entity.PinRateRequest{From: "USD", To: "RUB", Rate: 90, ExpiresAt: now.Add(-time.Minute), Reason: "r"}},
+		{"too long", entity.PinRateRequest{From: "USD", To: "RUB", Rate: 90, ExpiresAt: now.Add(MaxRatePinDuration + time.Hour), Reason: "r"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pinRepo := new(mocks.MockRatePinRepository)
+			svc := NewExchangeRateService(new(mocks.MockExchangeRateRepository), new(mocks.MockExchangeRateAPIClient), pinRepo)
+
+			_, err := svc.PinRate(context.Background(), &tt.req, "admin-1")
+
+			assert.ErrorIs(t, err, ErrInvalidRatePin)
+			pinRepo.AssertNotCalled(t, "Set", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPinRate_Disabled(t *testing.T) {
+	svc := NewExchangeRateService(new(mocks.MockExchangeRateRepository), new(mocks.MockExchangeRateAPIClient), nil)
+
+	_, err := svc.PinRate(context.Background(), &entity.PinRateRequest{}, "admin-1")
+
+	assert.ErrorIs(t, err, ErrRatePinsDisabled)
+}
+
+func TestUnpinRate_WritesAudit(t *testing.T) {
+	pinRepo := new(mocks.MockRatePinRepository)
+	svc := NewExchangeRateService(new(mocks.MockExchangeRateRepository), new(mocks.MockExchangeRateAPIClient), pinRepo)
+	ctx := context.Background()
+
+	pinRepo.On("Delete", ctx, "USD", "RUB").Return(true, nil)
+	pinRepo.On("AppendAudit", ctx, mock.MatchedBy(func(record *entity.RatePinAuditRecord) bool {
+		return record.Action == entity.RatePinActionUnpin && record.Reason == "provider fixed"
+	})).Return(nil)
+
+	err := svc.UnpinRate(ctx, "usd", "rub", "admin-1", "provider fixed")
+
+	require.NoError(t, err)
+	pinRepo.AssertExpectations(t)
+}
+
+func TestListPins_SkipsExpired(t *testing.T) {
+	pinRepo := new(mocks.MockRatePinRepository)
+	svc := NewExchangeRateService(new(mocks.MockExchangeRateRepository), new(mocks.MockExchangeRateAPIClient), pinRepo)
+
+	pinRepo.On("List", mock.Anything).Return([]entity.RatePin{
+		{From: "USD", To: "RUB", ExpiresAt: time.Now().Add(time.Hour)},
+		{From: "EUR", To: "RUB", ExpiresAt: time.Now().Add(-time.Hour)},
+	}, nil)
+
+	pins, err := svc.ListPins(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, pins, 1)
+	assert.Equal(t, "USD", pins[0].From)
+}
+
+// ===================== Pinned Conversion Tests =====================
+
+func TestConvertCurrency_UsesPinnedRate(t *testing.T) {
+	// Arrange
+	rateRepo := new(mocks.MockExchangeRateRepository)
+	pinRepo := new(mocks.MockRatePinRepository)
+	svc := NewExchangeRateService(rateRepo, new(mocks.MockExchangeRateAPIClient), pinRepo)
+	ctx := context.Background()
+
+	pinRepo.On("Find", ctx, "USD", "RUB").Return(&entity.RatePin{From: "USD", To: "RUB", Rate: 90, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+
+	// Act
+	converted, rate, err := svc.ConvertCurrency(ctx, money.MustParse("10.00"), "USD", "RUB")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 90.0, rate)
+	assert.Equal(t, money.MustParse("900.00"), converted)
+	rateRepo.AssertNotCalled(t, "GetMultiple", mock.Anything, mock.Anything)
+}
+
+func TestConvertCurrency_UsesInversePinnedRate(t *testing.T) {
+	pinRepo := new(mocks.MockRatePinRepository)
+	svc := NewExchangeRateService(new(mocks.MockExchangeRateRepository), new(mocks.MockExchangeRateAPIClient), pinRepo)
+	ctx := context.Background()
+
+	pinRepo.On("Find", ctx, "RUB", "USD").Return(&entity.RatePin{From: "USD", To: "RUB", Rate: 80, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+
+	converted, rate, err := svc.ConvertCurrency(ctx, money.MustParse("800.00"), "RUB", "USD")
+
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0/80, rate, 1e-12)
+	assert.Equal(t, money.MustParse("10.00"), converted)
+}
+
+func TestConvertCurrency_IgnoresExpiredPinAndRedisErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		pin  *entity.RatePin
+		err  error
+	}{
+		{"expired pin", &entity.RatePin{From: "USD", To: "RUB", Rate: 1, ExpiresAt: time.Now().Add(-time.Minute)}, nil},
+		{"redis error", nil, errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rateRepo := new(mocks.MockExchangeRateRepository)
+			pinRepo := new(mocks.MockRatePinRepository)
+			svc := NewExchangeRateService(rateRepo, new(mocks.MockExchangeRateAPIClient), pinRepo)
+			ctx := context.Background()
+
+			pinRepo.On("Find", ctx, "USD", "RUB").Return(tt.pin, tt.err)
+			rateRepo.On("GetMultiple", ctx, []string{"USD", "RUB"}).Return(map[string]*entity.ExchangeRate{
+				"USD": {Currency: "USD", Rate: 1.0},
+				"RUB": {Currency: "RUB", Rate: 91.23},
+			}, nil)
+
+			_, rate, err := svc.ConvertCurrency(ctx, money.MustParse("1.00"), "USD", "RUB")
+
+			require.NoError(t, err)
+			assert.Equal(t, 91.23, rate)
+		})
+	}
+}
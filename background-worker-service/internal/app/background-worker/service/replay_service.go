@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"augustberries/background-worker-service/internal/app/background-worker/entity"
+	"augustberries/pkg/kafka"
+
+	"github.com/google/uuid"
+)
+
+// ErrReplayFilterRequired - не указаны заказы для повторной обработки
+// Повтор всего топика без фильтра слишком легко запустить случайно
+var ErrReplayFilterRequired = errors.New("at least one order ID is required")
+
+// ErrReplayInvalidOffset - отрицательный начальный offset
+var ErrReplayInvalidOffset = errors.New("from_offset cannot be negative")
+
+// ReplayService повторно обрабатывает события заказов из Kafka
+// Используется для восстановления после ошибок обработки без ручной работы с kafka-console-consumer
+type ReplayService struct {
+	replayer kafka.Replayer
+	orderSvc OrderProcessingServiceInterface
+}
+
+// NewReplayService создает сервис повторной обработки событий
+func NewReplayService(replayer kafka.Replayer, orderSvc OrderProcessingServiceInterface) *ReplayService {
+	return &ReplayService{
+		replayer: replayer,
+		orderSvc: orderSvc,
+	}
+}
+
+// Offsets возвращает границы партиций топика событий заказов
+func (s *ReplayService) Offsets(ctx context.Context) ([]kafka.PartitionOffsets, error) {
+	return s.replayer.Offsets(ctx)
+}
+
+// Replay перечитывает топик с заданной позиции и обрабатывает события выбранных заказов
+// Ошибка обработки одного события не прерывает повтор, а попадает в результат
+// В режиме dry-run события только собираются в результат
+func (s *ReplayService) Replay(ctx context.Context, req entity.ReplayRequest) (*entity.ReplayResult, error) {
+	if len(req.OrderIDs) == 0 {
+		return nil, ErrReplayFilterRequired
+	}
+	if req.FromOffset < 0 {
+		return nil, ErrReplayInvalidOffset
+	}
+
+	orderIDs := make(map[uuid.UUID]struct{}, len(req.OrderIDs))
+	for _, id := range req.OrderIDs {
+		orderIDs[id] = struct{}{}
+	}
+
+	from := kafka.ReplayPosition{Offset: req.FromOffset}
+	if req.Since != nil {
+		from.Since = *req.Since
+	}
+
+	result := &entity.ReplayResult{DryRun: req.DryRun, Events: []entity.ReplayedEvent{}}
+
+	err := s.replayer.Replay(ctx, from, func(ctx context.Context, msg kafka.Message) error {
+		result.Scanned++
+
+		var event entity.OrderEvent
+		if err := kafka.Decode(kafka.JSONCodec{}, msg, &event); err != nil {
+			// Нечитаемые сообщения уже были в DLQ, при повторе их пропускаем
+			return nil
+		}
+		if _, ok := orderIDs[event.OrderID]; !ok {
+			return nil
+		}
+
+		result.Matched++
+		replayed := entity.ReplayedEvent{
+			Partition: msg.Partition,
+			Offset:    msg.Offset,
+			EventType: event.EventType,
+			OrderID:   event.OrderID,
+			Timestamp: event.Timestamp,
+		}
+
+		if !req.DryRun {
+			if err := s.orderSvc.ProcessOrderEvent(ctx, &event); err != nil {
+				log.Printf("Replay of %s event for order %s (%d@%d) failed: %v",
+					event.EventType, event.OrderID, msg.Partition, msg.Offset, err)
+				replayed.Error = err.Error()
+				result.Failed++
+			} else {
+				result.Processed++
+			}
+		}
+
+		result.Events = append(result.Events, replayed)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay order events: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"augustberries/background-worker-service/internal/app/background-worker/entity"
+	"augustberries/pkg/kafka"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReplayer отдает заранее заданные сообщения и запоминает позицию чтения
+type fakeReplayer struct {
+	messages []kafka.Message
+	from     kafka.ReplayPosition
+	err      error
+}
+
+func (r *fakeReplayer) Offsets(ctx context.Context) ([]kafka.PartitionOffsets, error) {
+	return nil, r.err
+}
+
+func (r *fakeReplayer) Replay(ctx context.Context, from kafka.ReplayPosition, handler kafka.Handler) error {
+	r.from = from
+	if r.err != nil {
+		return r.err
+	}
+	for _, msg := range r.messages {
+		if err := handler(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordingOrderProcessor запоминает обработанные события
+type recordingOrderProcessor struct {
+	processed []uuid.UUID
+	failFor   uuid.UUID
+}
+
+func (p *recordingOrderProcessor) ProcessOrderEvent(ctx context.Context, event *entity.OrderEvent) error {
+	if event.OrderID == p.failFor {
+		return errors.New("order validation failed")
+	}
+	p.processed = append(p.processed, event.OrderID)
+	return nil
+}
+
+func (p *recordingOrderProcessor) ProcessOrderEventsBatch(ctx context.Context, events []*entity.OrderEvent) []error {
+	errs := make([]error, len(events))
+	for i, event := range events {
+		errs[i] = p.ProcessOrderEvent(ctx, event)
+	}
+	return errs
+}
+
+func orderEventMessage(t *testing.T, offset int64, orderID uuid.UUID) kafka.Message {
+	value, err := json.Marshal(entity.OrderEvent{
+		EventType: entity.EventTypeOrderCreated,
+		OrderID:   orderID,
+		Timestamp: time.Now(),
+	})
+	require.NoError(t, err)
+	return kafka.Message{Partition: 0, Offset: offset, Value: value}
+}
+
+// ==================== Replay Tests ====================
+
+func TestReplay_ProcessesOnlySelectedOrders(t *testing.T) {
+	// Arrange
+	target, other := uuid.New(), uuid.New()
+	replayer := &fakeReplayer{messages: []kafka.Message{
+		orderEventMessage(t, 10, other),
+		orderEventMessage(t, 11, target),
+		{Partition: 0, Offset: 12, Value: []byte("not json")},
+	}}
+	processor := &recordingOrderProcessor{}
+	svc := NewReplayService(replayer, processor)
+
+	// Act
+	result, err := svc.Replay(context.Background(), entity.ReplayRequest{OrderIDs: []uuid.UUID{target}, FromOffset: 10})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), replayer.from.Offset)
+	assert.Equal(t, 3, result.Scanned)
+	assert.Equal(t, 1, result.Matched)
+	assert.Equal(t, 1, result.Processed)
+	assert.Equal(t, []uuid.UUID{target}, processor.processed)
+	require.Len(t, result.Events, 1)
+	assert.Equal(t, int64(11), result.Events[0].Offset)
+}
+
+func TestReplay_DryRunDoesNotProcess(t *testing.T) {
+	// Arrange
+	target := uuid.New()
+	since := time.Now().Add(-time.Hour)
+	replayer := &fakeReplayer{messages: []kafka.Message{orderEventMessage(t, 0, target)}}
+	processor := &recordingOrderProcessor{}
+	svc := NewReplayService(replayer, processor)
+
+	// Act
+	result, err := svc.Replay(context.Background(), entity.ReplayRequest{
+		OrderIDs: []uuid.UUID{target},
+		Since:    &since,
+		DryRun:   true,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, replayer.from.Since.Equal(since))
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 1, result.Matched)
+	assert.Zero(t, result.Processed)
+	assert.Empty(t, processor.processed)
+}
+
+func TestReplay_ProcessingErrorDoesNotStopReplay(t *testing.T) {
+	// Arrange
+	failing, ok := uuid.New(), uuid.New()
+	replayer := &fakeReplayer{messages: []kafka.Message{
+		orderEventMessage(t, 0, failing),
+		orderEventMessage(t, 1, ok),
+	}}
+	processor := &recordingOrderProcessor{failFor: failing}
+	svc := NewReplayService(replayer, processor)
+
+	// Act
+	result, err := svc.Replay(context.Background(), entity.ReplayRequest{OrderIDs: []uuid.UUID{failing, ok}})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, 1, result.Processed)
+	assert.Equal(t, "order validation failed", result.Events[0].Error)
+	assert.Empty(t, result.Events[1].Error)
+}
+
+func TestReplay_RequiresOrderFilter(t *testing.T) {
+	replayer := &fakeReplayer{}
+	svc := NewReplayService(replayer, &recordingOrderProcessor{})
+
+	_, err := svc.Replay(context.Background(), entity.ReplayRequest{})
+
+	assert.ErrorIs(t, err, ErrReplayFilterRequired)
+}
+
+func TestReplay_KafkaError(t *testing.T) {
+	replayer := &fakeReplayer{err: errors.New("connection refused")}
+	svc := NewReplayService(replayer, &recordingOrderProcessor{})
+
+	_, err := svc.Replay(context.Background(), entity.ReplayRequest{OrderIDs: []uuid.UUID{uuid.New()}})
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"augustberries/background-worker-service/internal/app/background-worker/entity"
+	"augustberries/background-worker-service/internal/app/background-worker/repository"
+	"augustberries/pkg/kafka"
+
+	"github.com/google/uuid"
+)
+
+// MaxStatusReportPeriod - максимальный период отчета о сроках выполнения заказов
+const MaxStatusReportPeriod = 366 * 24 * time.Hour
+
+// ErrInvalidReportPeriod - некорректный период отчета
+var ErrInvalidReportPeriod = errors.New("invalid report period")
+
+// StatusAnalyticsService ведет проекцию статусов заказов по ORDER_STATUS_CHANGED и строит отчет о сроках
+// Время в статусе - от входа в статус до следующего перехода, время до доставки - от создания заказа
+type StatusAnalyticsService struct {
+	repo repository.OrderStatusRepository
+}
+
+// NewStatusAnalyticsService создает сервис аналитики статусов заказов
+func NewStatusAnalyticsService(repo repository.OrderStatusRepository) *StatusAnalyticsService {
+	return &StatusAnalyticsService{repo: repo}
+}
+
+// HandleMessage обрабатывает сообщение ORDER_STATUS_CHANGED (обработчик KafkaConsumer.Handle)
+func (s *StatusAnalyticsService) HandleMessage(ctx context.Context, message kafka.Message) error {
+	var event entity.OrderEvent
+	if err := kafka.Decode(kafka.JSONCodec{}, message, &event); err != nil {
+		return kafka.Permanent(fmt.Errorf("failed to unmarshal order status event: %w", err))
+	}
+	return s.ProcessStatusChanged(ctx, &event)
+}
+
+// ProcessStatusChanged сохраняет переход статуса с длительностью предыдущего статуса
+// События одного заказа приходят по порядку (ключ сообщения - ID заказа); повторные и устаревшие события пропускаются
+func (s *StatusAnalyticsService) ProcessStatusChanged(ctx context.Context, event *entity.OrderEvent) error {
+	changedAt := eventTimestamp(event)
+
+	projection, err := s.repo.GetProjection(ctx, event.OrderID)
+	if err != nil {
+		return err
+	}
+	if projection != nil && !changedAt.After(projection.StatusSince) {
+		log.Printf("Skipping stale %s event for order %s (%s -> %s)",
+			event.EventType, event.OrderID, event.PreviousStatus, event.Status)
+		return nil
+	}
+
+	transition := &entity.OrderStatusTransition{
+		ID:         uuid.New(),
+		OrderID:    event.OrderID,
+		TenantID:   event.TenantID,
+		FromStatus: event.PreviousStatus,
+		ToStatus:   event.Status,
+		Reason:     event.Reason,
+		ChangedAt:  changedAt,
+	}
+	if event.Actor != nil {
+		transition.ActorType = event.Actor.Type
+		transition.ActorID = event.Actor.ID
+		transition.ImpersonatorID = event.Actor.ImpersonatorID
+	}
+	if enteredAt := statusEnteredAt(event, projection); enteredAt != nil && !changedAt.Before(*enteredAt) {
+		duration := changedAt.Sub(*enteredAt).Seconds()
+		transition.DurationSeconds = &duration
+	}
+
+	next := &entity.OrderStatusProjection{
+		OrderID:        event.OrderID,
+		TenantID:       event.TenantID,
+		Status:         event.Status,
+		StatusSince:    changedAt,
+		OrderCreatedAt: event.OrderCreatedAt,
+	}
+	if projection != nil && projection.OrderCreatedAt != nil {
+		next.OrderCreatedAt = projection.OrderCreatedAt
+	}
+	if event.Status == entity.OrderStatusDelivered {
+		next.DeliveredAt = &changedAt
+	}
+
+	return s.repo.SaveTransition(ctx, transition, next)
+}
+
+// statusEnteredAt возвращает момент входа заказа в предыдущий статус, nil - неизвестен
+// Первый статус заказа (pending или scheduled) начинается при создании заказа
+func statusEnteredAt(event *entity.OrderEvent, projection *entity.OrderStatusProjection) *time.Time {
+	if projection != nil {
+		if projection.Status == event.PreviousStatus {
+			return &projection.StatusSince
+		}
+		// События между проекцией и текущим пропущены
+		return nil
+	}
+
+	if event.PreviousStatus == entity.OrderStatusPending || event.PreviousStatus == entity.OrderStatusScheduled {
+		return event.OrderCreatedAt
+	}
+	return nil
+}
+
+// Report строит отчет о сроках выполнения заказов за период [From, To)
+func (s *StatusAnalyticsService) Report(ctx context.Context, filter entity.StatusReportFilter) (*entity.OrderStatusReport, error) {
+	if !filter.To.After(filter.From) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidReportPeriod)
+	}
+	if filter.To.Sub(filter.From) > MaxStatusReportPeriod {
+		return nil, fmt.Errorf("%w: period must not exceed %d days", ErrInvalidReportPeriod, int(MaxStatusReportPeriod.Hours()/24))
+	}
+
+	durations, err := s.repo.StatusDurations(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	delivery, err := s.repo.DeliveryDurations(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if durations == nil {
+		durations = []entity.StatusDuration{}
+	}
+	return &entity.OrderStatusReport{
+		From:          filter.From,
+		To:            filter.To,
+		TenantID:      filter.TenantID,
+		TimeInStatus:  durations,
+		TimeToDeliver: delivery,
+	}, nil
+}
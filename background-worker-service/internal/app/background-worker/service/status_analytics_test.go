@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"augustberries/background-worker-service/internal/app/background-worker/entity"
+	"augustberries/background-worker-service/internal/app/background-worker/repository/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// statusChangedEvent создает событие ORDER_STATUS_CHANGED заказа, созданного в createdAt
+func statusChangedEvent(orderID uuid.UUID, from, to entity.OrderStatus, at, createdAt time.Time) *entity.OrderEvent {
+	adminID := uuid.New()
+	return &entity.OrderEvent{
+		EventType:      entity.EventTypeOrderStatusChanged,
+		TenantID:       "shop-a",
+		OrderID:        orderID,
+		PreviousStatus: from,
+		Status:         to,
+		Actor:          &entity.StatusActor{Type: "admin", ID: &adminID},
+		Reason:         "warehouse",
+		OrderCreatedAt: &createdAt,
+		Timestamp:      at,
+	}
+}
+
+// ===================== ProcessStatusChanged Tests =====================
+
+func TestProcessStatusChanged_FirstTransitionCountsFromCreation(t *testing.T) {
+	// Arrange
+	repo := new(mocks.MockOrderStatusRepository)
+	svc := NewStatusAnalyticsService(repo)
+	ctx := context.Background()
+
+	orderID := uuid.New()
+	createdAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	confirmedAt := createdAt.Add(90 * time.Minute)
+	event := statusChangedEvent(orderID, entity.OrderStatusPending, entity.OrderStatusConfirmed, confirmedAt, createdAt)
+
+	repo.On("GetProjection", ctx, orderID).Return(nil, nil)
+	repo.On("SaveTransition", ctx,
+		mock.MatchedBy(func(tr *entity.OrderStatusTransition) bool {
+			return tr.FromStatus == entity.OrderStatusPending && tr.ToStatus == entity.OrderStatusConfirmed &&
+				tr.ActorType == "admin" && tr.ActorID == event.Actor.ID && tr.Reason == "warehouse" &&
+				tr.DurationSeconds != nil && *tr.DurationSeconds == 5400
+		}),
+		mock.MatchedBy(func(p *entity.OrderStatusProjection) bool {
+			return p.Status == entity.OrderStatusConfirmed && p.StatusSince.Equal(confirmedAt) &&
+				p.TenantID == "shop-a" && p.DeliveredAt == nil
+		}),
+	).Return(nil)
+
+	// Act
+	err := svc.ProcessStatusChanged(ctx, event)
+
+	// Assert
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestProcessStatusChanged_DurationFromProjection(t *testing.T) {
+	// Arrange
+	repo := new(mocks.MockOrderStatusRepository)
+	svc := NewStatusAnalyticsService(repo)
+	ctx := context.Background()
+
+	orderID := uuid.New()
+	createdAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	shippedAt := createdAt.Add(24 * time.Hour)
+	deliveredAt := shippedAt.Add(48 * time.Hour)
+	event := statusChangedEvent(orderID, entity.OrderStatusShipped, entity.OrderStatusDelivered, deliveredAt, createdAt)
+
+	repo.On("GetProjection", ctx, orderID).Return(&entity.OrderStatusProjection{
+		OrderID: orderID, Status: entity.OrderStatusShipped, StatusSince: shippedAt, OrderCreatedAt: &createdAt,
+	}, nil)
+	repo.On("SaveTransition", ctx,
+		mock.MatchedBy(func(tr *entity.OrderStatusTransition) bool {
+			return tr.DurationSeconds != nil && *tr.DurationSeconds == (48*time.Hour).Seconds()
+		}),
+		mock.MatchedBy(func(p *entity.OrderStatusProjection) bool {
+			// Время доставки сохраняется для отчета о времени до доставки
+			return p.DeliveredAt != nil && p.DeliveredAt.Equal(deliveredAt) && p.OrderCreatedAt.Equal(createdAt)
+		}),
+	).Return(nil)
+
+	// Act
+	err := svc.ProcessStatusChanged(ctx, event)
+
+	// Assert
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestProcessStatusChanged_MissedEventsLeaveDurationUnknown(t *testing.T) {
+	// Arrange
+	repo := new(mocks.MockOrderStatusRepository)
+	svc := NewStatusAnalyticsService(repo)
+	ctx := context.Background()
+
+	orderID := uuid.New()
+	createdAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	// Переход confirmed -> shipped пропущен: проекция еще в confirmed
+	repo.On("GetProjection", ctx, orderID).Return(&entity.OrderStatusProjection{
+		OrderID: orderID, Status: entity.OrderStatusConfirmed, StatusSince: createdAt.Add(time.Hour),
+	}, nil)
+	repo.On("SaveTransition", ctx,
+		mock.MatchedBy(func(tr *entity.OrderStatusTransition) bool { return tr.DurationSeconds == nil }),
+		mock.AnythingOfType("*entity.OrderStatusProjection"),
+	).Return(nil)
+
+	// Act
+	err := svc.ProcessStatusChanged(ctx, statusChangedEvent(orderID, entity.OrderStatusShipped, entity.OrderStatusDelivered, createdAt.Add(72*time.Hour), createdAt))
+
+	// Assert
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestProcessStatusChanged_SkipsStaleEvent(t *testing.T) {
+	// Arrange
+	repo := new(mocks.MockOrderStatusRepository)
+	svc := NewStatusAnalyticsService(repo)
+	ctx := context.Background()
+
+	orderID := uuid.New()
+	createdAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	confirmedAt := createdAt.Add(time.Hour)
+	repo.On("GetProjection", ctx, orderID).Return(&entity.OrderStatusProjection{
+		OrderID: orderID, Status: entity.OrderStatusConfirmed, StatusSince: confirmedAt,
+	}, nil)
+
+	// Act: повторная доставка уже учтенного перехода
+	err := svc.ProcessStatusChanged(ctx, statusChangedEvent(orderID, entity.OrderStatusPending, entity.OrderStatusConfirmed, confirmedAt, createdAt))
+
+	// Assert
+	require.NoError(t, err)
+	repo.AssertNotCalled(t, "SaveTransition", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// ===================== Report Tests =====================
+
+func TestStatusReport(t *testing.T) {
+	// Arrange
+	repo := new(mocks.MockOrderStatusRepository)
+	svc := NewStatusAnalyticsService(repo)
+	ctx := context.Background()
+
+	to := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	filter := entity.StatusReportFilter{From: to.AddDate(0, -1, 0), To: to, TenantID: "shop-a"}
+	pending := entity.StatusDuration{Status: entity.OrderStatusPending, DurationStats: entity.DurationStats{Count: 10, AvgSeconds: 3600}}
+	delivery := entity.DurationStats{Count: 4, P90Seconds: 259200}
+	repo.On("StatusDurations", ctx, filter).Return([]entity.StatusDuration{pending}, nil)
+	repo.On("DeliveryDurations", ctx, filter).Return(delivery, nil)
+
+	// Act
+	report, err := svc.Report(ctx, filter)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "shop-a", report.TenantID)
+	assert.Equal(t, []entity.StatusDuration{pending}, report.TimeInStatus)
+	assert.Equal(t, delivery, report.TimeToDeliver)
+}
+
+func TestStatusReport_InvalidPeriod(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name   string
+		filter entity.StatusReportFilter
+	}{
+		{name: "from after to", filter: entity.StatusReportFilter{From: now, To: now.Add(-time.Hour)}},
+		{name: "too long", filter: entity.StatusReportFilter{From: now.Add(-MaxStatusReportPeriod - time.Hour), To: now}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			repo := new(mocks.MockOrderStatusRepository)
+			svc := NewStatusAnalyticsService(repo)
+
+			// Act
+			report, err := svc.Report(context.Background(), tt.filter)
+
+			// Assert
+			assert.True(t, errors.Is(err, ErrInvalidReportPeriod))
+			assert.Nil(t, report)
+			repo.AssertNotCalled(t, "StatusDurations", mock.Anything, mock.Anything)
+		})
+	}
+}
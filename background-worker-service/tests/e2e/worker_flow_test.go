@@ -13,6 +13,8 @@ import (
 	"augustberries/background-worker-service/internal/app/background-worker/processor"
 	"augustberries/background-worker-service/internal/app/background-worker/repository"
 	"augustberries/background-worker-service/internal/app/background-worker/service"
+	kafkapkg "augustberries/pkg/kafka"
+	"augustberries/pkg/money"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
@@ -105,19 +107,19 @@ func (s *BackgroundWorkerE2ETestSuite) SetupSuite() {
 	}
 
 	// Services
-	s.exchangeService = service.NewExchangeRateService(s.rateRepo, s.mockAPIClient)
+	s.exchangeService = service.NewExchangeRateService(s.rateRepo, s.mockAPIClient, nil)
 	s.orderProcessingService = service.NewOrderProcessingService(s.orderRepo, s.exchangeService)
 
 	// Kafka Consumer
-	s.kafkaConsumer = processor.NewKafkaConsumer(
-		[]string{kafkaBroker},
-		kafkaTopic,
-		"e2e-test-group-"+uuid.New().String(), // Уникальный group ID для каждого запуска
-		1,      // minBytes
-		10e6,   // maxBytes (10MB)
-		s.orderProcessingService,
-		s.exchangeService,
-	)
+	consumer := kafkapkg.NewConsumer(kafkapkg.ConsumerConfig{
+		Brokers:  []string{kafkaBroker},
+		Topic:    kafkaTopic,
+		GroupID:  "e2e-test-group-" + uuid.New().String(), // Уникальный group ID для каждого запуска
+		Service:  "background-worker-e2e",
+		MinBytes: 1,
+		MaxBytes: 10e6, // 10MB
+	})
+	s.kafkaConsumer = processor.NewKafkaConsumer(consumer, nil, s.orderProcessingService, s.exchangeService)
 }
 
 func (s *BackgroundWorkerE2ETestSuite) createKafkaTopic(broker, topic string) {
@@ -199,8 +201,8 @@ func (s *BackgroundWorkerE2ETestSuite) TestE2E_OrderCreated_FullFlow() {
 	order := &entity.Order{
 		ID:            orderID,
 		UserID:        userID,
-		TotalPrice:    110.0, // 100 товары + 10 доставка
-		DeliveryPrice: 10.0,
+		TotalPrice:    money.MustParse("110.00"), // 100 товары + 10 доставка
+		DeliveryPrice: money.MustParse("10.00"),
 		Currency:      "USD",
 		Status:        entity.OrderStatusPending,
 		CreatedAt:     time.Now(),
@@ -213,7 +215,7 @@ func (s *BackgroundWorkerE2ETestSuite) TestE2E_OrderCreated_FullFlow() {
 		EventType:  entity.EventTypeOrderCreated,
 		OrderID:    orderID,
 		UserID:     userID,
-		TotalPrice: 110.0,
+		TotalPrice: money.MustParse("110.00"),
 		Currency:   "USD",
 		Status:     entity.OrderStatusPending,
 		ItemsCount: 2,
@@ -244,11 +246,11 @@ func (s *BackgroundWorkerE2ETestSuite) TestE2E_OrderCreated_FullFlow() {
 	s.Require().NoError(err)
 
 	// Проверяем конвертацию
-	// DeliveryPrice: 10 USD * 91.23 = 912.3 RUB
-	// TotalPrice: 100 * 91.23 + 912.3 = 10035.3 RUB
+	// DeliveryPrice: money.MustParse("10.00") USD * 91.23 = 912.3 RUB
+	// TotalPrice: money.MustParse("100.00") * 91.23 + 912.3 = 10035.3 RUB
 	s.Equal("RUB", updatedOrder.Currency)
-	s.InDelta(912.3, updatedOrder.DeliveryPrice, 0.1)
-	s.InDelta(10035.3, updatedOrder.TotalPrice, 0.1)
+	s.InDelta(912.3, updatedOrder.DeliveryPrice.Float64(), 0.1)
+	s.InDelta(10035.3, updatedOrder.TotalPrice.Float64(), 0.1)
 }
 
 func (s *BackgroundWorkerE2ETestSuite) TestE2E_OrderCreated_EURtoRUB() {
@@ -260,8 +262,8 @@ func (s *BackgroundWorkerE2ETestSuite) TestE2E_OrderCreated_EURtoRUB() {
 	order := &entity.Order{
 		ID:            orderID,
 		UserID:        userID,
-		TotalPrice:    220.0, // 200 товары + 20 доставка
-		DeliveryPrice: 20.0,
+		TotalPrice:    money.MustParse("220.00"), // 200 товары + 20 доставка
+		DeliveryPrice: money.MustParse("20.00"),
 		Currency:      "EUR",
 		Status:        entity.OrderStatusPending,
 		CreatedAt:     time.Now(),
@@ -274,7 +276,7 @@ func (s *BackgroundWorkerE2ETestSuite) TestE2E_OrderCreated_EURtoRUB() {
 		EventType:  entity.EventTypeOrderCreated,
 		OrderID:    orderID,
 		UserID:     userID,
-		TotalPrice: 220.0,
+		TotalPrice: money.MustParse("220.00"),
 		Currency:   "EUR",
 		Timestamp:  time.Now(),
 	}
@@ -301,8 +303,8 @@ func (s *BackgroundWorkerE2ETestSuite) TestE2E_OrderCreated_EURtoRUB() {
 	expectedTotal := 200.0*expectedRate + expectedDelivery
 
 	s.Equal("RUB", updatedOrder.Currency)
-	s.InDelta(expectedDelivery, updatedOrder.DeliveryPrice, 0.5)
-	s.InDelta(expectedTotal, updatedOrder.TotalPrice, 0.5)
+	s.InDelta(expectedDelivery, updatedOrder.DeliveryPrice.Float64(), 0.5)
+	s.InDelta(expectedTotal, updatedOrder.TotalPrice.Float64(), 0.5)
 }
 
 func (s *BackgroundWorkerE2ETestSuite) TestE2E_MultipleOrders_Sequential() {
@@ -310,13 +312,13 @@ func (s *BackgroundWorkerE2ETestSuite) TestE2E_MultipleOrders_Sequential() {
 
 	orders := []struct {
 		id       uuid.UUID
-		total    float64
-		delivery float64
+		total    money.Amount
+		delivery money.Amount
 		currency string
 	}{
-		{uuid.New(), 110.0, 10.0, "USD"},
-		{uuid.New(), 220.0, 20.0, "EUR"},
-		{uuid.New(), 330.0, 30.0, "USD"},
+		{uuid.New(), money.MustParse("110.00"), money.MustParse("10.00"), "USD"},
+		{uuid.New(), money.MustParse("220.00"), money.MustParse("20.00"), "EUR"},
+		{uuid.New(), money.MustParse("330.00"), money.MustParse("30.00"), "USD"},
 	}
 
 	// Создаём заказы в БД
@@ -371,7 +373,7 @@ func (s *BackgroundWorkerE2ETestSuite) TestE2E_MultipleOrders_Sequential() {
 }
 
 func (s *BackgroundWorkerE2ETestSuite) TestE2E_OrderUpdated_Ignored() {
-	// ORDER_UPDATED должен игнорироваться
+	// ORDER_UPDATED без изменения позиций и итога должен игнорироваться
 
 	orderID := uuid.New()
 	userID := uuid.New()
@@ -379,8 +381,8 @@ func (s *BackgroundWorkerE2ETestSuite) TestE2E_OrderUpdated_Ignored() {
 	order := &entity.Order{
 		ID:            orderID,
 		UserID:        userID,
-		TotalPrice:    110.0,
-		DeliveryPrice: 10.0,
+		TotalPrice:    money.MustParse("110.00"),
+		DeliveryPrice: money.MustParse("10.00"),
 		Currency:      "USD", // Останется USD
 		Status:        entity.OrderStatusPending,
 	}
@@ -413,8 +415,8 @@ func (s *BackgroundWorkerE2ETestSuite) TestE2E_OrderUpdated_Ignored() {
 	s.db.First(&updatedOrder, "id = ?", orderID)
 
 	s.Equal("USD", updatedOrder.Currency) // Валюта не изменилась
-	s.Equal(110.0, updatedOrder.TotalPrice)
-	s.Equal(10.0, updatedOrder.DeliveryPrice)
+	s.Equal(money.MustParse("110.00"), updatedOrder.TotalPrice)
+	s.Equal(money.MustParse("10.00"), updatedOrder.DeliveryPrice)
 }
 
 func (s *BackgroundWorkerE2ETestSuite) TestE2E_ZeroDelivery_Skipped() {
@@ -426,8 +428,8 @@ func (s *BackgroundWorkerE2ETestSuite) TestE2E_ZeroDelivery_Skipped() {
 	order := &entity.Order{
 		ID:            orderID,
 		UserID:        userID,
-		TotalPrice:    100.0,
-		DeliveryPrice: 0.0, // Нулевая доставка
+		TotalPrice:    money.MustParse("100.00"),
+		DeliveryPrice: 0, // Нулевая доставка
 		Currency:      "USD",
 		Status:        entity.OrderStatusPending,
 	}
@@ -459,8 +461,8 @@ func (s *BackgroundWorkerE2ETestSuite) TestE2E_ZeroDelivery_Skipped() {
 
 	// Заказ не изменился
 	s.Equal("USD", updatedOrder.Currency)
-	s.Equal(100.0, updatedOrder.TotalPrice)
-	s.Equal(0.0, updatedOrder.DeliveryPrice)
+	s.Equal(money.MustParse("100.00"), updatedOrder.TotalPrice)
+	s.Equal(0, updatedOrder.DeliveryPrice)
 }
 
 // ===================== Helper Methods =====================
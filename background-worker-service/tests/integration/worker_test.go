@@ -11,6 +11,7 @@ import (
 	"augustberries/background-worker-service/internal/app/background-worker/entity"
 	"augustberries/background-worker-service/internal/app/background-worker/repository"
 	"augustberries/background-worker-service/internal/app/background-worker/service"
+	"augustberries/pkg/money"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
@@ -85,7 +86,7 @@ func (s *BackgroundWorkerIntegrationTestSuite) SetupSuite() {
 	}
 
 	// Services
-	s.exchangeService = service.NewExchangeRateService(s.rateRepo, s.mockAPIClient)
+	s.exchangeService = service.NewExchangeRateService(s.rateRepo, s.mockAPIClient, nil)
 	s.orderProcessingService = service.NewOrderProcessingService(s.orderRepo, s.exchangeService)
 }
 
@@ -162,8 +163,8 @@ func (s *BackgroundWorkerIntegrationTestSuite) TestOrderProcessing_FullCycle() {
 	order := &entity.Order{
 		ID:            orderID,
 		UserID:        userID,
-		TotalPrice:    110.0, // 100 товары + 10 доставка
-		DeliveryPrice: 10.0,
+		TotalPrice:    money.MustParse("110.00"), // 100 товары + 10 доставка
+		DeliveryPrice: money.MustParse("10.00"),
 		Currency:      "USD",
 		Status:        entity.OrderStatusPending,
 		CreatedAt:     time.Now(),
@@ -177,7 +178,7 @@ func (s *BackgroundWorkerIntegrationTestSuite) TestOrderProcessing_FullCycle() {
 		EventType:  entity.EventTypeOrderCreated,
 		OrderID:    orderID,
 		UserID:     userID,
-		TotalPrice: 110.0,
+		TotalPrice: money.MustParse("110.00"),
 		Currency:   "USD",
 	}
 
@@ -191,11 +192,11 @@ func (s *BackgroundWorkerIntegrationTestSuite) TestOrderProcessing_FullCycle() {
 	s.NoError(err)
 
 	// Ожидаемые значения:
-	// DeliveryPrice: 10 USD * 91.23 = 912.3 RUB
-	// TotalPrice: 100 * 91.23 + 912.3 = 9123 + 912.3 = 10035.3 RUB
+	// DeliveryPrice: money.MustParse("10.00") USD * 91.23 = 912.3 RUB
+	// TotalPrice: money.MustParse("100.00") * 91.23 + 912.3 = 9123 + 912.3 = 10035.3 RUB
 	s.Equal("RUB", updatedOrder.Currency)
-	s.InDelta(912.3, updatedOrder.DeliveryPrice, 0.01)
-	s.InDelta(10035.3, updatedOrder.TotalPrice, 0.01)
+	s.InDelta(912.3, updatedOrder.DeliveryPrice.Float64(), 0.01)
+	s.InDelta(10035.3, updatedOrder.TotalPrice.Float64(), 0.01)
 }
 
 func (s *BackgroundWorkerIntegrationTestSuite) TestOrderProcessing_ZeroDelivery_Skipped() {
@@ -207,8 +208,8 @@ func (s *BackgroundWorkerIntegrationTestSuite) TestOrderProcessing_ZeroDelivery_
 	order := &entity.Order{
 		ID:            orderID,
 		UserID:        userID,
-		TotalPrice:    100.0,
-		DeliveryPrice: 0.0, // Нулевая доставка
+		TotalPrice:    money.MustParse("100.00"),
+		DeliveryPrice: 0, // Нулевая доставка
 		Currency:      "USD",
 		Status:        entity.OrderStatusPending,
 	}
@@ -229,7 +230,7 @@ func (s *BackgroundWorkerIntegrationTestSuite) TestOrderProcessing_ZeroDelivery_
 	s.db.First(&updatedOrder, "id = ?", orderID)
 
 	s.Equal("USD", updatedOrder.Currency) // Валюта не изменилась
-	s.Equal(100.0, updatedOrder.TotalPrice)
+	s.Equal(money.MustParse("100.00"), updatedOrder.TotalPrice)
 }
 
 func (s *BackgroundWorkerIntegrationTestSuite) TestOrderProcessing_EUROrder() {
@@ -241,8 +242,8 @@ func (s *BackgroundWorkerIntegrationTestSuite) TestOrderProcessing_EUROrder() {
 	order := &entity.Order{
 		ID:            orderID,
 		UserID:        userID,
-		TotalPrice:    110.0,
-		DeliveryPrice: 10.0,
+		TotalPrice:    money.MustParse("110.00"),
+		DeliveryPrice: money.MustParse("10.00"),
 		Currency:      "EUR", // Заказ в EUR
 		Status:        entity.OrderStatusPending,
 	}
@@ -267,8 +268,8 @@ func (s *BackgroundWorkerIntegrationTestSuite) TestOrderProcessing_EUROrder() {
 	expectedTotal := 100.0*expectedRate + expectedDelivery
 
 	s.Equal("RUB", updatedOrder.Currency)
-	s.InDelta(expectedDelivery, updatedOrder.DeliveryPrice, 0.1)
-	s.InDelta(expectedTotal, updatedOrder.TotalPrice, 0.1)
+	s.InDelta(expectedDelivery, updatedOrder.DeliveryPrice.Float64(), 0.1)
+	s.InDelta(expectedTotal, updatedOrder.TotalPrice.Float64(), 0.1)
 }
 
 func (s *BackgroundWorkerIntegrationTestSuite) TestExchangeRates_EnsureAvailable() {
@@ -16,9 +16,19 @@ import (
 
 	"augustberries/catalog-service/internal/app/catalog/config"
 	"augustberries/catalog-service/internal/app/catalog/handler"
+	"augustberries/catalog-service/internal/app/catalog/processor"
 	"augustberries/catalog-service/internal/app/catalog/repository"
 	"augustberries/catalog-service/internal/app/catalog/service"
 	"augustberries/catalog-service/internal/app/catalog/util"
+	"augustberries/pkg/authcookie"
+	"augustberries/pkg/jwtclaims"
+	"augustberries/pkg/kafka"
+	"augustberries/pkg/lock"
+	"augustberries/pkg/openapi"
+	"augustberries/pkg/quota"
+	"augustberries/pkg/quote"
+	"augustberries/pkg/recovery"
+	"augustberries/pkg/redis"
 )
 
 func main() {
@@ -29,6 +39,17 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Паники в обработчиках и фоновых задачах отправляются в Sentry, если задан SENTRY_DSN
+	if err := recovery.ConfigureFromEnv(); err != nil {
+		log.Fatalf("Failed to configure panic reporting: %v", err)
+	}
+
+	// Проверка запросов и ответов по OpenAPI спецификации, только для dev и staging (OPENAPI_VALIDATION)
+	contract, err := openapi.FromEnv("catalog-service")
+	if err != nil {
+		log.Fatalf("Failed to configure OpenAPI validation: %v", err)
+	}
+
 	// === ПОДКЛЮЧЕНИЕ К POSTGRESQL ===
 	// Используем GORM для работы с PostgreSQL
 	db, err := connectDB(cfg.Database)
@@ -39,11 +60,11 @@ func main() {
 
 	// === ПОДКЛЮЧЕНИЕ К REDIS ===
 	// Redis используется для кеширования списка категорий
-	redisClient, err := util.NewRedisClient(
-		cfg.Redis.Address(),
-		cfg.Redis.Password,
-		cfg.Redis.DB,
-	)
+	redisConn, err := redis.NewClient(cfg.Redis.ClientConfig())
+	if err != nil {
+		log.Fatalf("Failed to create Redis client: %v", err)
+	}
+	redisClient, err := util.NewRedisClient(redisConn)
 	if err != nil {
 		log.Fatalf("Failed to connect to Redis: %v", err)
 	}
@@ -51,9 +72,22 @@ func main() {
 	log.Println("Successfully connected to Redis")
 
 	// === ИНИЦИАЛИЗАЦИЯ KAFKA PRODUCER ===
-	// Kafka producer отправляет события PRODUCT_UPDATED в топик product_events
+	// Kafka producer отправляет события товаров (PRODUCT_*, PRICE_CHANGED) и категорий (CATEGORY_*) в топик product_events
 	// Background Worker подписан на этот топик для обработки событий
-	kafkaProducer := util.NewKafkaProducer(cfg.Kafka.Brokers, cfg.Kafka.Topic)
+	kafkaProducer, err := kafka.NewProducer(kafka.ProducerConfig{
+		Brokers:     cfg.Kafka.Brokers,
+		Topic:       cfg.Kafka.Topic,
+		Service:     "catalog-service",
+		Compression: cfg.Kafka.Compression,
+		BatchSize:   cfg.Kafka.BatchSize,
+		BatchBytes:  cfg.Kafka.BatchBytes,
+		Linger:      cfg.Kafka.Linger,
+		Acks:        cfg.Kafka.Acks,
+		Idempotent:  cfg.Kafka.Idempotent,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create Kafka producer: %v", err)
+	}
 	defer kafkaProducer.Close()
 	log.Println("Successfully initialized Kafka producer")
 
@@ -61,36 +95,171 @@ func main() {
 	// Репозитории отвечают за работу с PostgreSQL
 	categoryRepo := repository.NewCategoryRepository(db)
 	productRepo := repository.NewProductRepository(db)
+	brandRepo := repository.NewBrandRepository(db)
+	supplierRepo := repository.NewSupplierRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	scheduledPriceRepo := repository.NewScheduledPriceRepository(db)
 
 	// === ИНИЦИАЛИЗАЦИЯ БИЗНЕС-ЛОГИКИ ===
 	// Service layer координирует работу репозиториев, кеша и Kafka
+	// Изменения товаров и категорий записываются в журнал catalog_audit
+	auditLog := service.NewAuditLog(auditRepo)
 	catalogService := service.NewCatalogService(
 		categoryRepo,
 		productRepo,
+		brandRepo,
+		supplierRepo,
 		redisClient,
 		kafkaProducer,
+		auditLog,
+		redisClient,
+	)
+	brandService := service.NewBrandService(brandRepo, supplierRepo, redisClient)
+	tagService := service.NewTagService(tagRepo, productRepo)
+	// Переводы товаров: основной язык хранится в товаре, остальные - в product_translations
+	translationService := service.NewTranslationService(repository.NewTranslationRepository(db), productRepo, cfg.Locale.Default)
+	// Котировки подписываются общим с Orders Service секретом
+	quoteService := service.NewQuoteService(productRepo, quote.NewSigner(cfg.Quote.Secret), cfg.Quote.TTL)
+	priceScheduleService := service.NewPriceScheduleService(scheduledPriceRepo, productRepo, kafkaProducer, auditLog)
+	// Импорт товаров из фидов поставщиков: новые товары создаются черновиками, события и журнал как при ручной правке
+	feedService := service.NewFeedService(
+		repository.NewFeedRepository(db),
+		supplierRepo,
+		categoryRepo,
+		productRepo,
+		util.NewHTTPFeedFetcher(cfg.Feeds.FetchTimeout, cfg.Feeds.MaxBytes),
+		kafkaProducer,
+		auditLog,
+		redisClient,
+	)
+
+	// === ПОИСКОВЫЙ ИНДЕКС ===
+	// Без OPENSEARCH_URL поиск выполняется в PostgreSQL
+	var searchIndex util.SearchIndex
+	if cfg.Search.URL != "" {
+		openSearch := util.NewOpenSearchClient(util.OpenSearchConfig{
+			URL:      cfg.Search.URL,
+			Alias:    cfg.Search.Index,
+			Username: cfg.Search.Username,
+			Password: cfg.Search.Password,
+			Timeout:  cfg.Search.Timeout,
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := openSearch.EnsureIndex(ctx); err != nil {
+			log.Printf("Warning: failed to prepare search index: %v", err)
+		}
+		cancel()
+		searchIndex = openSearch
+	}
+	searchService := service.NewSearchService(searchIndex, productRepo)
+
+	// Индексатор читает события товаров из product_events и обновляет OpenSearch
+	if searchIndex != nil {
+		searchIndexer := processor.NewSearchIndexer(kafka.NewConsumer(kafka.ConsumerConfig{
+			Brokers:  cfg.Kafka.Brokers,
+			Topic:    cfg.Kafka.Topic,
+			GroupID:  cfg.Search.GroupID,
+			Service:  "catalog-service",
+			MinBytes: 1,
+			MaxBytes: 10e6,
+		}), searchService)
+		searchIndexer.Start(context.Background())
+		defer searchIndexer.Stop()
+		log.Printf("Search indexer started (topic: %s, group: %s)", cfg.Kafka.Topic, cfg.Search.GroupID)
+	}
+
+	// === ЗАПУСК ПЛАНИРОВЩИКА ЦЕН ===
+	// Фоновая задача применяет запланированные цены и отправляет PRICE_CHANGED в Kafka
+	priceScheduler := service.NewPriceScheduler(priceScheduleService)
+	if err := priceScheduler.Start(context.Background(), cfg.Prices.Cron); err != nil {
+		log.Fatalf("Failed to start price scheduler: %v", err)
+	}
+	defer priceScheduler.Stop()
+
+	// === СЧЕТЧИКИ ТОВАРОВ В КАТЕГОРИЯХ ===
+	// product_count пересчитывается одним запросом по всем магазинам, до прогрева кеша, чтобы в нем были актуальные счетчики
+	categoryCounter := service.NewCategoryCounter(categoryRepo, redisClient)
+	categoryCounter.SetLocker(lock.New(redisConn, "catalog-service"), cfg.Counts.LockTTL)
+	if err := categoryCounter.Start(context.Background(), cfg.Counts.Cron); err != nil {
+		log.Fatalf("Failed to start category counter: %v", err)
+	}
+	defer categoryCounter.Stop()
+
+	// === ПРОГРЕВ КЕША ===
+	// Категории и популярные товары загружаются в Redis до приема запросов и обновляются по расписанию
+	cacheWarmer := service.NewCacheWarmer(categoryRepo, productRepo, redisClient, redisClient, cfg.Cache.TopProducts)
+	cacheWarmer.SetLocker(lock.New(redisConn, "catalog-service"), cfg.Cache.LockTTL)
+	if err := cacheWarmer.Start(context.Background(), cfg.Cache.WarmCron); err != nil {
+		log.Fatalf("Failed to start cache warmer: %v", err)
+	}
+	defer cacheWarmer.Stop()
+
+	// === ИМПОРТ ФИДОВ ПОСТАВЩИКОВ ===
+	// Фиды, которым пора обновиться, загружает одна реплика за запуск
+	feedScheduler := service.NewFeedScheduler(feedService)
+	feedScheduler.SetLocker(lock.New(redisConn, "catalog-service"), cfg.Feeds.LockTTL)
+	if err := feedScheduler.Start(context.Background(), cfg.Feeds.Cron); err != nil {
+		log.Fatalf("Failed to start feed scheduler: %v", err)
+	}
+	defer feedScheduler.Stop()
+
+	// Квоты запросов: счетчики в Redis, индивидуальные квоты и сохраненный расход в PostgreSQL
+	quotas := quota.NewTracker(
+		quota.NewPostgresStore(db),
+		redisConn,
+		quota.Limits{Daily: cfg.Quota.Daily, Monthly: cfg.Quota.Monthly},
+		cfg.Quota.CacheTTL,
 	)
+	quotaCtx, stopQuotas := context.WithCancel(context.Background())
+	quotasDone := make(chan struct{})
+	go func() {
+		defer close(quotasDone)
+		quotas.Run(quotaCtx, cfg.Quota.FlushInterval)
+	}()
 
 	// === ИНИЦИАЛИЗАЦИЯ AUTH MIDDLEWARE ===
 	// Middleware проверяет JWT токены для защиты API эндпоинтов
 	// JWT Secret должен совпадать с Auth Service
 	authMiddleware := handler.NewAuthMiddleware(cfg.JWT.Secret)
+	authMiddleware.SetExpectedClaims(jwtclaims.Expected{Issuer: cfg.JWT.Issuer, Audience: cfg.JWT.Audience})
+	// Токен из httpOnly cookie для SPA (AUTH_COOKIES); изменяющие запросы с cookie требуют X-CSRF-Token
+	cookies, err := authcookie.FromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure auth cookies: %v", err)
+	}
+	authMiddleware.SetCookies(cookies)
+	// Внутренний API (сверка оценок с Reviews Service) доступен по INTERNAL_API_TOKEN
+	authMiddleware.SetInternalToken(cfg.JWT.InternalToken)
 	log.Println("Initialized Auth middleware")
 
 	// === ИНИЦИАЛИЗАЦИЯ HTTP HANDLERS ===
 	// Handler обрабатывает HTTP запросы и вызывает методы service
-	catalogHandler := handler.NewCatalogHandler(catalogService)
+	catalogHandler := handler.NewCatalogHandler(catalogService, translationService)
+	brandHandler := handler.NewBrandHandler(brandService)
+	tagHandler := handler.NewTagHandler(tagService)
+	auditHandler := handler.NewAuditHandler(auditLog)
+	quoteHandler := handler.NewQuoteHandler(quoteService)
+	priceScheduleHandler := handler.NewPriceScheduleHandler(priceScheduleService)
+	translationHandler := handler.NewTranslationHandler(translationService)
+	searchHandler := handler.NewSearchHandler(searchService)
+	feedHandler := handler.NewFeedHandler(feedService)
+	// Выгрузка каталога для аналитики: не чаще PRODUCT_STREAM_RATE_LIMIT раз за PRODUCT_STREAM_RATE_WINDOW на магазин
+	streamHandler := handler.NewStreamHandler(service.NewProductStreamService(productRepo, redisClient, service.ProductStreamConfig{
+		RateLimit:  cfg.Stream.RateLimit,
+		RateWindow: cfg.Stream.RateWindow,
+	}))
 
 	// === НАСТРОЙКА МАРШРУТОВ ===
 	// Настраиваем REST API endpoints согласно заданию с использованием Gin
 	// Применяем Auth middleware для защиты эндпоинтов
-	router := handler.SetupRoutes(catalogHandler, authMiddleware)
+	router := handler.SetupRoutes(catalogHandler, brandHandler, tagHandler, quoteHandler, priceScheduleHandler, auditHandler, translationHandler, searchHandler, feedHandler, streamHandler, quotas, authMiddleware, cfg.CORS, cfg.Compression)
 
 	// === НАСТРОЙКА HTTP СЕРВЕРА ===
 	// Production-ready настройки с таймаутами
 	server := &http.Server{
 		Addr:         cfg.Server.Address(),
-		Handler:      router,
+		Handler:      contract.Wrap(router),
 		ReadTimeout:  15 * time.Second, // Таймаут чтения запроса
 		WriteTimeout: 15 * time.Second, // Таймаут записи ответа
 		IdleTimeout:  60 * time.Second, // Таймаут idle соединений
@@ -121,6 +290,10 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	// Сохраняем расход квот, накопленный с последнего сброса
+	stopQuotas()
+	<-quotasDone
+
 	log.Println("Catalog Service stopped gracefully")
 }
 
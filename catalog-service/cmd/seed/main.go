@@ -0,0 +1,115 @@
+// Команда seed заполняет каталог синтетическими категориями и товарами для нагрузочного тестирования
+// Данные пишутся напрямую через репозитории, минуя HTTP, Kafka и кеш. Повторный запуск с тем же
+// seed пропускает уже созданные записи, поэтому увеличить объем можно запуском с большим -scale
+//
+//	go run ./catalog-service/cmd/seed -seed 42 -scale medium
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"augustberries/catalog-service/internal/app/catalog/config"
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/repository"
+	"augustberries/pkg/seed"
+	"augustberries/pkg/tenant"
+)
+
+func main() {
+	opts, err := seed.ParseFlags()
+	if err != nil {
+		log.Fatalf("Invalid arguments: %v", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := connectDB(cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	ctx := tenant.WithID(context.Background(), opts.Tenant)
+	gen := opts.Generator()
+
+	if err := seedCategories(ctx, repository.NewCategoryRepository(db), gen); err != nil {
+		log.Fatalf("Failed to seed categories: %v", err)
+	}
+	if err := seedProducts(ctx, repository.NewProductRepository(db), gen); err != nil {
+		log.Fatalf("Failed to seed products: %v", err)
+	}
+}
+
+// seedCategories создает недостающие категории
+func seedCategories(ctx context.Context, repo repository.CategoryRepository, gen *seed.Generator) error {
+	progress := seed.NewProgress("categories", gen.Scale().Categories)
+	for _, c := range gen.Categories() {
+		_, err := repo.GetByID(ctx, c.ID)
+		if err == nil {
+			progress.Skipped()
+			continue
+		}
+		if !errors.Is(err, repository.ErrCategoryNotFound) {
+			return err
+		}
+
+		if err := repo.Create(ctx, &entity.Category{ID: c.ID, Name: c.Name}); err != nil {
+			return fmt.Errorf("category %q: %w", c.Name, err)
+		}
+		progress.Created()
+	}
+	progress.Done()
+	return nil
+}
+
+// seedProducts создает недостающие товары; все товары опубликованы и доступны для заказа
+func seedProducts(ctx context.Context, repo repository.ProductRepository, gen *seed.Generator) error {
+	progress := seed.NewProgress("products", gen.Scale().Products)
+	for i := 0; i < gen.Scale().Products; i++ {
+		p := gen.Product(i)
+		_, err := repo.GetByID(ctx, p.ID)
+		if err == nil {
+			progress.Skipped()
+			continue
+		}
+		if !errors.Is(err, repository.ErrProductNotFound) {
+			return err
+		}
+
+		stock := p.Stock
+		product := &entity.Product{
+			ID:          p.ID,
+			Name:        p.Name,
+			Description: p.Description,
+			Price:       p.Price,
+			CategoryID:  p.CategoryID,
+			Status:      entity.ProductStatusPublished,
+			Stock:       &stock,
+			CreatedAt:   p.CreatedAt,
+		}
+		if err := repo.Create(ctx, product); err != nil {
+			return fmt.Errorf("product %q: %w", p.Name, err)
+		}
+		progress.Created()
+	}
+	progress.Done()
+	return nil
+}
+
+// connectDB подключается к PostgreSQL каталога без логирования каждого SQL запроса
+func connectDB(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	dsn := fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+		cfg.Host, cfg.User, cfg.Password, cfg.DBName, cfg.Port, cfg.SSLMode,
+	)
+	return gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Warn)})
+}
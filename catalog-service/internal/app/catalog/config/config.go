@@ -4,16 +4,31 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
+
+	"augustberries/pkg/httpmw"
+	"augustberries/pkg/redis"
 )
 
 // Config содержит все настройки приложения Catalog Service
 // Включает конфигурацию для HTTP сервера, PostgreSQL, Redis, Kafka и JWT
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	Kafka    KafkaConfig
-	JWT      JWTConfig
+	Server      ServerConfig
+	CORS        httpmw.CORSConfig
+	Compression httpmw.CompressConfig
+	Database    DatabaseConfig
+	Redis       RedisConfig
+	Kafka       KafkaConfig
+	JWT         JWTConfig
+	Quote       QuoteConfig
+	Prices      PriceScheduleConfig
+	Locale      LocaleConfig
+	Search      SearchConfig
+	Quota       QuotaConfig
+	Cache       CacheConfig
+	Feeds       FeedConfig
+	Counts      CategoryCountsConfig
+	Stream      StreamConfig
 }
 
 // ServerConfig - настройки HTTP сервера
@@ -36,23 +51,101 @@ type DatabaseConfig struct {
 // RedisConfig - настройки подключения к Redis для кеширования
 // Используется для кеширования списка категорий
 type RedisConfig struct {
-	Host     string // Хост Redis
-	Port     string // Порт Redis
-	Password string // Пароль Redis (опционально)
-	DB       int    // Номер БД Redis (0-15)
+	Host       string   // Хост Redis
+	Port       string   // Порт Redis
+	Password   string   // Пароль Redis (опционально)
+	DB         int      // Номер БД Redis (0-15)
+	Mode       string   // Режим подключения: standalone, sentinel или cluster
+	Addrs      []string // Адреса Sentinel или узлов кластера (по умолчанию Host:Port)
+	MasterName string   // Имя master для Sentinel
+	TLS        bool     // Подключаться по TLS
 }
 
 // KafkaConfig - настройки Kafka для отправки событий
 // События отправляются при изменении товаров (создание/обновление/удаление)
 type KafkaConfig struct {
-	Brokers []string // Список брокеров Kafka (формат: host:port)
-	Topic   string   // Топик для событий PRODUCT_CREATED, PRODUCT_UPDATED, PRODUCT_DELETED
+	Brokers     []string      // Список брокеров Kafka (формат: host:port)
+	Topic       string        // Топик для событий PRODUCT_*, PRICE_CHANGED и CATEGORY_*
+	Compression string        // Кодек сжатия: none, gzip, snappy, lz4, zstd
+	BatchSize   int           // Максимум сообщений в батче
+	BatchBytes  int64         // Максимальный размер батча в байтах
+	Linger      time.Duration // Время ожидания заполнения батча перед отправкой
+	Acks        string        // Уровень подтверждения записи: none, one, all
+	Idempotent  bool          // Идемпотентный режим (acks=all, порядок по ключу)
 }
 
 // JWTConfig - настройки для проверки JWT токенов
 // Используется для аутентификации запросов от других сервисов
 type JWTConfig struct {
-	Secret string // Секретный ключ для проверки JWT токенов (должен совпадать с Auth Service)
+	Secret   string // Секретный ключ для проверки JWT токенов (должен совпадать с Auth Service)
+	Issuer   string // Ожидаемый iss (JWT_ISSUER Auth Service), пустой - не проверяется
+	Audience string // Ожидаемый aud (JWT_AUDIENCE Auth Service), пустой - не проверяется
+	// InternalToken - токен внутреннего API /internal для других сервисов, пустой - внутренний API отключен
+	InternalToken string
+}
+
+// QuoteConfig - настройки подписанных ценовых котировок
+// Секрет должен совпадать с Orders Service, который проверяет котировки при создании заказа
+type QuoteConfig struct {
+	Secret string        // Ключ HMAC подписи котировок
+	TTL    time.Duration // Срок действия котировки
+}
+
+// PriceScheduleConfig - настройки фоновой задачи запланированных цен
+type PriceScheduleConfig struct {
+	Cron string // Расписание проверки наступивших цен (формат robfig/cron)
+}
+
+// LocaleConfig - настройки локализации товаров
+type LocaleConfig struct {
+	Default string // Язык основного названия и описания товаров; переводы хранятся для остальных языков
+}
+
+// SearchConfig - настройки поискового индекса товаров в OpenSearch
+// Пустой URL отключает индекс: поиск выполняется в PostgreSQL
+type SearchConfig struct {
+	URL      string        // Адрес OpenSearch (http://host:9200)
+	Index    string        // Имя alias индекса товаров
+	Username string        // Basic auth (опционально)
+	Password string        // Basic auth (опционально)
+	Timeout  time.Duration // Таймаут запроса к OpenSearch, после него поиск уходит в PostgreSQL
+	GroupID  string        // Consumer group индексатора событий product_events
+}
+
+// QuotaConfig - квоты запросов пользователей и API ключей (pkg/quota)
+// Индивидуальные квоты задаются через /admin/quotas, 0 - без ограничения
+type QuotaConfig struct {
+	Daily         int64         // Запросов в сутки по умолчанию
+	Monthly       int64         // Запросов в месяц по умолчанию
+	CacheTTL      time.Duration // Время кеширования индивидуальной квоты в памяти процесса
+	FlushInterval time.Duration // Период сохранения счетчиков из Redis в PostgreSQL
+}
+
+// CacheConfig - прогрев кеша категорий и популярных товаров
+type CacheConfig struct {
+	WarmCron    string        // Расписание обновления кеша (формат robfig/cron)
+	TopProducts int           // Число популярных товаров магазина, загружаемых в кеш
+	LockTTL     time.Duration // Время, на которое прогрев закрепляется за одним экземпляром (меньше интервала расписания)
+}
+
+// FeedConfig - импорт товаров из фидов поставщиков
+type FeedConfig struct {
+	Cron         string        // Расписание проверки фидов, которым пора обновиться (формат robfig/cron)
+	LockTTL      time.Duration // Время, на которое запуск закрепляется за одним экземпляром (меньше интервала расписания)
+	FetchTimeout time.Duration // Таймаут загрузки одного фида
+	MaxBytes     int64         // Максимальный размер фида
+}
+
+// CategoryCountsConfig - пересчет числа товаров в категориях
+type CategoryCountsConfig struct {
+	Cron    string        // Расписание пересчета (формат robfig/cron)
+	LockTTL time.Duration // Время, на которое пересчет закрепляется за одним экземпляром (меньше интервала расписания)
+}
+
+// StreamConfig - выгрузка каталога в хранилище аналитики (GET /internal/products/stream)
+type StreamConfig struct {
+	RateLimit  int           // Выгрузок одного магазина за RateWindow, 0 - без ограничения
+	RateWindow time.Duration // Окно ограничения частоты выгрузок
 }
 
 // Load загружает конфигурацию из переменных окружения
@@ -64,11 +157,148 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid REDIS_DB value: %w", err)
 	}
 
+	quoteTTL, err := time.ParseDuration(getEnv("PRICE_QUOTE_TTL", "15m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PRICE_QUOTE_TTL value: %w", err)
+	}
+
+	// Настройки Kafka producer: по умолчанию snappy, небольшие батчи и подтверждение всеми репликами
+	kafkaBatchSize, err := strconv.Atoi(getEnv("KAFKA_BATCH_SIZE", "100"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAFKA_BATCH_SIZE value: %w", err)
+	}
+
+	kafkaBatchBytes, err := strconv.ParseInt(getEnv("KAFKA_BATCH_BYTES", "1048576"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAFKA_BATCH_BYTES value: %w", err)
+	}
+
+	kafkaLinger, err := time.ParseDuration(getEnv("KAFKA_LINGER", "10ms"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAFKA_LINGER value: %w", err)
+	}
+
+	kafkaIdempotent, err := strconv.ParseBool(getEnv("KAFKA_IDEMPOTENT", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAFKA_IDEMPOTENT value: %w", err)
+	}
+
+	cacheWarmLockTTL, err := time.ParseDuration(getEnv("CACHE_WARM_LOCK_TTL", "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CACHE_WARM_LOCK_TTL value: %w", err)
+	}
+
+	searchTimeout, err := time.ParseDuration(getEnv("OPENSEARCH_TIMEOUT", "2s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OPENSEARCH_TIMEOUT value: %w", err)
+	}
+
+	quotaDaily, err := strconv.ParseInt(getEnv("QUOTA_DAILY", "10000"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid QUOTA_DAILY value: %w", err)
+	}
+
+	quotaMonthly, err := strconv.ParseInt(getEnv("QUOTA_MONTHLY", "200000"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid QUOTA_MONTHLY value: %w", err)
+	}
+
+	quotaCacheTTL, err := time.ParseDuration(getEnv("QUOTA_CACHE_TTL", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid QUOTA_CACHE_TTL value: %w", err)
+	}
+
+	quotaFlushInterval, err := time.ParseDuration(getEnv("QUOTA_FLUSH_INTERVAL", "1m"))
+	if err != nil || quotaFlushInterval <= 0 {
+		return nil, fmt.Errorf("invalid QUOTA_FLUSH_INTERVAL value: %q", getEnv("QUOTA_FLUSH_INTERVAL", "1m"))
+	}
+
+	cacheTopProducts, err := strconv.Atoi(getEnv("CACHE_WARM_TOP_PRODUCTS", "100"))
+	if err != nil || cacheTopProducts < 0 {
+		return nil, fmt.Errorf("invalid CACHE_WARM_TOP_PRODUCTS value: %q", getEnv("CACHE_WARM_TOP_PRODUCTS", "100"))
+	}
+
+	feedLockTTL, err := time.ParseDuration(getEnv("SUPPLIER_FEEDS_LOCK_TTL", "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SUPPLIER_FEEDS_LOCK_TTL value: %w", err)
+	}
+
+	countsLockTTL, err := time.ParseDuration(getEnv("CATEGORY_COUNTS_LOCK_TTL", "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CATEGORY_COUNTS_LOCK_TTL value: %w", err)
+	}
+
+	feedFetchTimeout, err := time.ParseDuration(getEnv("SUPPLIER_FEEDS_FETCH_TIMEOUT", "5m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SUPPLIER_FEEDS_FETCH_TIMEOUT value: %w", err)
+	}
+
+	feedMaxBytes, err := strconv.ParseInt(getEnv("SUPPLIER_FEEDS_MAX_BYTES", "104857600"), 10, 64)
+	if err != nil || feedMaxBytes < 0 {
+		return nil, fmt.Errorf("invalid SUPPLIER_FEEDS_MAX_BYTES value: %q", getEnv("SUPPLIER_FEEDS_MAX_BYTES", "104857600"))
+	}
+
+	streamRateLimit, err := strconv.Atoi(getEnv("PRODUCT_STREAM_RATE_LIMIT", "60"))
+	if err != nil || streamRateLimit < 0 {
+		return nil, fmt.Errorf("invalid PRODUCT_STREAM_RATE_LIMIT value: %q", getEnv("PRODUCT_STREAM_RATE_LIMIT", "60"))
+	}
+
+	streamRateWindow, err := time.ParseDuration(getEnv("PRODUCT_STREAM_RATE_WINDOW", "1m"))
+	if err != nil || streamRateWindow <= 0 {
+		return nil, fmt.Errorf("invalid PRODUCT_STREAM_RATE_WINDOW value: %q", getEnv("PRODUCT_STREAM_RATE_WINDOW", "1m"))
+	}
+
+	// CORS для браузерных клиентов; credentials нужны SPA с токенами в cookie и требуют явного списка origin
+	corsMaxAge, err := time.ParseDuration(getEnv("CORS_MAX_AGE", "5m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CORS_MAX_AGE value: %w", err)
+	}
+
+	corsCredentials, err := strconv.ParseBool(getEnv("CORS_ALLOW_CREDENTIALS", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CORS_ALLOW_CREDENTIALS value: %w", err)
+	}
+
+	cors := httpmw.CORSConfig{
+		AllowedOrigins:   httpmw.ParseList(getEnv("CORS_ALLOWED_ORIGINS", "*")),
+		AllowedMethods:   httpmw.ParseList(getEnv("CORS_ALLOWED_METHODS", "")),
+		AllowedHeaders:   httpmw.ParseList(getEnv("CORS_ALLOWED_HEADERS", "")),
+		ExposedHeaders:   httpmw.ParseList(getEnv("CORS_EXPOSED_HEADERS", "")),
+		AllowCredentials: corsCredentials,
+		MaxAge:           corsMaxAge,
+	}
+	if err := cors.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Сжатие ответов больших списков (gzip, zstd) по Accept-Encoding
+	compressionEnabled, err := strconv.ParseBool(getEnv("RESPONSE_COMPRESSION", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RESPONSE_COMPRESSION value: %w", err)
+	}
+
+	compressionMinSize, err := strconv.Atoi(getEnv("RESPONSE_COMPRESSION_MIN_SIZE", strconv.Itoa(httpmw.DefaultMinSize)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RESPONSE_COMPRESSION_MIN_SIZE value: %w", err)
+	}
+
+	compression := httpmw.CompressConfig{
+		Enabled:      compressionEnabled,
+		Encodings:    httpmw.ParseList(getEnv("RESPONSE_COMPRESSION_ENCODINGS", "zstd,gzip")),
+		MinSize:      compressionMinSize,
+		ContentTypes: httpmw.ParseList(getEnv("RESPONSE_COMPRESSION_CONTENT_TYPES", "")),
+	}
+	if err := compression.Validate(); err != nil {
+		return nil, err
+	}
+
 	return &Config{
 		Server: ServerConfig{
 			Host: getEnv("SERVER_HOST", "0.0.0.0"),
 			Port: getEnv("SERVER_PORT", "8081"),
 		},
+		CORS:        cors,
+		Compression: compression,
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
 			Port:     getEnv("DB_PORT", "5432"),
@@ -78,19 +308,76 @@ func Load() (*Config, error) {
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		},
 		Redis: RedisConfig{
-			Host:     getEnv("REDIS_HOST", "localhost"),
-			Port:     getEnv("REDIS_PORT", "6379"),
-			Password: getEnv("REDIS_PASSWORD", ""),
-			DB:       redisDB,
+			Host:       getEnv("REDIS_HOST", "localhost"),
+			Port:       getEnv("REDIS_PORT", "6379"),
+			Password:   getEnv("REDIS_PASSWORD", ""),
+			DB:         redisDB,
+			Mode:       getEnv("REDIS_MODE", redis.ModeStandalone),
+			Addrs:      redis.ParseAddrs(getEnv("REDIS_ADDRS", ""), getEnv("REDIS_HOST", "localhost")+":"+getEnv("REDIS_PORT", "6379")),
+			MasterName: getEnv("REDIS_MASTER_NAME", ""),
+			TLS:        getEnv("REDIS_TLS", "false") == "true",
 		},
 		Kafka: KafkaConfig{
 			// ИСПРАВЛЕНО: Топик должен быть product_events согласно заданию
-			Brokers: []string{getEnv("KAFKA_BROKERS", "localhost:9092")},
-			Topic:   getEnv("KAFKA_TOPIC", "product_events"),
+			Brokers:     []string{getEnv("KAFKA_BROKERS", "localhost:9092")},
+			Topic:       getEnv("KAFKA_TOPIC", "product_events"),
+			Compression: getEnv("KAFKA_COMPRESSION", "snappy"),
+			BatchSize:   kafkaBatchSize,
+			BatchBytes:  kafkaBatchBytes,
+			Linger:      kafkaLinger,
+			Acks:        getEnv("KAFKA_ACKS", "all"),
+			Idempotent:  kafkaIdempotent,
 		},
 		JWT: JWTConfig{
 			// JWT Secret должен совпадать с Auth Service для валидации токенов
-			Secret: getEnv("JWT_SECRET", "your-secret-key-change-this-in-production"),
+			Secret:   getEnv("JWT_SECRET", "your-secret-key-change-this-in-production"),
+			Issuer:   getEnv("JWT_ISSUER", ""),
+			Audience: getEnv("JWT_AUDIENCE", ""),
+			// Должен совпадать с CATALOG_INTERNAL_TOKEN Reviews Service
+			InternalToken: getEnv("INTERNAL_API_TOKEN", ""),
+		},
+		Quote: QuoteConfig{
+			Secret: getEnv("PRICE_QUOTE_SECRET", "your-quote-secret-change-this-in-production"),
+			TTL:    quoteTTL,
+		},
+		Prices: PriceScheduleConfig{
+			Cron: getEnv("PRICE_SCHEDULE_CRON", "@every 1m"),
+		},
+		Locale: LocaleConfig{
+			Default: getEnv("CATALOG_DEFAULT_LOCALE", "ru"),
+		},
+		Search: SearchConfig{
+			URL:      getEnv("OPENSEARCH_URL", ""),
+			Index:    getEnv("OPENSEARCH_INDEX", "products"),
+			Username: getEnv("OPENSEARCH_USERNAME", ""),
+			Password: getEnv("OPENSEARCH_PASSWORD", ""),
+			Timeout:  searchTimeout,
+			GroupID:  getEnv("KAFKA_SEARCH_INDEXER_GROUP_ID", "catalog-search-indexer"),
+		},
+		Quota: QuotaConfig{
+			Daily:         quotaDaily,
+			Monthly:       quotaMonthly,
+			CacheTTL:      quotaCacheTTL,
+			FlushInterval: quotaFlushInterval,
+		},
+		Cache: CacheConfig{
+			WarmCron:    getEnv("CACHE_WARM_CRON", "@every 5m"),
+			TopProducts: cacheTopProducts,
+			LockTTL:     cacheWarmLockTTL,
+		},
+		Feeds: FeedConfig{
+			Cron:         getEnv("SUPPLIER_FEEDS_CRON", "@every 5m"),
+			LockTTL:      feedLockTTL,
+			FetchTimeout: feedFetchTimeout,
+			MaxBytes:     feedMaxBytes,
+		},
+		Counts: CategoryCountsConfig{
+			Cron:    getEnv("CATEGORY_COUNTS_CRON", "@every 5m"),
+			LockTTL: countsLockTTL,
+		},
+		Stream: StreamConfig{
+			RateLimit:  streamRateLimit,
+			RateWindow: streamRateWindow,
 		},
 	}, nil
 }
@@ -113,6 +400,19 @@ func (c *RedisConfig) Address() string {
 	return c.Host + ":" + c.Port
 }
 
+// ClientConfig возвращает настройки клиента для pkg/redis
+func (c *RedisConfig) ClientConfig() redis.Config {
+	return redis.Config{
+		Mode:       c.Mode,
+		Addrs:      c.Addrs,
+		Password:   c.Password,
+		DB:         c.DB,
+		MasterName: c.MasterName,
+		TLS:        c.TLS,
+		Service:    "catalog-service",
+	}
+}
+
 // getEnv получает значение переменной окружения или возвращает значение по умолчанию
 // Используется для гибкой конфигурации через environment variables
 func getEnv(key, defaultValue string) string {
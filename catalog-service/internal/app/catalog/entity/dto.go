@@ -1,6 +1,16 @@
 package entity
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"augustberries/pkg/money"
+	"augustberries/pkg/pagination"
+	"augustberries/pkg/patch"
+	"augustberries/pkg/quote"
+	"augustberries/pkg/units"
+
+	"github.com/google/uuid"
+)
 
 // CreateCategoryRequest - запрос на создание категории
 type CreateCategoryRequest struct {
@@ -14,18 +24,253 @@ type UpdateCategoryRequest struct {
 
 // CreateProductRequest - запрос на создание товара
 type CreateProductRequest struct {
-	Name        string    `json:"name" validate:"required,min=2,max=200"`
-	Description string    `json:"description" validate:"required,min=10,max=2000"`
-	Price       float64   `json:"price" validate:"required,gt=0"`
-	CategoryID  uuid.UUID `json:"category_id" validate:"required"`
+	Name        string        `json:"name" validate:"required,min=2,max=200"`
+	Description string        `json:"description" validate:"required,min=10,max=2000"`
+	Price       money.Amount  `json:"price" validate:"required,money"`
+	CategoryID  uuid.UUID     `json:"category_id" validate:"required"`
+	BrandID     *uuid.UUID    `json:"brand_id,omitempty"`
+	SupplierID  *uuid.UUID    `json:"supplier_id,omitempty"`
+	Stock       *int          `json:"stock,omitempty" validate:"omitempty,gte=0"`      // Без stock остаток не отслеживается
+	CostPrice   *money.Amount `json:"cost_price,omitempty" validate:"omitempty,money"` // Закупочная цена (видна только manager и admin)
+	// Unit - единица измерения (по умолчанию piece), QuantityStep - шаг количества (по умолчанию 1)
+	Unit         units.Unit     `json:"unit,omitempty" validate:"omitempty,oneof=piece kg liter"`
+	QuantityStep units.Quantity `json:"quantity_step,omitempty" validate:"gte=0"`
+	// Ограничения количества в заказе; без поля ограничения нет
+	MinQty           *units.Quantity `json:"min_qty,omitempty" validate:"omitempty,gt=0"`
+	MaxQty           *units.Quantity `json:"max_qty,omitempty" validate:"omitempty,gt=0"`
+	PerCustomerLimit *units.Quantity `json:"per_customer_limit,omitempty" validate:"omitempty,gt=0"` // Сколько покупатель может заказать всего
 }
 
-// UpdateProductRequest - запрос на обновление товара
+// UpdateProductRequest - частичное обновление товара (JSON Merge Patch)
+// Отсутствующие поля не меняются; null снимает бренд и поставщика, отключает учет остатка и удаляет закупочную цену
 type UpdateProductRequest struct {
-	Name        string    `json:"name" validate:"omitempty,min=2,max=200"`
-	Description string    `json:"description" validate:"omitempty,min=10,max=2000"`
-	Price       float64   `json:"price" validate:"omitempty,gt=0"`
-	CategoryID  uuid.UUID `json:"category_id" validate:"omitempty"`
+	Name         patch.Field[string]          `json:"name,omitzero" validate:"omitempty,min=2,max=200"`
+	Description  patch.Field[string]          `json:"description,omitzero" validate:"omitempty,min=10,max=2000"`
+	Price        patch.Field[money.Amount]    `json:"price,omitzero" validate:"omitempty,money"` // 0 - бесплатный товар
+	CategoryID   patch.Field[uuid.UUID]       `json:"category_id,omitzero"`
+	BrandID      patch.Nullable[uuid.UUID]    `json:"brand_id,omitzero"`
+	SupplierID   patch.Nullable[uuid.UUID]    `json:"supplier_id,omitzero"`
+	Stock        patch.Nullable[int]          `json:"stock,omitzero" validate:"omitempty,gte=0"`
+	CostPrice    patch.Nullable[money.Amount] `json:"cost_price,omitzero" validate:"omitempty,money"` // Закупочная цена (видна только manager и admin)
+	Unit         patch.Field[units.Unit]      `json:"unit,omitzero" validate:"omitempty,oneof=piece kg liter"`
+	QuantityStep patch.Field[units.Quantity]  `json:"quantity_step,omitzero" validate:"omitempty,gt=0"`
+	// null снимает ограничение количества
+	MinQty           patch.Nullable[units.Quantity] `json:"min_qty,omitzero" validate:"omitempty,gt=0"`
+	MaxQty           patch.Nullable[units.Quantity] `json:"max_qty,omitzero" validate:"omitempty,gt=0"`
+	PerCustomerLimit patch.Nullable[units.Quantity] `json:"per_customer_limit,omitzero" validate:"omitempty,gt=0"`
+}
+
+// ProductAvailability - цена, статус и остаток товара для проверки при оформлении заказа
+// Легче полного ответа GET /products/:id: без тегов, брендов и переводов.
+// Название, описание и категория сохраняются Orders Service в снимке позиции заказа
+type ProductAvailability struct {
+	ID           uuid.UUID      `json:"id"`
+	Name         string         `json:"name"`
+	Description  string         `json:"description"`
+	Price        money.Amount   `json:"price"`
+	CategoryID   uuid.UUID      `json:"category_id"`
+	CategoryName string         `json:"category_name"`
+	Status       ProductStatus  `json:"status"`
+	Stock        *int           `json:"stock,omitempty"` // nil - остаток не отслеживается
+	Unit         units.Unit     `json:"unit"`
+	QuantityStep units.Quantity `json:"quantity_step"` // Количество в заказе должно быть кратно шагу
+	Available    bool           `json:"available"`     // Опубликован и есть на складе
+
+	MinQty           *units.Quantity `json:"min_qty,omitempty"`
+	MaxQty           *units.Quantity `json:"max_qty,omitempty"`
+	PerCustomerLimit *units.Quantity `json:"per_customer_limit,omitempty"`
+}
+
+// ProductAvailabilityResponse - ответ GET /products/availability
+// Missing - запрошенные ID, которых нет в каталоге (или которые скрыты от пользователя)
+type ProductAvailabilityResponse struct {
+	Products []ProductAvailability `json:"products"`
+	Missing  []uuid.UUID           `json:"missing"`
+}
+
+// ProductBatchResponse - ответ GET /products/batch: товары по ID для корзин и избранного витрины
+// Missing - запрошенные ID, которых нет в каталоге (или которые скрыты от пользователя)
+type ProductBatchResponse struct {
+	Products map[uuid.UUID]*Product `json:"products"`
+	Missing  []uuid.UUID            `json:"missing"`
+}
+
+// ProductRating - денормализованная оценка товара из Reviews Service
+type ProductRating struct {
+	ProductID   uuid.UUID `json:"product_id" validate:"required"`
+	RatingAvg   float64   `json:"rating_avg" validate:"gte=0,lte=5"`
+	RatingCount int       `json:"rating_count" validate:"gte=0"`
+}
+
+// ProductRatingsResponse - ответ GET /internal/products/ratings: оценки всех товаров магазина
+type ProductRatingsResponse struct {
+	Ratings []ProductRating `json:"ratings"`
+}
+
+// UpdateRatingsRequest - исправление оценок товаров сверкой Reviews Service
+type UpdateRatingsRequest struct {
+	Ratings []ProductRating `json:"ratings" validate:"required,min=1,max=1000,dive"`
+}
+
+// DashboardSummary - сводка каталога магазина для внутренней панели (GET /admin/summary)
+type DashboardSummary struct {
+	Products         int64                   `json:"products"`
+	ProductsByStatus map[ProductStatus]int64 `json:"products_by_status"`
+	Categories       int64                   `json:"categories"`
+	Cache            CacheSummary            `json:"cache"`
+	GeneratedAt      time.Time               `json:"generated_at"`
+}
+
+// CacheSummary - попадания в Redis кеш этого экземпляра сервиса с момента запуска
+type CacheSummary struct {
+	Hits    float64 `json:"hits"`
+	Misses  float64 `json:"misses"`
+	HitRate float64 `json:"hit_rate"` // От 0 до 1, 0 - обращений к кешу не было
+}
+
+// ProductFilter - фильтры списка товаров (query параметры GET /products)
+type ProductFilter struct {
+	CategoryID *uuid.UUID
+	BrandID    *uuid.UUID
+	SupplierID *uuid.UUID
+	Status     ProductStatus // Пустой статус - товары во всех статусах
+	Tags       []string      // Slug тегов: товар должен иметь все перечисленные теги
+}
+
+// CreateBrandRequest - запрос на создание бренда
+type CreateBrandRequest struct {
+	Name        string `json:"name" validate:"required,min=2,max=100"`
+	Description string `json:"description" validate:"omitempty,max=2000"`
+}
+
+// UpdateBrandRequest - частичное обновление бренда (JSON Merge Patch), null удаляет описание
+type UpdateBrandRequest struct {
+	Name        patch.Field[string]    `json:"name,omitzero" validate:"omitempty,min=2,max=100"`
+	Description patch.Nullable[string] `json:"description,omitzero" validate:"omitempty,max=2000"`
+}
+
+// CreateScheduledPriceRequest - запрос на планирование цены товара
+// Без effective_to цена меняется навсегда, с ним - на время распродажи
+type CreateScheduledPriceRequest struct {
+	Price         money.Amount `json:"price" validate:"required,money"`
+	EffectiveFrom time.Time    `json:"effective_from" validate:"required"`
+	EffectiveTo   *time.Time   `json:"effective_to,omitempty"`
+}
+
+// BulkPriceItem - новая цена товара в массовом изменении цен
+type BulkPriceItem struct {
+	ProductID uuid.UUID    `json:"product_id" validate:"required"`
+	Price     money.Amount `json:"price" validate:"required,money"`
+}
+
+// BulkPriceAdjustment - изменение цен всех товаров категории на процент (10 - подорожание на 10%, -15 - скидка 15%)
+type BulkPriceAdjustment struct {
+	CategoryID uuid.UUID `json:"category_id" validate:"required"`
+	Percent    float64   `json:"percent" validate:"required,gt=-100,lte=1000"`
+}
+
+// BulkPriceRequest - запрос POST /admin/products/bulk-price
+// Явная цена товара важнее процента его категории; dry_run только считает изменения
+type BulkPriceRequest struct {
+	Items      []BulkPriceItem       `json:"items" validate:"max=1000,dive"`
+	Categories []BulkPriceAdjustment `json:"categories" validate:"max=50,dive"`
+	DryRun     bool                  `json:"dry_run"`
+}
+
+// PriceChange - изменение цены товара в массовом изменении цен
+type PriceChange struct {
+	ProductID uuid.UUID    `json:"product_id"`
+	Name      string       `json:"name"`
+	OldPrice  money.Amount `json:"old_price"`
+	NewPrice  money.Amount `json:"new_price"`
+}
+
+// BulkPriceResponse - результат массового изменения цен (или расчет для dry_run)
+type BulkPriceResponse struct {
+	DryRun    bool          `json:"dry_run"`
+	Changes   []PriceChange `json:"changes"`
+	Unchanged int           `json:"unchanged"`         // Товары, цена которых уже равна новой
+	Missing   []uuid.UUID   `json:"missing,omitempty"` // Товары из items, которых нет в магазине
+}
+
+// ScheduledPriceListResponse - ответ со списком запланированных цен товара
+type ScheduledPriceListResponse struct {
+	ScheduledPrices []ScheduledPrice `json:"scheduled_prices"`
+	Total           int              `json:"total"`
+}
+
+// CreateTagRequest - запрос на создание тега
+type CreateTagRequest struct {
+	Name string `json:"name" validate:"required,min=2,max=100"`
+}
+
+// UpdateTagRequest - запрос на обновление тега
+type UpdateTagRequest struct {
+	Name string `json:"name" validate:"required,min=2,max=100"`
+}
+
+// SetTranslationRequest - запрос на создание или замену перевода товара
+type SetTranslationRequest struct {
+	Name        string `json:"name" validate:"required,min=1,max=255"`
+	Description string `json:"description"`
+}
+
+// TranslationListResponse - переводы товара
+type TranslationListResponse struct {
+	DefaultLocale string               `json:"default_locale"` // Язык основного названия и описания товара
+	Translations  []ProductTranslation `json:"translations"`
+	Total         int                  `json:"total"`
+}
+
+// SetProductTagsRequest - запрос на замену тегов товара
+// Пустой список снимает с товара все теги
+type SetProductTagsRequest struct {
+	TagIDs []uuid.UUID `json:"tag_ids" validate:"max=50,uuid_list"`
+}
+
+// CreateSupplierRequest - запрос на создание поставщика
+type CreateSupplierRequest struct {
+	Name         string `json:"name" validate:"required,min=2,max=200"`
+	ContactEmail string `json:"contact_email" validate:"omitempty,email"`
+	Phone        string `json:"phone" validate:"omitempty,max=50"`
+}
+
+// UpdateSupplierRequest - частичное обновление поставщика (JSON Merge Patch), null удаляет контакты
+type UpdateSupplierRequest struct {
+	Name         patch.Field[string]    `json:"name,omitzero" validate:"omitempty,min=2,max=200"`
+	ContactEmail patch.Nullable[string] `json:"contact_email,omitzero" validate:"omitempty,email"`
+	Phone        patch.Nullable[string] `json:"phone,omitzero" validate:"omitempty,max=50"`
+}
+
+// SupplierFeedRequest - создание или замена фида поставщика
+type SupplierFeedRequest struct {
+	SupplierID      uuid.UUID   `json:"supplier_id" validate:"required"`
+	URL             string      `json:"url" validate:"required,url,max=2000"`
+	Format          FeedFormat  `json:"format" validate:"required,oneof=csv xml"`
+	Mapping         FeedMapping `json:"mapping" validate:"required"`
+	CategoryID      uuid.UUID   `json:"category_id" validate:"required"`             // Категория новых товаров фида
+	IntervalMinutes int         `json:"interval_minutes" validate:"gte=0,max=10080"` // 0 - по умолчанию раз в час
+	Enabled         *bool       `json:"enabled"`                                     // nil - включен
+}
+
+// QuoteItemRequest - позиция для подтверждения цены
+type QuoteItemRequest struct {
+	ProductID uuid.UUID      `json:"product_id" validate:"required"`
+	Quantity  units.Quantity `json:"quantity" validate:"required,gt=0"` // Кратно шагу товара (1.5 кг при шаге 0.5)
+}
+
+// CreateQuoteRequest - запрос ценовой котировки (корзина перед оформлением заказа)
+type CreateQuoteRequest struct {
+	Items []QuoteItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+// PriceQuoteResponse - подписанная котировка цен
+// Token передается в Orders Service при оформлении заказа, остальные поля - для отображения клиенту
+type PriceQuoteResponse struct {
+	Token     string       `json:"token"`
+	Items     []quote.Item `json:"items"`
+	Currency  string       `json:"currency"`
+	ExpiresAt time.Time    `json:"expires_at"`
 }
 
 // ErrorResponse - стандартный ответ об ошибке
@@ -40,10 +285,95 @@ type SuccessResponse struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
-// ProductListResponse - ответ со списком товаров
+// ProductListResponse - ответ со страницей товаров
 type ProductListResponse struct {
-	Products []ProductWithCategory `json:"products"`
-	Total    int                   `json:"total"`
+	Products  []ProductWithCategory `json:"products"`
+	TagFacets []TagFacet            `json:"tag_facets"`       // Теги всех найденных товаров с количеством
+	Facets    *ProductFacets        `json:"facets,omitempty"` // Все фасеты, если запрошены (?facets=true)
+	pagination.Meta
+}
+
+// ProductSearchQuery - полнотекстовый поиск опубликованных товаров магазина
+type ProductSearchQuery struct {
+	Text       string     // Строка поиска по названию и описанию
+	CategoryID *uuid.UUID // Ограничить поиск категорией
+	Offset     int
+	Limit      int
+}
+
+// ProductSearchResult - найденная страница товаров в порядке релевантности
+type ProductSearchResult struct {
+	Products []Product
+	Total    int
+	Source   string // SearchSourceOpenSearch или SearchSourcePostgres
+}
+
+// Источники результатов поиска
+const (
+	SearchSourceOpenSearch = "opensearch"
+	SearchSourcePostgres   = "postgres"
+)
+
+// ProductSearchResponse - ответ GET /products/search
+type ProductSearchResponse struct {
+	Products []Product `json:"products"`
+	Source   string    `json:"source"` // Откуда получены результаты: opensearch или postgres (резервный поиск)
+	pagination.Meta
+}
+
+// ReindexResponse - ответ на запуск перестроения поискового индекса
+type ReindexResponse struct {
+	Status string `json:"status"`
+}
+
+// TagFacet - тег и число товаров с ним в результатах поиска
+type TagFacet struct {
+	Slug  string `json:"slug"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// ProductFacets - счетчики для боковой панели фильтров каталога
+// Считаются по товарам, подходящим под текущие фильтры
+type ProductFacets struct {
+	Categories  []FacetCount  `json:"categories"`
+	Brands      []FacetCount  `json:"brands"`
+	Tags        []TagFacet    `json:"tags"`
+	PriceRanges []PriceFacet  `json:"price_ranges"`
+	Ratings     []RatingFacet `json:"ratings"`
+}
+
+// FacetCount - значение фильтра (категория, бренд) и число товаров с ним
+type FacetCount struct {
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Count int64     `json:"count"`
+}
+
+// PriceFacet - ценовой диапазон [From, To) и число товаров в нем
+// To отсутствует у последнего диапазона ("от 500")
+type PriceFacet struct {
+	From  money.Amount  `json:"from"`
+	To    *money.Amount `json:"to,omitempty"`
+	Count int64         `json:"count"`
+}
+
+// RatingFacet - число товаров со средней оценкой не ниже MinRating ("4 звезды и выше")
+type RatingFacet struct {
+	MinRating int   `json:"min_rating"`
+	Count     int64 `json:"count"`
+}
+
+// AuditListResponse - ответ с историей изменений сущности
+type AuditListResponse struct {
+	Entries []AuditEntry `json:"entries"`
+	Total   int          `json:"total"`
+}
+
+// TagListResponse - ответ со списком тегов
+type TagListResponse struct {
+	Tags  []Tag `json:"tags"`
+	Total int   `json:"total"`
 }
 
 // CategoryListResponse - ответ со списком категорий
@@ -51,3 +381,74 @@ type CategoryListResponse struct {
 	Categories []Category `json:"categories"`
 	Total      int        `json:"total"`
 }
+
+// BrandListResponse - ответ со списком брендов
+type BrandListResponse struct {
+	Brands []Brand `json:"brands"`
+	Total  int     `json:"total"`
+}
+
+// SupplierListResponse - ответ со списком поставщиков
+type SupplierListResponse struct {
+	Suppliers []Supplier `json:"suppliers"`
+	Total     int        `json:"total"`
+}
+
+// SupplierFeedListResponse - ответ со списком фидов поставщиков
+type SupplierFeedListResponse struct {
+	Feeds []SupplierFeed `json:"feeds"`
+	Total int            `json:"total"`
+}
+
+// FeedImportListResponse - история запусков импорта фида, последние первыми
+type FeedImportListResponse struct {
+	Imports []FeedImport `json:"imports"`
+	Total   int          `json:"total"`
+}
+
+// ProductComparison - ответ GET /products/compare
+// Products - колонки сравнения в порядке запроса, Attributes - строки, выровненные по Products
+type ProductComparison struct {
+	Products   []Product           `json:"products"`
+	Attributes []ComparedAttribute `json:"attributes"`
+	Missing    []uuid.UUID         `json:"missing,omitempty"` // Запрошенные ID, которых нет в каталоге (или которые скрыты от пользователя)
+}
+
+// ComparedAttribute - строка сравнения: Values[i] относится к Products[i], null - у товара нет значения
+type ComparedAttribute struct {
+	Key       string        `json:"key"`
+	Values    []interface{} `json:"values"`
+	Different bool          `json:"different"` // Значения различаются: UI может показывать только отличия
+}
+
+// ProductStreamCursor - позиция выгрузки каталога: товары упорядочены по (updated_at, id)
+// Следующая выгрузка продолжается с товаров, измененных строго позже этой позиции
+type ProductStreamCursor struct {
+	UpdatedAt time.Time `json:"updated_at"`
+	ID        uuid.UUID `json:"id"`
+}
+
+// ProductStreamQuery - параметры GET /internal/products/stream
+// Cursor - продолжение прошлой выгрузки, UpdatedSince - нижняя граница времени изменения (включительно)
+type ProductStreamQuery struct {
+	Cursor       *ProductStreamCursor
+	UpdatedSince *time.Time
+	Limit        int
+}
+
+// Типы строк NDJSON выгрузки каталога
+const (
+	ProductStreamLineProduct = "product"
+	ProductStreamLineEnd     = "end"
+)
+
+// ProductStreamLine - строка NDJSON выгрузки каталога
+// Строки product содержат товар (включая удаленные, с deleted_at) и курсор сразу после него,
+// чтобы прерванную выгрузку можно было продолжить с последней полученной строки.
+// Последняя строка end содержит курсор следующей выгрузки и признак, что изменения еще остались
+type ProductStreamLine struct {
+	Type    string   `json:"type"`
+	Cursor  string   `json:"cursor,omitempty"`
+	Product *Product `json:"product,omitempty"`
+	HasMore *bool    `json:"has_more,omitempty"`
+}
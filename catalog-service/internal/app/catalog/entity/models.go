@@ -3,14 +3,23 @@ package entity
 import (
 	"time"
 
+	"augustberries/pkg/money"
+	"augustberries/pkg/units"
+
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // Category представляет категорию товаров
 type Category struct {
 	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
-	Name      string    `json:"name" gorm:"type:varchar(255);unique;not null"`
+	TenantID  string    `json:"-" gorm:"type:varchar(64);not null;default:'default';uniqueIndex:idx_categories_tenant_name;uniqueIndex:idx_categories_tenant_slug"`
+	Name      string    `json:"name" gorm:"type:varchar(255);not null;uniqueIndex:idx_categories_tenant_name"`
+	Slug      string    `json:"slug" gorm:"type:varchar(255);not null;uniqueIndex:idx_categories_tenant_slug"` // Генерируется из названия для SEO URL
 	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	// ProductCount - опубликованные неудаленные товары категории; пересчитывается фоновой задачей (CategoryCounter)
+	ProductCount int `json:"product_count" gorm:"not null;default:0"`
 }
 
 // TableName указывает имя таблицы для GORM
@@ -18,34 +27,370 @@ func (Category) TableName() string {
 	return "categories"
 }
 
-// Product представляет товар в каталоге
-type Product struct {
+// Brand представляет бренд (торговую марку) товаров
+type Brand struct {
 	ID          uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
-	Name        string    `json:"name" gorm:"type:varchar(255);not null"`
+	TenantID    string    `json:"-" gorm:"type:varchar(64);not null;default:'default';uniqueIndex:idx_brands_tenant_name"`
+	Name        string    `json:"name" gorm:"type:varchar(255);not null;uniqueIndex:idx_brands_tenant_name"`
 	Description string    `json:"description" gorm:"type:text"`
-	Price       float64   `json:"price" gorm:"type:decimal(10,2);not null"` // Цена в базовой валюте (USD)
-	CategoryID  uuid.UUID `json:"category_id" gorm:"type:uuid;not null"`
-	Category    *Category `json:"category,omitempty" gorm:"foreignKey:CategoryID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
 	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
 }
 
+// TableName указывает имя таблицы для GORM
+func (Brand) TableName() string {
+	return "brands"
+}
+
+// Supplier представляет поставщика товаров
+// Данные поставщика видны только manager и admin
+type Supplier struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	TenantID     string    `json:"-" gorm:"type:varchar(64);not null;default:'default';uniqueIndex:idx_suppliers_tenant_name"`
+	Name         string    `json:"name" gorm:"type:varchar(255);not null;uniqueIndex:idx_suppliers_tenant_name"`
+	ContactEmail string    `json:"contact_email" gorm:"type:varchar(255)"`
+	Phone        string    `json:"phone" gorm:"type:varchar(50)"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (Supplier) TableName() string {
+	return "suppliers"
+}
+
+// FeedFormat формат фида поставщика
+type FeedFormat string
+
+const (
+	FeedFormatCSV FeedFormat = "csv" // Первая строка - заголовок с именами колонок
+	FeedFormatXML FeedFormat = "xml" // Запись - элемент Mapping.Record, поля - дочерние элементы или атрибуты
+)
+
+// FeedMapping - правила сопоставления полей фида с полями товара
+// Значение - имя колонки CSV или дочернего элемента/атрибута записи XML; пусто - поле не импортируется
+type FeedMapping struct {
+	SKU         string `json:"sku" validate:"required,max=100"`
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description,omitempty" validate:"max=100"`
+	Price       string `json:"price" validate:"required,max=100"`
+	CostPrice   string `json:"cost_price,omitempty" validate:"max=100"`
+	Stock       string `json:"stock,omitempty" validate:"max=100"`
+	Record      string `json:"record,omitempty" validate:"max=100"`  // XML: имя элемента записи (по умолчанию item)
+	Delimiter   string `json:"delimiter,omitempty" validate:"max=1"` // CSV: разделитель колонок (по умолчанию запятая)
+}
+
+// SupplierFeed - фид поставщика, который периодически загружается в каталог
+// Новые товары фида создаются черновиками в категории CategoryID, существующие обновляются по артикулу
+type SupplierFeed struct {
+	ID              uuid.UUID   `json:"id" gorm:"type:uuid;primaryKey"`
+	TenantID        string      `json:"-" gorm:"type:varchar(64);not null;default:'default';index"`
+	SupplierID      uuid.UUID   `json:"supplier_id" gorm:"type:uuid;not null"`
+	URL             string      `json:"url" gorm:"type:text;not null"`
+	Format          FeedFormat  `json:"format" gorm:"type:varchar(10);not null"`
+	Mapping         FeedMapping `json:"mapping" gorm:"type:jsonb;not null;serializer:json"`
+	CategoryID      uuid.UUID   `json:"category_id" gorm:"type:uuid;not null"`
+	IntervalMinutes int         `json:"interval_minutes" gorm:"not null;default:60"` // Период загрузки фида
+	Enabled         bool        `json:"enabled" gorm:"not null;default:true"`        // Выключенный фид загружается только вручную
+	LastRunAt       *time.Time  `json:"last_run_at,omitempty"`
+	CreatedAt       time.Time   `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (SupplierFeed) TableName() string {
+	return "supplier_feeds"
+}
+
+// Результаты запуска импорта фида
+const (
+	FeedImportSucceeded = "succeeded" // Фид загружен; ошибки отдельных строк - в Errors
+	FeedImportFailed    = "failed"    // Фид не удалось загрузить или разобрать, см. Error
+)
+
+// FeedImport - статистика одного запуска импорта фида
+type FeedImport struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	TenantID   string    `json:"-" gorm:"type:varchar(64);not null;default:'default'"`
+	FeedID     uuid.UUID `json:"feed_id" gorm:"type:uuid;not null"`
+	Status     string    `json:"status" gorm:"type:varchar(20);not null"`
+	Rows       int       `json:"rows" gorm:"not null;default:0"`                    // Прочитано записей
+	Created    int       `json:"created" gorm:"not null;default:0"`                 // Создано товаров
+	Updated    int       `json:"updated" gorm:"not null;default:0"`                 // Обновлено товаров
+	Unchanged  int       `json:"unchanged" gorm:"not null;default:0"`               // Товар уже совпадает с фидом
+	Skipped    int       `json:"skipped" gorm:"not null;default:0"`                 // Записи с ошибками
+	Errors     []string  `json:"errors" gorm:"type:jsonb;not null;serializer:json"` // Первые ошибки записей
+	Error      string    `json:"error,omitempty" gorm:"type:text;not null;default:''"`
+	StartedAt  time.Time `json:"started_at" gorm:"not null"`
+	FinishedAt time.Time `json:"finished_at" gorm:"not null"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (FeedImport) TableName() string {
+	return "supplier_feed_imports"
+}
+
+// Tag представляет тег товаров для подборок ("sale", "new-arrivals")
+// Slug генерируется из названия и используется в фильтре ?tags=
+type Tag struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	TenantID  string    `json:"-" gorm:"type:varchar(64);not null;default:'default';uniqueIndex:idx_tags_tenant_name;uniqueIndex:idx_tags_tenant_slug"`
+	Name      string    `json:"name" gorm:"type:varchar(100);not null;uniqueIndex:idx_tags_tenant_name"`
+	Slug      string    `json:"slug" gorm:"type:varchar(100);not null;uniqueIndex:idx_tags_tenant_slug"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// ProductStatus статус жизненного цикла товара
+type ProductStatus string
+
+const (
+	ProductStatusDraft     ProductStatus = "draft"     // Черновик, виден только admin
+	ProductStatusPublished ProductStatus = "published" // Опубликован, доступен для заказа
+	ProductStatusArchived  ProductStatus = "archived"  // Снят с продажи, виден только admin
+)
+
+// Product представляет товар в каталоге
+// Поля с тегом visibility:"staff" отдаются только manager и admin (см. util.Redact)
+type Product struct {
+	ID          uuid.UUID     `json:"id" gorm:"type:uuid;primaryKey"`
+	TenantID    string        `json:"-" gorm:"type:varchar(64);not null;default:'default';index;uniqueIndex:idx_products_tenant_slug"` // Магазин, которому принадлежит товар
+	Name        string        `json:"name" gorm:"type:varchar(255);not null"`
+	Slug        string        `json:"slug" gorm:"type:varchar(255);not null;uniqueIndex:idx_products_tenant_slug"` // Генерируется из названия для SEO URL
+	Description string        `json:"description" gorm:"type:text"`
+	Price       money.Amount  `json:"price" gorm:"type:decimal(10,2);not null"` // Цена в базовой валюте (USD) за одну единицу Unit
+	CategoryID  uuid.UUID     `json:"category_id" gorm:"type:uuid;not null"`
+	Category    *Category     `json:"category,omitempty" gorm:"foreignKey:CategoryID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+	BrandID     *uuid.UUID    `json:"brand_id,omitempty" gorm:"type:uuid"` // Бренд товара (необязательный)
+	Brand       *Brand        `json:"brand,omitempty" gorm:"foreignKey:BrandID;references:ID"`
+	SupplierID  *uuid.UUID    `json:"supplier_id,omitempty" gorm:"type:uuid" visibility:"staff"` // Поставщик товара (необязательный)
+	Status      ProductStatus `json:"status" gorm:"type:varchar(20);not null;default:'draft'"`
+	Stock       *int          `json:"stock,omitempty"` // Остаток на складе, nil - остаток не отслеживается
+	// Unit - единица измерения, QuantityStep - шаг количества в заказе (0.5 для продажи по полкило)
+	Unit         units.Unit     `json:"unit" gorm:"type:varchar(16);not null;default:'piece'"`
+	QuantityStep units.Quantity `json:"quantity_step" gorm:"type:decimal(12,3);not null;default:1"`
+	// Ограничения количества в одном заказе и всего на покупателя (по его заказам, кроме отмененных); nil - без ограничения
+	MinQty           *units.Quantity `json:"min_qty,omitempty" gorm:"type:decimal(12,3)"`
+	MaxQty           *units.Quantity `json:"max_qty,omitempty" gorm:"type:decimal(12,3)"`
+	PerCustomerLimit *units.Quantity `json:"per_customer_limit,omitempty" gorm:"type:decimal(12,3)"`
+	CostPrice        *money.Amount   `json:"cost_price,omitempty" gorm:"type:decimal(10,2)" visibility:"staff"`        // Закупочная цена, nil - не указана
+	SupplierSKU      *string         `json:"supplier_sku,omitempty" gorm:"type:varchar(100)" visibility:"staff"`       // Артикул поставщика, по нему импорт фида находит товар
+	Tags             []Tag           `json:"tags,omitempty" gorm:"many2many:product_tags;constraint:OnDelete:CASCADE"` // Теги подборок (sale, new-arrivals)
+	RatingAvg        float64         `json:"rating_avg" gorm:"type:decimal(3,2);not null;default:0"`                   // Средняя оценка из Reviews Service (денормализована для фильтров)
+	RatingCount      int             `json:"rating_count" gorm:"not null;default:0"`                                   // Число отзывов, 0 - товар без оценок
+	CreatedAt        time.Time       `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time       `json:"updated_at" gorm:"autoUpdateTime"` // Время последнего изменения, по нему выгрузка каталога забирает изменения
+	DeletedAt        *time.Time      `json:"deleted_at,omitempty"`             // Время удаления; удаленный товар архивирован и скрыт из списков
+
+	// Locale - язык названия и описания в ответе; пусто - основной язык магазина без локализации
+	Locale string `json:"locale,omitempty" gorm:"-"`
+	// PricePerUnit - цена для витрины с единицей измерения ("12.50/kg"); заполняется при чтении из БД
+	PricePerUnit string `json:"price_per_unit" gorm:"-"`
+}
+
+// FillPricePerUnit заполняет цену за единицу для витрины
+func (p *Product) FillPricePerUnit() {
+	p.PricePerUnit = p.Price.String() + "/" + p.Unit.Label()
+}
+
+// AfterFind заполняет вычисляемые поля товара, прочитанного из БД
+func (p *Product) AfterFind(*gorm.DB) error {
+	p.FillPricePerUnit()
+	return nil
+}
+
 // TableName указывает имя таблицы для GORM
 func (Product) TableName() string {
 	return "products"
 }
 
+// ProductTranslation - перевод названия и описания товара на другой язык
+type ProductTranslation struct {
+	ProductID   uuid.UUID `json:"product_id" gorm:"type:uuid;primaryKey"`
+	Locale      string    `json:"locale" gorm:"type:varchar(16);primaryKey"` // BCP 47 в нижнем регистре: en, de, pt-br
+	TenantID    string    `json:"-" gorm:"type:varchar(64);not null;default:'default';index"`
+	Name        string    `json:"name" gorm:"type:varchar(255);not null"`
+	Description string    `json:"description" gorm:"type:text;not null;default:''"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (ProductTranslation) TableName() string {
+	return "product_translations"
+}
+
+// Типы сущностей, для которых хранится история slug
+const (
+	SlugEntityCategory = "category"
+	SlugEntityProduct  = "product"
+)
+
+// SlugRedirect - прежний slug переименованной сущности
+// По нему старые ссылки перенаправляются на актуальный URL
+type SlugRedirect struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	TenantID   string    `json:"-" gorm:"type:varchar(64);not null;uniqueIndex:idx_slug_redirects_lookup"`
+	EntityType string    `json:"entity_type" gorm:"type:varchar(20);not null;uniqueIndex:idx_slug_redirects_lookup"`
+	Slug       string    `json:"slug" gorm:"type:varchar(255);not null;uniqueIndex:idx_slug_redirects_lookup"`
+	EntityID   uuid.UUID `json:"entity_id" gorm:"type:uuid;not null"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (SlugRedirect) TableName() string {
+	return "slug_redirects"
+}
+
+// ScheduledPriceStatus статус запланированной цены
+type ScheduledPriceStatus string
+
+const (
+	ScheduledPricePending   ScheduledPriceStatus = "pending"   // Ожидает начала действия
+	ScheduledPriceActive    ScheduledPriceStatus = "active"    // Распродажа идет, по окончании вернется прежняя цена
+	ScheduledPriceCompleted ScheduledPriceStatus = "completed" // Цена применена (и при наличии окна - возвращена)
+	ScheduledPriceCancelled ScheduledPriceStatus = "cancelled" // Отменена до начала действия
+)
+
+// ScheduledPrice - цена товара, которая вступит в силу в EffectiveFrom
+// С EffectiveTo это окно распродажи: по окончании возвращается цена, действовавшая до начала
+// Без EffectiveTo цена меняется навсегда
+type ScheduledPrice struct {
+	ID            uuid.UUID            `json:"id" gorm:"type:uuid;primaryKey"`
+	TenantID      string               `json:"-" gorm:"type:varchar(64);not null;default:'default'"`
+	ProductID     uuid.UUID            `json:"product_id" gorm:"type:uuid;not null;index"`
+	Price         money.Amount         `json:"price" gorm:"type:decimal(10,2);not null"`
+	PreviousPrice *money.Amount        `json:"previous_price,omitempty" gorm:"type:decimal(10,2)"` // Цена до начала действия, заполняется при активации
+	EffectiveFrom time.Time            `json:"effective_from" gorm:"not null"`
+	EffectiveTo   *time.Time           `json:"effective_to,omitempty"`
+	Status        ScheduledPriceStatus `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	CreatedAt     time.Time            `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (ScheduledPrice) TableName() string {
+	return "scheduled_prices"
+}
+
 // ProductWithCategory содержит продукт с информацией о категории
 type ProductWithCategory struct {
 	Product
 	Category Category `json:"category"`
 }
 
+// Типы событий каталога в топике product_events
+const (
+	EventTypeProductCreated   = "PRODUCT_CREATED"
+	EventTypeProductUpdated   = "PRODUCT_UPDATED"
+	EventTypeProductPublished = "PRODUCT_PUBLISHED"
+	EventTypeProductDeleted   = "PRODUCT_DELETED"
+	EventTypePriceChanged     = "PRICE_CHANGED"
+
+	EventTypeCategoryCreated = "CATEGORY_CREATED"
+	EventTypeCategoryUpdated = "CATEGORY_UPDATED"
+	EventTypeCategoryDeleted = "CATEGORY_DELETED"
+)
+
+// EventSchemaVersion - версия схемы ProductEvent и CategoryEvent (заголовок schema_version)
+// Увеличивается при несовместимых изменениях полей
+const EventSchemaVersion = 1
+
 // ProductEvent представляет событие изменения продукта для Kafka
+// Событие содержит состояние товара после изменения (для PRODUCT_DELETED - перед удалением)
 type ProductEvent struct {
-	EventType  string    `json:"event_type"` // PRODUCT_CREATED, PRODUCT_UPDATED, PRODUCT_DELETED
-	ProductID  uuid.UUID `json:"product_id"`
-	Name       string    `json:"name"`
-	Price      float64   `json:"price"`
-	CategoryID uuid.UUID `json:"category_id"`
-	Timestamp  time.Time `json:"timestamp"`
+	EventType  string        `json:"event_type"` // PRODUCT_CREATED, PRODUCT_UPDATED, PRODUCT_PUBLISHED, PRODUCT_DELETED, PRICE_CHANGED
+	TenantID   string        `json:"tenant_id"`
+	ProductID  uuid.UUID     `json:"product_id"`
+	Name       string        `json:"name"`
+	Slug       string        `json:"slug,omitempty"`
+	Status     ProductStatus `json:"status,omitempty"`
+	Price      money.Amount  `json:"price"`
+	OldPrice   *money.Amount `json:"old_price,omitempty"` // Цена до изменения (PRICE_CHANGED)
+	CategoryID uuid.UUID     `json:"category_id"`
+	BrandID    *uuid.UUID    `json:"brand_id,omitempty"`
+	SupplierID *uuid.UUID    `json:"supplier_id,omitempty"`
+	Stock      *int          `json:"stock,omitempty"`
+	Changes    AuditChanges  `json:"changes,omitempty"` // Изменившиеся поля (PRODUCT_UPDATED), в формате журнала изменений
+	Timestamp  time.Time     `json:"timestamp"`
+}
+
+// ProductSearchDocument - товар в поисковом индексе OpenSearch
+// В индекс попадают только опубликованные товары, ID документа совпадает с ID товара
+type ProductSearchDocument struct {
+	ID          uuid.UUID    `json:"-"`
+	TenantID    string       `json:"tenant_id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Slug        string       `json:"slug"`
+	Price       money.Amount `json:"price"`
+	CategoryID  string       `json:"category_id"`
+	BrandID     string       `json:"brand_id,omitempty"`
+	IndexedAt   time.Time    `json:"indexed_at"`
+}
+
+// NewProductSearchDocument формирует документ индекса из товара
+func NewProductSearchDocument(p *Product) *ProductSearchDocument {
+	doc := &ProductSearchDocument{
+		ID:          p.ID,
+		TenantID:    p.TenantID,
+		Name:        p.Name,
+		Description: p.Description,
+		Slug:        p.Slug,
+		Price:       p.Price,
+		CategoryID:  p.CategoryID.String(),
+		IndexedAt:   time.Now(),
+	}
+	if p.BrandID != nil {
+		doc.BrandID = p.BrandID.String()
+	}
+	return doc
+}
+
+// CategoryEvent представляет событие изменения категории для Kafka (топик product_events)
+type CategoryEvent struct {
+	EventType  string       `json:"event_type"` // CATEGORY_CREATED, CATEGORY_UPDATED, CATEGORY_DELETED
+	TenantID   string       `json:"tenant_id"`
+	CategoryID uuid.UUID    `json:"category_id"`
+	Name       string       `json:"name,omitempty"`
+	Slug       string       `json:"slug,omitempty"`
+	Changes    AuditChanges `json:"changes,omitempty"` // Изменившиеся поля (CATEGORY_UPDATED)
+	Timestamp  time.Time    `json:"timestamp"`
+}
+
+// Действия, которые попадают в журнал изменений каталога
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// AuditEntry - запись журнала изменений товара или категории
+// Changes хранит только изменившиеся поля: для create - новые значения, для delete - прежние
+type AuditEntry struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	TenantID   string    `json:"-" gorm:"type:varchar(64);not null;index:idx_catalog_audit_entity"`
+	EntityType string    `json:"entity_type" gorm:"type:varchar(20);not null;index:idx_catalog_audit_entity"` // SlugEntityProduct или SlugEntityCategory
+	EntityID   uuid.UUID `json:"entity_id" gorm:"type:uuid;not null;index:idx_catalog_audit_entity"`
+	Action     string    `json:"action" gorm:"type:varchar(10);not null"`
+	ActorID    string    `json:"actor_id" gorm:"type:varchar(64);not null;default:''"` // user_id из JWT
+	ActorEmail string    `json:"actor_email" gorm:"type:varchar(255);not null;default:''"`
+	// Сотрудник поддержки, выполнивший изменение от имени actor; пусто - изменение сделал сам actor
+	ImpersonatorID    string       `json:"impersonator_id,omitempty" gorm:"type:varchar(64);not null;default:''"`
+	ImpersonatorEmail string       `json:"impersonator_email,omitempty" gorm:"type:varchar(255);not null;default:''"`
+	Changes           AuditChanges `json:"changes" gorm:"type:jsonb;not null;serializer:json"`
+	CreatedAt         time.Time    `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (AuditEntry) TableName() string {
+	return "catalog_audit"
+}
+
+// AuditChanges - изменения по именам полей
+type AuditChanges map[string]FieldChange
+
+// FieldChange - значение поля до и после изменения
+type FieldChange struct {
+	Old interface{} `json:"old,omitempty"`
+	New interface{} `json:"new,omitempty"`
 }
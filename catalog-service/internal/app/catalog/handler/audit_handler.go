@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxAuditLimit - максимум записей истории в одном ответе
+const maxAuditLimit = 500
+
+// AuditHandler обрабатывает HTTP запросы журнала изменений каталога
+type AuditHandler struct {
+	auditLog *service.AuditLog
+}
+
+// NewAuditHandler создает новый обработчик журнала изменений
+func NewAuditHandler(auditLog *service.AuditLog) *AuditHandler {
+	return &AuditHandler{auditLog: auditLog}
+}
+
+// GetProductAudit обрабатывает GET /admin/products/:id/audit
+func (h *AuditHandler) GetProductAudit(c *gin.Context) {
+	h.history(c, "Invalid product ID", h.auditLog.ProductHistory)
+}
+
+// GetCategoryAudit обрабатывает GET /admin/categories/:id/audit
+func (h *AuditHandler) GetCategoryAudit(c *gin.Context) {
+	h.history(c, "Invalid category ID", h.auditLog.CategoryHistory)
+}
+
+// history общая обработка эндпоинтов истории (?limit=N, по умолчанию service.DefaultAuditLimit)
+// История удаленной сущности доступна, поэтому существование не проверяется
+func (h *AuditHandler) history(c *gin.Context, invalidID string, load func(ctx context.Context, id uuid.UUID, limit int) ([]entity.AuditEntry, error)) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": invalidID})
+		return
+	}
+
+	limit := service.DefaultAuditLimit
+	if value := c.Query("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > maxAuditLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+	}
+
+	entries, err := load(c.Request.Context(), id, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get audit history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.AuditListResponse{
+		Entries: entries,
+		Total:   len(entries),
+	})
+}
@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/service"
+	"augustberries/pkg/validate"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// BrandHandler обрабатывает HTTP запросы для брендов и поставщиков
+type BrandHandler struct {
+	brandService *service.BrandService
+	validator    *validate.Validator
+}
+
+// NewBrandHandler создает новый обработчик брендов и поставщиков
+func NewBrandHandler(brandService *service.BrandService) *BrandHandler {
+	return &BrandHandler{
+		brandService: brandService,
+		validator:    validate.New(),
+	}
+}
+
+// === BRANDS HANDLERS ===
+
+// CreateBrand обрабатывает POST /brands
+func (h *BrandHandler) CreateBrand(c *gin.Context) {
+	var req entity.CreateBrandRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+
+	brand, err := h.brandService.CreateBrand(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrBrandAlreadyExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Brand already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create brand"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, brand)
+}
+
+// GetBrand обрабатывает GET /brands/:id
+func (h *BrandHandler) GetBrand(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid brand ID"})
+		return
+	}
+
+	brand, err := h.brandService.GetBrand(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrBrandNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Brand not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get brand"})
+		return
+	}
+
+	c.JSON(http.StatusOK, brand)
+}
+
+// GetAllBrands обрабатывает GET /brands (с кешированием)
+func (h *BrandHandler) GetAllBrands(c *gin.Context) {
+	brands, err := h.brandService.GetAllBrands(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get brands"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.BrandListResponse{
+		Brands: brands,
+		Total:  len(brands),
+	})
+}
+
+// UpdateBrand обрабатывает PUT и PATCH /brands/:id (JSON Merge Patch)
+func (h *BrandHandler) UpdateBrand(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid brand ID"})
+		return
+	}
+
+	var req entity.UpdateBrandRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+
+	brand, err := h.brandService.UpdateBrand(c.Request.Context(), id, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrBrandNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Brand not found"})
+			return
+		}
+		if errors.Is(err, service.ErrBrandAlreadyExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Brand already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update brand"})
+		return
+	}
+
+	c.JSON(http.StatusOK, brand)
+}
+
+// DeleteBrand обрабатывает DELETE /brands/:id
+func (h *BrandHandler) DeleteBrand(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid brand ID"})
+		return
+	}
+
+	if err := h.brandService.DeleteBrand(c.Request.Context(), id); err != nil {
+		if errors.Is(err, service.ErrBrandNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Brand not found"})
+			return
+		}
+		if errors.Is(err, service.ErrBrandHasProducts) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Brand has products"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete brand"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.SuccessResponse{
+		Message: "Brand deleted successfully",
+	})
+}
+
+// === SUPPLIERS HANDLERS ===
+
+// CreateSupplier обрабатывает POST /suppliers
+func (h *BrandHandler) CreateSupplier(c *gin.Context) {
+	var req entity.CreateSupplierRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+
+	supplier, err := h.brandService.CreateSupplier(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrSupplierAlreadyExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Supplier already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create supplier"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, supplier)
+}
+
+// GetSupplier обрабатывает GET /suppliers/:id
+func (h *BrandHandler) GetSupplier(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid supplier ID"})
+		return
+	}
+
+	supplier, err := h.brandService.GetSupplier(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrSupplierNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Supplier not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get supplier"})
+		return
+	}
+
+	c.JSON(http.StatusOK, supplier)
+}
+
+// GetAllSuppliers обрабатывает GET /suppliers
+func (h *BrandHandler) GetAllSuppliers(c *gin.Context) {
+	suppliers, err := h.brandService.GetAllSuppliers(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get suppliers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.SupplierListResponse{
+		Suppliers: suppliers,
+		Total:     len(suppliers),
+	})
+}
+
+// UpdateSupplier обрабатывает PUT и PATCH /suppliers/:id (JSON Merge Patch)
+func (h *BrandHandler) UpdateSupplier(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid supplier ID"})
+		return
+	}
+
+	var req entity.UpdateSupplierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+
+	supplier, err := h.brandService.UpdateSupplier(c.Request.Context(), id, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrSupplierNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Supplier not found"})
+			return
+		}
+		if errors.Is(err, service.ErrSupplierAlreadyExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Supplier already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update supplier"})
+		return
+	}
+
+	c.JSON(http.StatusOK, supplier)
+}
+
+// DeleteSupplier обрабатывает DELETE /suppliers/:id
+func (h *BrandHandler) DeleteSupplier(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid supplier ID"})
+		return
+	}
+
+	if err := h.brandService.DeleteSupplier(c.Request.Context(), id); err != nil {
+		if errors.Is(err, service.ErrSupplierNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Supplier not found"})
+			return
+		}
+		if errors.Is(err, service.ErrSupplierHasProducts) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Supplier has products"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete supplier"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.SuccessResponse{
+		Message: "Supplier deleted successfully",
+	})
+}
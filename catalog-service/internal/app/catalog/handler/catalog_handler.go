@@ -1,28 +1,36 @@
 package handler
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"augustberries/catalog-service/internal/app/catalog/entity"
 	"augustberries/catalog-service/internal/app/catalog/service"
+	"augustberries/catalog-service/internal/app/catalog/util"
+	"augustberries/pkg/pagination"
+	"augustberries/pkg/validate"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
 
 // CatalogHandler обрабатывает HTTP запросы для каталога с использованием Gin
 type CatalogHandler struct {
 	catalogService *service.CatalogService
-	validator      *validator.Validate
+	translations   *service.TranslationService // Локализация товаров по Accept-Language, nil - отключена
+	validator      *validate.Validator
 }
 
 // NewCatalogHandler создает новый обработчик каталога
-func NewCatalogHandler(catalogService *service.CatalogService) *CatalogHandler {
+// translations может быть nil - тогда товары отдаются на основном языке магазина
+func NewCatalogHandler(catalogService *service.CatalogService, translations *service.TranslationService) *CatalogHandler {
 	return &CatalogHandler{
 		catalogService: catalogService,
-		validator:      validator.New(),
+		translations:   translations,
+		validator:      validate.New(),
 	}
 }
 
@@ -39,7 +47,7 @@ func (h *CatalogHandler) CreateCategory(c *gin.Context) {
 
 	// Валидация
 	if err := h.validator.Struct(req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": formatValidationError(err)})
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
 		return
 	}
 
@@ -74,7 +82,29 @@ func (h *CatalogHandler) GetCategory(c *gin.Context) {
 	c.JSON(http.StatusOK, category)
 }
 
+// GetCategoryBySlug обрабатывает GET /categories/by-slug/:slug
+// По устаревшему slug отвечает 301 на актуальный URL категории
+func (h *CatalogHandler) GetCategoryBySlug(c *gin.Context) {
+	category, moved, err := h.catalogService.GetCategoryBySlug(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		if errors.Is(err, service.ErrCategoryNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get category"})
+		return
+	}
+
+	if moved {
+		c.Redirect(http.StatusMovedPermanently, "/categories/by-slug/"+category.Slug)
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
 // GetAllCategories обрабатывает GET /categories (с кешированием)
+// ?only_non_empty=true оставляет категории, в которых есть опубликованные товары
 func (h *CatalogHandler) GetAllCategories(c *gin.Context) {
 	categories, err := h.catalogService.GetAllCategories(c.Request.Context())
 	if err != nil {
@@ -82,6 +112,16 @@ func (h *CatalogHandler) GetAllCategories(c *gin.Context) {
 		return
 	}
 
+	if c.Query("only_non_empty") == "true" {
+		nonEmpty := make([]entity.Category, 0, len(categories))
+		for _, category := range categories {
+			if category.ProductCount > 0 {
+				nonEmpty = append(nonEmpty, category)
+			}
+		}
+		categories = nonEmpty
+	}
+
 	response := entity.CategoryListResponse{
 		Categories: categories,
 		Total:      len(categories),
@@ -107,7 +147,7 @@ func (h *CatalogHandler) UpdateCategory(c *gin.Context) {
 
 	// Валидация
 	if err := h.validator.Struct(req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": formatValidationError(err)})
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
 		return
 	}
 
@@ -160,7 +200,7 @@ func (h *CatalogHandler) CreateProduct(c *gin.Context) {
 
 	// Валидация
 	if err := h.validator.Struct(req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": formatValidationError(err)})
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
 		return
 	}
 
@@ -170,6 +210,22 @@ func (h *CatalogHandler) CreateProduct(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Category not found"})
 			return
 		}
+		if errors.Is(err, service.ErrBrandNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Brand not found"})
+			return
+		}
+		if errors.Is(err, service.ErrSupplierNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Supplier not found"})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidQuantityStep) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Quantity step of piece products must be a whole number"})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidQuantityLimits) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "min_qty must not exceed max_qty"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create product"})
 		return
 	}
@@ -196,27 +252,222 @@ func (h *CatalogHandler) GetProduct(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, product)
+	// Неопубликованные товары видны только admin
+	if product.Status != entity.ProductStatusPublished && !isAdmin(c) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		return
+	}
+
+	h.localize(c, &product.Product)
+	respond(c, http.StatusOK, product)
+}
+
+// GetProductsAvailability обрабатывает GET /products/availability?ids=id1,id2
+// Используется Orders Service для проверки цен, статуса и остатков позиций заказа одним запросом
+func (h *CatalogHandler) GetProductsAvailability(c *gin.Context) {
+	ids, ok := parseProductIDs(c)
+	if !ok {
+		return
+	}
+
+	availability, err := h.catalogService.GetProductsAvailability(c.Request.Context(), ids)
+	if err != nil {
+		if errors.Is(err, service.ErrTooManyProductIDs) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d product IDs per request", service.MaxAvailabilityIDs)})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get products availability"})
+		return
+	}
+
+	// Неопубликованные товары видны только admin, для остальных они отсутствуют в каталоге
+	if !isAdmin(c) {
+		visible := availability.Products[:0]
+		for _, p := range availability.Products {
+			if p.Status == entity.ProductStatusPublished {
+				visible = append(visible, p)
+			} else {
+				availability.Missing = append(availability.Missing, p.ID)
+			}
+		}
+		availability.Products = visible
+	}
+
+	c.JSON(http.StatusOK, availability)
+}
+
+// GetProductsBatch обрабатывает GET /products/batch?ids=id1,id2
+// Витрина загружает товары корзины и избранного одним запросом; ненайденные ID перечислены в missing
+func (h *CatalogHandler) GetProductsBatch(c *gin.Context) {
+	ids, ok := parseProductIDs(c)
+	if !ok {
+		return
+	}
+
+	// Неопубликованные товары видны только admin
+	batch, err := h.catalogService.GetProductsBatch(c.Request.Context(), ids, isAdmin(c))
+	if err != nil {
+		if errors.Is(err, service.ErrTooManyProductIDs) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d product IDs per request", service.MaxBatchProducts)})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get products"})
+		return
+	}
+
+	products := make([]*entity.Product, 0, len(batch.Products))
+	for _, product := range batch.Products {
+		products = append(products, product)
+	}
+	h.localize(c, products...)
+	respond(c, http.StatusOK, batch)
 }
 
-// GetAllProducts обрабатывает GET /products
+// CompareProducts обрабатывает GET /products/compare?ids=id1,id2
+// Возвращает товары и выровненную по ним матрицу характеристик для страницы сравнения
+func (h *CatalogHandler) CompareProducts(c *gin.Context) {
+	ids, ok := parseProductIDs(c)
+	if !ok {
+		return
+	}
+
+	// Неопубликованные товары видны только admin
+	comparison, err := h.catalogService.CompareProducts(c.Request.Context(), ids, isAdmin(c))
+	if err != nil {
+		if errors.Is(err, service.ErrTooManyProductIDs) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d products can be compared", service.MaxCompareProducts)})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compare products"})
+		return
+	}
+
+	products := make([]*entity.Product, len(comparison.Products))
+	for i := range comparison.Products {
+		products[i] = &comparison.Products[i]
+	}
+	h.localize(c, products...)
+	respond(c, http.StatusOK, comparison)
+}
+
+// parseProductIDs читает ID товаров из query параметров ids (через запятую, можно повторять)
+// При ошибке отвечает 400 и возвращает false
+func parseProductIDs(c *gin.Context) ([]uuid.UUID, bool) {
+	var ids []uuid.UUID
+	for _, param := range c.QueryArray("ids") {
+		for _, raw := range strings.Split(param, ",") {
+			if raw = strings.TrimSpace(raw); raw == "" {
+				continue
+			}
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID: " + raw})
+				return nil, false
+			}
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ids query parameter is required"})
+		return nil, false
+	}
+	return ids, true
+}
+
+// GetProductBySlug обрабатывает GET /products/by-slug/:slug
+// По устаревшему slug отвечает 301 на актуальный URL товара
+func (h *CatalogHandler) GetProductBySlug(c *gin.Context) {
+	product, moved, err := h.catalogService.GetProductBySlug(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get product"})
+		return
+	}
+
+	// Неопубликованные товары видны только admin (в том числе через перенаправление)
+	if product.Status != entity.ProductStatusPublished && !isAdmin(c) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		return
+	}
+
+	if moved {
+		c.Redirect(http.StatusMovedPermanently, "/products/by-slug/"+product.Slug)
+		return
+	}
+
+	h.localize(c, &product.Product)
+	respond(c, http.StatusOK, product)
+}
+
+// GetAllProducts обрабатывает GET /products?page=&per_page=
+// С ?facets=true ответ дополняется фасетами для боковой панели фильтров
 func (h *CatalogHandler) GetAllProducts(c *gin.Context) {
-	products, err := h.catalogService.GetAllProducts(c.Request.Context())
+	filter, err := parseProductFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := pagination.Parse(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	products, err := h.catalogService.GetAllProducts(c.Request.Context(), filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get products"})
 		return
 	}
 
+	// Теги считаются по всем найденным товарам, переводятся только товары страницы
+	pageProducts := pagination.Slice(products, page)
+	list := make([]*entity.Product, len(pageProducts))
+	for i := range pageProducts {
+		list[i] = &pageProducts[i].Product
+	}
+	h.localize(c, list...)
+
 	response := entity.ProductListResponse{
-		Products: products,
-		Total:    len(products),
+		Products:  pageProducts,
+		TagFacets: service.TagFacets(products),
+		Meta:      pagination.NewMeta(c.Request.URL, page, len(products)),
 	}
 
-	c.JSON(http.StatusOK, response)
+	if c.Query("facets") == "true" {
+		response.Facets, err = h.catalogService.GetProductFacets(c.Request.Context(), filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get product facets"})
+			return
+		}
+	}
+
+	respond(c, http.StatusOK, &response)
+}
+
+// GetProductFacets обрабатывает GET /products/facets
+// Принимает те же фильтры, что и список товаров
+func (h *CatalogHandler) GetProductFacets(c *gin.Context) {
+	filter, err := parseProductFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	facets, err := h.catalogService.GetProductFacets(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get product facets"})
+		return
+	}
+
+	c.JSON(http.StatusOK, facets)
 }
 
-// UpdateProduct обрабатывает PUT /products/:id
-// При изменении цены отправляет событие PRODUCT_UPDATED в Kafka
+// UpdateProduct обрабатывает PUT и PATCH /products/:id (JSON Merge Patch)
+// Отправляет событие PRODUCT_UPDATED с изменившимися полями в Kafka
 func (h *CatalogHandler) UpdateProduct(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
@@ -231,6 +482,11 @@ func (h *CatalogHandler) UpdateProduct(c *gin.Context) {
 		return
 	}
 
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+
 	product, err := h.catalogService.UpdateProduct(c.Request.Context(), id, &req)
 	if err != nil {
 		if errors.Is(err, service.ErrProductNotFound) {
@@ -241,6 +497,22 @@ func (h *CatalogHandler) UpdateProduct(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Category not found"})
 			return
 		}
+		if errors.Is(err, service.ErrBrandNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Brand not found"})
+			return
+		}
+		if errors.Is(err, service.ErrSupplierNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Supplier not found"})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidQuantityStep) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Quantity step of piece products must be a whole number"})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidQuantityLimits) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "min_qty must not exceed max_qty"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update product"})
 		return
 	}
@@ -248,7 +520,44 @@ func (h *CatalogHandler) UpdateProduct(c *gin.Context) {
 	c.JSON(http.StatusOK, product)
 }
 
+// PublishProduct обрабатывает POST /products/:id/publish
+// Переводит черновик в статус published и отправляет событие PRODUCT_PUBLISHED в Kafka
+func (h *CatalogHandler) PublishProduct(c *gin.Context) {
+	h.changeProductStatus(c, h.catalogService.PublishProduct)
+}
+
+// ArchiveProduct обрабатывает POST /products/:id/archive
+func (h *CatalogHandler) ArchiveProduct(c *gin.Context) {
+	h.changeProductStatus(c, h.catalogService.ArchiveProduct)
+}
+
+// changeProductStatus общая обработка эндпоинтов смены статуса товара
+func (h *CatalogHandler) changeProductStatus(c *gin.Context, change func(ctx context.Context, id uuid.UUID) (*entity.Product, error)) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	product, err := change(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidProductStatus) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Invalid product status transition"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to change product status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
 // DeleteProduct обрабатывает DELETE /products/:id
+// По умолчанию товар архивируется; ?purge=true удаляет его безвозвратно, если на него не могут ссылаться заказы и отзывы
 func (h *CatalogHandler) DeleteProduct(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
@@ -257,11 +566,24 @@ func (h *CatalogHandler) DeleteProduct(c *gin.Context) {
 		return
 	}
 
-	if err := h.catalogService.DeleteProduct(c.Request.Context(), id); err != nil {
+	remove := h.catalogService.DeleteProduct
+	if c.Query("purge") == "true" {
+		remove = h.catalogService.PurgeProduct
+	}
+
+	if err := remove(c.Request.Context(), id); err != nil {
 		if errors.Is(err, service.ErrProductNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
 			return
 		}
+		if errors.Is(err, service.ErrProductReferenced) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Product cannot be purged",
+				"code":    "PRODUCT_REFERENCED",
+				"message": "Only unpublished drafts without reviews can be purged; delete without purge to archive the product",
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete product"})
 		return
 	}
@@ -271,12 +593,172 @@ func (h *CatalogHandler) DeleteProduct(c *gin.Context) {
 	})
 }
 
-// formatValidationError форматирует ошибки валидации
-func formatValidationError(err error) string {
-	if validationErrors, ok := err.(validator.ValidationErrors); ok {
-		for _, fieldError := range validationErrors {
-			return fieldError.Field() + " is " + fieldError.Tag()
+// localize переводит название и описание товаров на язык из Accept-Language
+// Для одного товара выставляет Content-Language. Ошибка загрузки переводов не ломает ответ:
+// товары отдаются на основном языке магазина
+func (h *CatalogHandler) localize(c *gin.Context, products ...*entity.Product) {
+	if h.translations == nil {
+		return
+	}
+
+	c.Writer.Header().Add("Vary", "Accept-Language")
+
+	chain := h.translations.ResolveChain(c.GetHeader("Accept-Language"))
+	if err := h.translations.Localize(c.Request.Context(), chain, products...); err != nil {
+		fmt.Printf("failed to localize products: %v\n", err)
+		return
+	}
+
+	if len(products) == 1 {
+		c.Header("Content-Language", products[0].Locale)
+	}
+}
+
+// parseProductFilter читает фильтры списка товаров из query параметров
+// Поддерживаются category_id, brand_id, supplier_id, tags (slug через запятую) и status (только admin)
+func parseProductFilter(c *gin.Context) (entity.ProductFilter, error) {
+	var filter entity.ProductFilter
+
+	params := []struct {
+		name   string
+		target **uuid.UUID
+	}{
+		{"category_id", &filter.CategoryID},
+		{"brand_id", &filter.BrandID},
+		{"supplier_id", &filter.SupplierID},
+	}
+
+	for _, p := range params {
+		value := c.Query(p.name)
+		if value == "" {
+			continue
+		}
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return entity.ProductFilter{}, errors.New("Invalid " + p.name)
+		}
+		*p.target = &id
+	}
+
+	// Поставщик - внутреннее поле, фильтр по нему доступен только manager и admin
+	if audience(c) != util.AudienceStaff {
+		filter.SupplierID = nil
+	}
+
+	// Admin может фильтровать по статусу (?status=draft), остальные видят только опубликованные товары
+	if isAdmin(c) {
+		filter.Status = entity.ProductStatus(c.Query("status"))
+	} else {
+		filter.Status = entity.ProductStatusPublished
+	}
+
+	seen := make(map[string]bool)
+	for _, slug := range strings.Split(c.Query("tags"), ",") {
+		slug = strings.TrimSpace(slug)
+		if slug == "" || seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		filter.Tags = append(filter.Tags, slug)
+	}
+
+	return filter, nil
+}
+
+// audience определяет, какие поля товаров видит пользователь запроса
+// Запрос без токена (публичные GET каталога) получает только публичные поля
+func audience(c *gin.Context) util.Audience {
+	return util.AudienceForRole(c.GetString("role_name"))
+}
+
+// respond отвечает JSON, предварительно убрав поля, закрытые для пользователя запроса
+func respond(c *gin.Context, status int, v interface{}) {
+	util.Redact(v, audience(c))
+	c.JSON(status, v)
+}
+
+// isAdmin проверяет роль пользователя, установленную AuthMiddleware
+func isAdmin(c *gin.Context) bool {
+	return c.GetString("role_name") == "admin"
+}
+
+// === ADMIN HANDLERS ===
+
+// BulkUpdatePrices обрабатывает POST /admin/products/bulk-price
+// С "dry_run": true возвращает рассчитанные изменения цен без записи
+func (h *CatalogHandler) BulkUpdatePrices(c *gin.Context) {
+	var req entity.BulkPriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+
+	response, err := h.catalogService.BulkUpdatePrices(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrEmptyBulkPrice), errors.Is(err, service.ErrDuplicateBulkPrice), errors.Is(err, service.ErrInvalidBulkPrice):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrCategoryNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		case errors.Is(err, service.ErrBulkPriceConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update prices"})
 		}
+		return
 	}
-	return "Validation failed"
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetDashboardSummary обрабатывает GET /admin/summary
+// Сводка для внутренней панели: товары по статусам, категории, попадания в кеш
+func (h *CatalogHandler) GetDashboardSummary(c *gin.Context) {
+	summary, err := h.catalogService.GetDashboardSummary(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get summary"})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// === INTERNAL HANDLERS ===
+
+// GetProductRatings обрабатывает GET /internal/products/ratings
+func (h *CatalogHandler) GetProductRatings(c *gin.Context) {
+	ratings, err := h.catalogService.GetProductRatings(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get product ratings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ProductRatingsResponse{Ratings: ratings})
+}
+
+// UpdateProductRatings обрабатывает PUT /internal/products/ratings
+func (h *CatalogHandler) UpdateProductRatings(c *gin.Context) {
+	var req entity.UpdateRatingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.catalogService.UpdateProductRatings(c.Request.Context(), req.Ratings)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update product ratings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"updated": updated})
 }
@@ -5,14 +5,19 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"augustberries/catalog-service/internal/app/catalog/entity"
 	"augustberries/catalog-service/internal/app/catalog/repository/mocks"
 	"augustberries/catalog-service/internal/app/catalog/service"
+	"augustberries/pkg/authcookie"
+	"augustberries/pkg/money"
+	"augustberries/pkg/patch"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -30,9 +35,11 @@ func setupTestHandler() (*CatalogHandler, *mocks.MockCategoryRepository, *mocks.
 	productRepo := new(mocks.MockProductRepository)
 	redisCache := new(mocks.MockRedisCache)
 	kafkaProducer := new(mocks.MockMessagePublisher)
+	// События каталога проверяются в тестах сервиса
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
 
-	catalogService := service.NewCatalogService(categoryRepo, productRepo, redisCache, kafkaProducer)
-	handler := NewCatalogHandler(catalogService)
+	catalogService := service.NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
+	handler := NewCatalogHandler(catalogService, nil)
 
 	return handler, categoryRepo, productRepo, redisCache, kafkaProducer
 }
@@ -50,8 +57,9 @@ func newTestProduct(categoryID uuid.UUID) *entity.Product {
 		ID:          uuid.New(),
 		Name:        "Laptop",
 		Description: "High-performance laptop",
-		Price:       1299.99,
+		Price:       money.MustParse("1299.99"),
 		CategoryID:  categoryID,
+		Status:      entity.ProductStatusPublished,
 		CreatedAt:   time.Now(),
 	}
 }
@@ -216,6 +224,35 @@ func TestCatalogHandler_GetAllCategories_Success(t *testing.T) {
 	assert.Len(t, response.Categories, 2)
 }
 
+func TestCatalogHandler_GetAllCategories_OnlyNonEmpty(t *testing.T) {
+	// Arrange
+	handler, _, _, redisCache, _ := setupTestHandler()
+
+	categories := []entity.Category{
+		{ID: uuid.New(), Name: "Electronics", ProductCount: 3},
+		{ID: uuid.New(), Name: "Books"},
+	}
+	redisCache.On("GetCategories", mock.Anything).Return(categories, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/categories?only_non_empty=true", nil)
+
+	// Act
+	handler.GetAllCategories(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response entity.CategoryListResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Total)
+	require.Len(t, response.Categories, 1)
+	assert.Equal(t, "Electronics", response.Categories[0].Name)
+	assert.Equal(t, 3, response.Categories[0].ProductCount)
+}
+
 func TestCatalogHandler_UpdateCategory_Success(t *testing.T) {
 	// Arrange
 	handler, categoryRepo, _, redisCache, _ := setupTestHandler()
@@ -316,7 +353,7 @@ func TestCatalogHandler_CreateProduct_Success(t *testing.T) {
 	reqBody := entity.CreateProductRequest{
 		Name:        "Laptop",
 		Description: "High-performance laptop for developers",
-		Price:       1299.99,
+		Price:       money.MustParse("1299.99"),
 		CategoryID:  category.ID,
 	}
 	body, _ := json.Marshal(reqBody)
@@ -336,32 +373,49 @@ func TestCatalogHandler_CreateProduct_Success(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Equal(t, "Laptop", response.Name)
-	assert.Equal(t, 1299.99, response.Price)
+	assert.Equal(t, money.MustParse("1299.99"), response.Price)
 }
 
 func TestCatalogHandler_CreateProduct_ValidationError(t *testing.T) {
-	// Arrange
-	handler, _, _, _, _ := setupTestHandler()
-
-	// Цена должна быть > 0
-	reqBody := entity.CreateProductRequest{
-		Name:        "Laptop",
-		Description: "Description here",
-		Price:       0,
-		CategoryID:  uuid.New(),
+	tests := []struct {
+		name     string
+		price    money.Amount
+		expected string
+	}{
+		// Цена должна быть > 0 и помещаться в decimal(10,2)
+		{"zero price", 0, "Price is required"},
+		{"negative price", money.MustParse("-1.00"), "Price must be between 0 and 99999999.99"},
+		{"price overflow", money.MustParse("100000000.00"), "Price must be between 0 and 99999999.99"},
 	}
-	body, _ := json.Marshal(reqBody)
-
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-	c.Request = httptest.NewRequest(http.MethodPost, "/products", bytes.NewBuffer(body))
-	c.Request.Header.Set("Content-Type", "application/json")
 
-	// Act
-	handler.CreateProduct(c)
-
-	// Assert
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler, _, _, _, _ := setupTestHandler()
+
+			reqBody := entity.CreateProductRequest{
+				Name:        "Laptop",
+				Description: "Description here",
+				Price:       tt.price,
+				CategoryID:  uuid.New(),
+			}
+			body, _ := json.Marshal(reqBody)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/products", bytes.NewBuffer(body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			// Act
+			handler.CreateProduct(c)
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var response map[string]string
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expected, response["error"])
+		})
+	}
 }
 
 func TestCatalogHandler_CreateProduct_CategoryNotFound(t *testing.T) {
@@ -374,7 +428,7 @@ func TestCatalogHandler_CreateProduct_CategoryNotFound(t *testing.T) {
 	reqBody := entity.CreateProductRequest{
 		Name:        "Laptop",
 		Description: "High-performance laptop for developers",
-		Price:       1299.99,
+		Price:       money.MustParse("1299.99"),
 		CategoryID:  categoryID,
 	}
 	body, _ := json.Marshal(reqBody)
@@ -415,6 +469,38 @@ func TestCatalogHandler_GetProduct_Success(t *testing.T) {
 	assert.Equal(t, product.ID, response.ID)
 }
 
+func TestCatalogHandler_GetProduct_LocalizedByAcceptLanguage(t *testing.T) {
+	// Arrange
+	handler, _, productRepo, _, _ := setupTestHandler()
+	translationRepo := new(mocks.MockTranslationRepository)
+	handler.translations = service.NewTranslationService(translationRepo, productRepo, "ru")
+
+	product := newTestProductWithCategory()
+	productRepo.On("GetWithCategory", mock.Anything, product.ID).Return(product, nil)
+	translationRepo.On("GetForProducts", mock.Anything, []uuid.UUID{product.ID}, []string{"de-at", "de"}).Return([]entity.ProductTranslation{
+		{ProductID: product.ID, Locale: "de", Name: "Notebook", Description: "Schneller Notebook"},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/products/"+product.ID.String(), nil)
+	c.Request.Header.Set("Accept-Language", "de-AT")
+	c.Params = gin.Params{{Key: "id", Value: product.ID.String()}}
+
+	// Act
+	handler.GetProduct(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "de", w.Header().Get("Content-Language"))
+	assert.Equal(t, "Accept-Language", w.Header().Get("Vary"))
+
+	var response entity.ProductWithCategory
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Notebook", response.Name)
+	assert.Equal(t, "de", response.Locale)
+}
+
 func TestCatalogHandler_GetProduct_NotFound(t *testing.T) {
 	// Arrange
 	handler, _, productRepo, _, _ := setupTestHandler()
@@ -434,6 +520,97 @@ func TestCatalogHandler_GetProduct_NotFound(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
+func TestCatalogHandler_GetProduct_DraftHiddenFromUser(t *testing.T) {
+	// Arrange
+	handler, _, productRepo, _, _ := setupTestHandler()
+
+	product := newTestProductWithCategory()
+	product.Status = entity.ProductStatusDraft
+	productRepo.On("GetWithCategory", mock.Anything, product.ID).Return(product, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/products/"+product.ID.String(), nil)
+	c.Params = gin.Params{{Key: "id", Value: product.ID.String()}}
+	c.Set("role_name", "user")
+
+	// Act
+	handler.GetProduct(c)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCatalogHandler_GetProduct_DraftVisibleToAdmin(t *testing.T) {
+	// Arrange
+	handler, _, productRepo, _, _ := setupTestHandler()
+
+	product := newTestProductWithCategory()
+	product.Status = entity.ProductStatusDraft
+	productRepo.On("GetWithCategory", mock.Anything, product.ID).Return(product, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/products/"+product.ID.String(), nil)
+	c.Params = gin.Params{{Key: "id", Value: product.ID.String()}}
+	c.Set("role_name", "admin")
+
+	// Act
+	handler.GetProduct(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestCatalogHandler_GetProduct_PrivateFieldsByRole(t *testing.T) {
+	tests := []struct {
+		name        string
+		role        string // Пустая роль - запрос без токена
+		wantPrivate bool
+	}{
+		{name: "anonymous", role: "", wantPrivate: false},
+		{name: "user", role: "user", wantPrivate: false},
+		{name: "manager", role: "manager", wantPrivate: true},
+		{name: "admin", role: "admin", wantPrivate: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler, _, productRepo, _, _ := setupTestHandler()
+
+			product := newTestProductWithCategory()
+			supplierID := uuid.New()
+			costPrice := money.MustParse("800.00")
+			product.SupplierID = &supplierID
+			product.CostPrice = &costPrice
+			productRepo.On("GetWithCategory", mock.Anything, product.ID).Return(product, nil)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/products/"+product.ID.String(), nil)
+			c.Params = gin.Params{{Key: "id", Value: product.ID.String()}}
+			if tt.role != "" {
+				c.Set("role_name", tt.role)
+			}
+
+			// Act
+			handler.GetProduct(c)
+
+			// Assert
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			_, hasSupplier := response["supplier_id"]
+			_, hasCost := response["cost_price"]
+			assert.Equal(t, tt.wantPrivate, hasSupplier)
+			assert.Equal(t, tt.wantPrivate, hasCost)
+			assert.Equal(t, product.Name, response["name"])
+		})
+	}
+}
+
 func TestCatalogHandler_GetAllProducts_Success(t *testing.T) {
 	// Arrange
 	handler, _, productRepo, _, _ := setupTestHandler()
@@ -442,7 +619,7 @@ func TestCatalogHandler_GetAllProducts_Success(t *testing.T) {
 		*newTestProductWithCategory(),
 		*newTestProductWithCategory(),
 	}
-	productRepo.On("GetAllWithCategories", mock.Anything).Return(products, nil)
+	productRepo.On("GetAllWithCategories", mock.Anything, entity.ProductFilter{Status: entity.ProductStatusPublished}).Return(products, nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -460,6 +637,170 @@ func TestCatalogHandler_GetAllProducts_Success(t *testing.T) {
 	assert.Equal(t, 2, response.Total)
 }
 
+func TestCatalogHandler_GetAllProducts_Pagination(t *testing.T) {
+	// Arrange
+	handler, _, productRepo, _, _ := setupTestHandler()
+
+	products := make([]entity.ProductWithCategory, 3)
+	for i := range products {
+		products[i] = *newTestProductWithCategory()
+		products[i].ID = uuid.New()
+	}
+	productRepo.On("GetAllWithCategories", mock.Anything, entity.ProductFilter{Status: entity.ProductStatusPublished}).Return(products, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/products?page=2&per_page=2", nil)
+
+	// Act
+	handler.GetAllProducts(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response entity.ProductListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 3, response.Total)
+	assert.Equal(t, 2, response.Page)
+	require.Len(t, response.Products, 1)
+	assert.Equal(t, products[2].ID, response.Products[0].ID)
+	assert.Empty(t, response.Links.Next)
+	assert.Equal(t, "/products?page=1&per_page=2", response.Links.Prev)
+}
+
+func TestCatalogHandler_GetAllProducts_InvalidPerPage(t *testing.T) {
+	handler, _, productRepo, _, _ := setupTestHandler()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/products?per_page=abc", nil)
+
+	handler.GetAllProducts(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	productRepo.AssertNotCalled(t, "GetAllWithCategories", mock.Anything, mock.Anything)
+}
+
+func TestCatalogHandler_GetAllProducts_FilterByBrand(t *testing.T) {
+	// Arrange
+	handler, _, productRepo, _, _ := setupTestHandler()
+
+	brandID := uuid.New()
+	products := []entity.ProductWithCategory{*newTestProductWithCategory()}
+	productRepo.On("GetAllWithCategories", mock.Anything, entity.ProductFilter{BrandID: &brandID, Status: entity.ProductStatusPublished}).Return(products, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/products?brand_id="+brandID.String(), nil)
+
+	// Act
+	handler.GetAllProducts(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	productRepo.AssertExpectations(t)
+}
+
+func TestCatalogHandler_GetAllProducts_FilterByTagsWithFacets(t *testing.T) {
+	// Arrange
+	handler, _, productRepo, _, _ := setupTestHandler()
+
+	sale := entity.Tag{ID: uuid.New(), Name: "Sale", Slug: "sale"}
+	newArrivals := entity.Tag{ID: uuid.New(), Name: "New arrivals", Slug: "new-arrivals"}
+
+	first := newTestProductWithCategory()
+	first.Tags = []entity.Tag{newArrivals, sale}
+	second := newTestProductWithCategory()
+	second.Tags = []entity.Tag{sale}
+	products := []entity.ProductWithCategory{*first, *second}
+
+	filter := entity.ProductFilter{Status: entity.ProductStatusPublished, Tags: []string{"sale"}}
+	productRepo.On("GetAllWithCategories", mock.Anything, filter).Return(products, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/products?tags=sale,%20sale,", nil)
+
+	// Act
+	handler.GetAllProducts(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	productRepo.AssertExpectations(t)
+
+	var response entity.ProductListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []entity.TagFacet{
+		{Slug: "sale", Name: "Sale", Count: 2},
+		{Slug: "new-arrivals", Name: "New arrivals", Count: 1},
+	}, response.TagFacets)
+}
+
+func TestCatalogHandler_GetAllProducts_WithFacets(t *testing.T) {
+	// Arrange
+	handler, _, productRepo, _, _ := setupTestHandler()
+
+	filter := entity.ProductFilter{Status: entity.ProductStatusPublished}
+	products := []entity.ProductWithCategory{*newTestProductWithCategory()}
+	facets := &entity.ProductFacets{
+		Categories: []entity.FacetCount{{ID: products[0].CategoryID, Name: "Electronics", Count: 1}},
+		Ratings:    []entity.RatingFacet{{MinRating: 4, Count: 1}},
+	}
+	productRepo.On("GetAllWithCategories", mock.Anything, filter).Return(products, nil)
+	productRepo.On("Facets", mock.Anything, filter, service.PriceFacetBounds).Return(facets, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/products?facets=true", nil)
+
+	// Act
+	handler.GetAllProducts(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response entity.ProductListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotNil(t, response.Facets)
+	assert.Equal(t, facets.Categories, response.Facets.Categories)
+	assert.Equal(t, facets.Ratings, response.Facets.Ratings)
+}
+
+func TestCatalogHandler_GetProductFacets_UsesListFilters(t *testing.T) {
+	// Arrange
+	handler, _, productRepo, _, _ := setupTestHandler()
+
+	brandID := uuid.New()
+	filter := entity.ProductFilter{BrandID: &brandID, Status: entity.ProductStatusPublished, Tags: []string{"sale"}}
+	productRepo.On("Facets", mock.Anything, filter, service.PriceFacetBounds).Return(&entity.ProductFacets{}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/products/facets?brand_id="+brandID.String()+"&tags=sale&status=draft", nil)
+
+	// Act
+	handler.GetProductFacets(c)
+
+	// Assert: статус draft доступен только admin
+	assert.Equal(t, http.StatusOK, w.Code)
+	productRepo.AssertExpectations(t)
+}
+
+func TestCatalogHandler_GetAllProducts_InvalidBrandID(t *testing.T) {
+	// Arrange
+	handler, _, _, _, _ := setupTestHandler()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/products?brand_id=invalid", nil)
+
+	// Act
+	handler.GetAllProducts(c)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestCatalogHandler_UpdateProduct_Success(t *testing.T) {
 	// Arrange
 	handler, _, productRepo, _, _ := setupTestHandler()
@@ -470,7 +811,7 @@ func TestCatalogHandler_UpdateProduct_Success(t *testing.T) {
 	productRepo.On("GetByID", mock.Anything, product.ID).Return(product, nil)
 	productRepo.On("Update", mock.Anything, product).Return(nil)
 
-	reqBody := entity.UpdateProductRequest{Name: "Updated Laptop"}
+	reqBody := entity.UpdateProductRequest{Name: patch.Of("Updated Laptop")}
 	body, _ := json.Marshal(reqBody)
 
 	w := httptest.NewRecorder()
@@ -493,7 +834,7 @@ func TestCatalogHandler_UpdateProduct_NotFound(t *testing.T) {
 	productID := uuid.New()
 	productRepo.On("GetByID", mock.Anything, productID).Return(nil, service.ErrProductNotFound)
 
-	reqBody := entity.UpdateProductRequest{Name: "Updated"}
+	reqBody := entity.UpdateProductRequest{Name: patch.Of("Updated")}
 	body, _ := json.Marshal(reqBody)
 
 	w := httptest.NewRecorder()
@@ -509,13 +850,44 @@ func TestCatalogHandler_UpdateProduct_NotFound(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
+func TestCatalogHandler_UpdateProduct_InvalidPatch(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "null required field", body: `{"name": null}`},
+		{name: "empty name", body: `{"name": ""}`},
+		{name: "negative stock", body: `{"stock": -1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler, _, _, _, _ := setupTestHandler()
+			productID := uuid.New()
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPatch, "/products/"+productID.String(), bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/merge-patch+json")
+			c.Params = gin.Params{{Key: "id", Value: productID.String()}}
+
+			// Act
+			handler.UpdateProduct(c)
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
 func TestCatalogHandler_DeleteProduct_Success(t *testing.T) {
 	// Arrange
 	handler, _, productRepo, _, _ := setupTestHandler()
 
 	product := newTestProduct(uuid.New())
 	productRepo.On("GetByID", mock.Anything, product.ID).Return(product, nil)
-	productRepo.On("Delete", mock.Anything, product.ID).Return(nil)
+	productRepo.On("SoftDelete", mock.Anything, product.ID, mock.Anything).Return(nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -547,3 +919,309 @@ func TestCatalogHandler_DeleteProduct_NotFound(t *testing.T) {
 	// Assert
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
+
+func TestCatalogHandler_DeleteProduct_PurgePublishedConflict(t *testing.T) {
+	// Arrange
+	handler, _, productRepo, _, _ := setupTestHandler()
+
+	product := newTestProduct(uuid.New())
+	productRepo.On("GetByID", mock.Anything, product.ID).Return(product, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodDelete, "/products/"+product.ID.String()+"?purge=true", nil)
+	c.Params = gin.Params{{Key: "id", Value: product.ID.String()}}
+
+	// Act
+	handler.DeleteProduct(c)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "PRODUCT_REFERENCED")
+	productRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+// ==================== Product Availability Handler Tests ====================
+
+func TestCatalogHandler_GetProductsAvailability_Success(t *testing.T) {
+	// Arrange
+	handler, _, productRepo, _, _ := setupTestHandler()
+
+	inStock, soldOut, draft, missing := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	zero := 0
+	productRepo.On("GetAvailability", mock.Anything, []uuid.UUID{inStock, soldOut, draft, missing}).Return([]entity.ProductAvailability{
+		{ID: inStock, Price: money.MustParse("10.00"), Status: entity.ProductStatusPublished},
+		{ID: soldOut, Price: money.MustParse("20.00"), Status: entity.ProductStatusPublished, Stock: &zero},
+		{ID: draft, Price: money.MustParse("30.00"), Status: entity.ProductStatusDraft},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	url := "/products/availability?ids=" + inStock.String() + "," + soldOut.String() + "&ids=" + draft.String() + "," + missing.String() + "," + inStock.String()
+	c.Request = httptest.NewRequest(http.MethodGet, url, nil)
+	c.Set("role_name", "user")
+
+	// Act
+	handler.GetProductsAvailability(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response entity.ProductAvailabilityResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Products, 2)
+	assert.True(t, response.Products[0].Available)
+	assert.False(t, response.Products[1].Available)
+	// Черновик скрыт от пользователя так же, как в GET /products/:id
+	assert.ElementsMatch(t, []uuid.UUID{draft, missing}, response.Missing)
+}
+
+func TestCatalogHandler_GetProductsAvailability_InvalidIDs(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"no ids", ""},
+		{"invalid uuid", "?ids=not-a-uuid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, _, _, _, _ := setupTestHandler()
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/products/availability"+tt.query, nil)
+
+			handler.GetProductsAvailability(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+// ==================== Products Batch Handler Tests ====================
+
+func TestCatalogHandler_GetProductsBatch_Success(t *testing.T) {
+	// Arrange
+	handler, _, productRepo, _, _ := setupTestHandler()
+
+	published := newTestProduct(uuid.New())
+	supplierID := uuid.New()
+	published.SupplierID = &supplierID
+	draft := newTestProduct(uuid.New())
+	draft.Status = entity.ProductStatusDraft
+	missing := uuid.New()
+	productRepo.On("GetDetailsByIDs", mock.Anything, []uuid.UUID{published.ID, draft.ID, missing}).Return([]entity.Product{*published, *draft}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	url := "/products/batch?ids=" + published.ID.String() + "," + draft.ID.String() + "," + missing.String() + "," + published.ID.String()
+	c.Request = httptest.NewRequest(http.MethodGet, url, nil)
+	c.Set("role_name", "user")
+
+	// Act
+	handler.GetProductsBatch(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response entity.ProductBatchResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Products, 1)
+	require.Contains(t, response.Products, published.ID)
+	// Закрытые поля товара не попадают в публичный ответ
+	assert.Nil(t, response.Products[published.ID].SupplierID)
+	// Черновик скрыт от пользователя так же, как в GET /products/:id
+	assert.Equal(t, []uuid.UUID{draft.ID, missing}, response.Missing)
+}
+
+func TestCatalogHandler_GetProductsBatch_TooMany(t *testing.T) {
+	// Arrange
+	handler, _, productRepo, _, _ := setupTestHandler()
+
+	ids := make([]string, service.MaxBatchProducts+1)
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/products/batch?ids="+strings.Join(ids, ","), nil)
+
+	// Act
+	handler.GetProductsBatch(c)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "At most 100")
+	productRepo.AssertNotCalled(t, "GetDetailsByIDs", mock.Anything, mock.Anything)
+}
+
+// ==================== Compare Products Handler Tests ====================
+
+func TestCatalogHandler_CompareProducts_Success(t *testing.T) {
+	// Arrange
+	handler, _, productRepo, _, _ := setupTestHandler()
+
+	first := newTestProduct(uuid.New())
+	second := newTestProduct(uuid.New())
+	supplierID := uuid.New()
+	second.SupplierID = &supplierID
+	productRepo.On("GetDetailsByIDs", mock.Anything, []uuid.UUID{first.ID, second.ID}).Return([]entity.Product{*first, *second}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/products/compare?ids="+first.ID.String()+","+second.ID.String(), nil)
+
+	// Act
+	handler.CompareProducts(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response entity.ProductComparison
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Products, 2)
+	assert.NotEmpty(t, response.Attributes)
+	// Закрытые поля товара не попадают в публичный ответ
+	assert.Nil(t, response.Products[1].SupplierID)
+}
+
+func TestCatalogHandler_CompareProducts_TooMany(t *testing.T) {
+	// Arrange
+	handler, _, _, _, _ := setupTestHandler()
+
+	ids := make([]string, service.MaxCompareProducts+1)
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/products/compare?ids="+strings.Join(ids, ","), nil)
+
+	// Act
+	handler.CompareProducts(c)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "At most")
+}
+
+// ==================== Internal Ratings Tests ====================
+
+func TestCatalogHandler_UpdateProductRatings_Success(t *testing.T) {
+	// Arrange
+	handler, _, productRepo, _, _ := setupTestHandler()
+
+	ratings := []entity.ProductRating{{ProductID: uuid.New(), RatingAvg: 4.5, RatingCount: 2}}
+	productRepo.On("UpdateRatings", mock.Anything, ratings).Return(1, nil)
+
+	body, _ := json.Marshal(entity.UpdateRatingsRequest{Ratings: ratings})
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPut, "/internal/products/ratings", bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	// Act
+	handler.UpdateProductRatings(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"updated": 1}`, w.Body.String())
+	productRepo.AssertExpectations(t)
+}
+
+func TestCatalogHandler_UpdateProductRatings_ValidationError(t *testing.T) {
+	// Arrange
+	handler, _, productRepo, _, _ := setupTestHandler()
+
+	body := `{"ratings": [{"product_id": "` + uuid.New().String() + `", "rating_avg": 7, "rating_count": 1}]}`
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPut, "/internal/products/ratings", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	// Act
+	handler.UpdateProductRatings(c)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	productRepo.AssertNotCalled(t, "UpdateRatings", mock.Anything, mock.Anything)
+}
+
+func TestAuthMiddleware_RequireInternalToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured string
+		header     string
+		wantStatus int
+	}{
+		{name: "valid token", configured: "secret", header: "secret", wantStatus: http.StatusOK},
+		{name: "wrong token", configured: "secret", header: "other", wantStatus: http.StatusUnauthorized},
+		{name: "missing token", configured: "secret", wantStatus: http.StatusUnauthorized},
+		{name: "internal API disabled", header: "", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			middleware := NewAuthMiddleware("jwt-secret")
+			middleware.SetInternalToken(tt.configured)
+
+			router := gin.New()
+			router.GET("/internal/ping", middleware.RequireInternalToken(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/internal/ping", nil)
+			if tt.header != "" {
+				req.Header.Set(InternalTokenHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestAuthMiddleware_CookieToken(t *testing.T) {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &JWTClaims{UserID: uuid.New().String(), RoleName: "customer"}).
+		SignedString([]byte("jwt-secret"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		disabled   bool
+		method     string
+		csrf       string
+		wantStatus int
+	}{
+		{name: "safe method", method: http.MethodGet, wantStatus: http.StatusOK},
+		{name: "unsafe method with csrf token", method: http.MethodPost, csrf: "csrf-value", wantStatus: http.StatusOK},
+		{name: "unsafe method without csrf token", method: http.MethodPost, wantStatus: http.StatusForbidden},
+		{name: "cookies disabled", disabled: true, method: http.MethodGet, wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			middleware := NewAuthMiddleware("jwt-secret")
+			if !tt.disabled {
+				middleware.SetCookies(authcookie.New(authcookie.Config{}))
+			}
+
+			router := gin.New()
+			router.Handle(tt.method, "/products", middleware.Authenticate(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(tt.method, "/products", nil)
+			req.AddCookie(&http.Cookie{Name: authcookie.AccessCookie, Value: token})
+			req.AddCookie(&http.Cookie{Name: authcookie.CSRFCookie, Value: "csrf-value"})
+			if tt.csrf != "" {
+				req.Header.Set(authcookie.CSRFHeader, tt.csrf)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/service"
+	"augustberries/pkg/validate"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// FeedHandler обрабатывает HTTP запросы для фидов поставщиков и статистики импорта
+type FeedHandler struct {
+	feedService *service.FeedService
+	validator   *validate.Validator
+}
+
+// NewFeedHandler создает новый обработчик фидов поставщиков
+func NewFeedHandler(feedService *service.FeedService) *FeedHandler {
+	return &FeedHandler{
+		feedService: feedService,
+		validator:   validate.New(),
+	}
+}
+
+// CreateFeed обрабатывает POST /admin/feeds
+func (h *FeedHandler) CreateFeed(c *gin.Context) {
+	req, ok := h.bindFeedRequest(c)
+	if !ok {
+		return
+	}
+
+	feed, err := h.feedService.CreateFeed(c.Request.Context(), req)
+	if err != nil {
+		respondFeedError(c, err, "Failed to create feed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, feed)
+}
+
+// GetFeeds обрабатывает GET /admin/feeds
+func (h *FeedHandler) GetFeeds(c *gin.Context) {
+	feeds, err := h.feedService.ListFeeds(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get feeds"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.SupplierFeedListResponse{
+		Feeds: feeds,
+		Total: len(feeds),
+	})
+}
+
+// GetFeed обрабатывает GET /admin/feeds/:id
+func (h *FeedHandler) GetFeed(c *gin.Context) {
+	id, ok := feedID(c)
+	if !ok {
+		return
+	}
+
+	feed, err := h.feedService.GetFeed(c.Request.Context(), id)
+	if err != nil {
+		respondFeedError(c, err, "Failed to get feed")
+		return
+	}
+
+	c.JSON(http.StatusOK, feed)
+}
+
+// UpdateFeed обрабатывает PUT /admin/feeds/:id
+func (h *FeedHandler) UpdateFeed(c *gin.Context) {
+	id, ok := feedID(c)
+	if !ok {
+		return
+	}
+	req, ok := h.bindFeedRequest(c)
+	if !ok {
+		return
+	}
+
+	feed, err := h.feedService.UpdateFeed(c.Request.Context(), id, req)
+	if err != nil {
+		respondFeedError(c, err, "Failed to update feed")
+		return
+	}
+
+	c.JSON(http.StatusOK, feed)
+}
+
+// DeleteFeed обрабатывает DELETE /admin/feeds/:id
+func (h *FeedHandler) DeleteFeed(c *gin.Context) {
+	id, ok := feedID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.feedService.DeleteFeed(c.Request.Context(), id); err != nil {
+		respondFeedError(c, err, "Failed to delete feed")
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.SuccessResponse{
+		Message: "Feed deleted successfully",
+	})
+}
+
+// RunFeed обрабатывает POST /admin/feeds/:id/run - импорт вне расписания
+// Ответ - статистика запуска; неудачная загрузка фида возвращается как status failed
+func (h *FeedHandler) RunFeed(c *gin.Context) {
+	id, ok := feedID(c)
+	if !ok {
+		return
+	}
+
+	imp, err := h.feedService.RunFeed(c.Request.Context(), id)
+	if err != nil {
+		respondFeedError(c, err, "Failed to import feed")
+		return
+	}
+
+	c.JSON(http.StatusOK, imp)
+}
+
+// GetFeedImports обрабатывает GET /admin/feeds/:id/imports - статистика последних запусков
+func (h *FeedHandler) GetFeedImports(c *gin.Context) {
+	id, ok := feedID(c)
+	if !ok {
+		return
+	}
+
+	imports, err := h.feedService.ListImports(c.Request.Context(), id)
+	if err != nil {
+		respondFeedError(c, err, "Failed to get feed imports")
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.FeedImportListResponse{
+		Imports: imports,
+		Total:   len(imports),
+	})
+}
+
+func (h *FeedHandler) bindFeedRequest(c *gin.Context) (*entity.SupplierFeedRequest, bool) {
+	var req entity.SupplierFeedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return nil, false
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return nil, false
+	}
+
+	return &req, true
+}
+
+func feedID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid feed ID"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func respondFeedError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrFeedNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
+	case errors.Is(err, service.ErrSupplierNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Supplier not found"})
+	case errors.Is(err, service.ErrCategoryNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Category not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -1,8 +1,15 @@
 package handler
 
 import (
+	"crypto/subtle"
+	"errors"
 	"net/http"
-	"strings"
+
+	"augustberries/catalog-service/internal/app/catalog/util"
+	"augustberries/pkg/authcookie"
+	"augustberries/pkg/impersonation"
+	"augustberries/pkg/jwtclaims"
+	"augustberries/pkg/tenant"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
@@ -15,12 +22,23 @@ type JWTClaims struct {
 	RoleID      int      `json:"role_id"`
 	RoleName    string   `json:"role_name"`
 	Permissions []string `json:"permissions"`
+	TenantID    string   `json:"tenant_id,omitempty"` // Магазин пользователя (пусто для токенов без тенанта)
+	// TenantRoles - роли пользователя в других магазинах, применяются tenant.Middleware по X-Tenant-ID
+	TenantRoles tenant.Roles `json:"tenant_roles,omitempty"`
+	// Impersonator - сотрудник поддержки, действующий от имени пользователя; nil - обычный токен
+	Impersonator *impersonation.Impersonator `json:"impersonator,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// InternalTokenHeader - заголовок с токеном внутреннего API
+const InternalTokenHeader = "X-Internal-Token"
+
 // AuthMiddleware проверяет JWT токен в запросах для Gin
 type AuthMiddleware struct {
-	jwtSecret string
+	jwtSecret     string
+	internalToken string              // Токен внутреннего API для других сервисов, пустой - внутренний API отключен
+	cookies       *authcookie.Cookies // nil - токен только в заголовке Authorization
+	expected      jwtclaims.Expected  // Ожидаемые iss и aud токенов, пустые - не проверяются
 }
 
 // NewAuthMiddleware создает новый middleware для аутентификации
@@ -30,59 +48,131 @@ func NewAuthMiddleware(jwtSecret string) *AuthMiddleware {
 	}
 }
 
-// Authenticate проверяет JWT токен и добавляет данные пользователя в контекст Gin
-func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
+// SetCookies включает токен из httpOnly cookie с проверкой CSRF токена у изменяющих запросов
+func (m *AuthMiddleware) SetCookies(cookies *authcookie.Cookies) {
+	m.cookies = cookies
+}
+
+// SetExpectedClaims включает проверку iss и aud: токены другого окружения отклоняются
+func (m *AuthMiddleware) SetExpectedClaims(expected jwtclaims.Expected) {
+	m.expected = expected
+}
+
+// SetInternalToken задает токен, которым другие сервисы подписывают запросы к внутреннему API
+func (m *AuthMiddleware) SetInternalToken(token string) {
+	m.internalToken = token
+}
+
+// RequireInternalToken пропускает только запросы сервисов с токеном внутреннего API в заголовке X-Internal-Token
+// Без настроенного токена внутренний API недоступен
+func (m *AuthMiddleware) RequireInternalToken() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Извлекаем токен из заголовка Authorization
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+		token := c.GetHeader(InternalTokenHeader)
+		if m.internalToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(m.internalToken)) != 1 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid internal token"})
 			c.Abort()
 			return
 		}
 
-		// Проверяем формат "Bearer <token>"
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
-			c.Abort()
+		c.Next()
+	}
+}
+
+// Authenticate проверяет JWT токен и добавляет данные пользователя в контекст Gin
+func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Токен из заголовка Authorization или из cookie
+		tokenString, err := m.cookies.Token(c.Request)
+		if err != nil {
+			abortTokenError(c, err)
 			return
 		}
 
-		tokenString := parts[1]
-
-		// Парсим и валидируем токен
-		token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-			return []byte(m.jwtSecret), nil
-		})
+		m.authenticate(c, tokenString)
+	}
+}
 
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-			c.Abort()
+// OptionalAuthenticate пропускает запросы без токена как анонимные (публичные GET каталога)
+// Переданный токен проверяется так же, как в Authenticate: невалидный токен - 401, а не анонимный доступ
+func (m *AuthMiddleware) OptionalAuthenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString, err := m.cookies.Token(c.Request)
+		if errors.Is(err, authcookie.ErrNoToken) {
+			c.Next()
 			return
 		}
-
-		// Извлекаем claims
-		claims, ok := token.Claims.(*JWTClaims)
-		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
-			c.Abort()
+		if err != nil {
+			abortTokenError(c, err)
 			return
 		}
 
-		// Добавляем данные пользователя в контекст Gin
-		c.Set("user_id", claims.UserID)
-		c.Set("email", claims.Email)
-		c.Set("role_id", claims.RoleID)
-		c.Set("role_name", claims.RoleName)
-		c.Set("permissions", claims.Permissions)
+		m.authenticate(c, tokenString)
+	}
+}
 
-		// Передаем управление следующему обработчику
-		c.Next()
+// authenticate проверяет токен и кладет данные пользователя в контекст
+func (m *AuthMiddleware) authenticate(c *gin.Context, tokenString string) {
+	// Парсим и валидируем токен
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return []byte(m.jwtSecret), nil
+	}, m.expected.ParserOptions()...)
+
+	if err != nil || !token.Valid {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": jwtclaims.Message(err)})
+		c.Abort()
+		return
+	}
+
+	// Извлекаем claims
+	claims, ok := token.Claims.(*JWTClaims)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+		c.Abort()
+		return
+	}
+
+	// Добавляем данные пользователя в контекст Gin
+	c.Set("user_id", claims.UserID)
+	c.Set("email", claims.Email)
+	c.Set("role_id", claims.RoleID)
+	c.Set("role_name", claims.RoleName)
+	c.Set("permissions", claims.Permissions)
+	c.Set(tenant.ContextKey, claims.TenantID)
+	c.Set(tenant.RolesKey, claims.TenantRoles)
+
+	// Токен сотрудника поддержки: отметка в ответе и журнале запросов
+	impersonation.Set(c, claims.Impersonator)
+
+	// Пользователь запроса нужен service layer для журнала изменений
+	actor := util.Actor{
+		UserID: claims.UserID,
+		Email:  claims.Email,
+	}
+	if claims.Impersonator != nil {
+		actor.ImpersonatorID = claims.Impersonator.ID
+		actor.ImpersonatorEmail = claims.Impersonator.Email
+	}
+	c.Request = c.Request.WithContext(util.WithActor(c.Request.Context(), actor))
+
+	// Передаем управление следующему обработчику
+	c.Next()
+}
+
+// abortTokenError отвечает на ошибку извлечения токена: 403 для CSRF, иначе 401
+func abortTokenError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, authcookie.ErrCSRF):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid CSRF token"})
+	case errors.Is(err, authcookie.ErrInvalidHeader):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
+	default:
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
 	}
+	c.Abort()
 }
 
-// RequireRole проверяет, что у пользователя есть требуемая роль
+// RequireRole проверяет, что у пользователя есть требуемая роль в активном магазине
+// Роль другого магазина подставляет tenant.Middleware, поэтому он должен стоять раньше
 func (m *AuthMiddleware) RequireRole(roles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		roleName, exists := c.Get("role_name")
@@ -118,7 +208,7 @@ func (m *AuthMiddleware) RequireRole(roles ...string) gin.HandlerFunc {
 	}
 }
 
-// RequirePermission проверяет, что у пользователя есть требуемое разрешение
+// RequirePermission проверяет, что у пользователя есть требуемое разрешение в активном магазине
 func (m *AuthMiddleware) RequirePermission(permission string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		perms, exists := c.Get("permissions")
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/service"
+	"augustberries/pkg/validate"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PriceScheduleHandler обрабатывает HTTP запросы для запланированных цен товаров
+type PriceScheduleHandler struct {
+	scheduleService *service.PriceScheduleService
+	validator       *validate.Validator
+}
+
+// NewPriceScheduleHandler создает новый обработчик запланированных цен
+func NewPriceScheduleHandler(scheduleService *service.PriceScheduleService) *PriceScheduleHandler {
+	return &PriceScheduleHandler{
+		scheduleService: scheduleService,
+		validator:       validate.New(),
+	}
+}
+
+// SchedulePrice обрабатывает POST /products/:id/scheduled-prices
+func (h *PriceScheduleHandler) SchedulePrice(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req entity.CreateScheduledPriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+
+	sp, err := h.scheduleService.Schedule(c.Request.Context(), productID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrProductNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		case errors.Is(err, service.ErrInvalidPriceWindow):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "effective_to must be after effective_from and in the future"})
+		case errors.Is(err, service.ErrPriceWindowOverlap):
+			c.JSON(http.StatusConflict, gin.H{"error": "Scheduled price overlaps another scheduled price"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to schedule price"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, sp)
+}
+
+// GetScheduledPrices обрабатывает GET /products/:id/scheduled-prices
+func (h *PriceScheduleHandler) GetScheduledPrices(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	prices, err := h.scheduleService.List(c.Request.Context(), productID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get scheduled prices"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ScheduledPriceListResponse{
+		ScheduledPrices: prices,
+		Total:           len(prices),
+	})
+}
+
+// CancelScheduledPrice обрабатывает DELETE /products/:id/scheduled-prices/:schedule_id
+func (h *PriceScheduleHandler) CancelScheduledPrice(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	scheduleID, err := uuid.Parse(c.Param("schedule_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scheduled price ID"})
+		return
+	}
+
+	if err := h.scheduleService.Cancel(c.Request.Context(), productID, scheduleID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrScheduledPriceNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Scheduled price not found"})
+		case errors.Is(err, service.ErrScheduledPriceStarted):
+			c.JSON(http.StatusConflict, gin.H{"error": "Scheduled price already started"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel scheduled price"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.SuccessResponse{
+		Message: "Scheduled price cancelled successfully",
+	})
+}
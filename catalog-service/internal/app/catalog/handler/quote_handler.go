@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/service"
+	"augustberries/pkg/validate"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QuoteHandler обрабатывает запросы на подтверждение товаров и цен
+type QuoteHandler struct {
+	quoteService *service.QuoteService
+	validator    *validate.Validator
+}
+
+// NewQuoteHandler создает новый обработчик котировок
+func NewQuoteHandler(quoteService *service.QuoteService) *QuoteHandler {
+	return &QuoteHandler{
+		quoteService: quoteService,
+		validator:    validate.New(),
+	}
+}
+
+// CreateQuote обрабатывает POST /products/quotes
+// Клиент получает котировку при показе корзины и передает токен при оформлении заказа.
+// Orders Service также вызывает этот эндпоинт для подтверждения цен перед сохранением заказа.
+func (h *QuoteHandler) CreateQuote(c *gin.Context) {
+	var req entity.CreateQuoteRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+
+	quote, err := h.quoteService.CreateQuote(c.Request.Context(), req.Items)
+	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			return
+		}
+		if errors.Is(err, service.ErrProductArchived) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Product archived", "code": "PRODUCT_ARCHIVED"})
+			return
+		}
+		if errors.Is(err, service.ErrProductNotAvailable) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Product not available"})
+			return
+		}
+		if errors.Is(err, service.ErrDuplicateQuoteItem) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Duplicate product in request"})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidQuantity) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Quantity is not a multiple of the product quantity step"})
+			return
+		}
+		if errors.Is(err, service.ErrQuantityBelowMinimum) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Quantity is below the product minimum", "code": "QUANTITY_BELOW_MIN"})
+			return
+		}
+		if errors.Is(err, service.ErrQuantityAboveMaximum) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Quantity exceeds the product maximum per order", "code": "QUANTITY_ABOVE_MAX"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create quote"})
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
@@ -6,55 +6,203 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"augustberries/pkg/buildinfo"
+	"augustberries/pkg/httpmw"
+	"augustberries/pkg/impersonation"
 	"augustberries/pkg/metrics"
+	"augustberries/pkg/quota"
+	"augustberries/pkg/recovery"
+	"augustberries/pkg/tenant"
 )
 
 // SetupRoutes настраивает все маршруты Catalog Service с использованием Gin
-// Применяет Auth middleware для защиты эндпоинтов
-func SetupRoutes(catalogHandler *CatalogHandler, authMiddleware *AuthMiddleware) *gin.Engine {
-	router := gin.Default()
+// GET эндпоинты каталога публичные, остальные защищены Auth middleware
+// Tenant middleware изолирует данные магазинов
+func SetupRoutes(catalogHandler *CatalogHandler, brandHandler *BrandHandler, tagHandler *TagHandler, quoteHandler *QuoteHandler, priceScheduleHandler *PriceScheduleHandler, auditHandler *AuditHandler, translationHandler *TranslationHandler, searchHandler *SearchHandler, feedHandler *FeedHandler, streamHandler *StreamHandler, quotas *quota.Tracker, authMiddleware *AuthMiddleware, cors httpmw.CORSConfig, compression httpmw.CompressConfig) *gin.Engine {
+	router := gin.New()
+	router.Use(gin.Logger(), recovery.Middleware("catalog-service"), impersonation.AuditMiddleware("catalog-service"))
 
 	// Prometheus metrics middleware
 	router.Use(metrics.GinPrometheusMiddleware("catalog-service"))
 
+	// CORS для браузерных клиентов (CORS_ALLOWED_ORIGINS и др.), preflight запросы не доходят до маршрутов
+	router.Use(httpmw.CORS(cors))
+
+	// Сжатие ответов больших списков (gzip, zstd) по Accept-Encoding
+	compress := httpmw.Compress("catalog-service", compression)
+
 	// Health check endpoint - публичный, без аутентификации
+	build := buildinfo.Get("catalog-service")
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
 			"status":  "ok",
 			"service": "catalog-service",
+			"build":   build,
 		})
 	})
 
+	// Сведения о сборке (коммит, время сборки, версия Go) - публичный
+	router.GET("/version", gin.WrapF(buildinfo.Handler("catalog-service")))
+
 	// Prometheus metrics endpoint
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
-	// Products endpoints - все требуют аутентификации
+	// Квоты запросов пользователей и API ключей: после аутентификации, анонимные запросы не учитываются
+	// Admin API не ограничивается, чтобы администратор всегда мог изменить квоты
+	metered := quota.Middleware(quotas)
+
+	// Публичные эндпоинты каталога - JWT не обязателен, магазин берется из токена или X-Tenant-ID
+	// Закрытые поля товаров (поставщик, закупочная цена) отдаются только manager и admin
+	// Неопубликованные товары (draft, archived) видны только admin
+	// Название и описание переводятся на язык из Accept-Language с откатом к основному языку магазина
+	publicProducts := router.Group("/products")
+	publicProducts.Use(authMiddleware.OptionalAuthenticate(), tenant.Middleware(), metered)
+	{
+		publicProducts.GET("", compress, catalogHandler.GetAllProducts)         // Список товаров (фильтры category_id, brand_id, tags, supplier_id для staff) и фасеты тегов
+		publicProducts.GET("/facets", catalogHandler.GetProductFacets)          // Фасеты для фильтров: категории, бренды, теги, цены, оценки
+		publicProducts.GET("/search", compress, searchHandler.Search)           // Полнотекстовый поиск опубликованных товаров (OpenSearch, резервно PostgreSQL)
+		publicProducts.GET("/batch", compress, catalogHandler.GetProductsBatch) // Товары по списку ID (до 100) для корзины и избранного, ненайденные - в missing
+		publicProducts.GET("/compare", catalogHandler.CompareProducts)          // Сравнение товаров: выровненная матрица цены, категории, бренда, оценки и наличия
+		publicProducts.GET("/:id", catalogHandler.GetProduct)                   // Товар по ID
+
+		// SEO URL: товар по slug, устаревший slug перенаправляется (301) на актуальный
+		publicProducts.GET("/by-slug/:slug", catalogHandler.GetProductBySlug)
+	}
+
+	publicCategories := router.Group("/categories")
+	publicCategories.Use(authMiddleware.OptionalAuthenticate(), tenant.Middleware(), metered)
+	{
+		publicCategories.GET("", compress, catalogHandler.GetAllCategories)      // Список категорий (кеш Redis)
+		publicCategories.GET("/:id", catalogHandler.GetCategory)                 // Категория по ID
+		publicCategories.GET("/by-slug/:slug", catalogHandler.GetCategoryBySlug) // Категория по slug (301 для устаревшего)
+	}
+
+	publicBrands := router.Group("/brands")
+	publicBrands.Use(authMiddleware.OptionalAuthenticate(), tenant.Middleware(), metered)
+	{
+		publicBrands.GET("", brandHandler.GetAllBrands) // Список брендов (кеш Redis)
+		publicBrands.GET("/:id", brandHandler.GetBrand) // Бренд по ID
+	}
+
+	publicTags := router.Group("/tags")
+	publicTags.Use(authMiddleware.OptionalAuthenticate(), tenant.Middleware(), metered)
+	{
+		publicTags.GET("", tagHandler.GetAllTags) // Список тегов
+		publicTags.GET("/:id", tagHandler.GetTag) // Тег по ID
+	}
+
+	// Products endpoints - изменения и служебные запросы требуют аутентификации
 	products := router.Group("/products")
-	products.Use(authMiddleware.Authenticate()) // Все маршруты требуют JWT токен
+	products.Use(authMiddleware.Authenticate(), tenant.Middleware(), metered)
 	{
-		// GET эндпоинты доступны всем аутентифицированным пользователям
-		products.GET("", catalogHandler.GetAllProducts) // Список всех товаров
-		products.GET("/:id", catalogHandler.GetProduct) // Товар по ID
+		// Цена, статус и остаток нескольких товаров одним запросом (проверка заказа в Orders Service)
+		products.GET("/availability", catalogHandler.GetProductsAvailability)
+
+		// Подписанная котировка цен с ограниченным сроком действия (передается в Orders Service)
+		products.POST("/quotes", quoteHandler.CreateQuote)
 
 		// POST, PUT, DELETE только для manager и admin
-		products.POST("", authMiddleware.RequireRole("manager", "admin"), catalogHandler.CreateProduct)    // Создать товар
-		products.PUT("/:id", authMiddleware.RequireRole("manager", "admin"), catalogHandler.UpdateProduct) // Обновить товар (отправляет в Kafka при изменении цены)
-		products.DELETE("/:id", authMiddleware.RequireRole("admin"), catalogHandler.DeleteProduct)         // Удалить товар (только admin)
+		products.POST("", authMiddleware.RequireRole("manager", "admin"), catalogHandler.CreateProduct)      // Создать товар
+		products.PUT("/:id", authMiddleware.RequireRole("manager", "admin"), catalogHandler.UpdateProduct)   // Обновить товар (отправляет в Kafka при изменении цены)
+		products.PATCH("/:id", authMiddleware.RequireRole("manager", "admin"), catalogHandler.UpdateProduct) // То же по JSON Merge Patch: null удаляет значение
+		products.DELETE("/:id", authMiddleware.RequireRole("admin"), catalogHandler.DeleteProduct)           // Архивировать товар, ?purge=true - удалить черновик (только admin)
+
+		// Подборки: замена тегов товара (manager и admin)
+		products.PUT("/:id/tags", authMiddleware.RequireRole("manager", "admin"), tagHandler.SetProductTags)
+
+		// Переводы названия и описания товара на языки других рынков (manager и admin)
+		translations := products.Group("/:id/translations", authMiddleware.RequireRole("manager", "admin"))
+		translations.GET("", translationHandler.GetTranslations)              // Все переводы товара
+		translations.PUT("/:locale", translationHandler.SetTranslation)       // Создать или заменить перевод
+		translations.DELETE("/:locale", translationHandler.DeleteTranslation) // Удалить перевод
+
+		// Запланированные цены и распродажи: применяются фоновой задачей (manager и admin)
+		scheduled := products.Group("/:id/scheduled-prices", authMiddleware.RequireRole("manager", "admin"))
+		scheduled.GET("", priceScheduleHandler.GetScheduledPrices)                   // Запланированные цены товара
+		scheduled.POST("", priceScheduleHandler.SchedulePrice)                       // Запланировать цену (effective_to - конец распродажи)
+		scheduled.DELETE("/:schedule_id", priceScheduleHandler.CancelScheduledPrice) // Отменить еще не вступившую цену
+
+		// Жизненный цикл товара: draft -> published -> archived (только admin)
+		products.POST("/:id/publish", authMiddleware.RequireRole("admin"), catalogHandler.PublishProduct) // Опубликовать (отправляет PRODUCT_PUBLISHED в Kafka)
+		products.POST("/:id/archive", authMiddleware.RequireRole("admin"), catalogHandler.ArchiveProduct) // Снять с продажи
 	}
 
-	// Categories endpoints - все требуют аутентификации
+	// Categories endpoints - изменения требуют аутентификации
 	categories := router.Group("/categories")
-	categories.Use(authMiddleware.Authenticate()) // Все маршруты требуют JWT токен
+	categories.Use(authMiddleware.Authenticate(), tenant.Middleware(), metered)
 	{
-		// GET эндпоинты доступны всем аутентифицированным пользователям
-		categories.GET("", catalogHandler.GetAllCategories) // Список категорий (кеш Redis)
-		categories.GET("/:id", catalogHandler.GetCategory)  // Категория по ID
-
 		// POST, PUT, DELETE только для manager и admin
 		categories.POST("", authMiddleware.RequireRole("manager", "admin"), catalogHandler.CreateCategory)    // Создать категорию
 		categories.PUT("/:id", authMiddleware.RequireRole("manager", "admin"), catalogHandler.UpdateCategory) // Обновить категорию
 		categories.DELETE("/:id", authMiddleware.RequireRole("admin"), catalogHandler.DeleteCategory)         // Удалить категорию (только admin)
 	}
 
+	// Brands endpoints - изменения требуют аутентификации
+	brands := router.Group("/brands")
+	brands.Use(authMiddleware.Authenticate(), tenant.Middleware(), metered)
+	{
+		brands.POST("", authMiddleware.RequireRole("manager", "admin"), brandHandler.CreateBrand)    // Создать бренд
+		brands.PUT("/:id", authMiddleware.RequireRole("manager", "admin"), brandHandler.UpdateBrand) // Обновить бренд
+		brands.PATCH("/:id", authMiddleware.RequireRole("manager", "admin"), brandHandler.UpdateBrand)
+		brands.DELETE("/:id", authMiddleware.RequireRole("admin"), brandHandler.DeleteBrand) // Удалить бренд (только admin)
+	}
+
+	// Tags endpoints - теги для подборок товаров
+	tags := router.Group("/tags")
+	tags.Use(authMiddleware.Authenticate(), tenant.Middleware(), metered)
+	{
+		tags.POST("", authMiddleware.RequireRole("manager", "admin"), tagHandler.CreateTag)       // Создать тег
+		tags.PUT("/:id", authMiddleware.RequireRole("manager", "admin"), tagHandler.UpdateTag)    // Переименовать тег
+		tags.DELETE("/:id", authMiddleware.RequireRole("manager", "admin"), tagHandler.DeleteTag) // Удалить тег (снимается со всех товаров)
+	}
+
+	// Suppliers endpoints - внутренние данные, только для manager и admin
+	suppliers := router.Group("/suppliers")
+	suppliers.Use(authMiddleware.Authenticate(), tenant.Middleware(), metered)
+	{
+		suppliers.GET("", authMiddleware.RequireRole("manager", "admin"), brandHandler.GetAllSuppliers)    // Список поставщиков
+		suppliers.GET("/:id", authMiddleware.RequireRole("manager", "admin"), brandHandler.GetSupplier)    // Поставщик по ID
+		suppliers.POST("", authMiddleware.RequireRole("manager", "admin"), brandHandler.CreateSupplier)    // Создать поставщика
+		suppliers.PUT("/:id", authMiddleware.RequireRole("manager", "admin"), brandHandler.UpdateSupplier) // Обновить поставщика
+		suppliers.PATCH("/:id", authMiddleware.RequireRole("manager", "admin"), brandHandler.UpdateSupplier)
+		suppliers.DELETE("/:id", authMiddleware.RequireRole("admin"), brandHandler.DeleteSupplier) // Удалить поставщика (только admin)
+	}
+
+	// Admin endpoints - сводка и журнал изменений каталога
+	admin := router.Group("/admin")
+	admin.Use(authMiddleware.Authenticate(), tenant.Middleware(), authMiddleware.RequireRole("admin"))
+	{
+		admin.GET("/summary", catalogHandler.GetDashboardSummary)           // Сводка для внутренней панели
+		admin.GET("/products/:id/audit", auditHandler.GetProductAudit)      // Кто и что менял в товаре
+		admin.POST("/products/bulk-price", catalogHandler.BulkUpdatePrices) // Массовое изменение цен (dry_run - только расчет)
+		admin.GET("/categories/:id/audit", auditHandler.GetCategoryAudit)   // Кто и что менял в категории
+		admin.POST("/search/reindex", searchHandler.Reindex)                // Перестроить поисковый индекс всех магазинов с нуля
+
+		// Фиды поставщиков (CSV/XML): загружаются по расписанию, товары сопоставляются по артикулу поставщика
+		feeds := admin.Group("/feeds")
+		feeds.GET("", feedHandler.GetFeeds)                   // Фиды магазина
+		feeds.POST("", feedHandler.CreateFeed)                // Добавить фид: URL, формат, правила сопоставления полей
+		feeds.GET("/:id", feedHandler.GetFeed)                // Фид по ID
+		feeds.PUT("/:id", feedHandler.UpdateFeed)             // Заменить настройки фида
+		feeds.DELETE("/:id", feedHandler.DeleteFeed)          // Удалить фид (товары остаются)
+		feeds.POST("/:id/run", feedHandler.RunFeed)           // Импортировать сейчас, ответ - статистика запуска
+		feeds.GET("/:id/imports", feedHandler.GetFeedImports) // Статистика последних запусков
+
+		// Квоты запросов: расход субъекта, индивидуальные квоты (user:<id> или key:<хеш ключа>)
+		quota.NewHandler(quotas).RegisterRoutes(admin.Group("/quotas"))
+	}
+
+	// Internal endpoints - запросы других сервисов по токену X-Internal-Token, магазин из X-Tenant-ID
+	internal := router.Group("/internal")
+	internal.Use(authMiddleware.RequireInternalToken(), tenant.Middleware())
+	{
+		// Сверка денормализованных оценок товаров с Reviews Service
+		internal.GET("/products/ratings", catalogHandler.GetProductRatings)
+		internal.PUT("/products/ratings", catalogHandler.UpdateProductRatings)
+
+		// Выгрузка изменений каталога в хранилище аналитики (NDJSON с курсором продолжения, ограничена по частоте)
+		internal.GET("/products/stream", streamHandler.StreamProducts)
+	}
+
 	return router
 }
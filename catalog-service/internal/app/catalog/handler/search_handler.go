@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/service"
+	"augustberries/pkg/pagination"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxSearchQueryLength - максимальная длина строки поиска
+const maxSearchQueryLength = 200
+
+// SearchServiceInterface определяет методы поиска товаров для dependency injection
+type SearchServiceInterface interface {
+	Search(ctx context.Context, query entity.ProductSearchQuery) (*entity.ProductSearchResult, error)
+	StartReindex(ctx context.Context) error
+}
+
+// SearchHandler обрабатывает HTTP запросы полнотекстового поиска товаров
+type SearchHandler struct {
+	searchService SearchServiceInterface
+}
+
+// NewSearchHandler создает новый обработчик поиска
+func NewSearchHandler(searchService SearchServiceInterface) *SearchHandler {
+	return &SearchHandler{searchService: searchService}
+}
+
+// Search обрабатывает GET /products/search?q=&category_id=&page=&per_page=
+// Ищет опубликованные товары в OpenSearch, при недоступности индекса - в PostgreSQL
+func (h *SearchHandler) Search(c *gin.Context) {
+	text := strings.TrimSpace(c.Query("q"))
+	if text == "" || len(text) > maxSearchQueryLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter q is required (up to 200 characters)"})
+		return
+	}
+
+	page, err := pagination.Parse(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := entity.ProductSearchQuery{Text: text, Offset: page.Offset(), Limit: page.Limit()}
+	if value := c.Query("category_id"); value != "" {
+		categoryID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+			return
+		}
+		query.CategoryID = &categoryID
+	}
+
+	result, err := h.searchService.Search(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search products"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ProductSearchResponse{
+		Products: result.Products,
+		Source:   result.Source,
+		Meta:     pagination.NewMeta(c.Request.URL, page, result.Total),
+	})
+}
+
+// Reindex обрабатывает POST /admin/search/reindex
+// Запускает перестроение индекса с нуля в фоне и сразу отвечает 202
+func (h *SearchHandler) Reindex(c *gin.Context) {
+	err := h.searchService.StartReindex(c.Request.Context())
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSearchIndexDisabled):
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Search index is not configured"})
+		case errors.Is(err, service.ErrReindexInProgress):
+			c.JSON(http.StatusConflict, gin.H{"error": "Reindex already in progress"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start reindex"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, entity.ReindexResponse{Status: "started"})
+}
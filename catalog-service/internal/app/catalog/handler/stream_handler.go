@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Размер выгрузки каталога за один запрос
+const (
+	defaultStreamLimit = 1000
+	maxStreamLimit     = 10000
+)
+
+// streamFlushEvery - число строк, после которого ответ отправляется клиенту, не дожидаясь конца выгрузки
+const streamFlushEvery = 100
+
+// ProductStreamServiceInterface определяет методы выгрузки каталога для dependency injection
+type ProductStreamServiceInterface interface {
+	Allow(ctx context.Context) error
+	RetryAfter() time.Duration
+	Stream(ctx context.Context, query entity.ProductStreamQuery, emit func(entity.ProductStreamLine) error) error
+}
+
+// StreamHandler обрабатывает выгрузку каталога для хранилища аналитики
+type StreamHandler struct {
+	streamService ProductStreamServiceInterface
+}
+
+// NewStreamHandler создает новый обработчик выгрузки каталога
+func NewStreamHandler(streamService ProductStreamServiceInterface) *StreamHandler {
+	return &StreamHandler{streamService: streamService}
+}
+
+// StreamProducts обрабатывает GET /internal/products/stream?cursor=&updated_since=&limit=
+// Отвечает NDJSON: строки product в порядке (updated_at, id) и завершающая строка end с курсором следующей выгрузки.
+// Ошибка после начала ответа обрывает поток без строки end - получатель повторяет выгрузку с последнего курсора
+func (h *StreamHandler) StreamProducts(c *gin.Context) {
+	query := entity.ProductStreamQuery{Limit: defaultStreamLimit}
+
+	if value := c.Query("cursor"); value != "" {
+		cursor, err := service.DecodeStreamCursor(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
+		query.Cursor = cursor
+	}
+
+	if value := c.Query("updated_since"); value != "" {
+		since, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "updated_since must be an RFC 3339 timestamp"})
+			return
+		}
+		query.UpdatedSince = &since
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		query.Limit = min(limit, maxStreamLimit)
+	}
+
+	if err := h.streamService.Allow(c.Request.Context()); err != nil {
+		if errors.Is(err, service.ErrStreamRateLimited) {
+			c.Header("Retry-After", strconv.Itoa(int(h.streamService.RetryAfter().Seconds())))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to stream products"})
+		return
+	}
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Status(http.StatusOK)
+
+	encoder := json.NewEncoder(c.Writer)
+	written := 0
+	err := h.streamService.Stream(c.Request.Context(), query, func(line entity.ProductStreamLine) error {
+		if err := encoder.Encode(line); err != nil {
+			return err
+		}
+		written++
+		if written%streamFlushEvery == 0 {
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		// Ошибка до первой строки еще может быть обычным ответом 500
+		if !c.Writer.Written() {
+			c.Header("Content-Type", "application/json; charset=utf-8")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to stream products"})
+			return
+		}
+		log.Printf("Product stream aborted after %d lines: %v", written, err)
+		return
+	}
+	c.Writer.Flush()
+}
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/service"
+	"augustberries/pkg/validate"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TagHandler обрабатывает HTTP запросы для тегов товаров
+type TagHandler struct {
+	tagService *service.TagService
+	validator  *validate.Validator
+}
+
+// NewTagHandler создает новый обработчик тегов
+func NewTagHandler(tagService *service.TagService) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+		validator:  validate.New(),
+	}
+}
+
+// CreateTag обрабатывает POST /tags
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	var req entity.CreateTagRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+
+	tag, err := h.tagService.CreateTag(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTagName) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Tag name must contain letters or digits"})
+			return
+		}
+		if errors.Is(err, service.ErrTagAlreadyExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Tag already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create tag"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+// GetTag обрабатывает GET /tags/:id
+func (h *TagHandler) GetTag(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag ID"})
+		return
+	}
+
+	tag, err := h.tagService.GetTag(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrTagNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get tag"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// GetAllTags обрабатывает GET /tags
+func (h *TagHandler) GetAllTags(c *gin.Context) {
+	tags, err := h.tagService.GetAllTags(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get tags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.TagListResponse{
+		Tags:  tags,
+		Total: len(tags),
+	})
+}
+
+// UpdateTag обрабатывает PUT /tags/:id
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag ID"})
+		return
+	}
+
+	var req entity.UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+
+	tag, err := h.tagService.UpdateTag(c.Request.Context(), id, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTagName) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Tag name must contain letters or digits"})
+			return
+		}
+		if errors.Is(err, service.ErrTagNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+			return
+		}
+		if errors.Is(err, service.ErrTagAlreadyExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Tag already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update tag"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// DeleteTag обрабатывает DELETE /tags/:id
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag ID"})
+		return
+	}
+
+	if err := h.tagService.DeleteTag(c.Request.Context(), id); err != nil {
+		if errors.Is(err, service.ErrTagNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete tag"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.SuccessResponse{
+		Message: "Tag deleted successfully",
+	})
+}
+
+// SetProductTags обрабатывает PUT /products/:id/tags
+// Заменяет теги товара переданным списком
+func (h *TagHandler) SetProductTags(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req entity.SetProductTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+
+	tags, err := h.tagService.SetProductTags(c.Request.Context(), id, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			return
+		}
+		if errors.Is(err, service.ErrTagNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Tag not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set product tags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.TagListResponse{
+		Tags:  tags,
+		Total: len(tags),
+	})
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/service"
+	"augustberries/pkg/validate"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TranslationHandler обрабатывает HTTP запросы для переводов товаров
+type TranslationHandler struct {
+	translationService *service.TranslationService
+	validator          *validate.Validator
+}
+
+// NewTranslationHandler создает новый обработчик переводов
+func NewTranslationHandler(translationService *service.TranslationService) *TranslationHandler {
+	return &TranslationHandler{
+		translationService: translationService,
+		validator:          validate.New(),
+	}
+}
+
+// GetTranslations обрабатывает GET /products/:id/translations
+func (h *TranslationHandler) GetTranslations(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	translations, err := h.translationService.GetTranslations(c.Request.Context(), productID)
+	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get translations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.TranslationListResponse{
+		DefaultLocale: h.translationService.DefaultLocale(),
+		Translations:  translations,
+		Total:         len(translations),
+	})
+}
+
+// SetTranslation обрабатывает PUT /products/:id/translations/:locale
+func (h *TranslationHandler) SetTranslation(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req entity.SetTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+
+	translation, err := h.translationService.SetTranslation(c.Request.Context(), productID, c.Param("locale"), &req)
+	if err != nil {
+		h.respondError(c, err, "Failed to save translation")
+		return
+	}
+
+	c.JSON(http.StatusOK, translation)
+}
+
+// DeleteTranslation обрабатывает DELETE /products/:id/translations/:locale
+func (h *TranslationHandler) DeleteTranslation(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	if err := h.translationService.DeleteTranslation(c.Request.Context(), productID, c.Param("locale")); err != nil {
+		h.respondError(c, err, "Failed to delete translation")
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.SuccessResponse{Message: "Translation deleted successfully"})
+}
+
+// respondError отвечает на ошибку сервиса переводов
+func (h *TranslationHandler) respondError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidLocale):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid locale"})
+	case errors.Is(err, service.ErrDefaultLocaleTranslation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Default locale content is edited on the product itself"})
+	case errors.Is(err, service.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+	case errors.Is(err, service.ErrTranslationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Translation not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
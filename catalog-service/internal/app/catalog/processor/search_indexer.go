@@ -0,0 +1,64 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/pkg/kafka"
+)
+
+// ProductIndexer применяет события товаров к поисковому индексу
+type ProductIndexer interface {
+	ApplyProductEvent(ctx context.Context, event *entity.ProductEvent) error
+}
+
+// SearchIndexer читает собственный топик product_events и поддерживает индекс OpenSearch
+type SearchIndexer struct {
+	consumer kafka.Consumer
+	indexer  ProductIndexer
+	stopChan chan struct{}
+	doneChan chan struct{}
+}
+
+func NewSearchIndexer(consumer kafka.Consumer, indexer ProductIndexer) *SearchIndexer {
+	return &SearchIndexer{
+		consumer: consumer,
+		indexer:  indexer,
+		stopChan: make(chan struct{}),
+		doneChan: make(chan struct{}),
+	}
+}
+
+func (c *SearchIndexer) Start(ctx context.Context) {
+	runCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		<-c.stopChan
+		cancel()
+	}()
+
+	go func() {
+		defer close(c.doneChan)
+		handler := kafka.Retry(c.processMessage, kafka.DefaultRetryPolicy)
+		if err := c.consumer.Run(runCtx, handler); err != nil {
+			log.Printf("Search indexer stopped with error: %v", err)
+		}
+	}()
+}
+
+func (c *SearchIndexer) Stop() {
+	close(c.stopChan)
+	<-c.doneChan
+	c.consumer.Close()
+}
+
+func (c *SearchIndexer) processMessage(ctx context.Context, message kafka.Message) error {
+	// События категорий имеют другую структуру, но event_type и product_id разбираются одинаково
+	var event entity.ProductEvent
+	if err := kafka.Decode(kafka.JSONCodec{}, message, &event); err != nil {
+		return kafka.Permanent(fmt.Errorf("failed to unmarshal product event: %w", err))
+	}
+
+	return c.indexer.ApplyProductEvent(ctx, &event)
+}
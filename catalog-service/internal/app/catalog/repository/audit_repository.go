@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository создает новый репозиторий журнала изменений каталога
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// Create добавляет запись в журнал
+func (r *auditRepository) Create(ctx context.Context, entry *entity.AuditEntry) error {
+	entry.TenantID = tenant.FromContext(ctx)
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+// ListByEntity возвращает историю сущности, новые записи первыми
+func (r *auditRepository) ListByEntity(ctx context.Context, entityType string, entityID uuid.UUID, limit int) ([]entity.AuditEntry, error) {
+	var entries []entity.AuditEntry
+	result := scoped(ctx, r.db).
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&entries)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrBrandNotFound      = errors.New("brand not found")
+	ErrBrandAlreadyExists = errors.New("brand with this name already exists")
+	ErrBrandHasProducts   = errors.New("cannot delete brand with existing products")
+)
+
+type brandRepository struct {
+	db *gorm.DB
+}
+
+// NewBrandRepository создает новый репозиторий брендов
+func NewBrandRepository(db *gorm.DB) BrandRepository {
+	return &brandRepository{db: db}
+}
+
+// Create создает новый бренд
+func (r *brandRepository) Create(ctx context.Context, brand *entity.Brand) error {
+	brand.TenantID = tenant.FromContext(ctx)
+	result := r.db.WithContext(ctx).Create(brand)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return ErrBrandAlreadyExists
+		}
+		return result.Error
+	}
+	return nil
+}
+
+// GetByID получает бренд по ID
+func (r *brandRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Brand, error) {
+	var brand entity.Brand
+	result := scoped(ctx, r.db).First(&brand, "id = ?", id)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrBrandNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &brand, nil
+}
+
+// GetAll получает все бренды отсортированные по имени
+// Результат кешируется в Redis через service layer
+func (r *brandRepository) GetAll(ctx context.Context) ([]entity.Brand, error) {
+	var brands []entity.Brand
+	result := scoped(ctx, r.db).Order("name ASC").Find(&brands)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return brands, nil
+}
+
+// Update обновляет бренд
+func (r *brandRepository) Update(ctx context.Context, brand *entity.Brand) error {
+	result := scoped(ctx, r.db).Model(brand).Where("id = ?", brand.ID).Updates(map[string]interface{}{
+		"name":        brand.Name,
+		"description": brand.Description,
+	})
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return ErrBrandAlreadyExists
+		}
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrBrandNotFound
+	}
+
+	return nil
+}
+
+// Delete удаляет бренд
+// Бренд с привязанными товарами удалить нельзя
+func (r *brandRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	var productCount int64
+	if err := scoped(ctx, r.db).Model(&entity.Product{}).Where("brand_id = ?", id).Count(&productCount).Error; err != nil {
+		return err
+	}
+
+	if productCount > 0 {
+		return ErrBrandHasProducts
+	}
+
+	result := scoped(ctx, r.db).Delete(&entity.Brand{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrBrandNotFound
+	}
+
+	return nil
+}
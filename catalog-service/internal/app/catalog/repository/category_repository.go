@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/pkg/tenant"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -27,8 +28,15 @@ func NewCategoryRepository(db *gorm.DB) CategoryRepository {
 }
 
 // Create создает новую категорию в PostgreSQL
-// Проверяет уникальность имени через UNIQUE constraint
+// Проверяет уникальность имени через UNIQUE constraint и генерирует свободный slug
 func (r *categoryRepository) Create(ctx context.Context, category *entity.Category) error {
+	slug, err := uniqueSlug(ctx, r.db, &entity.Category{}, category.Name, entity.SlugEntityCategory, uuid.Nil)
+	if err != nil {
+		return err
+	}
+	category.Slug = slug
+
+	category.TenantID = tenant.FromContext(ctx)
 	result := r.db.WithContext(ctx).Create(category)
 	if result.Error != nil {
 		// Проверяем на ошибку уникальности
@@ -43,7 +51,22 @@ func (r *categoryRepository) Create(ctx context.Context, category *entity.Catego
 // GetByID получает категорию по ID из PostgreSQL
 func (r *categoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Category, error) {
 	var category entity.Category
-	result := r.db.WithContext(ctx).First(&category, "id = ?", id)
+	result := scoped(ctx, r.db).First(&category, "id = ?", id)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &category, nil
+}
+
+// GetBySlug получает категорию по текущему slug
+func (r *categoryRepository) GetBySlug(ctx context.Context, slug string) (*entity.Category, error) {
+	var category entity.Category
+	result := scoped(ctx, r.db).First(&category, "slug = ?", slug)
 
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -55,11 +78,24 @@ func (r *categoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity
 	return &category, nil
 }
 
+// GetByOldSlug получает категорию по slug, который она имела до переименования
+func (r *categoryRepository) GetByOldSlug(ctx context.Context, slug string) (*entity.Category, error) {
+	id, err := findSlugRedirect(ctx, r.db, entity.SlugEntityCategory, slug)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+
+	return r.GetByID(ctx, id)
+}
+
 // GetAll получает все категории отсортированные по имени
 // Результат может быть закеширован в Redis через service layer
 func (r *categoryRepository) GetAll(ctx context.Context) ([]entity.Category, error) {
 	var categories []entity.Category
-	result := r.db.WithContext(ctx).Order("name ASC").Find(&categories)
+	result := scoped(ctx, r.db).Order("name ASC").Find(&categories)
 
 	if result.Error != nil {
 		return nil, result.Error
@@ -68,27 +104,97 @@ func (r *categoryRepository) GetAll(ctx context.Context) ([]entity.Category, err
 	return categories, nil
 }
 
-// Update обновляет категорию в PostgreSQL
-// Проверяет уникальность нового имени
-func (r *categoryRepository) Update(ctx context.Context, category *entity.Category) error {
-	result := r.db.WithContext(ctx).Model(category).Where("id = ?", category.ID).Updates(map[string]interface{}{
-		"name": category.Name,
-	})
+// ListTenants возвращает магазины, в которых заведены категории
+func (r *categoryRepository) ListTenants(ctx context.Context) ([]string, error) {
+	var tenants []string
+	result := r.db.WithContext(ctx).Model(&entity.Category{}).Distinct("tenant_id").Order("tenant_id").Pluck("tenant_id", &tenants)
 
 	if result.Error != nil {
-		// Проверяем на ошибку уникальности
-		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
-			return ErrCategoryAlreadyExists
-		}
-		return result.Error
+		return nil, result.Error
 	}
 
-	// Проверяем что категория существует
-	if result.RowsAffected == 0 {
-		return ErrCategoryNotFound
+	return tenants, nil
+}
+
+// Count считает категории магазина
+func (r *categoryRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	if err := scoped(ctx, r.db).Model(&entity.Category{}).Count(&count).Error; err != nil {
+		return 0, err
 	}
+	return count, nil
+}
 
-	return nil
+// refreshProductCountsSQL пересчитывает товары категорий одним запросом
+// Обновляются только строки с изменившимся числом, RETURNING отдает их магазины
+const refreshProductCountsSQL = `
+UPDATE categories c SET product_count = counts.product_count
+FROM (
+	SELECT c2.id, COUNT(p.id) AS product_count
+	FROM categories c2
+	LEFT JOIN products p ON p.category_id = c2.id AND p.tenant_id = c2.tenant_id
+		AND p.status = ? AND p.deleted_at IS NULL
+	GROUP BY c2.id
+) counts
+WHERE c.id = counts.id AND c.product_count <> counts.product_count
+RETURNING c.tenant_id`
+
+// RefreshProductCounts пересчитывает число опубликованных товаров в категориях всех магазинов
+func (r *categoryRepository) RefreshProductCounts(ctx context.Context) ([]string, error) {
+	var changed []string
+	if err := r.db.WithContext(ctx).Raw(refreshProductCountsSQL, entity.ProductStatusPublished).Scan(&changed).Error; err != nil {
+		return nil, err
+	}
+
+	tenants := make([]string, 0, len(changed))
+	seen := make(map[string]struct{}, len(changed))
+	for _, id := range changed {
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			tenants = append(tenants, id)
+		}
+	}
+	return tenants, nil
+}
+
+// Update обновляет категорию в PostgreSQL
+// Проверяет уникальность нового имени; при смене имени меняет slug и сохраняет прежний в истории
+func (r *categoryRepository) Update(ctx context.Context, category *entity.Category) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current entity.Category
+		if err := scoped(ctx, tx).First(&current, "id = ?", category.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCategoryNotFound
+			}
+			return err
+		}
+
+		slug, err := renameSlug(ctx, tx, &entity.Category{}, entity.SlugEntityCategory, category.ID, current.Name, current.Slug, category.Name)
+		if err != nil {
+			return err
+		}
+		category.Slug = slug
+
+		result := scoped(ctx, tx).Model(category).Where("id = ?", category.ID).Updates(map[string]interface{}{
+			"name": category.Name,
+			"slug": category.Slug,
+		})
+
+		if result.Error != nil {
+			// Проверяем на ошибку уникальности
+			if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+				return ErrCategoryAlreadyExists
+			}
+			return result.Error
+		}
+
+		// Проверяем что категория существует
+		if result.RowsAffected == 0 {
+			return ErrCategoryNotFound
+		}
+
+		return nil
+	})
 }
 
 // Delete удаляет категорию из PostgreSQL
@@ -96,7 +202,7 @@ func (r *categoryRepository) Update(ctx context.Context, category *entity.Catego
 func (r *categoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	// Сначала проверяем есть ли товары в этой категории
 	var productCount int64
-	r.db.WithContext(ctx).Model(&entity.Product{}).Where("category_id = ?", id).Count(&productCount)
+	scoped(ctx, r.db).Model(&entity.Product{}).Where("category_id = ?", id).Count(&productCount)
 
 	// Если есть товары, возвращаем ошибку
 	if productCount > 0 {
@@ -104,7 +210,7 @@ func (r *categoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	}
 
 	// Удаляем категорию
-	result := r.db.WithContext(ctx).Delete(&entity.Category{}, "id = ?", id)
+	result := scoped(ctx, r.db).Delete(&entity.Category{}, "id = ?", id)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -121,7 +227,7 @@ func (r *categoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
 // Может быть полезна для проверки уникальности перед созданием
 func (r *categoryRepository) GetByName(ctx context.Context, name string) (*entity.Category, error) {
 	var category entity.Category
-	result := r.db.WithContext(ctx).Where("LOWER(name) = LOWER(?)", name).First(&category)
+	result := scoped(ctx, r.db).Where("LOWER(name) = LOWER(?)", name).First(&category)
 
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrFeedNotFound = errors.New("supplier feed not found")
+
+type feedRepository struct {
+	db *gorm.DB
+}
+
+// NewFeedRepository создает новый репозиторий фидов поставщиков
+func NewFeedRepository(db *gorm.DB) FeedRepository {
+	return &feedRepository{db: db}
+}
+
+// Create сохраняет фид поставщика
+func (r *feedRepository) Create(ctx context.Context, feed *entity.SupplierFeed) error {
+	feed.TenantID = tenant.FromContext(ctx)
+	return r.db.WithContext(ctx).Create(feed).Error
+}
+
+// GetByID получает фид по ID
+func (r *feedRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.SupplierFeed, error) {
+	var feed entity.SupplierFeed
+	result := scoped(ctx, r.db).First(&feed, "id = ?", id)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrFeedNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &feed, nil
+}
+
+// List возвращает фиды магазина в порядке создания
+func (r *feedRepository) List(ctx context.Context) ([]entity.SupplierFeed, error) {
+	var feeds []entity.SupplierFeed
+	result := scoped(ctx, r.db).Order("created_at ASC").Find(&feeds)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return feeds, nil
+}
+
+// Update заменяет настройки фида; время последнего запуска не меняется
+func (r *feedRepository) Update(ctx context.Context, feed *entity.SupplierFeed) error {
+	result := scoped(ctx, r.db).Model(feed).
+		Select("supplier_id", "url", "format", "mapping", "category_id", "interval_minutes", "enabled").
+		Updates(feed)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrFeedNotFound
+	}
+
+	return nil
+}
+
+// Delete удаляет фид вместе с историей импорта; импортированные товары остаются
+func (r *feedRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := scoped(ctx, r.db).Delete(&entity.SupplierFeed{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrFeedNotFound
+	}
+
+	return nil
+}
+
+// ListDue возвращает включенные фиды всех магазинов, которые пора загрузить
+// Запрос без scoped: фоновая задача обслуживает все магазины
+func (r *feedRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]entity.SupplierFeed, error) {
+	var feeds []entity.SupplierFeed
+	result := r.db.WithContext(ctx).
+		Where("enabled AND (last_run_at IS NULL OR last_run_at + interval_minutes * INTERVAL '1 minute' <= ?)", now).
+		Order("last_run_at ASC NULLS FIRST").
+		Limit(limit).
+		Find(&feeds)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return feeds, nil
+}
+
+// MarkRun запоминает время запуска импорта фида
+func (r *feedRepository) MarkRun(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return scoped(ctx, r.db).Model(&entity.SupplierFeed{}).Where("id = ?", id).Update("last_run_at", at).Error
+}
+
+// SaveImport сохраняет статистику запуска импорта
+func (r *feedRepository) SaveImport(ctx context.Context, imp *entity.FeedImport) error {
+	imp.TenantID = tenant.FromContext(ctx)
+	return r.db.WithContext(ctx).Create(imp).Error
+}
+
+// ListImports возвращает последние запуски импорта фида, новые первыми
+func (r *feedRepository) ListImports(ctx context.Context, feedID uuid.UUID, limit int) ([]entity.FeedImport, error) {
+	var imports []entity.FeedImport
+	result := scoped(ctx, r.db).Where("feed_id = ?", feedID).Order("started_at DESC").Limit(limit).Find(&imports)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return imports, nil
+}
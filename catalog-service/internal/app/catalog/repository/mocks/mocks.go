@@ -2,9 +2,11 @@ package mocks
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/pkg/money"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
@@ -28,6 +30,22 @@ func (m *MockCategoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*en
 	return args.Get(0).(*entity.Category), args.Error(1)
 }
 
+func (m *MockCategoryRepository) GetBySlug(ctx context.Context, slug string) (*entity.Category, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) GetByOldSlug(ctx context.Context, slug string) (*entity.Category, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Category), args.Error(1)
+}
+
 func (m *MockCategoryRepository) GetAll(ctx context.Context) ([]entity.Category, error) {
 	args := m.Called(ctx)
 	if args.Get(0) == nil {
@@ -36,6 +54,27 @@ func (m *MockCategoryRepository) GetAll(ctx context.Context) ([]entity.Category,
 	return args.Get(0).([]entity.Category), args.Error(1)
 }
 
+func (m *MockCategoryRepository) ListTenants(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockCategoryRepository) Count(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCategoryRepository) RefreshProductCounts(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 func (m *MockCategoryRepository) Update(ctx context.Context, category *entity.Category) error {
 	args := m.Called(ctx, category)
 	return args.Error(0)
@@ -64,6 +103,22 @@ func (m *MockProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*ent
 	return args.Get(0).(*entity.Product), args.Error(1)
 }
 
+func (m *MockProductRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.Product, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) GetAvailability(ctx context.Context, ids []uuid.UUID) ([]entity.ProductAvailability, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.ProductAvailability), args.Error(1)
+}
+
 func (m *MockProductRepository) GetAll(ctx context.Context) ([]entity.Product, error) {
 	args := m.Called(ctx)
 	if args.Get(0) == nil {
@@ -80,24 +135,426 @@ func (m *MockProductRepository) GetWithCategory(ctx context.Context, id uuid.UUI
 	return args.Get(0).(*entity.ProductWithCategory), args.Error(1)
 }
 
-func (m *MockProductRepository) GetAllWithCategories(ctx context.Context) ([]entity.ProductWithCategory, error) {
-	args := m.Called(ctx)
+func (m *MockProductRepository) GetBySlug(ctx context.Context, slug string) (*entity.ProductWithCategory, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ProductWithCategory), args.Error(1)
+}
+
+func (m *MockProductRepository) GetByOldSlug(ctx context.Context, slug string) (*entity.ProductWithCategory, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ProductWithCategory), args.Error(1)
+}
+
+func (m *MockProductRepository) GetAllWithCategories(ctx context.Context, filter entity.ProductFilter) ([]entity.ProductWithCategory, error) {
+	args := m.Called(ctx, filter)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]entity.ProductWithCategory), args.Error(1)
 }
 
+func (m *MockProductRepository) Facets(ctx context.Context, filter entity.ProductFilter, priceBounds []money.Amount) (*entity.ProductFacets, error) {
+	args := m.Called(ctx, filter, priceBounds)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ProductFacets), args.Error(1)
+}
+
 func (m *MockProductRepository) Update(ctx context.Context, product *entity.Product) error {
 	args := m.Called(ctx, product)
 	return args.Error(0)
 }
 
+func (m *MockProductRepository) UpdateStatus(ctx context.Context, id uuid.UUID, from, to entity.ProductStatus) error {
+	args := m.Called(ctx, id, from, to)
+	return args.Error(0)
+}
+
 func (m *MockProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *MockProductRepository) SoftDelete(ctx context.Context, id uuid.UUID, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) Search(ctx context.Context, query entity.ProductSearchQuery) ([]entity.Product, int64, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]entity.Product), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockProductRepository) ListPublishedAfter(ctx context.Context, afterID uuid.UUID, limit int) ([]entity.Product, error) {
+	args := m.Called(ctx, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) ListChanged(ctx context.Context, query entity.ProductStreamQuery) ([]entity.Product, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) ListPopular(ctx context.Context, limit int) ([]entity.ProductWithCategory, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.ProductWithCategory), args.Error(1)
+}
+
+func (m *MockProductRepository) CountByStatus(ctx context.Context) (map[entity.ProductStatus]int64, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[entity.ProductStatus]int64), args.Error(1)
+}
+
+func (m *MockProductRepository) ListRatings(ctx context.Context) ([]entity.ProductRating, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.ProductRating), args.Error(1)
+}
+
+func (m *MockProductRepository) UpdateRatings(ctx context.Context, ratings []entity.ProductRating) (int, error) {
+	args := m.Called(ctx, ratings)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockProductRepository) ListBySupplierSKUs(ctx context.Context, supplierID uuid.UUID, skus []string) ([]entity.Product, error) {
+	args := m.Called(ctx, supplierID, skus)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) SaveBatch(ctx context.Context, created, updated []*entity.Product) error {
+	args := m.Called(ctx, created, updated)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) ListByCategory(ctx context.Context, categoryID uuid.UUID) ([]entity.Product, error) {
+	args := m.Called(ctx, categoryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) UpdatePrices(ctx context.Context, changes []entity.PriceChange) error {
+	args := m.Called(ctx, changes)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) GetDetailsByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.Product, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Product), args.Error(1)
+}
+
+// MockBrandRepository мок для BrandRepository
+type MockBrandRepository struct {
+	mock.Mock
+}
+
+func (m *MockBrandRepository) Create(ctx context.Context, brand *entity.Brand) error {
+	args := m.Called(ctx, brand)
+	return args.Error(0)
+}
+
+func (m *MockBrandRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Brand, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Brand), args.Error(1)
+}
+
+func (m *MockBrandRepository) GetAll(ctx context.Context) ([]entity.Brand, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Brand), args.Error(1)
+}
+
+func (m *MockBrandRepository) Update(ctx context.Context, brand *entity.Brand) error {
+	args := m.Called(ctx, brand)
+	return args.Error(0)
+}
+
+func (m *MockBrandRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockSupplierRepository мок для SupplierRepository
+type MockSupplierRepository struct {
+	mock.Mock
+}
+
+func (m *MockSupplierRepository) Create(ctx context.Context, supplier *entity.Supplier) error {
+	args := m.Called(ctx, supplier)
+	return args.Error(0)
+}
+
+func (m *MockSupplierRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Supplier, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Supplier), args.Error(1)
+}
+
+func (m *MockSupplierRepository) GetAll(ctx context.Context) ([]entity.Supplier, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Supplier), args.Error(1)
+}
+
+func (m *MockSupplierRepository) Update(ctx context.Context, supplier *entity.Supplier) error {
+	args := m.Called(ctx, supplier)
+	return args.Error(0)
+}
+
+func (m *MockSupplierRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockTranslationRepository мок для TranslationRepository
+type MockTranslationRepository struct {
+	mock.Mock
+}
+
+func (m *MockTranslationRepository) Upsert(ctx context.Context, translation *entity.ProductTranslation) error {
+	args := m.Called(ctx, translation)
+	return args.Error(0)
+}
+
+func (m *MockTranslationRepository) ListByProduct(ctx context.Context, productID uuid.UUID) ([]entity.ProductTranslation, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.ProductTranslation), args.Error(1)
+}
+
+func (m *MockTranslationRepository) GetForProducts(ctx context.Context, productIDs []uuid.UUID, locales []string) ([]entity.ProductTranslation, error) {
+	args := m.Called(ctx, productIDs, locales)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.ProductTranslation), args.Error(1)
+}
+
+func (m *MockTranslationRepository) Delete(ctx context.Context, productID uuid.UUID, locale string) error {
+	args := m.Called(ctx, productID, locale)
+	return args.Error(0)
+}
+
+// MockTagRepository мок для TagRepository
+type MockTagRepository struct {
+	mock.Mock
+}
+
+func (m *MockTagRepository) Create(ctx context.Context, tag *entity.Tag) error {
+	args := m.Called(ctx, tag)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Tag, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.Tag, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) GetAll(ctx context.Context) ([]entity.Tag, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) Update(ctx context.Context, tag *entity.Tag) error {
+	args := m.Called(ctx, tag)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) SetProductTags(ctx context.Context, productID uuid.UUID, tagIDs []uuid.UUID) error {
+	args := m.Called(ctx, productID, tagIDs)
+	return args.Error(0)
+}
+
+// MockScheduledPriceRepository мок для ScheduledPriceRepository
+type MockScheduledPriceRepository struct {
+	mock.Mock
+}
+
+func (m *MockScheduledPriceRepository) Create(ctx context.Context, sp *entity.ScheduledPrice) error {
+	args := m.Called(ctx, sp)
+	return args.Error(0)
+}
+
+func (m *MockScheduledPriceRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.ScheduledPrice, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ScheduledPrice), args.Error(1)
+}
+
+func (m *MockScheduledPriceRepository) ListByProduct(ctx context.Context, productID uuid.UUID) ([]entity.ScheduledPrice, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.ScheduledPrice), args.Error(1)
+}
+
+func (m *MockScheduledPriceRepository) Cancel(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockScheduledPriceRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]entity.ScheduledPrice, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.ScheduledPrice), args.Error(1)
+}
+
+func (m *MockScheduledPriceRepository) Activate(ctx context.Context, sp *entity.ScheduledPrice) (money.Amount, error) {
+	args := m.Called(ctx, sp)
+	return args.Get(0).(money.Amount), args.Error(1)
+}
+
+func (m *MockScheduledPriceRepository) Complete(ctx context.Context, sp *entity.ScheduledPrice) (bool, error) {
+	args := m.Called(ctx, sp)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockAuditRepository мок для AuditRepository
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Create(ctx context.Context, entry *entity.AuditEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) ListByEntity(ctx context.Context, entityType string, entityID uuid.UUID, limit int) ([]entity.AuditEntry, error) {
+	args := m.Called(ctx, entityType, entityID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.AuditEntry), args.Error(1)
+}
+
+// MockFeedRepository мок для FeedRepository
+type MockFeedRepository struct {
+	mock.Mock
+}
+
+func (m *MockFeedRepository) Create(ctx context.Context, feed *entity.SupplierFeed) error {
+	args := m.Called(ctx, feed)
+	return args.Error(0)
+}
+
+func (m *MockFeedRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.SupplierFeed, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.SupplierFeed), args.Error(1)
+}
+
+func (m *MockFeedRepository) List(ctx context.Context) ([]entity.SupplierFeed, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.SupplierFeed), args.Error(1)
+}
+
+func (m *MockFeedRepository) Update(ctx context.Context, feed *entity.SupplierFeed) error {
+	args := m.Called(ctx, feed)
+	return args.Error(0)
+}
+
+func (m *MockFeedRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockFeedRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]entity.SupplierFeed, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.SupplierFeed), args.Error(1)
+}
+
+func (m *MockFeedRepository) MarkRun(ctx context.Context, id uuid.UUID, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockFeedRepository) SaveImport(ctx context.Context, imp *entity.FeedImport) error {
+	args := m.Called(ctx, imp)
+	return args.Error(0)
+}
+
+func (m *MockFeedRepository) ListImports(ctx context.Context, feedID uuid.UUID, limit int) ([]entity.FeedImport, error) {
+	args := m.Called(ctx, feedID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.FeedImport), args.Error(1)
+}
+
 // MockRedisCache мок для RedisCache
 type MockRedisCache struct {
 	mock.Mock
@@ -121,11 +578,113 @@ func (m *MockRedisCache) DeleteCategories(ctx context.Context) error {
 	return args.Error(0)
 }
 
+func (m *MockRedisCache) SetBrands(ctx context.Context, brands []entity.Brand, ttl time.Duration) error {
+	args := m.Called(ctx, brands, ttl)
+	return args.Error(0)
+}
+
+func (m *MockRedisCache) GetBrands(ctx context.Context) ([]entity.Brand, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Brand), args.Error(1)
+}
+
+func (m *MockRedisCache) DeleteBrands(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
 func (m *MockRedisCache) Close() error {
 	args := m.Called()
 	return args.Error(0)
 }
 
+// MockProductCache мок для ProductCache
+type MockProductCache struct {
+	mock.Mock
+}
+
+func (m *MockProductCache) SetProduct(ctx context.Context, product *entity.ProductWithCategory, ttl time.Duration) error {
+	args := m.Called(ctx, product, ttl)
+	return args.Error(0)
+}
+
+func (m *MockProductCache) GetProduct(ctx context.Context, id uuid.UUID) (*entity.ProductWithCategory, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ProductWithCategory), args.Error(1)
+}
+
+func (m *MockProductCache) DeleteProduct(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockSearchIndex мок для SearchIndex (OpenSearch)
+type MockSearchIndex struct {
+	mock.Mock
+}
+
+func (m *MockSearchIndex) Search(ctx context.Context, query entity.ProductSearchQuery) ([]uuid.UUID, int, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]uuid.UUID), args.Int(1), args.Error(2)
+}
+
+func (m *MockSearchIndex) IndexProduct(ctx context.Context, doc *entity.ProductSearchDocument) error {
+	args := m.Called(ctx, doc)
+	return args.Error(0)
+}
+
+func (m *MockSearchIndex) DeleteProduct(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSearchIndex) CreateIndex(ctx context.Context) (string, error) {
+	args := m.Called(ctx)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockSearchIndex) BulkIndex(ctx context.Context, index string, docs []*entity.ProductSearchDocument) error {
+	args := m.Called(ctx, index, docs)
+	return args.Error(0)
+}
+
+func (m *MockSearchIndex) SwitchAlias(ctx context.Context, index string) error {
+	args := m.Called(ctx, index)
+	return args.Error(0)
+}
+
+// MockRateCounter мок для RateCounter (счетчики частоты запросов в Redis)
+type MockRateCounter struct {
+	mock.Mock
+}
+
+func (m *MockRateCounter) Increment(ctx context.Context, key string, window time.Duration) (int64, error) {
+	args := m.Called(ctx, key, window)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockFeedFetcher мок для FeedFetcher (загрузка фидов поставщиков)
+type MockFeedFetcher struct {
+	mock.Mock
+}
+
+func (m *MockFeedFetcher) Fetch(ctx context.Context, url string) (io.ReadCloser, error) {
+	args := m.Called(ctx, url)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
 // MockMessagePublisher мок для MessagePublisher (Kafka)
 type MockMessagePublisher struct {
 	mock.Mock
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/pkg/money"
+
+	"gorm.io/gorm"
+)
+
+// maxRatingFacet - наибольшая оценка в фасете "N звезд и выше"
+const maxRatingFacet = 4
+
+// bucketCount - строка агрегата по номеру диапазона
+type bucketCount struct {
+	Bucket int
+	Count  int64
+}
+
+// Facets считает фасеты по товарам, подходящим под фильтр
+// Каждый фасет - один агрегирующий запрос по отфильтрованным товарам
+// priceBounds - возрастающие границы ценовых диапазонов
+func (r *productRepository) Facets(ctx context.Context, filter entity.ProductFilter, priceBounds []money.Amount) (*entity.ProductFacets, error) {
+	facets := &entity.ProductFacets{}
+
+	if err := r.facetQuery(ctx, filter).
+		Select("c.id, c.name, COUNT(*) AS count").
+		Joins("JOIN categories c ON c.id = p.category_id").
+		Group("c.id, c.name").
+		Order("count DESC, c.name ASC").
+		Scan(&facets.Categories).Error; err != nil {
+		return nil, fmt.Errorf("failed to count category facets: %w", err)
+	}
+
+	if err := r.facetQuery(ctx, filter).
+		Select("b.id, b.name, COUNT(*) AS count").
+		Joins("JOIN brands b ON b.id = p.brand_id").
+		Group("b.id, b.name").
+		Order("count DESC, b.name ASC").
+		Scan(&facets.Brands).Error; err != nil {
+		return nil, fmt.Errorf("failed to count brand facets: %w", err)
+	}
+
+	if err := r.facetQuery(ctx, filter).
+		Select("t.slug, t.name, COUNT(*) AS count").
+		Joins("JOIN product_tags pt ON pt.product_id = p.id").
+		Joins("JOIN tags t ON t.id = pt.tag_id").
+		Group("t.id, t.slug, t.name").
+		Order("count DESC, t.name ASC").
+		Scan(&facets.Tags).Error; err != nil {
+		return nil, fmt.Errorf("failed to count tag facets: %w", err)
+	}
+
+	var prices []bucketCount
+	expr, args := priceBucketExpr(priceBounds)
+	if err := r.facetQuery(ctx, filter).
+		Select("("+expr+") AS bucket, COUNT(*) AS count", args...).
+		Group("bucket").
+		Scan(&prices).Error; err != nil {
+		return nil, fmt.Errorf("failed to count price facets: %w", err)
+	}
+	facets.PriceRanges = priceFacets(priceBounds, prices)
+
+	var ratings []bucketCount
+	if err := r.facetQuery(ctx, filter).
+		Select("FLOOR(p.rating_avg)::int AS bucket, COUNT(*) AS count").
+		Where("p.rating_count > 0").
+		Group("bucket").
+		Scan(&ratings).Error; err != nil {
+		return nil, fmt.Errorf("failed to count rating facets: %w", err)
+	}
+	facets.Ratings = ratingFacets(ratings)
+
+	return facets, nil
+}
+
+// facetQuery - запрос по отфильтрованным товарам магазина под псевдонимом p
+// Подзапрос избавляет фильтры от неоднозначных колонок при JOIN
+func (r *productRepository) facetQuery(ctx context.Context, filter entity.ProductFilter) *gorm.DB {
+	products := filterProducts(ctx, r.db, scoped(ctx, r.db).Model(&entity.Product{}), filter).
+		Select("id, category_id, brand_id, price, rating_avg, rating_count")
+
+	return r.db.WithContext(ctx).Table("(?) AS p", products)
+}
+
+// priceBucketExpr строит CASE с номером ценового диапазона для каждой границы
+// Цена ниже первой границы - диапазон 0, не ниже последней - len(bounds)
+func priceBucketExpr(bounds []money.Amount) (string, []interface{}) {
+	if len(bounds) == 0 {
+		return "0", nil
+	}
+
+	var b strings.Builder
+	args := make([]interface{}, 0, len(bounds))
+
+	b.WriteString("CASE")
+	for i, bound := range bounds {
+		fmt.Fprintf(&b, " WHEN p.price < ? THEN %d", i)
+		args = append(args, bound)
+	}
+	fmt.Fprintf(&b, " ELSE %d END", len(bounds))
+
+	return b.String(), args
+}
+
+// priceFacets превращает счетчики по номерам диапазонов в диапазоны цен
+// Пустые диапазоны не возвращаются
+func priceFacets(bounds []money.Amount, counts []bucketCount) []entity.PriceFacet {
+	byBucket := make(map[int]int64, len(counts))
+	for _, c := range counts {
+		byBucket[c.Bucket] = c.Count
+	}
+
+	facets := []entity.PriceFacet{}
+	for i := 0; i <= len(bounds); i++ {
+		count := byBucket[i]
+		if count == 0 {
+			continue
+		}
+
+		facet := entity.PriceFacet{Count: count}
+		if i > 0 {
+			facet.From = bounds[i-1]
+		}
+		if i < len(bounds) {
+			to := bounds[i]
+			facet.To = &to
+		}
+		facets = append(facets, facet)
+	}
+
+	return facets
+}
+
+// ratingFacets превращает счетчики по целой части оценки в накопительные "N звезд и выше"
+func ratingFacets(counts []bucketCount) []entity.RatingFacet {
+	byStars := make(map[int]int64, len(counts))
+	for _, c := range counts {
+		stars := c.Bucket
+		if stars > maxRatingFacet {
+			stars = maxRatingFacet
+		}
+		byStars[stars] += c.Count
+	}
+
+	facets := []entity.RatingFacet{}
+	var total int64
+	for stars := maxRatingFacet; stars >= 1; stars-- {
+		total += byStars[stars]
+		if total > 0 {
+			facets = append(facets, entity.RatingFacet{MinRating: stars, Count: total})
+		}
+	}
+
+	return facets
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"testing"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ==================== Price Facets Tests ====================
+
+func TestPriceBucketExpr(t *testing.T) {
+	// Arrange
+	bounds := []money.Amount{money.MustParse("10"), money.MustParse("50")}
+
+	// Act
+	expr, args := priceBucketExpr(bounds)
+
+	// Assert
+	assert.Equal(t, "CASE WHEN p.price < ? THEN 0 WHEN p.price < ? THEN 1 ELSE 2 END", expr)
+	assert.Equal(t, []interface{}{bounds[0], bounds[1]}, args)
+}
+
+func TestPriceFacets_SkipsEmptyRanges(t *testing.T) {
+	// Arrange
+	bounds := []money.Amount{money.MustParse("10"), money.MustParse("50")}
+	counts := []bucketCount{{Bucket: 0, Count: 3}, {Bucket: 2, Count: 1}}
+
+	// Act
+	facets := priceFacets(bounds, counts)
+
+	// Assert
+	to := bounds[0]
+	assert.Equal(t, []entity.PriceFacet{
+		{From: 0, To: &to, Count: 3},
+		{From: bounds[1], Count: 1},
+	}, facets)
+}
+
+// ==================== Rating Facets Tests ====================
+
+func TestRatingFacets_Cumulative(t *testing.T) {
+	// Arrange: оценка 5.00 попадает в "4 и выше"
+	counts := []bucketCount{
+		{Bucket: 5, Count: 1},
+		{Bucket: 4, Count: 2},
+		{Bucket: 2, Count: 4},
+	}
+
+	// Act
+	facets := ratingFacets(counts)
+
+	// Assert
+	assert.Equal(t, []entity.RatingFacet{
+		{MinRating: 4, Count: 3},
+		{MinRating: 3, Count: 3},
+		{MinRating: 2, Count: 7},
+		{MinRating: 1, Count: 7},
+	}, facets)
+}
+
+func TestRatingFacets_NoRatings(t *testing.T) {
+	assert.Empty(t, ratingFacets(nil))
+}
@@ -3,15 +3,22 @@ package repository
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
 	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/pkg/tenant"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var (
-	ErrProductNotFound = errors.New("product not found")
+	ErrProductNotFound      = errors.New("product not found")
+	ErrProductStatusChanged = errors.New("product status changed concurrently")
+	// ErrPriceChanged - цену товара изменили после расчета массового изменения цен
+	ErrPriceChanged = errors.New("product price changed concurrently")
 )
 
 type productRepository struct {
@@ -23,8 +30,15 @@ func NewProductRepository(db *gorm.DB) ProductRepository {
 	return &productRepository{db: db}
 }
 
-// Create создает новый товар
+// Create создает новый товар со свободным slug
 func (r *productRepository) Create(ctx context.Context, product *entity.Product) error {
+	slug, err := uniqueSlug(ctx, r.db, &entity.Product{}, product.Name, entity.SlugEntityProduct, uuid.Nil)
+	if err != nil {
+		return err
+	}
+	product.Slug = slug
+
+	product.TenantID = tenant.FromContext(ctx)
 	result := r.db.WithContext(ctx).Create(product)
 	return result.Error
 }
@@ -32,7 +46,7 @@ func (r *productRepository) Create(ctx context.Context, product *entity.Product)
 // GetByID получает товар по ID
 func (r *productRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Product, error) {
 	var product entity.Product
-	result := r.db.WithContext(ctx).First(&product, "id = ?", id)
+	result := scoped(ctx, r.db).First(&product, "id = ?", id)
 
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -44,10 +58,114 @@ func (r *productRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.
 	return &product, nil
 }
 
-// GetAll получает все товары
+// GetByIDs получает товары с категориями по списку ID
+// Отсутствующие товары просто не попадают в результат
+func (r *productRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.Product, error) {
+	var products []entity.Product
+	result := scoped(ctx, r.db).Preload("Category").Where("id IN ?", ids).Find(&products)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return products, nil
+}
+
+// GetAvailability получает цену, статус и остаток товаров по списку ID одним запросом
+// Читаются только нужные для заказа колонки, отсутствующие товары не попадают в результат
+func (r *productRepository) GetAvailability(ctx context.Context, ids []uuid.UUID) ([]entity.ProductAvailability, error) {
+	var availability []entity.ProductAvailability
+	result := r.db.WithContext(ctx).Model(&entity.Product{}).
+		Select("products.id, products.name, products.description, products.price, products.category_id, "+
+			"categories.name AS category_name, products.status, products.stock, products.unit, products.quantity_step, "+
+			"products.min_qty, products.max_qty, products.per_customer_limit").
+		Joins("LEFT JOIN categories ON categories.id = products.category_id").
+		Where("products.tenant_id = ? AND products.id IN ?", tenant.FromContext(ctx), ids).
+		Scan(&availability)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return availability, nil
+}
+
+// GetAll получает все товары, кроме удаленных
 func (r *productRepository) GetAll(ctx context.Context) ([]entity.Product, error) {
 	var products []entity.Product
-	result := r.db.WithContext(ctx).Order("created_at DESC").Find(&products)
+	result := scoped(ctx, r.db).Where("deleted_at IS NULL").Order("created_at DESC, id DESC").Find(&products)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return products, nil
+}
+
+// Search ищет опубликованные товары по подстроке в названии или описании (резервный поиск без OpenSearch)
+// Возвращает страницу товаров и общее число найденных
+func (r *productRepository) Search(ctx context.Context, query entity.ProductSearchQuery) ([]entity.Product, int64, error) {
+	pattern := "%" + escapeLike(query.Text) + "%"
+	matching := func() *gorm.DB {
+		q := scoped(ctx, r.db).Model(&entity.Product{}).
+			Where("status = ?", entity.ProductStatusPublished).
+			Where("(name ILIKE ? OR description ILIKE ?)", pattern, pattern)
+		if query.CategoryID != nil {
+			q = q.Where("category_id = ?", *query.CategoryID)
+		}
+		return q
+	}
+
+	var total int64
+	if err := matching().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var products []entity.Product
+	// Совпадение в названии важнее совпадения в описании
+	err := matching().Order(clause.Expr{SQL: "CASE WHEN name ILIKE ? THEN 0 ELSE 1 END, name", Vars: []interface{}{pattern}}).
+		Offset(query.Offset).Limit(query.Limit).Find(&products).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return products, total, nil
+}
+
+// escapeLike экранирует спецсимволы шаблона LIKE во введенной строке
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// ListPublishedAfter возвращает опубликованные товары всех магазинов с ID больше afterID
+// Используется для перестроения поискового индекса пачками
+func (r *productRepository) ListPublishedAfter(ctx context.Context, afterID uuid.UUID, limit int) ([]entity.Product, error) {
+	var products []entity.Product
+	result := r.db.WithContext(ctx).
+		Where("status = ? AND id > ?", entity.ProductStatusPublished, afterID).
+		Order("id").Limit(limit).Find(&products)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return products, nil
+}
+
+// ListChanged получает товары магазина в порядке изменения для выгрузки каталога
+// Удаленные товары тоже выгружаются, чтобы получатель мог их удалить у себя.
+// Сравнение по паре (updated_at, id) не теряет товары с одинаковым временем изменения на границе страниц
+func (r *productRepository) ListChanged(ctx context.Context, query entity.ProductStreamQuery) ([]entity.Product, error) {
+	db := scoped(ctx, r.db).Preload("Tags", orderTags)
+	if query.UpdatedSince != nil {
+		db = db.Where("updated_at >= ?", *query.UpdatedSince)
+	}
+	if query.Cursor != nil {
+		db = db.Where("(updated_at, id) > (?, ?)", query.Cursor.UpdatedAt, query.Cursor.ID)
+	}
+
+	var products []entity.Product
+	result := db.Order("updated_at, id").Limit(query.Limit).Find(&products)
 
 	if result.Error != nil {
 		return nil, result.Error
@@ -56,10 +174,205 @@ func (r *productRepository) GetAll(ctx context.Context) ([]entity.Product, error
 	return products, nil
 }
 
-// GetWithCategory получает товар с информацией о категории
+// ListPopular получает опубликованные товары магазина с наибольшим числом отзывов
+func (r *productRepository) ListPopular(ctx context.Context, limit int) ([]entity.ProductWithCategory, error) {
+	var products []entity.Product
+	result := scoped(ctx, r.db).Preload("Category").Preload("Brand").Preload("Tags", orderTags).
+		Where("status = ?", entity.ProductStatusPublished).
+		Order("rating_count DESC, rating_avg DESC, id").Limit(limit).Find(&products)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	popular := make([]entity.ProductWithCategory, 0, len(products))
+	for _, p := range products {
+		pwc := entity.ProductWithCategory{Product: p}
+		if p.Category != nil {
+			pwc.Category = *p.Category
+		}
+		popular = append(popular, pwc)
+	}
+
+	return popular, nil
+}
+
+// CountByStatus считает товары магазина по статусам
+func (r *productRepository) CountByStatus(ctx context.Context) (map[entity.ProductStatus]int64, error) {
+	var rows []struct {
+		Status entity.ProductStatus
+		Count  int64
+	}
+	err := scoped(ctx, r.db).Model(&entity.Product{}).
+		Select("status, COUNT(*) AS count").Group("status").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[entity.ProductStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// ListRatings получает денормализованные оценки всех товаров магазина
+func (r *productRepository) ListRatings(ctx context.Context) ([]entity.ProductRating, error) {
+	var ratings []entity.ProductRating
+	result := scoped(ctx, r.db).Model(&entity.Product{}).
+		Select("id AS product_id", "rating_avg", "rating_count").
+		Order("id").Scan(&ratings)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return ratings, nil
+}
+
+// UpdateRatings обновляет оценки товаров в одной транзакции
+// updated_at не меняется: оценка - производные данные Reviews Service, а не правка товара
+func (r *productRepository) UpdateRatings(ctx context.Context, ratings []entity.ProductRating) (int, error) {
+	updated := 0
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, rating := range ratings {
+			result := scoped(ctx, tx).Model(&entity.Product{}).Where("id = ?", rating.ProductID).UpdateColumns(map[string]interface{}{
+				"rating_avg":   rating.RatingAvg,
+				"rating_count": rating.RatingCount,
+			})
+			if result.Error != nil {
+				return result.Error
+			}
+			updated += int(result.RowsAffected)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return updated, nil
+}
+
+// ListBySupplierSKUs получает товары поставщика по артикулам, включая удаленные
+// Отсутствующие артикулы просто не попадают в результат
+func (r *productRepository) ListBySupplierSKUs(ctx context.Context, supplierID uuid.UUID, skus []string) ([]entity.Product, error) {
+	var products []entity.Product
+	result := scoped(ctx, r.db).Where("supplier_id = ? AND supplier_sku IN ?", supplierID, skus).Find(&products)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return products, nil
+}
+
+// ListByCategory получает неудаленные товары категории
+func (r *productRepository) ListByCategory(ctx context.Context, categoryID uuid.UUID) ([]entity.Product, error) {
+	var products []entity.Product
+	result := scoped(ctx, r.db).Where("category_id = ? AND deleted_at IS NULL", categoryID).Order("id").Find(&products)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return products, nil
+}
+
+// UpdatePrices меняет цены товаров в одной транзакции
+// Условие по прежней цене защищает от перезаписи цены, измененной после расчета
+func (r *productRepository) UpdatePrices(ctx context.Context, changes []entity.PriceChange) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, change := range changes {
+			result := scoped(ctx, tx).Model(&entity.Product{}).
+				Where("id = ? AND price = ? AND deleted_at IS NULL", change.ProductID, change.OldPrice).
+				Update("price", change.NewPrice)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrPriceChanged
+			}
+		}
+		return nil
+	})
+}
+
+// SaveBatch создает и обновляет товары импорта фида в одной транзакции
+// Новым товарам подбирается свободный slug; у обновляемых меняются только поля фида
+func (r *productRepository) SaveBatch(ctx context.Context, created, updated []*entity.Product) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, product := range created {
+			slug, err := uniqueSlug(ctx, tx, &entity.Product{}, product.Name, entity.SlugEntityProduct, uuid.Nil)
+			if err != nil {
+				return err
+			}
+			product.Slug = slug
+			product.TenantID = tenant.FromContext(ctx)
+
+			if err := tx.WithContext(ctx).Create(product).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, product := range updated {
+			var current entity.Product
+			if err := scoped(ctx, tx).Select("name", "slug").First(&current, "id = ?", product.ID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ErrProductNotFound
+				}
+				return err
+			}
+
+			slug, err := renameSlug(ctx, tx, &entity.Product{}, entity.SlugEntityProduct, product.ID, current.Name, current.Slug, product.Name)
+			if err != nil {
+				return err
+			}
+			product.Slug = slug
+
+			err = scoped(ctx, tx).Model(&entity.Product{}).Where("id = ?", product.ID).Updates(map[string]interface{}{
+				"name":        product.Name,
+				"slug":        product.Slug,
+				"description": product.Description,
+				"price":       product.Price,
+				"stock":       product.Stock,
+				"cost_price":  product.CostPrice,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetWithCategory получает товар с информацией о категории и бренде
 func (r *productRepository) GetWithCategory(ctx context.Context, id uuid.UUID) (*entity.ProductWithCategory, error) {
+	return r.getWithCategory(ctx, "id = ?", id)
+}
+
+// GetBySlug получает товар с категорией и брендом по текущему slug
+func (r *productRepository) GetBySlug(ctx context.Context, slug string) (*entity.ProductWithCategory, error) {
+	return r.getWithCategory(ctx, "slug = ?", slug)
+}
+
+// GetByOldSlug получает товар с категорией и брендом по slug, который он имел до переименования
+func (r *productRepository) GetByOldSlug(ctx context.Context, slug string) (*entity.ProductWithCategory, error) {
+	id, err := findSlugRedirect(ctx, r.db, entity.SlugEntityProduct, slug)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	return r.getWithCategory(ctx, "id = ?", id)
+}
+
+func (r *productRepository) getWithCategory(ctx context.Context, query string, arg interface{}) (*entity.ProductWithCategory, error) {
 	var product entity.Product
-	result := r.db.WithContext(ctx).Preload("Category").First(&product, "id = ?", id)
+	result := scoped(ctx, r.db).Preload("Category").Preload("Brand").Preload("Tags", orderTags).First(&product, query, arg)
 
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -79,10 +392,40 @@ func (r *productRepository) GetWithCategory(ctx context.Context, id uuid.UUID) (
 	return pwc, nil
 }
 
-// GetAllWithCategories получает все товары с информацией о категориях
-func (r *productRepository) GetAllWithCategories(ctx context.Context) ([]entity.ProductWithCategory, error) {
+// filterProducts применяет фильтры списка товаров к запросу по таблице products
+func filterProducts(ctx context.Context, db, query *gorm.DB, filter entity.ProductFilter) *gorm.DB {
+	if filter.CategoryID != nil {
+		query = query.Where("category_id = ?", *filter.CategoryID)
+	}
+	if filter.BrandID != nil {
+		query = query.Where("brand_id = ?", *filter.BrandID)
+	}
+	if filter.SupplierID != nil {
+		query = query.Where("supplier_id = ?", *filter.SupplierID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	// Удаленные товары остаются в базе для заказов и отзывов, но в списки не попадают
+	query = query.Where("deleted_at IS NULL")
+	if len(filter.Tags) > 0 {
+		query = query.Where("id IN (?)", productsWithTags(ctx, db, filter.Tags))
+	}
+	return query
+}
+
+// orderTags сортирует теги товара по названию при Preload
+func orderTags(db *gorm.DB) *gorm.DB {
+	return db.Order("tags.name ASC")
+}
+
+// GetAllWithCategories получает товары с информацией о категориях и брендах
+// Пустой фильтр возвращает все товары
+func (r *productRepository) GetAllWithCategories(ctx context.Context, filter entity.ProductFilter) ([]entity.ProductWithCategory, error) {
 	var products []entity.Product
-	result := r.db.WithContext(ctx).Preload("Category").Order("created_at DESC").Find(&products)
+	query := filterProducts(ctx, r.db, scoped(ctx, r.db).Preload("Category").Preload("Brand").Preload("Tags", orderTags), filter)
+
+	result := query.Order("created_at DESC, id DESC").Find(&products)
 
 	if result.Error != nil {
 		return nil, result.Error
@@ -104,13 +447,79 @@ func (r *productRepository) GetAllWithCategories(ctx context.Context) ([]entity.
 }
 
 // Update обновляет товар
+// При смене названия меняет slug и сохраняет прежний в истории
 func (r *productRepository) Update(ctx context.Context, product *entity.Product) error {
-	result := r.db.WithContext(ctx).Model(product).Where("id = ?", product.ID).Updates(map[string]interface{}{
-		"name":        product.Name,
-		"description": product.Description,
-		"price":       product.Price,
-		"category_id": product.CategoryID,
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current entity.Product
+		if err := scoped(ctx, tx).Select("name", "slug").First(&current, "id = ?", product.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProductNotFound
+			}
+			return err
+		}
+
+		slug, err := renameSlug(ctx, tx, &entity.Product{}, entity.SlugEntityProduct, product.ID, current.Name, current.Slug, product.Name)
+		if err != nil {
+			return err
+		}
+		product.Slug = slug
+
+		result := scoped(ctx, tx).Model(product).Where("id = ?", product.ID).Updates(map[string]interface{}{
+			"name":               product.Name,
+			"slug":               product.Slug,
+			"description":        product.Description,
+			"price":              product.Price,
+			"category_id":        product.CategoryID,
+			"brand_id":           product.BrandID,
+			"supplier_id":        product.SupplierID,
+			"stock":              product.Stock,
+			"cost_price":         product.CostPrice,
+			"unit":               product.Unit,
+			"quantity_step":      product.QuantityStep,
+			"min_qty":            product.MinQty,
+			"max_qty":            product.MaxQty,
+			"per_customer_limit": product.PerCustomerLimit,
+		})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrProductNotFound
+		}
+
+		return nil
 	})
+}
+
+// UpdateStatus переводит товар из статуса from в статус to
+// Условие по текущему статусу защищает от параллельных переходов
+func (r *productRepository) UpdateStatus(ctx context.Context, id uuid.UUID, from, to entity.ProductStatus) error {
+	result := scoped(ctx, r.db).Model(&entity.Product{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrProductStatusChanged
+	}
+
+	return nil
+}
+
+// SoftDelete снимает товар с продажи и отмечает удаленным
+// Уже удаленный товар - ErrProductNotFound
+func (r *productRepository) SoftDelete(ctx context.Context, id uuid.UUID, at time.Time) error {
+	result := scoped(ctx, r.db).Model(&entity.Product{}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"status":     entity.ProductStatusArchived,
+			"deleted_at": at,
+		})
 
 	if result.Error != nil {
 		return result.Error
@@ -125,7 +534,7 @@ func (r *productRepository) Update(ctx context.Context, product *entity.Product)
 
 // Delete удаляет товар
 func (r *productRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	result := r.db.WithContext(ctx).Delete(&entity.Product{}, "id = ?", id)
+	result := scoped(ctx, r.db).Delete(&entity.Product{}, "id = ?", id)
 
 	if result.Error != nil {
 		return result.Error
@@ -137,3 +546,17 @@ func (r *productRepository) Delete(ctx context.Context, id uuid.UUID) error {
 
 	return nil
 }
+
+// GetDetailsByIDs получает неудаленные товары с категорией, брендом и тегами по списку ID
+// Отсутствующие товары не попадают в результат
+func (r *productRepository) GetDetailsByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.Product, error) {
+	var products []entity.Product
+	result := scoped(ctx, r.db).Preload("Category").Preload("Brand").Preload("Tags", orderTags).
+		Where("id IN ? AND deleted_at IS NULL", ids).Find(&products)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return products, nil
+}
@@ -2,28 +2,149 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/pkg/money"
+	"augustberries/pkg/tenant"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// scoped ограничивает запрос данными магазина из контекста
+// Все запросы репозиториев, кроме вставки, должны строиться через scoped
+func scoped(ctx context.Context, db *gorm.DB) *gorm.DB {
+	return db.WithContext(ctx).Where("tenant_id = ?", tenant.FromContext(ctx))
+}
+
 // CategoryRepository определяет методы для работы с категориями
 type CategoryRepository interface {
 	Create(ctx context.Context, category *entity.Category) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.Category, error)
+	GetBySlug(ctx context.Context, slug string) (*entity.Category, error)
+	GetByOldSlug(ctx context.Context, slug string) (*entity.Category, error)
 	GetAll(ctx context.Context) ([]entity.Category, error)
 	Update(ctx context.Context, category *entity.Category) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	// ListTenants возвращает магазины, в которых есть категории (без учета магазина запроса)
+	ListTenants(ctx context.Context) ([]string, error)
+	// Count возвращает число категорий магазина
+	Count(ctx context.Context) (int64, error)
+	// RefreshProductCounts пересчитывает product_count категорий всех магазинов
+	// Возвращает магазины, в которых изменилось число товаров хотя бы одной категории
+	RefreshProductCounts(ctx context.Context) ([]string, error)
 }
 
 // ProductRepository определяет методы для работы с товарами
 type ProductRepository interface {
 	Create(ctx context.Context, product *entity.Product) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.Product, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.Product, error)
+	GetAvailability(ctx context.Context, ids []uuid.UUID) ([]entity.ProductAvailability, error)
 	GetAll(ctx context.Context) ([]entity.Product, error)
 	GetWithCategory(ctx context.Context, id uuid.UUID) (*entity.ProductWithCategory, error)
-	GetAllWithCategories(ctx context.Context) ([]entity.ProductWithCategory, error)
+	GetBySlug(ctx context.Context, slug string) (*entity.ProductWithCategory, error)
+	GetByOldSlug(ctx context.Context, slug string) (*entity.ProductWithCategory, error)
+	GetAllWithCategories(ctx context.Context, filter entity.ProductFilter) ([]entity.ProductWithCategory, error)
+	Facets(ctx context.Context, filter entity.ProductFilter, priceBounds []money.Amount) (*entity.ProductFacets, error)
 	Update(ctx context.Context, product *entity.Product) error
+	UpdateStatus(ctx context.Context, id uuid.UUID, from, to entity.ProductStatus) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// SoftDelete архивирует товар и отмечает его удаленным; строка остается для заказов и отзывов
+	SoftDelete(ctx context.Context, id uuid.UUID, at time.Time) error
+	// Search - резервный полнотекстовый поиск, когда OpenSearch недоступен
+	Search(ctx context.Context, query entity.ProductSearchQuery) ([]entity.Product, int64, error)
+	// ListPublishedAfter читает опубликованные товары всех магазинов по возрастанию ID (без учета магазина запроса)
+	ListPublishedAfter(ctx context.Context, afterID uuid.UUID, limit int) ([]entity.Product, error)
+	// ListChanged возвращает товары магазина, включая удаленные, по возрастанию (updated_at, id) после позиции query.Cursor
+	ListChanged(ctx context.Context, query entity.ProductStreamQuery) ([]entity.Product, error)
+	// ListPopular возвращает самые популярные опубликованные товары магазина (по числу отзывов)
+	ListPopular(ctx context.Context, limit int) ([]entity.ProductWithCategory, error)
+	// CountByStatus возвращает число товаров магазина в каждом статусе
+	CountByStatus(ctx context.Context) (map[entity.ProductStatus]int64, error)
+	// ListRatings возвращает оценки всех товаров магазина
+	ListRatings(ctx context.Context) ([]entity.ProductRating, error)
+	// UpdateRatings сохраняет оценки товаров магазина и возвращает число обновленных товаров
+	// Отсутствующие в магазине товары пропускаются
+	UpdateRatings(ctx context.Context, ratings []entity.ProductRating) (int, error)
+	// ListBySupplierSKUs возвращает товары поставщика по артикулам (для импорта фида), включая удаленные
+	ListBySupplierSKUs(ctx context.Context, supplierID uuid.UUID, skus []string) ([]entity.Product, error)
+	// SaveBatch создает и обновляет товары пачки импорта в одной транзакции
+	SaveBatch(ctx context.Context, created, updated []*entity.Product) error
+	// ListByCategory возвращает неудаленные товары категории магазина
+	ListByCategory(ctx context.Context, categoryID uuid.UUID) ([]entity.Product, error)
+	// UpdatePrices меняет цены товаров в одной транзакции; если цена товара уже отличается от OldPrice,
+	// транзакция откатывается с ErrPriceChanged
+	UpdatePrices(ctx context.Context, changes []entity.PriceChange) error
+	// GetDetailsByIDs возвращает неудаленные товары магазина с категорией, брендом и тегами
+	GetDetailsByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.Product, error)
+}
+
+// BrandRepository определяет методы для работы с брендами
+type BrandRepository interface {
+	Create(ctx context.Context, brand *entity.Brand) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Brand, error)
+	GetAll(ctx context.Context) ([]entity.Brand, error)
+	Update(ctx context.Context, brand *entity.Brand) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// TagRepository определяет методы для работы с тегами товаров
+type TagRepository interface {
+	Create(ctx context.Context, tag *entity.Tag) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Tag, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.Tag, error)
+	GetAll(ctx context.Context) ([]entity.Tag, error)
+	Update(ctx context.Context, tag *entity.Tag) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	SetProductTags(ctx context.Context, productID uuid.UUID, tagIDs []uuid.UUID) error
+}
+
+// TranslationRepository определяет методы для работы с переводами товаров
+type TranslationRepository interface {
+	Upsert(ctx context.Context, translation *entity.ProductTranslation) error
+	ListByProduct(ctx context.Context, productID uuid.UUID) ([]entity.ProductTranslation, error)
+	GetForProducts(ctx context.Context, productIDs []uuid.UUID, locales []string) ([]entity.ProductTranslation, error)
+	Delete(ctx context.Context, productID uuid.UUID, locale string) error
+}
+
+// ScheduledPriceRepository определяет методы для работы с запланированными ценами
+type ScheduledPriceRepository interface {
+	Create(ctx context.Context, sp *entity.ScheduledPrice) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.ScheduledPrice, error)
+	ListByProduct(ctx context.Context, productID uuid.UUID) ([]entity.ScheduledPrice, error)
+	Cancel(ctx context.Context, id uuid.UUID) error
+	ListDue(ctx context.Context, now time.Time, limit int) ([]entity.ScheduledPrice, error)
+	Activate(ctx context.Context, sp *entity.ScheduledPrice) (oldPrice money.Amount, err error)
+	Complete(ctx context.Context, sp *entity.ScheduledPrice) (restored bool, err error)
+}
+
+// AuditRepository определяет методы для работы с журналом изменений каталога
+type AuditRepository interface {
+	Create(ctx context.Context, entry *entity.AuditEntry) error
+	ListByEntity(ctx context.Context, entityType string, entityID uuid.UUID, limit int) ([]entity.AuditEntry, error)
+}
+
+// SupplierRepository определяет методы для работы с поставщиками
+type SupplierRepository interface {
+	Create(ctx context.Context, supplier *entity.Supplier) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Supplier, error)
+	GetAll(ctx context.Context) ([]entity.Supplier, error)
+	Update(ctx context.Context, supplier *entity.Supplier) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// FeedRepository определяет методы для работы с фидами поставщиков и статистикой импорта
+type FeedRepository interface {
+	Create(ctx context.Context, feed *entity.SupplierFeed) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.SupplierFeed, error)
+	List(ctx context.Context) ([]entity.SupplierFeed, error)
+	Update(ctx context.Context, feed *entity.SupplierFeed) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	// ListDue возвращает фиды всех магазинов, которые пора загрузить (без учета магазина запроса)
+	ListDue(ctx context.Context, now time.Time, limit int) ([]entity.SupplierFeed, error)
+	MarkRun(ctx context.Context, id uuid.UUID, at time.Time) error
+	SaveImport(ctx context.Context, imp *entity.FeedImport) error
+	ListImports(ctx context.Context, feedID uuid.UUID, limit int) ([]entity.FeedImport, error)
 }
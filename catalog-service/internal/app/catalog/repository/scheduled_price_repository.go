@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/pkg/money"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrScheduledPriceNotFound = errors.New("scheduled price not found")
+	// ErrScheduledPriceChanged - статус запланированной цены уже изменен (другим экземпляром или пользователем)
+	ErrScheduledPriceChanged = errors.New("scheduled price status changed concurrently")
+)
+
+type scheduledPriceRepository struct {
+	db *gorm.DB
+}
+
+// NewScheduledPriceRepository создает новый репозиторий запланированных цен
+func NewScheduledPriceRepository(db *gorm.DB) ScheduledPriceRepository {
+	return &scheduledPriceRepository{db: db}
+}
+
+// Create сохраняет запланированную цену
+func (r *scheduledPriceRepository) Create(ctx context.Context, sp *entity.ScheduledPrice) error {
+	sp.TenantID = tenant.FromContext(ctx)
+	return r.db.WithContext(ctx).Create(sp).Error
+}
+
+// GetByID получает запланированную цену по ID
+func (r *scheduledPriceRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.ScheduledPrice, error) {
+	var sp entity.ScheduledPrice
+	result := scoped(ctx, r.db).First(&sp, "id = ?", id)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrScheduledPriceNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &sp, nil
+}
+
+// ListByProduct возвращает цены товара в порядке начала действия
+func (r *scheduledPriceRepository) ListByProduct(ctx context.Context, productID uuid.UUID) ([]entity.ScheduledPrice, error) {
+	var prices []entity.ScheduledPrice
+	result := scoped(ctx, r.db).Where("product_id = ?", productID).Order("effective_from ASC").Find(&prices)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return prices, nil
+}
+
+// Cancel отменяет цену, которая еще не вступила в силу
+func (r *scheduledPriceRepository) Cancel(ctx context.Context, id uuid.UUID) error {
+	result := scoped(ctx, r.db).Model(&entity.ScheduledPrice{}).
+		Where("id = ? AND status = ?", id, entity.ScheduledPricePending).
+		Update("status", entity.ScheduledPriceCancelled)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrScheduledPriceChanged
+	}
+
+	return nil
+}
+
+// ListDue возвращает цены всех магазинов, которые пора применить или вернуть
+// Единственный запрос без scoped: фоновая задача обслуживает все магазины
+func (r *scheduledPriceRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]entity.ScheduledPrice, error) {
+	var prices []entity.ScheduledPrice
+	result := r.db.WithContext(ctx).
+		Where("(status = ? AND effective_from <= ?) OR (status = ? AND effective_to <= ?)",
+			entity.ScheduledPricePending, now, entity.ScheduledPriceActive, now).
+		Order("COALESCE(effective_to, effective_from) ASC").
+		Limit(limit).
+		Find(&prices)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return prices, nil
+}
+
+// Activate применяет цену к товару и запоминает прежнюю
+// Цена без окна сразу завершается, с окном - становится активной до EffectiveTo
+func (r *scheduledPriceRepository) Activate(ctx context.Context, sp *entity.ScheduledPrice) (money.Amount, error) {
+	var oldPrice money.Amount
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var product entity.Product
+		if err := scoped(ctx, tx).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "price").First(&product, "id = ?", sp.ProductID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProductNotFound
+			}
+			return err
+		}
+		oldPrice = product.Price
+
+		status := entity.ScheduledPriceCompleted
+		if sp.EffectiveTo != nil {
+			status = entity.ScheduledPriceActive
+		}
+
+		result := scoped(ctx, tx).Model(&entity.ScheduledPrice{}).
+			Where("id = ? AND status = ?", sp.ID, entity.ScheduledPricePending).
+			Updates(map[string]interface{}{"status": status, "previous_price": oldPrice})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrScheduledPriceChanged
+		}
+
+		if err := scoped(ctx, tx).Model(&entity.Product{}).Where("id = ?", sp.ProductID).Update("price", sp.Price).Error; err != nil {
+			return err
+		}
+
+		sp.Status = status
+		sp.PreviousPrice = &oldPrice
+		return nil
+	})
+
+	return oldPrice, err
+}
+
+// Complete завершает окно распродажи и возвращает прежнюю цену
+// Если цену товара за время распродажи изменили вручную, она сохраняется (restored = false)
+func (r *scheduledPriceRepository) Complete(ctx context.Context, sp *entity.ScheduledPrice) (restored bool, err error) {
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := scoped(ctx, tx).Model(&entity.ScheduledPrice{}).
+			Where("id = ? AND status = ?", sp.ID, entity.ScheduledPriceActive).
+			Update("status", entity.ScheduledPriceCompleted)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrScheduledPriceChanged
+		}
+
+		if sp.PreviousPrice != nil {
+			result = scoped(ctx, tx).Model(&entity.Product{}).
+				Where("id = ? AND price = ?", sp.ProductID, sp.Price).
+				Update("price", *sp.PreviousPrice)
+			if result.Error != nil {
+				return result.Error
+			}
+			restored = result.RowsAffected > 0
+		}
+
+		sp.Status = entity.ScheduledPriceCompleted
+		return nil
+	})
+
+	return restored, err
+}
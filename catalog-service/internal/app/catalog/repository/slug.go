@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/util"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// uniqueSlug подбирает свободный slug для названия в пределах магазина: base, base-2, base-3...
+// Для названия без латиницы, кириллицы и цифр используется fallback (тип сущности)
+// excludeID исключает саму сущность при переименовании
+func uniqueSlug(ctx context.Context, db *gorm.DB, model interface{}, name, fallback string, excludeID uuid.UUID) (string, error) {
+	base := util.Slugify(name)
+	if base == "" {
+		base = fallback
+	}
+
+	query := scoped(ctx, db).Model(model).Where("slug = ? OR slug LIKE ?", base, base+"-%")
+	if excludeID != uuid.Nil {
+		query = query.Where("id <> ?", excludeID)
+	}
+
+	var taken []string
+	if err := query.Pluck("slug", &taken).Error; err != nil {
+		return "", fmt.Errorf("failed to check slug: %w", err)
+	}
+
+	used := make(map[string]bool, len(taken))
+	for _, slug := range taken {
+		used[slug] = true
+	}
+
+	if !used[base] {
+		return base, nil
+	}
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", base, i)
+		if !used[candidate] {
+			return candidate, nil
+		}
+	}
+}
+
+// saveSlugRedirect запоминает прежний slug сущности
+// Если этот slug раньше вел на другую сущность, перенаправление перезаписывается
+func saveSlugRedirect(ctx context.Context, tx *gorm.DB, entityType, slug string, entityID uuid.UUID) error {
+	redirect := &entity.SlugRedirect{
+		ID:         uuid.New(),
+		TenantID:   tenant.FromContext(ctx),
+		EntityType: entityType,
+		Slug:       slug,
+		EntityID:   entityID,
+	}
+
+	err := tx.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "entity_type"}, {Name: "slug"}},
+		DoUpdates: clause.AssignmentColumns([]string{"entity_id", "created_at"}),
+	}).Create(redirect).Error
+	if err != nil {
+		return fmt.Errorf("failed to save slug redirect: %w", err)
+	}
+	return nil
+}
+
+// findSlugRedirect возвращает ID сущности, которой раньше принадлежал slug
+func findSlugRedirect(ctx context.Context, db *gorm.DB, entityType, slug string) (uuid.UUID, error) {
+	var redirect entity.SlugRedirect
+	err := scoped(ctx, db).First(&redirect, "entity_type = ? AND slug = ?", entityType, slug).Error
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return redirect.EntityID, nil
+}
+
+// renameSlug возвращает slug сущности после смены названия
+// Если slug меняется, прежний сохраняется в истории для перенаправления старых ссылок
+func renameSlug(ctx context.Context, tx *gorm.DB, model interface{}, entityType string, id uuid.UUID, oldName, oldSlug, newName string) (string, error) {
+	if util.Slugify(newName) == util.Slugify(oldName) {
+		return oldSlug, nil
+	}
+
+	slug, err := uniqueSlug(ctx, tx, model, newName, entityType, id)
+	if err != nil {
+		return "", err
+	}
+
+	if oldSlug != "" && oldSlug != slug {
+		if err := saveSlugRedirect(ctx, tx, entityType, oldSlug, id); err != nil {
+			return "", err
+		}
+	}
+	return slug, nil
+}
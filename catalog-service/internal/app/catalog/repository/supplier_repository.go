@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSupplierNotFound      = errors.New("supplier not found")
+	ErrSupplierAlreadyExists = errors.New("supplier with this name already exists")
+	ErrSupplierHasProducts   = errors.New("cannot delete supplier with existing products")
+)
+
+type supplierRepository struct {
+	db *gorm.DB
+}
+
+// NewSupplierRepository создает новый репозиторий поставщиков
+func NewSupplierRepository(db *gorm.DB) SupplierRepository {
+	return &supplierRepository{db: db}
+}
+
+// Create создает нового поставщика
+func (r *supplierRepository) Create(ctx context.Context, supplier *entity.Supplier) error {
+	supplier.TenantID = tenant.FromContext(ctx)
+	result := r.db.WithContext(ctx).Create(supplier)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return ErrSupplierAlreadyExists
+		}
+		return result.Error
+	}
+	return nil
+}
+
+// GetByID получает поставщика по ID
+func (r *supplierRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Supplier, error) {
+	var supplier entity.Supplier
+	result := scoped(ctx, r.db).First(&supplier, "id = ?", id)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrSupplierNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &supplier, nil
+}
+
+// GetAll получает всех поставщиков отсортированных по имени
+func (r *supplierRepository) GetAll(ctx context.Context) ([]entity.Supplier, error) {
+	var suppliers []entity.Supplier
+	result := scoped(ctx, r.db).Order("name ASC").Find(&suppliers)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return suppliers, nil
+}
+
+// Update обновляет данные поставщика
+func (r *supplierRepository) Update(ctx context.Context, supplier *entity.Supplier) error {
+	result := scoped(ctx, r.db).Model(supplier).Where("id = ?", supplier.ID).Updates(map[string]interface{}{
+		"name":          supplier.Name,
+		"contact_email": supplier.ContactEmail,
+		"phone":         supplier.Phone,
+	})
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return ErrSupplierAlreadyExists
+		}
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrSupplierNotFound
+	}
+
+	return nil
+}
+
+// Delete удаляет поставщика
+// Поставщик с привязанными товарами удалить нельзя
+func (r *supplierRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	var productCount int64
+	if err := scoped(ctx, r.db).Model(&entity.Product{}).Where("supplier_id = ?", id).Count(&productCount).Error; err != nil {
+		return err
+	}
+
+	if productCount > 0 {
+		return ErrSupplierHasProducts
+	}
+
+	result := scoped(ctx, r.db).Delete(&entity.Supplier{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrSupplierNotFound
+	}
+
+	return nil
+}
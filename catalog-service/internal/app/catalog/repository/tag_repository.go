@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTagNotFound      = errors.New("tag not found")
+	ErrTagAlreadyExists = errors.New("tag with this name already exists")
+)
+
+// productTag - строка связи товара с тегом
+type productTag struct {
+	ProductID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	TagID     uuid.UUID `gorm:"type:uuid;primaryKey"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (productTag) TableName() string {
+	return "product_tags"
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository создает новый репозиторий тегов
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// Create создает новый тег
+func (r *tagRepository) Create(ctx context.Context, tag *entity.Tag) error {
+	tag.TenantID = tenant.FromContext(ctx)
+	result := r.db.WithContext(ctx).Create(tag)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return ErrTagAlreadyExists
+		}
+		return result.Error
+	}
+	return nil
+}
+
+// GetByID получает тег по ID
+func (r *tagRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Tag, error) {
+	var tag entity.Tag
+	result := scoped(ctx, r.db).First(&tag, "id = ?", id)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &tag, nil
+}
+
+// GetByIDs получает теги по списку ID одним запросом
+// Отсутствующие теги просто не попадают в результат
+func (r *tagRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.Tag, error) {
+	var tags []entity.Tag
+	result := scoped(ctx, r.db).Where("id IN ?", ids).Order("name ASC").Find(&tags)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return tags, nil
+}
+
+// GetAll получает все теги отсортированные по имени
+func (r *tagRepository) GetAll(ctx context.Context) ([]entity.Tag, error) {
+	var tags []entity.Tag
+	result := scoped(ctx, r.db).Order("name ASC").Find(&tags)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return tags, nil
+}
+
+// Update обновляет название и slug тега
+func (r *tagRepository) Update(ctx context.Context, tag *entity.Tag) error {
+	result := scoped(ctx, r.db).Model(tag).Where("id = ?", tag.ID).Updates(map[string]interface{}{
+		"name": tag.Name,
+		"slug": tag.Slug,
+	})
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return ErrTagAlreadyExists
+		}
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrTagNotFound
+	}
+
+	return nil
+}
+
+// Delete удаляет тег вместе со связями с товарами
+func (r *tagRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := scoped(ctx, tx).Delete(&entity.Tag{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrTagNotFound
+		}
+
+		return tx.Where("tag_id = ?", id).Delete(&productTag{}).Error
+	})
+}
+
+// SetProductTags заменяет теги товара
+// Принадлежность товара и тегов магазину проверяет service layer
+func (r *tagRepository) SetProductTags(ctx context.Context, productID uuid.UUID, tagIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", productID).Delete(&productTag{}).Error; err != nil {
+			return err
+		}
+
+		if len(tagIDs) == 0 {
+			return nil
+		}
+
+		links := make([]productTag, len(tagIDs))
+		for i, tagID := range tagIDs {
+			links[i] = productTag{ProductID: productID, TagID: tagID}
+		}
+
+		return tx.Create(&links).Error
+	})
+}
+
+// productsWithTags - подзапрос ID товаров, у которых есть все теги с указанными slug
+// slugs не должен содержать повторов
+func productsWithTags(ctx context.Context, db *gorm.DB, slugs []string) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).
+		Table("product_tags").
+		Select("product_tags.product_id").
+		Joins("JOIN tags ON tags.id = product_tags.tag_id").
+		Where("tags.tenant_id = ? AND tags.slug IN ?", tenant.FromContext(ctx), slugs).
+		Group("product_tags.product_id").
+		Having("COUNT(DISTINCT tags.id) = ?", len(slugs))
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrTranslationNotFound - перевод товара на указанный язык не найден
+var ErrTranslationNotFound = errors.New("translation not found")
+
+type translationRepository struct {
+	db *gorm.DB
+}
+
+// NewTranslationRepository создает новый репозиторий переводов товаров
+func NewTranslationRepository(db *gorm.DB) TranslationRepository {
+	return &translationRepository{db: db}
+}
+
+// Upsert создает перевод или заменяет существующий на тот же язык
+func (r *translationRepository) Upsert(ctx context.Context, translation *entity.ProductTranslation) error {
+	translation.TenantID = tenant.FromContext(ctx)
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "product_id"}, {Name: "locale"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "description", "updated_at"}),
+	}).Create(translation).Error
+}
+
+// ListByProduct получает все переводы товара, отсортированные по языку
+func (r *translationRepository) ListByProduct(ctx context.Context, productID uuid.UUID) ([]entity.ProductTranslation, error) {
+	var translations []entity.ProductTranslation
+	result := scoped(ctx, r.db).Where("product_id = ?", productID).Order("locale ASC").Find(&translations)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return translations, nil
+}
+
+// GetForProducts получает переводы товаров на указанные языки одним запросом
+// Товары без перевода на эти языки просто не попадают в результат
+func (r *translationRepository) GetForProducts(ctx context.Context, productIDs []uuid.UUID, locales []string) ([]entity.ProductTranslation, error) {
+	var translations []entity.ProductTranslation
+	result := scoped(ctx, r.db).
+		Where("product_id IN ? AND locale IN ?", productIDs, locales).
+		Find(&translations)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return translations, nil
+}
+
+// Delete удаляет перевод товара на язык
+func (r *translationRepository) Delete(ctx context.Context, productID uuid.UUID, locale string) error {
+	result := scoped(ctx, r.db).Delete(&entity.ProductTranslation{}, "product_id = ? AND locale = ?", productID, locale)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrTranslationNotFound
+	}
+
+	return nil
+}
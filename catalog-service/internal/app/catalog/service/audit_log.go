@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/repository"
+	"augustberries/catalog-service/internal/app/catalog/util"
+	"augustberries/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// DefaultAuditLimit - записей истории в ответе по умолчанию
+const DefaultAuditLimit = 50
+
+// AuditLog ведет журнал изменений товаров и категорий
+type AuditLog struct {
+	repo repository.AuditRepository
+}
+
+func NewAuditLog(repo repository.AuditRepository) *AuditLog {
+	return &AuditLog{repo: repo}
+}
+
+// ProductHistory возвращает историю изменений товара, новые записи первыми
+func (a *AuditLog) ProductHistory(ctx context.Context, productID uuid.UUID, limit int) ([]entity.AuditEntry, error) {
+	return a.history(ctx, entity.SlugEntityProduct, productID, limit)
+}
+
+// CategoryHistory возвращает историю изменений категории, новые записи первыми
+func (a *AuditLog) CategoryHistory(ctx context.Context, categoryID uuid.UUID, limit int) ([]entity.AuditEntry, error) {
+	return a.history(ctx, entity.SlugEntityCategory, categoryID, limit)
+}
+
+func (a *AuditLog) history(ctx context.Context, entityType string, id uuid.UUID, limit int) ([]entity.AuditEntry, error) {
+	if limit <= 0 {
+		limit = DefaultAuditLimit
+	}
+
+	entries, err := a.repo.ListByEntity(ctx, entityType, id, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit history: %w", err)
+	}
+	return entries, nil
+}
+
+// record сохраняет изменение сущности от имени пользователя запроса
+// Изменение без отличий в полях не записывается; ошибка журнала не отменяет операцию
+// nil AuditLog - журнал отключен
+func (a *AuditLog) record(ctx context.Context, entityType string, id uuid.UUID, action string, before, after map[string]interface{}) {
+	if a == nil {
+		return
+	}
+
+	changes := diffFields(before, after)
+	if len(changes) == 0 && action == entity.AuditActionUpdate {
+		return
+	}
+
+	actor := util.ActorFromContext(ctx)
+	entry := &entity.AuditEntry{
+		ID:                uuid.New(),
+		EntityType:        entityType,
+		EntityID:          id,
+		Action:            action,
+		ActorID:           actor.UserID,
+		ActorEmail:        actor.Email,
+		ImpersonatorID:    actor.ImpersonatorID,
+		ImpersonatorEmail: actor.ImpersonatorEmail,
+		Changes:           changes,
+		CreatedAt:         time.Now(),
+	}
+
+	if err := a.repo.Create(ctx, entry); err != nil {
+		fmt.Printf("failed to record %s %s audit: %v\n", entityType, action, err)
+	}
+}
+
+// diffFields возвращает поля, значения которых различаются
+// nil вместо снимка означает отсутствие сущности (до создания или после удаления)
+func diffFields(before, after map[string]interface{}) entity.AuditChanges {
+	changes := entity.AuditChanges{}
+
+	for field, old := range before {
+		if value := after[field]; !reflect.DeepEqual(old, value) {
+			changes[field] = entity.FieldChange{Old: old, New: value}
+		}
+	}
+	for field, value := range after {
+		if _, ok := before[field]; !ok && value != nil {
+			changes[field] = entity.FieldChange{New: value}
+		}
+	}
+
+	return changes
+}
+
+// productAuditFields - отслеживаемые поля товара
+func productAuditFields(p *entity.Product) map[string]interface{} {
+	return map[string]interface{}{
+		"name":        p.Name,
+		"slug":        p.Slug,
+		"description": p.Description,
+		"price":       p.Price,
+		"category_id": p.CategoryID.String(),
+		"brand_id":    optionalID(p.BrandID),
+		"supplier_id": optionalID(p.SupplierID),
+		"status":      string(p.Status),
+		"stock":       optionalInt(p.Stock),
+		"cost_price":  optionalAmount(p.CostPrice),
+	}
+}
+
+// categoryAuditFields - отслеживаемые поля категории
+func categoryAuditFields(c *entity.Category) map[string]interface{} {
+	return map[string]interface{}{
+		"name": c.Name,
+		"slug": c.Slug,
+	}
+}
+
+// optionalID приводит необязательную ссылку к строке или nil для журнала
+func optionalID(id *uuid.UUID) interface{} {
+	if id == nil {
+		return nil
+	}
+	return id.String()
+}
+
+// optionalInt приводит необязательное число к значению или nil для журнала
+func optionalInt(n *int) interface{} {
+	if n == nil {
+		return nil
+	}
+	return *n
+}
+
+// optionalAmount приводит необязательную сумму к значению или nil для журнала
+func optionalAmount(a *money.Amount) interface{} {
+	if a == nil {
+		return nil
+	}
+	return *a
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/repository/mocks"
+	"augustberries/catalog-service/internal/app/catalog/util"
+	"augustberries/pkg/money"
+	"augustberries/pkg/patch"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// captureAudit запоминает записи журнала, переданные в репозиторий
+func captureAudit(auditRepo *mocks.MockAuditRepository, entries *[]*entity.AuditEntry) {
+	auditRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.AuditEntry")).
+		Run(func(args mock.Arguments) {
+			*entries = append(*entries, args.Get(1).(*entity.AuditEntry))
+		}).
+		Return(nil)
+}
+
+// ==================== Audit Recording Tests ====================
+
+func TestCatalogService_UpdateProduct_RecordsPriceDiffWithActor(t *testing.T) {
+	// Arrange
+	ctx := util.WithActor(context.Background(), util.Actor{UserID: "user-1", Email: "manager@example.com"})
+	productRepo := new(mocks.MockProductRepository)
+	auditRepo := new(mocks.MockAuditRepository)
+	kafkaProducer := new(mocks.MockMessagePublisher)
+
+	product := newTestProduct(uuid.New())
+	oldPrice := product.Price
+	newPrice := oldPrice + money.MustParse("100.00")
+
+	productRepo.On("GetByID", ctx, product.ID).Return(product, nil)
+	productRepo.On("Update", ctx, product).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, product.ID.String(), mock.Anything).Return(nil)
+
+	var entries []*entity.AuditEntry
+	captureAudit(auditRepo, &entries)
+
+	service := NewCatalogService(new(mocks.MockCategoryRepository), productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), kafkaProducer, NewAuditLog(auditRepo), nil)
+
+	// Act
+	_, err := service.UpdateProduct(ctx, product.ID, &entity.UpdateProductRequest{Price: patch.Of(newPrice)})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	assert.Equal(t, entity.SlugEntityProduct, entry.EntityType)
+	assert.Equal(t, product.ID, entry.EntityID)
+	assert.Equal(t, entity.AuditActionUpdate, entry.Action)
+	assert.Equal(t, "user-1", entry.ActorID)
+	assert.Equal(t, "manager@example.com", entry.ActorEmail)
+	assert.Equal(t, entity.AuditChanges{
+		"price": {Old: oldPrice, New: newPrice},
+	}, entry.Changes)
+}
+
+func TestCatalogService_UpdateProduct_NoChangesNotRecorded(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	productRepo := new(mocks.MockProductRepository)
+	auditRepo := new(mocks.MockAuditRepository)
+
+	product := newTestProduct(uuid.New())
+	productRepo.On("GetByID", ctx, product.ID).Return(product, nil)
+	productRepo.On("Update", ctx, product).Return(nil)
+
+	service := NewCatalogService(new(mocks.MockCategoryRepository), productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), new(mocks.MockMessagePublisher), NewAuditLog(auditRepo), nil)
+
+	// Act
+	_, err := service.UpdateProduct(ctx, product.ID, &entity.UpdateProductRequest{Name: patch.Of(product.Name)})
+
+	// Assert
+	require.NoError(t, err)
+	auditRepo.AssertNotCalled(t, "Create")
+}
+
+func TestCatalogService_DeleteProduct_RecordsOldValues(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	productRepo := new(mocks.MockProductRepository)
+	auditRepo := new(mocks.MockAuditRepository)
+
+	product := newTestProduct(uuid.New())
+	productRepo.On("GetByID", ctx, product.ID).Return(product, nil)
+	productRepo.On("SoftDelete", ctx, product.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	var entries []*entity.AuditEntry
+	captureAudit(auditRepo, &entries)
+
+	service := NewCatalogService(new(mocks.MockCategoryRepository), productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), newAcceptingPublisher(), NewAuditLog(auditRepo), nil)
+
+	// Act
+	err := service.DeleteProduct(ctx, product.ID)
+
+	// Assert: пустой бренд не попадает в журнал
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, entity.AuditActionDelete, entries[0].Action)
+	assert.Equal(t, entity.FieldChange{Old: product.Name}, entries[0].Changes["name"])
+	assert.NotContains(t, entries[0].Changes, "brand_id")
+}
+
+func TestCatalogService_DeleteProduct_RecordsImpersonator(t *testing.T) {
+	// Arrange
+	ctx := util.WithActor(context.Background(), util.Actor{
+		UserID:            "user-1",
+		Email:             "customer@example.com",
+		ImpersonatorID:    "support-1",
+		ImpersonatorEmail: "support@example.com",
+	})
+	productRepo := new(mocks.MockProductRepository)
+	auditRepo := new(mocks.MockAuditRepository)
+
+	product := newTestProduct(uuid.New())
+	productRepo.On("GetByID", ctx, product.ID).Return(product, nil)
+	productRepo.On("SoftDelete", ctx, product.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	var entries []*entity.AuditEntry
+	captureAudit(auditRepo, &entries)
+
+	service := NewCatalogService(new(mocks.MockCategoryRepository), productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), newAcceptingPublisher(), NewAuditLog(auditRepo), nil)
+
+	// Act
+	err := service.DeleteProduct(ctx, product.ID)
+
+	// Assert: изменение записано на пользователя с отметкой сотрудника поддержки
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "user-1", entries[0].ActorID)
+	assert.Equal(t, "support-1", entries[0].ImpersonatorID)
+	assert.Equal(t, "support@example.com", entries[0].ImpersonatorEmail)
+}
+
+func TestCatalogService_CreateCategory_AuditErrorIgnored(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	categoryRepo := new(mocks.MockCategoryRepository)
+	redisCache := new(mocks.MockRedisCache)
+	auditRepo := new(mocks.MockAuditRepository)
+
+	categoryRepo.On("Create", ctx, mock.AnythingOfType("*entity.Category")).Return(nil)
+	redisCache.On("DeleteCategories", ctx).Return(nil)
+	auditRepo.On("Create", ctx, mock.AnythingOfType("*entity.AuditEntry")).Return(errors.New("db unavailable"))
+
+	service := NewCatalogService(categoryRepo, new(mocks.MockProductRepository), new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, newAcceptingPublisher(), NewAuditLog(auditRepo), nil)
+
+	// Act
+	category, err := service.CreateCategory(ctx, &entity.CreateCategoryRequest{Name: "Electronics"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Electronics", category.Name)
+	auditRepo.AssertExpectations(t)
+}
+
+// ==================== Audit History Tests ====================
+
+func TestAuditLog_ProductHistory_DefaultLimit(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	auditRepo := new(mocks.MockAuditRepository)
+	id := uuid.New()
+	entries := []entity.AuditEntry{{ID: uuid.New(), EntityID: id, Action: entity.AuditActionCreate}}
+	auditRepo.On("ListByEntity", ctx, entity.SlugEntityProduct, id, DefaultAuditLimit).Return(entries, nil)
+
+	// Act
+	result, err := NewAuditLog(auditRepo).ProductHistory(ctx, id, 0)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, entries, result)
+}
+
+func TestDiffFields(t *testing.T) {
+	// Arrange
+	before := map[string]interface{}{"name": "Old", "brand_id": nil, "status": "draft"}
+	after := map[string]interface{}{"name": "New", "brand_id": "b-1", "status": "draft"}
+
+	// Act
+	changes := diffFields(before, after)
+
+	// Assert
+	assert.Equal(t, entity.AuditChanges{
+		"name":     {Old: "Old", New: "New"},
+		"brand_id": {New: "b-1"},
+	}, changes)
+}
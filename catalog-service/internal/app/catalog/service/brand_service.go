@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/repository"
+	"augustberries/catalog-service/internal/app/catalog/util"
+	"augustberries/pkg/metrics"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrBrandNotFound         = errors.New("brand not found")
+	ErrBrandAlreadyExists    = errors.New("brand already exists")
+	ErrBrandHasProducts      = errors.New("brand has products")
+	ErrSupplierNotFound      = errors.New("supplier not found")
+	ErrSupplierAlreadyExists = errors.New("supplier already exists")
+	ErrSupplierHasProducts   = errors.New("supplier has products")
+)
+
+// BrandService управляет брендами и поставщиками товаров
+type BrandService struct {
+	brandRepo    repository.BrandRepository
+	supplierRepo repository.SupplierRepository
+	redisClient  util.RedisCache
+}
+
+func NewBrandService(
+	brandRepo repository.BrandRepository,
+	supplierRepo repository.SupplierRepository,
+	redisClient util.RedisCache,
+) *BrandService {
+	return &BrandService{
+		brandRepo:    brandRepo,
+		supplierRepo: supplierRepo,
+		redisClient:  redisClient,
+	}
+}
+
+func (s *BrandService) CreateBrand(ctx context.Context, req *entity.CreateBrandRequest) (*entity.Brand, error) {
+	brand := &entity.Brand{
+		ID:          uuid.New(),
+		Name:        req.Name,
+		Description: req.Description,
+		CreatedAt:   time.Now(),
+	}
+
+	if err := s.brandRepo.Create(ctx, brand); err != nil {
+		if errors.Is(err, repository.ErrBrandAlreadyExists) {
+			return nil, ErrBrandAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to create brand: %w", err)
+	}
+
+	s.invalidateBrands(ctx)
+
+	return brand, nil
+}
+
+func (s *BrandService) GetBrand(ctx context.Context, id uuid.UUID) (*entity.Brand, error) {
+	brand, err := s.brandRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrBrandNotFound) {
+			return nil, ErrBrandNotFound
+		}
+		return nil, fmt.Errorf("failed to get brand: %w", err)
+	}
+	return brand, nil
+}
+
+// GetAllBrands возвращает список брендов (с кешированием в Redis)
+func (s *BrandService) GetAllBrands(ctx context.Context) ([]entity.Brand, error) {
+	brands, err := s.redisClient.GetBrands(ctx)
+	if err == nil && len(brands) > 0 {
+		metrics.RecordCacheHit("catalog-service", "brands")
+		return brands, nil
+	}
+
+	metrics.RecordCacheMiss("catalog-service", "brands")
+
+	brands, err = s.brandRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get brands: %w", err)
+	}
+
+	if err := s.redisClient.SetBrands(ctx, brands, time.Hour); err != nil {
+		fmt.Printf("failed to cache brands: %v\n", err)
+	}
+
+	return brands, nil
+}
+
+func (s *BrandService) UpdateBrand(ctx context.Context, id uuid.UUID, req *entity.UpdateBrandRequest) (*entity.Brand, error) {
+	brand, err := s.brandRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrBrandNotFound) {
+			return nil, ErrBrandNotFound
+		}
+		return nil, fmt.Errorf("failed to get brand: %w", err)
+	}
+
+	if req.Name.Set {
+		brand.Name = req.Name.Value
+	}
+	if req.Description.Set {
+		brand.Description = req.Description.OrZero()
+	}
+
+	if err := s.brandRepo.Update(ctx, brand); err != nil {
+		if errors.Is(err, repository.ErrBrandAlreadyExists) {
+			return nil, ErrBrandAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to update brand: %w", err)
+	}
+
+	s.invalidateBrands(ctx)
+
+	return brand, nil
+}
+
+func (s *BrandService) DeleteBrand(ctx context.Context, id uuid.UUID) error {
+	if err := s.brandRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrBrandNotFound) {
+			return ErrBrandNotFound
+		}
+		if errors.Is(err, repository.ErrBrandHasProducts) {
+			return ErrBrandHasProducts
+		}
+		return fmt.Errorf("failed to delete brand: %w", err)
+	}
+
+	s.invalidateBrands(ctx)
+
+	return nil
+}
+
+func (s *BrandService) CreateSupplier(ctx context.Context, req *entity.CreateSupplierRequest) (*entity.Supplier, error) {
+	supplier := &entity.Supplier{
+		ID:           uuid.New(),
+		Name:         req.Name,
+		ContactEmail: req.ContactEmail,
+		Phone:        req.Phone,
+		CreatedAt:    time.Now(),
+	}
+
+	if err := s.supplierRepo.Create(ctx, supplier); err != nil {
+		if errors.Is(err, repository.ErrSupplierAlreadyExists) {
+			return nil, ErrSupplierAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to create supplier: %w", err)
+	}
+
+	return supplier, nil
+}
+
+func (s *BrandService) GetSupplier(ctx context.Context, id uuid.UUID) (*entity.Supplier, error) {
+	supplier, err := s.supplierRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrSupplierNotFound) {
+			return nil, ErrSupplierNotFound
+		}
+		return nil, fmt.Errorf("failed to get supplier: %w", err)
+	}
+	return supplier, nil
+}
+
+func (s *BrandService) GetAllSuppliers(ctx context.Context) ([]entity.Supplier, error) {
+	suppliers, err := s.supplierRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suppliers: %w", err)
+	}
+	return suppliers, nil
+}
+
+func (s *BrandService) UpdateSupplier(ctx context.Context, id uuid.UUID, req *entity.UpdateSupplierRequest) (*entity.Supplier, error) {
+	supplier, err := s.supplierRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrSupplierNotFound) {
+			return nil, ErrSupplierNotFound
+		}
+		return nil, fmt.Errorf("failed to get supplier: %w", err)
+	}
+
+	if req.Name.Set {
+		supplier.Name = req.Name.Value
+	}
+	if req.ContactEmail.Set {
+		supplier.ContactEmail = req.ContactEmail.OrZero()
+	}
+	if req.Phone.Set {
+		supplier.Phone = req.Phone.OrZero()
+	}
+
+	if err := s.supplierRepo.Update(ctx, supplier); err != nil {
+		if errors.Is(err, repository.ErrSupplierAlreadyExists) {
+			return nil, ErrSupplierAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to update supplier: %w", err)
+	}
+
+	return supplier, nil
+}
+
+func (s *BrandService) DeleteSupplier(ctx context.Context, id uuid.UUID) error {
+	if err := s.supplierRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrSupplierNotFound) {
+			return ErrSupplierNotFound
+		}
+		if errors.Is(err, repository.ErrSupplierHasProducts) {
+			return ErrSupplierHasProducts
+		}
+		return fmt.Errorf("failed to delete supplier: %w", err)
+	}
+	return nil
+}
+
+// invalidateBrands сбрасывает кеш списка брендов
+// Ошибка не критична: кеш истечет по TTL
+func (s *BrandService) invalidateBrands(ctx context.Context) {
+	if err := s.redisClient.DeleteBrands(ctx); err != nil {
+		fmt.Printf("failed to invalidate brands cache: %v\n", err)
+	}
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/repository"
+	"augustberries/catalog-service/internal/app/catalog/repository/mocks"
+	"augustberries/pkg/money"
+	"augustberries/pkg/patch"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBrand() *entity.Brand {
+	return &entity.Brand{
+		ID:          uuid.New(),
+		Name:        "Logitech",
+		Description: "Computer peripherals",
+		CreatedAt:   time.Now(),
+	}
+}
+
+// ==================== Brand Tests ====================
+
+func TestBrandService_CreateBrand_InvalidatesCache(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	brandRepo := new(mocks.MockBrandRepository)
+	redisCache := new(mocks.MockRedisCache)
+
+	brandRepo.On("Create", ctx, mock.AnythingOfType("*entity.Brand")).Return(nil)
+	redisCache.On("DeleteBrands", ctx).Return(nil)
+
+	service := NewBrandService(brandRepo, new(mocks.MockSupplierRepository), redisCache)
+
+	// Act
+	brand, err := service.CreateBrand(ctx, &entity.CreateBrandRequest{Name: "Logitech"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Logitech", brand.Name)
+	brandRepo.AssertExpectations(t)
+	redisCache.AssertExpectations(t)
+}
+
+func TestBrandService_GetAllBrands_CacheHit(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	brandRepo := new(mocks.MockBrandRepository)
+	redisCache := new(mocks.MockRedisCache)
+
+	brands := []entity.Brand{*newTestBrand()}
+	redisCache.On("GetBrands", ctx).Return(brands, nil)
+
+	service := NewBrandService(brandRepo, new(mocks.MockSupplierRepository), redisCache)
+
+	// Act
+	result, err := service.GetAllBrands(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Len(t, result, 1)
+	brandRepo.AssertNotCalled(t, "GetAll")
+}
+
+func TestBrandService_GetAllBrands_CacheMiss(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	brandRepo := new(mocks.MockBrandRepository)
+	redisCache := new(mocks.MockRedisCache)
+
+	brands := []entity.Brand{*newTestBrand()}
+	redisCache.On("GetBrands", ctx).Return(nil, nil)
+	brandRepo.On("GetAll", ctx).Return(brands, nil)
+	redisCache.On("SetBrands", ctx, brands, time.Hour).Return(nil)
+
+	service := NewBrandService(brandRepo, new(mocks.MockSupplierRepository), redisCache)
+
+	// Act
+	result, err := service.GetAllBrands(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, brands, result)
+	redisCache.AssertExpectations(t)
+}
+
+func TestBrandService_DeleteBrand_HasProducts(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	brandRepo := new(mocks.MockBrandRepository)
+	redisCache := new(mocks.MockRedisCache)
+
+	brandID := uuid.New()
+	brandRepo.On("Delete", ctx, brandID).Return(repository.ErrBrandHasProducts)
+
+	service := NewBrandService(brandRepo, new(mocks.MockSupplierRepository), redisCache)
+
+	// Act
+	err := service.DeleteBrand(ctx, brandID)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrBrandHasProducts)
+	redisCache.AssertNotCalled(t, "DeleteBrands", ctx)
+}
+
+// ==================== Supplier Tests ====================
+
+func TestBrandService_UpdateSupplier_NotFound(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	supplierRepo := new(mocks.MockSupplierRepository)
+
+	supplierID := uuid.New()
+	supplierRepo.On("GetByID", ctx, supplierID).Return(nil, repository.ErrSupplierNotFound)
+
+	service := NewBrandService(new(mocks.MockBrandRepository), supplierRepo, new(mocks.MockRedisCache))
+
+	// Act
+	supplier, err := service.UpdateSupplier(ctx, supplierID, &entity.UpdateSupplierRequest{Name: patch.Of("Acme")})
+
+	// Assert
+	assert.Nil(t, supplier)
+	assert.ErrorIs(t, err, ErrSupplierNotFound)
+}
+
+// ==================== Product Association Tests ====================
+
+func TestCatalogService_CreateProduct_BrandNotFound(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	categoryRepo := new(mocks.MockCategoryRepository)
+	productRepo := new(mocks.MockProductRepository)
+	brandRepo := new(mocks.MockBrandRepository)
+
+	category := newTestCategory()
+	brandID := uuid.New()
+	categoryRepo.On("GetByID", ctx, category.ID).Return(category, nil)
+	brandRepo.On("GetByID", ctx, brandID).Return(nil, repository.ErrBrandNotFound)
+
+	service := NewCatalogService(categoryRepo, productRepo, brandRepo, new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), new(mocks.MockMessagePublisher), nil, nil)
+
+	req := &entity.CreateProductRequest{
+		Name:        "Mouse",
+		Description: "Wireless ergonomic mouse",
+		Price:       money.MustParse("99.99"),
+		CategoryID:  category.ID,
+		BrandID:     &brandID,
+	}
+
+	// Act
+	product, err := service.CreateProduct(ctx, req)
+
+	// Assert
+	assert.Nil(t, product)
+	assert.ErrorIs(t, err, ErrBrandNotFound)
+	productRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/repository"
+	"augustberries/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrEmptyBulkPrice - в запросе массового изменения цен нет ни товаров, ни категорий
+	ErrEmptyBulkPrice = errors.New("items or categories are required")
+	// ErrDuplicateBulkPrice - товар или категория указаны в запросе несколько раз
+	ErrDuplicateBulkPrice = errors.New("duplicate product or category in bulk price request")
+	// ErrInvalidBulkPrice - после изменения на процент цена товара стала бы нулевой или отрицательной
+	ErrInvalidBulkPrice = errors.New("adjusted price must be positive")
+	// ErrBulkPriceConflict - цену товара изменили во время массового изменения цен
+	ErrBulkPriceConflict = errors.New("product price changed concurrently, recalculate bulk price update")
+)
+
+// bulkPriceBatchSize - сколько товаров обновляется в одной транзакции массового изменения цен
+const bulkPriceBatchSize = 500
+
+// BulkUpdatePrices меняет цены товаров по списку и на процент по категориям
+// Изменения применяются пачками по bulkPriceBatchSize товаров в отдельных транзакциях, после каждой пачки
+// отправляются события PRICE_CHANGED. Если пачка не применилась, предыдущие остаются в силе.
+// С DryRun возвращает рассчитанные изменения без записи
+func (s *CatalogService) BulkUpdatePrices(ctx context.Context, req *entity.BulkPriceRequest) (*entity.BulkPriceResponse, error) {
+	if len(req.Items) == 0 && len(req.Categories) == 0 {
+		return nil, ErrEmptyBulkPrice
+	}
+
+	response, products, err := s.planPriceChanges(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if req.DryRun {
+		return response, nil
+	}
+
+	for start := 0; start < len(response.Changes); start += bulkPriceBatchSize {
+		end := min(start+bulkPriceBatchSize, len(response.Changes))
+		batch := response.Changes[start:end]
+
+		if err := s.productRepo.UpdatePrices(ctx, batch); err != nil {
+			if errors.Is(err, repository.ErrPriceChanged) {
+				err = ErrBulkPriceConflict
+			}
+			return nil, fmt.Errorf("failed to update prices (%d of %d applied): %w", start, len(response.Changes), err)
+		}
+
+		for _, change := range batch {
+			s.bulkPriceChanged(ctx, products[change.ProductID], change)
+		}
+	}
+
+	return response, nil
+}
+
+// planPriceChanges рассчитывает новые цены товаров запроса
+// Возвращает товары по ID для событий после записи
+func (s *CatalogService) planPriceChanges(ctx context.Context, req *entity.BulkPriceRequest) (*entity.BulkPriceResponse, map[uuid.UUID]*entity.Product, error) {
+	response := &entity.BulkPriceResponse{DryRun: req.DryRun, Changes: []entity.PriceChange{}}
+	products := make(map[uuid.UUID]*entity.Product)
+	newPrices := make(map[uuid.UUID]money.Amount)
+	// Порядок товаров в ответе: сначала явные цены в порядке запроса, затем товары категорий
+	var order []uuid.UUID
+
+	if len(req.Items) > 0 {
+		ids := make([]uuid.UUID, 0, len(req.Items))
+		for _, item := range req.Items {
+			if _, ok := newPrices[item.ProductID]; ok {
+				return nil, nil, ErrDuplicateBulkPrice
+			}
+			newPrices[item.ProductID] = item.Price
+			ids = append(ids, item.ProductID)
+		}
+
+		found, err := s.productRepo.GetByIDs(ctx, ids)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get products: %w", err)
+		}
+		for i := range found {
+			if found[i].DeletedAt == nil {
+				products[found[i].ID] = &found[i]
+			}
+		}
+
+		for _, id := range ids {
+			if _, ok := products[id]; !ok {
+				response.Missing = append(response.Missing, id)
+				delete(newPrices, id)
+				continue
+			}
+			order = append(order, id)
+		}
+	}
+
+	seenCategories := make(map[uuid.UUID]bool, len(req.Categories))
+	for _, adjustment := range req.Categories {
+		if seenCategories[adjustment.CategoryID] {
+			return nil, nil, ErrDuplicateBulkPrice
+		}
+		seenCategories[adjustment.CategoryID] = true
+
+		if _, err := s.categoryRepo.GetByID(ctx, adjustment.CategoryID); err != nil {
+			if errors.Is(err, repository.ErrCategoryNotFound) {
+				return nil, nil, ErrCategoryNotFound
+			}
+			return nil, nil, fmt.Errorf("failed to verify category: %w", err)
+		}
+
+		categoryProducts, err := s.productRepo.ListByCategory(ctx, adjustment.CategoryID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get category products: %w", err)
+		}
+
+		rate := 1 + adjustment.Percent/100
+		for i := range categoryProducts {
+			product := &categoryProducts[i]
+			// Явная цена товара важнее процента категории
+			if _, ok := newPrices[product.ID]; ok {
+				continue
+			}
+
+			price := product.Price.MulRate(rate)
+			if price <= 0 {
+				return nil, nil, fmt.Errorf("%w: product %s", ErrInvalidBulkPrice, product.ID)
+			}
+
+			products[product.ID] = product
+			newPrices[product.ID] = price
+			order = append(order, product.ID)
+		}
+	}
+
+	for _, id := range order {
+		product := products[id]
+		if product.Price == newPrices[id] {
+			response.Unchanged++
+			continue
+		}
+		response.Changes = append(response.Changes, entity.PriceChange{
+			ProductID: id,
+			Name:      product.Name,
+			OldPrice:  product.Price,
+			NewPrice:  newPrices[id],
+		})
+	}
+
+	return response, products, nil
+}
+
+// bulkPriceChanged сбрасывает карточку товара в кэше, пишет журнал и отправляет PRICE_CHANGED
+func (s *CatalogService) bulkPriceChanged(ctx context.Context, product *entity.Product, change entity.PriceChange) {
+	product.Price = change.NewPrice
+	s.invalidateProduct(ctx, product.ID)
+
+	s.audit.record(ctx, entity.SlugEntityProduct, product.ID, entity.AuditActionUpdate,
+		map[string]interface{}{"price": change.OldPrice},
+		map[string]interface{}{"price": change.NewPrice})
+
+	event := newProductEvent(entity.EventTypePriceChanged, product, nil)
+	event.OldPrice = &change.OldPrice
+	event.Timestamp = time.Now()
+	s.publishProductEvent(ctx, event)
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/repository"
+	"augustberries/catalog-service/internal/app/catalog/repository/mocks"
+	"augustberries/pkg/money"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// ==================== BulkUpdatePrices Tests ====================
+
+func TestCatalogService_BulkUpdatePrices_ItemsAndCategory(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	categoryRepo := new(mocks.MockCategoryRepository)
+	productRepo := new(mocks.MockProductRepository)
+	kafkaProducer := new(mocks.MockMessagePublisher)
+
+	category := newTestCategory()
+	explicit := newTestProduct(category.ID)
+	explicit.Price = money.MustParse("100")
+	adjusted := newTestProduct(category.ID)
+	adjusted.Price = money.MustParse("20")
+	missingID := uuid.New()
+
+	productRepo.On("GetByIDs", ctx, []uuid.UUID{explicit.ID, missingID}).Return([]entity.Product{*explicit}, nil)
+	categoryRepo.On("GetByID", ctx, category.ID).Return(category, nil)
+	productRepo.On("ListByCategory", ctx, category.ID).Return([]entity.Product{*explicit, *adjusted}, nil)
+	productRepo.On("UpdatePrices", ctx, mock.Anything).Return(nil)
+
+	var events []entity.ProductEvent
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		var event entity.ProductEvent
+		require.NoError(t, json.Unmarshal(args.Get(2).([]byte), &event))
+		events = append(events, event)
+	})
+
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), kafkaProducer, nil, nil)
+
+	// Act
+	response, err := service.BulkUpdatePrices(ctx, &entity.BulkPriceRequest{
+		Items: []entity.BulkPriceItem{
+			{ProductID: explicit.ID, Price: money.MustParse("90")},
+			{ProductID: missingID, Price: money.MustParse("5")},
+		},
+		Categories: []entity.BulkPriceAdjustment{{CategoryID: category.ID, Percent: -12.5}},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{missingID}, response.Missing)
+	require.Len(t, response.Changes, 2)
+	// Явная цена важнее процента категории
+	assert.Equal(t, money.MustParse("90"), response.Changes[0].NewPrice)
+	assert.Equal(t, money.MustParse("17.50"), response.Changes[1].NewPrice)
+
+	require.Len(t, events, 2)
+	for _, event := range events {
+		assert.Equal(t, entity.EventTypePriceChanged, event.EventType)
+		require.NotNil(t, event.OldPrice)
+	}
+	assert.Equal(t, money.MustParse("20"), *events[1].OldPrice)
+	assert.Equal(t, money.MustParse("17.50"), events[1].Price)
+}
+
+func TestCatalogService_BulkUpdatePrices_DryRun(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	categoryRepo := new(mocks.MockCategoryRepository)
+	productRepo := new(mocks.MockProductRepository)
+	kafkaProducer := new(mocks.MockMessagePublisher)
+
+	category := newTestCategory()
+	product := newTestProduct(category.ID)
+	product.Price = money.MustParse("10")
+	unchanged := newTestProduct(category.ID)
+	unchanged.Price = money.MustParse("10")
+
+	productRepo.On("GetByIDs", ctx, []uuid.UUID{product.ID, unchanged.ID}).Return([]entity.Product{*product, *unchanged}, nil)
+
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), kafkaProducer, nil, nil)
+
+	// Act
+	response, err := service.BulkUpdatePrices(ctx, &entity.BulkPriceRequest{
+		Items: []entity.BulkPriceItem{
+			{ProductID: product.ID, Price: money.MustParse("12")},
+			{ProductID: unchanged.ID, Price: money.MustParse("10")},
+		},
+		DryRun: true,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, response.DryRun)
+	assert.Equal(t, 1, response.Unchanged)
+	require.Len(t, response.Changes, 1)
+	assert.Equal(t, money.MustParse("10"), response.Changes[0].OldPrice)
+	assert.Equal(t, money.MustParse("12"), response.Changes[0].NewPrice)
+	productRepo.AssertNotCalled(t, "UpdatePrices", mock.Anything, mock.Anything)
+	kafkaProducer.AssertNotCalled(t, "PublishMessage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCatalogService_BulkUpdatePrices_Conflict(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	productRepo := new(mocks.MockProductRepository)
+	kafkaProducer := new(mocks.MockMessagePublisher)
+
+	product := newTestProduct(uuid.New())
+	productRepo.On("GetByIDs", ctx, []uuid.UUID{product.ID}).Return([]entity.Product{*product}, nil)
+	productRepo.On("UpdatePrices", ctx, mock.Anything).Return(repository.ErrPriceChanged)
+
+	service := NewCatalogService(new(mocks.MockCategoryRepository), productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), kafkaProducer, nil, nil)
+
+	// Act
+	response, err := service.BulkUpdatePrices(ctx, &entity.BulkPriceRequest{
+		Items: []entity.BulkPriceItem{{ProductID: product.ID, Price: money.MustParse("1")}},
+	})
+
+	// Assert
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, ErrBulkPriceConflict)
+	kafkaProducer.AssertNotCalled(t, "PublishMessage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCatalogService_BulkUpdatePrices_InvalidRequest(t *testing.T) {
+	productID := uuid.New()
+	categoryID := uuid.New()
+
+	tests := []struct {
+		name    string
+		req     *entity.BulkPriceRequest
+		wantErr error
+	}{
+		{name: "empty", req: &entity.BulkPriceRequest{}, wantErr: ErrEmptyBulkPrice},
+		{
+			name: "duplicate product",
+			req: &entity.BulkPriceRequest{Items: []entity.BulkPriceItem{
+				{ProductID: productID, Price: money.MustParse("1")},
+				{ProductID: productID, Price: money.MustParse("2")},
+			}},
+			wantErr: ErrDuplicateBulkPrice,
+		},
+		{
+			name: "duplicate category",
+			req: &entity.BulkPriceRequest{Categories: []entity.BulkPriceAdjustment{
+				{CategoryID: categoryID, Percent: 5},
+				{CategoryID: categoryID, Percent: 10},
+			}},
+			wantErr: ErrDuplicateBulkPrice,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			categoryRepo := new(mocks.MockCategoryRepository)
+			productRepo := new(mocks.MockProductRepository)
+			categoryRepo.On("GetByID", mock.Anything, categoryID).Return(&entity.Category{ID: categoryID}, nil)
+			productRepo.On("ListByCategory", mock.Anything, categoryID).Return([]entity.Product{}, nil)
+
+			service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), new(mocks.MockMessagePublisher), nil, nil)
+
+			// Act
+			response, err := service.BulkUpdatePrices(context.Background(), tt.req)
+
+			// Assert
+			assert.Nil(t, response)
+			assert.ErrorIs(t, err, tt.wantErr)
+			productRepo.AssertNotCalled(t, "UpdatePrices", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/repository"
+	"augustberries/catalog-service/internal/app/catalog/util"
+	"augustberries/pkg/lock"
+	"augustberries/pkg/recovery"
+	"augustberries/pkg/tenant"
+
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	categoriesCacheTTL = time.Hour
+	productCacheTTL    = 10 * time.Minute
+	// cacheTTLJitter - доля TTL, на которую случайно удлиняется срок жизни ключа,
+	// чтобы прогретые одновременно ключи не истекали одновременно
+	cacheTTLJitter = 0.1
+)
+
+// jitterTTL возвращает TTL, увеличенный на случайную величину до cacheTTLJitter
+func jitterTTL(ttl time.Duration) time.Duration {
+	return ttl + time.Duration(rand.Float64()*cacheTTLJitter*float64(ttl))
+}
+
+// CacheWarmer заранее загружает в Redis категории и популярные товары каждого магазина,
+// чтобы первые запросы после старта или истечения TTL не шли в PostgreSQL
+type CacheWarmer struct {
+	categoryRepo repository.CategoryRepository
+	productRepo  repository.ProductRepository
+	cache        util.RedisCache
+	products     util.ProductCache
+	topProducts  int
+	cron         *cron.Cron
+	locker       *lock.Locker // nil - кеш прогревает каждый экземпляр
+	lockTTL      time.Duration
+}
+
+// NewCacheWarmer создает прогрев кэша; topProducts - число товаров на магазин, 0 - только категории
+func NewCacheWarmer(
+	categoryRepo repository.CategoryRepository,
+	productRepo repository.ProductRepository,
+	cache util.RedisCache,
+	products util.ProductCache,
+	topProducts int,
+) *CacheWarmer {
+	c := cron.New(
+		cron.WithLogger(cron.VerbosePrintfLogger(log.Default())),
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)),
+	)
+
+	return &CacheWarmer{
+		categoryRepo: categoryRepo,
+		productRepo:  productRepo,
+		cache:        cache,
+		products:     products,
+		topProducts:  topProducts,
+		cron:         c,
+	}
+}
+
+// SetLocker включает распределенную блокировку: кеш в общем Redis прогревает один экземпляр за запуск
+func (w *CacheWarmer) SetLocker(locker *lock.Locker, ttl time.Duration) {
+	w.locker = locker
+	w.lockTTL = ttl
+}
+
+// Warm прогревает кэш всех магазинов; ошибка одного магазина не останавливает остальные
+func (w *CacheWarmer) Warm(ctx context.Context) error {
+	tenants, err := w.categoryRepo.ListTenants(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list tenants: %w", err)
+	}
+
+	for _, id := range tenants {
+		if err := w.warmTenant(tenant.WithID(ctx, id)); err != nil {
+			log.Printf("ERROR: Failed to warm cache for tenant %s: %v", id, err)
+		}
+	}
+
+	return nil
+}
+
+func (w *CacheWarmer) warmTenant(ctx context.Context) error {
+	categories, err := w.categoryRepo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get categories: %w", err)
+	}
+	if err := w.cache.SetCategories(ctx, categories, jitterTTL(categoriesCacheTTL)); err != nil {
+		return fmt.Errorf("failed to cache categories: %w", err)
+	}
+
+	if w.products == nil || w.topProducts <= 0 {
+		return nil
+	}
+
+	products, err := w.productRepo.ListPopular(ctx, w.topProducts)
+	if err != nil {
+		return fmt.Errorf("failed to get popular products: %w", err)
+	}
+	for i := range products {
+		if err := w.products.SetProduct(ctx, &products[i], jitterTTL(productCacheTTL)); err != nil {
+			return fmt.Errorf("failed to cache product: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Start прогревает кэш до приема запросов и затем обновляет его по расписанию
+func (w *CacheWarmer) Start(ctx context.Context, schedule string) error {
+	log.Printf("Starting cache warmer with schedule: %s", schedule)
+
+	if _, err := w.cron.AddFunc(schedule, recovery.Wrap("catalog-service", "cache_warmer", func() { w.run(ctx) })); err != nil {
+		return err
+	}
+
+	w.run(ctx)
+	w.cron.Start()
+
+	return nil
+}
+
+// Stop останавливает обновление кэша и ждет завершения текущего прогрева
+func (w *CacheWarmer) Stop() {
+	log.Println("Stopping cache warmer...")
+	<-w.cron.Stop().Done()
+	log.Println("Cache warmer stopped")
+}
+
+func (w *CacheWarmer) run(ctx context.Context) {
+	err := w.locker.Once(ctx, "cache_warmer", w.lockTTL, func(ctx context.Context) {
+		start := time.Now()
+		if err := w.Warm(ctx); err != nil {
+			log.Printf("ERROR: Failed to warm cache: %v", err)
+			return
+		}
+		log.Printf("Cache warmed in %s", time.Since(start))
+	})
+	if errors.Is(err, lock.ErrNotAcquired) {
+		log.Println("Cache warm skipped: running on another instance")
+	} else if err != nil {
+		log.Printf("ERROR: Cache warmer: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/repository/mocks"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// tenantCtx сопоставляет контекст с магазином
+func tenantCtx(id string) interface{} {
+	return mock.MatchedBy(func(ctx context.Context) bool { return tenant.FromContext(ctx) == id })
+}
+
+// ==================== Warm Tests ====================
+
+func TestCacheWarmer_Warm_PerTenant(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	categoryRepo := new(mocks.MockCategoryRepository)
+	productRepo := new(mocks.MockProductRepository)
+	redisCache := new(mocks.MockRedisCache)
+	productCache := new(mocks.MockProductCache)
+
+	shopCategories := []entity.Category{{ID: uuid.New(), Name: "Berries"}}
+	popular := []entity.ProductWithCategory{*newTestProductWithCategory(), *newTestProductWithCategory()}
+
+	categoryRepo.On("ListTenants", ctx).Return([]string{"default", "shop"}, nil)
+	categoryRepo.On("GetAll", tenantCtx("default")).Return([]entity.Category{}, nil)
+	categoryRepo.On("GetAll", tenantCtx("shop")).Return(shopCategories, nil)
+	productRepo.On("ListPopular", tenantCtx("default"), 2).Return([]entity.ProductWithCategory{}, nil)
+	productRepo.On("ListPopular", tenantCtx("shop"), 2).Return(popular, nil)
+	redisCache.On("SetCategories", mock.Anything, mock.Anything, mock.AnythingOfType("time.Duration")).Return(nil)
+	productCache.On("SetProduct", tenantCtx("shop"), mock.Anything, mock.AnythingOfType("time.Duration")).Return(nil)
+
+	warmer := NewCacheWarmer(categoryRepo, productRepo, redisCache, productCache, 2)
+
+	// Act
+	err := warmer.Warm(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	redisCache.AssertCalled(t, "SetCategories", tenantCtx("shop"), shopCategories, mock.AnythingOfType("time.Duration"))
+	productCache.AssertNumberOfCalls(t, "SetProduct", 2)
+}
+
+func TestCacheWarmer_Warm_TenantErrorDoesNotStopOthers(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	categoryRepo := new(mocks.MockCategoryRepository)
+	redisCache := new(mocks.MockRedisCache)
+
+	categoryRepo.On("ListTenants", ctx).Return([]string{"broken", "shop"}, nil)
+	categoryRepo.On("GetAll", tenantCtx("broken")).Return(nil, errors.New("db error"))
+	categoryRepo.On("GetAll", tenantCtx("shop")).Return([]entity.Category{}, nil)
+	redisCache.On("SetCategories", tenantCtx("shop"), mock.Anything, mock.AnythingOfType("time.Duration")).Return(nil)
+
+	// Без кеша товаров прогреваются только категории
+	warmer := NewCacheWarmer(categoryRepo, new(mocks.MockProductRepository), redisCache, nil, 100)
+
+	// Act
+	err := warmer.Warm(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	redisCache.AssertNumberOfCalls(t, "SetCategories", 1)
+}
+
+func TestJitterTTL_Bounds(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		ttl := jitterTTL(time.Hour)
+		assert.GreaterOrEqual(t, ttl, time.Hour)
+		assert.LessOrEqual(t, ttl, time.Hour+6*time.Minute)
+	}
+}
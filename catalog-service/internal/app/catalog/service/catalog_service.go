@@ -2,7 +2,6 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -10,7 +9,11 @@ import (
 	"augustberries/catalog-service/internal/app/catalog/entity"
 	"augustberries/catalog-service/internal/app/catalog/repository"
 	"augustberries/catalog-service/internal/app/catalog/util"
+	"augustberries/pkg/id"
 	"augustberries/pkg/metrics"
+	"augustberries/pkg/money"
+	"augustberries/pkg/tenant"
+	"augustberries/pkg/units"
 
 	"github.com/google/uuid"
 )
@@ -18,26 +21,64 @@ import (
 var (
 	ErrCategoryNotFound = errors.New("category not found")
 	ErrProductNotFound  = errors.New("product not found")
+	// ErrInvalidProductStatus - недопустимый переход статуса товара
+	ErrInvalidProductStatus = errors.New("invalid product status transition")
+	// ErrTooManyProductIDs - в запросе доступности или сравнения больше допустимого числа товаров
+	ErrTooManyProductIDs = errors.New("too many product IDs")
+	// ErrProductReferenced - товар публиковался, на него могут ссылаться заказы и отзывы; его можно только архивировать
+	ErrProductReferenced = errors.New("product may be referenced by orders or reviews")
+	// ErrInvalidQuantityStep - дробный шаг у штучного товара
+	ErrInvalidQuantityStep = errors.New("quantity step of piece products must be a whole number")
+	// ErrInvalidQuantityLimits - минимальное количество больше максимального
+	ErrInvalidQuantityLimits = errors.New("min_qty must not exceed max_qty")
 )
 
+// MaxAvailabilityIDs - максимум товаров в одном запросе доступности
+const MaxAvailabilityIDs = 100
+
+// MaxBatchProducts - максимум товаров в одном запросе GET /products/batch
+const MaxBatchProducts = 100
+
+// PriceFacetBounds - границы ценовых диапазонов фасетов в базовой валюте
+var PriceFacetBounds = []money.Amount{
+	money.MustParse("10"),
+	money.MustParse("25"),
+	money.MustParse("50"),
+	money.MustParse("100"),
+	money.MustParse("250"),
+	money.MustParse("500"),
+}
+
 type CatalogService struct {
 	categoryRepo  repository.CategoryRepository
 	productRepo   repository.ProductRepository
+	brandRepo     repository.BrandRepository
+	supplierRepo  repository.SupplierRepository
 	redisClient   util.RedisCache
 	kafkaProducer util.MessagePublisher
+	audit         *AuditLog         // Журнал изменений товаров и категорий, nil - отключен
+	productCache  util.ProductCache // Кэш карточек товаров, nil - отключен
 }
 
 func NewCatalogService(
 	categoryRepo repository.CategoryRepository,
 	productRepo repository.ProductRepository,
+	brandRepo repository.BrandRepository,
+	supplierRepo repository.SupplierRepository,
 	redisClient util.RedisCache,
 	kafkaProducer util.MessagePublisher,
+	audit *AuditLog,
+	productCache util.ProductCache,
 ) *CatalogService {
 	return &CatalogService{
 		categoryRepo:  categoryRepo,
 		productRepo:   productRepo,
+		brandRepo:     brandRepo,
+		supplierRepo:  supplierRepo,
 		redisClient:   redisClient,
 		kafkaProducer: kafkaProducer,
+		audit:         audit,
+		productCache:  productCache,
 	}
 }
 
@@ -52,6 +93,9 @@ func (s *CatalogService) CreateCategory(ctx context.Context, req *entity.CreateC
 		return nil, fmt.Errorf("failed to create category: %w", err)
 	}
 
+	s.audit.record(ctx, entity.SlugEntityCategory, category.ID, entity.AuditActionCreate, nil, categoryAuditFields(category))
+	s.publishCategoryEvent(ctx, newCategoryEvent(entity.EventTypeCategoryCreated, category, nil))
+
 	if err := s.redisClient.DeleteCategories(ctx); err != nil {
 		fmt.Printf("failed to invalidate categories cache: %v\n", err)
 	}
@@ -70,6 +114,23 @@ func (s *CatalogService) GetCategory(ctx context.Context, id uuid.UUID) (*entity
 	return category, nil
 }
 
+// GetCategoryBySlug ищет категорию по slug
+// moved = true, если slug устарел после переименования: клиента нужно перенаправить на category.Slug
+func (s *CatalogService) GetCategoryBySlug(ctx context.Context, slug string) (category *entity.Category, moved bool, err error) {
+	category, err = s.categoryRepo.GetBySlug(ctx, slug)
+	if errors.Is(err, repository.ErrCategoryNotFound) {
+		category, err = s.categoryRepo.GetByOldSlug(ctx, slug)
+		moved = true
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			return nil, false, ErrCategoryNotFound
+		}
+		return nil, false, fmt.Errorf("failed to get category by slug: %w", err)
+	}
+	return category, moved, nil
+}
+
 func (s *CatalogService) GetAllCategories(ctx context.Context) ([]entity.Category, error) {
 	categories, err := s.redisClient.GetCategories(ctx)
 	if err == nil && len(categories) > 0 {
@@ -84,7 +145,7 @@ func (s *CatalogService) GetAllCategories(ctx context.Context) ([]entity.Categor
 		return nil, fmt.Errorf("failed to get categories: %w", err)
 	}
 
-	if err := s.redisClient.SetCategories(ctx, categories, time.Hour); err != nil {
+	if err := s.redisClient.SetCategories(ctx, categories, jitterTTL(categoriesCacheTTL)); err != nil {
 		fmt.Printf("failed to cache categories: %v\n", err)
 	}
 
@@ -100,12 +161,19 @@ func (s *CatalogService) UpdateCategory(ctx context.Context, id uuid.UUID, req *
 		return nil, fmt.Errorf("failed to get category: %w", err)
 	}
 
+	before := categoryAuditFields(category)
 	category.Name = req.Name
 
 	if err := s.categoryRepo.Update(ctx, category); err != nil {
 		return nil, fmt.Errorf("failed to update category: %w", err)
 	}
 
+	after := categoryAuditFields(category)
+	s.audit.record(ctx, entity.SlugEntityCategory, category.ID, entity.AuditActionUpdate, before, after)
+	if changes := diffFields(before, after); len(changes) > 0 {
+		s.publishCategoryEvent(ctx, newCategoryEvent(entity.EventTypeCategoryUpdated, category, changes))
+	}
+
 	if err := s.redisClient.DeleteCategories(ctx); err != nil {
 		fmt.Printf("failed to invalidate categories cache: %v\n", err)
 	}
@@ -121,6 +189,14 @@ func (s *CatalogService) DeleteCategory(ctx context.Context, id uuid.UUID) error
 		return fmt.Errorf("failed to delete category: %w", err)
 	}
 
+	s.audit.record(ctx, entity.SlugEntityCategory, id, entity.AuditActionDelete, nil, nil)
+	s.publishCategoryEvent(ctx, entity.CategoryEvent{
+		EventType:  entity.EventTypeCategoryDeleted,
+		TenantID:   tenant.FromContext(ctx),
+		CategoryID: id,
+		Timestamp:  time.Now(),
+	})
+
 	if err := s.redisClient.DeleteCategories(ctx); err != nil {
 		fmt.Printf("failed to invalidate categories cache: %v\n", err)
 	}
@@ -135,24 +211,59 @@ func (s *CatalogService) CreateProduct(ctx context.Context, req *entity.CreatePr
 		}
 		return nil, fmt.Errorf("failed to verify category: %w", err)
 	}
+	if err := s.verifyBrandAndSupplier(ctx, req.BrandID, req.SupplierID); err != nil {
+		return nil, err
+	}
 
 	product := &entity.Product{
-		ID:          uuid.New(),
-		Name:        req.Name,
-		Description: req.Description,
-		Price:       req.Price,
-		CategoryID:  req.CategoryID,
-		CreatedAt:   time.Now(),
+		ID:               id.New(),
+		Name:             req.Name,
+		Description:      req.Description,
+		Price:            req.Price,
+		CategoryID:       req.CategoryID,
+		BrandID:          req.BrandID,
+		SupplierID:       req.SupplierID,
+		Stock:            req.Stock,
+		CostPrice:        req.CostPrice,
+		Unit:             req.Unit.OrDefault(),
+		QuantityStep:     req.QuantityStep,
+		MinQty:           req.MinQty,
+		MaxQty:           req.MaxQty,
+		PerCustomerLimit: req.PerCustomerLimit,
+		Status:           entity.ProductStatusDraft, // Новый товар создается черновиком
+		CreatedAt:        time.Now(),
+	}
+	if product.QuantityStep == 0 {
+		product.QuantityStep = units.One
+	}
+	if err := validateQuantityStep(product); err != nil {
+		return nil, err
+	}
+	if err := validateQuantityLimits(product); err != nil {
+		return nil, err
 	}
+	product.FillPricePerUnit()
 
 	if err := s.productRepo.Create(ctx, product); err != nil {
 		return nil, fmt.Errorf("failed to create product: %w", err)
 	}
 
+	s.audit.record(ctx, entity.SlugEntityProduct, product.ID, entity.AuditActionCreate, nil, productAuditFields(product))
+	s.publishProductEvent(ctx, newProductEvent(entity.EventTypeProductCreated, product, nil))
+
 	return product, nil
 }
 
 func (s *CatalogService) GetProduct(ctx context.Context, id uuid.UUID) (*entity.ProductWithCategory, error) {
+	if s.productCache != nil {
+		cached, err := s.productCache.GetProduct(ctx, id)
+		if err == nil && cached != nil {
+			metrics.RecordCacheHit("catalog-service", "products")
+			return cached, nil
+		}
+		metrics.RecordCacheMiss("catalog-service", "products")
+	}
+
 	product, err := s.productRepo.GetWithCategory(ctx, id)
 	if err != nil {
 		if errors.Is(err, repository.ErrProductNotFound) {
@@ -160,18 +271,140 @@ func (s *CatalogService) GetProduct(ctx context.Context, id uuid.UUID) (*entity.
 		}
 		return nil, fmt.Errorf("failed to get product: %w", err)
 	}
+
+	if s.productCache != nil {
+		if err := s.productCache.SetProduct(ctx, product, jitterTTL(productCacheTTL)); err != nil {
+			fmt.Printf("failed to cache product: %v\n", err)
+		}
+	}
+
 	return product, nil
 }
 
-func (s *CatalogService) GetAllProducts(ctx context.Context) ([]entity.ProductWithCategory, error) {
-	products, err := s.productRepo.GetAllWithCategories(ctx)
+// invalidateProduct удаляет карточку товара из кэша после изменения
+func (s *CatalogService) invalidateProduct(ctx context.Context, id uuid.UUID) {
+	if s.productCache == nil {
+		return
+	}
+	if err := s.productCache.DeleteProduct(ctx, id); err != nil {
+		fmt.Printf("failed to invalidate product cache: %v\n", err)
+	}
+}
+
+// GetProductsAvailability возвращает цену, статус и остаток товаров для проверки заказа
+// Повторяющиеся ID учитываются один раз, отсутствующие в каталоге возвращаются в Missing
+func (s *CatalogService) GetProductsAvailability(ctx context.Context, ids []uuid.UUID) (*entity.ProductAvailabilityResponse, error) {
+	unique := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) > MaxAvailabilityIDs {
+		return nil, ErrTooManyProductIDs
+	}
+
+	products, err := s.productRepo.GetAvailability(ctx, unique)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products availability: %w", err)
+	}
+
+	if products == nil {
+		products = []entity.ProductAvailability{}
+	}
+
+	found := make(map[uuid.UUID]struct{}, len(products))
+	for i := range products {
+		p := &products[i]
+		p.Available = p.Status == entity.ProductStatusPublished && (p.Stock == nil || *p.Stock > 0)
+		found[p.ID] = struct{}{}
+	}
+
+	response := &entity.ProductAvailabilityResponse{Products: products, Missing: []uuid.UUID{}}
+	for _, id := range unique {
+		if _, ok := found[id]; !ok {
+			response.Missing = append(response.Missing, id)
+		}
+	}
+	return response, nil
+}
+
+// GetProductsBatch возвращает товары с категорией, брендом и тегами по списку ID
+// Повторяющиеся ID учитываются один раз; удаленные и отсутствующие товары, а без includeUnpublished
+// и неопубликованные, возвращаются в Missing
+func (s *CatalogService) GetProductsBatch(ctx context.Context, ids []uuid.UUID, includeUnpublished bool) (*entity.ProductBatchResponse, error) {
+	unique := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) > MaxBatchProducts {
+		return nil, ErrTooManyProductIDs
+	}
+
+	found, err := s.productRepo.GetDetailsByIDs(ctx, unique)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+
+	response := &entity.ProductBatchResponse{
+		Products: make(map[uuid.UUID]*entity.Product, len(found)),
+		Missing:  []uuid.UUID{},
+	}
+	for i := range found {
+		if includeUnpublished || found[i].Status == entity.ProductStatusPublished {
+			response.Products[found[i].ID] = &found[i]
+		}
+	}
+	for _, id := range unique {
+		if _, ok := response.Products[id]; !ok {
+			response.Missing = append(response.Missing, id)
+		}
+	}
+	return response, nil
+}
+
+// GetProductBySlug ищет товар по slug
+// moved = true, если slug устарел после переименования: клиента нужно перенаправить на product.Slug
+func (s *CatalogService) GetProductBySlug(ctx context.Context, slug string) (product *entity.ProductWithCategory, moved bool, err error) {
+	product, err = s.productRepo.GetBySlug(ctx, slug)
+	if errors.Is(err, repository.ErrProductNotFound) {
+		product, err = s.productRepo.GetByOldSlug(ctx, slug)
+		moved = true
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrProductNotFound) {
+			return nil, false, ErrProductNotFound
+		}
+		return nil, false, fmt.Errorf("failed to get product by slug: %w", err)
+	}
+	return product, moved, nil
+}
+
+// GetAllProducts возвращает список товаров с учетом фильтров (категория, бренд, поставщик)
+func (s *CatalogService) GetAllProducts(ctx context.Context, filter entity.ProductFilter) ([]entity.ProductWithCategory, error) {
+	products, err := s.productRepo.GetAllWithCategories(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get products: %w", err)
 	}
 	return products, nil
 }
 
-// UpdateProduct обновляет товар и отправляет событие PRODUCT_UPDATED в Kafka при изменении цены
+// GetProductFacets считает фасеты (категории, бренды, теги, цены, оценки) для фильтров списка товаров
+func (s *CatalogService) GetProductFacets(ctx context.Context, filter entity.ProductFilter) (*entity.ProductFacets, error) {
+	facets, err := s.productRepo.Facets(ctx, filter, PriceFacetBounds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product facets: %w", err)
+	}
+	return facets, nil
+}
+
+// UpdateProduct обновляет товар и отправляет событие PRODUCT_UPDATED со всеми изменившимися полями
 func (s *CatalogService) UpdateProduct(ctx context.Context, id uuid.UUID, req *entity.UpdateProductRequest) (*entity.Product, error) {
 	product, err := s.productRepo.GetByID(ctx, id)
 	if err != nil {
@@ -182,72 +415,230 @@ func (s *CatalogService) UpdateProduct(ctx context.Context, id uuid.UUID, req *e
 	}
 
 	oldPrice := product.Price
+	before := productAuditFields(product)
 
-	if req.Name != "" {
-		product.Name = req.Name
+	if req.Name.Set {
+		product.Name = req.Name.Value
 	}
-	if req.Description != "" {
-		product.Description = req.Description
+	if req.Description.Set {
+		product.Description = req.Description.Value
 	}
-	if req.Price > 0 {
-		product.Price = req.Price
+	if req.Price.Set {
+		product.Price = req.Price.Value
 	}
-	if req.CategoryID != uuid.Nil {
-		if _, err := s.categoryRepo.GetByID(ctx, req.CategoryID); err != nil {
+	if req.CategoryID.Set {
+		if _, err := s.categoryRepo.GetByID(ctx, req.CategoryID.Value); err != nil {
 			if errors.Is(err, repository.ErrCategoryNotFound) {
 				return nil, ErrCategoryNotFound
 			}
 			return nil, fmt.Errorf("failed to verify category: %w", err)
 		}
-		product.CategoryID = req.CategoryID
+		product.CategoryID = req.CategoryID.Value
+	}
+	if err := s.verifyBrandAndSupplier(ctx, req.BrandID.Value, req.SupplierID.Value); err != nil {
+		return nil, err
+	}
+	req.BrandID.Apply(&product.BrandID)
+	req.SupplierID.Apply(&product.SupplierID)
+	req.Stock.Apply(&product.Stock)
+	req.CostPrice.Apply(&product.CostPrice)
+	if req.Unit.Set {
+		product.Unit = req.Unit.Value
 	}
+	if req.QuantityStep.Set {
+		product.QuantityStep = req.QuantityStep.Value
+	}
+	req.MinQty.Apply(&product.MinQty)
+	req.MaxQty.Apply(&product.MaxQty)
+	req.PerCustomerLimit.Apply(&product.PerCustomerLimit)
+	if err := validateQuantityStep(product); err != nil {
+		return nil, err
+	}
+	if err := validateQuantityLimits(product); err != nil {
+		return nil, err
+	}
+	product.FillPricePerUnit()
 
 	if err := s.productRepo.Update(ctx, product); err != nil {
 		return nil, fmt.Errorf("failed to update product: %w", err)
 	}
+	s.invalidateProduct(ctx, product.ID)
 
-	if product.Price != oldPrice {
-		event := entity.ProductEvent{
-			EventType:  "PRODUCT_UPDATED",
-			ProductID:  product.ID,
-			Name:       product.Name,
-			Price:      product.Price,
-			CategoryID: product.CategoryID,
-			Timestamp:  time.Now(),
+	after := productAuditFields(product)
+	s.audit.record(ctx, entity.SlugEntityProduct, product.ID, entity.AuditActionUpdate, before, after)
+
+	if changes := diffFields(before, after); len(changes) > 0 {
+		event := newProductEvent(entity.EventTypeProductUpdated, product, changes)
+		if product.Price != oldPrice {
+			event.OldPrice = &oldPrice
 		}
-		if err := s.publishProductEvent(ctx, event); err != nil {
-			fmt.Printf("failed to publish product updated event: %v\n", err)
+		s.publishProductEvent(ctx, event)
+	}
+
+	return product, nil
+}
+
+// PublishProduct публикует товар и отправляет событие PRODUCT_PUBLISHED в Kafka
+func (s *CatalogService) PublishProduct(ctx context.Context, id uuid.UUID) (*entity.Product, error) {
+	product, err := s.changeProductStatus(ctx, id, entity.ProductStatusPublished)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishProductEvent(ctx, newProductEvent(entity.EventTypeProductPublished, product, nil))
+
+	return product, nil
+}
+
+// ArchiveProduct снимает опубликованный товар с продажи и отправляет PRODUCT_UPDATED со сменой статуса
+func (s *CatalogService) ArchiveProduct(ctx context.Context, id uuid.UUID) (*entity.Product, error) {
+	product, err := s.changeProductStatus(ctx, id, entity.ProductStatusArchived)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishProductEvent(ctx, newProductEvent(entity.EventTypeProductUpdated, product, entity.AuditChanges{
+		"status": {Old: string(entity.ProductStatusPublished), New: string(entity.ProductStatusArchived)},
+	}))
+
+	return product, nil
+}
+
+// changeProductStatus проверяет допустимость перехода и сохраняет новый статус
+func (s *CatalogService) changeProductStatus(ctx context.Context, id uuid.UUID, to entity.ProductStatus) (*entity.Product, error) {
+	product, err := s.productRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrProductNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	if !isValidProductStatusTransition(product.Status, to) {
+		return nil, ErrInvalidProductStatus
+	}
+
+	if err := s.productRepo.UpdateStatus(ctx, id, product.Status, to); err != nil {
+		if errors.Is(err, repository.ErrProductStatusChanged) {
+			return nil, ErrInvalidProductStatus
 		}
+		return nil, fmt.Errorf("failed to update product status: %w", err)
 	}
+	s.invalidateProduct(ctx, id)
+
+	before := productAuditFields(product)
+	product.Status = to
+	s.audit.record(ctx, entity.SlugEntityProduct, product.ID, entity.AuditActionUpdate, before, productAuditFields(product))
 
 	return product, nil
 }
 
+// DeleteProduct архивирует товар: он скрывается из каталога, но остается в базе для заказов и отзывов
 func (s *CatalogService) DeleteProduct(ctx context.Context, id uuid.UUID) error {
-	_, err := s.productRepo.GetByID(ctx, id)
+	product, err := s.getLiveProduct(ctx, id)
 	if err != nil {
+		return err
+	}
+
+	if err := s.productRepo.SoftDelete(ctx, id, time.Now()); err != nil {
 		if errors.Is(err, repository.ErrProductNotFound) {
 			return ErrProductNotFound
 		}
-		return fmt.Errorf("failed to get product: %w", err)
+		return fmt.Errorf("failed to delete product: %w", err)
+	}
+	s.invalidateProduct(ctx, id)
+
+	s.audit.record(ctx, entity.SlugEntityProduct, id, entity.AuditActionDelete, productAuditFields(product), nil)
+	s.publishProductEvent(ctx, newProductEvent(entity.EventTypeProductDeleted, product, nil))
+
+	return nil
+}
+
+// PurgeProduct удаляет товар из базы безвозвратно
+// Удалить можно только черновик без отзывов: заказать можно лишь опубликованный товар,
+// а вернуть опубликованный товар в черновики нельзя, поэтому на черновик заказы не ссылаются
+func (s *CatalogService) PurgeProduct(ctx context.Context, id uuid.UUID) error {
+	product, err := s.getLiveProduct(ctx, id)
+	if err != nil {
+		return err
+	}
+	if product.Status != entity.ProductStatusDraft || product.RatingCount > 0 {
+		return ErrProductReferenced
 	}
 
 	if err := s.productRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrProductNotFound) {
+			return ErrProductNotFound
+		}
 		return fmt.Errorf("failed to delete product: %w", err)
 	}
+	s.invalidateProduct(ctx, id)
+
+	s.audit.record(ctx, entity.SlugEntityProduct, id, entity.AuditActionDelete, productAuditFields(product), nil)
+	s.publishProductEvent(ctx, newProductEvent(entity.EventTypeProductDeleted, product, nil))
 
 	return nil
 }
 
-func (s *CatalogService) publishProductEvent(ctx context.Context, event entity.ProductEvent) error {
-	eventData, err := json.Marshal(event)
+// getLiveProduct получает товар, который еще не удален
+func (s *CatalogService) getLiveProduct(ctx context.Context, id uuid.UUID) (*entity.Product, error) {
+	product, err := s.productRepo.GetByID(ctx, id)
 	if err != nil {
-		return fmt.Errorf("failed to marshal product event: %w", err)
+		if errors.Is(err, repository.ErrProductNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product.DeletedAt != nil {
+		return nil, ErrProductNotFound
 	}
+	return product, nil
+}
 
-	if err := s.kafkaProducer.PublishMessage(ctx, event.ProductID.String(), eventData); err != nil {
-		return fmt.Errorf("failed to publish to kafka: %w", err)
+// validateQuantityStep проверяет шаг количества: штучный товар продается только целыми штуками
+func validateQuantityStep(product *entity.Product) error {
+	if product.Unit.OrDefault() == units.UnitPiece && !product.QuantityStep.IsWhole() {
+		return ErrInvalidQuantityStep
 	}
+	return nil
+}
 
+// validateQuantityLimits проверяет, что минимальное количество в заказе не больше максимального
+func validateQuantityLimits(product *entity.Product) error {
+	if product.MinQty != nil && product.MaxQty != nil && *product.MinQty > *product.MaxQty {
+		return ErrInvalidQuantityLimits
+	}
+	return nil
+}
+
+// verifyBrandAndSupplier проверяет существование указанных бренда и поставщика
+func (s *CatalogService) verifyBrandAndSupplier(ctx context.Context, brandID, supplierID *uuid.UUID) error {
+	if brandID != nil {
+		if _, err := s.brandRepo.GetByID(ctx, *brandID); err != nil {
+			if errors.Is(err, repository.ErrBrandNotFound) {
+				return ErrBrandNotFound
+			}
+			return fmt.Errorf("failed to verify brand: %w", err)
+		}
+	}
+	if supplierID != nil {
+		if _, err := s.supplierRepo.GetByID(ctx, *supplierID); err != nil {
+			if errors.Is(err, repository.ErrSupplierNotFound) {
+				return ErrSupplierNotFound
+			}
+			return fmt.Errorf("failed to verify supplier: %w", err)
+		}
+	}
 	return nil
 }
+
+// isValidProductStatusTransition проверяет переход draft -> published -> archived
+func isValidProductStatusTransition(from, to entity.ProductStatus) bool {
+	validTransitions := map[entity.ProductStatus]entity.ProductStatus{
+		entity.ProductStatusDraft:     entity.ProductStatusPublished,
+		entity.ProductStatusPublished: entity.ProductStatusArchived,
+	}
+
+	next, exists := validTransitions[from]
+	return exists && next == to
+}
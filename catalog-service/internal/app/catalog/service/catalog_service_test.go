@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 	"time"
@@ -9,6 +10,9 @@ import (
 	"augustberries/catalog-service/internal/app/catalog/entity"
 	"augustberries/catalog-service/internal/app/catalog/repository"
 	"augustberries/catalog-service/internal/app/catalog/repository/mocks"
+	"augustberries/pkg/money"
+	"augustberries/pkg/patch"
+	"augustberries/pkg/units"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -31,8 +35,9 @@ func newTestProduct(categoryID uuid.UUID) *entity.Product {
 		ID:          uuid.New(),
 		Name:        "Laptop",
 		Description: "High-performance laptop for developers",
-		Price:       1299.99,
+		Price:       money.MustParse("1299.99"),
 		CategoryID:  categoryID,
+		Status:      entity.ProductStatusPublished,
 		CreatedAt:   time.Now(),
 	}
 }
@@ -55,11 +60,12 @@ func TestCatalogService_CreateCategory_Success(t *testing.T) {
 	productRepo := new(mocks.MockProductRepository)
 	redisCache := new(mocks.MockRedisCache)
 	kafkaProducer := new(mocks.MockMessagePublisher)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	categoryRepo.On("Create", ctx, mock.AnythingOfType("*entity.Category")).Return(nil)
 	redisCache.On("DeleteCategories", ctx).Return(nil)
 
-	service := NewCatalogService(categoryRepo, productRepo, redisCache, kafkaProducer)
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
 
 	req := &entity.CreateCategoryRequest{
 		Name: "Electronics",
@@ -88,7 +94,7 @@ func TestCatalogService_CreateCategory_RepoError(t *testing.T) {
 
 	categoryRepo.On("Create", ctx, mock.AnythingOfType("*entity.Category")).Return(errors.New("db error"))
 
-	service := NewCatalogService(categoryRepo, productRepo, redisCache, kafkaProducer)
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
 
 	req := &entity.CreateCategoryRequest{Name: "Electronics"}
 
@@ -108,11 +114,12 @@ func TestCatalogService_CreateCategory_CacheErrorIgnored(t *testing.T) {
 	productRepo := new(mocks.MockProductRepository)
 	redisCache := new(mocks.MockRedisCache)
 	kafkaProducer := new(mocks.MockMessagePublisher)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	categoryRepo.On("Create", ctx, mock.AnythingOfType("*entity.Category")).Return(nil)
 	redisCache.On("DeleteCategories", ctx).Return(errors.New("redis error"))
 
-	service := NewCatalogService(categoryRepo, productRepo, redisCache, kafkaProducer)
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
 
 	req := &entity.CreateCategoryRequest{Name: "Electronics"}
 
@@ -135,7 +142,7 @@ func TestCatalogService_GetCategory_Success(t *testing.T) {
 	expectedCategory := newTestCategory()
 	categoryRepo.On("GetByID", ctx, expectedCategory.ID).Return(expectedCategory, nil)
 
-	service := NewCatalogService(categoryRepo, productRepo, redisCache, kafkaProducer)
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
 
 	// Act
 	category, err := service.GetCategory(ctx, expectedCategory.ID)
@@ -157,7 +164,7 @@ func TestCatalogService_GetCategory_NotFound(t *testing.T) {
 	categoryID := uuid.New()
 	categoryRepo.On("GetByID", ctx, categoryID).Return(nil, repository.ErrCategoryNotFound)
 
-	service := NewCatalogService(categoryRepo, productRepo, redisCache, kafkaProducer)
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
 
 	// Act
 	category, err := service.GetCategory(ctx, categoryID)
@@ -181,7 +188,7 @@ func TestCatalogService_GetAllCategories_CacheHit(t *testing.T) {
 	}
 	redisCache.On("GetCategories", ctx).Return(cachedCategories, nil)
 
-	service := NewCatalogService(categoryRepo, productRepo, redisCache, kafkaProducer)
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
 
 	// Act
 	categories, err := service.GetAllCategories(ctx)
@@ -207,9 +214,13 @@ func TestCatalogService_GetAllCategories_CacheMiss(t *testing.T) {
 	}
 	redisCache.On("GetCategories", ctx).Return(nil, errors.New("cache miss"))
 	categoryRepo.On("GetAll", ctx).Return(dbCategories, nil)
-	redisCache.On("SetCategories", ctx, dbCategories, time.Hour).Return(nil)
+	// TTL кеша категорий - час плюс случайная добавка до 10%
+	hourWithJitter := mock.MatchedBy(func(ttl time.Duration) bool {
+		return ttl >= time.Hour && ttl <= time.Hour+6*time.Minute
+	})
+	redisCache.On("SetCategories", ctx, dbCategories, hourWithJitter).Return(nil)
 
-	service := NewCatalogService(categoryRepo, productRepo, redisCache, kafkaProducer)
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
 
 	// Act
 	categories, err := service.GetAllCategories(ctx)
@@ -218,7 +229,7 @@ func TestCatalogService_GetAllCategories_CacheMiss(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, categories, 2)
 	categoryRepo.AssertCalled(t, "GetAll", ctx)
-	redisCache.AssertCalled(t, "SetCategories", ctx, dbCategories, time.Hour)
+	redisCache.AssertCalled(t, "SetCategories", ctx, dbCategories, hourWithJitter)
 }
 
 func TestCatalogService_UpdateCategory_Success(t *testing.T) {
@@ -228,13 +239,14 @@ func TestCatalogService_UpdateCategory_Success(t *testing.T) {
 	productRepo := new(mocks.MockProductRepository)
 	redisCache := new(mocks.MockRedisCache)
 	kafkaProducer := new(mocks.MockMessagePublisher)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	existingCategory := newTestCategory()
 	categoryRepo.On("GetByID", ctx, existingCategory.ID).Return(existingCategory, nil)
 	categoryRepo.On("Update", ctx, existingCategory).Return(nil)
 	redisCache.On("DeleteCategories", ctx).Return(nil)
 
-	service := NewCatalogService(categoryRepo, productRepo, redisCache, kafkaProducer)
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
 
 	req := &entity.UpdateCategoryRequest{Name: "Updated Electronics"}
 
@@ -257,7 +269,7 @@ func TestCatalogService_UpdateCategory_NotFound(t *testing.T) {
 	categoryID := uuid.New()
 	categoryRepo.On("GetByID", ctx, categoryID).Return(nil, repository.ErrCategoryNotFound)
 
-	service := NewCatalogService(categoryRepo, productRepo, redisCache, kafkaProducer)
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
 
 	req := &entity.UpdateCategoryRequest{Name: "Updated"}
 
@@ -276,12 +288,13 @@ func TestCatalogService_DeleteCategory_Success(t *testing.T) {
 	productRepo := new(mocks.MockProductRepository)
 	redisCache := new(mocks.MockRedisCache)
 	kafkaProducer := new(mocks.MockMessagePublisher)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	categoryID := uuid.New()
 	categoryRepo.On("Delete", ctx, categoryID).Return(nil)
 	redisCache.On("DeleteCategories", ctx).Return(nil)
 
-	service := NewCatalogService(categoryRepo, productRepo, redisCache, kafkaProducer)
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
 
 	// Act
 	err := service.DeleteCategory(ctx, categoryID)
@@ -303,7 +316,7 @@ func TestCatalogService_DeleteCategory_NotFound(t *testing.T) {
 	categoryID := uuid.New()
 	categoryRepo.On("Delete", ctx, categoryID).Return(repository.ErrCategoryNotFound)
 
-	service := NewCatalogService(categoryRepo, productRepo, redisCache, kafkaProducer)
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
 
 	// Act
 	err := service.DeleteCategory(ctx, categoryID)
@@ -321,17 +334,18 @@ func TestCatalogService_CreateProduct_Success(t *testing.T) {
 	productRepo := new(mocks.MockProductRepository)
 	redisCache := new(mocks.MockRedisCache)
 	kafkaProducer := new(mocks.MockMessagePublisher)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	category := newTestCategory()
 	categoryRepo.On("GetByID", ctx, category.ID).Return(category, nil)
 	productRepo.On("Create", ctx, mock.AnythingOfType("*entity.Product")).Return(nil)
 
-	service := NewCatalogService(categoryRepo, productRepo, redisCache, kafkaProducer)
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
 
 	req := &entity.CreateProductRequest{
 		Name:        "Laptop",
 		Description: "High-performance laptop for developers",
-		Price:       1299.99,
+		Price:       money.MustParse("1299.99"),
 		CategoryID:  category.ID,
 	}
 
@@ -342,8 +356,100 @@ func TestCatalogService_CreateProduct_Success(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotNil(t, product)
 	assert.Equal(t, "Laptop", product.Name)
-	assert.Equal(t, 1299.99, product.Price)
+	assert.Equal(t, money.MustParse("1299.99"), product.Price)
 	assert.Equal(t, category.ID, product.CategoryID)
+	assert.Equal(t, uuid.Version(7), product.ID.Version())
+}
+
+func TestCatalogService_CreateProduct_WeighedProduct(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	categoryRepo := new(mocks.MockCategoryRepository)
+	productRepo := new(mocks.MockProductRepository)
+	kafkaProducer := new(mocks.MockMessagePublisher)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	category := newTestCategory()
+	categoryRepo.On("GetByID", ctx, category.ID).Return(category, nil)
+	productRepo.On("Create", ctx, mock.AnythingOfType("*entity.Product")).Return(nil)
+
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), kafkaProducer, nil, nil)
+
+	req := &entity.CreateProductRequest{
+		Name:         "Черешня",
+		Description:  "Черешня весовая, от 0.5 кг",
+		Price:        money.MustParse("349.90"),
+		CategoryID:   category.ID,
+		Unit:         units.UnitKilogram,
+		QuantityStep: units.MustParse("0.5"),
+	}
+
+	// Act
+	product, err := service.CreateProduct(ctx, req)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, units.UnitKilogram, product.Unit)
+	assert.Equal(t, units.MustParse("0.5"), product.QuantityStep)
+	assert.Equal(t, "349.90/kg", product.PricePerUnit)
+}
+
+func TestCatalogService_CreateProduct_FractionalPieceStepRejected(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	categoryRepo := new(mocks.MockCategoryRepository)
+	productRepo := new(mocks.MockProductRepository)
+
+	category := newTestCategory()
+	categoryRepo.On("GetByID", ctx, category.ID).Return(category, nil)
+
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), new(mocks.MockMessagePublisher), nil, nil)
+
+	req := &entity.CreateProductRequest{
+		Name:         "Laptop",
+		Description:  "High-performance laptop for developers",
+		Price:        money.MustParse("1299.99"),
+		CategoryID:   category.ID,
+		QuantityStep: units.MustParse("0.5"),
+	}
+
+	// Act
+	product, err := service.CreateProduct(ctx, req)
+
+	// Assert
+	assert.Nil(t, product)
+	assert.ErrorIs(t, err, ErrInvalidQuantityStep)
+	productRepo.AssertNotCalled(t, "Create")
+}
+
+func TestCatalogService_CreateProduct_MinAboveMaxRejected(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	categoryRepo := new(mocks.MockCategoryRepository)
+	productRepo := new(mocks.MockProductRepository)
+
+	category := newTestCategory()
+	categoryRepo.On("GetByID", ctx, category.ID).Return(category, nil)
+
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), new(mocks.MockMessagePublisher), nil, nil)
+
+	minQty, maxQty := units.Of(5), units.Of(2)
+	req := &entity.CreateProductRequest{
+		Name:        "Laptop",
+		Description: "High-performance laptop for developers",
+		Price:       money.MustParse("1299.99"),
+		CategoryID:  category.ID,
+		MinQty:      &minQty,
+		MaxQty:      &maxQty,
+	}
+
+	// Act
+	product, err := service.CreateProduct(ctx, req)
+
+	// Assert
+	assert.Nil(t, product)
+	assert.ErrorIs(t, err, ErrInvalidQuantityLimits)
+	productRepo.AssertNotCalled(t, "Create")
 }
 
 func TestCatalogService_CreateProduct_CategoryNotFound(t *testing.T) {
@@ -357,12 +463,12 @@ func TestCatalogService_CreateProduct_CategoryNotFound(t *testing.T) {
 	categoryID := uuid.New()
 	categoryRepo.On("GetByID", ctx, categoryID).Return(nil, repository.ErrCategoryNotFound)
 
-	service := NewCatalogService(categoryRepo, productRepo, redisCache, kafkaProducer)
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
 
 	req := &entity.CreateProductRequest{
 		Name:        "Laptop",
 		Description: "Description here",
-		Price:       999.99,
+		Price:       money.MustParse("999.99"),
 		CategoryID:  categoryID,
 	}
 
@@ -385,7 +491,7 @@ func TestCatalogService_GetProduct_Success(t *testing.T) {
 	expectedProduct := newTestProductWithCategory()
 	productRepo.On("GetWithCategory", ctx, expectedProduct.ID).Return(expectedProduct, nil)
 
-	service := NewCatalogService(categoryRepo, productRepo, redisCache, kafkaProducer)
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
 
 	// Act
 	product, err := service.GetProduct(ctx, expectedProduct.ID)
@@ -397,6 +503,48 @@ func TestCatalogService_GetProduct_Success(t *testing.T) {
 	assert.NotEmpty(t, product.Category.Name)
 }
 
+func TestCatalogService_GetProduct_CacheHit(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	productRepo := new(mocks.MockProductRepository)
+	productCache := new(mocks.MockProductCache)
+
+	cachedProduct := newTestProductWithCategory()
+	productCache.On("GetProduct", ctx, cachedProduct.ID).Return(cachedProduct, nil)
+
+	service := NewCatalogService(new(mocks.MockCategoryRepository), productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), new(mocks.MockMessagePublisher), nil, productCache)
+
+	// Act
+	product, err := service.GetProduct(ctx, cachedProduct.ID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, cachedProduct.ID, product.ID)
+	productRepo.AssertNotCalled(t, "GetWithCategory", mock.Anything, mock.Anything)
+}
+
+func TestCatalogService_GetProduct_CacheMiss(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	productRepo := new(mocks.MockProductRepository)
+	productCache := new(mocks.MockProductCache)
+
+	expectedProduct := newTestProductWithCategory()
+	productCache.On("GetProduct", ctx, expectedProduct.ID).Return(nil, nil)
+	productRepo.On("GetWithCategory", ctx, expectedProduct.ID).Return(expectedProduct, nil)
+	productCache.On("SetProduct", ctx, expectedProduct, mock.AnythingOfType("time.Duration")).Return(nil)
+
+	service := NewCatalogService(new(mocks.MockCategoryRepository), productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), new(mocks.MockMessagePublisher), nil, productCache)
+
+	// Act
+	product, err := service.GetProduct(ctx, expectedProduct.ID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, expectedProduct.ID, product.ID)
+	productCache.AssertCalled(t, "SetProduct", ctx, expectedProduct, mock.AnythingOfType("time.Duration"))
+}
+
 func TestCatalogService_GetProduct_NotFound(t *testing.T) {
 	// Arrange
 	ctx := context.Background()
@@ -408,7 +556,7 @@ func TestCatalogService_GetProduct_NotFound(t *testing.T) {
 	productID := uuid.New()
 	productRepo.On("GetWithCategory", ctx, productID).Return(nil, repository.ErrProductNotFound)
 
-	service := NewCatalogService(categoryRepo, productRepo, redisCache, kafkaProducer)
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
 
 	// Act
 	product, err := service.GetProduct(ctx, productID)
@@ -430,12 +578,12 @@ func TestCatalogService_GetAllProducts_Success(t *testing.T) {
 		*newTestProductWithCategory(),
 		*newTestProductWithCategory(),
 	}
-	productRepo.On("GetAllWithCategories", ctx).Return(products, nil)
+	productRepo.On("GetAllWithCategories", ctx, entity.ProductFilter{}).Return(products, nil)
 
-	service := NewCatalogService(categoryRepo, productRepo, redisCache, kafkaProducer)
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
 
 	// Act
-	result, err := service.GetAllProducts(ctx)
+	result, err := service.GetAllProducts(ctx, entity.ProductFilter{})
 
 	// Assert
 	require.NoError(t, err)
@@ -455,11 +603,12 @@ func TestCatalogService_UpdateProduct_Success_NoPriceChange(t *testing.T) {
 
 	productRepo.On("GetByID", ctx, existingProduct.ID).Return(existingProduct, nil)
 	productRepo.On("Update", ctx, existingProduct).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, existingProduct.ID.String(), mock.Anything).Return(nil)
 
-	service := NewCatalogService(categoryRepo, productRepo, redisCache, kafkaProducer)
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
 
 	req := &entity.UpdateProductRequest{
-		Name: "Updated Laptop",
+		Name: patch.Of("Updated Laptop"),
 	}
 
 	// Act
@@ -468,8 +617,12 @@ func TestCatalogService_UpdateProduct_Success_NoPriceChange(t *testing.T) {
 	// Assert
 	require.NoError(t, err)
 	assert.Equal(t, "Updated Laptop", product.Name)
-	// Kafka НЕ должен вызываться, т.к. цена не изменилась
-	kafkaProducer.AssertNotCalled(t, "PublishMessage")
+
+	// PRODUCT_UPDATED отправляется при любом изменении и содержит только изменившиеся поля
+	event := decodeProductEvent(t, kafkaProducer)
+	assert.Equal(t, entity.EventTypeProductUpdated, event.EventType)
+	assert.Equal(t, entity.AuditChanges{"name": {Old: "Laptop", New: "Updated Laptop"}}, event.Changes)
+	assert.Nil(t, event.OldPrice)
 }
 
 func TestCatalogService_UpdateProduct_Success_PriceChanged(t *testing.T) {
@@ -486,13 +639,13 @@ func TestCatalogService_UpdateProduct_Success_PriceChanged(t *testing.T) {
 
 	productRepo.On("GetByID", ctx, existingProduct.ID).Return(existingProduct, nil)
 	productRepo.On("Update", ctx, existingProduct).Return(nil)
-	kafkaProducer.On("PublishMessage", ctx, existingProduct.ID.String(), mock.AnythingOfType("[]uint8")).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, existingProduct.ID.String(), mock.AnythingOfType("[]uint8")).Return(nil)
 
-	service := NewCatalogService(categoryRepo, productRepo, redisCache, kafkaProducer)
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
 
-	newPrice := oldPrice + 100.0
+	newPrice := oldPrice + money.MustParse("100.00")
 	req := &entity.UpdateProductRequest{
-		Price: newPrice,
+		Price: patch.Of(newPrice),
 	}
 
 	// Act
@@ -502,7 +655,7 @@ func TestCatalogService_UpdateProduct_Success_PriceChanged(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, newPrice, product.Price)
 	// Kafka ДОЛЖЕН вызываться, т.к. цена изменилась
-	kafkaProducer.AssertCalled(t, "PublishMessage", ctx, existingProduct.ID.String(), mock.AnythingOfType("[]uint8"))
+	kafkaProducer.AssertCalled(t, "PublishMessage", mock.Anything, existingProduct.ID.String(), mock.AnythingOfType("[]uint8"))
 }
 
 func TestCatalogService_UpdateProduct_NotFound(t *testing.T) {
@@ -516,9 +669,9 @@ func TestCatalogService_UpdateProduct_NotFound(t *testing.T) {
 	productID := uuid.New()
 	productRepo.On("GetByID", ctx, productID).Return(nil, repository.ErrProductNotFound)
 
-	service := NewCatalogService(categoryRepo, productRepo, redisCache, kafkaProducer)
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
 
-	req := &entity.UpdateProductRequest{Name: "Updated"}
+	req := &entity.UpdateProductRequest{Name: patch.Of("Updated")}
 
 	// Act
 	product, err := service.UpdateProduct(ctx, productID, req)
@@ -528,6 +681,44 @@ func TestCatalogService_UpdateProduct_NotFound(t *testing.T) {
 	assert.ErrorIs(t, err, ErrProductNotFound)
 }
 
+func TestCatalogService_UpdateProduct_MergePatchClearsFields(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	productRepo := new(mocks.MockProductRepository)
+	kafkaProducer := new(mocks.MockMessagePublisher)
+
+	brandID := uuid.New()
+	stock := 5
+	costPrice := money.MustParse("900.00")
+	existingProduct := newTestProduct(uuid.New())
+	existingProduct.BrandID = &brandID
+	existingProduct.Stock = &stock
+	existingProduct.CostPrice = &costPrice
+
+	productRepo.On("GetByID", ctx, existingProduct.ID).Return(existingProduct, nil)
+	productRepo.On("Update", ctx, existingProduct).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, existingProduct.ID.String(), mock.Anything).Return(nil)
+
+	service := NewCatalogService(new(mocks.MockCategoryRepository), productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), kafkaProducer, nil, nil)
+
+	// {"price": 0, "brand_id": null, "stock": null}: cost_price не передан и не меняется
+	req := &entity.UpdateProductRequest{
+		Price:   patch.Of(money.Amount(0)),
+		BrandID: patch.Null[uuid.UUID](),
+		Stock:   patch.Null[int](),
+	}
+
+	// Act
+	product, err := service.UpdateProduct(ctx, existingProduct.ID, req)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, money.Amount(0), product.Price)
+	assert.Nil(t, product.BrandID)
+	assert.Nil(t, product.Stock)
+	assert.Equal(t, &costPrice, product.CostPrice)
+}
+
 func TestCatalogService_UpdateProduct_CategoryNotFound(t *testing.T) {
 	// Arrange
 	ctx := context.Background()
@@ -542,10 +733,10 @@ func TestCatalogService_UpdateProduct_CategoryNotFound(t *testing.T) {
 	productRepo.On("GetByID", ctx, existingProduct.ID).Return(existingProduct, nil)
 	categoryRepo.On("GetByID", ctx, newCategoryID).Return(nil, repository.ErrCategoryNotFound)
 
-	service := NewCatalogService(categoryRepo, productRepo, redisCache, kafkaProducer)
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
 
 	req := &entity.UpdateProductRequest{
-		CategoryID: newCategoryID,
+		CategoryID: patch.Of(newCategoryID),
 	}
 
 	// Act
@@ -556,6 +747,138 @@ func TestCatalogService_UpdateProduct_CategoryNotFound(t *testing.T) {
 	assert.ErrorIs(t, err, ErrCategoryNotFound)
 }
 
+// ==================== Slug Tests ====================
+
+func TestCatalogService_GetProductBySlug_Current(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	productRepo := new(mocks.MockProductRepository)
+
+	expectedProduct := newTestProductWithCategory()
+	expectedProduct.Slug = "gaming-laptop"
+	productRepo.On("GetBySlug", ctx, "gaming-laptop").Return(expectedProduct, nil)
+
+	service := NewCatalogService(new(mocks.MockCategoryRepository), productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), new(mocks.MockMessagePublisher), nil, nil)
+
+	// Act
+	product, moved, err := service.GetProductBySlug(ctx, "gaming-laptop")
+
+	// Assert
+	require.NoError(t, err)
+	assert.False(t, moved)
+	assert.Equal(t, expectedProduct.ID, product.ID)
+	productRepo.AssertNotCalled(t, "GetByOldSlug", mock.Anything, mock.Anything)
+}
+
+func TestCatalogService_GetProductBySlug_Moved(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	productRepo := new(mocks.MockProductRepository)
+
+	renamed := newTestProductWithCategory()
+	renamed.Slug = "gaming-laptop-pro"
+	productRepo.On("GetBySlug", ctx, "gaming-laptop").Return(nil, repository.ErrProductNotFound)
+	productRepo.On("GetByOldSlug", ctx, "gaming-laptop").Return(renamed, nil)
+
+	service := NewCatalogService(new(mocks.MockCategoryRepository), productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), new(mocks.MockMessagePublisher), nil, nil)
+
+	// Act
+	product, moved, err := service.GetProductBySlug(ctx, "gaming-laptop")
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, moved)
+	assert.Equal(t, "gaming-laptop-pro", product.Slug)
+}
+
+func TestCatalogService_GetCategoryBySlug_NotFound(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	categoryRepo := new(mocks.MockCategoryRepository)
+
+	categoryRepo.On("GetBySlug", ctx, "unknown").Return(nil, repository.ErrCategoryNotFound)
+	categoryRepo.On("GetByOldSlug", ctx, "unknown").Return(nil, repository.ErrCategoryNotFound)
+
+	service := NewCatalogService(categoryRepo, new(mocks.MockProductRepository), new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), new(mocks.MockMessagePublisher), nil, nil)
+
+	// Act
+	category, moved, err := service.GetCategoryBySlug(ctx, "unknown")
+
+	// Assert
+	assert.Nil(t, category)
+	assert.False(t, moved)
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+}
+
+// ==================== Product Lifecycle Tests ====================
+
+func TestCatalogService_PublishProduct_Success(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	productRepo := new(mocks.MockProductRepository)
+	kafkaProducer := new(mocks.MockMessagePublisher)
+
+	draft := newTestProduct(uuid.New())
+	draft.Status = entity.ProductStatusDraft
+
+	productRepo.On("GetByID", ctx, draft.ID).Return(draft, nil)
+	productRepo.On("UpdateStatus", ctx, draft.ID, entity.ProductStatusDraft, entity.ProductStatusPublished).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, draft.ID.String(), mock.MatchedBy(func(data []byte) bool {
+		var event entity.ProductEvent
+		return json.Unmarshal(data, &event) == nil && event.EventType == "PRODUCT_PUBLISHED"
+	})).Return(nil)
+
+	service := NewCatalogService(new(mocks.MockCategoryRepository), productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), kafkaProducer, nil, nil)
+
+	// Act
+	product, err := service.PublishProduct(ctx, draft.ID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, entity.ProductStatusPublished, product.Status)
+	productRepo.AssertExpectations(t)
+	kafkaProducer.AssertExpectations(t)
+}
+
+func TestCatalogService_PublishProduct_InvalidTransition(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	productRepo := new(mocks.MockProductRepository)
+
+	archived := newTestProduct(uuid.New())
+	archived.Status = entity.ProductStatusArchived
+	productRepo.On("GetByID", ctx, archived.ID).Return(archived, nil)
+
+	service := NewCatalogService(new(mocks.MockCategoryRepository), productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), new(mocks.MockMessagePublisher), nil, nil)
+
+	// Act
+	product, err := service.PublishProduct(ctx, archived.ID)
+
+	// Assert
+	assert.Nil(t, product)
+	assert.ErrorIs(t, err, ErrInvalidProductStatus)
+	productRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCatalogService_ArchiveProduct_Success(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	productRepo := new(mocks.MockProductRepository)
+
+	published := newTestProduct(uuid.New())
+	productRepo.On("GetByID", ctx, published.ID).Return(published, nil)
+	productRepo.On("UpdateStatus", ctx, published.ID, entity.ProductStatusPublished, entity.ProductStatusArchived).Return(nil)
+
+	service := NewCatalogService(new(mocks.MockCategoryRepository), productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), newAcceptingPublisher(), nil, nil)
+
+	// Act
+	product, err := service.ArchiveProduct(ctx, published.ID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, entity.ProductStatusArchived, product.Status)
+}
+
 func TestCatalogService_DeleteProduct_Success(t *testing.T) {
 	// Arrange
 	ctx := context.Background()
@@ -563,13 +886,14 @@ func TestCatalogService_DeleteProduct_Success(t *testing.T) {
 	productRepo := new(mocks.MockProductRepository)
 	redisCache := new(mocks.MockRedisCache)
 	kafkaProducer := new(mocks.MockMessagePublisher)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	existingProduct := newTestProduct(uuid.New())
 
 	productRepo.On("GetByID", ctx, existingProduct.ID).Return(existingProduct, nil)
-	productRepo.On("Delete", ctx, existingProduct.ID).Return(nil)
+	productRepo.On("SoftDelete", ctx, existingProduct.ID, mock.AnythingOfType("time.Time")).Return(nil)
 
-	service := NewCatalogService(categoryRepo, productRepo, redisCache, kafkaProducer)
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
 
 	// Act
 	err := service.DeleteProduct(ctx, existingProduct.ID)
@@ -590,7 +914,7 @@ func TestCatalogService_DeleteProduct_NotFound(t *testing.T) {
 	productID := uuid.New()
 	productRepo.On("GetByID", ctx, productID).Return(nil, repository.ErrProductNotFound)
 
-	service := NewCatalogService(categoryRepo, productRepo, redisCache, kafkaProducer)
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
 
 	// Act
 	err := service.DeleteProduct(ctx, productID)
@@ -599,6 +923,81 @@ func TestCatalogService_DeleteProduct_NotFound(t *testing.T) {
 	assert.ErrorIs(t, err, ErrProductNotFound)
 }
 
+func TestCatalogService_DeleteProduct_AlreadyDeleted(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	productRepo := new(mocks.MockProductRepository)
+
+	product := newTestProduct(uuid.New())
+	deletedAt := time.Now()
+	product.DeletedAt = &deletedAt
+	productRepo.On("GetByID", ctx, product.ID).Return(product, nil)
+
+	service := NewCatalogService(new(mocks.MockCategoryRepository), productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), new(mocks.MockMessagePublisher), nil, nil)
+
+	// Act
+	err := service.DeleteProduct(ctx, product.ID)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrProductNotFound)
+	productRepo.AssertNotCalled(t, "SoftDelete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCatalogService_PurgeProduct_Draft(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	productRepo := new(mocks.MockProductRepository)
+
+	product := newTestProduct(uuid.New())
+	product.Status = entity.ProductStatusDraft
+	productRepo.On("GetByID", ctx, product.ID).Return(product, nil)
+	productRepo.On("Delete", ctx, product.ID).Return(nil)
+
+	service := NewCatalogService(new(mocks.MockCategoryRepository), productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), newAcceptingPublisher(), nil, nil)
+
+	// Act
+	err := service.PurgeProduct(ctx, product.ID)
+
+	// Assert
+	require.NoError(t, err)
+	productRepo.AssertExpectations(t)
+}
+
+func TestCatalogService_PurgeProduct_Referenced(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(p *entity.Product)
+	}{
+		{name: "published", modify: func(p *entity.Product) { p.Status = entity.ProductStatusPublished }},
+		{name: "archived", modify: func(p *entity.Product) { p.Status = entity.ProductStatusArchived }},
+		{name: "draft with reviews", modify: func(p *entity.Product) {
+			p.Status = entity.ProductStatusDraft
+			p.RatingCount = 2
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			productRepo := new(mocks.MockProductRepository)
+
+			product := newTestProduct(uuid.New())
+			tt.modify(product)
+			productRepo.On("GetByID", ctx, product.ID).Return(product, nil)
+
+			service := NewCatalogService(new(mocks.MockCategoryRepository), productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), new(mocks.MockMessagePublisher), nil, nil)
+
+			// Act
+			err := service.PurgeProduct(ctx, product.ID)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrProductReferenced)
+			productRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+		})
+	}
+}
+
 func TestCatalogService_UpdateProduct_KafkaErrorIgnored(t *testing.T) {
 	// Arrange
 	ctx := context.Background()
@@ -612,12 +1011,12 @@ func TestCatalogService_UpdateProduct_KafkaErrorIgnored(t *testing.T) {
 
 	productRepo.On("GetByID", ctx, existingProduct.ID).Return(existingProduct, nil)
 	productRepo.On("Update", ctx, existingProduct).Return(nil)
-	kafkaProducer.On("PublishMessage", ctx, existingProduct.ID.String(), mock.AnythingOfType("[]uint8")).Return(errors.New("kafka error"))
+	kafkaProducer.On("PublishMessage", mock.Anything, existingProduct.ID.String(), mock.AnythingOfType("[]uint8")).Return(errors.New("kafka error"))
 
-	service := NewCatalogService(categoryRepo, productRepo, redisCache, kafkaProducer)
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
 
 	req := &entity.UpdateProductRequest{
-		Price: oldPrice + 50.0,
+		Price: patch.Of(oldPrice + money.MustParse("50.00")),
 	}
 
 	// Act
@@ -627,3 +1026,30 @@ func TestCatalogService_UpdateProduct_KafkaErrorIgnored(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotNil(t, product)
 }
+
+// ==================== Dashboard Summary Tests ====================
+
+func TestCatalogService_GetDashboardSummary(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	categoryRepo := new(mocks.MockCategoryRepository)
+	productRepo := new(mocks.MockProductRepository)
+
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), new(mocks.MockMessagePublisher), nil, nil)
+
+	productRepo.On("CountByStatus", ctx).Return(map[entity.ProductStatus]int64{
+		entity.ProductStatusPublished: 8,
+		entity.ProductStatusDraft:     2,
+	}, nil)
+	categoryRepo.On("Count", ctx).Return(int64(4), nil)
+
+	// Act
+	summary, err := service.GetDashboardSummary(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), summary.Products)
+	assert.Equal(t, int64(4), summary.Categories)
+	assert.GreaterOrEqual(t, summary.Cache.HitRate, 0.0)
+	assert.LessOrEqual(t, summary.Cache.HitRate, 1.0)
+}
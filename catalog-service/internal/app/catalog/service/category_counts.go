@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/repository"
+	"augustberries/catalog-service/internal/app/catalog/util"
+	"augustberries/pkg/lock"
+	"augustberries/pkg/recovery"
+	"augustberries/pkg/tenant"
+
+	"github.com/robfig/cron/v3"
+)
+
+// CategoryCounter периодически пересчитывает число товаров в категориях
+// Счетчик не обновляется при каждом изменении товара: список категорий отстает от каталога не больше чем на период задачи
+type CategoryCounter struct {
+	categoryRepo repository.CategoryRepository
+	cache        util.RedisCache
+	cron         *cron.Cron
+	locker       *lock.Locker // nil - счетчики пересчитывает каждый экземпляр
+	lockTTL      time.Duration
+}
+
+// NewCategoryCounter создает задачу пересчета; запуски не накладываются друг на друга
+func NewCategoryCounter(categoryRepo repository.CategoryRepository, cache util.RedisCache) *CategoryCounter {
+	c := cron.New(
+		cron.WithLogger(cron.VerbosePrintfLogger(log.Default())),
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)),
+	)
+
+	return &CategoryCounter{
+		categoryRepo: categoryRepo,
+		cache:        cache,
+		cron:         c,
+	}
+}
+
+// SetLocker включает распределенную блокировку: один запуск пересчитывает счетчики только на одном экземпляре
+func (c *CategoryCounter) SetLocker(locker *lock.Locker, ttl time.Duration) {
+	c.locker = locker
+	c.lockTTL = ttl
+}
+
+// Refresh пересчитывает счетчики и сбрасывает кеш категорий магазинов, в которых они изменились
+func (c *CategoryCounter) Refresh(ctx context.Context) error {
+	tenants, err := c.categoryRepo.RefreshProductCounts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to refresh category product counts: %w", err)
+	}
+
+	for _, id := range tenants {
+		if err := c.cache.DeleteCategories(tenant.WithID(ctx, id)); err != nil {
+			log.Printf("ERROR: Failed to invalidate categories cache for tenant %s: %v", id, err)
+		}
+	}
+
+	return nil
+}
+
+// Start пересчитывает счетчики при старте и затем по расписанию
+func (c *CategoryCounter) Start(ctx context.Context, schedule string) error {
+	log.Printf("Starting category counter with schedule: %s", schedule)
+
+	if _, err := c.cron.AddFunc(schedule, recovery.Wrap("catalog-service", "category_counter", func() { c.run(ctx) })); err != nil {
+		return err
+	}
+
+	c.cron.Start()
+	c.run(ctx)
+
+	return nil
+}
+
+// Stop останавливает задачу и ждет завершения текущего пересчета
+func (c *CategoryCounter) Stop() {
+	log.Println("Stopping category counter...")
+	<-c.cron.Stop().Done()
+	log.Println("Category counter stopped")
+}
+
+func (c *CategoryCounter) run(ctx context.Context) {
+	err := c.locker.Once(ctx, "category_counter", c.lockTTL, func(ctx context.Context) {
+		if err := c.Refresh(ctx); err != nil {
+			log.Printf("ERROR: %v", err)
+		}
+	})
+	if errors.Is(err, lock.ErrNotAcquired) {
+		log.Println("Category counts refresh skipped: running on another instance")
+	} else if err != nil {
+		log.Printf("ERROR: Category counter: %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"augustberries/catalog-service/internal/app/catalog/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// ==================== CategoryCounter Tests ====================
+
+func TestCategoryCounter_Refresh_InvalidatesChangedTenants(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	categoryRepo := new(mocks.MockCategoryRepository)
+	redisCache := new(mocks.MockRedisCache)
+
+	categoryRepo.On("RefreshProductCounts", ctx).Return([]string{"default", "shop"}, nil)
+	redisCache.On("DeleteCategories", tenantCtx("default")).Return(errors.New("redis down"))
+	redisCache.On("DeleteCategories", tenantCtx("shop")).Return(nil)
+
+	counter := NewCategoryCounter(categoryRepo, redisCache)
+
+	// Act
+	err := counter.Refresh(ctx)
+
+	// Assert: ошибка кеша одного магазина не мешает сбросить кеш остальных
+	require.NoError(t, err)
+	redisCache.AssertNumberOfCalls(t, "DeleteCategories", 2)
+}
+
+func TestCategoryCounter_Refresh_RepositoryError(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	categoryRepo := new(mocks.MockCategoryRepository)
+	redisCache := new(mocks.MockRedisCache)
+
+	categoryRepo.On("RefreshProductCounts", ctx).Return(nil, errors.New("db error"))
+
+	counter := NewCategoryCounter(categoryRepo, redisCache)
+
+	// Act
+	err := counter.Refresh(ctx)
+
+	// Assert
+	assert.Error(t, err)
+	redisCache.AssertNotCalled(t, "DeleteCategories", mock.Anything)
+}
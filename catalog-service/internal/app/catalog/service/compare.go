@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+
+	"github.com/google/uuid"
+)
+
+// MaxCompareProducts - максимум товаров в одном сравнении
+const MaxCompareProducts = 6
+
+// Ключи строк сравнения в порядке вывода
+// Характеристики и варианты товаров добавляются сюда же, когда появятся в каталоге
+const (
+	CompareKeyPrice       = "price"
+	CompareKeyCategory    = "category"
+	CompareKeyBrand       = "brand"
+	CompareKeyRatingAvg   = "rating_avg"
+	CompareKeyRatingCount = "rating_count"
+	CompareKeyInStock     = "in_stock"
+	CompareKeyTags        = "tags"
+)
+
+// CompareProducts возвращает товары и выровненную по ним матрицу характеристик для страницы сравнения
+// Повторяющиеся ID учитываются один раз, порядок колонок совпадает с порядком запроса
+// Неопубликованные товары сравниваются только при includeUnpublished (admin), иначе попадают в Missing
+func (s *CatalogService) CompareProducts(ctx context.Context, ids []uuid.UUID, includeUnpublished bool) (*entity.ProductComparison, error) {
+	unique := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) > MaxCompareProducts {
+		return nil, ErrTooManyProductIDs
+	}
+
+	found, err := s.productRepo.GetDetailsByIDs(ctx, unique)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+
+	byID := make(map[uuid.UUID]*entity.Product, len(found))
+	for i := range found {
+		if includeUnpublished || found[i].Status == entity.ProductStatusPublished {
+			byID[found[i].ID] = &found[i]
+		}
+	}
+
+	comparison := &entity.ProductComparison{Products: make([]entity.Product, 0, len(unique))}
+	for _, id := range unique {
+		product, ok := byID[id]
+		if !ok {
+			comparison.Missing = append(comparison.Missing, id)
+			continue
+		}
+		comparison.Products = append(comparison.Products, *product)
+	}
+
+	comparison.Attributes = compareAttributes(comparison.Products)
+	return comparison, nil
+}
+
+// compareAttributes строит строки сравнения; nil в строке - у товара нет значения
+func compareAttributes(products []entity.Product) []entity.ComparedAttribute {
+	rows := []entity.ComparedAttribute{
+		{Key: CompareKeyPrice},
+		{Key: CompareKeyCategory},
+		{Key: CompareKeyBrand},
+		{Key: CompareKeyRatingAvg},
+		{Key: CompareKeyRatingCount},
+		{Key: CompareKeyInStock},
+		{Key: CompareKeyTags},
+	}
+	for i := range rows {
+		rows[i].Values = make([]interface{}, len(products))
+	}
+
+	for col, product := range products {
+		rows[0].Values[col] = product.Price
+		if product.Category != nil {
+			rows[1].Values[col] = product.Category.Name
+		}
+		if product.Brand != nil {
+			rows[2].Values[col] = product.Brand.Name
+		}
+		// Средняя оценка товара без отзывов не сравнивается с оценками других товаров
+		if product.RatingCount > 0 {
+			rows[3].Values[col] = product.RatingAvg
+		}
+		rows[4].Values[col] = product.RatingCount
+		// Остаток nil - остаток не отслеживается, товар всегда в наличии
+		rows[5].Values[col] = product.Stock == nil || *product.Stock > 0
+		tags := make([]string, 0, len(product.Tags))
+		for _, tag := range product.Tags {
+			tags = append(tags, tag.Slug)
+		}
+		rows[6].Values[col] = tags
+	}
+
+	for i := range rows {
+		values := rows[i].Values
+		for j := 1; j < len(values); j++ {
+			if !reflect.DeepEqual(values[j], values[0]) {
+				rows[i].Different = true
+				break
+			}
+		}
+	}
+	return rows
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/repository/mocks"
+	"augustberries/pkg/money"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ==================== CompareProducts Tests ====================
+
+func TestCatalogService_CompareProducts_AlignedMatrix(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	productRepo := new(mocks.MockProductRepository)
+
+	category := newTestCategory()
+	first := newTestProduct(category.ID)
+	first.Category = category
+	first.Brand = &entity.Brand{ID: uuid.New(), Name: "Acme"}
+	first.RatingAvg, first.RatingCount = 4.5, 10
+	first.Tags = []entity.Tag{{ID: uuid.New(), Slug: "sale"}}
+	zero := 0
+	second := newTestProduct(category.ID)
+	second.Category = category
+	second.Price = money.MustParse("999.99")
+	second.Stock = &zero
+	draft := newTestProduct(category.ID)
+	draft.Status = entity.ProductStatusDraft
+	missingID := uuid.New()
+
+	// Репозиторий возвращает товары в произвольном порядке
+	productRepo.On("GetDetailsByIDs", ctx, []uuid.UUID{second.ID, first.ID, draft.ID, missingID}).
+		Return([]entity.Product{*first, *draft, *second}, nil)
+
+	service := NewCatalogService(new(mocks.MockCategoryRepository), productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), new(mocks.MockMessagePublisher), nil, nil)
+
+	// Act
+	comparison, err := service.CompareProducts(ctx, []uuid.UUID{second.ID, first.ID, second.ID, draft.ID, missingID}, false)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, comparison.Products, 2)
+	assert.Equal(t, second.ID, comparison.Products[0].ID)
+	assert.Equal(t, first.ID, comparison.Products[1].ID)
+	// Черновик скрыт от пользователя так же, как в GET /products/:id
+	assert.Equal(t, []uuid.UUID{draft.ID, missingID}, comparison.Missing)
+
+	rows := make(map[string]entity.ComparedAttribute, len(comparison.Attributes))
+	for _, row := range comparison.Attributes {
+		require.Len(t, row.Values, 2)
+		rows[row.Key] = row
+	}
+	assert.Equal(t, []interface{}{money.MustParse("999.99"), money.MustParse("1299.99")}, rows[CompareKeyPrice].Values)
+	assert.True(t, rows[CompareKeyPrice].Different)
+	assert.Equal(t, []interface{}{"Electronics", "Electronics"}, rows[CompareKeyCategory].Values)
+	assert.False(t, rows[CompareKeyCategory].Different)
+	assert.Equal(t, []interface{}{nil, "Acme"}, rows[CompareKeyBrand].Values)
+	// Товар без отзывов не получает среднюю оценку 0
+	assert.Equal(t, []interface{}{nil, 4.5}, rows[CompareKeyRatingAvg].Values)
+	assert.Equal(t, []interface{}{false, true}, rows[CompareKeyInStock].Values)
+	assert.Equal(t, []interface{}{[]string{}, []string{"sale"}}, rows[CompareKeyTags].Values)
+}
+
+func TestCatalogService_CompareProducts_AdminSeesUnpublished(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	productRepo := new(mocks.MockProductRepository)
+
+	draft := newTestProduct(uuid.New())
+	draft.Status = entity.ProductStatusDraft
+	productRepo.On("GetDetailsByIDs", ctx, []uuid.UUID{draft.ID}).Return([]entity.Product{*draft}, nil)
+
+	service := NewCatalogService(new(mocks.MockCategoryRepository), productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), new(mocks.MockMessagePublisher), nil, nil)
+
+	// Act
+	comparison, err := service.CompareProducts(ctx, []uuid.UUID{draft.ID}, true)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, comparison.Products, 1)
+	assert.Empty(t, comparison.Missing)
+}
+
+func TestCatalogService_CompareProducts_TooMany(t *testing.T) {
+	// Arrange
+	productRepo := new(mocks.MockProductRepository)
+	service := NewCatalogService(new(mocks.MockCategoryRepository), productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), new(mocks.MockMessagePublisher), nil, nil)
+
+	ids := make([]uuid.UUID, MaxCompareProducts+1)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+
+	// Act
+	comparison, err := service.CompareProducts(context.Background(), ids, false)
+
+	// Assert
+	assert.Nil(t, comparison)
+	assert.ErrorIs(t, err, ErrTooManyProductIDs)
+	productRepo.AssertNotCalled(t, "GetDetailsByIDs")
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/util"
+	"augustberries/pkg/kafka"
+)
+
+// newProductEvent заполняет событие текущим состоянием товара
+func newProductEvent(eventType string, product *entity.Product, changes entity.AuditChanges) entity.ProductEvent {
+	return entity.ProductEvent{
+		EventType:  eventType,
+		TenantID:   product.TenantID,
+		ProductID:  product.ID,
+		Name:       product.Name,
+		Slug:       product.Slug,
+		Status:     product.Status,
+		Price:      product.Price,
+		CategoryID: product.CategoryID,
+		BrandID:    product.BrandID,
+		SupplierID: product.SupplierID,
+		Stock:      product.Stock,
+		Changes:    changes,
+		Timestamp:  time.Now(),
+	}
+}
+
+// newCategoryEvent заполняет событие текущим состоянием категории
+func newCategoryEvent(eventType string, category *entity.Category, changes entity.AuditChanges) entity.CategoryEvent {
+	return entity.CategoryEvent{
+		EventType:  eventType,
+		TenantID:   category.TenantID,
+		CategoryID: category.ID,
+		Name:       category.Name,
+		Slug:       category.Slug,
+		Changes:    changes,
+		Timestamp:  time.Now(),
+	}
+}
+
+// publishProductEvent отправляет событие товара с ключом по ID товара
+// Ошибка отправки не отменяет изменение и только логируется
+func (s *CatalogService) publishProductEvent(ctx context.Context, event entity.ProductEvent) {
+	publishCatalogEvent(ctx, s.kafkaProducer, event.ProductID.String(), event.EventType, event)
+}
+
+// publishCategoryEvent отправляет событие категории с ключом по ID категории
+func (s *CatalogService) publishCategoryEvent(ctx context.Context, event entity.CategoryEvent) {
+	publishCatalogEvent(ctx, s.kafkaProducer, event.CategoryID.String(), event.EventType, event)
+}
+
+func publishCatalogEvent(ctx context.Context, producer util.MessagePublisher, key, eventType string, event interface{}) {
+	eventData, err := json.Marshal(event)
+	if err != nil {
+		fmt.Printf("failed to marshal %s event: %v\n", eventType, err)
+		return
+	}
+
+	if err := producer.PublishMessage(kafka.WithEvent(ctx, eventType, entity.EventSchemaVersion), key, eventData); err != nil {
+		fmt.Printf("failed to publish %s event: %v\n", eventType, err)
+	}
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/repository/mocks"
+	"augustberries/pkg/money"
+	"augustberries/pkg/patch"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newAcceptingPublisher создает мок Kafka, принимающий любые события
+func newAcceptingPublisher() *mocks.MockMessagePublisher {
+	kafkaProducer := new(mocks.MockMessagePublisher)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	return kafkaProducer
+}
+
+// decodeProductEvent разбирает последнее событие, отправленное в мок Kafka
+func decodeProductEvent(t *testing.T, kafkaProducer *mocks.MockMessagePublisher) entity.ProductEvent {
+	var event entity.ProductEvent
+	decodeLastEvent(t, kafkaProducer, &event)
+	return event
+}
+
+func decodeLastEvent(t *testing.T, kafkaProducer *mocks.MockMessagePublisher, event interface{}) {
+	require.NotEmpty(t, kafkaProducer.Calls)
+	data := kafkaProducer.Calls[len(kafkaProducer.Calls)-1].Arguments.Get(2).([]byte)
+	require.NoError(t, json.Unmarshal(data, event))
+}
+
+// ==================== Product Events Tests ====================
+
+func TestCatalogService_CreateProduct_PublishesProductCreated(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	categoryRepo := new(mocks.MockCategoryRepository)
+	productRepo := new(mocks.MockProductRepository)
+	kafkaProducer := new(mocks.MockMessagePublisher)
+
+	category := newTestCategory()
+	categoryRepo.On("GetByID", ctx, category.ID).Return(category, nil)
+	productRepo.On("Create", ctx, mock.AnythingOfType("*entity.Product")).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+
+	service := NewCatalogService(categoryRepo, productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), kafkaProducer, nil, nil)
+
+	// Act
+	product, err := service.CreateProduct(ctx, &entity.CreateProductRequest{Name: "Laptop", Price: money.MustParse("10.00"), CategoryID: category.ID})
+
+	// Assert
+	require.NoError(t, err)
+	event := decodeProductEvent(t, kafkaProducer)
+	assert.Equal(t, entity.EventTypeProductCreated, event.EventType)
+	assert.Equal(t, product.ID, event.ProductID)
+	assert.Equal(t, entity.ProductStatusDraft, event.Status)
+	assert.Empty(t, event.Changes)
+	kafkaProducer.AssertCalled(t, "PublishMessage", mock.Anything, product.ID.String(), mock.Anything)
+}
+
+func TestCatalogService_UpdateProduct_NoChanges_NoEvent(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	productRepo := new(mocks.MockProductRepository)
+	kafkaProducer := new(mocks.MockMessagePublisher)
+
+	product := newTestProduct(uuid.New())
+	productRepo.On("GetByID", ctx, product.ID).Return(product, nil)
+	productRepo.On("Update", ctx, product).Return(nil)
+
+	service := NewCatalogService(new(mocks.MockCategoryRepository), productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), kafkaProducer, nil, nil)
+
+	// Act
+	_, err := service.UpdateProduct(ctx, product.ID, &entity.UpdateProductRequest{Name: patch.Of(product.Name)})
+
+	// Assert
+	require.NoError(t, err)
+	kafkaProducer.AssertNotCalled(t, "PublishMessage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCatalogService_DeleteProduct_PublishesProductDeleted(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	productRepo := new(mocks.MockProductRepository)
+	kafkaProducer := new(mocks.MockMessagePublisher)
+
+	product := newTestProduct(uuid.New())
+	product.TenantID = "shop-a"
+	productRepo.On("GetByID", ctx, product.ID).Return(product, nil)
+	productRepo.On("SoftDelete", ctx, product.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, product.ID.String(), mock.Anything).Return(nil)
+
+	service := NewCatalogService(new(mocks.MockCategoryRepository), productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), kafkaProducer, nil, nil)
+
+	// Act
+	err := service.DeleteProduct(ctx, product.ID)
+
+	// Assert
+	require.NoError(t, err)
+	event := decodeProductEvent(t, kafkaProducer)
+	assert.Equal(t, entity.EventTypeProductDeleted, event.EventType)
+	assert.Equal(t, "shop-a", event.TenantID)
+	assert.Equal(t, product.Name, event.Name)
+}
+
+func TestCatalogService_ArchiveProduct_PublishesStatusChange(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	productRepo := new(mocks.MockProductRepository)
+	kafkaProducer := new(mocks.MockMessagePublisher)
+
+	product := newTestProduct(uuid.New())
+	productRepo.On("GetByID", ctx, product.ID).Return(product, nil)
+	productRepo.On("UpdateStatus", ctx, product.ID, entity.ProductStatusPublished, entity.ProductStatusArchived).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, product.ID.String(), mock.Anything).Return(nil)
+
+	service := NewCatalogService(new(mocks.MockCategoryRepository), productRepo, new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), new(mocks.MockRedisCache), kafkaProducer, nil, nil)
+
+	// Act
+	_, err := service.ArchiveProduct(ctx, product.ID)
+
+	// Assert
+	require.NoError(t, err)
+	event := decodeProductEvent(t, kafkaProducer)
+	assert.Equal(t, entity.EventTypeProductUpdated, event.EventType)
+	assert.Equal(t, entity.AuditChanges{"status": {Old: "published", New: "archived"}}, event.Changes)
+}
+
+// ==================== Category Events Tests ====================
+
+func TestCatalogService_UpdateCategory_PublishesCategoryUpdated(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	categoryRepo := new(mocks.MockCategoryRepository)
+	redisCache := new(mocks.MockRedisCache)
+	kafkaProducer := new(mocks.MockMessagePublisher)
+
+	category := newTestCategory()
+	categoryRepo.On("GetByID", ctx, category.ID).Return(category, nil)
+	categoryRepo.On("Update", ctx, category).Return(nil)
+	redisCache.On("DeleteCategories", ctx).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, category.ID.String(), mock.Anything).Return(nil)
+
+	service := NewCatalogService(categoryRepo, new(mocks.MockProductRepository), new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
+
+	// Act
+	_, err := service.UpdateCategory(ctx, category.ID, &entity.UpdateCategoryRequest{Name: "Phones"})
+
+	// Assert
+	require.NoError(t, err)
+	var event entity.CategoryEvent
+	decodeLastEvent(t, kafkaProducer, &event)
+	assert.Equal(t, entity.EventTypeCategoryUpdated, event.EventType)
+	assert.Equal(t, category.ID, event.CategoryID)
+	assert.Equal(t, entity.AuditChanges{"name": {Old: "Electronics", New: "Phones"}}, event.Changes)
+}
+
+func TestCatalogService_DeleteCategory_PublishesCategoryDeleted(t *testing.T) {
+	// Arrange
+	ctx := tenant.WithID(context.Background(), "shop-a")
+	categoryRepo := new(mocks.MockCategoryRepository)
+	redisCache := new(mocks.MockRedisCache)
+	kafkaProducer := new(mocks.MockMessagePublisher)
+
+	categoryID := uuid.New()
+	categoryRepo.On("Delete", ctx, categoryID).Return(nil)
+	redisCache.On("DeleteCategories", ctx).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, categoryID.String(), mock.Anything).Return(nil)
+
+	service := NewCatalogService(categoryRepo, new(mocks.MockProductRepository), new(mocks.MockBrandRepository), new(mocks.MockSupplierRepository), redisCache, kafkaProducer, nil, nil)
+
+	// Act
+	err := service.DeleteCategory(ctx, categoryID)
+
+	// Assert
+	require.NoError(t, err)
+	var event entity.CategoryEvent
+	decodeLastEvent(t, kafkaProducer, &event)
+	assert.Equal(t, entity.EventTypeCategoryDeleted, event.EventType)
+	assert.Equal(t, "shop-a", event.TenantID)
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"augustberries/pkg/lock"
+	"augustberries/pkg/recovery"
+
+	"github.com/robfig/cron/v3"
+)
+
+// FeedScheduler периодически загружает фиды поставщиков, которым пора обновиться
+type FeedScheduler struct {
+	cron    *cron.Cron
+	service *FeedService
+	locker  *lock.Locker // nil - фиды загружает каждый экземпляр
+	lockTTL time.Duration
+}
+
+// NewFeedScheduler создает планировщик; запуски не накладываются друг на друга
+func NewFeedScheduler(service *FeedService) *FeedScheduler {
+	c := cron.New(
+		cron.WithLogger(cron.VerbosePrintfLogger(log.Default())),
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)),
+	)
+
+	return &FeedScheduler{
+		cron:    c,
+		service: service,
+	}
+}
+
+// SetLocker включает распределенную блокировку: один запуск загружает фиды только на одном экземпляре
+func (s *FeedScheduler) SetLocker(locker *lock.Locker, ttl time.Duration) {
+	s.locker = locker
+	s.lockTTL = ttl
+}
+
+// Start запускает планировщик; первая загрузка - по расписанию, чтобы не задерживать старт сервиса
+func (s *FeedScheduler) Start(ctx context.Context, schedule string) error {
+	log.Printf("Starting feed scheduler with schedule: %s", schedule)
+
+	if _, err := s.cron.AddFunc(schedule, recovery.Wrap("catalog-service", "feed_scheduler", func() { s.run(ctx) })); err != nil {
+		return err
+	}
+
+	s.cron.Start()
+
+	return nil
+}
+
+// Stop останавливает планировщик и ждет завершения текущего импорта
+func (s *FeedScheduler) Stop() {
+	log.Println("Stopping feed scheduler...")
+	<-s.cron.Stop().Done()
+	log.Println("Feed scheduler stopped")
+}
+
+func (s *FeedScheduler) run(ctx context.Context) {
+	err := s.locker.Once(ctx, "feed_scheduler", s.lockTTL, func(ctx context.Context) {
+		if err := s.service.RunDue(ctx); err != nil {
+			log.Printf("ERROR: Failed to import supplier feeds: %v", err)
+		}
+	})
+	if errors.Is(err, lock.ErrNotAcquired) {
+		log.Println("Feed import skipped: running on another instance")
+	} else if err != nil {
+		log.Printf("ERROR: Feed scheduler: %v", err)
+	}
+}
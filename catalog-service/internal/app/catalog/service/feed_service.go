@@ -0,0 +1,482 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/repository"
+	"augustberries/catalog-service/internal/app/catalog/util"
+	"augustberries/pkg/id"
+	"augustberries/pkg/money"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+)
+
+var ErrFeedNotFound = errors.New("supplier feed not found")
+
+const (
+	// feedImportActor - от имени этого пользователя плановый импорт пишет журнал изменений
+	feedImportActor = "feed-import"
+	// feedBatchSize - сколько записей фида сохраняется одной транзакцией
+	feedBatchSize = 500
+	// dueFeedsBatch - сколько фидов загружается за один запуск задачи
+	dueFeedsBatch = 20
+	// maxFeedImportErrors - сколько ошибок записей сохраняется в статистике запуска
+	maxFeedImportErrors = 20
+	// feedImportsLimit - сколько последних запусков отдает история импорта
+	feedImportsLimit = 50
+	// defaultFeedInterval - период загрузки фида, если он не задан (в минутах)
+	defaultFeedInterval = 60
+)
+
+// FeedService управляет фидами поставщиков и импортирует из них товары
+// Товар фида находится по артикулу поставщика: новые создаются черновиками, существующие обновляются
+type FeedService struct {
+	feedRepo      repository.FeedRepository
+	supplierRepo  repository.SupplierRepository
+	categoryRepo  repository.CategoryRepository
+	productRepo   repository.ProductRepository
+	fetcher       util.FeedFetcher
+	kafkaProducer util.MessagePublisher
+	audit         *AuditLog         // nil - журнал изменений отключен
+	productCache  util.ProductCache // nil - кэш карточек отключен
+	now           func() time.Time
+}
+
+func NewFeedService(
+	feedRepo repository.FeedRepository,
+	supplierRepo repository.SupplierRepository,
+	categoryRepo repository.CategoryRepository,
+	productRepo repository.ProductRepository,
+	fetcher util.FeedFetcher,
+	kafkaProducer util.MessagePublisher,
+	audit *AuditLog,
+	productCache util.ProductCache,
+) *FeedService {
+	return &FeedService{
+		feedRepo:      feedRepo,
+		supplierRepo:  supplierRepo,
+		categoryRepo:  categoryRepo,
+		productRepo:   productRepo,
+		fetcher:       fetcher,
+		kafkaProducer: kafkaProducer,
+		audit:         audit,
+		productCache:  productCache,
+		now:           time.Now,
+	}
+}
+
+// CreateFeed добавляет фид поставщика; первая загрузка - при следующем запуске задачи
+func (s *FeedService) CreateFeed(ctx context.Context, req *entity.SupplierFeedRequest) (*entity.SupplierFeed, error) {
+	feed := &entity.SupplierFeed{
+		ID:        uuid.New(),
+		CreatedAt: s.now(),
+	}
+	if err := s.applyFeedRequest(ctx, feed, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.feedRepo.Create(ctx, feed); err != nil {
+		return nil, fmt.Errorf("failed to create feed: %w", err)
+	}
+
+	return feed, nil
+}
+
+// ListFeeds возвращает фиды магазина
+func (s *FeedService) ListFeeds(ctx context.Context) ([]entity.SupplierFeed, error) {
+	feeds, err := s.feedRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feeds: %w", err)
+	}
+	return feeds, nil
+}
+
+// GetFeed возвращает фид по ID
+func (s *FeedService) GetFeed(ctx context.Context, id uuid.UUID) (*entity.SupplierFeed, error) {
+	feed, err := s.feedRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrFeedNotFound) {
+			return nil, ErrFeedNotFound
+		}
+		return nil, fmt.Errorf("failed to get feed: %w", err)
+	}
+	return feed, nil
+}
+
+// UpdateFeed заменяет настройки фида
+func (s *FeedService) UpdateFeed(ctx context.Context, id uuid.UUID, req *entity.SupplierFeedRequest) (*entity.SupplierFeed, error) {
+	feed, err := s.GetFeed(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyFeedRequest(ctx, feed, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.feedRepo.Update(ctx, feed); err != nil {
+		if errors.Is(err, repository.ErrFeedNotFound) {
+			return nil, ErrFeedNotFound
+		}
+		return nil, fmt.Errorf("failed to update feed: %w", err)
+	}
+
+	return feed, nil
+}
+
+// DeleteFeed удаляет фид; импортированные товары остаются в каталоге
+func (s *FeedService) DeleteFeed(ctx context.Context, id uuid.UUID) error {
+	if err := s.feedRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrFeedNotFound) {
+			return ErrFeedNotFound
+		}
+		return fmt.Errorf("failed to delete feed: %w", err)
+	}
+	return nil
+}
+
+// ListImports возвращает статистику последних запусков импорта фида
+func (s *FeedService) ListImports(ctx context.Context, id uuid.UUID) ([]entity.FeedImport, error) {
+	if _, err := s.GetFeed(ctx, id); err != nil {
+		return nil, err
+	}
+
+	imports, err := s.feedRepo.ListImports(ctx, id, feedImportsLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed imports: %w", err)
+	}
+	return imports, nil
+}
+
+// RunFeed загружает фид немедленно, независимо от расписания и выключения
+func (s *FeedService) RunFeed(ctx context.Context, id uuid.UUID) (*entity.FeedImport, error) {
+	feed, err := s.GetFeed(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.Import(ctx, feed)
+}
+
+// RunDue загружает фиды всех магазинов, которые пора обновить
+// Вызывается фоновой задачей; ошибка одного фида не останавливает остальные
+func (s *FeedService) RunDue(ctx context.Context) error {
+	feeds, err := s.feedRepo.ListDue(ctx, s.now(), dueFeedsBatch)
+	if err != nil {
+		return fmt.Errorf("failed to get due feeds: %w", err)
+	}
+
+	for i := range feeds {
+		feed := &feeds[i]
+		// Задача обслуживает все магазины: запросы выполняются в магазине фида
+		feedCtx := util.WithActor(tenant.WithID(ctx, feed.TenantID), util.Actor{UserID: feedImportActor})
+
+		imp, err := s.Import(feedCtx, feed)
+		if err != nil {
+			fmt.Printf("failed to import feed %s: %v\n", feed.ID, err)
+			continue
+		}
+		if imp.Status == entity.FeedImportFailed {
+			fmt.Printf("feed %s import failed: %s\n", feed.ID, imp.Error)
+		}
+	}
+
+	return nil
+}
+
+// Import загружает фид и сохраняет статистику запуска
+// Ошибка загрузки или разбора фида не возвращается, а попадает в статистику (status failed);
+// пачки, сохраненные до ошибки, остаются в каталоге
+func (s *FeedService) Import(ctx context.Context, feed *entity.SupplierFeed) (*entity.FeedImport, error) {
+	imp := &entity.FeedImport{
+		ID:        uuid.New(),
+		FeedID:    feed.ID,
+		Status:    entity.FeedImportSucceeded,
+		Errors:    []string{},
+		StartedAt: s.now(),
+	}
+
+	// Время запуска отмечается сразу: недоступный фид не загружается повторно до следующего периода
+	if err := s.feedRepo.MarkRun(ctx, feed.ID, imp.StartedAt); err != nil {
+		return nil, fmt.Errorf("failed to mark feed run: %w", err)
+	}
+
+	if err := s.importFeed(ctx, feed, imp); err != nil {
+		imp.Status = entity.FeedImportFailed
+		imp.Error = err.Error()
+	}
+	imp.FinishedAt = s.now()
+
+	if err := s.feedRepo.SaveImport(ctx, imp); err != nil {
+		return nil, fmt.Errorf("failed to save feed import: %w", err)
+	}
+
+	return imp, nil
+}
+
+// feedItem - запись фида, приведенная к полям товара
+type feedItem struct {
+	row         int
+	sku         string
+	name        string
+	description *string
+	price       money.Amount
+	costPrice   *money.Amount
+	stock       *int
+}
+
+func (s *FeedService) importFeed(ctx context.Context, feed *entity.SupplierFeed, imp *entity.FeedImport) error {
+	body, err := s.fetcher.Fetch(ctx, feed.URL)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	batch := make([]feedItem, 0, feedBatchSize)
+	seen := make(map[string]bool)
+
+	err = util.ParseFeed(body, feed.Format, feed.Mapping, func(record util.FeedRecord) error {
+		imp.Rows++
+		item, err := mapFeedRecord(record, feed.Mapping)
+		if err != nil {
+			s.skip(imp, imp.Rows, err.Error())
+			return nil
+		}
+		if seen[item.sku] {
+			s.skip(imp, imp.Rows, fmt.Sprintf("duplicate sku %q", item.sku))
+			return nil
+		}
+		seen[item.sku] = true
+		item.row = imp.Rows
+
+		batch = append(batch, item)
+		if len(batch) < feedBatchSize {
+			return nil
+		}
+		err = s.saveBatch(ctx, feed, batch, imp)
+		batch = batch[:0]
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(batch) > 0 {
+		return s.saveBatch(ctx, feed, batch, imp)
+	}
+	return nil
+}
+
+// saveBatch сопоставляет пачку записей с товарами поставщика и сохраняет изменения одной транзакцией
+func (s *FeedService) saveBatch(ctx context.Context, feed *entity.SupplierFeed, batch []feedItem, imp *entity.FeedImport) error {
+	skus := make([]string, len(batch))
+	for i, item := range batch {
+		skus[i] = item.sku
+	}
+
+	existing, err := s.productRepo.ListBySupplierSKUs(ctx, feed.SupplierID, skus)
+	if err != nil {
+		return fmt.Errorf("failed to get supplier products: %w", err)
+	}
+	bySKU := make(map[string]*entity.Product, len(existing))
+	for i := range existing {
+		if existing[i].SupplierSKU != nil {
+			bySKU[*existing[i].SupplierSKU] = &existing[i]
+		}
+	}
+
+	var created, updated []*entity.Product
+	var changes []entity.AuditChanges
+	var before []map[string]interface{}
+	var oldPrices []money.Amount
+
+	for _, item := range batch {
+		product, ok := bySKU[item.sku]
+		if !ok {
+			created = append(created, newFeedProduct(feed, item))
+			continue
+		}
+		if product.DeletedAt != nil {
+			s.skip(imp, item.row, fmt.Sprintf("product with sku %q is deleted", item.sku))
+			continue
+		}
+
+		fields := productAuditFields(product)
+		oldPrice := product.Price
+		applyFeedItem(product, item)
+		diff := diffFields(fields, productAuditFields(product))
+		if len(diff) == 0 {
+			imp.Unchanged++
+			continue
+		}
+
+		updated = append(updated, product)
+		changes = append(changes, diff)
+		before = append(before, fields)
+		oldPrices = append(oldPrices, oldPrice)
+	}
+
+	if len(created) == 0 && len(updated) == 0 {
+		return nil
+	}
+	if err := s.productRepo.SaveBatch(ctx, created, updated); err != nil {
+		return fmt.Errorf("failed to save products: %w", err)
+	}
+	imp.Created += len(created)
+	imp.Updated += len(updated)
+
+	for _, product := range created {
+		s.audit.record(ctx, entity.SlugEntityProduct, product.ID, entity.AuditActionCreate, nil, productAuditFields(product))
+		publishCatalogEvent(ctx, s.kafkaProducer, product.ID.String(), entity.EventTypeProductCreated,
+			newProductEvent(entity.EventTypeProductCreated, product, nil))
+	}
+	for i, product := range updated {
+		s.invalidateProduct(ctx, product.ID)
+		s.audit.record(ctx, entity.SlugEntityProduct, product.ID, entity.AuditActionUpdate, before[i], productAuditFields(product))
+
+		event := newProductEvent(entity.EventTypeProductUpdated, product, changes[i])
+		if product.Price != oldPrices[i] {
+			event.OldPrice = &oldPrices[i]
+		}
+		publishCatalogEvent(ctx, s.kafkaProducer, product.ID.String(), event.EventType, event)
+	}
+
+	return nil
+}
+
+// skip учитывает пропущенную запись; сохраняются только первые maxFeedImportErrors ошибок
+func (s *FeedService) skip(imp *entity.FeedImport, row int, reason string) {
+	imp.Skipped++
+	if len(imp.Errors) < maxFeedImportErrors {
+		imp.Errors = append(imp.Errors, fmt.Sprintf("row %d: %s", row, reason))
+	}
+}
+
+// invalidateProduct удаляет карточку обновленного товара из кэша
+func (s *FeedService) invalidateProduct(ctx context.Context, id uuid.UUID) {
+	if s.productCache == nil {
+		return
+	}
+	if err := s.productCache.DeleteProduct(ctx, id); err != nil {
+		fmt.Printf("failed to invalidate product cache: %v\n", err)
+	}
+}
+
+// applyFeedRequest проверяет поставщика и категорию и переносит настройки запроса в фид
+func (s *FeedService) applyFeedRequest(ctx context.Context, feed *entity.SupplierFeed, req *entity.SupplierFeedRequest) error {
+	if _, err := s.supplierRepo.GetByID(ctx, req.SupplierID); err != nil {
+		if errors.Is(err, repository.ErrSupplierNotFound) {
+			return ErrSupplierNotFound
+		}
+		return fmt.Errorf("failed to verify supplier: %w", err)
+	}
+	if _, err := s.categoryRepo.GetByID(ctx, req.CategoryID); err != nil {
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			return ErrCategoryNotFound
+		}
+		return fmt.Errorf("failed to verify category: %w", err)
+	}
+
+	feed.SupplierID = req.SupplierID
+	feed.URL = req.URL
+	feed.Format = req.Format
+	feed.Mapping = req.Mapping
+	feed.CategoryID = req.CategoryID
+	feed.IntervalMinutes = req.IntervalMinutes
+	if feed.IntervalMinutes == 0 {
+		feed.IntervalMinutes = defaultFeedInterval
+	}
+	feed.Enabled = req.Enabled == nil || *req.Enabled
+	return nil
+}
+
+// mapFeedRecord приводит запись фида к полям товара по правилам сопоставления
+func mapFeedRecord(record util.FeedRecord, mapping entity.FeedMapping) (feedItem, error) {
+	item := feedItem{
+		sku:  record[mapping.SKU],
+		name: record[mapping.Name],
+	}
+	if item.sku == "" {
+		return item, fmt.Errorf("missing sku (%s)", mapping.SKU)
+	}
+	if len(item.sku) > 100 {
+		return item, fmt.Errorf("sku %q is longer than 100 characters", item.sku)
+	}
+	if item.name == "" {
+		return item, fmt.Errorf("sku %q: missing name (%s)", item.sku, mapping.Name)
+	}
+	if len([]rune(item.name)) > 255 {
+		return item, fmt.Errorf("sku %q: name is longer than 255 characters", item.sku)
+	}
+
+	price, err := parseFeedAmount(record[mapping.Price])
+	if err != nil || price <= 0 {
+		return item, fmt.Errorf("sku %q: invalid price %q", item.sku, record[mapping.Price])
+	}
+	item.price = price
+
+	if mapping.Description != "" {
+		description := record[mapping.Description]
+		item.description = &description
+	}
+	if value := record[mapping.CostPrice]; mapping.CostPrice != "" && value != "" {
+		costPrice, err := parseFeedAmount(value)
+		if err != nil || costPrice < 0 {
+			return item, fmt.Errorf("sku %q: invalid cost price %q", item.sku, value)
+		}
+		item.costPrice = &costPrice
+	}
+	if value := record[mapping.Stock]; mapping.Stock != "" && value != "" {
+		stock, err := strconv.Atoi(value)
+		if err != nil || stock < 0 {
+			return item, fmt.Errorf("sku %q: invalid stock %q", item.sku, value)
+		}
+		item.stock = &stock
+	}
+
+	return item, nil
+}
+
+// parseFeedAmount разбирает сумму фида; поставщики часто пишут десятичную запятую
+func parseFeedAmount(value string) (money.Amount, error) {
+	if !strings.Contains(value, ".") {
+		value = strings.Replace(value, ",", ".", 1)
+	}
+	return money.Parse(value)
+}
+
+// newFeedProduct создает черновик товара поставщика из записи фида
+func newFeedProduct(feed *entity.SupplierFeed, item feedItem) *entity.Product {
+	supplierID := feed.SupplierID
+	sku := item.sku
+	product := &entity.Product{
+		ID:          id.New(),
+		CategoryID:  feed.CategoryID,
+		SupplierID:  &supplierID,
+		SupplierSKU: &sku,
+		Status:      entity.ProductStatusDraft, // Товар фида публикует администратор
+		CreatedAt:   time.Now(),
+	}
+	applyFeedItem(product, item)
+	return product
+}
+
+// applyFeedItem переносит в товар поля фида; несопоставленные поля не меняются
+func applyFeedItem(product *entity.Product, item feedItem) {
+	product.Name = item.name
+	product.Price = item.price
+	if item.description != nil {
+		product.Description = *item.description
+	}
+	if item.costPrice != nil {
+		product.CostPrice = item.costPrice
+	}
+	if item.stock != nil {
+		product.Stock = item.stock
+	}
+}
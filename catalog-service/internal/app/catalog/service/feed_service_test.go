@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/repository"
+	"augustberries/catalog-service/internal/app/catalog/repository/mocks"
+	"augustberries/pkg/money"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type feedServiceMocks struct {
+	feedRepo      *mocks.MockFeedRepository
+	supplierRepo  *mocks.MockSupplierRepository
+	categoryRepo  *mocks.MockCategoryRepository
+	productRepo   *mocks.MockProductRepository
+	fetcher       *mocks.MockFeedFetcher
+	kafkaProducer *mocks.MockMessagePublisher
+}
+
+func newTestFeedService(now time.Time) (*FeedService, *feedServiceMocks) {
+	m := &feedServiceMocks{
+		feedRepo:      new(mocks.MockFeedRepository),
+		supplierRepo:  new(mocks.MockSupplierRepository),
+		categoryRepo:  new(mocks.MockCategoryRepository),
+		productRepo:   new(mocks.MockProductRepository),
+		fetcher:       new(mocks.MockFeedFetcher),
+		kafkaProducer: new(mocks.MockMessagePublisher),
+	}
+	s := NewFeedService(m.feedRepo, m.supplierRepo, m.categoryRepo, m.productRepo, m.fetcher, m.kafkaProducer, nil, nil)
+	s.now = func() time.Time { return now }
+	return s, m
+}
+
+func newTestFeed() *entity.SupplierFeed {
+	return &entity.SupplierFeed{
+		ID:         uuid.New(),
+		TenantID:   "shop-1",
+		SupplierID: uuid.New(),
+		URL:        "https://supplier.example/feed.csv",
+		Format:     entity.FeedFormatCSV,
+		Mapping:    entity.FeedMapping{SKU: "sku", Name: "name", Price: "price", Stock: "qty"},
+		CategoryID: uuid.New(),
+		Enabled:    true,
+	}
+}
+
+func feedBody(data string) io.ReadCloser {
+	return io.NopCloser(strings.NewReader(data))
+}
+
+// ==================== Import Tests ====================
+
+func TestFeedService_Import_UpsertsBySupplierSKU(t *testing.T) {
+	// Arrange
+	ctx := tenant.WithID(context.Background(), "shop-1")
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	service, m := newTestFeedService(now)
+	feed := newTestFeed()
+
+	stock := 3
+	changedSKU, sameSKU := "B-2", "C-3"
+	changed := entity.Product{ID: uuid.New(), Name: "Кофе", Price: money.MustParse("10.00"), SupplierID: &feed.SupplierID, SupplierSKU: &changedSKU}
+	same := entity.Product{ID: uuid.New(), Name: "Какао", Price: money.MustParse("5.00"), Stock: &stock, SupplierID: &feed.SupplierID, SupplierSKU: &sameSKU}
+
+	data := "sku,name,price,qty\n" +
+		"A-1,Чай,\"12,50\",7\n" +
+		"B-2,Кофе,11.00,\n" +
+		"C-3,Какао,5.00,3\n" +
+		"D-4,Сахар,free,1\n" +
+		"A-1,Чай,12.50,7\n"
+
+	m.feedRepo.On("MarkRun", ctx, feed.ID, now).Return(nil)
+	m.fetcher.On("Fetch", ctx, feed.URL).Return(feedBody(data), nil)
+	m.productRepo.On("ListBySupplierSKUs", ctx, feed.SupplierID, []string{"A-1", "B-2", "C-3"}).
+		Return([]entity.Product{changed, same}, nil)
+
+	var created, updated []*entity.Product
+	m.productRepo.On("SaveBatch", ctx, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			created = args.Get(1).([]*entity.Product)
+			updated = args.Get(2).([]*entity.Product)
+		}).
+		Return(nil)
+
+	events := map[string]entity.ProductEvent{}
+	m.kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			var event entity.ProductEvent
+			require.NoError(t, json.Unmarshal(args.Get(2).([]byte), &event))
+			events[event.EventType] = event
+		}).
+		Return(nil)
+
+	var saved *entity.FeedImport
+	m.feedRepo.On("SaveImport", ctx, mock.AnythingOfType("*entity.FeedImport")).
+		Run(func(args mock.Arguments) { saved = args.Get(1).(*entity.FeedImport) }).
+		Return(nil)
+
+	// Act
+	imp, err := service.Import(ctx, feed)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Same(t, saved, imp)
+	assert.Equal(t, entity.FeedImportSucceeded, imp.Status)
+	assert.Equal(t, 5, imp.Rows)
+	assert.Equal(t, 1, imp.Created)
+	assert.Equal(t, 1, imp.Updated)
+	assert.Equal(t, 1, imp.Unchanged)
+	assert.Equal(t, 2, imp.Skipped)
+	assert.Equal(t, []string{`row 4: sku "D-4": invalid price "free"`, `row 5: duplicate sku "A-1"`}, imp.Errors)
+
+	require.Len(t, created, 1)
+	assert.Equal(t, "A-1", *created[0].SupplierSKU)
+	assert.Equal(t, money.MustParse("12.50"), created[0].Price)
+	assert.Equal(t, 7, *created[0].Stock)
+	assert.Equal(t, feed.CategoryID, created[0].CategoryID)
+	assert.Equal(t, entity.ProductStatusDraft, created[0].Status)
+
+	require.Len(t, updated, 1)
+	assert.Equal(t, changed.ID, updated[0].ID)
+	assert.Equal(t, money.MustParse("11.00"), updated[0].Price)
+
+	require.Contains(t, events, entity.EventTypeProductCreated)
+	require.Contains(t, events, entity.EventTypeProductUpdated)
+	require.NotNil(t, events[entity.EventTypeProductUpdated].OldPrice)
+	assert.Equal(t, money.MustParse("10.00"), *events[entity.EventTypeProductUpdated].OldPrice)
+}
+
+func TestFeedService_Import_FetchFailureRecorded(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	service, m := newTestFeedService(now)
+	feed := newTestFeed()
+
+	m.feedRepo.On("MarkRun", ctx, feed.ID, now).Return(nil)
+	m.fetcher.On("Fetch", ctx, feed.URL).Return(nil, errors.New("unexpected status 404"))
+	m.feedRepo.On("SaveImport", ctx, mock.AnythingOfType("*entity.FeedImport")).Return(nil)
+
+	// Act
+	imp, err := service.Import(ctx, feed)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, entity.FeedImportFailed, imp.Status)
+	assert.Contains(t, imp.Error, "404")
+	m.productRepo.AssertNotCalled(t, "SaveBatch", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFeedService_Import_SkipsDeletedProducts(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	service, m := newTestFeedService(now)
+	feed := newTestFeed()
+
+	sku := "A-1"
+	deleted := entity.Product{ID: uuid.New(), Name: "Чай", Price: money.MustParse("1.00"), SupplierSKU: &sku, DeletedAt: &now}
+
+	m.feedRepo.On("MarkRun", ctx, feed.ID, now).Return(nil)
+	m.fetcher.On("Fetch", ctx, feed.URL).Return(feedBody("sku,name,price\nA-1,Чай,2.00\n"), nil)
+	m.productRepo.On("ListBySupplierSKUs", ctx, feed.SupplierID, []string{"A-1"}).Return([]entity.Product{deleted}, nil)
+	m.feedRepo.On("SaveImport", ctx, mock.AnythingOfType("*entity.FeedImport")).Return(nil)
+
+	// Act
+	imp, err := service.Import(ctx, feed)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, imp.Skipped)
+	assert.Equal(t, 0, imp.Updated)
+	m.productRepo.AssertNotCalled(t, "SaveBatch", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// ==================== RunDue Tests ====================
+
+func TestFeedService_RunDue_ImportsInFeedTenant(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	service, m := newTestFeedService(now)
+	feed := newTestFeed()
+
+	inTenant := mock.MatchedBy(func(c context.Context) bool { return tenant.FromContext(c) == "shop-1" })
+
+	m.feedRepo.On("ListDue", ctx, now, dueFeedsBatch).Return([]entity.SupplierFeed{*feed}, nil)
+	m.feedRepo.On("MarkRun", inTenant, feed.ID, now).Return(nil)
+	m.fetcher.On("Fetch", inTenant, feed.URL).Return(feedBody("sku,name,price\n"), nil)
+	m.feedRepo.On("SaveImport", inTenant, mock.AnythingOfType("*entity.FeedImport")).Return(nil)
+
+	// Act
+	err := service.RunDue(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	m.feedRepo.AssertExpectations(t)
+	m.fetcher.AssertExpectations(t)
+}
+
+// ==================== Feed Settings Tests ====================
+
+func TestFeedService_CreateFeed_SupplierNotFound(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	service, m := newTestFeedService(time.Now())
+	req := &entity.SupplierFeedRequest{SupplierID: uuid.New(), CategoryID: uuid.New(), URL: "https://supplier.example/feed.xml", Format: entity.FeedFormatXML}
+
+	m.supplierRepo.On("GetByID", ctx, req.SupplierID).Return(nil, repository.ErrSupplierNotFound)
+
+	// Act
+	feed, err := service.CreateFeed(ctx, req)
+
+	// Assert
+	assert.Nil(t, feed)
+	assert.ErrorIs(t, err, ErrSupplierNotFound)
+	m.feedRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestFeedService_CreateFeed_Defaults(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	service, m := newTestFeedService(time.Now())
+	req := &entity.SupplierFeedRequest{SupplierID: uuid.New(), CategoryID: uuid.New(), URL: "https://supplier.example/feed.xml", Format: entity.FeedFormatXML}
+
+	m.supplierRepo.On("GetByID", ctx, req.SupplierID).Return(&entity.Supplier{ID: req.SupplierID}, nil)
+	m.categoryRepo.On("GetByID", ctx, req.CategoryID).Return(&entity.Category{ID: req.CategoryID}, nil)
+	m.feedRepo.On("Create", ctx, mock.AnythingOfType("*entity.SupplierFeed")).Return(nil)
+
+	// Act
+	feed, err := service.CreateFeed(ctx, req)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, defaultFeedInterval, feed.IntervalMinutes)
+	assert.True(t, feed.Enabled)
+}
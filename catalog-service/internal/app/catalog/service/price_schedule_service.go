@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/repository"
+	"augustberries/catalog-service/internal/app/catalog/util"
+	"augustberries/pkg/money"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrScheduledPriceNotFound = errors.New("scheduled price not found")
+	ErrInvalidPriceWindow     = errors.New("effective_to must be after effective_from and in the future")
+	ErrPriceWindowOverlap     = errors.New("scheduled price overlaps another scheduled price")
+	ErrScheduledPriceStarted  = errors.New("scheduled price already started")
+)
+
+// priceSchedulerActor - от имени этого пользователя фоновая задача пишет журнал изменений
+const priceSchedulerActor = "price-scheduler"
+
+// duePricesBatch - сколько запланированных цен обрабатывается за один запуск задачи
+const duePricesBatch = 100
+
+// PriceScheduleService планирует цены товаров и применяет их по расписанию
+type PriceScheduleService struct {
+	scheduleRepo  repository.ScheduledPriceRepository
+	productRepo   repository.ProductRepository
+	kafkaProducer util.MessagePublisher
+	audit         *AuditLog // nil - журнал изменений отключен
+	now           func() time.Time
+}
+
+func NewPriceScheduleService(
+	scheduleRepo repository.ScheduledPriceRepository,
+	productRepo repository.ProductRepository,
+	kafkaProducer util.MessagePublisher,
+	audit *AuditLog,
+) *PriceScheduleService {
+	return &PriceScheduleService{
+		scheduleRepo:  scheduleRepo,
+		productRepo:   productRepo,
+		kafkaProducer: kafkaProducer,
+		audit:         audit,
+		now:           time.Now,
+	}
+}
+
+// Schedule планирует цену товара
+// Окна ожидающих и активных цен одного товара не должны пересекаться
+func (s *PriceScheduleService) Schedule(ctx context.Context, productID uuid.UUID, req *entity.CreateScheduledPriceRequest) (*entity.ScheduledPrice, error) {
+	if req.EffectiveTo != nil && (!req.EffectiveTo.After(req.EffectiveFrom) || !req.EffectiveTo.After(s.now())) {
+		return nil, ErrInvalidPriceWindow
+	}
+
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		if errors.Is(err, repository.ErrProductNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	existing, err := s.scheduleRepo.ListByProduct(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled prices: %w", err)
+	}
+
+	sp := &entity.ScheduledPrice{
+		ID:            uuid.New(),
+		ProductID:     productID,
+		Price:         req.Price,
+		EffectiveFrom: req.EffectiveFrom,
+		EffectiveTo:   req.EffectiveTo,
+		Status:        entity.ScheduledPricePending,
+		CreatedAt:     s.now(),
+	}
+
+	for i := range existing {
+		if windowsOverlap(&existing[i], sp) {
+			return nil, ErrPriceWindowOverlap
+		}
+	}
+
+	if err := s.scheduleRepo.Create(ctx, sp); err != nil {
+		return nil, fmt.Errorf("failed to create scheduled price: %w", err)
+	}
+
+	return sp, nil
+}
+
+// List возвращает все запланированные цены товара, включая завершенные
+func (s *PriceScheduleService) List(ctx context.Context, productID uuid.UUID) ([]entity.ScheduledPrice, error) {
+	prices, err := s.scheduleRepo.ListByProduct(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled prices: %w", err)
+	}
+	return prices, nil
+}
+
+// Cancel отменяет цену, которая еще не вступила в силу
+func (s *PriceScheduleService) Cancel(ctx context.Context, productID, id uuid.UUID) error {
+	sp, err := s.scheduleRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrScheduledPriceNotFound) {
+			return ErrScheduledPriceNotFound
+		}
+		return fmt.Errorf("failed to get scheduled price: %w", err)
+	}
+	if sp.ProductID != productID {
+		return ErrScheduledPriceNotFound
+	}
+
+	if err := s.scheduleRepo.Cancel(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrScheduledPriceChanged) {
+			return ErrScheduledPriceStarted
+		}
+		return fmt.Errorf("failed to cancel scheduled price: %w", err)
+	}
+
+	return nil
+}
+
+// ApplyDue применяет наступившие цены и возвращает прежние по окончании распродаж
+// Вызывается фоновой задачей; ошибка одной цены не останавливает остальные
+func (s *PriceScheduleService) ApplyDue(ctx context.Context) error {
+	due, err := s.scheduleRepo.ListDue(ctx, s.now(), duePricesBatch)
+	if err != nil {
+		return fmt.Errorf("failed to get due scheduled prices: %w", err)
+	}
+
+	for i := range due {
+		sp := &due[i]
+		// Задача обслуживает все магазины: запросы выполняются в магазине цены
+		spCtx := util.WithActor(tenant.WithID(ctx, sp.TenantID), util.Actor{UserID: priceSchedulerActor})
+
+		var applyErr error
+		switch sp.Status {
+		case entity.ScheduledPricePending:
+			applyErr = s.activate(spCtx, sp)
+		case entity.ScheduledPriceActive:
+			applyErr = s.complete(spCtx, sp)
+		}
+		if applyErr != nil && !errors.Is(applyErr, repository.ErrScheduledPriceChanged) {
+			fmt.Printf("failed to apply scheduled price %s: %v\n", sp.ID, applyErr)
+		}
+	}
+
+	return nil
+}
+
+// activate устанавливает запланированную цену товару
+func (s *PriceScheduleService) activate(ctx context.Context, sp *entity.ScheduledPrice) error {
+	oldPrice, err := s.scheduleRepo.Activate(ctx, sp)
+	if err != nil {
+		return err
+	}
+
+	s.priceChanged(ctx, sp.ProductID, oldPrice)
+	return nil
+}
+
+// complete возвращает товару цену, действовавшую до распродажи
+func (s *PriceScheduleService) complete(ctx context.Context, sp *entity.ScheduledPrice) error {
+	restored, err := s.scheduleRepo.Complete(ctx, sp)
+	if err != nil {
+		return err
+	}
+
+	if restored {
+		s.priceChanged(ctx, sp.ProductID, sp.Price)
+	}
+	return nil
+}
+
+// priceChanged записывает изменение цены в журнал и отправляет PRICE_CHANGED в Kafka
+func (s *PriceScheduleService) priceChanged(ctx context.Context, productID uuid.UUID, oldPrice money.Amount) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		fmt.Printf("failed to get product %s after price change: %v\n", productID, err)
+		return
+	}
+
+	s.audit.record(ctx, entity.SlugEntityProduct, productID, entity.AuditActionUpdate,
+		map[string]interface{}{"price": oldPrice},
+		map[string]interface{}{"price": product.Price})
+
+	event := newProductEvent(entity.EventTypePriceChanged, product, nil)
+	event.OldPrice = &oldPrice
+	event.Timestamp = s.now()
+	publishCatalogEvent(ctx, s.kafkaProducer, event.ProductID.String(), event.EventType, event)
+}
+
+// windowsOverlap проверяет пересечение новой цены с ожидающей или активной
+// Окна полуоткрытые [from, to); цена без окончания занимает только момент from
+func windowsOverlap(existing, candidate *entity.ScheduledPrice) bool {
+	if existing.Status != entity.ScheduledPricePending && existing.Status != entity.ScheduledPriceActive {
+		return false
+	}
+
+	aFrom, aTo := priceWindow(existing)
+	bFrom, bTo := priceWindow(candidate)
+	return aFrom.Before(bTo) && bFrom.Before(aTo)
+}
+
+// priceWindow возвращает окно действия цены
+func priceWindow(sp *entity.ScheduledPrice) (time.Time, time.Time) {
+	if sp.EffectiveTo == nil {
+		return sp.EffectiveFrom, sp.EffectiveFrom.Add(time.Nanosecond)
+	}
+	return sp.EffectiveFrom, *sp.EffectiveTo
+}
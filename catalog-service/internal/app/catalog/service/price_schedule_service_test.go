@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/repository"
+	"augustberries/catalog-service/internal/app/catalog/repository/mocks"
+	"augustberries/pkg/money"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPriceScheduleService(scheduleRepo *mocks.MockScheduledPriceRepository, productRepo *mocks.MockProductRepository, kafkaProducer *mocks.MockMessagePublisher, now time.Time) *PriceScheduleService {
+	s := NewPriceScheduleService(scheduleRepo, productRepo, kafkaProducer, nil)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+// ==================== Schedule Tests ====================
+
+func TestPriceScheduleService_Schedule_Success(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	scheduleRepo := new(mocks.MockScheduledPriceRepository)
+	productRepo := new(mocks.MockProductRepository)
+
+	product := newTestProduct(uuid.New())
+	to := now.Add(48 * time.Hour)
+	req := &entity.CreateScheduledPriceRequest{
+		Price:         money.MustParse("999.99"),
+		EffectiveFrom: now.Add(24 * time.Hour),
+		EffectiveTo:   &to,
+	}
+
+	productRepo.On("GetByID", ctx, product.ID).Return(product, nil)
+	scheduleRepo.On("ListByProduct", ctx, product.ID).Return([]entity.ScheduledPrice{}, nil)
+	scheduleRepo.On("Create", ctx, mock.AnythingOfType("*entity.ScheduledPrice")).Return(nil)
+
+	service := newTestPriceScheduleService(scheduleRepo, productRepo, new(mocks.MockMessagePublisher), now)
+
+	// Act
+	sp, err := service.Schedule(ctx, product.ID, req)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, product.ID, sp.ProductID)
+	assert.Equal(t, req.Price, sp.Price)
+	assert.Equal(t, entity.ScheduledPricePending, sp.Status)
+	scheduleRepo.AssertExpectations(t)
+}
+
+func TestPriceScheduleService_Schedule_InvalidWindow(t *testing.T) {
+	// Arrange
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	from := now.Add(24 * time.Hour)
+	before := from.Add(-time.Hour)
+
+	service := newTestPriceScheduleService(new(mocks.MockScheduledPriceRepository), new(mocks.MockProductRepository), new(mocks.MockMessagePublisher), now)
+
+	// Act
+	_, err := service.Schedule(context.Background(), uuid.New(), &entity.CreateScheduledPriceRequest{
+		Price:         money.MustParse("10.00"),
+		EffectiveFrom: from,
+		EffectiveTo:   &before,
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidPriceWindow)
+}
+
+func TestPriceScheduleService_Schedule_Overlap(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	scheduleRepo := new(mocks.MockScheduledPriceRepository)
+	productRepo := new(mocks.MockProductRepository)
+
+	product := newTestProduct(uuid.New())
+	saleTo := now.Add(72 * time.Hour)
+	existing := []entity.ScheduledPrice{{
+		ID:            uuid.New(),
+		ProductID:     product.ID,
+		Price:         money.MustParse("999.99"),
+		EffectiveFrom: now.Add(24 * time.Hour),
+		EffectiveTo:   &saleTo,
+		Status:        entity.ScheduledPricePending,
+	}}
+
+	productRepo.On("GetByID", ctx, product.ID).Return(product, nil)
+	scheduleRepo.On("ListByProduct", ctx, product.ID).Return(existing, nil)
+
+	service := newTestPriceScheduleService(scheduleRepo, productRepo, new(mocks.MockMessagePublisher), now)
+
+	// Act: постоянная цена внутри окна распродажи
+	_, err := service.Schedule(ctx, product.ID, &entity.CreateScheduledPriceRequest{
+		Price:         money.MustParse("1099.99"),
+		EffectiveFrom: now.Add(48 * time.Hour),
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrPriceWindowOverlap)
+	scheduleRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestPriceScheduleService_Schedule_AdjacentWindowsAllowed(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	scheduleRepo := new(mocks.MockScheduledPriceRepository)
+	productRepo := new(mocks.MockProductRepository)
+
+	product := newTestProduct(uuid.New())
+	saleTo := now.Add(72 * time.Hour)
+	existing := []entity.ScheduledPrice{
+		{
+			ID:            uuid.New(),
+			Price:         money.MustParse("999.99"),
+			EffectiveFrom: now.Add(24 * time.Hour),
+			EffectiveTo:   &saleTo,
+			Status:        entity.ScheduledPricePending,
+		},
+		{
+			// Отмененные цены не учитываются
+			ID:            uuid.New(),
+			Price:         money.MustParse("899.99"),
+			EffectiveFrom: saleTo,
+			Status:        entity.ScheduledPriceCancelled,
+		},
+	}
+
+	productRepo.On("GetByID", ctx, product.ID).Return(product, nil)
+	scheduleRepo.On("ListByProduct", ctx, product.ID).Return(existing, nil)
+	scheduleRepo.On("Create", ctx, mock.AnythingOfType("*entity.ScheduledPrice")).Return(nil)
+
+	service := newTestPriceScheduleService(scheduleRepo, productRepo, new(mocks.MockMessagePublisher), now)
+
+	// Act: новая цена начинает действовать в момент окончания распродажи
+	_, err := service.Schedule(ctx, product.ID, &entity.CreateScheduledPriceRequest{
+		Price:         money.MustParse("1199.99"),
+		EffectiveFrom: saleTo,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	scheduleRepo.AssertExpectations(t)
+}
+
+// ==================== Cancel Tests ====================
+
+func TestPriceScheduleService_Cancel_AlreadyStarted(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	scheduleRepo := new(mocks.MockScheduledPriceRepository)
+
+	sp := &entity.ScheduledPrice{ID: uuid.New(), ProductID: uuid.New(), Status: entity.ScheduledPriceActive}
+	scheduleRepo.On("GetByID", ctx, sp.ID).Return(sp, nil)
+	scheduleRepo.On("Cancel", ctx, sp.ID).Return(repository.ErrScheduledPriceChanged)
+
+	service := NewPriceScheduleService(scheduleRepo, new(mocks.MockProductRepository), new(mocks.MockMessagePublisher), nil)
+
+	// Act
+	err := service.Cancel(ctx, sp.ProductID, sp.ID)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrScheduledPriceStarted)
+}
+
+func TestPriceScheduleService_Cancel_OtherProduct(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	scheduleRepo := new(mocks.MockScheduledPriceRepository)
+
+	sp := &entity.ScheduledPrice{ID: uuid.New(), ProductID: uuid.New(), Status: entity.ScheduledPricePending}
+	scheduleRepo.On("GetByID", ctx, sp.ID).Return(sp, nil)
+
+	service := NewPriceScheduleService(scheduleRepo, new(mocks.MockProductRepository), new(mocks.MockMessagePublisher), nil)
+
+	// Act
+	err := service.Cancel(ctx, uuid.New(), sp.ID)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrScheduledPriceNotFound)
+	scheduleRepo.AssertNotCalled(t, "Cancel", mock.Anything, mock.Anything)
+}
+
+// ==================== ApplyDue Tests ====================
+
+func TestPriceScheduleService_ApplyDue_ActivatesAndPublishesPriceChanged(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	scheduleRepo := new(mocks.MockScheduledPriceRepository)
+	productRepo := new(mocks.MockProductRepository)
+	kafkaProducer := new(mocks.MockMessagePublisher)
+
+	product := newTestProduct(uuid.New())
+	product.TenantID = "shop-1"
+	oldPrice := product.Price
+	product.Price = money.MustParse("999.99")
+
+	sp := entity.ScheduledPrice{
+		ID:            uuid.New(),
+		TenantID:      "shop-1",
+		ProductID:     product.ID,
+		Price:         product.Price,
+		EffectiveFrom: now.Add(-time.Minute),
+		Status:        entity.ScheduledPricePending,
+	}
+
+	// Запросы выполняются в магазине запланированной цены
+	inTenant := mock.MatchedBy(func(c context.Context) bool { return tenant.FromContext(c) == "shop-1" })
+
+	scheduleRepo.On("ListDue", ctx, now, duePricesBatch).Return([]entity.ScheduledPrice{sp}, nil)
+	scheduleRepo.On("Activate", inTenant, mock.AnythingOfType("*entity.ScheduledPrice")).Return(oldPrice, nil)
+	productRepo.On("GetByID", inTenant, product.ID).Return(product, nil)
+
+	var published entity.ProductEvent
+	kafkaProducer.On("PublishMessage", inTenant, product.ID.String(), mock.Anything).
+		Run(func(args mock.Arguments) {
+			require.NoError(t, json.Unmarshal(args.Get(2).([]byte), &published))
+		}).
+		Return(nil)
+
+	service := newTestPriceScheduleService(scheduleRepo, productRepo, kafkaProducer, now)
+
+	// Act
+	err := service.ApplyDue(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "PRICE_CHANGED", published.EventType)
+	assert.Equal(t, product.Price, published.Price)
+	require.NotNil(t, published.OldPrice)
+	assert.Equal(t, oldPrice, *published.OldPrice)
+	kafkaProducer.AssertExpectations(t)
+}
+
+func TestPriceScheduleService_ApplyDue_NotRestoredAfterManualChange(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	scheduleRepo := new(mocks.MockScheduledPriceRepository)
+	kafkaProducer := new(mocks.MockMessagePublisher)
+
+	to := now.Add(-time.Minute)
+	sp := entity.ScheduledPrice{
+		ID:            uuid.New(),
+		ProductID:     uuid.New(),
+		Price:         money.MustParse("999.99"),
+		EffectiveFrom: now.Add(-time.Hour),
+		EffectiveTo:   &to,
+		Status:        entity.ScheduledPriceActive,
+	}
+
+	scheduleRepo.On("ListDue", ctx, now, duePricesBatch).Return([]entity.ScheduledPrice{sp}, nil)
+	scheduleRepo.On("Complete", mock.Anything, mock.AnythingOfType("*entity.ScheduledPrice")).Return(false, nil)
+
+	service := newTestPriceScheduleService(scheduleRepo, new(mocks.MockProductRepository), kafkaProducer, now)
+
+	// Act
+	err := service.ApplyDue(ctx)
+
+	// Assert: цену изменили вручную во время распродажи - событие не отправляется
+	require.NoError(t, err)
+	scheduleRepo.AssertExpectations(t)
+	kafkaProducer.AssertNotCalled(t, "PublishMessage", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"augustberries/pkg/recovery"
+
+	"github.com/robfig/cron/v3"
+)
+
+// PriceScheduler периодически применяет запланированные цены товаров
+type PriceScheduler struct {
+	cron    *cron.Cron
+	service *PriceScheduleService
+}
+
+// NewPriceScheduler создает планировщик; запуски не накладываются друг на друга
+func NewPriceScheduler(service *PriceScheduleService) *PriceScheduler {
+	c := cron.New(
+		cron.WithLogger(cron.VerbosePrintfLogger(log.Default())),
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)),
+	)
+
+	return &PriceScheduler{
+		cron:    c,
+		service: service,
+	}
+}
+
+// Start запускает планировщик и сразу применяет наступившие цены
+func (s *PriceScheduler) Start(ctx context.Context, schedule string) error {
+	log.Printf("Starting price scheduler with schedule: %s", schedule)
+
+	if _, err := s.cron.AddFunc(schedule, recovery.Wrap("catalog-service", "price_scheduler", func() { s.run(ctx) })); err != nil {
+		return err
+	}
+
+	s.cron.Start()
+	s.run(ctx)
+
+	return nil
+}
+
+// Stop останавливает планировщик и ждет завершения текущего запуска
+func (s *PriceScheduler) Stop() {
+	log.Println("Stopping price scheduler...")
+	<-s.cron.Stop().Done()
+	log.Println("Price scheduler stopped")
+}
+
+func (s *PriceScheduler) run(ctx context.Context) {
+	if err := s.service.ApplyDue(ctx); err != nil {
+		log.Printf("ERROR: Failed to apply scheduled prices: %v", err)
+	}
+}
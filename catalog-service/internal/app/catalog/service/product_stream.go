@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/repository"
+	"augustberries/catalog-service/internal/app/catalog/util"
+)
+
+// streamBatchSize - число товаров, читаемых из PostgreSQL за один запрос выгрузки
+const streamBatchSize = 500
+
+// streamRateKey - ключ счетчика запросов выгрузки в Redis
+const streamRateKey = "products-stream"
+
+// Ошибки выгрузки каталога
+var (
+	ErrInvalidStreamCursor = errors.New("invalid stream cursor")
+	ErrStreamRateLimited   = errors.New("too many stream requests, try again later")
+)
+
+// ProductStreamConfig - ограничение частоты выгрузок каталога одного магазина
+type ProductStreamConfig struct {
+	RateLimit  int           // Выгрузок магазина за RateWindow, 0 - без ограничения
+	RateWindow time.Duration // Окно ограничения
+}
+
+// ProductStreamService выгружает изменения каталога для хранилища аналитики
+// Получатель запоминает курсор из последней строки и передает его в следующую выгрузку
+type ProductStreamService struct {
+	productRepo repository.ProductRepository
+	counter     util.RateCounter // nil - частота выгрузок не ограничивается
+	cfg         ProductStreamConfig
+}
+
+// NewProductStreamService создает сервис выгрузки каталога
+func NewProductStreamService(productRepo repository.ProductRepository, counter util.RateCounter, cfg ProductStreamConfig) *ProductStreamService {
+	return &ProductStreamService{
+		productRepo: productRepo,
+		counter:     counter,
+		cfg:         cfg,
+	}
+}
+
+// Allow учитывает выгрузку магазина из контекста и возвращает ErrStreamRateLimited, если лимит окна исчерпан
+// При недоступности Redis выгрузка разрешается, как и квоты запросов
+func (s *ProductStreamService) Allow(ctx context.Context) error {
+	if s.counter == nil || s.cfg.RateLimit <= 0 {
+		return nil
+	}
+
+	count, err := s.counter.Increment(ctx, streamRateKey, s.cfg.RateWindow)
+	if err != nil {
+		log.Printf("Stream rate check failed, request allowed: %v", err)
+		return nil
+	}
+	if count > int64(s.cfg.RateLimit) {
+		return ErrStreamRateLimited
+	}
+
+	return nil
+}
+
+// RetryAfter возвращает окно ограничения частоты выгрузок (для заголовка Retry-After)
+func (s *ProductStreamService) RetryAfter() time.Duration {
+	return s.cfg.RateWindow
+}
+
+// Stream читает до query.Limit измененных товаров пачками и передает их в emit по одному
+// Последним вызовом emit получает строку end с курсором продолжения; ошибка emit прерывает выгрузку
+func (s *ProductStreamService) Stream(ctx context.Context, query entity.ProductStreamQuery, emit func(entity.ProductStreamLine) error) error {
+	remaining := query.Limit
+	cursor := query.Cursor
+	hasMore := true
+
+	for remaining > 0 && hasMore {
+		batch := query
+		batch.Cursor = cursor
+		batch.Limit = min(remaining, streamBatchSize)
+
+		products, err := s.productRepo.ListChanged(ctx, batch)
+		if err != nil {
+			return err
+		}
+
+		for i := range products {
+			product := &products[i]
+			cursor = &entity.ProductStreamCursor{UpdatedAt: product.UpdatedAt, ID: product.ID}
+			line := entity.ProductStreamLine{
+				Type:    entity.ProductStreamLineProduct,
+				Cursor:  EncodeStreamCursor(*cursor),
+				Product: product,
+			}
+			if err := emit(line); err != nil {
+				return err
+			}
+		}
+
+		remaining -= len(products)
+		hasMore = len(products) == batch.Limit
+	}
+
+	// Курсор end совпадает с курсором последнего товара; пустая выгрузка возвращает исходный курсор
+	end := entity.ProductStreamLine{Type: entity.ProductStreamLineEnd, HasMore: &hasMore}
+	if cursor != nil {
+		end.Cursor = EncodeStreamCursor(*cursor)
+	}
+	return emit(end)
+}
+
+// EncodeStreamCursor кодирует позицию выгрузки в непрозрачный токен для query-параметра cursor
+func EncodeStreamCursor(cursor entity.ProductStreamCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeStreamCursor разбирает токен, выданный EncodeStreamCursor
+func DecodeStreamCursor(token string) (*entity.ProductStreamCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidStreamCursor
+	}
+
+	var cursor entity.ProductStreamCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.UpdatedAt.IsZero() {
+		return nil, ErrInvalidStreamCursor
+	}
+
+	return &cursor, nil
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/repository/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// ==================== Product Stream Tests ====================
+
+func TestProductStreamService_Stream_EmitsProductsAndEndCursor(t *testing.T) {
+	// Arrange
+	productRepo := new(mocks.MockProductRepository)
+	svc := NewProductStreamService(productRepo, nil, ProductStreamConfig{})
+
+	updated := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	first := entity.Product{ID: uuid.New(), UpdatedAt: updated}
+	second := entity.Product{ID: uuid.New(), UpdatedAt: updated.Add(time.Second)}
+	productRepo.On("ListChanged", mock.Anything, entity.ProductStreamQuery{Limit: 10}).
+		Return([]entity.Product{first, second}, nil)
+
+	// Act
+	var lines []entity.ProductStreamLine
+	err := svc.Stream(context.Background(), entity.ProductStreamQuery{Limit: 10}, func(line entity.ProductStreamLine) error {
+		lines = append(lines, line)
+		return nil
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, lines, 3)
+	assert.Equal(t, entity.ProductStreamLineProduct, lines[0].Type)
+	assert.Equal(t, first.ID, lines[0].Product.ID)
+	assert.Equal(t, entity.ProductStreamLineEnd, lines[2].Type)
+	assert.Equal(t, lines[1].Cursor, lines[2].Cursor)
+	require.NotNil(t, lines[2].HasMore)
+	assert.False(t, *lines[2].HasMore)
+
+	cursor, err := DecodeStreamCursor(lines[2].Cursor)
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, cursor.ID)
+	assert.True(t, second.UpdatedAt.Equal(cursor.UpdatedAt))
+}
+
+func TestProductStreamService_Stream_ReadsInBatchesUntilLimit(t *testing.T) {
+	// Arrange
+	productRepo := new(mocks.MockProductRepository)
+	svc := NewProductStreamService(productRepo, nil, ProductStreamConfig{})
+
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	page := make([]entity.Product, streamBatchSize)
+	for i := range page {
+		page[i] = entity.Product{ID: uuid.New(), UpdatedAt: since.Add(time.Duration(i) * time.Second)}
+	}
+	last := page[len(page)-1]
+	productRepo.On("ListChanged", mock.Anything, entity.ProductStreamQuery{UpdatedSince: &since, Limit: streamBatchSize}).
+		Return(page, nil)
+	productRepo.On("ListChanged", mock.Anything, entity.ProductStreamQuery{
+		UpdatedSince: &since,
+		Cursor:       &entity.ProductStreamCursor{UpdatedAt: last.UpdatedAt, ID: last.ID},
+		Limit:        100,
+	}).Return(page[:100], nil)
+
+	// Act
+	var end entity.ProductStreamLine
+	count := 0
+	err := svc.Stream(context.Background(), entity.ProductStreamQuery{UpdatedSince: &since, Limit: streamBatchSize + 100}, func(line entity.ProductStreamLine) error {
+		if line.Type == entity.ProductStreamLineEnd {
+			end = line
+		} else {
+			count++
+		}
+		return nil
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, streamBatchSize+100, count)
+	require.NotNil(t, end.HasMore)
+	assert.True(t, *end.HasMore)
+	productRepo.AssertNumberOfCalls(t, "ListChanged", 2)
+}
+
+func TestProductStreamService_Stream_EmptyKeepsCursor(t *testing.T) {
+	// Arrange
+	productRepo := new(mocks.MockProductRepository)
+	svc := NewProductStreamService(productRepo, nil, ProductStreamConfig{})
+
+	cursor := entity.ProductStreamCursor{UpdatedAt: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), ID: uuid.New()}
+	productRepo.On("ListChanged", mock.Anything, mock.Anything).Return([]entity.Product{}, nil)
+
+	// Act
+	var lines []entity.ProductStreamLine
+	err := svc.Stream(context.Background(), entity.ProductStreamQuery{Cursor: &cursor, Limit: 10}, func(line entity.ProductStreamLine) error {
+		lines = append(lines, line)
+		return nil
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+	assert.Equal(t, EncodeStreamCursor(cursor), lines[0].Cursor)
+}
+
+func TestDecodeStreamCursor_RejectsGarbage(t *testing.T) {
+	for _, token := range []string{"not-base64!", "bm90LWpzb24", "e30"} {
+		_, err := DecodeStreamCursor(token)
+		assert.ErrorIs(t, err, ErrInvalidStreamCursor, token)
+	}
+}
+
+func TestProductStreamService_Allow_RateLimited(t *testing.T) {
+	// Arrange
+	counter := new(mocks.MockRateCounter)
+	svc := NewProductStreamService(new(mocks.MockProductRepository), counter, ProductStreamConfig{RateLimit: 2, RateWindow: time.Minute})
+	counter.On("Increment", mock.Anything, streamRateKey, time.Minute).Return(int64(3), nil)
+
+	// Act
+	err := svc.Allow(context.Background())
+
+	// Assert
+	assert.ErrorIs(t, err, ErrStreamRateLimited)
+}
+
+func TestProductStreamService_Allow_CounterErrorAllows(t *testing.T) {
+	// Arrange
+	counter := new(mocks.MockRateCounter)
+	svc := NewProductStreamService(new(mocks.MockProductRepository), counter, ProductStreamConfig{RateLimit: 2, RateWindow: time.Minute})
+	counter.On("Increment", mock.Anything, streamRateKey, time.Minute).Return(int64(0), errors.New("redis: connection refused"))
+
+	// Act
+	err := svc.Allow(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+}
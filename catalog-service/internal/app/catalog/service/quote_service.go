@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/repository"
+	"augustberries/pkg/quote"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrProductNotAvailable - товар существует, но не опубликован
+	ErrProductNotAvailable = errors.New("product not available")
+	// ErrProductArchived - товар снят с продажи или удален
+	ErrProductArchived = errors.New("product archived")
+	// ErrDuplicateQuoteItem - товар указан в запросе несколько раз
+	ErrDuplicateQuoteItem = errors.New("duplicate product in quote request")
+	// ErrInvalidQuantity - количество не кратно шагу товара (1.3 кг при шаге 0.5)
+	ErrInvalidQuantity = errors.New("quantity is not a multiple of the product quantity step")
+	// ErrQuantityBelowMinimum - количество меньше минимального для товара (min_qty)
+	ErrQuantityBelowMinimum = errors.New("quantity is below the product minimum")
+	// ErrQuantityAboveMaximum - количество больше максимального для товара в одном заказе (max_qty)
+	ErrQuantityAboveMaximum = errors.New("quantity exceeds the product maximum")
+)
+
+// quoteCurrency - цены каталога хранятся в базовой валюте
+const quoteCurrency = "USD"
+
+// QuoteService выдает подписанные ценовые котировки
+// Котировка подтверждает существование товаров и их цены на момент выдачи,
+// Orders Service проверяет подпись и срок действия перед сохранением заказа
+type QuoteService struct {
+	productRepo repository.ProductRepository
+	signer      *quote.Signer
+	ttl         time.Duration
+}
+
+func NewQuoteService(productRepo repository.ProductRepository, signer *quote.Signer, ttl time.Duration) *QuoteService {
+	return &QuoteService{
+		productRepo: productRepo,
+		signer:      signer,
+		ttl:         ttl,
+	}
+}
+
+// CreateQuote проверяет товары и возвращает подписанную котировку цен
+func (s *QuoteService) CreateQuote(ctx context.Context, items []entity.QuoteItemRequest) (*entity.PriceQuoteResponse, error) {
+	ids := make([]uuid.UUID, 0, len(items))
+	seen := make(map[uuid.UUID]bool, len(items))
+	for _, item := range items {
+		if seen[item.ProductID] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateQuoteItem, item.ProductID)
+		}
+		seen[item.ProductID] = true
+		ids = append(ids, item.ProductID)
+	}
+
+	products, err := s.productRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+
+	byID := make(map[uuid.UUID]entity.Product, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
+
+	now := time.Now()
+	q := &quote.Quote{
+		ID:        uuid.New(),
+		TenantID:  tenant.FromContext(ctx),
+		Items:     make([]quote.Item, 0, len(items)),
+		Currency:  quoteCurrency,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(s.ttl),
+	}
+
+	for _, item := range items {
+		product, ok := byID[item.ProductID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrProductNotFound, item.ProductID)
+		}
+		if product.Status == entity.ProductStatusArchived {
+			return nil, fmt.Errorf("%w: %s", ErrProductArchived, item.ProductID)
+		}
+		if product.Status != entity.ProductStatusPublished {
+			return nil, fmt.Errorf("%w: %s", ErrProductNotAvailable, item.ProductID)
+		}
+		if !item.Quantity.MultipleOf(product.QuantityStep) {
+			return nil, fmt.Errorf("%w: %s %s", ErrInvalidQuantity, item.ProductID, item.Quantity)
+		}
+		if product.MinQty != nil && item.Quantity < *product.MinQty {
+			return nil, fmt.Errorf("%w: %s %s < %s", ErrQuantityBelowMinimum, item.ProductID, item.Quantity, *product.MinQty)
+		}
+		if product.MaxQty != nil && item.Quantity > *product.MaxQty {
+			return nil, fmt.Errorf("%w: %s %s > %s", ErrQuantityAboveMaximum, item.ProductID, item.Quantity, *product.MaxQty)
+		}
+		quoted := quote.Item{
+			ProductID:  product.ID,
+			Quantity:   item.Quantity,
+			UnitPrice:  product.Price,
+			CategoryID: product.CategoryID,
+			Name:       product.Name,
+			Unit:       product.Unit,
+			// Лимит на покупателя проверяет Orders Service по прежним заказам покупателя
+			PerCustomerLimit: product.PerCustomerLimit,
+		}
+		if product.Category != nil {
+			quoted.CategoryName = product.Category.Name
+		}
+		q.Items = append(q.Items, quoted)
+	}
+
+	token, err := s.signer.Sign(q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign quote: %w", err)
+	}
+
+	return &entity.PriceQuoteResponse{
+		Token:     token,
+		Items:     q.Items,
+		Currency:  q.Currency,
+		ExpiresAt: q.ExpiresAt,
+	}, nil
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/repository/mocks"
+	"augustberries/pkg/money"
+	"augustberries/pkg/quote"
+	"augustberries/pkg/units"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ==================== Quote Tests ====================
+
+func TestQuoteService_CreateQuote_Success(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	productRepo := new(mocks.MockProductRepository)
+	signer := quote.NewSigner("test-secret")
+
+	product := newTestProduct(uuid.New())
+	productRepo.On("GetByIDs", ctx, []uuid.UUID{product.ID}).Return([]entity.Product{*product}, nil)
+
+	service := NewQuoteService(productRepo, signer, 15*time.Minute)
+
+	// Act
+	resp, err := service.CreateQuote(ctx, []entity.QuoteItemRequest{{ProductID: product.ID, Quantity: units.Of(3)}})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "USD", resp.Currency)
+
+	q, err := signer.Verify(resp.Token)
+	require.NoError(t, err)
+	require.Len(t, q.Items, 1)
+	assert.Equal(t, money.MustParse("1299.99"), q.Items[0].UnitPrice)
+	assert.Equal(t, units.Of(3), q.Items[0].Quantity)
+}
+
+func TestQuoteService_CreateQuote_QuantityStep(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	productRepo := new(mocks.MockProductRepository)
+	signer := quote.NewSigner("test-secret")
+
+	product := newTestProduct(uuid.New())
+	product.Unit = units.UnitKilogram
+	product.QuantityStep = units.MustParse("0.5")
+	productRepo.On("GetByIDs", ctx, []uuid.UUID{product.ID}).Return([]entity.Product{*product}, nil)
+
+	service := NewQuoteService(productRepo, signer, time.Minute)
+
+	// Act
+	resp, err := service.CreateQuote(ctx, []entity.QuoteItemRequest{{ProductID: product.ID, Quantity: units.MustParse("1.5")}})
+	_, stepErr := service.CreateQuote(ctx, []entity.QuoteItemRequest{{ProductID: product.ID, Quantity: units.MustParse("1.3")}})
+
+	// Assert
+	require.NoError(t, err)
+	q, err := signer.Verify(resp.Token)
+	require.NoError(t, err)
+	assert.Equal(t, units.UnitKilogram, q.Items[0].Unit)
+	assert.ErrorIs(t, stepErr, ErrInvalidQuantity)
+}
+
+func TestQuoteService_CreateQuote_QuantityLimits(t *testing.T) {
+	minQty, maxQty, limit := units.Of(2), units.Of(5), units.Of(10)
+
+	tests := []struct {
+		name     string
+		quantity units.Quantity
+		wantErr  error
+	}{
+		{name: "within limits", quantity: units.Of(3)},
+		{name: "below minimum", quantity: units.Of(1), wantErr: ErrQuantityBelowMinimum},
+		{name: "above maximum", quantity: units.Of(6), wantErr: ErrQuantityAboveMaximum},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			productRepo := new(mocks.MockProductRepository)
+			signer := quote.NewSigner("test-secret")
+
+			product := newTestProduct(uuid.New())
+			product.MinQty, product.MaxQty, product.PerCustomerLimit = &minQty, &maxQty, &limit
+			productRepo.On("GetByIDs", ctx, []uuid.UUID{product.ID}).Return([]entity.Product{*product}, nil)
+
+			service := NewQuoteService(productRepo, signer, time.Minute)
+
+			// Act
+			resp, err := service.CreateQuote(ctx, []entity.QuoteItemRequest{{ProductID: product.ID, Quantity: tt.quantity}})
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, resp)
+				return
+			}
+			require.NoError(t, err)
+			q, err := signer.Verify(resp.Token)
+			require.NoError(t, err)
+			// Общий лимит проверяет Orders Service по прошлым заказам, котировка его только передает
+			require.NotNil(t, q.Items[0].PerCustomerLimit)
+			assert.Equal(t, limit, *q.Items[0].PerCustomerLimit)
+		})
+	}
+}
+
+func TestQuoteService_CreateQuote_ProductDeleted(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	productRepo := new(mocks.MockProductRepository)
+
+	productID := uuid.New()
+	productRepo.On("GetByIDs", ctx, []uuid.UUID{productID}).Return([]entity.Product{}, nil)
+
+	service := NewQuoteService(productRepo, quote.NewSigner("test-secret"), time.Minute)
+
+	// Act
+	resp, err := service.CreateQuote(ctx, []entity.QuoteItemRequest{{ProductID: productID, Quantity: units.Of(1)}})
+
+	// Assert
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, ErrProductNotFound)
+}
+
+func TestQuoteService_CreateQuote_DraftProduct(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	productRepo := new(mocks.MockProductRepository)
+
+	product := newTestProduct(uuid.New())
+	product.Status = entity.ProductStatusDraft
+	productRepo.On("GetByIDs", ctx, []uuid.UUID{product.ID}).Return([]entity.Product{*product}, nil)
+
+	service := NewQuoteService(productRepo, quote.NewSigner("test-secret"), time.Minute)
+
+	// Act
+	resp, err := service.CreateQuote(ctx, []entity.QuoteItemRequest{{ProductID: product.ID, Quantity: units.Of(1)}})
+
+	// Assert
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, ErrProductNotAvailable)
+}
+
+func TestQuoteService_CreateQuote_ArchivedProduct(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	productRepo := new(mocks.MockProductRepository)
+
+	product := newTestProduct(uuid.New())
+	product.Status = entity.ProductStatusArchived
+	productRepo.On("GetByIDs", ctx, []uuid.UUID{product.ID}).Return([]entity.Product{*product}, nil)
+
+	service := NewQuoteService(productRepo, quote.NewSigner("test-secret"), time.Minute)
+
+	// Act
+	resp, err := service.CreateQuote(ctx, []entity.QuoteItemRequest{{ProductID: product.ID, Quantity: units.Of(1)}})
+
+	// Assert
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, ErrProductArchived)
+}
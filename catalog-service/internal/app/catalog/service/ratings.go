@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+)
+
+// GetProductRatings возвращает денормализованные оценки всех товаров магазина для сверки с Reviews Service
+func (s *CatalogService) GetProductRatings(ctx context.Context) ([]entity.ProductRating, error) {
+	ratings, err := s.productRepo.ListRatings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ratings == nil {
+		ratings = []entity.ProductRating{}
+	}
+	return ratings, nil
+}
+
+// UpdateProductRatings исправляет оценки товаров по данным Reviews Service и сбрасывает их карточки в кэше
+// Возвращает число обновленных товаров; товары, удаленные из каталога, пропускаются
+func (s *CatalogService) UpdateProductRatings(ctx context.Context, ratings []entity.ProductRating) (int, error) {
+	updated, err := s.productRepo.UpdateRatings(ctx, ratings)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, rating := range ratings {
+		s.invalidateProduct(ctx, rating.ProductID)
+	}
+
+	return updated, nil
+}
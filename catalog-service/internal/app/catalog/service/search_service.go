@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync/atomic"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/repository"
+	"augustberries/catalog-service/internal/app/catalog/util"
+	"augustberries/pkg/recovery"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrSearchIndexDisabled - OpenSearch не настроен, перестраивать нечего
+	ErrSearchIndexDisabled = errors.New("search index is disabled")
+	// ErrReindexInProgress - перестроение индекса уже запущено
+	ErrReindexInProgress = errors.New("reindex already in progress")
+)
+
+// reindexBatchSize - товаров в одном запросе _bulk при перестроении индекса
+const reindexBatchSize = 500
+
+// SearchService ищет товары в OpenSearch и поддерживает индекс в актуальном состоянии
+// При недоступном или отключенном индексе поиск выполняется в PostgreSQL
+type SearchService struct {
+	index       util.SearchIndex // nil - OpenSearch не настроен
+	productRepo repository.ProductRepository
+	reindexing  atomic.Bool
+}
+
+func NewSearchService(index util.SearchIndex, productRepo repository.ProductRepository) *SearchService {
+	return &SearchService{
+		index:       index,
+		productRepo: productRepo,
+	}
+}
+
+// Search возвращает страницу опубликованных товаров магазина по строке поиска
+func (s *SearchService) Search(ctx context.Context, query entity.ProductSearchQuery) (*entity.ProductSearchResult, error) {
+	if s.index != nil {
+		result, err := s.searchIndex(ctx, query)
+		if err == nil {
+			return result, nil
+		}
+		log.Printf("Search index unavailable, falling back to PostgreSQL: %v", err)
+	}
+
+	products, total, err := s.productRepo.Search(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search products: %w", err)
+	}
+	if products == nil {
+		products = []entity.Product{}
+	}
+	return &entity.ProductSearchResult{Products: products, Total: int(total), Source: entity.SearchSourcePostgres}, nil
+}
+
+// searchIndex ищет ID в OpenSearch и загружает товары из PostgreSQL в порядке релевантности
+// Товары, удаленные после индексации или снятые с публикации, пропускаются
+func (s *SearchService) searchIndex(ctx context.Context, query entity.ProductSearchQuery) (*entity.ProductSearchResult, error) {
+	ids, total, err := s.index.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	products := []entity.Product{}
+	if len(ids) > 0 {
+		found, err := s.productRepo.GetByIDs(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load found products: %w", err)
+		}
+
+		byID := make(map[uuid.UUID]entity.Product, len(found))
+		for _, p := range found {
+			byID[p.ID] = p
+		}
+		for _, id := range ids {
+			if p, ok := byID[id]; ok && p.Status == entity.ProductStatusPublished {
+				products = append(products, p)
+			}
+		}
+	}
+
+	return &entity.ProductSearchResult{Products: products, Total: total, Source: entity.SearchSourceOpenSearch}, nil
+}
+
+// ApplyProductEvent обновляет документ товара по событию из product_events
+// Состояние товара читается из PostgreSQL, поэтому повторные и переупорядоченные события дают актуальный документ
+func (s *SearchService) ApplyProductEvent(ctx context.Context, event *entity.ProductEvent) error {
+	if s.index == nil || event.ProductID == uuid.Nil {
+		return nil
+	}
+
+	switch event.EventType {
+	case entity.EventTypeProductDeleted:
+		return s.index.DeleteProduct(ctx, event.ProductID)
+	case entity.EventTypeProductCreated, entity.EventTypeProductUpdated, entity.EventTypeProductPublished, entity.EventTypePriceChanged:
+	default:
+		return nil
+	}
+
+	product, err := s.productRepo.GetByID(tenant.WithID(ctx, event.TenantID), event.ProductID)
+	if err != nil {
+		if errors.Is(err, repository.ErrProductNotFound) {
+			return s.index.DeleteProduct(ctx, event.ProductID)
+		}
+		return fmt.Errorf("failed to get product: %w", err)
+	}
+
+	// В индексе только опубликованные товары
+	if product.Status != entity.ProductStatusPublished {
+		return s.index.DeleteProduct(ctx, product.ID)
+	}
+	return s.index.IndexProduct(ctx, entity.NewProductSearchDocument(product))
+}
+
+// StartReindex запускает перестроение индекса в фоне
+func (s *SearchService) StartReindex(ctx context.Context) error {
+	if s.index == nil {
+		return ErrSearchIndexDisabled
+	}
+	if !s.reindexing.CompareAndSwap(false, true) {
+		return ErrReindexInProgress
+	}
+
+	recovery.Go("catalog-service", "search_reindex", func() {
+		defer s.reindexing.Store(false)
+		count, err := s.reindex(context.WithoutCancel(ctx))
+		if err != nil {
+			log.Printf("Search reindex failed after %d products: %v", count, err)
+			return
+		}
+		log.Printf("Search reindex completed: %d products", count)
+	})
+	return nil
+}
+
+// reindex перестраивает индекс с нуля и возвращает число проиндексированных товаров
+// Новый индекс заполняется товарами всех магазинов, затем на него переключается alias
+// До переключения поиск и индексатор событий работают со старым индексом
+func (s *SearchService) reindex(ctx context.Context) (int, error) {
+	index, err := s.index.CreateIndex(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	afterID := uuid.Nil
+	for {
+		products, err := s.productRepo.ListPublishedAfter(ctx, afterID, reindexBatchSize)
+		if err != nil {
+			return count, fmt.Errorf("failed to list products: %w", err)
+		}
+		if len(products) == 0 {
+			break
+		}
+
+		docs := make([]*entity.ProductSearchDocument, len(products))
+		for i := range products {
+			docs[i] = entity.NewProductSearchDocument(&products[i])
+		}
+		if err := s.index.BulkIndex(ctx, index, docs); err != nil {
+			return count, err
+		}
+
+		count += len(products)
+		afterID = products[len(products)-1].ID
+	}
+
+	if err := s.index.SwitchAlias(ctx, index); err != nil {
+		return count, err
+	}
+	return count, nil
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/repository"
+	"augustberries/catalog-service/internal/app/catalog/repository/mocks"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// ==================== Search Tests ====================
+
+func TestSearchService_Search_KeepsIndexOrderAndSkipsUnpublished(t *testing.T) {
+	// Arrange
+	index := new(mocks.MockSearchIndex)
+	productRepo := new(mocks.MockProductRepository)
+	svc := NewSearchService(index, productRepo)
+
+	first, second, archived := uuid.New(), uuid.New(), uuid.New()
+	query := entity.ProductSearchQuery{Text: "клубника", Limit: 20}
+	index.On("Search", mock.Anything, query).Return([]uuid.UUID{second, archived, first}, 3, nil)
+	productRepo.On("GetByIDs", mock.Anything, []uuid.UUID{second, archived, first}).Return([]entity.Product{
+		{ID: first, Status: entity.ProductStatusPublished},
+		{ID: archived, Status: entity.ProductStatusArchived},
+		{ID: second, Status: entity.ProductStatusPublished},
+	}, nil)
+
+	// Act
+	result, err := svc.Search(context.Background(), query)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, entity.SearchSourceOpenSearch, result.Source)
+	assert.Equal(t, 3, result.Total)
+	require.Len(t, result.Products, 2)
+	assert.Equal(t, second, result.Products[0].ID)
+	assert.Equal(t, first, result.Products[1].ID)
+}
+
+func TestSearchService_Search_FallsBackToPostgresOnIndexError(t *testing.T) {
+	// Arrange
+	index := new(mocks.MockSearchIndex)
+	productRepo := new(mocks.MockProductRepository)
+	svc := NewSearchService(index, productRepo)
+
+	query := entity.ProductSearchQuery{Text: "малина", Limit: 20}
+	index.On("Search", mock.Anything, query).Return(nil, 0, errors.New("connection refused"))
+	productRepo.On("Search", mock.Anything, query).Return([]entity.Product{{ID: uuid.New()}}, int64(1), nil)
+
+	// Act
+	result, err := svc.Search(context.Background(), query)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, entity.SearchSourcePostgres, result.Source)
+	assert.Equal(t, 1, result.Total)
+	assert.Len(t, result.Products, 1)
+}
+
+func TestSearchService_Search_WithoutIndexUsesPostgres(t *testing.T) {
+	productRepo := new(mocks.MockProductRepository)
+	svc := NewSearchService(nil, productRepo)
+
+	query := entity.ProductSearchQuery{Text: "ежевика", Limit: 20}
+	productRepo.On("Search", mock.Anything, query).Return([]entity.Product{}, int64(0), nil)
+
+	result, err := svc.Search(context.Background(), query)
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.SearchSourcePostgres, result.Source)
+	assert.Empty(t, result.Products)
+}
+
+// ==================== ApplyProductEvent Tests ====================
+
+func TestSearchService_ApplyProductEvent_IndexesPublishedProduct(t *testing.T) {
+	// Arrange
+	index := new(mocks.MockSearchIndex)
+	productRepo := new(mocks.MockProductRepository)
+	svc := NewSearchService(index, productRepo)
+
+	product := &entity.Product{ID: uuid.New(), TenantID: "shop-a", Name: "Клубника", Status: entity.ProductStatusPublished}
+	productRepo.On("GetByID", mock.MatchedBy(func(ctx context.Context) bool {
+		return tenant.FromContext(ctx) == "shop-a"
+	}), product.ID).Return(product, nil)
+	index.On("IndexProduct", mock.Anything, mock.MatchedBy(func(doc *entity.ProductSearchDocument) bool {
+		return doc.ID == product.ID && doc.TenantID == "shop-a" && doc.Name == "Клубника"
+	})).Return(nil)
+
+	// Act
+	err := svc.ApplyProductEvent(context.Background(), &entity.ProductEvent{
+		EventType: entity.EventTypeProductUpdated,
+		TenantID:  "shop-a",
+		ProductID: product.ID,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	index.AssertExpectations(t)
+}
+
+func TestSearchService_ApplyProductEvent_RemovesUnpublishedProduct(t *testing.T) {
+	index := new(mocks.MockSearchIndex)
+	productRepo := new(mocks.MockProductRepository)
+	svc := NewSearchService(index, productRepo)
+
+	product := &entity.Product{ID: uuid.New(), Status: entity.ProductStatusArchived}
+	productRepo.On("GetByID", mock.Anything, product.ID).Return(product, nil)
+	index.On("DeleteProduct", mock.Anything, product.ID).Return(nil)
+
+	err := svc.ApplyProductEvent(context.Background(), &entity.ProductEvent{
+		EventType: entity.EventTypeProductUpdated,
+		ProductID: product.ID,
+	})
+
+	require.NoError(t, err)
+	index.AssertExpectations(t)
+	index.AssertNotCalled(t, "IndexProduct", mock.Anything, mock.Anything)
+}
+
+func TestSearchService_ApplyProductEvent_RemovesMissingProduct(t *testing.T) {
+	index := new(mocks.MockSearchIndex)
+	productRepo := new(mocks.MockProductRepository)
+	svc := NewSearchService(index, productRepo)
+
+	productID := uuid.New()
+	productRepo.On("GetByID", mock.Anything, productID).Return(nil, repository.ErrProductNotFound)
+	index.On("DeleteProduct", mock.Anything, productID).Return(nil)
+
+	err := svc.ApplyProductEvent(context.Background(), &entity.ProductEvent{
+		EventType: entity.EventTypePriceChanged,
+		ProductID: productID,
+	})
+
+	require.NoError(t, err)
+	index.AssertExpectations(t)
+}
+
+func TestSearchService_ApplyProductEvent_DeletedEventSkipsDatabase(t *testing.T) {
+	index := new(mocks.MockSearchIndex)
+	productRepo := new(mocks.MockProductRepository)
+	svc := NewSearchService(index, productRepo)
+
+	productID := uuid.New()
+	index.On("DeleteProduct", mock.Anything, productID).Return(nil)
+
+	err := svc.ApplyProductEvent(context.Background(), &entity.ProductEvent{
+		EventType: entity.EventTypeProductDeleted,
+		ProductID: productID,
+	})
+
+	require.NoError(t, err)
+	productRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+// ==================== Reindex Tests ====================
+
+func TestSearchService_Reindex_BulkIndexesBatchesAndSwitchesAlias(t *testing.T) {
+	// Arrange
+	index := new(mocks.MockSearchIndex)
+	productRepo := new(mocks.MockProductRepository)
+	svc := NewSearchService(index, productRepo)
+
+	batch := make([]entity.Product, reindexBatchSize)
+	for i := range batch {
+		batch[i] = entity.Product{ID: uuid.New(), Status: entity.ProductStatusPublished}
+	}
+	last := batch[len(batch)-1].ID
+	tail := []entity.Product{{ID: uuid.New(), Status: entity.ProductStatusPublished}}
+
+	index.On("CreateIndex", mock.Anything).Return("products_2", nil)
+	productRepo.On("ListPublishedAfter", mock.Anything, uuid.Nil, reindexBatchSize).Return(batch, nil)
+	productRepo.On("ListPublishedAfter", mock.Anything, last, reindexBatchSize).Return(tail, nil)
+	productRepo.On("ListPublishedAfter", mock.Anything, tail[0].ID, reindexBatchSize).Return([]entity.Product{}, nil)
+	index.On("BulkIndex", mock.Anything, "products_2", mock.Anything).Return(nil).Twice()
+	index.On("SwitchAlias", mock.Anything, "products_2").Return(nil)
+
+	// Act
+	count, err := svc.reindex(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, reindexBatchSize+1, count)
+	index.AssertExpectations(t)
+}
+
+func TestSearchService_Reindex_KeepsAliasOnFailure(t *testing.T) {
+	index := new(mocks.MockSearchIndex)
+	productRepo := new(mocks.MockProductRepository)
+	svc := NewSearchService(index, productRepo)
+
+	index.On("CreateIndex", mock.Anything).Return("products_2", nil)
+	productRepo.On("ListPublishedAfter", mock.Anything, uuid.Nil, reindexBatchSize).Return(nil, errors.New("db down"))
+
+	_, err := svc.reindex(context.Background())
+
+	assert.Error(t, err)
+	index.AssertNotCalled(t, "SwitchAlias", mock.Anything, mock.Anything)
+}
+
+func TestSearchService_StartReindex_DisabledIndex(t *testing.T) {
+	svc := NewSearchService(nil, new(mocks.MockProductRepository))
+
+	err := svc.StartReindex(context.Background())
+
+	assert.ErrorIs(t, err, ErrSearchIndexDisabled)
+}
+
+func TestSearchService_StartReindex_RejectsConcurrentRun(t *testing.T) {
+	svc := NewSearchService(new(mocks.MockSearchIndex), new(mocks.MockProductRepository))
+	svc.reindexing.Store(true)
+
+	err := svc.StartReindex(context.Background())
+
+	assert.ErrorIs(t, err, ErrReindexInProgress)
+}
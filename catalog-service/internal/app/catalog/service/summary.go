@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/pkg/metrics"
+)
+
+// GetDashboardSummary возвращает сводку каталога магазина для внутренней панели:
+// число товаров по статусам, число категорий и долю попаданий в кеш
+func (s *CatalogService) GetDashboardSummary(ctx context.Context) (*entity.DashboardSummary, error) {
+	byStatus, err := s.productRepo.CountByStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count products: %w", err)
+	}
+
+	categories, err := s.categoryRepo.Count(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count categories: %w", err)
+	}
+
+	summary := &entity.DashboardSummary{
+		ProductsByStatus: byStatus,
+		Categories:       categories,
+		GeneratedAt:      time.Now().UTC(),
+	}
+	for _, count := range byStatus {
+		summary.Products += count
+	}
+
+	// Кеш общий для магазинов, поэтому статистика не зависит от магазина запроса
+	cache := metrics.RedisCacheStats("catalog-service")
+	summary.Cache = entity.CacheSummary{Hits: cache.Hits, Misses: cache.Misses, HitRate: cache.HitRate}
+
+	return summary, nil
+}
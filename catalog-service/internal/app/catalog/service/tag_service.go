@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/repository"
+	"augustberries/catalog-service/internal/app/catalog/util"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTagNotFound      = errors.New("tag not found")
+	ErrTagAlreadyExists = errors.New("tag already exists")
+	ErrInvalidTagName   = errors.New("tag name must contain letters or digits")
+)
+
+// TagService управляет тегами и их привязкой к товарам
+type TagService struct {
+	tagRepo     repository.TagRepository
+	productRepo repository.ProductRepository
+}
+
+func NewTagService(tagRepo repository.TagRepository, productRepo repository.ProductRepository) *TagService {
+	return &TagService{
+		tagRepo:     tagRepo,
+		productRepo: productRepo,
+	}
+}
+
+func (s *TagService) CreateTag(ctx context.Context, req *entity.CreateTagRequest) (*entity.Tag, error) {
+	slug := util.Slugify(req.Name)
+	if slug == "" {
+		return nil, ErrInvalidTagName
+	}
+
+	tag := &entity.Tag{
+		ID:        uuid.New(),
+		Name:      req.Name,
+		Slug:      slug,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.tagRepo.Create(ctx, tag); err != nil {
+		if errors.Is(err, repository.ErrTagAlreadyExists) {
+			return nil, ErrTagAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	return tag, nil
+}
+
+func (s *TagService) GetTag(ctx context.Context, id uuid.UUID) (*entity.Tag, error) {
+	tag, err := s.tagRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrTagNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+	return tag, nil
+}
+
+func (s *TagService) GetAllTags(ctx context.Context) ([]entity.Tag, error) {
+	tags, err := s.tagRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	return tags, nil
+}
+
+// UpdateTag переименовывает тег; slug меняется вместе с названием
+func (s *TagService) UpdateTag(ctx context.Context, id uuid.UUID, req *entity.UpdateTagRequest) (*entity.Tag, error) {
+	slug := util.Slugify(req.Name)
+	if slug == "" {
+		return nil, ErrInvalidTagName
+	}
+
+	tag, err := s.tagRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrTagNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	tag.Name = req.Name
+	tag.Slug = slug
+
+	if err := s.tagRepo.Update(ctx, tag); err != nil {
+		if errors.Is(err, repository.ErrTagNotFound) {
+			return nil, ErrTagNotFound
+		}
+		if errors.Is(err, repository.ErrTagAlreadyExists) {
+			return nil, ErrTagAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to update tag: %w", err)
+	}
+
+	return tag, nil
+}
+
+// DeleteTag удаляет тег; товары теряют его автоматически
+func (s *TagService) DeleteTag(ctx context.Context, id uuid.UUID) error {
+	if err := s.tagRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrTagNotFound) {
+			return ErrTagNotFound
+		}
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	return nil
+}
+
+// SetProductTags заменяет теги товара и возвращает новый список тегов
+// Все теги должны существовать в магазине товара
+func (s *TagService) SetProductTags(ctx context.Context, productID uuid.UUID, req *entity.SetProductTagsRequest) ([]entity.Tag, error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		if errors.Is(err, repository.ErrProductNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	tagIDs := uniqueIDs(req.TagIDs)
+	tags := []entity.Tag{}
+
+	if len(tagIDs) > 0 {
+		found, err := s.tagRepo.GetByIDs(ctx, tagIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tags: %w", err)
+		}
+		if len(found) != len(tagIDs) {
+			return nil, ErrTagNotFound
+		}
+		tags = found
+	}
+
+	if err := s.tagRepo.SetProductTags(ctx, productID, tagIDs); err != nil {
+		return nil, fmt.Errorf("failed to set product tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// TagFacets считает, сколько товаров из списка отмечено каждым тегом
+// Фасеты отсортированы по убыванию количества, при равенстве - по названию
+func TagFacets(products []entity.ProductWithCategory) []entity.TagFacet {
+	index := make(map[uuid.UUID]int)
+	facets := []entity.TagFacet{}
+
+	for _, p := range products {
+		for _, tag := range p.Tags {
+			i, ok := index[tag.ID]
+			if !ok {
+				i = len(facets)
+				index[tag.ID] = i
+				facets = append(facets, entity.TagFacet{Slug: tag.Slug, Name: tag.Name})
+			}
+			facets[i].Count++
+		}
+	}
+
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Name < facets[j].Name
+	})
+
+	return facets
+}
+
+// uniqueIDs убирает повторы, сохраняя порядок
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	result := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/repository"
+	"augustberries/catalog-service/internal/app/catalog/repository/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// ==================== Tag Tests ====================
+
+func TestTagService_CreateTag_GeneratesSlug(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	tagRepo := new(mocks.MockTagRepository)
+	tagRepo.On("Create", ctx, mock.AnythingOfType("*entity.Tag")).Return(nil)
+
+	service := NewTagService(tagRepo, new(mocks.MockProductRepository))
+
+	// Act
+	tag, err := service.CreateTag(ctx, &entity.CreateTagRequest{Name: "New Arrivals"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "new-arrivals", tag.Slug)
+	tagRepo.AssertExpectations(t)
+}
+
+func TestTagService_CreateTag_InvalidName(t *testing.T) {
+	// Arrange
+	tagRepo := new(mocks.MockTagRepository)
+	service := NewTagService(tagRepo, new(mocks.MockProductRepository))
+
+	// Act
+	_, err := service.CreateTag(context.Background(), &entity.CreateTagRequest{Name: "!!"})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidTagName)
+	tagRepo.AssertNotCalled(t, "Create")
+}
+
+func TestTagService_CreateTag_AlreadyExists(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	tagRepo := new(mocks.MockTagRepository)
+	tagRepo.On("Create", ctx, mock.AnythingOfType("*entity.Tag")).Return(repository.ErrTagAlreadyExists)
+
+	service := NewTagService(tagRepo, new(mocks.MockProductRepository))
+
+	// Act
+	_, err := service.CreateTag(ctx, &entity.CreateTagRequest{Name: "Sale"})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrTagAlreadyExists)
+}
+
+func TestTagService_UpdateTag_RenamesSlug(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	tagRepo := new(mocks.MockTagRepository)
+	tag := &entity.Tag{ID: uuid.New(), Name: "Sale", Slug: "sale"}
+	tagRepo.On("GetByID", ctx, tag.ID).Return(tag, nil)
+	tagRepo.On("Update", ctx, tag).Return(nil)
+
+	service := NewTagService(tagRepo, new(mocks.MockProductRepository))
+
+	// Act
+	result, err := service.UpdateTag(ctx, tag.ID, &entity.UpdateTagRequest{Name: "Black Friday"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "black-friday", result.Slug)
+	tagRepo.AssertExpectations(t)
+}
+
+func TestTagService_DeleteTag_NotFound(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	tagRepo := new(mocks.MockTagRepository)
+	id := uuid.New()
+	tagRepo.On("Delete", ctx, id).Return(repository.ErrTagNotFound)
+
+	service := NewTagService(tagRepo, new(mocks.MockProductRepository))
+
+	// Act
+	err := service.DeleteTag(ctx, id)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrTagNotFound)
+}
+
+// ==================== Product Tags Tests ====================
+
+func TestTagService_SetProductTags_Success(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	tagRepo := new(mocks.MockTagRepository)
+	productRepo := new(mocks.MockProductRepository)
+
+	product := &entity.Product{ID: uuid.New()}
+	sale := entity.Tag{ID: uuid.New(), Name: "Sale", Slug: "sale"}
+	productRepo.On("GetByID", ctx, product.ID).Return(product, nil)
+	tagRepo.On("GetByIDs", ctx, []uuid.UUID{sale.ID}).Return([]entity.Tag{sale}, nil)
+	tagRepo.On("SetProductTags", ctx, product.ID, []uuid.UUID{sale.ID}).Return(nil)
+
+	service := NewTagService(tagRepo, productRepo)
+
+	// Act: повтор ID не должен приводить к ошибке
+	tags, err := service.SetProductTags(ctx, product.ID, &entity.SetProductTagsRequest{TagIDs: []uuid.UUID{sale.ID, sale.ID}})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []entity.Tag{sale}, tags)
+	tagRepo.AssertExpectations(t)
+}
+
+func TestTagService_SetProductTags_ClearsTags(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	tagRepo := new(mocks.MockTagRepository)
+	productRepo := new(mocks.MockProductRepository)
+
+	product := &entity.Product{ID: uuid.New()}
+	productRepo.On("GetByID", ctx, product.ID).Return(product, nil)
+	tagRepo.On("SetProductTags", ctx, product.ID, []uuid.UUID{}).Return(nil)
+
+	service := NewTagService(tagRepo, productRepo)
+
+	// Act
+	tags, err := service.SetProductTags(ctx, product.ID, &entity.SetProductTagsRequest{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+	tagRepo.AssertNotCalled(t, "GetByIDs")
+}
+
+func TestTagService_SetProductTags_UnknownTag(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	tagRepo := new(mocks.MockTagRepository)
+	productRepo := new(mocks.MockProductRepository)
+
+	product := &entity.Product{ID: uuid.New()}
+	unknown := uuid.New()
+	productRepo.On("GetByID", ctx, product.ID).Return(product, nil)
+	tagRepo.On("GetByIDs", ctx, []uuid.UUID{unknown}).Return([]entity.Tag{}, nil)
+
+	service := NewTagService(tagRepo, productRepo)
+
+	// Act
+	_, err := service.SetProductTags(ctx, product.ID, &entity.SetProductTagsRequest{TagIDs: []uuid.UUID{unknown}})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrTagNotFound)
+	tagRepo.AssertNotCalled(t, "SetProductTags")
+}
+
+func TestTagService_SetProductTags_ProductNotFound(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	productRepo := new(mocks.MockProductRepository)
+	id := uuid.New()
+	productRepo.On("GetByID", ctx, id).Return(nil, repository.ErrProductNotFound)
+
+	service := NewTagService(new(mocks.MockTagRepository), productRepo)
+
+	// Act
+	_, err := service.SetProductTags(ctx, id, &entity.SetProductTagsRequest{})
+
+	// Assert
+	assert.True(t, errors.Is(err, ErrProductNotFound))
+}
+
+// ==================== Tag Facets Tests ====================
+
+func TestTagFacets_SortedByCountThenName(t *testing.T) {
+	// Arrange
+	sale := entity.Tag{ID: uuid.New(), Name: "Sale", Slug: "sale"}
+	gifts := entity.Tag{ID: uuid.New(), Name: "Gifts", Slug: "gifts"}
+	eco := entity.Tag{ID: uuid.New(), Name: "Eco", Slug: "eco"}
+
+	products := []entity.ProductWithCategory{
+		{Product: entity.Product{Tags: []entity.Tag{sale, gifts}}},
+		{Product: entity.Product{Tags: []entity.Tag{sale, eco}}},
+		{Product: entity.Product{}},
+	}
+
+	// Act
+	facets := TagFacets(products)
+
+	// Assert
+	assert.Equal(t, []entity.TagFacet{
+		{Slug: "sale", Name: "Sale", Count: 2},
+		{Slug: "eco", Name: "Eco", Count: 1},
+		{Slug: "gifts", Name: "Gifts", Count: 1},
+	}, facets)
+}
+
+func TestTagFacets_NoProducts(t *testing.T) {
+	assert.Empty(t, TagFacets(nil))
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/repository"
+	"augustberries/catalog-service/internal/app/catalog/util"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTranslationNotFound = errors.New("translation not found")
+	ErrInvalidLocale       = errors.New("invalid locale")
+	// ErrDefaultLocaleTranslation - основной язык магазина редактируется в самом товаре
+	ErrDefaultLocaleTranslation = errors.New("default locale content is stored in the product")
+)
+
+// TranslationService управляет переводами товаров и локализует ответы каталога
+// Основной язык магазина хранится в самих товарах, переводы - в отдельной таблице
+type TranslationService struct {
+	translationRepo repository.TranslationRepository
+	productRepo     repository.ProductRepository
+	defaultLocale   string
+}
+
+// NewTranslationService создает сервис переводов с основным языком магазина defaultLocale
+func NewTranslationService(translationRepo repository.TranslationRepository, productRepo repository.ProductRepository, defaultLocale string) *TranslationService {
+	return &TranslationService{
+		translationRepo: translationRepo,
+		productRepo:     productRepo,
+		defaultLocale:   util.NormalizeLocale(defaultLocale),
+	}
+}
+
+// DefaultLocale возвращает основной язык магазина
+func (s *TranslationService) DefaultLocale() string {
+	return s.defaultLocale
+}
+
+// ResolveChain строит цепочку поиска перевода по заголовку Accept-Language
+func (s *TranslationService) ResolveChain(acceptLanguage string) []string {
+	return util.LocaleChain(util.ParseAcceptLanguage(acceptLanguage), s.defaultLocale)
+}
+
+// GetTranslations возвращает все переводы товара
+func (s *TranslationService) GetTranslations(ctx context.Context, productID uuid.UUID) ([]entity.ProductTranslation, error) {
+	if err := s.checkProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	translations, err := s.translationRepo.ListByProduct(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get translations: %w", err)
+	}
+	return translations, nil
+}
+
+// SetTranslation создает или заменяет перевод товара на язык locale
+func (s *TranslationService) SetTranslation(ctx context.Context, productID uuid.UUID, locale string, req *entity.SetTranslationRequest) (*entity.ProductTranslation, error) {
+	locale, err := s.translationLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	translation := &entity.ProductTranslation{
+		ProductID:   productID,
+		Locale:      locale,
+		Name:        req.Name,
+		Description: req.Description,
+		UpdatedAt:   time.Now(),
+	}
+
+	if err := s.translationRepo.Upsert(ctx, translation); err != nil {
+		return nil, fmt.Errorf("failed to save translation: %w", err)
+	}
+
+	return translation, nil
+}
+
+// DeleteTranslation удаляет перевод товара; товар снова показывается на следующем языке цепочки
+func (s *TranslationService) DeleteTranslation(ctx context.Context, productID uuid.UUID, locale string) error {
+	locale, err := s.translationLocale(locale)
+	if err != nil {
+		return err
+	}
+
+	if err := s.translationRepo.Delete(ctx, productID, locale); err != nil {
+		if errors.Is(err, repository.ErrTranslationNotFound) {
+			return ErrTranslationNotFound
+		}
+		return fmt.Errorf("failed to delete translation: %w", err)
+	}
+	return nil
+}
+
+// Localize подставляет в товары название и описание на первом языке цепочки, для которого есть перевод
+// Дойдя до основного языка магазина, товар остается с собственным содержимым
+// Нулевой (nil) сервис ничего не меняет
+func (s *TranslationService) Localize(ctx context.Context, chain []string, products ...*entity.Product) error {
+	if s == nil || len(products) == 0 {
+		return nil
+	}
+
+	// Языки цепочки до основного: переводы на более дальние языки не понадобятся
+	var locales []string
+	for _, locale := range chain {
+		if locale == s.defaultLocale {
+			break
+		}
+		locales = append(locales, locale)
+	}
+
+	for _, p := range products {
+		p.Locale = s.defaultLocale
+	}
+	if len(locales) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+
+	translations, err := s.translationRepo.GetForProducts(ctx, uniqueIDs(ids), locales)
+	if err != nil {
+		return fmt.Errorf("failed to get translations: %w", err)
+	}
+
+	byProduct := make(map[uuid.UUID]map[string]*entity.ProductTranslation, len(translations))
+	for i := range translations {
+		t := &translations[i]
+		if byProduct[t.ProductID] == nil {
+			byProduct[t.ProductID] = make(map[string]*entity.ProductTranslation)
+		}
+		byProduct[t.ProductID][t.Locale] = t
+	}
+
+	for _, p := range products {
+		for _, locale := range locales {
+			if t, ok := byProduct[p.ID][locale]; ok {
+				p.Name = t.Name
+				p.Description = t.Description
+				p.Locale = locale
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// translationLocale нормализует и проверяет язык перевода
+func (s *TranslationService) translationLocale(locale string) (string, error) {
+	locale = util.NormalizeLocale(locale)
+	if !util.ValidLocale(locale) {
+		return "", ErrInvalidLocale
+	}
+	if locale == s.defaultLocale {
+		return "", ErrDefaultLocaleTranslation
+	}
+	return locale, nil
+}
+
+// checkProduct проверяет, что товар существует в магазине
+func (s *TranslationService) checkProduct(ctx context.Context, productID uuid.UUID) error {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		if errors.Is(err, repository.ErrProductNotFound) {
+			return ErrProductNotFound
+		}
+		return fmt.Errorf("failed to get product: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/catalog-service/internal/app/catalog/repository"
+	"augustberries/catalog-service/internal/app/catalog/repository/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// ==================== Localize Tests ====================
+
+func TestTranslationService_Localize_FallbackChain(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	translationRepo := new(mocks.MockTranslationRepository)
+	service := NewTranslationService(translationRepo, new(mocks.MockProductRepository), "ru")
+
+	german := &entity.Product{ID: uuid.New(), Name: "Ноутбук"}
+	english := &entity.Product{ID: uuid.New(), Name: "Мышь"}
+	untranslated := &entity.Product{ID: uuid.New(), Name: "Клавиатура"}
+
+	translationRepo.On("GetForProducts", ctx, mock.Anything, []string{"de-at", "de", "en"}).Return([]entity.ProductTranslation{
+		{ProductID: german.ID, Locale: "de", Name: "Laptop", Description: "Schnell"},
+		{ProductID: german.ID, Locale: "en", Name: "Laptop (en)"},
+		{ProductID: english.ID, Locale: "en", Name: "Mouse"},
+	}, nil)
+
+	// Act
+	err := service.Localize(ctx, service.ResolveChain("de-AT, en;q=0.5"), german, english, untranslated)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Laptop", german.Name)
+	assert.Equal(t, "Schnell", german.Description)
+	assert.Equal(t, "de", german.Locale)
+	assert.Equal(t, "Mouse", english.Name)
+	assert.Equal(t, "en", english.Locale)
+	assert.Equal(t, "Клавиатура", untranslated.Name)
+	assert.Equal(t, "ru", untranslated.Locale)
+}
+
+func TestTranslationService_Localize_DefaultLocaleSkipsLookup(t *testing.T) {
+	// Arrange
+	translationRepo := new(mocks.MockTranslationRepository)
+	service := NewTranslationService(translationRepo, new(mocks.MockProductRepository), "ru")
+	product := &entity.Product{ID: uuid.New(), Name: "Ноутбук"}
+
+	// Act: основной язык стоит первым, языки после него не ищутся
+	err := service.Localize(context.Background(), service.ResolveChain("ru,en;q=0.8"), product)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Ноутбук", product.Name)
+	assert.Equal(t, "ru", product.Locale)
+	translationRepo.AssertNotCalled(t, "GetForProducts", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// ==================== Translation CRUD Tests ====================
+
+func TestTranslationService_SetTranslation(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	translationRepo := new(mocks.MockTranslationRepository)
+	productRepo := new(mocks.MockProductRepository)
+	service := NewTranslationService(translationRepo, productRepo, "ru")
+
+	productID := uuid.New()
+	productRepo.On("GetByID", ctx, productID).Return(&entity.Product{ID: productID}, nil)
+	translationRepo.On("Upsert", ctx, mock.AnythingOfType("*entity.ProductTranslation")).Return(nil)
+
+	// Act
+	translation, err := service.SetTranslation(ctx, productID, "PT_br", &entity.SetTranslationRequest{Name: "Notebook"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "pt-br", translation.Locale)
+	translationRepo.AssertExpectations(t)
+}
+
+func TestTranslationService_SetTranslation_InvalidLocale(t *testing.T) {
+	translationRepo := new(mocks.MockTranslationRepository)
+	service := NewTranslationService(translationRepo, new(mocks.MockProductRepository), "ru")
+	req := &entity.SetTranslationRequest{Name: "Notebook"}
+
+	_, invalidErr := service.SetTranslation(context.Background(), uuid.New(), "english", req)
+	_, defaultErr := service.SetTranslation(context.Background(), uuid.New(), "RU", req)
+
+	assert.ErrorIs(t, invalidErr, ErrInvalidLocale)
+	assert.ErrorIs(t, defaultErr, ErrDefaultLocaleTranslation)
+	translationRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestTranslationService_DeleteTranslation_NotFound(t *testing.T) {
+	ctx := context.Background()
+	translationRepo := new(mocks.MockTranslationRepository)
+	service := NewTranslationService(translationRepo, new(mocks.MockProductRepository), "ru")
+
+	productID := uuid.New()
+	translationRepo.On("Delete", ctx, productID, "en").Return(repository.ErrTranslationNotFound)
+
+	err := service.DeleteTranslation(ctx, productID, "en")
+
+	assert.ErrorIs(t, err, ErrTranslationNotFound)
+}
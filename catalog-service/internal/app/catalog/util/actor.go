@@ -0,0 +1,27 @@
+package util
+
+import "context"
+
+// Actor - пользователь, выполняющий запрос (из JWT claims)
+type Actor struct {
+	UserID string
+	Email  string
+
+	// Сотрудник поддержки, действующий от имени пользователя (токен имперсонации)
+	ImpersonatorID    string
+	ImpersonatorEmail string
+}
+
+type actorKey struct{}
+
+// WithActor кладет пользователя запроса в контекст
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext возвращает пользователя запроса
+// Для фоновых операций без пользователя возвращает пустой Actor
+func ActorFromContext(ctx context.Context) Actor {
+	actor, _ := ctx.Value(actorKey{}).(Actor)
+	return actor
+}
@@ -0,0 +1,193 @@
+package util
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+)
+
+var (
+	// ErrFeedTooLarge - фид больше допустимого размера
+	ErrFeedTooLarge = errors.New("feed is too large")
+	// ErrUnsupportedFeedFormat - формат фида не поддерживается
+	ErrUnsupportedFeedFormat = errors.New("unsupported feed format")
+)
+
+// defaultFeedRecord - элемент записи XML фида, если в правилах он не задан
+const defaultFeedRecord = "item"
+
+// FeedRecord - запись фида: значения по именам колонок CSV или дочерних элементов и атрибутов XML
+type FeedRecord map[string]string
+
+// ParseFeed читает записи фида по одной и передает их в fn, не загружая фид в память целиком
+// Ошибка fn прерывает чтение и возвращается как есть
+func ParseFeed(r io.Reader, format entity.FeedFormat, mapping entity.FeedMapping, fn func(FeedRecord) error) error {
+	switch format {
+	case entity.FeedFormatCSV:
+		return parseCSVFeed(r, mapping.Delimiter, fn)
+	case entity.FeedFormatXML:
+		record := mapping.Record
+		if record == "" {
+			record = defaultFeedRecord
+		}
+		return parseXMLFeed(r, record, fn)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedFeedFormat, format)
+	}
+}
+
+// parseCSVFeed читает CSV с заголовком; строки с другим числом колонок не считаются ошибкой
+func parseCSVFeed(r io.Reader, delimiter string, fn func(FeedRecord) error) error {
+	reader := csv.NewReader(r)
+	if delimiter != "" {
+		reader.Comma = []rune(delimiter)[0]
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return fmt.Errorf("failed to read feed header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+	// Выгрузки из Excel начинаются с BOM
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read feed row: %w", err)
+		}
+
+		record := make(FeedRecord, len(header))
+		for i, value := range row {
+			if i < len(header) {
+				record[header[i]] = strings.TrimSpace(value)
+			}
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+}
+
+// xmlFeedRecord - запись XML фида: атрибуты и дочерние элементы первого уровня
+type xmlFeedRecord struct {
+	Attrs  []xml.Attr `xml:",any,attr"`
+	Fields []struct {
+		XMLName xml.Name
+		Value   string `xml:",chardata"`
+	} `xml:",any"`
+}
+
+// parseXMLFeed читает элементы записи с именем record на любой глубине документа
+func parseXMLFeed(r io.Reader, record string, fn func(FeedRecord) error) error {
+	decoder := xml.NewDecoder(r)
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read feed: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != record {
+			continue
+		}
+
+		var item xmlFeedRecord
+		if err := decoder.DecodeElement(&item, &start); err != nil {
+			return fmt.Errorf("failed to read feed record: %w", err)
+		}
+
+		values := make(FeedRecord, len(item.Attrs)+len(item.Fields))
+		for _, attr := range item.Attrs {
+			values[attr.Name.Local] = strings.TrimSpace(attr.Value)
+		}
+		for _, field := range item.Fields {
+			values[field.XMLName.Local] = strings.TrimSpace(field.Value)
+		}
+		if err := fn(values); err != nil {
+			return err
+		}
+	}
+}
+
+// HTTPFeedFetcher загружает фиды поставщиков по HTTP(S)
+type HTTPFeedFetcher struct {
+	client   *http.Client
+	maxBytes int64
+}
+
+// NewHTTPFeedFetcher создает загрузчик фидов; maxBytes ограничивает размер фида, 0 - без ограничения
+func NewHTTPFeedFetcher(timeout time.Duration, maxBytes int64) *HTTPFeedFetcher {
+	return &HTTPFeedFetcher{
+		client:   &http.Client{Timeout: timeout},
+		maxBytes: maxBytes,
+	}
+}
+
+// Fetch открывает фид; тело читается потоково и должно быть закрыто вызывающим
+func (f *HTTPFeedFetcher) Fetch(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid feed url: %w", err)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch feed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch feed: unexpected status %d", resp.StatusCode)
+	}
+
+	if f.maxBytes <= 0 {
+		return resp.Body, nil
+	}
+	return &limitedBody{body: resp.Body, remaining: f.maxBytes}, nil
+}
+
+// limitedBody возвращает ErrFeedTooLarge, если тело длиннее лимита (а не обрезает его молча)
+type limitedBody struct {
+	body      io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.body.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n, ErrFeedTooLarge
+	}
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	return b.body.Close()
+}
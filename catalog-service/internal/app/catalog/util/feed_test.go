@@ -0,0 +1,81 @@
+package util
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func collectFeed(t *testing.T, data string, format entity.FeedFormat, mapping entity.FeedMapping) []FeedRecord {
+	t.Helper()
+	var records []FeedRecord
+	err := ParseFeed(strings.NewReader(data), format, mapping, func(record FeedRecord) error {
+		records = append(records, record)
+		return nil
+	})
+	require.NoError(t, err)
+	return records
+}
+
+func TestParseFeed_CSV(t *testing.T) {
+	data := "\ufeffsku; title ;price\n" +
+		"A-1;\"Чай, черный\";12,50\n" +
+		"A-2;Кофе\n"
+
+	records := collectFeed(t, data, entity.FeedFormatCSV, entity.FeedMapping{Delimiter: ";"})
+
+	require.Len(t, records, 2)
+	assert.Equal(t, FeedRecord{"sku": "A-1", "title": "Чай, черный", "price": "12,50"}, records[0])
+	assert.Equal(t, FeedRecord{"sku": "A-2", "title": "Кофе"}, records[1])
+}
+
+func TestParseFeed_XML(t *testing.T) {
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<catalog>
+  <offers>
+    <offer id="A-1"><name> Чай </name><price>12.50</price></offer>
+    <offer id="A-2"><name>Кофе</name></offer>
+  </offers>
+</catalog>`
+
+	records := collectFeed(t, data, entity.FeedFormatXML, entity.FeedMapping{Record: "offer"})
+
+	require.Len(t, records, 2)
+	assert.Equal(t, FeedRecord{"id": "A-1", "name": "Чай", "price": "12.50"}, records[0])
+	assert.Equal(t, FeedRecord{"id": "A-2", "name": "Кофе"}, records[1])
+}
+
+func TestParseFeed_StopsOnCallbackError(t *testing.T) {
+	stop := errors.New("stop")
+	calls := 0
+
+	err := ParseFeed(strings.NewReader("sku\n1\n2\n"), entity.FeedFormatCSV, entity.FeedMapping{}, func(FeedRecord) error {
+		calls++
+		return stop
+	})
+
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls)
+}
+
+func TestParseFeed_UnsupportedFormat(t *testing.T) {
+	err := ParseFeed(strings.NewReader(""), "json", entity.FeedMapping{}, func(FeedRecord) error { return nil })
+	assert.ErrorIs(t, err, ErrUnsupportedFeedFormat)
+}
+
+func TestLimitedBody(t *testing.T) {
+	body := &limitedBody{body: io.NopCloser(strings.NewReader("12345")), remaining: 5}
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, "12345", string(data))
+
+	body = &limitedBody{body: io.NopCloser(strings.NewReader("123456")), remaining: 5}
+	_, err = io.ReadAll(body)
+	assert.ErrorIs(t, err, ErrFeedTooLarge)
+}
@@ -2,9 +2,12 @@ package util
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"augustberries/catalog-service/internal/app/catalog/entity"
+
+	"github.com/google/uuid"
 )
 
 // RedisCache интерфейс для работы с Redis кешем
@@ -13,12 +16,49 @@ type RedisCache interface {
 	SetCategories(ctx context.Context, categories []entity.Category, ttl time.Duration) error
 	GetCategories(ctx context.Context) ([]entity.Category, error)
 	DeleteCategories(ctx context.Context) error
+	SetBrands(ctx context.Context, brands []entity.Brand, ttl time.Duration) error
+	GetBrands(ctx context.Context) ([]entity.Brand, error)
+	DeleteBrands(ctx context.Context) error
 	Close() error
 }
 
+// ProductCache кеш карточек товаров (GET /products/:id)
+// Ключи хранятся с префиксом магазина, промах кеша - (nil, nil)
+type ProductCache interface {
+	SetProduct(ctx context.Context, product *entity.ProductWithCategory, ttl time.Duration) error
+	GetProduct(ctx context.Context, id uuid.UUID) (*entity.ProductWithCategory, error)
+	DeleteProduct(ctx context.Context, id uuid.UUID) error
+}
+
+// RateCounter считает запросы в окнах фиксированной длины (ограничение частоты запросов)
+type RateCounter interface {
+	// Increment увеличивает счетчик key магазина из контекста в текущем окне и возвращает его новое значение
+	Increment(ctx context.Context, key string, window time.Duration) (int64, error)
+}
+
 // MessagePublisher интерфейс для отправки сообщений в очередь (Kafka)
 // Используется для dependency injection и упрощения тестирования
 type MessagePublisher interface {
 	PublishMessage(ctx context.Context, key string, value []byte) error
 	Close() error
 }
+
+// SearchIndex интерфейс поискового индекса товаров (OpenSearch)
+// Чтение и запись идут через alias, перестроение создает новый индекс и переключает на него alias
+type SearchIndex interface {
+	// Search возвращает ID найденных товаров магазина из контекста в порядке релевантности и общее число найденных
+	Search(ctx context.Context, query entity.ProductSearchQuery) ([]uuid.UUID, int, error)
+	IndexProduct(ctx context.Context, doc *entity.ProductSearchDocument) error
+	// DeleteProduct удаляет документ; отсутствующий документ ошибкой не считается
+	DeleteProduct(ctx context.Context, id uuid.UUID) error
+	// CreateIndex создает новый пустой индекс (без alias) и возвращает его имя
+	CreateIndex(ctx context.Context) (string, error)
+	BulkIndex(ctx context.Context, index string, docs []*entity.ProductSearchDocument) error
+	// SwitchAlias переключает alias на index и удаляет прежние индексы
+	SwitchAlias(ctx context.Context, index string) error
+}
+
+// FeedFetcher загружает фиды поставщиков по URL
+type FeedFetcher interface {
+	Fetch(ctx context.Context, url string) (io.ReadCloser, error)
+}
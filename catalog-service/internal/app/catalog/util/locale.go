@@ -0,0 +1,92 @@
+package util
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// localePattern - язык BCP 47 с необязательным регионом или скриптом: en, de-at, pt-br, zh-hant
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})?$`)
+
+// maxAcceptLanguages - сколько языков из Accept-Language учитывается
+const maxAcceptLanguages = 10
+
+// NormalizeLocale приводит код языка к каноническому виду: нижний регистр, дефис вместо подчеркивания
+func NormalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// ValidLocale проверяет формат нормализованного кода языка
+func ValidLocale(locale string) bool {
+	return localePattern.MatchString(locale)
+}
+
+// ParseAcceptLanguage разбирает заголовок Accept-Language в список языков по убыванию веса
+// "de-AT,de;q=0.9,en;q=0.5" -> [de-at de en]; "*" и языки с q=0 пропускаются
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	var parsed []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		locale := NormalizeLocale(tag)
+		if locale == "" || locale == "*" || !ValidLocale(locale) {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			weight, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = weight
+		}
+		if q <= 0 {
+			continue
+		}
+
+		parsed = append(parsed, weighted{locale: locale, q: q})
+		if len(parsed) == maxAcceptLanguages {
+			break
+		}
+	}
+
+	// Стабильная сортировка сохраняет порядок клиента для языков с одинаковым весом
+	sort.SliceStable(parsed, func(i, j int) bool { return parsed[i].q > parsed[j].q })
+
+	locales := make([]string, len(parsed))
+	for i, w := range parsed {
+		locales[i] = w.locale
+	}
+	return locales
+}
+
+// LocaleChain строит цепочку поиска перевода: каждый запрошенный язык, затем его базовый язык,
+// в конце основной язык магазина. [de-at en], "ru" -> [de-at de en ru]
+func LocaleChain(preferred []string, defaultLocale string) []string {
+	chain := make([]string, 0, len(preferred)*2+1)
+	seen := make(map[string]bool, cap(chain))
+
+	add := func(locale string) {
+		if locale != "" && !seen[locale] {
+			seen[locale] = true
+			chain = append(chain, locale)
+		}
+	}
+
+	for _, locale := range preferred {
+		add(locale)
+		if base, _, found := strings.Cut(locale, "-"); found {
+			add(base)
+		}
+	}
+	add(NormalizeLocale(defaultLocale))
+
+	return chain
+}
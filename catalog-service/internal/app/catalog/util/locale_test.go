@@ -0,0 +1,36 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{"de-AT,de;q=0.9,en;q=0.5", []string{"de-at", "de", "en"}},
+		{"en;q=0.3, fr , pt_BR;q=0.8", []string{"fr", "pt-br", "en"}},
+		{"*, ru;q=0, es;q=bad, it", []string{"it"}},
+		{"", []string{}},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ParseAcceptLanguage(tt.header), tt.header)
+	}
+}
+
+func TestLocaleChain(t *testing.T) {
+	assert.Equal(t, []string{"de-at", "de", "en", "ru"}, LocaleChain([]string{"de-at", "en"}, "ru"))
+	assert.Equal(t, []string{"ru-ru", "ru"}, LocaleChain([]string{"ru-ru"}, "RU"))
+	assert.Equal(t, []string{"ru"}, LocaleChain(nil, "ru"))
+}
+
+func TestValidLocale(t *testing.T) {
+	assert.True(t, ValidLocale("en"))
+	assert.True(t, ValidLocale("zh-hant"))
+	assert.False(t, ValidLocale("english"))
+	assert.False(t, ValidLocale("en-"))
+}
@@ -0,0 +1,260 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+)
+
+// productIndexMapping - схема индекса товаров
+// price хранится как scaled_float с точностью до копейки
+const productIndexMapping = `{
+  "settings": {"number_of_shards": 1},
+  "mappings": {
+    "properties": {
+      "tenant_id":   {"type": "keyword"},
+      "name":        {"type": "text", "fields": {"keyword": {"type": "keyword", "ignore_above": 256}}},
+      "description": {"type": "text"},
+      "slug":        {"type": "keyword"},
+      "price":       {"type": "scaled_float", "scaling_factor": 100},
+      "category_id": {"type": "keyword"},
+      "brand_id":    {"type": "keyword"},
+      "indexed_at":  {"type": "date"}
+    }
+  }
+}`
+
+// OpenSearchConfig - настройки клиента OpenSearch
+type OpenSearchConfig struct {
+	URL      string
+	Alias    string // Alias, через который читается и пишется индекс товаров
+	Username string
+	Password string
+	Timeout  time.Duration
+}
+
+// OpenSearchClient - поисковый индекс товаров в OpenSearch через REST API
+type OpenSearchClient struct {
+	baseURL  string
+	alias    string
+	username string
+	password string
+	client   *http.Client
+	now      func() time.Time
+}
+
+func NewOpenSearchClient(cfg OpenSearchConfig) *OpenSearchClient {
+	return &OpenSearchClient{
+		baseURL:  strings.TrimRight(cfg.URL, "/"),
+		alias:    cfg.Alias,
+		username: cfg.Username,
+		password: cfg.Password,
+		client:   &http.Client{Timeout: cfg.Timeout},
+		now:      time.Now,
+	}
+}
+
+// EnsureIndex создает первый индекс и alias на него, если alias еще не существует
+func (c *OpenSearchClient) EnsureIndex(ctx context.Context) error {
+	status, err := c.do(ctx, http.MethodHead, "/_alias/"+c.alias, nil, nil)
+	if err != nil && status != http.StatusNotFound {
+		return fmt.Errorf("failed to check search alias: %w", err)
+	}
+	if status == http.StatusOK {
+		return nil
+	}
+
+	index, err := c.CreateIndex(ctx)
+	if err != nil {
+		return err
+	}
+	return c.SwitchAlias(ctx, index)
+}
+
+func (c *OpenSearchClient) Search(ctx context.Context, query entity.ProductSearchQuery) ([]uuid.UUID, int, error) {
+	filter := []interface{}{
+		map[string]interface{}{"term": map[string]interface{}{"tenant_id": tenant.FromContext(ctx)}},
+	}
+	if query.CategoryID != nil {
+		filter = append(filter, map[string]interface{}{"term": map[string]interface{}{"category_id": query.CategoryID.String()}})
+	}
+
+	body := map[string]interface{}{
+		"from":             query.Offset,
+		"size":             query.Limit,
+		"track_total_hits": true,
+		"_source":          false,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": map[string]interface{}{
+					"multi_match": map[string]interface{}{
+						"query":     query.Text,
+						"fields":    []string{"name^3", "description"},
+						"fuzziness": "AUTO",
+					},
+				},
+				"filter": filter,
+			},
+		},
+	}
+
+	var response struct {
+		Hits struct {
+			Total struct {
+				Value int `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				ID string `json:"_id"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if _, err := c.do(ctx, http.MethodPost, "/"+c.alias+"/_search", body, &response); err != nil {
+		return nil, 0, fmt.Errorf("failed to search products: %w", err)
+	}
+
+	ids := make([]uuid.UUID, 0, len(response.Hits.Hits))
+	for _, hit := range response.Hits.Hits {
+		id, err := uuid.Parse(hit.ID)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, response.Hits.Total.Value, nil
+}
+
+func (c *OpenSearchClient) IndexProduct(ctx context.Context, doc *entity.ProductSearchDocument) error {
+	if _, err := c.do(ctx, http.MethodPut, "/"+c.alias+"/_doc/"+doc.ID.String(), doc, nil); err != nil {
+		return fmt.Errorf("failed to index product: %w", err)
+	}
+	return nil
+}
+
+func (c *OpenSearchClient) DeleteProduct(ctx context.Context, id uuid.UUID) error {
+	status, err := c.do(ctx, http.MethodDelete, "/"+c.alias+"/_doc/"+id.String(), nil, nil)
+	if err != nil && status != http.StatusNotFound {
+		return fmt.Errorf("failed to delete product from index: %w", err)
+	}
+	return nil
+}
+
+// CreateIndex создает индекс с именем <alias>_<время создания>
+func (c *OpenSearchClient) CreateIndex(ctx context.Context) (string, error) {
+	index := fmt.Sprintf("%s_%d", c.alias, c.now().UnixNano())
+	if _, err := c.do(ctx, http.MethodPut, "/"+index, json.RawMessage(productIndexMapping), nil); err != nil {
+		return "", fmt.Errorf("failed to create search index: %w", err)
+	}
+	return index, nil
+}
+
+// BulkIndex записывает пачку документов одним запросом _bulk
+func (c *OpenSearchClient) BulkIndex(ctx context.Context, index string, docs []*entity.ProductSearchDocument) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, doc := range docs {
+		action := map[string]interface{}{"index": map[string]string{"_index": index, "_id": doc.ID.String()}}
+		if err := enc.Encode(action); err != nil {
+			return fmt.Errorf("failed to encode bulk action: %w", err)
+		}
+		if err := enc.Encode(doc); err != nil {
+			return fmt.Errorf("failed to encode product document: %w", err)
+		}
+	}
+
+	var response struct {
+		Errors bool `json:"errors"`
+	}
+	if _, err := c.do(ctx, http.MethodPost, "/_bulk", body.Bytes(), &response); err != nil {
+		return fmt.Errorf("failed to bulk index products: %w", err)
+	}
+	if response.Errors {
+		return fmt.Errorf("failed to bulk index products: some documents were rejected")
+	}
+	return nil
+}
+
+// SwitchAlias атомарно переводит alias на index и удаляет индексы, на которые он указывал
+func (c *OpenSearchClient) SwitchAlias(ctx context.Context, index string) error {
+	var current map[string]json.RawMessage
+	status, err := c.do(ctx, http.MethodGet, "/_alias/"+c.alias, nil, &current)
+	if err != nil && status != http.StatusNotFound {
+		return fmt.Errorf("failed to get search alias: %w", err)
+	}
+
+	actions := []interface{}{
+		map[string]interface{}{"add": map[string]string{"index": index, "alias": c.alias}},
+	}
+	for old := range current {
+		if old != index {
+			actions = append(actions, map[string]interface{}{"remove_index": map[string]string{"index": old}})
+		}
+	}
+
+	if _, err := c.do(ctx, http.MethodPost, "/_aliases", map[string]interface{}{"actions": actions}, nil); err != nil {
+		return fmt.Errorf("failed to switch search alias: %w", err)
+	}
+	return nil
+}
+
+// do выполняет запрос к OpenSearch и разбирает JSON ответ в out
+// Возвращает HTTP статус; статус 300 и выше - ошибка
+func (c *OpenSearchClient) do(ctx context.Context, method, path string, body, out interface{}) (int, error) {
+	var reader io.Reader
+	contentType := "application/json"
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		// Тело _bulk уже в формате NDJSON
+		reader = bytes.NewReader(b)
+		contentType = "application/x-ndjson"
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return 0, err
+	}
+	if reader != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return resp.StatusCode, fmt.Errorf("opensearch %s %s: status %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	if out != nil && method != http.MethodHead {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
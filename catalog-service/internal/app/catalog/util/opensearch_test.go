@@ -0,0 +1,67 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOpenSearch(t *testing.T, handler http.HandlerFunc) *OpenSearchClient {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewOpenSearchClient(OpenSearchConfig{URL: server.URL, Alias: "products", Timeout: time.Second})
+}
+
+// ==================== OpenSearch Tests ====================
+
+func TestOpenSearchClient_Search_FiltersByTenant(t *testing.T) {
+	// Arrange
+	productID := uuid.New()
+	var body map[string]interface{}
+	client := newTestOpenSearch(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/products/_search", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Write([]byte(`{"hits":{"total":{"value":7},"hits":[{"_id":"` + productID.String() + `"}]}}`))
+	})
+
+	// Act
+	ids, total, err := client.Search(tenant.WithID(context.Background(), "shop-a"), entity.ProductSearchQuery{Text: "клубника", Limit: 20})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 7, total)
+	assert.Equal(t, []uuid.UUID{productID}, ids)
+	filter := body["query"].(map[string]interface{})["bool"].(map[string]interface{})["filter"].([]interface{})
+	assert.Equal(t, map[string]interface{}{"term": map[string]interface{}{"tenant_id": "shop-a"}}, filter[0])
+}
+
+func TestOpenSearchClient_DeleteProduct_IgnoresMissingDocument(t *testing.T) {
+	client := newTestOpenSearch(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	err := client.DeleteProduct(context.Background(), uuid.New())
+
+	assert.NoError(t, err)
+}
+
+func TestOpenSearchClient_BulkIndex_ReportsRejectedDocuments(t *testing.T) {
+	client := newTestOpenSearch(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+		w.Write([]byte(`{"errors":true}`))
+	})
+
+	err := client.BulkIndex(context.Background(), "products_1", []*entity.ProductSearchDocument{{ID: uuid.New()}})
+
+	assert.Error(t, err)
+}
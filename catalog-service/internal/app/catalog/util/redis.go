@@ -7,23 +7,27 @@ import (
 	"time"
 
 	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/pkg/tenant"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
-const categoriesCacheKey = "categories:all"
+// Ключи кеша хранятся с префиксом магазина (см. tenant.CacheKey)
+const (
+	categoriesCacheKey = "categories:all"
+	brandsCacheKey     = "brands:all"
+	productCachePrefix = "products:"
+	rateCounterPrefix  = "ratelimit:"
+)
 
 type RedisClient struct {
-	client *redis.Client
+	client redis.UniversalClient
 }
 
-func NewRedisClient(addr, password string, db int) (*RedisClient, error) {
-	client := redis.NewClient(&redis.Options{
-		Addr:     addr,
-		Password: password,
-		DB:       db,
-	})
-
+// NewRedisClient оборачивает клиента Redis кешем каталога и проверяет соединение
+// Клиент создается через pkg/redis и может работать в режимах standalone, sentinel и cluster
+func NewRedisClient(client redis.UniversalClient) (*RedisClient, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -40,7 +44,7 @@ func (r *RedisClient) SetCategories(ctx context.Context, categories []entity.Cat
 		return fmt.Errorf("failed to marshal categories: %w", err)
 	}
 
-	if err := r.client.Set(ctx, categoriesCacheKey, data, ttl).Err(); err != nil {
+	if err := r.client.Set(ctx, tenant.CacheKey(ctx, categoriesCacheKey), data, ttl).Err(); err != nil {
 		return fmt.Errorf("failed to set categories in cache: %w", err)
 	}
 
@@ -48,7 +52,7 @@ func (r *RedisClient) SetCategories(ctx context.Context, categories []entity.Cat
 }
 
 func (r *RedisClient) GetCategories(ctx context.Context) ([]entity.Category, error) {
-	data, err := r.client.Get(ctx, categoriesCacheKey).Bytes()
+	data, err := r.client.Get(ctx, tenant.CacheKey(ctx, categoriesCacheKey)).Bytes()
 	if err != nil {
 		if err == redis.Nil {
 			return nil, nil
@@ -65,12 +69,105 @@ func (r *RedisClient) GetCategories(ctx context.Context) ([]entity.Category, err
 }
 
 func (r *RedisClient) DeleteCategories(ctx context.Context) error {
-	if err := r.client.Del(ctx, categoriesCacheKey).Err(); err != nil {
+	if err := r.client.Del(ctx, tenant.CacheKey(ctx, categoriesCacheKey)).Err(); err != nil {
 		return fmt.Errorf("failed to delete categories from cache: %w", err)
 	}
 	return nil
 }
 
+func (r *RedisClient) SetBrands(ctx context.Context, brands []entity.Brand, ttl time.Duration) error {
+	data, err := json.Marshal(brands)
+	if err != nil {
+		return fmt.Errorf("failed to marshal brands: %w", err)
+	}
+
+	if err := r.client.Set(ctx, tenant.CacheKey(ctx, brandsCacheKey), data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set brands in cache: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RedisClient) GetBrands(ctx context.Context) ([]entity.Brand, error) {
+	data, err := r.client.Get(ctx, tenant.CacheKey(ctx, brandsCacheKey)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get brands from cache: %w", err)
+	}
+
+	var brands []entity.Brand
+	if err := json.Unmarshal(data, &brands); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal brands: %w", err)
+	}
+
+	return brands, nil
+}
+
+func (r *RedisClient) DeleteBrands(ctx context.Context) error {
+	if err := r.client.Del(ctx, tenant.CacheKey(ctx, brandsCacheKey)).Err(); err != nil {
+		return fmt.Errorf("failed to delete brands from cache: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisClient) SetProduct(ctx context.Context, product *entity.ProductWithCategory, ttl time.Duration) error {
+	data, err := json.Marshal(product)
+	if err != nil {
+		return fmt.Errorf("failed to marshal product: %w", err)
+	}
+
+	if err := r.client.Set(ctx, productCacheKey(ctx, product.ID), data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set product in cache: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RedisClient) GetProduct(ctx context.Context, id uuid.UUID) (*entity.ProductWithCategory, error) {
+	data, err := r.client.Get(ctx, productCacheKey(ctx, id)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get product from cache: %w", err)
+	}
+
+	var product entity.ProductWithCategory
+	if err := json.Unmarshal(data, &product); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal product: %w", err)
+	}
+
+	return &product, nil
+}
+
+func (r *RedisClient) DeleteProduct(ctx context.Context, id uuid.UUID) error {
+	if err := r.client.Del(ctx, productCacheKey(ctx, id)).Err(); err != nil {
+		return fmt.Errorf("failed to delete product from cache: %w", err)
+	}
+	return nil
+}
+
+func productCacheKey(ctx context.Context, id uuid.UUID) string {
+	return tenant.CacheKey(ctx, productCachePrefix+id.String())
+}
+
+// Increment считает запрос в текущем окне: ключ окна содержит его номер и удаляется после окончания окна
+func (r *RedisClient) Increment(ctx context.Context, key string, window time.Duration) (int64, error) {
+	bucket := time.Now().UnixNano() / int64(window)
+	redisKey := tenant.CacheKey(ctx, fmt.Sprintf("%s%s:%d", rateCounterPrefix, key, bucket))
+
+	pipe := r.client.TxPipeline()
+	incr := pipe.Incr(ctx, redisKey)
+	pipe.Expire(ctx, redisKey, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to increment rate counter: %w", err)
+	}
+
+	return incr.Val(), nil
+}
+
 func (r *RedisClient) Close() error {
 	return r.client.Close()
 }
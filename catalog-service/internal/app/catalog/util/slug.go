@@ -0,0 +1,54 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+)
+
+// maxSlugLength - ограничение длины slug (оставляет место для суффикса коллизии "-N")
+const maxSlugLength = 200
+
+// Транслитерация кириллицы для человекочитаемых URL
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "h", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "sch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya",
+}
+
+// Slugify формирует slug из названия: латиница в нижнем регистре, цифры и дефисы
+// "Беспроводная мышь Logitech MX" -> "besprovodnaya-mysh-logitech-mx"
+// Для названия без букв и цифр возвращает пустую строку
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+
+	for _, r := range strings.ToLower(name) {
+		var part string
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			part = string(r)
+		case translit[r] != "":
+			part = translit[r]
+		case r == 'ъ' || r == 'ь':
+			continue
+		default:
+			// Любые другие символы схлопываются в один дефис
+			dash = b.Len() > 0
+			continue
+		}
+
+		if dash {
+			b.WriteByte('-')
+			dash = false
+		}
+		b.WriteString(part)
+	}
+
+	slug := b.String()
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	return slug
+}
@@ -0,0 +1,33 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Gaming Laptop", "gaming-laptop"},
+		{"  Logitech MX Master 3S!  ", "logitech-mx-master-3s"},
+		{"Беспроводная мышь", "besprovodnaya-mysh"},
+		{"Объектив 50мм", "obektiv-50mm"},
+		{"C++ / C#", "c-c"},
+		{"!!!", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Slugify(tt.name), tt.name)
+	}
+}
+
+func TestSlugify_Truncates(t *testing.T) {
+	slug := Slugify(strings.Repeat("ab ", 200))
+
+	assert.LessOrEqual(t, len(slug), maxSlugLength)
+	assert.False(t, strings.HasSuffix(slug, "-"))
+}
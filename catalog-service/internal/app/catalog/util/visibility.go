@@ -0,0 +1,77 @@
+package util
+
+import "reflect"
+
+// Audience - кто получает ответ; определяет, какие поля сущностей в него попадают
+type Audience int
+
+const (
+	AudiencePublic Audience = iota // Анонимный посетитель или покупатель
+	AudienceStaff                  // manager и admin
+)
+
+// visibilityTag - тег поля, ограничивающий его видимость:
+//
+//	CostPrice *money.Amount `json:"cost_price,omitempty" visibility:"staff"`
+//
+// Поля без тега видны всем
+const visibilityTag = "visibility"
+
+// AudienceForRole возвращает аудиторию по роли из JWT; пустая роль - запрос без токена
+func AudienceForRole(role string) Audience {
+	switch role {
+	case "manager", "admin":
+		return AudienceStaff
+	default:
+		return AudiencePublic
+	}
+}
+
+// Redact обнуляет поля с тегом visibility:"staff", если аудитория их не видит
+// Обходит вложенные структуры, указатели, срезы и map; v изменяется на месте,
+// поэтому передается указатель или значение, содержащее указатели/срезы
+// Для полей с omitempty обнуление убирает их из JSON ответа
+func Redact(v interface{}, audience Audience) {
+	if audience == AudienceStaff || v == nil {
+		return
+	}
+	redact(reflect.ValueOf(v))
+}
+
+func redact(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			redact(v.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			redact(v.Index(i))
+		}
+	case reflect.Map:
+		// Значения map не адресуемы: копия очищается и записывается обратно
+		iter := v.MapRange()
+		for iter.Next() {
+			value := reflect.New(iter.Value().Type()).Elem()
+			value.Set(iter.Value())
+			redact(value)
+			v.SetMapIndex(iter.Key(), value)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fv := v.Field(i)
+			if field.Tag.Get(visibilityTag) == "staff" {
+				if fv.CanSet() {
+					fv.SetZero()
+				}
+				continue
+			}
+			redact(fv)
+		}
+	}
+}
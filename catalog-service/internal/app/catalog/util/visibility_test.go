@@ -0,0 +1,62 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type redactItem struct {
+	Name   string
+	Secret *int `visibility:"staff"`
+}
+
+type redactPage struct {
+	Items  []redactItem
+	ByName map[string]redactItem
+	Top    *redactItem
+	Note   string `visibility:"staff"`
+}
+
+func newRedactPage() *redactPage {
+	secret := 42
+	item := redactItem{Name: "a", Secret: &secret}
+	return &redactPage{
+		Items:  []redactItem{item, item},
+		ByName: map[string]redactItem{"a": item},
+		Top:    &item,
+		Note:   "internal",
+	}
+}
+
+func TestRedact_PublicStripsStaffFields(t *testing.T) {
+	page := newRedactPage()
+
+	Redact(page, AudiencePublic)
+
+	assert.Empty(t, page.Note)
+	assert.Nil(t, page.Top.Secret)
+	assert.Equal(t, "a", page.Top.Name)
+	for _, item := range page.Items {
+		assert.Nil(t, item.Secret)
+		assert.Equal(t, "a", item.Name)
+	}
+	assert.Nil(t, page.ByName["a"].Secret)
+}
+
+func TestRedact_StaffKeepsFields(t *testing.T) {
+	page := newRedactPage()
+
+	Redact(page, AudienceStaff)
+
+	assert.Equal(t, "internal", page.Note)
+	assert.Equal(t, 42, *page.Top.Secret)
+	assert.Equal(t, 42, *page.Items[0].Secret)
+}
+
+func TestAudienceForRole(t *testing.T) {
+	assert.Equal(t, AudienceStaff, AudienceForRole("admin"))
+	assert.Equal(t, AudienceStaff, AudienceForRole("manager"))
+	assert.Equal(t, AudiencePublic, AudienceForRole("user"))
+	assert.Equal(t, AudiencePublic, AudienceForRole(""))
+}
@@ -0,0 +1,25 @@
+-- Создание таблицы брендов
+CREATE TABLE IF NOT EXISTS brands (
+    id UUID PRIMARY KEY,
+    name TEXT UNIQUE NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Создание таблицы поставщиков
+CREATE TABLE IF NOT EXISTS suppliers (
+    id UUID PRIMARY KEY,
+    name TEXT UNIQUE NOT NULL,
+    contact_email TEXT NOT NULL DEFAULT '',
+    phone TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Связь товаров с брендом и поставщиком (необязательная)
+-- ON DELETE RESTRICT: бренд/поставщик с товарами удалить нельзя
+ALTER TABLE products ADD COLUMN IF NOT EXISTS brand_id UUID REFERENCES brands(id) ON DELETE RESTRICT;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS supplier_id UUID REFERENCES suppliers(id) ON DELETE RESTRICT;
+
+-- Индексы для фильтрации списка товаров по бренду и поставщику
+CREATE INDEX IF NOT EXISTS idx_products_brand_id ON products(brand_id);
+CREATE INDEX IF NOT EXISTS idx_products_supplier_id ON products(supplier_id);
@@ -0,0 +1,10 @@
+-- Статус жизненного цикла товара: draft -> published -> archived
+-- Существующие товары уже продаются, поэтому считаются опубликованными
+ALTER TABLE products ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published'
+    CHECK (status IN ('draft', 'published', 'archived'));
+
+-- Новые товары создаются черновиками
+ALTER TABLE products ALTER COLUMN status SET DEFAULT 'draft';
+
+-- Индекс для фильтрации опубликованных товаров
+CREATE INDEX IF NOT EXISTS idx_products_status ON products(status);
@@ -0,0 +1,18 @@
+-- Мультитенантность: каждый магазин видит только свои данные
+-- Существующие данные принадлежат магазину по умолчанию
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE brands ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE suppliers ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE products ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+
+-- Имена уникальны в пределах магазина, а не глобально
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_name_key;
+ALTER TABLE brands DROP CONSTRAINT IF EXISTS brands_name_key;
+ALTER TABLE suppliers DROP CONSTRAINT IF EXISTS suppliers_name_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_tenant_name ON categories(tenant_id, name);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_brands_tenant_name ON brands(tenant_id, name);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_suppliers_tenant_name ON suppliers(tenant_id, name);
+
+-- Все запросы к товарам фильтруются по магазину
+CREATE INDEX IF NOT EXISTS idx_products_tenant_id ON products(tenant_id);
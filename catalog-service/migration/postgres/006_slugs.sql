@@ -0,0 +1,49 @@
+-- Slug для SEO URL: /products/by-slug/:slug и /categories/by-slug/:slug
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS slug VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE products ADD COLUMN IF NOT EXISTS slug VARCHAR(255) NOT NULL DEFAULT '';
+
+-- Заполнение slug для существующих записей: латиница и цифры из названия, остальное - дефисы
+-- Названия без латиницы получают slug по типу сущности; дубликаты - суффикс -2, -3...
+WITH base AS (
+    SELECT id, tenant_id,
+           COALESCE(NULLIF(trim(both '-' from lower(regexp_replace(name, '[^a-zA-Z0-9]+', '-', 'g'))), ''), 'category') AS slug
+    FROM categories
+    WHERE slug = ''
+), numbered AS (
+    SELECT id, slug, row_number() OVER (PARTITION BY tenant_id, slug ORDER BY id) AS n FROM base
+)
+UPDATE categories c
+SET slug = CASE WHEN numbered.n = 1 THEN numbered.slug ELSE numbered.slug || '-' || numbered.n END
+FROM numbered
+WHERE c.id = numbered.id;
+
+WITH base AS (
+    SELECT id, tenant_id,
+           COALESCE(NULLIF(trim(both '-' from lower(regexp_replace(name, '[^a-zA-Z0-9]+', '-', 'g'))), ''), 'product') AS slug
+    FROM products
+    WHERE slug = ''
+), numbered AS (
+    SELECT id, slug, row_number() OVER (PARTITION BY tenant_id, slug ORDER BY created_at, id) AS n FROM base
+)
+UPDATE products p
+SET slug = CASE WHEN numbered.n = 1 THEN numbered.slug ELSE numbered.slug || '-' || numbered.n END
+FROM numbered
+WHERE p.id = numbered.id;
+
+ALTER TABLE categories ALTER COLUMN slug DROP DEFAULT;
+ALTER TABLE products ALTER COLUMN slug DROP DEFAULT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_tenant_slug ON categories(tenant_id, slug);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_tenant_slug ON products(tenant_id, slug);
+
+-- История slug: старые ссылки после переименования перенаправляются на актуальный URL
+CREATE TABLE IF NOT EXISTS slug_redirects (
+    id UUID PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL,
+    entity_type VARCHAR(20) NOT NULL CHECK (entity_type IN ('category', 'product')),
+    slug VARCHAR(255) NOT NULL,
+    entity_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_slug_redirects_lookup ON slug_redirects(tenant_id, entity_type, slug);
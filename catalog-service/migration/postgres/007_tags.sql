@@ -0,0 +1,22 @@
+-- Теги товаров для подборок ("sale", "new-arrivals")
+CREATE TABLE IF NOT EXISTS tags (
+    id UUID PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Название и slug уникальны в пределах магазина
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_tenant_name ON tags(tenant_id, name);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_tenant_slug ON tags(tenant_id, slug);
+
+-- Связь многие-ко-многим: при удалении товара или тега связь удаляется
+CREATE TABLE IF NOT EXISTS product_tags (
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (product_id, tag_id)
+);
+
+-- Индекс для фильтра списка товаров по тегам
+CREATE INDEX IF NOT EXISTS idx_product_tags_tag_id ON product_tags(tag_id);
@@ -0,0 +1,7 @@
+-- Средняя оценка и число отзывов товара из Reviews Service
+-- Денормализованы в каталог для фасетов и фильтров по рейтингу
+ALTER TABLE products ADD COLUMN IF NOT EXISTS rating_avg NUMERIC(3,2) NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS rating_count INTEGER NOT NULL DEFAULT 0;
+
+-- Фасеты считаются по опубликованным товарам магазина
+CREATE INDEX IF NOT EXISTS idx_products_tenant_status ON products(tenant_id, status);
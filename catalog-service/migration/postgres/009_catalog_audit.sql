@@ -0,0 +1,15 @@
+-- Журнал изменений товаров и категорий: кто, когда и какие поля изменил
+CREATE TABLE IF NOT EXISTS catalog_audit (
+    id UUID PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id UUID NOT NULL,
+    action VARCHAR(10) NOT NULL,
+    actor_id VARCHAR(64) NOT NULL DEFAULT '',
+    actor_email VARCHAR(255) NOT NULL DEFAULT '',
+    changes JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- История сущности запрашивается по магазину, типу и ID; без внешнего ключа, чтобы пережить удаление
+CREATE INDEX IF NOT EXISTS idx_catalog_audit_entity ON catalog_audit(tenant_id, entity_type, entity_id, created_at DESC);
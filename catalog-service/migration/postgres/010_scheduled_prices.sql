@@ -0,0 +1,20 @@
+-- Запланированные цены и окна распродаж
+-- previous_price заполняется при активации и возвращается товару по окончании окна
+CREATE TABLE IF NOT EXISTS scheduled_prices (
+    id UUID PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    price DECIMAL(10,2) NOT NULL,
+    previous_price DECIMAL(10,2),
+    effective_from TIMESTAMP NOT NULL,
+    effective_to TIMESTAMP,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (effective_to IS NULL OR effective_to > effective_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_prices_product_id ON scheduled_prices(product_id);
+
+-- Фоновая задача выбирает ожидающие и активные цены по времени
+CREATE INDEX IF NOT EXISTS idx_scheduled_prices_due ON scheduled_prices(status, effective_from, effective_to)
+    WHERE status IN ('pending', 'active');
@@ -0,0 +1,3 @@
+-- Остаток товара на складе для проверки доступности при оформлении заказа
+-- NULL - остаток не отслеживается, товар доступен в любом количестве
+ALTER TABLE products ADD COLUMN IF NOT EXISTS stock INTEGER CHECK (stock >= 0);
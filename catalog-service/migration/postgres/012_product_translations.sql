@@ -0,0 +1,13 @@
+-- Переводы названия и описания товаров для разных рынков
+-- Основной язык магазина хранится в самих товарах, здесь только переводы на другие языки
+CREATE TABLE IF NOT EXISTS product_translations (
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    locale VARCHAR(16) NOT NULL, -- BCP 47 в нижнем регистре: en, de, pt-br
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (product_id, locale)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_translations_tenant ON product_translations(tenant_id);
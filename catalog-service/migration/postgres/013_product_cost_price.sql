@@ -0,0 +1,3 @@
+-- Закупочная цена товара: внутреннее поле, видно только manager и admin
+-- NULL - закупочная цена не указана
+ALTER TABLE products ADD COLUMN IF NOT EXISTS cost_price DECIMAL(10,2) CHECK (cost_price >= 0);
@@ -0,0 +1,20 @@
+-- Квоты запросов (pkg/quota): индивидуальные квоты пользователей и API ключей
+-- Субъекты без записи получают квоты по умолчанию из QUOTA_DAILY и QUOTA_MONTHLY, 0 - без ограничения
+CREATE TABLE IF NOT EXISTS request_quotas (
+    subject VARCHAR(100) PRIMARY KEY, -- user:<id> или key:<хеш ключа>
+    daily BIGINT NOT NULL DEFAULT 0 CHECK (daily >= 0),
+    monthly BIGINT NOT NULL DEFAULT 0 CHECK (monthly >= 0),
+    note TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Расход квот: счетчики живут в Redis и периодически сохраняются сюда,
+-- чтобы после потери Redis учет продолжился с сохраненных значений
+CREATE TABLE IF NOT EXISTS request_quota_usage (
+    subject VARCHAR(100) NOT NULL,
+    period VARCHAR(10) NOT NULL CHECK (period IN ('daily', 'monthly')),
+    period_start DATE NOT NULL,
+    count BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (subject, period, period_start)
+);
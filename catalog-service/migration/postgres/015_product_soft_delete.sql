@@ -0,0 +1,5 @@
+-- Мягкое удаление товаров: на товар могут ссылаться заказы и отзывы, поэтому DELETE только архивирует его
+-- NULL - товар не удален; удаленный товар снят с продажи (status = 'archived') и скрыт из списков
+ALTER TABLE products ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_products_tenant_not_deleted ON products(tenant_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,42 @@
+-- Импорт каталога из фидов поставщиков (CSV/XML)
+-- Товар фида сопоставляется по артикулу поставщика: повторный импорт обновляет его, а не создает копию
+ALTER TABLE products ADD COLUMN IF NOT EXISTS supplier_sku VARCHAR(100);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_tenant_supplier_sku
+    ON products(tenant_id, supplier_id, supplier_sku) WHERE supplier_sku IS NOT NULL;
+
+-- Фиды поставщиков: адрес, формат и правила сопоставления полей
+CREATE TABLE IF NOT EXISTS supplier_feeds (
+    id UUID PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    supplier_id UUID NOT NULL REFERENCES suppliers(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    mapping JSONB NOT NULL,
+    category_id UUID NOT NULL REFERENCES categories(id) ON DELETE RESTRICT,
+    interval_minutes INTEGER NOT NULL DEFAULT 60,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    last_run_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_supplier_feeds_tenant ON supplier_feeds(tenant_id);
+
+-- Статистика запусков импорта: по одной записи на запуск
+CREATE TABLE IF NOT EXISTS supplier_feed_imports (
+    id UUID PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    feed_id UUID NOT NULL REFERENCES supplier_feeds(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    rows INTEGER NOT NULL DEFAULT 0,
+    created INTEGER NOT NULL DEFAULT 0,
+    updated INTEGER NOT NULL DEFAULT 0,
+    unchanged INTEGER NOT NULL DEFAULT 0,
+    skipped INTEGER NOT NULL DEFAULT 0,
+    errors JSONB NOT NULL DEFAULT '[]',
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_supplier_feed_imports_feed ON supplier_feed_imports(tenant_id, feed_id, started_at DESC);
@@ -0,0 +1,3 @@
+-- Изменения, сделанные сотрудником поддержки от имени пользователя (токен имперсонации)
+ALTER TABLE catalog_audit ADD COLUMN IF NOT EXISTS impersonator_id VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE catalog_audit ADD COLUMN IF NOT EXISTS impersonator_email VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,11 @@
+-- Число опубликованных неудаленных товаров категории для списка категорий
+-- Пересчитывается фоновой задачей каталога (CATEGORY_COUNTS_CRON)
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS product_count INTEGER NOT NULL DEFAULT 0;
+
+-- Начальное заполнение, дальше счетчики поддерживает задача
+UPDATE categories c SET product_count = (
+    SELECT COUNT(*) FROM products p
+    WHERE p.category_id = c.id AND p.tenant_id = c.tenant_id
+      AND p.status = 'published' AND p.deleted_at IS NULL
+);
+
@@ -0,0 +1,7 @@
+-- Единица измерения товара и шаг количества в заказе: весовой товар продается, например, по 0.5 кг
+-- Существующие товары штучные с шагом в одну штуку
+ALTER TABLE products ADD COLUMN IF NOT EXISTS unit VARCHAR(16) NOT NULL DEFAULT 'piece';
+ALTER TABLE products ADD COLUMN IF NOT EXISTS quantity_step DECIMAL(12,3) NOT NULL DEFAULT 1;
+
+ALTER TABLE products ADD CONSTRAINT chk_products_unit CHECK (unit IN ('piece', 'kg', 'liter'));
+ALTER TABLE products ADD CONSTRAINT chk_products_quantity_step CHECK (quantity_step > 0);
@@ -0,0 +1,5 @@
+-- Время последнего изменения товара: выгрузка каталога в хранилище аналитики забирает изменения по (updated_at, id)
+-- Существующие товары считаются измененными в момент миграции и попадут в первую выгрузку
+ALTER TABLE products ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS idx_products_tenant_updated ON products(tenant_id, updated_at, id);
@@ -0,0 +1,9 @@
+-- Ограничения количества товара в заказе: минимум и максимум в одном заказе и лимит на покупателя по всем его заказам
+-- NULL - ограничения нет; существующие товары без ограничений
+ALTER TABLE products ADD COLUMN IF NOT EXISTS min_qty DECIMAL(12,3);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS max_qty DECIMAL(12,3);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS per_customer_limit DECIMAL(12,3);
+
+ALTER TABLE products ADD CONSTRAINT chk_products_min_qty CHECK (min_qty IS NULL OR min_qty > 0);
+ALTER TABLE products ADD CONSTRAINT chk_products_max_qty CHECK (max_qty IS NULL OR max_qty >= COALESCE(min_qty, 0));
+ALTER TABLE products ADD CONSTRAINT chk_products_per_customer_limit CHECK (per_customer_limit IS NULL OR per_customer_limit > 0);
@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"augustberries/catalog-service/internal/app/catalog/entity"
+	"augustberries/pkg/money"
+	"augustberries/pkg/patch"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -83,7 +85,7 @@ func TestFullCatalogFlow(t *testing.T) {
 	createProductReq := entity.CreateProductRequest{
 		Name:        productName,
 		Description: "This is a test product created by E2E tests",
-		Price:       99.99,
+		Price:       money.MustParse("99.99"),
 		CategoryID:  categoryID,
 	}
 	productBody, _ := json.Marshal(createProductReq)
@@ -102,11 +104,11 @@ func TestFullCatalogFlow(t *testing.T) {
 	err = json.NewDecoder(resp.Body).Decode(&product)
 	require.NoError(t, err)
 	assert.Equal(t, productName, product.Name)
-	assert.Equal(t, 99.99, product.Price)
+	assert.Equal(t, money.MustParse("99.99"), product.Price)
 	assert.Equal(t, categoryID, product.CategoryID)
 
 	productID := product.ID
-	t.Logf("Created product: %s (ID: %s, Price: %.2f)", product.Name, productID, product.Price)
+	t.Logf("Created product: %s (ID: %s, Price: %.2f)", product.Name, productID, product.Price.Float64())
 
 	// ==================== Step 4: Get Product with Category ====================
 	t.Log("Step 4: Getting product with category info")
@@ -128,9 +130,9 @@ func TestFullCatalogFlow(t *testing.T) {
 	// ==================== Step 5: Update Product Price ====================
 	t.Log("Step 5: Updating product price (triggers Kafka event)")
 
-	newPrice := 149.99
+	newPrice := money.MustParse("149.99")
 	updateProductReq := entity.UpdateProductRequest{
-		Price: newPrice,
+		Price: patch.Of(newPrice),
 	}
 	updateBody, _ := json.Marshal(updateProductReq)
 
@@ -148,7 +150,7 @@ func TestFullCatalogFlow(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, newPrice, updatedProduct.Price)
 
-	t.Logf("Updated product price: %.2f -> %.2f", 99.99, newPrice)
+	t.Logf("Updated product price: %.2f -> %.2f", 99.99, newPrice.Float64())
 
 	// ==================== Step 6: Delete Product ====================
 	t.Log("Step 6: Deleting product")
@@ -242,7 +244,7 @@ func TestProductValidation(t *testing.T) {
 			request: entity.CreateProductRequest{
 				Name:        "",
 				Description: "Valid description here",
-				Price:       99.99,
+				Price:       money.MustParse("99.99"),
 				CategoryID:  uuid.New(),
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -262,7 +264,7 @@ func TestProductValidation(t *testing.T) {
 			request: entity.CreateProductRequest{
 				Name:        "Valid Name",
 				Description: "Valid description here",
-				Price:       -10.0,
+				Price:       money.MustParse("-10.00"),
 				CategoryID:  uuid.New(),
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -272,7 +274,7 @@ func TestProductValidation(t *testing.T) {
 			request: entity.CreateProductRequest{
 				Name:        "Valid Name",
 				Description: "Valid description here",
-				Price:       99.99,
+				Price:       money.MustParse("99.99"),
 				CategoryID:  uuid.New(),
 			},
 			expectedStatus: http.StatusBadRequest,
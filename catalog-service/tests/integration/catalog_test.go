@@ -16,6 +16,8 @@ import (
 	"augustberries/catalog-service/internal/app/catalog/repository"
 	"augustberries/catalog-service/internal/app/catalog/service"
 	"augustberries/catalog-service/internal/app/catalog/util"
+	"augustberries/pkg/money"
+	"augustberries/pkg/patch"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -54,15 +56,17 @@ func (s *CatalogIntegrationTestSuite) SetupSuite() {
 	// Инициализируем репозитории
 	categoryRepo := repository.NewCategoryRepository(s.db)
 	productRepo := repository.NewProductRepository(s.db)
+	brandRepo := repository.NewBrandRepository(s.db)
+	supplierRepo := repository.NewSupplierRepository(s.db)
 
 	// Создаем mock Kafka producer для тестов (не отправляет реальные сообщения)
 	kafkaProducer := &mockKafkaProducer{}
 
 	// Инициализируем сервис
-	catalogService := service.NewCatalogService(categoryRepo, productRepo, s.redisClient, kafkaProducer)
+	catalogService := service.NewCatalogService(categoryRepo, productRepo, brandRepo, supplierRepo, s.redisClient, kafkaProducer, nil, nil)
 
 	// Инициализируем handler
-	catalogHandler := handler.NewCatalogHandler(catalogService)
+	catalogHandler := handler.NewCatalogHandler(catalogService, nil)
 
 	// Настраиваем router
 	s.router = gin.New()
@@ -81,13 +85,17 @@ func (s *CatalogIntegrationTestSuite) SetupSuite() {
 	}
 
 	// Products routes
+	// Тесты работают от имени admin, чтобы видеть товары во всех статусах (draft, archived)
 	products := s.router.Group("/products")
+	products.Use(func(c *gin.Context) { c.Set("role_name", "admin") })
 	{
 		products.POST("", catalogHandler.CreateProduct)
 		products.GET("", catalogHandler.GetAllProducts)
 		products.GET("/:id", catalogHandler.GetProduct)
 		products.PUT("/:id", catalogHandler.UpdateProduct)
 		products.DELETE("/:id", catalogHandler.DeleteProduct)
+		products.POST("/:id/publish", catalogHandler.PublishProduct)
+		products.POST("/:id/archive", catalogHandler.ArchiveProduct)
 	}
 }
 
@@ -264,7 +272,7 @@ func (s *CatalogIntegrationTestSuite) TestCreateProduct_Success() {
 	reqBody := entity.CreateProductRequest{
 		Name:        "Laptop",
 		Description: "High-performance laptop for developers",
-		Price:       1299.99,
+		Price:       money.MustParse("1299.99"),
 		CategoryID:  category.ID,
 	}
 	body, _ := json.Marshal(reqBody)
@@ -292,7 +300,7 @@ func (s *CatalogIntegrationTestSuite) TestCreateProduct_CategoryNotFound() {
 	reqBody := entity.CreateProductRequest{
 		Name:        "Laptop",
 		Description: "Description here for validation",
-		Price:       999.99,
+		Price:       money.MustParse("999.99"),
 		CategoryID:  uuid.New(), // Несуществующая категория
 	}
 	body, _ := json.Marshal(reqBody)
@@ -314,8 +322,8 @@ func (s *CatalogIntegrationTestSuite) TestGetAllProducts_Success() {
 	s.db.Create(category)
 
 	products := []entity.Product{
-		{ID: uuid.New(), Name: "Laptop", Description: "Desc1", Price: 1299.99, CategoryID: category.ID, CreatedAt: time.Now()},
-		{ID: uuid.New(), Name: "Phone", Description: "Desc2", Price: 999.99, CategoryID: category.ID, CreatedAt: time.Now()},
+		{ID: uuid.New(), Name: "Laptop", Description: "Desc1", Price: money.MustParse("1299.99"), CategoryID: category.ID, CreatedAt: time.Now()},
+		{ID: uuid.New(), Name: "Phone", Description: "Desc2", Price: money.MustParse("999.99"), CategoryID: category.ID, CreatedAt: time.Now()},
 	}
 	for _, p := range products {
 		s.db.Create(&p)
@@ -345,7 +353,7 @@ func (s *CatalogIntegrationTestSuite) TestGetProduct_Success() {
 		ID:          uuid.New(),
 		Name:        "Laptop",
 		Description: "Description",
-		Price:       1299.99,
+		Price:       money.MustParse("1299.99"),
 		CategoryID:  category.ID,
 		CreatedAt:   time.Now(),
 	}
@@ -376,15 +384,15 @@ func (s *CatalogIntegrationTestSuite) TestUpdateProduct_Success() {
 		ID:          uuid.New(),
 		Name:        "Laptop",
 		Description: "Description",
-		Price:       1299.99,
+		Price:       money.MustParse("1299.99"),
 		CategoryID:  category.ID,
 		CreatedAt:   time.Now(),
 	}
 	s.db.Create(product)
 
 	reqBody := entity.UpdateProductRequest{
-		Name:  "Updated Laptop",
-		Price: 1399.99,
+		Name:  patch.Of("Updated Laptop"),
+		Price: patch.Of(money.MustParse("1399.99")),
 	}
 	body, _ := json.Marshal(reqBody)
 
@@ -414,7 +422,7 @@ func (s *CatalogIntegrationTestSuite) TestDeleteProduct_Success() {
 		ID:          uuid.New(),
 		Name:        "ToDelete",
 		Description: "Description",
-		Price:       99.99,
+		Price:       money.MustParse("99.99"),
 		CategoryID:  category.ID,
 		CreatedAt:   time.Now(),
 	}
@@ -2,7 +2,6 @@ package main
 
 import (
 	http2 "augustberries/orders-service/internal/app/orders/infrastructure/http"
-	"augustberries/orders-service/internal/app/orders/infrastructure/messaging"
 	"context"
 	"fmt"
 	"log"
@@ -20,6 +19,14 @@ import (
 	"augustberries/orders-service/internal/app/orders/handler"
 	"augustberries/orders-service/internal/app/orders/repository"
 	"augustberries/orders-service/internal/app/orders/service"
+	"augustberries/pkg/authcookie"
+	"augustberries/pkg/featureflags"
+	"augustberries/pkg/jwtclaims"
+	"augustberries/pkg/kafka"
+	"augustberries/pkg/money"
+	"augustberries/pkg/openapi"
+	"augustberries/pkg/quote"
+	"augustberries/pkg/recovery"
 )
 
 func main() {
@@ -30,6 +37,22 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Паники в обработчиках и фоновых задачах отправляются в Sentry, если задан SENTRY_DSN
+	if err := recovery.ConfigureFromEnv(); err != nil {
+		log.Fatalf("Failed to configure panic reporting: %v", err)
+	}
+
+	// Проверка запросов и ответов по OpenAPI спецификации, только для dev и staging (OPENAPI_VALIDATION)
+	contract, err := openapi.FromEnv("orders-service")
+	if err != nil {
+		log.Fatalf("Failed to configure OpenAPI validation: %v", err)
+	}
+
+	// Точность валют должна совпадать с background-worker, который конвертирует заказы
+	if err := money.Currencies.Configure(cfg.Currency.Precision); err != nil {
+		log.Fatalf("Failed to configure currency precision: %v", err)
+	}
+
 	// === ПОДКЛЮЧЕНИЕ К POSTGRESQL ===
 	// Используем GORM для работы с PostgreSQL
 	db, err := connectDB(cfg.Database)
@@ -40,49 +63,144 @@ func main() {
 
 	// === ИНИЦИАЛИЗАЦИЯ KAFKA PRODUCER ===
 	// Kafka producer отправляет события ORDER_CREATED, ORDER_UPDATED в топик order_events
-	kafkaProducer := messaging.NewKafkaProducer(cfg.Kafka.Brokers, cfg.Kafka.Topic)
+	kafkaProducer, err := kafka.NewProducer(kafka.ProducerConfig{
+		Brokers:     cfg.Kafka.Brokers,
+		Topic:       cfg.Kafka.Topic,
+		Service:     "orders-service",
+		Compression: cfg.Kafka.Compression,
+		BatchSize:   cfg.Kafka.BatchSize,
+		BatchBytes:  cfg.Kafka.BatchBytes,
+		Linger:      cfg.Kafka.Linger,
+		Acks:        cfg.Kafka.Acks,
+		Idempotent:  cfg.Kafka.Idempotent,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create Kafka producer: %v", err)
+	}
 	defer kafkaProducer.Close()
 	log.Println("Successfully initialized Kafka producer")
 
 	// === ИНИЦИАЛИЗАЦИЯ CATALOG CLIENT ===
 	// HTTP клиент для взаимодействия с Catalog Service
-	catalogClient := http2.NewCatalogClient(cfg.CatalogService.URL)
+	// Ответы каталога кешируются в памяти на CATALOG_CACHE_TTL (LRU на CATALOG_CACHE_SIZE товаров)
+	catalogClient := http2.NewCatalogClient(cfg.CatalogService.URL, http2.CacheConfig{
+		Size: cfg.CatalogService.CacheSize,
+		TTL:  cfg.CatalogService.CacheTTL,
+	})
 	log.Println("Initialized Catalog Service client")
 
 	// === ИНИЦИАЛИЗАЦИЯ СЛОЯ РЕПОЗИТОРИЕВ ===
 	// Репозитории отвечают за работу с PostgreSQL
 	orderRepo := repository.NewOrderRepository(db)
 	orderItemRepo := repository.NewOrderItemRepository(db)
+	shipmentRepo := repository.NewShipmentRepository(db)
+	noteRepo := repository.NewOrderNoteRepository(db)
+	// Проекция для списков заказов: итоги по позициям без соединения orders с order_items
+	summaryRepo := repository.NewOrderSummaryRepository(db)
+	taxRateRepo := repository.NewTaxRateRepository(db)
+	orderNumberRepo := repository.NewOrderNumberRepository(db)
 
 	// === ИНИЦИАЛИЗАЦИЯ БИЗНЕС-ЛОГИКИ ===
 	// Service layer координирует работу репозиториев, Catalog Service и Kafka
+	// Налоги позиций считаются по ставкам магазина для страны доставки
+	taxEngine := service.NewTaxEngine(taxRateRepo, cfg.Tax.DefaultCountry)
 	orderService := service.NewOrderService(
 		orderRepo,
 		orderItemRepo,
 		catalogClient,
 		kafkaProducer,
+		quote.NewSigner(cfg.CatalogService.QuoteSecret), // Проверка котировок Catalog Service
+		taxEngine,
+		service.NewOrderNumberer(orderNumberRepo, cfg.OrderNumbers.Prefix), // Номера заказов по магазину и дню
 	)
+	// Валюта заказа без валюты в запросе и предпочитаемой валюты пользователя
+	orderService.SetDefaultCurrency(cfg.Currency.Default)
+	orderService.SetStatsCacheTTL(cfg.Stats.CacheTTL)
+
+	// Окно ожидаемой доставки: сборка заказа и сроки перевозчиков по регионам
+	leadTimes, err := service.ParseLeadTimes(cfg.Delivery.LeadTimes)
+	if err != nil {
+		log.Fatalf("Failed to configure delivery lead times: %v", err)
+	}
+	deliveryEstimator := service.NewDeliveryEstimator(service.DeliveryRules{
+		LeadTimes:      leadTimes,
+		ProcessingDays: cfg.Delivery.ProcessingDays,
+		CutoffHour:     cfg.Delivery.CutoffHour,
+	})
+	orderService.SetDeliveryEstimator(deliveryEstimator)
+	orderService.SetSummaries(summaryRepo)
+	// Предпросмотр заказа показывает итог в предпочитаемой валюте по курсам Background Worker Service
+	if cfg.WorkerService.URL != "" {
+		orderService.SetExchangeRates(http2.NewRatesClient(cfg.WorkerService.URL))
+	}
+
+	// === ЗАПУСК АКТИВАЦИИ ОТЛОЖЕННЫХ ЗАКАЗОВ ===
+	// Фоновая задача переводит наступившие отложенные заказы в pending и отправляет ORDER_CREATED
+	orderScheduler := service.NewOrderScheduler(orderService)
+	if err := orderScheduler.Start(context.Background(), cfg.Scheduled.Cron); err != nil {
+		log.Fatalf("Failed to start order scheduler: %v", err)
+	}
+	defer orderScheduler.Stop()
+
+	// Отправления: частичная отгрузка заказа, статус заказа выводится из отправлений
+	shipmentService := service.NewShipmentService(orderRepo, shipmentRepo, kafkaProducer)
+	shipmentService.SetDeliveryEstimator(deliveryEstimator)
+	shipmentService.SetSummaries(summaryRepo)
+	// Заметки поддержки к заказам
+	noteService := service.NewNoteService(orderRepo, noteRepo)
+	noteService.SetSummaries(summaryRepo)
+
+	// === ЗАПУСК ПОЛИТИКИ ХРАНЕНИЯ ЗАКАЗОВ ===
+	// Завершенные заказы старше ORDER_RETENTION_DAYS обезличиваются или удаляются, кроме заказов на legal hold
+	retentionService := service.NewRetentionService(repository.NewRetentionRepository(db), service.RetentionPolicy{
+		PeriodDays: cfg.Retention.Days,
+		Mode:       service.RetentionMode(cfg.Retention.Mode),
+		Schedule:   cfg.Retention.Cron,
+		BatchSize:  cfg.Retention.BatchSize,
+	})
+	if cfg.Retention.Days > 0 {
+		retentionScheduler := service.NewRetentionScheduler(retentionService)
+		if err := retentionScheduler.Start(context.Background()); err != nil {
+			log.Fatalf("Failed to start order retention scheduler: %v", err)
+		}
+		defer retentionScheduler.Stop()
+	}
 
 	// === ИНИЦИАЛИЗАЦИЯ AUTH MIDDLEWARE ===
 	// Middleware проверяет JWT токены для защиты API эндпоинтов
 	// JWT Secret должен совпадать с Auth Service
 	authMiddleware := handler.NewAuthMiddleware(cfg.JWT.Secret)
+	authMiddleware.SetExpectedClaims(jwtclaims.Expected{Issuer: cfg.JWT.Issuer, Audience: cfg.JWT.Audience})
+	// Токен из httpOnly cookie для SPA (AUTH_COOKIES); изменяющие запросы с cookie требуют X-CSRF-Token
+	cookies, err := authcookie.FromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure auth cookies: %v", err)
+	}
+	authMiddleware.SetCookies(cookies)
 	log.Println("Initialized Auth middleware")
 
+	// === ИНИЦИАЛИЗАЦИЯ FEATURE FLAGS ===
+	// Флаги хранятся в таблице feature_flags и кешируются в памяти процесса
+	flags := featureflags.NewClient(featureflags.NewPostgresStore(db), cfg.FeatureFlags.CacheTTL)
+
 	// === ИНИЦИАЛИЗАЦИЯ HTTP HANDLERS ===
 	// Handler обрабатывает HTTP запросы и вызывает методы service
-	orderHandler := handler.NewOrderHandler(orderService)
+	orderHandler := handler.NewOrderHandler(orderService, flags)
+	shipmentHandler := handler.NewShipmentHandler(shipmentService)
+	noteHandler := handler.NewNoteHandler(noteService)
+	taxHandler := handler.NewTaxHandler(taxEngine)
+	retentionHandler := handler.NewRetentionHandler(retentionService)
 
 	// === НАСТРОЙКА МАРШРУТОВ ===
 	// Настраиваем REST API endpoints согласно заданию с использованием Gin
 	// Применяем Auth middleware для защиты эндпоинтов
-	router := handler.SetupRoutes(orderHandler, authMiddleware)
+	router := handler.SetupRoutes(orderHandler, shipmentHandler, noteHandler, taxHandler, retentionHandler, featureflags.NewHandler(flags), authMiddleware, cfg.CORS, cfg.Compression)
 
 	// === НАСТРОЙКА HTTP СЕРВЕРА ===
 	// Production-ready настройки с таймаутами
 	server := &http.Server{
 		Addr:         cfg.Server.Address(),
-		Handler:      router,
+		Handler:      contract.Wrap(router),
 		ReadTimeout:  15 * time.Second, // Таймаут чтения запроса
 		WriteTimeout: 15 * time.Second, // Таймаут записи ответа
 		IdleTimeout:  60 * time.Second, // Таймаут idle соединений
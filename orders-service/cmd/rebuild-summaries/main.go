@@ -0,0 +1,55 @@
+// Команда rebuild-summaries пересчитывает проекцию order_summaries по таблицам orders и order_items
+// всех магазинов. Нужна после ручных правок заказов в базе, восстановления из бэкапа
+// или если обновление проекции при изменении заказа завершилось ошибкой
+//
+//	go run ./orders-service/cmd/rebuild-summaries -batch 1000
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"augustberries/orders-service/internal/app/orders/config"
+	"augustberries/orders-service/internal/app/orders/repository"
+)
+
+func main() {
+	batch := flag.Int("batch", 1000, "orders per refresh query")
+	flag.Parse()
+	if *batch <= 0 {
+		log.Fatalf("Invalid arguments: batch must be positive")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := connectDB(cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	started := time.Now()
+	rebuilt, err := repository.NewOrderSummaryRepository(db).Rebuild(context.Background(), *batch)
+	if err != nil {
+		log.Fatalf("Failed to rebuild order summaries after %d orders: %v", rebuilt, err)
+	}
+	log.Printf("Rebuilt order summaries: %d orders in %s", rebuilt, time.Since(started).Round(time.Millisecond))
+}
+
+// connectDB подключается к PostgreSQL заказов без логирования каждого SQL запроса
+func connectDB(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	dsn := fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+		cfg.Host, cfg.User, cfg.Password, cfg.DBName, cfg.Port, cfg.SSLMode,
+	)
+	return gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Warn)})
+}
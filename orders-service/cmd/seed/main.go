@@ -0,0 +1,127 @@
+// Команда seed создает историю синтетических заказов для нагрузочного тестирования
+// Заказы ссылаются на товары и пользователей, которые создают catalog-service/cmd/seed и
+// auth-service/cmd/seed с тем же seed; цены позиций совпадают с ценами товаров в каталоге
+//
+//	go run ./orders-service/cmd/seed -seed 42 -scale medium
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"augustberries/orders-service/internal/app/orders/config"
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/repository"
+	"augustberries/pkg/money"
+	"augustberries/pkg/seed"
+	"augustberries/pkg/tenant"
+	"augustberries/pkg/units"
+)
+
+func main() {
+	opts, err := seed.ParseFlags()
+	if err != nil {
+		log.Fatalf("Invalid arguments: %v", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := connectDB(cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	ctx := tenant.WithID(context.Background(), opts.Tenant)
+	gen := opts.Generator()
+	orderRepo := repository.NewOrderRepository(db)
+	calculator := money.NewOrderCalculator()
+
+	progress := seed.NewProgress("orders", gen.Scale().Orders)
+	for i := 0; i < gen.Scale().Orders; i++ {
+		o := gen.Order(i)
+		_, err := orderRepo.GetByID(ctx, o.ID)
+		if err == nil {
+			progress.Skipped()
+			continue
+		}
+		if !errors.Is(err, repository.ErrOrderNotFound) {
+			log.Fatalf("Failed to check order %s: %v", o.Number, err)
+		}
+
+		if err := createOrder(ctx, db, calculator, o); err != nil {
+			log.Fatalf("Failed to create order %s: %v", o.Number, err)
+		}
+		progress.Created()
+	}
+	progress.Done()
+}
+
+// createOrder сохраняет заказ с позициями и строкой списка order_summaries в одной транзакции
+// Итог пересчитывается тем же калькулятором, что и при оформлении заказа через API
+func createOrder(ctx context.Context, db *gorm.DB, calculator *money.OrderCalculator, o seed.Order) error {
+	lines := make([]money.Line, len(o.Items))
+	for i, item := range o.Items {
+		lines[i] = money.Line{UnitPrice: item.UnitPrice, Quantity: units.Of(item.Quantity)}
+	}
+	totals, err := calculator.Calculate(lines, o.DeliveryPrice, 0)
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		order := &entity.Order{
+			ID:            o.ID,
+			Number:        o.Number,
+			UserID:        o.UserID,
+			TotalPrice:    totals.Total,
+			DeliveryPrice: totals.Delivery,
+			Currency:      o.Currency,
+			Status:        entity.OrderStatus(o.Status),
+			CreatedAt:     o.CreatedAt,
+
+			SourceCurrency:      o.Currency,
+			SourceDeliveryPrice: totals.Delivery,
+		}
+		if err := repository.NewOrderRepository(tx).Create(ctx, order); err != nil {
+			return err
+		}
+
+		itemRepo := repository.NewOrderItemRepository(tx)
+		for i, item := range o.Items {
+			if err := itemRepo.Create(ctx, &entity.OrderItem{
+				ID:        item.ID,
+				OrderID:   o.ID,
+				ProductID: item.ProductID,
+				Quantity:  units.Of(item.Quantity),
+				UnitPrice: item.UnitPrice,
+				Position:  i,
+				Product: entity.ProductSnapshot{
+					Name:         item.Name,
+					Description:  item.Description,
+					CategoryName: item.CategoryName,
+				},
+			}); err != nil {
+				return err
+			}
+		}
+		return repository.NewOrderSummaryRepository(tx).Refresh(ctx, o.ID)
+	})
+}
+
+// connectDB подключается к PostgreSQL заказов без логирования каждого SQL запроса
+func connectDB(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	dsn := fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+		cfg.Host, cfg.User, cfg.Password, cfg.DBName, cfg.Port, cfg.SSLMode,
+	)
+	return gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Warn)})
+}
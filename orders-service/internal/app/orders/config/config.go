@@ -4,16 +4,30 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
+
+	"augustberries/pkg/httpmw"
 )
 
 // Config содержит все настройки приложения Orders Service
 // Включает конфигурацию для HTTP сервера, PostgreSQL, Kafka и JWT
 type Config struct {
 	Server         ServerConfig
+	CORS           httpmw.CORSConfig
+	Compression    httpmw.CompressConfig
 	Database       DatabaseConfig
 	Kafka          KafkaConfig
 	JWT            JWTConfig
 	CatalogService CatalogServiceConfig
+	WorkerService  WorkerServiceConfig
+	FeatureFlags   FeatureFlagsConfig
+	Tax            TaxConfig
+	OrderNumbers   OrderNumbersConfig
+	Currency       CurrencyConfig
+	Scheduled      ScheduledOrdersConfig
+	Stats          StatsConfig
+	Delivery       DeliveryConfig
+	Retention      RetentionConfig
 }
 
 // ServerConfig - настройки HTTP сервера
@@ -36,30 +50,192 @@ type DatabaseConfig struct {
 // KafkaConfig - настройки Kafka для отправки событий
 // События отправляются при создании/обновлении заказов
 type KafkaConfig struct {
-	Brokers []string // Список брокеров Kafka (формат: host:port)
-	Topic   string   // Топик для событий ORDER_CREATED, ORDER_UPDATED
+	Brokers     []string      // Список брокеров Kafka (формат: host:port)
+	Topic       string        // Топик для событий ORDER_CREATED, ORDER_UPDATED
+	Compression string        // Кодек сжатия: none, gzip, snappy, lz4, zstd
+	BatchSize   int           // Максимум сообщений в батче
+	BatchBytes  int64         // Максимальный размер батча в байтах
+	Linger      time.Duration // Время ожидания заполнения батча перед отправкой
+	Acks        string        // Уровень подтверждения записи: none, one, all
+	Idempotent  bool          // Идемпотентный режим (acks=all, порядок по ключу)
 }
 
 // JWTConfig - настройки для проверки JWT токенов
 // Используется для аутентификации запросов от пользователей
 type JWTConfig struct {
-	Secret string // Секретный ключ для проверки JWT токенов (должен совпадать с Auth Service)
+	Secret   string // Секретный ключ для проверки JWT токенов (должен совпадать с Auth Service)
+	Issuer   string // Ожидаемый iss (JWT_ISSUER Auth Service), пустой - не проверяется
+	Audience string // Ожидаемый aud (JWT_AUDIENCE Auth Service), пустой - не проверяется
 }
 
 // CatalogServiceConfig - настройки для обращения к Catalog Service
 // Используется для проверки цен товаров
 type CatalogServiceConfig struct {
-	URL string // URL Catalog Service для получения информации о товарах
+	URL         string // URL Catalog Service для получения информации о товарах
+	QuoteSecret string // Ключ проверки подписи ценовых котировок (должен совпадать с Catalog Service)
+
+	CacheSize int           // Максимум товаров в кеше ответов каталога в памяти процесса
+	CacheTTL  time.Duration // Время жизни записи кеша, 0 - кеш отключен
+}
+
+// WorkerServiceConfig - настройки для обращения к Background Worker Service
+// Используется предпросмотром заказа для конвертации итога в предпочитаемую валюту покупателя
+type WorkerServiceConfig struct {
+	URL string // URL HTTP сервера worker, пустой - предпросмотр без конвертации
+}
+
+// FeatureFlagsConfig - настройки флагов функциональности (таблица feature_flags)
+type FeatureFlagsConfig struct {
+	CacheTTL time.Duration // Время кеширования флага в памяти процесса
+}
+
+// TaxConfig - настройки расчета налогов
+type TaxConfig struct {
+	DefaultCountry string // Страна для заказов без страны доставки; пусто - такие заказы без налога
+}
+
+// OrderNumbersConfig - настройки человекочитаемых номеров заказов
+type OrderNumbersConfig struct {
+	Prefix string // Префикс номера (AB-20240115-000123)
+}
+
+// CurrencyConfig - настройки валют
+type CurrencyConfig struct {
+	Precision string // Точность и округление поверх встроенного реестра pkg/money ("JPY:0:half_even,RUB:2")
+	Default   string // Валюта заказа, если ее нет в запросе и у пользователя не задана предпочитаемая
+}
+
+// ScheduledOrdersConfig - настройки активации отложенных заказов
+type ScheduledOrdersConfig struct {
+	Cron string // Расписание проверки наступивших заказов (формат robfig/cron)
+}
+
+// StatsConfig - настройки статистики заказов пользователя (GET /orders/stats)
+type StatsConfig struct {
+	CacheTTL time.Duration // Время кеширования статистики в памяти процесса, 0 - без кеша
+}
+
+// DeliveryConfig - настройки расчета ожидаемой доставки
+type DeliveryConfig struct {
+	LeadTimes      string // Сроки перевозчиков по регионам ("RU:2-5,dhl/DE:2-3,*:7-14"), пусто - без оценки
+	ProcessingDays int    // Рабочих дней на сборку заказа до отгрузки
+	CutoffHour     int    // Час UTC, после которого сборка начинается на следующий рабочий день; 0 - без отсечки
+}
+
+// RetentionConfig - срок хранения завершенных (delivered, cancelled) заказов
+type RetentionConfig struct {
+	Days      int    // Срок хранения от оформления заказа в днях; 0 - заказы хранятся бессрочно
+	Mode      string // anonymize - удалить персональные данные, purge - удалить заказ целиком
+	Cron      string // Расписание фоновой задачи (формат robfig/cron)
+	BatchSize int    // Заказов за один запрос к базе
 }
 
 // Load загружает конфигурацию из переменных окружения
 // Возвращает ошибку, если не удалось распарсить значения
 func Load() (*Config, error) {
+	// Настройки Kafka producer: по умолчанию snappy, небольшие батчи и подтверждение всеми репликами
+	kafkaBatchSize, err := strconv.Atoi(getEnv("KAFKA_BATCH_SIZE", "100"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAFKA_BATCH_SIZE value: %w", err)
+	}
+
+	kafkaBatchBytes, err := strconv.ParseInt(getEnv("KAFKA_BATCH_BYTES", "1048576"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAFKA_BATCH_BYTES value: %w", err)
+	}
+
+	kafkaLinger, err := time.ParseDuration(getEnv("KAFKA_LINGER", "10ms"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAFKA_LINGER value: %w", err)
+	}
+
+	kafkaIdempotent, err := strconv.ParseBool(getEnv("KAFKA_IDEMPOTENT", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAFKA_IDEMPOTENT value: %w", err)
+	}
+
+	flagsCacheTTL, err := time.ParseDuration(getEnv("FEATURE_FLAGS_CACHE_TTL", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid FEATURE_FLAGS_CACHE_TTL value: %w", err)
+	}
+
+	catalogCacheTTL, err := time.ParseDuration(getEnv("CATALOG_CACHE_TTL", "5s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CATALOG_CACHE_TTL value: %w", err)
+	}
+
+	statsCacheTTL, err := time.ParseDuration(getEnv("ORDER_STATS_CACHE_TTL", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ORDER_STATS_CACHE_TTL value: %w", err)
+	}
+
+	// CORS для браузерных клиентов; credentials нужны SPA с токенами в cookie и требуют явного списка origin
+	corsMaxAge, err := time.ParseDuration(getEnv("CORS_MAX_AGE", "5m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CORS_MAX_AGE value: %w", err)
+	}
+
+	corsCredentials, err := strconv.ParseBool(getEnv("CORS_ALLOW_CREDENTIALS", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CORS_ALLOW_CREDENTIALS value: %w", err)
+	}
+
+	cors := httpmw.CORSConfig{
+		AllowedOrigins:   httpmw.ParseList(getEnv("CORS_ALLOWED_ORIGINS", "*")),
+		AllowedMethods:   httpmw.ParseList(getEnv("CORS_ALLOWED_METHODS", "")),
+		AllowedHeaders:   httpmw.ParseList(getEnv("CORS_ALLOWED_HEADERS", "")),
+		ExposedHeaders:   httpmw.ParseList(getEnv("CORS_EXPOSED_HEADERS", "")),
+		AllowCredentials: corsCredentials,
+		MaxAge:           corsMaxAge,
+	}
+	if err := cors.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Сжатие ответов больших списков (gzip, zstd) по Accept-Encoding
+	compressionEnabled, err := strconv.ParseBool(getEnv("RESPONSE_COMPRESSION", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RESPONSE_COMPRESSION value: %w", err)
+	}
+
+	compressionMinSize, err := strconv.Atoi(getEnv("RESPONSE_COMPRESSION_MIN_SIZE", strconv.Itoa(httpmw.DefaultMinSize)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RESPONSE_COMPRESSION_MIN_SIZE value: %w", err)
+	}
+
+	compression := httpmw.CompressConfig{
+		Enabled:      compressionEnabled,
+		Encodings:    httpmw.ParseList(getEnv("RESPONSE_COMPRESSION_ENCODINGS", "zstd,gzip")),
+		MinSize:      compressionMinSize,
+		ContentTypes: httpmw.ParseList(getEnv("RESPONSE_COMPRESSION_CONTENT_TYPES", "")),
+	}
+	if err := compression.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Срок хранения завершенных заказов: по умолчанию бессрочно
+	retentionDays, err := strconv.Atoi(getEnv("ORDER_RETENTION_DAYS", "0"))
+	if err != nil || retentionDays < 0 {
+		return nil, fmt.Errorf("invalid ORDER_RETENTION_DAYS value: %q", getEnv("ORDER_RETENTION_DAYS", "0"))
+	}
+
+	retentionMode := getEnv("ORDER_RETENTION_MODE", "anonymize")
+	if retentionMode != "anonymize" && retentionMode != "purge" {
+		return nil, fmt.Errorf("invalid ORDER_RETENTION_MODE value: %q", retentionMode)
+	}
+
+	retentionBatch, err := strconv.Atoi(getEnv("ORDER_RETENTION_BATCH_SIZE", "500"))
+	if err != nil || retentionBatch <= 0 {
+		return nil, fmt.Errorf("invalid ORDER_RETENTION_BATCH_SIZE value: %q", getEnv("ORDER_RETENTION_BATCH_SIZE", "500"))
+	}
+
 	return &Config{
 		Server: ServerConfig{
 			Host: getEnv("SERVER_HOST", "0.0.0.0"),
 			Port: getEnv("SERVER_PORT", "8082"),
 		},
+		CORS:        cors,
+		Compression: compression,
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
 			Port:     getEnv("DB_PORT", "5432"),
@@ -69,15 +245,59 @@ func Load() (*Config, error) {
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		},
 		Kafka: KafkaConfig{
-			Brokers: []string{getEnv("KAFKA_BROKERS", "localhost:9092")},
-			Topic:   getEnv("KAFKA_TOPIC", "order_events"),
+			Brokers:     []string{getEnv("KAFKA_BROKERS", "localhost:9092")},
+			Topic:       getEnv("KAFKA_TOPIC", "order_events"),
+			Compression: getEnv("KAFKA_COMPRESSION", "snappy"),
+			BatchSize:   kafkaBatchSize,
+			BatchBytes:  kafkaBatchBytes,
+			Linger:      kafkaLinger,
+			Acks:        getEnv("KAFKA_ACKS", "all"),
+			Idempotent:  kafkaIdempotent,
 		},
 		JWT: JWTConfig{
 			// JWT Secret должен совпадать с Auth Service для валидации токенов
-			Secret: getEnv("JWT_SECRET", "your-secret-key-change-this-in-production"),
+			Secret:   getEnv("JWT_SECRET", "your-secret-key-change-this-in-production"),
+			Issuer:   getEnv("JWT_ISSUER", ""),
+			Audience: getEnv("JWT_AUDIENCE", ""),
 		},
 		CatalogService: CatalogServiceConfig{
-			URL: getEnv("CATALOG_SERVICE_URL", "http://localhost:8081"),
+			URL:         getEnv("CATALOG_SERVICE_URL", "http://localhost:8081"),
+			QuoteSecret: getEnv("PRICE_QUOTE_SECRET", "your-quote-secret-change-this-in-production"),
+			CacheSize:   getEnvInt("CATALOG_CACHE_SIZE", 1000),
+			CacheTTL:    catalogCacheTTL,
+		},
+		WorkerService: WorkerServiceConfig{
+			URL: getEnv("WORKER_SERVICE_URL", ""),
+		},
+		FeatureFlags: FeatureFlagsConfig{
+			CacheTTL: flagsCacheTTL,
+		},
+		Tax: TaxConfig{
+			DefaultCountry: getEnv("TAX_DEFAULT_COUNTRY", ""),
+		},
+		OrderNumbers: OrderNumbersConfig{
+			Prefix: getEnv("ORDER_NUMBER_PREFIX", "AB"),
+		},
+		Currency: CurrencyConfig{
+			Precision: getEnv("CURRENCY_PRECISION", ""),
+			Default:   getEnv("DEFAULT_CURRENCY", "RUB"),
+		},
+		Scheduled: ScheduledOrdersConfig{
+			Cron: getEnv("SCHEDULED_ORDERS_CRON", "@every 1m"),
+		},
+		Stats: StatsConfig{
+			CacheTTL: statsCacheTTL,
+		},
+		Delivery: DeliveryConfig{
+			LeadTimes:      getEnv("DELIVERY_LEAD_TIMES", "*:3-10"),
+			ProcessingDays: getEnvInt("DELIVERY_PROCESSING_DAYS", 1),
+			CutoffHour:     getEnvInt("DELIVERY_CUTOFF_HOUR", 14),
+		},
+		Retention: RetentionConfig{
+			Days:      retentionDays,
+			Mode:      retentionMode,
+			Cron:      getEnv("ORDER_RETENTION_CRON", "@daily"),
+			BatchSize: retentionBatch,
 		},
 	}, nil
 }
@@ -1,23 +1,174 @@
 package entity
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"augustberries/pkg/money"
+	"augustberries/pkg/pagination"
+	"augustberries/pkg/units"
+
+	"github.com/google/uuid"
+)
 
 // CreateOrderRequest - запрос на создание заказа
 type CreateOrderRequest struct {
 	Items         []OrderItemRequest `json:"items" validate:"required,min=1,dive"`
-	DeliveryPrice float64            `json:"delivery_price" validate:"gte=0"`
-	Currency      string             `json:"currency" validate:"required,oneof=USD EUR RUB"`
+	DeliveryPrice money.Amount       `json:"delivery_price" validate:"money"`
+	Currency      string             `json:"currency" validate:"omitempty,oneof=USD EUR RUB"`    // Без валюты - предпочитаемая валюта пользователя или валюта магазина
+	Country       string             `json:"country,omitempty" validate:"omitempty,len=2,alpha"` // Страна доставки; без нее - страна магазина по умолчанию
+	ExpectedTotal *money.Amount      `json:"expected_total,omitempty"`                           // Итог, который видел клиент; при расхождении заказ отклоняется
+	QuoteToken    string             `json:"quote_token,omitempty"`                              // Подписанная котировка POST /products/quotes; цены берутся из нее
+	ScheduledFor  *time.Time         `json:"scheduled_for,omitempty"`                            // Предзаказ: заказ активируется (ORDER_CREATED) в это время
+
+	// GuestEmail заполняется handler из гостевого токена
+	GuestEmail string `json:"-"`
+	// PreferredCurrency заполняется handler из JWT (preferred_currency)
+	PreferredCurrency string `json:"-"`
+}
+
+// GuestOrderLookupRequest - поиск гостевого заказа без аккаунта по ID или номеру заказа
+type GuestOrderLookupRequest struct {
+	OrderID string `form:"order_id" validate:"required_without=Number,omitempty,uuid"`
+	Number  string `form:"number" validate:"required_without=OrderID,omitempty,max=32"`
+	Email   string `form:"email" validate:"required,email"`
+}
+
+// LinkGuestOrdersResponse - результат привязки гостевых заказов
+type LinkGuestOrdersResponse struct {
+	Linked int64 `json:"linked"` // Сколько заказов перенесено на аккаунт
 }
 
 // OrderItemRequest - позиция заказа в запросе
 type OrderItemRequest struct {
-	ProductID uuid.UUID `json:"product_id" validate:"required"`
-	Quantity  int       `json:"quantity" validate:"required,gt=0"`
+	ProductID uuid.UUID      `json:"product_id" validate:"required"`
+	Quantity  units.Quantity `json:"quantity" validate:"required,gt=0"` // Дробное для весовых товаров, кратно шагу товара
+}
+
+// UpdateOrderItemsRequest - изменение количества позиций заказа до подтверждения (PATCH /orders/:id/items)
+// Позиции, которых нет в запросе, не меняются
+type UpdateOrderItemsRequest struct {
+	Items []OrderItemQuantity `json:"items" validate:"required,min=1,unique=ItemID,dive"`
+}
+
+// OrderItemQuantity - новое количество позиции заказа
+type OrderItemQuantity struct {
+	ItemID   uuid.UUID      `json:"item_id" validate:"required"`
+	Quantity units.Quantity `json:"quantity" validate:"gte=0"` // 0 - удалить позицию
 }
 
 // UpdateOrderStatusRequest - запрос на обновление статуса заказа
 type UpdateOrderStatusRequest struct {
 	Status OrderStatus `json:"status" validate:"required,oneof=pending confirmed shipped delivered cancelled"`
+	Reason string      `json:"reason,omitempty" validate:"max=500"` // Причина, например причина отмены
+}
+
+// BulkOrderStatusRequest - запрос на массовую смену статуса заказов (POST /admin/orders/bulk-status)
+type BulkOrderStatusRequest struct {
+	OrderIDs []uuid.UUID `json:"order_ids" validate:"required,min=1,max=500,uuid_list"`
+	Status   OrderStatus `json:"status" validate:"required,oneof=pending confirmed shipped delivered cancelled"`
+	Reason   string      `json:"reason,omitempty" validate:"max=500"`
+}
+
+// BulkOrderStatusResult - результат смены статуса одного заказа
+// При ошибке статус заказа не меняется, Error содержит причину
+type BulkOrderStatusResult struct {
+	OrderID        uuid.UUID   `json:"order_id"`
+	Success        bool        `json:"success"`
+	PreviousStatus OrderStatus `json:"previous_status,omitempty"`
+	Status         OrderStatus `json:"status,omitempty"`
+	Error          string      `json:"error,omitempty"`
+}
+
+// BulkOrderStatusResponse - отчет о массовой смене статуса в порядке запроса
+type BulkOrderStatusResponse struct {
+	Results   []BulkOrderStatusResult `json:"results"`
+	Succeeded int                     `json:"succeeded"`
+	Failed    int                     `json:"failed"`
+}
+
+// CreateShipmentRequest - запрос на отправку части заказа
+type CreateShipmentRequest struct {
+	TrackingNumber string                `json:"tracking_number" validate:"required,max=100"`
+	Carrier        string                `json:"carrier" validate:"required,max=100"`
+	Items          []ShipmentItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+// ShipmentItemRequest - сколько единиц позиции заказа уходит в отправлении
+type ShipmentItemRequest struct {
+	OrderItemID uuid.UUID      `json:"order_item_id" validate:"required"`
+	Quantity    units.Quantity `json:"quantity" validate:"required,gt=0"`
+}
+
+// UpdateShipmentStatusRequest - запрос на обновление статуса отправления
+type UpdateShipmentStatusRequest struct {
+	Status ShipmentStatus `json:"status" validate:"required,oneof=in_transit delivered"`
+}
+
+// ShipmentsResponse - отправления заказа и выведенный из них статус заказа
+type ShipmentsResponse struct {
+	OrderID     uuid.UUID   `json:"order_id"`
+	OrderStatus OrderStatus `json:"order_status"`
+	Shipments   []Shipment  `json:"shipments"`
+}
+
+// CreateOrderNoteRequest - запрос на добавление заметки к заказу
+// Покупатель может оставлять только заметки с видимостью customer
+type CreateOrderNoteRequest struct {
+	Body       string         `json:"body" validate:"required,max=5000"`
+	Visibility NoteVisibility `json:"visibility" validate:"omitempty,oneof=internal customer"` // По умолчанию customer
+	TicketID   string         `json:"ticket_id,omitempty" validate:"omitempty,max=100"`
+}
+
+// UpsertTaxRateRequest - запрос на установку ставки налога страны или категории
+// Повторный запрос для той же страны и категории заменяет ставку
+type UpsertTaxRateRequest struct {
+	Country    string     `json:"country" validate:"required,len=2,alpha"`
+	CategoryID *uuid.UUID `json:"category_id,omitempty"`
+	Name       string     `json:"name" validate:"required,max=100"`
+	Rate       float64    `json:"rate" validate:"gte=0,lte=1"`
+}
+
+// TaxRateListResponse - ставки налогов магазина
+type TaxRateListResponse struct {
+	TaxRates []TaxRate `json:"tax_rates"`
+	Total    int       `json:"total"`
+}
+
+// OrderFilter - фильтр списка заказов магазина (admin)
+type OrderFilter struct {
+	Status OrderStatus // Пустой статус - заказы во всех статусах
+	UserID *uuid.UUID
+}
+
+// DashboardSummary - сводка заказов магазина для внутренней панели (GET /admin/summary)
+type DashboardSummary struct {
+	OrdersByStatus map[OrderStatus]int64 `json:"orders_by_status"`
+	RevenueToday   []CurrencyRevenue     `json:"revenue_today"` // Выручка с начала суток (UTC) по валютам, без отмененных заказов
+	Since          time.Time             `json:"since"`         // Начало суток, с которого считается выручка
+	GeneratedAt    time.Time             `json:"generated_at"`
+}
+
+// CurrencyRevenue - выручка и число заказов в одной валюте
+type CurrencyRevenue struct {
+	Currency string       `json:"currency"`
+	Total    money.Amount `json:"total"`
+	Orders   int64        `json:"orders"`
+}
+
+// UserOrderStats - статистика заказов пользователя для личного кабинета (GET /orders/stats)
+type UserOrderStats struct {
+	TotalOrders    int64                 `json:"total_orders"`
+	OrdersByStatus map[OrderStatus]int64 `json:"orders_by_status"`
+	TotalSpent     []CurrencyRevenue     `json:"total_spent"`    // По валютам, без отмененных и отложенных заказов
+	FirstOrderAt   *time.Time            `json:"first_order_at"` // nil - заказов нет
+	LastOrderAt    *time.Time            `json:"last_order_at"`
+	GeneratedAt    time.Time             `json:"generated_at"` // Время расчета: ответ кешируется на ORDER_STATS_CACHE_TTL
+}
+
+// AdminOrderSummary - заказ в admin списке с числом заметок поддержки
+type AdminOrderSummary struct {
+	OrderSummary
+	NotesCount int `json:"notes_count"`
 }
 
 // ErrorResponse - стандартный ответ об ошибке
@@ -32,24 +183,122 @@ type SuccessResponse struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// OrderListResponse - страница заказов пользователя
+type OrderListResponse struct {
+	Orders []OrderSummary `json:"orders"`
+	pagination.Meta
+}
+
 // OrderResponse - полный ответ с заказом
 type OrderResponse struct {
 	ID            uuid.UUID      `json:"id"`
+	Number        string         `json:"number,omitempty"`
 	UserID        uuid.UUID      `json:"user_id"`
-	TotalPrice    float64        `json:"total_price"`
-	DeliveryPrice float64        `json:"delivery_price"`
+	TotalPrice    money.Amount   `json:"total_price"`
+	DeliveryPrice money.Amount   `json:"delivery_price"`
+	TaxTotal      money.Amount   `json:"tax_total"`
 	Currency      string         `json:"currency"`
+	Country       string         `json:"country,omitempty"`
 	Status        OrderStatus    `json:"status"`
+	GuestEmail    *string        `json:"guest_email,omitempty"`
 	CreatedAt     string         `json:"created_at"`
 	Items         []ItemResponse `json:"items"`
+	TaxBreakdown  []TaxLine      `json:"tax_breakdown"`
+}
+
+// OrderPreview - ответ POST /orders/preview: суммы, которые получит заказ, оформленный тем же запросом
+// Ничего не сохраняется, номер заказа не выдается
+type OrderPreview struct {
+	Currency      string         `json:"currency"`
+	Country       string         `json:"country,omitempty"`
+	Items         []ItemResponse `json:"items"`
+	Subtotal      money.Amount   `json:"subtotal"` // Сумма позиций без налога
+	TaxTotal      money.Amount   `json:"tax_total"`
+	DeliveryPrice money.Amount   `json:"delivery_price"`
+	TotalPrice    money.Amount   `json:"total_price"`
+	TaxBreakdown  []TaxLine      `json:"tax_breakdown"`
+
+	EstimatedDeliveryFrom *time.Time `json:"estimated_delivery_from,omitempty"`
+	EstimatedDeliveryTo   *time.Time `json:"estimated_delivery_to,omitempty"`
+
+	// Conversion - итог в предпочитаемой валюте покупателя по текущему курсу; nil - валюты совпадают или курс недоступен
+	Conversion *ConversionPreview `json:"conversion,omitempty"`
+}
+
+// ConversionPreview - суммы заказа, сконвертированные по курсу так же, как их сконвертирует Background Worker
+// Ориентировочные: worker конвертирует по курсу на момент обработки ORDER_CREATED
+type ConversionPreview struct {
+	Currency      string       `json:"currency"`
+	Rate          float64      `json:"rate"`
+	Subtotal      money.Amount `json:"subtotal"`
+	TaxTotal      money.Amount `json:"tax_total"`
+	DeliveryPrice money.Amount `json:"delivery_price"`
+	TotalPrice    money.Amount `json:"total_price"`
 }
 
 // ItemResponse - позиция заказа в ответе
 type ItemResponse struct {
-	ID          uuid.UUID `json:"id"`
-	ProductID   uuid.UUID `json:"product_id"`
-	ProductName string    `json:"product_name,omitempty"`
-	Quantity    int       `json:"quantity"`
-	UnitPrice   float64   `json:"unit_price"`
-	TotalPrice  float64   `json:"total_price"`
+	ID          uuid.UUID      `json:"id"`
+	ProductID   uuid.UUID      `json:"product_id"`
+	ProductName string         `json:"product_name,omitempty"`
+	Quantity    units.Quantity `json:"quantity"`
+	Unit        units.Unit     `json:"unit,omitempty"`
+	UnitPrice   money.Amount   `json:"unit_price"`
+	TotalPrice  money.Amount   `json:"total_price"` // Без налога
+	TaxRate     float64        `json:"tax_rate"`
+	TaxAmount   money.Amount   `json:"tax_amount"`
+}
+
+// TaxLine - строка налоговой разбивки: сумма налога по одной ставке
+type TaxLine struct {
+	Name    string       `json:"name"`
+	Rate    float64      `json:"rate"`
+	Taxable money.Amount `json:"taxable"` // Облагаемая сумма позиций
+	Amount  money.Amount `json:"amount"`
+}
+
+// Invoice - счет по заказу: позиции без налога, налоги по ставкам и итог
+type Invoice struct {
+	OrderID      uuid.UUID     `json:"order_id"`
+	OrderNumber  string        `json:"order_number,omitempty"`
+	IssuedAt     string        `json:"issued_at"`
+	Currency     string        `json:"currency"`
+	Country      string        `json:"country,omitempty"`
+	Lines        []InvoiceLine `json:"lines"`
+	Subtotal     money.Amount  `json:"subtotal"`
+	TaxBreakdown []TaxLine     `json:"tax_breakdown"`
+	TaxTotal     money.Amount  `json:"tax_total"`
+	Delivery     money.Amount  `json:"delivery"`
+	Total        money.Amount  `json:"total"`
+}
+
+// InvoiceLine - позиция счета
+type InvoiceLine struct {
+	ProductID    uuid.UUID      `json:"product_id"`
+	ProductName  string         `json:"product_name,omitempty"`  // Название на момент покупки
+	CategoryName string         `json:"category_name,omitempty"` // Категория на момент покупки
+	Quantity     units.Quantity `json:"quantity"`
+	Unit         units.Unit     `json:"unit,omitempty"`
+	UnitPrice    money.Amount   `json:"unit_price"`
+	Net          money.Amount   `json:"net"` // Сумма без налога
+	TaxName      string         `json:"tax_name,omitempty"`
+	TaxRate      float64        `json:"tax_rate"`
+	TaxAmount    money.Amount   `json:"tax_amount"`
+	Gross        money.Amount   `json:"gross"` // Сумма с налогом
+}
+
+// RetentionPolicyResponse - политика хранения завершенных заказов (GET /admin/retention)
+type RetentionPolicyResponse struct {
+	Enabled    bool          `json:"enabled"`
+	PeriodDays int           `json:"period_days"` // Срок хранения от оформления заказа, 0 - бессрочно
+	Mode       string        `json:"mode"`        // anonymize или purge
+	Statuses   []OrderStatus `json:"statuses"`    // Статусы, на которые распространяется срок
+	Schedule   string        `json:"schedule"`
+	BatchSize  int           `json:"batch_size"`
+	LegalHolds int64         `json:"legal_holds"` // Заказы магазина на legal hold, исключенные из обработки
+}
+
+// LegalHoldRequest - запрос постановки или снятия legal hold заказа
+type LegalHoldRequest struct {
+	LegalHold *bool `json:"legal_hold" validate:"required"`
 }
@@ -3,19 +3,43 @@ package entity
 import (
 	"time"
 
+	"augustberries/pkg/money"
+	"augustberries/pkg/units"
+
 	"github.com/google/uuid"
 )
 
 // Order представляет заказ в системе
 type Order struct {
-	ID            uuid.UUID   `json:"id" gorm:"type:uuid;primaryKey"`
-	UserID        uuid.UUID   `json:"user_id" gorm:"type:uuid;not null"`                       // ID пользователя из Auth Service
-	TotalPrice    float64     `json:"total_price" gorm:"type:decimal(10,2);not null"`          // Итоговая стоимость в валюте клиента
-	DeliveryPrice float64     `json:"delivery_price" gorm:"type:decimal(10,2);not null"`       // Цена доставки
-	Currency      string      `json:"currency" gorm:"type:varchar(10);not null;default:'RUB'"` // Валюта (USD, EUR, RUB и т.п.)
-	Status        OrderStatus `json:"status" gorm:"type:varchar(50);not null;default:'pending'"`
-	CreatedAt     time.Time   `json:"created_at" gorm:"autoCreateTime"`
-	Items         []OrderItem `json:"items,omitempty" gorm:"foreignKey:OrderID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	ID                uuid.UUID    `json:"id" gorm:"type:uuid;primaryKey"`
+	Number            string       `json:"number,omitempty" gorm:"type:varchar(32)"`                   // Человекочитаемый номер (AB-20240115-000123), уникален в магазине
+	TenantID          string       `json:"-" gorm:"type:varchar(64);not null;default:'default';index"` // Магазин, в котором оформлен заказ
+	UserID            uuid.UUID    `json:"user_id" gorm:"type:uuid;not null"`                          // ID пользователя из Auth Service
+	TotalPrice        money.Amount `json:"total_price" gorm:"type:decimal(10,2);not null"`             // Итоговая стоимость в валюте клиента
+	DeliveryPrice     money.Amount `json:"delivery_price" gorm:"type:decimal(10,2);not null"`          // Цена доставки
+	TaxTotal          money.Amount `json:"tax_total" gorm:"type:decimal(10,2);not null;default:0"`     // Сумма налогов по позициям (входит в TotalPrice)
+	Currency          string       `json:"currency" gorm:"type:varchar(10);not null;default:'RUB'"`    // Валюта (USD, EUR, RUB и т.п.)
+	Country           string       `json:"country,omitempty" gorm:"type:varchar(2)"`                   // Страна доставки (ISO 3166-1 alpha-2), определяет ставки налога
+	Status            OrderStatus  `json:"status" gorm:"type:varchar(50);not null;default:'pending'"`
+	GuestEmail        *string      `json:"guest_email,omitempty" gorm:"type:varchar(255)"` // Email гостя; nil - заказ оформлен из аккаунта
+	ScheduledFor      *time.Time   `json:"scheduled_for,omitempty"`                        // Время активации отложенного заказа; nil - заказ оформлен сразу
+	PreferredCurrency string       `json:"-" gorm:"type:varchar(10);not null;default:''"`  // Предпочитаемая валюта покупателя на момент оформления (для ORDER_CREATED)
+	CreatedAt         time.Time    `json:"created_at" gorm:"autoCreateTime"`
+	Items             []OrderItem  `json:"items,omitempty" gorm:"foreignKey:OrderID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+
+	// Валюта и доставка на момент оформления: Background Worker конвертирует заказ в PreferredCurrency,
+	// а изменение состава пересчитывает его в исходной валюте. Пустая SourceCurrency - заказ оформлен до их сохранения
+	SourceCurrency      string       `json:"-" gorm:"type:varchar(10);not null;default:''"`
+	SourceDeliveryPrice money.Amount `json:"-" gorm:"type:decimal(10,2);not null;default:0"`
+	ConvertedAt         *time.Time   `json:"-"` // Время события последней конвертации Background Worker; nil - заказ в исходной валюте
+
+	// Окно ожидаемой доставки (даты UTC): рассчитывается при оформлении и уточняется по отправлениям; nil - оценки нет
+	EstimatedDeliveryFrom *time.Time `json:"estimated_delivery_from,omitempty" gorm:"type:date"`
+	EstimatedDeliveryTo   *time.Time `json:"estimated_delivery_to,omitempty" gorm:"type:date"`
+
+	// Срок хранения: заказ на legal hold не обезличивается и не удаляется; AnonymizedAt - время обезличивания
+	LegalHold    bool       `json:"legal_hold,omitempty" gorm:"not null;default:false"`
+	AnonymizedAt *time.Time `json:"anonymized_at,omitempty"`
 }
 
 // TableName указывает имя таблицы для GORM
@@ -32,15 +56,37 @@ const (
 	OrderStatusShipped   OrderStatus = "shipped"   // Отправлен
 	OrderStatusDelivered OrderStatus = "delivered" // Доставлен
 	OrderStatusCancelled OrderStatus = "cancelled" // Отменен
+
+	// OrderStatusPartiallyShipped - отправлена часть позиций; выставляется только по отправлениям
+	OrderStatusPartiallyShipped OrderStatus = "partially_shipped"
+	// OrderStatusScheduled - отложенный заказ ждет scheduled_for; в pending его переводит только фоновая задача
+	OrderStatusScheduled OrderStatus = "scheduled"
 )
 
 // OrderItem представляет позицию в заказе
 type OrderItem struct {
-	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
-	OrderID   uuid.UUID `json:"order_id" gorm:"type:uuid;not null"` // Ссылка на заказ
-	ProductID uuid.UUID `json:"product_id" gorm:"type:uuid;not null"`
-	Quantity  int       `json:"quantity" gorm:"not null;check:quantity > 0"`
-	UnitPrice float64   `json:"unit_price" gorm:"type:decimal(10,2);not null"` // Цена за единицу на момент покупки
+	ID        uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey"`
+	OrderID   uuid.UUID      `json:"order_id" gorm:"type:uuid;not null"` // Ссылка на заказ
+	ProductID uuid.UUID      `json:"product_id" gorm:"type:uuid;not null"`
+	Quantity  units.Quantity `json:"quantity" gorm:"type:decimal(12,3);not null;check:quantity > 0"` // В единицах товара (Product.Unit)
+	UnitPrice money.Amount   `json:"unit_price" gorm:"type:decimal(10,2);not null"`                  // Цена за единицу на момент покупки
+	TaxName   string         `json:"tax_name,omitempty" gorm:"type:varchar(100)"`                    // Название примененной ставки (НДС, VAT)
+	TaxRate   float64        `json:"tax_rate" gorm:"type:decimal(6,4);not null;default:0"`
+	TaxAmount money.Amount   `json:"tax_amount" gorm:"type:decimal(10,2);not null;default:0"` // Налог на всю позицию в валюте заказа
+	Position  int            `json:"-" gorm:"not null;default:0"`                             // Порядок позиции в заказе при оформлении
+
+	// Product - данные товара на момент покупки; не меняются при переименовании или удалении товара в каталоге
+	Product ProductSnapshot `json:"product" gorm:"embedded;embeddedPrefix:product_"`
+}
+
+// ProductSnapshot - снимок товара из Catalog Service, сохраненный в позиции заказа
+// Пустой снимок - позиция создана до введения снимков или каталог был недоступен
+type ProductSnapshot struct {
+	Name         string     `json:"name,omitempty" gorm:"type:varchar(255);not null;default:''"`
+	Description  string     `json:"description,omitempty" gorm:"type:text;not null;default:''"`
+	ImageURL     string     `json:"image_url,omitempty" gorm:"type:varchar(1024);not null;default:''"`
+	CategoryName string     `json:"category_name,omitempty" gorm:"type:varchar(255);not null;default:''"`
+	Unit         units.Unit `json:"unit,omitempty" gorm:"type:varchar(16);not null;default:'piece'"` // Единица измерения количества
 }
 
 // TableName указывает имя таблицы для GORM
@@ -48,30 +94,307 @@ func (OrderItem) TableName() string {
 	return "order_items"
 }
 
+// OrderSummary - строка денормализованной проекции order_summaries для списков заказов
+// Поля заказа копируются при каждом изменении, итоги по позициям считаются заранее, чтобы список не соединял
+// orders с order_items; пересобирается командой orders-service/cmd/rebuild-summaries
+type OrderSummary struct {
+	OrderID       uuid.UUID    `json:"id" gorm:"type:uuid;primaryKey"`
+	Number        string       `json:"number,omitempty" gorm:"type:varchar(32)"`
+	TenantID      string       `json:"-" gorm:"type:varchar(64);not null;default:'default'"`
+	UserID        uuid.UUID    `json:"user_id" gorm:"type:uuid;not null"`
+	TotalPrice    money.Amount `json:"total_price" gorm:"type:decimal(10,2);not null"`
+	DeliveryPrice money.Amount `json:"delivery_price" gorm:"type:decimal(10,2);not null"`
+	TaxTotal      money.Amount `json:"tax_total" gorm:"type:decimal(10,2);not null;default:0"`
+	Currency      string       `json:"currency" gorm:"type:varchar(10);not null"`
+	Country       string       `json:"country,omitempty" gorm:"type:varchar(2)"`
+	Status        OrderStatus  `json:"status" gorm:"type:varchar(50);not null"`
+	GuestEmail    *string      `json:"guest_email,omitempty" gorm:"type:varchar(255)"`
+	ScheduledFor  *time.Time   `json:"scheduled_for,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+
+	EstimatedDeliveryFrom *time.Time `json:"estimated_delivery_from,omitempty" gorm:"type:date"`
+	EstimatedDeliveryTo   *time.Time `json:"estimated_delivery_to,omitempty" gorm:"type:date"`
+
+	ItemsCount    int            `json:"items_count"`                              // Число позиций
+	ItemsQuantity units.Quantity `json:"items_quantity" gorm:"type:decimal(12,3)"` // Сумма количеств всех позиций
+	FirstItemName string         `json:"first_item_name,omitempty"`                // Название первой позиции из снимка товара
+
+	RefreshedAt time.Time `json:"-"` // Время последнего пересчета строки
+}
+
+// TableName указывает имя таблицы для GORM
+func (OrderSummary) TableName() string {
+	return "order_summaries"
+}
+
+// NewOrderSummary строит строку списка из заказа и его позиций
+// Используется, когда проекция не подключена: items == nil оставляет итоги по позициям пустыми
+func NewOrderSummary(order Order, items []OrderItem) OrderSummary {
+	summary := OrderSummary{
+		OrderID:       order.ID,
+		Number:        order.Number,
+		TenantID:      order.TenantID,
+		UserID:        order.UserID,
+		TotalPrice:    order.TotalPrice,
+		DeliveryPrice: order.DeliveryPrice,
+		TaxTotal:      order.TaxTotal,
+		Currency:      order.Currency,
+		Country:       order.Country,
+		Status:        order.Status,
+		GuestEmail:    order.GuestEmail,
+		ScheduledFor:  order.ScheduledFor,
+		CreatedAt:     order.CreatedAt,
+
+		EstimatedDeliveryFrom: order.EstimatedDeliveryFrom,
+		EstimatedDeliveryTo:   order.EstimatedDeliveryTo,
+	}
+
+	first := -1
+	for i, item := range items {
+		summary.ItemsCount++
+		summary.ItemsQuantity += item.Quantity
+		if first < 0 || item.Position < items[first].Position {
+			first = i
+		}
+	}
+	if first >= 0 {
+		summary.FirstItemName = items[first].Product.Name
+	}
+	return summary
+}
+
+// TaxRate - ставка налога магазина для страны доставки
+// Ставка без категории применяется ко всем товарам страны, ставка категории ее переопределяет
+type TaxRate struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
+	TenantID   string     `json:"-" gorm:"type:varchar(64);not null;default:'default';index"`
+	Country    string     `json:"country" gorm:"type:varchar(2);not null"`
+	CategoryID *uuid.UUID `json:"category_id,omitempty" gorm:"type:uuid"`
+	Name       string     `json:"name" gorm:"type:varchar(100);not null"`
+	Rate       float64    `json:"rate" gorm:"type:decimal(6,4);not null"` // Доля от цены: 0.2 - 20%
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (TaxRate) TableName() string {
+	return "tax_rates"
+}
+
+// Shipment - отправление: часть заказа, переданная перевозчику под одним трек-номером
+// Заказ может быть разделен на несколько отправлений
+type Shipment struct {
+	ID             uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey"`
+	OrderID        uuid.UUID      `json:"order_id" gorm:"type:uuid;not null;index"`
+	TrackingNumber string         `json:"tracking_number" gorm:"type:varchar(100);not null"`
+	Carrier        string         `json:"carrier" gorm:"type:varchar(100);not null"`
+	Status         ShipmentStatus `json:"status" gorm:"type:varchar(50);not null;default:'in_transit'"`
+	ShippedAt      time.Time      `json:"shipped_at" gorm:"not null"`
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	Items          []ShipmentItem `json:"items" gorm:"foreignKey:ShipmentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (Shipment) TableName() string {
+	return "shipments"
+}
+
+// ShipmentStatus представляет статусы отправления
+type ShipmentStatus string
+
+const (
+	ShipmentStatusInTransit ShipmentStatus = "in_transit" // Передано перевозчику
+	ShipmentStatusDelivered ShipmentStatus = "delivered"  // Доставлено получателю
+)
+
+// ShipmentItem - количество единиц позиции заказа в отправлении
+type ShipmentItem struct {
+	ID          uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey"`
+	ShipmentID  uuid.UUID      `json:"shipment_id" gorm:"type:uuid;not null"`
+	OrderItemID uuid.UUID      `json:"order_item_id" gorm:"type:uuid;not null"`
+	Quantity    units.Quantity `json:"quantity" gorm:"type:decimal(12,3);not null;check:quantity > 0"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (ShipmentItem) TableName() string {
+	return "shipment_items"
+}
+
+// OrderNote - заметка к заказу: переписка поддержки с клиентом или служебный комментарий
+type OrderNote struct {
+	ID         uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey"`
+	OrderID    uuid.UUID      `json:"order_id" gorm:"type:uuid;not null;index"`
+	AuthorID   uuid.UUID      `json:"author_id" gorm:"type:uuid;not null"`
+	Visibility NoteVisibility `json:"visibility" gorm:"type:varchar(20);not null"`
+	Body       string         `json:"body" gorm:"type:text;not null"`
+	TicketID   string         `json:"ticket_id,omitempty" gorm:"type:varchar(100)"` // Номер обращения во внешней системе поддержки
+	CreatedAt  time.Time      `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (OrderNote) TableName() string {
+	return "order_notes"
+}
+
+// NoteVisibility определяет, кому видна заметка
+type NoteVisibility string
+
+const (
+	NoteVisibilityInternal NoteVisibility = "internal" // Только сотрудникам (manager, admin)
+	NoteVisibilityCustomer NoteVisibility = "customer" // Сотрудникам и покупателю
+)
+
 // OrderWithItems содержит заказ с полным списком позиций
 type OrderWithItems struct {
 	Order
 	Items []OrderItem `json:"items"`
 }
 
+// DefaultCurrency - валюта заказа по умолчанию (совпадает со значением по умолчанию колонки currency)
+const DefaultCurrency = "RUB"
+
+// supportedCurrencies - валюты, в которых можно оформить заказ
+var supportedCurrencies = map[string]bool{"USD": true, "EUR": true, "RUB": true}
+
+// IsSupportedCurrency сообщает, можно ли оформить заказ в валюте
+func IsSupportedCurrency(code string) bool {
+	return supportedCurrencies[code]
+}
+
+// EventSchemaVersion - версия схемы OrderEvent (заголовок schema_version)
+const EventSchemaVersion = 1
+
 // OrderEvent представляет событие изменения заказа для Kafka
 type OrderEvent struct {
-	EventType    string      `json:"event_type"` // ORDER_CREATED, ORDER_UPDATED
-	OrderID      uuid.UUID   `json:"order_id"`
-	UserID       uuid.UUID   `json:"user_id"`
-	TotalPrice   float64     `json:"total_price"`
-	Currency     string      `json:"currency"`
-	Status       OrderStatus `json:"status"`
-	ItemsCount   int         `json:"items_count"`
-	Timestamp    time.Time   `json:"timestamp"`
+	EventType   string       `json:"event_type"` // ORDER_CREATED, ORDER_UPDATED, ORDER_STATUS_CHANGED, ORDER_DELIVERED
+	TenantID    string       `json:"tenant_id"`
+	OrderID     uuid.UUID    `json:"order_id"`
+	OrderNumber string       `json:"order_number,omitempty"` // Человекочитаемый номер заказа
+	UserID      uuid.UUID    `json:"user_id"`
+	TotalPrice  money.Amount `json:"total_price"`
+	TaxTotal    money.Amount `json:"tax_total"`
+	Currency    string       `json:"currency"`
+	Status      OrderStatus  `json:"status"`
+	ItemsCount  int          `json:"items_count"`
+	Timestamp   time.Time    `json:"timestamp"`
+	// PreferredCurrency - предпочитаемая валюта покупателя, в нее Background Worker конвертирует заказ
+	PreferredCurrency string `json:"preferred_currency,omitempty"`
+	// ItemChanges - измененные позиции (ORDER_UPDATED после изменения состава заказа)
+	// Background Worker по ним заново конвертирует заказ
+	ItemChanges []OrderItemChange `json:"item_changes,omitempty"`
+	// PreviousTotalPrice - итог заказа до изменения позиций
+	PreviousTotalPrice *money.Amount `json:"previous_total_price,omitempty"`
+	// Окно ожидаемой доставки заказа для уведомлений покупателя
+	EstimatedDeliveryFrom *time.Time `json:"estimated_delivery_from,omitempty"`
+	EstimatedDeliveryTo   *time.Time `json:"estimated_delivery_to,omitempty"`
+	// ProductIDs - товары доставленного заказа (ORDER_DELIVERED), по ним Reviews Service отмечает покупки
+	ProductIDs []uuid.UUID `json:"product_ids,omitempty"`
+	// Поля ORDER_STATUS_CHANGED: из какого статуса, кем и почему переведен заказ
+	// По ним Background Worker считает время заказа в каждом статусе
+	PreviousStatus OrderStatus  `json:"previous_status,omitempty"`
+	Actor          *StatusActor `json:"actor,omitempty"`
+	Reason         string       `json:"reason,omitempty"`
+	// OrderCreatedAt - время создания заказа: от него считается время в первом статусе
+	OrderCreatedAt *time.Time `json:"order_created_at,omitempty"`
+}
+
+// Инициаторы смены статуса заказа
+const (
+	ActorCustomer = "customer" // Покупатель через PATCH /orders/:id
+	ActorAdmin    = "admin"    // Администратор через admin API (массовая смена статуса, отправления)
+	ActorSystem   = "system"   // Фоновые задачи сервиса
+)
+
+// StatusActor - кто сменил статус заказа
+type StatusActor struct {
+	Type string     `json:"type"`         // customer, admin, system
+	ID   *uuid.UUID `json:"id,omitempty"` // Пользователь; nil для system
+	// ImpersonatorID - сотрудник поддержки, сменивший статус от имени покупателя
+	ImpersonatorID string `json:"impersonator_id,omitempty"`
+}
+
+// StatusChange - инициатор и причина смены статуса, попадают в ORDER_STATUS_CHANGED
+type StatusChange struct {
+	Actor  StatusActor
+	Reason string
+}
+
+// OrderItemChange - изменение количества позиции заказа; NewQuantity 0 - позиция удалена
+type OrderItemChange struct {
+	ItemID      uuid.UUID      `json:"item_id"`
+	ProductID   uuid.UUID      `json:"product_id"`
+	OldQuantity units.Quantity `json:"old_quantity"`
+	NewQuantity units.Quantity `json:"new_quantity"`
 }
 
 // Product представляет информацию о товаре из Catalog Service
 type Product struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Price       float64   `json:"price"`
-	CategoryID  uuid.UUID `json:"category_id"`
+	ID         uuid.UUID    `json:"id"`
+	Name       string       `json:"name"`
+	Price      money.Amount `json:"price"`
+	CategoryID uuid.UUID    `json:"category_id"`
+	Status     string       `json:"status"` // draft, published, archived
+}
+
+const (
+	// ProductStatusPublished - статус товара, доступного для заказа
+	ProductStatusPublished = "published"
+	// ProductStatusArchived - товар снят с продажи или удален из каталога
+	ProductStatusArchived = "archived"
+)
+
+// ProductAvailability - цена, статус и остаток товара из GET /products/batch Catalog Service
+type ProductAvailability struct {
+	ID           uuid.UUID      `json:"id"`
+	Name         string         `json:"name"`
+	Description  string         `json:"description"`
+	ImageURL     string         `json:"image_url,omitempty"` // Каталог пока не хранит изображения
+	Price        money.Amount   `json:"price"`
+	CategoryID   uuid.UUID      `json:"category_id"`
+	CategoryName string         `json:"category_name"`
+	Status       string         `json:"status"`
+	Stock        *int           `json:"stock,omitempty"` // nil - остаток не отслеживается
+	Available    bool           `json:"available"`
+	Unit         units.Unit     `json:"unit"`
+	QuantityStep units.Quantity `json:"quantity_step"` // Шаг количества, 0 - одна единица
+
+	// Ограничения количества в заказе; nil - ограничения нет
+	MinQty           *units.Quantity `json:"min_qty,omitempty"`
+	MaxQty           *units.Quantity `json:"max_qty,omitempty"`
+	PerCustomerLimit *units.Quantity `json:"per_customer_limit,omitempty"` // Сколько покупатель может заказать за все заказы
+}
+
+// Snapshot возвращает снимок товара для позиции заказа
+func (p *ProductAvailability) Snapshot() ProductSnapshot {
+	return ProductSnapshot{
+		Name:         p.Name,
+		Description:  p.Description,
+		ImageURL:     p.ImageURL,
+		CategoryName: p.CategoryName,
+		Unit:         p.Unit.OrDefault(),
+	}
+}
+
+// InStock проверяет, хватает ли остатка на quantity единиц
+func (p *ProductAvailability) InStock(quantity units.Quantity) bool {
+	return p.Stock == nil || units.Of(*p.Stock) >= quantity
+}
+
+// AcceptsQuantity проверяет, что количество кратно шагу товара (весовой товар по 0.5 кг)
+// Шаг штучного товара - одна штука, дробное количество штук не принимается
+func (p *ProductAvailability) AcceptsQuantity(quantity units.Quantity) bool {
+	return quantity.MultipleOf(p.QuantityStep) && (p.Unit.OrDefault() != units.UnitPiece || quantity.IsWhole())
+}
+
+// BelowMinimum проверяет, что количество меньше минимального для заказа
+func (p *ProductAvailability) BelowMinimum(quantity units.Quantity) bool {
+	return p.MinQty != nil && quantity < *p.MinQty
+}
+
+// AboveMaximum проверяет, что количество больше максимального для одного заказа
+func (p *ProductAvailability) AboveMaximum(quantity units.Quantity) bool {
+	return p.MaxQty != nil && quantity > *p.MaxQty
 }
 
 // ProductWithCategory содержит продукт с информацией о категории
@@ -1,8 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
-	"strings"
+
+	"augustberries/pkg/authcookie"
+	"augustberries/pkg/impersonation"
+	"augustberries/pkg/jwtclaims"
+	"augustberries/pkg/tenant"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
@@ -16,12 +21,27 @@ type JWTClaims struct {
 	RoleID      int      `json:"role_id"`
 	RoleName    string   `json:"role_name"`
 	Permissions []string `json:"permissions"`
+	TenantID    string   `json:"tenant_id,omitempty"` // Магазин пользователя (пусто для токенов без тенанта)
+	// TenantRoles - роли пользователя в других магазинах, применяются tenant.Middleware по X-Tenant-ID
+	TenantRoles tenant.Roles `json:"tenant_roles,omitempty"`
+	// Impersonator - сотрудник поддержки, действующий от имени пользователя; nil - обычный токен
+	Impersonator *impersonation.Impersonator `json:"impersonator,omitempty"`
+	// PreferredCurrency - валюта заказов пользователя по умолчанию (пусто - валюта магазина)
+	PreferredCurrency string `json:"preferred_currency,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// GuestRoleName - роль в токене гостевой сессии (оформление заказа без аккаунта)
+const GuestRoleName = "guest"
+
+// GuestTokenHeader - заголовок с гостевым токеном при привязке заказов к аккаунту
+const GuestTokenHeader = "X-Guest-Token"
+
 // AuthMiddleware проверяет JWT токен в запросах для Gin
 type AuthMiddleware struct {
 	jwtSecret string
+	cookies   *authcookie.Cookies // nil - токен только в заголовке Authorization
+	expected  jwtclaims.Expected  // Ожидаемые iss и aud токенов, пустые - не проверяются
 }
 
 // NewAuthMiddleware создает новый middleware для аутентификации
@@ -31,70 +51,150 @@ func NewAuthMiddleware(jwtSecret string) *AuthMiddleware {
 	}
 }
 
+// SetCookies включает токен из httpOnly cookie с проверкой CSRF токена у изменяющих запросов
+func (m *AuthMiddleware) SetCookies(cookies *authcookie.Cookies) {
+	m.cookies = cookies
+}
+
+// SetExpectedClaims включает проверку iss и aud: токены другого окружения отклоняются
+func (m *AuthMiddleware) SetExpectedClaims(expected jwtclaims.Expected) {
+	m.expected = expected
+}
+
 // Authenticate проверяет JWT токен и добавляет данные пользователя в контекст Gin
 func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Извлекаем токен из заголовка Authorization
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-			c.Abort()
+		// Токен из заголовка Authorization или из cookie
+		tokenString, err := m.cookies.Token(c.Request)
+		if err != nil {
+			abortTokenError(c, err)
 			return
 		}
 
-		// Проверяем формат "Bearer <token>"
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
-			c.Abort()
+		// Сохраняем полный токен для передачи в Catalog Service
+		c.Set("auth_token", tokenString)
+
+		// Парсим и валидируем токен
+		claims, userID, ok := m.parseToken(c, tokenString)
+		if !ok {
 			return
 		}
 
-		tokenString := parts[1]
+		// Добавляем данные пользователя в контекст Gin
+		c.Set("user_id", userID)
+		c.Set("email", claims.Email)
+		c.Set("role_id", claims.RoleID)
+		c.Set("role_name", claims.RoleName)
+		c.Set("permissions", claims.Permissions)
+		c.Set(tenant.ContextKey, claims.TenantID)
+		c.Set(tenant.RolesKey, claims.TenantRoles)
+		c.Set("preferred_currency", claims.PreferredCurrency)
 
-		// Сохраняем полный токен для передачи в Catalog Service
-		c.Set("auth_token", tokenString)
+		// Токен сотрудника поддержки: отметка в ответе и журнале запросов
+		impersonation.Set(c, claims.Impersonator)
 
-		// Парсим и валидируем токен
-		token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-			return []byte(m.jwtSecret), nil
-		})
+		// Передаем управление следующему обработчику
+		c.Next()
+	}
+}
 
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+// DenyGuest запрещает маршрут гостевым токенам
+// Гость может только оформить и просматривать свои заказы
+func (m *AuthMiddleware) DenyGuest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role_name") == GuestRoleName {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Not available for guest checkout"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireGuestToken проверяет гостевой токен из заголовка X-Guest-Token
+// Ставится после Authenticate и Tenant middleware: гостевая сессия должна быть из того же магазина
+// Добавляет в контекст guest_id и guest_email
+func (m *AuthMiddleware) RequireGuestToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := c.GetHeader(GuestTokenHeader)
+		if tokenString == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Guest token header required"})
 			c.Abort()
 			return
 		}
 
-		// Извлекаем claims
-		claims, ok := token.Claims.(*JWTClaims)
+		claims, guestID, ok := m.parseToken(c, tokenString)
 		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+			return
+		}
+
+		if claims.RoleName != GuestRoleName {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Not a guest token"})
 			c.Abort()
 			return
 		}
 
-		// Парсим UserID из string в UUID
-		userID, err := uuid.Parse(claims.UserID)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID in token"})
+		guestTenant, err := tenant.Resolve(claims.TenantID, "")
+		if err != nil || guestTenant != c.GetString(tenant.ContextKey) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Guest token belongs to another tenant"})
 			c.Abort()
 			return
 		}
 
-		// Добавляем данные пользователя в контекст Gin
-		c.Set("user_id", userID)
-		c.Set("email", claims.Email)
-		c.Set("role_id", claims.RoleID)
-		c.Set("role_name", claims.RoleName)
-		c.Set("permissions", claims.Permissions)
+		c.Set("guest_id", guestID)
+		c.Set("guest_email", claims.Email)
 
-		// Передаем управление следующему обработчику
 		c.Next()
 	}
 }
 
-// RequireRole проверяет, что у пользователя есть требуемая роль
+// parseToken проверяет подпись и срок токена и извлекает ID пользователя
+// При ошибке отвечает 401 и прерывает запрос
+func (m *AuthMiddleware) parseToken(c *gin.Context, tokenString string) (*JWTClaims, uuid.UUID, bool) {
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return []byte(m.jwtSecret), nil
+	}, m.expected.ParserOptions()...)
+
+	if err != nil || !token.Valid {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": jwtclaims.Message(err)})
+		c.Abort()
+		return nil, uuid.Nil, false
+	}
+
+	// Извлекаем claims
+	claims, ok := token.Claims.(*JWTClaims)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+		c.Abort()
+		return nil, uuid.Nil, false
+	}
+
+	// Парсим UserID из string в UUID
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID in token"})
+		c.Abort()
+		return nil, uuid.Nil, false
+	}
+
+	return claims, userID, true
+}
+
+// abortTokenError отвечает на ошибку извлечения токена: 403 для CSRF, иначе 401
+func abortTokenError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, authcookie.ErrCSRF):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid CSRF token"})
+	case errors.Is(err, authcookie.ErrInvalidHeader):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
+	default:
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+	}
+	c.Abort()
+}
+
+// RequireRole проверяет, что у пользователя есть требуемая роль в активном магазине
+// Роль другого магазина подставляет tenant.Middleware, поэтому он должен стоять раньше
 func (m *AuthMiddleware) RequireRole(roles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		roleName, exists := c.Get("role_name")
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/service"
+	"augustberries/pkg/validate"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// NoteHandler обрабатывает заметки к заказам и admin список заказов
+type NoteHandler struct {
+	noteService *service.NoteService
+	validator   *validate.Validator
+}
+
+// NewNoteHandler создает новый обработчик заметок
+func NewNoteHandler(noteService *service.NoteService) *NoteHandler {
+	return &NoteHandler{
+		noteService: noteService,
+		validator:   validate.New(),
+	}
+}
+
+// AddNote обрабатывает POST /orders/{id}/notes
+// Сотрудники могут оставлять служебные заметки (visibility=internal), покупатель - только видимые ему
+func (h *NoteHandler) AddNote(c *gin.Context) {
+	userUUID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	var req entity.CreateOrderNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+
+	note, err := h.noteService.AddNote(c.Request.Context(), orderID, userUUID, isStaff(c), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrOrderNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+			return
+		}
+		if errors.Is(err, service.ErrUnauthorized) || errors.Is(err, service.ErrInternalNoteForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add order note"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, note)
+}
+
+// GetNotes обрабатывает GET /orders/{id}/notes
+// Служебные заметки возвращаются только сотрудникам
+func (h *NoteHandler) GetNotes(c *gin.Context) {
+	userUUID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	notes, err := h.noteService.GetNotes(c.Request.Context(), orderID, userUUID, isStaff(c))
+	if err != nil {
+		if errors.Is(err, service.ErrOrderNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+			return
+		}
+		if errors.Is(err, service.ErrUnauthorized) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get order notes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notes": notes,
+		"total": len(notes),
+	})
+}
+
+// ListOrders обрабатывает GET /admin/orders
+// Возвращает заказы магазина с числом заметок; фильтры ?status= и ?user_id=
+func (h *NoteHandler) ListOrders(c *gin.Context) {
+	filter := entity.OrderFilter{Status: entity.OrderStatus(c.Query("status"))}
+	if value := c.Query("user_id"); value != "" {
+		userID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
+			return
+		}
+		filter.UserID = &userID
+	}
+
+	orders, err := h.noteService.ListOrders(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get orders"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"orders": orders,
+		"total":  len(orders),
+	})
+}
+
+// currentUserID извлекает ID пользователя, установленный AuthMiddleware
+// При ошибке сам отвечает клиенту и возвращает false
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return uuid.Nil, false
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return uuid.Nil, false
+	}
+
+	return userUUID, true
+}
+
+// isStaff проверяет, что пользователь - сотрудник магазина (manager или admin)
+func isStaff(c *gin.Context) bool {
+	role := c.GetString("role_name")
+	return role == "manager" || role == "admin"
+}
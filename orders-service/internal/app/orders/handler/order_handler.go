@@ -6,72 +6,151 @@ import (
 
 	"augustberries/orders-service/internal/app/orders/entity"
 	"augustberries/orders-service/internal/app/orders/service"
+	"augustberries/pkg/featureflags"
+	"augustberries/pkg/pagination"
+	"augustberries/pkg/validate"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
 
+// FlagQuoteCheckout - оформление заказа по подписанной котировке из корзины
+// При выключенном флаге токен котировки игнорируется и цены запрашиваются в Catalog Service
+const FlagQuoteCheckout = "orders.quote_checkout"
+
 // OrderHandler обрабатывает HTTP запросы для заказов с использованием Gin
 type OrderHandler struct {
 	orderService *service.OrderService
-	validator    *validator.Validate
+	flags        *featureflags.Client
+	validator    *validate.Validator
 }
 
 // NewOrderHandler создает новый обработчик заказов
-func NewOrderHandler(orderService *service.OrderService) *OrderHandler {
+// flags может быть nil - тогда все флаги принимают значения по умолчанию
+func NewOrderHandler(orderService *service.OrderService, flags *featureflags.Client) *OrderHandler {
 	return &OrderHandler{
 		orderService: orderService,
-		validator:    validator.New(),
+		flags:        flags,
+		validator:    validate.New(),
 	}
 }
 
 // CreateOrder обрабатывает POST /orders/
 // Создает новый заказ с проверкой цен из Catalog Service
 func (h *OrderHandler) CreateOrder(c *gin.Context) {
+	userID, req, authToken, ok := h.bindCreateOrder(c)
+	if !ok {
+		return
+	}
+
+	// Создаем заказ
+	order, err := h.orderService.CreateOrder(c.Request.Context(), userID, req, authToken)
+	if err != nil {
+		writeCreateOrderError(c, err, "Failed to create order")
+		return
+	}
+
+	// Формируем ответ
+	response := buildOrderResponse(order)
+	c.JSON(http.StatusCreated, response)
+}
+
+// PreviewOrder обрабатывает POST /orders/preview
+// Считает заказ тем же расчетом, что и CreateOrder (цены каталога или котировки, налоги, доставка, итог),
+// но ничего не сохраняет; тело запроса и ошибки те же, что у POST /orders/
+// Купонов и скидок в Orders Service нет, поэтому в расчете их нет и у предпросмотра
+func (h *OrderHandler) PreviewOrder(c *gin.Context) {
+	userID, req, authToken, ok := h.bindCreateOrder(c)
+	if !ok {
+		return
+	}
+
+	preview, err := h.orderService.PreviewOrder(c.Request.Context(), userID, req, authToken)
+	if err != nil {
+		writeCreateOrderError(c, err, "Failed to preview order")
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+// bindCreateOrder читает и проверяет запрос оформления заказа, дополняя его данными токена
+// При ошибке ответ уже записан и ok = false
+func (h *OrderHandler) bindCreateOrder(c *gin.Context) (userID uuid.UUID, req *entity.CreateOrderRequest, authToken string, ok bool) {
 	// Получаем userID из контекста (установлен middleware)
-	userID, exists := c.Get("user_id")
+	value, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
+		return uuid.Nil, nil, "", false
 	}
 
-	userUUID, ok := userID.(uuid.UUID)
-	if !ok {
+	userID, isUUID := value.(uuid.UUID)
+	if !isUUID {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
-		return
+		return uuid.Nil, nil, "", false
 	}
 
 	// Получаем auth токен для запросов к Catalog Service
-	authToken, _ := c.Get("auth_token")
-	authTokenStr, _ := authToken.(string)
+	token, _ := c.Get("auth_token")
+	authToken, _ = token.(string)
 
-	var req entity.CreateOrderRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	req = &entity.CreateOrderRequest{}
+	if err := c.ShouldBindJSON(req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
-		return
+		return uuid.Nil, nil, "", false
 	}
 
 	// Валидация
 	if err := h.validator.Struct(req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": formatValidationError(err)})
-		return
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return uuid.Nil, nil, "", false
 	}
 
-	// Создаем заказ
-	order, err := h.orderService.CreateOrder(c.Request.Context(), userUUID, &req, authTokenStr)
-	if err != nil {
-		if errors.Is(err, service.ErrProductNotFound) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "One or more products not found in catalog"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create order"})
-		return
+	// Гостевой заказ запоминает email из гостевой сессии для поиска заказа без аккаунта
+	if c.GetString("role_name") == GuestRoleName {
+		req.GuestEmail = c.GetString("email")
 	}
+	req.PreferredCurrency = c.GetString("preferred_currency")
 
-	// Формируем ответ
-	response := buildOrderResponse(order)
-	c.JSON(http.StatusCreated, response)
+	// Оформление по котировке раскатывается флагом; без флага оно включено для всех
+	if req.QuoteToken != "" && !h.flags.Enabled(c.Request.Context(), FlagQuoteCheckout, userID.String(), true) {
+		req.QuoteToken = ""
+	}
+
+	return userID, req, authToken, true
+}
+
+// writeCreateOrderError отвечает на ошибку оформления или предпросмотра заказа
+// fallback - сообщение для непредвиденных ошибок
+func writeCreateOrderError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrProductNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "One or more products not found in catalog"})
+	case errors.Is(err, service.ErrProductArchived):
+		c.JSON(http.StatusConflict, gin.H{"error": "One or more products have been archived", "code": "PRODUCT_ARCHIVED"})
+	case errors.Is(err, service.ErrProductNotAvailable):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "One or more products are not available for ordering"})
+	case errors.Is(err, service.ErrInvalidQuantity):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Quantity is not a multiple of the product quantity step"})
+	case errors.Is(err, service.ErrQuantityBelowMinimum):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Quantity is below the product minimum", "code": "QUANTITY_BELOW_MIN"})
+	case errors.Is(err, service.ErrQuantityAboveMaximum):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Quantity is above the product maximum", "code": "QUANTITY_ABOVE_MAX"})
+	case errors.Is(err, service.ErrCustomerLimitExceeded):
+		c.JSON(http.StatusConflict, gin.H{"error": "Per-customer limit for the product exceeded", "code": "CUSTOMER_LIMIT_EXCEEDED"})
+	case errors.Is(err, service.ErrTotalMismatch):
+		c.JSON(http.StatusConflict, gin.H{"error": "Order total mismatch, prices have changed"})
+	case errors.Is(err, service.ErrInvalidQuote):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid price quote"})
+	case errors.Is(err, service.ErrQuoteExpired):
+		c.JSON(http.StatusConflict, gin.H{"error": "Price quote expired, request a new quote"})
+	case errors.Is(err, service.ErrInvalidOrderTotal):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order total"})
+	case errors.Is(err, service.ErrInvalidScheduledFor):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "scheduled_for must be in the future"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
 }
 
 // GetOrder обрабатывает GET /orders/{id}
@@ -118,6 +197,144 @@ func (h *OrderHandler) GetOrder(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// LookupGuestOrder обрабатывает GET /orders/lookup?order_id=&email= (или ?number=&email=)
+// Публичный поиск гостевого заказа: магазин берется из заголовка X-Tenant-ID
+func (h *OrderHandler) LookupGuestOrder(c *gin.Context) {
+	var req entity.GuestOrderLookupRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters"})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+
+	var order *entity.OrderWithItems
+	var err error
+	if req.OrderID != "" {
+		order, err = h.orderService.LookupGuestOrder(c.Request.Context(), uuid.MustParse(req.OrderID), req.Email)
+	} else {
+		order, err = h.orderService.LookupGuestOrderByNumber(c.Request.Context(), req.Number, req.Email)
+	}
+	if err != nil {
+		if errors.Is(err, service.ErrOrderNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get order"})
+		return
+	}
+
+	c.JSON(http.StatusOK, buildOrderResponse(order))
+}
+
+// LinkGuestOrders обрабатывает POST /orders/link-guest
+// Переносит заказы гостевой сессии из заголовка X-Guest-Token на аккаунт текущего пользователя
+func (h *OrderHandler) LinkGuestOrders(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	guestID, ok := c.Get("guest_id")
+	guestUUID, isUUID := guestID.(uuid.UUID)
+	if !ok || !isUUID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Guest token required"})
+		return
+	}
+
+	linked, err := h.orderService.LinkGuestOrders(c.Request.Context(), guestUUID, c.GetString("guest_email"), userUUID, c.GetString("email"))
+	if err != nil {
+		if errors.Is(err, service.ErrGuestEmailMismatch) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Guest session belongs to another email"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to link guest orders"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.LinkGuestOrdersResponse{Linked: linked})
+}
+
+// GetOrderByNumber обрабатывает GET /orders/by-number/{number}
+// Получает заказ по человекочитаемому номеру с проверкой прав доступа
+func (h *OrderHandler) GetOrderByNumber(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	order, err := h.orderService.GetOrderByNumber(c.Request.Context(), c.Param("number"), userUUID)
+	if err != nil {
+		if errors.Is(err, service.ErrOrderNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+			return
+		}
+		if errors.Is(err, service.ErrUnauthorized) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get order"})
+		return
+	}
+
+	c.JSON(http.StatusOK, buildOrderResponse(order))
+}
+
+// GetInvoice обрабатывает GET /orders/{id}/invoice
+// Счет по заказу с налоговой разбивкой по ставкам
+func (h *OrderHandler) GetInvoice(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	invoice, err := h.orderService.GetInvoice(c.Request.Context(), orderID, userUUID)
+	if err != nil {
+		if errors.Is(err, service.ErrOrderNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+			return
+		}
+		if errors.Is(err, service.ErrUnauthorized) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get invoice"})
+		return
+	}
+
+	c.JSON(http.StatusOK, invoice)
+}
+
 // UpdateOrderStatus обрабатывает PATCH /orders/{id}
 // Обновляет статус заказа (shipped, delivered)
 func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
@@ -150,12 +367,12 @@ func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 
 	// Валидация
 	if err := h.validator.Struct(req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": formatValidationError(err)})
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
 		return
 	}
 
 	// Обновляем статус
-	order, err := h.orderService.UpdateOrderStatus(c.Request.Context(), orderID, userUUID, req.Status)
+	order, err := h.orderService.UpdateOrderStatus(c.Request.Context(), orderID, userUUID, req.Status, req.Reason)
 	if err != nil {
 		if errors.Is(err, service.ErrOrderNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
@@ -169,6 +386,10 @@ func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status transition"})
 			return
 		}
+		if errors.Is(err, service.ErrOrderStatusConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Order status was changed by another request", "code": "ORDER_STATUS_CONFLICT"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update order status"})
 		return
 	}
@@ -179,6 +400,148 @@ func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 	})
 }
 
+// BulkUpdateOrderStatus обрабатывает POST /admin/orders/bulk-status
+// Переводит до 500 заказов в один статус и возвращает результат по каждому заказу
+// Ответ 200 даже при частичных ошибках: отчет показывает, какие заказы не изменились и почему
+func (h *OrderHandler) BulkUpdateOrderStatus(c *gin.Context) {
+	var req entity.BulkOrderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+
+	change := entity.StatusChange{Actor: adminActor(c), Reason: req.Reason}
+	c.JSON(http.StatusOK, h.orderService.BulkUpdateOrderStatus(c.Request.Context(), req.OrderIDs, req.Status, change))
+}
+
+// adminActor возвращает администратора запроса как инициатора смены статуса заказа
+func adminActor(c *gin.Context) entity.StatusActor {
+	actor := entity.StatusActor{Type: entity.ActorAdmin}
+	if userID, ok := c.Get("user_id"); ok {
+		if id, ok := userID.(uuid.UUID); ok {
+			actor.ID = &id
+		}
+	}
+	return actor
+}
+
+// GetDashboardSummary обрабатывает GET /admin/summary
+// Сводка для внутренней панели: заказы по статусам и выручка за сегодня
+func (h *OrderHandler) GetDashboardSummary(c *gin.Context) {
+	summary, err := h.orderService.GetDashboardSummary(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get summary"})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetUserOrderStats обрабатывает GET /orders/stats
+// Статистика заказов текущего пользователя для личного кабинета
+func (h *OrderHandler) GetUserOrderStats(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	stats, err := h.orderService.GetUserStats(c.Request.Context(), userUUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get order stats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// UpdateOrderItems обрабатывает PATCH /orders/{id}/items
+// Меняет количество позиций заказа до подтверждения; количество 0 удаляет позицию
+func (h *OrderHandler) UpdateOrderItems(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	// Получаем auth токен для запросов к Catalog Service
+	authToken, _ := c.Get("auth_token")
+	authTokenStr, _ := authToken.(string)
+
+	var req entity.UpdateOrderItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+
+	order, err := h.orderService.UpdateOrderItems(c.Request.Context(), orderID, userUUID, req.Items, authTokenStr)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOrderNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+		case errors.Is(err, service.ErrUnauthorized):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		case errors.Is(err, service.ErrOrderNotEditable):
+			c.JSON(http.StatusConflict, gin.H{"error": "Order items can only be changed while the order is pending"})
+		case errors.Is(err, service.ErrOrderConverted):
+			c.JSON(http.StatusConflict, gin.H{"error": "Order was converted to another currency and its items can no longer be changed"})
+		case errors.Is(err, service.ErrOrderItemNotFound):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "One or more items not found in order"})
+		case errors.Is(err, service.ErrEmptyOrder):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Order must keep at least one item, cancel the order instead"})
+		case errors.Is(err, service.ErrProductNotFound):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "One or more products not found in catalog"})
+		case errors.Is(err, service.ErrProductArchived):
+			c.JSON(http.StatusConflict, gin.H{"error": "One or more products have been archived", "code": "PRODUCT_ARCHIVED"})
+		case errors.Is(err, service.ErrProductNotAvailable):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "One or more products are not available for ordering"})
+		case errors.Is(err, service.ErrInvalidQuantity):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Quantity is not a multiple of the product quantity step"})
+		case errors.Is(err, service.ErrQuantityBelowMinimum):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Quantity is below the product minimum", "code": "QUANTITY_BELOW_MIN"})
+		case errors.Is(err, service.ErrQuantityAboveMaximum):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Quantity is above the product maximum", "code": "QUANTITY_ABOVE_MAX"})
+		case errors.Is(err, service.ErrCustomerLimitExceeded):
+			c.JSON(http.StatusConflict, gin.H{"error": "Per-customer limit for the product exceeded", "code": "CUSTOMER_LIMIT_EXCEEDED"})
+		case errors.Is(err, service.ErrTotalMismatch):
+			c.JSON(http.StatusConflict, gin.H{"error": "Order total mismatch, prices have changed"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update order items"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
 // DeleteOrder обрабатывает DELETE /orders/{id}
 // Удаляет заказ с проверкой прав доступа
 func (h *OrderHandler) DeleteOrder(c *gin.Context) {
@@ -222,8 +585,8 @@ func (h *OrderHandler) DeleteOrder(c *gin.Context) {
 	})
 }
 
-// GetUserOrders обрабатывает GET /orders/
-// Получает все заказы текущего пользователя
+// GetUserOrders обрабатывает GET /orders/?page=&per_page=
+// Получает страницу заказов текущего пользователя
 func (h *OrderHandler) GetUserOrders(c *gin.Context) {
 	// Получаем userID из контекста
 	userID, exists := c.Get("user_id")
@@ -238,6 +601,12 @@ func (h *OrderHandler) GetUserOrders(c *gin.Context) {
 		return
 	}
 
+	page, err := pagination.Parse(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Получаем заказы пользователя
 	orders, err := h.orderService.GetUserOrders(c.Request.Context(), userUUID)
 	if err != nil {
@@ -245,9 +614,9 @@ func (h *OrderHandler) GetUserOrders(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"orders": orders,
-		"total":  len(orders),
+	c.JSON(http.StatusOK, entity.OrderListResponse{
+		Orders: pagination.Slice(orders, page),
+		Meta:   pagination.NewMeta(c.Request.URL, page, len(orders)),
 	})
 }
 
@@ -259,29 +628,27 @@ func buildOrderResponse(order *entity.OrderWithItems) entity.OrderResponse {
 			ID:         item.ID,
 			ProductID:  item.ProductID,
 			Quantity:   item.Quantity,
+			Unit:       item.Product.Unit,
 			UnitPrice:  item.UnitPrice,
-			TotalPrice: item.UnitPrice * float64(item.Quantity),
+			TotalPrice: item.UnitPrice.MulQuantity(item.Quantity),
+			TaxRate:    item.TaxRate,
+			TaxAmount:  item.TaxAmount,
 		}
 	}
 
 	return entity.OrderResponse{
 		ID:            order.ID,
+		Number:        order.Number,
 		UserID:        order.UserID,
 		TotalPrice:    order.TotalPrice,
 		DeliveryPrice: order.DeliveryPrice,
+		TaxTotal:      order.TaxTotal,
 		Currency:      order.Currency,
+		Country:       order.Country,
 		Status:        order.Status,
+		GuestEmail:    order.GuestEmail,
 		CreatedAt:     order.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		Items:         items,
+		TaxBreakdown:  service.TaxBreakdown(order.Items),
 	}
 }
-
-// formatValidationError форматирует ошибки валидации
-func formatValidationError(err error) string {
-	if validationErrors, ok := err.(validator.ValidationErrors); ok {
-		for _, fieldError := range validationErrors {
-			return fieldError.Field() + " is " + fieldError.Tag()
-		}
-	}
-	return "Validation failed"
-}
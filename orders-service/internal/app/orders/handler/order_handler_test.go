@@ -10,6 +10,8 @@ import (
 
 	"augustberries/orders-service/internal/app/orders/entity"
 	"augustberries/orders-service/internal/app/orders/service"
+	"augustberries/pkg/money"
+	"augustberries/pkg/units"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -77,14 +79,14 @@ func TestCreateOrderHandler_Success(t *testing.T) {
 		Order: entity.Order{
 			ID:            orderID,
 			UserID:        userID,
-			TotalPrice:    110.0,
-			DeliveryPrice: 10.0,
+			TotalPrice:    money.MustParse("110.00"),
+			DeliveryPrice: money.MustParse("10.00"),
 			Currency:      "USD",
 			Status:        entity.OrderStatusPending,
 			CreatedAt:     time.Now(),
 		},
 		Items: []entity.OrderItem{
-			{ID: uuid.New(), OrderID: orderID, ProductID: productID, Quantity: 2, UnitPrice: 50.0},
+			{ID: uuid.New(), OrderID: orderID, ProductID: productID, Quantity: units.Of(2), UnitPrice: money.MustParse("50.00")},
 		},
 	}
 
@@ -112,9 +114,9 @@ func TestCreateOrderHandler_Success(t *testing.T) {
 
 	reqBody := entity.CreateOrderRequest{
 		Items: []entity.OrderItemRequest{
-			{ProductID: productID, Quantity: 2},
+			{ProductID: productID, Quantity: units.Of(2)},
 		},
-		DeliveryPrice: 10.0,
+		DeliveryPrice: money.MustParse("10.00"),
 		Currency:      "USD",
 	}
 	body, _ := json.Marshal(reqBody)
@@ -172,7 +174,7 @@ func TestCreateOrderHandler_Unauthorized(t *testing.T) {
 
 	reqBody := entity.CreateOrderRequest{
 		Items: []entity.OrderItemRequest{
-			{ProductID: uuid.New(), Quantity: 1},
+			{ProductID: uuid.New(), Quantity: units.Of(1)},
 		},
 		Currency: "USD",
 	}
@@ -209,7 +211,7 @@ func TestCreateOrderHandler_ProductNotFound(t *testing.T) {
 	})
 
 	reqBody := entity.CreateOrderRequest{
-		Items:    []entity.OrderItemRequest{{ProductID: uuid.New(), Quantity: 1}},
+		Items:    []entity.OrderItemRequest{{ProductID: uuid.New(), Quantity: units.Of(1)}},
 		Currency: "USD",
 	}
 	body, _ := json.Marshal(reqBody)
@@ -235,7 +237,7 @@ func TestGetOrderHandler_Success(t *testing.T) {
 		Order: entity.Order{
 			ID:         orderID,
 			UserID:     userID,
-			TotalPrice: 100.0,
+			TotalPrice: money.MustParse("100.00"),
 			Status:     entity.OrderStatusPending,
 			CreatedAt:  time.Now(),
 		},
@@ -496,8 +498,8 @@ func TestGetUserOrdersHandler_Success(t *testing.T) {
 	userID := uuid.New()
 
 	orders := []entity.Order{
-		{ID: uuid.New(), UserID: userID, TotalPrice: 100.0, Status: entity.OrderStatusPending},
-		{ID: uuid.New(), UserID: userID, TotalPrice: 200.0, Status: entity.OrderStatusDelivered},
+		{ID: uuid.New(), UserID: userID, TotalPrice: money.MustParse("100.00"), Status: entity.OrderStatusPending},
+		{ID: uuid.New(), UserID: userID, TotalPrice: money.MustParse("200.00"), Status: entity.OrderStatusDelivered},
 	}
 
 	mockService := new(MockOrderService)
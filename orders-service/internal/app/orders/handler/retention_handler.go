@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/service"
+	"augustberries/pkg/validate"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RetentionHandler обрабатывает политику хранения заказов и legal hold
+type RetentionHandler struct {
+	retentionService *service.RetentionService
+	validator        *validate.Validator
+}
+
+// NewRetentionHandler создает новый обработчик сроков хранения
+func NewRetentionHandler(retentionService *service.RetentionService) *RetentionHandler {
+	return &RetentionHandler{
+		retentionService: retentionService,
+		validator:        validate.New(),
+	}
+}
+
+// GetRetentionPolicy обрабатывает GET /admin/retention
+func (h *RetentionHandler) GetRetentionPolicy(c *gin.Context) {
+	policy, err := h.retentionService.Policy(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get retention policy"})
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// SetLegalHold обрабатывает PUT /admin/orders/{id}/legal-hold
+// Заказ на legal hold не обезличивается и не удаляется по сроку хранения
+func (h *RetentionHandler) SetLegalHold(c *gin.Context) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	var req entity.LegalHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+
+	if err := h.retentionService.SetLegalHold(c.Request.Context(), orderID, *req.LegalHold); err != nil {
+		if errors.Is(err, service.ErrOrderNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set legal hold"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"id": orderID, "legal_hold": *req.LegalHold})
+}
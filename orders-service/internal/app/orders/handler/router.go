@@ -6,39 +6,112 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"augustberries/pkg/buildinfo"
+	"augustberries/pkg/featureflags"
+	"augustberries/pkg/httpmw"
+	"augustberries/pkg/impersonation"
 	"augustberries/pkg/metrics"
+	"augustberries/pkg/recovery"
+	"augustberries/pkg/tenant"
 )
 
 // SetupRoutes настраивает все маршруты Orders Service с использованием Gin
-// Применяет Auth middleware для защиты эндпоинтов
-func SetupRoutes(orderHandler *OrderHandler, authMiddleware *AuthMiddleware) *gin.Engine {
-	router := gin.Default()
+// Применяет Auth middleware для защиты эндпоинтов и Tenant middleware для изоляции данных магазинов
+func SetupRoutes(orderHandler *OrderHandler, shipmentHandler *ShipmentHandler, noteHandler *NoteHandler, taxHandler *TaxHandler, retentionHandler *RetentionHandler, flagsHandler *featureflags.Handler, authMiddleware *AuthMiddleware, cors httpmw.CORSConfig, compression httpmw.CompressConfig) *gin.Engine {
+	router := gin.New()
+	router.Use(gin.Logger(), recovery.Middleware("orders-service"), impersonation.AuditMiddleware("orders-service"))
 
 	// Prometheus metrics middleware
 	router.Use(metrics.GinPrometheusMiddleware("orders-service"))
 
+	// CORS для браузерных клиентов (CORS_ALLOWED_ORIGINS и др.), preflight запросы не доходят до маршрутов
+	router.Use(httpmw.CORS(cors))
+
+	// Сжатие ответов больших списков (gzip, zstd) по Accept-Encoding
+	compress := httpmw.Compress("orders-service", compression)
+
 	// Health check endpoint - публичный, без аутентификации
+	build := buildinfo.Get("orders-service")
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
 			"status":  "ok",
 			"service": "orders-service",
+			"build":   build,
 		})
 	})
 
+	// Сведения о сборке (коммит, время сборки, версия Go) - публичный
+	router.GET("/version", gin.WrapF(buildinfo.Handler("orders-service")))
+
 	// Prometheus metrics endpoint
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
+	// Поиск гостевого заказа по ID или номеру и email - публичный, магазин из заголовка X-Tenant-ID
+	router.GET("/orders/lookup", tenant.Middleware(), orderHandler.LookupGuestOrder)
+
 	// Orders endpoints - все требуют аутентификации
+	// Гостевой токен (оформление без аккаунта) допускает только создание и просмотр своих заказов
 	orders := router.Group("/orders")
-	orders.Use(authMiddleware.Authenticate()) // Все маршруты требуют JWT токен
+	orders.Use(authMiddleware.Authenticate(), tenant.Middleware()) // Все маршруты требуют JWT токен
 	{
+		denyGuest := authMiddleware.DenyGuest()
+
 		// Базовые операции с заказами
-		orders.POST("/", orderHandler.CreateOrder)           // Создать заказ
-		orders.GET("/", orderHandler.GetUserOrders)          // Получить все заказы пользователя
-		orders.GET("/:id", orderHandler.GetOrder)            // Получить заказ по ID
-		orders.PATCH("/:id", orderHandler.UpdateOrderStatus) // Обновить статус заказа
-		orders.DELETE("/:id", orderHandler.DeleteOrder)      // Удалить заказ
+		orders.POST("/", orderHandler.CreateOrder)                           // Создать заказ
+		orders.POST("/preview", orderHandler.PreviewOrder)                   // Расчет заказа перед оформлением без сохранения
+		orders.GET("/", compress, orderHandler.GetUserOrders)                // Получить все заказы пользователя
+		orders.GET("/stats", denyGuest, orderHandler.GetUserOrderStats)      // Статистика заказов пользователя для личного кабинета
+		orders.GET("/:id", orderHandler.GetOrder)                            // Получить заказ по ID
+		orders.GET("/by-number/:number", orderHandler.GetOrderByNumber)      // Получить заказ по номеру
+		orders.GET("/:id/invoice", orderHandler.GetInvoice)                  // Счет с налоговой разбивкой
+		orders.PATCH("/:id", denyGuest, orderHandler.UpdateOrderStatus)      // Обновить статус заказа
+		orders.PATCH("/:id/items", denyGuest, orderHandler.UpdateOrderItems) // Изменить количество позиций до подтверждения
+		orders.DELETE("/:id", denyGuest, orderHandler.DeleteOrder)           // Удалить заказ
+
+		// Привязка заказов гостевой сессии к аккаунту после регистрации
+		orders.POST("/link-guest", denyGuest, authMiddleware.RequireGuestToken(), orderHandler.LinkGuestOrders)
+
+		// Заметки поддержки: видимость зависит от роли
+		orders.GET("/:id/notes", denyGuest, noteHandler.GetNotes) // Заметки заказа
+		orders.POST("/:id/notes", denyGuest, noteHandler.AddNote) // Добавить заметку
 	}
 
+	// Admin API заказов: список с числом заметок и отправления (manager, admin)
+	adminOrders := router.Group("/admin/orders")
+	adminOrders.Use(authMiddleware.Authenticate(), tenant.Middleware(), authMiddleware.RequireRole("manager", "admin"))
+	{
+		adminOrders.GET("", compress, noteHandler.ListOrders) // Заказы магазина с числом заметок
+
+		// Массовая смена статуса (подтверждение заказов складом), отчет по каждому заказу
+		adminOrders.POST("/bulk-status", orderHandler.BulkUpdateOrderStatus)
+
+		adminOrders.GET("/:id/shipments", shipmentHandler.GetShipments)                        // Отправления заказа
+		adminOrders.POST("/:id/shipments", shipmentHandler.CreateShipment)                     // Отправить часть позиций
+		adminOrders.PATCH("/:id/shipments/:shipment_id", shipmentHandler.UpdateShipmentStatus) // Отметить доставку
+
+		// Legal hold исключает заказ из обезличивания и удаления по сроку хранения (только admin)
+		adminOrders.PUT("/:id/legal-hold", authMiddleware.RequireRole("admin"), retentionHandler.SetLegalHold)
+	}
+
+	// Сводка для внутренней панели: заказы по статусам и выручка за сегодня (manager, admin)
+	router.GET("/admin/summary", authMiddleware.Authenticate(), tenant.Middleware(), authMiddleware.RequireRole("manager", "admin"), orderHandler.GetDashboardSummary)
+
+	// Политика хранения завершенных заказов и число заказов на legal hold (manager, admin)
+	router.GET("/admin/retention", authMiddleware.Authenticate(), tenant.Middleware(), authMiddleware.RequireRole("manager", "admin"), retentionHandler.GetRetentionPolicy)
+
+	// Ставки налогов магазина по странам и категориям (только admin)
+	taxRates := router.Group("/admin/tax-rates")
+	taxRates.Use(authMiddleware.Authenticate(), tenant.Middleware(), authMiddleware.RequireRole("admin"))
+	{
+		taxRates.GET("", taxHandler.GetTaxRates)          // Все ставки магазина
+		taxRates.PUT("", taxHandler.UpsertTaxRate)        // Установить ставку страны или категории
+		taxRates.DELETE("/:id", taxHandler.DeleteTaxRate) // Удалить ставку
+	}
+
+	// Флаги функциональности - переключение раскатки (только admin)
+	flags := router.Group("/admin/flags")
+	flags.Use(authMiddleware.Authenticate(), authMiddleware.RequireRole("admin"))
+	flagsHandler.RegisterRoutes(flags)
+
 	return router
 }
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/service"
+	"augustberries/pkg/validate"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ShipmentHandler обрабатывает admin API отправлений заказов
+type ShipmentHandler struct {
+	shipmentService *service.ShipmentService
+	validator       *validate.Validator
+}
+
+// NewShipmentHandler создает новый обработчик отправлений
+func NewShipmentHandler(shipmentService *service.ShipmentService) *ShipmentHandler {
+	return &ShipmentHandler{
+		shipmentService: shipmentService,
+		validator:       validate.New(),
+	}
+}
+
+// CreateShipment обрабатывает POST /admin/orders/{id}/shipments
+// Отправляет часть позиций заказа с трек-номером перевозчика
+func (h *ShipmentHandler) CreateShipment(c *gin.Context) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	var req entity.CreateShipmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+
+	shipment, order, err := h.shipmentService.CreateShipment(c.Request.Context(), orderID, &req, adminActor(c))
+	if err != nil {
+		if errors.Is(err, service.ErrOrderNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidOrderStatus) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Order cannot be shipped in its current status"})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidShipment) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrOrderStatusConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Order status was changed by another request", "code": "ORDER_STATUS_CONFLICT"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create shipment"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"shipment":     shipment,
+		"order_status": order.Status,
+	})
+}
+
+// GetShipments обрабатывает GET /admin/orders/{id}/shipments
+func (h *ShipmentHandler) GetShipments(c *gin.Context) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	response, err := h.shipmentService.GetShipments(c.Request.Context(), orderID)
+	if err != nil {
+		if errors.Is(err, service.ErrOrderNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get shipments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateShipmentStatus обрабатывает PATCH /admin/orders/{id}/shipments/{shipment_id}
+// Отмечает отправление доставленным; статус заказа пересчитывается
+func (h *ShipmentHandler) UpdateShipmentStatus(c *gin.Context) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	shipmentID, err := uuid.Parse(c.Param("shipment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shipment ID"})
+		return
+	}
+
+	var req entity.UpdateShipmentStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+
+	shipment, order, err := h.shipmentService.UpdateShipmentStatus(c.Request.Context(), orderID, shipmentID, req.Status, adminActor(c))
+	if err != nil {
+		if errors.Is(err, service.ErrOrderNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+			return
+		}
+		if errors.Is(err, service.ErrShipmentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Shipment not found"})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidShipmentStatus) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shipment status transition"})
+			return
+		}
+		if errors.Is(err, service.ErrOrderStatusConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Order status was changed by another request", "code": "ORDER_STATUS_CONFLICT"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update shipment status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"shipment":     shipment,
+		"order_status": order.Status,
+	})
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/service"
+	"augustberries/pkg/validate"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TaxHandler обрабатывает HTTP запросы для ставок налогов магазина
+type TaxHandler struct {
+	taxEngine *service.TaxEngine
+	validator *validate.Validator
+}
+
+// NewTaxHandler создает новый обработчик ставок налогов
+func NewTaxHandler(taxEngine *service.TaxEngine) *TaxHandler {
+	return &TaxHandler{
+		taxEngine: taxEngine,
+		validator: validate.New(),
+	}
+}
+
+// GetTaxRates обрабатывает GET /admin/tax-rates
+func (h *TaxHandler) GetTaxRates(c *gin.Context) {
+	rates, err := h.taxEngine.GetRates(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get tax rates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.TaxRateListResponse{
+		TaxRates: rates,
+		Total:    len(rates),
+	})
+}
+
+// UpsertTaxRate обрабатывает PUT /admin/tax-rates
+// Устанавливает ставку страны (без category_id) или категории в стране
+func (h *TaxHandler) UpsertTaxRate(c *gin.Context) {
+	var req entity.UpsertTaxRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+
+	rate, err := h.taxEngine.UpsertRate(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save tax rate"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rate)
+}
+
+// DeleteTaxRate обрабатывает DELETE /admin/tax-rates/:id
+func (h *TaxHandler) DeleteTaxRate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tax rate ID"})
+		return
+	}
+
+	if err := h.taxEngine.DeleteRate(c.Request.Context(), id); err != nil {
+		if errors.Is(err, service.ErrTaxRateNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tax rate not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete tax rate"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.SuccessResponse{
+		Message: "Tax rate deleted successfully",
+	})
+}
@@ -2,34 +2,54 @@ package http
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"net/http"
+	"errors"
+	"sort"
+	"strings"
 	"time"
 
 	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/infrastructure"
+	"augustberries/pkg/clients"
+	"augustberries/pkg/clients/catalog"
+	"augustberries/pkg/tenant"
 
 	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
 )
 
 // CatalogClient клиент для взаимодействия с Catalog Service
 // Используется для проверки цен товаров при создании заказа
+// Ответы GetProduct и GetAvailability кешируются в памяти на короткое время, одновременные
+// запросы одних и тех же товаров объединяются в один. Устаревшая цена из кеша не попадет
+// в заказ: перед сохранением цены подтверждаются через ValidateProducts без кеша
+// Повторы при временных ошибках и circuit breaker - в pkg/clients
 type CatalogClient struct {
-	baseURL    string
-	httpClient *http.Client
-	authToken  string // JWT токен для аутентификации в Catalog Service
+	catalog   *catalog.Client
+	authToken string // JWT токен для аутентификации в Catalog Service
+
+	products     *lruCache[entity.ProductWithCategory] // nil - кеш отключен
+	availability *lruCache[entity.ProductAvailability]
+	inflight     singleflight.Group
 }
 
+// sharedFetchTimeout ограничивает общую загрузку singleflight: она не зависит от контекста
+// вызывающих, поэтому без своего таймаута могла бы ждать каталог бесконечно
+const sharedFetchTimeout = 30 * time.Second
+
 // NewCatalogClient создает новый клиент для Catalog Service
-func NewCatalogClient(baseURL string) *CatalogClient {
+func NewCatalogClient(baseURL string, cache CacheConfig) *CatalogClient {
 	return &CatalogClient{
-		baseURL: baseURL,
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second, // Таймаут для HTTP запросов
-		},
+		catalog:      catalog.New(clients.DefaultConfig(baseURL)),
+		products:     newLRUCache[entity.ProductWithCategory]("catalog_products", cache),
+		availability: newLRUCache[entity.ProductAvailability]("catalog_availability", cache),
 	}
 }
 
+// cacheKey - ключ кеша: каталоги магазинов не пересекаются
+func cacheKey(ctx context.Context, productID uuid.UUID) string {
+	return tenant.FromContext(ctx) + ":" + productID.String()
+}
+
 // SetAuthToken устанавливает JWT токен для аутентификации
 func (c *CatalogClient) SetAuthToken(token string) {
 	c.authToken = token
@@ -38,54 +58,199 @@ func (c *CatalogClient) SetAuthToken(token string) {
 // GetProduct получает информацию о товаре из Catalog Service
 // Используется для проверки актуальности цены при создании заказа
 func (c *CatalogClient) GetProduct(ctx context.Context, productID uuid.UUID) (*entity.ProductWithCategory, error) {
-	url := fmt.Sprintf("%s/products/%s", c.baseURL, productID.String())
+	key := cacheKey(ctx, productID)
+	if product, ok := c.products.Get(key); ok {
+		return &product, nil
+	}
+
+	result, err := c.shared(ctx, "product:"+key, func(ctx context.Context) (interface{}, error) {
+		product, err := c.fetchProduct(ctx, productID)
+		if err != nil {
+			return nil, err
+		}
+		c.products.Set(key, *product)
+		return *product, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Каждый вызывающий получает свою копию, общий результат singleflight не изменяется
+	product := result.(entity.ProductWithCategory)
+	return &product, nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+// fetchProduct запрашивает товар в Catalog Service
+func (c *CatalogClient) fetchProduct(ctx context.Context, productID uuid.UUID) (*entity.ProductWithCategory, error) {
+	products, err := c.fetchBatch(ctx, []uuid.UUID{productID})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	// Добавляем JWT токен для аутентификации
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	product, ok := products[productID]
+	if !ok {
+		return nil, infrastructure.ErrProductNotFound
+	}
+	return withCategory(product), nil
+}
+
+// GetAvailability получает цены, статусы и остатки нескольких товаров одним запросом GET /products/batch
+// Товары из кеша не запрашиваются, отсутствующие в каталоге товары не кешируются
+func (c *CatalogClient) GetAvailability(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID]*entity.ProductAvailability, error) {
+	products := make(map[uuid.UUID]*entity.ProductAvailability, len(productIDs))
+	var missing []uuid.UUID
+	seen := make(map[uuid.UUID]struct{}, len(productIDs))
+	for _, id := range productIDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		if product, ok := c.availability.Get(cacheKey(ctx, id)); ok {
+			products[id] = &product
+			continue
+		}
+		missing = append(missing, id)
+	}
+	if len(missing) == 0 {
+		return products, nil
 	}
 
-	resp, err := c.httpClient.Do(req)
+	// Одинаковые наборы товаров, запрошенные одновременно, загружаются один раз
+	keys := make([]string, len(missing))
+	for i, id := range missing {
+		keys[i] = id.String()
+	}
+	sort.Strings(keys)
+
+	result, err := c.shared(ctx, "availability:"+tenant.FromContext(ctx)+":"+strings.Join(keys, ","), func(ctx context.Context) (interface{}, error) {
+		fetched, err := c.fetchAvailability(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+		for _, product := range fetched {
+			c.availability.Set(cacheKey(ctx, product.ID), product)
+		}
+		return fetched, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("product not found")
+	for _, product := range result.([]entity.ProductAvailability) {
+		product := product
+		products[product.ID] = &product
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	return products, nil
+}
+
+// shared выполняет fn один раз для одновременных вызовов с одинаковым key
+// fn работает на контексте первого вызывающего без отмены и со своим таймаутом: отмена его запроса
+// не прерывает загрузку для остальных. Каждый вызывающий ждет результат, пока не отменен его ctx
+func (c *CatalogClient) shared(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	ch := c.inflight.DoChan(key, func() (interface{}, error) {
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sharedFetchTimeout)
+		defer cancel()
+		return fn(fetchCtx)
+	})
+
+	select {
+	case res := <-ch:
+		return res.Val, res.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
+}
 
-	var product entity.ProductWithCategory
-	if err := json.NewDecoder(resp.Body).Decode(&product); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+// fetchAvailability запрашивает доступность товаров в Catalog Service
+func (c *CatalogClient) fetchAvailability(ctx context.Context, productIDs []uuid.UUID) ([]entity.ProductAvailability, error) {
+	products, err := c.fetchBatch(ctx, productIDs)
+	if err != nil {
+		return nil, err
 	}
 
-	return &product, nil
+	availability := make([]entity.ProductAvailability, 0, len(products))
+	for _, product := range products {
+		availability = append(availability, availabilityOf(product))
+	}
+	return availability, nil
 }
 
-// GetProducts получает информацию о нескольких товарах
-// Можно использовать для оптимизации запросов при создании заказа с несколькими позициями
-func (c *CatalogClient) GetProducts(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID]*entity.ProductWithCategory, error) {
-	products := make(map[uuid.UUID]*entity.ProductWithCategory)
+func withCategory(p catalog.Product) *entity.ProductWithCategory {
+	product := &entity.ProductWithCategory{Product: entity.Product{
+		ID:         p.ID,
+		Name:       p.Name,
+		Price:      p.Price,
+		CategoryID: p.CategoryID,
+		Status:     p.Status,
+	}}
+	if p.Category != nil {
+		product.Category = entity.Category{ID: p.Category.ID, Name: p.Category.Name}
+	}
+	return product
+}
 
-	// В реальном проекте здесь можно сделать batch запрос к Catalog Service
-	// Но так как в API нет batch endpoint, делаем последовательные запросы
-	for _, productID := range productIDs {
-		product, err := c.GetProduct(ctx, productID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get product %s: %w", productID, err)
-		}
-		products[productID] = product
+func availabilityOf(p catalog.Product) entity.ProductAvailability {
+	availability := entity.ProductAvailability{
+		ID:           p.ID,
+		Name:         p.Name,
+		Description:  p.Description,
+		Price:        p.Price,
+		CategoryID:   p.CategoryID,
+		Status:       p.Status,
+		Stock:        p.Stock,
+		Available:    p.Status == entity.ProductStatusPublished && (p.Stock == nil || *p.Stock > 0),
+		Unit:         p.Unit,
+		QuantityStep: p.QuantityStep,
+
+		MinQty:           p.MinQty,
+		MaxQty:           p.MaxQty,
+		PerCustomerLimit: p.PerCustomerLimit,
+	}
+	if p.Category != nil {
+		availability.CategoryName = p.Category.Name
 	}
+	return availability
+}
 
-	return products, nil
+// withToken передает в каталог токен, установленный SetAuthToken
+func (c *CatalogClient) withToken(ctx context.Context) context.Context {
+	if c.authToken == "" {
+		return ctx
+	}
+	return clients.WithBearerToken(ctx, c.authToken)
+}
+
+// fetchBatch запрашивает товары публичным GET /products/batch; ненайденных и скрытых товаров в карте нет
+// Запрос выполняется в каталоге того же магазина, что и заказ
+func (c *CatalogClient) fetchBatch(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID]catalog.Product, error) {
+	batch, err := c.catalog.GetProducts(c.withToken(ctx), productIDs)
+	if err != nil {
+		return nil, err
+	}
+	return batch.Products, nil
+}
+
+// ValidateProducts подтверждает существование товаров и их цены в Catalog Service
+// Возвращает подписанный токен котировки, который проверяется перед сохранением заказа
+func (c *CatalogClient) ValidateProducts(ctx context.Context, items []entity.OrderItemRequest) (string, error) {
+	quoteItems := make([]catalog.QuoteItem, len(items))
+	for i, item := range items {
+		quoteItems[i] = catalog.QuoteItem{ProductID: item.ProductID, Quantity: item.Quantity}
+	}
+
+	quote, err := c.catalog.CreateQuote(c.withToken(ctx), quoteItems)
+	switch {
+	case err == nil:
+		return quote.Token, nil
+	case errors.Is(err, catalog.ErrProductNotFound):
+		return "", infrastructure.ErrProductNotFound
+	case errors.Is(err, catalog.ErrProductArchived):
+		return "", infrastructure.ErrProductArchived
+	case errors.Is(err, catalog.ErrProductNotAvailable):
+		return "", infrastructure.ErrProductNotAvailable
+	default:
+		return "", err
+	}
 }
@@ -0,0 +1,185 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/infrastructure"
+	"augustberries/pkg/money"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchServer имитирует GET /products/batch и считает запросы
+func batchServer(t *testing.T, requests *int32, delay time.Duration) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		time.Sleep(delay)
+		assert.Equal(t, "/products/batch", r.URL.Path)
+
+		products := map[uuid.UUID]interface{}{}
+		for _, raw := range strings.Split(r.URL.Query().Get("ids"), ",") {
+			id := uuid.MustParse(raw)
+			products[id] = map[string]interface{}{
+				"id":       id,
+				"name":     "Berry box",
+				"price":    money.MustParse("10.00"),
+				"status":   entity.ProductStatusPublished,
+				"category": map[string]interface{}{"id": uuid.New(), "name": "Berries"},
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"products": products, "missing": []uuid.UUID{}})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// ==================== GetAvailability Tests ====================
+
+func TestGetAvailability_CachesProducts(t *testing.T) {
+	// Arrange
+	var requests int32
+	server := batchServer(t, &requests, 0)
+	client := NewCatalogClient(server.URL, CacheConfig{Size: 100, TTL: time.Minute})
+	first, second := uuid.New(), uuid.New()
+
+	// Act
+	_, err := client.GetAvailability(context.Background(), []uuid.UUID{first, first})
+	require.NoError(t, err)
+	products, err := client.GetAvailability(context.Background(), []uuid.UUID{first, second})
+	require.NoError(t, err)
+
+	// Assert: во втором запросе из каталога запрашивается только second
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	assert.Len(t, products, 2)
+	assert.Equal(t, money.MustParse("10.00"), products[first].Price)
+	assert.Equal(t, "Berries", products[first].CategoryName)
+	assert.True(t, products[first].Available)
+}
+
+func TestGetAvailability_ConcurrentRequestsShareFetch(t *testing.T) {
+	// Arrange
+	var requests int32
+	server := batchServer(t, &requests, 50*time.Millisecond)
+	client := NewCatalogClient(server.URL, CacheConfig{Size: 100, TTL: time.Minute})
+	productID := uuid.New()
+
+	// Act
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			products, err := client.GetAvailability(context.Background(), []uuid.UUID{productID})
+			assert.NoError(t, err)
+			assert.Contains(t, products, productID)
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestGetProduct_CanceledCallerDoesNotFailSharedFetch(t *testing.T) {
+	// Arrange
+	var requests int32
+	server := batchServer(t, &requests, 100*time.Millisecond)
+	client := NewCatalogClient(server.URL, CacheConfig{Size: 100, TTL: time.Minute})
+	productID := uuid.New()
+
+	// Первый вызывающий запускает общую загрузку и отменяет свой запрос до ответа каталога
+	firstCtx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := client.GetProduct(firstCtx, productID)
+		firstErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	// Act
+	secondDone := make(chan struct{})
+	var product *entity.ProductWithCategory
+	var err error
+	go func() {
+		defer close(secondDone)
+		product, err = client.GetProduct(context.Background(), productID)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	<-secondDone
+
+	// Assert: первый получает свою отмену, второй - товар из той же загрузки
+	assert.ErrorIs(t, <-firstErr, context.Canceled)
+	require.NoError(t, err)
+	assert.Equal(t, productID, product.ID)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestGetAvailability_CacheDisabled(t *testing.T) {
+	// Arrange
+	var requests int32
+	server := batchServer(t, &requests, 0)
+	client := NewCatalogClient(server.URL, CacheConfig{})
+	productID := uuid.New()
+
+	// Act
+	for i := 0; i < 3; i++ {
+		_, err := client.GetAvailability(context.Background(), []uuid.UUID{productID})
+		require.NoError(t, err)
+	}
+
+	// Assert
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+}
+
+func TestGetAvailability_MissingAndOutOfStock(t *testing.T) {
+	// Arrange
+	inStock, outOfStock, missing := uuid.New(), uuid.New(), uuid.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"products":{` +
+			`"` + inStock.String() + `":{"id":"` + inStock.String() + `","price":"5.00","status":"published","stock":3},` +
+			`"` + outOfStock.String() + `":{"id":"` + outOfStock.String() + `","price":"5.00","status":"published","stock":0}` +
+			`},"missing":["` + missing.String() + `"]}`))
+	}))
+	t.Cleanup(server.Close)
+	client := NewCatalogClient(server.URL, CacheConfig{})
+
+	// Act
+	products, err := client.GetAvailability(context.Background(), []uuid.UUID{inStock, outOfStock, missing})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, products, 2)
+	assert.True(t, products[inStock].Available)
+	assert.False(t, products[outOfStock].Available)
+	assert.NotContains(t, products, missing)
+}
+
+// ==================== GetProduct Tests ====================
+
+func TestGetProduct_NotFound(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"products": map[string]interface{}{}, "missing": []string{r.URL.Query().Get("ids")}})
+	}))
+	t.Cleanup(server.Close)
+	client := NewCatalogClient(server.URL, CacheConfig{})
+
+	// Act
+	_, err := client.GetProduct(context.Background(), uuid.New())
+
+	// Assert
+	assert.ErrorIs(t, err, infrastructure.ErrProductNotFound)
+}
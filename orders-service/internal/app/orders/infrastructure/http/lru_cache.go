@@ -0,0 +1,122 @@
+package http
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"augustberries/pkg/metrics"
+)
+
+// metricsService - имя сервиса в метках метрик кеша
+const metricsService = "orders-service"
+
+// CacheConfig - настройки кеша ответов Catalog Service в памяти процесса
+// Size или TTL равные нулю отключают кеш
+type CacheConfig struct {
+	Size int           // Максимум записей, при переполнении вытесняются давно не использованные
+	TTL  time.Duration // Время жизни записи
+}
+
+// lruCache - потокобезопасный LRU кеш с ограниченным временем жизни записей
+// Нулевой (nil) кеш ничего не хранит: Get всегда промахивается, Set ничего не делает
+type lruCache[V any] struct {
+	name  string // Имя кеша для меток метрик
+	size  int
+	ttl   time.Duration
+	now   func() time.Time
+	mu    sync.Mutex
+	items map[string]*list.Element
+	order *list.List // Начало списка - недавно использованные записи
+}
+
+type lruEntry[V any] struct {
+	key       string
+	value     V
+	expiresAt time.Time
+}
+
+// newLRUCache создает кеш или возвращает nil, если кеш отключен настройками
+func newLRUCache[V any](name string, cfg CacheConfig) *lruCache[V] {
+	if cfg.Size <= 0 || cfg.TTL <= 0 {
+		return nil
+	}
+	return &lruCache[V]{
+		name:  name,
+		size:  cfg.Size,
+		ttl:   cfg.TTL,
+		now:   time.Now,
+		items: make(map[string]*list.Element, cfg.Size),
+		order: list.New(),
+	}
+}
+
+// Get возвращает неистекшее значение по ключу
+func (c *lruCache[V]) Get(key string) (V, bool) {
+	var zero V
+	if c == nil {
+		return zero, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		metrics.RecordLocalCacheMiss(metricsService, c.name)
+		return zero, false
+	}
+
+	entry := elem.Value.(*lruEntry[V])
+	if !c.now().Before(entry.expiresAt) {
+		c.remove(elem)
+		metrics.RecordLocalCacheMiss(metricsService, c.name)
+		return zero, false
+	}
+
+	c.order.MoveToFront(elem)
+	metrics.RecordLocalCacheHit(metricsService, c.name)
+	return entry.value, true
+}
+
+// Set сохраняет значение, вытесняя самую давно использованную запись при переполнении
+func (c *lruCache[V]) Set(key string, value V) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(c.ttl)
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*lruEntry[V])
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry[V]{key: key, value: value, expiresAt: expiresAt})
+	if c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+	metrics.LocalCacheEntries.WithLabelValues(metricsService, c.name).Set(float64(c.order.Len()))
+}
+
+// Len возвращает число записей, включая еще не удаленные истекшие
+func (c *lruCache[V]) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// remove удаляет запись, вызывается под блокировкой
+func (c *lruCache[V]) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.items, elem.Value.(*lruEntry[V]).key)
+	metrics.LocalCacheEntries.WithLabelValues(metricsService, c.name).Set(float64(c.order.Len()))
+}
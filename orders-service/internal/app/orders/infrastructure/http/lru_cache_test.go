@@ -0,0 +1,64 @@
+package http
+
+import (
+	"testing"
+	"time"
+
+	"augustberries/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// ==================== lruCache Tests ====================
+
+func TestLRUCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	// Arrange
+	cache := newLRUCache[int]("test_lru", CacheConfig{Size: 2, TTL: time.Minute})
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Get("a") // "a" становится недавно использованной
+
+	// Act
+	cache.Set("c", 3)
+
+	// Assert
+	_, okA := cache.Get("a")
+	_, okB := cache.Get("b")
+	_, okC := cache.Get("c")
+	assert.True(t, okA)
+	assert.False(t, okB)
+	assert.True(t, okC)
+	assert.Equal(t, 2, cache.Len())
+}
+
+func TestLRUCache_ExpiredEntryIsMiss(t *testing.T) {
+	// Arrange
+	now := time.Now()
+	cache := newLRUCache[int]("test_lru_ttl", CacheConfig{Size: 10, TTL: time.Second})
+	cache.now = func() time.Time { return now }
+	cache.Set("a", 1)
+
+	// Act
+	_, fresh := cache.Get("a")
+	now = now.Add(time.Second)
+	_, expired := cache.Get("a")
+
+	// Assert
+	assert.True(t, fresh)
+	assert.False(t, expired)
+	assert.Zero(t, cache.Len())
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.LocalCacheHits.WithLabelValues(metricsService, "test_lru_ttl")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.LocalCacheMisses.WithLabelValues(metricsService, "test_lru_ttl")))
+}
+
+func TestLRUCache_DisabledCache(t *testing.T) {
+	cache := newLRUCache[int]("test_lru_disabled", CacheConfig{Size: 10})
+	assert.Nil(t, cache)
+
+	cache.Set("a", 1)
+	_, ok := cache.Get("a")
+
+	assert.False(t, ok)
+	assert.Zero(t, cache.Len())
+}
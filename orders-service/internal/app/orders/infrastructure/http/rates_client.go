@@ -0,0 +1,28 @@
+package http
+
+import (
+	"context"
+
+	"augustberries/pkg/clients"
+	"augustberries/pkg/clients/worker"
+)
+
+// RatesClient клиент курсов валют Background Worker Service
+// Курс тот же, по которому worker конвертирует заказы после ORDER_CREATED
+type RatesClient struct {
+	worker *worker.Client
+}
+
+// NewRatesClient создает клиент курсов валют
+func NewRatesClient(baseURL string) *RatesClient {
+	return &RatesClient{worker: worker.New(clients.DefaultConfig(baseURL))}
+}
+
+// GetRate возвращает курс конвертации сумм из from в to
+func (c *RatesClient) GetRate(ctx context.Context, from, to string) (float64, error) {
+	rate, err := c.worker.GetRate(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+	return rate.Rate, nil
+}
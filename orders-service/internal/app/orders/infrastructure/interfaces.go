@@ -2,12 +2,22 @@ package infrastructure
 
 import (
 	"context"
+	"errors"
 
 	"augustberries/orders-service/internal/app/orders/entity"
 
 	"github.com/google/uuid"
 )
 
+var (
+	// ErrProductNotFound - Catalog Service не нашел товар (или товар не опубликован)
+	ErrProductNotFound = errors.New("product not found in catalog")
+	// ErrProductNotAvailable - Catalog Service отказал в подтверждении товара (не опубликован)
+	ErrProductNotAvailable = errors.New("product not available in catalog")
+	// ErrProductArchived - товар снят с продажи или удален из каталога
+	ErrProductArchived = errors.New("product archived in catalog")
+)
+
 // MessagePublisher интерфейс для отправки сообщений в очередь (Kafka)
 // Используется для dependency injection и упрощения тестирования
 type MessagePublisher interface {
@@ -20,5 +30,14 @@ type MessagePublisher interface {
 type CatalogServiceClient interface {
 	SetAuthToken(token string)
 	GetProduct(ctx context.Context, productID uuid.UUID) (*entity.ProductWithCategory, error)
-	GetProducts(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID]*entity.ProductWithCategory, error)
+	// GetAvailability возвращает цену, статус и остаток товаров одним запросом; отсутствующих товаров в карте нет
+	GetAvailability(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID]*entity.ProductAvailability, error)
+	// ValidateProducts подтверждает товары и цены, возвращает подписанный токен котировки
+	ValidateProducts(ctx context.Context, items []entity.OrderItemRequest) (string, error)
+}
+
+// ExchangeRateClient получает курсы валют Background Worker Service
+// Используется предпросмотром заказа, чтобы показать итог в валюте, в которую worker сконвертирует заказ
+type ExchangeRateClient interface {
+	GetRate(ctx context.Context, from, to string) (float64, error)
 }
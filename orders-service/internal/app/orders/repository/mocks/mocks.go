@@ -2,8 +2,11 @@ package mocks
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/pkg/units"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
@@ -35,8 +38,13 @@ func (m *MockOrderRepository) GetByUserID(ctx context.Context, userID uuid.UUID)
 	return args.Get(0).([]entity.Order), args.Error(1)
 }
 
-func (m *MockOrderRepository) Update(ctx context.Context, order *entity.Order) error {
-	args := m.Called(ctx, order)
+func (m *MockOrderRepository) UpdateDeliveryEstimate(ctx context.Context, id uuid.UUID, from, to *time.Time) error {
+	args := m.Called(ctx, id, from, to)
+	return args.Error(0)
+}
+
+func (m *MockOrderRepository) UpdateStatus(ctx context.Context, id uuid.UUID, from, to entity.OrderStatus) error {
+	args := m.Called(ctx, id, from, to)
 	return args.Error(0)
 }
 
@@ -53,6 +61,79 @@ func (m *MockOrderRepository) GetWithItems(ctx context.Context, id uuid.UUID) (*
 	return args.Get(0).(*entity.OrderWithItems), args.Error(1)
 }
 
+func (m *MockOrderRepository) List(ctx context.Context, filter entity.OrderFilter) ([]entity.Order, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Order), args.Error(1)
+}
+
+func (m *MockOrderRepository) GetByNumberWithItems(ctx context.Context, number string) (*entity.OrderWithItems, error) {
+	args := m.Called(ctx, number)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.OrderWithItems), args.Error(1)
+}
+
+func (m *MockOrderRepository) ReassignGuestOrders(ctx context.Context, guestID, userID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, guestID, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockOrderRepository) CountByStatus(ctx context.Context) (map[entity.OrderStatus]int64, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[entity.OrderStatus]int64), args.Error(1)
+}
+
+func (m *MockOrderRepository) RevenueSince(ctx context.Context, since time.Time) ([]entity.CurrencyRevenue, error) {
+	args := m.Called(ctx, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.CurrencyRevenue), args.Error(1)
+}
+
+func (m *MockOrderRepository) UserStats(ctx context.Context, userID uuid.UUID) (*entity.UserOrderStats, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.UserOrderStats), args.Error(1)
+}
+
+func (m *MockOrderRepository) ListDueScheduled(ctx context.Context, now time.Time, limit int) ([]entity.Order, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Order), args.Error(1)
+}
+
+func (m *MockOrderRepository) UpdateItems(ctx context.Context, order *entity.Order, items []entity.OrderItem, removed []uuid.UUID) error {
+	args := m.Called(ctx, order, items, removed)
+	return args.Error(0)
+}
+
+func (m *MockOrderRepository) ActivateScheduled(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockOrderNumberRepository мок для OrderNumberRepository
+type MockOrderNumberRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderNumberRepository) Next(ctx context.Context, day time.Time, step int64) (int64, error) {
+	args := m.Called(ctx, day, step)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // MockOrderItemRepository мок для OrderItemRepository
 type MockOrderItemRepository struct {
 	mock.Mock
@@ -76,6 +157,163 @@ func (m *MockOrderItemRepository) DeleteByOrderID(ctx context.Context, orderID u
 	return args.Error(0)
 }
 
+func (m *MockOrderItemRepository) SumQuantitiesByUser(ctx context.Context, userID uuid.UUID, productIDs []uuid.UUID, excludeOrderID uuid.UUID) (map[uuid.UUID]units.Quantity, error) {
+	args := m.Called(ctx, userID, productIDs, excludeOrderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID]units.Quantity), args.Error(1)
+}
+
+// MockShipmentRepository мок для ShipmentRepository
+type MockShipmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockShipmentRepository) Create(ctx context.Context, shipment *entity.Shipment) error {
+	args := m.Called(ctx, shipment)
+	return args.Error(0)
+}
+
+func (m *MockShipmentRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Shipment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Shipment), args.Error(1)
+}
+
+func (m *MockShipmentRepository) GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]entity.Shipment, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Shipment), args.Error(1)
+}
+
+func (m *MockShipmentRepository) UpdateStatus(ctx context.Context, shipment *entity.Shipment) error {
+	args := m.Called(ctx, shipment)
+	return args.Error(0)
+}
+
+// MockOrderNoteRepository мок для OrderNoteRepository
+type MockOrderNoteRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderNoteRepository) Create(ctx context.Context, note *entity.OrderNote) error {
+	args := m.Called(ctx, note)
+	return args.Error(0)
+}
+
+func (m *MockOrderNoteRepository) GetByOrderID(ctx context.Context, orderID uuid.UUID, includeInternal bool) ([]entity.OrderNote, error) {
+	args := m.Called(ctx, orderID, includeInternal)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.OrderNote), args.Error(1)
+}
+
+func (m *MockOrderNoteRepository) CountByOrderIDs(ctx context.Context, orderIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	args := m.Called(ctx, orderIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID]int), args.Error(1)
+}
+
+// MockOrderSummaryRepository мок для OrderSummaryRepository
+type MockOrderSummaryRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderSummaryRepository) Refresh(ctx context.Context, orderIDs ...uuid.UUID) error {
+	args := m.Called(ctx, orderIDs)
+	return args.Error(0)
+}
+
+func (m *MockOrderSummaryRepository) RefreshUser(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockOrderSummaryRepository) List(ctx context.Context, filter entity.OrderFilter) ([]entity.OrderSummary, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.OrderSummary), args.Error(1)
+}
+
+func (m *MockOrderSummaryRepository) Rebuild(ctx context.Context, batchSize int) (int64, error) {
+	args := m.Called(ctx, batchSize)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockRetentionRepository мок для RetentionRepository
+type MockRetentionRepository struct {
+	mock.Mock
+}
+
+func (m *MockRetentionRepository) ListExpired(ctx context.Context, statuses []entity.OrderStatus, before time.Time, limit int) ([]entity.Order, error) {
+	args := m.Called(ctx, statuses, before, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Order), args.Error(1)
+}
+
+func (m *MockRetentionRepository) Anonymize(ctx context.Context, id uuid.UUID, now time.Time) error {
+	args := m.Called(ctx, id, now)
+	return args.Error(0)
+}
+
+func (m *MockRetentionRepository) Purge(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRetentionRepository) SetLegalHold(ctx context.Context, id uuid.UUID, hold bool) error {
+	args := m.Called(ctx, id, hold)
+	return args.Error(0)
+}
+
+func (m *MockRetentionRepository) CountLegalHolds(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockTaxRateRepository мок для TaxRateRepository
+type MockTaxRateRepository struct {
+	mock.Mock
+}
+
+func (m *MockTaxRateRepository) Upsert(ctx context.Context, rate *entity.TaxRate) error {
+	args := m.Called(ctx, rate)
+	return args.Error(0)
+}
+
+func (m *MockTaxRateRepository) GetAll(ctx context.Context) ([]entity.TaxRate, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.TaxRate), args.Error(1)
+}
+
+func (m *MockTaxRateRepository) GetByCountry(ctx context.Context, country string) ([]entity.TaxRate, error) {
+	args := m.Called(ctx, country)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.TaxRate), args.Error(1)
+}
+
+func (m *MockTaxRateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 // MockCatalogServiceClient мок для CatalogServiceClient
 type MockCatalogServiceClient struct {
 	mock.Mock
@@ -94,22 +332,40 @@ func (m *MockCatalogServiceClient) GetProduct(ctx context.Context, productID uui
 	return args.Get(0).(*entity.ProductWithCategory), args.Error(1)
 }
 
-func (m *MockCatalogServiceClient) GetProducts(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID]*entity.ProductWithCategory, error) {
+func (m *MockCatalogServiceClient) GetAvailability(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID]*entity.ProductAvailability, error) {
 	args := m.Called(ctx, productIDs)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(map[uuid.UUID]*entity.ProductWithCategory), args.Error(1)
+	return args.Get(0).(map[uuid.UUID]*entity.ProductAvailability), args.Error(1)
+}
+
+func (m *MockCatalogServiceClient) ValidateProducts(ctx context.Context, items []entity.OrderItemRequest) (string, error) {
+	args := m.Called(ctx, items)
+	return args.String(0), args.Error(1)
+}
+
+// MockExchangeRateClient мок для ExchangeRateClient
+type MockExchangeRateClient struct {
+	mock.Mock
+}
+
+func (m *MockExchangeRateClient) GetRate(ctx context.Context, from, to string) (float64, error) {
+	args := m.Called(ctx, from, to)
+	return args.Get(0).(float64), args.Error(1)
 }
 
 // MockMessagePublisher мок для MessagePublisher (Kafka)
 type MockMessagePublisher struct {
 	mock.Mock
 	Messages [][]byte
+	mu       sync.Mutex // Защищает Messages при параллельной публикации
 }
 
 func (m *MockMessagePublisher) PublishMessage(ctx context.Context, key string, value []byte) error {
+	m.mu.Lock()
 	m.Messages = append(m.Messages, value)
+	m.mu.Unlock()
 	args := m.Called(ctx, key, value)
 	return args.Error(0)
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/pkg/tenant"
+	"augustberries/pkg/units"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -24,11 +26,12 @@ func (r *orderItemRepository) Create(ctx context.Context, item *entity.OrderItem
 	return result.Error
 }
 
-// GetByOrderID получает все позиции заказа
+// GetByOrderID получает все позиции заказа в порядке оформления
 func (r *orderItemRepository) GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]entity.OrderItem, error) {
 	var items []entity.OrderItem
 	result := r.db.WithContext(ctx).
 		Where("order_id = ?", orderID).
+		Order("position").
 		Find(&items)
 
 	if result.Error != nil {
@@ -46,3 +49,31 @@ func (r *orderItemRepository) DeleteByOrderID(ctx context.Context, orderID uuid.
 
 	return result.Error
 }
+
+// SumQuantitiesByUser считает, сколько каждого товара пользователь уже заказал во всех неотмененных заказах магазина
+// Заказ excludeOrderID не учитывается (позиции редактируемого заказа считаются заново); uuid.Nil - учитываются все заказы
+func (r *orderItemRepository) SumQuantitiesByUser(ctx context.Context, userID uuid.UUID, productIDs []uuid.UUID, excludeOrderID uuid.UUID) (map[uuid.UUID]units.Quantity, error) {
+	var rows []struct {
+		ProductID uuid.UUID
+		Total     units.Quantity
+	}
+	err := r.db.WithContext(ctx).
+		Table("order_items").
+		Select("order_items.product_id, SUM(order_items.quantity) AS total").
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Where("orders.tenant_id = ? AND orders.user_id = ? AND orders.status <> ?",
+			tenant.FromContext(ctx), userID, entity.OrderStatusCancelled).
+		Where("orders.id <> ?", excludeOrderID).
+		Where("order_items.product_id IN ?", productIDs).
+		Group("order_items.product_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[uuid.UUID]units.Quantity, len(rows))
+	for _, row := range rows {
+		totals[row.ProductID] = row.Total
+	}
+	return totals, nil
+}
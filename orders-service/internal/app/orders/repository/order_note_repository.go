@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type orderNoteRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderNoteRepository создает новый репозиторий заметок к заказам
+func NewOrderNoteRepository(db *gorm.DB) OrderNoteRepository {
+	return &orderNoteRepository{db: db}
+}
+
+// Create добавляет заметку к заказу
+func (r *orderNoteRepository) Create(ctx context.Context, note *entity.OrderNote) error {
+	result := r.db.WithContext(ctx).Create(note)
+	return result.Error
+}
+
+// GetByOrderID получает заметки заказа в хронологическом порядке
+func (r *orderNoteRepository) GetByOrderID(ctx context.Context, orderID uuid.UUID, includeInternal bool) ([]entity.OrderNote, error) {
+	query := r.db.WithContext(ctx).Where("order_id = ?", orderID)
+	if !includeInternal {
+		query = query.Where("visibility = ?", entity.NoteVisibilityCustomer)
+	}
+
+	var notes []entity.OrderNote
+	if err := query.Order("created_at ASC").Find(&notes).Error; err != nil {
+		return nil, err
+	}
+
+	return notes, nil
+}
+
+// CountByOrderIDs считает заметки (всех видимостей) по каждому заказу одним запросом
+// Заказы без заметок в результат не попадают
+func (r *orderNoteRepository) CountByOrderIDs(ctx context.Context, orderIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int, len(orderIDs))
+	if len(orderIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		OrderID uuid.UUID
+		Count   int
+	}
+	err := r.db.WithContext(ctx).Model(&entity.OrderNote{}).
+		Select("order_id, COUNT(*) AS count").
+		Where("order_id IN ?", orderIDs).
+		Group("order_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.OrderID] = row.Count
+	}
+	return counts, nil
+}
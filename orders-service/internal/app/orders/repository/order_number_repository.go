@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"augustberries/pkg/tenant"
+
+	"gorm.io/gorm"
+)
+
+type orderNumberRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderNumberRepository создает репозиторий счетчиков номеров заказов
+func NewOrderNumberRepository(db *gorm.DB) OrderNumberRepository {
+	return &orderNumberRepository{db: db}
+}
+
+// Next атомарно увеличивает счетчик магазина за день на step и возвращает новое значение
+// Один UPSERT без транзакции: параллельные заказы получают разные значения
+func (r *orderNumberRepository) Next(ctx context.Context, day time.Time, step int64) (int64, error) {
+	var value int64
+	err := r.db.WithContext(ctx).Raw(`
+		INSERT INTO order_number_sequences (tenant_id, day, last_value)
+		VALUES (?, ?, ?)
+		ON CONFLICT (tenant_id, day) DO UPDATE
+		SET last_value = order_number_sequences.last_value + EXCLUDED.last_value
+		RETURNING last_value`,
+		tenant.FromContext(ctx), day.Format("2006-01-02"), step,
+	).Scan(&value).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return value, nil
+}
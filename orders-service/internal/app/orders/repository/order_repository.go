@@ -3,16 +3,21 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/pkg/tenant"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var (
 	// Стандартные ошибки репозитория для обработки в service layer
 	ErrOrderNotFound = errors.New("order not found")
+	// ErrOrderStatusChanged - статус заказа изменился между чтением и обновлением
+	ErrOrderStatusChanged = errors.New("order status changed")
 )
 
 type orderRepository struct {
@@ -26,6 +31,7 @@ func NewOrderRepository(db *gorm.DB) OrderRepository {
 
 // Create создает новый заказ в PostgreSQL
 func (r *orderRepository) Create(ctx context.Context, order *entity.Order) error {
+	order.TenantID = tenant.FromContext(ctx)
 	result := r.db.WithContext(ctx).Create(order)
 	return result.Error
 }
@@ -33,7 +39,7 @@ func (r *orderRepository) Create(ctx context.Context, order *entity.Order) error
 // GetByID получает заказ по ID из PostgreSQL
 func (r *orderRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Order, error) {
 	var order entity.Order
-	result := r.db.WithContext(ctx).First(&order, "id = ?", id)
+	result := scoped(ctx, r.db).First(&order, "id = ?", id)
 
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -48,9 +54,9 @@ func (r *orderRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Or
 // GetByUserID получает все заказы пользователя
 func (r *orderRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]entity.Order, error) {
 	var orders []entity.Order
-	result := r.db.WithContext(ctx).
+	result := scoped(ctx, r.db).
 		Where("user_id = ?", userID).
-		Order("created_at DESC").
+		Order("created_at DESC, id DESC").
 		Find(&orders)
 
 	if result.Error != nil {
@@ -60,15 +66,13 @@ func (r *orderRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]
 	return orders, nil
 }
 
-// Update обновляет заказ в PostgreSQL
-func (r *orderRepository) Update(ctx context.Context, order *entity.Order) error {
-	result := r.db.WithContext(ctx).Model(order).
-		Where("id = ?", order.ID).
+// UpdateDeliveryEstimate обновляет окно ожидаемой доставки заказа; статус и суммы заказа не меняются
+func (r *orderRepository) UpdateDeliveryEstimate(ctx context.Context, id uuid.UUID, from, to *time.Time) error {
+	result := scoped(ctx, r.db).Model(&entity.Order{}).
+		Where("id = ?", id).
 		Updates(map[string]interface{}{
-			"status":         order.Status,
-			"total_price":    order.TotalPrice,
-			"delivery_price": order.DeliveryPrice,
-			"currency":       order.Currency,
+			"estimated_delivery_from": from,
+			"estimated_delivery_to":   to,
 		})
 
 	if result.Error != nil {
@@ -82,10 +86,32 @@ func (r *orderRepository) Update(ctx context.Context, order *entity.Order) error
 	return nil
 }
 
+// UpdateStatus переводит заказ из статуса from в to
+// Строка заказа блокируется (SELECT ... FOR UPDATE) до конца транзакции: параллельные смены статуса
+// выполняются по очереди, и проигравший видит уже новый статус
+func (r *orderRepository) UpdateStatus(ctx context.Context, id uuid.UUID, from, to entity.OrderStatus) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var order entity.Order
+		err := scoped(ctx, tx).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "status").Where("id = ?", id).Take(&order).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrOrderNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if order.Status != from {
+			return ErrOrderStatusChanged
+		}
+
+		return tx.Model(&entity.Order{}).Where("id = ?", id).Update("status", to).Error
+	})
+}
+
 // Delete удаляет заказ из PostgreSQL
 // Позиции заказа удаляются автоматически через CASCADE
 func (r *orderRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	result := r.db.WithContext(ctx).Delete(&entity.Order{}, "id = ?", id)
+	result := scoped(ctx, r.db).Delete(&entity.Order{}, "id = ?", id)
 
 	if result.Error != nil {
 		return result.Error
@@ -101,7 +127,7 @@ func (r *orderRepository) Delete(ctx context.Context, id uuid.UUID) error {
 // GetWithItems получает заказ с полным списком позиций
 func (r *orderRepository) GetWithItems(ctx context.Context, id uuid.UUID) (*entity.OrderWithItems, error) {
 	var order entity.Order
-	result := r.db.WithContext(ctx).
+	result := scoped(ctx, r.db).
 		Preload("Items").
 		First(&order, "id = ?", id)
 
@@ -117,3 +143,207 @@ func (r *orderRepository) GetWithItems(ctx context.Context, id uuid.UUID) (*enti
 		Items: order.Items,
 	}, nil
 }
+
+// GetByNumberWithItems получает заказ магазина по человекочитаемому номеру
+func (r *orderRepository) GetByNumberWithItems(ctx context.Context, number string) (*entity.OrderWithItems, error) {
+	var order entity.Order
+	result := scoped(ctx, r.db).
+		Preload("Items").
+		First(&order, "number = ?", number)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &entity.OrderWithItems{
+		Order: order,
+		Items: order.Items,
+	}, nil
+}
+
+// List получает заказы магазина по фильтру, новые первыми
+func (r *orderRepository) List(ctx context.Context, filter entity.OrderFilter) ([]entity.Order, error) {
+	query := scoped(ctx, r.db)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+
+	var orders []entity.Order
+	if err := query.Order("created_at DESC, id DESC").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// CountByStatus считает заказы магазина по статусам
+func (r *orderRepository) CountByStatus(ctx context.Context) (map[entity.OrderStatus]int64, error) {
+	var rows []struct {
+		Status entity.OrderStatus
+		Count  int64
+	}
+	err := scoped(ctx, r.db).Model(&entity.Order{}).
+		Select("status, COUNT(*) AS count").Group("status").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[entity.OrderStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// RevenueSince суммирует итоги заказов магазина по валютам, отмененные заказы не учитываются
+func (r *orderRepository) RevenueSince(ctx context.Context, since time.Time) ([]entity.CurrencyRevenue, error) {
+	var revenue []entity.CurrencyRevenue
+	err := scoped(ctx, r.db).Model(&entity.Order{}).
+		Select("currency, COALESCE(SUM(total_price), 0) AS total, COUNT(*) AS orders").
+		Where("created_at >= ? AND status <> ?", since, entity.OrderStatusCancelled).
+		Group("currency").Order("currency").Scan(&revenue).Error
+	if err != nil {
+		return nil, err
+	}
+	return revenue, nil
+}
+
+// UserStats считает статистику заказов пользователя агрегирующими запросами
+// Сумма покупок не включает отмененные и еще не активированные отложенные заказы
+func (r *orderRepository) UserStats(ctx context.Context, userID uuid.UUID) (*entity.UserOrderStats, error) {
+	var rows []struct {
+		Status entity.OrderStatus
+		Count  int64
+		First  time.Time
+		Last   time.Time
+	}
+	err := scoped(ctx, r.db).Model(&entity.Order{}).
+		Select("status, COUNT(*) AS count, MIN(created_at) AS first, MAX(created_at) AS last").
+		Where("user_id = ?", userID).
+		Group("status").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &entity.UserOrderStats{
+		OrdersByStatus: make(map[entity.OrderStatus]int64, len(rows)),
+		TotalSpent:     []entity.CurrencyRevenue{},
+	}
+	for _, row := range rows {
+		stats.OrdersByStatus[row.Status] = row.Count
+		stats.TotalOrders += row.Count
+		if stats.FirstOrderAt == nil || row.First.Before(*stats.FirstOrderAt) {
+			first := row.First
+			stats.FirstOrderAt = &first
+		}
+		if stats.LastOrderAt == nil || row.Last.After(*stats.LastOrderAt) {
+			last := row.Last
+			stats.LastOrderAt = &last
+		}
+	}
+	if stats.TotalOrders == 0 {
+		return stats, nil
+	}
+
+	err = scoped(ctx, r.db).Model(&entity.Order{}).
+		Select("currency, COALESCE(SUM(total_price), 0) AS total, COUNT(*) AS orders").
+		Where("user_id = ? AND status NOT IN ?", userID, []entity.OrderStatus{entity.OrderStatusCancelled, entity.OrderStatusScheduled}).
+		Group("currency").Order("currency").Scan(&stats.TotalSpent).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// ReassignGuestOrders переносит заказы гостевой сессии на аккаунт пользователя
+// Затрагиваются только гостевые заказы (guest_email задан) магазина из контекста
+func (r *orderRepository) ReassignGuestOrders(ctx context.Context, guestID, userID uuid.UUID) (int64, error) {
+	result := scoped(ctx, r.db).Model(&entity.Order{}).
+		Where("user_id = ? AND guest_email IS NOT NULL", guestID).
+		Update("user_id", userID)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+// ListDueScheduled возвращает наступившие отложенные заказы, самые ранние первыми
+// Запрос без scoped: фоновая задача обслуживает все магазины
+func (r *orderRepository) ListDueScheduled(ctx context.Context, now time.Time, limit int) ([]entity.Order, error) {
+	var orders []entity.Order
+	result := r.db.WithContext(ctx).
+		Where("status = ? AND scheduled_for <= ?", entity.OrderStatusScheduled, now).
+		Order("scheduled_for ASC").
+		Limit(limit).
+		Find(&orders)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return orders, nil
+}
+
+// ActivateScheduled переводит отложенный заказ в pending
+// Условие на статус не дает активировать заказ дважды, если задача запущена на нескольких экземплярах
+func (r *orderRepository) ActivateScheduled(ctx context.Context, id uuid.UUID) error {
+	result := scoped(ctx, r.db).Model(&entity.Order{}).
+		Where("id = ? AND status = ?", id, entity.OrderStatusScheduled).
+		Update("status", entity.OrderStatusPending)
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOrderStatusChanged
+	}
+
+	return nil
+}
+
+// UpdateItems пересохраняет позиции и итоги заказа, пока он в статусе pending
+// Условие на статус не дает изменить заказ, который подтвердили параллельно
+func (r *orderRepository) UpdateItems(ctx context.Context, order *entity.Order, items []entity.OrderItem, removed []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Конвертация, выполненная Background Worker после чтения заказа, тоже считается изменением:
+		// иначе новые позиции в исходной валюте смешались бы со сконвертированными суммами.
+		// Позиции сохраняются в исходной валюте, поэтому конвертация сбрасывается и выполнится заново по ORDER_UPDATED
+		result := scoped(ctx, tx).Model(&entity.Order{}).
+			Where("id = ? AND status = ? AND converted_at IS NOT DISTINCT FROM ?", order.ID, entity.OrderStatusPending, order.ConvertedAt).
+			Updates(map[string]interface{}{
+				"total_price":    order.TotalPrice,
+				"tax_total":      order.TaxTotal,
+				"delivery_price": order.DeliveryPrice,
+				"currency":       order.Currency,
+				"converted_at":   nil,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrOrderStatusChanged
+		}
+
+		// Позиции не содержат tenant_id: доступ к ним подтвержден обновлением заказа выше
+		for i := range items {
+			if err := tx.Save(&items[i]).Error; err != nil {
+				return err
+			}
+		}
+		if len(removed) > 0 {
+			if err := tx.Where("order_id = ? AND id IN ?", order.ID, removed).Delete(&entity.OrderItem{}).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// refreshSummariesSQL пересчитывает строки проекции по orders и order_items одним запросом
+// %s - условие отбора заказов; первая позиция - с наименьшим position
+const refreshSummariesSQL = `
+INSERT INTO order_summaries (
+    order_id, number, tenant_id, user_id, total_price, delivery_price, tax_total, currency, country, status,
+    guest_email, scheduled_for, created_at, estimated_delivery_from, estimated_delivery_to,
+    items_count, items_quantity, first_item_name, refreshed_at
+)
+SELECT o.id, o.number, o.tenant_id, o.user_id, o.total_price, o.delivery_price, o.tax_total, o.currency, o.country, o.status,
+       o.guest_email, o.scheduled_for, o.created_at, o.estimated_delivery_from, o.estimated_delivery_to,
+       COUNT(i.id), COALESCE(SUM(i.quantity), 0),
+       COALESCE((ARRAY_AGG(i.product_name ORDER BY i.position, i.id) FILTER (WHERE i.id IS NOT NULL))[1], ''),
+       NOW()
+FROM orders o
+LEFT JOIN order_items i ON i.order_id = o.id
+WHERE %s
+GROUP BY o.id
+ON CONFLICT (order_id) DO UPDATE SET
+    number = EXCLUDED.number,
+    tenant_id = EXCLUDED.tenant_id,
+    user_id = EXCLUDED.user_id,
+    total_price = EXCLUDED.total_price,
+    delivery_price = EXCLUDED.delivery_price,
+    tax_total = EXCLUDED.tax_total,
+    currency = EXCLUDED.currency,
+    country = EXCLUDED.country,
+    status = EXCLUDED.status,
+    guest_email = EXCLUDED.guest_email,
+    scheduled_for = EXCLUDED.scheduled_for,
+    created_at = EXCLUDED.created_at,
+    estimated_delivery_from = EXCLUDED.estimated_delivery_from,
+    estimated_delivery_to = EXCLUDED.estimated_delivery_to,
+    items_count = EXCLUDED.items_count,
+    items_quantity = EXCLUDED.items_quantity,
+    first_item_name = EXCLUDED.first_item_name,
+    refreshed_at = EXCLUDED.refreshed_at`
+
+type orderSummaryRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderSummaryRepository создает репозиторий проекции order_summaries
+// Строки удаленных заказов удаляет внешний ключ с ON DELETE CASCADE
+func NewOrderSummaryRepository(db *gorm.DB) OrderSummaryRepository {
+	return &orderSummaryRepository{db: db}
+}
+
+// Refresh пересчитывает строки заказов магазина из контекста
+func (r *orderSummaryRepository) Refresh(ctx context.Context, orderIDs ...uuid.UUID) error {
+	if len(orderIDs) == 0 {
+		return nil
+	}
+	return r.refresh(ctx, "o.tenant_id = ? AND o.id IN ?", tenant.FromContext(ctx), orderIDs)
+}
+
+// RefreshUser пересчитывает строки всех заказов пользователя в магазине из контекста
+func (r *orderSummaryRepository) RefreshUser(ctx context.Context, userID uuid.UUID) error {
+	return r.refresh(ctx, "o.tenant_id = ? AND o.user_id = ?", tenant.FromContext(ctx), userID)
+}
+
+func (r *orderSummaryRepository) refresh(ctx context.Context, where string, args ...interface{}) error {
+	if err := r.db.WithContext(ctx).Exec(fmt.Sprintf(refreshSummariesSQL, where), args...).Error; err != nil {
+		return fmt.Errorf("failed to refresh order summaries: %w", err)
+	}
+	return nil
+}
+
+// List возвращает строки заказов магазина по фильтру, новые первыми
+func (r *orderSummaryRepository) List(ctx context.Context, filter entity.OrderFilter) ([]entity.OrderSummary, error) {
+	query := scoped(ctx, r.db)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+
+	var summaries []entity.OrderSummary
+	if err := query.Order("created_at DESC, id DESC").Find(&summaries).Error; err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
+
+// Rebuild пересчитывает проекцию заказов всех магазинов пачками по batchSize в порядке ID
+// Запрос без scoped: команда пересборки обслуживает все магазины
+func (r *orderSummaryRepository) Rebuild(ctx context.Context, batchSize int) (int64, error) {
+	var rebuilt int64
+	last := uuid.Nil
+	for {
+		var ids []uuid.UUID
+		err := r.db.WithContext(ctx).Model(&entity.Order{}).
+			Where("id > ?", last).
+			Order("id").
+			Limit(batchSize).
+			Pluck("id", &ids).Error
+		if err != nil {
+			return rebuilt, fmt.Errorf("failed to list orders: %w", err)
+		}
+		if len(ids) == 0 {
+			return rebuilt, nil
+		}
+
+		if err := r.refresh(ctx, "o.id IN ?", ids); err != nil {
+			return rebuilt, err
+		}
+		rebuilt += int64(len(ids))
+		last = ids[len(ids)-1]
+	}
+}
@@ -2,20 +2,80 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/pkg/tenant"
+	"augustberries/pkg/units"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// scoped ограничивает запрос заказами магазина из контекста
+// Позиции заказа, отправления и заметки отдельно не фильтруются: доступ к ним идет только через заказ
+func scoped(ctx context.Context, db *gorm.DB) *gorm.DB {
+	return db.WithContext(ctx).Where("tenant_id = ?", tenant.FromContext(ctx))
+}
+
 // OrderRepository определяет методы для работы с заказами
 type OrderRepository interface {
 	Create(ctx context.Context, order *entity.Order) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.Order, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]entity.Order, error)
-	Update(ctx context.Context, order *entity.Order) error
+	// UpdateDeliveryEstimate меняет только окно ожидаемой доставки заказа
+	UpdateDeliveryEstimate(ctx context.Context, id uuid.UUID, from, to *time.Time) error
+	// UpdateStatus меняет только статус, если заказ все еще в from; ErrOrderStatusChanged - статус уже сменил другой запрос
+	UpdateStatus(ctx context.Context, id uuid.UUID, from, to entity.OrderStatus) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetWithItems(ctx context.Context, id uuid.UUID) (*entity.OrderWithItems, error)
+	List(ctx context.Context, filter entity.OrderFilter) ([]entity.Order, error)
+	GetByNumberWithItems(ctx context.Context, number string) (*entity.OrderWithItems, error)
+	// ReassignGuestOrders переносит гостевые заказы на аккаунт и возвращает их число
+	ReassignGuestOrders(ctx context.Context, guestID, userID uuid.UUID) (int64, error)
+	// CountByStatus возвращает число заказов магазина в каждом статусе
+	CountByStatus(ctx context.Context) (map[entity.OrderStatus]int64, error)
+	// RevenueSince возвращает выручку магазина по валютам за заказы, созданные с since (без отмененных)
+	RevenueSince(ctx context.Context, since time.Time) ([]entity.CurrencyRevenue, error)
+	// UserStats считает заказы пользователя по статусам, сумму покупок по валютам и даты первого и последнего заказа
+	UserStats(ctx context.Context, userID uuid.UUID) (*entity.UserOrderStats, error)
+	// ListDueScheduled возвращает отложенные заказы всех магазинов, время активации которых наступило
+	ListDueScheduled(ctx context.Context, now time.Time, limit int) ([]entity.Order, error)
+	// UpdateItems сохраняет новые позиции, итоги, валюту и доставку заказа в статусе pending одной транзакцией
+	// и сбрасывает converted_at. removed - ID удаленных позиций
+	// ErrOrderStatusChanged - заказ уже не в pending или сконвертирован после чтения (order.ConvertedAt)
+	UpdateItems(ctx context.Context, order *entity.Order, items []entity.OrderItem, removed []uuid.UUID) error
+	// ActivateScheduled переводит отложенный заказ в pending; ErrOrderStatusChanged - заказ уже активирован или отменен
+	ActivateScheduled(ctx context.Context, id uuid.UUID) error
+}
+
+// RetentionRepository - сроки хранения завершенных заказов и legal hold
+type RetentionRepository interface {
+	// ListExpired возвращает заказы всех магазинов в statuses, созданные раньше before, без legal hold и не обезличенные
+	ListExpired(ctx context.Context, statuses []entity.OrderStatus, before time.Time, limit int) ([]entity.Order, error)
+	// Anonymize удаляет персональные данные заказа; ErrOrderRetained - заказ на legal hold или уже обезличен
+	Anonymize(ctx context.Context, id uuid.UUID, now time.Time) error
+	// Purge удаляет заказ со всеми связанными данными; ErrOrderRetained - заказ на legal hold или уже обработан
+	Purge(ctx context.Context, id uuid.UUID) error
+	SetLegalHold(ctx context.Context, id uuid.UUID, hold bool) error
+	CountLegalHolds(ctx context.Context) (int64, error)
+}
+
+// OrderSummaryRepository - проекция order_summaries для списков заказов
+type OrderSummaryRepository interface {
+	// Refresh пересчитывает строки заказов по orders и order_items
+	Refresh(ctx context.Context, orderIDs ...uuid.UUID) error
+	// RefreshUser пересчитывает строки всех заказов пользователя
+	RefreshUser(ctx context.Context, userID uuid.UUID) error
+	// List возвращает строки заказов магазина по фильтру, новые первыми
+	List(ctx context.Context, filter entity.OrderFilter) ([]entity.OrderSummary, error)
+	// Rebuild пересчитывает проекцию всех магазинов и возвращает число заказов
+	Rebuild(ctx context.Context, batchSize int) (int64, error)
+}
+
+// OrderNumberRepository выдает значения счетчиков номеров заказов магазина по дням
+type OrderNumberRepository interface {
+	Next(ctx context.Context, day time.Time, step int64) (int64, error)
 }
 
 // OrderItemRepository определяет методы для работы с позициями заказов
@@ -23,4 +83,29 @@ type OrderItemRepository interface {
 	Create(ctx context.Context, item *entity.OrderItem) error
 	GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]entity.OrderItem, error)
 	DeleteByOrderID(ctx context.Context, orderID uuid.UUID) error
+	SumQuantitiesByUser(ctx context.Context, userID uuid.UUID, productIDs []uuid.UUID, excludeOrderID uuid.UUID) (map[uuid.UUID]units.Quantity, error)
+}
+
+// ShipmentRepository определяет методы для работы с отправлениями заказов
+type ShipmentRepository interface {
+	Create(ctx context.Context, shipment *entity.Shipment) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Shipment, error)
+	GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]entity.Shipment, error)
+	UpdateStatus(ctx context.Context, shipment *entity.Shipment) error
+}
+
+// OrderNoteRepository определяет методы для работы с заметками к заказам
+type OrderNoteRepository interface {
+	Create(ctx context.Context, note *entity.OrderNote) error
+	// GetByOrderID возвращает заметки заказа; без includeInternal - только видимые покупателю
+	GetByOrderID(ctx context.Context, orderID uuid.UUID, includeInternal bool) ([]entity.OrderNote, error)
+	CountByOrderIDs(ctx context.Context, orderIDs []uuid.UUID) (map[uuid.UUID]int, error)
+}
+
+// TaxRateRepository определяет методы для работы со ставками налогов магазина
+type TaxRateRepository interface {
+	Upsert(ctx context.Context, rate *entity.TaxRate) error
+	GetAll(ctx context.Context) ([]entity.TaxRate, error)
+	GetByCountry(ctx context.Context, country string) ([]entity.TaxRate, error)
+	Delete(ctx context.Context, id uuid.UUID) error
 }
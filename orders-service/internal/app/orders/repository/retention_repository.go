@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrOrderRetained - заказ на legal hold или уже обработан другим экземпляром задачи
+var ErrOrderRetained = errors.New("order retained")
+
+// retainable - условие, при котором заказ еще можно обезличить или удалить
+const retainable = "NOT legal_hold AND anonymized_at IS NULL"
+
+type retentionRepository struct {
+	db *gorm.DB
+}
+
+// NewRetentionRepository создает репозиторий сроков хранения заказов
+func NewRetentionRepository(db *gorm.DB) RetentionRepository {
+	return &retentionRepository{db: db}
+}
+
+// ListExpired возвращает завершенные заказы, созданные раньше before, самые старые первыми
+// Запрос без scoped: фоновая задача обслуживает все магазины
+func (r *retentionRepository) ListExpired(ctx context.Context, statuses []entity.OrderStatus, before time.Time, limit int) ([]entity.Order, error) {
+	var orders []entity.Order
+	result := r.db.WithContext(ctx).
+		Where("status IN ? AND created_at < ? AND "+retainable, statuses, before).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&orders)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return orders, nil
+}
+
+// Anonymize удаляет персональные данные заказа: покупателя, email гостя и заметки поддержки
+// Суммы, позиции и отправления остаются для отчетности; строка order_summaries обновляется в той же транзакции
+func (r *retentionRepository) Anonymize(ctx context.Context, id uuid.UUID, now time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := scoped(ctx, tx).Model(&entity.Order{}).
+			Where("id = ? AND "+retainable, id).
+			Updates(map[string]interface{}{
+				"user_id":       uuid.Nil,
+				"guest_email":   nil,
+				"anonymized_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrOrderRetained
+		}
+
+		if err := tx.Where("order_id = ?", id).Delete(&entity.OrderNote{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entity.OrderSummary{}).Where("order_id = ?", id).
+			Updates(map[string]interface{}{
+				"user_id":      uuid.Nil,
+				"guest_email":  nil,
+				"refreshed_at": now,
+			}).Error
+	})
+}
+
+// Purge удаляет заказ; позиции, отправления, заметки и строка order_summaries удаляются через CASCADE
+func (r *retentionRepository) Purge(ctx context.Context, id uuid.UUID) error {
+	result := scoped(ctx, r.db).Where("id = ? AND "+retainable, id).Delete(&entity.Order{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOrderRetained
+	}
+
+	return nil
+}
+
+// SetLegalHold ставит или снимает legal hold заказа магазина
+func (r *retentionRepository) SetLegalHold(ctx context.Context, id uuid.UUID, hold bool) error {
+	result := scoped(ctx, r.db).Model(&entity.Order{}).
+		Where("id = ?", id).
+		Update("legal_hold", hold)
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOrderNotFound
+	}
+
+	return nil
+}
+
+// CountLegalHolds считает заказы магазина на legal hold
+func (r *retentionRepository) CountLegalHolds(ctx context.Context) (int64, error) {
+	var count int64
+	err := scoped(ctx, r.db).Model(&entity.Order{}).Where("legal_hold").Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrShipmentNotFound = errors.New("shipment not found")
+)
+
+type shipmentRepository struct {
+	db *gorm.DB
+}
+
+// NewShipmentRepository создает новый репозиторий отправлений
+func NewShipmentRepository(db *gorm.DB) ShipmentRepository {
+	return &shipmentRepository{db: db}
+}
+
+// Create создает отправление вместе с его позициями
+func (r *shipmentRepository) Create(ctx context.Context, shipment *entity.Shipment) error {
+	result := r.db.WithContext(ctx).Create(shipment)
+	return result.Error
+}
+
+// GetByID получает отправление с позициями
+func (r *shipmentRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Shipment, error) {
+	var shipment entity.Shipment
+	result := r.db.WithContext(ctx).
+		Preload("Items").
+		First(&shipment, "id = ?", id)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrShipmentNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &shipment, nil
+}
+
+// GetByOrderID получает все отправления заказа в порядке отправки
+func (r *shipmentRepository) GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]entity.Shipment, error) {
+	var shipments []entity.Shipment
+	result := r.db.WithContext(ctx).
+		Preload("Items").
+		Where("order_id = ?", orderID).
+		Order("shipped_at ASC").
+		Find(&shipments)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return shipments, nil
+}
+
+// UpdateStatus обновляет статус и время доставки отправления
+func (r *shipmentRepository) UpdateStatus(ctx context.Context, shipment *entity.Shipment) error {
+	result := r.db.WithContext(ctx).Model(shipment).
+		Where("id = ?", shipment.ID).
+		Updates(map[string]interface{}{
+			"status":       shipment.Status,
+			"delivered_at": shipment.DeliveredAt,
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrShipmentNotFound
+	}
+
+	return nil
+}
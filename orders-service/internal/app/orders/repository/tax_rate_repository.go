@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrTaxRateNotFound - ставка налога не найдена
+var ErrTaxRateNotFound = errors.New("tax rate not found")
+
+type taxRateRepository struct {
+	db *gorm.DB
+}
+
+// NewTaxRateRepository создает новый репозиторий ставок налогов
+func NewTaxRateRepository(db *gorm.DB) TaxRateRepository {
+	return &taxRateRepository{db: db}
+}
+
+// Upsert создает ставку или заменяет существующую для той же страны и категории
+func (r *taxRateRepository) Upsert(ctx context.Context, rate *entity.TaxRate) error {
+	rate.TenantID = tenant.FromContext(ctx)
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := scoped(ctx, tx).Where("country = ?", rate.Country)
+		if rate.CategoryID == nil {
+			query = query.Where("category_id IS NULL")
+		} else {
+			query = query.Where("category_id = ?", *rate.CategoryID)
+		}
+
+		var existing entity.TaxRate
+		err := query.First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(rate).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		rate.ID = existing.ID
+		rate.CreatedAt = existing.CreatedAt
+		return tx.Model(&existing).Updates(map[string]interface{}{
+			"name": rate.Name,
+			"rate": rate.Rate,
+		}).Error
+	})
+}
+
+// GetAll получает все ставки магазина, сгруппированные по странам
+func (r *taxRateRepository) GetAll(ctx context.Context) ([]entity.TaxRate, error) {
+	var rates []entity.TaxRate
+	if err := scoped(ctx, r.db).Order("country, category_id NULLS FIRST").Find(&rates).Error; err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// GetByCountry получает ставки магазина для страны: общую и ставки категорий
+func (r *taxRateRepository) GetByCountry(ctx context.Context, country string) ([]entity.TaxRate, error) {
+	var rates []entity.TaxRate
+	if err := scoped(ctx, r.db).Where("country = ?", country).Find(&rates).Error; err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// Delete удаляет ставку магазина
+func (r *taxRateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := scoped(ctx, r.db).Delete(&entity.TaxRate{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTaxRateNotFound
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+)
+
+// ErrInvalidLeadTimes - сроки доставки перевозчиков заданы в неверном формате
+var ErrInvalidLeadTimes = errors.New("invalid delivery lead times")
+
+// anyRegion - правило сроков доставки для стран и перевозчиков без своего правила
+const anyRegion = "*"
+
+// LeadTime - срок доставки перевозчиком в календарных днях от отгрузки
+type LeadTime struct {
+	MinDays int
+	MaxDays int
+}
+
+// DeliveryRules - правила расчета ожидаемой доставки
+type DeliveryRules struct {
+	// LeadTimes - сроки по ключам "RU", "dhl/DE", "dhl/*", "*"; см. ParseLeadTimes
+	LeadTimes map[string]LeadTime
+	// ProcessingDays - рабочих дней на сборку заказа до отгрузки
+	ProcessingDays int
+	// CutoffHour - час UTC, после которого заказ начинают собирать на следующий рабочий день; 0 - без отсечки
+	CutoffHour int
+}
+
+// ParseLeadTimes разбирает сроки доставки вида "RU:2-5,dhl/DE:2-3,*:7-14"
+// Ключ - страна, перевозчик/страна, перевозчик/* или * для остальных; "RU:3" - ровно 3 дня
+func ParseLeadTimes(spec string) (map[string]LeadTime, error) {
+	leadTimes := make(map[string]LeadTime)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		key, days, ok := strings.Cut(entry, ":")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%w: %q must be REGION:MIN-MAX", ErrInvalidLeadTimes, entry)
+		}
+		minDays, maxDays, ranged := strings.Cut(days, "-")
+		if !ranged {
+			maxDays = minDays
+		}
+
+		var lt LeadTime
+		var errMin, errMax error
+		lt.MinDays, errMin = strconv.Atoi(strings.TrimSpace(minDays))
+		lt.MaxDays, errMax = strconv.Atoi(strings.TrimSpace(maxDays))
+		if errMin != nil || errMax != nil || lt.MinDays < 0 || lt.MaxDays < lt.MinDays {
+			return nil, fmt.Errorf("%w: %q: days must be 0 <= MIN <= MAX", ErrInvalidLeadTimes, entry)
+		}
+
+		leadTimes[regionKey(key)] = lt
+	}
+	return leadTimes, nil
+}
+
+// regionKey приводит ключ правила к виду "перевозчик/СТРАНА"
+func regionKey(key string) string {
+	carrier, country, ok := strings.Cut(strings.TrimSpace(key), "/")
+	if !ok {
+		return strings.ToUpper(carrier)
+	}
+	return strings.ToLower(strings.TrimSpace(carrier)) + "/" + strings.ToUpper(strings.TrimSpace(country))
+}
+
+// DeliveryEstimator рассчитывает окно ожидаемой доставки заказа
+// При оформлении: сборка ProcessingDays рабочих дней, затем срок перевозчика для страны.
+// После отгрузки окно уточняется по сроку перевозчика отправления от даты отгрузки
+type DeliveryEstimator struct {
+	rules DeliveryRules
+}
+
+// NewDeliveryEstimator создает калькулятор ожидаемой доставки
+func NewDeliveryEstimator(rules DeliveryRules) *DeliveryEstimator {
+	return &DeliveryEstimator{rules: rules}
+}
+
+// Estimate возвращает окно доставки заказа, оформленного в placedAt
+// ok == false - для страны нет правила, оценки нет
+func (e *DeliveryEstimator) Estimate(country string, placedAt time.Time) (from, to time.Time, ok bool) {
+	if e == nil {
+		return time.Time{}, time.Time{}, false
+	}
+	lt, ok := e.leadTime("", country)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+
+	placedAt = placedAt.UTC()
+	day := truncateDay(placedAt)
+	if e.rules.CutoffHour > 0 && placedAt.Hour() >= e.rules.CutoffHour {
+		day = day.AddDate(0, 0, 1)
+	}
+	// Сборка начинается в рабочий день
+	day = nextBusinessDay(day)
+	for i := 0; i < e.rules.ProcessingDays; i++ {
+		day = nextBusinessDay(day.AddDate(0, 0, 1))
+	}
+
+	return day.AddDate(0, 0, lt.MinDays), day.AddDate(0, 0, lt.MaxDays), true
+}
+
+// EstimateShipment возвращает окно доставки отправления, переданного перевозчику в shippedAt
+func (e *DeliveryEstimator) EstimateShipment(carrier, country string, shippedAt time.Time) (from, to time.Time, ok bool) {
+	if e == nil {
+		return time.Time{}, time.Time{}, false
+	}
+	lt, ok := e.leadTime(carrier, country)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+
+	day := truncateDay(shippedAt.UTC())
+	return day.AddDate(0, 0, lt.MinDays), day.AddDate(0, 0, lt.MaxDays), true
+}
+
+// UpdateFromShipments уточняет окно доставки заказа по отправлениям в пути
+// Пока часть позиций не отправлена, конец окна не раньше исходной оценки.
+// Возвращает true, если окно изменилось
+func (e *DeliveryEstimator) UpdateFromShipments(order *entity.Order, shipments []entity.Shipment) bool {
+	if e == nil {
+		return false
+	}
+
+	var from, to time.Time
+	for _, shipment := range shipments {
+		if shipment.Status != entity.ShipmentStatusInTransit {
+			continue
+		}
+		shipmentFrom, shipmentTo, ok := e.EstimateShipment(shipment.Carrier, order.Country, shipment.ShippedAt)
+		if !ok {
+			continue
+		}
+		if from.IsZero() || shipmentFrom.Before(from) {
+			from = shipmentFrom
+		}
+		if shipmentTo.After(to) {
+			to = shipmentTo
+		}
+	}
+	if from.IsZero() {
+		return false
+	}
+
+	if order.Status == entity.OrderStatusPartiallyShipped && order.EstimatedDeliveryTo != nil && order.EstimatedDeliveryTo.After(to) {
+		to = *order.EstimatedDeliveryTo
+	}
+
+	if sameDay(order.EstimatedDeliveryFrom, from) && sameDay(order.EstimatedDeliveryTo, to) {
+		return false
+	}
+	order.EstimatedDeliveryFrom = &from
+	order.EstimatedDeliveryTo = &to
+	return true
+}
+
+// leadTime ищет срок: перевозчик и страна, перевозчик, страна, затем правило по умолчанию
+func (e *DeliveryEstimator) leadTime(carrier, country string) (LeadTime, bool) {
+	carrier = strings.ToLower(carrier)
+	country = strings.ToUpper(country)
+
+	var keys []string
+	if carrier != "" {
+		keys = append(keys, carrier+"/"+country, carrier+"/"+anyRegion)
+	}
+	keys = append(keys, country, anyRegion)
+
+	for _, key := range keys {
+		if lt, ok := e.rules.LeadTimes[key]; ok {
+			return lt, true
+		}
+	}
+	return LeadTime{}, false
+}
+
+// SetDeliveryEstimator включает расчет ожидаемой доставки при оформлении заказа; nil - заказы без оценки
+func (s *OrderService) SetDeliveryEstimator(e *DeliveryEstimator) {
+	s.deliveryEstimator = e
+}
+
+// SetDeliveryEstimator включает уточнение ожидаемой доставки по отправлениям; nil - окно не меняется
+func (s *ShipmentService) SetDeliveryEstimator(e *DeliveryEstimator) {
+	s.deliveryEstimator = e
+}
+
+// estimateDelivery заполняет окно доставки нового заказа
+// Отложенный заказ начинают собирать после активации
+func estimateDelivery(e *DeliveryEstimator, order *entity.Order) {
+	placedAt := order.CreatedAt
+	if order.ScheduledFor != nil {
+		placedAt = *order.ScheduledFor
+	}
+	if from, to, ok := e.Estimate(order.Country, placedAt); ok {
+		order.EstimatedDeliveryFrom = &from
+		order.EstimatedDeliveryTo = &to
+	}
+}
+
+// truncateDay отбрасывает время, оставляя дату UTC
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// nextBusinessDay возвращает day или ближайший следующий понедельник, если day выпадает на выходные
+func nextBusinessDay(day time.Time) time.Time {
+	switch day.Weekday() {
+	case time.Saturday:
+		return day.AddDate(0, 0, 2)
+	case time.Sunday:
+		return day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// sameDay сравнивает сохраненную дату окна с новой
+func sameDay(current *time.Time, day time.Time) bool {
+	return current != nil && truncateDay(current.UTC()).Equal(day)
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/repository/mocks"
+	"augustberries/pkg/money"
+	"augustberries/pkg/units"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestEstimator создает калькулятор: сборка 1 рабочий день, отсечка в 14:00 UTC
+func newTestEstimator(t *testing.T) *DeliveryEstimator {
+	t.Helper()
+	leadTimes, err := ParseLeadTimes("RU:2-5, dhl/DE:1-2, DHL/*:4-6, *:7-14")
+	require.NoError(t, err)
+	return NewDeliveryEstimator(DeliveryRules{LeadTimes: leadTimes, ProcessingDays: 1, CutoffHour: 14})
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// ===================== ParseLeadTimes Tests =====================
+
+func TestParseLeadTimes(t *testing.T) {
+	leadTimes, err := ParseLeadTimes("ru:2-5,Dhl/de:3,*:7-14")
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]LeadTime{
+		"RU":     {MinDays: 2, MaxDays: 5},
+		"dhl/DE": {MinDays: 3, MaxDays: 3},
+		"*":      {MinDays: 7, MaxDays: 14},
+	}, leadTimes)
+}
+
+func TestParseLeadTimes_Invalid(t *testing.T) {
+	for _, spec := range []string{"RU", "RU:5-2", "RU:-1", "RU:two", ":3"} {
+		t.Run(spec, func(t *testing.T) {
+			_, err := ParseLeadTimes(spec)
+
+			assert.ErrorIs(t, err, ErrInvalidLeadTimes)
+		})
+	}
+}
+
+// ===================== Estimate Tests =====================
+
+func TestDeliveryEstimator_Estimate(t *testing.T) {
+	estimator := newTestEstimator(t)
+
+	tests := []struct {
+		name     string
+		country  string
+		placedAt time.Time
+		wantFrom time.Time
+		wantTo   time.Time
+	}{
+		{
+			// Среда до отсечки: сборка в четверг
+			name:     "before cutoff",
+			country:  "RU",
+			placedAt: time.Date(2024, 1, 17, 10, 0, 0, 0, time.UTC),
+			wantFrom: date(2024, 1, 20),
+			wantTo:   date(2024, 1, 23),
+		},
+		{
+			// Пятница после отсечки: сборка начинается в понедельник, отгрузка во вторник
+			name:     "after cutoff on friday",
+			country:  "ru",
+			placedAt: time.Date(2024, 1, 19, 15, 0, 0, 0, time.UTC),
+			wantFrom: date(2024, 1, 25),
+			wantTo:   date(2024, 1, 28),
+		},
+		{
+			// Суббота: сборка с понедельника, отгрузка во вторник; страна без правила - правило по умолчанию
+			name:     "weekend default region",
+			country:  "FR",
+			placedAt: time.Date(2024, 1, 20, 9, 0, 0, 0, time.UTC),
+			wantFrom: date(2024, 1, 30),
+			wantTo:   date(2024, 2, 6),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, ok := estimator.Estimate(tt.country, tt.placedAt)
+
+			require.True(t, ok)
+			assert.Equal(t, tt.wantFrom, from)
+			assert.Equal(t, tt.wantTo, to)
+		})
+	}
+}
+
+func TestDeliveryEstimator_Estimate_NoRule(t *testing.T) {
+	estimator := NewDeliveryEstimator(DeliveryRules{LeadTimes: map[string]LeadTime{"RU": {MinDays: 1, MaxDays: 2}}})
+
+	_, _, ok := estimator.Estimate("DE", time.Now())
+	assert.False(t, ok)
+
+	var disabled *DeliveryEstimator
+	_, _, ok = disabled.Estimate("RU", time.Now())
+	assert.False(t, ok)
+}
+
+func TestDeliveryEstimator_EstimateShipment_CarrierRules(t *testing.T) {
+	estimator := newTestEstimator(t)
+	shippedAt := time.Date(2024, 1, 17, 18, 0, 0, 0, time.UTC)
+
+	from, to, ok := estimator.EstimateShipment("DHL", "de", shippedAt)
+	require.True(t, ok)
+	assert.Equal(t, date(2024, 1, 18), from)
+	assert.Equal(t, date(2024, 1, 19), to)
+
+	// Перевозчик без правила для страны - правило перевозчика для всех стран
+	from, to, ok = estimator.EstimateShipment("dhl", "RU", shippedAt)
+	require.True(t, ok)
+	assert.Equal(t, date(2024, 1, 21), from)
+	assert.Equal(t, date(2024, 1, 23), to)
+
+	// Неизвестный перевозчик - правило страны
+	from, _, ok = estimator.EstimateShipment("СДЭК", "RU", shippedAt)
+	require.True(t, ok)
+	assert.Equal(t, date(2024, 1, 19), from)
+}
+
+// ===================== UpdateFromShipments Tests =====================
+
+func TestDeliveryEstimator_UpdateFromShipments(t *testing.T) {
+	estimator := newTestEstimator(t)
+	initialFrom, initialTo := date(2024, 1, 20), date(2024, 1, 30)
+	shipments := []entity.Shipment{
+		{Carrier: "DHL", Status: entity.ShipmentStatusInTransit, ShippedAt: time.Date(2024, 1, 18, 9, 0, 0, 0, time.UTC)},
+		{Carrier: "DHL", Status: entity.ShipmentStatusDelivered, ShippedAt: time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC)},
+	}
+
+	t.Run("shipped", func(t *testing.T) {
+		order := &entity.Order{Country: "DE", Status: entity.OrderStatusShipped, EstimatedDeliveryFrom: &initialFrom, EstimatedDeliveryTo: &initialTo}
+
+		changed := estimator.UpdateFromShipments(order, shipments)
+
+		assert.True(t, changed)
+		assert.Equal(t, date(2024, 1, 19), *order.EstimatedDeliveryFrom)
+		assert.Equal(t, date(2024, 1, 20), *order.EstimatedDeliveryTo)
+		// Повторный расчет по тем же отправлениям окно не меняет
+		assert.False(t, estimator.UpdateFromShipments(order, shipments))
+	})
+
+	t.Run("partially shipped keeps initial end", func(t *testing.T) {
+		order := &entity.Order{Country: "DE", Status: entity.OrderStatusPartiallyShipped, EstimatedDeliveryFrom: &initialFrom, EstimatedDeliveryTo: &initialTo}
+
+		changed := estimator.UpdateFromShipments(order, shipments)
+
+		assert.True(t, changed)
+		assert.Equal(t, date(2024, 1, 19), *order.EstimatedDeliveryFrom)
+		assert.Equal(t, initialTo, *order.EstimatedDeliveryTo)
+	})
+
+	t.Run("nothing in transit", func(t *testing.T) {
+		order := &entity.Order{Country: "DE", Status: entity.OrderStatusDelivered, EstimatedDeliveryFrom: &initialFrom, EstimatedDeliveryTo: &initialTo}
+
+		assert.False(t, estimator.UpdateFromShipments(order, shipments[1:]))
+		assert.Equal(t, initialFrom, *order.EstimatedDeliveryFrom)
+	})
+}
+
+// ===================== Order ETA Tests =====================
+
+func TestCreateOrder_ScheduledEstimatesFromActivation(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+	service.SetDeliveryEstimator(newTestEstimator(t))
+
+	ctx := context.Background()
+	productID := uuid.New()
+	scheduledFor := time.Now().Add(10 * 24 * time.Hour)
+
+	req := &entity.CreateOrderRequest{
+		Items:        []entity.OrderItemRequest{{ProductID: productID, Quantity: units.Of(1)}},
+		Currency:     "USD",
+		Country:      "ru",
+		ScheduledFor: &scheduledFor,
+	}
+
+	products := map[uuid.UUID]*entity.ProductAvailability{
+		productID: {ID: productID, Price: money.MustParse("20.00"), Status: entity.ProductStatusPublished},
+	}
+	catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).Return(products, nil)
+	expectQuote(catalogClient, req, products)
+	orderRepo.On("Create", ctx, mock.AnythingOfType("*entity.Order")).Return(nil)
+	orderItemRepo.On("Create", ctx, mock.AnythingOfType("*entity.OrderItem")).Return(nil)
+
+	// Act
+	result, err := service.CreateOrder(ctx, uuid.New(), req, "test-token")
+
+	// Assert
+	require.NoError(t, err)
+	wantFrom, wantTo, _ := service.deliveryEstimator.Estimate("RU", scheduledFor)
+	require.NotNil(t, result.EstimatedDeliveryFrom)
+	assert.Equal(t, wantFrom, *result.EstimatedDeliveryFrom)
+	assert.Equal(t, wantTo, *result.EstimatedDeliveryTo)
+}
+
+// ===================== Shipment ETA Tests =====================
+
+func TestCreateShipment_UpdatesDeliveryEstimate(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	shipmentRepo := new(mocks.MockShipmentRepository)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+	service := NewShipmentService(orderRepo, shipmentRepo, kafkaProducer)
+	service.SetDeliveryEstimator(newTestEstimator(t))
+
+	ctx := context.Background()
+	order := newShippableOrder()
+	order.Country = "RU"
+	req := &entity.CreateShipmentRequest{
+		TrackingNumber: "RA123456789RU",
+		Carrier:        "Почта России",
+		Items: []entity.ShipmentItemRequest{
+			{OrderItemID: order.Items[0].ID, Quantity: units.Of(3)},
+			{OrderItemID: order.Items[1].ID, Quantity: units.Of(1)},
+		},
+	}
+
+	orderRepo.On("GetWithItems", ctx, order.ID).Return(order, nil)
+	shipmentRepo.On("GetByOrderID", ctx, order.ID).Return([]entity.Shipment{}, nil)
+	shipmentRepo.On("Create", ctx, mock.AnythingOfType("*entity.Shipment")).Return(nil)
+	orderRepo.On("UpdateStatus", ctx, order.ID, order.Status, entity.OrderStatusShipped).Return(nil)
+	orderRepo.On("UpdateDeliveryEstimate", ctx, order.ID, mock.Anything, mock.Anything).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, order.ID.String(), mock.Anything).Return(nil)
+
+	// Act
+	shipment, updated, err := service.CreateShipment(ctx, order.ID, req, testAdmin)
+
+	// Assert
+	require.NoError(t, err)
+	wantFrom, wantTo, _ := service.deliveryEstimator.EstimateShipment("", "RU", shipment.ShippedAt)
+	require.NotNil(t, updated.EstimatedDeliveryFrom)
+	assert.Equal(t, wantFrom, *updated.EstimatedDeliveryFrom)
+	assert.Equal(t, wantTo, *updated.EstimatedDeliveryTo)
+
+	require.Len(t, kafkaProducer.Messages, 2)
+	var event entity.OrderEvent
+	require.NoError(t, json.Unmarshal(kafkaProducer.Messages[0], &event))
+	require.NotNil(t, event.EstimatedDeliveryTo)
+	assert.True(t, wantTo.Equal(*event.EstimatedDeliveryTo))
+}
+
+func TestUpdateShipmentStatus_EstimateOnlyChange(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	shipmentRepo := new(mocks.MockShipmentRepository)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+	service := NewShipmentService(orderRepo, shipmentRepo, kafkaProducer)
+	service.SetDeliveryEstimator(newTestEstimator(t))
+
+	ctx := context.Background()
+	order := newShippableOrder()
+	order.Country = "DE"
+	order.Status = entity.OrderStatusShipped
+	early := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	late := time.Date(2024, 1, 17, 9, 0, 0, 0, time.UTC)
+	first := entity.Shipment{
+		ID: uuid.New(), OrderID: order.ID, Carrier: "DHL", Status: entity.ShipmentStatusInTransit, ShippedAt: early,
+		Items: []entity.ShipmentItem{{OrderItemID: order.Items[0].ID, Quantity: units.Of(3)}},
+	}
+	second := entity.Shipment{
+		ID: uuid.New(), OrderID: order.ID, Carrier: "DHL", Status: entity.ShipmentStatusInTransit, ShippedAt: late,
+		Items: []entity.ShipmentItem{{OrderItemID: order.Items[1].ID, Quantity: units.Of(1)}},
+	}
+	order.EstimatedDeliveryFrom = ptrTime(date(2024, 1, 16))
+	order.EstimatedDeliveryTo = ptrTime(date(2024, 1, 19))
+
+	delivered := first
+	delivered.Status = entity.ShipmentStatusDelivered
+
+	orderRepo.On("GetWithItems", ctx, order.ID).Return(order, nil)
+	shipmentRepo.On("GetByID", ctx, first.ID).Return(&first, nil)
+	shipmentRepo.On("UpdateStatus", ctx, mock.AnythingOfType("*entity.Shipment")).Return(nil)
+	shipmentRepo.On("GetByOrderID", ctx, order.ID).Return([]entity.Shipment{delivered, second}, nil)
+	orderRepo.On("UpdateDeliveryEstimate", ctx, order.ID, mock.Anything, mock.Anything).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, order.ID.String(), mock.Anything).Return(nil)
+
+	// Act
+	_, updated, err := service.UpdateShipmentStatus(ctx, order.ID, first.ID, entity.ShipmentStatusDelivered, testAdmin)
+
+	// Assert: статус не изменился, но окно сузилось до второго отправления
+	require.NoError(t, err)
+	assert.Equal(t, entity.OrderStatusShipped, updated.Status)
+	assert.Equal(t, date(2024, 1, 18), *updated.EstimatedDeliveryFrom)
+	assert.Equal(t, date(2024, 1, 19), *updated.EstimatedDeliveryTo)
+	orderRepo.AssertCalled(t, "UpdateDeliveryEstimate", ctx, order.ID, mock.Anything, mock.Anything)
+	orderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.Len(t, kafkaProducer.Messages, 1)
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
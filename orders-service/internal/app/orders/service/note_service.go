@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/repository"
+
+	"github.com/google/uuid"
+)
+
+// ErrInternalNoteForbidden - покупатель пытается оставить служебную заметку
+var ErrInternalNoteForbidden = errors.New("internal notes are staff-only")
+
+// NoteService управляет заметками к заказам
+// Сотрудники (staff) видят и оставляют заметки к любому заказу магазина,
+// покупатель - только видимые ему заметки к своим заказам
+type NoteService struct {
+	orderRepo repository.OrderRepository
+	noteRepo  repository.OrderNoteRepository
+	summaries repository.OrderSummaryRepository // Проекция списков заказов, nil - список из таблицы orders
+}
+
+func NewNoteService(orderRepo repository.OrderRepository, noteRepo repository.OrderNoteRepository) *NoteService {
+	return &NoteService{
+		orderRepo: orderRepo,
+		noteRepo:  noteRepo,
+	}
+}
+
+// AddNote добавляет заметку к заказу от имени автора
+func (s *NoteService) AddNote(ctx context.Context, orderID, authorID uuid.UUID, staff bool, req *entity.CreateOrderNoteRequest) (*entity.OrderNote, error) {
+	if err := s.checkAccess(ctx, orderID, authorID, staff); err != nil {
+		return nil, err
+	}
+
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = entity.NoteVisibilityCustomer
+	}
+	if visibility == entity.NoteVisibilityInternal && !staff {
+		return nil, ErrInternalNoteForbidden
+	}
+
+	note := &entity.OrderNote{
+		ID:         uuid.New(),
+		OrderID:    orderID,
+		AuthorID:   authorID,
+		Visibility: visibility,
+		Body:       req.Body,
+		TicketID:   req.TicketID,
+	}
+
+	if err := s.noteRepo.Create(ctx, note); err != nil {
+		return nil, fmt.Errorf("failed to create order note: %w", err)
+	}
+
+	return note, nil
+}
+
+// GetNotes возвращает заметки заказа с учетом роли: служебные заметки видны только сотрудникам
+func (s *NoteService) GetNotes(ctx context.Context, orderID, userID uuid.UUID, staff bool) ([]entity.OrderNote, error) {
+	if err := s.checkAccess(ctx, orderID, userID, staff); err != nil {
+		return nil, err
+	}
+
+	notes, err := s.noteRepo.GetByOrderID(ctx, orderID, staff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order notes: %w", err)
+	}
+
+	return notes, nil
+}
+
+// ListOrders возвращает заказы магазина для admin списка с числом заметок по каждому заказу
+func (s *NoteService) ListOrders(ctx context.Context, filter entity.OrderFilter) ([]entity.AdminOrderSummary, error) {
+	orders, err := listSummaries(ctx, s.summaries, s.orderRepo, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	orderIDs := make([]uuid.UUID, len(orders))
+	for i, order := range orders {
+		orderIDs[i] = order.OrderID
+	}
+
+	counts, err := s.noteRepo.CountByOrderIDs(ctx, orderIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count order notes: %w", err)
+	}
+
+	summaries := make([]entity.AdminOrderSummary, len(orders))
+	for i, order := range orders {
+		summaries[i] = entity.AdminOrderSummary{OrderSummary: order, NotesCount: counts[order.OrderID]}
+	}
+
+	return summaries, nil
+}
+
+// checkAccess проверяет, что заказ существует в магазине и принадлежит пользователю (кроме сотрудников)
+func (s *NoteService) checkAccess(ctx context.Context, orderID, userID uuid.UUID, staff bool) error {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrderNotFound) {
+			return ErrOrderNotFound
+		}
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+
+	if !staff && order.UserID != userID {
+		return ErrUnauthorized
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/repository/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ===================== AddNote Tests =====================
+
+func TestAddNote_CustomerDefaultsToCustomerVisibility(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	noteRepo := new(mocks.MockOrderNoteRepository)
+	service := NewNoteService(orderRepo, noteRepo)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	order := &entity.Order{ID: uuid.New(), UserID: userID}
+
+	orderRepo.On("GetByID", ctx, order.ID).Return(order, nil)
+	noteRepo.On("Create", ctx, mock.AnythingOfType("*entity.OrderNote")).Return(nil)
+
+	// Act
+	note, err := service.AddNote(ctx, order.ID, userID, false, &entity.CreateOrderNoteRequest{Body: "Оставьте у двери"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, entity.NoteVisibilityCustomer, note.Visibility)
+	assert.Equal(t, userID, note.AuthorID)
+	noteRepo.AssertExpectations(t)
+}
+
+func TestAddNote_CustomerCannotAddInternal(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	noteRepo := new(mocks.MockOrderNoteRepository)
+	service := NewNoteService(orderRepo, noteRepo)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	order := &entity.Order{ID: uuid.New(), UserID: userID}
+
+	orderRepo.On("GetByID", ctx, order.ID).Return(order, nil)
+
+	// Act
+	req := &entity.CreateOrderNoteRequest{Body: "note", Visibility: entity.NoteVisibilityInternal}
+	_, err := service.AddNote(ctx, order.ID, userID, false, req)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInternalNoteForbidden)
+	noteRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestAddNote_StaffOnForeignOrder(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	noteRepo := new(mocks.MockOrderNoteRepository)
+	service := NewNoteService(orderRepo, noteRepo)
+
+	ctx := context.Background()
+	order := &entity.Order{ID: uuid.New(), UserID: uuid.New()}
+
+	orderRepo.On("GetByID", ctx, order.ID).Return(order, nil)
+	noteRepo.On("Create", ctx, mock.AnythingOfType("*entity.OrderNote")).Return(nil)
+
+	// Act
+	req := &entity.CreateOrderNoteRequest{Body: "Клиент звонил", Visibility: entity.NoteVisibilityInternal, TicketID: "SUP-1024"}
+	note, err := service.AddNote(ctx, order.ID, uuid.New(), true, req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, entity.NoteVisibilityInternal, note.Visibility)
+	assert.Equal(t, "SUP-1024", note.TicketID)
+}
+
+// ===================== GetNotes Tests =====================
+
+func TestGetNotes_CustomerSeesOnlyCustomerNotes(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	noteRepo := new(mocks.MockOrderNoteRepository)
+	service := NewNoteService(orderRepo, noteRepo)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	order := &entity.Order{ID: uuid.New(), UserID: userID}
+
+	orderRepo.On("GetByID", ctx, order.ID).Return(order, nil)
+	noteRepo.On("GetByOrderID", ctx, order.ID, false).Return([]entity.OrderNote{{ID: uuid.New()}}, nil)
+
+	// Act
+	notes, err := service.GetNotes(ctx, order.ID, userID, false)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, notes, 1)
+	noteRepo.AssertExpectations(t)
+}
+
+func TestGetNotes_Unauthorized(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	noteRepo := new(mocks.MockOrderNoteRepository)
+	service := NewNoteService(orderRepo, noteRepo)
+
+	ctx := context.Background()
+	order := &entity.Order{ID: uuid.New(), UserID: uuid.New()}
+
+	orderRepo.On("GetByID", ctx, order.ID).Return(order, nil)
+
+	// Act
+	_, err := service.GetNotes(ctx, order.ID, uuid.New(), false)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrUnauthorized)
+}
+
+// ===================== ListOrders Tests =====================
+
+func TestListOrders_WithNoteCounts(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	noteRepo := new(mocks.MockOrderNoteRepository)
+	service := NewNoteService(orderRepo, noteRepo)
+
+	ctx := context.Background()
+	first, second := uuid.New(), uuid.New()
+	filter := entity.OrderFilter{Status: entity.OrderStatusConfirmed}
+
+	orderRepo.On("List", ctx, filter).Return([]entity.Order{{ID: first}, {ID: second}}, nil)
+	noteRepo.On("CountByOrderIDs", ctx, []uuid.UUID{first, second}).Return(map[uuid.UUID]int{first: 3}, nil)
+
+	// Act
+	summaries, err := service.ListOrders(ctx, filter)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, summaries, 2)
+	assert.Equal(t, 3, summaries[0].NotesCount)
+	assert.Equal(t, 0, summaries[1].NotesCount)
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/repository"
+	"augustberries/pkg/money"
+	"augustberries/pkg/units"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrOrderNotEditable - состав заказа можно менять только до подтверждения (pending)
+	ErrOrderNotEditable = errors.New("order items can only be changed while order is pending")
+	// ErrOrderItemNotFound - позиции из запроса нет в заказе
+	ErrOrderItemNotFound = errors.New("order item not found")
+	// ErrEmptyOrder - после изменения в заказе не осталось позиций; такой заказ нужно отменить
+	ErrEmptyOrder = errors.New("order must keep at least one item")
+	// ErrOrderConverted - заказ сконвертирован в валюту покупателя до сохранения исходной валюты,
+	// пересчитать его по ценам каталога нельзя
+	ErrOrderConverted = errors.New("order was converted before its source currency was recorded")
+)
+
+// UpdateOrderItems меняет количество позиций заказа до подтверждения; количество 0 удаляет позицию
+// Все оставшиеся позиции заново проверяются и оцениваются по текущим ценам и остаткам каталога,
+// налоги и итог пересчитываются в исходной валюте заказа. В ORDER_UPDATED передается список изменений позиций
+func (s *OrderService) UpdateOrderItems(ctx context.Context, orderID, userID uuid.UUID, changes []entity.OrderItemQuantity, authToken string) (*entity.OrderWithItems, error) {
+	order, err := s.getOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+	if order.Status != entity.OrderStatusPending {
+		return nil, ErrOrderNotEditable
+	}
+	// Каталог отдает цены в валюте оформления: сконвертированный заказ пересчитывается в ней вместе с исходной доставкой,
+	// а Background Worker конвертирует его заново по ORDER_UPDATED
+	if order.ConvertedAt != nil {
+		if order.SourceCurrency == "" {
+			return nil, ErrOrderConverted
+		}
+		order.Currency = order.SourceCurrency
+		order.DeliveryPrice = order.SourceDeliveryPrice
+	}
+
+	items, err := s.orderItemRepo.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order items: %w", err)
+	}
+
+	quantities := make(map[uuid.UUID]units.Quantity, len(changes))
+	for _, change := range changes {
+		quantities[change.ItemID] = change.Quantity
+	}
+
+	var (
+		kept        []entity.OrderItem
+		removed     []uuid.UUID
+		itemChanges []entity.OrderItemChange
+	)
+	for _, item := range items {
+		quantity, changed := quantities[item.ID]
+		if !changed {
+			kept = append(kept, item)
+			continue
+		}
+		delete(quantities, item.ID)
+
+		if quantity != item.Quantity {
+			itemChanges = append(itemChanges, entity.OrderItemChange{
+				ItemID:      item.ID,
+				ProductID:   item.ProductID,
+				OldQuantity: item.Quantity,
+				NewQuantity: quantity,
+			})
+		}
+		if quantity == 0 {
+			removed = append(removed, item.ID)
+			continue
+		}
+		item.Quantity = quantity
+		kept = append(kept, item)
+	}
+	if len(quantities) > 0 {
+		return nil, ErrOrderItemNotFound
+	}
+	if len(kept) == 0 {
+		return nil, ErrEmptyOrder
+	}
+
+	s.catalogClient.SetAuthToken(authToken)
+
+	reqItems := make([]entity.OrderItemRequest, len(kept))
+	for i, item := range kept {
+		reqItems[i] = entity.OrderItemRequest{ProductID: item.ProductID, Quantity: item.Quantity}
+	}
+
+	prices, err := s.fetchPrices(ctx, reqItems)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCustomerLimits(ctx, userID, order.ID, reqItems, prices); err != nil {
+		return nil, err
+	}
+
+	categories := make(map[uuid.UUID]uuid.UUID, len(prices))
+	for i := range kept {
+		pricing := prices[kept[i].ProductID]
+		categories[kept[i].ProductID] = pricing.CategoryID
+
+		kept[i].UnitPrice = pricing.UnitPrice
+		kept[i].Product = pricing.Snapshot
+		// Налог считается заново: ставка могла измениться или перестать действовать
+		kept[i].TaxName, kept[i].TaxRate, kept[i].TaxAmount = "", 0, 0
+	}
+
+	if err := s.taxEngine.Apply(ctx, order.Country, order.Currency, kept, categories); err != nil {
+		return nil, err
+	}
+
+	lines := make([]money.Line, len(kept))
+	for i, item := range kept {
+		lines[i] = money.Line{UnitPrice: item.UnitPrice, Quantity: item.Quantity, Tax: item.TaxAmount}
+	}
+	totals, err := s.calculator.Calculate(lines, order.DeliveryPrice, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOrderTotal, err)
+	}
+
+	if err := s.confirmPrices(ctx, reqItems, kept); err != nil {
+		return nil, err
+	}
+
+	previousTotal := order.TotalPrice
+	order.TotalPrice = totals.Total
+	order.TaxTotal = totals.Tax
+
+	if err := s.orderRepo.UpdateItems(ctx, order, kept, removed); err != nil {
+		if errors.Is(err, repository.ErrOrderStatusChanged) {
+			return nil, ErrOrderNotEditable
+		}
+		return nil, fmt.Errorf("failed to update order items: %w", err)
+	}
+	order.ConvertedAt = nil
+	refreshSummaries(ctx, s.summaries, order.ID)
+
+	event := entity.OrderEvent{
+		EventType:          "ORDER_UPDATED",
+		TenantID:           order.TenantID,
+		OrderID:            order.ID,
+		OrderNumber:        order.Number,
+		UserID:             order.UserID,
+		TotalPrice:         order.TotalPrice,
+		TaxTotal:           order.TaxTotal,
+		Currency:           order.Currency,
+		PreferredCurrency:  order.PreferredCurrency,
+		Status:             order.Status,
+		ItemsCount:         len(kept),
+		Timestamp:          time.Now(),
+		ItemChanges:        itemChanges,
+		PreviousTotalPrice: &previousTotal,
+
+		EstimatedDeliveryFrom: order.EstimatedDeliveryFrom,
+		EstimatedDeliveryTo:   order.EstimatedDeliveryTo,
+	}
+	if err := s.publishOrderEvent(ctx, event); err != nil {
+		fmt.Printf("failed to publish order updated event: %v\n", err)
+	}
+
+	return &entity.OrderWithItems{
+		Order: *order,
+		Items: kept,
+	}, nil
+}
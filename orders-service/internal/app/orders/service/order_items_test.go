@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/repository"
+	"augustberries/orders-service/internal/app/orders/repository/mocks"
+	"augustberries/pkg/money"
+	"augustberries/pkg/units"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newPendingOrder создает заказ в pending с двумя позициями: 2 x 50.00 и 1 x 20.00, доставка 10.00
+func newPendingOrder(userID uuid.UUID) (*entity.Order, []entity.OrderItem) {
+	order := &entity.Order{
+		ID:            uuid.New(),
+		UserID:        userID,
+		Status:        entity.OrderStatusPending,
+		Currency:      "USD",
+		DeliveryPrice: money.MustParse("10.00"),
+		TotalPrice:    money.MustParse("130.00"),
+	}
+	items := []entity.OrderItem{
+		{ID: uuid.New(), OrderID: order.ID, ProductID: uuid.New(), Quantity: units.Of(2), UnitPrice: money.MustParse("50.00")},
+		{ID: uuid.New(), OrderID: order.ID, ProductID: uuid.New(), Quantity: units.Of(1), UnitPrice: money.MustParse("20.00")},
+	}
+	return order, items
+}
+
+// ==================== UpdateOrderItems Tests ====================
+
+func TestUpdateOrderItems_RepricesAndPublishesDiff(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	order, items := newPendingOrder(userID)
+	kept, removed := items[0], items[1]
+
+	orderRepo.On("GetByID", ctx, order.ID).Return(order, nil)
+	orderItemRepo.On("GetByOrderID", ctx, order.ID).Return(items, nil)
+
+	// Цена товара изменилась с момента оформления: позиция оценивается заново
+	products := map[uuid.UUID]*entity.ProductAvailability{
+		kept.ProductID: {ID: kept.ProductID, Price: money.MustParse("55.00"), Status: entity.ProductStatusPublished},
+	}
+	catalogClient.On("SetAuthToken", "test-token").Return()
+	catalogClient.On("GetAvailability", ctx, []uuid.UUID{kept.ProductID}).Return(products, nil)
+	expectQuote(catalogClient, &entity.CreateOrderRequest{Items: []entity.OrderItemRequest{{ProductID: kept.ProductID, Quantity: units.Of(1)}}}, products)
+	orderRepo.On("UpdateItems", ctx, order, mock.Anything, []uuid.UUID{removed.ID}).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, order.ID.String(), mock.Anything).Return(nil)
+
+	// Act
+	result, err := service.UpdateOrderItems(ctx, order.ID, userID, []entity.OrderItemQuantity{
+		{ItemID: kept.ID, Quantity: units.Of(1)},
+		{ItemID: removed.ID, Quantity: units.Of(0)},
+	}, "test-token")
+
+	// Assert: 1 x 55.00 + доставка 10.00
+	require.NoError(t, err)
+	assert.Equal(t, money.MustParse("65.00"), result.TotalPrice)
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, units.Of(1), result.Items[0].Quantity)
+	assert.Equal(t, money.MustParse("55.00"), result.Items[0].UnitPrice)
+
+	require.Len(t, kafkaProducer.Messages, 1)
+	var event entity.OrderEvent
+	require.NoError(t, json.Unmarshal(kafkaProducer.Messages[0], &event))
+	assert.Equal(t, "ORDER_UPDATED", event.EventType)
+	assert.Equal(t, 1, event.ItemsCount)
+	require.NotNil(t, event.PreviousTotalPrice)
+	assert.Equal(t, money.MustParse("130.00"), *event.PreviousTotalPrice)
+	assert.ElementsMatch(t, []entity.OrderItemChange{
+		{ItemID: kept.ID, ProductID: kept.ProductID, OldQuantity: units.Of(2), NewQuantity: units.Of(1)},
+		{ItemID: removed.ID, ProductID: removed.ProductID, OldQuantity: units.Of(1), NewQuantity: units.Of(0)},
+	}, event.ItemChanges)
+}
+
+func TestUpdateOrderItems_ConvertedOrderRepricedInSourceCurrency(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	order, items := newPendingOrder(userID)
+	kept, removed := items[0], items[1]
+
+	// Background Worker сконвертировал заказ из USD в EUR по курсу 0.5
+	convertedAt := time.Now().Add(-time.Minute)
+	order.SourceCurrency, order.SourceDeliveryPrice = "USD", order.DeliveryPrice
+	order.Currency, order.PreferredCurrency = "EUR", "EUR"
+	order.DeliveryPrice, order.TotalPrice = money.MustParse("5.00"), money.MustParse("65.00")
+	order.ConvertedAt = &convertedAt
+	items[0].UnitPrice, items[1].UnitPrice = money.MustParse("25.00"), money.MustParse("10.00")
+
+	orderRepo.On("GetByID", ctx, order.ID).Return(order, nil)
+	orderItemRepo.On("GetByOrderID", ctx, order.ID).Return(items, nil)
+
+	// Каталог отдает цену в валюте оформления (USD)
+	products := map[uuid.UUID]*entity.ProductAvailability{
+		kept.ProductID: {ID: kept.ProductID, Price: money.MustParse("55.00"), Status: entity.ProductStatusPublished},
+	}
+	catalogClient.On("SetAuthToken", "test-token").Return()
+	catalogClient.On("GetAvailability", ctx, []uuid.UUID{kept.ProductID}).Return(products, nil)
+	expectQuote(catalogClient, &entity.CreateOrderRequest{Items: []entity.OrderItemRequest{{ProductID: kept.ProductID, Quantity: units.Of(1)}}}, products)
+	// Сохранение проверяет, что заказ не сконвертировали заново после чтения
+	orderRepo.On("UpdateItems", ctx, mock.MatchedBy(func(o *entity.Order) bool {
+		return o.Currency == "USD" && o.ConvertedAt != nil && o.ConvertedAt.Equal(convertedAt)
+	}), mock.Anything, []uuid.UUID{removed.ID}).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, order.ID.String(), mock.Anything).Return(nil)
+
+	// Act
+	result, err := service.UpdateOrderItems(ctx, order.ID, userID, []entity.OrderItemQuantity{
+		{ItemID: kept.ID, Quantity: units.Of(1)},
+		{ItemID: removed.ID, Quantity: units.Of(0)},
+	}, "test-token")
+
+	// Assert: 1 x 55.00 + исходная доставка 10.00, все в USD
+	require.NoError(t, err)
+	assert.Equal(t, "USD", result.Currency)
+	assert.Equal(t, money.MustParse("10.00"), result.DeliveryPrice)
+	assert.Equal(t, money.MustParse("65.00"), result.TotalPrice)
+	assert.Nil(t, result.ConvertedAt)
+	orderRepo.AssertExpectations(t)
+
+	// Worker конвертирует заказ заново из USD в предпочитаемую валюту
+	require.Len(t, kafkaProducer.Messages, 1)
+	var event entity.OrderEvent
+	require.NoError(t, json.Unmarshal(kafkaProducer.Messages[0], &event))
+	assert.Equal(t, "USD", event.Currency)
+	assert.Equal(t, "EUR", event.PreferredCurrency)
+	assert.NotEmpty(t, event.ItemChanges)
+}
+
+func TestUpdateOrderItems_Rejected(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name      string
+		status    entity.OrderStatus
+		converted bool // Заказ сконвертирован до сохранения исходной валюты
+		changes   func(items []entity.OrderItem) []entity.OrderItemQuantity
+		wantErr   error
+	}{
+		{
+			name:   "order confirmed",
+			status: entity.OrderStatusConfirmed,
+			changes: func(items []entity.OrderItem) []entity.OrderItemQuantity {
+				return []entity.OrderItemQuantity{{ItemID: items[0].ID, Quantity: units.Of(1)}}
+			},
+			wantErr: ErrOrderNotEditable,
+		},
+		{
+			name:   "unknown item",
+			status: entity.OrderStatusPending,
+			changes: func([]entity.OrderItem) []entity.OrderItemQuantity {
+				return []entity.OrderItemQuantity{{ItemID: uuid.New(), Quantity: units.Of(1)}}
+			},
+			wantErr: ErrOrderItemNotFound,
+		},
+		{
+			name:   "all items removed",
+			status: entity.OrderStatusPending,
+			changes: func(items []entity.OrderItem) []entity.OrderItemQuantity {
+				return []entity.OrderItemQuantity{{ItemID: items[0].ID}, {ItemID: items[1].ID}}
+			},
+			wantErr: ErrEmptyOrder,
+		},
+		{
+			name:      "converted without source currency",
+			status:    entity.OrderStatusPending,
+			converted: true,
+			changes: func(items []entity.OrderItem) []entity.OrderItemQuantity {
+				return []entity.OrderItemQuantity{{ItemID: items[0].ID, Quantity: units.Of(1)}}
+			},
+			wantErr: ErrOrderConverted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			orderRepo := new(mocks.MockOrderRepository)
+			orderItemRepo := new(mocks.MockOrderItemRepository)
+			catalogClient := new(mocks.MockCatalogServiceClient)
+			service := NewOrderService(orderRepo, orderItemRepo, catalogClient, &mocks.MockMessagePublisher{}, testQuoteSigner, nil, nil)
+
+			order, items := newPendingOrder(userID)
+			order.Status = tt.status
+			if tt.converted {
+				convertedAt := time.Now()
+				order.ConvertedAt = &convertedAt
+			}
+			orderRepo.On("GetByID", ctx, order.ID).Return(order, nil)
+			orderItemRepo.On("GetByOrderID", ctx, order.ID).Return(items, nil)
+
+			// Act
+			result, err := service.UpdateOrderItems(ctx, order.ID, userID, tt.changes(items), "test-token")
+
+			// Assert
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, result)
+			catalogClient.AssertNotCalled(t, "GetAvailability", mock.Anything, mock.Anything)
+			orderRepo.AssertNotCalled(t, "UpdateItems", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUpdateOrderItems_ConfirmedConcurrently(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, &mocks.MockMessagePublisher{}, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	order, items := newPendingOrder(userID)
+
+	products := map[uuid.UUID]*entity.ProductAvailability{
+		items[0].ProductID: {ID: items[0].ProductID, Price: money.MustParse("50.00"), Status: entity.ProductStatusPublished},
+		items[1].ProductID: {ID: items[1].ProductID, Price: money.MustParse("20.00"), Status: entity.ProductStatusPublished},
+	}
+	orderRepo.On("GetByID", ctx, order.ID).Return(order, nil)
+	orderItemRepo.On("GetByOrderID", ctx, order.ID).Return(items, nil)
+	catalogClient.On("SetAuthToken", mock.Anything).Return()
+	catalogClient.On("GetAvailability", ctx, mock.Anything).Return(products, nil)
+	expectQuote(catalogClient, &entity.CreateOrderRequest{Items: []entity.OrderItemRequest{
+		{ProductID: items[0].ProductID, Quantity: units.Of(3)},
+		{ProductID: items[1].ProductID, Quantity: units.Of(1)},
+	}}, products)
+	orderRepo.On("UpdateItems", ctx, order, mock.Anything, []uuid.UUID(nil)).Return(repository.ErrOrderStatusChanged)
+
+	// Act
+	_, err := service.UpdateOrderItems(ctx, order.ID, userID, []entity.OrderItemQuantity{{ItemID: items[0].ID, Quantity: units.Of(3)}}, "test-token")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrOrderNotEditable)
+}
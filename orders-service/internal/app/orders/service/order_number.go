@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"augustberries/orders-service/internal/app/orders/repository"
+)
+
+// orderNumberMaxStep - наибольший шаг счетчика номеров
+// Случайный шаг сохраняет порядок номеров, но не позволяет перебрать чужие заказы по своему номеру
+const orderNumberMaxStep = 9
+
+// OrderNumberer выдает человекочитаемые номера заказов вида AB-20240115-000123
+// Счетчик ведется отдельно для каждого магазина и дня
+type OrderNumberer struct {
+	repo   repository.OrderNumberRepository
+	prefix string
+	now    func() time.Time
+	step   func() int64
+}
+
+// NewOrderNumberer создает генератор номеров заказов с префиксом магазина
+func NewOrderNumberer(repo repository.OrderNumberRepository, prefix string) *OrderNumberer {
+	return &OrderNumberer{
+		repo:   repo,
+		prefix: strings.ToUpper(prefix),
+		now:    time.Now,
+		step:   func() int64 { return 1 + rand.Int64N(orderNumberMaxStep) },
+	}
+}
+
+// Next выдает следующий номер заказа магазина из контекста
+// Нулевой (nil) генератор возвращает пустой номер: заказ создается только с UUID
+func (n *OrderNumberer) Next(ctx context.Context) (string, error) {
+	if n == nil {
+		return "", nil
+	}
+
+	day := n.now().UTC()
+	value, err := n.repo.Next(ctx, day, n.step())
+	if err != nil {
+		return "", fmt.Errorf("failed to generate order number: %w", err)
+	}
+
+	return fmt.Sprintf("%s-%s-%06d", n.prefix, day.Format("20060102"), value), nil
+}
+
+// NormalizeOrderNumber приводит номер из запроса к каноническому виду
+func NormalizeOrderNumber(number string) string {
+	return strings.ToUpper(strings.TrimSpace(number))
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/repository/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// ==================== OrderNumberer Tests ====================
+
+func TestOrderNumberer_Next(t *testing.T) {
+	// Arrange
+	repo := new(mocks.MockOrderNumberRepository)
+	numberer := NewOrderNumberer(repo, "ab")
+	day := time.Date(2024, 1, 15, 23, 30, 0, 0, time.UTC)
+	numberer.now = func() time.Time { return day }
+	numberer.step = func() int64 { return 3 }
+
+	ctx := context.Background()
+	repo.On("Next", ctx, day, int64(3)).Return(int64(123), nil)
+
+	// Act
+	number, err := numberer.Next(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "AB-20240115-000123", number)
+}
+
+func TestOrderNumberer_StepIsRandomButPositive(t *testing.T) {
+	numberer := NewOrderNumberer(nil, "AB")
+
+	for i := 0; i < 100; i++ {
+		step := numberer.step()
+		assert.GreaterOrEqual(t, step, int64(1))
+		assert.LessOrEqual(t, step, int64(orderNumberMaxStep))
+	}
+}
+
+func TestOrderNumberer_NilGeneratesNoNumber(t *testing.T) {
+	var numberer *OrderNumberer
+
+	number, err := numberer.Next(context.Background())
+
+	require.NoError(t, err)
+	assert.Empty(t, number)
+}
+
+func TestOrderNumberer_RepoError(t *testing.T) {
+	repo := new(mocks.MockOrderNumberRepository)
+	repo.On("Next", mock.Anything, mock.Anything, mock.Anything).Return(int64(0), errors.New("db down"))
+
+	_, err := NewOrderNumberer(repo, "AB").Next(context.Background())
+
+	assert.Error(t, err)
+}
+
+// ==================== GetOrderByNumber Tests ====================
+
+func TestGetOrderByNumber_NormalizesAndChecksOwner(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	service := NewOrderService(orderRepo, new(mocks.MockOrderItemRepository), new(mocks.MockCatalogServiceClient), &mocks.MockMessagePublisher{}, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	order := &entity.OrderWithItems{Order: entity.Order{Number: "AB-20240115-000123"}}
+	orderRepo.On("GetByNumberWithItems", ctx, "AB-20240115-000123").Return(order, nil)
+
+	// Act
+	_, err := service.GetOrderByNumber(ctx, " ab-20240115-000123 ", order.UserID)
+	_, otherErr := service.GetOrderByNumber(ctx, "AB-20240115-000123", uuid.New())
+
+	// Assert
+	require.NoError(t, err)
+	assert.ErrorIs(t, otherErr, ErrUnauthorized)
+}
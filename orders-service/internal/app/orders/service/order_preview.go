@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/infrastructure"
+	"augustberries/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// SetExchangeRates включает в предпросмотр заказа итог в предпочитаемой валюте покупателя; nil - без конвертации
+func (s *OrderService) SetExchangeRates(rates infrastructure.ExchangeRateClient) {
+	s.rates = rates
+}
+
+// PreviewOrder считает заказ так же, как CreateOrder, но ничего не сохраняет и не отправляет событий
+// Ошибки те же, что у CreateOrder: недоступные товары, истекшая котировка, расхождение с expected_total
+func (s *OrderService) PreviewOrder(ctx context.Context, userID uuid.UUID, req *entity.CreateOrderRequest, authToken string) (*entity.OrderPreview, error) {
+	order, orderItems, totals, err := s.priceOrder(ctx, userID, req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]entity.ItemResponse, len(orderItems))
+	for i, item := range orderItems {
+		items[i] = entity.ItemResponse{
+			ProductID:   item.ProductID,
+			ProductName: item.Product.Name,
+			Quantity:    item.Quantity,
+			Unit:        item.Product.Unit,
+			UnitPrice:   item.UnitPrice,
+			TotalPrice:  item.UnitPrice.MulQuantity(item.Quantity),
+			TaxRate:     item.TaxRate,
+			TaxAmount:   item.TaxAmount,
+		}
+	}
+
+	return &entity.OrderPreview{
+		Currency:      order.Currency,
+		Country:       order.Country,
+		Items:         items,
+		Subtotal:      totals.Subtotal,
+		TaxTotal:      totals.Tax,
+		DeliveryPrice: totals.Delivery,
+		TotalPrice:    totals.Total,
+		TaxBreakdown:  TaxBreakdown(orderItems),
+
+		EstimatedDeliveryFrom: order.EstimatedDeliveryFrom,
+		EstimatedDeliveryTo:   order.EstimatedDeliveryTo,
+
+		Conversion: s.previewConversion(ctx, order, orderItems),
+	}, nil
+}
+
+// previewConversion пересчитывает заказ в предпочитаемую валюту покупателя тем же расчетом, что и Background Worker
+// Недоступный курс не мешает предпросмотру: итог в валюте заказа точный, конвертация справочная
+func (s *OrderService) previewConversion(ctx context.Context, order *entity.Order, items []entity.OrderItem) *entity.ConversionPreview {
+	target := order.PreferredCurrency
+	if s.rates == nil || target == "" || target == order.Currency {
+		return nil
+	}
+
+	rate, err := s.rates.GetRate(ctx, order.Currency, target)
+	if err != nil {
+		log.Printf("Order preview without conversion %s -> %s: %v", order.Currency, target, err)
+		return nil
+	}
+
+	lines := make([]money.Line, len(items))
+	for i, item := range items {
+		lines[i] = money.Line{UnitPrice: item.UnitPrice, Quantity: item.Quantity, Tax: item.TaxAmount}
+	}
+
+	_, totals, err := s.calculator.Convert(lines, order.DeliveryPrice, 0, rate, money.LookupCurrency(target))
+	if err != nil {
+		log.Printf("Order preview without conversion %s -> %s: %v", order.Currency, target, err)
+		return nil
+	}
+
+	return &entity.ConversionPreview{
+		Currency:      target,
+		Rate:          rate,
+		Subtotal:      totals.Subtotal,
+		TaxTotal:      totals.Tax,
+		DeliveryPrice: totals.Delivery,
+		TotalPrice:    totals.Total,
+	}
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/repository/mocks"
+	"augustberries/pkg/money"
+	"augustberries/pkg/quote"
+	"augustberries/pkg/units"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// ===================== PreviewOrder Tests =====================
+
+func TestPreviewOrder_MatchesCreatedOrder(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	firstID, secondID := uuid.New(), uuid.New()
+
+	req := &entity.CreateOrderRequest{
+		Items: []entity.OrderItemRequest{
+			{ProductID: firstID, Quantity: units.Of(3)},
+			{ProductID: secondID, Quantity: units.Of(1)},
+		},
+		DeliveryPrice: money.MustParse("4.99"),
+		Currency:      "USD",
+	}
+
+	products := map[uuid.UUID]*entity.ProductAvailability{
+		firstID:  {ID: firstID, Name: "Blueberry Box", Price: money.MustParse("12.49"), Status: entity.ProductStatusPublished},
+		secondID: {ID: secondID, Name: "Honey Jar", Price: money.MustParse("7.30"), Status: entity.ProductStatusPublished},
+	}
+	catalogClient.On("GetAvailability", ctx, mock.Anything).Return(products, nil)
+	expectQuote(catalogClient, req, products)
+	orderRepo.On("Create", ctx, mock.AnythingOfType("*entity.Order")).Return(nil)
+	orderItemRepo.On("Create", ctx, mock.AnythingOfType("*entity.OrderItem")).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+
+	// Act
+	preview, err := service.PreviewOrder(ctx, userID, req, "test-token")
+	require.NoError(t, err)
+	// Предпросмотр ничего не сохраняет и не отправляет событий
+	orderRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	assert.Empty(t, kafkaProducer.Messages)
+
+	order, err := service.CreateOrder(ctx, userID, req, "test-token")
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, order.Currency, preview.Currency)
+	assert.Equal(t, order.TotalPrice, preview.TotalPrice)
+	assert.Equal(t, order.TaxTotal, preview.TaxTotal)
+	assert.Equal(t, order.DeliveryPrice, preview.DeliveryPrice)
+	assert.Equal(t, money.MustParse("44.77"), preview.Subtotal)
+	assert.Equal(t, TaxBreakdown(order.Items), preview.TaxBreakdown)
+	require.Len(t, preview.Items, len(order.Items))
+	for i, item := range order.Items {
+		assert.Equal(t, item.ProductID, preview.Items[i].ProductID)
+		assert.Equal(t, item.UnitPrice, preview.Items[i].UnitPrice)
+		assert.Equal(t, item.Quantity, preview.Items[i].Quantity)
+	}
+	assert.Nil(t, preview.Conversion)
+}
+
+func TestPreviewOrder_WithQuoteToken(t *testing.T) {
+	// Arrange
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	service := NewOrderService(new(mocks.MockOrderRepository), new(mocks.MockOrderItemRepository), catalogClient,
+		&mocks.MockMessagePublisher{}, testQuoteSigner, nil, nil)
+
+	productID := uuid.New()
+	token, err := testQuoteSigner.Sign(&quote.Quote{
+		ID:        uuid.New(),
+		Items:     []quote.Item{{ProductID: productID, Quantity: units.Of(2), UnitPrice: money.MustParse("99.99"), Name: "Blueberry Box"}},
+		Currency:  "USD",
+		IssuedAt:  time.Now(),
+		ExpiresAt: time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+
+	req := &entity.CreateOrderRequest{
+		Items:         []entity.OrderItemRequest{{ProductID: productID, Quantity: units.Of(2)}},
+		DeliveryPrice: money.MustParse("10.00"),
+		Currency:      "USD",
+		QuoteToken:    token,
+	}
+
+	// Act
+	preview, err := service.PreviewOrder(context.Background(), uuid.New(), req, "test-token")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, money.MustParse("209.98"), preview.TotalPrice)
+	assert.Equal(t, "Blueberry Box", preview.Items[0].ProductName)
+	// Цены берутся из подписанной котировки, каталог не вызывается
+	catalogClient.AssertNotCalled(t, "GetAvailability", mock.Anything, mock.Anything)
+	catalogClient.AssertNotCalled(t, "ValidateProducts", mock.Anything, mock.Anything)
+}
+
+func TestPreviewOrder_ExpectedTotalMismatch(t *testing.T) {
+	// Arrange
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	service := NewOrderService(new(mocks.MockOrderRepository), new(mocks.MockOrderItemRepository), catalogClient,
+		&mocks.MockMessagePublisher{}, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	productID := uuid.New()
+	staleTotal := money.MustParse("100.00")
+	req := &entity.CreateOrderRequest{
+		Items:         []entity.OrderItemRequest{{ProductID: productID, Quantity: units.Of(2)}},
+		DeliveryPrice: money.MustParse("10.00"),
+		Currency:      "USD",
+		ExpectedTotal: &staleTotal,
+	}
+	catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).Return(map[uuid.UUID]*entity.ProductAvailability{
+		productID: {ID: productID, Price: money.MustParse("50.00"), Status: entity.ProductStatusPublished},
+	}, nil)
+
+	// Act
+	preview, err := service.PreviewOrder(ctx, uuid.New(), req, "test-token")
+
+	// Assert
+	assert.True(t, errors.Is(err, ErrTotalMismatch))
+	assert.Nil(t, preview)
+}
+
+func TestPreviewOrder_Conversion(t *testing.T) {
+	tests := []struct {
+		name     string
+		rateErr  error
+		expected *entity.ConversionPreview
+	}{
+		{
+			name: "converted with worker rate",
+			expected: &entity.ConversionPreview{
+				Currency:      "EUR",
+				Rate:          0.5,
+				Subtotal:      money.MustParse("50.00"),
+				DeliveryPrice: money.MustParse("5.00"),
+				TotalPrice:    money.MustParse("55.00"),
+			},
+		},
+		// Недоступный курс не мешает предпросмотру
+		{name: "rate unavailable", rateErr: errors.New("worker unavailable")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			catalogClient := new(mocks.MockCatalogServiceClient)
+			rates := new(mocks.MockExchangeRateClient)
+			service := NewOrderService(new(mocks.MockOrderRepository), new(mocks.MockOrderItemRepository), catalogClient,
+				&mocks.MockMessagePublisher{}, testQuoteSigner, nil, nil)
+			service.SetExchangeRates(rates)
+
+			ctx := context.Background()
+			productID := uuid.New()
+			req := &entity.CreateOrderRequest{
+				Items:             []entity.OrderItemRequest{{ProductID: productID, Quantity: units.Of(2)}},
+				DeliveryPrice:     money.MustParse("10.00"),
+				Currency:          "USD",
+				PreferredCurrency: "EUR",
+			}
+			catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).Return(map[uuid.UUID]*entity.ProductAvailability{
+				productID: {ID: productID, Price: money.MustParse("50.00"), Status: entity.ProductStatusPublished},
+			}, nil)
+			rates.On("GetRate", ctx, "USD", "EUR").Return(0.5, tt.rateErr)
+
+			// Act
+			preview, err := service.PreviewOrder(ctx, uuid.New(), req, "test-token")
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, money.MustParse("110.00"), preview.TotalPrice)
+			assert.Equal(t, tt.expected, preview.Conversion)
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/repository"
+	"augustberries/pkg/metrics"
+	"augustberries/pkg/recovery"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+)
+
+// RetentionMode - что происходит с заказом по истечении срока хранения
+type RetentionMode string
+
+const (
+	RetentionAnonymize RetentionMode = "anonymize" // Удалить покупателя, email гостя и заметки, суммы остаются для отчетности
+	RetentionPurge     RetentionMode = "purge"     // Удалить заказ целиком
+)
+
+// RetentionStatuses - завершенные статусы: заказы в остальных статусах срок хранения не затрагивает
+var RetentionStatuses = []entity.OrderStatus{entity.OrderStatusDelivered, entity.OrderStatusCancelled}
+
+// RetentionPolicy - политика хранения завершенных заказов
+type RetentionPolicy struct {
+	PeriodDays int           // Срок хранения от оформления заказа; 0 - заказы хранятся бессрочно
+	Mode       RetentionMode // Обезличивание или удаление
+	Schedule   string        // Расписание фоновой задачи (формат robfig/cron)
+	BatchSize  int           // Заказов за один запрос к базе
+}
+
+// Enabled сообщает, ограничен ли срок хранения
+func (p RetentionPolicy) Enabled() bool {
+	return p.PeriodDays > 0
+}
+
+// RetentionService обезличивает или удаляет завершенные заказы по истечении срока хранения
+// Заказы на legal hold пропускаются, пока hold не снят
+type RetentionService struct {
+	repo   repository.RetentionRepository
+	policy RetentionPolicy
+	now    func() time.Time
+}
+
+// NewRetentionService создает сервис сроков хранения заказов
+func NewRetentionService(repo repository.RetentionRepository, policy RetentionPolicy) *RetentionService {
+	return &RetentionService{
+		repo:   repo,
+		policy: policy,
+		now:    time.Now,
+	}
+}
+
+// Policy возвращает политику хранения и число заказов магазина на legal hold
+func (s *RetentionService) Policy(ctx context.Context) (*entity.RetentionPolicyResponse, error) {
+	holds, err := s.repo.CountLegalHolds(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count legal holds: %w", err)
+	}
+
+	return &entity.RetentionPolicyResponse{
+		Enabled:    s.policy.Enabled(),
+		PeriodDays: s.policy.PeriodDays,
+		Mode:       string(s.policy.Mode),
+		Statuses:   RetentionStatuses,
+		Schedule:   s.policy.Schedule,
+		BatchSize:  s.policy.BatchSize,
+		LegalHolds: holds,
+	}, nil
+}
+
+// SetLegalHold ставит или снимает legal hold заказа магазина
+func (s *RetentionService) SetLegalHold(ctx context.Context, orderID uuid.UUID, hold bool) error {
+	if err := s.repo.SetLegalHold(ctx, orderID, hold); err != nil {
+		if errors.Is(err, repository.ErrOrderNotFound) {
+			return ErrOrderNotFound
+		}
+		return fmt.Errorf("failed to set legal hold: %w", err)
+	}
+	return nil
+}
+
+// ApplyRetention обрабатывает заказы всех магазинов с истекшим сроком хранения и возвращает их число
+// Пачки запрашиваются, пока находятся заказы: обработанные заказы в следующую пачку не попадают
+func (s *RetentionService) ApplyRetention(ctx context.Context) (int, error) {
+	if !s.policy.Enabled() {
+		return 0, nil
+	}
+
+	before := s.now().AddDate(0, 0, -s.policy.PeriodDays)
+	processed := 0
+	for {
+		expired, err := s.repo.ListExpired(ctx, RetentionStatuses, before, s.policy.BatchSize)
+		if err != nil {
+			return processed, fmt.Errorf("failed to get expired orders: %w", err)
+		}
+
+		batch := 0
+		for i := range expired {
+			order := &expired[i]
+			// Задача обслуживает все магазины: запросы выполняются в магазине заказа
+			if err := s.apply(tenant.WithID(ctx, order.TenantID), order.ID); err != nil {
+				// Legal hold поставлен после выборки или заказ обработан другим экземпляром
+				if !errors.Is(err, repository.ErrOrderRetained) {
+					log.Printf("ERROR: Failed to %s expired order %s: %v", s.policy.Mode, order.ID, err)
+				}
+				continue
+			}
+			batch++
+		}
+		metrics.OrdersRetentionProcessed.WithLabelValues(string(s.policy.Mode)).Add(float64(batch))
+		processed += batch
+
+		// Пачка, в которой ничего не обработано, повторится целиком: ждем следующего запуска
+		if len(expired) < s.policy.BatchSize || batch == 0 {
+			return processed, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return processed, err
+		}
+	}
+}
+
+func (s *RetentionService) apply(ctx context.Context, orderID uuid.UUID) error {
+	if s.policy.Mode == RetentionPurge {
+		return s.repo.Purge(ctx, orderID)
+	}
+	return s.repo.Anonymize(ctx, orderID, s.now())
+}
+
+// RetentionScheduler периодически применяет политику хранения заказов
+type RetentionScheduler struct {
+	cron    *cron.Cron
+	service *RetentionService
+}
+
+// NewRetentionScheduler создает планировщик; запуски не накладываются друг на друга
+func NewRetentionScheduler(service *RetentionService) *RetentionScheduler {
+	c := cron.New(
+		cron.WithLogger(cron.VerbosePrintfLogger(log.Default())),
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)),
+	)
+
+	return &RetentionScheduler{
+		cron:    c,
+		service: service,
+	}
+}
+
+// Start запускает планировщик по расписанию политики; первый запуск - по расписанию, а не при старте
+func (s *RetentionScheduler) Start(ctx context.Context) error {
+	policy := s.service.policy
+	log.Printf("Starting order retention (%s after %d days) with schedule: %s", policy.Mode, policy.PeriodDays, policy.Schedule)
+
+	if _, err := s.cron.AddFunc(policy.Schedule, recovery.Wrap("orders-service", "order_retention", func() { s.run(ctx) })); err != nil {
+		return err
+	}
+
+	s.cron.Start()
+	return nil
+}
+
+// Stop останавливает планировщик и ждет завершения текущего запуска
+func (s *RetentionScheduler) Stop() {
+	log.Println("Stopping order retention scheduler...")
+	<-s.cron.Stop().Done()
+	log.Println("Order retention scheduler stopped")
+}
+
+func (s *RetentionScheduler) run(ctx context.Context) {
+	processed, err := s.service.ApplyRetention(ctx)
+	if err != nil {
+		log.Printf("ERROR: Failed to apply order retention: %v", err)
+	}
+	if processed > 0 {
+		log.Printf("Order retention: %d orders processed (%s)", processed, s.service.policy.Mode)
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/repository"
+	"augustberries/orders-service/internal/app/orders/repository/mocks"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var retentionNow = time.Date(2025, 3, 10, 3, 0, 0, 0, time.UTC)
+
+func newTestRetentionService(repo *mocks.MockRetentionRepository, mode RetentionMode, batch int) *RetentionService {
+	s := NewRetentionService(repo, RetentionPolicy{PeriodDays: 365, Mode: mode, Schedule: "@daily", BatchSize: batch})
+	s.now = func() time.Time { return retentionNow }
+	return s
+}
+
+// ===================== ApplyRetention Tests =====================
+
+func TestApplyRetention_AnonymizesInOrderTenant(t *testing.T) {
+	// Arrange
+	repo := new(mocks.MockRetentionRepository)
+	service := newTestRetentionService(repo, RetentionAnonymize, 10)
+
+	ctx := context.Background()
+	before := retentionNow.AddDate(-1, 0, 0)
+	first := entity.Order{ID: uuid.New(), TenantID: "shop-a"}
+	second := entity.Order{ID: uuid.New(), TenantID: "shop-b"}
+
+	repo.On("ListExpired", ctx, RetentionStatuses, before, 10).Return([]entity.Order{first, second}, nil)
+	repo.On("Anonymize", tenant.WithID(ctx, "shop-a"), first.ID, retentionNow).Return(nil)
+	repo.On("Anonymize", tenant.WithID(ctx, "shop-b"), second.ID, retentionNow).Return(nil)
+
+	// Act
+	processed, err := service.ApplyRetention(ctx)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2, processed)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "Purge", mock.Anything, mock.Anything)
+}
+
+func TestApplyRetention_PurgeSkipsRetainedAndContinuesBatches(t *testing.T) {
+	// Arrange
+	repo := new(mocks.MockRetentionRepository)
+	service := newTestRetentionService(repo, RetentionPurge, 2)
+
+	ctx := context.Background()
+	held := entity.Order{ID: uuid.New(), TenantID: "default"}
+	failed := entity.Order{ID: uuid.New(), TenantID: "default"}
+	expired := entity.Order{ID: uuid.New(), TenantID: "default"}
+	last := entity.Order{ID: uuid.New(), TenantID: "default"}
+
+	// Legal hold поставлен после выборки: заказ пропускается, следующая пачка запрашивается
+	repo.On("ListExpired", ctx, RetentionStatuses, mock.Anything, 2).Return([]entity.Order{held, expired}, nil).Once()
+	repo.On("ListExpired", ctx, RetentionStatuses, mock.Anything, 2).Return([]entity.Order{failed, last}, nil).Once()
+	repo.On("ListExpired", ctx, RetentionStatuses, mock.Anything, 2).Return([]entity.Order{}, nil).Once()
+	repo.On("Purge", mock.Anything, held.ID).Return(repository.ErrOrderRetained)
+	repo.On("Purge", mock.Anything, expired.ID).Return(nil)
+	repo.On("Purge", mock.Anything, failed.ID).Return(errors.New("connection reset"))
+	repo.On("Purge", mock.Anything, last.ID).Return(nil)
+
+	// Act
+	processed, err := service.ApplyRetention(ctx)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2, processed)
+	repo.AssertNumberOfCalls(t, "ListExpired", 3)
+	repo.AssertNotCalled(t, "Anonymize", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestApplyRetention_StopsWhenBatchMakesNoProgress(t *testing.T) {
+	// Arrange
+	repo := new(mocks.MockRetentionRepository)
+	service := newTestRetentionService(repo, RetentionAnonymize, 1)
+
+	ctx := context.Background()
+	order := entity.Order{ID: uuid.New(), TenantID: "default"}
+
+	repo.On("ListExpired", ctx, RetentionStatuses, mock.Anything, 1).Return([]entity.Order{order}, nil)
+	repo.On("Anonymize", mock.Anything, order.ID, retentionNow).Return(errors.New("deadlock detected"))
+
+	// Act
+	processed, err := service.ApplyRetention(ctx)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Zero(t, processed)
+	repo.AssertNumberOfCalls(t, "ListExpired", 1)
+}
+
+func TestApplyRetention_Disabled(t *testing.T) {
+	// Arrange
+	repo := new(mocks.MockRetentionRepository)
+	service := NewRetentionService(repo, RetentionPolicy{Mode: RetentionPurge, BatchSize: 100})
+
+	// Act
+	processed, err := service.ApplyRetention(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Zero(t, processed)
+	repo.AssertNotCalled(t, "ListExpired", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// ===================== Policy Tests =====================
+
+func TestRetentionPolicy_IncludesLegalHolds(t *testing.T) {
+	// Arrange
+	repo := new(mocks.MockRetentionRepository)
+	service := newTestRetentionService(repo, RetentionAnonymize, 500)
+
+	ctx := context.Background()
+	repo.On("CountLegalHolds", ctx).Return(int64(3), nil)
+
+	// Act
+	policy, err := service.Policy(ctx)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, policy.Enabled)
+	assert.Equal(t, 365, policy.PeriodDays)
+	assert.Equal(t, "anonymize", policy.Mode)
+	assert.Equal(t, []entity.OrderStatus{entity.OrderStatusDelivered, entity.OrderStatusCancelled}, policy.Statuses)
+	assert.Equal(t, int64(3), policy.LegalHolds)
+}
+
+func TestSetLegalHold_NotFound(t *testing.T) {
+	// Arrange
+	repo := new(mocks.MockRetentionRepository)
+	service := newTestRetentionService(repo, RetentionAnonymize, 500)
+
+	ctx := context.Background()
+	orderID := uuid.New()
+	repo.On("SetLegalHold", ctx, orderID, true).Return(repository.ErrOrderNotFound)
+
+	// Act
+	err := service.SetLegalHold(ctx, orderID, true)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrOrderNotFound)
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"augustberries/pkg/recovery"
+
+	"github.com/robfig/cron/v3"
+)
+
+// OrderScheduler периодически активирует отложенные заказы
+type OrderScheduler struct {
+	cron    *cron.Cron
+	service *OrderService
+}
+
+// NewOrderScheduler создает планировщик; запуски не накладываются друг на друга
+func NewOrderScheduler(service *OrderService) *OrderScheduler {
+	c := cron.New(
+		cron.WithLogger(cron.VerbosePrintfLogger(log.Default())),
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)),
+	)
+
+	return &OrderScheduler{
+		cron:    c,
+		service: service,
+	}
+}
+
+// Start запускает планировщик и сразу активирует наступившие заказы
+func (s *OrderScheduler) Start(ctx context.Context, schedule string) error {
+	log.Printf("Starting scheduled orders activation with schedule: %s", schedule)
+
+	if _, err := s.cron.AddFunc(schedule, recovery.Wrap("orders-service", "order_scheduler", func() { s.run(ctx) })); err != nil {
+		return err
+	}
+
+	s.cron.Start()
+	s.run(ctx)
+
+	return nil
+}
+
+// Stop останавливает планировщик и ждет завершения текущего запуска
+func (s *OrderScheduler) Stop() {
+	log.Println("Stopping order scheduler...")
+	<-s.cron.Stop().Done()
+	log.Println("Order scheduler stopped")
+}
+
+func (s *OrderScheduler) run(ctx context.Context) {
+	if err := s.service.ActivateScheduledOrders(ctx); err != nil {
+		log.Printf("ERROR: Failed to activate scheduled orders: %v", err)
+	}
+}
@@ -5,12 +5,21 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"augustberries/orders-service/internal/app/orders/entity"
 	"augustberries/orders-service/internal/app/orders/infrastructure"
 	"augustberries/orders-service/internal/app/orders/repository"
+	"augustberries/pkg/id"
+	"augustberries/pkg/impersonation"
+	"augustberries/pkg/kafka"
 	"augustberries/pkg/metrics"
+	"augustberries/pkg/money"
+	"augustberries/pkg/quote"
+	"augustberries/pkg/tenant"
+	"augustberries/pkg/units"
 
 	"github.com/google/uuid"
 )
@@ -20,6 +29,38 @@ var (
 	ErrProductNotFound    = errors.New("product not found")
 	ErrInvalidOrderStatus = errors.New("invalid order status")
 	ErrUnauthorized       = errors.New("unauthorized access to order")
+	ErrTotalMismatch      = errors.New("order total mismatch")
+	ErrInvalidOrderTotal  = errors.New("invalid order total")
+
+	// ErrProductNotAvailable - товар не опубликован (draft) или снят с продажи (archived)
+	ErrProductNotAvailable = errors.New("product not available for ordering")
+	// ErrProductArchived - товар снят с продажи или удален из каталога; заказать его больше нельзя
+	ErrProductArchived = errors.New("product archived")
+	// ErrInvalidQuantity - количество не кратно шагу товара или дробное для штучного товара
+	ErrInvalidQuantity = errors.New("quantity is not a multiple of the product quantity step")
+	// ErrQuantityBelowMinimum - количество товара в заказе меньше min_qty товара
+	ErrQuantityBelowMinimum = errors.New("quantity is below the product minimum")
+	// ErrQuantityAboveMaximum - количество товара в заказе больше max_qty товара
+	ErrQuantityAboveMaximum = errors.New("quantity is above the product maximum")
+	// ErrCustomerLimitExceeded - вместе с прошлыми заказами покупатель превысит per_customer_limit товара
+	ErrCustomerLimitExceeded = errors.New("per-customer limit exceeded")
+	// ErrInvalidQuote - котировка Catalog Service не прошла проверку (подпись, состав)
+	ErrInvalidQuote = errors.New("invalid price quote")
+	// ErrQuoteExpired - срок действия котировки истек, клиенту нужно запросить новую
+	ErrQuoteExpired = errors.New("price quote expired")
+	// ErrGuestEmailMismatch - гостевая сессия оформлена на другой email, чем аккаунт
+	ErrGuestEmailMismatch = errors.New("guest email does not match account")
+	// ErrInvalidScheduledFor - время активации отложенного заказа уже наступило
+	ErrInvalidScheduledFor = errors.New("scheduled_for must be in the future")
+	// ErrOrderStatusConflict - статус заказа сменил параллельный запрос между чтением и обновлением
+	ErrOrderStatusConflict = errors.New("order status changed concurrently")
+)
+
+const (
+	// bulkStatusParallelism - сколько заказов одновременно обрабатывается при массовой смене статуса
+	bulkStatusParallelism = 8
+	// dueScheduledBatch - сколько отложенных заказов активируется за один запуск задачи
+	dueScheduledBatch = 500
 )
 
 type OrderService struct {
@@ -27,6 +68,19 @@ type OrderService struct {
 	orderItemRepo repository.OrderItemRepository
 	catalogClient infrastructure.CatalogServiceClient
 	kafkaProducer infrastructure.MessagePublisher
+	calculator    *money.OrderCalculator
+	quoteSigner   *quote.Signer
+	taxEngine     *TaxEngine     // nil - заказы без налога
+	numberer      *OrderNumberer // nil - заказы без человекочитаемого номера
+	// defaultCurrency - валюта заказа без валюты в запросе и предпочитаемой валюты пользователя
+	defaultCurrency string
+	statsCache      *statsCache // Кеш статистики заказов пользователей, nil - отключен
+	// deliveryEstimator - расчет окна ожидаемой доставки, nil - заказы без оценки
+	deliveryEstimator *DeliveryEstimator
+	// summaries - проекция списков заказов, nil - списки из таблицы orders
+	summaries repository.OrderSummaryRepository
+	// rates - курсы валют для предпросмотра заказа, nil - предпросмотр без конвертации
+	rates infrastructure.ExchangeRateClient
 }
 
 func NewOrderService(
@@ -34,84 +88,215 @@ func NewOrderService(
 	orderItemRepo repository.OrderItemRepository,
 	catalogClient infrastructure.CatalogServiceClient,
 	kafkaProducer infrastructure.MessagePublisher,
+	quoteSigner *quote.Signer,
+	taxEngine *TaxEngine,
+	numberer *OrderNumberer,
 ) *OrderService {
 	return &OrderService{
 		orderRepo:     orderRepo,
 		orderItemRepo: orderItemRepo,
 		catalogClient: catalogClient,
 		kafkaProducer: kafkaProducer,
+		calculator:    money.NewOrderCalculator(),
+		quoteSigner:   quoteSigner,
+		taxEngine:     taxEngine,
+		numberer:      numberer,
+
+		defaultCurrency: entity.DefaultCurrency,
 	}
 }
 
-func (s *OrderService) CreateOrder(ctx context.Context, userID uuid.UUID, req *entity.CreateOrderRequest, authToken string) (*entity.OrderWithItems, error) {
-	s.catalogClient.SetAuthToken(authToken)
+// SetDefaultCurrency задает валюту заказа по умолчанию; неподдерживаемая валюта игнорируется
+func (s *OrderService) SetDefaultCurrency(code string) {
+	if entity.IsSupportedCurrency(code) {
+		s.defaultCurrency = code
+	}
+}
 
-	productIDs := make([]uuid.UUID, len(req.Items))
-	for i, item := range req.Items {
-		productIDs[i] = item.ProductID
+// orderCurrency выбирает валюту заказа: из запроса, затем предпочитаемую валюту пользователя, затем валюту по умолчанию
+func (s *OrderService) orderCurrency(req *entity.CreateOrderRequest) string {
+	if req.Currency != "" {
+		return req.Currency
+	}
+	if entity.IsSupportedCurrency(req.PreferredCurrency) {
+		return req.PreferredCurrency
 	}
+	return s.defaultCurrency
+}
+
+// itemPricing - цена товара, его категория (для ставки налога) и снимок данных товара
+// PerCustomerLimit - сколько товара покупатель может заказать за все заказы, nil - без ограничения
+type itemPricing struct {
+	UnitPrice        money.Amount
+	CategoryID       uuid.UUID
+	Snapshot         entity.ProductSnapshot
+	PerCustomerLimit *units.Quantity
+}
 
-	products, err := s.catalogClient.GetProducts(ctx, productIDs)
+func (s *OrderService) CreateOrder(ctx context.Context, userID uuid.UUID, req *entity.CreateOrderRequest, authToken string) (*entity.OrderWithItems, error) {
+	order, orderItems, _, err := s.priceOrder(ctx, userID, req, authToken)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get products from catalog: %w", err)
+		return nil, err
 	}
 
-	for _, productID := range productIDs {
-		if _, exists := products[productID]; !exists {
-			return nil, ErrProductNotFound
+	// Второй этап: Catalog Service подтверждает товары и цены непосредственно перед сохранением.
+	// Если товар удалили или изменили цену после GetAvailability, заказ не создается.
+	// Подписанная клиентская котировка уже является таким подтверждением.
+	if req.QuoteToken == "" {
+		if err := s.confirmPrices(ctx, req.Items, orderItems); err != nil {
+			return nil, err
 		}
 	}
 
+	// Номер выдается последним шагом перед сохранением, чтобы отклоненные заказы не расходовали номера
+	if order.Number, err = s.numberer.Next(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := s.orderRepo.Create(ctx, order); err != nil {
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+
+	for _, item := range orderItems {
+		if err := s.orderItemRepo.Create(ctx, &item); err != nil {
+			return nil, fmt.Errorf("failed to create order item: %w", err)
+		}
+	}
+	refreshSummaries(ctx, s.summaries, order.ID)
+
+	// ORDER_CREATED отложенного заказа отправляется при активации
+	if order.Status == entity.OrderStatusScheduled {
+		metrics.OrdersByStatus.WithLabelValues(string(order.Status)).Inc()
+	} else {
+		s.orderCreated(ctx, order, len(orderItems))
+	}
+
+	return &entity.OrderWithItems{
+		Order: *order,
+		Items: orderItems,
+	}, nil
+}
+
+// priceOrder собирает заказ и его позиции по ценам каталога или котировки, считает налоги и итог, ничего не сохраняя
+// Общий шаг оформления и предпросмотра заказа: предпросмотр показывает ровно те суммы, которые получит заказ
+func (s *OrderService) priceOrder(ctx context.Context, userID uuid.UUID, req *entity.CreateOrderRequest, authToken string) (*entity.Order, []entity.OrderItem, money.Totals, error) {
+	if req.ScheduledFor != nil && !req.ScheduledFor.After(time.Now()) {
+		return nil, nil, money.Totals{}, ErrInvalidScheduledFor
+	}
+
+	s.catalogClient.SetAuthToken(authToken)
+
+	// Цены берутся либо из подписанной котировки (цены, которые клиент видел в корзине),
+	// либо запрашиваются в Catalog Service с последующим подтверждением перед сохранением
+	var prices map[uuid.UUID]itemPricing
+	var err error
+	if req.QuoteToken != "" {
+		prices, err = s.pricesFromQuote(ctx, req)
+	} else {
+		prices, err = s.fetchPrices(ctx, req.Items)
+	}
+	if err != nil {
+		return nil, nil, money.Totals{}, err
+	}
+	if err := s.checkCustomerLimits(ctx, userID, uuid.Nil, req.Items, prices); err != nil {
+		return nil, nil, money.Totals{}, err
+	}
+
+	// Суммы заказа хранятся с точностью его валюты
+	currencyCode := s.orderCurrency(req)
+	currency := money.LookupCurrency(currencyCode)
+	deliveryPrice := currency.Round(req.DeliveryPrice)
+
 	order := &entity.Order{
-		ID:            uuid.New(),
+		ID:            id.New(),
 		UserID:        userID,
-		DeliveryPrice: req.DeliveryPrice,
-		Currency:      req.Currency,
+		DeliveryPrice: deliveryPrice,
+		Currency:      currencyCode,
+		Country:       s.taxEngine.Country(req.Country),
 		Status:        entity.OrderStatusPending,
 		CreatedAt:     time.Now(),
+
+		SourceCurrency:      currencyCode,
+		SourceDeliveryPrice: deliveryPrice,
+	}
+	// Отложенный заказ ждет scheduled_for; цены и налоги фиксируются при оформлении
+	if req.ScheduledFor != nil {
+		order.Status = entity.OrderStatusScheduled
+		order.ScheduledFor = req.ScheduledFor
+	}
+	if entity.IsSupportedCurrency(req.PreferredCurrency) {
+		order.PreferredCurrency = req.PreferredCurrency
+	}
+	if req.GuestEmail != "" {
+		guestEmail := strings.ToLower(req.GuestEmail)
+		order.GuestEmail = &guestEmail
 	}
+	estimateDelivery(s.deliveryEstimator, order)
 
-	var totalPrice float64
 	orderItems := make([]entity.OrderItem, 0, len(req.Items))
+	categories := make(map[uuid.UUID]uuid.UUID, len(prices))
 
 	for _, itemReq := range req.Items {
-		product := products[itemReq.ProductID]
-		unitPrice := product.Price
+		pricing := prices[itemReq.ProductID]
+		categories[itemReq.ProductID] = pricing.CategoryID
 
-		item := entity.OrderItem{
-			ID:        uuid.New(),
+		orderItems = append(orderItems, entity.OrderItem{
+			ID:        id.New(),
 			OrderID:   order.ID,
 			ProductID: itemReq.ProductID,
 			Quantity:  itemReq.Quantity,
-			UnitPrice: unitPrice,
-		}
+			UnitPrice: pricing.UnitPrice,
+			Position:  len(orderItems),
+			Product:   pricing.Snapshot,
+		})
+	}
 
-		orderItems = append(orderItems, item)
-		totalPrice += unitPrice * float64(itemReq.Quantity)
+	// Налог считается по позициям в валюте заказа и входит в итог
+	if err := s.taxEngine.Apply(ctx, order.Country, order.Currency, orderItems, categories); err != nil {
+		return nil, nil, money.Totals{}, err
 	}
 
-	totalPrice += req.DeliveryPrice
-	order.TotalPrice = totalPrice
+	lines := make([]money.Line, len(orderItems))
+	for i, item := range orderItems {
+		lines[i] = money.Line{UnitPrice: item.UnitPrice, Quantity: item.Quantity, Tax: item.TaxAmount}
+	}
 
-	if err := s.orderRepo.Create(ctx, order); err != nil {
-		return nil, fmt.Errorf("failed to create order: %w", err)
+	// Итог всегда пересчитывается на сервере из цен каталога, суммы от клиента не принимаются
+	totals, err := s.calculator.Calculate(lines, deliveryPrice, 0)
+	if err != nil {
+		return nil, nil, money.Totals{}, fmt.Errorf("%w: %v", ErrInvalidOrderTotal, err)
 	}
 
-	for _, item := range orderItems {
-		if err := s.orderItemRepo.Create(ctx, &item); err != nil {
-			return nil, fmt.Errorf("failed to create order item: %w", err)
+	if req.ExpectedTotal != nil {
+		if err := s.calculator.Verify(*req.ExpectedTotal, totals); err != nil {
+			return nil, nil, money.Totals{}, fmt.Errorf("%w: %v", ErrTotalMismatch, err)
 		}
 	}
 
+	order.TotalPrice = totals.Total
+	order.TaxTotal = totals.Tax
+
+	return order, orderItems, totals, nil
+}
+
+// orderCreated отправляет ORDER_CREATED и учитывает заказ в метриках
+func (s *OrderService) orderCreated(ctx context.Context, order *entity.Order, itemsCount int) {
 	event := entity.OrderEvent{
-		EventType:  "ORDER_CREATED",
-		OrderID:    order.ID,
-		UserID:     order.UserID,
-		TotalPrice: order.TotalPrice,
-		Currency:   order.Currency,
-		Status:     order.Status,
-		ItemsCount: len(orderItems),
-		Timestamp:  time.Now(),
+		EventType:         "ORDER_CREATED",
+		TenantID:          order.TenantID,
+		OrderID:           order.ID,
+		OrderNumber:       order.Number,
+		UserID:            order.UserID,
+		TotalPrice:        order.TotalPrice,
+		TaxTotal:          order.TaxTotal,
+		Currency:          order.Currency,
+		PreferredCurrency: order.PreferredCurrency,
+		Status:            order.Status,
+		ItemsCount:        itemsCount,
+		Timestamp:         time.Now(),
+
+		EstimatedDeliveryFrom: order.EstimatedDeliveryFrom,
+		EstimatedDeliveryTo:   order.EstimatedDeliveryTo,
 	}
 
 	if err := s.publishOrderEvent(ctx, event); err != nil {
@@ -119,13 +304,197 @@ func (s *OrderService) CreateOrder(ctx context.Context, userID uuid.UUID, req *e
 	}
 
 	metrics.OrdersCreated.Inc()
-	metrics.OrdersTotal.Add(order.TotalPrice)
+	metrics.OrdersTotal.Add(order.TotalPrice.Float64())
 	metrics.OrdersByStatus.WithLabelValues(string(order.Status)).Inc()
+}
 
-	return &entity.OrderWithItems{
-		Order: *order,
-		Items: orderItems,
-	}, nil
+// ActivateScheduledOrders переводит наступившие отложенные заказы всех магазинов в pending и отправляет ORDER_CREATED
+func (s *OrderService) ActivateScheduledOrders(ctx context.Context) error {
+	due, err := s.orderRepo.ListDueScheduled(ctx, time.Now(), dueScheduledBatch)
+	if err != nil {
+		return fmt.Errorf("failed to get due scheduled orders: %w", err)
+	}
+
+	for i := range due {
+		order := &due[i]
+		// Задача обслуживает все магазины: запросы выполняются в магазине заказа
+		orderCtx := tenant.WithID(ctx, order.TenantID)
+
+		if err := s.orderRepo.ActivateScheduled(orderCtx, order.ID); err != nil {
+			// Заказ отменен или активирован другим экземпляром
+			if !errors.Is(err, repository.ErrOrderStatusChanged) {
+				fmt.Printf("failed to activate scheduled order %s: %v\n", order.ID, err)
+			}
+			continue
+		}
+		order.Status = entity.OrderStatusPending
+		refreshSummaries(orderCtx, s.summaries, order.ID)
+
+		items, _ := s.orderItemRepo.GetByOrderID(orderCtx, order.ID)
+		s.orderCreated(orderCtx, order, len(items))
+		orderStatusChanged(orderCtx, s.kafkaProducer, order, entity.OrderStatusScheduled, entity.StatusChange{
+			Actor:  entity.StatusActor{Type: entity.ActorSystem},
+			Reason: "scheduled time reached",
+		}, len(items))
+	}
+
+	return nil
+}
+
+// fetchPrices получает текущие цены и категории товаров из Catalog Service
+// Заодно проверяет, что товары опубликованы и остатка хватает на весь заказ
+func (s *OrderService) fetchPrices(ctx context.Context, items []entity.OrderItemRequest) (map[uuid.UUID]itemPricing, error) {
+	quantities, merged := aggregateItems(items)
+	productIDs := make([]uuid.UUID, len(merged))
+	for i, item := range merged {
+		productIDs[i] = item.ProductID
+	}
+
+	products, err := s.catalogClient.GetAvailability(ctx, productIDs)
+	if err != nil {
+		if errors.Is(err, infrastructure.ErrProductNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to get products from catalog: %w", err)
+	}
+
+	prices := make(map[uuid.UUID]itemPricing, len(productIDs))
+	for _, productID := range productIDs {
+		product, exists := products[productID]
+		if !exists {
+			return nil, ErrProductNotFound
+		}
+		// Заказывать можно только опубликованные товары
+		if product.Status == entity.ProductStatusArchived {
+			return nil, fmt.Errorf("%w: %s", ErrProductArchived, productID)
+		}
+		if product.Status != entity.ProductStatusPublished {
+			return nil, fmt.Errorf("%w: %s", ErrProductNotAvailable, productID)
+		}
+		if !product.AcceptsQuantity(quantities[productID]) {
+			return nil, fmt.Errorf("%w: %s %s", ErrInvalidQuantity, productID, quantities[productID])
+		}
+		if product.BelowMinimum(quantities[productID]) {
+			return nil, fmt.Errorf("%w: %s %s < %s", ErrQuantityBelowMinimum, productID, quantities[productID], *product.MinQty)
+		}
+		if product.AboveMaximum(quantities[productID]) {
+			return nil, fmt.Errorf("%w: %s %s > %s", ErrQuantityAboveMaximum, productID, quantities[productID], *product.MaxQty)
+		}
+		if !product.InStock(quantities[productID]) {
+			return nil, fmt.Errorf("%w: %s is out of stock", ErrProductNotAvailable, productID)
+		}
+		prices[productID] = itemPricing{
+			UnitPrice:        product.Price,
+			CategoryID:       product.CategoryID,
+			Snapshot:         product.Snapshot(),
+			PerCustomerLimit: product.PerCustomerLimit,
+		}
+	}
+
+	return prices, nil
+}
+
+// pricesFromQuote проверяет клиентскую котировку и возвращает зафиксированные в ней цены
+// Catalog Service не вызывается: подпись гарантирует, что цены выданы каталогом
+func (s *OrderService) pricesFromQuote(ctx context.Context, req *entity.CreateOrderRequest) (map[uuid.UUID]itemPricing, error) {
+	q, err := s.quoteSigner.Verify(req.QuoteToken)
+	if err != nil {
+		if errors.Is(err, quote.ErrQuoteExpired) {
+			return nil, ErrQuoteExpired
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidQuote, err)
+	}
+
+	// Котировка другого магазина не подтверждает цены этого магазина
+	// Котировки без магазина выданы до введения тенантов и относятся к магазину по умолчанию
+	quoteTenant := q.TenantID
+	if quoteTenant == "" {
+		quoteTenant = tenant.DefaultID
+	}
+	if quoteTenant != tenant.FromContext(ctx) {
+		return nil, fmt.Errorf("%w: issued for another tenant", ErrInvalidQuote)
+	}
+
+	requested, _ := aggregateItems(req.Items)
+	if err := q.Match(requested); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidQuote, err)
+	}
+
+	prices := make(map[uuid.UUID]itemPricing, len(q.Items))
+	for _, item := range q.Items {
+		prices[item.ProductID] = itemPricing{
+			UnitPrice:  item.UnitPrice,
+			CategoryID: item.CategoryID,
+			Snapshot:   entity.ProductSnapshot{Name: item.Name, CategoryName: item.CategoryName, Unit: item.Unit.OrDefault()},
+			// min_qty и max_qty каталог проверил при выдаче котировки, общий лимит зависит от прошлых заказов
+			PerCustomerLimit: item.PerCustomerLimit,
+		}
+	}
+
+	return prices, nil
+}
+
+// checkCustomerLimits проверяет per_customer_limit товаров: уже заказанное пользователем вместе с позициями items
+// не должно превышать лимит. Отмененные заказы и заказ excludeOrderID (редактируемый) не учитываются
+func (s *OrderService) checkCustomerLimits(ctx context.Context, userID, excludeOrderID uuid.UUID, items []entity.OrderItemRequest, prices map[uuid.UUID]itemPricing) error {
+	quantities, _ := aggregateItems(items)
+	var limited []uuid.UUID
+	for productID := range quantities {
+		if prices[productID].PerCustomerLimit != nil {
+			limited = append(limited, productID)
+		}
+	}
+	if len(limited) == 0 {
+		return nil
+	}
+
+	ordered, err := s.orderItemRepo.SumQuantitiesByUser(ctx, userID, limited, excludeOrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get ordered quantities: %w", err)
+	}
+	for _, productID := range limited {
+		limit := *prices[productID].PerCustomerLimit
+		if total := ordered[productID] + quantities[productID]; total > limit {
+			return fmt.Errorf("%w: %s %s > %s", ErrCustomerLimitExceeded, productID, total, limit)
+		}
+	}
+	return nil
+}
+
+// confirmPrices получает подписанную котировку и сверяет ее с ценами позиций заказа
+func (s *OrderService) confirmPrices(ctx context.Context, reqItems []entity.OrderItemRequest, orderItems []entity.OrderItem) error {
+	requested, validateItems := aggregateItems(reqItems)
+
+	token, err := s.catalogClient.ValidateProducts(ctx, validateItems)
+	if err != nil {
+		if errors.Is(err, infrastructure.ErrProductNotFound) {
+			return ErrProductNotFound
+		}
+		if errors.Is(err, infrastructure.ErrProductArchived) {
+			return ErrProductArchived
+		}
+		if errors.Is(err, infrastructure.ErrProductNotAvailable) {
+			return ErrProductNotAvailable
+		}
+		return fmt.Errorf("failed to validate products in catalog: %w", err)
+	}
+
+	q, err := s.quoteSigner.Verify(token)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidQuote, err)
+	}
+	if err := q.Match(requested); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidQuote, err)
+	}
+
+	for _, item := range orderItems {
+		price, _ := q.Price(item.ProductID)
+		if price != item.UnitPrice {
+			return fmt.Errorf("%w: price of product %s changed", ErrTotalMismatch, item.ProductID)
+		}
+	}
+
+	return nil
 }
 
 func (s *OrderService) GetOrder(ctx context.Context, orderID uuid.UUID, userID uuid.UUID) (*entity.OrderWithItems, error) {
@@ -144,8 +513,36 @@ func (s *OrderService) GetOrder(ctx context.Context, orderID uuid.UUID, userID u
 	return order, nil
 }
 
-func (s *OrderService) UpdateOrderStatus(ctx context.Context, orderID uuid.UUID, userID uuid.UUID, newStatus entity.OrderStatus) (*entity.Order, error) {
-	order, err := s.orderRepo.GetByID(ctx, orderID)
+// GetOrderByNumber получает заказ пользователя по человекочитаемому номеру
+func (s *OrderService) GetOrderByNumber(ctx context.Context, number string, userID uuid.UUID) (*entity.OrderWithItems, error) {
+	order, err := s.getByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	return order, nil
+}
+
+// getByNumber получает заказ с позициями по номеру
+func (s *OrderService) getByNumber(ctx context.Context, number string) (*entity.OrderWithItems, error) {
+	order, err := s.orderRepo.GetByNumberWithItems(ctx, NormalizeOrderNumber(number))
+	if err != nil {
+		if errors.Is(err, repository.ErrOrderNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	return order, nil
+}
+
+// LookupGuestOrder находит гостевой заказ по ID и email покупателя
+// Несовпадение email неотличимо от отсутствия заказа, чтобы по ответу нельзя было перебирать заказы
+func (s *OrderService) LookupGuestOrder(ctx context.Context, orderID uuid.UUID, email string) (*entity.OrderWithItems, error) {
+	order, err := s.orderRepo.GetWithItems(ctx, orderID)
 	if err != nil {
 		if errors.Is(err, repository.ErrOrderNotFound) {
 			return nil, ErrOrderNotFound
@@ -153,39 +550,218 @@ func (s *OrderService) UpdateOrderStatus(ctx context.Context, orderID uuid.UUID,
 		return nil, fmt.Errorf("failed to get order: %w", err)
 	}
 
+	return matchGuestOrder(order, email)
+}
+
+// LookupGuestOrderByNumber находит гостевой заказ по номеру и email покупателя
+func (s *OrderService) LookupGuestOrderByNumber(ctx context.Context, number, email string) (*entity.OrderWithItems, error) {
+	order, err := s.getByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return matchGuestOrder(order, email)
+}
+
+// matchGuestOrder проверяет, что заказ гостевой и оформлен на этот email
+func matchGuestOrder(order *entity.OrderWithItems, email string) (*entity.OrderWithItems, error) {
+	if order.GuestEmail == nil || !strings.EqualFold(*order.GuestEmail, strings.TrimSpace(email)) {
+		return nil, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// LinkGuestOrders переносит заказы гостевой сессии на аккаунт после регистрации гостя
+// Сессия должна быть оформлена на тот же email, что и аккаунт
+func (s *OrderService) LinkGuestOrders(ctx context.Context, guestID uuid.UUID, guestEmail string, userID uuid.UUID, userEmail string) (int64, error) {
+	if !strings.EqualFold(guestEmail, userEmail) {
+		return 0, ErrGuestEmailMismatch
+	}
+
+	linked, err := s.orderRepo.ReassignGuestOrders(ctx, guestID, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to link guest orders: %w", err)
+	}
+	if linked > 0 && s.summaries != nil {
+		if err := s.summaries.RefreshUser(ctx, userID); err != nil {
+			fmt.Printf("failed to refresh order summaries: %v\n", err)
+		}
+	}
+
+	return linked, nil
+}
+
+// GetInvoice формирует счет по заказу пользователя
+func (s *OrderService) GetInvoice(ctx context.Context, orderID uuid.UUID, userID uuid.UUID) (*entity.Invoice, error) {
+	order, err := s.GetOrder(ctx, orderID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return BuildInvoice(order), nil
+}
+
+// UpdateOrderStatus меняет статус заказа покупателя; reason попадает в ORDER_STATUS_CHANGED
+func (s *OrderService) UpdateOrderStatus(ctx context.Context, orderID uuid.UUID, userID uuid.UUID, newStatus entity.OrderStatus, reason string) (*entity.Order, error) {
+	order, err := s.getOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
 	if order.UserID != userID {
 		return nil, ErrUnauthorized
 	}
 
-	if !isValidStatusTransition(order.Status, newStatus) {
-		return nil, ErrInvalidOrderStatus
+	change := entity.StatusChange{
+		Actor:  entity.StatusActor{Type: entity.ActorCustomer, ID: &userID},
+		Reason: reason,
+	}
+	if err := s.changeStatus(ctx, order, newStatus, change); err != nil {
+		return nil, err
 	}
 
-	order.Status = newStatus
+	return order, nil
+}
 
-	if err := s.orderRepo.Update(ctx, order); err != nil {
-		return nil, fmt.Errorf("failed to update order: %w", err)
+// BulkUpdateOrderStatus переводит заказы магазина в статус newStatus (admin API склада)
+// Каждый заказ проверяется машиной состояний отдельно: ошибка одного заказа не останавливает остальные
+// Заказы обрабатываются параллельно, не больше bulkStatusParallelism одновременно
+// change - администратор и причина, одинаковые для всех заказов
+func (s *OrderService) BulkUpdateOrderStatus(ctx context.Context, orderIDs []uuid.UUID, newStatus entity.OrderStatus, change entity.StatusChange) *entity.BulkOrderStatusResponse {
+	ids := make([]uuid.UUID, 0, len(orderIDs))
+	seen := make(map[uuid.UUID]struct{}, len(orderIDs))
+	for _, id := range orderIDs {
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+		}
 	}
 
-	items, _ := s.orderItemRepo.GetByOrderID(ctx, orderID)
+	results := make([]entity.BulkOrderStatusResult, len(ids))
+	sem := make(chan struct{}, bulkStatusParallelism)
+	var wg sync.WaitGroup
+
+	for i, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, id uuid.UUID) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = s.bulkUpdateOne(ctx, id, newStatus, change)
+		}(i, id)
+	}
+	wg.Wait()
+
+	response := &entity.BulkOrderStatusResponse{Results: results}
+	for _, result := range results {
+		if result.Success {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+	return response
+}
+
+// bulkUpdateOne меняет статус одного заказа и описывает результат для отчета
+func (s *OrderService) bulkUpdateOne(ctx context.Context, orderID uuid.UUID, newStatus entity.OrderStatus, change entity.StatusChange) entity.BulkOrderStatusResult {
+	result := entity.BulkOrderStatusResult{OrderID: orderID}
+
+	order, err := s.getOrder(ctx, orderID)
+	if err != nil {
+		result.Error = bulkStatusError(err)
+		return result
+	}
+	result.PreviousStatus = order.Status
+
+	if err := s.changeStatus(ctx, order, newStatus, change); err != nil {
+		if errors.Is(err, ErrInvalidOrderStatus) {
+			result.Error = fmt.Sprintf("invalid status transition from %s to %s", result.PreviousStatus, newStatus)
+		} else {
+			result.Error = bulkStatusError(err)
+		}
+		return result
+	}
+
+	result.Success = true
+	result.Status = order.Status
+	return result
+}
+
+// bulkStatusError - текст ошибки для отчета; внутренние ошибки только логируются
+func bulkStatusError(err error) string {
+	if errors.Is(err, ErrOrderNotFound) {
+		return "order not found"
+	}
+	if errors.Is(err, ErrOrderStatusConflict) {
+		return "order status was changed by another request"
+	}
+	fmt.Printf("failed to update order status in bulk: %v\n", err)
+	return "failed to update order"
+}
+
+// getOrder получает заказ, приводя ошибку репозитория к ошибке сервиса
+func (s *OrderService) getOrder(ctx context.Context, orderID uuid.UUID) (*entity.Order, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrderNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	return order, nil
+}
+
+// changeStatus проверяет переход статуса, сохраняет заказ и отправляет ORDER_UPDATED, ORDER_STATUS_CHANGED
+// (и ORDER_DELIVERED после доставки)
+func (s *OrderService) changeStatus(ctx context.Context, order *entity.Order, newStatus entity.OrderStatus, change entity.StatusChange) error {
+	if !isValidStatusTransition(order.Status, newStatus) {
+		return ErrInvalidOrderStatus
+	}
+
+	// Меняется только статус и только если заказ все еще в прочитанном статусе:
+	// из двух параллельных переходов выполняется один, второй получает ErrOrderStatusConflict
+	if err := s.orderRepo.UpdateStatus(ctx, order.ID, order.Status, newStatus); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrOrderStatusChanged):
+			return ErrOrderStatusConflict
+		case errors.Is(err, repository.ErrOrderNotFound):
+			return ErrOrderNotFound
+		}
+		return fmt.Errorf("failed to update order: %w", err)
+	}
+	previous := order.Status
+	order.Status = newStatus
+	refreshSummaries(ctx, s.summaries, order.ID)
+
+	items, _ := s.orderItemRepo.GetByOrderID(ctx, order.ID)
 	event := entity.OrderEvent{
-		EventType:  "ORDER_UPDATED",
-		OrderID:    order.ID,
-		UserID:     order.UserID,
-		TotalPrice: order.TotalPrice,
-		Currency:   order.Currency,
-		Status:     order.Status,
-		ItemsCount: len(items),
-		Timestamp:  time.Now(),
+		EventType:   "ORDER_UPDATED",
+		TenantID:    order.TenantID,
+		OrderID:     order.ID,
+		OrderNumber: order.Number,
+		UserID:      order.UserID,
+		TotalPrice:  order.TotalPrice,
+		TaxTotal:    order.TaxTotal,
+		Currency:    order.Currency,
+		Status:      order.Status,
+		ItemsCount:  len(items),
+		Timestamp:   time.Now(),
+
+		EstimatedDeliveryFrom: order.EstimatedDeliveryFrom,
+		EstimatedDeliveryTo:   order.EstimatedDeliveryTo,
 	}
 
 	if err := s.publishOrderEvent(ctx, event); err != nil {
 		fmt.Printf("failed to publish order updated event: %v\n", err)
 	}
+	orderStatusChanged(ctx, s.kafkaProducer, order, previous, change, len(items))
+	if order.Status == entity.OrderStatusDelivered {
+		orderDelivered(ctx, s.kafkaProducer, order, items)
+	}
 
 	metrics.OrdersByStatus.WithLabelValues(string(order.Status)).Inc()
 
-	return order, nil
+	return nil
 }
 
 func (s *OrderService) DeleteOrder(ctx context.Context, orderID uuid.UUID, userID uuid.UUID) error {
@@ -208,34 +784,135 @@ func (s *OrderService) DeleteOrder(ctx context.Context, orderID uuid.UUID, userI
 	return nil
 }
 
-func (s *OrderService) GetUserOrders(ctx context.Context, userID uuid.UUID) ([]entity.Order, error) {
+// GetUserOrders возвращает строки списка заказов пользователя, новые первыми
+func (s *OrderService) GetUserOrders(ctx context.Context, userID uuid.UUID) ([]entity.OrderSummary, error) {
+	if s.summaries != nil {
+		summaries, err := s.summaries.List(ctx, entity.OrderFilter{UserID: &userID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user orders: %w", err)
+		}
+		return summaries, nil
+	}
+
 	orders, err := s.orderRepo.GetByUserID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user orders: %w", err)
 	}
-	return orders, nil
+	summaries := make([]entity.OrderSummary, len(orders))
+	for i, order := range orders {
+		summaries[i] = entity.NewOrderSummary(order, nil)
+	}
+	return summaries, nil
 }
 
 func (s *OrderService) publishOrderEvent(ctx context.Context, event entity.OrderEvent) error {
+	return publishOrderEvent(ctx, s.kafkaProducer, event)
+}
+
+// publishOrderEvent отправляет событие заказа в Kafka с ключом по ID заказа
+func publishOrderEvent(ctx context.Context, producer infrastructure.MessagePublisher, event entity.OrderEvent) error {
 	eventData, err := json.Marshal(event)
 	if err != nil {
 		return fmt.Errorf("failed to marshal order event: %w", err)
 	}
 
-	if err := s.kafkaProducer.PublishMessage(ctx, event.OrderID.String(), eventData); err != nil {
+	ctx = kafka.WithEvent(ctx, event.EventType, entity.EventSchemaVersion)
+	if err := producer.PublishMessage(ctx, event.OrderID.String(), eventData); err != nil {
 		return fmt.Errorf("failed to publish to kafka: %w", err)
 	}
 
 	return nil
 }
 
+// orderDelivered отправляет ORDER_DELIVERED с товарами заказа: по нему Reviews Service отмечает покупки
+func orderDelivered(ctx context.Context, producer infrastructure.MessagePublisher, order *entity.Order, items []entity.OrderItem) {
+	productIDs := make([]uuid.UUID, 0, len(items))
+	seen := make(map[uuid.UUID]bool, len(items))
+	for _, item := range items {
+		if !seen[item.ProductID] {
+			seen[item.ProductID] = true
+			productIDs = append(productIDs, item.ProductID)
+		}
+	}
+
+	event := entity.OrderEvent{
+		EventType:   "ORDER_DELIVERED",
+		TenantID:    order.TenantID,
+		OrderID:     order.ID,
+		OrderNumber: order.Number,
+		UserID:      order.UserID,
+		TotalPrice:  order.TotalPrice,
+		Currency:    order.Currency,
+		Status:      order.Status,
+		ItemsCount:  len(items),
+		ProductIDs:  productIDs,
+		Timestamp:   time.Now(),
+	}
+
+	if err := publishOrderEvent(ctx, producer, event); err != nil {
+		fmt.Printf("failed to publish order delivered event: %v\n", err)
+	}
+}
+
+// orderStatusChanged отправляет ORDER_STATUS_CHANGED: по нему Background Worker считает время заказа в статусах
+// Сотрудник поддержки, сменивший статус от имени покупателя, берется из контекста запроса
+func orderStatusChanged(ctx context.Context, producer infrastructure.MessagePublisher, order *entity.Order, previous entity.OrderStatus, change entity.StatusChange, itemsCount int) {
+	actor := change.Actor
+	if imp := impersonation.FromContext(ctx); imp != nil {
+		actor.ImpersonatorID = imp.ID
+	}
+	createdAt := order.CreatedAt
+
+	event := entity.OrderEvent{
+		EventType:      "ORDER_STATUS_CHANGED",
+		TenantID:       order.TenantID,
+		OrderID:        order.ID,
+		OrderNumber:    order.Number,
+		UserID:         order.UserID,
+		TotalPrice:     order.TotalPrice,
+		Currency:       order.Currency,
+		Status:         order.Status,
+		PreviousStatus: previous,
+		Actor:          &actor,
+		Reason:         change.Reason,
+		ItemsCount:     itemsCount,
+		OrderCreatedAt: &createdAt,
+		Timestamp:      time.Now(),
+	}
+
+	if err := publishOrderEvent(ctx, producer, event); err != nil {
+		fmt.Printf("failed to publish order status changed event: %v\n", err)
+	}
+}
+
+// aggregateItems объединяет позиции с одинаковым товаром: котировка выдается по товару
+// Возвращает количество по товару и объединенные позиции в исходном порядке
+func aggregateItems(items []entity.OrderItemRequest) (map[uuid.UUID]units.Quantity, []entity.OrderItemRequest) {
+	quantities := make(map[uuid.UUID]units.Quantity, len(items))
+	merged := make([]entity.OrderItemRequest, 0, len(items))
+	for _, item := range items {
+		if _, exists := quantities[item.ProductID]; !exists {
+			merged = append(merged, entity.OrderItemRequest{ProductID: item.ProductID})
+		}
+		quantities[item.ProductID] += item.Quantity
+	}
+	for i := range merged {
+		merged[i].Quantity = quantities[merged[i].ProductID]
+	}
+	return quantities, merged
+}
+
 func isValidStatusTransition(from, to entity.OrderStatus) bool {
 	validTransitions := map[entity.OrderStatus][]entity.OrderStatus{
+		entity.OrderStatusScheduled: {entity.OrderStatusCancelled}, // В pending переводит только ActivateScheduledOrders
 		entity.OrderStatusPending:   {entity.OrderStatusConfirmed, entity.OrderStatusCancelled},
 		entity.OrderStatusConfirmed: {entity.OrderStatusShipped, entity.OrderStatusCancelled},
 		entity.OrderStatusShipped:   {entity.OrderStatusDelivered},
 		entity.OrderStatusDelivered: {},
 		entity.OrderStatusCancelled: {},
+
+		// Частично отправленный заказ меняет статус только через отправления
+		entity.OrderStatusPartiallyShipped: {},
 	}
 
 	allowedStatuses, exists := validTransitions[from]
@@ -2,19 +2,46 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/infrastructure"
 	"augustberries/orders-service/internal/app/orders/repository"
 	"augustberries/orders-service/internal/app/orders/repository/mocks"
+	"augustberries/pkg/impersonation"
+	"augustberries/pkg/money"
+	"augustberries/pkg/quote"
+	"augustberries/pkg/tenant"
+	"augustberries/pkg/units"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
+var testQuoteSigner = quote.NewSigner("test-quote-secret")
+
+// expectQuote настраивает мок Catalog Service на выдачу подписанной котировки по текущим ценам товаров
+func expectQuote(catalogClient *mocks.MockCatalogServiceClient, req *entity.CreateOrderRequest, products map[uuid.UUID]*entity.ProductAvailability) {
+	quantities := make(map[uuid.UUID]units.Quantity)
+	for _, item := range req.Items {
+		quantities[item.ProductID] += item.Quantity
+	}
+
+	q := &quote.Quote{ID: uuid.New(), Currency: "USD", IssuedAt: time.Now(), ExpiresAt: time.Now().Add(time.Minute)}
+	for productID, quantity := range quantities {
+		q.Items = append(q.Items, quote.Item{ProductID: productID, Quantity: quantity, UnitPrice: products[productID].Price})
+	}
+
+	token, _ := testQuoteSigner.Sign(q)
+	catalogClient.On("ValidateProducts", mock.Anything, mock.Anything).Return(token, nil)
+}
+
 // ===================== CreateOrder Tests =====================
 
 func TestCreateOrder_Success(t *testing.T) {
@@ -24,7 +51,7 @@ func TestCreateOrder_Success(t *testing.T) {
 	catalogClient := new(mocks.MockCatalogServiceClient)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
 
-	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer)
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
 
 	ctx := context.Background()
 	userID := uuid.New()
@@ -33,28 +60,27 @@ func TestCreateOrder_Success(t *testing.T) {
 
 	req := &entity.CreateOrderRequest{
 		Items: []entity.OrderItemRequest{
-			{ProductID: productID, Quantity: 2},
+			{ProductID: productID, Quantity: units.Of(2)},
 		},
-		DeliveryPrice: 10.0,
+		DeliveryPrice: money.MustParse("10.00"),
 		Currency:      "USD",
 	}
 
 	// Mock Catalog Service
-	products := map[uuid.UUID]*entity.ProductWithCategory{
+	products := map[uuid.UUID]*entity.ProductAvailability{
 		productID: {
-			Product: entity.Product{
-				ID:    productID,
-				Name:  "Test Product",
-				Price: 50.0,
-			},
+			ID:     productID,
+			Price:  money.MustParse("50.00"),
+			Status: entity.ProductStatusPublished,
 		},
 	}
-	catalogClient.On("GetProducts", ctx, []uuid.UUID{productID}).Return(products, nil)
+	catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).Return(products, nil)
+	expectQuote(catalogClient, req, products)
 
 	// Mock repository
 	orderRepo.On("Create", ctx, mock.AnythingOfType("*entity.Order")).Return(nil)
 	orderItemRepo.On("Create", ctx, mock.AnythingOfType("*entity.OrderItem")).Return(nil)
-	kafkaProducer.On("PublishMessage", ctx, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 
 	// Act
 	result, err := service.CreateOrder(ctx, userID, req, authToken)
@@ -66,14 +92,66 @@ func TestCreateOrder_Success(t *testing.T) {
 	assert.Equal(t, entity.OrderStatusPending, result.Status)
 	assert.Equal(t, "USD", result.Currency)
 	// TotalPrice = (50.0 * 2) + 10.0 = 110.0
-	assert.Equal(t, 110.0, result.TotalPrice)
+	assert.Equal(t, money.MustParse("110.00"), result.TotalPrice)
 	assert.Len(t, result.Items, 1)
+	// ID заказа и позиций упорядочены по времени создания
+	assert.Equal(t, uuid.Version(7), result.ID.Version())
+	assert.Equal(t, uuid.Version(7), result.Items[0].ID.Version())
 
 	orderRepo.AssertExpectations(t)
 	orderItemRepo.AssertExpectations(t)
 	catalogClient.AssertExpectations(t)
 }
 
+func TestCreateOrder_StoresProductSnapshot(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	productID := uuid.New()
+
+	req := &entity.CreateOrderRequest{
+		Items:         []entity.OrderItemRequest{{ProductID: productID, Quantity: units.Of(1)}},
+		DeliveryPrice: money.MustParse("5.00"),
+		Currency:      "USD",
+	}
+
+	products := map[uuid.UUID]*entity.ProductAvailability{
+		productID: {
+			ID:           productID,
+			Name:         "Fresh Strawberry 500 g",
+			Description:  "Sweet garden strawberries",
+			Price:        money.MustParse("7.50"),
+			CategoryName: "Berries",
+			Status:       entity.ProductStatusPublished,
+		},
+	}
+	catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).Return(products, nil)
+	expectQuote(catalogClient, req, products)
+
+	var saved *entity.OrderItem
+	orderRepo.On("Create", ctx, mock.AnythingOfType("*entity.Order")).Return(nil)
+	orderItemRepo.On("Create", ctx, mock.AnythingOfType("*entity.OrderItem")).
+		Run(func(args mock.Arguments) { saved = args.Get(1).(*entity.OrderItem) }).
+		Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+
+	// Act
+	result, err := service.CreateOrder(ctx, uuid.New(), req, "test-token")
+
+	// Assert
+	require.NoError(t, err)
+	want := entity.ProductSnapshot{Name: "Fresh Strawberry 500 g", Description: "Sweet garden strawberries", CategoryName: "Berries", Unit: units.UnitPiece}
+	assert.Equal(t, want, result.Items[0].Product)
+	require.NotNil(t, saved)
+	assert.Equal(t, want, saved.Product)
+}
+
 func TestCreateOrder_ProductNotFound(t *testing.T) {
 	// Arrange
 	orderRepo := new(mocks.MockOrderRepository)
@@ -81,7 +159,7 @@ func TestCreateOrder_ProductNotFound(t *testing.T) {
 	catalogClient := new(mocks.MockCatalogServiceClient)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
 
-	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer)
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
 
 	ctx := context.Background()
 	userID := uuid.New()
@@ -90,170 +168,711 @@ func TestCreateOrder_ProductNotFound(t *testing.T) {
 
 	req := &entity.CreateOrderRequest{
 		Items: []entity.OrderItemRequest{
-			{ProductID: productID, Quantity: 1},
+			{ProductID: productID, Quantity: units.Of(1)},
+		},
+		DeliveryPrice: money.MustParse("5.00"),
+		Currency:      "USD",
+	}
+
+	// Mock: товар не найден в Catalog Service
+	catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).Return(map[uuid.UUID]*entity.ProductAvailability{}, nil)
+
+	// Act
+	result, err := service.CreateOrder(ctx, userID, req, authToken)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrProductNotFound)
+}
+
+func TestCreateOrder_CatalogServiceError(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	productID := uuid.New()
+
+	req := &entity.CreateOrderRequest{
+		Items: []entity.OrderItemRequest{
+			{ProductID: productID, Quantity: units.Of(1)},
+		},
+		DeliveryPrice: money.MustParse("5.00"),
+		Currency:      "USD",
+	}
+
+	catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).Return(nil, errors.New("catalog service unavailable"))
+
+	// Act
+	result, err := service.CreateOrder(ctx, userID, req, "token")
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "failed to get products from catalog")
+}
+
+func TestCreateOrder_OrderRepoError(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	productID := uuid.New()
+
+	req := &entity.CreateOrderRequest{
+		Items: []entity.OrderItemRequest{
+			{ProductID: productID, Quantity: units.Of(1)},
+		},
+		DeliveryPrice: money.MustParse("5.00"),
+		Currency:      "USD",
+	}
+
+	products := map[uuid.UUID]*entity.ProductAvailability{
+		productID: {ID: productID, Price: money.MustParse("100.00"), Status: entity.ProductStatusPublished},
+	}
+	catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).Return(products, nil)
+	expectQuote(catalogClient, req, products)
+	orderRepo.On("Create", ctx, mock.Anything).Return(errors.New("db error"))
+
+	// Act
+	result, err := service.CreateOrder(ctx, userID, req, "token")
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "failed to create order")
+}
+
+func TestCreateOrder_KafkaErrorIgnored(t *testing.T) {
+	// Ошибка Kafka не должна прерывать создание заказа
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	productID := uuid.New()
+
+	req := &entity.CreateOrderRequest{
+		Items: []entity.OrderItemRequest{
+			{ProductID: productID, Quantity: units.Of(1)},
+		},
+		DeliveryPrice: money.MustParse("5.00"),
+		Currency:      "RUB",
+	}
+
+	products := map[uuid.UUID]*entity.ProductAvailability{
+		productID: {ID: productID, Price: money.MustParse("1000.00"), Status: entity.ProductStatusPublished},
+	}
+	catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).Return(products, nil)
+	expectQuote(catalogClient, req, products)
+	orderRepo.On("Create", ctx, mock.Anything).Return(nil)
+	orderItemRepo.On("Create", ctx, mock.Anything).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("kafka error"))
+
+	// Act
+	result, err := service.CreateOrder(ctx, userID, req, "token")
+
+	// Assert
+	assert.NoError(t, err) // Заказ создан несмотря на ошибку Kafka
+	assert.NotNil(t, result)
+}
+
+func TestCreateOrder_MultipleItems(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	productID1 := uuid.New()
+	productID2 := uuid.New()
+
+	req := &entity.CreateOrderRequest{
+		Items: []entity.OrderItemRequest{
+			{ProductID: productID1, Quantity: units.Of(2)},
+			{ProductID: productID2, Quantity: units.Of(3)},
+		},
+		DeliveryPrice: money.MustParse("15.00"),
+		Currency:      "USD",
+	}
+
+	products := map[uuid.UUID]*entity.ProductAvailability{
+		productID1: {ID: productID1, Price: money.MustParse("100.00"), Status: entity.ProductStatusPublished},
+		productID2: {ID: productID2, Price: money.MustParse("50.00"), Status: entity.ProductStatusPublished},
+	}
+	catalogClient.On("GetAvailability", ctx, mock.Anything).Return(products, nil)
+	expectQuote(catalogClient, req, products)
+	orderRepo.On("Create", ctx, mock.Anything).Return(nil)
+	orderItemRepo.On("Create", ctx, mock.Anything).Return(nil).Times(2)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	// Act
+	result, err := service.CreateOrder(ctx, userID, req, "token")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, result.Items, 2)
+	// TotalPrice = (100*2) + (50*3) + 15 = 200 + 150 + 15 = 365
+	assert.Equal(t, money.MustParse("365.00"), result.TotalPrice)
+}
+
+func TestCreateOrder_ExpectedTotalMismatch(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	productID := uuid.New()
+	staleTotal := money.MustParse("100.00") // Клиент видел старую цену
+
+	req := &entity.CreateOrderRequest{
+		Items:         []entity.OrderItemRequest{{ProductID: productID, Quantity: units.Of(2)}},
+		DeliveryPrice: money.MustParse("10.00"),
+		Currency:      "USD",
+		ExpectedTotal: &staleTotal,
+	}
+
+	products := map[uuid.UUID]*entity.ProductAvailability{
+		productID: {ID: productID, Price: money.MustParse("50.00"), Status: entity.ProductStatusPublished},
+	}
+	catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).Return(products, nil)
+
+	// Act
+	result, err := service.CreateOrder(ctx, uuid.New(), req, "test-token")
+
+	// Assert
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrTotalMismatch))
+	assert.Nil(t, result)
+	orderRepo.AssertNotCalled(t, "Create")
+}
+
+func TestCreateOrder_UnpublishedProductRejected(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	productID := uuid.New()
+
+	req := &entity.CreateOrderRequest{
+		Items:         []entity.OrderItemRequest{{ProductID: productID, Quantity: units.Of(1)}},
+		DeliveryPrice: money.MustParse("10.00"),
+		Currency:      "USD",
+	}
+
+	products := map[uuid.UUID]*entity.ProductAvailability{
+		productID: {ID: productID, Price: money.MustParse("50.00"), Status: "draft"},
+	}
+	catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).Return(products, nil)
+
+	// Act
+	result, err := service.CreateOrder(ctx, uuid.New(), req, "test-token")
+
+	// Assert
+	assert.True(t, errors.Is(err, ErrProductNotAvailable))
+	assert.Nil(t, result)
+	orderRepo.AssertNotCalled(t, "Create")
+}
+
+func TestCreateOrder_ArchivedProductRejected(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	productID := uuid.New()
+
+	req := &entity.CreateOrderRequest{
+		Items:         []entity.OrderItemRequest{{ProductID: productID, Quantity: units.Of(1)}},
+		DeliveryPrice: money.MustParse("10.00"),
+		Currency:      "USD",
+	}
+
+	products := map[uuid.UUID]*entity.ProductAvailability{
+		productID: {ID: productID, Price: money.MustParse("50.00"), Status: entity.ProductStatusArchived},
+	}
+	catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).Return(products, nil)
+
+	// Act
+	result, err := service.CreateOrder(ctx, uuid.New(), req, "test-token")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrProductArchived)
+	assert.Nil(t, result)
+	orderRepo.AssertNotCalled(t, "Create")
+}
+
+func TestCreateOrder_OutOfStockRejected(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	productID := uuid.New()
+
+	// Две позиции одного товара суммируются: 2 + 2 больше остатка 3
+	req := &entity.CreateOrderRequest{
+		Items:         []entity.OrderItemRequest{{ProductID: productID, Quantity: units.Of(2)}, {ProductID: productID, Quantity: units.Of(2)}},
+		DeliveryPrice: money.MustParse("10.00"),
+		Currency:      "USD",
+	}
+
+	stock := 3
+	products := map[uuid.UUID]*entity.ProductAvailability{
+		productID: {ID: productID, Price: money.MustParse("50.00"), Status: entity.ProductStatusPublished, Stock: &stock},
+	}
+	catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).Return(products, nil)
+
+	// Act
+	result, err := service.CreateOrder(ctx, uuid.New(), req, "test-token")
+
+	// Assert
+	assert.True(t, errors.Is(err, ErrProductNotAvailable))
+	assert.Nil(t, result)
+	orderRepo.AssertNotCalled(t, "Create")
+}
+
+func TestCreateOrder_WeighedProduct(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	productID := uuid.New()
+
+	req := &entity.CreateOrderRequest{
+		Items:         []entity.OrderItemRequest{{ProductID: productID, Quantity: units.MustParse("1.5")}},
+		DeliveryPrice: money.MustParse("10.00"),
+		Currency:      "USD",
+	}
+
+	products := map[uuid.UUID]*entity.ProductAvailability{
+		productID: {
+			ID:           productID,
+			Name:         "Черешня",
+			Price:        money.MustParse("349.90"),
+			Status:       entity.ProductStatusPublished,
+			Unit:         units.UnitKilogram,
+			QuantityStep: units.MustParse("0.5"),
 		},
-		DeliveryPrice: 5.0,
+	}
+	catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).Return(products, nil)
+	expectQuote(catalogClient, req, products)
+	orderRepo.On("Create", ctx, mock.AnythingOfType("*entity.Order")).Return(nil)
+	orderItemRepo.On("Create", ctx, mock.AnythingOfType("*entity.OrderItem")).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+
+	// Act
+	result, err := service.CreateOrder(ctx, uuid.New(), req, "test-token")
+
+	// Assert: 1.5 кг x 349.90 = 524.85 + доставка 10.00
+	require.NoError(t, err)
+	assert.Equal(t, money.MustParse("534.85"), result.TotalPrice)
+	assert.Equal(t, units.MustParse("1.5"), result.Items[0].Quantity)
+	assert.Equal(t, units.UnitKilogram, result.Items[0].Product.Unit)
+}
+
+func TestCreateOrder_QuantityStepRejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		unit     units.Unit
+		step     units.Quantity
+		quantity units.Quantity
+	}{
+		{"not a multiple of step", units.UnitKilogram, units.MustParse("0.5"), units.MustParse("1.3")},
+		{"fractional pieces", units.UnitPiece, 0, units.MustParse("1.5")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderRepo := new(mocks.MockOrderRepository)
+			orderItemRepo := new(mocks.MockOrderItemRepository)
+			catalogClient := new(mocks.MockCatalogServiceClient)
+			kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+			service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+
+			ctx := context.Background()
+			productID := uuid.New()
+			req := &entity.CreateOrderRequest{
+				Items:         []entity.OrderItemRequest{{ProductID: productID, Quantity: tt.quantity}},
+				DeliveryPrice: money.MustParse("10.00"),
+				Currency:      "USD",
+			}
+			products := map[uuid.UUID]*entity.ProductAvailability{
+				productID: {ID: productID, Price: money.MustParse("100.00"), Status: entity.ProductStatusPublished, Unit: tt.unit, QuantityStep: tt.step},
+			}
+			catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).Return(products, nil)
+
+			result, err := service.CreateOrder(ctx, uuid.New(), req, "test-token")
+
+			assert.ErrorIs(t, err, ErrInvalidQuantity)
+			assert.Nil(t, result)
+			orderRepo.AssertNotCalled(t, "Create")
+		})
+	}
+}
+
+func TestCreateOrder_ProductHiddenByCatalog(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	productID := uuid.New()
+
+	req := &entity.CreateOrderRequest{
+		Items:         []entity.OrderItemRequest{{ProductID: productID, Quantity: units.Of(1)}},
+		DeliveryPrice: money.MustParse("10.00"),
+		Currency:      "USD",
+	}
+
+	// Catalog Service отвечает 404 на неопубликованный товар для обычного пользователя
+	catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).
+		Return(nil, fmt.Errorf("failed to get product %s: %w", productID, infrastructure.ErrProductNotFound))
+
+	// Act
+	result, err := service.CreateOrder(ctx, uuid.New(), req, "test-token")
+
+	// Assert
+	assert.True(t, errors.Is(err, ErrProductNotFound))
+	assert.Nil(t, result)
+}
+
+func TestCreateOrder_ExpectedTotalMatches(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	productID := uuid.New()
+	expectedTotal := money.MustParse("209.98") // (99.99 * 2) + 10.00
+
+	req := &entity.CreateOrderRequest{
+		Items:         []entity.OrderItemRequest{{ProductID: productID, Quantity: units.Of(2)}},
+		DeliveryPrice: money.MustParse("10.00"),
 		Currency:      "USD",
+		ExpectedTotal: &expectedTotal,
+	}
+
+	products := map[uuid.UUID]*entity.ProductAvailability{
+		productID: {ID: productID, Price: money.MustParse("99.99"), Status: entity.ProductStatusPublished},
+	}
+	catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).Return(products, nil)
+	expectQuote(catalogClient, req, products)
+	orderRepo.On("Create", ctx, mock.AnythingOfType("*entity.Order")).Return(nil)
+	orderItemRepo.On("Create", ctx, mock.AnythingOfType("*entity.OrderItem")).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+
+	// Act
+	result, err := service.CreateOrder(ctx, uuid.New(), req, "test-token")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expectedTotal, result.TotalPrice)
+}
+
+func TestCreateOrder_ProductDeletedBeforeConfirm(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	productID := uuid.New()
+
+	req := &entity.CreateOrderRequest{
+		Items:    []entity.OrderItemRequest{{ProductID: productID, Quantity: units.Of(1)}},
+		Currency: "USD",
+	}
+
+	products := map[uuid.UUID]*entity.ProductAvailability{
+		productID: {ID: productID, Price: money.MustParse("50.00"), Status: entity.ProductStatusPublished},
+	}
+	catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).Return(products, nil)
+	// Товар удалили между GetAvailability и подтверждением
+	catalogClient.On("ValidateProducts", ctx, req.Items).Return("", infrastructure.ErrProductNotFound)
+
+	// Act
+	result, err := service.CreateOrder(ctx, uuid.New(), req, "test-token")
+
+	// Assert
+	assert.True(t, errors.Is(err, ErrProductNotFound))
+	assert.Nil(t, result)
+	orderRepo.AssertNotCalled(t, "Create")
+}
+
+func TestCreateOrder_PriceChangedBeforeConfirm(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	productID := uuid.New()
+
+	req := &entity.CreateOrderRequest{
+		Items:    []entity.OrderItemRequest{{ProductID: productID, Quantity: units.Of(1)}},
+		Currency: "USD",
+	}
+
+	products := map[uuid.UUID]*entity.ProductAvailability{
+		productID: {ID: productID, Price: money.MustParse("50.00"), Status: entity.ProductStatusPublished},
+	}
+	catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).Return(products, nil)
+
+	// Котировка подтверждает уже новую цену
+	repriced := map[uuid.UUID]*entity.ProductAvailability{
+		productID: {ID: productID, Price: money.MustParse("55.00")},
+	}
+	expectQuote(catalogClient, req, repriced)
+
+	// Act
+	result, err := service.CreateOrder(ctx, uuid.New(), req, "test-token")
+
+	// Assert
+	assert.True(t, errors.Is(err, ErrTotalMismatch))
+	assert.Nil(t, result)
+	orderRepo.AssertNotCalled(t, "Create")
+}
+
+func TestCreateOrder_ForgedQuoteRejected(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, quote.NewSigner("another-secret"), nil, nil)
+
+	ctx := context.Background()
+	productID := uuid.New()
+
+	req := &entity.CreateOrderRequest{
+		Items:    []entity.OrderItemRequest{{ProductID: productID, Quantity: units.Of(1)}},
+		Currency: "USD",
 	}
 
-	// Mock: товар не найден в Catalog Service
-	catalogClient.On("GetProducts", ctx, []uuid.UUID{productID}).Return(map[uuid.UUID]*entity.ProductWithCategory{}, nil)
+	products := map[uuid.UUID]*entity.ProductAvailability{
+		productID: {ID: productID, Price: money.MustParse("50.00"), Status: entity.ProductStatusPublished},
+	}
+	catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).Return(products, nil)
+	expectQuote(catalogClient, req, products) // Подписано testQuoteSigner, а сервис ждет другой ключ
 
 	// Act
-	result, err := service.CreateOrder(ctx, userID, req, authToken)
+	result, err := service.CreateOrder(ctx, uuid.New(), req, "test-token")
 
 	// Assert
-	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidQuote))
 	assert.Nil(t, result)
-	assert.ErrorIs(t, err, ErrProductNotFound)
+	orderRepo.AssertNotCalled(t, "Create")
 }
 
-func TestCreateOrder_CatalogServiceError(t *testing.T) {
+func TestCreateOrder_WithQuoteToken_UsesQuotedPrices(t *testing.T) {
 	// Arrange
 	orderRepo := new(mocks.MockOrderRepository)
 	orderItemRepo := new(mocks.MockOrderItemRepository)
 	catalogClient := new(mocks.MockCatalogServiceClient)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
 
-	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer)
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
 
 	ctx := context.Background()
-	userID := uuid.New()
 	productID := uuid.New()
 
+	token, err := testQuoteSigner.Sign(&quote.Quote{
+		ID:        uuid.New(),
+		Items:     []quote.Item{{ProductID: productID, Quantity: units.Of(2), UnitPrice: money.MustParse("99.99"), Name: "Blueberry Box", CategoryName: "Berries"}},
+		Currency:  "USD",
+		IssuedAt:  time.Now(),
+		ExpiresAt: time.Now().Add(time.Minute),
+	})
+	assert.NoError(t, err)
+
 	req := &entity.CreateOrderRequest{
-		Items: []entity.OrderItemRequest{
-			{ProductID: productID, Quantity: 1},
-		},
-		DeliveryPrice: 5.0,
+		Items:         []entity.OrderItemRequest{{ProductID: productID, Quantity: units.Of(2)}},
+		DeliveryPrice: money.MustParse("10.00"),
 		Currency:      "USD",
+		QuoteToken:    token,
 	}
 
-	catalogClient.On("GetProducts", ctx, []uuid.UUID{productID}).Return(nil, errors.New("catalog service unavailable"))
+	orderRepo.On("Create", ctx, mock.AnythingOfType("*entity.Order")).Return(nil)
+	orderItemRepo.On("Create", ctx, mock.AnythingOfType("*entity.OrderItem")).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 
 	// Act
-	result, err := service.CreateOrder(ctx, userID, req, "token")
+	result, err := service.CreateOrder(ctx, uuid.New(), req, "test-token")
 
 	// Assert
-	assert.Error(t, err)
-	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "failed to get products from catalog")
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("209.98"), result.TotalPrice)
+	assert.Equal(t, entity.ProductSnapshot{Name: "Blueberry Box", CategoryName: "Berries", Unit: units.UnitPiece}, result.Items[0].Product)
+	// Цены не запрашиваются повторно: котировка уже подписана каталогом
+	catalogClient.AssertNotCalled(t, "GetAvailability", mock.Anything, mock.Anything)
+	catalogClient.AssertNotCalled(t, "ValidateProducts", mock.Anything, mock.Anything)
 }
 
-func TestCreateOrder_OrderRepoError(t *testing.T) {
+func TestCreateOrder_WithExpiredQuoteToken(t *testing.T) {
 	// Arrange
 	orderRepo := new(mocks.MockOrderRepository)
 	orderItemRepo := new(mocks.MockOrderItemRepository)
 	catalogClient := new(mocks.MockCatalogServiceClient)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
 
-	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer)
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
 
 	ctx := context.Background()
-	userID := uuid.New()
 	productID := uuid.New()
 
-	req := &entity.CreateOrderRequest{
-		Items: []entity.OrderItemRequest{
-			{ProductID: productID, Quantity: 1},
-		},
-		DeliveryPrice: 5.0,
-		Currency:      "USD",
-	}
+	token, err := testQuoteSigner.Sign(&quote.Quote{
+		ID:        uuid.New(),
+		Items:     []quote.Item{{ProductID: productID, Quantity: units.Of(1), UnitPrice: money.MustParse("50.00")}},
+		Currency:  "USD",
+		IssuedAt:  time.Now().Add(-time.Hour),
+		ExpiresAt: time.Now().Add(-time.Minute),
+	})
+	assert.NoError(t, err)
 
-	products := map[uuid.UUID]*entity.ProductWithCategory{
-		productID: {Product: entity.Product{ID: productID, Price: 100.0}},
+	req := &entity.CreateOrderRequest{
+		Items:      []entity.OrderItemRequest{{ProductID: productID, Quantity: units.Of(1)}},
+		Currency:   "USD",
+		QuoteToken: token,
 	}
-	catalogClient.On("GetProducts", ctx, []uuid.UUID{productID}).Return(products, nil)
-	orderRepo.On("Create", ctx, mock.Anything).Return(errors.New("db error"))
 
 	// Act
-	result, err := service.CreateOrder(ctx, userID, req, "token")
+	result, err := service.CreateOrder(ctx, uuid.New(), req, "test-token")
 
 	// Assert
-	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrQuoteExpired))
 	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "failed to create order")
+	orderRepo.AssertNotCalled(t, "Create")
 }
 
-func TestCreateOrder_KafkaErrorIgnored(t *testing.T) {
-	// Ошибка Kafka не должна прерывать создание заказа
+func TestCreateOrder_WithQuoteToken_QuantityMismatch(t *testing.T) {
 	// Arrange
 	orderRepo := new(mocks.MockOrderRepository)
 	orderItemRepo := new(mocks.MockOrderItemRepository)
 	catalogClient := new(mocks.MockCatalogServiceClient)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
 
-	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer)
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
 
 	ctx := context.Background()
-	userID := uuid.New()
 	productID := uuid.New()
 
-	req := &entity.CreateOrderRequest{
-		Items: []entity.OrderItemRequest{
-			{ProductID: productID, Quantity: 1},
-		},
-		DeliveryPrice: 5.0,
-		Currency:      "RUB",
-	}
+	token, err := testQuoteSigner.Sign(&quote.Quote{
+		ID:        uuid.New(),
+		Items:     []quote.Item{{ProductID: productID, Quantity: units.Of(1), UnitPrice: money.MustParse("50.00")}},
+		Currency:  "USD",
+		IssuedAt:  time.Now(),
+		ExpiresAt: time.Now().Add(time.Minute),
+	})
+	assert.NoError(t, err)
 
-	products := map[uuid.UUID]*entity.ProductWithCategory{
-		productID: {Product: entity.Product{ID: productID, Price: 1000.0}},
+	req := &entity.CreateOrderRequest{
+		Items:      []entity.OrderItemRequest{{ProductID: productID, Quantity: units.Of(5)}},
+		Currency:   "USD",
+		QuoteToken: token,
 	}
-	catalogClient.On("GetProducts", ctx, []uuid.UUID{productID}).Return(products, nil)
-	orderRepo.On("Create", ctx, mock.Anything).Return(nil)
-	orderItemRepo.On("Create", ctx, mock.Anything).Return(nil)
-	kafkaProducer.On("PublishMessage", ctx, mock.Anything, mock.Anything).Return(errors.New("kafka error"))
 
 	// Act
-	result, err := service.CreateOrder(ctx, userID, req, "token")
+	result, err := service.CreateOrder(ctx, uuid.New(), req, "test-token")
 
 	// Assert
-	assert.NoError(t, err) // Заказ создан несмотря на ошибку Kafka
-	assert.NotNil(t, result)
+	assert.True(t, errors.Is(err, ErrInvalidQuote))
+	assert.Nil(t, result)
 }
 
-func TestCreateOrder_MultipleItems(t *testing.T) {
+func TestCreateOrder_WithQuoteToken_OtherTenant(t *testing.T) {
 	// Arrange
 	orderRepo := new(mocks.MockOrderRepository)
 	orderItemRepo := new(mocks.MockOrderItemRepository)
 	catalogClient := new(mocks.MockCatalogServiceClient)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
 
-	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer)
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
 
-	ctx := context.Background()
-	userID := uuid.New()
-	productID1 := uuid.New()
-	productID2 := uuid.New()
+	ctx := tenant.WithID(context.Background(), "shop-b")
+	productID := uuid.New()
 
-	req := &entity.CreateOrderRequest{
-		Items: []entity.OrderItemRequest{
-			{ProductID: productID1, Quantity: 2},
-			{ProductID: productID2, Quantity: 3},
-		},
-		DeliveryPrice: 15.0,
-		Currency:      "USD",
-	}
+	token, err := testQuoteSigner.Sign(&quote.Quote{
+		ID:        uuid.New(),
+		TenantID:  "shop-a",
+		Items:     []quote.Item{{ProductID: productID, Quantity: units.Of(1), UnitPrice: money.MustParse("50.00")}},
+		Currency:  "USD",
+		IssuedAt:  time.Now(),
+		ExpiresAt: time.Now().Add(time.Minute),
+	})
+	assert.NoError(t, err)
 
-	products := map[uuid.UUID]*entity.ProductWithCategory{
-		productID1: {Product: entity.Product{ID: productID1, Price: 100.0}},
-		productID2: {Product: entity.Product{ID: productID2, Price: 50.0}},
+	req := &entity.CreateOrderRequest{
+		Items:      []entity.OrderItemRequest{{ProductID: productID, Quantity: units.Of(1)}},
+		Currency:   "USD",
+		QuoteToken: token,
 	}
-	catalogClient.On("GetProducts", ctx, mock.Anything).Return(products, nil)
-	orderRepo.On("Create", ctx, mock.Anything).Return(nil)
-	orderItemRepo.On("Create", ctx, mock.Anything).Return(nil).Times(2)
-	kafkaProducer.On("PublishMessage", ctx, mock.Anything, mock.Anything).Return(nil)
 
 	// Act
-	result, err := service.CreateOrder(ctx, userID, req, "token")
+	result, err := service.CreateOrder(ctx, uuid.New(), req, "test-token")
 
 	// Assert
-	assert.NoError(t, err)
-	assert.Len(t, result.Items, 2)
-	// TotalPrice = (100*2) + (50*3) + 15 = 200 + 150 + 15 = 365
-	assert.Equal(t, 365.0, result.TotalPrice)
+	assert.True(t, errors.Is(err, ErrInvalidQuote))
+	assert.Nil(t, result)
+	orderRepo.AssertNotCalled(t, "Create")
 }
 
 // ===================== GetOrder Tests =====================
@@ -265,7 +884,7 @@ func TestGetOrder_Success(t *testing.T) {
 	catalogClient := new(mocks.MockCatalogServiceClient)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
 
-	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer)
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
 
 	ctx := context.Background()
 	userID := uuid.New()
@@ -275,11 +894,11 @@ func TestGetOrder_Success(t *testing.T) {
 		Order: entity.Order{
 			ID:         orderID,
 			UserID:     userID,
-			TotalPrice: 100.0,
+			TotalPrice: money.MustParse("100.00"),
 			Status:     entity.OrderStatusPending,
 		},
 		Items: []entity.OrderItem{
-			{ID: uuid.New(), OrderID: orderID, ProductID: uuid.New(), Quantity: 1, UnitPrice: 100.0},
+			{ID: uuid.New(), OrderID: orderID, ProductID: uuid.New(), Quantity: units.Of(1), UnitPrice: money.MustParse("100.00")},
 		},
 	}
 
@@ -302,7 +921,7 @@ func TestGetOrder_NotFound(t *testing.T) {
 	catalogClient := new(mocks.MockCatalogServiceClient)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
 
-	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer)
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
 
 	ctx := context.Background()
 	userID := uuid.New()
@@ -327,7 +946,7 @@ func TestGetOrder_Unauthorized(t *testing.T) {
 	catalogClient := new(mocks.MockCatalogServiceClient)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
 
-	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer)
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
 
 	ctx := context.Background()
 	ownerID := uuid.New()
@@ -352,6 +971,284 @@ func TestGetOrder_Unauthorized(t *testing.T) {
 	assert.ErrorIs(t, err, ErrUnauthorized)
 }
 
+// ===================== Order Currency Tests =====================
+
+func TestCreateOrder_CurrencyResolution(t *testing.T) {
+	tests := []struct {
+		name              string
+		currency          string
+		preferredCurrency string
+		defaultCurrency   string
+		expected          string
+		expectedEvent     string
+	}{
+		{name: "request currency wins", currency: "USD", preferredCurrency: "EUR", expected: "USD", expectedEvent: "EUR"},
+		{name: "preferred currency", preferredCurrency: "EUR", expected: "EUR", expectedEvent: "EUR"},
+		{name: "unsupported preferred currency", preferredCurrency: "JPY", defaultCurrency: "USD", expected: "USD"},
+		{name: "configured default", defaultCurrency: "USD", expected: "USD"},
+		{name: "built-in default", expected: entity.DefaultCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			orderRepo := new(mocks.MockOrderRepository)
+			orderItemRepo := new(mocks.MockOrderItemRepository)
+			catalogClient := new(mocks.MockCatalogServiceClient)
+			kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+			service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+			service.SetDefaultCurrency(tt.defaultCurrency)
+
+			ctx := context.Background()
+			productID := uuid.New()
+			req := &entity.CreateOrderRequest{
+				Items:             []entity.OrderItemRequest{{ProductID: productID, Quantity: units.Of(1)}},
+				Currency:          tt.currency,
+				PreferredCurrency: tt.preferredCurrency,
+			}
+
+			products := map[uuid.UUID]*entity.ProductAvailability{
+				productID: {ID: productID, Price: money.MustParse("20.00"), Status: entity.ProductStatusPublished},
+			}
+			catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).Return(products, nil)
+			expectQuote(catalogClient, req, products)
+			orderRepo.On("Create", ctx, mock.AnythingOfType("*entity.Order")).Return(nil)
+			orderItemRepo.On("Create", ctx, mock.AnythingOfType("*entity.OrderItem")).Return(nil)
+			kafkaProducer.On("PublishMessage", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+
+			// Act
+			result, err := service.CreateOrder(ctx, uuid.New(), req, "test-token")
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Currency)
+
+			require.Len(t, kafkaProducer.Messages, 1)
+			var event entity.OrderEvent
+			require.NoError(t, json.Unmarshal(kafkaProducer.Messages[0], &event))
+			assert.Equal(t, tt.expectedEvent, event.PreferredCurrency)
+		})
+	}
+}
+
+// ===================== Guest Checkout Tests =====================
+
+func TestCreateOrder_GuestStoresEmail(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	guestID := uuid.New()
+	productID := uuid.New()
+
+	req := &entity.CreateOrderRequest{
+		Items:      []entity.OrderItemRequest{{ProductID: productID, Quantity: units.Of(1)}},
+		Currency:   "USD",
+		GuestEmail: "Guest@Example.com",
+	}
+
+	products := map[uuid.UUID]*entity.ProductAvailability{
+		productID: {ID: productID, Price: money.MustParse("20.00"), Status: entity.ProductStatusPublished},
+	}
+	catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).Return(products, nil)
+	expectQuote(catalogClient, req, products)
+	orderRepo.On("Create", ctx, mock.AnythingOfType("*entity.Order")).Return(nil)
+	orderItemRepo.On("Create", ctx, mock.AnythingOfType("*entity.OrderItem")).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+
+	// Act
+	result, err := service.CreateOrder(ctx, guestID, req, "guest-token")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, guestID, result.UserID)
+	require.NotNil(t, result.GuestEmail)
+	assert.Equal(t, "guest@example.com", *result.GuestEmail)
+}
+
+func TestLookupGuestOrder(t *testing.T) {
+	ctx := context.Background()
+	orderID := uuid.New()
+	guestEmail := "guest@example.com"
+
+	tests := []struct {
+		name       string
+		guestEmail *string
+		email      string
+		wantErr    error
+	}{
+		{"email matches case-insensitively", &guestEmail, " Guest@Example.COM ", nil},
+		{"other email", &guestEmail, "other@example.com", ErrOrderNotFound},
+		{"account order", nil, "guest@example.com", ErrOrderNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			orderRepo := new(mocks.MockOrderRepository)
+			service := NewOrderService(orderRepo, new(mocks.MockOrderItemRepository), new(mocks.MockCatalogServiceClient), &mocks.MockMessagePublisher{}, testQuoteSigner, nil, nil)
+
+			order := &entity.OrderWithItems{Order: entity.Order{ID: orderID, UserID: uuid.New(), GuestEmail: tt.guestEmail}}
+			orderRepo.On("GetWithItems", ctx, orderID).Return(order, nil)
+
+			// Act
+			result, err := service.LookupGuestOrder(ctx, orderID, tt.email)
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, result)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, orderID, result.ID)
+		})
+	}
+}
+
+func TestLinkGuestOrders_Success(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	service := NewOrderService(orderRepo, new(mocks.MockOrderItemRepository), new(mocks.MockCatalogServiceClient), &mocks.MockMessagePublisher{}, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	guestID, userID := uuid.New(), uuid.New()
+	orderRepo.On("ReassignGuestOrders", ctx, guestID, userID).Return(int64(2), nil)
+
+	// Act
+	linked, err := service.LinkGuestOrders(ctx, guestID, "guest@example.com", userID, "Guest@example.com")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), linked)
+	orderRepo.AssertExpectations(t)
+}
+
+func TestLinkGuestOrders_EmailMismatch(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	service := NewOrderService(orderRepo, new(mocks.MockOrderItemRepository), new(mocks.MockCatalogServiceClient), &mocks.MockMessagePublisher{}, testQuoteSigner, nil, nil)
+
+	// Act
+	_, err := service.LinkGuestOrders(context.Background(), uuid.New(), "guest@example.com", uuid.New(), "user@example.com")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrGuestEmailMismatch)
+	orderRepo.AssertNotCalled(t, "ReassignGuestOrders", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// ===================== Scheduled Orders Tests =====================
+
+func TestCreateOrder_ScheduledDefersOrderCreated(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	productID := uuid.New()
+	scheduledFor := time.Now().Add(24 * time.Hour)
+
+	req := &entity.CreateOrderRequest{
+		Items:             []entity.OrderItemRequest{{ProductID: productID, Quantity: units.Of(1)}},
+		Currency:          "USD",
+		ScheduledFor:      &scheduledFor,
+		PreferredCurrency: "EUR",
+	}
+
+	products := map[uuid.UUID]*entity.ProductAvailability{
+		productID: {ID: productID, Price: money.MustParse("20.00"), Status: entity.ProductStatusPublished},
+	}
+	catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).Return(products, nil)
+	expectQuote(catalogClient, req, products)
+	orderRepo.On("Create", ctx, mock.AnythingOfType("*entity.Order")).Return(nil)
+	orderItemRepo.On("Create", ctx, mock.AnythingOfType("*entity.OrderItem")).Return(nil)
+
+	// Act
+	result, err := service.CreateOrder(ctx, uuid.New(), req, "test-token")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, entity.OrderStatusScheduled, result.Status)
+	assert.Equal(t, &scheduledFor, result.ScheduledFor)
+	assert.Equal(t, "EUR", result.PreferredCurrency)
+	kafkaProducer.AssertNotCalled(t, "PublishMessage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateOrder_ScheduledInPastRejected(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	service := NewOrderService(orderRepo, new(mocks.MockOrderItemRepository), catalogClient, &mocks.MockMessagePublisher{}, testQuoteSigner, nil, nil)
+
+	scheduledFor := time.Now().Add(-time.Minute)
+	req := &entity.CreateOrderRequest{
+		Items:        []entity.OrderItemRequest{{ProductID: uuid.New(), Quantity: units.Of(1)}},
+		ScheduledFor: &scheduledFor,
+	}
+
+	// Act
+	result, err := service.CreateOrder(context.Background(), uuid.New(), req, "test-token")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidScheduledFor)
+	assert.Nil(t, result)
+	catalogClient.AssertNotCalled(t, "GetAvailability", mock.Anything, mock.Anything)
+	orderRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestActivateScheduledOrders(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, new(mocks.MockCatalogServiceClient), kafkaProducer, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	due := entity.Order{ID: uuid.New(), TenantID: "shop-a", Status: entity.OrderStatusScheduled, Currency: "USD", PreferredCurrency: "EUR"}
+	cancelled := entity.Order{ID: uuid.New(), TenantID: "shop-b", Status: entity.OrderStatusScheduled}
+
+	orderRepo.On("ListDueScheduled", ctx, mock.AnythingOfType("time.Time"), dueScheduledBatch).Return([]entity.Order{due, cancelled}, nil)
+	orderRepo.On("ActivateScheduled", tenant.WithID(ctx, "shop-a"), due.ID).Return(nil)
+	orderRepo.On("ActivateScheduled", tenant.WithID(ctx, "shop-b"), cancelled.ID).Return(repository.ErrOrderStatusChanged)
+	orderItemRepo.On("GetByOrderID", mock.Anything, due.ID).Return([]entity.OrderItem{{ID: uuid.New()}, {ID: uuid.New()}}, nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, due.ID.String(), mock.Anything).Return(nil)
+
+	// Act
+	err := service.ActivateScheduledOrders(ctx)
+
+	// Assert: события отправлены только по активированному заказу
+	require.NoError(t, err)
+	require.Len(t, kafkaProducer.Messages, 2)
+
+	var event entity.OrderEvent
+	require.NoError(t, json.Unmarshal(kafkaProducer.Messages[0], &event))
+	assert.Equal(t, "ORDER_CREATED", event.EventType)
+	assert.Equal(t, "shop-a", event.TenantID)
+	assert.Equal(t, entity.OrderStatusPending, event.Status)
+	assert.Equal(t, "EUR", event.PreferredCurrency)
+	assert.Equal(t, 2, event.ItemsCount)
+
+	var changed entity.OrderEvent
+	require.NoError(t, json.Unmarshal(kafkaProducer.Messages[1], &changed))
+	assert.Equal(t, "ORDER_STATUS_CHANGED", changed.EventType)
+	assert.Equal(t, entity.OrderStatusScheduled, changed.PreviousStatus)
+	assert.Equal(t, entity.OrderStatusPending, changed.Status)
+	require.NotNil(t, changed.Actor)
+	assert.Equal(t, entity.ActorSystem, changed.Actor.Type)
+	orderRepo.AssertExpectations(t)
+}
+
 // ===================== UpdateOrderStatus Tests =====================
 
 func TestUpdateOrderStatus_Success(t *testing.T) {
@@ -361,7 +1258,7 @@ func TestUpdateOrderStatus_Success(t *testing.T) {
 	catalogClient := new(mocks.MockCatalogServiceClient)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
 
-	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer)
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
 
 	ctx := context.Background()
 	userID := uuid.New()
@@ -371,17 +1268,17 @@ func TestUpdateOrderStatus_Success(t *testing.T) {
 		ID:         orderID,
 		UserID:     userID,
 		Status:     entity.OrderStatusPending,
-		TotalPrice: 100.0,
+		TotalPrice: money.MustParse("100.00"),
 		Currency:   "USD",
 	}
 
 	orderRepo.On("GetByID", ctx, orderID).Return(order, nil)
-	orderRepo.On("Update", ctx, mock.AnythingOfType("*entity.Order")).Return(nil)
+	orderRepo.On("UpdateStatus", ctx, orderID, entity.OrderStatusPending, entity.OrderStatusConfirmed).Return(nil)
 	orderItemRepo.On("GetByOrderID", ctx, orderID).Return([]entity.OrderItem{}, nil)
-	kafkaProducer.On("PublishMessage", ctx, mock.Anything, mock.Anything).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	// Act
-	result, err := service.UpdateOrderStatus(ctx, orderID, userID, entity.OrderStatusConfirmed)
+	result, err := service.UpdateOrderStatus(ctx, orderID, userID, entity.OrderStatusConfirmed, "")
 
 	// Assert
 	assert.NoError(t, err)
@@ -389,6 +1286,45 @@ func TestUpdateOrderStatus_Success(t *testing.T) {
 	assert.Equal(t, entity.OrderStatusConfirmed, result.Status)
 }
 
+func TestUpdateOrderStatus_PublishesStatusChanged(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, new(mocks.MockCatalogServiceClient), kafkaProducer, testQuoteSigner, nil, nil)
+
+	// Статус меняет сотрудник поддержки от имени покупателя
+	ctx := impersonation.WithImpersonator(context.Background(), &impersonation.Impersonator{ID: "support-1"})
+	userID := uuid.New()
+	createdAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	order := &entity.Order{ID: uuid.New(), TenantID: "shop-a", UserID: userID, Status: entity.OrderStatusPending, CreatedAt: createdAt}
+
+	orderRepo.On("GetByID", ctx, order.ID).Return(order, nil)
+	orderRepo.On("UpdateStatus", ctx, order.ID, entity.OrderStatusPending, entity.OrderStatusCancelled).Return(nil)
+	orderItemRepo.On("GetByOrderID", ctx, order.ID).Return([]entity.OrderItem{{ID: uuid.New()}}, nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, order.ID.String(), mock.Anything).Return(nil)
+
+	// Act
+	_, err := service.UpdateOrderStatus(ctx, order.ID, userID, entity.OrderStatusCancelled, "changed my mind")
+
+	// Assert: ORDER_UPDATED, затем ORDER_STATUS_CHANGED
+	require.NoError(t, err)
+	require.Len(t, kafkaProducer.Messages, 2)
+
+	var event entity.OrderEvent
+	require.NoError(t, json.Unmarshal(kafkaProducer.Messages[1], &event))
+	assert.Equal(t, "ORDER_STATUS_CHANGED", event.EventType)
+	assert.Equal(t, "shop-a", event.TenantID)
+	assert.Equal(t, entity.OrderStatusPending, event.PreviousStatus)
+	assert.Equal(t, entity.OrderStatusCancelled, event.Status)
+	assert.Equal(t, "changed my mind", event.Reason)
+	assert.Equal(t, &entity.StatusActor{Type: entity.ActorCustomer, ID: &userID, ImpersonatorID: "support-1"}, event.Actor)
+	require.NotNil(t, event.OrderCreatedAt)
+	assert.True(t, createdAt.Equal(*event.OrderCreatedAt))
+	assert.Equal(t, 1, event.ItemsCount)
+}
+
 func TestUpdateOrderStatus_NotFound(t *testing.T) {
 	// Arrange
 	orderRepo := new(mocks.MockOrderRepository)
@@ -396,7 +1332,7 @@ func TestUpdateOrderStatus_NotFound(t *testing.T) {
 	catalogClient := new(mocks.MockCatalogServiceClient)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
 
-	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer)
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
 
 	ctx := context.Background()
 	userID := uuid.New()
@@ -405,7 +1341,7 @@ func TestUpdateOrderStatus_NotFound(t *testing.T) {
 	orderRepo.On("GetByID", ctx, orderID).Return(nil, repository.ErrOrderNotFound)
 
 	// Act
-	result, err := service.UpdateOrderStatus(ctx, orderID, userID, entity.OrderStatusConfirmed)
+	result, err := service.UpdateOrderStatus(ctx, orderID, userID, entity.OrderStatusConfirmed, "")
 
 	// Assert
 	assert.Error(t, err)
@@ -413,6 +1349,34 @@ func TestUpdateOrderStatus_NotFound(t *testing.T) {
 	assert.ErrorIs(t, err, ErrOrderNotFound)
 }
 
+func TestUpdateOrderStatus_ConcurrentChange(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	orderID := uuid.New()
+	order := &entity.Order{ID: orderID, UserID: userID, Status: entity.OrderStatusPending}
+
+	// Заказ прочитан в pending, но параллельный запрос уже отменил его
+	orderRepo.On("GetByID", ctx, orderID).Return(order, nil)
+	orderRepo.On("UpdateStatus", ctx, orderID, entity.OrderStatusPending, entity.OrderStatusConfirmed).Return(repository.ErrOrderStatusChanged)
+
+	// Act
+	result, err := service.UpdateOrderStatus(ctx, orderID, userID, entity.OrderStatusConfirmed, "")
+
+	// Assert
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrOrderStatusConflict)
+	assert.Equal(t, entity.OrderStatusPending, order.Status)
+	kafkaProducer.AssertNotCalled(t, "PublishMessage", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestUpdateOrderStatus_Unauthorized(t *testing.T) {
 	// Arrange
 	orderRepo := new(mocks.MockOrderRepository)
@@ -420,7 +1384,7 @@ func TestUpdateOrderStatus_Unauthorized(t *testing.T) {
 	catalogClient := new(mocks.MockCatalogServiceClient)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
 
-	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer)
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
 
 	ctx := context.Background()
 	ownerID := uuid.New()
@@ -436,7 +1400,7 @@ func TestUpdateOrderStatus_Unauthorized(t *testing.T) {
 	orderRepo.On("GetByID", ctx, orderID).Return(order, nil)
 
 	// Act
-	result, err := service.UpdateOrderStatus(ctx, orderID, anotherUserID, entity.OrderStatusConfirmed)
+	result, err := service.UpdateOrderStatus(ctx, orderID, anotherUserID, entity.OrderStatusConfirmed, "")
 
 	// Assert
 	assert.Error(t, err)
@@ -452,7 +1416,7 @@ func TestUpdateOrderStatus_InvalidTransition(t *testing.T) {
 	catalogClient := new(mocks.MockCatalogServiceClient)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
 
-	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer)
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
 
 	ctx := context.Background()
 	userID := uuid.New()
@@ -467,7 +1431,7 @@ func TestUpdateOrderStatus_InvalidTransition(t *testing.T) {
 	orderRepo.On("GetByID", ctx, orderID).Return(order, nil)
 
 	// Act
-	result, err := service.UpdateOrderStatus(ctx, orderID, userID, entity.OrderStatusPending)
+	result, err := service.UpdateOrderStatus(ctx, orderID, userID, entity.OrderStatusPending, "")
 
 	// Assert
 	assert.Error(t, err)
@@ -475,6 +1439,51 @@ func TestUpdateOrderStatus_InvalidTransition(t *testing.T) {
 	assert.ErrorIs(t, err, ErrInvalidOrderStatus)
 }
 
+// ===================== BulkUpdateOrderStatus Tests =====================
+
+func TestBulkUpdateOrderStatus_ReportsPerOrderResults(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	pending := &entity.Order{ID: uuid.New(), UserID: uuid.New(), Status: entity.OrderStatusPending}
+	delivered := &entity.Order{ID: uuid.New(), UserID: uuid.New(), Status: entity.OrderStatusDelivered}
+	missingID := uuid.New()
+
+	orderRepo.On("GetByID", ctx, pending.ID).Return(pending, nil)
+	orderRepo.On("GetByID", ctx, delivered.ID).Return(delivered, nil)
+	orderRepo.On("GetByID", ctx, missingID).Return(nil, repository.ErrOrderNotFound)
+	orderRepo.On("UpdateStatus", ctx, pending.ID, entity.OrderStatusPending, entity.OrderStatusConfirmed).Return(nil)
+	orderItemRepo.On("GetByOrderID", ctx, pending.ID).Return([]entity.OrderItem{}, nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	// Act: повтор ID обрабатывается один раз
+	result := service.BulkUpdateOrderStatus(ctx, []uuid.UUID{pending.ID, delivered.ID, missingID, pending.ID}, entity.OrderStatusConfirmed, entity.StatusChange{})
+
+	// Assert
+	require.Len(t, result.Results, 3)
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Equal(t, 2, result.Failed)
+
+	assert.True(t, result.Results[0].Success)
+	assert.Equal(t, entity.OrderStatusPending, result.Results[0].PreviousStatus)
+	assert.Equal(t, entity.OrderStatusConfirmed, result.Results[0].Status)
+
+	assert.False(t, result.Results[1].Success)
+	assert.Equal(t, "invalid status transition from delivered to confirmed", result.Results[1].Error)
+	assert.Equal(t, entity.OrderStatusDelivered, delivered.Status)
+
+	assert.Equal(t, missingID, result.Results[2].OrderID)
+	assert.Equal(t, "order not found", result.Results[2].Error)
+
+	orderRepo.AssertNumberOfCalls(t, "UpdateStatus", 1)
+}
+
 // ===================== Status Transitions Tests =====================
 
 func TestStatusTransitions(t *testing.T) {
@@ -495,6 +1504,8 @@ func TestStatusTransitions(t *testing.T) {
 		{"shipped -> cancelled", entity.OrderStatusShipped, entity.OrderStatusCancelled, false},
 		{"delivered -> any", entity.OrderStatusDelivered, entity.OrderStatusPending, false},
 		{"cancelled -> any", entity.OrderStatusCancelled, entity.OrderStatusPending, false},
+		{"scheduled -> cancelled", entity.OrderStatusScheduled, entity.OrderStatusCancelled, true},
+		{"scheduled -> pending", entity.OrderStatusScheduled, entity.OrderStatusPending, false},
 	}
 
 	for _, tc := range testCases {
@@ -514,7 +1525,7 @@ func TestDeleteOrder_Success(t *testing.T) {
 	catalogClient := new(mocks.MockCatalogServiceClient)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
 
-	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer)
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
 
 	ctx := context.Background()
 	userID := uuid.New()
@@ -543,7 +1554,7 @@ func TestDeleteOrder_NotFound(t *testing.T) {
 	catalogClient := new(mocks.MockCatalogServiceClient)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
 
-	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer)
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
 
 	ctx := context.Background()
 	userID := uuid.New()
@@ -566,7 +1577,7 @@ func TestDeleteOrder_Unauthorized(t *testing.T) {
 	catalogClient := new(mocks.MockCatalogServiceClient)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
 
-	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer)
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
 
 	ctx := context.Background()
 	ownerID := uuid.New()
@@ -597,14 +1608,14 @@ func TestGetUserOrders_Success(t *testing.T) {
 	catalogClient := new(mocks.MockCatalogServiceClient)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
 
-	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer)
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
 
 	ctx := context.Background()
 	userID := uuid.New()
 
 	orders := []entity.Order{
-		{ID: uuid.New(), UserID: userID, TotalPrice: 100.0, Status: entity.OrderStatusPending, CreatedAt: time.Now()},
-		{ID: uuid.New(), UserID: userID, TotalPrice: 200.0, Status: entity.OrderStatusDelivered, CreatedAt: time.Now()},
+		{ID: uuid.New(), UserID: userID, TotalPrice: money.MustParse("100.00"), Status: entity.OrderStatusPending, CreatedAt: time.Now()},
+		{ID: uuid.New(), UserID: userID, TotalPrice: money.MustParse("200.00"), Status: entity.OrderStatusDelivered, CreatedAt: time.Now()},
 	}
 
 	orderRepo.On("GetByUserID", ctx, userID).Return(orders, nil)
@@ -624,7 +1635,7 @@ func TestGetUserOrders_Empty(t *testing.T) {
 	catalogClient := new(mocks.MockCatalogServiceClient)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
 
-	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer)
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
 
 	ctx := context.Background()
 	userID := uuid.New()
@@ -646,7 +1657,7 @@ func TestGetUserOrders_RepoError(t *testing.T) {
 	catalogClient := new(mocks.MockCatalogServiceClient)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
 
-	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer)
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
 
 	ctx := context.Background()
 	userID := uuid.New()
@@ -660,3 +1671,108 @@ func TestGetUserOrders_RepoError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, result)
 }
+
+// ===================== Quantity Limits Tests =====================
+
+func TestCreateOrder_QuantityLimits(t *testing.T) {
+	minQty, maxQty, limit := units.Of(2), units.Of(5), units.Of(8)
+
+	tests := []struct {
+		name     string
+		quantity units.Quantity
+		ordered  units.Quantity // Уже заказано пользователем в прошлых заказах
+		wantErr  error
+	}{
+		{name: "within limits", quantity: units.Of(3), ordered: units.Of(5)},
+		{name: "below minimum", quantity: units.Of(1), wantErr: ErrQuantityBelowMinimum},
+		{name: "above maximum", quantity: units.Of(6), wantErr: ErrQuantityAboveMaximum},
+		{name: "customer limit exceeded", quantity: units.Of(4), ordered: units.Of(5), wantErr: ErrCustomerLimitExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			orderRepo := new(mocks.MockOrderRepository)
+			orderItemRepo := new(mocks.MockOrderItemRepository)
+			catalogClient := new(mocks.MockCatalogServiceClient)
+			kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+
+			service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+
+			ctx := context.Background()
+			userID, productID := uuid.New(), uuid.New()
+			req := &entity.CreateOrderRequest{
+				// Позиции одного товара складываются: лимиты проверяются по общему количеству
+				Items: []entity.OrderItemRequest{
+					{ProductID: productID, Quantity: tt.quantity - units.Of(1)},
+					{ProductID: productID, Quantity: units.Of(1)},
+				},
+				DeliveryPrice: money.MustParse("5.00"),
+				Currency:      "USD",
+			}
+
+			products := map[uuid.UUID]*entity.ProductAvailability{
+				productID: {
+					ID: productID, Price: money.MustParse("10.00"), Status: entity.ProductStatusPublished,
+					MinQty: &minQty, MaxQty: &maxQty, PerCustomerLimit: &limit,
+				},
+			}
+			catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).Return(products, nil)
+			orderItemRepo.On("SumQuantitiesByUser", ctx, userID, []uuid.UUID{productID}, uuid.Nil).
+				Return(map[uuid.UUID]units.Quantity{productID: tt.ordered}, nil)
+			expectQuote(catalogClient, req, products)
+			orderRepo.On("Create", ctx, mock.AnythingOfType("*entity.Order")).Return(nil)
+			orderItemRepo.On("Create", ctx, mock.AnythingOfType("*entity.OrderItem")).Return(nil)
+			kafkaProducer.On("PublishMessage", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+
+			// Act
+			order, err := service.CreateOrder(ctx, userID, req, "test-token")
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, order)
+				orderRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, money.MustParse("35.00"), order.TotalPrice)
+		})
+	}
+}
+
+func TestCreateOrder_CustomerLimitFromQuote(t *testing.T) {
+	// Arrange
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	service := NewOrderService(new(mocks.MockOrderRepository), orderItemRepo, catalogClient,
+		&mocks.MockMessagePublisher{}, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	userID, productID := uuid.New(), uuid.New()
+	limit := units.Of(3)
+	token, err := testQuoteSigner.Sign(&quote.Quote{
+		ID:        uuid.New(),
+		Items:     []quote.Item{{ProductID: productID, Quantity: units.Of(2), UnitPrice: money.MustParse("10.00"), PerCustomerLimit: &limit}},
+		Currency:  "USD",
+		IssuedAt:  time.Now(),
+		ExpiresAt: time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+
+	req := &entity.CreateOrderRequest{
+		Items:         []entity.OrderItemRequest{{ProductID: productID, Quantity: units.Of(2)}},
+		DeliveryPrice: money.MustParse("5.00"),
+		Currency:      "USD",
+		QuoteToken:    token,
+	}
+	orderItemRepo.On("SumQuantitiesByUser", ctx, userID, []uuid.UUID{productID}, uuid.Nil).
+		Return(map[uuid.UUID]units.Quantity{productID: units.Of(2)}, nil)
+
+	// Act
+	order, err := service.CreateOrder(ctx, userID, req, "test-token")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrCustomerLimitExceeded)
+	assert.Nil(t, order)
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/repository"
+
+	"github.com/google/uuid"
+)
+
+// SetSummaries включает проекцию order_summaries: списки заказов читаются из нее, изменения заказов ее обновляют
+// nil - списки строятся по таблице orders без итогов по позициям
+func (s *OrderService) SetSummaries(repo repository.OrderSummaryRepository) {
+	s.summaries = repo
+}
+
+// SetSummaries включает обновление проекции order_summaries при смене статуса по отправлениям
+func (s *ShipmentService) SetSummaries(repo repository.OrderSummaryRepository) {
+	s.summaries = repo
+}
+
+// SetSummaries включает чтение admin списка заказов из проекции order_summaries
+func (s *NoteService) SetSummaries(repo repository.OrderSummaryRepository) {
+	s.summaries = repo
+}
+
+// refreshSummaries пересчитывает строки проекции после изменения заказов
+// Ошибка не отменяет изменение: строка отстанет до следующего изменения заказа или пересборки (cmd/rebuild-summaries)
+func refreshSummaries(ctx context.Context, repo repository.OrderSummaryRepository, orderIDs ...uuid.UUID) {
+	if repo == nil {
+		return
+	}
+	if err := repo.Refresh(ctx, orderIDs...); err != nil {
+		fmt.Printf("failed to refresh order summaries: %v\n", err)
+	}
+}
+
+// listSummaries возвращает строки списка заказов из проекции или, без нее, из таблицы orders
+func listSummaries(ctx context.Context, repo repository.OrderSummaryRepository, orderRepo repository.OrderRepository, filter entity.OrderFilter) ([]entity.OrderSummary, error) {
+	if repo != nil {
+		return repo.List(ctx, filter)
+	}
+
+	orders, err := orderRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]entity.OrderSummary, len(orders))
+	for i, order := range orders {
+		summaries[i] = entity.NewOrderSummary(order, nil)
+	}
+	return summaries, nil
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/repository/mocks"
+	"augustberries/pkg/money"
+	"augustberries/pkg/units"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// ===================== Order Summaries Tests =====================
+
+func TestCreateOrder_RefreshesSummary(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+	summaryRepo := new(mocks.MockOrderSummaryRepository)
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+	service.SetSummaries(summaryRepo)
+
+	ctx := context.Background()
+	first, second := uuid.New(), uuid.New()
+	req := &entity.CreateOrderRequest{
+		Items: []entity.OrderItemRequest{
+			{ProductID: first, Quantity: units.Of(1)},
+			{ProductID: second, Quantity: units.Of(3)},
+		},
+		DeliveryPrice: money.MustParse("10.00"),
+		Currency:      "USD",
+	}
+	products := map[uuid.UUID]*entity.ProductAvailability{
+		first:  {ID: first, Price: money.MustParse("50.00"), Status: entity.ProductStatusPublished},
+		second: {ID: second, Price: money.MustParse("5.00"), Status: entity.ProductStatusPublished},
+	}
+	catalogClient.On("GetAvailability", ctx, []uuid.UUID{first, second}).Return(products, nil)
+	expectQuote(catalogClient, req, products)
+
+	orderRepo.On("Create", ctx, mock.AnythingOfType("*entity.Order")).Return(nil)
+	orderItemRepo.On("Create", ctx, mock.AnythingOfType("*entity.OrderItem")).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	summaryRepo.On("Refresh", ctx, mock.Anything).Return(nil)
+
+	// Act
+	result, err := service.CreateOrder(ctx, uuid.New(), req, "test-token")
+
+	// Assert
+	require.NoError(t, err)
+	summaryRepo.AssertCalled(t, "Refresh", ctx, []uuid.UUID{result.ID})
+	// Порядок позиций сохраняется для first_item_name
+	assert.Equal(t, 0, result.Items[0].Position)
+	assert.Equal(t, 1, result.Items[1].Position)
+}
+
+func TestUpdateOrderStatus_SummaryRefreshFailureDoesNotFail(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+	summaryRepo := new(mocks.MockOrderSummaryRepository)
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, nil, nil)
+	service.SetSummaries(summaryRepo)
+
+	ctx := context.Background()
+	userID, orderID := uuid.New(), uuid.New()
+	order := &entity.Order{ID: orderID, UserID: userID, Status: entity.OrderStatusPending, Currency: "USD"}
+
+	orderRepo.On("GetByID", ctx, orderID).Return(order, nil)
+	orderRepo.On("UpdateStatus", ctx, orderID, entity.OrderStatusPending, entity.OrderStatusConfirmed).Return(nil)
+	orderItemRepo.On("GetByOrderID", ctx, orderID).Return([]entity.OrderItem{}, nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	summaryRepo.On("Refresh", ctx, []uuid.UUID{orderID}).Return(errors.New("connection reset"))
+
+	// Act
+	result, err := service.UpdateOrderStatus(ctx, orderID, userID, entity.OrderStatusConfirmed, "")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, entity.OrderStatusConfirmed, result.Status)
+	summaryRepo.AssertExpectations(t)
+}
+
+func TestGetUserOrders_FromSummaries(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	summaryRepo := new(mocks.MockOrderSummaryRepository)
+	service := NewOrderService(orderRepo, new(mocks.MockOrderItemRepository), new(mocks.MockCatalogServiceClient),
+		&mocks.MockMessagePublisher{}, testQuoteSigner, nil, nil)
+	service.SetSummaries(summaryRepo)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	summaries := []entity.OrderSummary{{OrderID: uuid.New(), UserID: userID, ItemsCount: 2, FirstItemName: "Клубника"}}
+	summaryRepo.On("List", ctx, entity.OrderFilter{UserID: &userID}).Return(summaries, nil)
+
+	// Act
+	result, err := service.GetUserOrders(ctx, userID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, summaries, result)
+	orderRepo.AssertNotCalled(t, "GetByUserID", mock.Anything, mock.Anything)
+}
+
+func TestLinkGuestOrders_RefreshesUserSummaries(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	summaryRepo := new(mocks.MockOrderSummaryRepository)
+	service := NewOrderService(orderRepo, new(mocks.MockOrderItemRepository), new(mocks.MockCatalogServiceClient),
+		&mocks.MockMessagePublisher{}, testQuoteSigner, nil, nil)
+	service.SetSummaries(summaryRepo)
+
+	ctx := context.Background()
+	guestID, userID := uuid.New(), uuid.New()
+	orderRepo.On("ReassignGuestOrders", ctx, guestID, userID).Return(int64(2), nil)
+	summaryRepo.On("RefreshUser", ctx, userID).Return(nil)
+
+	// Act
+	linked, err := service.LinkGuestOrders(ctx, guestID, "guest@example.com", userID, "guest@example.com")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), linked)
+	summaryRepo.AssertExpectations(t)
+}
+
+func TestListOrders_FromSummaries(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	noteRepo := new(mocks.MockOrderNoteRepository)
+	summaryRepo := new(mocks.MockOrderSummaryRepository)
+	service := NewNoteService(orderRepo, noteRepo)
+	service.SetSummaries(summaryRepo)
+
+	ctx := context.Background()
+	orderID := uuid.New()
+	filter := entity.OrderFilter{Status: entity.OrderStatusPending}
+	summaryRepo.On("List", ctx, filter).Return([]entity.OrderSummary{{OrderID: orderID, ItemsCount: 3}}, nil)
+	noteRepo.On("CountByOrderIDs", ctx, []uuid.UUID{orderID}).Return(map[uuid.UUID]int{orderID: 1}, nil)
+
+	// Act
+	result, err := service.ListOrders(ctx, filter)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, 3, result[0].ItemsCount)
+	assert.Equal(t, 1, result[0].NotesCount)
+	orderRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+}
+
+func TestNewOrderSummary_ItemTotals(t *testing.T) {
+	// Arrange
+	order := entity.Order{ID: uuid.New(), Number: "AB-1", Status: entity.OrderStatusPending, CreatedAt: time.Now()}
+	items := []entity.OrderItem{
+		{Quantity: units.Of(2), Position: 1, Product: entity.ProductSnapshot{Name: "Малина"}},
+		{Quantity: units.Of(1), Position: 0, Product: entity.ProductSnapshot{Name: "Клубника"}},
+	}
+
+	// Act
+	summary := entity.NewOrderSummary(order, items)
+
+	// Assert
+	assert.Equal(t, order.ID, summary.OrderID)
+	assert.Equal(t, "AB-1", summary.Number)
+	assert.Equal(t, 2, summary.ItemsCount)
+	assert.Equal(t, units.Of(3), summary.ItemsQuantity)
+	assert.Equal(t, "Клубника", summary.FirstItemName)
+}
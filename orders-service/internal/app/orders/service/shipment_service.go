@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/infrastructure"
+	"augustberries/orders-service/internal/app/orders/repository"
+	"augustberries/pkg/metrics"
+	"augustberries/pkg/units"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrShipmentNotFound = errors.New("shipment not found")
+	// ErrInvalidShipment - позиции отправления не соответствуют заказу или превышают неотправленный остаток
+	ErrInvalidShipment = errors.New("invalid shipment")
+	// ErrInvalidShipmentStatus - недопустимый переход статуса отправления
+	ErrInvalidShipmentStatus = errors.New("invalid shipment status")
+)
+
+// ShipmentService управляет отправлениями заказов
+// Статус заказа после первой отправки выводится из состояния его отправлений
+type ShipmentService struct {
+	orderRepo     repository.OrderRepository
+	shipmentRepo  repository.ShipmentRepository
+	kafkaProducer infrastructure.MessagePublisher
+	// deliveryEstimator - уточнение ожидаемой доставки по отправлениям, nil - окно не меняется
+	deliveryEstimator *DeliveryEstimator
+	// summaries - проекция списков заказов, nil - не обновляется
+	summaries repository.OrderSummaryRepository
+}
+
+func NewShipmentService(
+	orderRepo repository.OrderRepository,
+	shipmentRepo repository.ShipmentRepository,
+	kafkaProducer infrastructure.MessagePublisher,
+) *ShipmentService {
+	return &ShipmentService{
+		orderRepo:     orderRepo,
+		shipmentRepo:  shipmentRepo,
+		kafkaProducer: kafkaProducer,
+	}
+}
+
+// CreateShipment отправляет часть позиций заказа под одним трек-номером
+// Возвращает созданное отправление и заказ с пересчитанным статусом; actor - администратор, создавший отправление
+func (s *ShipmentService) CreateShipment(ctx context.Context, orderID uuid.UUID, req *entity.CreateShipmentRequest, actor entity.StatusActor) (*entity.Shipment, *entity.Order, error) {
+	order, err := s.getOrder(ctx, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Отправлять можно только подтвержденный заказ, у которого остались неотправленные позиции
+	if order.Status != entity.OrderStatusConfirmed && order.Status != entity.OrderStatusPartiallyShipped {
+		return nil, nil, ErrInvalidOrderStatus
+	}
+
+	shipments, err := s.shipmentRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get shipments: %w", err)
+	}
+
+	if err := validateShipmentItems(order.Items, shipments, req.Items); err != nil {
+		return nil, nil, err
+	}
+
+	shipment := &entity.Shipment{
+		ID:             uuid.New(),
+		OrderID:        orderID,
+		TrackingNumber: req.TrackingNumber,
+		Carrier:        req.Carrier,
+		Status:         entity.ShipmentStatusInTransit,
+		ShippedAt:      time.Now(),
+	}
+	for _, item := range req.Items {
+		shipment.Items = append(shipment.Items, entity.ShipmentItem{
+			ID:          uuid.New(),
+			ShipmentID:  shipment.ID,
+			OrderItemID: item.OrderItemID,
+			Quantity:    item.Quantity,
+		})
+	}
+
+	if err := s.shipmentRepo.Create(ctx, shipment); err != nil {
+		return nil, nil, fmt.Errorf("failed to create shipment: %w", err)
+	}
+
+	shipments = append(shipments, *shipment)
+	change := entity.StatusChange{Actor: actor, Reason: fmt.Sprintf("shipment %s created", shipment.TrackingNumber)}
+	if err := s.syncOrderStatus(ctx, order, shipments, change); err != nil {
+		return nil, nil, err
+	}
+
+	return shipment, &order.Order, nil
+}
+
+// UpdateShipmentStatus отмечает отправление доставленным и пересчитывает статус заказа
+func (s *ShipmentService) UpdateShipmentStatus(ctx context.Context, orderID, shipmentID uuid.UUID, status entity.ShipmentStatus, actor entity.StatusActor) (*entity.Shipment, *entity.Order, error) {
+	order, err := s.getOrder(ctx, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shipment, err := s.shipmentRepo.GetByID(ctx, shipmentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrShipmentNotFound) {
+			return nil, nil, ErrShipmentNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get shipment: %w", err)
+	}
+	if shipment.OrderID != orderID {
+		return nil, nil, ErrShipmentNotFound
+	}
+
+	// Единственный переход: in_transit -> delivered
+	if shipment.Status != entity.ShipmentStatusInTransit || status != entity.ShipmentStatusDelivered {
+		return nil, nil, ErrInvalidShipmentStatus
+	}
+
+	now := time.Now()
+	shipment.Status = status
+	shipment.DeliveredAt = &now
+
+	if err := s.shipmentRepo.UpdateStatus(ctx, shipment); err != nil {
+		return nil, nil, fmt.Errorf("failed to update shipment: %w", err)
+	}
+
+	shipments, err := s.shipmentRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get shipments: %w", err)
+	}
+	change := entity.StatusChange{Actor: actor, Reason: fmt.Sprintf("shipment %s delivered", shipment.TrackingNumber)}
+	if err := s.syncOrderStatus(ctx, order, shipments, change); err != nil {
+		return nil, nil, err
+	}
+
+	return shipment, &order.Order, nil
+}
+
+// GetShipments возвращает отправления заказа
+func (s *ShipmentService) GetShipments(ctx context.Context, orderID uuid.UUID) (*entity.ShipmentsResponse, error) {
+	order, err := s.getOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	shipments, err := s.shipmentRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shipments: %w", err)
+	}
+
+	return &entity.ShipmentsResponse{
+		OrderID:     order.ID,
+		OrderStatus: order.Status,
+		Shipments:   shipments,
+	}, nil
+}
+
+func (s *ShipmentService) getOrder(ctx context.Context, orderID uuid.UUID) (*entity.OrderWithItems, error) {
+	order, err := s.orderRepo.GetWithItems(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrderNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	return order, nil
+}
+
+// syncOrderStatus сохраняет выведенный из отправлений статус и окно доставки заказа
+// и публикует ORDER_UPDATED при их изменении (и ORDER_STATUS_CHANGED со сменой статуса)
+// Статус меняется только из прочитанного (UpdateStatus): параллельная отмена или второе отправление
+// не перезаписываются, проигравший запрос получает ErrOrderStatusConflict. Суммы и валюта заказа здесь не пишутся
+func (s *ShipmentService) syncOrderStatus(ctx context.Context, order *entity.OrderWithItems, shipments []entity.Shipment, change entity.StatusChange) error {
+	previous := order.Status
+	status := deriveOrderStatus(order.Items, shipments, previous)
+	statusChanged := status != previous
+	order.Status = status
+	etaChanged := s.deliveryEstimator.UpdateFromShipments(&order.Order, shipments)
+	if !statusChanged && !etaChanged {
+		return nil
+	}
+
+	if statusChanged {
+		if err := s.orderRepo.UpdateStatus(ctx, order.ID, previous, status); err != nil {
+			switch {
+			case errors.Is(err, repository.ErrOrderStatusChanged):
+				return ErrOrderStatusConflict
+			case errors.Is(err, repository.ErrOrderNotFound):
+				return ErrOrderNotFound
+			}
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+	}
+	if etaChanged {
+		if err := s.orderRepo.UpdateDeliveryEstimate(ctx, order.ID, order.EstimatedDeliveryFrom, order.EstimatedDeliveryTo); err != nil {
+			return fmt.Errorf("failed to update delivery estimate: %w", err)
+		}
+	}
+	refreshSummaries(ctx, s.summaries, order.ID)
+
+	event := entity.OrderEvent{
+		EventType:   "ORDER_UPDATED",
+		TenantID:    order.TenantID,
+		OrderID:     order.ID,
+		OrderNumber: order.Number,
+		UserID:      order.UserID,
+		TotalPrice:  order.TotalPrice,
+		Currency:    order.Currency,
+		Status:      order.Status,
+		ItemsCount:  len(order.Items),
+		Timestamp:   time.Now(),
+
+		EstimatedDeliveryFrom: order.EstimatedDeliveryFrom,
+		EstimatedDeliveryTo:   order.EstimatedDeliveryTo,
+	}
+
+	if err := publishOrderEvent(ctx, s.kafkaProducer, event); err != nil {
+		fmt.Printf("failed to publish order updated event: %v\n", err)
+	}
+	if statusChanged {
+		orderStatusChanged(ctx, s.kafkaProducer, &order.Order, previous, change, len(order.Items))
+	}
+	if statusChanged && order.Status == entity.OrderStatusDelivered {
+		orderDelivered(ctx, s.kafkaProducer, &order.Order, order.Items)
+	}
+
+	if statusChanged {
+		metrics.OrdersByStatus.WithLabelValues(string(order.Status)).Inc()
+	}
+
+	return nil
+}
+
+// validateShipmentItems проверяет, что позиции принадлежат заказу и не превышают неотправленный остаток
+func validateShipmentItems(orderItems []entity.OrderItem, shipments []entity.Shipment, items []entity.ShipmentItemRequest) error {
+	remaining := make(map[uuid.UUID]units.Quantity, len(orderItems))
+	for _, item := range orderItems {
+		remaining[item.ID] = item.Quantity
+	}
+	for _, shipment := range shipments {
+		for _, item := range shipment.Items {
+			remaining[item.OrderItemID] -= item.Quantity
+		}
+	}
+
+	requested := make(map[uuid.UUID]units.Quantity, len(items))
+	for _, item := range items {
+		if _, exists := remaining[item.OrderItemID]; !exists {
+			return fmt.Errorf("%w: item %s does not belong to order", ErrInvalidShipment, item.OrderItemID)
+		}
+		requested[item.OrderItemID] += item.Quantity
+		if requested[item.OrderItemID] > remaining[item.OrderItemID] {
+			return fmt.Errorf("%w: only %s of item %s left to ship", ErrInvalidShipment, remaining[item.OrderItemID], item.OrderItemID)
+		}
+	}
+
+	return nil
+}
+
+// deriveOrderStatus выводит статус заказа из отправлений:
+// отправлены не все единицы - partially_shipped, отправлено все - shipped, все отправления доставлены - delivered
+// Без отправлений статус заказа не меняется
+func deriveOrderStatus(orderItems []entity.OrderItem, shipments []entity.Shipment, current entity.OrderStatus) entity.OrderStatus {
+	if len(shipments) == 0 {
+		return current
+	}
+
+	var ordered units.Quantity
+	for _, item := range orderItems {
+		ordered += item.Quantity
+	}
+
+	var shipped units.Quantity
+	allDelivered := true
+	for _, shipment := range shipments {
+		for _, item := range shipment.Items {
+			shipped += item.Quantity
+		}
+		if shipment.Status != entity.ShipmentStatusDelivered {
+			allDelivered = false
+		}
+	}
+
+	switch {
+	case shipped < ordered:
+		return entity.OrderStatusPartiallyShipped
+	case allDelivered:
+		return entity.OrderStatusDelivered
+	default:
+		return entity.OrderStatusShipped
+	}
+}
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/repository"
+	"augustberries/orders-service/internal/app/orders/repository/mocks"
+	"augustberries/pkg/money"
+	"augustberries/pkg/units"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testAdmin - администратор, создающий отправления в тестах
+var testAdmin = entity.StatusActor{Type: entity.ActorAdmin}
+
+// newShippableOrder создает подтвержденный заказ из двух позиций: 3 и 1 единица
+func newShippableOrder() *entity.OrderWithItems {
+	orderID := uuid.New()
+	items := []entity.OrderItem{
+		{ID: uuid.New(), OrderID: orderID, ProductID: uuid.New(), Quantity: units.Of(3), UnitPrice: money.MustParse("10.00")},
+		{ID: uuid.New(), OrderID: orderID, ProductID: uuid.New(), Quantity: units.Of(1), UnitPrice: money.MustParse("25.00")},
+	}
+	return &entity.OrderWithItems{
+		Order: entity.Order{ID: orderID, UserID: uuid.New(), Status: entity.OrderStatusConfirmed, Currency: "USD"},
+		Items: items,
+	}
+}
+
+// ===================== CreateShipment Tests =====================
+
+func TestCreateShipment_Partial(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	shipmentRepo := new(mocks.MockShipmentRepository)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+	service := NewShipmentService(orderRepo, shipmentRepo, kafkaProducer)
+
+	ctx := context.Background()
+	order := newShippableOrder()
+	req := &entity.CreateShipmentRequest{
+		TrackingNumber: "RA123456789RU",
+		Carrier:        "Почта России",
+		Items:          []entity.ShipmentItemRequest{{OrderItemID: order.Items[0].ID, Quantity: units.Of(2)}},
+	}
+
+	orderRepo.On("GetWithItems", ctx, order.ID).Return(order, nil)
+	shipmentRepo.On("GetByOrderID", ctx, order.ID).Return([]entity.Shipment{}, nil)
+	shipmentRepo.On("Create", ctx, mock.AnythingOfType("*entity.Shipment")).Return(nil)
+	orderRepo.On("UpdateStatus", ctx, order.ID, order.Status, mock.Anything).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, order.ID.String(), mock.Anything).Return(nil)
+
+	// Act
+	shipment, updated, err := service.CreateShipment(ctx, order.ID, req, testAdmin)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ShipmentStatusInTransit, shipment.Status)
+	assert.Len(t, shipment.Items, 1)
+	assert.Equal(t, entity.OrderStatusPartiallyShipped, updated.Status)
+
+	// ORDER_UPDATED и ORDER_STATUS_CHANGED с администратором, создавшим отправление
+	require.Len(t, kafkaProducer.Messages, 2)
+	var event entity.OrderEvent
+	require.NoError(t, json.Unmarshal(kafkaProducer.Messages[1], &event))
+	assert.Equal(t, "ORDER_STATUS_CHANGED", event.EventType)
+	assert.Equal(t, entity.OrderStatusConfirmed, event.PreviousStatus)
+	assert.Equal(t, entity.OrderStatusPartiallyShipped, event.Status)
+	assert.Equal(t, &testAdmin, event.Actor)
+	assert.Equal(t, "shipment RA123456789RU created", event.Reason)
+	shipmentRepo.AssertExpectations(t)
+	orderRepo.AssertExpectations(t)
+}
+
+func TestCreateShipment_CompletesOrder(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	shipmentRepo := new(mocks.MockShipmentRepository)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+	service := NewShipmentService(orderRepo, shipmentRepo, kafkaProducer)
+
+	ctx := context.Background()
+	order := newShippableOrder()
+	order.Status = entity.OrderStatusPartiallyShipped
+	existing := []entity.Shipment{{
+		ID:     uuid.New(),
+		Status: entity.ShipmentStatusInTransit,
+		Items:  []entity.ShipmentItem{{OrderItemID: order.Items[0].ID, Quantity: units.Of(2)}},
+	}}
+	req := &entity.CreateShipmentRequest{
+		TrackingNumber: "CDEK-42",
+		Carrier:        "СДЭК",
+		Items: []entity.ShipmentItemRequest{
+			{OrderItemID: order.Items[0].ID, Quantity: units.Of(1)},
+			{OrderItemID: order.Items[1].ID, Quantity: units.Of(1)},
+		},
+	}
+
+	orderRepo.On("GetWithItems", ctx, order.ID).Return(order, nil)
+	shipmentRepo.On("GetByOrderID", ctx, order.ID).Return(existing, nil)
+	shipmentRepo.On("Create", ctx, mock.AnythingOfType("*entity.Shipment")).Return(nil)
+	orderRepo.On("UpdateStatus", ctx, order.ID, order.Status, mock.Anything).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, order.ID.String(), mock.Anything).Return(nil)
+
+	// Act
+	_, updated, err := service.CreateShipment(ctx, order.ID, req, testAdmin)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, entity.OrderStatusShipped, updated.Status)
+}
+
+func TestCreateShipment_StatusChangedConcurrently(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	shipmentRepo := new(mocks.MockShipmentRepository)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+	service := NewShipmentService(orderRepo, shipmentRepo, kafkaProducer)
+
+	ctx := context.Background()
+	order := newShippableOrder()
+	req := &entity.CreateShipmentRequest{
+		TrackingNumber: "RA123456789RU",
+		Carrier:        "Почта России",
+		Items:          []entity.ShipmentItemRequest{{OrderItemID: order.Items[0].ID, Quantity: units.Of(2)}},
+	}
+
+	orderRepo.On("GetWithItems", ctx, order.ID).Return(order, nil)
+	shipmentRepo.On("GetByOrderID", ctx, order.ID).Return([]entity.Shipment{}, nil)
+	shipmentRepo.On("Create", ctx, mock.AnythingOfType("*entity.Shipment")).Return(nil)
+	// Заказ отменили между чтением и сменой статуса
+	orderRepo.On("UpdateStatus", ctx, order.ID, entity.OrderStatusConfirmed, entity.OrderStatusPartiallyShipped).
+		Return(repository.ErrOrderStatusChanged)
+
+	// Act
+	_, _, err := service.CreateShipment(ctx, order.ID, req, testAdmin)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrOrderStatusConflict)
+	assert.Empty(t, kafkaProducer.Messages)
+}
+
+func TestCreateShipment_ExceedsRemaining(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	shipmentRepo := new(mocks.MockShipmentRepository)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+	service := NewShipmentService(orderRepo, shipmentRepo, kafkaProducer)
+
+	ctx := context.Background()
+	order := newShippableOrder()
+	existing := []entity.Shipment{{
+		ID:    uuid.New(),
+		Items: []entity.ShipmentItem{{OrderItemID: order.Items[0].ID, Quantity: units.Of(2)}},
+	}}
+	// Одна и та же позиция дважды: в сумме 2 единицы при остатке 1
+	req := &entity.CreateShipmentRequest{
+		TrackingNumber: "X1",
+		Carrier:        "DHL",
+		Items: []entity.ShipmentItemRequest{
+			{OrderItemID: order.Items[0].ID, Quantity: units.Of(1)},
+			{OrderItemID: order.Items[0].ID, Quantity: units.Of(1)},
+		},
+	}
+
+	orderRepo.On("GetWithItems", ctx, order.ID).Return(order, nil)
+	shipmentRepo.On("GetByOrderID", ctx, order.ID).Return(existing, nil)
+
+	// Act
+	_, _, err := service.CreateShipment(ctx, order.ID, req, testAdmin)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidShipment)
+	shipmentRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateShipment_ForeignItem(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	shipmentRepo := new(mocks.MockShipmentRepository)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+	service := NewShipmentService(orderRepo, shipmentRepo, kafkaProducer)
+
+	ctx := context.Background()
+	order := newShippableOrder()
+	req := &entity.CreateShipmentRequest{
+		TrackingNumber: "X1",
+		Carrier:        "DHL",
+		Items:          []entity.ShipmentItemRequest{{OrderItemID: uuid.New(), Quantity: units.Of(1)}},
+	}
+
+	orderRepo.On("GetWithItems", ctx, order.ID).Return(order, nil)
+	shipmentRepo.On("GetByOrderID", ctx, order.ID).Return([]entity.Shipment{}, nil)
+
+	// Act
+	_, _, err := service.CreateShipment(ctx, order.ID, req, testAdmin)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidShipment)
+}
+
+func TestCreateShipment_PendingOrder(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	shipmentRepo := new(mocks.MockShipmentRepository)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+	service := NewShipmentService(orderRepo, shipmentRepo, kafkaProducer)
+
+	ctx := context.Background()
+	order := newShippableOrder()
+	order.Status = entity.OrderStatusPending
+
+	orderRepo.On("GetWithItems", ctx, order.ID).Return(order, nil)
+
+	// Act
+	_, _, err := service.CreateShipment(ctx, order.ID, &entity.CreateShipmentRequest{}, testAdmin)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidOrderStatus)
+}
+
+// ===================== UpdateShipmentStatus Tests =====================
+
+func TestUpdateShipmentStatus_AllDelivered(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	shipmentRepo := new(mocks.MockShipmentRepository)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+	service := NewShipmentService(orderRepo, shipmentRepo, kafkaProducer)
+
+	ctx := context.Background()
+	order := newShippableOrder()
+	order.Status = entity.OrderStatusShipped
+	shipment := &entity.Shipment{
+		ID:      uuid.New(),
+		OrderID: order.ID,
+		Status:  entity.ShipmentStatusInTransit,
+		Items: []entity.ShipmentItem{
+			{OrderItemID: order.Items[0].ID, Quantity: units.Of(3)},
+			{OrderItemID: order.Items[1].ID, Quantity: units.Of(1)},
+		},
+	}
+	delivered := *shipment
+	delivered.Status = entity.ShipmentStatusDelivered
+
+	orderRepo.On("GetWithItems", ctx, order.ID).Return(order, nil)
+	shipmentRepo.On("GetByID", ctx, shipment.ID).Return(shipment, nil)
+	shipmentRepo.On("UpdateStatus", ctx, shipment).Return(nil)
+	shipmentRepo.On("GetByOrderID", ctx, order.ID).Return([]entity.Shipment{delivered}, nil)
+	orderRepo.On("UpdateStatus", ctx, order.ID, order.Status, mock.Anything).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, order.ID.String(), mock.Anything).Return(nil)
+
+	// Act
+	result, updated, err := service.UpdateShipmentStatus(ctx, order.ID, shipment.ID, entity.ShipmentStatusDelivered, testAdmin)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotNil(t, result.DeliveredAt)
+	assert.Equal(t, entity.OrderStatusDelivered, updated.Status)
+
+	// ORDER_UPDATED, ORDER_STATUS_CHANGED и ORDER_DELIVERED с товарами заказа для Reviews Service
+	require.Len(t, kafkaProducer.Messages, 3)
+	var event entity.OrderEvent
+	require.NoError(t, json.Unmarshal(kafkaProducer.Messages[2], &event))
+	assert.Equal(t, "ORDER_DELIVERED", event.EventType)
+	assert.ElementsMatch(t, []uuid.UUID{order.Items[0].ProductID, order.Items[1].ProductID}, event.ProductIDs)
+}
+
+func TestUpdateShipmentStatus_OtherOrder(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	shipmentRepo := new(mocks.MockShipmentRepository)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+	service := NewShipmentService(orderRepo, shipmentRepo, kafkaProducer)
+
+	ctx := context.Background()
+	order := newShippableOrder()
+	shipment := &entity.Shipment{ID: uuid.New(), OrderID: uuid.New(), Status: entity.ShipmentStatusInTransit}
+
+	orderRepo.On("GetWithItems", ctx, order.ID).Return(order, nil)
+	shipmentRepo.On("GetByID", ctx, shipment.ID).Return(shipment, nil)
+
+	// Act
+	_, _, err := service.UpdateShipmentStatus(ctx, order.ID, shipment.ID, entity.ShipmentStatusDelivered, testAdmin)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrShipmentNotFound)
+}
+
+// ===================== Derived Status Tests =====================
+
+func TestDeriveOrderStatus(t *testing.T) {
+	itemID := uuid.New()
+	items := []entity.OrderItem{{ID: itemID, Quantity: units.Of(2)}}
+	shipment := func(quantity int, status entity.ShipmentStatus) entity.Shipment {
+		return entity.Shipment{Status: status, Items: []entity.ShipmentItem{{OrderItemID: itemID, Quantity: units.Of(quantity)}}}
+	}
+
+	tests := []struct {
+		name      string
+		shipments []entity.Shipment
+		expected  entity.OrderStatus
+	}{
+		{"no shipments", nil, entity.OrderStatusConfirmed},
+		{"partial", []entity.Shipment{shipment(1, entity.ShipmentStatusDelivered)}, entity.OrderStatusPartiallyShipped},
+		{"all shipped", []entity.Shipment{shipment(1, entity.ShipmentStatusDelivered), shipment(1, entity.ShipmentStatusInTransit)}, entity.OrderStatusShipped},
+		{"all delivered", []entity.Shipment{shipment(1, entity.ShipmentStatusDelivered), shipment(1, entity.ShipmentStatusDelivered)}, entity.OrderStatusDelivered},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, deriveOrderStatus(items, tt.shipments, entity.OrderStatusConfirmed))
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+)
+
+// GetDashboardSummary возвращает сводку заказов магазина для внутренней панели:
+// число заказов по статусам и выручку с начала текущих суток (UTC) по валютам
+func (s *OrderService) GetDashboardSummary(ctx context.Context) (*entity.DashboardSummary, error) {
+	now := time.Now().UTC()
+	since := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	counts, err := s.orderRepo.CountByStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count orders: %w", err)
+	}
+
+	revenue, err := s.orderRepo.RevenueSince(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revenue: %w", err)
+	}
+	if revenue == nil {
+		revenue = []entity.CurrencyRevenue{}
+	}
+
+	return &entity.DashboardSummary{
+		OrdersByStatus: counts,
+		RevenueToday:   revenue,
+		Since:          since,
+		GeneratedAt:    now,
+	}, nil
+}
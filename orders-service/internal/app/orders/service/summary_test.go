@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/repository/mocks"
+	"augustberries/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// ===================== Dashboard Summary Tests =====================
+
+func TestGetDashboardSummary_Success(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	service := NewOrderService(orderRepo, nil, nil, nil, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	counts := map[entity.OrderStatus]int64{entity.OrderStatusPending: 3, entity.OrderStatusDelivered: 7}
+	revenue := []entity.CurrencyRevenue{{Currency: "RUB", Total: money.MustParse("1500.00"), Orders: 2}}
+
+	orderRepo.On("CountByStatus", ctx).Return(counts, nil)
+	// Выручка считается с начала текущих суток UTC
+	orderRepo.On("RevenueSince", ctx, mock.MatchedBy(func(since time.Time) bool {
+		return since.Location() == time.UTC && since.Equal(since.Truncate(24*time.Hour)) && time.Since(since) < 24*time.Hour
+	})).Return(revenue, nil)
+
+	// Act
+	summary, err := service.GetDashboardSummary(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, counts, summary.OrdersByStatus)
+	assert.Equal(t, revenue, summary.RevenueToday)
+	orderRepo.AssertExpectations(t)
+}
+
+func TestGetDashboardSummary_NoOrdersToday(t *testing.T) {
+	orderRepo := new(mocks.MockOrderRepository)
+	service := NewOrderService(orderRepo, nil, nil, nil, testQuoteSigner, nil, nil)
+
+	orderRepo.On("CountByStatus", mock.Anything).Return(map[entity.OrderStatus]int64{}, nil)
+	orderRepo.On("RevenueSince", mock.Anything, mock.Anything).Return(nil, nil)
+
+	summary, err := service.GetDashboardSummary(context.Background())
+
+	require.NoError(t, err)
+	assert.NotNil(t, summary.RevenueToday)
+}
+
+func TestGetDashboardSummary_RepositoryError(t *testing.T) {
+	orderRepo := new(mocks.MockOrderRepository)
+	service := NewOrderService(orderRepo, nil, nil, nil, testQuoteSigner, nil, nil)
+
+	orderRepo.On("CountByStatus", mock.Anything).Return(nil, errors.New("db error"))
+
+	_, err := service.GetDashboardSummary(context.Background())
+
+	assert.Error(t, err)
+}
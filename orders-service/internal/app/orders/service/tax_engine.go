@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/repository"
+	"augustberries/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// ErrTaxRateNotFound - ставка налога не найдена
+var ErrTaxRateNotFound = errors.New("tax rate not found")
+
+// TaxEngine рассчитывает налоги позиций заказа по ставкам магазина
+// Налог начисляется сверх цены: сумма позиции в валюте заказа умножается на ставку
+// и округляется до минорной единицы, итог заказа собирается из округленных налогов позиций
+type TaxEngine struct {
+	taxRepo        repository.TaxRateRepository
+	defaultCountry string
+}
+
+// NewTaxEngine создает движок налогов
+// defaultCountry применяется к заказам без страны доставки; пустая строка - такие заказы без налога
+func NewTaxEngine(taxRepo repository.TaxRateRepository, defaultCountry string) *TaxEngine {
+	return &TaxEngine{
+		taxRepo:        taxRepo,
+		defaultCountry: strings.ToUpper(defaultCountry),
+	}
+}
+
+// Country возвращает страну налогообложения заказа
+func (e *TaxEngine) Country(requested string) string {
+	if requested != "" {
+		return strings.ToUpper(requested)
+	}
+	if e == nil {
+		return ""
+	}
+	return e.defaultCountry
+}
+
+// Apply заполняет налог каждой позиции по ставкам страны
+// categories - категории товаров; ставка категории переопределяет общую ставку страны
+// Налог округляется до минорной единицы валюты заказа по ее правилу округления
+func (e *TaxEngine) Apply(ctx context.Context, country, currency string, items []entity.OrderItem, categories map[uuid.UUID]uuid.UUID) error {
+	if e == nil || country == "" {
+		return nil
+	}
+
+	rates, err := e.taxRepo.GetByCountry(ctx, country)
+	if err != nil {
+		return fmt.Errorf("failed to get tax rates: %w", err)
+	}
+	if len(rates) == 0 {
+		return nil
+	}
+
+	var general *entity.TaxRate
+	byCategory := make(map[uuid.UUID]*entity.TaxRate, len(rates))
+	for i := range rates {
+		if rates[i].CategoryID == nil {
+			general = &rates[i]
+		} else {
+			byCategory[*rates[i].CategoryID] = &rates[i]
+		}
+	}
+
+	cur := money.LookupCurrency(currency)
+	for i := range items {
+		rate := general
+		if categoryRate, ok := byCategory[categories[items[i].ProductID]]; ok {
+			rate = categoryRate
+		}
+		if rate == nil {
+			continue
+		}
+
+		items[i].TaxName = rate.Name
+		items[i].TaxRate = rate.Rate
+		items[i].TaxAmount = cur.MulRate(items[i].UnitPrice.MulQuantity(items[i].Quantity), rate.Rate)
+	}
+
+	return nil
+}
+
+// UpsertRate устанавливает ставку страны или категории
+func (e *TaxEngine) UpsertRate(ctx context.Context, req *entity.UpsertTaxRateRequest) (*entity.TaxRate, error) {
+	rate := &entity.TaxRate{
+		ID:         uuid.New(),
+		Country:    strings.ToUpper(req.Country),
+		CategoryID: req.CategoryID,
+		Name:       req.Name,
+		Rate:       req.Rate,
+	}
+
+	if err := e.taxRepo.Upsert(ctx, rate); err != nil {
+		return nil, fmt.Errorf("failed to save tax rate: %w", err)
+	}
+
+	return rate, nil
+}
+
+// GetRates возвращает все ставки магазина
+func (e *TaxEngine) GetRates(ctx context.Context) ([]entity.TaxRate, error) {
+	rates, err := e.taxRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tax rates: %w", err)
+	}
+	return rates, nil
+}
+
+// DeleteRate удаляет ставку; уже оформленные заказы сохраняют рассчитанный налог
+func (e *TaxEngine) DeleteRate(ctx context.Context, id uuid.UUID) error {
+	if err := e.taxRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrTaxRateNotFound) {
+			return ErrTaxRateNotFound
+		}
+		return fmt.Errorf("failed to delete tax rate: %w", err)
+	}
+	return nil
+}
+
+// TaxBreakdown группирует налоги позиций по ставкам
+// Позиции без ставки в разбивку не попадают
+func TaxBreakdown(items []entity.OrderItem) []entity.TaxLine {
+	type key struct {
+		name string
+		rate float64
+	}
+
+	lines := make([]entity.TaxLine, 0)
+	index := make(map[key]int)
+	for _, item := range items {
+		if item.TaxName == "" {
+			continue
+		}
+
+		k := key{name: item.TaxName, rate: item.TaxRate}
+		i, ok := index[k]
+		if !ok {
+			i = len(lines)
+			index[k] = i
+			lines = append(lines, entity.TaxLine{Name: item.TaxName, Rate: item.TaxRate})
+		}
+		lines[i].Taxable += item.UnitPrice.MulQuantity(item.Quantity)
+		lines[i].Amount += item.TaxAmount
+	}
+
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i].Rate != lines[j].Rate {
+			return lines[i].Rate > lines[j].Rate
+		}
+		return lines[i].Name < lines[j].Name
+	})
+
+	return lines
+}
+
+// BuildInvoice формирует счет по заказу
+func BuildInvoice(order *entity.OrderWithItems) *entity.Invoice {
+	invoice := &entity.Invoice{
+		OrderID:      order.ID,
+		OrderNumber:  order.Number,
+		IssuedAt:     order.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Currency:     order.Currency,
+		Country:      order.Country,
+		Lines:        make([]entity.InvoiceLine, len(order.Items)),
+		TaxBreakdown: TaxBreakdown(order.Items),
+		TaxTotal:     order.TaxTotal,
+		Delivery:     order.DeliveryPrice,
+		Total:        order.TotalPrice,
+	}
+
+	var subtotal money.Amount
+	for i, item := range order.Items {
+		net := item.UnitPrice.MulQuantity(item.Quantity)
+		subtotal += net
+		invoice.Lines[i] = entity.InvoiceLine{
+			ProductID:    item.ProductID,
+			ProductName:  item.Product.Name,
+			CategoryName: item.Product.CategoryName,
+			Quantity:     item.Quantity,
+			Unit:         item.Product.Unit,
+			UnitPrice:    item.UnitPrice,
+			Net:          net,
+			TaxName:      item.TaxName,
+			TaxRate:      item.TaxRate,
+			TaxAmount:    item.TaxAmount,
+			Gross:        net + item.TaxAmount,
+		}
+	}
+	invoice.Subtotal = subtotal
+
+	return invoice
+}
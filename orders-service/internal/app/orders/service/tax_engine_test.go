@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/repository/mocks"
+	"augustberries/pkg/money"
+	"augustberries/pkg/units"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// ===================== TaxEngine Tests =====================
+
+func TestTaxEngine_Apply_CategoryRateOverridesCountryRate(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	taxRepo := new(mocks.MockTaxRateRepository)
+	foodCategory := uuid.New()
+
+	taxRepo.On("GetByCountry", ctx, "DE").Return([]entity.TaxRate{
+		{Country: "DE", Name: "VAT", Rate: 0.19},
+		{Country: "DE", CategoryID: &foodCategory, Name: "VAT reduced", Rate: 0.07},
+	}, nil)
+
+	food, other := uuid.New(), uuid.New()
+	items := []entity.OrderItem{
+		{ProductID: food, Quantity: units.Of(3), UnitPrice: money.MustParse("3.33")},
+		{ProductID: other, Quantity: units.Of(1), UnitPrice: money.MustParse("10.05")},
+	}
+
+	engine := NewTaxEngine(taxRepo, "")
+
+	// Act
+	err := engine.Apply(ctx, "DE", "EUR", items, map[uuid.UUID]uuid.UUID{food: foodCategory, other: uuid.New()})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "VAT reduced", items[0].TaxName)
+	assert.Equal(t, money.MustParse("0.70"), items[0].TaxAmount) // 9.99 * 0.07 = 0.6993
+	assert.Equal(t, "VAT", items[1].TaxName)
+	assert.Equal(t, money.MustParse("1.91"), items[1].TaxAmount) // 10.05 * 0.19 = 1.9095
+}
+
+func TestTaxEngine_Apply_UsesCurrencyRounding(t *testing.T) {
+	// Arrange: XTS - ISO код для тестов, банковское округление
+	require.NoError(t, money.Currencies.Register(money.Currency{Code: "XTS", Decimals: 2, Rounding: money.RoundHalfEven}))
+
+	ctx := context.Background()
+	taxRepo := new(mocks.MockTaxRateRepository)
+	taxRepo.On("GetByCountry", ctx, "DE").Return([]entity.TaxRate{{Country: "DE", Name: "VAT", Rate: 0.5}}, nil)
+	items := []entity.OrderItem{{ProductID: uuid.New(), Quantity: units.Of(1), UnitPrice: money.MustParse("0.25")}}
+
+	engine := NewTaxEngine(taxRepo, "")
+
+	// Act
+	err := engine.Apply(ctx, "DE", "XTS", items, nil)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, money.MustParse("0.12"), items[0].TaxAmount) // 0.125 -> 0.12, половина к четному
+}
+
+func TestTaxEngine_Apply_NoCountry(t *testing.T) {
+	// Arrange
+	taxRepo := new(mocks.MockTaxRateRepository)
+	items := []entity.OrderItem{{ProductID: uuid.New(), Quantity: units.Of(1), UnitPrice: money.MustParse("10.00")}}
+
+	engine := NewTaxEngine(taxRepo, "")
+
+	// Act
+	err := engine.Apply(context.Background(), engine.Country(""), "RUB", items, nil)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, items[0].TaxAmount.IsZero())
+	taxRepo.AssertNotCalled(t, "GetByCountry", mock.Anything, mock.Anything)
+}
+
+func TestTaxEngine_Country(t *testing.T) {
+	engine := NewTaxEngine(new(mocks.MockTaxRateRepository), "ru")
+
+	assert.Equal(t, "RU", engine.Country(""))
+	assert.Equal(t, "DE", engine.Country("de"))
+
+	var disabled *TaxEngine
+	assert.Equal(t, "", disabled.Country(""))
+}
+
+func TestTaxBreakdown_GroupsByRate(t *testing.T) {
+	// Arrange
+	items := []entity.OrderItem{
+		{Quantity: units.Of(2), UnitPrice: money.MustParse("10.00"), TaxName: "VAT", TaxRate: 0.2, TaxAmount: money.MustParse("4.00")},
+		{Quantity: units.Of(1), UnitPrice: money.MustParse("5.00"), TaxName: "VAT reduced", TaxRate: 0.1, TaxAmount: money.MustParse("0.50")},
+		{Quantity: units.Of(1), UnitPrice: money.MustParse("30.00"), TaxName: "VAT", TaxRate: 0.2, TaxAmount: money.MustParse("6.00")},
+		{Quantity: units.Of(1), UnitPrice: money.MustParse("1.00")},
+	}
+
+	// Act
+	lines := TaxBreakdown(items)
+
+	// Assert
+	require.Len(t, lines, 2)
+	assert.Equal(t, entity.TaxLine{Name: "VAT", Rate: 0.2, Taxable: money.MustParse("50.00"), Amount: money.MustParse("10.00")}, lines[0])
+	assert.Equal(t, entity.TaxLine{Name: "VAT reduced", Rate: 0.1, Taxable: money.MustParse("5.00"), Amount: money.MustParse("0.50")}, lines[1])
+}
+
+// ===================== CreateOrder With Tax Tests =====================
+
+func TestCreateOrder_AddsTaxToTotal(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	orderItemRepo := new(mocks.MockOrderItemRepository)
+	catalogClient := new(mocks.MockCatalogServiceClient)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+	taxRepo := new(mocks.MockTaxRateRepository)
+
+	service := NewOrderService(orderRepo, orderItemRepo, catalogClient, kafkaProducer, testQuoteSigner, NewTaxEngine(taxRepo, "RU"), nil)
+
+	ctx := context.Background()
+	productID := uuid.New()
+	categoryID := uuid.New()
+
+	req := &entity.CreateOrderRequest{
+		Items:         []entity.OrderItemRequest{{ProductID: productID, Quantity: units.Of(2)}},
+		DeliveryPrice: money.MustParse("10.00"),
+		Currency:      "USD",
+	}
+
+	products := map[uuid.UUID]*entity.ProductAvailability{
+		productID: {
+			ID:         productID,
+			Price:      money.MustParse("50.00"),
+			CategoryID: categoryID,
+			Status:     entity.ProductStatusPublished,
+		},
+	}
+	catalogClient.On("GetAvailability", ctx, []uuid.UUID{productID}).Return(products, nil)
+	expectQuote(catalogClient, req, products)
+	taxRepo.On("GetByCountry", ctx, "RU").Return([]entity.TaxRate{{Country: "RU", Name: "НДС", Rate: 0.2}}, nil)
+
+	orderRepo.On("Create", ctx, mock.AnythingOfType("*entity.Order")).Return(nil)
+	orderItemRepo.On("Create", ctx, mock.AnythingOfType("*entity.OrderItem")).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+
+	// Act
+	result, err := service.CreateOrder(ctx, uuid.New(), req, "test-token")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "RU", result.Country)
+	assert.Equal(t, money.MustParse("20.00"), result.TaxTotal)
+	// TotalPrice = 50.00 * 2 + 20.00 налог + 10.00 доставка
+	assert.Equal(t, money.MustParse("130.00"), result.TotalPrice)
+	assert.Equal(t, money.MustParse("20.00"), result.Items[0].TaxAmount)
+	assert.Equal(t, "НДС", result.Items[0].TaxName)
+}
+
+// ===================== Invoice Tests =====================
+
+func TestBuildInvoice_UsesProductSnapshot(t *testing.T) {
+	// Arrange
+	order := &entity.OrderWithItems{
+		Order: entity.Order{ID: uuid.New(), Currency: "USD"},
+		Items: []entity.OrderItem{{
+			ProductID: uuid.New(),
+			Quantity:  units.Of(2),
+			UnitPrice: money.MustParse("10.00"),
+			Product:   entity.ProductSnapshot{Name: "Blueberry Box", CategoryName: "Berries"},
+		}},
+	}
+
+	// Act
+	invoice := BuildInvoice(order)
+
+	// Assert
+	require.Len(t, invoice.Lines, 1)
+	assert.Equal(t, "Blueberry Box", invoice.Lines[0].ProductName)
+	assert.Equal(t, "Berries", invoice.Lines[0].CategoryName)
+	assert.Equal(t, money.MustParse("20.00"), invoice.Subtotal)
+}
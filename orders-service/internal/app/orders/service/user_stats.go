@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+)
+
+// maxStatsCacheEntries - предел записей кеша статистики; при переполнении кеш очищается
+const maxStatsCacheEntries = 10000
+
+// GetUserStats возвращает статистику заказов пользователя для личного кабинета
+// Результат кешируется в памяти процесса на короткое время (SetStatsCacheTTL), поэтому новый заказ
+// может появиться в статистике с задержкой
+func (s *OrderService) GetUserStats(ctx context.Context, userID uuid.UUID) (*entity.UserOrderStats, error) {
+	key := tenant.FromContext(ctx) + ":" + userID.String()
+	if stats, ok := s.statsCache.get(key); ok {
+		return stats, nil
+	}
+
+	stats, err := s.orderRepo.UserStats(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order stats: %w", err)
+	}
+	stats.GeneratedAt = time.Now().UTC()
+
+	s.statsCache.set(key, stats)
+	return stats, nil
+}
+
+// SetStatsCacheTTL задает время кеширования статистики заказов пользователя, 0 - без кеша
+func (s *OrderService) SetStatsCacheTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		s.statsCache = nil
+		return
+	}
+	s.statsCache = &statsCache{ttl: ttl, entries: make(map[string]statsCacheEntry)}
+}
+
+// statsCache - кеш статистики заказов по магазину и пользователю; nil - кеш отключен
+type statsCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]statsCacheEntry
+}
+
+type statsCacheEntry struct {
+	stats     *entity.UserOrderStats
+	expiresAt time.Time
+}
+
+func (c *statsCache) get(key string) (*entity.UserOrderStats, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.stats, true
+}
+
+func (c *statsCache) set(key string, stats *entity.UserOrderStats) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if len(c.entries) >= maxStatsCacheEntries {
+		for k, entry := range c.entries {
+			if now.After(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxStatsCacheEntries {
+			c.entries = make(map[string]statsCacheEntry)
+		}
+	}
+	c.entries[key] = statsCacheEntry{stats: stats, expiresAt: now.Add(c.ttl)}
+}
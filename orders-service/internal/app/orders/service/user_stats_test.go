@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/orders-service/internal/app/orders/repository/mocks"
+	"augustberries/pkg/money"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ===================== User Stats Tests =====================
+
+func TestGetUserStats_CachedPerTenantAndUser(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	service := NewOrderService(orderRepo, nil, nil, nil, testQuoteSigner, nil, nil)
+	service.SetStatsCacheTTL(time.Minute)
+
+	userID := uuid.New()
+	shopA := tenant.WithID(context.Background(), "shop-a")
+	shopB := tenant.WithID(context.Background(), "shop-b")
+	stats := &entity.UserOrderStats{
+		TotalOrders:    2,
+		OrdersByStatus: map[entity.OrderStatus]int64{entity.OrderStatusDelivered: 2},
+		TotalSpent:     []entity.CurrencyRevenue{{Currency: "RUB", Total: money.MustParse("900.00"), Orders: 2}},
+	}
+
+	orderRepo.On("UserStats", shopA, userID).Return(stats, nil).Once()
+	orderRepo.On("UserStats", shopB, userID).Return(&entity.UserOrderStats{}, nil).Once()
+
+	// Act
+	first, err := service.GetUserStats(shopA, userID)
+	require.NoError(t, err)
+	second, err := service.GetUserStats(shopA, userID)
+	require.NoError(t, err)
+	other, err := service.GetUserStats(shopB, userID)
+	require.NoError(t, err)
+
+	// Assert: повторный запрос берется из кеша, другой магазин считается отдельно
+	assert.Same(t, first, second)
+	assert.False(t, first.GeneratedAt.IsZero())
+	assert.Equal(t, int64(0), other.TotalOrders)
+	orderRepo.AssertExpectations(t)
+}
+
+func TestGetUserStats_WithoutCache(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	service := NewOrderService(orderRepo, nil, nil, nil, testQuoteSigner, nil, nil)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	orderRepo.On("UserStats", ctx, userID).Return(&entity.UserOrderStats{}, nil).Twice()
+
+	// Act
+	_, err1 := service.GetUserStats(ctx, userID)
+	_, err2 := service.GetUserStats(ctx, userID)
+
+	// Assert
+	require.NoError(t, err1)
+	require.NoError(t, err2)
+	orderRepo.AssertExpectations(t)
+}
+
+func TestGetUserStats_RepositoryError(t *testing.T) {
+	// Arrange
+	orderRepo := new(mocks.MockOrderRepository)
+	service := NewOrderService(orderRepo, nil, nil, nil, testQuoteSigner, nil, nil)
+	service.SetStatsCacheTTL(time.Minute)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	orderRepo.On("UserStats", ctx, userID).Return(nil, errors.New("db down"))
+
+	// Act
+	stats, err := service.GetUserStats(ctx, userID)
+
+	// Assert
+	assert.Nil(t, stats)
+	assert.Error(t, err)
+}
@@ -0,0 +1,6 @@
+-- Мультитенантность: заказы принадлежат магазину, в котором оформлены
+-- Существующие заказы относятся к магазину по умолчанию
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+
+-- Списки заказов пользователя всегда фильтруются по магазину
+CREATE INDEX IF NOT EXISTS idx_orders_tenant_user ON orders(tenant_id, user_id);
@@ -0,0 +1,15 @@
+-- Флаги функциональности (pkg/featureflags)
+-- users - JSON массив ID пользователей, для которых флаг включен всегда
+CREATE TABLE IF NOT EXISTS feature_flags (
+    key VARCHAR(100) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    percentage INTEGER NOT NULL DEFAULT 0 CHECK (percentage BETWEEN 0 AND 100),
+    users JSONB NOT NULL DEFAULT '[]',
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Оформление по котировке уже работает для всех: флаг служит аварийным выключателем
+INSERT INTO feature_flags (key, description, enabled, percentage)
+VALUES ('orders.quote_checkout', 'Checkout with a signed catalog price quote', TRUE, 100)
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,34 @@
+-- Частичная отгрузка: заказ делится на отправления с трек-номерами
+-- После первой отправки статус заказа выводится из состояния отправлений
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS chk_status;
+ALTER TABLE orders ADD CONSTRAINT chk_status
+    CHECK (status IN ('pending', 'confirmed', 'partially_shipped', 'shipped', 'delivered', 'cancelled'));
+
+-- Отправления заказа
+CREATE TABLE IF NOT EXISTS shipments (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    tracking_number VARCHAR(100) NOT NULL,
+    carrier VARCHAR(100) NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'in_transit',
+    shipped_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_shipment_status CHECK (status IN ('in_transit', 'delivered'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_shipments_order_id ON shipments(order_id);
+
+-- Количество единиц каждой позиции заказа в отправлении
+CREATE TABLE IF NOT EXISTS shipment_items (
+    id UUID PRIMARY KEY,
+    shipment_id UUID NOT NULL REFERENCES shipments(id) ON DELETE CASCADE,
+    order_item_id UUID NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    quantity INT NOT NULL,
+
+    CONSTRAINT chk_shipment_item_quantity CHECK (quantity > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shipment_items_shipment_id ON shipment_items(shipment_id);
+CREATE INDEX IF NOT EXISTS idx_shipment_items_order_item_id ON shipment_items(order_item_id);
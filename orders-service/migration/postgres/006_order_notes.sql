@@ -0,0 +1,18 @@
+-- Заметки поддержки к заказам
+-- internal - только сотрудникам магазина, customer - также покупателю
+CREATE TABLE IF NOT EXISTS order_notes (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL,
+    visibility VARCHAR(20) NOT NULL,
+    body TEXT NOT NULL,
+    ticket_id VARCHAR(100),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_note_visibility CHECK (visibility IN ('internal', 'customer'))
+);
+
+-- Заметки читаются по заказу в хронологическом порядке и считаются для admin списка
+CREATE INDEX IF NOT EXISTS idx_order_notes_order_id ON order_notes(order_id, created_at);
+-- Поиск заметок по номеру обращения в системе поддержки
+CREATE INDEX IF NOT EXISTS idx_order_notes_ticket_id ON order_notes(ticket_id) WHERE ticket_id IS NOT NULL;
@@ -0,0 +1,26 @@
+-- Ставки налогов магазина по странам доставки
+-- Ставка без категории применяется ко всем товарам страны, ставка категории ее переопределяет
+CREATE TABLE IF NOT EXISTS tax_rates (
+    id UUID PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    country VARCHAR(2) NOT NULL,
+    category_id UUID,
+    name VARCHAR(100) NOT NULL,
+    rate DECIMAL(6, 4) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_tax_rate CHECK (rate >= 0 AND rate <= 1)
+);
+
+-- Одна ставка на страну и категорию магазина (общая ставка страны - category_id IS NULL)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tax_rates_lookup
+    ON tax_rates(tenant_id, country, COALESCE(category_id, '00000000-0000-0000-0000-000000000000'::uuid));
+
+-- Налог начисляется сверх цены позиции и входит в итог заказа
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS country VARCHAR(2);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tax_total DECIMAL(10, 2) NOT NULL DEFAULT 0;
+
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS tax_name VARCHAR(100);
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS tax_rate DECIMAL(6, 4) NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS tax_amount DECIMAL(10, 2) NOT NULL DEFAULT 0;
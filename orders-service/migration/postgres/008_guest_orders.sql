@@ -0,0 +1,7 @@
+-- Гостевое оформление заказа без аккаунта
+-- user_id гостевого заказа - идентификатор гостевой сессии из Auth Service,
+-- после регистрации гостя заказы переносятся на его аккаунт
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS guest_email VARCHAR(255);
+
+-- Поиск гостевого заказа по номеру и email
+CREATE INDEX IF NOT EXISTS idx_orders_guest_email ON orders(tenant_id, lower(guest_email)) WHERE guest_email IS NOT NULL;
@@ -0,0 +1,14 @@
+-- Человекочитаемые номера заказов (AB-20240115-000123) в дополнение к UUID
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS number VARCHAR(32);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_orders_tenant_number ON orders(tenant_id, number) WHERE number IS NOT NULL;
+
+-- Счетчики номеров магазина по дням
+-- Номер увеличивается на случайный шаг, поэтому по своему номеру нельзя угадать соседние
+CREATE TABLE IF NOT EXISTS order_number_sequences (
+    tenant_id VARCHAR(64) NOT NULL,
+    day DATE NOT NULL,
+    last_value BIGINT NOT NULL,
+
+    PRIMARY KEY (tenant_id, day)
+);
@@ -0,0 +1,6 @@
+-- Снимок товара на момент покупки: история заказов не зависит от переименования и удаления товаров в каталоге
+-- Позиции, созданные до миграции, остаются с пустым снимком
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS product_name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS product_description TEXT NOT NULL DEFAULT '';
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS product_image_url VARCHAR(1024) NOT NULL DEFAULT '';
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS product_category_name VARCHAR(255) NOT NULL DEFAULT '';
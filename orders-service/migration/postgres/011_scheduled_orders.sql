@@ -0,0 +1,7 @@
+-- Отложенные заказы (предзаказы, распродажи): статус scheduled до scheduled_for, затем фоновая задача переводит их в pending
+-- preferred_currency сохраняется, чтобы ORDER_CREATED при активации содержал валюту покупателя
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS scheduled_for TIMESTAMPTZ;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS preferred_currency VARCHAR(10) NOT NULL DEFAULT '';
+
+-- Фоновая задача ищет наступившие заказы всех магазинов
+CREATE INDEX IF NOT EXISTS idx_orders_scheduled_for ON orders(scheduled_for) WHERE status = 'scheduled';
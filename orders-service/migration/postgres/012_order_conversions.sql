@@ -0,0 +1,3 @@
+-- Время события, по которому Background Worker последний раз сконвертировал заказ в валюту покупателя
+-- Событие старше converted_at (повтор или доставка не по порядку) не перезаписывает более новую конвертацию
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS converted_at TIMESTAMPTZ;
@@ -0,0 +1,3 @@
+-- Окно ожидаемой доставки: рассчитывается при оформлении по срокам перевозчиков и сборки, уточняется по отправлениям
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS estimated_delivery_from DATE;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS estimated_delivery_to DATE;
@@ -0,0 +1,46 @@
+-- Порядок позиций при оформлении: первая позиция показывается в списке заказов
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS position INT NOT NULL DEFAULT 0;
+
+-- Денормализованная проекция для списков заказов: поля заказа и итоги по позициям без соединения с order_items
+-- Обновляется сервисом при каждом изменении заказа, пересобирается командой orders-service/cmd/rebuild-summaries
+CREATE TABLE IF NOT EXISTS order_summaries (
+    order_id UUID PRIMARY KEY REFERENCES orders(id) ON DELETE CASCADE,
+    number VARCHAR(32),
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    user_id UUID NOT NULL,
+    total_price DECIMAL(10, 2) NOT NULL,
+    delivery_price DECIMAL(10, 2) NOT NULL,
+    tax_total DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    currency VARCHAR(10) NOT NULL,
+    country VARCHAR(2),
+    status VARCHAR(50) NOT NULL,
+    guest_email VARCHAR(255),
+    scheduled_for TIMESTAMP,
+    created_at TIMESTAMP NOT NULL,
+    estimated_delivery_from DATE,
+    estimated_delivery_to DATE,
+    items_count INT NOT NULL DEFAULT 0,
+    items_quantity INT NOT NULL DEFAULT 0,
+    first_item_name VARCHAR(255) NOT NULL DEFAULT '',
+    refreshed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Списки: заказы пользователя и заказы магазина по статусу, новые первыми
+CREATE INDEX IF NOT EXISTS idx_order_summaries_tenant_user ON order_summaries(tenant_id, user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_order_summaries_tenant_status ON order_summaries(tenant_id, status, created_at DESC);
+
+-- Заполнение по существующим заказам
+INSERT INTO order_summaries (
+    order_id, number, tenant_id, user_id, total_price, delivery_price, tax_total, currency, country, status,
+    guest_email, scheduled_for, created_at, estimated_delivery_from, estimated_delivery_to,
+    items_count, items_quantity, first_item_name, refreshed_at
+)
+SELECT o.id, o.number, o.tenant_id, o.user_id, o.total_price, o.delivery_price, o.tax_total, o.currency, o.country, o.status,
+       o.guest_email, o.scheduled_for, o.created_at, o.estimated_delivery_from, o.estimated_delivery_to,
+       COUNT(i.id), COALESCE(SUM(i.quantity), 0),
+       COALESCE((ARRAY_AGG(i.product_name ORDER BY i.position, i.id) FILTER (WHERE i.id IS NOT NULL))[1], ''),
+       NOW()
+FROM orders o
+LEFT JOIN order_items i ON i.order_id = o.id
+GROUP BY o.id
+ON CONFLICT (order_id) DO NOTHING;
@@ -0,0 +1,8 @@
+-- Срок хранения завершенных заказов: по истечении ORDER_RETENTION_DAYS фоновая задача обезличивает или удаляет заказ
+-- legal_hold исключает заказ из обработки (судебный запрет, проверка); anonymized_at - заказ уже обезличен
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS legal_hold BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMPTZ;
+
+-- Фоновая задача ищет старые завершенные заказы всех магазинов
+CREATE INDEX IF NOT EXISTS idx_orders_retention ON orders(created_at)
+    WHERE status IN ('delivered', 'cancelled') AND NOT legal_hold AND anonymized_at IS NULL;
@@ -0,0 +1,8 @@
+-- Дробное количество весовых и разливных товаров (1.5 кг, 0.75 л) с точностью до тысячной
+-- Целые количества штучных товаров сохраняются без изменений
+ALTER TABLE order_items ALTER COLUMN quantity TYPE DECIMAL(12,3);
+ALTER TABLE shipment_items ALTER COLUMN quantity TYPE DECIMAL(12,3);
+ALTER TABLE order_summaries ALTER COLUMN items_quantity TYPE DECIMAL(12,3);
+
+-- Единица измерения в снимке товара; позиции, созданные до миграции, штучные
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS product_unit VARCHAR(16) NOT NULL DEFAULT 'piece';
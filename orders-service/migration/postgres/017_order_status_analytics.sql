@@ -0,0 +1,36 @@
+-- Аналитика сроков выполнения заказов: Background Worker заполняет таблицы по событиям ORDER_STATUS_CHANGED
+-- Внешних ключей на orders нет: статистика сохраняется после удаления заказа по сроку хранения
+
+-- Переходы заказов между статусами; duration_seconds - время в from_status (NULL - начало статуса неизвестно)
+CREATE TABLE IF NOT EXISTS order_status_transitions (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    from_status VARCHAR(50) NOT NULL,
+    to_status VARCHAR(50) NOT NULL,
+    actor_type VARCHAR(20) NOT NULL,
+    actor_id UUID,
+    impersonator_id VARCHAR(64),
+    reason VARCHAR(500),
+    changed_at TIMESTAMPTZ NOT NULL,
+    duration_seconds DOUBLE PRECISION
+);
+
+-- Повторная доставка события не создает второй переход
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_status_transitions_event ON order_status_transitions(order_id, to_status, changed_at);
+-- Отчет по периоду (и магазину)
+CREATE INDEX IF NOT EXISTS idx_order_status_transitions_changed ON order_status_transitions(changed_at, tenant_id);
+
+-- Текущий статус заказа и момент входа в него
+CREATE TABLE IF NOT EXISTS order_status_projection (
+    order_id UUID PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    status VARCHAR(50) NOT NULL,
+    status_since TIMESTAMPTZ NOT NULL,
+    order_created_at TIMESTAMPTZ,
+    delivered_at TIMESTAMPTZ
+);
+
+-- Время до доставки по периоду доставки
+CREATE INDEX IF NOT EXISTS idx_order_status_projection_delivered ON order_status_projection(delivered_at, tenant_id)
+    WHERE delivered_at IS NOT NULL;
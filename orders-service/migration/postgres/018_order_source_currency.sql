@@ -0,0 +1,8 @@
+-- Валюта и доставка заказа на момент оформления, до конвертации Background Worker
+-- Изменение состава заказа пересчитывает его в исходной валюте и сбрасывает converted_at, чтобы конвертация выполнилась заново
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS source_currency VARCHAR(10) NOT NULL DEFAULT '';
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS source_delivery_price DECIMAL(10,2) NOT NULL DEFAULT 0;
+
+-- Несконвертированные заказы еще в исходной валюте; для сконвертированных исходные значения не восстановить
+UPDATE orders SET source_currency = currency, source_delivery_price = delivery_price
+WHERE source_currency = '' AND converted_at IS NULL;
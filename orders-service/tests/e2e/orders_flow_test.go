@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"augustberries/orders-service/internal/app/orders/entity"
+	"augustberries/pkg/money"
+	"augustberries/pkg/units"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -50,9 +52,9 @@ func TestFullOrderFlow(t *testing.T) {
 
 	createReq := entity.CreateOrderRequest{
 		Items: []entity.OrderItemRequest{
-			{ProductID: productID, Quantity: 2},
+			{ProductID: productID, Quantity: units.Of(2)},
 		},
-		DeliveryPrice: 15.0,
+		DeliveryPrice: money.MustParse("15.00"),
 		Currency:      "USD",
 	}
 	body, _ := json.Marshal(createReq)
@@ -173,9 +175,9 @@ func TestInvalidStatusTransition(t *testing.T) {
 	// Создаём заказ
 	createReq := entity.CreateOrderRequest{
 		Items: []entity.OrderItemRequest{
-			{ProductID: productID, Quantity: 1},
+			{ProductID: productID, Quantity: units.Of(1)},
 		},
-		DeliveryPrice: 10.0,
+		DeliveryPrice: money.MustParse("10.00"),
 		Currency:      "USD",
 	}
 	body, _ := json.Marshal(createReq)
@@ -299,9 +301,9 @@ func TestCancelOrder(t *testing.T) {
 	// Создаём заказ
 	createReq := entity.CreateOrderRequest{
 		Items: []entity.OrderItemRequest{
-			{ProductID: productID, Quantity: 1},
+			{ProductID: productID, Quantity: units.Of(1)},
 		},
-		DeliveryPrice: 5.0,
+		DeliveryPrice: money.MustParse("5.00"),
 		Currency:      "RUB",
 	}
 	body, _ := json.Marshal(createReq)
@@ -369,7 +371,7 @@ func TestConcurrentOrderCreation(t *testing.T) {
 				Items: []entity.OrderItemRequest{
 					{ProductID: productID, Quantity: idx + 1},
 				},
-				DeliveryPrice: float64(idx * 5),
+				DeliveryPrice: money.FromMinor(int64(idx * 5 * money.Scale)),
 				Currency:      "USD",
 			}
 			body, _ := json.Marshal(createReq)
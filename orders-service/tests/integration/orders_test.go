@@ -16,6 +16,9 @@ import (
 	"augustberries/orders-service/internal/app/orders/handler"
 	"augustberries/orders-service/internal/app/orders/repository"
 	"augustberries/orders-service/internal/app/orders/service"
+	"augustberries/pkg/money"
+	"augustberries/pkg/quote"
+	"augustberries/pkg/units"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -45,12 +48,17 @@ func (m *MockCatalogClient) GetProduct(ctx context.Context, productID uuid.UUID)
 	return args.Get(0).(*entity.ProductWithCategory), args.Error(1)
 }
 
-func (m *MockCatalogClient) GetProducts(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID]*entity.ProductWithCategory, error) {
+func (m *MockCatalogClient) GetAvailability(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID]*entity.ProductAvailability, error) {
 	args := m.Called(ctx, productIDs)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(map[uuid.UUID]*entity.ProductWithCategory), args.Error(1)
+	return args.Get(0).(map[uuid.UUID]*entity.ProductAvailability), args.Error(1)
+}
+
+func (m *MockCatalogClient) ValidateProducts(ctx context.Context, items []entity.OrderItemRequest) (string, error) {
+	args := m.Called(ctx, items)
+	return args.String(0), args.Error(1)
 }
 
 // MockKafkaProducer мок для Kafka в integration тестах
@@ -77,6 +85,7 @@ type OrdersIntegrationTestSuite struct {
 	orderService  *service.OrderService
 	catalogClient *MockCatalogClient
 	kafkaProducer *MockKafkaProducer
+	quoteSigner   *quote.Signer
 	testUserID    uuid.UUID
 	testProductID uuid.UUID
 }
@@ -94,7 +103,7 @@ func (s *OrdersIntegrationTestSuite) SetupSuite() {
 	require.NoError(s.T(), err, "Failed to connect to database")
 
 	// Автомиграция
-	err = s.db.AutoMigrate(&entity.Order{}, &entity.OrderItem{})
+	err = s.db.AutoMigrate(&entity.Order{}, &entity.OrderItem{}, &entity.Shipment{}, &entity.ShipmentItem{}, &entity.OrderNote{})
 	require.NoError(s.T(), err, "Failed to migrate database")
 
 	// Инициализация компонентов
@@ -103,8 +112,9 @@ func (s *OrdersIntegrationTestSuite) SetupSuite() {
 
 	s.catalogClient = &MockCatalogClient{}
 	s.kafkaProducer = &MockKafkaProducer{Messages: make([][]byte, 0)}
+	s.quoteSigner = quote.NewSigner("test-quote-secret")
 
-	s.orderService = service.NewOrderService(orderRepo, orderItemRepo, s.catalogClient, s.kafkaProducer)
+	s.orderService = service.NewOrderService(orderRepo, orderItemRepo, s.catalogClient, s.kafkaProducer, s.quoteSigner, nil, nil)
 
 	// Тестовые данные
 	s.testUserID = uuid.New()
@@ -114,7 +124,7 @@ func (s *OrdersIntegrationTestSuite) SetupSuite() {
 	gin.SetMode(gin.TestMode)
 	s.router = gin.New()
 
-	orderHandler := handler.NewOrderHandler(s.orderService)
+	orderHandler := handler.NewOrderHandler(s.orderService, nil)
 
 	// Middleware для установки user_id и auth_token
 	authMiddleware := func(c *gin.Context) {
@@ -158,27 +168,39 @@ func (s *OrdersIntegrationTestSuite) TearDownSuite() {
 	}
 }
 
+// expectQuote настраивает мок Catalog Service на выдачу подписанной котировки для тестового товара
+func (s *OrdersIntegrationTestSuite) expectQuote(products map[uuid.UUID]*entity.ProductAvailability, quantity int) {
+	token, err := s.quoteSigner.Sign(&quote.Quote{
+		ID:        uuid.New(),
+		Items:     []quote.Item{{ProductID: s.testProductID, Quantity: quantity, UnitPrice: products[s.testProductID].Price}},
+		Currency:  "USD",
+		IssuedAt:  time.Now(),
+		ExpiresAt: time.Now().Add(time.Minute),
+	})
+	s.Require().NoError(err)
+	s.catalogClient.On("ValidateProducts", mock.Anything, mock.Anything).Return(token, nil)
+}
+
 // ===================== Integration Tests =====================
 
 func (s *OrdersIntegrationTestSuite) TestCreateOrder_Success() {
 	// Настраиваем mock Catalog Service
-	products := map[uuid.UUID]*entity.ProductWithCategory{
+	products := map[uuid.UUID]*entity.ProductAvailability{
 		s.testProductID: {
-			Product: entity.Product{
-				ID:    s.testProductID,
-				Name:  "Test Product",
-				Price: 99.99,
-			},
+			ID:     s.testProductID,
+			Price:  money.MustParse("99.99"),
+			Status: entity.ProductStatusPublished,
 		},
 	}
-	s.catalogClient.On("GetProducts", mock.Anything, mock.Anything).Return(products, nil)
+	s.catalogClient.On("GetAvailability", mock.Anything, mock.Anything).Return(products, nil)
+	s.expectQuote(products, 2)
 	s.kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	reqBody := entity.CreateOrderRequest{
 		Items: []entity.OrderItemRequest{
-			{ProductID: s.testProductID, Quantity: 2},
+			{ProductID: s.testProductID, Quantity: units.Of(2)},
 		},
-		DeliveryPrice: 10.0,
+		DeliveryPrice: money.MustParse("10.00"),
 		Currency:      "USD",
 	}
 	body, _ := json.Marshal(reqBody)
@@ -199,7 +221,7 @@ func (s *OrdersIntegrationTestSuite) TestCreateOrder_Success() {
 	s.Equal(entity.OrderStatusPending, response.Status)
 	s.Equal("USD", response.Currency)
 	// TotalPrice = (99.99 * 2) + 10.0 = 209.98
-	s.Equal(209.98, response.TotalPrice)
+	s.Equal(money.MustParse("209.98"), response.TotalPrice)
 
 	// Проверяем что заказ сохранён в БД
 	var dbOrder entity.Order
@@ -216,8 +238,8 @@ func (s *OrdersIntegrationTestSuite) TestGetOrder_Success() {
 	order := entity.Order{
 		ID:            orderID,
 		UserID:        s.testUserID,
-		TotalPrice:    150.0,
-		DeliveryPrice: 10.0,
+		TotalPrice:    money.MustParse("150.00"),
+		DeliveryPrice: money.MustParse("10.00"),
 		Currency:      "USD",
 		Status:        entity.OrderStatusPending,
 		CreatedAt:     time.Now(),
@@ -228,8 +250,8 @@ func (s *OrdersIntegrationTestSuite) TestGetOrder_Success() {
 		ID:        uuid.New(),
 		OrderID:   orderID,
 		ProductID: s.testProductID,
-		Quantity:  1,
-		UnitPrice: 140.0,
+		Quantity:  units.Of(1),
+		UnitPrice: money.MustParse("140.00"),
 	}
 	s.db.Create(&item)
 
@@ -265,7 +287,7 @@ func (s *OrdersIntegrationTestSuite) TestGetOrder_AccessDenied() {
 	order := entity.Order{
 		ID:         orderID,
 		UserID:     anotherUserID, // Другой пользователь
-		TotalPrice: 100.0,
+		TotalPrice: money.MustParse("100.00"),
 		Status:     entity.OrderStatusPending,
 		CreatedAt:  time.Now(),
 	}
@@ -285,7 +307,7 @@ func (s *OrdersIntegrationTestSuite) TestUpdateOrderStatus_Success() {
 	order := entity.Order{
 		ID:         orderID,
 		UserID:     s.testUserID,
-		TotalPrice: 100.0,
+		TotalPrice: money.MustParse("100.00"),
 		Currency:   "USD",
 		Status:     entity.OrderStatusPending,
 		CreatedAt:  time.Now(),
@@ -318,7 +340,7 @@ func (s *OrdersIntegrationTestSuite) TestUpdateOrderStatus_InvalidTransition() {
 	order := entity.Order{
 		ID:         orderID,
 		UserID:     s.testUserID,
-		TotalPrice: 100.0,
+		TotalPrice: money.MustParse("100.00"),
 		Status:     entity.OrderStatusDelivered,
 		CreatedAt:  time.Now(),
 	}
@@ -343,7 +365,7 @@ func (s *OrdersIntegrationTestSuite) TestDeleteOrder_Success() {
 	order := entity.Order{
 		ID:         orderID,
 		UserID:     s.testUserID,
-		TotalPrice: 100.0,
+		TotalPrice: money.MustParse("100.00"),
 		Status:     entity.OrderStatusPending,
 		CreatedAt:  time.Now(),
 	}
@@ -353,8 +375,8 @@ func (s *OrdersIntegrationTestSuite) TestDeleteOrder_Success() {
 		ID:        uuid.New(),
 		OrderID:   orderID,
 		ProductID: s.testProductID,
-		Quantity:  1,
-		UnitPrice: 100.0,
+		Quantity:  units.Of(1),
+		UnitPrice: money.MustParse("100.00"),
 	}
 	s.db.Create(&item)
 
@@ -381,7 +403,7 @@ func (s *OrdersIntegrationTestSuite) TestGetUserOrders_Success() {
 		order := entity.Order{
 			ID:         uuid.New(),
 			UserID:     s.testUserID,
-			TotalPrice: float64(100 * (i + 1)),
+			TotalPrice: money.FromMinor(int64(100 * (i + 1) * money.Scale)),
 			Status:     entity.OrderStatusPending,
 			CreatedAt:  time.Now(),
 		}
@@ -423,16 +445,17 @@ func (s *OrdersIntegrationTestSuite) TestHealthCheck() {
 
 func (s *OrdersIntegrationTestSuite) TestOrderWorkflow_FullCycle() {
 	// Настраиваем моки
-	products := map[uuid.UUID]*entity.ProductWithCategory{
-		s.testProductID: {Product: entity.Product{ID: s.testProductID, Price: 100.0}},
+	products := map[uuid.UUID]*entity.ProductAvailability{
+		s.testProductID: {ID: s.testProductID, Price: money.MustParse("100.00"), Status: entity.ProductStatusPublished},
 	}
-	s.catalogClient.On("GetProducts", mock.Anything, mock.Anything).Return(products, nil)
+	s.catalogClient.On("GetAvailability", mock.Anything, mock.Anything).Return(products, nil)
+	s.expectQuote(products, 1)
 	s.kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	// 1. Создаём заказ
 	createReq := entity.CreateOrderRequest{
-		Items:         []entity.OrderItemRequest{{ProductID: s.testProductID, Quantity: 1}},
-		DeliveryPrice: 10.0,
+		Items:         []entity.OrderItemRequest{{ProductID: s.testProductID, Quantity: units.Of(1)}},
+		DeliveryPrice: money.MustParse("10.00"),
 		Currency:      "USD",
 	}
 	body, _ := json.Marshal(createReq)
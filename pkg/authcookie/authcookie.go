@@ -0,0 +1,198 @@
+// Package authcookie - токены в httpOnly cookie для SPA и защита от CSRF по схеме double-submit
+//
+// Auth Service при входе кладет access и refresh токены в httpOnly cookie, недоступные JS,
+// и случайный CSRF токен в обычную cookie. SPA читает CSRF cookie и повторяет значение в заголовке
+// X-CSRF-Token: чужой сайт может заставить браузер отправить cookie, но не может прочитать ее значение.
+// Заголовок проверяется только у изменяющих запросов, аутентифицированных cookie;
+// запросы с Authorization: Bearer CSRF не подвержены и проверяются как раньше
+package authcookie
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Имена cookie и заголовка CSRF токена
+const (
+	AccessCookie  = "access_token"
+	RefreshCookie = "refresh_token"
+	CSRFCookie    = "csrf_token"
+	CSRFHeader    = "X-CSRF-Token"
+)
+
+// RefreshPath - путь refresh cookie: браузер отправляет ее только в Auth Service
+const RefreshPath = "/auth"
+
+var (
+	// ErrNoToken - в запросе нет ни заголовка Authorization, ни cookie с токеном
+	ErrNoToken = errors.New("authorization header required")
+	// ErrInvalidHeader - заголовок Authorization не в формате "Bearer <token>"
+	ErrInvalidHeader = errors.New("invalid authorization header format")
+	// ErrCSRF - изменяющий запрос с cookie без CSRF токена или с чужим токеном
+	ErrCSRF = errors.New("invalid csrf token")
+)
+
+// Config - атрибуты выдаваемых cookie
+type Config struct {
+	Domain   string        // Пусто - cookie только для хоста Auth Service
+	Secure   bool          // Только HTTPS; отключается для локальной разработки по HTTP
+	SameSite http.SameSite // Lax по умолчанию; None требует Secure
+}
+
+// Cookies извлекает токены из cookie и выдает их; nil - cookie отключены, токен только в заголовке
+type Cookies struct {
+	cfg Config
+}
+
+// New включает аутентификацию по cookie
+func New(cfg Config) *Cookies {
+	if cfg.SameSite == 0 {
+		cfg.SameSite = http.SameSiteLaxMode
+	}
+	return &Cookies{cfg: cfg}
+}
+
+// FromEnv включает cookie по AUTH_COOKIES=true; атрибуты - AUTH_COOKIE_DOMAIN, AUTH_COOKIE_SECURE
+// (по умолчанию true) и AUTH_COOKIE_SAMESITE (lax, strict, none)
+// Без AUTH_COOKIES возвращает nil. Используется в main всех HTTP сервисов
+func FromEnv() (*Cookies, error) {
+	enabled, err := envBool("AUTH_COOKIES", false)
+	if err != nil || !enabled {
+		return nil, err
+	}
+
+	secure, err := envBool("AUTH_COOKIE_SECURE", true)
+	if err != nil {
+		return nil, err
+	}
+
+	var sameSite http.SameSite
+	switch value := strings.ToLower(os.Getenv("AUTH_COOKIE_SAMESITE")); value {
+	case "", "lax":
+		sameSite = http.SameSiteLaxMode
+	case "strict":
+		sameSite = http.SameSiteStrictMode
+	case "none":
+		if !secure {
+			return nil, fmt.Errorf("AUTH_COOKIE_SAMESITE=none requires AUTH_COOKIE_SECURE=true")
+		}
+		sameSite = http.SameSiteNoneMode
+	default:
+		return nil, fmt.Errorf("invalid AUTH_COOKIE_SAMESITE value: %q", value)
+	}
+
+	return New(Config{Domain: os.Getenv("AUTH_COOKIE_DOMAIN"), Secure: secure, SameSite: sameSite}), nil
+}
+
+func envBool(key string, fallback bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s value: %q", key, value)
+	}
+	return parsed, nil
+}
+
+// Token возвращает access токен из заголовка Authorization, а без него - из cookie
+// Для изменяющих запросов с cookie проверяется CSRF токен. Безопасен для nil: тогда только заголовок
+func (c *Cookies) Token(r *http.Request) (string, error) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		parts := strings.Split(header, " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			return "", ErrInvalidHeader
+		}
+		return parts[1], nil
+	}
+
+	return c.cookieToken(r, AccessCookie)
+}
+
+// RefreshToken возвращает refresh токен из cookie с проверкой CSRF; nil - ErrNoToken
+func (c *Cookies) RefreshToken(r *http.Request) (string, error) {
+	return c.cookieToken(r, RefreshCookie)
+}
+
+func (c *Cookies) cookieToken(r *http.Request, name string) (string, error) {
+	if c == nil {
+		return "", ErrNoToken
+	}
+	cookie, err := r.Cookie(name)
+	if err != nil || cookie.Value == "" {
+		return "", ErrNoToken
+	}
+	if err := verifyCSRF(r); err != nil {
+		return "", err
+	}
+	return cookie.Value, nil
+}
+
+// verifyCSRF сравнивает заголовок X-CSRF-Token с CSRF cookie у изменяющих запросов
+func verifyCSRF(r *http.Request) error {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return nil
+	}
+
+	cookie, err := r.Cookie(CSRFCookie)
+	if err != nil || cookie.Value == "" {
+		return ErrCSRF
+	}
+	header := r.Header.Get(CSRFHeader)
+	if subtle.ConstantTimeCompare([]byte(header), []byte(cookie.Value)) != 1 {
+		return ErrCSRF
+	}
+	return nil
+}
+
+// SetTokens кладет токены в httpOnly cookie и выдает новый CSRF токен на срок refresh токена
+// Без refresh токена (выпуск только access токена) refresh cookie не меняется
+func (c *Cookies) SetTokens(w http.ResponseWriter, access string, accessTTL time.Duration, refresh string, refreshTTL time.Duration) error {
+	csrf := make([]byte, 32)
+	if _, err := rand.Read(csrf); err != nil {
+		return fmt.Errorf("failed to generate csrf token: %w", err)
+	}
+
+	http.SetCookie(w, c.cookie(AccessCookie, "/", access, accessTTL, true))
+	if refresh != "" {
+		http.SetCookie(w, c.cookie(RefreshCookie, RefreshPath, refresh, refreshTTL, true))
+	}
+	// CSRF токен читает JS, поэтому без HttpOnly
+	http.SetCookie(w, c.cookie(CSRFCookie, "/", base64.RawURLEncoding.EncodeToString(csrf), max(accessTTL, refreshTTL), false))
+	return nil
+}
+
+// Clear удаляет cookie токенов и CSRF токена (выход)
+func (c *Cookies) Clear(w http.ResponseWriter) {
+	http.SetCookie(w, c.cookie(AccessCookie, "/", "", -1, true))
+	http.SetCookie(w, c.cookie(RefreshCookie, RefreshPath, "", -1, true))
+	http.SetCookie(w, c.cookie(CSRFCookie, "/", "", -1, false))
+}
+
+// cookie собирает cookie с атрибутами конфигурации; отрицательный ttl удаляет cookie
+func (c *Cookies) cookie(name, path, value string, ttl time.Duration, httpOnly bool) *http.Cookie {
+	maxAge := int(ttl.Seconds())
+	if ttl < 0 {
+		maxAge = -1
+	}
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   c.cfg.Domain,
+		MaxAge:   maxAge,
+		Secure:   c.cfg.Secure,
+		HttpOnly: httpOnly,
+		SameSite: c.cfg.SameSite,
+	}
+}
@@ -0,0 +1,170 @@
+package authcookie
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRequest создает запрос с cookie
+func newRequest(method string, cookies ...*http.Cookie) *http.Request {
+	r := httptest.NewRequest(method, "/orders", nil)
+	for _, cookie := range cookies {
+		r.AddCookie(cookie)
+	}
+	return r
+}
+
+// ==================== Token Tests ====================
+
+func TestToken_Header(t *testing.T) {
+	r := newRequest(http.MethodPost, &http.Cookie{Name: AccessCookie, Value: "cookie-token"})
+	r.Header.Set("Authorization", "Bearer header-token")
+
+	token, err := New(Config{}).Token(r)
+
+	// Заголовок важнее cookie и не требует CSRF токена
+	require.NoError(t, err)
+	assert.Equal(t, "header-token", token)
+}
+
+func TestToken_InvalidHeader(t *testing.T) {
+	r := newRequest(http.MethodGet)
+	r.Header.Set("Authorization", "Token abc")
+
+	_, err := New(Config{}).Token(r)
+
+	assert.ErrorIs(t, err, ErrInvalidHeader)
+}
+
+func TestToken_CookiesDisabled(t *testing.T) {
+	var disabled *Cookies
+	r := newRequest(http.MethodGet, &http.Cookie{Name: AccessCookie, Value: "cookie-token"})
+
+	_, err := disabled.Token(r)
+
+	assert.ErrorIs(t, err, ErrNoToken)
+}
+
+func TestToken_Cookie(t *testing.T) {
+	access := &http.Cookie{Name: AccessCookie, Value: "cookie-token"}
+	csrf := &http.Cookie{Name: CSRFCookie, Value: "csrf-value"}
+
+	tests := []struct {
+		name    string
+		method  string
+		cookies []*http.Cookie
+		header  string
+		wantErr error
+	}{
+		{"safe method without csrf", http.MethodGet, []*http.Cookie{access}, "", nil},
+		{"unsafe method with matching csrf", http.MethodPost, []*http.Cookie{access, csrf}, "csrf-value", nil},
+		{"unsafe method without header", http.MethodDelete, []*http.Cookie{access, csrf}, "", ErrCSRF},
+		{"unsafe method with other token", http.MethodPatch, []*http.Cookie{access, csrf}, "forged", ErrCSRF},
+		{"unsafe method without csrf cookie", http.MethodPut, []*http.Cookie{access}, "csrf-value", ErrCSRF},
+		{"no cookie", http.MethodGet, nil, "", ErrNoToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRequest(tt.method, tt.cookies...)
+			if tt.header != "" {
+				r.Header.Set(CSRFHeader, tt.header)
+			}
+
+			token, err := New(Config{}).Token(r)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "cookie-token", token)
+		})
+	}
+}
+
+// ==================== SetTokens Tests ====================
+
+func TestSetTokens_RoundTrip(t *testing.T) {
+	cookies := New(Config{Domain: "example.com", Secure: true})
+	w := httptest.NewRecorder()
+
+	require.NoError(t, cookies.SetTokens(w, "access", 15*time.Minute, "refresh", 7*24*time.Hour))
+
+	issued := map[string]*http.Cookie{}
+	for _, cookie := range w.Result().Cookies() {
+		issued[cookie.Name] = cookie
+	}
+	require.Len(t, issued, 3)
+	assert.True(t, issued[AccessCookie].HttpOnly)
+	assert.True(t, issued[RefreshCookie].HttpOnly)
+	assert.Equal(t, RefreshPath, issued[RefreshCookie].Path)
+	assert.False(t, issued[CSRFCookie].HttpOnly, "SPA должно читать CSRF токен")
+	assert.Equal(t, 900, issued[AccessCookie].MaxAge)
+	assert.True(t, issued[CSRFCookie].Secure)
+	assert.Equal(t, http.SameSiteLaxMode, issued[CSRFCookie].SameSite)
+
+	// Выданные cookie и CSRF токен в заголовке проходят проверку
+	r := newRequest(http.MethodPost, issued[AccessCookie], issued[CSRFCookie])
+	r.Header.Set(CSRFHeader, issued[CSRFCookie].Value)
+	token, err := cookies.Token(r)
+	require.NoError(t, err)
+	assert.Equal(t, "access", token)
+
+	r = newRequest(http.MethodPost, issued[RefreshCookie], issued[CSRFCookie])
+	r.Header.Set(CSRFHeader, issued[CSRFCookie].Value)
+	token, err = cookies.RefreshToken(r)
+	require.NoError(t, err)
+	assert.Equal(t, "refresh", token)
+}
+
+func TestClear(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	New(Config{}).Clear(w)
+
+	cleared := w.Result().Cookies()
+	require.Len(t, cleared, 3)
+	for _, cookie := range cleared {
+		assert.Empty(t, cookie.Value)
+		assert.Negative(t, cookie.MaxAge)
+	}
+}
+
+// ==================== FromEnv Tests ====================
+
+func TestFromEnv(t *testing.T) {
+	t.Run("disabled by default", func(t *testing.T) {
+		cookies, err := FromEnv()
+
+		require.NoError(t, err)
+		assert.Nil(t, cookies)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Setenv("AUTH_COOKIES", "true")
+		t.Setenv("AUTH_COOKIE_SAMESITE", "strict")
+
+		cookies, err := FromEnv()
+
+		require.NoError(t, err)
+		require.NotNil(t, cookies)
+		assert.True(t, cookies.cfg.Secure)
+		assert.Equal(t, http.SameSiteStrictMode, cookies.cfg.SameSite)
+	})
+
+	t.Run("samesite none requires secure", func(t *testing.T) {
+		t.Setenv("AUTH_COOKIES", "true")
+		t.Setenv("AUTH_COOKIE_SECURE", "false")
+		t.Setenv("AUTH_COOKIE_SAMESITE", "none")
+
+		_, err := FromEnv()
+
+		assert.Error(t, err)
+	})
+}
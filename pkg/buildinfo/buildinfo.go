@@ -0,0 +1,77 @@
+// Package buildinfo хранит сведения о сборке сервиса: коммит, время сборки и версию Go
+// Коммит и время передаются при сборке через ldflags:
+//
+//	go build -ldflags "-X augustberries/pkg/buildinfo.Commit=$(git rev-parse HEAD) -X augustberries/pkg/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Без ldflags используются сведения VCS, которые go build встраивает сам при сборке из git репозитория
+package buildinfo
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+)
+
+// unknown - значение поля, которое не удалось определить
+const unknown = "unknown"
+
+// Задаются через -ldflags "-X augustberries/pkg/buildinfo.<Имя>=<значение>"
+var (
+	Commit    = "" // Хеш git коммита
+	BuildTime = "" // Время сборки (RFC 3339, UTC)
+)
+
+// Info - сведения о сборке сервиса для GET /version и /health
+type Info struct {
+	Service   string `json:"service"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+	Modified  bool   `json:"modified,omitempty"` // Сборка из рабочей копии с незакоммиченными изменениями
+}
+
+// Get возвращает сведения о сборке сервиса
+func Get(service string) Info {
+	info := Info{
+		Service:   service,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildTime == "" {
+					info.BuildTime = setting.Value
+				}
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+
+	if info.Commit == "" {
+		info.Commit = unknown
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = unknown
+	}
+	return info
+}
+
+// Handler отвечает на GET /version сведениями о сборке сервиса
+// Публичный: сведения помогают определить сборку при разборе инцидента и не содержат секретов
+func Handler(service string) http.HandlerFunc {
+	info := Get(service)
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_ = json.NewEncoder(w).Encode(info)
+	}
+}
@@ -0,0 +1,52 @@
+package buildinfo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setLDFlags(t *testing.T, commit, buildTime string) {
+	t.Helper()
+	prevCommit, prevBuildTime := Commit, BuildTime
+	Commit, BuildTime = commit, buildTime
+	t.Cleanup(func() { Commit, BuildTime = prevCommit, prevBuildTime })
+}
+
+func TestGet_UsesLDFlags(t *testing.T) {
+	setLDFlags(t, "abc123", "2026-01-02T03:04:05Z")
+
+	info := Get("orders-service")
+
+	assert.Equal(t, "orders-service", info.Service)
+	assert.Equal(t, "abc123", info.Commit)
+	assert.Equal(t, "2026-01-02T03:04:05Z", info.BuildTime)
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+}
+
+func TestGet_WithoutLDFlagsNeverEmpty(t *testing.T) {
+	setLDFlags(t, "", "")
+
+	info := Get("orders-service")
+
+	// В тестовом бинарнике сведений VCS нет, поля заполняются значением по умолчанию
+	assert.NotEmpty(t, info.Commit)
+	assert.NotEmpty(t, info.BuildTime)
+}
+
+func TestHandler(t *testing.T) {
+	setLDFlags(t, "abc123", "2026-01-02T03:04:05Z")
+
+	w := httptest.NewRecorder()
+	Handler("orders-service").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var info Info
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &info))
+	assert.Equal(t, Get("orders-service"), info)
+}
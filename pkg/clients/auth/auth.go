@@ -0,0 +1,109 @@
+// Package auth - клиент Auth Service: проверка токенов пользователей и интроспекция по RFC 7662
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"augustberries/pkg/clients"
+	"augustberries/pkg/impersonation"
+	"augustberries/pkg/tenant"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidToken - токен подделан, отозван или имеет неверный формат
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrExpiredToken - срок действия токена истек
+	ErrExpiredToken = errors.New("token has expired")
+	// ErrInvalidClient - Auth Service не принял client_id и client_secret интроспекции
+	ErrInvalidClient = errors.New("invalid introspection client")
+)
+
+// Claims - данные действующего токена из POST /auth/validate
+type Claims struct {
+	UserID            uuid.UUID                   `json:"user_id"`
+	Email             string                      `json:"email"`
+	RoleID            int                         `json:"role_id"`
+	RoleName          string                      `json:"role_name"`
+	Permissions       []string                    `json:"permissions"`
+	TenantID          string                      `json:"tenant_id,omitempty"`
+	PreferredCurrency string                      `json:"preferred_currency,omitempty"`
+	TenantRoles       tenant.Roles                `json:"tenant_roles,omitempty"`
+	Impersonator      *impersonation.Impersonator `json:"impersonator,omitempty"`
+	ExpiresAt         int64                       `json:"exp"` // Unix time
+}
+
+// Introspection - ответ POST /auth/introspect; для неактивного токена заполнено только Active
+type Introspection struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"` // Разрешения через пробел
+	Username  string `json:"username,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+	Iat       int64  `json:"iat,omitempty"`
+	Nbf       int64  `json:"nbf,omitempty"`
+	Sub       string `json:"sub,omitempty"`
+	Role      string `json:"role,omitempty"`
+	TenantID  string `json:"tenant_id,omitempty"`
+}
+
+// Client - клиент Auth Service
+type Client struct {
+	base         *clients.Client
+	clientID     string // Учетные данные интроспекции (INTROSPECTION_CLIENTS Auth Service)
+	clientSecret string
+}
+
+// New создает клиент; clientID и clientSecret нужны только для Introspect
+func New(cfg clients.Config, clientID, clientSecret string) *Client {
+	return &Client{base: clients.New("auth", cfg), clientID: clientID, clientSecret: clientSecret}
+}
+
+// Validate проверяет токен пользователя и возвращает его claims
+func (c *Client) Validate(ctx context.Context, token string) (*Claims, error) {
+	var claims Claims
+	err := c.base.Do(ctx, clients.Request{
+		Method:     http.MethodPost,
+		Path:       "/auth/validate",
+		Header:     http.Header{"Authorization": {"Bearer " + token}},
+		Idempotent: true,
+	}, &claims)
+	if err != nil {
+		var statusErr *clients.StatusError
+		if errors.As(err, &statusErr) && (statusErr.StatusCode == http.StatusUnauthorized || statusErr.StatusCode == http.StatusBadRequest) {
+			if strings.Contains(strings.ToLower(statusErr.Message), "expired") {
+				return nil, ErrExpiredToken
+			}
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+	return &claims, nil
+}
+
+// Introspect возвращает состояние токена по RFC 7662; неактивный токен - не ошибка
+func (c *Client) Introspect(ctx context.Context, token string) (*Introspection, error) {
+	credentials := base64.StdEncoding.EncodeToString([]byte(c.clientID + ":" + c.clientSecret))
+
+	var result Introspection
+	err := c.base.Do(ctx, clients.Request{
+		Method:     http.MethodPost,
+		Path:       "/auth/introspect",
+		Form:       url.Values{"token": {token}},
+		Header:     http.Header{"Authorization": {"Basic " + credentials}},
+		Idempotent: true,
+	}, &result)
+	if clients.HasStatus(err, http.StatusUnauthorized) {
+		return nil, ErrInvalidClient
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"augustberries/pkg/clients"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return New(clients.DefaultConfig(server.URL), "orders-service", "secret")
+}
+
+// ==================== Validate Tests ====================
+
+func TestValidate_ReturnsClaims(t *testing.T) {
+	// Arrange
+	userID := uuid.New()
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/auth/validate", r.URL.Path)
+		assert.Equal(t, "Bearer user-token", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"user_id":"` + userID.String() + `","email":"a@b.c","role_name":"customer","permissions":["order.create"],"tenant_id":"shop-a","exp":1767225600}`))
+	})
+
+	// Act
+	claims, err := client.Validate(context.Background(), "user-token")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, userID, claims.UserID)
+	assert.Equal(t, "customer", claims.RoleName)
+	assert.Equal(t, "shop-a", claims.TenantID)
+	assert.Equal(t, int64(1767225600), claims.ExpiresAt)
+}
+
+func TestValidate_MapsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want error
+	}{
+		{"expired", `{"error":"Unauthorized","message":"Token has expired"}`, ErrExpiredToken},
+		{"invalid", `{"error":"Unauthorized","message":"Invalid token"}`, ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(tt.body))
+			})
+
+			_, err := client.Validate(context.Background(), "token")
+
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+// ==================== Introspect Tests ====================
+
+func TestIntrospect_UsesClientCredentials(t *testing.T) {
+	// Arrange
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		clientID, secret, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "orders-service", clientID)
+		assert.Equal(t, "secret", secret)
+		assert.Equal(t, "user-token", r.PostFormValue("token"))
+		_, _ = w.Write([]byte(`{"active":true,"sub":"user-1","role":"admin","scope":"order.read order.write"}`))
+	})
+
+	// Act
+	result, err := client.Introspect(context.Background(), "user-token")
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, result.Active)
+	assert.Equal(t, "admin", result.Role)
+}
+
+func TestIntrospect_InvalidClient(t *testing.T) {
+	// Arrange
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+	})
+
+	// Act
+	_, err := client.Introspect(context.Background(), "token")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidClient)
+}
@@ -0,0 +1,68 @@
+package clients
+
+import (
+	"sync"
+	"time"
+
+	"augustberries/pkg/metrics"
+)
+
+// breaker размыкает цепь после threshold неудачных запросов подряд
+// Через cooldown пропускается один пробный запрос: успех замыкает цепь, ошибка размыкает снова
+type breaker struct {
+	target    string
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu          sync.Mutex
+	failures    int
+	openedUntil time.Time
+	probing     bool
+}
+
+func newBreaker(target string, threshold int, cooldown time.Duration) *breaker {
+	return &breaker{target: target, threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// allow возвращает ErrCircuitOpen, пока цепь разомкнута или идет пробный запрос
+func (b *breaker) allow() error {
+	if b.threshold <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return nil
+	}
+	if b.probing || b.now().Before(b.openedUntil) {
+		return ErrCircuitOpen
+	}
+	b.probing = true
+	return nil
+}
+
+// record учитывает результат запроса, пропущенного allow
+func (b *breaker) record(ok bool) {
+	if b.threshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if ok {
+		if b.failures >= b.threshold {
+			metrics.ServiceClientCircuitOpen.WithLabelValues(b.target).Set(0)
+		}
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openedUntil = b.now().Add(b.cooldown)
+		metrics.ServiceClientCircuitOpen.WithLabelValues(b.target).Set(1)
+	}
+}
@@ -0,0 +1,173 @@
+// Package catalog - клиент Catalog Service: товары, ценовые котировки и оценки товаров (внутренний API)
+package catalog
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"augustberries/pkg/clients"
+	"augustberries/pkg/money"
+	"augustberries/pkg/quote"
+	"augustberries/pkg/units"
+
+	"github.com/google/uuid"
+)
+
+// productArchivedCode - код ошибки каталога для снятого с продажи товара
+const productArchivedCode = "PRODUCT_ARCHIVED"
+
+var (
+	// ErrProductNotFound - товара нет в каталоге магазина или он скрыт от вызывающего
+	ErrProductNotFound = errors.New("product not found")
+	// ErrProductArchived - товар снят с продажи
+	ErrProductArchived = errors.New("product archived")
+	// ErrProductNotAvailable - товар не опубликован или закончился
+	ErrProductNotAvailable = errors.New("product not available")
+)
+
+// ProductStatusPublished - статус товара, доступного для заказа
+const ProductStatusPublished = "published"
+
+// Category - категория товара
+type Category struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+// Product - товар каталога
+type Product struct {
+	ID           uuid.UUID      `json:"id"`
+	Name         string         `json:"name"`
+	Slug         string         `json:"slug"`
+	Description  string         `json:"description"`
+	Price        money.Amount   `json:"price"`
+	CategoryID   uuid.UUID      `json:"category_id"`
+	Category     *Category      `json:"category,omitempty"`
+	Status       string         `json:"status"`          // draft, published, archived
+	Stock        *int           `json:"stock,omitempty"` // nil - остаток не отслеживается
+	Unit         units.Unit     `json:"unit"`            // Единица измерения; пусто у каталога до единиц - штуки
+	QuantityStep units.Quantity `json:"quantity_step"`   // Шаг количества в заказе; 0 - одна единица
+	// Ограничения количества в заказе; nil - без ограничения
+	MinQty           *units.Quantity `json:"min_qty,omitempty"`
+	MaxQty           *units.Quantity `json:"max_qty,omitempty"`
+	PerCustomerLimit *units.Quantity `json:"per_customer_limit,omitempty"` // Всего на покупателя по его заказам
+	RatingAvg        float64         `json:"rating_avg"`
+	RatingCount      int             `json:"rating_count"`
+}
+
+// ProductBatch - товары по списку ID; Missing - ненайденные и скрытые от вызывающего товары
+type ProductBatch struct {
+	Products map[uuid.UUID]Product `json:"products"`
+	Missing  []uuid.UUID           `json:"missing"`
+}
+
+// QuoteItem - позиция запроса котировки
+type QuoteItem struct {
+	ProductID uuid.UUID      `json:"product_id"`
+	Quantity  units.Quantity `json:"quantity"`
+}
+
+// Quote - подписанная котировка цен; Token передается в Orders Service при оформлении заказа
+type Quote struct {
+	Token     string       `json:"token"`
+	Items     []quote.Item `json:"items"`
+	Currency  string       `json:"currency"`
+	ExpiresAt time.Time    `json:"expires_at"`
+}
+
+// ProductRating - оценка товара по отзывам (внутренний API /internal/products/ratings)
+type ProductRating struct {
+	ProductID   uuid.UUID `json:"product_id"`
+	RatingAvg   float64   `json:"rating_avg"`
+	RatingCount int       `json:"rating_count"`
+}
+
+// Client - клиент Catalog Service
+// Оценки товаров требуют Config.InternalToken (INTERNAL_API_TOKEN каталога)
+type Client struct {
+	base *clients.Client
+}
+
+func New(cfg clients.Config) *Client {
+	return &Client{base: clients.New("catalog", cfg)}
+}
+
+// GetProduct возвращает товар по ID (GET /products/:id)
+func (c *Client) GetProduct(ctx context.Context, id uuid.UUID) (*Product, error) {
+	var product Product
+	err := c.base.Do(ctx, clients.Request{Method: http.MethodGet, Path: "/products/" + id.String()}, &product)
+	if clients.HasStatus(err, http.StatusNotFound) {
+		return nil, ErrProductNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// GetProducts возвращает товары одним запросом GET /products/batch (до 100 ID)
+func (c *Client) GetProducts(ctx context.Context, ids []uuid.UUID) (*ProductBatch, error) {
+	raw := make([]string, len(ids))
+	for i, id := range ids {
+		raw[i] = id.String()
+	}
+
+	var batch ProductBatch
+	err := c.base.Do(ctx, clients.Request{
+		Method: http.MethodGet,
+		Path:   "/products/batch",
+		Query:  url.Values{"ids": {strings.Join(raw, ",")}},
+	}, &batch)
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// CreateQuote подтверждает цены и наличие товаров (POST /products/quotes)
+// Котировка ничего не резервирует, поэтому запрос повторяется при временных ошибках
+func (c *Client) CreateQuote(ctx context.Context, items []QuoteItem) (*Quote, error) {
+	var result Quote
+	err := c.base.Do(ctx, clients.Request{
+		Method:     http.MethodPost,
+		Path:       "/products/quotes",
+		Body:       map[string]interface{}{"items": items},
+		Idempotent: true,
+	}, &result)
+	switch {
+	case err == nil:
+		return &result, nil
+	case clients.HasStatus(err, http.StatusNotFound):
+		return nil, ErrProductNotFound
+	case clients.HasCode(err, productArchivedCode):
+		return nil, ErrProductArchived
+	case clients.HasStatus(err, http.StatusConflict):
+		return nil, ErrProductNotAvailable
+	default:
+		return nil, err
+	}
+}
+
+// GetProductRatings возвращает оценки всех товаров магазина из каталога
+func (c *Client) GetProductRatings(ctx context.Context) ([]ProductRating, error) {
+	var result struct {
+		Ratings []ProductRating `json:"ratings"`
+	}
+	if err := c.base.Do(ctx, clients.Request{Method: http.MethodGet, Path: "/internal/products/ratings"}, &result); err != nil {
+		return nil, err
+	}
+	return result.Ratings, nil
+}
+
+// UpdateProductRatings записывает оценки товаров в каталог
+func (c *Client) UpdateProductRatings(ctx context.Context, ratings []ProductRating) error {
+	return c.base.Do(ctx, clients.Request{
+		Method: http.MethodPut,
+		Path:   "/internal/products/ratings",
+		Body:   map[string]interface{}{"ratings": ratings},
+	}, nil)
+}
@@ -0,0 +1,133 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"augustberries/pkg/clients"
+	"augustberries/pkg/money"
+	"augustberries/pkg/units"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	cfg := clients.DefaultConfig(server.URL)
+	cfg.RetryBackoff = time.Millisecond
+	cfg.InternalToken = "internal"
+	return New(cfg)
+}
+
+// ==================== GetProducts Tests ====================
+
+func TestGetProducts(t *testing.T) {
+	// Arrange
+	found, missing := uuid.New(), uuid.New()
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/products/batch", r.URL.Path)
+		assert.Equal(t, found.String()+","+missing.String(), r.URL.Query().Get("ids"))
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"products": map[uuid.UUID]interface{}{
+				found: map[string]interface{}{"id": found, "name": "Berry box", "price": "10.50", "status": "published", "stock": 3},
+			},
+			"missing": []uuid.UUID{missing},
+		})
+	})
+
+	// Act
+	batch, err := client.GetProducts(context.Background(), []uuid.UUID{found, missing})
+
+	// Assert
+	require.NoError(t, err)
+	require.Contains(t, batch.Products, found)
+	assert.Equal(t, money.MustParse("10.50"), batch.Products[found].Price)
+	assert.Equal(t, 3, *batch.Products[found].Stock)
+	assert.Equal(t, []uuid.UUID{missing}, batch.Missing)
+}
+
+// ==================== CreateQuote Tests ====================
+
+func TestCreateQuote_MapsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{"not found", http.StatusNotFound, `{"error":"Product not found"}`, ErrProductNotFound},
+		{"archived", http.StatusConflict, `{"error":"Product archived","code":"PRODUCT_ARCHIVED"}`, ErrProductArchived},
+		{"not available", http.StatusConflict, `{"error":"Product not available"}`, ErrProductNotAvailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+
+			_, err := client.CreateQuote(context.Background(), []QuoteItem{{ProductID: uuid.New(), Quantity: units.Of(1)}})
+
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestCreateQuote_RetriesServerErrors(t *testing.T) {
+	// Arrange
+	attempts := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var body struct {
+			Items []QuoteItem `json:"items"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Len(t, body.Items, 1)
+		_, _ = w.Write([]byte(`{"token":"signed","currency":"USD"}`))
+	})
+
+	// Act
+	quote, err := client.CreateQuote(context.Background(), []QuoteItem{{ProductID: uuid.New(), Quantity: units.Of(2)}})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "signed", quote.Token)
+	assert.Equal(t, 2, attempts)
+}
+
+// ==================== Ratings Tests ====================
+
+func TestUpdateProductRatings_SendsInternalToken(t *testing.T) {
+	// Arrange
+	productID := uuid.New()
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/internal/products/ratings", r.URL.Path)
+		assert.Equal(t, "internal", r.Header.Get(clients.InternalTokenHeader))
+		var body struct {
+			Ratings []ProductRating `json:"ratings"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, []ProductRating{{ProductID: productID, RatingAvg: 4.5, RatingCount: 2}}, body.Ratings)
+		_, _ = w.Write([]byte(`{"updated":1}`))
+	})
+
+	// Act
+	err := client.UpdateProductRatings(context.Background(), []ProductRating{{ProductID: productID, RatingAvg: 4.5, RatingCount: 2}})
+
+	// Assert
+	assert.NoError(t, err)
+}
@@ -0,0 +1,251 @@
+// Package clients - общая основа типизированных клиентов внутренних сервисов (подпакеты auth, catalog, orders, reviews, worker)
+// Client повторяет идемпотентные запросы при временных ошибках, размыкает цепь при недоступности сервиса
+// и передает в запросе магазин, токен и trace context из контекста
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"augustberries/pkg/kafka"
+	"augustberries/pkg/metrics"
+	"augustberries/pkg/tenant"
+)
+
+// InternalTokenHeader - заголовок с токеном внутреннего API (/internal/...)
+const InternalTokenHeader = "X-Internal-Token"
+
+// Config - настройки клиента внутреннего сервиса
+type Config struct {
+	BaseURL string
+
+	Timeout      time.Duration // Таймаут одной попытки
+	MaxAttempts  int           // Попыток на запрос, включая первую; повторяются только идемпотентные запросы
+	RetryBackoff time.Duration // Пауза перед второй попыткой, дальше удваивается
+
+	BreakerThreshold int           // Неудачных запросов подряд до размыкания цепи, 0 - без circuit breaker
+	BreakerCooldown  time.Duration // Сколько запросы отклоняются без обращения к сервису после размыкания
+
+	AuthToken     string // Bearer токен по умолчанию; WithBearerToken заменяет его для отдельного запроса
+	InternalToken string // Токен внутреннего API, передается в X-Internal-Token
+}
+
+// DefaultConfig возвращает настройки по умолчанию: 3 попытки, размыкание после 5 ошибок подряд на 30 секунд
+func DefaultConfig(baseURL string) Config {
+	return Config{
+		BaseURL:          baseURL,
+		Timeout:          10 * time.Second,
+		MaxAttempts:      3,
+		RetryBackoff:     200 * time.Millisecond,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+	}
+}
+
+// Request - запрос к сервису
+type Request struct {
+	Method string
+	Path   string // Путь относительно BaseURL, сегменты экранирует вызывающий
+	Query  url.Values
+	Body   any        // Тело в JSON; nil - без тела
+	Form   url.Values // Тело application/x-www-form-urlencoded вместо Body
+	Header http.Header
+
+	// Idempotent разрешает повтор POST и PATCH; GET, HEAD, PUT и DELETE повторяются всегда
+	Idempotent bool
+}
+
+// Client выполняет запросы к одному внутреннему сервису
+type Client struct {
+	target  string // Имя сервиса в метриках и ошибках
+	baseURL string
+	cfg     Config
+	http    *http.Client
+	breaker *breaker
+}
+
+// New создает клиент сервиса target (auth, catalog, orders, reviews, worker)
+func New(target string, cfg Config) *Client {
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	return &Client{
+		target:  target,
+		baseURL: strings.TrimRight(cfg.BaseURL, "/"),
+		cfg:     cfg,
+		http:    &http.Client{Timeout: cfg.Timeout},
+		breaker: newBreaker(target, cfg.BreakerThreshold, cfg.BreakerCooldown),
+	}
+}
+
+// Do выполняет запрос и декодирует JSON ответа 2xx в out (nil - ответ не читается)
+// Ответ с другим статусом возвращается *StatusError; при разомкнутой цепи - ErrCircuitOpen
+func (c *Client) Do(ctx context.Context, req Request, out any) error {
+	if err := c.breaker.allow(); err != nil {
+		metrics.ServiceClientRequests.WithLabelValues(c.target, "circuit_open").Inc()
+		return fmt.Errorf("%s: %w", c.target, err)
+	}
+
+	body, contentType, err := encodeBody(req)
+	if err != nil {
+		return err
+	}
+
+	retryable := req.Idempotent || idempotentMethod(req.Method)
+	backoff := c.cfg.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		err = c.attempt(ctx, req, body, contentType, out)
+		if err == nil || !retryable || !temporary(err) || attempt >= c.cfg.MaxAttempts || ctx.Err() != nil {
+			break
+		}
+
+		metrics.ServiceClientRetries.WithLabelValues(c.target).Inc()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	outcome := outcomeOf(err)
+	metrics.ServiceClientRequests.WithLabelValues(c.target, outcome).Inc()
+	// Ошибки клиента (4xx) и отмена запроса вызывающим не говорят о недоступности сервиса
+	if ctx.Err() == nil {
+		c.breaker.record(outcome != "server_error" && outcome != "network_error")
+	}
+	return err
+}
+
+// attempt отправляет запрос один раз
+func (c *Client) attempt(ctx context.Context, req Request, body []byte, contentType string, out any) error {
+	endpoint := c.baseURL + req.Path
+	if len(req.Query) > 0 {
+		endpoint += "?" + req.Query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", c.target, err)
+	}
+	c.setHeaders(ctx, httpReq, req.Header, contentType)
+
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return &networkError{target: c.target, err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newStatusError(c.target, resp)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", c.target, err)
+	}
+	return nil
+}
+
+// setHeaders передает магазин, токены и trace context; заголовки запроса имеют приоритет
+func (c *Client) setHeaders(ctx context.Context, httpReq *http.Request, header http.Header, contentType string) {
+	httpReq.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+	httpReq.Header.Set(tenant.Header, tenant.FromContext(ctx))
+	httpReq.Header.Set(kafka.HeaderTraceParent, kafka.ChildTraceParent(TraceParentFromContext(ctx)))
+
+	token := c.cfg.AuthToken
+	if fromCtx := bearerTokenFromContext(ctx); fromCtx != "" {
+		token = fromCtx
+	}
+	if token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+	if c.cfg.InternalToken != "" {
+		httpReq.Header.Set(InternalTokenHeader, c.cfg.InternalToken)
+	}
+
+	for key, values := range header {
+		httpReq.Header[http.CanonicalHeaderKey(key)] = values
+	}
+}
+
+func encodeBody(req Request) ([]byte, string, error) {
+	switch {
+	case req.Form != nil:
+		return []byte(req.Form.Encode()), "application/x-www-form-urlencoded", nil
+	case req.Body != nil:
+		body, err := json.Marshal(req.Body)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to marshal request: %w", err)
+		}
+		return body, "application/json", nil
+	default:
+		return nil, "", nil
+	}
+}
+
+func idempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// temporary - ошибка, после которой запрос имеет смысл повторить: сеть, 5xx и 429
+func temporary(err error) bool {
+	var netErr *networkError
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500 || statusErr.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+func outcomeOf(err error) string {
+	var statusErr *StatusError
+	var netErr *networkError
+	switch {
+	case err == nil:
+		return "success"
+	case errors.As(err, &statusErr) && statusErr.StatusCode >= 500:
+		return "server_error"
+	case errors.As(err, &statusErr):
+		return "client_error"
+	case errors.As(err, &netErr):
+		return "network_error"
+	default:
+		return "client_error" // Ответ 2xx не удалось декодировать
+	}
+}
+
+// networkError - запрос не дошел до сервиса или ответ не получен (в том числе по таймауту)
+type networkError struct {
+	target string
+	err    error
+}
+
+func (e *networkError) Error() string {
+	return fmt.Sprintf("failed to call %s service: %v", e.target, e.err)
+}
+
+func (e *networkError) Unwrap() error { return e.err }
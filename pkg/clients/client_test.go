@@ -0,0 +1,207 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"augustberries/pkg/kafka"
+	"augustberries/pkg/tenant"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig(url string) Config {
+	cfg := DefaultConfig(url)
+	cfg.RetryBackoff = time.Millisecond
+	return cfg
+}
+
+// flakyServer отвечает status первые failures запросов, затем 200 с {"ok": true}
+func flakyServer(t *testing.T, failures int32, status int) (*httptest.Server, *int32) {
+	t.Helper()
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= failures {
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"error":"Temporary failure","code":"TEMPORARY"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// ==================== Do Tests ====================
+
+func TestDo_SendsTenantTokenAndTraceContext(t *testing.T) {
+	// Arrange
+	parent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/products/batch", r.URL.Path)
+		assert.Equal(t, "a,b", r.URL.Query().Get("ids"))
+		assert.Equal(t, "shop-a", r.Header.Get(tenant.Header))
+		assert.Equal(t, "Bearer user-token", r.Header.Get("Authorization"))
+		assert.Equal(t, "internal", r.Header.Get(InternalTokenHeader))
+		// Trace продолжается новым span
+		traceParent := r.Header.Get(kafka.HeaderTraceParent)
+		assert.Equal(t, parent[:35], traceParent[:35])
+		assert.NotEqual(t, parent, traceParent)
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	}))
+	defer server.Close()
+
+	cfg := testConfig(server.URL + "/")
+	cfg.AuthToken = "service-token"
+	cfg.InternalToken = "internal"
+	client := New("catalog", cfg)
+
+	ctx := WithTraceParent(WithBearerToken(tenant.WithID(context.Background(), "shop-a"), "user-token"), parent)
+
+	// Act
+	var out struct {
+		OK bool `json:"ok"`
+	}
+	err := client.Do(ctx, Request{Method: http.MethodGet, Path: "/products/batch", Query: map[string][]string{"ids": {"a,b"}}}, &out)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, out.OK)
+}
+
+func TestDo_RetriesIdempotentRequests(t *testing.T) {
+	// Arrange
+	server, requests := flakyServer(t, 2, http.StatusServiceUnavailable)
+	client := New("catalog", testConfig(server.URL))
+
+	// Act
+	err := client.Do(context.Background(), Request{Method: http.MethodGet, Path: "/products"}, nil)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(requests))
+}
+
+func TestDo_DoesNotRetryNonIdempotentRequests(t *testing.T) {
+	// Arrange
+	server, requests := flakyServer(t, 1, http.StatusBadGateway)
+	client := New("orders", testConfig(server.URL))
+
+	// Act
+	err := client.Do(context.Background(), Request{Method: http.MethodPatch, Path: "/orders/1", Body: map[string]string{"status": "shipped"}}, nil)
+
+	// Assert
+	assert.True(t, HasStatus(err, http.StatusBadGateway))
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+}
+
+func TestDo_ClientErrorIsNotRetried(t *testing.T) {
+	// Arrange
+	server, requests := flakyServer(t, 1, http.StatusConflict)
+	client := New("catalog", testConfig(server.URL))
+
+	// Act
+	err := client.Do(context.Background(), Request{Method: http.MethodGet, Path: "/products"}, nil)
+
+	// Assert
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusConflict, statusErr.StatusCode)
+	assert.Equal(t, "TEMPORARY", statusErr.Code)
+	assert.Equal(t, "Temporary failure", statusErr.Message)
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+}
+
+func TestDo_TimeoutIsRetried(t *testing.T) {
+	// Arrange
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	cfg := testConfig(server.URL)
+	cfg.Timeout = 50 * time.Millisecond
+	client := New("reviews", cfg)
+
+	// Act
+	err := client.Do(context.Background(), Request{Method: http.MethodGet, Path: "/reviews"}, nil)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
+
+// ==================== Circuit Breaker Tests ====================
+
+func TestDo_CircuitOpensAfterConsecutiveFailures(t *testing.T) {
+	// Arrange
+	server, requests := flakyServer(t, 2, http.StatusInternalServerError)
+	cfg := testConfig(server.URL)
+	cfg.MaxAttempts = 1
+	cfg.BreakerThreshold = 2
+	cfg.BreakerCooldown = time.Minute
+	client := New("auth", cfg)
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	client.breaker.now = func() time.Time { return now }
+	ctx := context.Background()
+	req := Request{Method: http.MethodGet, Path: "/auth/me"}
+
+	// Act & Assert: две ошибки размыкают цепь
+	assert.Error(t, client.Do(ctx, req, nil))
+	assert.Error(t, client.Do(ctx, req, nil))
+	assert.ErrorIs(t, client.Do(ctx, req, nil), ErrCircuitOpen)
+	assert.Equal(t, int32(2), atomic.LoadInt32(requests))
+
+	// После cooldown пробный запрос проходит и замыкает цепь
+	now = now.Add(time.Minute)
+	require.NoError(t, client.Do(ctx, req, nil))
+	require.NoError(t, client.Do(ctx, req, nil))
+	assert.Equal(t, int32(4), atomic.LoadInt32(requests))
+}
+
+func TestBreaker_FailedProbeReopens(t *testing.T) {
+	// Arrange
+	b := newBreaker("catalog", 1, time.Minute)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b.now = func() time.Time { return now }
+
+	require.NoError(t, b.allow())
+	b.record(false)
+	now = now.Add(time.Minute)
+
+	// Act: пробный запрос пропускается один, его ошибка снова размыкает цепь
+	require.NoError(t, b.allow())
+	concurrent := b.allow()
+	b.record(false)
+
+	// Assert
+	assert.True(t, errors.Is(concurrent, ErrCircuitOpen))
+	assert.ErrorIs(t, b.allow(), ErrCircuitOpen)
+}
+
+func TestBreaker_ClientErrorsDoNotOpen(t *testing.T) {
+	// Arrange
+	server, _ := flakyServer(t, 10, http.StatusNotFound)
+	cfg := testConfig(server.URL)
+	cfg.BreakerThreshold = 1
+	client := New("catalog", cfg)
+
+	// Act
+	for i := 0; i < 3; i++ {
+		assert.True(t, HasStatus(client.Do(context.Background(), Request{Method: http.MethodGet, Path: "/products/x"}, nil), http.StatusNotFound))
+	}
+
+	// Assert
+	assert.NoError(t, client.breaker.allow())
+}
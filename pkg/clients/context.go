@@ -0,0 +1,36 @@
+package clients
+
+import (
+	"context"
+
+	"augustberries/pkg/kafka"
+)
+
+type bearerTokenKey struct{}
+
+type traceParentKey struct{}
+
+// WithBearerToken задает токен для запросов с этим контекстом вместо Config.AuthToken
+// Используется, когда сервис обращается к другому от имени пользователя
+func WithBearerToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, bearerTokenKey{}, token)
+}
+
+func bearerTokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(bearerTokenKey{}).(string)
+	return token
+}
+
+// WithTraceParent задает trace context (W3C traceparent), который продолжают исходящие запросы
+func WithTraceParent(ctx context.Context, traceParent string) context.Context {
+	return context.WithValue(ctx, traceParentKey{}, traceParent)
+}
+
+// TraceParentFromContext возвращает traceparent из WithTraceParent или заголовков обрабатываемого сообщения Kafka
+// Пустая строка - запрос начинает новую трассу
+func TraceParentFromContext(ctx context.Context) string {
+	if traceParent, ok := ctx.Value(traceParentKey{}).(string); ok && traceParent != "" {
+		return traceParent
+	}
+	return kafka.HeadersFromContext(ctx)[kafka.HeaderTraceParent]
+}
@@ -0,0 +1,66 @@
+package clients
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrCircuitOpen - сервис недавно был недоступен, запрос отклонен без обращения к нему
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// StatusError - сервис ответил статусом вне 2xx
+// Code и Message берутся из тела ответа {"error", "code", "message"}, если оно в JSON
+type StatusError struct {
+	Target     string
+	StatusCode int
+	Code       string // Машиночитаемый код ошибки (PRODUCT_ARCHIVED, ORDER_STATUS_CONFLICT), может быть пустым
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s service returned status %d", e.Target, e.StatusCode)
+	}
+	return fmt.Sprintf("%s service returned status %d: %s", e.Target, e.StatusCode, e.Message)
+}
+
+// HasStatus сообщает, что err - ответ сервиса со статусом status
+func HasStatus(err error, status int) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == status
+}
+
+// HasCode сообщает, что err - ответ сервиса с кодом ошибки code
+func HasCode(err error, code string) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.Code == code
+}
+
+func newStatusError(target string, resp *http.Response) *StatusError {
+	statusErr := &StatusError{Target: target, StatusCode: resp.StatusCode}
+
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var body struct {
+		Error   string `json:"error"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(raw, &body) == nil {
+		statusErr.Code = body.Code
+		statusErr.Message = body.Error
+		if body.Message != "" {
+			statusErr.Message = body.Message
+		}
+		return statusErr
+	}
+
+	statusErr.Message = strings.TrimSpace(string(raw))
+	if len(statusErr.Message) > 512 {
+		statusErr.Message = statusErr.Message[:512]
+	}
+	return statusErr
+}
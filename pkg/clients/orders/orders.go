@@ -0,0 +1,78 @@
+// Package orders - клиент Orders Service: смена статуса заказа
+package orders
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"augustberries/pkg/clients"
+
+	"github.com/google/uuid"
+)
+
+// statusConflictCode - код ошибки Orders Service при параллельной смене статуса
+const statusConflictCode = "ORDER_STATUS_CONFLICT"
+
+var (
+	// ErrOrderNotFound - заказа нет в магазине
+	ErrOrderNotFound = errors.New("order not found")
+	// ErrAccessDenied - токен не дает права менять статус заказа
+	ErrAccessDenied = errors.New("access denied")
+	// ErrInvalidTransition - переход в статус недопустим (или статус неизвестен)
+	ErrInvalidTransition = errors.New("invalid status transition")
+	// ErrStatusConflict - статус заказа одновременно изменен другим запросом, переход нужно проверить заново
+	ErrStatusConflict = errors.New("order status was changed by another request")
+)
+
+// Status - статус заказа
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusConfirmed Status = "confirmed"
+	StatusShipped   Status = "shipped"
+	StatusDelivered Status = "delivered"
+	StatusCancelled Status = "cancelled"
+)
+
+// StatusUpdate - результат смены статуса
+type StatusUpdate struct {
+	ID     uuid.UUID `json:"id"`
+	Status Status    `json:"status"`
+}
+
+// Client - клиент Orders Service
+// Статус меняется от имени токена (Config.AuthToken или clients.WithBearerToken) с ролью manager или admin
+type Client struct {
+	base *clients.Client
+}
+
+func New(cfg clients.Config) *Client {
+	return &Client{base: clients.New("orders", cfg)}
+}
+
+// UpdateStatus переводит заказ в статус status (PATCH /orders/:id)
+// Повторная смена статуса не идемпотентна, поэтому запрос не повторяется
+func (c *Client) UpdateStatus(ctx context.Context, orderID uuid.UUID, status Status) (*StatusUpdate, error) {
+	var result StatusUpdate
+	err := c.base.Do(ctx, clients.Request{
+		Method: http.MethodPatch,
+		Path:   "/orders/" + orderID.String(),
+		Body:   map[string]Status{"status": status},
+	}, &result)
+	switch {
+	case err == nil:
+		return &result, nil
+	case clients.HasStatus(err, http.StatusNotFound):
+		return nil, ErrOrderNotFound
+	case clients.HasStatus(err, http.StatusForbidden):
+		return nil, ErrAccessDenied
+	case clients.HasStatus(err, http.StatusBadRequest):
+		return nil, ErrInvalidTransition
+	case clients.HasCode(err, statusConflictCode):
+		return nil, ErrStatusConflict
+	default:
+		return nil, err
+	}
+}
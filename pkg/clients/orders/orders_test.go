@@ -0,0 +1,67 @@
+package orders
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"augustberries/pkg/clients"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ==================== UpdateStatus Tests ====================
+
+func TestUpdateStatus(t *testing.T) {
+	// Arrange
+	orderID := uuid.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "/orders/"+orderID.String(), r.URL.Path)
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "shipped", body["status"])
+		_, _ = w.Write([]byte(`{"id":"` + orderID.String() + `","status":"shipped"}`))
+	}))
+	defer server.Close()
+	client := New(clients.DefaultConfig(server.URL))
+
+	// Act
+	result, err := client.UpdateStatus(context.Background(), orderID, StatusShipped)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, StatusShipped, result.Status)
+}
+
+func TestUpdateStatus_MapsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{"not found", http.StatusNotFound, `{"error":"Order not found"}`, ErrOrderNotFound},
+		{"forbidden", http.StatusForbidden, `{"error":"Access denied"}`, ErrAccessDenied},
+		{"transition", http.StatusBadRequest, `{"error":"Invalid status transition"}`, ErrInvalidTransition},
+		{"conflict", http.StatusConflict, `{"error":"Order status was changed by another request","code":"ORDER_STATUS_CONFLICT"}`, ErrStatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			_, err := New(clients.DefaultConfig(server.URL)).UpdateStatus(context.Background(), uuid.New(), StatusCancelled)
+
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
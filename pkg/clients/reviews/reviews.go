@@ -0,0 +1,51 @@
+// Package reviews - клиент Reviews Service: сводка оценок товара
+package reviews
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"augustberries/pkg/clients"
+)
+
+// SentimentSummary - тональность отзывов товара; отзывы без оценки тональности не учитываются
+type SentimentSummary struct {
+	Analyzed     int     `json:"analyzed"`
+	AverageScore float64 `json:"average_score"` // От -1 до 1
+	Positive     int     `json:"positive"`
+	Neutral      int     `json:"neutral"`
+	Negative     int     `json:"negative"`
+}
+
+// RatingSummary - сводка оценок опубликованных отзывов товара
+type RatingSummary struct {
+	ProductID    string           `json:"product_id"`
+	Count        int              `json:"count"`
+	Average      float64          `json:"average"`      // 0 - отзывов нет
+	Distribution map[int]int      `json:"distribution"` // Число отзывов по оценкам 1-5
+	Sentiment    SentimentSummary `json:"sentiment"`
+}
+
+// Client - клиент Reviews Service
+type Client struct {
+	base *clients.Client
+}
+
+func New(cfg clients.Config) *Client {
+	return &Client{base: clients.New("reviews", cfg)}
+}
+
+// GetRatingSummary возвращает сводку оценок товара (GET /reviews/product/:product_id/summary)
+// Для товара без отзывов возвращается пустая сводка, а не ошибка
+func (c *Client) GetRatingSummary(ctx context.Context, productID string) (*RatingSummary, error) {
+	var summary RatingSummary
+	err := c.base.Do(ctx, clients.Request{
+		Method: http.MethodGet,
+		Path:   "/reviews/product/" + url.PathEscape(productID) + "/summary",
+	}, &summary)
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
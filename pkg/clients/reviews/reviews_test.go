@@ -0,0 +1,34 @@
+package reviews
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"augustberries/pkg/clients"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ==================== GetRatingSummary Tests ====================
+
+func TestGetRatingSummary(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/reviews/product/product-1/summary", r.URL.Path)
+		_, _ = w.Write([]byte(`{"product_id":"product-1","count":3,"average":4.33,"distribution":{"1":0,"2":0,"3":0,"4":2,"5":1},` +
+			`"sentiment":{"analyzed":2,"average_score":0.4,"positive":1,"neutral":1,"negative":0}}`))
+	}))
+	defer server.Close()
+
+	// Act
+	summary, err := New(clients.DefaultConfig(server.URL)).GetRatingSummary(context.Background(), "product-1")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 3, summary.Count)
+	assert.Equal(t, 2, summary.Distribution[4])
+	assert.Equal(t, 1, summary.Sentiment.Positive)
+}
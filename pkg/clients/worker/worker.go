@@ -0,0 +1,39 @@
+// Package worker - клиент Background Worker Service: курсы валют для конвертации заказов
+package worker
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"augustberries/pkg/clients"
+)
+
+// Rate - курс пары: сумма в From умножается на Rate, чтобы получить сумму в To
+type Rate struct {
+	From string  `json:"from"`
+	To   string  `json:"to"`
+	Rate float64 `json:"rate"`
+}
+
+// Client - клиент Background Worker Service
+type Client struct {
+	base *clients.Client
+}
+
+func New(cfg clients.Config) *Client {
+	return &Client{base: clients.New("worker", cfg)}
+}
+
+// GetRate возвращает курс, по которому worker конвертирует заказы из from в to (GET /rates/:from/:to)
+func (c *Client) GetRate(ctx context.Context, from, to string) (*Rate, error) {
+	var rate Rate
+	err := c.base.Do(ctx, clients.Request{
+		Method: http.MethodGet,
+		Path:   "/rates/" + url.PathEscape(from) + "/" + url.PathEscape(to),
+	}, &rate)
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
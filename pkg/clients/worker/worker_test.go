@@ -0,0 +1,31 @@
+package worker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"augustberries/pkg/clients"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ==================== GetRate Tests ====================
+
+func TestGetRate(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rates/USD/EUR", r.URL.Path)
+		_, _ = w.Write([]byte(`{"from":"USD","to":"EUR","rate":0.92}`))
+	}))
+	defer server.Close()
+
+	// Act
+	rate, err := New(clients.DefaultConfig(server.URL)).GetRate(context.Background(), "USD", "EUR")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 0.92, rate.Rate)
+}
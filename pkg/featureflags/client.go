@@ -0,0 +1,112 @@
+package featureflags
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+type cacheEntry struct {
+	flag    *Flag // nil - флаг не существует
+	expires time.Time
+}
+
+// Client вычисляет флаги с кешированием в памяти процесса
+// Флаги проверяются на каждом запросе, поэтому хранилище опрашивается не чаще раза в ttl на ключ
+// Изменения через Client (admin API) видны в этом процессе сразу, в остальных - через ttl
+type Client struct {
+	store Store
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu    sync.RWMutex
+	cache map[string]cacheEntry
+}
+
+// NewClient создает клиент флагов
+func NewClient(store Store, ttl time.Duration) *Client {
+	return &Client{
+		store: store,
+		ttl:   ttl,
+		now:   time.Now,
+		cache: make(map[string]cacheEntry),
+	}
+}
+
+// Enabled сообщает, включен ли флаг для субъекта (обычно ID пользователя)
+// fallback возвращается, если флаг не заведен или хранилище недоступно:
+// для новой функциональности это false, для аварийного выключателя существующей - true
+// Nil-клиент всегда возвращает fallback
+func (c *Client) Enabled(ctx context.Context, key, subject string, fallback bool) bool {
+	if c == nil {
+		return fallback
+	}
+
+	flag, err := c.lookup(ctx, key)
+	if err != nil {
+		log.Printf("Feature flag %s: %v, using fallback %t", key, err, fallback)
+		return fallback
+	}
+	if flag == nil {
+		return fallback
+	}
+	return flag.EnabledFor(subject)
+}
+
+// List возвращает все флаги из хранилища
+func (c *Client) List(ctx context.Context) ([]Flag, error) {
+	return c.store.List(ctx)
+}
+
+// Get возвращает флаг из хранилища
+func (c *Client) Get(ctx context.Context, key string) (*Flag, error) {
+	return c.store.Get(ctx, key)
+}
+
+// Set создает или заменяет флаг и сбрасывает его кеш
+func (c *Client) Set(ctx context.Context, flag *Flag) error {
+	if err := c.store.Set(ctx, flag); err != nil {
+		return err
+	}
+	c.invalidate(flag.Key)
+	return nil
+}
+
+// Delete удаляет флаг и сбрасывает его кеш
+func (c *Client) Delete(ctx context.Context, key string) error {
+	if err := c.store.Delete(ctx, key); err != nil {
+		return err
+	}
+	c.invalidate(key)
+	return nil
+}
+
+func (c *Client) lookup(ctx context.Context, key string) (*Flag, error) {
+	now := c.now()
+
+	c.mu.RLock()
+	entry, ok := c.cache[key]
+	c.mu.RUnlock()
+	if ok && now.Before(entry.expires) {
+		return entry.flag, nil
+	}
+
+	flag, err := c.store.Get(ctx, key)
+	if err != nil && !errors.Is(err, ErrFlagNotFound) {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.cache[key] = cacheEntry{flag: flag, expires: now.Add(c.ttl)}
+	c.mu.Unlock()
+
+	return flag, nil
+}
+
+func (c *Client) invalidate(key string) {
+	c.mu.Lock()
+	delete(c.cache, key)
+	c.mu.Unlock()
+}
@@ -0,0 +1,142 @@
+package featureflags
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisStore(t *testing.T) Store {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewRedisStore(client)
+}
+
+// ====== Evaluation Tests ======
+
+func TestFlag_EnabledFor(t *testing.T) {
+	flag := &Flag{Key: "orders.quote_checkout", Enabled: true, Users: []string{"pilot"}}
+
+	assert.True(t, flag.EnabledFor("pilot"))
+	assert.False(t, flag.EnabledFor("someone"))
+
+	flag.Percentage = 100
+	assert.True(t, flag.EnabledFor("someone"))
+
+	flag.Enabled = false
+	assert.False(t, flag.EnabledFor("pilot"))
+}
+
+func TestFlag_EnabledFor_PercentageRollout(t *testing.T) {
+	flag := &Flag{Key: "orders.quote_checkout", Enabled: true, Percentage: 30}
+
+	enabled := 0
+	for i := 0; i < 1000; i++ {
+		if flag.EnabledFor(fmt.Sprintf("user-%d", i)) {
+			enabled++
+		}
+	}
+	assert.InDelta(t, 300, enabled, 60)
+
+	// Увеличение процента не выключает уже включенных пользователей
+	wider := &Flag{Key: flag.Key, Enabled: true, Percentage: 60}
+	for i := 0; i < 1000; i++ {
+		subject := fmt.Sprintf("user-%d", i)
+		if flag.EnabledFor(subject) {
+			assert.True(t, wider.EnabledFor(subject), subject)
+		}
+	}
+}
+
+// ====== Store Tests ======
+
+func TestRedisStore_CRUD(t *testing.T) {
+	ctx := context.Background()
+	store := newTestRedisStore(t)
+
+	_, err := store.Get(ctx, "missing")
+	assert.ErrorIs(t, err, ErrFlagNotFound)
+
+	require.NoError(t, store.Set(ctx, &Flag{Key: "b", Enabled: true, Percentage: 10}))
+	require.NoError(t, store.Set(ctx, &Flag{Key: "a", Users: []string{"u1"}}))
+
+	flag, err := store.Get(ctx, "b")
+	require.NoError(t, err)
+	assert.Equal(t, 10, flag.Percentage)
+
+	flags, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, flags, 2)
+	assert.Equal(t, "a", flags[0].Key)
+
+	require.NoError(t, store.Delete(ctx, "a"))
+	assert.ErrorIs(t, store.Delete(ctx, "a"), ErrFlagNotFound)
+
+	assert.ErrorIs(t, store.Set(ctx, &Flag{Key: "c", Percentage: 101}), ErrInvalidFlag)
+}
+
+// ====== Client Tests ======
+
+func TestClient_Enabled_Fallback(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient(newTestRedisStore(t), time.Minute)
+
+	assert.True(t, client.Enabled(ctx, "missing", "user", true))
+	assert.False(t, client.Enabled(ctx, "missing", "user", false))
+
+	var nilClient *Client
+	assert.True(t, nilClient.Enabled(ctx, "missing", "user", true))
+}
+
+func TestClient_Enabled_Cache(t *testing.T) {
+	ctx := context.Background()
+	store := newTestRedisStore(t)
+	client := NewClient(store, time.Minute)
+
+	require.NoError(t, client.Set(ctx, &Flag{Key: "f", Enabled: true, Percentage: 100}))
+	assert.True(t, client.Enabled(ctx, "f", "user", false))
+
+	// Изменение в обход клиента видно только после истечения ttl
+	require.NoError(t, store.Set(ctx, &Flag{Key: "f", Enabled: false}))
+	assert.True(t, client.Enabled(ctx, "f", "user", false))
+
+	client.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	assert.False(t, client.Enabled(ctx, "f", "user", false))
+}
+
+// ====== Handler Tests ======
+
+func TestHandler_SetAndGet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewHandler(NewClient(newTestRedisStore(t), time.Minute)).RegisterRoutes(router.Group("/admin/flags"))
+
+	body := []byte(`{"enabled":true,"percentage":25,"users":["pilot"]}`)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/flags/orders.quote_checkout", bytes.NewReader(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/flags/orders.quote_checkout", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"percentage":25`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/flags/x", bytes.NewReader([]byte(`{"percentage":150}`))))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/admin/flags/unknown", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
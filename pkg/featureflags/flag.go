@@ -0,0 +1,83 @@
+package featureflags
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"time"
+)
+
+var (
+	// ErrFlagNotFound - флаг с таким ключом не существует
+	ErrFlagNotFound = errors.New("feature flag not found")
+	// ErrInvalidFlag - некорректные параметры флага
+	ErrInvalidFlag = errors.New("invalid feature flag")
+)
+
+// Flag - переключатель функциональности с постепенной раскаткой
+// Enabled - глобальный выключатель: выключенный флаг не включается ни для кого
+// Users - пользователи, для которых флаг включен всегда (тестировщики, пилотные клиенты)
+// Percentage - доля остальных пользователей (0-100), для которых флаг включен
+type Flag struct {
+	Key         string    `json:"key" bson:"_id" gorm:"primaryKey;type:varchar(100)"`
+	Description string    `json:"description" bson:"description" gorm:"type:text;not null;default:''"`
+	Enabled     bool      `json:"enabled" bson:"enabled" gorm:"not null;default:false"`
+	Percentage  int       `json:"percentage" bson:"percentage" gorm:"not null;default:0"`
+	Users       []string  `json:"users" bson:"users" gorm:"type:jsonb;serializer:json;not null"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (Flag) TableName() string {
+	return "feature_flags"
+}
+
+// Validate проверяет параметры флага перед сохранением
+func (f *Flag) Validate() error {
+	if f.Key == "" || len(f.Key) > 100 {
+		return ErrInvalidFlag
+	}
+	if f.Percentage < 0 || f.Percentage > 100 {
+		return ErrInvalidFlag
+	}
+	return nil
+}
+
+// EnabledFor вычисляет флаг для субъекта (обычно ID пользователя)
+// Раскатка детерминирована: один и тот же субъект всегда попадает в одну и ту же группу,
+// а при увеличении Percentage включенные ранее субъекты остаются включенными
+func (f *Flag) EnabledFor(subject string) bool {
+	if !f.Enabled {
+		return false
+	}
+	for _, user := range f.Users {
+		if user == subject {
+			return true
+		}
+	}
+	if f.Percentage >= 100 {
+		return true
+	}
+	if f.Percentage <= 0 || subject == "" {
+		return false
+	}
+	return bucket(f.Key, subject) < f.Percentage
+}
+
+// bucket распределяет субъект по 100 корзинам
+// Ключ флага участвует в хеше, чтобы разные флаги раскатывались на разные группы пользователей
+func bucket(key, subject string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	h.Write([]byte{':'})
+	h.Write([]byte(subject))
+	return int(h.Sum32() % 100)
+}
+
+// Store - хранилище флагов
+type Store interface {
+	Get(ctx context.Context, key string) (*Flag, error)
+	List(ctx context.Context) ([]Flag, error)
+	Set(ctx context.Context, flag *Flag) error
+	Delete(ctx context.Context, key string) error
+}
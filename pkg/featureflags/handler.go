@@ -0,0 +1,102 @@
+package featureflags
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetFlagRequest - тело запроса PUT /admin/flags/:key
+type SetFlagRequest struct {
+	Description string   `json:"description"`
+	Enabled     bool     `json:"enabled"`
+	Percentage  int      `json:"percentage"`
+	Users       []string `json:"users"`
+}
+
+// Handler - admin API для просмотра и переключения флагов
+type Handler struct {
+	client *Client
+}
+
+// NewHandler создает admin API флагов
+func NewHandler(client *Client) *Handler {
+	return &Handler{client: client}
+}
+
+// RegisterRoutes регистрирует маршруты в группе (доступ к группе ограничивает вызывающий сервис)
+func (h *Handler) RegisterRoutes(group *gin.RouterGroup) {
+	group.GET("", h.List)
+	group.GET("/:key", h.Get)
+	group.PUT("/:key", h.Set)
+	group.DELETE("/:key", h.Delete)
+}
+
+// List обрабатывает GET /admin/flags
+func (h *Handler) List(c *gin.Context) {
+	flags, err := h.client.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list feature flags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"flags": flags, "total": len(flags)})
+}
+
+// Get обрабатывает GET /admin/flags/:key
+func (h *Handler) Get(c *gin.Context) {
+	flag, err := h.client.Get(c.Request.Context(), c.Param("key"))
+	if err != nil {
+		if errors.Is(err, ErrFlagNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Feature flag not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get feature flag"})
+		return
+	}
+
+	c.JSON(http.StatusOK, flag)
+}
+
+// Set обрабатывает PUT /admin/flags/:key - создает или полностью заменяет флаг
+func (h *Handler) Set(c *gin.Context) {
+	var req SetFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	flag := &Flag{
+		Key:         c.Param("key"),
+		Description: req.Description,
+		Enabled:     req.Enabled,
+		Percentage:  req.Percentage,
+		Users:       req.Users,
+	}
+
+	if err := h.client.Set(c.Request.Context(), flag); err != nil {
+		if errors.Is(err, ErrInvalidFlag) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid feature flag: percentage must be between 0 and 100"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set feature flag"})
+		return
+	}
+
+	c.JSON(http.StatusOK, flag)
+}
+
+// Delete обрабатывает DELETE /admin/flags/:key
+func (h *Handler) Delete(c *gin.Context) {
+	if err := h.client.Delete(c.Request.Context(), c.Param("key")); err != nil {
+		if errors.Is(err, ErrFlagNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Feature flag not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete feature flag"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Feature flag deleted successfully"})
+}
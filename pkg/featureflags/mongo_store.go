@@ -0,0 +1,78 @@
+package featureflags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mongoFlagsCollection - коллекция флагов, _id документа - ключ флага
+const mongoFlagsCollection = "feature_flags"
+
+type mongoStore struct {
+	collection *mongo.Collection
+}
+
+// NewMongoStore создает хранилище флагов в коллекции feature_flags базы сервиса
+// Подходит для сервисов, которые хранят данные в MongoDB
+func NewMongoStore(db *mongo.Database) Store {
+	return &mongoStore{collection: db.Collection(mongoFlagsCollection)}
+}
+
+func (s *mongoStore) Get(ctx context.Context, key string) (*Flag, error) {
+	var flag Flag
+	if err := s.collection.FindOne(ctx, bson.M{"_id": key}).Decode(&flag); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrFlagNotFound
+		}
+		return nil, fmt.Errorf("failed to get feature flag: %w", err)
+	}
+	return &flag, nil
+}
+
+func (s *mongoStore) List(ctx context.Context) ([]Flag, error) {
+	cursor, err := s.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feature flags: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	flags := make([]Flag, 0)
+	if err := cursor.All(ctx, &flags); err != nil {
+		return nil, fmt.Errorf("failed to decode feature flags: %w", err)
+	}
+	return flags, nil
+}
+
+func (s *mongoStore) Set(ctx context.Context, flag *Flag) error {
+	if err := flag.Validate(); err != nil {
+		return err
+	}
+	if flag.Users == nil {
+		flag.Users = []string{}
+	}
+	flag.UpdatedAt = time.Now()
+
+	// Upsert: PUT флага создает его или полностью заменяет
+	_, err := s.collection.ReplaceOne(ctx, bson.M{"_id": flag.Key}, flag, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to set feature flag: %w", err)
+	}
+	return nil
+}
+
+func (s *mongoStore) Delete(ctx context.Context, key string) error {
+	result, err := s.collection.DeleteOne(ctx, bson.M{"_id": key})
+	if err != nil {
+		return fmt.Errorf("failed to delete feature flag: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrFlagNotFound
+	}
+	return nil
+}
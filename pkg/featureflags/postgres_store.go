@@ -0,0 +1,69 @@
+package featureflags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type postgresStore struct {
+	db *gorm.DB
+}
+
+// NewPostgresStore создает хранилище флагов в таблице feature_flags
+// Таблица создается миграцией сервиса (см. migration/postgres)
+func NewPostgresStore(db *gorm.DB) Store {
+	return &postgresStore{db: db}
+}
+
+func (s *postgresStore) Get(ctx context.Context, key string) (*Flag, error) {
+	var flag Flag
+	if err := s.db.WithContext(ctx).First(&flag, "key = ?", key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFlagNotFound
+		}
+		return nil, fmt.Errorf("failed to get feature flag: %w", err)
+	}
+	return &flag, nil
+}
+
+func (s *postgresStore) List(ctx context.Context) ([]Flag, error) {
+	var flags []Flag
+	if err := s.db.WithContext(ctx).Order("key ASC").Find(&flags).Error; err != nil {
+		return nil, fmt.Errorf("failed to list feature flags: %w", err)
+	}
+	return flags, nil
+}
+
+func (s *postgresStore) Set(ctx context.Context, flag *Flag) error {
+	if err := flag.Validate(); err != nil {
+		return err
+	}
+	if flag.Users == nil {
+		flag.Users = []string{}
+	}
+
+	// Upsert: PUT флага создает его или полностью заменяет
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"description", "enabled", "percentage", "users", "updated_at"}),
+	}).Create(flag).Error
+	if err != nil {
+		return fmt.Errorf("failed to set feature flag: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresStore) Delete(ctx context.Context, key string) error {
+	result := s.db.WithContext(ctx).Delete(&Flag{}, "key = ?", key)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete feature flag: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrFlagNotFound
+	}
+	return nil
+}
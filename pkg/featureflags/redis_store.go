@@ -0,0 +1,88 @@
+package featureflags
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisFlagsKey - hash, в котором поле - ключ флага, значение - JSON флага
+const redisFlagsKey = "featureflags"
+
+type redisStore struct {
+	client redis.UniversalClient
+}
+
+// NewRedisStore создает хранилище флагов в Redis
+// Подходит для сервисов без PostgreSQL и для флагов, общих для нескольких сервисов
+func NewRedisStore(client redis.UniversalClient) Store {
+	return &redisStore{client: client}
+}
+
+func (s *redisStore) Get(ctx context.Context, key string) (*Flag, error) {
+	data, err := s.client.HGet(ctx, redisFlagsKey, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrFlagNotFound
+		}
+		return nil, fmt.Errorf("failed to get feature flag: %w", err)
+	}
+
+	var flag Flag
+	if err := json.Unmarshal(data, &flag); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal feature flag: %w", err)
+	}
+	return &flag, nil
+}
+
+func (s *redisStore) List(ctx context.Context) ([]Flag, error) {
+	values, err := s.client.HGetAll(ctx, redisFlagsKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feature flags: %w", err)
+	}
+
+	flags := make([]Flag, 0, len(values))
+	for _, value := range values {
+		var flag Flag
+		if err := json.Unmarshal([]byte(value), &flag); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal feature flag: %w", err)
+		}
+		flags = append(flags, flag)
+	}
+
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Key < flags[j].Key })
+	return flags, nil
+}
+
+func (s *redisStore) Set(ctx context.Context, flag *Flag) error {
+	if err := flag.Validate(); err != nil {
+		return err
+	}
+	flag.UpdatedAt = time.Now()
+
+	data, err := json.Marshal(flag)
+	if err != nil {
+		return fmt.Errorf("failed to marshal feature flag: %w", err)
+	}
+
+	if err := s.client.HSet(ctx, redisFlagsKey, flag.Key, data).Err(); err != nil {
+		return fmt.Errorf("failed to set feature flag: %w", err)
+	}
+	return nil
+}
+
+func (s *redisStore) Delete(ctx context.Context, key string) error {
+	deleted, err := s.client.HDel(ctx, redisFlagsKey, key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to delete feature flag: %w", err)
+	}
+	if deleted == 0 {
+		return ErrFlagNotFound
+	}
+	return nil
+}
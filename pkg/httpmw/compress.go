@@ -0,0 +1,304 @@
+package httpmw
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/zstd"
+
+	"augustberries/pkg/metrics"
+)
+
+// Поддерживаемые кодировки сжатия ответов
+const (
+	EncodingZstd     = "zstd"
+	EncodingGzip     = "gzip"
+	EncodingIdentity = "identity"
+)
+
+// Значения сжатия по умолчанию
+var (
+	DefaultEncodings    = []string{EncodingZstd, EncodingGzip}
+	DefaultContentTypes = []string{"application/json", "application/x-ndjson", "text/csv", "text/plain"}
+)
+
+// DefaultMinSize - ответы меньше порога не сжимаются: выигрыш меньше накладных расходов
+const DefaultMinSize = 1024
+
+// CompressConfig - настройки сжатия ответов; пустые списки заменяются значениями по умолчанию
+type CompressConfig struct {
+	Enabled      bool
+	Encodings    []string // Кодировки в порядке предпочтения сервера при равном q в Accept-Encoding
+	MinSize      int      // Минимальный размер тела для сжатия в байтах
+	ContentTypes []string // Сжимаемые типы содержимого без параметров (application/json)
+}
+
+// Validate проверяет кодировки и порог
+func (c CompressConfig) Validate() error {
+	for _, encoding := range c.Encodings {
+		if encoding != EncodingZstd && encoding != EncodingGzip {
+			return fmt.Errorf("unsupported compression encoding: %q", encoding)
+		}
+	}
+	if c.MinSize < 0 {
+		return fmt.Errorf("invalid compression min size: %d", c.MinSize)
+	}
+	return nil
+}
+
+// Compress сжимает ответы по Accept-Encoding и записывает размеры ответов в метрики
+// Тело накапливается до MinSize: меньшие ответы и ответы с типом вне списка отправляются как есть
+// Применяется к отдельным маршрутам с большими списками, а не ко всему роутеру
+func Compress(service string, cfg CompressConfig) gin.HandlerFunc {
+	encodings := orDefault(cfg.Encodings, DefaultEncodings)
+	contentTypes := make(map[string]struct{})
+	for _, contentType := range orDefault(cfg.ContentTypes, DefaultContentTypes) {
+		contentTypes[strings.ToLower(contentType)] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		if !cfg.Enabled || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		// Ответ зависит от Accept-Encoding, даже если этот клиент получит его без сжатия
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+
+		w := &compressWriter{
+			ResponseWriter: c.Writer,
+			encoding:       NegotiateEncoding(c.GetHeader("Accept-Encoding"), encodings),
+			minSize:        cfg.MinSize,
+			contentTypes:   contentTypes,
+			status:         http.StatusOK,
+		}
+		c.Writer = w
+		defer func() {
+			c.Writer = w.ResponseWriter
+		}()
+
+		c.Next()
+
+		if err := w.close(); err != nil {
+			_ = c.Error(err)
+		}
+		encoding := EncodingIdentity
+		if w.enc != nil {
+			encoding = w.encoding
+		}
+		metrics.RecordHTTPResponseSize(service, c.FullPath(), encoding, w.size, w.ResponseWriter.Size())
+	}
+}
+
+// NegotiateEncoding выбирает кодировку с наибольшим q из Accept-Encoding (RFC 9110)
+// При равном q выигрывает более ранняя в supported; пустая строка - без сжатия
+func NegotiateEncoding(header string, supported []string) string {
+	if header == "" {
+		return ""
+	}
+
+	weights := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		weights[name] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, encoding := range supported {
+		q, ok := weights[encoding]
+		if !ok {
+			q = weights["*"]
+		}
+		if q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+	return best
+}
+
+// compressWriter откладывает заголовки до решения о сжатии: оно принимается по первым MinSize байтам тела
+type compressWriter struct {
+	gin.ResponseWriter
+
+	encoding     string // Согласованная кодировка, пусто - клиент не принимает сжатие
+	minSize      int
+	contentTypes map[string]struct{}
+
+	status  int
+	buf     bytes.Buffer
+	size    int  // Тело до сжатия
+	decided bool // Заголовки отправлены
+	enc     io.WriteCloser
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if code > 0 && !w.decided {
+		w.status = code
+	}
+}
+
+// WriteHeaderNow не отправляет заголовки: они уходят вместе с решением о сжатии
+func (w *compressWriter) WriteHeaderNow() {}
+
+func (w *compressWriter) Status() int {
+	if w.decided {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+func (w *compressWriter) Written() bool {
+	return w.decided || w.size > 0
+}
+
+func (w *compressWriter) Size() int {
+	if w.size == 0 {
+		return -1
+	}
+	return w.size
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+	w.size += len(p)
+	if !w.decided {
+		w.buf.Write(p)
+		if w.buf.Len() < w.minSize {
+			return len(p), nil
+		}
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if w.enc != nil {
+		return w.enc.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush для потоковых ответов: решение о сжатии принимается без порога размера
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		if err := w.decide(true); err != nil {
+			return
+		}
+	}
+	if f, ok := w.enc.(interface{ Flush() error }); ok {
+		if err := f.Flush(); err != nil {
+			return
+		}
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide отправляет заголовки и накопленное тело; compressible - тело достаточно велико для сжатия
+func (w *compressWriter) decide(compressible bool) error {
+	w.decided = true
+
+	header := w.ResponseWriter.Header()
+	if compressible && w.shouldCompress(header) {
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		w.ResponseWriter.WriteHeader(w.status)
+		w.enc = newEncoder(w.encoding, w.ResponseWriter)
+	} else {
+		w.ResponseWriter.WriteHeader(w.status)
+		w.ResponseWriter.WriteHeaderNow()
+	}
+
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	var err error
+	if w.enc != nil {
+		_, err = w.enc.Write(w.buf.Bytes())
+	} else {
+		_, err = w.ResponseWriter.Write(w.buf.Bytes())
+	}
+	w.buf.Reset()
+	return err
+}
+
+func (w *compressWriter) shouldCompress(header http.Header) bool {
+	if w.encoding == "" || header.Get("Content-Encoding") != "" || !bodyAllowed(w.status) {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	_, ok := w.contentTypes[mediaType]
+	return ok
+}
+
+// close отправляет тело меньше порога без сжатия и завершает поток сжатия
+func (w *compressWriter) close() error {
+	if !w.decided {
+		return w.decide(false)
+	}
+	if w.enc == nil {
+		return nil
+	}
+	err := w.enc.Close()
+	releaseEncoder(w.encoding, w.enc)
+	return err
+}
+
+func bodyAllowed(status int) bool {
+	return status >= http.StatusOK && status != http.StatusNoContent && status != http.StatusNotModified
+}
+
+// Кодировщики переиспользуются: создание zstd кодировщика дороже сжатия типичного ответа
+var (
+	gzipPool = sync.Pool{New: func() any {
+		enc, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+		return enc
+	}}
+	zstdPool = sync.Pool{New: func() any {
+		enc, _ := zstd.NewWriter(io.Discard, zstd.WithEncoderConcurrency(1), zstd.WithWindowSize(1<<20))
+		return enc
+	}}
+)
+
+func newEncoder(encoding string, w io.Writer) io.WriteCloser {
+	if encoding == EncodingZstd {
+		enc := zstdPool.Get().(*zstd.Encoder)
+		enc.Reset(w)
+		return enc
+	}
+	enc := gzipPool.Get().(*gzip.Writer)
+	enc.Reset(w)
+	return enc
+}
+
+func releaseEncoder(encoding string, enc io.WriteCloser) {
+	if encoding == EncodingZstd {
+		zstdPool.Put(enc)
+		return
+	}
+	gzipPool.Put(enc)
+}
@@ -0,0 +1,144 @@
+package httpmw
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCompressRouter создает роутер со сжатием маршрута /products и ответом заданного размера
+func newCompressRouter(cfg CompressConfig, size int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/products", Compress("test-service", cfg), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"items": strings.Repeat("a", size)})
+	})
+	router.GET("/export.bin", Compress("test-service", cfg), func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/octet-stream", []byte(strings.Repeat("a", size)))
+	})
+	router.GET("/empty", Compress("test-service", cfg), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	return router
+}
+
+func get(router *gin.Engine, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+var compressEnabled = CompressConfig{Enabled: true, MinSize: DefaultMinSize}
+
+// ==================== Compress Tests ====================
+
+func TestCompress_Gzip(t *testing.T) {
+	router := newCompressRouter(compressEnabled, 10000)
+
+	w := get(router, "/products", "gzip, deflate")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Contains(t, w.Header().Values("Vary"), "Accept-Encoding")
+	assert.Less(t, w.Body.Len(), 10000)
+
+	reader, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, `{"items":"`+strings.Repeat("a", 10000)+`"}`, string(body))
+}
+
+func TestCompress_Zstd(t *testing.T) {
+	router := newCompressRouter(compressEnabled, 10000)
+
+	w := get(router, "/products", "gzip, zstd")
+
+	// При равном q выбирается zstd: первая в списке сервера
+	assert.Equal(t, "zstd", w.Header().Get("Content-Encoding"))
+
+	decoder, err := zstd.NewReader(w.Body)
+	require.NoError(t, err)
+	defer decoder.Close()
+	body, err := io.ReadAll(decoder)
+	require.NoError(t, err)
+	assert.Len(t, body, 10000+len(`{"items":""}`))
+}
+
+func TestCompress_Identity(t *testing.T) {
+	tests := []struct {
+		name           string
+		cfg            CompressConfig
+		path           string
+		size           int
+		acceptEncoding string
+		wantStatus     int
+	}{
+		{"below threshold", compressEnabled, "/products", 100, "gzip", http.StatusOK},
+		{"client without compression", compressEnabled, "/products", 10000, "", http.StatusOK},
+		{"encoding not accepted", compressEnabled, "/products", 10000, "br, gzip;q=0", http.StatusOK},
+		{"content type not allowed", compressEnabled, "/export.bin", 10000, "gzip", http.StatusOK},
+		{"no body", compressEnabled, "/empty", 0, "gzip", http.StatusNoContent},
+		{"disabled", CompressConfig{}, "/products", 10000, "gzip", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newCompressRouter(tt.cfg, tt.size)
+
+			w := get(router, tt.path, tt.acceptEncoding)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Empty(t, w.Header().Get("Content-Encoding"))
+			if tt.size > 0 {
+				assert.GreaterOrEqual(t, w.Body.Len(), tt.size)
+			}
+		})
+	}
+}
+
+// ==================== NegotiateEncoding Tests ====================
+
+func TestNegotiateEncoding(t *testing.T) {
+	supported := []string{EncodingZstd, EncodingGzip}
+
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"gzip", "gzip"},
+		{"gzip, zstd", "zstd"},
+		{"zstd;q=0.5, gzip", "gzip"},
+		{"GZIP;q=0.8", "gzip"},
+		{"*", "zstd"},
+		{"*;q=0.5, zstd;q=0", "gzip"},
+		{"identity", ""},
+		{"br, deflate", ""},
+		{"gzip;q=invalid", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.want, NegotiateEncoding(tt.header, supported))
+		})
+	}
+}
+
+func TestCompressConfig_Validate(t *testing.T) {
+	assert.NoError(t, CompressConfig{Encodings: []string{"gzip", "zstd"}}.Validate())
+	assert.Error(t, CompressConfig{Encodings: []string{"br"}}.Validate())
+	assert.Error(t, CompressConfig{MinSize: -1}.Validate())
+}
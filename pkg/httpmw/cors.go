@@ -0,0 +1,170 @@
+// Package httpmw - общие HTTP middleware сервисов на Gin
+package httpmw
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Значения CORS по умолчанию: заголовки, которые принимают и отдают сервисы
+var (
+	DefaultAllowedMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	DefaultAllowedHeaders = []string{
+		"Accept", "Accept-Language", "Authorization", "Content-Type", "If-None-Match",
+		"X-CSRF-Token", "X-Tenant-ID", "X-Guest-Token", "X-API-Key",
+	}
+	DefaultExposedHeaders = []string{
+		"Link", "ETag", "Retry-After", "Content-Language", "X-Impersonated-By",
+		"X-Quota-Limit", "X-Quota-Remaining", "X-Quota-Reset", "X-Quota-Subject",
+	}
+)
+
+// ErrWildcardCredentials - браузер не принимает cookie в ответе с Access-Control-Allow-Origin: *
+var ErrWildcardCredentials = errors.New("CORS credentials require explicit allowed origins instead of *")
+
+// CORSConfig - настройки CORS; пустые списки методов и заголовков заменяются значениями по умолчанию
+type CORSConfig struct {
+	AllowedOrigins   []string      // Точные origin, "*" или поддомены "https://*.example.com"; пусто - CORS отключен
+	AllowedMethods   []string      // Методы, разрешенные в preflight
+	AllowedHeaders   []string      // Заголовки запроса, разрешенные в preflight
+	ExposedHeaders   []string      // Заголовки ответа, доступные JS
+	AllowCredentials bool          // Cookie и Authorization в кросс-доменных запросах (токены в cookie)
+	MaxAge           time.Duration // Время кеширования preflight браузером
+}
+
+// Validate проверяет, что credentials не сочетаются с любым origin
+func (c CORSConfig) Validate() error {
+	if !c.AllowCredentials {
+		return nil
+	}
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			return ErrWildcardCredentials
+		}
+	}
+	return nil
+}
+
+// ParseList разбирает список через запятую из переменной окружения, пустые элементы пропускаются
+func ParseList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// CORS отвечает на preflight запросы и добавляет CORS заголовки к ответам разрешенным origin
+// Запросы без Origin и с неразрешенным origin проходят без CORS заголовков: браузер сам не отдаст ответ JS
+func CORS(cfg CORSConfig) gin.HandlerFunc {
+	if len(cfg.AllowedOrigins) == 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	methods := strings.Join(orDefault(cfg.AllowedMethods, DefaultAllowedMethods), ", ")
+	headers := strings.Join(orDefault(cfg.AllowedHeaders, DefaultAllowedHeaders), ", ")
+	exposed := strings.Join(orDefault(cfg.ExposedHeaders, DefaultExposedHeaders), ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+	origins := newOriginMatcher(cfg.AllowedOrigins)
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		// Ответ зависит от Origin: кеши не должны отдавать его другим сайтам
+		header := c.Writer.Header()
+		header.Add("Vary", "Origin")
+
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		allowed, wildcard := origins.match(origin)
+		if !allowed {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if wildcard && !cfg.AllowCredentials {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+		if cfg.AllowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+			header.Set("Access-Control-Allow-Methods", methods)
+			header.Set("Access-Control-Allow-Headers", headers)
+			if cfg.MaxAge > 0 {
+				header.Set("Access-Control-Max-Age", maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		if exposed != "" {
+			header.Set("Access-Control-Expose-Headers", exposed)
+		}
+		c.Next()
+	}
+}
+
+func orDefault(values, fallback []string) []string {
+	if len(values) == 0 {
+		return fallback
+	}
+	return values
+}
+
+// originMatcher сравнивает origin без учета регистра с точными значениями и шаблонами поддоменов
+type originMatcher struct {
+	any      bool
+	exact    map[string]struct{}
+	suffixes [][2]string // Схема с "://" и суффикс домена с точкой: "https://", ".example.com"
+}
+
+func newOriginMatcher(origins []string) originMatcher {
+	m := originMatcher{exact: make(map[string]struct{})}
+	for _, origin := range origins {
+		origin = strings.ToLower(strings.TrimSuffix(origin, "/"))
+		if origin == "*" {
+			m.any = true
+			continue
+		}
+		if scheme, host, ok := strings.Cut(origin, "://*."); ok {
+			m.suffixes = append(m.suffixes, [2]string{scheme + "://", "." + host})
+			continue
+		}
+		m.exact[origin] = struct{}{}
+	}
+	return m
+}
+
+// match сообщает, разрешен ли origin и разрешен ли он только через "*"
+func (m originMatcher) match(origin string) (allowed, wildcard bool) {
+	origin = strings.ToLower(origin)
+	if _, ok := m.exact[origin]; ok {
+		return true, false
+	}
+	for _, s := range m.suffixes {
+		if strings.HasPrefix(origin, s[0]) && strings.HasSuffix(origin, s[1]) && len(origin) > len(s[0])+len(s[1]) {
+			return true, false
+		}
+	}
+	return m.any, m.any
+}
@@ -0,0 +1,29 @@
+// Package id генерирует идентификаторы сущностей
+// UUIDv7 начинается с времени создания в миллисекундах, поэтому новые ID упорядочены по времени
+// и вставляются в конец B-tree индекса PostgreSQL, а не в случайные страницы, как UUIDv4.
+// Сохраненные ранее UUIDv4 остаются валидными: формат и тип колонки (uuid) не меняются,
+// только старые ID не несут времени создания
+package id
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// New возвращает UUIDv7
+// ID одного процесса строго возрастают, в том числе созданные в одну миллисекунду.
+// Паникует, если не удалось прочитать случайные байты, как uuid.New
+func New() uuid.UUID {
+	return uuid.Must(uuid.NewV7())
+}
+
+// Time возвращает время создания, записанное в UUIDv7, с точностью до миллисекунды
+// Для ID других версий (UUIDv4 существующих записей) ok = false
+func Time(id uuid.UUID) (t time.Time, ok bool) {
+	if id.Version() != 7 {
+		return time.Time{}, false
+	}
+	sec, nsec := id.Time().UnixTime()
+	return time.Unix(sec, nsec).UTC(), true
+}
@@ -0,0 +1,66 @@
+package id
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ==================== New Tests ====================
+
+func TestNew_Version7(t *testing.T) {
+	id := New()
+
+	assert.Equal(t, uuid.Version(7), id.Version())
+	assert.Equal(t, uuid.RFC4122, id.Variant())
+}
+
+func TestNew_StrictlyIncreasing(t *testing.T) {
+	// Тысячи ID укладываются в несколько миллисекунд: порядок внутри миллисекунды тоже должен сохраняться
+	ids := make([]uuid.UUID, 10000)
+	for i := range ids {
+		ids[i] = New()
+	}
+
+	for i := 1; i < len(ids); i++ {
+		// PostgreSQL сравнивает uuid побайтно, строковое представление упорядочено так же
+		require.Equal(t, -1, bytes.Compare(ids[i-1][:], ids[i][:]), "id %d is not greater than previous", i)
+		require.Less(t, ids[i-1].String(), ids[i].String())
+	}
+}
+
+func TestNew_OrderedByCreationTime(t *testing.T) {
+	first := New()
+	time.Sleep(2 * time.Millisecond)
+	second := New()
+
+	ids := []uuid.UUID{second, first}
+	sort.Slice(ids, func(i, j int) bool { return bytes.Compare(ids[i][:], ids[j][:]) < 0 })
+
+	assert.Equal(t, []uuid.UUID{first, second}, ids)
+}
+
+// ==================== Time Tests ====================
+
+func TestTime(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+	id := New()
+	after := time.Now()
+
+	created, ok := Time(id)
+
+	require.True(t, ok)
+	assert.False(t, created.Before(before))
+	assert.False(t, created.After(after))
+}
+
+func TestTime_LegacyUUIDv4(t *testing.T) {
+	_, ok := Time(uuid.New())
+
+	assert.False(t, ok)
+}
@@ -0,0 +1,86 @@
+// Package impersonation - вход сотрудника поддержки от имени пользователя
+//
+// Auth Service выдает короткоживущий access токен пользователя с claim impersonator.
+// Сервисы кладут данные сотрудника в контекст запроса (Set), отмечают ответ заголовком
+// X-Impersonated-By и пишут каждый такой запрос в журнал (AuditMiddleware)
+package impersonation
+
+import (
+	"context"
+	"log"
+
+	"github.com/gin-gonic/gin"
+
+	"augustberries/pkg/tenant"
+)
+
+const (
+	// Header - заголовок ответа на запрос с токеном имперсонации: ID сотрудника поддержки
+	Header = "X-Impersonated-By"
+	// ContextKey - ключ gin.Context с *Impersonator
+	ContextKey = "impersonator"
+)
+
+// Impersonator - сотрудник поддержки, действующий от имени пользователя (JWT claim impersonator)
+type Impersonator struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+}
+
+type ctxKey struct{}
+
+// WithImpersonator кладет сотрудника поддержки в контекст
+func WithImpersonator(ctx context.Context, imp *Impersonator) context.Context {
+	return context.WithValue(ctx, ctxKey{}, imp)
+}
+
+// FromContext возвращает сотрудника поддержки, nil - запрос выполняет сам пользователь
+func FromContext(ctx context.Context) *Impersonator {
+	imp, _ := ctx.Value(ctxKey{}).(*Impersonator)
+	return imp
+}
+
+// Set отмечает запрос как выполняемый сотрудником поддержки от имени пользователя
+// Вызывается auth middleware после проверки токена; nil - обычный запрос
+func Set(c *gin.Context, imp *Impersonator) {
+	if imp == nil {
+		return
+	}
+
+	c.Set(ContextKey, imp)
+	c.Header(Header, imp.ID)
+	c.Request = c.Request.WithContext(WithImpersonator(c.Request.Context(), imp))
+}
+
+// FromGin возвращает сотрудника поддержки запроса, nil - обычный запрос
+func FromGin(c *gin.Context) *Impersonator {
+	value, exists := c.Get(ContextKey)
+	if !exists {
+		return nil
+	}
+	imp, _ := value.(*Impersonator)
+	return imp
+}
+
+// IsImpersonated сообщает, что запрос выполнен с токеном имперсонации
+func IsImpersonated(c *gin.Context) bool {
+	return FromGin(c) != nil
+}
+
+// AuditMiddleware записывает в журнал каждый запрос, выполненный от имени пользователя
+// Ставится глобально: данные сотрудника появляются после auth middleware, поэтому читаются после обработки
+func AuditMiddleware(service string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		imp := FromGin(c)
+		if imp == nil {
+			return
+		}
+
+		userID, _ := c.Get("user_id")
+		log.Printf("[%s] impersonated request: impersonator=%s (%s) user=%v tenant=%s %s %s status=%d",
+			service, imp.ID, imp.Email, userID, c.GetString(tenant.ContextKey),
+			c.Request.Method, c.Request.URL.Path, c.Writer.Status())
+	}
+}
@@ -0,0 +1,92 @@
+package impersonation
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRouter(imp *Impersonator, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(AuditMiddleware("test-service"))
+	router.GET("/orders", func(c *gin.Context) {
+		c.Set("user_id", "user-1")
+		Set(c, imp)
+		c.Next()
+	}, handler)
+	return router
+}
+
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
+
+func TestAuditMiddleware_LogsImpersonatedRequest(t *testing.T) {
+	buf := captureLog(t)
+	imp := &Impersonator{ID: "support-1", Email: "support@example.com"}
+
+	var fromCtx *Impersonator
+	router := newTestRouter(imp, func(c *gin.Context) {
+		fromCtx = FromContext(c.Request.Context())
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+	assert.Equal(t, "support-1", w.Header().Get(Header))
+	assert.Equal(t, imp, fromCtx)
+	assert.Contains(t, buf.String(), "impersonator=support-1 (support@example.com) user=user-1")
+	assert.Contains(t, buf.String(), "GET /orders status=204")
+}
+
+func TestAuditMiddleware_SkipsRegularRequest(t *testing.T) {
+	buf := captureLog(t)
+
+	router := newTestRouter(nil, func(c *gin.Context) {
+		assert.Nil(t, FromContext(c.Request.Context()))
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+	assert.Empty(t, w.Header().Get(Header))
+	assert.Empty(t, buf.String())
+}
+
+func TestIsImpersonated(t *testing.T) {
+	tests := []struct {
+		name     string
+		imp      *Impersonator
+		expected bool
+	}{
+		{"impersonation token", &Impersonator{ID: "support-1"}, true},
+		{"regular token", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var impersonated bool
+			router := newTestRouter(tt.imp, func(c *gin.Context) {
+				impersonated = IsImpersonated(c)
+				c.Status(http.StatusOK)
+			})
+
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+			assert.Equal(t, tt.expected, impersonated)
+		})
+	}
+}
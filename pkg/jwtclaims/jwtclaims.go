@@ -0,0 +1,44 @@
+// Package jwtclaims проверяет издателя (iss) и аудиторию (aud) токенов Auth Service в middleware сервисов
+// Auth Service записывает JWT_ISSUER и JWT_AUDIENCE в каждый токен, остальные сервисы принимают только токены
+// с теми же значениями, поэтому токен одного окружения не действует в другом
+package jwtclaims
+
+import (
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Expected - ожидаемые iss и aud (JWT_ISSUER и JWT_AUDIENCE сервиса); пустое значение не проверяется
+type Expected struct {
+	Issuer   string
+	Audience string
+}
+
+// ParserOptions возвращает опции jwt.Parse для проверки iss и aud
+func (e Expected) ParserOptions() []jwt.ParserOption {
+	var opts []jwt.ParserOption
+	if e.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(e.Issuer))
+	}
+	if e.Audience != "" {
+		opts = append(opts, jwt.WithAudience(e.Audience))
+	}
+	return opts
+}
+
+// Message возвращает текст ответа 401 для ошибки разбора токена
+func Message(err error) string {
+	switch {
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return "Token was issued by an untrusted issuer"
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return "Token is not intended for this environment"
+	case errors.Is(err, jwt.ErrTokenRequiredClaimMissing):
+		return "Token has no issuer or audience claim" // Выпущен до настройки JWT_ISSUER и JWT_AUDIENCE
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return "Token has expired"
+	default:
+		return "Invalid or expired token"
+	}
+}
@@ -0,0 +1,64 @@
+package jwtclaims
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var secret = []byte("secret")
+
+func sign(t *testing.T, claims jwt.RegisteredClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	require.NoError(t, err)
+	return token
+}
+
+func parse(expected Expected, token string) error {
+	_, err := jwt.ParseWithClaims(token, &jwt.RegisteredClaims{}, func(*jwt.Token) (interface{}, error) {
+		return secret, nil
+	}, expected.ParserOptions()...)
+	return err
+}
+
+// ==================== ParserOptions Tests ====================
+
+func TestParserOptions(t *testing.T) {
+	expires := jwt.NewNumericDate(time.Now().Add(time.Hour))
+	expected := Expected{Issuer: "augustberries-auth", Audience: "augustberries-prod"}
+
+	tests := []struct {
+		name    string
+		claims  jwt.RegisteredClaims
+		message string // пусто - токен принят
+	}{
+		{"matching", jwt.RegisteredClaims{Issuer: "augustberries-auth", Audience: jwt.ClaimStrings{"augustberries-prod"}, ExpiresAt: expires}, ""},
+		{"other environment", jwt.RegisteredClaims{Issuer: "augustberries-auth", Audience: jwt.ClaimStrings{"augustberries-staging"}, ExpiresAt: expires}, "Token is not intended for this environment"},
+		{"no audience", jwt.RegisteredClaims{Issuer: "augustberries-auth", ExpiresAt: expires}, "Token has no issuer or audience claim"},
+		{"other issuer", jwt.RegisteredClaims{Issuer: "someone-else", Audience: jwt.ClaimStrings{"augustberries-prod"}, ExpiresAt: expires}, "Token was issued by an untrusted issuer"},
+		{"expired", jwt.RegisteredClaims{Issuer: "augustberries-auth", Audience: jwt.ClaimStrings{"augustberries-prod"}, ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour))}, "Token has expired"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := parse(expected, sign(t, tt.claims))
+
+			if tt.message == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, tt.message, Message(err))
+		})
+	}
+}
+
+func TestParserOptions_EmptyDisablesChecks(t *testing.T) {
+	token := sign(t, jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))})
+
+	assert.NoError(t, parse(Expected{}, token))
+}
@@ -0,0 +1,88 @@
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hamba/avro/v2"
+)
+
+// HeaderContentType - заголовок с форматом сериализации значения сообщения
+const HeaderContentType = "content-type"
+
+// Codec сериализует и десериализует значения сообщений
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+	// ContentType возвращает значение заголовка content-type для этого формата
+	ContentType() string
+}
+
+// JSONCodec - сериализация в JSON (формат всех текущих событий)
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (JSONCodec) ContentType() string {
+	return "application/json"
+}
+
+// AvroCodec - бинарная сериализация Avro по заданной схеме
+// Поля структур сопоставляются с полями схемы по тегу `avro:"..."`
+type AvroCodec struct {
+	schema avro.Schema
+}
+
+// NewAvroCodec разбирает схему Avro в формате JSON
+func NewAvroCodec(schema string) (*AvroCodec, error) {
+	parsed, err := avro.Parse(schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid avro schema: %w", err)
+	}
+	return &AvroCodec{schema: parsed}, nil
+}
+
+func (c *AvroCodec) Marshal(v any) ([]byte, error) {
+	return avro.Marshal(c.schema, v)
+}
+
+func (c *AvroCodec) Unmarshal(data []byte, v any) error {
+	return avro.Unmarshal(c.schema, data, v)
+}
+
+func (c *AvroCodec) ContentType() string {
+	return "application/avro"
+}
+
+// Encode сериализует значение и возвращает сообщение с заголовком content-type
+func Encode(codec Codec, key string, v any) (Message, error) {
+	value, err := codec.Marshal(v)
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	return Message{
+		Key:     []byte(key),
+		Value:   value,
+		Headers: map[string]string{HeaderContentType: codec.ContentType()},
+	}, nil
+}
+
+// Decode десериализует значение сообщения
+// Сообщение с другим content-type считается ошибкой; отсутствие заголовка допускается
+// для совместимости с событиями, отправленными до появления заголовков
+func Decode(codec Codec, msg Message, v any) error {
+	if ct, ok := msg.Headers[HeaderContentType]; ok && ct != codec.ContentType() {
+		return fmt.Errorf("unexpected content type %q, expected %q", ct, codec.ContentType())
+	}
+	if err := codec.Unmarshal(msg.Value, v); err != nil {
+		return fmt.Errorf("failed to decode message: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testEvent struct {
+	EventType string `json:"event_type" avro:"event_type"`
+	Count     int    `json:"count" avro:"count"`
+}
+
+const testEventSchema = `{
+	"type": "record",
+	"name": "TestEvent",
+	"fields": [
+		{"name": "event_type", "type": "string"},
+		{"name": "count", "type": "int"}
+	]
+}`
+
+// ====== Codec Tests ======
+
+func TestJSONCodec_RoundTrip(t *testing.T) {
+	msg, err := Encode(JSONCodec{}, "key", testEvent{EventType: "ORDER_CREATED", Count: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", msg.Headers[HeaderContentType])
+	assert.JSONEq(t, `{"event_type":"ORDER_CREATED","count":3}`, string(msg.Value))
+
+	var decoded testEvent
+	require.NoError(t, Decode(JSONCodec{}, msg, &decoded))
+	assert.Equal(t, testEvent{EventType: "ORDER_CREATED", Count: 3}, decoded)
+}
+
+func TestAvroCodec_RoundTrip(t *testing.T) {
+	codec, err := NewAvroCodec(testEventSchema)
+	require.NoError(t, err)
+
+	msg, err := Encode(codec, "key", testEvent{EventType: "ORDER_CREATED", Count: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "application/avro", msg.Headers[HeaderContentType])
+
+	var decoded testEvent
+	require.NoError(t, Decode(codec, msg, &decoded))
+	assert.Equal(t, testEvent{EventType: "ORDER_CREATED", Count: 3}, decoded)
+}
+
+func TestNewAvroCodec_InvalidSchema(t *testing.T) {
+	_, err := NewAvroCodec(`{"type": "record"}`)
+	assert.Error(t, err)
+}
+
+func TestDecode_ContentTypeMismatch(t *testing.T) {
+	msg, err := Encode(JSONCodec{}, "key", testEvent{EventType: "ORDER_CREATED"})
+	require.NoError(t, err)
+
+	codec, err := NewAvroCodec(testEventSchema)
+	require.NoError(t, err)
+
+	var decoded testEvent
+	assert.Error(t, Decode(codec, msg, &decoded))
+}
+
+func TestDecode_WithoutContentType(t *testing.T) {
+	// События без заголовков (отправленные до pkg/kafka) читаются как раньше
+	var decoded testEvent
+	require.NoError(t, Decode(JSONCodec{}, Message{Value: []byte(`{"event_type":"ORDER_UPDATED"}`)}, &decoded))
+	assert.Equal(t, "ORDER_UPDATED", decoded.EventType)
+}
@@ -0,0 +1,290 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"augustberries/pkg/metrics"
+
+	kafkago "github.com/segmentio/kafka-go"
+)
+
+// ConsumerConfig - настройки consumer
+type ConsumerConfig struct {
+	Brokers  []string // Список брокеров Kafka (формат: host:port)
+	Topic    string   // Топик для чтения
+	Topics   []string // Несколько топиков в одной consumer group вместо Topic (требует GroupID)
+	GroupID  string   // ID группы потребителей
+	Service  string   // Имя сервиса для меток метрик
+	MinBytes int      // Минимум байт для fetch запроса
+	MaxBytes int      // Максимум байт для fetch запроса
+	Gate     *Gate    // Пауза чтения при недоступных зависимостях (nil - без пауз)
+}
+
+// defaultRedeliverBackoff - пауза перед повторной обработкой сообщения с ошибкой Redeliver
+const defaultRedeliverBackoff = time.Second
+
+// reader - чтение и коммит offset'ов consumer group (*kafkago.Reader)
+type reader interface {
+	FetchMessage(ctx context.Context) (kafkago.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafkago.Message) error
+	Stats() kafkago.ReaderStats
+	Close() error
+}
+
+type consumer struct {
+	reader  reader
+	topic   string // Топик или топики через запятую - для логов и метрик, не относящихся к одному сообщению
+	groupID string
+	service string
+	gate    *Gate
+
+	redeliverBackoff time.Duration
+}
+
+// NewConsumer создает consumer, читающий новые сообщения топика или нескольких топиков
+func NewConsumer(cfg ConsumerConfig) Consumer {
+	readerCfg := kafkago.ReaderConfig{
+		Brokers:        cfg.Brokers,
+		Topic:          cfg.Topic,
+		GroupID:        cfg.GroupID,
+		MinBytes:       cfg.MinBytes,
+		MaxBytes:       cfg.MaxBytes,
+		StartOffset:    kafkago.LastOffset,
+		CommitInterval: time.Second,
+		ReadBackoffMin: 100 * time.Millisecond,
+		ReadBackoffMax: 1 * time.Second,
+	}
+	topic := cfg.Topic
+	if len(cfg.Topics) > 0 {
+		readerCfg.Topic = ""
+		readerCfg.GroupTopics = cfg.Topics
+		topic = strings.Join(cfg.Topics, ",")
+	}
+
+	return &consumer{
+		reader:  kafkago.NewReader(readerCfg),
+		topic:   topic,
+		groupID: cfg.GroupID,
+		service: cfg.Service,
+		gate:    cfg.Gate,
+
+		redeliverBackoff: defaultRedeliverBackoff,
+	}
+}
+
+func (c *consumer) Run(ctx context.Context, handler Handler) error {
+	for {
+		if err := c.gate.Wait(ctx); err != nil {
+			return nil
+		}
+
+		m, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("kafka reader closed: %w", err)
+			}
+			log.Printf("Error fetching message from %s: %v", c.topic, err)
+			metrics.RecordKafkaError(c.service, c.topic, "fetch")
+			time.Sleep(time.Second)
+			continue
+		}
+
+		// HighWaterMark - offset следующего сообщения партиции на момент fetch
+		metrics.RecordKafkaConsumerLag(c.service, m.Topic, c.groupID, m.Partition, max(m.HighWaterMark-m.Offset-1, 0))
+
+		msg := fromKafkaMessage(m)
+
+		// Текущее сообщение дообрабатывается даже при остановке consumer
+		handlerCtx := ContextWithHeaders(context.WithoutCancel(ctx), msg.Headers)
+
+		start := time.Now()
+		err = handler(handlerCtx, msg)
+		for err != nil && (c.gate.Paused() || IsRedeliver(err)) {
+			// Сообщение не коммитится и обрабатывается снова; следующий offset не читается,
+			// иначе его коммит пропустил бы это сообщение
+			if c.gate.Paused() {
+				log.Printf("Consumer of %s paused, message %s/%d@%d will be processed after resume: %v", c.topic, m.Topic, m.Partition, m.Offset, err)
+			} else {
+				log.Printf("Message %s/%d@%d will be processed again: %v", m.Topic, m.Partition, m.Offset, err)
+				metrics.RecordKafkaError(c.service, m.Topic, "redeliver")
+			}
+			if c.waitRedeliver(ctx) != nil {
+				return nil
+			}
+			err = handler(handlerCtx, msg)
+		}
+		if err != nil {
+			log.Printf("Error processing message %s/%d@%d: %v", m.Topic, m.Partition, m.Offset, err)
+			metrics.RecordKafkaError(c.service, m.Topic, "consume")
+			continue
+		}
+		metrics.RecordKafkaMessageConsumed(c.service, m.Topic, c.groupID, time.Since(start))
+
+		if err := c.reader.CommitMessages(context.WithoutCancel(ctx), m); err != nil {
+			log.Printf("Error committing message: %v", err)
+			metrics.RecordKafkaError(c.service, m.Topic, "commit")
+		}
+	}
+}
+
+func (c *consumer) RunBatch(ctx context.Context, size int, flushInterval time.Duration, handler BatchHandler) error {
+	if size < 1 {
+		size = 1
+	}
+
+	for {
+		if err := c.gate.Wait(ctx); err != nil {
+			return nil
+		}
+
+		batch, err := c.fetchBatch(ctx, size, flushInterval)
+		if len(batch) > 0 {
+			c.handleBatch(ctx, batch, handler)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// fetchBatch читает до size сообщений; ожидание следующего сообщения ограничено flushInterval с момента первого
+// Ошибка возвращается вместе с уже прочитанными сообщениями, чтобы они были обработаны
+func (c *consumer) fetchBatch(ctx context.Context, size int, flushInterval time.Duration) ([]kafkago.Message, error) {
+	batch := make([]kafkago.Message, 0, size)
+	var deadline time.Time
+
+	for len(batch) < size {
+		fetchCtx, cancel := ctx, context.CancelFunc(func() {})
+		if len(batch) > 0 {
+			fetchCtx, cancel = context.WithDeadline(ctx, deadline)
+		}
+		m, err := c.reader.FetchMessage(fetchCtx)
+		cancel()
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return batch, ctx.Err()
+			}
+			if fetchCtx.Err() != nil {
+				// Истек flushInterval - отдаем неполную пачку
+				return batch, nil
+			}
+			if errors.Is(err, io.EOF) {
+				return batch, fmt.Errorf("kafka reader closed: %w", err)
+			}
+			log.Printf("Error fetching message from %s: %v", c.topic, err)
+			metrics.RecordKafkaError(c.service, c.topic, "fetch")
+			if len(batch) > 0 {
+				return batch, nil
+			}
+			time.Sleep(time.Second)
+			continue
+		}
+
+		metrics.RecordKafkaConsumerLag(c.service, m.Topic, c.groupID, m.Partition, max(m.HighWaterMark-m.Offset-1, 0))
+		if len(batch) == 0 {
+			deadline = time.Now().Add(flushInterval)
+		}
+		batch = append(batch, m)
+	}
+
+	return batch, nil
+}
+
+// handleBatch обрабатывает пачку и коммитит ее offset'ы при успехе
+func (c *consumer) handleBatch(ctx context.Context, batch []kafkago.Message, handler BatchHandler) {
+	msgs := make([]Message, len(batch))
+	for i, m := range batch {
+		msgs[i] = fromKafkaMessage(m)
+	}
+
+	// Текущая пачка дообрабатывается даже при остановке consumer
+	handlerCtx := context.WithoutCancel(ctx)
+
+	start := time.Now()
+	err := handler(handlerCtx, msgs)
+	for err != nil && (c.gate.Paused() || IsRedeliver(err)) {
+		// Пачка не коммитится и обрабатывается снова до чтения следующих сообщений
+		if c.gate.Paused() {
+			log.Printf("Consumer of %s paused, batch of %d messages will be processed after resume: %v", c.topic, len(batch), err)
+		} else {
+			log.Printf("Batch of %d messages from %s will be processed again: %v", len(batch), c.topic, err)
+			metrics.RecordKafkaError(c.service, c.topic, "redeliver")
+		}
+		if c.waitRedeliver(ctx) != nil {
+			return
+		}
+		err = handler(handlerCtx, msgs)
+	}
+	if err != nil {
+		log.Printf("Error processing batch of %d messages from %s: %v", len(batch), c.topic, err)
+		metrics.RecordKafkaError(c.service, c.topic, "consume")
+		return
+	}
+	metrics.RecordKafkaBatchConsumed(c.service, c.topic, c.groupID, len(batch), time.Since(start))
+
+	if err := c.reader.CommitMessages(handlerCtx, batch...); err != nil {
+		log.Printf("Error committing batch: %v", err)
+		metrics.RecordKafkaError(c.service, c.topic, "commit")
+	}
+}
+
+// waitRedeliver ждет перед повторной обработкой: снятия паузы Gate или redeliverBackoff
+// Ошибка - consumer остановлен; сообщение не закоммичено и будет прочитано снова после перезапуска
+func (c *consumer) waitRedeliver(ctx context.Context) error {
+	if c.gate.Paused() {
+		return c.gate.Wait(ctx)
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(c.redeliverBackoff):
+		return nil
+	}
+}
+
+func (c *consumer) Stats() Stats {
+	stats := c.reader.Stats()
+	return Stats{
+		Topic:    stats.Topic,
+		Messages: stats.Messages,
+		Errors:   stats.Errors,
+		Lag:      stats.Lag,
+	}
+}
+
+func (c *consumer) Close() error {
+	return c.reader.Close()
+}
+
+func fromKafkaMessage(m kafkago.Message) Message {
+	var headers map[string]string
+	if len(m.Headers) > 0 {
+		headers = make(map[string]string, len(m.Headers))
+		for _, h := range m.Headers {
+			headers[h.Key] = string(h.Value)
+		}
+	}
+
+	return Message{
+		Topic:     m.Topic,
+		Partition: m.Partition,
+		Offset:    m.Offset,
+		Key:       m.Key,
+		Value:     m.Value,
+		Headers:   headers,
+		Time:      m.Time,
+	}
+}
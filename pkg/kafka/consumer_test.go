@@ -0,0 +1,132 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReader отдает заданные сообщения по порядку и запоминает закоммиченные offset'ы
+// Когда сообщения заканчиваются, останавливает consumer через stop
+type fakeReader struct {
+	mu        sync.Mutex
+	messages  []kafkago.Message
+	fetched   int
+	committed []int64
+	stop      context.CancelFunc
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (kafkago.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.fetched == len(r.messages) {
+		r.stop()
+		return kafkago.Message{}, ctx.Err()
+	}
+	r.fetched++
+	return r.messages[r.fetched-1], nil
+}
+
+func (r *fakeReader) CommitMessages(ctx context.Context, msgs ...kafkago.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, m := range msgs {
+		r.committed = append(r.committed, m.Offset)
+	}
+	return nil
+}
+
+func (r *fakeReader) Stats() kafkago.ReaderStats { return kafkago.ReaderStats{} }
+func (r *fakeReader) Close() error               { return nil }
+
+func newTestConsumer(offsets ...int64) (*consumer, *fakeReader, context.Context) {
+	ctx, cancel := context.WithCancel(context.Background())
+	reader := &fakeReader{stop: cancel}
+	for _, offset := range offsets {
+		reader.messages = append(reader.messages, kafkago.Message{Topic: "order_events", Offset: offset})
+	}
+	return &consumer{reader: reader, topic: "order_events", redeliverBackoff: time.Millisecond}, reader, ctx
+}
+
+func TestConsumerRun_DLQFailureRedeliversSameMessage(t *testing.T) {
+	// Arrange
+	c, reader, ctx := newTestConsumer(1, 2)
+	dlq := &recordingProducer{failures: 2}
+
+	var handled []int64
+	handler := DeadLetter(func(ctx context.Context, msg Message) error {
+		handled = append(handled, msg.Offset)
+		if msg.Offset == 1 {
+			return errors.New("boom")
+		}
+		return nil
+	}, dlq)
+
+	// Act
+	err := c.Run(ctx, handler)
+
+	// Assert
+	require.NoError(t, err)
+	// Пока DLQ недоступна, обрабатывается то же сообщение; следующее читается только после отправки в DLQ
+	assert.Equal(t, []int64{1, 1, 1, 2}, handled)
+	assert.Equal(t, []int64{1, 2}, reader.committed)
+	require.Len(t, dlq.published, 1)
+	assert.Equal(t, strconv.Itoa(1), dlq.published[0].Headers[HeaderDLQOriginalOffset])
+}
+
+func TestConsumerRun_StopWhileDLQUnavailable(t *testing.T) {
+	// Arrange
+	c, reader, ctx := newTestConsumer(1, 2)
+
+	attempts := 0
+	handler := DeadLetter(func(context.Context, Message) error {
+		attempts++
+		if attempts == 2 {
+			reader.stop()
+		}
+		return errors.New("boom")
+	}, &recordingProducer{err: errors.New("kafka down")})
+
+	// Act
+	err := c.Run(ctx, handler)
+
+	// Assert
+	// Сообщение не закоммичено и следующее не прочитано: после перезапуска обработка продолжится с него
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, 1, reader.fetched)
+	assert.Empty(t, reader.committed)
+}
+
+func TestConsumerRunBatch_RedeliverKeepsBatch(t *testing.T) {
+	// Arrange
+	c, reader, ctx := newTestConsumer(1, 2, 3)
+
+	var batches [][]int64
+	handler := func(ctx context.Context, msgs []Message) error {
+		offsets := make([]int64, len(msgs))
+		for i, msg := range msgs {
+			offsets[i] = msg.Offset
+		}
+		batches = append(batches, offsets)
+		if len(batches) == 1 {
+			return Redeliver(errors.New("dlq unavailable"))
+		}
+		return nil
+	}
+
+	// Act
+	err := c.RunBatch(ctx, 2, time.Second, handler)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, [][]int64{{1, 2}, {1, 2}, {3}}, batches)
+	assert.Equal(t, []int64{1, 2, 3}, reader.committed)
+}
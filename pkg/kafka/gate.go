@@ -0,0 +1,118 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Gate приостанавливает чтение consumer, пока недоступны зависимости обработчика
+// На паузе consumer не читает новые сообщения, а сообщение с ошибкой не коммитится
+// и обрабатывается повторно после возобновления. Нулевой (nil) gate никогда не на паузе
+type Gate struct {
+	mu      sync.Mutex
+	state   GateState
+	resumed chan struct{} // Закрывается при возобновлении
+}
+
+// GateState - состояние паузы
+type GateState struct {
+	Paused bool      `json:"paused"`
+	Reason string    `json:"reason,omitempty"`
+	Since  time.Time `json:"since,omitempty"` // Начало паузы
+}
+
+// NewGate создает gate в открытом состоянии
+func NewGate() *Gate {
+	return &Gate{}
+}
+
+// Pause ставит чтение на паузу; возвращает true, если до этого пауза не действовала
+// Повторный вызов на паузе только обновляет причину
+func (g *Gate) Pause(reason string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.state.Reason = reason
+	if g.state.Paused {
+		return false
+	}
+	g.state.Paused = true
+	g.state.Since = time.Now()
+	g.resumed = make(chan struct{})
+	return true
+}
+
+// Resume снимает паузу; возвращает true, если пауза действовала
+func (g *Gate) Resume() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.state.Paused {
+		return false
+	}
+	g.state = GateState{}
+	close(g.resumed)
+	return true
+}
+
+// Paused сообщает, действует ли пауза
+func (g *Gate) Paused() bool {
+	return g.State().Paused
+}
+
+// State возвращает текущее состояние
+func (g *Gate) State() GateState {
+	if g == nil {
+		return GateState{}
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.state
+}
+
+// Wait блокируется до снятия паузы или отмены контекста
+func (g *Gate) Wait(ctx context.Context) error {
+	if g == nil {
+		return nil
+	}
+
+	g.mu.Lock()
+	if !g.state.Paused {
+		g.mu.Unlock()
+		return nil
+	}
+	resumed := g.resumed
+	g.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-resumed:
+		return nil
+	}
+}
+
+type unavailableError struct {
+	err error
+}
+
+func (e *unavailableError) Error() string { return e.err.Error() }
+func (e *unavailableError) Unwrap() error { return e.err }
+
+// Unavailable помечает ошибку как следствие недоступной зависимости (БД, кеш)
+// Retry не повторяет такую обработку, а DeadLetter не отправляет сообщение в DLQ:
+// оно остается незакоммиченным и обрабатывается снова после снятия паузы
+func Unavailable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &unavailableError{err: err}
+}
+
+// IsUnavailable проверяет, помечена ли ошибка через Unavailable
+func IsUnavailable(err error) bool {
+	var u *unavailableError
+	return errors.As(err, &u)
+}
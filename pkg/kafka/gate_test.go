@@ -0,0 +1,66 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ====== Gate Tests ======
+
+func TestGate_PauseAndResume(t *testing.T) {
+	gate := NewGate()
+
+	assert.True(t, gate.Pause("redis: connection refused"))
+	assert.False(t, gate.Pause("database: timeout"))
+
+	state := gate.State()
+	assert.True(t, state.Paused)
+	assert.Equal(t, "database: timeout", state.Reason)
+	assert.False(t, state.Since.IsZero())
+
+	assert.True(t, gate.Resume())
+	assert.False(t, gate.Resume())
+	assert.Equal(t, GateState{}, gate.State())
+}
+
+func TestGate_WaitReleasedOnResume(t *testing.T) {
+	gate := NewGate()
+	gate.Pause("database: down")
+
+	done := make(chan error, 1)
+	go func() { done <- gate.Wait(context.Background()) }()
+
+	select {
+	case <-done:
+		t.Fatal("Wait returned while paused")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	gate.Resume()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Wait not released after Resume")
+	}
+}
+
+func TestGate_WaitStopsOnContextCancel(t *testing.T) {
+	gate := NewGate()
+	gate.Pause("redis: down")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, gate.Wait(ctx), context.Canceled)
+}
+
+func TestGate_NilNeverPaused(t *testing.T) {
+	var gate *Gate
+
+	assert.False(t, gate.Paused())
+	assert.NoError(t, gate.Wait(context.Background()))
+}
@@ -0,0 +1,120 @@
+package kafka
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Заголовки метаданных события: по ним потребители фильтруют и дедуплицируют сообщения,
+// не разбирая значение. Producer заполняет их для каждого сообщения
+const (
+	HeaderEventID       = "event_id"       // Уникальный ID сообщения, ключ идемпотентности
+	HeaderEventType     = "event_type"     // Тип события (ORDER_CREATED, PRODUCT_UPDATED, ...)
+	HeaderSchemaVersion = "schema_version" // Версия схемы значения
+	HeaderProducer      = "producer"       // Сервис-отправитель
+	HeaderTraceParent   = "traceparent"    // Trace context в формате W3C
+)
+
+// EventMeta - метаданные события из заголовков сообщения
+// Сообщения, отправленные до появления заголовков, возвращают пустые поля
+type EventMeta struct {
+	ID            string
+	Type          string
+	SchemaVersion int
+	Producer      string
+	TraceParent   string
+}
+
+// MetaFromHeaders читает метаданные события из заголовков сообщения
+func MetaFromHeaders(headers map[string]string) EventMeta {
+	version, _ := strconv.Atoi(headers[HeaderSchemaVersion])
+	return EventMeta{
+		ID:            headers[HeaderEventID],
+		Type:          headers[HeaderEventType],
+		SchemaVersion: version,
+		Producer:      headers[HeaderProducer],
+		TraceParent:   headers[HeaderTraceParent],
+	}
+}
+
+// TraceID возвращает ID трассы из traceparent или пустую строку
+func (m EventMeta) TraceID() string {
+	if !validTraceParent(m.TraceParent) {
+		return ""
+	}
+	return m.TraceParent[3:35]
+}
+
+type eventKey struct{}
+
+type eventInfo struct {
+	eventType     string
+	schemaVersion int
+}
+
+// WithEvent задает тип и версию схемы события для сообщений, отправленных с этим контекстом
+// В отличие от ContextWithHeaders, метаданные события не наследуются событиями,
+// которые отправляет consumer при обработке сообщения
+func WithEvent(ctx context.Context, eventType string, schemaVersion int) context.Context {
+	return context.WithValue(ctx, eventKey{}, eventInfo{eventType: eventType, schemaVersion: schemaVersion})
+}
+
+// eventHeaders дополняет заголовки из контекста метаданными нового сообщения
+// Метаданные входящего сообщения отбрасываются; trace продолжается с новым span
+func eventHeaders(ctx context.Context, propagated map[string]string, service string) map[string]string {
+	headers := make(map[string]string, len(propagated)+5)
+	for k, v := range propagated {
+		switch k {
+		case HeaderEventID, HeaderEventType, HeaderSchemaVersion, HeaderProducer:
+			continue
+		}
+		headers[k] = v
+	}
+
+	headers[HeaderEventID] = uuid.NewString()
+	headers[HeaderTraceParent] = ChildTraceParent(propagated[HeaderTraceParent])
+	if service != "" {
+		headers[HeaderProducer] = service
+	}
+	if event, ok := ctx.Value(eventKey{}).(eventInfo); ok {
+		headers[HeaderEventType] = event.eventType
+		headers[HeaderSchemaVersion] = strconv.Itoa(event.schemaVersion)
+	}
+
+	return headers
+}
+
+// ChildTraceParent возвращает traceparent нового span в трассе parent
+// Без корректного parent начинается новая трасса
+func ChildTraceParent(parent string) string {
+	traceID := randomHex(16)
+	if validTraceParent(parent) {
+		traceID = parent[3:35]
+	}
+	return "00-" + traceID + "-" + randomHex(8) + "-01"
+}
+
+// validTraceParent проверяет формат version-traceid-spanid-flags
+func validTraceParent(value string) bool {
+	parts := strings.Split(value, "-")
+	if len(parts) != 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return false
+	}
+	for _, part := range parts {
+		if _, err := hex.DecodeString(part); err != nil {
+			return false
+		}
+	}
+	return parts[1] != strings.Repeat("0", 32)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
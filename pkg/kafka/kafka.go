@@ -0,0 +1,81 @@
+package kafka
+
+import (
+	"context"
+	"time"
+)
+
+// Message - сообщение Kafka, независимое от клиентской библиотеки
+type Message struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	Key       []byte
+	Value     []byte
+	Headers   map[string]string
+	Time      time.Time
+}
+
+// Producer отправляет сообщения в топик, заданный при создании
+type Producer interface {
+	// PublishMessage отправляет одно сообщение с заголовками из контекста
+	// Тип события для заголовка event_type задается через WithEvent
+	PublishMessage(ctx context.Context, key string, value []byte) error
+	// Publish отправляет несколько сообщений одним вызовом
+	Publish(ctx context.Context, messages ...Message) error
+	// Close дожидается отправки буферизованных сообщений и закрывает соединения
+	Close() error
+}
+
+// Handler обрабатывает одно сообщение
+// Ошибка означает, что сообщение не обработано и его offset не коммитится
+type Handler func(ctx context.Context, msg Message) error
+
+// BatchHandler обрабатывает пачку сообщений
+// Ошибка означает, что пачка не обработана и offset'ы ее сообщений не коммитятся
+type BatchHandler func(ctx context.Context, msgs []Message) error
+
+// Consumer читает сообщения из топика (или нескольких топиков) в составе consumer group
+type Consumer interface {
+	// Run читает сообщения и передает их handler до отмены контекста
+	// Offset коммитится только после успешной обработки; на паузе Gate чтение приостанавливается
+	Run(ctx context.Context, handler Handler) error
+	// RunBatch копит до size сообщений, но не дольше flushInterval с первого сообщения пачки,
+	// и передает их handler до отмены контекста. Offset'ы коммитятся после успешной обработки пачки
+	RunBatch(ctx context.Context, size int, flushInterval time.Duration, handler BatchHandler) error
+	// Stats возвращает статистику чтения с момента предыдущего вызова
+	Stats() Stats
+	// Close закрывает reader
+	Close() error
+}
+
+// Stats - статистика consumer
+type Stats struct {
+	Topic    string
+	Messages int64 // Прочитано сообщений
+	Errors   int64 // Ошибок чтения
+	Lag      int64 // Отставание от конца партиции
+}
+
+type headersKey struct{}
+
+// ContextWithHeaders сохраняет заголовки в контексте
+// Producer добавляет их ко всем сообщениям, отправленным с этим контекстом
+func ContextWithHeaders(ctx context.Context, headers map[string]string) context.Context {
+	merged := make(map[string]string, len(headers))
+	for k, v := range HeadersFromContext(ctx) {
+		merged[k] = v
+	}
+	for k, v := range headers {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, headersKey{}, merged)
+}
+
+// HeadersFromContext возвращает заголовки, сохраненные в контексте
+// Consumer кладет в контекст заголовки входящего сообщения, поэтому события,
+// отправленные при его обработке, наследуют их (например, trace context)
+func HeadersFromContext(ctx context.Context) map[string]string {
+	headers, _ := ctx.Value(headersKey{}).(map[string]string)
+	return headers
+}
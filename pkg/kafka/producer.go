@@ -0,0 +1,143 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"augustberries/pkg/metrics"
+
+	kafkago "github.com/segmentio/kafka-go"
+)
+
+// ProducerConfig - настройки producer
+// Нулевые значения опций означают настройки kafka-go по умолчанию
+type ProducerConfig struct {
+	Brokers     []string      // Список брокеров Kafka (формат: host:port)
+	Topic       string        // Топик для отправки
+	Service     string        // Имя сервиса для меток метрик
+	Compression string        // Кодек сжатия: none, gzip, snappy, lz4, zstd
+	BatchSize   int           // Максимум сообщений в батче
+	BatchBytes  int64         // Максимальный размер батча в байтах
+	Linger      time.Duration // Сколько ждать заполнения батча перед отправкой
+	Acks        string        // Уровень подтверждения: none, one, all
+	// Idempotent требует acks=all и направляет сообщения с одним ключом в одну партицию,
+	// чтобы повторные отправки не нарушали порядок событий.
+	// kafka-go не поддерживает idempotent producer протокола Kafka, поэтому потребители
+	// по-прежнему должны быть готовы к дубликатам
+	Idempotent bool
+}
+
+type producer struct {
+	writer  *kafkago.Writer
+	topic   string
+	service string
+}
+
+// NewProducer создает producer с заданными настройками
+func NewProducer(cfg ProducerConfig) (Producer, error) {
+	p := &producer{topic: cfg.Topic, service: cfg.Service}
+
+	p.writer = &kafkago.Writer{
+		Addr:         kafkago.TCP(cfg.Brokers...),
+		Topic:        cfg.Topic,
+		Balancer:     &kafkago.LeastBytes{},
+		BatchSize:    cfg.BatchSize,
+		BatchBytes:   cfg.BatchBytes,
+		BatchTimeout: cfg.Linger,
+		Completion:   p.recordBatchFlush,
+	}
+
+	if cfg.Compression != "" {
+		if err := p.writer.Compression.UnmarshalText([]byte(cfg.Compression)); err != nil {
+			return nil, fmt.Errorf("invalid kafka compression: %w", err)
+		}
+	}
+
+	if cfg.Acks != "" {
+		if err := p.writer.RequiredAcks.UnmarshalText([]byte(cfg.Acks)); err != nil {
+			return nil, fmt.Errorf("invalid kafka acks: %w", err)
+		}
+	}
+
+	if cfg.Idempotent {
+		if cfg.Acks != "" && p.writer.RequiredAcks != kafkago.RequireAll {
+			return nil, fmt.Errorf("idempotent producer requires acks=all, got %q", cfg.Acks)
+		}
+		p.writer.RequiredAcks = kafkago.RequireAll
+		p.writer.Balancer = &kafkago.Hash{}
+	}
+
+	return p, nil
+}
+
+func (p *producer) PublishMessage(ctx context.Context, key string, value []byte) error {
+	return p.Publish(ctx, Message{Key: []byte(key), Value: value})
+}
+
+// Publish отправляет сообщения; каждое получает заголовки метаданных события (см. headers.go)
+func (p *producer) Publish(ctx context.Context, messages ...Message) error {
+	start := time.Now()
+	propagated := HeadersFromContext(ctx)
+
+	batch := make([]kafkago.Message, len(messages))
+	for i, m := range messages {
+		batch[i] = kafkago.Message{
+			Key:     m.Key,
+			Value:   m.Value,
+			Headers: toKafkaHeaders(eventHeaders(ctx, propagated, p.service), m.Headers),
+			Time:    start,
+		}
+	}
+
+	if err := p.writer.WriteMessages(ctx, batch...); err != nil {
+		metrics.RecordKafkaError(p.service, p.topic, "produce")
+		return fmt.Errorf("failed to write message to kafka: %w", err)
+	}
+
+	metrics.KafkaMessagesProduced.WithLabelValues(p.service, p.topic).Add(float64(len(batch)))
+	metrics.KafkaProduceDuration.WithLabelValues(p.service, p.topic).Observe(time.Since(start).Seconds())
+	return nil
+}
+
+func (p *producer) Close() error {
+	return p.writer.Close()
+}
+
+// recordBatchFlush записывает метрики отправленного батча
+// Задержка считается от постановки в очередь самого старого сообщения до подтверждения брокером
+func (p *producer) recordBatchFlush(messages []kafkago.Message, err error) {
+	if err != nil || len(messages) == 0 {
+		return
+	}
+
+	oldest := messages[0].Time
+	for _, m := range messages[1:] {
+		if m.Time.Before(oldest) {
+			oldest = m.Time
+		}
+	}
+
+	metrics.RecordKafkaBatchFlush(p.service, p.topic, len(messages), time.Since(oldest))
+}
+
+// toKafkaHeaders объединяет заголовки из контекста и сообщения (заголовки сообщения важнее)
+func toKafkaHeaders(propagated, own map[string]string) []kafkago.Header {
+	if len(propagated) == 0 && len(own) == 0 {
+		return nil
+	}
+
+	merged := make(map[string]string, len(propagated)+len(own))
+	for k, v := range propagated {
+		merged[k] = v
+	}
+	for k, v := range own {
+		merged[k] = v
+	}
+
+	headers := make([]kafkago.Header, 0, len(merged))
+	for k, v := range merged {
+		headers = append(headers, kafkago.Header{Key: k, Value: []byte(v)})
+	}
+	return headers
+}
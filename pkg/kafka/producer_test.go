@@ -0,0 +1,147 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProducer(t *testing.T, cfg ProducerConfig) *producer {
+	cfg.Brokers = []string{"localhost:9092"}
+	cfg.Topic = "order_events"
+
+	p, err := NewProducer(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { p.Close() })
+	return p.(*producer)
+}
+
+// ====== Producer Config Tests ======
+
+func TestNewProducer_AppliesOptions(t *testing.T) {
+	p := newTestProducer(t, ProducerConfig{
+		Compression: "zstd",
+		BatchSize:   50,
+		BatchBytes:  512 * 1024,
+		Linger:      5 * time.Millisecond,
+		Acks:        "one",
+	})
+
+	assert.Equal(t, kafkago.Zstd, p.writer.Compression)
+	assert.Equal(t, 50, p.writer.BatchSize)
+	assert.Equal(t, int64(512*1024), p.writer.BatchBytes)
+	assert.Equal(t, 5*time.Millisecond, p.writer.BatchTimeout)
+	assert.Equal(t, kafkago.RequireOne, p.writer.RequiredAcks)
+	assert.IsType(t, &kafkago.LeastBytes{}, p.writer.Balancer)
+}
+
+func TestNewProducer_Idempotent(t *testing.T) {
+	p := newTestProducer(t, ProducerConfig{Idempotent: true})
+
+	assert.Equal(t, kafkago.RequireAll, p.writer.RequiredAcks)
+	assert.IsType(t, &kafkago.Hash{}, p.writer.Balancer)
+}
+
+func TestNewProducer_InvalidOptions(t *testing.T) {
+	cases := map[string]ProducerConfig{
+		"unknown compression":    {Compression: "brotli"},
+		"unknown acks":           {Acks: "most"},
+		"idempotent without all": {Acks: "one", Idempotent: true},
+	}
+
+	for name, cfg := range cases {
+		t.Run(name, func(t *testing.T) {
+			p, err := NewProducer(cfg)
+			assert.Error(t, err)
+			assert.Nil(t, p)
+		})
+	}
+}
+
+// ====== Header Propagation Tests ======
+
+func TestContextWithHeaders_Merges(t *testing.T) {
+	ctx := ContextWithHeaders(context.Background(), map[string]string{"traceparent": "a", "x-request-id": "1"})
+	ctx = ContextWithHeaders(ctx, map[string]string{"x-request-id": "2"})
+
+	assert.Equal(t, map[string]string{"traceparent": "a", "x-request-id": "2"}, HeadersFromContext(ctx))
+	assert.Nil(t, HeadersFromContext(context.Background()))
+}
+
+func TestToKafkaHeaders_MessageOverridesContext(t *testing.T) {
+	headers := toKafkaHeaders(
+		map[string]string{"traceparent": "from-ctx", "x-request-id": "1"},
+		map[string]string{"traceparent": "own"},
+	)
+
+	got := make(map[string]string)
+	for _, h := range headers {
+		got[h.Key] = string(h.Value)
+	}
+	assert.Equal(t, map[string]string{"traceparent": "own", "x-request-id": "1"}, got)
+	assert.Nil(t, toKafkaHeaders(nil, nil))
+}
+
+func TestFromKafkaMessage_Headers(t *testing.T) {
+	msg := fromKafkaMessage(kafkago.Message{
+		Topic:   "order_events",
+		Offset:  42,
+		Value:   []byte("{}"),
+		Headers: []kafkago.Header{{Key: HeaderContentType, Value: []byte("application/json")}},
+	})
+
+	assert.Equal(t, int64(42), msg.Offset)
+	assert.Equal(t, "application/json", msg.Headers[HeaderContentType])
+}
+
+func TestRecordBatchFlush_IgnoresFailedBatches(t *testing.T) {
+	p := newTestProducer(t, ProducerConfig{Service: "test"})
+
+	// Не должно паниковать на пустых и неудачных батчах
+	p.recordBatchFlush(nil, nil)
+	p.recordBatchFlush([]kafkago.Message{{Time: time.Now()}}, assert.AnError)
+	p.recordBatchFlush([]kafkago.Message{{Time: time.Now().Add(-time.Second)}, {Time: time.Now()}}, nil)
+}
+
+// ====== Event Header Tests ======
+
+func TestEventHeaders_FillsMetadata(t *testing.T) {
+	ctx := WithEvent(context.Background(), "ORDER_CREATED", 2)
+
+	headers := eventHeaders(ctx, nil, "orders-service")
+	meta := MetaFromHeaders(headers)
+
+	assert.NotEmpty(t, meta.ID)
+	assert.Equal(t, "ORDER_CREATED", meta.Type)
+	assert.Equal(t, 2, meta.SchemaVersion)
+	assert.Equal(t, "orders-service", meta.Producer)
+	assert.Len(t, meta.TraceID(), 32)
+	assert.NotEqual(t, meta.ID, MetaFromHeaders(eventHeaders(ctx, nil, "orders-service")).ID)
+}
+
+func TestEventHeaders_ContinuesTraceButNotEvent(t *testing.T) {
+	// Событие, отправленное при обработке входящего, продолжает его трассу, но получает свои метаданные
+	incoming := eventHeaders(WithEvent(context.Background(), "USER_UPDATED", 1), nil, "auth-service")
+	ctx := ContextWithHeaders(context.Background(), incoming)
+
+	headers := eventHeaders(ctx, HeadersFromContext(ctx), "reviews-service")
+	meta := MetaFromHeaders(headers)
+	parent := MetaFromHeaders(incoming)
+
+	assert.Equal(t, parent.TraceID(), meta.TraceID())
+	assert.NotEqual(t, parent.TraceParent, meta.TraceParent)
+	assert.NotEqual(t, parent.ID, meta.ID)
+	assert.Empty(t, meta.Type)
+	assert.Equal(t, "reviews-service", meta.Producer)
+}
+
+func TestEventHeaders_InvalidTraceParentStartsNewTrace(t *testing.T) {
+	headers := eventHeaders(context.Background(), map[string]string{HeaderTraceParent: "garbage"}, "")
+
+	assert.True(t, validTraceParent(headers[HeaderTraceParent]))
+	assert.NotContains(t, headers, HeaderProducer)
+}
@@ -0,0 +1,202 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+)
+
+// replayIdleTimeout - сколько ждать следующего сообщения партиции, прежде чем считать ее прочитанной
+// Нужен, если последние offset'ы заняты служебными записями и сообщений до конца партиции нет
+const replayIdleTimeout = 10 * time.Second
+
+// ReplayConfig - настройки повторного чтения топика
+type ReplayConfig struct {
+	Brokers  []string // Список брокеров Kafka (формат: host:port)
+	Topic    string   // Топик для чтения
+	MaxBytes int      // Максимум байт для fetch запроса
+}
+
+// ReplayPosition - с какого места читать каждую партицию
+// Если задан Since, чтение начинается с первого сообщения не раньше этого момента,
+// иначе с Offset (значения за пределами партиции приводятся к ее границам)
+type ReplayPosition struct {
+	Offset int64
+	Since  time.Time
+}
+
+// PartitionOffsets - границы партиции: First - первый доступный offset, Last - offset следующего сообщения
+type PartitionOffsets struct {
+	Partition int   `json:"partition"`
+	First     int64 `json:"first"`
+	Last      int64 `json:"last"`
+}
+
+// Replayer перечитывает сообщения топика вне consumer group
+// Закоммиченные offset'ы групп при этом не меняются
+type Replayer interface {
+	// Offsets возвращает границы всех партиций топика
+	Offsets(ctx context.Context) ([]PartitionOffsets, error)
+	// Replay передает handler сообщения от позиции from до конца партиций на момент вызова
+	// Ошибка handler прерывает чтение
+	Replay(ctx context.Context, from ReplayPosition, handler Handler) error
+}
+
+type replayer struct {
+	cfg ReplayConfig
+}
+
+// NewReplayer создает Replayer для топика
+func NewReplayer(cfg ReplayConfig) Replayer {
+	return &replayer{cfg: cfg}
+}
+
+func (r *replayer) Offsets(ctx context.Context) ([]PartitionOffsets, error) {
+	partitions, err := r.partitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	offsets := make([]PartitionOffsets, 0, len(partitions))
+	for _, partition := range partitions {
+		bounds, _, err := r.bounds(ctx, partition, time.Time{})
+		if err != nil {
+			return nil, err
+		}
+		offsets = append(offsets, bounds)
+	}
+	return offsets, nil
+}
+
+func (r *replayer) Replay(ctx context.Context, from ReplayPosition, handler Handler) error {
+	partitions, err := r.partitions(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, partition := range partitions {
+		bounds, sinceOffset, err := r.bounds(ctx, partition, from.Since)
+		if err != nil {
+			return err
+		}
+
+		start := clampOffset(from.Offset, bounds)
+		if !from.Since.IsZero() {
+			start = clampOffset(sinceOffset, bounds)
+		}
+		if start >= bounds.Last {
+			continue
+		}
+
+		if err := r.replayPartition(ctx, partition, start, bounds.Last, handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replayPartition читает партицию с offset start до end (не включая)
+func (r *replayer) replayPartition(ctx context.Context, partition int, start, end int64, handler Handler) error {
+	reader := kafkago.NewReader(kafkago.ReaderConfig{
+		Brokers:   r.cfg.Brokers,
+		Topic:     r.cfg.Topic,
+		Partition: partition,
+		MaxBytes:  r.cfg.MaxBytes,
+	})
+	defer reader.Close()
+
+	if err := reader.SetOffset(start); err != nil {
+		return fmt.Errorf("failed to set offset %d for partition %d: %w", start, partition, err)
+	}
+
+	for {
+		readCtx, cancel := context.WithTimeout(ctx, replayIdleTimeout)
+		m, err := reader.ReadMessage(readCtx)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if errors.Is(err, context.DeadlineExceeded) {
+				return nil
+			}
+			return fmt.Errorf("failed to read partition %d: %w", partition, err)
+		}
+		if m.Offset >= end {
+			return nil
+		}
+
+		if err := handler(ctx, fromKafkaMessage(m)); err != nil {
+			return err
+		}
+		if m.Offset >= end-1 {
+			return nil
+		}
+	}
+}
+
+func (r *replayer) partitions(ctx context.Context) ([]int, error) {
+	if len(r.cfg.Brokers) == 0 {
+		return nil, errors.New("no kafka brokers configured")
+	}
+
+	conn, err := kafkago.DialContext(ctx, "tcp", r.cfg.Brokers[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to kafka: %w", err)
+	}
+	defer conn.Close()
+
+	partitions, err := conn.ReadPartitions(r.cfg.Topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read partitions of %s: %w", r.cfg.Topic, err)
+	}
+
+	ids := make([]int, len(partitions))
+	for i, p := range partitions {
+		ids[i] = p.ID
+	}
+	return ids, nil
+}
+
+// bounds возвращает границы партиции и, если задан since, первый offset не раньше since
+func (r *replayer) bounds(ctx context.Context, partition int, since time.Time) (PartitionOffsets, int64, error) {
+	conn, err := kafkago.DialLeader(ctx, "tcp", r.cfg.Brokers[0], r.cfg.Topic, partition)
+	if err != nil {
+		return PartitionOffsets{}, 0, fmt.Errorf("failed to connect to leader of partition %d: %w", partition, err)
+	}
+	defer conn.Close()
+
+	first, last, err := conn.ReadOffsets()
+	if err != nil {
+		return PartitionOffsets{}, 0, fmt.Errorf("failed to read offsets of partition %d: %w", partition, err)
+	}
+	bounds := PartitionOffsets{Partition: partition, First: first, Last: last}
+
+	if since.IsZero() {
+		return bounds, first, nil
+	}
+
+	offset, err := conn.ReadOffset(since)
+	if err != nil {
+		return PartitionOffsets{}, 0, fmt.Errorf("failed to find offset of partition %d at %s: %w", partition, since, err)
+	}
+	// Брокер возвращает -1, если сообщений позже since нет
+	if offset < 0 {
+		offset = last
+	}
+	return bounds, offset, nil
+}
+
+// clampOffset приводит offset к границам партиции
+func clampOffset(offset int64, bounds PartitionOffsets) int64 {
+	if offset < bounds.First {
+		return bounds.First
+	}
+	if offset > bounds.Last {
+		return bounds.Last
+	}
+	return offset
+}
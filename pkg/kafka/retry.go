@@ -0,0 +1,134 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+)
+
+// Заголовки, которые DeadLetter добавляет к сообщению в DLQ
+const (
+	HeaderDLQOriginalTopic     = "x-original-topic"
+	HeaderDLQOriginalPartition = "x-original-partition"
+	HeaderDLQOriginalOffset    = "x-original-offset"
+	HeaderDLQError             = "x-error"
+)
+
+// RetryPolicy - политика повторной обработки с экспоненциальной задержкой
+type RetryPolicy struct {
+	MaxAttempts    int           // Общее число попыток, включая первую
+	InitialBackoff time.Duration // Задержка перед второй попыткой
+	MaxBackoff     time.Duration // Максимальная задержка между попытками
+}
+
+// DefaultRetryPolicy - 3 попытки с задержкой 200ms, 400ms
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 200 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent помечает ошибку как неисправимую: Retry не повторяет такую обработку
+// Используется для некорректных сообщений (например, невалидный JSON)
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent проверяет, помечена ли ошибка через Permanent
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+type redeliverError struct {
+	err error
+}
+
+func (e *redeliverError) Error() string { return e.err.Error() }
+func (e *redeliverError) Unwrap() error { return e.err }
+
+// Redeliver помечает ошибку, после которой consumer обрабатывает то же сообщение снова,
+// не переходя к следующему offset (например, DLQ недоступна и сообщение некуда отложить)
+func Redeliver(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &redeliverError{err: err}
+}
+
+// IsRedeliver проверяет, помечена ли ошибка через Redeliver
+func IsRedeliver(err error) bool {
+	var r *redeliverError
+	return errors.As(err, &r)
+}
+
+// Retry повторяет обработку сообщения согласно политике
+// Повторы прекращаются при отмене контекста, permanent или unavailable ошибке
+func Retry(handler Handler, policy RetryPolicy) Handler {
+	return func(ctx context.Context, msg Message) error {
+		backoff := policy.InitialBackoff
+
+		var err error
+		for attempt := 1; ; attempt++ {
+			if err = handler(ctx, msg); err == nil || IsPermanent(err) || IsUnavailable(err) || attempt >= policy.MaxAttempts {
+				return err
+			}
+
+			log.Printf("Attempt %d/%d for message %s/%d@%d failed: %v", attempt, policy.MaxAttempts, msg.Topic, msg.Partition, msg.Offset, err)
+
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(backoff):
+			}
+
+			backoff *= 2
+			if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+				backoff = policy.MaxBackoff
+			}
+		}
+	}
+}
+
+// DeadLetter отправляет сообщение, которое не удалось обработать, в DLQ producer
+// и считает его обработанным, чтобы consumer закоммитил offset и продолжил чтение.
+// Если отправка в DLQ не удалась, возвращается исходная ошибка с пометкой Redeliver: consumer не коммитит offset
+// и обрабатывает это же сообщение снова, пока оно не будет обработано или отправлено в DLQ.
+// Сообщения с unavailable ошибкой в DLQ не отправляются: после восстановления зависимостей они обрабатываются снова
+func DeadLetter(handler Handler, dlq Producer) Handler {
+	return func(ctx context.Context, msg Message) error {
+		err := handler(ctx, msg)
+		if err == nil || IsUnavailable(err) {
+			return err
+		}
+
+		headers := make(map[string]string, len(msg.Headers)+4)
+		for k, v := range msg.Headers {
+			headers[k] = v
+		}
+		headers[HeaderDLQOriginalTopic] = msg.Topic
+		headers[HeaderDLQOriginalPartition] = strconv.Itoa(msg.Partition)
+		headers[HeaderDLQOriginalOffset] = strconv.FormatInt(msg.Offset, 10)
+		headers[HeaderDLQError] = err.Error()
+
+		if dlqErr := dlq.Publish(ctx, Message{Key: msg.Key, Value: msg.Value, Headers: headers}); dlqErr != nil {
+			return Redeliver(fmt.Errorf("%w (failed to publish to DLQ: %v)", err, dlqErr))
+		}
+
+		log.Printf("Message %s/%d@%d moved to DLQ: %v", msg.Topic, msg.Partition, msg.Offset, err)
+		return nil
+	}
+}
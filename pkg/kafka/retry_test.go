@@ -0,0 +1,163 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRetryPolicy = RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+
+// recordingProducer запоминает отправленные сообщения
+type recordingProducer struct {
+	published []Message
+	err       error // Все отправки завершаются ошибкой
+	failures  int   // Сколько первых отправок завершаются ошибкой
+}
+
+func (p *recordingProducer) PublishMessage(ctx context.Context, key string, value []byte) error {
+	return p.Publish(ctx, Message{Key: []byte(key), Value: value})
+}
+
+func (p *recordingProducer) Publish(ctx context.Context, messages ...Message) error {
+	if p.err != nil {
+		return p.err
+	}
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("kafka down")
+	}
+	p.published = append(p.published, messages...)
+	return nil
+}
+
+func (p *recordingProducer) Close() error { return nil }
+
+// ====== Retry Tests ======
+
+func TestRetry_SucceedsAfterTransientErrors(t *testing.T) {
+	attempts := 0
+	handler := Retry(func(ctx context.Context, msg Message) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("temporary")
+		}
+		return nil
+	}, testRetryPolicy)
+
+	assert.NoError(t, handler(context.Background(), Message{}))
+	assert.Equal(t, 3, attempts)
+}
+
+func TestRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	attempts := 0
+	handler := Retry(func(ctx context.Context, msg Message) error {
+		attempts++
+		return errors.New("still failing")
+	}, testRetryPolicy)
+
+	assert.Error(t, handler(context.Background(), Message{}))
+	assert.Equal(t, 3, attempts)
+}
+
+func TestRetry_PermanentErrorNotRetried(t *testing.T) {
+	attempts := 0
+	cause := errors.New("bad payload")
+	handler := Retry(func(ctx context.Context, msg Message) error {
+		attempts++
+		return Permanent(cause)
+	}, testRetryPolicy)
+
+	err := handler(context.Background(), Message{})
+	assert.ErrorIs(t, err, cause)
+	assert.True(t, IsPermanent(err))
+	assert.Equal(t, 1, attempts)
+}
+
+func TestRetry_StopsOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	attempts := 0
+	handler := Retry(func(ctx context.Context, msg Message) error {
+		attempts++
+		return errors.New("temporary")
+	}, RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour})
+
+	assert.Error(t, handler(ctx, Message{}))
+	assert.Equal(t, 1, attempts)
+}
+
+// ====== DeadLetter Tests ======
+
+func TestDeadLetter_PublishesFailedMessage(t *testing.T) {
+	dlq := &recordingProducer{}
+	handler := DeadLetter(func(ctx context.Context, msg Message) error {
+		return errors.New("boom")
+	}, dlq)
+
+	msg := Message{
+		Topic:     "order_events",
+		Partition: 2,
+		Offset:    17,
+		Key:       []byte("order-1"),
+		Value:     []byte("{}"),
+		Headers:   map[string]string{"traceparent": "abc"},
+	}
+
+	require.NoError(t, handler(context.Background(), msg))
+	require.Len(t, dlq.published, 1)
+
+	dead := dlq.published[0]
+	assert.Equal(t, msg.Key, dead.Key)
+	assert.Equal(t, msg.Value, dead.Value)
+	assert.Equal(t, "order_events", dead.Headers[HeaderDLQOriginalTopic])
+	assert.Equal(t, "2", dead.Headers[HeaderDLQOriginalPartition])
+	assert.Equal(t, "17", dead.Headers[HeaderDLQOriginalOffset])
+	assert.Equal(t, "boom", dead.Headers[HeaderDLQError])
+	assert.Equal(t, "abc", dead.Headers["traceparent"])
+	// Заголовки исходного сообщения не изменяются
+	assert.Len(t, msg.Headers, 1)
+}
+
+func TestDeadLetter_PublishFailureKeepsError(t *testing.T) {
+	cause := errors.New("boom")
+	handler := DeadLetter(func(ctx context.Context, msg Message) error {
+		return cause
+	}, &recordingProducer{err: errors.New("kafka down")})
+
+	err := handler(context.Background(), Message{})
+
+	assert.ErrorIs(t, err, cause)
+	// Сообщение некуда отложить: consumer должен обработать его снова, а не читать следующее
+	assert.True(t, IsRedeliver(err))
+}
+
+func TestDeadLetter_SuccessNotPublished(t *testing.T) {
+	dlq := &recordingProducer{}
+	handler := DeadLetter(func(ctx context.Context, msg Message) error {
+		return nil
+	}, dlq)
+
+	assert.NoError(t, handler(context.Background(), Message{}))
+	assert.Empty(t, dlq.published)
+}
+
+func TestDeadLetter_UnavailableNotPublished(t *testing.T) {
+	dlq := &recordingProducer{}
+	attempts := 0
+	handler := DeadLetter(Retry(func(ctx context.Context, msg Message) error {
+		attempts++
+		return Unavailable(errors.New("database is down"))
+	}, testRetryPolicy), dlq)
+
+	err := handler(context.Background(), Message{})
+
+	assert.True(t, IsUnavailable(err))
+	assert.Equal(t, 1, attempts)
+	assert.Empty(t, dlq.published)
+}
@@ -0,0 +1,190 @@
+// Package lock реализует распределенную блокировку на Redis по схеме Redlock для одного узла:
+// аренда с TTL, продление, снятие и fencing token, который растет с каждым захватом.
+// Используется, чтобы периодические задачи выполнялись на одном экземпляре сервиса
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrNotAcquired - блокировку держит другой экземпляр
+	ErrNotAcquired = errors.New("lock not acquired")
+	// ErrLeaseLost - аренда истекла и, возможно, захвачена другим экземпляром
+	ErrLeaseLost = errors.New("lock lease lost")
+)
+
+// clockDriftFactor - доля TTL на расхождение часов Redis и процесса (по алгоритму Redlock)
+const clockDriftFactor = 0.01
+
+// Ключи аренды и счетчика fencing token в одном hash slot, чтобы скрипт работал в Redis Cluster
+var (
+	acquireScript = goredis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return redis.call("INCR", KEYS[2])
+end
+return 0`)
+
+	renewScript = goredis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+	releaseScript = goredis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// Locker выдает аренды блокировок
+// Nil-Locker ничего не блокирует: Once просто выполняет задачу (один экземпляр, Redis не настроен)
+type Locker struct {
+	client goredis.UniversalClient
+	prefix string
+}
+
+// New создает Locker; prefix отделяет блокировки сервиса (например, "catalog-service")
+func New(client goredis.UniversalClient, prefix string) *Locker {
+	return &Locker{client: client, prefix: prefix}
+}
+
+// Lease - захваченная блокировка
+type Lease struct {
+	locker     *Locker
+	key        string
+	value      string // Случайное значение владельца: чужую аренду нельзя продлить или снять
+	token      int64
+	validUntil time.Time
+}
+
+// Acquire захватывает блокировку name на ttl
+// ErrNotAcquired - блокировку держит другой экземпляр
+func (l *Locker) Acquire(ctx context.Context, name string, ttl time.Duration) (*Lease, error) {
+	value, err := randomValue()
+	if err != nil {
+		return nil, err
+	}
+
+	key := l.key(name)
+	start := time.Now()
+	token, err := acquireScript.Run(ctx, l.client, []string{key, key + ":fence"}, value, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock %s: %w", name, err)
+	}
+	if token == 0 {
+		return nil, ErrNotAcquired
+	}
+
+	return &Lease{
+		locker:     l,
+		key:        key,
+		value:      value,
+		token:      token,
+		validUntil: validUntil(start, ttl),
+	}, nil
+}
+
+// Token возвращает fencing token: у каждого следующего захвата блокировки он больше
+// Хранилище может отклонять записи с токеном меньше уже виденного
+func (lease *Lease) Token() int64 {
+	return lease.token
+}
+
+// Valid сообщает, что аренда еще действует с учетом расхождения часов
+func (lease *Lease) Valid() bool {
+	return time.Now().Before(lease.validUntil)
+}
+
+// Renew продлевает аренду на ttl; ErrLeaseLost - блокировка уже не принадлежит этой аренде
+func (lease *Lease) Renew(ctx context.Context, ttl time.Duration) error {
+	start := time.Now()
+	ok, err := renewScript.Run(ctx, lease.locker.client, []string{lease.key}, lease.value, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return fmt.Errorf("failed to renew lock %s: %w", lease.key, err)
+	}
+	if ok == 0 {
+		return ErrLeaseLost
+	}
+
+	lease.validUntil = validUntil(start, ttl)
+	return nil
+}
+
+// Release снимает блокировку, если она еще принадлежит этой аренде
+func (lease *Lease) Release(ctx context.Context) error {
+	if err := releaseScript.Run(ctx, lease.locker.client, []string{lease.key}, lease.value).Err(); err != nil {
+		return fmt.Errorf("failed to release lock %s: %w", lease.key, err)
+	}
+	return nil
+}
+
+// Once выполняет fn, если блокировку name удалось захватить, иначе возвращает ErrNotAcquired
+// Пока fn работает, аренда продлевается каждую треть ttl; при потере аренды контекст fn отменяется.
+// После завершения fn блокировка не снимается, а истекает через ttl: экземпляры, у которых тот же запуск
+// cron сработал на несколько секунд позже, его пропускают. ttl должен быть меньше интервала расписания
+func (l *Locker) Once(ctx context.Context, name string, ttl time.Duration, fn func(ctx context.Context)) error {
+	if l == nil {
+		fn(ctx)
+		return nil
+	}
+
+	lease, err := l.Acquire(ctx, name, ttl)
+	if err != nil {
+		return err
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(runCtx)
+	}()
+
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return nil
+		case <-ticker.C:
+			if err := lease.Renew(ctx, ttl); err != nil {
+				log.Printf("WARNING: lost lock %s (token %d): %v", name, lease.token, err)
+				cancel()
+				<-done
+				return ErrLeaseLost
+			}
+		}
+	}
+}
+
+func (l *Locker) key(name string) string {
+	// Фигурные скобки - hash tag: ключ аренды и счетчик попадают в один slot
+	return "lock:{" + l.prefix + ":" + name + "}"
+}
+
+// validUntil - момент, до которого аренда гарантированно действует
+func validUntil(start time.Time, ttl time.Duration) time.Time {
+	drift := time.Duration(float64(ttl)*clockDriftFactor) + 2*time.Millisecond
+	return start.Add(ttl - drift)
+}
+
+func randomValue() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate lock value: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,98 @@
+package lock
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLocker(t *testing.T) (*Locker, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return New(client, "test"), server
+}
+
+// ==================== Acquire Tests ====================
+
+func TestAcquire_ExclusiveWithIncreasingToken(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	locker, server := newTestLocker(t)
+
+	// Act
+	first, err := locker.Acquire(ctx, "job", time.Minute)
+	require.NoError(t, err)
+	_, errBusy := locker.Acquire(ctx, "job", time.Minute)
+
+	server.FastForward(time.Minute)
+	second, err := locker.Acquire(ctx, "job", time.Minute)
+	require.NoError(t, err)
+
+	// Assert
+	assert.ErrorIs(t, errBusy, ErrNotAcquired)
+	assert.True(t, first.Valid())
+	assert.Greater(t, second.Token(), first.Token())
+}
+
+func TestRelease_OnlyOwnLease(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	locker, server := newTestLocker(t)
+
+	stale, err := locker.Acquire(ctx, "job", time.Second)
+	require.NoError(t, err)
+	server.FastForward(time.Second)
+	current, err := locker.Acquire(ctx, "job", time.Minute)
+	require.NoError(t, err)
+
+	// Act: истекшая аренда не снимает и не продлевает чужую блокировку
+	require.NoError(t, stale.Release(ctx))
+	renewErr := stale.Renew(ctx, time.Minute)
+	_, busyErr := locker.Acquire(ctx, "job", time.Minute)
+
+	// Assert
+	assert.ErrorIs(t, renewErr, ErrLeaseLost)
+	assert.ErrorIs(t, busyErr, ErrNotAcquired)
+
+	require.NoError(t, current.Release(ctx))
+	_, err = locker.Acquire(ctx, "job", time.Minute)
+	assert.NoError(t, err)
+}
+
+// ==================== Once Tests ====================
+
+func TestOnce_RunsOnSingleInstance(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	locker, _ := newTestLocker(t)
+	var runs atomic.Int32
+
+	// Act: два экземпляра запускают одну и ту же задачу
+	err := locker.Once(ctx, "job", time.Minute, func(context.Context) { runs.Add(1) })
+	errSecond := locker.Once(ctx, "job", time.Minute, func(context.Context) { runs.Add(1) })
+
+	// Assert: блокировка держится до истечения ttl и после завершения задачи
+	require.NoError(t, err)
+	assert.ErrorIs(t, errSecond, ErrNotAcquired)
+	assert.Equal(t, int32(1), runs.Load())
+}
+
+func TestOnce_NilLockerRunsTask(t *testing.T) {
+	// Arrange
+	var locker *Locker
+	ran := false
+
+	// Act
+	err := locker.Once(context.Background(), "job", time.Minute, func(context.Context) { ran = true })
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, ran)
+}
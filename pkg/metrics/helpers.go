@@ -2,7 +2,11 @@ package metrics
 
 import (
 	"context"
+	"strconv"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 )
 
 // =============================================================================
@@ -20,6 +24,8 @@ const (
 	RedisOpExpire RedisOperation = "expire"
 	RedisOpHGet   RedisOperation = "hget"
 	RedisOpHSet   RedisOperation = "hset"
+
+	RedisOpPipeline RedisOperation = "pipeline"
 )
 
 // RedisTimer помогает измерять время операций Redis
@@ -54,11 +60,82 @@ func RecordCacheMiss(service, keyPrefix string) {
 	RedisCacheMisses.WithLabelValues(service, keyPrefix).Inc()
 }
 
+// CacheStats - попадания и промахи кеша с момента запуска процесса
+type CacheStats struct {
+	Hits    float64 `json:"hits"`
+	Misses  float64 `json:"misses"`
+	HitRate float64 `json:"hit_rate"` // Доля попаданий от 0 до 1, 0 - обращений не было
+}
+
+// RedisCacheStats суммирует попадания и промахи Redis кеша сервиса по всем префиксам ключей
+// Значения локальны для процесса: сводка по всем экземплярам строится по Prometheus
+func RedisCacheStats(service string) CacheStats {
+	stats := CacheStats{
+		Hits:   sumByLabel(RedisCacheHits, "service", service),
+		Misses: sumByLabel(RedisCacheMisses, "service", service),
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = stats.Hits / total
+	}
+	return stats
+}
+
+// sumByLabel суммирует счетчики вектора с заданным значением метки
+func sumByLabel(vec *prometheus.CounterVec, name, value string) float64 {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		vec.Collect(ch)
+		close(ch)
+	}()
+
+	var sum float64
+	for m := range ch {
+		var metric dto.Metric
+		if err := m.Write(&metric); err != nil {
+			continue
+		}
+		for _, label := range metric.GetLabel() {
+			if label.GetName() == name && label.GetValue() == value {
+				sum += metric.GetCounter().GetValue()
+				break
+			}
+		}
+	}
+	return sum
+}
+
+// RecordLocalCacheHit записывает попадание в кеш в памяти процесса
+func RecordLocalCacheHit(service, cache string) {
+	LocalCacheHits.WithLabelValues(service, cache).Inc()
+}
+
+// RecordLocalCacheMiss записывает промах кеша в памяти процесса
+func RecordLocalCacheMiss(service, cache string) {
+	LocalCacheMisses.WithLabelValues(service, cache).Inc()
+}
+
 // RecordRedisError записывает ошибку Redis
 func RecordRedisError(service string, op RedisOperation) {
 	RedisErrors.WithLabelValues(service, string(op)).Inc()
 }
 
+// RecordRedisPoolStats записывает состояние пула соединений Redis
+func RecordRedisPoolStats(service string, total, idle, stale uint32) {
+	RedisPoolConnections.WithLabelValues(service, "total").Set(float64(total))
+	RedisPoolConnections.WithLabelValues(service, "idle").Set(float64(idle))
+	RedisPoolConnections.WithLabelValues(service, "stale").Set(float64(stale))
+}
+
+// =============================================================================
+// HTTP Instrumentation Helpers
+// =============================================================================
+
+// RecordHTTPResponseSize записывает размер тела ответа до и после сжатия
+func RecordHTTPResponseSize(service, path, encoding string, uncompressed, sent int) {
+	HttpResponseUncompressedSize.WithLabelValues(service, path, encoding).Observe(float64(uncompressed))
+	HttpResponseSize.WithLabelValues(service, path, encoding).Observe(float64(sent))
+}
+
 // =============================================================================
 // Kafka Instrumentation Helpers
 // =============================================================================
@@ -75,6 +152,24 @@ func RecordKafkaMessageConsumed(service, topic, group string, processingDuration
 	KafkaConsumeDuration.WithLabelValues(service, topic).Observe(processingDuration.Seconds())
 }
 
+// RecordKafkaBatchConsumed записывает обработку пачки сообщений из Kafka
+// Длительность учитывается один раз на пачку
+func RecordKafkaBatchConsumed(service, topic, group string, size int, processingDuration time.Duration) {
+	KafkaMessagesConsumed.WithLabelValues(service, topic, group).Add(float64(size))
+	KafkaConsumeDuration.WithLabelValues(service, topic).Observe(processingDuration.Seconds())
+}
+
+// RecordKafkaBatchFlush записывает размер отправленного батча и задержку его отправки
+func RecordKafkaBatchFlush(service, topic string, size int, latency time.Duration) {
+	KafkaBatchSize.WithLabelValues(service, topic).Observe(float64(size))
+	KafkaBatchFlushDuration.WithLabelValues(service, topic).Observe(latency.Seconds())
+}
+
+// RecordKafkaConsumerLag записывает отставание consumer от конца партиции
+func RecordKafkaConsumerLag(service, topic, group string, partition int, lag int64) {
+	KafkaConsumerLag.WithLabelValues(service, topic, group, strconv.Itoa(partition)).Set(float64(lag))
+}
+
 // RecordKafkaError записывает ошибку Kafka
 func RecordKafkaError(service, topic, operation string) {
 	KafkaErrors.WithLabelValues(service, topic, operation).Inc()
@@ -0,0 +1,20 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisCacheStats(t *testing.T) {
+	RecordCacheHit("stats-test", "products")
+	RecordCacheHit("stats-test", "products")
+	RecordCacheHit("stats-test", "categories")
+	RecordCacheMiss("stats-test", "products")
+	RecordCacheHit("other-service", "products")
+
+	stats := RedisCacheStats("stats-test")
+
+	assert.Equal(t, CacheStats{Hits: 3, Misses: 1, HitRate: 0.75}, stats)
+	assert.Equal(t, CacheStats{}, RedisCacheStats("unused-service"))
+}
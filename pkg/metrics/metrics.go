@@ -32,6 +32,26 @@ var HttpRequestsInFlight = promauto.NewGaugeVec(
 	[]string{"service"},
 )
 
+// HttpResponseSize - тело ответа, отправленное клиенту (после сжатия); encoding identity - без сжатия
+var HttpResponseSize = promauto.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "http_response_size_bytes",
+		Help:    "Size of HTTP response bodies sent to clients in bytes",
+		Buckets: prometheus.ExponentialBuckets(256, 4, 8), // 256B - 4MB
+	},
+	[]string{"service", "path", "encoding"},
+)
+
+// HttpResponseUncompressedSize - тело ответа до сжатия; отношение сумм с HttpResponseSize - степень сжатия
+var HttpResponseUncompressedSize = promauto.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "http_response_uncompressed_size_bytes",
+		Help:    "Size of HTTP response bodies before compression in bytes",
+		Buckets: prometheus.ExponentialBuckets(256, 4, 8),
+	},
+	[]string{"service", "path", "encoding"},
+)
+
 // Database Metrics
 
 var DbQueryDuration = promauto.NewHistogramVec(
@@ -77,6 +97,32 @@ var RedisCacheMisses = promauto.NewCounterVec(
 	[]string{"service", "key_prefix"},
 )
 
+// In-memory Cache Metrics
+
+var LocalCacheHits = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "local_cache_hits_total",
+		Help: "Total number of in-memory cache hits",
+	},
+	[]string{"service", "cache"},
+)
+
+var LocalCacheMisses = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "local_cache_misses_total",
+		Help: "Total number of in-memory cache misses (including expired entries)",
+	},
+	[]string{"service", "cache"},
+)
+
+var LocalCacheEntries = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "local_cache_entries",
+		Help: "Current number of entries in in-memory cache",
+	},
+	[]string{"service", "cache"},
+)
+
 var RedisOperationDuration = promauto.NewHistogramVec(
 	prometheus.HistogramOpts{
 		Name:    "redis_operation_duration_seconds",
@@ -94,6 +140,14 @@ var RedisErrors = promauto.NewCounterVec(
 	[]string{"service", "operation"},
 )
 
+var RedisPoolConnections = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "redis_pool_connections",
+		Help: "Number of connections in the Redis client pool by state",
+	},
+	[]string{"service", "state"},
+)
+
 // Kafka Metrics
 
 var KafkaMessagesProduced = promauto.NewCounterVec(
@@ -130,6 +184,24 @@ var KafkaConsumeDuration = promauto.NewHistogramVec(
 	[]string{"service", "topic"},
 )
 
+var KafkaBatchFlushDuration = promauto.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "kafka_batch_flush_duration_seconds",
+		Help:    "Time from enqueueing the oldest message of a batch until the batch is acknowledged",
+		Buckets: []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5},
+	},
+	[]string{"service", "topic"},
+)
+
+var KafkaBatchSize = promauto.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "kafka_batch_size_messages",
+		Help:    "Number of messages in flushed Kafka batches",
+		Buckets: []float64{1, 5, 10, 25, 50, 100, 250, 500},
+	},
+	[]string{"service", "topic"},
+)
+
 var KafkaErrors = promauto.NewCounterVec(
 	prometheus.CounterOpts{
 		Name: "kafka_errors_total",
@@ -138,6 +210,14 @@ var KafkaErrors = promauto.NewCounterVec(
 	[]string{"service", "topic", "operation"},
 )
 
+var KafkaConsumerLag = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "kafka_consumer_lag",
+		Help: "Number of messages in a partition behind the last consumed offset",
+	},
+	[]string{"service", "topic", "group", "partition"},
+)
+
 // Auth Service Metrics
 
 var AuthRegistrations = promauto.NewCounter(
@@ -163,6 +243,24 @@ var AuthTokensIssued = promauto.NewCounterVec(
 	[]string{"type"},
 )
 
+// AuthRefreshTokens - обмен refresh токенов: success, failure (токен не найден или истек) и reuse_detected
+// (предъявлен уже обмененный токен, сессии пользователя отзываются); type - refresh или remember
+var AuthRefreshTokens = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "auth_refresh_tokens_total",
+		Help: "Total number of refresh token exchanges by outcome",
+	},
+	[]string{"type", "outcome"},
+)
+
+// AuthBlacklistHits - запросы с отозванным access токеном
+var AuthBlacklistHits = promauto.NewCounter(
+	prometheus.CounterOpts{
+		Name: "auth_blacklist_hits_total",
+		Help: "Total number of revoked access tokens presented",
+	},
+)
+
 // Orders Service Metrics
 
 var OrdersCreated = promauto.NewCounter(
@@ -187,6 +285,14 @@ var OrdersByStatus = promauto.NewGaugeVec(
 	[]string{"status"},
 )
 
+var OrdersRetentionProcessed = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "orders_retention_processed_total",
+		Help: "Total number of expired orders anonymized or purged by the retention job",
+	},
+	[]string{"action"},
+)
+
 // Reviews Service Metrics
 
 var ReviewsCreated = promauto.NewCounter(
@@ -205,6 +311,21 @@ var ReviewsRating = promauto.NewHistogramVec(
 	[]string{},
 )
 
+var ReviewsRatingDiscrepancies = promauto.NewCounter(
+	prometheus.CounterOpts{
+		Name: "reviews_rating_discrepancies_total",
+		Help: "Total number of product ratings in catalog that did not match reviews during reconciliation",
+	},
+)
+
+var ReviewsAutoModeration = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "reviews_auto_moderation_total",
+		Help: "Total number of reviews checked by the external moderation provider",
+	},
+	[]string{"result"},
+)
+
 // Background Worker Metrics
 
 var WorkerOrdersProcessed = promauto.NewCounterVec(
@@ -230,3 +351,86 @@ var WorkerProcessingDuration = promauto.NewHistogram(
 		Buckets: []float64{0.1, 0.5, 1, 2, 5, 10, 30},
 	},
 )
+
+var WorkerEventProcessingDuration = promauto.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "event_processing_duration_seconds",
+		Help:    "Duration of order event processing in worker by event type",
+		Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1, 2, 5, 10, 30},
+	},
+	[]string{"event_type", "status"},
+)
+
+var WorkerConversionErrors = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "conversion_errors_total",
+		Help: "Total number of failed currency conversions",
+	},
+	[]string{"from", "to"},
+)
+
+var WorkerLastRateUpdate = promauto.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "last_successful_rate_update_timestamp",
+		Help: "Unix time of the last successful exchange rates update",
+	},
+)
+
+var WorkerEventsSkipped = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "worker_events_skipped_total",
+		Help: "Total number of events skipped by headers without decoding",
+	},
+	[]string{"reason"}, // unhandled_type, duplicate
+)
+
+var WorkerConsumerPaused = promauto.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "worker_consumer_paused",
+		Help: "1 while the Kafka consumer is paused because a dependency is unavailable",
+	},
+)
+
+var WorkerEmailsSent = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "worker_emails_sent_total",
+		Help: "Total number of order emails sent to customers by template",
+	},
+	[]string{"template", "status"}, // success, render_error, send_error
+)
+
+// Service Client Metrics
+
+var ServiceClientRequests = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "service_client_requests_total",
+		Help: "Total number of requests to internal services made by pkg/clients",
+	},
+	[]string{"target", "outcome"}, // success, client_error, server_error, network_error, circuit_open
+)
+
+var ServiceClientRetries = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "service_client_retries_total",
+		Help: "Total number of repeated requests to internal services after transient errors",
+	},
+	[]string{"target"},
+)
+
+var ServiceClientCircuitOpen = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "service_client_circuit_open",
+		Help: "1 while the circuit breaker for the internal service is open",
+	},
+	[]string{"target"},
+)
+
+// Panic Metrics
+
+var PanicsTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "panics_recovered_total",
+		Help: "Total number of recovered panics in HTTP handlers and background goroutines",
+	},
+	[]string{"service", "source"},
+)
@@ -0,0 +1,99 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+
+	"augustberries/pkg/units"
+)
+
+var (
+	// ErrTotalMismatch - переданная итоговая сумма не совпадает с пересчитанной
+	ErrTotalMismatch = errors.New("order total mismatch")
+	// ErrInvalidLine - некорректная позиция (отрицательная цена или количество <= 0)
+	ErrInvalidLine = errors.New("invalid order line")
+	// ErrInvalidDiscount - скидка отрицательная или больше суммы товаров
+	ErrInvalidDiscount = errors.New("invalid discount")
+)
+
+// Line - позиция заказа для расчета: цена за единицу, количество и налог на всю позицию
+// Количество может быть дробным (1.5 кг), сумма позиции округляется до минорной единицы
+type Line struct {
+	UnitPrice Amount
+	Quantity  units.Quantity
+	Tax       Amount // Налог сверх цены, уже округленный до минорной единицы
+}
+
+// Totals - результат расчета заказа
+// Инвариант: Total = Subtotal - Discount + Tax + Delivery
+type Totals struct {
+	Subtotal Amount // Сумма по позициям без налога
+	Discount Amount // Скидка на товары
+	Tax      Amount // Сумма налогов по позициям
+	Delivery Amount // Стоимость доставки
+	Total    Amount // Итоговая сумма
+}
+
+// OrderCalculator пересчитывает итоговые суммы заказа на стороне сервера
+// Используется orders-service при создании заказа и background-worker при конвертации валют
+type OrderCalculator struct{}
+
+// NewOrderCalculator создает калькулятор заказа
+func NewOrderCalculator() *OrderCalculator {
+	return &OrderCalculator{}
+}
+
+// Calculate считает итог по позициям, налогам, скидке и доставке
+func (c *OrderCalculator) Calculate(lines []Line, delivery, discount Amount) (Totals, error) {
+	var subtotal, tax Amount
+	for i, line := range lines {
+		if line.Quantity <= 0 || line.UnitPrice < 0 || line.Tax < 0 {
+			return Totals{}, fmt.Errorf("%w: line %d", ErrInvalidLine, i)
+		}
+		subtotal += line.UnitPrice.MulQuantity(line.Quantity)
+		tax += line.Tax
+	}
+
+	if delivery < 0 {
+		return Totals{}, fmt.Errorf("%w: negative delivery", ErrInvalidLine)
+	}
+	if discount < 0 || discount > subtotal {
+		return Totals{}, ErrInvalidDiscount
+	}
+
+	return Totals{
+		Subtotal: subtotal,
+		Discount: discount,
+		Tax:      tax,
+		Delivery: delivery,
+		Total:    subtotal - discount + tax + delivery,
+	}, nil
+}
+
+// Convert пересчитывает позиции, их налоги и доставку по курсу в валюту to и собирает итог из сконвертированных частей
+// Каждая сумма округляется отдельно по правилам валюты, поэтому итог всегда равен сумме сконвертированных позиций, налогов и доставки
+func (c *OrderCalculator) Convert(lines []Line, delivery, discount Amount, rate float64, to Currency) ([]Line, Totals, error) {
+	converted := make([]Line, len(lines))
+	for i, line := range lines {
+		converted[i] = Line{
+			UnitPrice: to.MulRate(line.UnitPrice, rate),
+			Quantity:  line.Quantity,
+			Tax:       to.MulRate(line.Tax, rate),
+		}
+	}
+
+	totals, err := c.Calculate(converted, to.MulRate(delivery, rate), to.MulRate(discount, rate))
+	if err != nil {
+		return nil, Totals{}, err
+	}
+
+	return converted, totals, nil
+}
+
+// Verify сверяет ожидаемую сумму с пересчитанной
+func (c *OrderCalculator) Verify(expected Amount, totals Totals) error {
+	if expected != totals.Total {
+		return fmt.Errorf("%w: expected %s, calculated %s", ErrTotalMismatch, expected, totals.Total)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package money
+
+import (
+	"testing"
+
+	"augustberries/pkg/units"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ====== OrderCalculator Tests ======
+
+func TestOrderCalculator_Calculate(t *testing.T) {
+	calc := NewOrderCalculator()
+
+	totals, err := calc.Calculate([]Line{
+		{UnitPrice: MustParse("99.99"), Quantity: units.Of(2)},
+		{UnitPrice: MustParse("0.10"), Quantity: units.Of(3)},
+	}, MustParse("10.00"), MustParse("5.00"))
+
+	require.NoError(t, err)
+	assert.Equal(t, MustParse("200.28"), totals.Subtotal)
+	assert.Equal(t, MustParse("205.28"), totals.Total) // 200.28 - 5.00 + 10.00
+}
+
+func TestOrderCalculator_Calculate_InvalidInput(t *testing.T) {
+	calc := NewOrderCalculator()
+
+	_, err := calc.Calculate([]Line{{UnitPrice: MustParse("10.00"), Quantity: units.Of(0)}}, 0, 0)
+	assert.ErrorIs(t, err, ErrInvalidLine)
+
+	_, err = calc.Calculate([]Line{{UnitPrice: MustParse("10.00"), Quantity: units.Of(1)}}, 0, MustParse("11.00"))
+	assert.ErrorIs(t, err, ErrInvalidDiscount)
+}
+
+func TestOrderCalculator_Convert_TotalEqualsConvertedParts(t *testing.T) {
+	calc := NewOrderCalculator()
+	lines := []Line{
+		{UnitPrice: MustParse("19.99"), Quantity: units.Of(3)},
+		{UnitPrice: MustParse("0.33"), Quantity: units.Of(7)},
+	}
+
+	converted, totals, err := calc.Convert(lines, MustParse("4.99"), 0, 91.2345, LookupCurrency("RUB"))
+	require.NoError(t, err)
+
+	var sum Amount
+	for _, line := range converted {
+		sum += line.UnitPrice.MulQuantity(line.Quantity)
+	}
+	assert.Equal(t, sum+totals.Delivery, totals.Total)
+}
+
+func TestOrderCalculator_Calculate_WithTax(t *testing.T) {
+	calc := NewOrderCalculator()
+
+	totals, err := calc.Calculate([]Line{
+		{UnitPrice: MustParse("100.00"), Quantity: units.Of(2), Tax: MustParse("40.00")},
+		{UnitPrice: MustParse("10.00"), Quantity: units.Of(1)},
+	}, MustParse("5.00"), 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, MustParse("210.00"), totals.Subtotal)
+	assert.Equal(t, MustParse("40.00"), totals.Tax)
+	assert.Equal(t, MustParse("255.00"), totals.Total) // 210.00 + 40.00 + 5.00
+
+	_, err = calc.Calculate([]Line{{UnitPrice: MustParse("10.00"), Quantity: units.Of(1), Tax: -1}}, 0, 0)
+	assert.ErrorIs(t, err, ErrInvalidLine)
+}
+
+func TestOrderCalculator_Convert_ConvertsTaxPerLine(t *testing.T) {
+	calc := NewOrderCalculator()
+	lines := []Line{
+		{UnitPrice: MustParse("19.99"), Quantity: units.Of(3), Tax: MustParse("11.99")},
+		{UnitPrice: MustParse("0.33"), Quantity: units.Of(7), Tax: MustParse("0.23")},
+	}
+
+	converted, totals, err := calc.Convert(lines, MustParse("4.99"), 0, 91.2345, LookupCurrency("RUB"))
+	require.NoError(t, err)
+
+	var sum, tax Amount
+	for i, line := range converted {
+		assert.Equal(t, lines[i].Tax.MulRate(91.2345), line.Tax)
+		sum += line.UnitPrice.MulQuantity(line.Quantity)
+		tax += line.Tax
+	}
+	assert.Equal(t, tax, totals.Tax)
+	assert.Equal(t, sum+tax+totals.Delivery, totals.Total)
+}
+
+func TestOrderCalculator_Convert_RoundsToTargetCurrency(t *testing.T) {
+	calc := NewOrderCalculator()
+	lines := []Line{{UnitPrice: MustParse("19.99"), Quantity: units.Of(2)}}
+
+	converted, totals, err := calc.Convert(lines, MustParse("4.99"), 0, 151.37, LookupCurrency("JPY"))
+	require.NoError(t, err)
+
+	assert.Equal(t, MustParse("3026"), converted[0].UnitPrice) // 19.99 * 151.37 = 3025.89
+	assert.Equal(t, MustParse("755"), totals.Delivery)         // 4.99 * 151.37 = 755.34
+	assert.Equal(t, MustParse("6807"), totals.Total)
+}
+
+func TestOrderCalculator_Verify(t *testing.T) {
+	calc := NewOrderCalculator()
+	totals := Totals{Total: MustParse("110.00")}
+
+	assert.NoError(t, calc.Verify(MustParse("110.00"), totals))
+	assert.ErrorIs(t, calc.Verify(MustParse("109.99"), totals), ErrTotalMismatch)
+}
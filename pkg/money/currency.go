@@ -0,0 +1,177 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// RoundingMode - способ округления до минорной единицы валюты
+type RoundingMode string
+
+const (
+	// RoundHalfUp - половина округляется от нуля (2.5 -> 3, -2.5 -> -3)
+	RoundHalfUp RoundingMode = "half_up"
+	// RoundHalfEven - банковское округление, половина к четному (2.5 -> 2, 3.5 -> 4)
+	RoundHalfEven RoundingMode = "half_even"
+)
+
+// MaxDecimals - максимальная точность валюты: Amount хранит суммы в сотых долях
+const MaxDecimals = 2
+
+// ErrInvalidCurrency - некорректное описание валюты в реестре
+var ErrInvalidCurrency = errors.New("invalid currency")
+
+// Currency - метаданные валюты: число знаков после запятой и способ округления
+type Currency struct {
+	Code     string       `json:"code"`
+	Decimals int          `json:"decimals"`
+	Rounding RoundingMode `json:"rounding"`
+}
+
+// defaultCurrency - параметры для валют, которых нет в реестре
+var defaultCurrency = Currency{Decimals: MaxDecimals, Rounding: RoundHalfUp}
+
+// defaultCurrencies - встроенные валюты реестра
+var defaultCurrencies = []Currency{
+	{Code: "USD", Decimals: 2, Rounding: RoundHalfUp},
+	{Code: "EUR", Decimals: 2, Rounding: RoundHalfUp},
+	{Code: "RUB", Decimals: 2, Rounding: RoundHalfUp},
+	{Code: "GBP", Decimals: 2, Rounding: RoundHalfUp},
+	{Code: "CNY", Decimals: 2, Rounding: RoundHalfUp},
+	{Code: "JPY", Decimals: 0, Rounding: RoundHalfUp},
+	{Code: "KRW", Decimals: 0, Rounding: RoundHalfUp},
+}
+
+// Currencies - общий реестр валют процесса, настраивается при старте сервиса через Configure
+var Currencies = NewCurrencyRegistry()
+
+// LookupCurrency возвращает валюту из общего реестра
+func LookupCurrency(code string) Currency {
+	return Currencies.Lookup(code)
+}
+
+// CurrencyRegistry - потокобезопасный реестр точности и округления валют
+type CurrencyRegistry struct {
+	mu         sync.RWMutex
+	currencies map[string]Currency
+}
+
+// NewCurrencyRegistry создает реестр со встроенными валютами
+func NewCurrencyRegistry() *CurrencyRegistry {
+	r := &CurrencyRegistry{currencies: make(map[string]Currency, len(defaultCurrencies))}
+	for _, c := range defaultCurrencies {
+		r.currencies[c.Code] = c
+	}
+	return r
+}
+
+// Lookup возвращает валюту по ISO коду
+// Неизвестная валюта получает два знака и округление половины вверх
+func (r *CurrencyRegistry) Lookup(code string) Currency {
+	code = strings.ToUpper(code)
+
+	r.mu.RLock()
+	c, ok := r.currencies[code]
+	r.mu.RUnlock()
+	if ok {
+		return c
+	}
+
+	c = defaultCurrency
+	c.Code = code
+	return c
+}
+
+// Register добавляет валюту или заменяет ее параметры
+func (r *CurrencyRegistry) Register(c Currency) error {
+	c.Code = strings.ToUpper(strings.TrimSpace(c.Code))
+	if len(c.Code) != 3 {
+		return fmt.Errorf("%w: code %q must be a 3-letter ISO code", ErrInvalidCurrency, c.Code)
+	}
+	if c.Decimals < 0 || c.Decimals > MaxDecimals {
+		return fmt.Errorf("%w: %s decimals must be between 0 and %d", ErrInvalidCurrency, c.Code, MaxDecimals)
+	}
+	if c.Rounding == "" {
+		c.Rounding = RoundHalfUp
+	}
+	if c.Rounding != RoundHalfUp && c.Rounding != RoundHalfEven {
+		return fmt.Errorf("%w: %s rounding %q", ErrInvalidCurrency, c.Code, c.Rounding)
+	}
+
+	r.mu.Lock()
+	r.currencies[c.Code] = c
+	r.mu.Unlock()
+	return nil
+}
+
+// Configure применяет настройки вида "JPY:0:half_even,RUB:2"
+// Способ округления можно опустить, тогда используется half_up
+func (r *CurrencyRegistry) Configure(spec string) error {
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return fmt.Errorf("%w: %q must be CODE:DECIMALS[:ROUNDING]", ErrInvalidCurrency, entry)
+		}
+		decimals, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return fmt.Errorf("%w: %q: decimals must be a number", ErrInvalidCurrency, entry)
+		}
+
+		c := Currency{Code: parts[0], Decimals: decimals}
+		if len(parts) == 3 {
+			c.Rounding = RoundingMode(strings.ToLower(parts[2]))
+		}
+		if err := r.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// step возвращает число сотых в минорной единице валюты (1 для центов, 100 для иены)
+func (c Currency) step() float64 {
+	return math.Pow10(MaxDecimals - c.Decimals)
+}
+
+// round округляет число шагов валюты согласно ее способу округления
+func (c Currency) round(value float64) float64 {
+	if c.Rounding == RoundHalfEven {
+		return math.RoundToEven(value)
+	}
+	return math.Round(value)
+}
+
+// Round округляет сумму до минорной единицы валюты
+func (c Currency) Round(a Amount) Amount {
+	if c.Decimals >= MaxDecimals {
+		return a
+	}
+	step := c.step()
+	return Amount(c.round(float64(a)/step) * step)
+}
+
+// MulRate умножает сумму на курс и округляет результат до минорной единицы валюты
+func (c Currency) MulRate(a Amount, rate float64) Amount {
+	step := c.step()
+	return Amount(c.round(float64(a)*rate/step) * step)
+}
+
+// Format форматирует сумму с числом знаков валюты ("1250" для JPY, "10.50" для USD)
+func (c Currency) Format(a Amount) string {
+	s := c.Round(a).String()
+	if c.Decimals >= MaxDecimals {
+		return s
+	}
+	// String всегда выводит два знака, лишние нули отбрасываются вместе с точкой при Decimals = 0
+	s = s[:len(s)-(MaxDecimals-c.Decimals)]
+	return strings.TrimSuffix(s, ".")
+}
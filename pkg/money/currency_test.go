@@ -0,0 +1,86 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ====== Currency Tests ======
+
+func TestCurrency_Round(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency Currency
+		amount   string
+		want     string
+	}{
+		{"two decimals unchanged", Currency{Code: "USD", Decimals: 2, Rounding: RoundHalfUp}, "10.55", "10.55"},
+		{"half up", Currency{Code: "JPY", Decimals: 0, Rounding: RoundHalfUp}, "102.50", "103.00"},
+		{"half up negative", Currency{Code: "JPY", Decimals: 0, Rounding: RoundHalfUp}, "-102.50", "-103.00"},
+		{"half even down", Currency{Code: "JPY", Decimals: 0, Rounding: RoundHalfEven}, "102.50", "102.00"},
+		{"half even up", Currency{Code: "JPY", Decimals: 0, Rounding: RoundHalfEven}, "103.50", "104.00"},
+		{"one decimal", Currency{Code: "XYZ", Decimals: 1, Rounding: RoundHalfUp}, "10.05", "10.10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, MustParse(tt.want), tt.currency.Round(MustParse(tt.amount)))
+		})
+	}
+}
+
+func TestCurrency_MulRate(t *testing.T) {
+	halfUp := Currency{Code: "USD", Decimals: 2, Rounding: RoundHalfUp}
+	halfEven := Currency{Code: "USD", Decimals: 2, Rounding: RoundHalfEven}
+
+	// 0.25 * 0.5 = 0.125: половина цента
+	assert.Equal(t, MustParse("0.13"), halfUp.MulRate(MustParse("0.25"), 0.5))
+	assert.Equal(t, MustParse("0.12"), halfEven.MulRate(MustParse("0.25"), 0.5))
+	// Для двух знаков half_up совпадает с Amount.MulRate
+	assert.Equal(t, MustParse("10.00").MulRate(91.23), halfUp.MulRate(MustParse("10.00"), 91.23))
+}
+
+func TestCurrency_Format(t *testing.T) {
+	assert.Equal(t, "10035.30", LookupCurrency("RUB").Format(MustParse("10035.3")))
+	assert.Equal(t, "1250", LookupCurrency("JPY").Format(MustParse("1249.6")))
+	assert.Equal(t, "-3", LookupCurrency("jpy").Format(MustParse("-2.5")))
+}
+
+// ====== CurrencyRegistry Tests ======
+
+func TestCurrencyRegistry_LookupUnknownUsesDefaults(t *testing.T) {
+	registry := NewCurrencyRegistry()
+
+	c := registry.Lookup("chf")
+
+	assert.Equal(t, Currency{Code: "CHF", Decimals: 2, Rounding: RoundHalfUp}, c)
+}
+
+func TestCurrencyRegistry_Configure(t *testing.T) {
+	registry := NewCurrencyRegistry()
+
+	err := registry.Configure("rub:2:half_even, KWD:1")
+
+	require.NoError(t, err)
+	assert.Equal(t, Currency{Code: "RUB", Decimals: 2, Rounding: RoundHalfEven}, registry.Lookup("RUB"))
+	assert.Equal(t, Currency{Code: "KWD", Decimals: 1, Rounding: RoundHalfUp}, registry.Lookup("KWD"))
+}
+
+func TestCurrencyRegistry_Configure_Invalid(t *testing.T) {
+	tests := []string{
+		"USD",
+		"USD:x",
+		"USD:3",
+		"USD:2:ceil",
+		"DOLLAR:2",
+	}
+
+	for _, spec := range tests {
+		t.Run(spec, func(t *testing.T) {
+			err := NewCurrencyRegistry().Configure(spec)
+			assert.ErrorIs(t, err, ErrInvalidCurrency)
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package money
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"augustberries/pkg/units"
+)
+
+// Scale - количество минорных единиц в одной основной (копейки, центы)
+const Scale = 100
+
+// Amount - денежная сумма в минорных единицах (копейках/центах)
+// Хранится как int64, чтобы исключить ошибки округления float64.
+// В JSON и в БД (decimal(10,2)) представляется десятичным числом с двумя знаками
+// для совместимости с существующими клиентами и схемой.
+type Amount int64
+
+// FromMinor создает сумму из минорных единиц
+func FromMinor(minor int64) Amount {
+	return Amount(minor)
+}
+
+// FromFloat создает сумму из float64 с округлением до ближайшей минорной единицы
+// Используется только на границах системы (внешние API, старые данные).
+// NaN, бесконечность и суммы, не помещающиеся в Amount (больше ±MaxInt64/Scale), - ошибка
+func FromFloat(value float64) (Amount, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("money: invalid amount %v", value)
+	}
+	// float64(math.MaxInt64) округляется до 2^63, поэтому граница не включается
+	minor := math.Round(value * Scale)
+	if minor >= math.MaxInt64 || minor <= math.MinInt64 {
+		return 0, fmt.Errorf("money: amount %v overflows", value)
+	}
+	return Amount(minor), nil
+}
+
+// Parse разбирает десятичную строку ("209.98", "-5", "10.5") без потери точности
+func Parse(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("money: empty amount")
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		// Второй знак ("--5") отклоняется, иначе ParseFloat принял бы его
+		return 0, fmt.Errorf("money: invalid amount %q", s)
+	}
+
+	intPart, fracPart, hasFrac := strings.Cut(s, ".")
+	if intPart == "" && (!hasFrac || fracPart == "") {
+		return 0, fmt.Errorf("money: invalid amount %q", s)
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+	if !isDigits(intPart) || (hasFrac && !isDigits(fracPart)) {
+		// Экспоненциальная запись и прочие форматы - через float с округлением
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, fmt.Errorf("money: invalid amount %q", s)
+		}
+		a, err := FromFloat(f)
+		if err != nil {
+			return 0, err
+		}
+		if negative {
+			a = -a
+		}
+		return a, nil
+	}
+
+	units, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("money: invalid amount %q: %w", s, err)
+	}
+
+	// Дробную часть дополняем до двух знаков, лишние знаки округляем половиной вверх
+	var cents int64
+	switch {
+	case len(fracPart) == 0:
+	case len(fracPart) == 1:
+		cents = int64(fracPart[0]-'0') * 10
+	default:
+		cents = int64(fracPart[0]-'0')*10 + int64(fracPart[1]-'0')
+		if len(fracPart) > 2 && fracPart[2] >= '5' {
+			cents++
+		}
+	}
+
+	if units > (math.MaxInt64-cents)/Scale {
+		return 0, fmt.Errorf("money: amount %q overflows", s)
+	}
+
+	minor := units*Scale + cents
+	if negative {
+		minor = -minor
+	}
+	return Amount(minor), nil
+}
+
+// MustParse как Parse, но паникует при ошибке (для констант и тестов)
+func MustParse(s string) Amount {
+	a, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// Minor возвращает сумму в минорных единицах
+func (a Amount) Minor() int64 {
+	return int64(a)
+}
+
+// Float64 возвращает сумму как float64 (для метрик и логов)
+func (a Amount) Float64() float64 {
+	return float64(a) / Scale
+}
+
+// Mul умножает сумму на целое количество (цена за единицу * количество)
+func (a Amount) Mul(quantity int64) Amount {
+	return a * Amount(quantity)
+}
+
+// MulQuantity умножает цену за единицу на количество в единицах товара (1.5 кг)
+// и округляет до минорной единицы (half away from zero)
+func (a Amount) MulQuantity(q units.Quantity) Amount {
+	product := int64(a) * q.Milli()
+	half := int64(units.Scale / 2)
+	if product < 0 {
+		half = -half
+	}
+	return Amount((product + half) / units.Scale)
+}
+
+// MulRate умножает сумму на курс и округляет до минорной единицы (half away from zero)
+func (a Amount) MulRate(rate float64) Amount {
+	return Amount(math.Round(float64(a) * rate))
+}
+
+// IsZero сообщает, равна ли сумма нулю
+func (a Amount) IsZero() bool {
+	return a == 0
+}
+
+// String форматирует сумму как десятичное число с двумя знаками ("209.98")
+func (a Amount) String() string {
+	minor := int64(a)
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, minor/Scale, minor%Scale)
+}
+
+// MarshalJSON сериализует сумму как JSON число с двумя знаками (совместимо с float API)
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON принимает JSON число или строку с десятичной суммой
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		data = data[1 : len(data)-1]
+	}
+
+	parsed, err := Parse(string(data))
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Value реализует driver.Valuer: сохраняет сумму в decimal колонку без потери точности
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// Scan реализует sql.Scanner: читает decimal/numeric колонку
+func (a *Amount) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*a = 0
+		return nil
+	case []byte:
+		parsed, err := Parse(string(v))
+		if err != nil {
+			return err
+		}
+		*a = parsed
+		return nil
+	case string:
+		parsed, err := Parse(v)
+		if err != nil {
+			return err
+		}
+		*a = parsed
+		return nil
+	case float64:
+		parsed, err := FromFloat(v)
+		if err != nil {
+			return err
+		}
+		*a = parsed
+		return nil
+	case float32:
+		parsed, err := FromFloat(float64(v))
+		if err != nil {
+			return err
+		}
+		*a = parsed
+		return nil
+	case int64:
+		if v > math.MaxInt64/Scale || v < math.MinInt64/Scale {
+			return fmt.Errorf("money: amount %d overflows", v)
+		}
+		*a = Amount(v * Scale)
+		return nil
+	default:
+		return fmt.Errorf("money: cannot scan %T into Amount", src)
+	}
+}
+
+// isDigits проверяет, что строка состоит только из цифр
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
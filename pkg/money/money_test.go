@@ -0,0 +1,137 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"augustberries/pkg/units"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ====== Parse Tests ======
+
+func TestParse(t *testing.T) {
+	cases := map[string]Amount{
+		"209.98":  20998,
+		"10":      1000,
+		"10.5":    1050,
+		"0.01":    1,
+		"-5.25":   -525,
+		"99.995":  10000,
+		"1e2":     10000,
+		"  7.10 ": 710,
+	}
+
+	for input, expected := range cases {
+		got, err := Parse(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, got, input)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	inputs := []string{
+		"", "-", ".", "abc", "1.2.3",
+		// Второй знак
+		"--5", "+-5", "-+5", "--1e2",
+		// NaN, бесконечность и суммы больше MaxInt64/Scale
+		"NaN", "Inf", "-Inf", "1e30", "-1e30", "92233720368547758.08",
+	}
+	for _, input := range inputs {
+		_, err := Parse(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestFromFloat(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    float64
+		expected Amount
+		wantErr  bool
+	}{
+		{name: "rounded to minor unit", value: 10.005, expected: 1001},
+		{name: "negative", value: -5.25, expected: -525},
+		{name: "large amount", value: 1e15, expected: Amount(1e17)},
+		{name: "NaN", value: math.NaN(), wantErr: true},
+		{name: "positive infinity", value: math.Inf(1), wantErr: true},
+		{name: "negative infinity", value: math.Inf(-1), wantErr: true},
+		{name: "overflow", value: 1e30, wantErr: true},
+		{name: "negative overflow", value: -1e30, wantErr: true},
+		{name: "just above MaxInt64/Scale", value: math.MaxInt64 / Scale * 1.01, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromFloat(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+// ====== Arithmetic Tests ======
+
+func TestAmount_SumHasNoFloatError(t *testing.T) {
+	// (99.99 * 2) + 10.00 = 209.98 ровно, без 209.97999999999999
+	total := MustParse("99.99").Mul(2) + MustParse("10.00")
+
+	assert.Equal(t, "209.98", total.String())
+	assert.Equal(t, int64(20998), total.Minor())
+}
+
+func TestAmount_MulRate(t *testing.T) {
+	assert.Equal(t, MustParse("9809.68"), MustParse("100.00").MulRate(91.23/0.93))
+	assert.Equal(t, MustParse("912.30"), MustParse("10.00").MulRate(91.23))
+}
+
+func TestAmount_MulQuantity(t *testing.T) {
+	// 1.5 кг по 349.90 = 524.85; 0.333 кг по 10.01 = 3.33333 -> 3.33
+	assert.Equal(t, MustParse("524.85"), MustParse("349.90").MulQuantity(units.MustParse("1.5")))
+	assert.Equal(t, MustParse("3.33"), MustParse("10.01").MulQuantity(units.MustParse("0.333")))
+	assert.Equal(t, MustParse("0.01"), MustParse("0.01").MulQuantity(units.MustParse("0.5")))
+	assert.Equal(t, MustParse("99.99").Mul(2), MustParse("99.99").MulQuantity(units.Of(2)))
+}
+
+// ====== Serialization Tests ======
+
+func TestAmount_JSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Price Amount `json:"price"`
+	}{Price: MustParse("1299.9")})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"price":1299.90}`, string(data))
+
+	var decoded struct {
+		Price Amount `json:"price"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"price":209.98}`), &decoded))
+	assert.Equal(t, Amount(20998), decoded.Price)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"price":"15.5"}`), &decoded))
+	assert.Equal(t, Amount(1550), decoded.Price)
+}
+
+func TestAmount_SQL(t *testing.T) {
+	value, err := MustParse("10035.3").Value()
+	require.NoError(t, err)
+	assert.Equal(t, "10035.30", value)
+
+	var a Amount
+	require.NoError(t, a.Scan([]byte("912.30")))
+	assert.Equal(t, Amount(91230), a)
+
+	require.NoError(t, a.Scan(110.0))
+	assert.Equal(t, Amount(11000), a)
+
+	assert.Error(t, a.Scan(true))
+	assert.Error(t, a.Scan(math.NaN()))
+	assert.Error(t, a.Scan(int64(math.MaxInt64)))
+}
@@ -0,0 +1,201 @@
+// Package openapi проверяет запросы и ответы сервисов по OpenAPI спецификации (kin-openapi)
+// Предназначен для dev и staging окружений: ловит расхождения обработчиков и спецификации
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// Mode - режим проверки по спецификации
+type Mode string
+
+const (
+	ModeOff     Mode = "off"     // Проверка отключена (по умолчанию, обязательно для production)
+	ModeLog     Mode = "log"     // Нарушения только логируются
+	ModeEnforce Mode = "enforce" // Запрос с нарушением отклоняется 400, нарушения ответа логируются
+)
+
+// maxResponseBody - ответы больше этого размера не проверяются, чтобы не держать их в памяти
+const maxResponseBody = 1 << 20
+
+// ErrorResponse - ответ на запрос, не соответствующий спецификации (режим enforce)
+type ErrorResponse struct {
+	Error   string `json:"error"`
+	Details string `json:"details"`
+}
+
+// Validator проверяет запросы и ответы по спецификации
+// Маршруты, которых нет в спецификации, пропускаются без проверки
+type Validator struct {
+	service string
+	mode    Mode
+	router  routers.Router
+}
+
+// NewValidator разбирает спецификацию (YAML или JSON) и создает Validator
+func NewValidator(service string, spec []byte, mode Mode) (*Validator, error) {
+	switch mode {
+	case ModeLog, ModeEnforce:
+	default:
+		return nil, fmt.Errorf("invalid openapi validation mode %q: expected log or enforce", mode)
+	}
+
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load openapi spec: %w", err)
+	}
+	if err := doc.Validate(loader.Context); err != nil {
+		return nil, fmt.Errorf("invalid openapi spec: %w", err)
+	}
+
+	// Сервер из спецификации не должен ограничивать хост и порт запроса
+	doc.Servers = nil
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build openapi router: %w", err)
+	}
+
+	return &Validator{service: service, mode: mode, router: router}, nil
+}
+
+// FromEnv создает Validator по OPENAPI_VALIDATION (off, log, enforce) и OPENAPI_SPEC (путь к спецификации)
+// Без OPENAPI_VALIDATION или с off возвращает nil - Wrap на nil не меняет обработчик
+// Используется в main всех HTTP сервисов
+func FromEnv(service string) (*Validator, error) {
+	mode := Mode(os.Getenv("OPENAPI_VALIDATION"))
+	if mode == "" || mode == ModeOff {
+		return nil, nil
+	}
+
+	path := os.Getenv("OPENAPI_SPEC")
+	if path == "" {
+		return nil, fmt.Errorf("OPENAPI_SPEC is required for OPENAPI_VALIDATION=%s", mode)
+	}
+	spec, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read openapi spec: %w", err)
+	}
+
+	return NewValidator(service, spec, mode)
+}
+
+// Wrap проверяет запросы перед next и ответы после него
+// Оборачивает весь HTTP обработчик сервиса, поэтому не зависит от порядка middleware Gin
+func (v *Validator) Wrap(next http.Handler) http.Handler {
+	if v == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := v.router.FindRoute(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				// Аутентификацию проверяют middleware сервиса, спецификация описывает только контракт
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+				MultiError:         true,
+			},
+		}
+		// ValidateRequest читает тело и подменяет его копией, обработчик получит тело целиком
+		if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+			v.violation(r, "request", err)
+			if v.mode == ModeEnforce {
+				writeError(w, err)
+				return
+			}
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		if rec.truncated {
+			return
+		}
+		responseInput := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 rec.status,
+			Header:                 w.Header(),
+			Options:                &openapi3filter.Options{IncludeResponseStatus: true, MultiError: true},
+		}
+		responseInput.SetBodyBytes(rec.body.Bytes())
+		if err := openapi3filter.ValidateResponse(context.WithoutCancel(r.Context()), responseInput); err != nil {
+			v.violation(r, fmt.Sprintf("response %d", rec.status), err)
+		}
+	})
+}
+
+// violation логирует нарушение спецификации
+func (v *Validator) violation(r *http.Request, what string, err error) {
+	log.Printf("[openapi] %s: %s %s: %s does not match spec: %v", v.service, r.Method, r.URL.Path, what, err)
+}
+
+// writeError отвечает 400 на запрос, не соответствующий спецификации
+func writeError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(ErrorResponse{
+		Error:   "Request does not match API specification",
+		Details: err.Error(),
+	})
+}
+
+// responseRecorder передает ответ клиенту и сохраняет копию тела для проверки
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+	truncated   bool // Тело больше maxResponseBody, ответ не проверяется
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	if !r.truncated {
+		if r.body.Len()+len(p) > maxResponseBody {
+			r.truncated = true
+			r.body.Reset()
+		} else {
+			r.body.Write(p)
+		}
+	}
+	return r.ResponseWriter.Write(p)
+}
+
+// Flush нужен потоковым ответам (SSE, выгрузки)
+func (r *responseRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap дает http.ResponseController доступ к исходному ResponseWriter
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
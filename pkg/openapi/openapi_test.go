@@ -0,0 +1,157 @@
+package openapi
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1.0"
+paths:
+  /reviews:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [rating]
+              properties:
+                rating:
+                  type: integer
+                  minimum: 1
+                  maximum: 5
+      responses:
+        "201":
+          description: created
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id]
+                properties:
+                  id:
+                    type: string
+`
+
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
+
+func newTestHandler(t *testing.T, mode Mode, response string) (http.Handler, *bool) {
+	t.Helper()
+	v, err := NewValidator("test-service", []byte(testSpec), mode)
+	require.NoError(t, err)
+
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		// Обработчик получает тело запроса целиком после проверки
+		body, _ := io.ReadAll(r.Body)
+		assert.NotEmpty(t, body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(response))
+	})
+	return v.Wrap(next), &called
+}
+
+func postReview(handler http.Handler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/reviews", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestValidator_ValidRequestAndResponse(t *testing.T) {
+	buf := captureLog(t)
+	handler, called := newTestHandler(t, ModeEnforce, `{"id":"r-1"}`)
+
+	w := postReview(handler, `{"rating":5}`)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.True(t, *called)
+	assert.Empty(t, buf.String())
+}
+
+func TestValidator_EnforceRejectsInvalidRequest(t *testing.T) {
+	buf := captureLog(t)
+	handler, called := newTestHandler(t, ModeEnforce, `{"id":"r-1"}`)
+
+	w := postReview(handler, `{"rating":7}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.False(t, *called)
+	assert.Contains(t, w.Body.String(), "Request does not match API specification")
+	assert.Contains(t, buf.String(), "POST /reviews: request does not match spec")
+}
+
+func TestValidator_LogModePassesInvalidRequest(t *testing.T) {
+	buf := captureLog(t)
+	handler, called := newTestHandler(t, ModeLog, `{"id":"r-1"}`)
+
+	w := postReview(handler, `{"rating":0}`)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.True(t, *called)
+	assert.Contains(t, buf.String(), "request does not match spec")
+}
+
+func TestValidator_LogsResponseDrift(t *testing.T) {
+	buf := captureLog(t)
+	handler, _ := newTestHandler(t, ModeEnforce, `{"review_id":"r-1"}`)
+
+	w := postReview(handler, `{"rating":4}`)
+
+	// Ответ уже отправлен клиенту, нарушение только логируется
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"review_id":"r-1"}`, w.Body.String())
+	assert.Contains(t, buf.String(), "response 201 does not match spec")
+}
+
+func TestValidator_SkipsUnknownRoutes(t *testing.T) {
+	buf := captureLog(t)
+	v, err := NewValidator("test-service", []byte(testSpec), ModeEnforce)
+	require.NoError(t, err)
+
+	handler := v.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, buf.String())
+}
+
+func TestValidator_NilWrapReturnsHandler(t *testing.T) {
+	var v *Validator
+	next := http.NotFoundHandler()
+
+	assert.NotNil(t, v.Wrap(next))
+}
+
+func TestNewValidator_InvalidMode(t *testing.T) {
+	_, err := NewValidator("test-service", []byte(testSpec), Mode("strict"))
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,141 @@
+// Package pagination разбирает параметры постраничного вывода и формирует метаданные списков
+// Все списочные эндпоинты принимают ?page=&per_page= и отвечают одинаковыми полями total, page, per_page и links
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+)
+
+// Значения по умолчанию и ограничения размера страницы
+const (
+	DefaultPerPage = 20
+	MaxPerPage     = 100
+	// MaxPage - наибольший номер страницы: смещение (MaxPage-1)*MaxPerPage помещается в int32,
+	// поэтому не переполняется и подходит для OFFSET в PostgreSQL и $skip в MongoDB
+	MaxPage = math.MaxInt32 / MaxPerPage
+)
+
+// Имена query-параметров
+const (
+	PageParam    = "page"
+	PerPageParam = "per_page"
+)
+
+// Ошибки разбора параметров
+var (
+	ErrInvalidPage    = fmt.Errorf("page must be an integer between 1 and %d", MaxPage)
+	ErrInvalidPerPage = errors.New("per_page must be a positive integer")
+)
+
+// Params - запрошенная страница (нумерация с 1)
+type Params struct {
+	Page    int
+	PerPage int
+}
+
+// Default возвращает первую страницу размера по умолчанию
+func Default() Params {
+	return Params{Page: 1, PerPage: DefaultPerPage}
+}
+
+// Parse читает page и per_page из query-параметров
+// Отсутствующие параметры заменяются значениями по умолчанию, per_page больше MaxPerPage урезается,
+// page больше MaxPage отклоняется
+func Parse(query url.Values) (Params, error) {
+	params := Default()
+
+	if value := query.Get(PageParam); value != "" {
+		page, err := strconv.Atoi(value)
+		if err != nil || page < 1 || page > MaxPage {
+			return Params{}, ErrInvalidPage
+		}
+		params.Page = page
+	}
+
+	if value := query.Get(PerPageParam); value != "" {
+		perPage, err := strconv.Atoi(value)
+		if err != nil || perPage < 1 {
+			return Params{}, ErrInvalidPerPage
+		}
+		params.PerPage = min(perPage, MaxPerPage)
+	}
+
+	return params, nil
+}
+
+// Offset возвращает число пропускаемых записей
+// Params, собранные не через Parse, ограничиваются так же: смещение не бывает отрицательным и не переполняется
+func (p Params) Offset() int {
+	page := min(max(p.Page, 1), MaxPage)
+	return (page - 1) * p.Limit()
+}
+
+// Limit возвращает размер страницы
+func (p Params) Limit() int {
+	return min(max(p.PerPage, 0), MaxPerPage)
+}
+
+// Slice возвращает страницу из уже загруженного списка
+// Страница за пределами списка пуста
+func Slice[T any](items []T, p Params) []T {
+	start := min(p.Offset(), len(items))
+	end := min(start+p.Limit(), len(items))
+	return items[start:end]
+}
+
+// Links - ссылки на соседние страницы того же запроса
+// Next и Prev отсутствуют на последней и первой странице
+type Links struct {
+	Self  string `json:"self"`
+	First string `json:"first"`
+	Last  string `json:"last"`
+	Next  string `json:"next,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+}
+
+// Meta - метаданные страницы, встраиваются в ответы со списками
+type Meta struct {
+	Total      int   `json:"total"`
+	Page       int   `json:"page"`
+	PerPage    int   `json:"per_page"`
+	TotalPages int   `json:"total_pages"`
+	Links      Links `json:"links"`
+}
+
+// NewMeta формирует метаданные для запроса u
+// Ссылки сохраняют остальные query-параметры запроса (фильтры, сортировку)
+func NewMeta(u *url.URL, p Params, total int) Meta {
+	totalPages := (total + p.PerPage - 1) / p.PerPage
+	lastPage := max(totalPages, 1)
+
+	meta := Meta{
+		Total:      total,
+		Page:       p.Page,
+		PerPage:    p.PerPage,
+		TotalPages: totalPages,
+		Links: Links{
+			Self:  pageURL(u, p.Page, p.PerPage),
+			First: pageURL(u, 1, p.PerPage),
+			Last:  pageURL(u, lastPage, p.PerPage),
+		},
+	}
+	if p.Page < totalPages {
+		meta.Links.Next = pageURL(u, p.Page+1, p.PerPage)
+	}
+	if p.Page > 1 {
+		meta.Links.Prev = pageURL(u, min(p.Page-1, lastPage), p.PerPage)
+	}
+	return meta
+}
+
+// pageURL возвращает относительную ссылку (путь и query) на страницу page
+func pageURL(u *url.URL, page, perPage int) string {
+	query := u.Query()
+	query.Set(PageParam, strconv.Itoa(page))
+	query.Set(PerPageParam, strconv.Itoa(perPage))
+	return (&url.URL{Path: u.Path, RawQuery: query.Encode()}).String()
+}
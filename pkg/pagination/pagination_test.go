@@ -0,0 +1,113 @@
+package pagination
+
+import (
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ==================== Parse Tests ====================
+
+func TestParse_Defaults(t *testing.T) {
+	params, err := Parse(url.Values{})
+
+	require.NoError(t, err)
+	assert.Equal(t, Params{Page: 1, PerPage: DefaultPerPage}, params)
+}
+
+func TestParse_ClampsPerPage(t *testing.T) {
+	params, err := Parse(url.Values{"page": {"3"}, "per_page": {"500"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, Params{Page: 3, PerPage: MaxPerPage}, params)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		query url.Values
+		err   error
+	}{
+		{"zero page", url.Values{"page": {"0"}}, ErrInvalidPage},
+		{"page above max", url.Values{"page": {strconv.Itoa(MaxPage + 1)}}, ErrInvalidPage},
+		{"offset overflow", url.Values{"page": {"92233720368547760"}, "per_page": {"100"}}, ErrInvalidPage},
+		{"not a number", url.Values{"page": {"two"}}, ErrInvalidPage},
+		{"negative per_page", url.Values{"per_page": {"-1"}}, ErrInvalidPerPage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+// ==================== Slice Tests ====================
+
+func TestSlice(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	assert.Equal(t, []int{1, 2}, Slice(items, Params{Page: 1, PerPage: 2}))
+	assert.Equal(t, []int{5}, Slice(items, Params{Page: 3, PerPage: 2}))
+	assert.Empty(t, Slice(items, Params{Page: 4, PerPage: 2}))
+}
+
+func TestOffset_Clamped(t *testing.T) {
+	items := []int{1, 2, 3}
+
+	// Смещение огромной страницы не переполняется, страница за пределами списка пуста
+	overflow := Params{Page: 92233720368547760, PerPage: 100}
+	assert.Equal(t, (MaxPage-1)*MaxPerPage, overflow.Offset())
+	assert.Empty(t, Slice(items, overflow))
+
+	assert.Equal(t, 0, Params{Page: -5, PerPage: 2}.Offset())
+	assert.Equal(t, []int{1, 2}, Slice(items, Params{Page: 0, PerPage: 2}))
+
+	params, err := Parse(url.Values{"page": {strconv.Itoa(MaxPage)}, "per_page": {"100"}})
+	require.NoError(t, err)
+	assert.Equal(t, (MaxPage-1)*MaxPerPage, params.Offset())
+	assert.Positive(t, params.Offset())
+}
+
+// ==================== NewMeta Tests ====================
+
+func TestNewMeta_MiddlePageKeepsFilters(t *testing.T) {
+	// Arrange
+	u, _ := url.Parse("/products?category_id=42&page=2&per_page=10")
+
+	// Act
+	meta := NewMeta(u, Params{Page: 2, PerPage: 10}, 35)
+
+	// Assert
+	assert.Equal(t, 35, meta.Total)
+	assert.Equal(t, 4, meta.TotalPages)
+	assert.Equal(t, "/products?category_id=42&page=2&per_page=10", meta.Links.Self)
+	assert.Equal(t, "/products?category_id=42&page=1&per_page=10", meta.Links.First)
+	assert.Equal(t, "/products?category_id=42&page=4&per_page=10", meta.Links.Last)
+	assert.Equal(t, "/products?category_id=42&page=3&per_page=10", meta.Links.Next)
+	assert.Equal(t, "/products?category_id=42&page=1&per_page=10", meta.Links.Prev)
+}
+
+func TestNewMeta_EmptyList(t *testing.T) {
+	u, _ := url.Parse("/reviews/product/1")
+
+	meta := NewMeta(u, Default(), 0)
+
+	assert.Zero(t, meta.TotalPages)
+	assert.Equal(t, "/reviews/product/1?page=1&per_page=20", meta.Links.Last)
+	assert.Empty(t, meta.Links.Next)
+	assert.Empty(t, meta.Links.Prev)
+}
+
+func TestNewMeta_PageBeyondEndLinksBackToLast(t *testing.T) {
+	u, _ := url.Parse("/orders")
+
+	meta := NewMeta(u, Params{Page: 9, PerPage: 20}, 30)
+
+	assert.Empty(t, meta.Links.Next)
+	assert.Equal(t, "/orders?page=2&per_page=20", meta.Links.Prev)
+}
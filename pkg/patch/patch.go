@@ -0,0 +1,121 @@
+// Package patch реализует поля запросов на частичное обновление по JSON Merge Patch (RFC 7386):
+// отсутствующее поле не меняется, null удаляет значение, остальное заменяет его.
+// В DTO поля объявляются с тегом json omitzero, чтобы незаданные поля не попадали в JSON
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ErrNull - null передан для поля, которое нельзя удалить
+var ErrNull = errors.New("field cannot be null")
+
+// Field - поле, которое можно изменить, но нельзя удалить
+// Нулевое значение (пустая строка, 0) считается заданным и проходит валидацию как есть
+type Field[T any] struct {
+	Set   bool // Поле присутствует в запросе
+	Value T
+}
+
+// Of возвращает заданное поле; используется в тестах и при сборке запросов в коде
+func Of[T any](value T) Field[T] {
+	return Field[T]{Set: true, Value: value}
+}
+
+// UnmarshalJSON отмечает поле заданным; null отклоняется
+func (f *Field[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return ErrNull
+	}
+	if err := json.Unmarshal(data, &f.Value); err != nil {
+		return err
+	}
+	f.Set = true
+	return nil
+}
+
+// MarshalJSON кодирует значение; незаданное поле пропускается тегом omitzero
+func (f Field[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.Value)
+}
+
+// IsZero сообщает, что поле не задано
+func (f Field[T]) IsZero() bool {
+	return !f.Set
+}
+
+// Nullable - поле, которое можно изменить или удалить передачей null
+type Nullable[T any] struct {
+	Set   bool // Поле присутствует в запросе
+	Value *T   // nil - значение удаляется
+}
+
+// Value возвращает заданное поле со значением
+func Value[T any](value T) Nullable[T] {
+	return Nullable[T]{Set: true, Value: &value}
+}
+
+// Null возвращает поле, удаляющее значение
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{Set: true}
+}
+
+// UnmarshalJSON отмечает поле заданным; null удаляет значение
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		n.Set, n.Value = true, nil
+		return nil
+	}
+	value := new(T)
+	if err := json.Unmarshal(data, value); err != nil {
+		return err
+	}
+	n.Set, n.Value = true, value
+	return nil
+}
+
+// MarshalJSON кодирует значение или null
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.Value)
+}
+
+// IsZero сообщает, что поле не задано
+func (n Nullable[T]) IsZero() bool {
+	return !n.Set
+}
+
+// OrZero возвращает значение или нулевое значение T для null
+func (n Nullable[T]) OrZero() T {
+	var zero T
+	if n.Value == nil {
+		return zero
+	}
+	return *n.Value
+}
+
+// Apply записывает значение в dst, если поле задано
+func (n Nullable[T]) Apply(dst **T) {
+	if n.Set {
+		*dst = n.Value
+	}
+}
+
+// Register учит validator проверять значения полей Field[T] и Nullable[T] по тегам поля DTO
+// Незаданные поля и null пропускаются тегом omitempty, как nil указатели
+func Register[T any](v *validator.Validate) {
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		f := field.Interface().(Field[T])
+		if !f.Set {
+			return (*T)(nil)
+		}
+		return &f.Value
+	}, Field[T]{})
+
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		return field.Interface().(Nullable[T]).Value
+	}, Nullable[T]{})
+}
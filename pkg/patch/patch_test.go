@@ -0,0 +1,96 @@
+package patch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRequest struct {
+	Name  Field[string] `json:"name" validate:"omitempty,min=2"`
+	Price Field[int]    `json:"price" validate:"omitempty,gte=0"`
+	Stock Nullable[int] `json:"stock" validate:"omitempty,gte=0"`
+}
+
+func newTestValidator() *validator.Validate {
+	v := validator.New()
+	Register[string](v)
+	Register[int](v)
+	return v
+}
+
+func TestUnmarshal_MergePatchSemantics(t *testing.T) {
+	var req testRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"price": 0, "stock": null}`), &req))
+
+	assert.False(t, req.Name.Set)
+	assert.Equal(t, Of(0), req.Price)
+	assert.Equal(t, Null[int](), req.Stock)
+
+	req = testRequest{}
+	require.NoError(t, json.Unmarshal([]byte(`{"stock": 5}`), &req))
+	assert.Equal(t, Value(5), req.Stock)
+}
+
+func TestUnmarshal_FieldRejectsNull(t *testing.T) {
+	var req testRequest
+	err := json.Unmarshal([]byte(`{"name": null}`), &req)
+	assert.ErrorIs(t, err, ErrNull)
+}
+
+func TestNullable_Apply(t *testing.T) {
+	current := 3
+	dst := &current
+
+	Nullable[int]{}.Apply(&dst)
+	assert.Equal(t, 3, *dst)
+
+	Value(7).Apply(&dst)
+	assert.Equal(t, 7, *dst)
+
+	Null[int]().Apply(&dst)
+	assert.Nil(t, dst)
+}
+
+func TestRegister_ValidatesSetValues(t *testing.T) {
+	v := newTestValidator()
+
+	tests := []struct {
+		name    string
+		req     testRequest
+		wantErr bool
+	}{
+		{name: "empty patch", req: testRequest{}},
+		{name: "zero price", req: testRequest{Price: Of(0)}},
+		{name: "null stock", req: testRequest{Stock: Null[int]()}},
+		{name: "empty name", req: testRequest{Name: Of("")}, wantErr: true},
+		{name: "negative price", req: testRequest{Price: Of(-1)}, wantErr: true},
+		{name: "negative stock", req: testRequest{Stock: Value(-1)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Struct(tt.req)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMarshal_OmitsUnsetFields(t *testing.T) {
+	type request struct {
+		Name  Field[string] `json:"name,omitzero"`
+		Price Field[int]    `json:"price,omitzero"`
+		Stock Nullable[int] `json:"stock,omitzero"`
+	}
+
+	data, err := json.Marshal(request{Price: Of(0), Stock: Null[int]()})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"price": 0, "stock": null}`, string(data))
+}
@@ -0,0 +1,97 @@
+package quota
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetLimitRequest - тело запроса PUT /admin/quotas/:subject
+type SetLimitRequest struct {
+	Daily   int64  `json:"daily"`   // 0 - без ограничения
+	Monthly int64  `json:"monthly"` // 0 - без ограничения
+	Note    string `json:"note"`
+}
+
+// Handler - admin API для просмотра расхода и изменения квот
+type Handler struct {
+	tracker *Tracker
+}
+
+// NewHandler создает admin API квот
+func NewHandler(tracker *Tracker) *Handler {
+	return &Handler{tracker: tracker}
+}
+
+// RegisterRoutes регистрирует маршруты в группе (доступ к группе ограничивает вызывающий сервис)
+// Субъект: user:<id пользователя> или key:<значение X-Quota-Subject из ответа на запрос с ключом>
+func (h *Handler) RegisterRoutes(group *gin.RouterGroup) {
+	group.GET("", h.List)
+	group.GET("/:subject", h.Get)
+	group.PUT("/:subject", h.Set)
+	group.DELETE("/:subject", h.Delete)
+}
+
+// List обрабатывает GET /admin/quotas - квоты по умолчанию и индивидуальные квоты
+func (h *Handler) List(c *gin.Context) {
+	limits, err := h.tracker.ListLimits(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list quotas"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"defaults": h.tracker.Defaults(), "limits": limits, "total": len(limits)})
+}
+
+// Get обрабатывает GET /admin/quotas/:subject - действующие квоты и расход за текущие периоды
+func (h *Handler) Get(c *gin.Context) {
+	status, err := h.tracker.Status(c.Request.Context(), c.Param("subject"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get quota"})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// Set обрабатывает PUT /admin/quotas/:subject - создает или заменяет индивидуальную квоту
+func (h *Handler) Set(c *gin.Context) {
+	var req SetLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	limit := &Limit{
+		Subject: c.Param("subject"),
+		Daily:   req.Daily,
+		Monthly: req.Monthly,
+		Note:    req.Note,
+	}
+
+	if err := h.tracker.SetLimit(c.Request.Context(), limit); err != nil {
+		if errors.Is(err, ErrInvalidLimit) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid quota: limits must not be negative"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set quota"})
+		return
+	}
+
+	c.JSON(http.StatusOK, limit)
+}
+
+// Delete обрабатывает DELETE /admin/quotas/:subject - возвращает квоты по умолчанию
+func (h *Handler) Delete(c *gin.Context) {
+	if err := h.tracker.DeleteLimit(c.Request.Context(), c.Param("subject")); err != nil {
+		if errors.Is(err, ErrLimitNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Quota not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete quota"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Quota reset to defaults"})
+}
@@ -0,0 +1,79 @@
+package quota
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyContextKey - ключ контекста gin с API ключом интеграции, уже проверенным по хранилищу ключей
+// Заголовок X-API-Key сам по себе квоту не выбирает: иначе случайный ключ в каждом запросе
+// давал бы новую квоту по умолчанию в обход квоты пользователя
+const APIKeyContextKey = "api_key"
+
+// Заголовки ответа с состоянием квоты
+const (
+	HeaderLimit     = "X-Quota-Limit"
+	HeaderRemaining = "X-Quota-Remaining"
+	HeaderReset     = "X-Quota-Reset" // Unix время начала следующего периода
+	HeaderSubject   = "X-Quota-Subject"
+)
+
+// Subject определяет, чью квоту расходует запрос
+// Учитываются только аутентифицированные субъекты: проверенный API ключ (по хешу, чтобы ключ
+// не попадал в Redis, PostgreSQL и admin API) или пользователь из JWT. Анонимные запросы квотой не ограничиваются
+func Subject(c *gin.Context) string {
+	if key := c.GetString(APIKeyContextKey); key != "" {
+		sum := sha256.Sum256([]byte(key))
+		return "key:" + hex.EncodeToString(sum[:8])
+	}
+	if userID, ok := c.Get("user_id"); ok {
+		return fmt.Sprintf("user:%v", userID)
+	}
+	return ""
+}
+
+// Middleware расходует квоту субъекта запроса и возвращает 429, если она исчерпана
+// Ставится после auth middleware. При недоступности Redis запросы пропускаются без учета
+// Nil-трекер ничего не ограничивает
+func Middleware(tracker *Tracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		subject := Subject(c)
+		if tracker == nil || subject == "" {
+			c.Next()
+			return
+		}
+
+		decision, err := tracker.Consume(c.Request.Context(), subject)
+		if err != nil {
+			log.Printf("Quota check for %s failed, request allowed: %v", subject, err)
+			c.Next()
+			return
+		}
+
+		if decision.Limit > 0 {
+			c.Header(HeaderLimit, strconv.FormatInt(decision.Limit, 10))
+			c.Header(HeaderRemaining, strconv.FormatInt(decision.Remaining, 10))
+			c.Header(HeaderReset, strconv.FormatInt(decision.Reset.Unix(), 10))
+			c.Header(HeaderSubject, subject)
+		}
+
+		if !decision.Allowed {
+			retryAfter := int(time.Until(decision.Reset).Seconds()) + 1
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":  "Request quota exceeded",
+				"period": decision.Period,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,99 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type postgresStore struct {
+	db *gorm.DB
+}
+
+// NewPostgresStore создает хранилище квот в таблицах request_quotas и request_quota_usage
+// Таблицы создаются миграцией сервиса (см. migration/postgres)
+func NewPostgresStore(db *gorm.DB) Store {
+	return &postgresStore{db: db}
+}
+
+func (s *postgresStore) GetLimit(ctx context.Context, subject string) (*Limit, error) {
+	var limit Limit
+	if err := s.db.WithContext(ctx).First(&limit, "subject = ?", subject).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLimitNotFound
+		}
+		return nil, fmt.Errorf("failed to get quota limit: %w", err)
+	}
+	return &limit, nil
+}
+
+func (s *postgresStore) ListLimits(ctx context.Context) ([]Limit, error) {
+	var limits []Limit
+	if err := s.db.WithContext(ctx).Order("subject ASC").Find(&limits).Error; err != nil {
+		return nil, fmt.Errorf("failed to list quota limits: %w", err)
+	}
+	return limits, nil
+}
+
+func (s *postgresStore) SetLimit(ctx context.Context, limit *Limit) error {
+	if err := limit.Validate(); err != nil {
+		return err
+	}
+
+	// Upsert: PUT квоты создает ее или полностью заменяет
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "subject"}},
+		DoUpdates: clause.AssignmentColumns([]string{"daily", "monthly", "note", "updated_at"}),
+	}).Create(limit).Error
+	if err != nil {
+		return fmt.Errorf("failed to set quota limit: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresStore) DeleteLimit(ctx context.Context, subject string) error {
+	result := s.db.WithContext(ctx).Delete(&Limit{}, "subject = ?", subject)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete quota limit: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrLimitNotFound
+	}
+	return nil
+}
+
+func (s *postgresStore) LoadUsage(ctx context.Context, subject string, period Period, start time.Time) (int64, error) {
+	var usage Usage
+	err := s.db.WithContext(ctx).
+		First(&usage, "subject = ? AND period = ? AND period_start = ?", subject, period, start).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to load quota usage: %w", err)
+	}
+	return usage.Count, nil
+}
+
+func (s *postgresStore) SaveUsage(ctx context.Context, usage []Usage) error {
+	if len(usage) == 0 {
+		return nil
+	}
+
+	// Несколько экземпляров сервиса сохраняют одни и те же счетчики: побеждает большее значение
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "subject"}, {Name: "period"}, {Name: "period_start"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"count":      gorm.Expr("GREATEST(request_quota_usage.count, EXCLUDED.count)"),
+			"updated_at": gorm.Expr("EXCLUDED.updated_at"),
+		}),
+	}).Create(&usage).Error
+	if err != nil {
+		return fmt.Errorf("failed to save quota usage: %w", err)
+	}
+	return nil
+}
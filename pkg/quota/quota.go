@@ -0,0 +1,100 @@
+// Package quota учитывает дневные и месячные квоты запросов пользователей и API ключей
+// Счетчики хранятся в Redis и периодически сохраняются в PostgreSQL, чтобы пережить потерю Redis
+// Индивидуальные квоты задаются администратором, остальные субъекты получают квоты по умолчанию
+package quota
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrLimitNotFound - для субъекта не задана индивидуальная квота
+	ErrLimitNotFound = errors.New("quota limit not found")
+	// ErrInvalidLimit - некорректные параметры квоты
+	ErrInvalidLimit = errors.New("invalid quota limit")
+)
+
+// Period - период, за который считаются запросы
+type Period string
+
+// Периоды квот: границы считаются по UTC
+const (
+	Daily   Period = "daily"
+	Monthly Period = "monthly"
+)
+
+// Limits - число запросов за период; 0 - без ограничения
+type Limits struct {
+	Daily   int64 `json:"daily"`
+	Monthly int64 `json:"monthly"`
+}
+
+// Limit - индивидуальная квота субъекта (user:<id> или key:<хеш ключа>)
+type Limit struct {
+	Subject   string    `json:"subject" gorm:"primaryKey;type:varchar(100)"`
+	Daily     int64     `json:"daily" gorm:"not null;default:0"`
+	Monthly   int64     `json:"monthly" gorm:"not null;default:0"`
+	Note      string    `json:"note" gorm:"type:text;not null;default:''"` // Причина изменения квоты
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (Limit) TableName() string {
+	return "request_quotas"
+}
+
+// Validate проверяет параметры квоты перед сохранением
+func (l *Limit) Validate() error {
+	if l.Subject == "" || len(l.Subject) > 100 {
+		return ErrInvalidLimit
+	}
+	if l.Daily < 0 || l.Monthly < 0 {
+		return ErrInvalidLimit
+	}
+	return nil
+}
+
+// Limits возвращает лимиты квоты
+func (l *Limit) Limits() Limits {
+	return Limits{Daily: l.Daily, Monthly: l.Monthly}
+}
+
+// Usage - сохраненное значение счетчика за период
+type Usage struct {
+	Subject     string    `gorm:"primaryKey;type:varchar(100)"`
+	Period      Period    `gorm:"primaryKey;type:varchar(10)"`
+	PeriodStart time.Time `gorm:"primaryKey;type:date"`
+	Count       int64     `gorm:"not null;default:0"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName указывает имя таблицы для GORM
+func (Usage) TableName() string {
+	return "request_quota_usage"
+}
+
+// Store - хранилище индивидуальных квот и сохраненных счетчиков
+type Store interface {
+	GetLimit(ctx context.Context, subject string) (*Limit, error)
+	ListLimits(ctx context.Context) ([]Limit, error)
+	SetLimit(ctx context.Context, limit *Limit) error
+	DeleteLimit(ctx context.Context, subject string) error
+
+	// LoadUsage возвращает сохраненное значение счетчика, 0 - если его нет
+	LoadUsage(ctx context.Context, subject string, period Period, start time.Time) (int64, error)
+	// SaveUsage сохраняет счетчики; значение не уменьшается, если в хранилище уже больше
+	SaveUsage(ctx context.Context, usage []Usage) error
+}
+
+// periodBounds возвращает начало и конец периода, в который попадает now (UTC)
+func periodBounds(period Period, now time.Time) (time.Time, time.Time) {
+	now = now.UTC()
+	if period == Monthly {
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	}
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 0, 1)
+}
@@ -0,0 +1,316 @@
+package quota
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore - хранилище квот в памяти для тестов
+type memoryStore struct {
+	mu     sync.Mutex
+	limits map[string]Limit
+	usage  map[string]int64
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{limits: make(map[string]Limit), usage: make(map[string]int64)}
+}
+
+func usageKey(subject string, period Period, start time.Time) string {
+	return subject + "|" + string(period) + "|" + start.Format(time.DateOnly)
+}
+
+func (s *memoryStore) GetLimit(_ context.Context, subject string) (*Limit, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	limit, ok := s.limits[subject]
+	if !ok {
+		return nil, ErrLimitNotFound
+	}
+	return &limit, nil
+}
+
+func (s *memoryStore) ListLimits(_ context.Context) ([]Limit, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	limits := make([]Limit, 0, len(s.limits))
+	for _, limit := range s.limits {
+		limits = append(limits, limit)
+	}
+	return limits, nil
+}
+
+func (s *memoryStore) SetLimit(_ context.Context, limit *Limit) error {
+	if err := limit.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limits[limit.Subject] = *limit
+	return nil
+}
+
+func (s *memoryStore) DeleteLimit(_ context.Context, subject string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.limits[subject]; !ok {
+		return ErrLimitNotFound
+	}
+	delete(s.limits, subject)
+	return nil
+}
+
+func (s *memoryStore) LoadUsage(_ context.Context, subject string, period Period, start time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.usage[usageKey(subject, period, start)], nil
+}
+
+func (s *memoryStore) SaveUsage(_ context.Context, usage []Usage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, u := range usage {
+		key := usageKey(u.Subject, u.Period, u.PeriodStart)
+		if u.Count > s.usage[key] {
+			s.usage[key] = u.Count
+		}
+	}
+	return nil
+}
+
+func newTestTracker(t *testing.T, defaults Limits) (*Tracker, *memoryStore, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	store := newMemoryStore()
+	tracker := NewTracker(store, client, defaults, time.Minute)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+	return tracker, store, mr
+}
+
+// ====== Tracker Tests ======
+
+func TestTracker_Consume_DailyLimit(t *testing.T) {
+	tracker, _, _ := newTestTracker(t, Limits{Daily: 3, Monthly: 100})
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		decision, err := tracker.Consume(ctx, "user:1")
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, Daily, decision.Period)
+		assert.Equal(t, int64(3-i), decision.Remaining)
+	}
+
+	decision, err := tracker.Consume(ctx, "user:1")
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, int64(0), decision.Remaining)
+	assert.Equal(t, time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC), decision.Reset)
+
+	// Отклоненный запрос не расходует квоту, другие субъекты не затронуты
+	status, err := tracker.Status(ctx, "user:1")
+	require.NoError(t, err)
+	assert.Equal(t, Limits{Daily: 3, Monthly: 3}, status.Used)
+
+	decision, err = tracker.Consume(ctx, "user:2")
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+}
+
+func TestTracker_Consume_CustomLimit(t *testing.T) {
+	tracker, _, _ := newTestTracker(t, Limits{Daily: 1})
+	ctx := context.Background()
+
+	require.NoError(t, tracker.SetLimit(ctx, &Limit{Subject: "key:abc", Monthly: 2}))
+
+	for i := 0; i < 2; i++ {
+		decision, err := tracker.Consume(ctx, "key:abc")
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, Monthly, decision.Period)
+	}
+	decision, err := tracker.Consume(ctx, "key:abc")
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), decision.Reset)
+
+	// Снятие индивидуальной квоты возвращает квоты по умолчанию
+	require.NoError(t, tracker.DeleteLimit(ctx, "key:abc"))
+	status, err := tracker.Status(ctx, "key:abc")
+	require.NoError(t, err)
+	assert.False(t, status.Custom)
+	assert.Equal(t, Limits{Daily: 1}, status.Limits)
+}
+
+func TestTracker_Unlimited(t *testing.T) {
+	tracker, _, _ := newTestTracker(t, Limits{})
+
+	decision, err := tracker.Consume(context.Background(), "user:1")
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	assert.Zero(t, decision.Limit)
+}
+
+func TestTracker_FlushAndRestoreAfterRedisLoss(t *testing.T) {
+	tracker, store, mr := newTestTracker(t, Limits{Daily: 5, Monthly: 10})
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		_, err := tracker.Consume(ctx, "user:1")
+		require.NoError(t, err)
+	}
+	require.NoError(t, tracker.Flush(ctx))
+
+	day, _ := periodBounds(Daily, tracker.now())
+	count, err := store.LoadUsage(ctx, "user:1", Daily, day)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), count)
+
+	// Redis потерял данные: счетчики продолжаются с сохраненных значений
+	mr.FlushAll()
+
+	decision, err := tracker.Consume(ctx, "user:1")
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, int64(0), decision.Remaining)
+
+	decision, err = tracker.Consume(ctx, "user:1")
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+}
+
+func TestTracker_CacheBounded(t *testing.T) {
+	tracker, _, _ := newTestTracker(t, Limits{Daily: 100})
+	ctx := context.Background()
+
+	for i := 0; i < maxCacheEntries+10; i++ {
+		_, _, err := tracker.limitsFor(ctx, fmt.Sprintf("user:%d", i))
+		require.NoError(t, err)
+	}
+
+	assert.LessOrEqual(t, len(tracker.cache), maxCacheEntries)
+	assert.Contains(t, tracker.cache, fmt.Sprintf("user:%d", maxCacheEntries+9))
+}
+
+// ====== Middleware Tests ======
+
+func newQuotaRouter(tracker *Tracker, userID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if userID != uuid.Nil {
+			c.Set("user_id", userID)
+		}
+	}, Middleware(tracker))
+	router.GET("/resource", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func TestMiddleware_HeadersAndTooManyRequests(t *testing.T) {
+	tracker, _, _ := newTestTracker(t, Limits{Daily: 1})
+	router := newQuotaRouter(tracker, uuid.New())
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/resource", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get(HeaderLimit))
+	assert.Equal(t, "0", rec.Header().Get(HeaderRemaining))
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/resource", nil))
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+}
+
+func TestMiddleware_AnonymousNotLimited(t *testing.T) {
+	tracker, _, _ := newTestTracker(t, Limits{Daily: 1})
+	router := newQuotaRouter(tracker, uuid.Nil)
+
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/resource", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get(HeaderRemaining))
+	}
+}
+
+func TestSubject_APIKeyIsHashed(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Set(APIKeyContextKey, "secret-key")
+	c.Set("user_id", uuid.New())
+
+	subject := Subject(c)
+	assert.Regexp(t, `^key:[0-9a-f]{16}$`, subject)
+	assert.NotContains(t, subject, "secret-key")
+}
+
+func TestSubject_UnverifiedAPIKeyHeaderIgnored(t *testing.T) {
+	userID := uuid.New()
+	tests := []struct {
+		name     string
+		userID   any
+		expected string
+	}{
+		{name: "authenticated user keeps own quota", userID: userID, expected: "user:" + userID.String()},
+		{name: "anonymous with forged key not metered", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			c.Request.Header.Set("X-API-Key", uuid.NewString())
+			if tt.userID != nil {
+				c.Set("user_id", tt.userID)
+			}
+
+			// Act
+			subject := Subject(c)
+
+			// Assert
+			assert.Equal(t, tt.expected, subject)
+		})
+	}
+}
+
+// ====== Handler Tests ======
+
+func TestHandler_SetAndGet(t *testing.T) {
+	tracker, _, _ := newTestTracker(t, Limits{Daily: 10})
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewHandler(tracker).RegisterRoutes(router.Group("/admin/quotas"))
+
+	body := bytes.NewBufferString(`{"daily": 500, "monthly": 10000, "note": "enterprise plan"}`)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/admin/quotas/user:42", body))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/quotas/user:42", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"limits":{"daily":500,"monthly":10000}`)
+	assert.Contains(t, rec.Body.String(), `"custom":true`)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/admin/quotas/user:42", bytes.NewBufferString(`{"daily": -1}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,389 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// maxCacheEntries - сколько индивидуальных квот держится в кеше процесса
+// При заполнении сначала удаляются истекшие записи, затем произвольные
+const maxCacheEntries = 10_000
+
+// counterGrace - сколько счетчик живет в Redis после конца периода, чтобы его успели сохранить
+const counterGrace = time.Hour
+
+// consumeScript атомарно проверяет квоты и увеличивает оба счетчика
+// KEYS: дневной и месячный счетчики (один hash tag - один слот в Redis Cluster)
+// ARGV: дневной лимит, месячный лимит, TTL дневного, TTL месячного (сек), restore, сохраненные значения
+// Если счетчиков нет и restore=0, возвращает -1: вызывающий загружает сохраненные значения и повторяет
+var consumeScript = redis.NewScript(`
+if ARGV[5] == '1' then
+	redis.call('SET', KEYS[1], ARGV[6], 'NX', 'EX', ARGV[3])
+	redis.call('SET', KEYS[2], ARGV[7], 'NX', 'EX', ARGV[4])
+elseif redis.call('EXISTS', KEYS[1], KEYS[2]) < 2 then
+	return {-1, -1, 0}
+end
+local daily = tonumber(redis.call('GET', KEYS[1]))
+local monthly = tonumber(redis.call('GET', KEYS[2]))
+local dailyLimit = tonumber(ARGV[1])
+local monthlyLimit = tonumber(ARGV[2])
+if (dailyLimit > 0 and daily >= dailyLimit) or (monthlyLimit > 0 and monthly >= monthlyLimit) then
+	return {daily, monthly, 0}
+end
+return {redis.call('INCR', KEYS[1]), redis.call('INCR', KEYS[2]), 1}
+`)
+
+// Decision - результат учета запроса
+// Limit, Remaining и Reset относятся к периоду, который исчерпается раньше
+type Decision struct {
+	Allowed   bool
+	Period    Period
+	Limit     int64 // 0 - квоты нет ни на один период
+	Remaining int64
+	Reset     time.Time
+}
+
+// Status - квоты субъекта и расход за текущие периоды
+type Status struct {
+	Subject  string `json:"subject"`
+	Limits   Limits `json:"limits"`
+	Custom   bool   `json:"custom"` // Задана индивидуальная квота
+	Used     Limits `json:"used"`
+	Defaults Limits `json:"defaults"`
+}
+
+type cacheEntry struct {
+	limit   *Limit // nil - индивидуальной квоты нет
+	expires time.Time
+}
+
+// Tracker учитывает запросы субъектов в Redis и проверяет квоты
+// Индивидуальные квоты кешируются в памяти процесса на cacheTTL, как флаги в featureflags
+// Затронутые счетчики запоминаются и сохраняются в хранилище методом Flush
+type Tracker struct {
+	store    Store
+	redis    redis.UniversalClient
+	defaults Limits
+	cacheTTL time.Duration
+	now      func() time.Time
+
+	mu    sync.RWMutex
+	cache map[string]cacheEntry
+
+	dirtyMu sync.Mutex
+	dirty   map[dirtyCounter]struct{}
+}
+
+// dirtyCounter - счетчики субъекта за день (и месяц этого дня), ожидающие сохранения
+// День хранится, чтобы сохранить счетчики прошлого периода после его окончания
+type dirtyCounter struct {
+	subject  string
+	dayStart time.Time
+}
+
+// NewTracker создает учет квот
+func NewTracker(store Store, client redis.UniversalClient, defaults Limits, cacheTTL time.Duration) *Tracker {
+	return &Tracker{
+		store:    store,
+		redis:    client,
+		defaults: defaults,
+		cacheTTL: cacheTTL,
+		now:      time.Now,
+		cache:    make(map[string]cacheEntry),
+		dirty:    make(map[dirtyCounter]struct{}),
+	}
+}
+
+// Consume учитывает запрос субъекта, если квота не исчерпана
+// Отклоненные запросы не расходуют квоту
+func (t *Tracker) Consume(ctx context.Context, subject string) (*Decision, error) {
+	limits, _, err := t.limitsFor(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	now := t.now()
+	dayStart, dayEnd := periodBounds(Daily, now)
+	monthStart, monthEnd := periodBounds(Monthly, now)
+	keys := counterKeys(subject, dayStart, monthStart)
+	args := []interface{}{
+		limits.Daily, limits.Monthly,
+		ttlSeconds(dayEnd, now), ttlSeconds(monthEnd, now),
+		0, 0, 0,
+	}
+
+	result, err := consumeScript.Run(ctx, t.redis, keys, args...).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume quota: %w", err)
+	}
+
+	// Начало периода или потеря Redis: продолжаем с сохраненных значений
+	if result[0] < 0 {
+		daily, err := t.store.LoadUsage(ctx, subject, Daily, dayStart)
+		if err != nil {
+			return nil, err
+		}
+		monthly, err := t.store.LoadUsage(ctx, subject, Monthly, monthStart)
+		if err != nil {
+			return nil, err
+		}
+		args[4], args[5], args[6] = 1, daily, monthly
+		if result, err = consumeScript.Run(ctx, t.redis, keys, args...).Int64Slice(); err != nil {
+			return nil, fmt.Errorf("failed to consume quota: %w", err)
+		}
+	}
+
+	allowed := result[2] == 1
+	if allowed {
+		t.markDirty(dirtyCounter{subject: subject, dayStart: dayStart})
+	}
+
+	decision := &Decision{Allowed: allowed}
+	daily := periodDecision(Daily, limits.Daily, result[0], dayEnd)
+	monthly := periodDecision(Monthly, limits.Monthly, result[1], monthEnd)
+	switch {
+	case daily == nil && monthly == nil:
+		return decision, nil
+	case daily == nil:
+		*decision = *monthly
+	case monthly == nil || daily.Remaining <= monthly.Remaining:
+		*decision = *daily
+	default:
+		*decision = *monthly
+	}
+	decision.Allowed = allowed
+	return decision, nil
+}
+
+// periodDecision описывает остаток квоты за период; nil - период не ограничен
+func periodDecision(period Period, limit, used int64, reset time.Time) *Decision {
+	if limit <= 0 {
+		return nil
+	}
+	remaining := limit - used
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &Decision{Period: period, Limit: limit, Remaining: remaining, Reset: reset}
+}
+
+// Status возвращает квоты субъекта и расход за текущие периоды
+func (t *Tracker) Status(ctx context.Context, subject string) (*Status, error) {
+	limits, custom, err := t.limitsFor(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := t.used(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Status{Subject: subject, Limits: limits, Custom: custom, Used: used, Defaults: t.defaults}, nil
+}
+
+// Defaults возвращает квоты по умолчанию
+func (t *Tracker) Defaults() Limits {
+	return t.defaults
+}
+
+// ListLimits возвращает индивидуальные квоты
+func (t *Tracker) ListLimits(ctx context.Context) ([]Limit, error) {
+	return t.store.ListLimits(ctx)
+}
+
+// SetLimit создает или заменяет индивидуальную квоту и сбрасывает ее кеш
+func (t *Tracker) SetLimit(ctx context.Context, limit *Limit) error {
+	if err := t.store.SetLimit(ctx, limit); err != nil {
+		return err
+	}
+	t.invalidate(limit.Subject)
+	return nil
+}
+
+// DeleteLimit возвращает субъекту квоты по умолчанию
+func (t *Tracker) DeleteLimit(ctx context.Context, subject string) error {
+	if err := t.store.DeleteLimit(ctx, subject); err != nil {
+		return err
+	}
+	t.invalidate(subject)
+	return nil
+}
+
+// Flush сохраняет в хранилище счетчики, изменившиеся с прошлого сохранения
+// Не сохраненные из-за ошибки счетчики остаются в очереди до следующего вызова
+func (t *Tracker) Flush(ctx context.Context) error {
+	t.dirtyMu.Lock()
+	counters := t.dirty
+	t.dirty = make(map[dirtyCounter]struct{})
+	t.dirtyMu.Unlock()
+	if len(counters) == 0 {
+		return nil
+	}
+
+	usage := make([]Usage, 0, 2*len(counters))
+	for counter := range counters {
+		monthStart, _ := periodBounds(Monthly, counter.dayStart)
+		values, err := t.redis.MGet(ctx, counterKeys(counter.subject, counter.dayStart, monthStart)...).Result()
+		if err != nil {
+			t.requeue(counters)
+			return fmt.Errorf("failed to read quota counters: %w", err)
+		}
+		if count, ok := parseCounter(values[0]); ok {
+			usage = append(usage, Usage{Subject: counter.subject, Period: Daily, PeriodStart: counter.dayStart, Count: count})
+		}
+		if count, ok := parseCounter(values[1]); ok {
+			usage = append(usage, Usage{Subject: counter.subject, Period: Monthly, PeriodStart: monthStart, Count: count})
+		}
+	}
+
+	if err := t.store.SaveUsage(ctx, usage); err != nil {
+		t.requeue(counters)
+		return err
+	}
+	return nil
+}
+
+// Run сохраняет счетчики каждые interval до отмены ctx, затем сохраняет последний раз
+func (t *Tracker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := t.Flush(flushCtx); err != nil {
+				log.Printf("Failed to flush quota usage: %v", err)
+			}
+			cancel()
+			return
+		case <-ticker.C:
+			if err := t.Flush(ctx); err != nil {
+				log.Printf("Failed to flush quota usage: %v", err)
+			}
+		}
+	}
+}
+
+// limitsFor возвращает действующие квоты субъекта и признак индивидуальной квоты
+func (t *Tracker) limitsFor(ctx context.Context, subject string) (Limits, bool, error) {
+	now := t.now()
+
+	t.mu.RLock()
+	entry, ok := t.cache[subject]
+	t.mu.RUnlock()
+	if !ok || !now.Before(entry.expires) {
+		limit, err := t.store.GetLimit(ctx, subject)
+		if err != nil && !errors.Is(err, ErrLimitNotFound) {
+			return Limits{}, false, err
+		}
+		entry = cacheEntry{limit: limit, expires: now.Add(t.cacheTTL)}
+		t.cacheLimit(subject, entry, now)
+	}
+
+	if entry.limit == nil {
+		return t.defaults, false, nil
+	}
+	return entry.limit.Limits(), true, nil
+}
+
+// used возвращает расход за текущие периоды: из Redis, если счетчиков там нет - из хранилища
+func (t *Tracker) used(ctx context.Context, subject string) (Limits, error) {
+	now := t.now()
+	dayStart, _ := periodBounds(Daily, now)
+	monthStart, _ := periodBounds(Monthly, now)
+
+	values, err := t.redis.MGet(ctx, counterKeys(subject, dayStart, monthStart)...).Result()
+	if err != nil {
+		return Limits{}, fmt.Errorf("failed to read quota counters: %w", err)
+	}
+
+	var used Limits
+	var ok bool
+	if used.Daily, ok = parseCounter(values[0]); !ok {
+		if used.Daily, err = t.store.LoadUsage(ctx, subject, Daily, dayStart); err != nil {
+			return Limits{}, err
+		}
+	}
+	if used.Monthly, ok = parseCounter(values[1]); !ok {
+		if used.Monthly, err = t.store.LoadUsage(ctx, subject, Monthly, monthStart); err != nil {
+			return Limits{}, err
+		}
+	}
+	return used, nil
+}
+
+// cacheLimit сохраняет квоту в кеше, не давая ему расти больше maxCacheEntries
+func (t *Tracker) cacheLimit(subject string, entry cacheEntry, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.cache[subject]; !ok && len(t.cache) >= maxCacheEntries {
+		for cached, e := range t.cache {
+			if !now.Before(e.expires) {
+				delete(t.cache, cached)
+			}
+		}
+		// Истекших нет: вытесняем произвольную запись, обход map в Go случайный
+		for cached := range t.cache {
+			if len(t.cache) < maxCacheEntries {
+				break
+			}
+			delete(t.cache, cached)
+		}
+	}
+	t.cache[subject] = entry
+}
+
+func (t *Tracker) invalidate(subject string) {
+	t.mu.Lock()
+	delete(t.cache, subject)
+	t.mu.Unlock()
+}
+
+func (t *Tracker) markDirty(counter dirtyCounter) {
+	t.dirtyMu.Lock()
+	t.dirty[counter] = struct{}{}
+	t.dirtyMu.Unlock()
+}
+
+func (t *Tracker) requeue(counters map[dirtyCounter]struct{}) {
+	t.dirtyMu.Lock()
+	for counter := range counters {
+		t.dirty[counter] = struct{}{}
+	}
+	t.dirtyMu.Unlock()
+}
+
+// counterKeys возвращает ключи дневного и месячного счетчиков субъекта
+// Субъект в фигурных скобках - hash tag: оба ключа попадают в один слот Redis Cluster
+func counterKeys(subject string, dayStart, monthStart time.Time) []string {
+	return []string{
+		"quota:{" + subject + "}:daily:" + dayStart.Format("2006-01-02"),
+		"quota:{" + subject + "}:monthly:" + monthStart.Format("2006-01"),
+	}
+}
+
+func ttlSeconds(periodEnd, now time.Time) int64 {
+	return int64((periodEnd.Sub(now) + counterGrace) / time.Second)
+}
+
+func parseCounter(value interface{}) (int64, bool) {
+	s, ok := value.(string)
+	if !ok {
+		return 0, false
+	}
+	count, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return count, true
+}
@@ -0,0 +1,144 @@
+package quote
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"augustberries/pkg/money"
+	"augustberries/pkg/units"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrMalformedToken - токен не удалось разобрать
+	ErrMalformedToken = errors.New("malformed quote token")
+	// ErrInvalidSignature - подпись не совпадает (токен подделан или подписан другим ключом)
+	ErrInvalidSignature = errors.New("invalid quote signature")
+	// ErrQuoteExpired - срок действия котировки истек
+	ErrQuoteExpired = errors.New("quote expired")
+	// ErrQuoteMismatch - позиции котировки не совпадают с позициями заказа
+	ErrQuoteMismatch = errors.New("quote does not match order items")
+)
+
+// Item - подтвержденная позиция: товар, количество и цена за единицу на момент котировки
+type Item struct {
+	ProductID  uuid.UUID      `json:"product_id"`
+	Quantity   units.Quantity `json:"quantity"`
+	UnitPrice  money.Amount   `json:"unit_price"`
+	CategoryID uuid.UUID      `json:"category_id"` // Категория товара для расчета налога; в старых котировках пустая
+
+	// Название товара и категории для снимка позиции заказа; описание не передается, чтобы токен оставался коротким
+	Name         string     `json:"name,omitempty"`
+	CategoryName string     `json:"category_name,omitempty"`
+	Unit         units.Unit `json:"unit,omitempty"` // Единица количества; в старых котировках пустая - штуки
+
+	// PerCustomerLimit - сколько товара покупатель может заказать всего; nil - без лимита
+	PerCustomerLimit *units.Quantity `json:"per_customer_limit,omitempty"`
+}
+
+// Quote - подписанная ценовая котировка Catalog Service
+// Подтверждает, что товары существовали и продавались по указанным ценам на момент IssuedAt
+type Quote struct {
+	ID        uuid.UUID `json:"id"`
+	TenantID  string    `json:"tenant_id,omitempty"` // Магазин, каталог которого выдал котировку
+	Items     []Item    `json:"items"`
+	Currency  string    `json:"currency"`
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Signer подписывает и проверяет котировки через HMAC-SHA256
+// Catalog Service и Orders Service должны использовать один и тот же секрет
+type Signer struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// NewSigner создает подписчик котировок
+func NewSigner(secret string) *Signer {
+	return &Signer{
+		secret: []byte(secret),
+		now:    time.Now,
+	}
+}
+
+// Sign сериализует котировку и возвращает токен вида base64(payload).base64(signature)
+func (s *Signer) Sign(q *Quote) (string, error) {
+	payload, err := json.Marshal(q)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal quote: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.sign(encoded)), nil
+}
+
+// Verify проверяет подпись и срок действия токена и возвращает котировку
+func (s *Signer) Verify(token string) (*Quote, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || encoded == "" || signature == "" {
+		return nil, ErrMalformedToken
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	if !hmac.Equal(sig, s.sign(encoded)) {
+		return nil, ErrInvalidSignature
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	var q Quote
+	if err := json.Unmarshal(payload, &q); err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	if !s.now().Before(q.ExpiresAt) {
+		return nil, ErrQuoteExpired
+	}
+
+	return &q, nil
+}
+
+// Match проверяет, что котировка покрывает ровно запрошенные товары и количества
+// requested - количество по каждому товару
+func (q *Quote) Match(requested map[uuid.UUID]units.Quantity) error {
+	if len(q.Items) != len(requested) {
+		return ErrQuoteMismatch
+	}
+	for _, item := range q.Items {
+		quantity, ok := requested[item.ProductID]
+		if !ok || quantity != item.Quantity {
+			return fmt.Errorf("%w: product %s", ErrQuoteMismatch, item.ProductID)
+		}
+	}
+	return nil
+}
+
+// Price возвращает цену товара из котировки
+func (q *Quote) Price(productID uuid.UUID) (money.Amount, bool) {
+	for _, item := range q.Items {
+		if item.ProductID == productID {
+			return item.UnitPrice, true
+		}
+	}
+	return 0, false
+}
+
+func (s *Signer) sign(data string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
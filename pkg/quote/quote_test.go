@@ -0,0 +1,97 @@
+package quote
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"augustberries/pkg/money"
+	"augustberries/pkg/units"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestQuote(productID uuid.UUID, ttl time.Duration) *Quote {
+	now := time.Now()
+	return &Quote{
+		ID:        uuid.New(),
+		Items:     []Item{{ProductID: productID, Quantity: units.Of(2), UnitPrice: money.MustParse("99.99")}},
+		Currency:  "USD",
+		IssuedAt:  now,
+		ExpiresAt: now.Add(ttl),
+	}
+}
+
+// ====== Signer Tests ======
+
+func TestSigner_SignAndVerify(t *testing.T) {
+	signer := NewSigner("secret")
+	productID := uuid.New()
+
+	token, err := signer.Sign(newTestQuote(productID, time.Minute))
+	require.NoError(t, err)
+
+	q, err := signer.Verify(token)
+	require.NoError(t, err)
+
+	price, ok := q.Price(productID)
+	assert.True(t, ok)
+	assert.Equal(t, money.MustParse("99.99"), price)
+}
+
+func TestSigner_Verify_TamperedPayload(t *testing.T) {
+	signer := NewSigner("secret")
+	token, err := signer.Sign(newTestQuote(uuid.New(), time.Minute))
+	require.NoError(t, err)
+
+	// Подменяем payload токеном другой котировки, сохраняя старую подпись
+	other, err := signer.Sign(newTestQuote(uuid.New(), time.Minute))
+	require.NoError(t, err)
+
+	otherPayload, _, _ := strings.Cut(other, ".")
+	_, signature, _ := strings.Cut(token, ".")
+
+	forged := otherPayload + "." + signature
+	_, err = signer.Verify(forged)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestSigner_Verify_WrongSecret(t *testing.T) {
+	token, err := NewSigner("secret").Sign(newTestQuote(uuid.New(), time.Minute))
+	require.NoError(t, err)
+
+	_, err = NewSigner("other-secret").Verify(token)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestSigner_Verify_Expired(t *testing.T) {
+	signer := NewSigner("secret")
+	token, err := signer.Sign(newTestQuote(uuid.New(), time.Minute))
+	require.NoError(t, err)
+
+	signer.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+
+	_, err = signer.Verify(token)
+	assert.ErrorIs(t, err, ErrQuoteExpired)
+}
+
+func TestSigner_Verify_Malformed(t *testing.T) {
+	signer := NewSigner("secret")
+	for _, token := range []string{"", "abc", "abc.", ".abc", "!!!.???"} {
+		_, err := signer.Verify(token)
+		assert.Error(t, err, token)
+	}
+}
+
+// ====== Quote Tests ======
+
+func TestQuote_Match(t *testing.T) {
+	productID := uuid.New()
+	q := newTestQuote(productID, time.Minute)
+
+	assert.NoError(t, q.Match(map[uuid.UUID]units.Quantity{productID: units.Of(2)}))
+	assert.ErrorIs(t, q.Match(map[uuid.UUID]units.Quantity{productID: units.Of(3)}), ErrQuoteMismatch)
+	assert.ErrorIs(t, q.Match(map[uuid.UUID]units.Quantity{productID: units.Of(2), uuid.New(): units.One}), ErrQuoteMismatch)
+}
@@ -0,0 +1,81 @@
+package recovery
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorResponse - ответ на запрос, обработка которого завершилась паникой
+type ErrorResponse struct {
+	Error   string `json:"error"`
+	ErrorID string `json:"error_id"` // Идентификатор паники в логах и Sentry
+}
+
+// Middleware перехватывает панику в обработчиках Gin и отвечает 500 с идентификатором ошибки
+// Заменяет gin.Recovery; должен стоять первым, чтобы покрывать остальные middleware
+func Middleware(service string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			value := recover()
+			if value == nil {
+				return
+			}
+
+			// Клиент закрыл соединение - отвечать некому, это не ошибка сервиса
+			if brokenPipe(value) {
+				c.Abort()
+				return
+			}
+
+			route := c.FullPath()
+			if route == "" {
+				route = c.Request.URL.Path
+			}
+			tags := map[string]string{
+				"method": c.Request.Method,
+				"path":   c.Request.URL.Path,
+			}
+			if tenantID := c.GetString("tenant_id"); tenantID != "" {
+				tags["tenant_id"] = tenantID
+			}
+
+			p := Capture(c.Request.Context(), service, c.Request.Method+" "+route, value, tags)
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "Internal server error",
+				ErrorID: p.ID,
+			})
+		}()
+		c.Next()
+	}
+}
+
+// brokenPipe сообщает, вызвана ли паника записью в закрытое клиентом соединение
+func brokenPipe(value interface{}) bool {
+	err, ok := value.(error)
+	if !ok {
+		return false
+	}
+	if errors.Is(err, http.ErrAbortHandler) {
+		return true
+	}
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+	var syscallErr *os.SyscallError
+	if !errors.As(opErr.Err, &syscallErr) {
+		return false
+	}
+	msg := strings.ToLower(syscallErr.Error())
+	return strings.Contains(msg, "broken pipe") || strings.Contains(msg, "connection reset by peer")
+}
@@ -0,0 +1,102 @@
+// Package recovery перехватывает паники в HTTP обработчиках и фоновых горутинах,
+// записывает их со стеком в лог и метрику и передает подключаемому Reporter (например, Sentry)
+package recovery
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"augustberries/pkg/metrics"
+)
+
+// Panic - перехваченная паника
+type Panic struct {
+	ID      string            // Идентификатор для поиска в логах и Sentry, возвращается клиенту
+	Service string            // Сервис, в котором произошла паника
+	Source  string            // HTTP маршрут ("GET /orders/:id") или имя горутины
+	Message string            // Значение, переданное в panic
+	Stack   string            // Стек вызовов на момент паники
+	Tags    map[string]string // Дополнительный контекст (метод, tenant и т.п.)
+	At      time.Time
+}
+
+// Reporter отправляет паники во внешнюю систему отслеживания ошибок
+// Report вызывается синхронно в обработчике паники и не должен блокироваться надолго
+type Reporter interface {
+	Report(ctx context.Context, p *Panic)
+}
+
+// ReporterFunc позволяет использовать функцию как Reporter
+type ReporterFunc func(ctx context.Context, p *Panic)
+
+func (f ReporterFunc) Report(ctx context.Context, p *Panic) { f(ctx, p) }
+
+var (
+	mu       sync.RWMutex
+	reporter Reporter
+)
+
+// SetReporter задает Reporter процесса; nil - паники только логируются
+// Вызывается при старте сервиса до запуска HTTP сервера и фоновых задач
+func SetReporter(r Reporter) {
+	mu.Lock()
+	reporter = r
+	mu.Unlock()
+}
+
+// Capture записывает панику в лог и метрику и передает ее Reporter
+// value - значение из recover(); стек берется из текущей горутины
+func Capture(ctx context.Context, service, source string, value interface{}, tags map[string]string) *Panic {
+	p := &Panic{
+		ID:      newID(),
+		Service: service,
+		Source:  source,
+		Message: fmt.Sprint(value),
+		Stack:   string(debug.Stack()),
+		Tags:    tags,
+		At:      time.Now().UTC(),
+	}
+
+	log.Printf("PANIC [%s] in %s (%s): %s\n%s", p.ID, p.Source, p.Service, p.Message, p.Stack)
+	metrics.PanicsTotal.WithLabelValues(service, source).Inc()
+
+	mu.RLock()
+	r := reporter
+	mu.RUnlock()
+	if r != nil {
+		r.Report(ctx, p)
+	}
+	return p
+}
+
+// Go запускает fn в горутине; паника перехватывается и сообщается, а не роняет процесс
+func Go(service, name string, fn func()) {
+	go Wrap(service, name, fn)()
+}
+
+// Wrap возвращает fn с перехватом паники (для задач cron и обработчиков в чужих горутинах)
+func Wrap(service, name string, fn func()) func() {
+	return func() {
+		defer func() {
+			if value := recover(); value != nil {
+				Capture(context.Background(), service, name, value, nil)
+			}
+		}()
+		fn()
+	}
+}
+
+// newID возвращает 32 hex символа (формат event_id Sentry)
+func newID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("%032x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b[:])
+}
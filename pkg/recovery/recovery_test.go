@@ -0,0 +1,176 @@
+package recovery
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingReporter запоминает сообщенные паники
+type recordingReporter struct {
+	mu     sync.Mutex
+	panics []*Panic
+}
+
+func (r *recordingReporter) Report(ctx context.Context, p *Panic) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.panics = append(r.panics, p)
+}
+
+func useReporter(t *testing.T) *recordingReporter {
+	r := &recordingReporter{}
+	SetReporter(r)
+	t.Cleanup(func() { SetReporter(nil) })
+	return r
+}
+
+// ====== Middleware Tests ======
+
+func TestMiddleware_PanicReturnsEnvelopeAndReports(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	reporter := useReporter(t)
+
+	router := gin.New()
+	router.Use(Middleware("orders-service"))
+	router.GET("/orders/:id", func(c *gin.Context) {
+		var m map[string]int
+		m["boom"] = 1
+	})
+
+	// Act
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/orders/42", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var body ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "Internal server error", body.Error)
+	assert.Len(t, body.ErrorID, 32)
+
+	require.Len(t, reporter.panics, 1)
+	p := reporter.panics[0]
+	assert.Equal(t, body.ErrorID, p.ID)
+	assert.Equal(t, "orders-service", p.Service)
+	assert.Equal(t, "GET /orders/:id", p.Source)
+	assert.Contains(t, p.Message, "nil map")
+	assert.Contains(t, p.Stack, "recovery_test.go")
+	assert.Equal(t, "/orders/42", p.Tags["path"])
+}
+
+func TestMiddleware_NoPanicPassesThrough(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	reporter := useReporter(t)
+
+	router := gin.New()
+	router.Use(Middleware("catalog-service"))
+	router.GET("/ok", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"status": "ok"}) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ok", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, reporter.panics)
+}
+
+func TestMiddleware_AbortHandlerNotReported(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	reporter := useReporter(t)
+
+	router := gin.New()
+	router.Use(Middleware("catalog-service"))
+	router.GET("/stream", func(c *gin.Context) { panic(http.ErrAbortHandler) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream", nil))
+
+	assert.Empty(t, reporter.panics)
+}
+
+// ====== Goroutine Tests ======
+
+func TestWrap_RecoversAndReports(t *testing.T) {
+	reporter := useReporter(t)
+
+	assert.NotPanics(t, Wrap("background-worker", "update_rates", func() { panic("rates api returned garbage") }))
+
+	require.Len(t, reporter.panics, 1)
+	assert.Equal(t, "update_rates", reporter.panics[0].Source)
+	assert.Equal(t, "rates api returned garbage", reporter.panics[0].Message)
+}
+
+func TestGo_RecoversPanicInGoroutine(t *testing.T) {
+	reported := make(chan *Panic, 1)
+	SetReporter(ReporterFunc(func(ctx context.Context, p *Panic) { reported <- p }))
+	t.Cleanup(func() { SetReporter(nil) })
+
+	Go("catalog-service", "search_reindex", func() { panic("index missing") })
+
+	select {
+	case p := <-reported:
+		assert.Equal(t, "search_reindex", p.Source)
+	case <-time.After(time.Second):
+		t.Fatal("panic was not reported")
+	}
+}
+
+// ====== Sentry Tests ======
+
+func TestSentryReporter_SendsEvent(t *testing.T) {
+	// Arrange
+	received := make(chan *http.Request, 1)
+	bodies := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dsn := strings.Replace(server.URL, "://", "://public-key@", 1) + "/42"
+	reporter, err := NewSentryReporter(SentryConfig{DSN: dsn, Environment: "staging"})
+	require.NoError(t, err)
+
+	// Act
+	reporter.Report(context.Background(), &Panic{
+		ID: "0123456789abcdef0123456789abcdef", Service: "orders-service", Source: "GET /orders/:id",
+		Message: "boom", Stack: "goroutine 1", At: time.Now(),
+	})
+
+	// Assert
+	select {
+	case r := <-received:
+		assert.Equal(t, "/api/42/store/", r.URL.Path)
+		assert.Contains(t, r.Header.Get("X-Sentry-Auth"), "sentry_key=public-key")
+	case <-time.After(time.Second):
+		t.Fatal("event was not sent")
+	}
+
+	var event map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(<-bodies), &event))
+	assert.Equal(t, "0123456789abcdef0123456789abcdef", event["event_id"])
+	assert.Equal(t, "staging", event["environment"])
+	assert.Equal(t, "GET /orders/:id", event["transaction"])
+	assert.Equal(t, "orders-service", event["tags"].(map[string]interface{})["service"])
+}
+
+func TestNewSentryReporter_InvalidDSN(t *testing.T) {
+	for _, dsn := range []string{"https://sentry.io/42", "https://key@sentry.io", "::"} {
+		_, err := NewSentryReporter(SentryConfig{DSN: dsn})
+		assert.Error(t, err, dsn)
+	}
+}
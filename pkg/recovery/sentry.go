@@ -0,0 +1,148 @@
+package recovery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// sentryTimeout - ограничение времени отправки одного события
+const sentryTimeout = 5 * time.Second
+
+// SentryConfig - настройки отправки паник в Sentry
+type SentryConfig struct {
+	DSN         string // https://<key>@<host>/<project_id>
+	Environment string // production, staging и т.п.
+	Release     string // Версия сервиса
+}
+
+// SentryReporter отправляет паники в Sentry через HTTP API store
+// Отправка выполняется в фоне, ошибки отправки только логируются
+type SentryReporter struct {
+	endpoint    string
+	auth        string
+	environment string
+	release     string
+	serverName  string
+	client      *http.Client
+}
+
+// NewSentryReporter разбирает DSN и создает reporter
+func NewSentryReporter(cfg SentryConfig) (*SentryReporter, error) {
+	dsn, err := url.Parse(cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sentry DSN: %w", err)
+	}
+	projectID := strings.Trim(dsn.Path, "/")
+	if dsn.User == nil || dsn.User.Username() == "" || projectID == "" || dsn.Host == "" {
+		return nil, fmt.Errorf("invalid sentry DSN: expected scheme://key@host/project_id")
+	}
+
+	serverName, _ := os.Hostname()
+	return &SentryReporter{
+		endpoint:    fmt.Sprintf("%s://%s/api/%s/store/", dsn.Scheme, dsn.Host, projectID),
+		auth:        fmt.Sprintf("Sentry sentry_version=7, sentry_client=augustberries/1.0, sentry_key=%s", dsn.User.Username()),
+		environment: cfg.Environment,
+		release:     cfg.Release,
+		serverName:  serverName,
+		client:      &http.Client{Timeout: sentryTimeout},
+	}, nil
+}
+
+// sentryEvent - событие Sentry (подмножество полей протокола)
+type sentryEvent struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Level       string            `json:"level"`
+	Platform    string            `json:"platform"`
+	Logger      string            `json:"logger"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	Transaction string            `json:"transaction,omitempty"`
+	Message     string            `json:"message"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Extra       map[string]string `json:"extra,omitempty"`
+}
+
+// Report отправляет событие в фоне, не задерживая ответ клиенту
+func (r *SentryReporter) Report(ctx context.Context, p *Panic) {
+	tags := make(map[string]string, len(p.Tags)+1)
+	for k, v := range p.Tags {
+		tags[k] = v
+	}
+	tags["service"] = p.Service
+
+	event := sentryEvent{
+		EventID:     p.ID,
+		Timestamp:   p.At.Format(time.RFC3339),
+		Level:       "fatal",
+		Platform:    "go",
+		Logger:      "recovery",
+		ServerName:  r.serverName,
+		Environment: r.environment,
+		Release:     r.release,
+		Transaction: p.Source,
+		Message:     "panic: " + p.Message,
+		Tags:        tags,
+		Extra:       map[string]string{"stacktrace": p.Stack},
+	}
+
+	go func() {
+		if err := r.send(context.WithoutCancel(ctx), &event); err != nil {
+			log.Printf("failed to report panic %s to sentry: %v", p.ID, err)
+		}
+	}()
+}
+
+func (r *SentryReporter) send(ctx context.Context, event *sentryEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", r.auth)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("sentry returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// ConfigureFromEnv подключает Sentry, если задан SENTRY_DSN; без него паники только логируются
+// Используется в main всех сервисов
+func ConfigureFromEnv() error {
+	dsn := os.Getenv("SENTRY_DSN")
+	if dsn == "" {
+		return nil
+	}
+
+	r, err := NewSentryReporter(SentryConfig{
+		DSN:         dsn,
+		Environment: os.Getenv("SENTRY_ENVIRONMENT"),
+		Release:     os.Getenv("SENTRY_RELEASE"),
+	})
+	if err != nil {
+		return err
+	}
+	SetReporter(r)
+	return nil
+}
@@ -0,0 +1,180 @@
+// Package redis создает клиентов Redis для standalone, Sentinel и Cluster режимов
+// Все сервисы получают redis.UniversalClient, поэтому репозитории не зависят от режима развертывания
+package redis
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"augustberries/pkg/metrics"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Режимы подключения
+const (
+	ModeStandalone = "standalone"
+	ModeSentinel   = "sentinel"
+	ModeCluster    = "cluster"
+)
+
+// Client - клиент Redis, одинаковый для всех режимов подключения
+type Client = goredis.UniversalClient
+
+// Config - настройки клиента Redis
+// Нулевые значения таймаутов и пула означают настройки go-redis по умолчанию
+type Config struct {
+	Mode     string   // standalone (по умолчанию), sentinel или cluster
+	Addrs    []string // standalone - адрес сервера, sentinel - адреса Sentinel, cluster - адреса узлов
+	Password string   // Пароль Redis
+	DB       int      // Номер БД (в режиме cluster не поддерживается)
+
+	MasterName       string // Имя master для Sentinel
+	SentinelPassword string // Пароль Sentinel, если отличается от пароля Redis
+
+	TLS                   bool // Подключаться по TLS
+	TLSInsecureSkipVerify bool // Не проверять сертификат сервера (только для разработки)
+
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	PoolSize     int
+	MinIdleConns int
+
+	Service string // Имя сервиса для меток метрик
+}
+
+// NewClient создает клиента Redis в заданном режиме
+// Клиент записывает длительность и ошибки команд, а также состояние пула соединений в метрики
+func NewClient(cfg Config) (Client, error) {
+	if len(cfg.Addrs) == 0 {
+		return nil, errors.New("redis: at least one address is required")
+	}
+
+	var tlsConfig *tls.Config
+	if cfg.TLS {
+		tlsConfig = &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: cfg.TLSInsecureSkipVerify,
+		}
+	}
+
+	var client Client
+	switch cfg.Mode {
+	case "", ModeStandalone:
+		client = goredis.NewClient(&goredis.Options{
+			Addr:         cfg.Addrs[0],
+			Password:     cfg.Password,
+			DB:           cfg.DB,
+			TLSConfig:    tlsConfig,
+			DialTimeout:  cfg.DialTimeout,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+			PoolSize:     cfg.PoolSize,
+			MinIdleConns: cfg.MinIdleConns,
+		})
+	case ModeSentinel:
+		if cfg.MasterName == "" {
+			return nil, errors.New("redis: master name is required in sentinel mode")
+		}
+		client = goredis.NewFailoverClient(&goredis.FailoverOptions{
+			MasterName:       cfg.MasterName,
+			SentinelAddrs:    cfg.Addrs,
+			SentinelPassword: cfg.SentinelPassword,
+			Password:         cfg.Password,
+			DB:               cfg.DB,
+			TLSConfig:        tlsConfig,
+			DialTimeout:      cfg.DialTimeout,
+			ReadTimeout:      cfg.ReadTimeout,
+			WriteTimeout:     cfg.WriteTimeout,
+			PoolSize:         cfg.PoolSize,
+			MinIdleConns:     cfg.MinIdleConns,
+		})
+	case ModeCluster:
+		if cfg.DB != 0 {
+			return nil, errors.New("redis: database selection is not supported in cluster mode")
+		}
+		client = goredis.NewClusterClient(&goredis.ClusterOptions{
+			Addrs:        cfg.Addrs,
+			Password:     cfg.Password,
+			TLSConfig:    tlsConfig,
+			DialTimeout:  cfg.DialTimeout,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+			PoolSize:     cfg.PoolSize,
+			MinIdleConns: cfg.MinIdleConns,
+		})
+	default:
+		return nil, fmt.Errorf("redis: unknown mode %q", cfg.Mode)
+	}
+
+	client.AddHook(&metricsHook{service: cfg.Service, client: client})
+	return client, nil
+}
+
+// ParseAddrs разбирает список адресов через запятую
+// Пустая строка означает один адрес fallback (обычно host:port из отдельных переменных)
+func ParseAddrs(addrs, fallback string) []string {
+	var result []string
+	for _, addr := range strings.Split(addrs, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			result = append(result, addr)
+		}
+	}
+	if len(result) == 0 {
+		return []string{fallback}
+	}
+	return result
+}
+
+// metricsHook записывает метрики команд и пула соединений
+type metricsHook struct {
+	service string
+	client  Client
+}
+
+func (h *metricsHook) DialHook(next goredis.DialHook) goredis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := next(ctx, network, addr)
+		if err != nil {
+			metrics.RecordRedisError(h.service, "dial")
+		}
+		return conn, err
+	}
+}
+
+func (h *metricsHook) ProcessHook(next goredis.ProcessHook) goredis.ProcessHook {
+	return func(ctx context.Context, cmd goredis.Cmder) error {
+		timer := metrics.NewRedisTimer(h.service, metrics.RedisOperation(cmd.Name()))
+		err := next(ctx, cmd)
+		timer.ObserveDuration()
+		h.record(metrics.RedisOperation(cmd.Name()), err)
+		return err
+	}
+}
+
+func (h *metricsHook) ProcessPipelineHook(next goredis.ProcessPipelineHook) goredis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []goredis.Cmder) error {
+		timer := metrics.NewRedisTimer(h.service, metrics.RedisOpPipeline)
+		err := next(ctx, cmds)
+		timer.ObserveDuration()
+		h.record(metrics.RedisOpPipeline, err)
+		return err
+	}
+}
+
+// record учитывает ошибку команды и обновляет состояние пула
+// redis.Nil (ключ не найден) ошибкой не считается
+func (h *metricsHook) record(op metrics.RedisOperation, err error) {
+	if err != nil && !errors.Is(err, goredis.Nil) {
+		metrics.RecordRedisError(h.service, op)
+	}
+
+	stats := h.client.PoolStats()
+	metrics.RecordRedisPoolStats(h.service, stats.TotalConns, stats.IdleConns, stats.StaleConns)
+}
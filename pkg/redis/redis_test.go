@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"augustberries/pkg/metrics"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ==================== NewClient Tests ====================
+
+func TestNewClient_Standalone(t *testing.T) {
+	// Arrange
+	server := miniredis.RunT(t)
+
+	// Act
+	client, err := NewClient(Config{Addrs: []string{server.Addr()}, Service: "redis-test"})
+	require.NoError(t, err)
+	defer client.Close()
+
+	// Assert
+	require.NoError(t, client.Set(context.Background(), "key", "value", 0).Err())
+	got, err := client.Get(context.Background(), "key").Result()
+	require.NoError(t, err)
+	assert.Equal(t, "value", got)
+}
+
+func TestNewClient_MissingKeyIsNotAnError(t *testing.T) {
+	// Arrange
+	server := miniredis.RunT(t)
+	client, err := NewClient(Config{Addrs: []string{server.Addr()}, Service: "redis-test-nil"})
+	require.NoError(t, err)
+	defer client.Close()
+
+	// Act
+	client.Get(context.Background(), "missing")
+
+	// Assert
+	assert.Zero(t, testutil.ToFloat64(metrics.RedisErrors.WithLabelValues("redis-test-nil", "get")))
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.RedisPoolConnections.WithLabelValues("redis-test-nil", "total")))
+}
+
+func TestNewClient_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"no addresses", Config{}},
+		{"unknown mode", Config{Mode: "replica", Addrs: []string{"localhost:6379"}}},
+		{"sentinel without master", Config{Mode: ModeSentinel, Addrs: []string{"localhost:26379"}}},
+		{"cluster with db", Config{Mode: ModeCluster, Addrs: []string{"localhost:7000"}, DB: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClient(tt.cfg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewClient_SentinelAndCluster(t *testing.T) {
+	sentinel, err := NewClient(Config{Mode: ModeSentinel, Addrs: []string{"localhost:26379"}, MasterName: "mymaster"})
+	require.NoError(t, err)
+	defer sentinel.Close()
+
+	cluster, err := NewClient(Config{Mode: ModeCluster, Addrs: []string{"localhost:7000", "localhost:7001"}, TLS: true})
+	require.NoError(t, err)
+	defer cluster.Close()
+}
+
+// ==================== ParseAddrs Tests ====================
+
+func TestParseAddrs(t *testing.T) {
+	assert.Equal(t, []string{"a:1", "b:2"}, ParseAddrs(" a:1, ,b:2 ", "localhost:6379"))
+	assert.Equal(t, []string{"localhost:6379"}, ParseAddrs("", "localhost:6379"))
+}
@@ -0,0 +1,83 @@
+package seed
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"augustberries/pkg/tenant"
+)
+
+// Options - общие параметры команд cmd/seed всех сервисов
+// Для согласованных данных команды разных сервисов запускаются с одинаковыми значениями
+type Options struct {
+	Seed   uint64
+	Scale  Scale
+	Tenant string
+	Anchor time.Time
+}
+
+// ParseFlags разбирает флаги командной строки:
+//
+//	-seed    seed генерации (по умолчанию 1)
+//	-scale   объем: small, medium, large
+//	-tenant  магазин, в который пишутся данные
+//	-anchor  дата YYYY-MM-DD, от которой отсчитывается история заказов (по умолчанию сегодня, UTC)
+func ParseFlags() (Options, error) {
+	seedValue := flag.Uint64("seed", 1, "deterministic generation seed")
+	scaleName := flag.String("scale", "small", "data volume: small, medium, large")
+	tenantID := flag.String("tenant", tenant.DefaultID, "tenant to write data to")
+	anchor := flag.String("anchor", time.Now().UTC().Format(time.DateOnly), "date (YYYY-MM-DD) the order history ends at")
+	flag.Parse()
+
+	scale, err := ParseScale(*scaleName)
+	if err != nil {
+		return Options{}, err
+	}
+	if !tenant.Valid(*tenantID) {
+		return Options{}, fmt.Errorf("invalid tenant %q", *tenantID)
+	}
+	at, err := time.Parse(time.DateOnly, *anchor)
+	if err != nil {
+		return Options{}, fmt.Errorf("invalid anchor date: %w", err)
+	}
+
+	return Options{Seed: *seedValue, Scale: scale, Tenant: *tenantID, Anchor: at}, nil
+}
+
+// Generator создает генератор по параметрам
+func (o Options) Generator() *Generator {
+	return NewGenerator(o.Seed, o.Scale, o.Anchor)
+}
+
+// Progress печатает прогресс каждые step записей и итог по завершении
+type Progress struct {
+	kind             string
+	total            int
+	created, skipped int
+}
+
+// NewProgress создает счетчик для total записей вида kind
+func NewProgress(kind string, total int) *Progress {
+	return &Progress{kind: kind, total: total}
+}
+
+// Created учитывает созданную запись
+func (p *Progress) Created() { p.created++; p.report() }
+
+// Skipped учитывает запись, которая уже существовала
+func (p *Progress) Skipped() { p.skipped++; p.report() }
+
+func (p *Progress) report() {
+	if done := p.created + p.skipped; done%progressStep == 0 && done != p.total {
+		fmt.Printf("%s: %d/%d\n", p.kind, done, p.total)
+	}
+}
+
+// Done печатает итог
+func (p *Progress) Done() {
+	fmt.Printf("%s: %d created, %d already existed\n", p.kind, p.created, p.skipped)
+}
+
+// progressStep - шаг вывода прогресса
+const progressStep = 1000
@@ -0,0 +1,294 @@
+// Package seed генерирует синтетические данные магазина для нагрузочного тестирования
+// Генерация детерминирована: один и тот же seed дает одинаковые ID, названия, цены и заказы,
+// поэтому команды cmd/seed разных сервисов независимо создают согласованные между собой данные
+package seed
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"augustberries/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// Scale - объем генерируемых данных
+type Scale struct {
+	Categories int
+	Products   int
+	Users      int
+	Orders     int
+	MaxItems   int // Максимум позиций в заказе
+	Days       int // Глубина истории заказов в днях
+}
+
+// Предустановленные объемы
+var Scales = map[string]Scale{
+	"small":  {Categories: 10, Products: 200, Users: 100, Orders: 1000, MaxItems: 5, Days: 90},
+	"medium": {Categories: 30, Products: 5000, Users: 2000, Orders: 50000, MaxItems: 8, Days: 365},
+	"large":  {Categories: 100, Products: 50000, Users: 20000, Orders: 500000, MaxItems: 10, Days: 730},
+}
+
+// maxHistoryDays - наибольшая глубина истории среди предустановленных объемов
+const maxHistoryDays = 730
+
+// ParseScale возвращает предустановленный объем по имени
+func ParseScale(name string) (Scale, error) {
+	scale, ok := Scales[strings.ToLower(name)]
+	if !ok {
+		return Scale{}, fmt.Errorf("unknown seed scale %q (small, medium, large)", name)
+	}
+	return scale, nil
+}
+
+// namespace - пространство имен UUIDv5 для синтетических данных
+var namespace = uuid.MustParse("6f1c2a4e-5b0d-4c1e-9a57-3e8d2b7f4a10")
+
+// Generator строит синтетические данные по seed
+// Записи с одинаковым индексом совпадают при любом Scale, поэтому увеличение объема только добавляет записи
+type Generator struct {
+	seed   uint64
+	scale  Scale
+	anchor time.Time // Момент, от которого отсчитывается история заказов
+
+	categoryNames map[uuid.UUID]string // Названия категорий для снимков позиций заказов
+}
+
+// NewGenerator создает генератор; anchor - "текущий момент" истории заказов
+func NewGenerator(seed uint64, scale Scale, anchor time.Time) *Generator {
+	g := &Generator{seed: seed, scale: scale, anchor: anchor.UTC()}
+	g.categoryNames = make(map[uuid.UUID]string, scale.Categories)
+	for _, c := range g.Categories() {
+		g.categoryNames[c.ID] = c.Name
+	}
+	return g
+}
+
+// Scale возвращает объем генерации
+func (g *Generator) Scale() Scale {
+	return g.scale
+}
+
+// Category - синтетическая категория
+type Category struct {
+	ID   uuid.UUID
+	Name string
+}
+
+// Product - синтетический товар
+type Product struct {
+	ID          uuid.UUID
+	CategoryID  uuid.UUID
+	Name        string
+	Description string
+	Price       money.Amount // В базовой валюте каталога (USD)
+	Stock       int
+	CreatedAt   time.Time
+}
+
+// User - синтетический покупатель
+type User struct {
+	ID        uuid.UUID
+	Email     string
+	Name      string
+	CreatedAt time.Time
+}
+
+// OrderItem - позиция синтетического заказа
+type OrderItem struct {
+	ID           uuid.UUID
+	ProductID    uuid.UUID
+	Name         string // Снимок товара на момент покупки
+	Description  string
+	CategoryName string
+	Quantity     int
+	UnitPrice    money.Amount
+}
+
+// Order - синтетический заказ из истории
+type Order struct {
+	ID            uuid.UUID
+	Number        string
+	UserID        uuid.UUID
+	Status        string
+	Currency      string
+	DeliveryPrice money.Amount
+	Items         []OrderItem
+	CreatedAt     time.Time
+}
+
+// Currency - валюта синтетических заказов; совпадает с базовой валютой каталога,
+// поэтому цены позиций не требуют конвертации по курсу
+const Currency = "USD"
+
+// Password - пароль всех синтетических пользователей (для сценариев нагрузочного теста с логином)
+const Password = "seed-password"
+
+// ID возвращает детерминированный UUID записи kind с индексом i
+func (g *Generator) ID(kind string, i int) uuid.UUID {
+	return uuid.NewSHA1(namespace, fmt.Appendf(nil, "%d/%s/%d", g.seed, kind, i))
+}
+
+// rng возвращает источник случайных чисел для записи kind с индексом i
+func (g *Generator) rng(kind string, i int) *rand.Rand {
+	id := g.ID(kind, i)
+	var hi, lo uint64
+	for k := 0; k < 8; k++ {
+		hi = hi<<8 | uint64(id[k])
+		lo = lo<<8 | uint64(id[8+k])
+	}
+	return rand.New(rand.NewPCG(hi, lo))
+}
+
+var (
+	categoryNames = []string{"Berries", "Fruits", "Vegetables", "Dairy", "Bakery", "Beverages", "Snacks", "Frozen", "Grocery", "Household"}
+	adjectives    = []string{"Fresh", "Organic", "Wild", "Premium", "Sweet", "Frozen", "Dried", "Local", "Seasonal", "Classic"}
+	nouns         = []string{"Strawberry", "Blueberry", "Raspberry", "Cherry", "Apple", "Pear", "Plum", "Currant", "Gooseberry", "Cranberry", "Lingonberry", "Cloudberry"}
+	packs         = []string{"250 g", "500 g", "1 kg", "2 kg", "Box", "Pack"}
+	firstNames    = []string{"Anna", "Ivan", "Maria", "Pavel", "Olga", "Dmitry", "Elena", "Sergey", "Irina", "Alexey"}
+	lastNames     = []string{"Ivanova", "Petrov", "Smirnova", "Kuznetsov", "Popova", "Sokolov", "Lebedeva", "Kozlov", "Novikova", "Morozov"}
+	currencies    = []string{"RUB", "RUB", "RUB", "USD", "EUR"}
+)
+
+// Category возвращает категорию с индексом i
+// Название уникально в магазине и содержит seed, чтобы не пересекаться с реальными категориями и другими наборами
+func (g *Generator) Category(i int) Category {
+	name := fmt.Sprintf("%s %d (seed %d)", categoryNames[i%len(categoryNames)], i/len(categoryNames)+1, g.seed)
+	return Category{ID: g.ID("category", i), Name: name}
+}
+
+// Categories возвращает все категории объема
+func (g *Generator) Categories() []Category {
+	categories := make([]Category, g.scale.Categories)
+	for i := range categories {
+		categories[i] = g.Category(i)
+	}
+	return categories
+}
+
+// Product возвращает товар с индексом i
+// Цена распределена логнормально: много недорогих товаров и немного дорогих
+func (g *Generator) Product(i int) Product {
+	r := g.rng("product", i)
+	name := fmt.Sprintf("%s %s %s #%d",
+		adjectives[r.IntN(len(adjectives))], nouns[r.IntN(len(nouns))], packs[r.IntN(len(packs))], i+1)
+
+	// Цена ограничена 0.5..999, поэтому FromFloat не возвращает ошибку
+	price, _ := money.FromFloat(min(max(3*expNorm(r), 0.5), 999))
+	return Product{
+		ID:          g.ID("product", i),
+		CategoryID:  g.ID("category", r.IntN(max(g.scale.Categories, 1))),
+		Name:        name,
+		Description: fmt.Sprintf("%s. Synthetic product for load testing.", name),
+		Price:       price,
+		Stock:       r.IntN(500),
+		CreatedAt:   g.registeredAt(r),
+	}
+}
+
+// Products возвращает все товары объема
+func (g *Generator) Products() []Product {
+	products := make([]Product, g.scale.Products)
+	for i := range products {
+		products[i] = g.Product(i)
+	}
+	return products
+}
+
+// User возвращает пользователя с индексом i
+func (g *Generator) User(i int) User {
+	r := g.rng("user", i)
+	first := firstNames[r.IntN(len(firstNames))]
+	last := lastNames[r.IntN(len(lastNames))]
+	return User{
+		ID:        g.ID("user", i),
+		Email:     fmt.Sprintf("seed-%d-user-%d@example.com", g.seed, i),
+		Name:      first + " " + last,
+		CreatedAt: g.registeredAt(r),
+	}
+}
+
+// Users возвращает всех пользователей объема
+func (g *Generator) Users() []User {
+	users := make([]User, g.scale.Users)
+	for i := range users {
+		users[i] = g.User(i)
+	}
+	return users
+}
+
+// Order возвращает заказ с индексом i
+// Цены позиций совпадают с ценами товаров из Product, поэтому итоги заказов сходятся с каталогом
+// Популярность товаров неравномерна: небольшая часть каталога попадает в большинство заказов
+func (g *Generator) Order(i int) Order {
+	r := g.rng("order", i)
+	orderID := g.ID("order", i)
+
+	itemsCount := 1 + r.IntN(max(g.scale.MaxItems, 1))
+	items := make([]OrderItem, 0, itemsCount)
+	seen := make(map[int]bool, itemsCount)
+	for k := 0; k < itemsCount && g.scale.Products > 0; k++ {
+		productIdx := int(float64(g.scale.Products) * r.Float64() * r.Float64())
+		if seen[productIdx] {
+			continue
+		}
+		seen[productIdx] = true
+
+		product := g.Product(productIdx)
+		items = append(items, OrderItem{
+			ID:           g.ID(fmt.Sprintf("order/%d/item", i), k),
+			ProductID:    product.ID,
+			Name:         product.Name,
+			Description:  product.Description,
+			CategoryName: g.categoryName(product.CategoryID),
+			Quantity:     1 + r.IntN(3),
+			UnitPrice:    product.Price,
+		})
+	}
+
+	// Старые заказы в основном доставлены, свежие еще в работе
+	createdAt := g.anchor.Add(-time.Duration(r.Float64() * float64(max(g.scale.Days, 1)) * float64(24*time.Hour))).Truncate(time.Second)
+	status := "delivered"
+	switch age := g.anchor.Sub(createdAt); {
+	case r.IntN(20) == 0:
+		status = "cancelled"
+	case age < 24*time.Hour:
+		status = "pending"
+	case age < 3*24*time.Hour:
+		status = "confirmed"
+	case age < 7*24*time.Hour:
+		status = "shipped"
+	}
+
+	return Order{
+		ID:            orderID,
+		Number:        fmt.Sprintf("SEED%d-%07d", g.seed, i+1),
+		UserID:        g.ID("user", r.IntN(max(g.scale.Users, 1))),
+		Status:        status,
+		Currency:      Currency,
+		DeliveryPrice: money.FromMinor(int64(r.IntN(5)) * 250),
+		Items:         items,
+		CreatedAt:     createdAt,
+	}
+}
+
+// categoryName возвращает название категории объема по ее ID
+func (g *Generator) categoryName(id uuid.UUID) string {
+	return g.categoryNames[id]
+}
+
+// registeredAt возвращает дату создания товара или пользователя
+// Она раньше любого заказа при любом объеме и не зависит от Scale
+func (g *Generator) registeredAt(r *rand.Rand) time.Time {
+	days := maxHistoryDays + r.IntN(365)
+	return g.anchor.AddDate(0, 0, -days)
+}
+
+// expNorm возвращает логнормально распределенное число с медианой 1
+// Хвосты ограничены, чтобы не получать цены, далекие от реальных
+func expNorm(r *rand.Rand) float64 {
+	return math.Exp(min(max(r.NormFloat64(), -2), 4))
+}
@@ -0,0 +1,78 @@
+package seed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testAnchor = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+// ====== Generator Tests ======
+
+func TestGenerator_Deterministic(t *testing.T) {
+	scale := Scales["small"]
+	a := NewGenerator(42, scale, testAnchor)
+	b := NewGenerator(42, scale, testAnchor)
+
+	assert.Equal(t, a.Categories(), b.Categories())
+	assert.Equal(t, a.Product(17), b.Product(17))
+	assert.Equal(t, a.User(3), b.User(3))
+	assert.Equal(t, a.Order(99), b.Order(99))
+}
+
+func TestGenerator_DifferentSeeds(t *testing.T) {
+	a := NewGenerator(1, Scales["small"], testAnchor)
+	b := NewGenerator(2, Scales["small"], testAnchor)
+
+	assert.NotEqual(t, a.Product(0).ID, b.Product(0).ID)
+	assert.NotEqual(t, a.User(0).Email, b.User(0).Email)
+}
+
+func TestGenerator_OrdersReferenceGeneratedData(t *testing.T) {
+	// Arrange
+	scale := Scale{Categories: 3, Products: 20, Users: 5, Orders: 50, MaxItems: 4, Days: 30}
+	g := NewGenerator(7, scale, testAnchor)
+
+	products := make(map[string]Product)
+	for _, p := range g.Products() {
+		products[p.ID.String()] = p
+	}
+	users := make(map[string]bool)
+	for _, u := range g.Users() {
+		users[u.ID.String()] = true
+	}
+
+	// Act & Assert
+	for i := 0; i < scale.Orders; i++ {
+		order := g.Order(i)
+		assert.True(t, users[order.UserID.String()], "order %d references unknown user", i)
+		assert.False(t, order.CreatedAt.After(testAnchor))
+		assert.False(t, order.CreatedAt.Before(testAnchor.AddDate(0, 0, -scale.Days)))
+		require.NotEmpty(t, order.Items)
+		for _, item := range order.Items {
+			product, ok := products[item.ProductID.String()]
+			require.True(t, ok, "order %d references unknown product", i)
+			assert.Equal(t, product.Price, item.UnitPrice)
+		}
+	}
+}
+
+func TestGenerator_GrowingScaleKeepsExistingRecords(t *testing.T) {
+	small := NewGenerator(5, Scales["small"], testAnchor)
+	medium := NewGenerator(5, Scales["medium"], testAnchor)
+
+	assert.Equal(t, small.User(10), medium.User(10))
+	assert.Equal(t, small.Category(2), medium.Category(2))
+}
+
+func TestParseScale(t *testing.T) {
+	scale, err := ParseScale("Medium")
+	require.NoError(t, err)
+	assert.Equal(t, Scales["medium"], scale)
+
+	_, err = ParseScale("huge")
+	assert.Error(t, err)
+}
@@ -0,0 +1,37 @@
+package tenant
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Middleware определяет магазин запроса и кладет его в контекст запроса
+// Должен стоять после auth middleware, который сохраняет claim под ContextKey
+// Для публичных маршрутов без JWT магазин берется из заголовка X-Tenant-ID
+// Если в заголовке другой магазин, в котором у пользователя есть роль (tenant_roles), запрос выполняется
+// в этом магазине с этой ролью: middleware должен стоять перед RequireRole и RequirePermission
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roles := rolesFromContext(c)
+		id, err := ResolveWithRoles(c.GetString(ContextKey), c.GetHeader(Header), roles)
+		if err != nil {
+			if errors.Is(err, ErrTenantMismatch) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Tenant does not match token"})
+			} else {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tenant ID"})
+			}
+			c.Abort()
+			return
+		}
+
+		if role, ok := roles[id]; ok {
+			applyRole(c, role)
+		}
+
+		c.Set(ContextKey, id)
+		c.Request = c.Request.WithContext(WithID(c.Request.Context(), id))
+		c.Next()
+	}
+}
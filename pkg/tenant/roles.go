@@ -0,0 +1,56 @@
+package tenant
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Ключи gin.Context, под которыми auth middleware сервисов сохраняют роль пользователя из JWT
+const (
+	RoleIDKey      = "role_id"
+	RoleNameKey    = "role_name"
+	PermissionsKey = "permissions"
+	// RolesKey - роли пользователя в других магазинах (claim tenant_roles)
+	RolesKey = "tenant_roles"
+)
+
+// Role - роль пользователя в магазине с ее разрешениями
+type Role struct {
+	ID          int      `json:"role_id"`
+	Name        string   `json:"role_name"`
+	Permissions []string `json:"permissions"`
+}
+
+// Roles - роли пользователя по магазинам, кроме магазина регистрации
+// Роль в магазине регистрации передается в JWT как раньше: role_id, role_name, permissions
+type Roles map[string]Role
+
+// ResolveWithRoles работает как Resolve, но разрешает заголовку указывать на другой магазин,
+// если у пользователя есть в нем роль
+func ResolveWithRoles(claim, header string, roles Roles) (string, error) {
+	id, err := Resolve(claim, header)
+	if errors.Is(err, ErrTenantMismatch) {
+		header = strings.TrimSpace(header)
+		if _, ok := roles[header]; ok && Valid(header) {
+			return header, nil
+		}
+	}
+	return id, err
+}
+
+// rolesFromContext возвращает роли, сохраненные auth middleware
+func rolesFromContext(c *gin.Context) Roles {
+	value, _ := c.Get(RolesKey)
+	roles, _ := value.(Roles)
+	return roles
+}
+
+// applyRole подменяет роль запроса ролью пользователя в активном магазине
+// После этого RequireRole, RequirePermission и обработчики проверяют права в этом магазине
+func applyRole(c *gin.Context, role Role) {
+	c.Set(RoleIDKey, role.ID)
+	c.Set(RoleNameKey, role.Name)
+	c.Set(PermissionsKey, role.Permissions)
+}
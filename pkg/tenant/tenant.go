@@ -0,0 +1,80 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+)
+
+const (
+	// DefaultID - магазин по умолчанию для данных и токенов, выпущенных до введения тенантов
+	DefaultID = "default"
+	// Header - HTTP заголовок с идентификатором магазина
+	Header = "X-Tenant-ID"
+	// ContextKey - ключ gin.Context, под которым auth middleware сохраняет tenant_id из JWT
+	ContextKey = "tenant_id"
+)
+
+var (
+	// ErrInvalidTenant - идентификатор магазина имеет недопустимый формат
+	ErrInvalidTenant = errors.New("invalid tenant id")
+	// ErrTenantMismatch - заголовок указывает на другой магазин, чем JWT токен
+	ErrTenantMismatch = errors.New("tenant id does not match token")
+)
+
+// Идентификатор магазина: латиница в нижнем регистре, цифры, дефис и подчеркивание
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+type ctxKey struct{}
+
+// Resolve определяет магазин запроса по claim из JWT и заголовку X-Tenant-ID
+// Claim имеет приоритет: заголовок может только совпадать с ним
+// Если не задано ни то, ни другое - возвращается DefaultID
+func Resolve(claim, header string) (string, error) {
+	claim = strings.TrimSpace(claim)
+	header = strings.TrimSpace(header)
+
+	if claim != "" {
+		if !Valid(claim) {
+			return "", ErrInvalidTenant
+		}
+		if header != "" && header != claim {
+			return "", ErrTenantMismatch
+		}
+		return claim, nil
+	}
+
+	if header != "" {
+		if !Valid(header) {
+			return "", ErrInvalidTenant
+		}
+		return header, nil
+	}
+
+	return DefaultID, nil
+}
+
+// Valid проверяет формат идентификатора магазина
+func Valid(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+// WithID возвращает контекст с идентификатором магазина
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext возвращает идентификатор магазина из контекста
+// Фоновые задачи и старые вызовы без тенанта работают с DefaultID
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(ctxKey{}).(string); ok && id != "" {
+		return id
+	}
+	return DefaultID
+}
+
+// CacheKey добавляет к ключу кеша префикс магазина, чтобы кеши магазинов не пересекались
+func CacheKey(ctx context.Context, key string) string {
+	return "tenant:" + FromContext(ctx) + ":" + key
+}
@@ -0,0 +1,177 @@
+package tenant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ====== Resolve Tests ======
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		claim   string
+		header  string
+		want    string
+		wantErr error
+	}{
+		{name: "default", want: DefaultID},
+		{name: "claim only", claim: "shop-a", want: "shop-a"},
+		{name: "header only", header: "shop-b", want: "shop-b"},
+		{name: "claim and same header", claim: "shop-a", header: "shop-a", want: "shop-a"},
+		{name: "header overrides claim", claim: "shop-a", header: "shop-b", wantErr: ErrTenantMismatch},
+		{name: "invalid header", header: "Shop B!", wantErr: ErrInvalidTenant},
+		{name: "invalid claim", claim: "../etc", wantErr: ErrInvalidTenant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(tt.claim, tt.header)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// ====== Context Tests ======
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, DefaultID, FromContext(context.Background()))
+	assert.Equal(t, "shop-a", FromContext(WithID(context.Background(), "shop-a")))
+}
+
+func TestCacheKey(t *testing.T) {
+	ctxA := WithID(context.Background(), "shop-a")
+	ctxB := WithID(context.Background(), "shop-b")
+
+	assert.Equal(t, "tenant:shop-a:categories:all", CacheKey(ctxA, "categories:all"))
+	assert.NotEqual(t, CacheKey(ctxA, "brands:all"), CacheKey(ctxB, "brands:all"))
+}
+
+// ====== Middleware Tests ======
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(claim string) *gin.Engine {
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			if claim != "" {
+				c.Set(ContextKey, claim)
+			}
+			c.Next()
+		})
+		router.Use(Middleware())
+		router.GET("/", func(c *gin.Context) {
+			c.String(http.StatusOK, FromContext(c.Request.Context()))
+		})
+		return router
+	}
+
+	t.Run("claim", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newRouter("shop-a").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "shop-a", w.Body.String())
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(Header, "shop-b")
+		w := httptest.NewRecorder()
+		newRouter("shop-a").ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("invalid header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(Header, "Shop B")
+		w := httptest.NewRecorder()
+		newRouter("").ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+// ====== Tenant Roles Tests ======
+
+func TestResolveWithRoles(t *testing.T) {
+	roles := Roles{"shop-b": {ID: 1, Name: "user"}}
+
+	tests := []struct {
+		name    string
+		header  string
+		want    string
+		wantErr error
+	}{
+		{name: "home tenant", want: "shop-a"},
+		{name: "tenant with role", header: "shop-b", want: "shop-b"},
+		{name: "tenant without role", header: "shop-c", wantErr: ErrTenantMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveWithRoles("shop-a", tt.header, roles)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMiddleware_AppliesTenantRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Администратор магазина shop-a и покупатель магазина shop-b
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set(ContextKey, "shop-a")
+		c.Set(RoleNameKey, "admin")
+		c.Set(PermissionsKey, []string{"product.create"})
+		c.Set(RolesKey, Roles{"shop-b": {ID: 1, Name: "user", Permissions: []string{}}})
+		c.Next()
+	})
+	router.Use(Middleware())
+	router.GET("/", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"tenant":      FromContext(c.Request.Context()),
+			"role":        c.GetString(RoleNameKey),
+			"permissions": c.GetStringSlice(PermissionsKey),
+		})
+	})
+
+	request := func(header string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if header != "" {
+			req.Header.Set(Header, header)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	home := request("")
+	assert.Equal(t, http.StatusOK, home.Code)
+	assert.JSONEq(t, `{"tenant":"shop-a","role":"admin","permissions":["product.create"]}`, home.Body.String())
+
+	other := request("shop-b")
+	assert.Equal(t, http.StatusOK, other.Code)
+	assert.JSONEq(t, `{"tenant":"shop-b","role":"user","permissions":[]}`, other.Body.String())
+
+	assert.Equal(t, http.StatusForbidden, request("shop-c").Code)
+}
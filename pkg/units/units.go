@@ -0,0 +1,213 @@
+// Package units - единицы измерения товаров и количество в этих единицах
+// Штучный товар продается целыми единицами, весовой и разливной - с шагом (например, по 0.5 кг)
+package units
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Unit - единица измерения товара
+type Unit string
+
+const (
+	UnitPiece    Unit = "piece" // Штуки, по умолчанию
+	UnitKilogram Unit = "kg"
+	UnitLiter    Unit = "liter"
+)
+
+// Valid сообщает, известна ли единица
+func (u Unit) Valid() bool {
+	switch u {
+	case UnitPiece, UnitKilogram, UnitLiter:
+		return true
+	}
+	return false
+}
+
+// OrDefault возвращает единицу или штуки для пустого значения (товары, созданные до единиц измерения)
+func (u Unit) OrDefault() Unit {
+	if u == "" {
+		return UnitPiece
+	}
+	return u
+}
+
+// Label - обозначение единицы в цене за единицу ("за кг")
+func (u Unit) Label() string {
+	switch u.OrDefault() {
+	case UnitKilogram:
+		return "kg"
+	case UnitLiter:
+		return "l"
+	default:
+		return "pc"
+	}
+}
+
+// Scale - количество тысячных в одной единице: количество хранится с точностью до грамма и миллилитра
+const Scale = 1000
+
+// Quantity - количество в единицах товара в тысячных долях
+// Хранится как int64, чтобы исключить ошибки округления float64. В JSON и в БД (decimal(12,3))
+// представляется десятичным числом без лишних нулей: целое количество штук остается целым числом (2, не 2.000)
+type Quantity int64
+
+// One - одна единица товара (шаг количества по умолчанию)
+const One Quantity = Scale
+
+// Of создает целое количество единиц
+func Of(n int) Quantity {
+	return Quantity(n) * Scale
+}
+
+// FromFloat создает количество из float64 с округлением до тысячной
+func FromFloat(value float64) Quantity {
+	return Quantity(math.Round(value * Scale))
+}
+
+// Parse разбирает десятичную строку ("1.5", "2", "0.250"); больше трех знаков после точки - ошибка
+func Parse(s string) (Quantity, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return 0, fmt.Errorf("units: invalid quantity %q", s)
+	}
+	if len(strings.TrimRight(fracPart, "0")) > 3 {
+		return 0, fmt.Errorf("units: quantity %q is more precise than 0.001", s)
+	}
+
+	var whole int64
+	if intPart != "" {
+		var err error
+		if whole, err = strconv.ParseInt(intPart, 10, 64); err != nil || whole > math.MaxInt64/Scale-1 {
+			return 0, fmt.Errorf("units: invalid quantity %q", s)
+		}
+	}
+	fracPart = (strings.TrimRight(fracPart, "0") + "000")[:3]
+	frac, _ := strconv.ParseInt(fracPart, 10, 64)
+
+	q := Quantity(whole*Scale + frac)
+	if negative {
+		q = -q
+	}
+	return q, nil
+}
+
+// MustParse как Parse, но паникует при ошибке (для констант и тестов)
+func MustParse(s string) Quantity {
+	q, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// Milli возвращает количество в тысячных долях единицы
+func (q Quantity) Milli() int64 {
+	return int64(q)
+}
+
+// Float64 возвращает количество как float64 (для метрик и логов)
+func (q Quantity) Float64() float64 {
+	return float64(q) / Scale
+}
+
+// IsWhole сообщает, что количество - целое число единиц
+func (q Quantity) IsWhole() bool {
+	return q%Scale == 0
+}
+
+// MultipleOf сообщает, что количество кратно шагу; нулевой шаг (не заданный) - одна единица
+func (q Quantity) MultipleOf(step Quantity) bool {
+	if step <= 0 {
+		step = One
+	}
+	return q%step == 0
+}
+
+// String форматирует количество без лишних нулей ("1.5", "2", "0.25")
+func (q Quantity) String() string {
+	milli := int64(q)
+	sign := ""
+	if milli < 0 {
+		sign = "-"
+		milli = -milli
+	}
+	if milli%Scale == 0 {
+		return fmt.Sprintf("%s%d", sign, milli/Scale)
+	}
+	frac := strings.TrimRight(fmt.Sprintf("%03d", milli%Scale), "0")
+	return fmt.Sprintf("%s%d.%s", sign, milli/Scale, frac)
+}
+
+// MarshalJSON сериализует количество как JSON число
+func (q Quantity) MarshalJSON() ([]byte, error) {
+	return []byte(q.String()), nil
+}
+
+// UnmarshalJSON принимает JSON число или строку с десятичным количеством
+func (q *Quantity) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		data = data[1 : len(data)-1]
+	}
+
+	parsed, err := Parse(string(data))
+	if err != nil {
+		return err
+	}
+	*q = parsed
+	return nil
+}
+
+// Value реализует driver.Valuer: сохраняет количество в decimal колонку без потери точности
+func (q Quantity) Value() (driver.Value, error) {
+	return q.String(), nil
+}
+
+// Scan реализует sql.Scanner: читает decimal/numeric и целочисленные колонки
+func (q *Quantity) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*q = 0
+		return nil
+	case []byte:
+		return q.Scan(string(v))
+	case string:
+		parsed, err := Parse(v)
+		if err != nil {
+			return err
+		}
+		*q = parsed
+		return nil
+	case float64:
+		*q = FromFloat(v)
+		return nil
+	case int64:
+		*q = Quantity(v * Scale)
+		return nil
+	default:
+		return fmt.Errorf("units: cannot scan %T into Quantity", src)
+	}
+}
+
+// isDigits проверяет, что строка состоит только из цифр (пустая строка тоже подходит)
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
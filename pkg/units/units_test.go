@@ -0,0 +1,100 @@
+package units
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ====== Parse Tests ======
+
+func TestParse(t *testing.T) {
+	cases := map[string]Quantity{
+		"2":      2000,
+		"1.5":    1500,
+		"0.250":  250,
+		".5":     500,
+		"0.001":  1,
+		"-1.25":  -1250,
+		" 3.10 ": 3100,
+	}
+
+	for input, expected := range cases {
+		got, err := Parse(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, got, input)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, input := range []string{"", ".", "abc", "1.2.3", "1e3", "0.0005"} {
+		_, err := Parse(input)
+		assert.Error(t, err, input)
+	}
+}
+
+// ====== Step Tests ======
+
+func TestQuantity_MultipleOf(t *testing.T) {
+	half := MustParse("0.5")
+
+	assert.True(t, MustParse("1.5").MultipleOf(half))
+	assert.False(t, MustParse("1.3").MultipleOf(half))
+	// Шаг не задан - количество кратно одной единице
+	assert.True(t, Of(3).MultipleOf(0))
+	assert.False(t, MustParse("0.5").MultipleOf(0))
+}
+
+func TestQuantity_String(t *testing.T) {
+	assert.Equal(t, "2", Of(2).String())
+	assert.Equal(t, "1.5", MustParse("1.500").String())
+	assert.Equal(t, "0.025", Quantity(25).String())
+	assert.Equal(t, "-0.5", Quantity(-500).String())
+}
+
+// ====== Serialization Tests ======
+
+func TestQuantity_JSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Quantity Quantity `json:"quantity"`
+	}{Quantity: Of(2)})
+	require.NoError(t, err)
+	// Целое количество штук остается целым числом, как до введения единиц
+	assert.JSONEq(t, `{"quantity":2}`, string(data))
+
+	var decoded struct {
+		Quantity Quantity `json:"quantity"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"quantity":1.5}`), &decoded))
+	assert.Equal(t, MustParse("1.5"), decoded.Quantity)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"quantity":"0.25"}`), &decoded))
+	assert.Equal(t, Quantity(250), decoded.Quantity)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"quantity":1.0001}`), &decoded))
+}
+
+func TestQuantity_Scan(t *testing.T) {
+	var q Quantity
+
+	require.NoError(t, q.Scan([]byte("1.500")))
+	assert.Equal(t, MustParse("1.5"), q)
+
+	require.NoError(t, q.Scan(int64(4)))
+	assert.Equal(t, Of(4), q)
+
+	value, err := MustParse("0.75").Value()
+	require.NoError(t, err)
+	assert.Equal(t, "0.75", value)
+}
+
+// ====== Unit Tests ======
+
+func TestUnit_Label(t *testing.T) {
+	assert.Equal(t, "kg", UnitKilogram.Label())
+	assert.Equal(t, "l", UnitLiter.Label())
+	assert.Equal(t, "pc", Unit("").Label())
+	assert.False(t, Unit("box").Valid())
+}
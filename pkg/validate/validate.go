@@ -0,0 +1,198 @@
+// Package validate - общая проверка DTO запросов для всех сервисов.
+// Оборачивает go-playground/validator: регистрирует типы patch, правила currency, uuid_list, money
+// и возвращает ошибки в едином формате "Field is required, Price must be greater than 0"
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"augustberries/pkg/money"
+	"augustberries/pkg/patch"
+	"augustberries/pkg/units"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// MaxAmount - наибольшая сумма, которая помещается в колонку decimal(10,2)
+const MaxAmount = money.Amount(99_999_999_99)
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Validator проверяет структуры по тегам validate
+type Validator struct {
+	validate *validator.Validate
+}
+
+// New создает Validator с типами patch и правилами сервисов:
+//   - currency - код валюты ISO 4217 (три заглавные латинские буквы)
+//   - uuid_list - каждый элемент списка ([]uuid.UUID или []string) - UUID, отличный от нулевого
+//   - money - сумма не меньше 0 и не больше MaxAmount
+func New() *Validator {
+	v := validator.New()
+	patch.Register[string](v)
+	patch.Register[int](v)
+	patch.Register[money.Amount](v)
+	patch.Register[uuid.UUID](v)
+	patch.Register[units.Unit](v)
+	patch.Register[units.Quantity](v)
+
+	// Ошибка регистрации возможна только при пустом имени тега
+	_ = v.RegisterValidation("currency", isCurrency)
+	_ = v.RegisterValidation("uuid_list", isUUIDList)
+	_ = v.RegisterValidation("money", isMoney)
+
+	return &Validator{validate: v}
+}
+
+// Struct проверяет структуру; нарушения правил возвращаются как Errors
+func (v *Validator) Struct(s any) error {
+	return convert(v.validate.Struct(s))
+}
+
+// Var проверяет одно значение по тегу; нарушения правил возвращаются как Errors
+func (v *Validator) Var(field any, tag string) error {
+	return convert(v.validate.Var(field, tag))
+}
+
+// FieldError - нарушение правила одним полем
+type FieldError struct {
+	Field   string `json:"field"`   // Имя поля структуры
+	Rule    string `json:"rule"`    // Тег правила: required, min, money...
+	Message string `json:"message"` // Сообщение для клиента
+}
+
+// Errors - нарушения правил запроса в порядке полей структуры
+type Errors []FieldError
+
+// Error объединяет сообщения всех полей через запятую
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldError := range e {
+		messages[i] = fieldError.Message
+	}
+	return strings.Join(messages, ", ")
+}
+
+// Message возвращает текст ошибки проверки для ответа клиенту
+// Ошибки, не связанные с правилами (например, передан не struct), не раскрываются
+func Message(err error) string {
+	var errs Errors
+	if errors.As(err, &errs) {
+		return errs.Error()
+	}
+	return "Validation failed"
+}
+
+func convert(err error) error {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	errs := make(Errors, len(validationErrors))
+	for i, fieldError := range validationErrors {
+		errs[i] = FieldError{
+			Field:   fieldError.Field(),
+			Rule:    fieldError.Tag(),
+			Message: message(fieldError),
+		}
+	}
+	return errs
+}
+
+// message формирует сообщение для одного нарушения
+func message(fe validator.FieldError) string {
+	field, param := fe.Field(), fe.Param()
+
+	switch fe.Tag() {
+	case "required", "required_without", "required_with", "required_if":
+		return field + " is required"
+	case "email":
+		return field + " must be a valid email"
+	case "uuid", "uuid4":
+		return field + " must be a valid UUID"
+	case "url", "http_url":
+		return field + " must be a valid URL"
+	case "min":
+		return field + " must be at least " + param + unit(fe.Kind())
+	case "max":
+		return field + " must be at most " + param + unit(fe.Kind())
+	case "len":
+		return field + " must be exactly " + param + unit(fe.Kind())
+	case "gt":
+		return field + " must be greater than " + param
+	case "gte":
+		return field + " must be at least " + param
+	case "lt":
+		return field + " must be less than " + param
+	case "lte":
+		return field + " must be at most " + param
+	case "oneof":
+		return field + " must be one of: " + param
+	case "unique":
+		return field + " must not contain duplicates"
+	case "currency":
+		return field + " must be a 3-letter ISO 4217 currency code"
+	case "uuid_list":
+		return field + " must contain only valid non-nil UUIDs"
+	case "money":
+		return fmt.Sprintf("%s must be between 0 and %s", field, MaxAmount)
+	default:
+		return field + " is invalid"
+	}
+}
+
+// unit возвращает единицу измерения для min/max/len: длина строки или размер списка
+func unit(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return " items"
+	default:
+		return ""
+	}
+}
+
+func isCurrency(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	return field.Kind() == reflect.String && currencyPattern.MatchString(field.String())
+}
+
+func isUUIDList(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() != reflect.Slice && field.Kind() != reflect.Array {
+		return false
+	}
+
+	for i := 0; i < field.Len(); i++ {
+		switch value := field.Index(i).Interface().(type) {
+		case uuid.UUID:
+			if value == uuid.Nil {
+				return false
+			}
+		case string:
+			id, err := uuid.Parse(value)
+			if err != nil || id == uuid.Nil {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isMoney(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() != reflect.Int64 {
+		return false
+	}
+	amount := money.Amount(field.Int())
+	return amount >= 0 && amount <= MaxAmount
+}
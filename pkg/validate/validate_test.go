@@ -0,0 +1,107 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+
+	"augustberries/pkg/money"
+	"augustberries/pkg/patch"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRequest struct {
+	Email    string                    `validate:"required,email"`
+	Password string                    `validate:"required,min=8"`
+	Rating   int                       `validate:"required,min=1,max=5"`
+	Price    money.Amount              `validate:"required,money"`
+	Discount patch.Field[money.Amount] `validate:"omitempty,money"`
+	Currency string                    `validate:"omitempty,currency"`
+	IDs      []uuid.UUID               `validate:"omitempty,max=2,uuid_list"`
+}
+
+func validRequest() testRequest {
+	return testRequest{
+		Email:    "user@example.com",
+		Password: "password123",
+		Rating:   5,
+		Price:    money.MustParse("10.00"),
+		Currency: "USD",
+		IDs:      []uuid.UUID{uuid.New()},
+	}
+}
+
+func TestStruct_Valid(t *testing.T) {
+	assert.NoError(t, New().Struct(validRequest()))
+}
+
+func TestStruct_Messages(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(r *testRequest)
+		expected string
+	}{
+		{"required", func(r *testRequest) { r.Email = "" }, "Email is required"},
+		{"email", func(r *testRequest) { r.Email = "not-an-email" }, "Email must be a valid email"},
+		{"min string", func(r *testRequest) { r.Password = "short" }, "Password must be at least 8 characters"},
+		{"max number", func(r *testRequest) { r.Rating = 6 }, "Rating must be at most 5"},
+		{"negative money", func(r *testRequest) { r.Price = money.MustParse("-1.00") }, "Price must be between 0 and 99999999.99"},
+		{"money overflow", func(r *testRequest) { r.Price = MaxAmount + 1 }, "Price must be between 0 and 99999999.99"},
+		{"patch money", func(r *testRequest) { r.Discount = patch.Of(money.MustParse("-0.01")) }, "Discount must be between 0 and 99999999.99"},
+		{"lowercase currency", func(r *testRequest) { r.Currency = "usd" }, "Currency must be a 3-letter ISO 4217 currency code"},
+		{"long currency", func(r *testRequest) { r.Currency = "USDT" }, "Currency must be a 3-letter ISO 4217 currency code"},
+		{"nil uuid", func(r *testRequest) { r.IDs = []uuid.UUID{uuid.New(), uuid.Nil} }, "IDs must contain only valid non-nil UUIDs"},
+		{"max items", func(r *testRequest) { r.IDs = []uuid.UUID{uuid.New(), uuid.New(), uuid.New()} }, "IDs must be at most 2 items"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			req := validRequest()
+			tt.modify(&req)
+
+			// Act
+			err := New().Struct(req)
+
+			// Assert
+			require.Error(t, err)
+			assert.Equal(t, tt.expected, err.Error())
+			assert.Equal(t, tt.expected, Message(err))
+		})
+	}
+}
+
+func TestStruct_AllFieldsReported(t *testing.T) {
+	// Arrange
+	req := validRequest()
+	req.Email = ""
+	req.Rating = 0
+
+	// Act
+	err := New().Struct(req)
+
+	// Assert
+	var errs Errors
+	require.True(t, errors.As(err, &errs))
+	assert.Equal(t, Errors{
+		{Field: "Email", Rule: "required", Message: "Email is required"},
+		{Field: "Rating", Rule: "required", Message: "Rating is required"},
+	}, errs)
+	assert.Equal(t, "Email is required, Rating is required", err.Error())
+}
+
+func TestVar_UUIDListStrings(t *testing.T) {
+	v := New()
+
+	assert.NoError(t, v.Var([]string{uuid.NewString(), uuid.NewString()}, "uuid_list"))
+	assert.Error(t, v.Var([]string{uuid.NewString(), "broken"}, "uuid_list"))
+	assert.Error(t, v.Var([]string{uuid.Nil.String()}, "uuid_list"))
+	assert.Error(t, v.Var([]int{1}, "uuid_list"))
+}
+
+func TestMessage_NotValidationError(t *testing.T) {
+	assert.Equal(t, "Validation failed", Message(errors.New("boom")))
+	assert.Equal(t, "Validation failed", Message(New().Struct("not a struct")))
+}
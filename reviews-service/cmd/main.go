@@ -1,9 +1,16 @@
 package main
 
 import (
+	"augustberries/pkg/authcookie"
+	"augustberries/pkg/featureflags"
+	"augustberries/pkg/jwtclaims"
+	"augustberries/pkg/kafka"
+	"augustberries/pkg/openapi"
+	"augustberries/pkg/recovery"
 	"augustberries/reviews-service/internal/app/reviews/config"
 	"augustberries/reviews-service/internal/app/reviews/handler"
-	"augustberries/reviews-service/internal/app/reviews/infrastructure/messaging"
+	"augustberries/reviews-service/internal/app/reviews/infrastructure"
+	"augustberries/reviews-service/internal/app/reviews/processor"
 	"augustberries/reviews-service/internal/app/reviews/repository"
 	"augustberries/reviews-service/internal/app/reviews/service"
 	"context"
@@ -15,7 +22,6 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func main() {
@@ -26,6 +32,17 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Паники в обработчиках и фоновых задачах отправляются в Sentry, если задан SENTRY_DSN
+	if err := recovery.ConfigureFromEnv(); err != nil {
+		log.Fatalf("Failed to configure panic reporting: %v", err)
+	}
+
+	// Проверка запросов и ответов по OpenAPI спецификации, только для dev и staging (OPENAPI_VALIDATION)
+	contract, err := openapi.FromEnv("reviews-service")
+	if err != nil {
+		log.Fatalf("Failed to configure OpenAPI validation: %v", err)
+	}
+
 	// === ПОДКЛЮЧЕНИЕ К MONGODB ===
 	// Используем официальный MongoDB driver для Go
 	mongoClient, err := connectMongoDB(cfg.MongoDB)
@@ -46,38 +63,163 @@ func main() {
 
 	// === ИНИЦИАЛИЗАЦИЯ KAFKA PRODUCER ===
 	// Kafka producer отправляет события REVIEW_CREATED в топик review_events
-	kafkaProducer := messaging.NewKafkaProducer(cfg.Kafka.Brokers, cfg.Kafka.Topic)
+	kafkaProducer, err := kafka.NewProducer(kafka.ProducerConfig{
+		Brokers:     cfg.Kafka.Brokers,
+		Topic:       cfg.Kafka.Topic,
+		Service:     "reviews-service",
+		Compression: cfg.Kafka.Compression,
+		BatchSize:   cfg.Kafka.BatchSize,
+		BatchBytes:  cfg.Kafka.BatchBytes,
+		Linger:      cfg.Kafka.Linger,
+		Acks:        cfg.Kafka.Acks,
+		Idempotent:  cfg.Kafka.Idempotent,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create Kafka producer: %v", err)
+	}
 	defer kafkaProducer.Close()
 	log.Println("Successfully initialized Kafka producer")
 
 	// === ИНИЦИАЛИЗАЦИЯ СЛОЯ РЕПОЗИТОРИЕВ ===
 	// Репозиторий отвечает за работу с MongoDB (с индексом по product_id)
-	reviewRepo := repository.NewReviewRepository(db)
+	// Транзакции включаются только на replica set (MONGODB_TRANSACTIONS=true)
+	reviewRepo := repository.NewReviewRepository(db, repository.TransactionConfig{
+		Enabled:     cfg.MongoDB.Transactions,
+		MaxAttempts: cfg.MongoDB.TransactionMaxAttempts,
+	})
+
+	// Профили авторов - копия публичных имен и аватаров из Auth Service, email не хранится
+	profileRepo := repository.NewProfileRepository(db)
 
 	// === ИНИЦИАЛИЗАЦИЯ БИЗНЕС-ЛОГИКИ ===
 	// Service layer координирует работу репозитория и Kafka
-	reviewService := service.NewReviewService(reviewRepo, kafkaProducer)
+	reviewService := service.NewReviewService(reviewRepo, kafkaProducer, profileRepo)
+	reviewService.SetEditWindow(cfg.Edits.Window)
+
+	// === ИНИЦИАЛИЗАЦИЯ KAFKA CONSUMER ===
+	// USER_REGISTERED и USER_UPDATED из user_events обновляют профили авторов отзывов
+	if cfg.Kafka.UserEventsTopic != "" {
+		userEventsConsumer := processor.NewUserEventsConsumer(kafka.NewConsumer(kafka.ConsumerConfig{
+			Brokers:  cfg.Kafka.Brokers,
+			Topic:    cfg.Kafka.UserEventsTopic,
+			GroupID:  cfg.Kafka.UserEventsGroupID,
+			Service:  "reviews-service",
+			MinBytes: 1,
+			MaxBytes: 10e6,
+		}), service.NewProfileService(profileRepo))
+		userEventsConsumer.Start(context.Background())
+		defer userEventsConsumer.Stop()
+		log.Printf("User events consumer started (topic: %s, group: %s)", cfg.Kafka.UserEventsTopic, cfg.Kafka.UserEventsGroupID)
+	}
+
+	// ORDER_DELIVERED из order_events отмечает доставленные покупки: по ним отзыв получает verified_purchase
+	// или отклоняется без покупки (REVIEW_REQUIRE_PURCHASE), без синхронных запросов в Orders Service
+	if cfg.Kafka.OrderEventsTopic != "" {
+		purchaseRepo := repository.NewPurchaseRepository(db)
+		reviewService.SetPurchases(purchaseRepo, cfg.Purchases.Required)
+
+		orderEventsConsumer := processor.NewOrderEventsConsumer(kafka.NewConsumer(kafka.ConsumerConfig{
+			Brokers:  cfg.Kafka.Brokers,
+			Topic:    cfg.Kafka.OrderEventsTopic,
+			GroupID:  cfg.Kafka.OrderEventsGroupID,
+			Service:  "reviews-service",
+			MinBytes: 1,
+			MaxBytes: 10e6,
+		}), service.NewPurchaseService(purchaseRepo))
+		orderEventsConsumer.Start(context.Background())
+		defer orderEventsConsumer.Stop()
+		log.Printf("Order events consumer started (topic: %s, group: %s)", cfg.Kafka.OrderEventsTopic, cfg.Kafka.OrderEventsGroupID)
+	}
+
+	// Жалобы на отзывы: лимит частоты и автоматическое скрытие после порога жалоб
+	reportService := service.NewReportService(repository.NewReportRepository(db), reviewService, service.ReportConfig{
+		FlagThreshold: cfg.Reports.FlagThreshold,
+		RateLimit:     cfg.Reports.RateLimit,
+		RateWindow:    cfg.Reports.RateWindow,
+	})
+
+	// === АНАЛИЗ ТОНАЛЬНОСТИ ===
+	// Фоновое задание оценивает новые и отредактированные отзывы выбранным анализатором
+	if analyzer := newSentimentAnalyzer(cfg.Sentiment); analyzer != nil {
+		sentimentJob := service.NewSentimentJob(service.NewSentimentService(reviewRepo, analyzer, cfg.Sentiment.BatchSize))
+		if err := sentimentJob.Start(context.Background(), cfg.Sentiment.Cron); err != nil {
+			log.Fatalf("Failed to start sentiment job: %v", err)
+		}
+		defer sentimentJob.Stop()
+		log.Printf("Sentiment job started (analyzer: %s)", analyzer.Name())
+	}
+
+	// === ФЛАГИ ФУНКЦИОНАЛЬНОСТИ ===
+	// Флаги хранятся в коллекции feature_flags и кешируются в памяти процесса
+	flags := featureflags.NewClient(featureflags.NewMongoStore(db), cfg.FeatureFlags.CacheTTL)
+
+	// === ВНЕШНЯЯ МОДЕРАЦИЯ ===
+	// Новые и измененные отзывы проверяются на токсичность пулом горутин после ответа клиенту
+	// Флаг reviews.auto_moderation выключает проверку без перезапуска
+	if cfg.Moderation.Provider == "http" {
+		provider := infrastructure.NewHTTPModerationProvider(infrastructure.HTTPModerationProviderConfig{
+			URL:     cfg.Moderation.APIURL,
+			APIKey:  cfg.Moderation.APIKey,
+			Timeout: cfg.Moderation.Timeout,
+		})
+		moderator := service.NewAutoModerator(provider, reviewService, flags, service.AutoModerationConfig{
+			Workers:      cfg.Moderation.Workers,
+			QueueSize:    cfg.Moderation.QueueSize,
+			Timeout:      cfg.Moderation.Timeout,
+			MaxAttempts:  cfg.Moderation.MaxAttempts,
+			RetryBackoff: cfg.Moderation.RetryBackoff,
+			Threshold:    cfg.Moderation.Threshold,
+		})
+		moderator.Start(context.Background())
+		defer moderator.Stop()
+		reviewService.SetAutoModerator(moderator)
+	}
+
+	// === СВЕРКА ОЦЕНОК С КАТАЛОГОМ ===
+	// Ночное задание пересчитывает оценки товаров по отзывам и исправляет rating_avg/rating_count в Catalog Service
+	if cfg.Ratings.InternalToken != "" {
+		catalogClient := infrastructure.NewHTTPCatalogClient(infrastructure.HTTPCatalogClientConfig{
+			BaseURL: cfg.Ratings.CatalogURL,
+			Token:   cfg.Ratings.InternalToken,
+			Timeout: cfg.Ratings.Timeout,
+		})
+		ratingJob := service.NewRatingReconcileJob(service.NewRatingReconciler(reviewRepo, catalogClient, cfg.Ratings.Repair))
+		if err := ratingJob.Start(context.Background(), cfg.Ratings.Cron); err != nil {
+			log.Fatalf("Failed to start rating reconcile job: %v", err)
+		}
+		defer ratingJob.Stop()
+		log.Printf("Rating reconcile job started (catalog: %s, repair: %t)", cfg.Ratings.CatalogURL, cfg.Ratings.Repair)
+	}
 
 	// === ИНИЦИАЛИЗАЦИЯ AUTH MIDDLEWARE ===
 	// Middleware проверяет JWT токены для защиты API эндпоинтов
 	// JWT Secret должен совпадать с Auth Service
 	authMiddleware := handler.NewAuthMiddleware(cfg.JWT.Secret)
+	authMiddleware.SetExpectedClaims(jwtclaims.Expected{Issuer: cfg.JWT.Issuer, Audience: cfg.JWT.Audience})
+	// Токен из httpOnly cookie для SPA (AUTH_COOKIES); изменяющие запросы с cookie требуют X-CSRF-Token
+	cookies, err := authcookie.FromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure auth cookies: %v", err)
+	}
+	authMiddleware.SetCookies(cookies)
 	log.Println("Initialized Auth middleware")
 
 	// === ИНИЦИАЛИЗАЦИЯ HTTP HANDLERS ===
 	// Handler обрабатывает HTTP запросы и вызывает методы service
 	reviewHandler := handler.NewReviewHandler(reviewService)
+	adminHandler := handler.NewAdminHandler(reviewService)
+	reportHandler := handler.NewReportHandler(reportService)
 
 	// === НАСТРОЙКА МАРШРУТОВ ===
 	// Настраиваем REST API endpoints согласно заданию с использованием Gin
 	// Применяем Auth middleware для защиты эндпоинтов
-	router := handler.SetupRoutes(reviewHandler, authMiddleware)
+	router := handler.SetupRoutes(reviewHandler, adminHandler, reportHandler, featureflags.NewHandler(flags), authMiddleware, cfg.CORS, cfg.Compression)
 
 	// === НАСТРОЙКА HTTP СЕРВЕРА ===
 	// Production-ready настройки с таймаутами
 	server := &http.Server{
 		Addr:         cfg.Server.Address(),
-		Handler:      router,
+		Handler:      contract.Wrap(router),
 		ReadTimeout:  15 * time.Second, // Таймаут чтения запроса
 		WriteTimeout: 15 * time.Second, // Таймаут записи ответа
 		IdleTimeout:  60 * time.Second, // Таймаут idle соединений
@@ -111,16 +253,34 @@ func main() {
 	log.Println("Reviews Service stopped gracefully")
 }
 
+// newSentimentAnalyzer создает анализатор тональности из конфигурации, nil - анализ отключен
+func newSentimentAnalyzer(cfg config.SentimentConfig) infrastructure.SentimentAnalyzer {
+	switch cfg.Analyzer {
+	case "http":
+		return infrastructure.NewHTTPSentimentAnalyzer(infrastructure.HTTPSentimentAnalyzerConfig{
+			URL:     cfg.APIURL,
+			APIKey:  cfg.APIKey,
+			Timeout: cfg.Timeout,
+		})
+	case "lexicon":
+		return infrastructure.NewLexiconSentimentAnalyzer()
+	default:
+		return nil
+	}
+}
+
 // connectMongoDB устанавливает соединение с MongoDB
 // Использует retry logic с 10 попытками для устойчивости при запуске в Docker
 func connectMongoDB(cfg config.MongoDBConfig) (*mongo.Client, error) {
-	// Настройка MongoDB клиента
-	clientOptions := options.Client().ApplyURI(cfg.URI)
+	// Настройка MongoDB клиента: write concern, read preference и retryable writes из конфигурации
+	clientOptions, err := cfg.ClientOptions()
+	if err != nil {
+		return nil, err
+	}
 
 	// Пробуем подключиться с повторными попытками
 	// При запуске в Docker MongoDB может быть еще не готов
 	var client *mongo.Client
-	var err error
 
 	for i := 0; i < 10; i++ {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -1,16 +1,34 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"time"
+
+	"augustberries/pkg/httpmw"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
 // Config содержит все настройки приложения Reviews Service
 // Включает конфигурацию для HTTP сервера, MongoDB, Kafka и JWT
 type Config struct {
-	Server  ServerConfig
-	MongoDB MongoDBConfig
-	Kafka   KafkaConfig
-	JWT     JWTConfig
+	Server       ServerConfig
+	CORS         httpmw.CORSConfig
+	Compression  httpmw.CompressConfig
+	MongoDB      MongoDBConfig
+	Kafka        KafkaConfig
+	JWT          JWTConfig
+	Reports      ReportsConfig
+	Sentiment    SentimentConfig
+	Ratings      RatingsConfig
+	Edits        EditsConfig
+	Purchases    PurchasesConfig
+	Moderation   ModerationConfig
+	FeatureFlags FeatureFlagsConfig
 }
 
 // ServerConfig - настройки HTTP сервера
@@ -24,39 +42,363 @@ type ServerConfig struct {
 type MongoDBConfig struct {
 	URI      string // URI подключения к MongoDB
 	Database string // Имя базы данных
+
+	Transactions           bool   // Выполнять многодокументные операции в транзакциях (нужен replica set)
+	TransactionMaxAttempts int    // Число попыток транзакции при временных ошибках
+	RetryWrites            bool   // Повторять одиночные записи при сетевых ошибках и смене primary
+	WriteConcern           string // majority или число подтверждающих узлов
+	ReadPreference         string // primary, primaryPreferred, secondary, secondaryPreferred, nearest
 }
 
 // KafkaConfig - настройки Kafka для отправки событий
 // События отправляются при создании отзывов
 type KafkaConfig struct {
-	Brokers []string // Список брокеров Kafka (формат: host:port)
-	Topic   string   // Топик для событий REVIEW_CREATED
+	Brokers     []string      // Список брокеров Kafka (формат: host:port)
+	Topic       string        // Топик для событий REVIEW_CREATED
+	Compression string        // Кодек сжатия: none, gzip, snappy, lz4, zstd
+	BatchSize   int           // Максимум сообщений в батче
+	BatchBytes  int64         // Максимальный размер батча в байтах
+	Linger      time.Duration // Время ожидания заполнения батча перед отправкой
+	Acks        string        // Уровень подтверждения записи: none, one, all
+	Idempotent  bool          // Идемпотентный режим (acks=all, порядок по ключу)
+
+	UserEventsTopic   string // Топик событий Auth Service для профилей авторов (пустой - не читать)
+	UserEventsGroupID string // Consumer group для user_events
+
+	OrderEventsTopic   string // Топик событий Orders Service для доставленных покупок (пустой - не читать)
+	OrderEventsGroupID string // Consumer group для order_events
 }
 
 // JWTConfig - настройки для проверки JWT токенов
 // Используется для аутентификации запросов от пользователей
 type JWTConfig struct {
-	Secret string // Секретный ключ для проверки JWT токенов (должен совпадать с Auth Service)
+	Secret   string // Секретный ключ для проверки JWT токенов (должен совпадать с Auth Service)
+	Issuer   string // Ожидаемый iss (JWT_ISSUER Auth Service), пустой - не проверяется
+	Audience string // Ожидаемый aud (JWT_AUDIENCE Auth Service), пустой - не проверяется
+}
+
+// ReportsConfig - настройки жалоб на отзывы
+type ReportsConfig struct {
+	FlagThreshold int           // Число открытых жалоб, после которого отзыв скрывается до решения модератора
+	RateLimit     int           // Максимум жалоб от одного пользователя за RateWindow
+	RateWindow    time.Duration // Окно ограничения частоты жалоб
+}
+
+// SentimentConfig - настройки фонового анализа тональности отзывов
+type SentimentConfig struct {
+	Analyzer  string        // lexicon - локальный словарный, http - внешний API, none - отключено
+	APIURL    string        // Адрес внешнего API для analyzer=http
+	APIKey    string        // Ключ внешнего API
+	Timeout   time.Duration // Таймаут запроса к внешнему API
+	Cron      string        // Расписание запуска задания (формат robfig/cron)
+	BatchSize int           // Сколько отзывов обрабатывается за один запуск
+}
+
+// RatingsConfig - настройки сверки оценок товаров с Catalog Service
+type RatingsConfig struct {
+	CatalogURL    string        // Адрес Catalog Service
+	InternalToken string        // Токен внутреннего API Catalog Service, пустой - сверка отключена
+	Timeout       time.Duration // Таймаут запроса к Catalog Service
+	Cron          string        // Расписание сверки (формат robfig/cron)
+	Repair        bool          // Исправлять расхождения в каталоге (false - только логировать)
+}
+
+// EditsConfig - настройки изменения отзывов авторами
+type EditsConfig struct {
+	Window time.Duration // Сколько после создания автор может изменять отзыв, 0 - без ограничения
+}
+
+// PurchasesConfig - настройки проверки покупки при создании отзыва
+type PurchasesConfig struct {
+	Required bool // Отзыв может оставить только покупатель, которому товар доставлен
+}
+
+// ModerationConfig - настройки проверки новых отзывов внешним сервисом модерации
+type ModerationConfig struct {
+	Provider     string        // http - внешний API оценки токсичности, none - отключено
+	APIURL       string        // Адрес внешнего API для provider=http
+	APIKey       string        // Ключ внешнего API
+	Timeout      time.Duration // Таймаут одной попытки
+	MaxAttempts  int           // Попыток на отзыв, включая первую
+	RetryBackoff time.Duration // Пауза перед повтором, удваивается с каждой попыткой
+	Workers      int           // Горутин, параллельно вызывающих API
+	QueueSize    int           // Размер очереди отзывов на проверку
+	Threshold    float64       // Токсичность (0..1), начиная с которой отзыв скрывается до решения модератора
+}
+
+// FeatureFlagsConfig - настройки флагов функциональности (коллекция feature_flags)
+type FeatureFlagsConfig struct {
+	CacheTTL time.Duration // Сколько значение флага кешируется в памяти процесса
 }
 
 // Load загружает конфигурацию из переменных окружения
 func Load() (*Config, error) {
+	// Настройки Kafka producer: по умолчанию snappy, небольшие батчи и подтверждение всеми репликами
+	kafkaBatchSize, err := strconv.Atoi(getEnv("KAFKA_BATCH_SIZE", "100"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAFKA_BATCH_SIZE value: %w", err)
+	}
+
+	kafkaBatchBytes, err := strconv.ParseInt(getEnv("KAFKA_BATCH_BYTES", "1048576"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAFKA_BATCH_BYTES value: %w", err)
+	}
+
+	kafkaLinger, err := time.ParseDuration(getEnv("KAFKA_LINGER", "10ms"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAFKA_LINGER value: %w", err)
+	}
+
+	kafkaIdempotent, err := strconv.ParseBool(getEnv("KAFKA_IDEMPOTENT", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid KAFKA_IDEMPOTENT value: %w", err)
+	}
+
+	mongoTransactions, err := strconv.ParseBool(getEnv("MONGODB_TRANSACTIONS", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MONGODB_TRANSACTIONS value: %w", err)
+	}
+
+	mongoTxAttempts, err := strconv.Atoi(getEnv("MONGODB_TRANSACTION_MAX_ATTEMPTS", "3"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MONGODB_TRANSACTION_MAX_ATTEMPTS value: %w", err)
+	}
+
+	mongoRetryWrites, err := strconv.ParseBool(getEnv("MONGODB_RETRY_WRITES", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MONGODB_RETRY_WRITES value: %w", err)
+	}
+
+	reportFlagThreshold, err := strconv.Atoi(getEnv("REVIEW_REPORT_FLAG_THRESHOLD", "3"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid REVIEW_REPORT_FLAG_THRESHOLD value: %w", err)
+	}
+
+	reportRateLimit, err := strconv.Atoi(getEnv("REVIEW_REPORT_RATE_LIMIT", "10"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid REVIEW_REPORT_RATE_LIMIT value: %w", err)
+	}
+
+	reportRateWindow, err := time.ParseDuration(getEnv("REVIEW_REPORT_RATE_WINDOW", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid REVIEW_REPORT_RATE_WINDOW value: %w", err)
+	}
+
+	sentimentTimeout, err := time.ParseDuration(getEnv("SENTIMENT_API_TIMEOUT", "5s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SENTIMENT_API_TIMEOUT value: %w", err)
+	}
+
+	sentimentBatchSize, err := strconv.Atoi(getEnv("SENTIMENT_BATCH_SIZE", "100"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SENTIMENT_BATCH_SIZE value: %w", err)
+	}
+
+	ratingsTimeout, err := time.ParseDuration(getEnv("RATINGS_RECONCILE_TIMEOUT", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RATINGS_RECONCILE_TIMEOUT value: %w", err)
+	}
+
+	ratingsRepair, err := strconv.ParseBool(getEnv("RATINGS_RECONCILE_REPAIR", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RATINGS_RECONCILE_REPAIR value: %w", err)
+	}
+
+	editWindow, err := time.ParseDuration(getEnv("REVIEW_EDIT_WINDOW", "168h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid REVIEW_EDIT_WINDOW value: %w", err)
+	}
+	if editWindow < 0 {
+		return nil, fmt.Errorf("REVIEW_EDIT_WINDOW must not be negative")
+	}
+
+	purchaseRequired, err := strconv.ParseBool(getEnv("REVIEW_REQUIRE_PURCHASE", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid REVIEW_REQUIRE_PURCHASE value: %w", err)
+	}
+
+	moderationTimeout, err := time.ParseDuration(getEnv("MODERATION_TIMEOUT", "3s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MODERATION_TIMEOUT value: %w", err)
+	}
+
+	moderationAttempts, err := strconv.Atoi(getEnv("MODERATION_MAX_ATTEMPTS", "3"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MODERATION_MAX_ATTEMPTS value: %w", err)
+	}
+
+	moderationBackoff, err := time.ParseDuration(getEnv("MODERATION_RETRY_BACKOFF", "500ms"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MODERATION_RETRY_BACKOFF value: %w", err)
+	}
+
+	moderationWorkers, err := strconv.Atoi(getEnv("MODERATION_WORKERS", "4"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MODERATION_WORKERS value: %w", err)
+	}
+
+	moderationQueueSize, err := strconv.Atoi(getEnv("MODERATION_QUEUE_SIZE", "1000"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MODERATION_QUEUE_SIZE value: %w", err)
+	}
+
+	moderationThreshold, err := strconv.ParseFloat(getEnv("MODERATION_TOXICITY_THRESHOLD", "0.8"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MODERATION_TOXICITY_THRESHOLD value: %w", err)
+	}
+	if moderationThreshold < 0 || moderationThreshold > 1 {
+		return nil, fmt.Errorf("MODERATION_TOXICITY_THRESHOLD must be between 0 and 1")
+	}
+
+	moderationProvider := getEnv("MODERATION_PROVIDER", "none")
+	switch moderationProvider {
+	case "none":
+	case "http":
+		if os.Getenv("MODERATION_API_URL") == "" {
+			return nil, fmt.Errorf("MODERATION_API_URL is required for MODERATION_PROVIDER=http")
+		}
+	default:
+		return nil, fmt.Errorf("invalid MODERATION_PROVIDER value: %q", moderationProvider)
+	}
+
+	flagsCacheTTL, err := time.ParseDuration(getEnv("FEATURE_FLAGS_CACHE_TTL", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid FEATURE_FLAGS_CACHE_TTL value: %w", err)
+	}
+
+	sentimentAnalyzer := getEnv("SENTIMENT_ANALYZER", "lexicon")
+	switch sentimentAnalyzer {
+	case "lexicon", "none":
+	case "http":
+		if os.Getenv("SENTIMENT_API_URL") == "" {
+			return nil, fmt.Errorf("SENTIMENT_API_URL is required for SENTIMENT_ANALYZER=http")
+		}
+	default:
+		return nil, fmt.Errorf("invalid SENTIMENT_ANALYZER value: %q", sentimentAnalyzer)
+	}
+
+	// CORS для браузерных клиентов; credentials нужны SPA с токенами в cookie и требуют явного списка origin
+	corsMaxAge, err := time.ParseDuration(getEnv("CORS_MAX_AGE", "5m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CORS_MAX_AGE value: %w", err)
+	}
+
+	corsCredentials, err := strconv.ParseBool(getEnv("CORS_ALLOW_CREDENTIALS", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CORS_ALLOW_CREDENTIALS value: %w", err)
+	}
+
+	cors := httpmw.CORSConfig{
+		AllowedOrigins:   httpmw.ParseList(getEnv("CORS_ALLOWED_ORIGINS", "*")),
+		AllowedMethods:   httpmw.ParseList(getEnv("CORS_ALLOWED_METHODS", "")),
+		AllowedHeaders:   httpmw.ParseList(getEnv("CORS_ALLOWED_HEADERS", "")),
+		ExposedHeaders:   httpmw.ParseList(getEnv("CORS_EXPOSED_HEADERS", "")),
+		AllowCredentials: corsCredentials,
+		MaxAge:           corsMaxAge,
+	}
+	if err := cors.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Сжатие ответов больших списков (gzip, zstd) по Accept-Encoding
+	compressionEnabled, err := strconv.ParseBool(getEnv("RESPONSE_COMPRESSION", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RESPONSE_COMPRESSION value: %w", err)
+	}
+
+	compressionMinSize, err := strconv.Atoi(getEnv("RESPONSE_COMPRESSION_MIN_SIZE", strconv.Itoa(httpmw.DefaultMinSize)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RESPONSE_COMPRESSION_MIN_SIZE value: %w", err)
+	}
+
+	compression := httpmw.CompressConfig{
+		Enabled:      compressionEnabled,
+		Encodings:    httpmw.ParseList(getEnv("RESPONSE_COMPRESSION_ENCODINGS", "zstd,gzip")),
+		MinSize:      compressionMinSize,
+		ContentTypes: httpmw.ParseList(getEnv("RESPONSE_COMPRESSION_CONTENT_TYPES", "")),
+	}
+	if err := compression.Validate(); err != nil {
+		return nil, err
+	}
+
 	return &Config{
 		Server: ServerConfig{
 			Host: getEnv("SERVER_HOST", "0.0.0.0"),
 			Port: getEnv("SERVER_PORT", "8083"),
 		},
+		CORS:        cors,
+		Compression: compression,
 		MongoDB: MongoDBConfig{
 			URI:      getEnv("MONGODB_URI", "mongodb://localhost:27017"),
 			Database: getEnv("MONGODB_DATABASE", "reviews_service"),
+
+			Transactions:           mongoTransactions,
+			TransactionMaxAttempts: mongoTxAttempts,
+			RetryWrites:            mongoRetryWrites,
+			WriteConcern:           getEnv("MONGODB_WRITE_CONCERN", "majority"),
+			ReadPreference:         getEnv("MONGODB_READ_PREFERENCE", "primary"),
 		},
 		Kafka: KafkaConfig{
-			Brokers: []string{getEnv("KAFKA_BROKERS", "localhost:9092")},
-			Topic:   getEnv("KAFKA_TOPIC", "review_events"),
+			Brokers:     []string{getEnv("KAFKA_BROKERS", "localhost:9092")},
+			Topic:       getEnv("KAFKA_TOPIC", "review_events"),
+			Compression: getEnv("KAFKA_COMPRESSION", "snappy"),
+			BatchSize:   kafkaBatchSize,
+			BatchBytes:  kafkaBatchBytes,
+			Linger:      kafkaLinger,
+			Acks:        getEnv("KAFKA_ACKS", "all"),
+			Idempotent:  kafkaIdempotent,
+
+			UserEventsTopic:   getEnv("KAFKA_USER_EVENTS_TOPIC", "user_events"),
+			UserEventsGroupID: getEnv("KAFKA_USER_EVENTS_GROUP_ID", "reviews-service-profiles"),
+
+			OrderEventsTopic:   getEnv("KAFKA_ORDER_EVENTS_TOPIC", "order_events"),
+			OrderEventsGroupID: getEnv("KAFKA_ORDER_EVENTS_GROUP_ID", "reviews-service-purchases"),
 		},
 		JWT: JWTConfig{
 			// JWT Secret должен совпадать с Auth Service для валидации токенов
-			Secret: getEnv("JWT_SECRET", "your-secret-key-change-this-in-production"),
+			Secret:   getEnv("JWT_SECRET", "your-secret-key-change-this-in-production"),
+			Issuer:   getEnv("JWT_ISSUER", ""),
+			Audience: getEnv("JWT_AUDIENCE", ""),
+		},
+		Reports: ReportsConfig{
+			FlagThreshold: reportFlagThreshold,
+			RateLimit:     reportRateLimit,
+			RateWindow:    reportRateWindow,
+		},
+		Sentiment: SentimentConfig{
+			Analyzer:  sentimentAnalyzer,
+			APIURL:    getEnv("SENTIMENT_API_URL", ""),
+			APIKey:    getEnv("SENTIMENT_API_KEY", ""),
+			Timeout:   sentimentTimeout,
+			Cron:      getEnv("SENTIMENT_CRON", "@every 5m"),
+			BatchSize: sentimentBatchSize,
+		},
+		Ratings: RatingsConfig{
+			CatalogURL:    getEnv("CATALOG_SERVICE_URL", "http://localhost:8081"),
+			InternalToken: getEnv("CATALOG_INTERNAL_TOKEN", ""),
+			Timeout:       ratingsTimeout,
+			Cron:          getEnv("RATINGS_RECONCILE_CRON", "0 3 * * *"), // Каждую ночь в 03:00
+			Repair:        ratingsRepair,
+		},
+		Edits: EditsConfig{
+			Window: editWindow,
+		},
+		Purchases: PurchasesConfig{
+			Required: purchaseRequired,
+		},
+		Moderation: ModerationConfig{
+			Provider:     moderationProvider,
+			APIURL:       getEnv("MODERATION_API_URL", ""),
+			APIKey:       getEnv("MODERATION_API_KEY", ""),
+			Timeout:      moderationTimeout,
+			MaxAttempts:  moderationAttempts,
+			RetryBackoff: moderationBackoff,
+			Workers:      moderationWorkers,
+			QueueSize:    moderationQueueSize,
+			Threshold:    moderationThreshold,
+		},
+		FeatureFlags: FeatureFlagsConfig{
+			CacheTTL: flagsCacheTTL,
 		},
 	}, nil
 }
@@ -66,6 +408,37 @@ func (c *ServerConfig) Address() string {
 	return c.Host + ":" + c.Port
 }
 
+// ClientOptions возвращает настройки клиента MongoDB: URI, retryable writes, write concern и read preference
+func (c *MongoDBConfig) ClientOptions() (*options.ClientOptions, error) {
+	opts := options.Client().ApplyURI(c.URI).SetRetryWrites(c.RetryWrites)
+
+	switch c.WriteConcern {
+	case "", "majority":
+		opts.SetWriteConcern(writeconcern.Majority())
+	default:
+		w, err := strconv.Atoi(c.WriteConcern)
+		if err != nil || w < 0 {
+			return nil, fmt.Errorf("invalid write concern %q: expected majority or a non-negative number", c.WriteConcern)
+		}
+		opts.SetWriteConcern(&writeconcern.WriteConcern{W: w})
+	}
+
+	mode := readpref.PrimaryMode
+	if c.ReadPreference != "" {
+		var err error
+		if mode, err = readpref.ModeFromString(c.ReadPreference); err != nil {
+			return nil, fmt.Errorf("invalid read preference %q: %w", c.ReadPreference, err)
+		}
+	}
+	rp, err := readpref.New(mode)
+	if err != nil {
+		return nil, fmt.Errorf("invalid read preference %q: %w", c.ReadPreference, err)
+	}
+	opts.SetReadPreference(rp)
+
+	return opts, nil
+}
+
 // getEnv получает значение переменной окружения или возвращает значение по умолчанию
 // Используется для гибкой конфигурации через environment variables
 func getEnv(key, defaultValue string) string {
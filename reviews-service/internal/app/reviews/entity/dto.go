@@ -1,5 +1,12 @@
 package entity
 
+import (
+	"time"
+
+	"augustberries/pkg/pagination"
+	"augustberries/pkg/patch"
+)
+
 // CreateReviewRequest - запрос на создание отзыва
 type CreateReviewRequest struct {
 	ProductID string `json:"product_id" validate:"required"`
@@ -7,10 +14,11 @@ type CreateReviewRequest struct {
 	Text      string `json:"text" validate:"required,min=10,max=1000"`
 }
 
-// UpdateReviewRequest - запрос на обновление отзыва
+// UpdateReviewRequest - частичное обновление отзыва (JSON Merge Patch)
+// Отсутствующие поля не меняются; оценку и текст нельзя удалить, null отклоняется
 type UpdateReviewRequest struct {
-	Rating int    `json:"rating" validate:"omitempty,min=1,max=5"`
-	Text   string `json:"text" validate:"omitempty,min=10,max=1000"`
+	Rating patch.Field[int]    `json:"rating,omitzero" validate:"omitempty,min=1,max=5"`
+	Text   patch.Field[string] `json:"text,omitzero" validate:"omitempty,min=10,max=1000"`
 }
 
 // ErrorResponse - стандартный ответ об ошибке
@@ -25,8 +33,115 @@ type SuccessResponse struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
-// ReviewListResponse - ответ со списком отзывов
+// ReviewListResponse - ответ со страницей отзывов
 type ReviewListResponse struct {
 	Reviews []Review `json:"reviews"`
-	Total   int      `json:"total"`
+	pagination.Meta
+}
+
+// ModerationAction - действие модератора над отзывом
+type ModerationAction string
+
+const (
+	ModerationActionApprove ModerationAction = "approve" // Опубликовать
+	ModerationActionHide    ModerationAction = "hide"    // Скрыть от покупателей
+	ModerationActionDelete  ModerationAction = "delete"  // Удалить (только admin)
+)
+
+// AdminDeleteReviewRequest - запрос на удаление отзыва модератором
+type AdminDeleteReviewRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// BulkModerationRequest - запрос на массовую модерацию (POST /admin/reviews/bulk)
+// Для hide и delete причина обязательна
+type BulkModerationRequest struct {
+	ReviewIDs []string         `json:"review_ids" validate:"required,min=1,max=100"`
+	Action    ModerationAction `json:"action" validate:"required,oneof=approve hide delete"`
+	Reason    string           `json:"reason" validate:"max=500"`
+}
+
+// BulkModerationResult - результат модерации одного отзыва
+type BulkModerationResult struct {
+	ReviewID string       `json:"review_id"`
+	Success  bool         `json:"success"`
+	Status   ReviewStatus `json:"status,omitempty"` // Новый статус (пусто для удаленных)
+	Error    string       `json:"error,omitempty"`
+}
+
+// BulkModerationResponse - отчет о массовой модерации в порядке запроса
+type BulkModerationResponse struct {
+	Results   []BulkModerationResult `json:"results"`
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+}
+
+// ReportReviewRequest - жалоба на отзыв (POST /reviews/:review_id/report)
+type ReportReviewRequest struct {
+	Reason  ReportReason `json:"reason" validate:"required,oneof=spam offensive off_topic fake other"`
+	Comment string       `json:"comment" validate:"max=500"`
+}
+
+// ResolveReportRequest - решение модератора по жалобе (POST /admin/reviews/reports/:report_id/resolve)
+// Решение применяется ко всем открытым жалобам на тот же отзыв
+type ResolveReportRequest struct {
+	Action ModerationAction `json:"action" validate:"required,oneof=approve hide delete"` // approve отклоняет жалобы и публикует отзыв
+	Note   string           `json:"note" validate:"max=500"`
+}
+
+// ResolveReportResponse - результат рассмотрения жалобы
+type ResolveReportResponse struct {
+	ReviewID string       `json:"review_id"`
+	Status   ReportStatus `json:"status"`   // Новый статус жалоб
+	Resolved int64        `json:"resolved"` // Сколько открытых жалоб закрыто
+}
+
+// ReportListResponse - страница очереди жалоб
+type ReportListResponse struct {
+	Reports []ReviewReport `json:"reports"`
+	pagination.Meta
+}
+
+// ProductRating - средняя оценка и число опубликованных отзывов товара
+// В таком виде оценки денормализованы в Catalog Service (rating_avg, rating_count)
+type ProductRating struct {
+	ProductID   string  `json:"product_id" bson:"_id"`
+	RatingAvg   float64 `json:"rating_avg" bson:"rating_avg"`
+	RatingCount int     `json:"rating_count" bson:"rating_count"`
+}
+
+// RatingReconcileResult - итог сверки оценок с Catalog Service
+type RatingReconcileResult struct {
+	Tenants       int // Сколько магазинов сверено
+	Checked       int // Сколько товаров каталога сверено
+	Discrepancies int // Сколько оценок расходилось с отзывами
+	Repaired      int // Сколько оценок исправлено в каталоге
+}
+
+// RatingSummary - сводка оценок товара (GET /reviews/product/:product_id/summary)
+// Учитываются только опубликованные отзывы
+type RatingSummary struct {
+	ProductID    string           `json:"product_id"`
+	Count        int              `json:"count"`
+	Average      float64          `json:"average"`      // Средняя оценка, 0 - отзывов нет
+	Distribution map[int]int      `json:"distribution"` // Число отзывов по оценкам 1-5
+	Sentiment    SentimentSummary `json:"sentiment"`
+}
+
+// SentimentSummary - агрегированная тональность отзывов товара
+// Отзывы, еще не обработанные заданием анализа, не учитываются
+type SentimentSummary struct {
+	Analyzed     int     `json:"analyzed"`      // Сколько отзывов имеют оценку тональности
+	AverageScore float64 `json:"average_score"` // Средняя оценка от -1 до 1
+	Positive     int     `json:"positive"`
+	Neutral      int     `json:"neutral"`
+	Negative     int     `json:"negative"`
+}
+
+// DashboardSummary - сводка модерации для панели администратора (GET /admin/summary)
+type DashboardSummary struct {
+	PendingModeration int64     `json:"pending_moderation"` // Открытые жалобы в очереди модерации
+	FlaggedReviews    int64     `json:"flagged_reviews"`    // Отзывы, скрытые по жалобам до решения модератора
+	HiddenReviews     int64     `json:"hidden_reviews"`
+	GeneratedAt       time.Time `json:"generated_at"`
 }
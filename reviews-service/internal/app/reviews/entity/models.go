@@ -6,23 +6,233 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ReviewStatus - статус модерации отзыва
+type ReviewStatus string
+
+const (
+	ReviewStatusPublished ReviewStatus = "published" // Виден покупателям (отзывы без статуса тоже опубликованы)
+	ReviewStatusHidden    ReviewStatus = "hidden"    // Скрыт модератором
+	ReviewStatusFlagged   ReviewStatus = "flagged"   // Скрыт по жалобам до решения модератора
+)
+
 // Review представляет отзыв на товар в системе
 type Review struct {
 	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	TenantID  string             `json:"-" bson:"tenant_id"`           // Магазин, в котором оставлен отзыв
 	ProductID string             `json:"product_id" bson:"product_id"` // UUID товара из Catalog Service
 	UserID    string             `json:"user_id" bson:"user_id"`       // UUID пользователя из Auth Service
 	Rating    int                `json:"rating" bson:"rating"`         // Оценка от 1 до 5
 	Text      string             `json:"text" bson:"text"`             // Текст отзыва
+	Status    ReviewStatus       `json:"status" bson:"status"`
 	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+
+	// Edited - автор изменял отзыв после публикации; прежние версии хранятся в History
+	Edited  bool            `json:"edited" bson:"edited,omitempty"`
+	History []ReviewVersion `json:"-" bson:"history,omitempty"`
+
+	// Последнее решение модератора
+	ModeratedBy      string     `json:"moderated_by,omitempty" bson:"moderated_by,omitempty"`
+	ModerationReason string     `json:"moderation_reason,omitempty" bson:"moderation_reason,omitempty"`
+	ModeratedAt      *time.Time `json:"moderated_at,omitempty" bson:"moderated_at,omitempty"`
+
+	// VerifiedPurchase - на момент создания отзыва автору был доставлен заказ с этим товаром
+	VerifiedPurchase bool `json:"verified_purchase" bson:"verified_purchase,omitempty"`
+
+	// Тональность текста, заполняется фоновым заданием; сбрасывается при изменении текста
+	Sentiment *ReviewSentiment `json:"sentiment,omitempty" bson:"sentiment,omitempty"`
+
+	// Проверка текста внешним сервисом модерации после создания и изменения; сбрасывается при изменении текста
+	AutoModeration *AutoModeration `json:"auto_moderation,omitempty" bson:"auto_moderation,omitempty"`
+
+	// Публичный профиль автора, заполняется при выдаче и не хранится в отзыве
+	Author *ReviewAuthor `json:"author,omitempty" bson:"-"`
+}
+
+// ReviewVersion - прежняя версия отзыва, сохраняется при каждом изменении автором
+type ReviewVersion struct {
+	Rating     int       `json:"rating" bson:"rating"`
+	Text       string    `json:"text" bson:"text"`
+	WrittenAt  time.Time `json:"written_at" bson:"written_at"`   // Когда версия была написана
+	ReplacedAt time.Time `json:"replaced_at" bson:"replaced_at"` // Когда версию заменила следующая
+}
+
+// ReviewHistory - история изменений отзыва, старые версии первыми
+type ReviewHistory struct {
+	ReviewID string          `json:"review_id"`
+	Current  ReviewVersion   `json:"current"`
+	Versions []ReviewVersion `json:"versions"`
+}
+
+// SentimentLabel - тональность отзыва
+type SentimentLabel string
+
+const (
+	SentimentPositive SentimentLabel = "positive"
+	SentimentNeutral  SentimentLabel = "neutral"
+	SentimentNegative SentimentLabel = "negative"
+)
+
+// ReviewSentiment - оценка тональности текста отзыва
+type ReviewSentiment struct {
+	Score      float64        `json:"score" bson:"score"` // От -1 (негативный) до 1 (позитивный)
+	Label      SentimentLabel `json:"label" bson:"label"`
+	Analyzer   string         `json:"analyzer" bson:"analyzer"` // Каким анализатором получена оценка
+	AnalyzedAt time.Time      `json:"analyzed_at" bson:"analyzed_at"`
+}
+
+// AutoModerationStatus - результат проверки отзыва внешним сервисом модерации
+type AutoModerationStatus string
+
+const (
+	AutoModerationPassed  AutoModerationStatus = "passed"  // Токсичность ниже порога
+	AutoModerationFlagged AutoModerationStatus = "flagged" // Отзыв скрыт до решения модератора
+	AutoModerationFailed  AutoModerationStatus = "failed"  // Сервис недоступен после всех попыток, отзыв остается опубликованным
+)
+
+// AutoModeration - оценка текста отзыва внешним сервисом модерации
+type AutoModeration struct {
+	Status    AutoModerationStatus `json:"status" bson:"status"`
+	Toxicity  float64              `json:"toxicity" bson:"toxicity"` // Вероятность токсичности от 0 до 1
+	Provider  string               `json:"provider" bson:"provider"`
+	Attempts  int                  `json:"attempts" bson:"attempts"`
+	CheckedAt time.Time            `json:"checked_at" bson:"checked_at"`
+}
+
+// ReviewAuthor - публичные данные автора отзыва (без email и других персональных данных)
+type ReviewAuthor struct {
+	DisplayName string `json:"display_name"`
+	AvatarURL   string `json:"avatar_url,omitempty"`
+}
+
+// UserProfile - локальная копия публичного профиля пользователя из Auth Service
+// Строится по событиям user_events, email не сохраняется
+type UserProfile struct {
+	UserID      string    `bson:"_id"`
+	TenantID    string    `bson:"tenant_id"`
+	DisplayName string    `bson:"display_name"`
+	AvatarURL   string    `bson:"avatar_url,omitempty"`
+	UpdatedAt   time.Time `bson:"updated_at"` // Время события, более старые события не применяются
 }
 
-// ReviewEvent представляет событие создания отзыва для Kafka
+// ReviewFilter - фильтр admin списка отзывов, пустые поля не ограничивают выборку
+type ReviewFilter struct {
+	ProductID string
+	UserID    string
+	Status    ReviewStatus
+	Rating    int
+}
+
+// Page - запрашиваемая страница списка отзывов
+type Page struct {
+	Offset int // Сколько отзывов пропустить
+	Limit  int // Размер страницы, больше нуля
+}
+
+// ReviewPage - страница отзывов и общее число отзывов, подходящих под условие
+type ReviewPage struct {
+	Reviews []Review
+	Total   int
+}
+
+// ReportReason - категория жалобы на отзыв
+type ReportReason string
+
+const (
+	ReportReasonSpam      ReportReason = "spam"
+	ReportReasonOffensive ReportReason = "offensive"
+	ReportReasonOffTopic  ReportReason = "off_topic"
+	ReportReasonFake      ReportReason = "fake"
+	ReportReasonOther     ReportReason = "other"
+)
+
+// ReportStatus - статус жалобы
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "open"      // Ждет модератора
+	ReportStatusActioned  ReportStatus = "actioned"  // Отзыв скрыт или удален
+	ReportStatusDismissed ReportStatus = "dismissed" // Жалоба отклонена, отзыв опубликован
+)
+
+// ReviewReport - жалоба пользователя на отзыв
+// Один пользователь может пожаловаться на отзыв только один раз
+type ReviewReport struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	TenantID   string             `json:"-" bson:"tenant_id"`
+	ReviewID   string             `json:"review_id" bson:"review_id"`
+	ProductID  string             `json:"product_id" bson:"product_id"`
+	ReporterID string             `json:"reporter_id" bson:"reporter_id"`
+	Reason     ReportReason       `json:"reason" bson:"reason"`
+	Comment    string             `json:"comment,omitempty" bson:"comment,omitempty"`
+	Status     ReportStatus       `json:"status" bson:"status"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+
+	ResolvedBy     string     `json:"resolved_by,omitempty" bson:"resolved_by,omitempty"`
+	ResolutionNote string     `json:"resolution_note,omitempty" bson:"resolution_note,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty" bson:"resolved_at,omitempty"`
+}
+
+// Типы событий отзывов
+const (
+	EventTypeReviewCreated   = "REVIEW_CREATED"
+	EventTypeReviewModerated = "REVIEW_MODERATED" // Модератор сменил статус отзыва
+	EventTypeReviewDeleted   = "REVIEW_DELETED"   // Отзыв удален модератором
+)
+
+// EventSchemaVersion - версия схемы ReviewEvent (заголовок schema_version)
+const EventSchemaVersion = 1
+
+// ReviewEvent представляет событие отзыва для Kafka
 type ReviewEvent struct {
-	EventType string    `json:"event_type"` // REVIEW_CREATED
-	ReviewID  string    `json:"review_id"`
-	ProductID string    `json:"product_id"`
+	EventType string       `json:"event_type"` // REVIEW_CREATED, REVIEW_MODERATED, REVIEW_DELETED
+	TenantID  string       `json:"tenant_id"`
+	ReviewID  string       `json:"review_id"`
+	ProductID string       `json:"product_id"`
+	UserID    string       `json:"user_id"`
+	Rating    int          `json:"rating"`
+	Status    ReviewStatus `json:"status,omitempty"` // Новый статус для REVIEW_MODERATED
+	Reason    string       `json:"reason,omitempty"` // Причина решения модератора
+	Timestamp time.Time    `json:"timestamp"`
+}
+
+// Типы событий Auth Service, из которых строится профиль автора
+const (
+	EventTypeUserRegistered = "USER_REGISTERED"
+	EventTypeUserUpdated    = "USER_UPDATED"
+)
+
+// UserEvent - событие пользователя из топика user_events
+// Email в событии есть, но сервис отзывов его не читает
+type UserEvent struct {
+	EventType string    `json:"event_type"`
+	TenantID  string    `json:"tenant_id"`
 	UserID    string    `json:"user_id"`
-	Rating    int       `json:"rating"`
+	Name      string    `json:"name"`
+	AvatarURL string    `json:"avatar_url"`
 	Timestamp time.Time `json:"timestamp"`
 }
+
+// Тип события Orders Service, по которому отмечаются покупки
+const EventTypeOrderDelivered = "ORDER_DELIVERED"
+
+// OrderEvent - событие заказа из топика order_events
+// Сервис отзывов читает только ORDER_DELIVERED, суммы и статусы заказа не нужны
+type OrderEvent struct {
+	EventType  string    `json:"event_type"`
+	TenantID   string    `json:"tenant_id"`
+	OrderID    string    `json:"order_id"`
+	UserID     string    `json:"user_id"`
+	ProductIDs []string  `json:"product_ids"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// Purchase - доставленная покупателю покупка товара, строится по ORDER_DELIVERED
+// Одна запись на магазин, пользователя и товар: повторные заказы товара ее не меняют
+type Purchase struct {
+	TenantID    string    `bson:"tenant_id"`
+	UserID      string    `bson:"user_id"`
+	ProductID   string    `bson:"product_id"`
+	OrderID     string    `bson:"order_id"` // Первый доставленный заказ с товаром
+	DeliveredAt time.Time `bson:"delivered_at"`
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"augustberries/pkg/pagination"
+	"augustberries/pkg/validate"
+	"augustberries/reviews-service/internal/app/reviews/entity"
+	"augustberries/reviews-service/internal/app/reviews/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReviewAdminServiceInterface - методы сервиса для модерации отзывов
+type ReviewAdminServiceInterface interface {
+	ListReviews(ctx context.Context, filter entity.ReviewFilter, page entity.Page) (*entity.ReviewPage, error)
+	AdminDeleteReview(ctx context.Context, reviewID, moderatorID, reason string) error
+	BulkModerate(ctx context.Context, moderatorID string, req *entity.BulkModerationRequest) *entity.BulkModerationResponse
+}
+
+// AdminHandler обрабатывает admin API модерации отзывов
+// Доступ по ролям ограничивается в роутере через RequireRole
+type AdminHandler struct {
+	reviewService ReviewAdminServiceInterface
+	validator     *validate.Validator
+}
+
+// NewAdminHandler создает обработчик модерации отзывов
+func NewAdminHandler(reviewService ReviewAdminServiceInterface) *AdminHandler {
+	return &AdminHandler{
+		reviewService: reviewService,
+		validator:     validate.New(),
+	}
+}
+
+// ListReviews обрабатывает GET /admin/reviews?product_id=&user_id=&status=&rating=&page=&per_page=
+// Возвращает отзывы по всем товарам магазина, включая скрытые
+func (h *AdminHandler) ListReviews(c *gin.Context) {
+	filter := entity.ReviewFilter{
+		ProductID: c.Query("product_id"),
+		UserID:    c.Query("user_id"),
+		Status:    entity.ReviewStatus(c.Query("status")),
+	}
+
+	switch filter.Status {
+	case "", entity.ReviewStatusPublished, entity.ReviewStatusHidden, entity.ReviewStatusFlagged:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		return
+	}
+
+	if value := c.Query("rating"); value != "" {
+		rating, err := strconv.Atoi(value)
+		if err != nil || rating < 1 || rating > 5 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rating"})
+			return
+		}
+		filter.Rating = rating
+	}
+
+	page, err := pagination.Parse(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.reviewService.ListReviews(c.Request.Context(), filter, entity.Page{Offset: page.Offset(), Limit: page.Limit()})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get reviews"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ReviewListResponse{
+		Reviews: result.Reviews,
+		Meta:    pagination.NewMeta(c.Request.URL, page, result.Total),
+	})
+}
+
+// DeleteReview обрабатывает DELETE /admin/reviews/:review_id
+// Удаляет любой отзыв магазина, причина удаления обязательна
+func (h *AdminHandler) DeleteReview(c *gin.Context) {
+	var req entity.AdminDeleteReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+
+	err := h.reviewService.AdminDeleteReview(c.Request.Context(), c.Param("review_id"), c.GetString("user_id"), req.Reason)
+	if err != nil {
+		if errors.Is(err, service.ErrReviewNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete review"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.SuccessResponse{
+		Message: "Review deleted successfully",
+	})
+}
+
+// BulkModerate обрабатывает POST /admin/reviews/bulk
+// Отвечает 200 с отчетом по каждому отзыву, даже если часть отзывов обработать не удалось
+func (h *AdminHandler) BulkModerate(c *gin.Context) {
+	var req entity.BulkModerationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+	if req.Action != entity.ModerationActionApprove && req.Reason == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": service.ErrModerationReasonMissing.Error()})
+		return
+	}
+
+	// Удалять отзывы может только admin, manager может публиковать и скрывать
+	if req.Action == entity.ModerationActionDelete && c.GetString("role_name") != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.reviewService.BulkModerate(c.Request.Context(), c.GetString("user_id"), &req))
+}
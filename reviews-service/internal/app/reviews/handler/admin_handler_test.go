@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"augustberries/reviews-service/internal/app/reviews/entity"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (m *MockReviewService) ListReviews(ctx context.Context, filter entity.ReviewFilter, page entity.Page) (*entity.ReviewPage, error) {
+	args := m.Called(ctx, filter, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ReviewPage), args.Error(1)
+}
+
+func (m *MockReviewService) AdminDeleteReview(ctx context.Context, reviewID, moderatorID, reason string) error {
+	args := m.Called(ctx, reviewID, moderatorID, reason)
+	return args.Error(0)
+}
+
+func (m *MockReviewService) BulkModerate(ctx context.Context, moderatorID string, req *entity.BulkModerationRequest) *entity.BulkModerationResponse {
+	args := m.Called(ctx, moderatorID, req)
+	return args.Get(0).(*entity.BulkModerationResponse)
+}
+
+// roleMiddleware устанавливает user_id и role_name в контекст
+func roleMiddleware(userID, role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Set("role_name", role)
+		c.Next()
+	}
+}
+
+// ==================== ListReviews Tests ====================
+
+func TestAdminListReviews_Filters(t *testing.T) {
+	// Arrange
+	mockService := new(MockReviewService)
+	handler := NewAdminHandler(mockService)
+
+	router := setupTestRouter()
+	router.GET("/admin/reviews", handler.ListReviews)
+
+	filter := entity.ReviewFilter{UserID: "user-1", Status: entity.ReviewStatusHidden, Rating: 1}
+	reviews := []entity.Review{{ID: primitive.NewObjectID(), UserID: "user-1", Rating: 1, Status: entity.ReviewStatusHidden}}
+	page := entity.Page{Offset: 0, Limit: 20}
+	mockService.On("ListReviews", mock.Anything, filter, page).Return(&entity.ReviewPage{Reviews: reviews, Total: 1}, nil)
+
+	// Act
+	req, _ := http.NewRequest(http.MethodGet, "/admin/reviews?user_id=user-1&status=hidden&rating=1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response entity.ReviewListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.Total)
+	mockService.AssertExpectations(t)
+}
+
+func TestAdminListReviews_InvalidRating(t *testing.T) {
+	mockService := new(MockReviewService)
+	handler := NewAdminHandler(mockService)
+
+	router := setupTestRouter()
+	router.GET("/admin/reviews", handler.ListReviews)
+
+	req, _ := http.NewRequest(http.MethodGet, "/admin/reviews?rating=6", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// ==================== DeleteReview Tests ====================
+
+func TestAdminDeleteReview_RequiresReason(t *testing.T) {
+	mockService := new(MockReviewService)
+	handler := NewAdminHandler(mockService)
+
+	router := setupTestRouter()
+	router.DELETE("/admin/reviews/:review_id", roleMiddleware("admin-1", "admin"), handler.DeleteReview)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/admin/reviews/review-1", bytes.NewBufferString(`{}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "AdminDeleteReview", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAdminDeleteReview_Success(t *testing.T) {
+	mockService := new(MockReviewService)
+	handler := NewAdminHandler(mockService)
+
+	router := setupTestRouter()
+	router.DELETE("/admin/reviews/:review_id", roleMiddleware("admin-1", "admin"), handler.DeleteReview)
+	mockService.On("AdminDeleteReview", mock.Anything, "review-1", "admin-1", "spam").Return(nil)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/admin/reviews/review-1", bytes.NewBufferString(`{"reason":"spam"}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+// ==================== BulkModerate Tests ====================
+
+func TestAdminBulkModerate_ManagerCannotDelete(t *testing.T) {
+	mockService := new(MockReviewService)
+	handler := NewAdminHandler(mockService)
+
+	router := setupTestRouter()
+	router.POST("/admin/reviews/bulk", roleMiddleware("manager-1", "manager"), handler.BulkModerate)
+
+	body := `{"review_ids":["r1"],"action":"delete","reason":"spam"}`
+	req, _ := http.NewRequest(http.MethodPost, "/admin/reviews/bulk", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockService.AssertNotCalled(t, "BulkModerate", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAdminBulkModerate_Hide(t *testing.T) {
+	mockService := new(MockReviewService)
+	handler := NewAdminHandler(mockService)
+
+	router := setupTestRouter()
+	router.POST("/admin/reviews/bulk", roleMiddleware("manager-1", "manager"), handler.BulkModerate)
+
+	report := &entity.BulkModerationResponse{
+		Results:   []entity.BulkModerationResult{{ReviewID: "r1", Success: true, Status: entity.ReviewStatusHidden}},
+		Succeeded: 1,
+	}
+	mockService.On("BulkModerate", mock.Anything, "manager-1", mock.AnythingOfType("*entity.BulkModerationRequest")).Return(report)
+
+	body := `{"review_ids":["r1"],"action":"hide","reason":"spam"}`
+	req, _ := http.NewRequest(http.MethodPost, "/admin/reviews/bulk", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response entity.BulkModerationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.Succeeded)
+}
@@ -1,8 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
-	"strings"
+
+	"augustberries/pkg/authcookie"
+	"augustberries/pkg/impersonation"
+	"augustberries/pkg/jwtclaims"
+	"augustberries/pkg/tenant"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
@@ -15,12 +20,19 @@ type JWTClaims struct {
 	RoleID      int      `json:"role_id"`
 	RoleName    string   `json:"role_name"`
 	Permissions []string `json:"permissions"`
+	TenantID    string   `json:"tenant_id,omitempty"` // Магазин пользователя (пусто для токенов без тенанта)
+	// TenantRoles - роли пользователя в других магазинах, применяются tenant.Middleware по X-Tenant-ID
+	TenantRoles tenant.Roles `json:"tenant_roles,omitempty"`
+	// Impersonator - сотрудник поддержки, действующий от имени пользователя; nil - обычный токен
+	Impersonator *impersonation.Impersonator `json:"impersonator,omitempty"`
 	jwt.RegisteredClaims
 }
 
 // AuthMiddleware проверяет JWT токен в запросах для Gin
 type AuthMiddleware struct {
 	jwtSecret string
+	cookies   *authcookie.Cookies // nil - токен только в заголовке Authorization
+	expected  jwtclaims.Expected  // Ожидаемые iss и aud токенов, пустые - не проверяются
 }
 
 // NewAuthMiddleware создает новый middleware для аутентификации
@@ -30,34 +42,33 @@ func NewAuthMiddleware(jwtSecret string) *AuthMiddleware {
 	}
 }
 
+// SetCookies включает токен из httpOnly cookie с проверкой CSRF токена у изменяющих запросов
+func (m *AuthMiddleware) SetCookies(cookies *authcookie.Cookies) {
+	m.cookies = cookies
+}
+
+// SetExpectedClaims включает проверку iss и aud: токены другого окружения отклоняются
+func (m *AuthMiddleware) SetExpectedClaims(expected jwtclaims.Expected) {
+	m.expected = expected
+}
+
 // Authenticate проверяет JWT токен и добавляет данные пользователя в контекст Gin
 func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Извлекаем токен из заголовка Authorization
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-			c.Abort()
-			return
-		}
-
-		// Проверяем формат "Bearer <token>"
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
-			c.Abort()
+		// Токен из заголовка Authorization или из cookie
+		tokenString, err := m.cookies.Token(c.Request)
+		if err != nil {
+			abortTokenError(c, err)
 			return
 		}
 
-		tokenString := parts[1]
-
 		// Парсим и валидируем токен
 		token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
 			return []byte(m.jwtSecret), nil
-		})
+		}, m.expected.ParserOptions()...)
 
 		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			c.JSON(http.StatusUnauthorized, gin.H{"error": jwtclaims.Message(err)})
 			c.Abort()
 			return
 		}
@@ -76,8 +87,63 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 		c.Set("role_id", claims.RoleID)
 		c.Set("role_name", claims.RoleName)
 		c.Set("permissions", claims.Permissions)
+		c.Set(tenant.ContextKey, claims.TenantID)
+		c.Set(tenant.RolesKey, claims.TenantRoles)
+
+		// Токен сотрудника поддержки: отметка в ответе и журнале запросов
+		impersonation.Set(c, claims.Impersonator)
 
 		// Передаем управление следующему обработчику
 		c.Next()
 	}
 }
+
+// abortTokenError отвечает на ошибку извлечения токена: 403 для CSRF, иначе 401
+func abortTokenError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, authcookie.ErrCSRF):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid CSRF token"})
+	case errors.Is(err, authcookie.ErrInvalidHeader):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
+	default:
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+	}
+	c.Abort()
+}
+
+// RequireRole проверяет, что у пользователя есть требуемая роль в активном магазине
+// Роль другого магазина подставляет tenant.Middleware, поэтому он должен стоять раньше
+func (m *AuthMiddleware) RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roleName, exists := c.Get("role_name")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			c.Abort()
+			return
+		}
+
+		roleNameStr, ok := roleName.(string)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid role data"})
+			c.Abort()
+			return
+		}
+
+		// Проверяем, есть ли роль пользователя в списке разрешенных
+		hasRole := false
+		for _, role := range roles {
+			if roleNameStr == role {
+				hasRole = true
+				break
+			}
+		}
+
+		if !hasRole {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"augustberries/pkg/pagination"
+	"augustberries/pkg/validate"
+	"augustberries/reviews-service/internal/app/reviews/entity"
+	"augustberries/reviews-service/internal/app/reviews/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReportServiceInterface - методы сервиса жалоб на отзывы
+type ReportServiceInterface interface {
+	ReportReview(ctx context.Context, reviewID, reporterID string, req *entity.ReportReviewRequest) (*entity.ReviewReport, error)
+	ListOpenReports(ctx context.Context) ([]entity.ReviewReport, error)
+	ResolveReport(ctx context.Context, reportID, moderatorID string, req *entity.ResolveReportRequest) (*entity.ResolveReportResponse, error)
+	GetDashboardSummary(ctx context.Context) (*entity.DashboardSummary, error)
+}
+
+// ReportHandler обрабатывает жалобы на отзывы и очередь их рассмотрения
+type ReportHandler struct {
+	reportService ReportServiceInterface
+	validator     *validate.Validator
+}
+
+// NewReportHandler создает обработчик жалоб
+func NewReportHandler(reportService ReportServiceInterface) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+		validator:     validate.New(),
+	}
+}
+
+// ReportReview обрабатывает POST /reviews/:review_id/report
+func (h *ReportHandler) ReportReview(c *gin.Context) {
+	var req entity.ReportReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+
+	report, err := h.reportService.ReportReview(c.Request.Context(), c.Param("review_id"), c.GetString("user_id"), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrReviewNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+		case errors.Is(err, service.ErrAlreadyReported):
+			c.JSON(http.StatusConflict, gin.H{"error": "Review already reported"})
+		case errors.Is(err, service.ErrCannotReportOwnReview):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot report own review"})
+		case errors.Is(err, service.ErrReportRateLimited):
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many reports, try again later"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to report review"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// ListReports обрабатывает GET /admin/reviews/reports?page=&per_page=
+// Возвращает открытые жалобы магазина, старые первыми
+func (h *ReportHandler) ListReports(c *gin.Context) {
+	page, err := pagination.Parse(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	reports, err := h.reportService.ListOpenReports(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get reports"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ReportListResponse{
+		Reports: pagination.Slice(reports, page),
+		Meta:    pagination.NewMeta(c.Request.URL, page, len(reports)),
+	})
+}
+
+// ResolveReport обрабатывает POST /admin/reviews/reports/:report_id/resolve
+func (h *ReportHandler) ResolveReport(c *gin.Context) {
+	var req entity.ResolveReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+
+	// Удалять отзывы может только admin, manager может публиковать и скрывать
+	if req.Action == entity.ModerationActionDelete && c.GetString("role_name") != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		return
+	}
+
+	response, err := h.reportService.ResolveReport(c.Request.Context(), c.Param("report_id"), c.GetString("user_id"), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrReportNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Report not found"})
+		case errors.Is(err, service.ErrReportAlreadyResolved):
+			c.JSON(http.StatusConflict, gin.H{"error": "Report already resolved"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve report"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetDashboardSummary обрабатывает GET /admin/summary
+func (h *ReportHandler) GetDashboardSummary(c *gin.Context) {
+	summary, err := h.reportService.GetDashboardSummary(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get dashboard summary"})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"augustberries/reviews-service/internal/app/reviews/entity"
+	"augustberries/reviews-service/internal/app/reviews/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockReportService реализует ReportServiceInterface для тестирования
+type MockReportService struct {
+	mock.Mock
+}
+
+func (m *MockReportService) ReportReview(ctx context.Context, reviewID, reporterID string, req *entity.ReportReviewRequest) (*entity.ReviewReport, error) {
+	args := m.Called(ctx, reviewID, reporterID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ReviewReport), args.Error(1)
+}
+
+func (m *MockReportService) ListOpenReports(ctx context.Context) ([]entity.ReviewReport, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.ReviewReport), args.Error(1)
+}
+
+func (m *MockReportService) ResolveReport(ctx context.Context, reportID, moderatorID string, req *entity.ResolveReportRequest) (*entity.ResolveReportResponse, error) {
+	args := m.Called(ctx, reportID, moderatorID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ResolveReportResponse), args.Error(1)
+}
+
+func (m *MockReportService) GetDashboardSummary(ctx context.Context) (*entity.DashboardSummary, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.DashboardSummary), args.Error(1)
+}
+
+// ==================== ReportReview Tests ====================
+
+func TestReportReviewHandler_StatusCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"created", nil, http.StatusCreated},
+		{"duplicate", service.ErrAlreadyReported, http.StatusConflict},
+		{"rate limited", service.ErrReportRateLimited, http.StatusTooManyRequests},
+		{"own review", service.ErrCannotReportOwnReview, http.StatusBadRequest},
+		{"not found", service.ErrReviewNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockReportService)
+			handler := NewReportHandler(mockService)
+
+			router := setupTestRouter()
+			router.POST("/reviews/:review_id/report", authMiddleware("reporter"), handler.ReportReview)
+
+			var report *entity.ReviewReport
+			if tt.err == nil {
+				report = &entity.ReviewReport{ReviewID: "r1", Reason: entity.ReportReasonSpam}
+			}
+			mockService.On("ReportReview", mock.Anything, "r1", "reporter", mock.Anything).Return(report, tt.err)
+
+			req, _ := http.NewRequest(http.MethodPost, "/reviews/r1/report", bytes.NewBufferString(`{"reason":"spam"}`))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestReportReviewHandler_InvalidReason(t *testing.T) {
+	mockService := new(MockReportService)
+	handler := NewReportHandler(mockService)
+
+	router := setupTestRouter()
+	router.POST("/reviews/:review_id/report", authMiddleware("reporter"), handler.ReportReview)
+
+	req, _ := http.NewRequest(http.MethodPost, "/reviews/r1/report", bytes.NewBufferString(`{"reason":"boring"}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "ReportReview", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// ==================== ResolveReport Tests ====================
+
+func TestResolveReportHandler_ManagerCannotDelete(t *testing.T) {
+	mockService := new(MockReportService)
+	handler := NewReportHandler(mockService)
+
+	router := setupTestRouter()
+	router.POST("/admin/reviews/reports/:report_id/resolve", roleMiddleware("manager-1", "manager"), handler.ResolveReport)
+
+	req, _ := http.NewRequest(http.MethodPost, "/admin/reviews/reports/rep1/resolve", bytes.NewBufferString(`{"action":"delete"}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -5,35 +5,38 @@ import (
 	"errors"
 	"net/http"
 
+	"augustberries/pkg/pagination"
+	"augustberries/pkg/validate"
 	"augustberries/reviews-service/internal/app/reviews/entity"
 	"augustberries/reviews-service/internal/app/reviews/service"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
 )
 
 // ReviewServiceInterface определяет методы сервиса для dependency injection
 // Позволяет подменять реальный сервис моком в тестах
 type ReviewServiceInterface interface {
 	CreateReview(ctx context.Context, userID string, req *entity.CreateReviewRequest) (*entity.Review, error)
-	GetReviewsByProduct(ctx context.Context, productID string) ([]entity.Review, error)
+	GetReviewsByProduct(ctx context.Context, productID string, page entity.Page) (*entity.ReviewPage, error)
+	GetRatingSummary(ctx context.Context, productID string) (*entity.RatingSummary, error)
 	GetReview(ctx context.Context, reviewID string) (*entity.Review, error)
 	UpdateReview(ctx context.Context, reviewID string, userID string, req *entity.UpdateReviewRequest) (*entity.Review, error)
 	DeleteReview(ctx context.Context, reviewID string, userID string) error
-	GetUserReviews(ctx context.Context, userID string) ([]entity.Review, error)
+	GetReviewHistory(ctx context.Context, reviewID string, userID string, moderator bool) (*entity.ReviewHistory, error)
+	GetUserReviews(ctx context.Context, userID string, page entity.Page) (*entity.ReviewPage, error)
 }
 
 // ReviewHandler обрабатывает HTTP запросы для отзывов с использованием Gin
 type ReviewHandler struct {
 	reviewService ReviewServiceInterface
-	validator     *validator.Validate
+	validator     *validate.Validator
 }
 
 // NewReviewHandler создает новый обработчик отзывов
 func NewReviewHandler(reviewService ReviewServiceInterface) *ReviewHandler {
 	return &ReviewHandler{
 		reviewService: reviewService,
-		validator:     validator.New(),
+		validator:     validate.New(),
 	}
 }
 
@@ -61,13 +64,17 @@ func (h *ReviewHandler) CreateReview(c *gin.Context) {
 
 	// Валидация
 	if err := h.validator.Struct(req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": formatValidationError(err)})
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
 		return
 	}
 
 	// Создаем отзыв
 	review, err := h.reviewService.CreateReview(c.Request.Context(), userIDStr, &req)
 	if err != nil {
+		if errors.Is(err, service.ErrPurchaseRequired) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only customers who received the product can review it"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create review"})
 		return
 	}
@@ -75,8 +82,8 @@ func (h *ReviewHandler) CreateReview(c *gin.Context) {
 	c.JSON(http.StatusCreated, review)
 }
 
-// GetReviewsByProduct обрабатывает GET /reviews/{product_id}
-// Получает все отзывы по товару (используется индекс product_id)
+// GetReviewsByProduct обрабатывает GET /reviews/{product_id}?page=&per_page=
+// Получает страницу отзывов по товару (используется индекс product_id)
 func (h *ReviewHandler) GetReviewsByProduct(c *gin.Context) {
 	productID := c.Param("product_id")
 	if productID == "" {
@@ -84,20 +91,44 @@ func (h *ReviewHandler) GetReviewsByProduct(c *gin.Context) {
 		return
 	}
 
-	reviews, err := h.reviewService.GetReviewsByProduct(c.Request.Context(), productID)
+	page, err := pagination.Parse(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.reviewService.GetReviewsByProduct(c.Request.Context(), productID, entity.Page{Offset: page.Offset(), Limit: page.Limit()})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get reviews"})
 		return
 	}
 
 	response := entity.ReviewListResponse{
-		Reviews: reviews,
-		Total:   len(reviews),
+		Reviews: result.Reviews,
+		Meta:    pagination.NewMeta(c.Request.URL, page, result.Total),
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// GetRatingSummary обрабатывает GET /reviews/product/{product_id}/summary
+// Возвращает среднюю оценку, распределение по оценкам и тональность отзывов товара
+func (h *ReviewHandler) GetRatingSummary(c *gin.Context) {
+	productID := c.Param("product_id")
+	if productID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Product ID is required"})
+		return
+	}
+
+	summary, err := h.reviewService.GetRatingSummary(c.Request.Context(), productID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get rating summary"})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
 // UpdateReview обрабатывает PATCH /reviews/{review_id}
 // Обновляет конкретный отзыв с проверкой прав доступа
 func (h *ReviewHandler) UpdateReview(c *gin.Context) {
@@ -127,6 +158,11 @@ func (h *ReviewHandler) UpdateReview(c *gin.Context) {
 		return
 	}
 
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validate.Message(err)})
+		return
+	}
+
 	// Обновляем отзыв
 	review, err := h.reviewService.UpdateReview(c.Request.Context(), reviewID, userIDStr, &req)
 	if err != nil {
@@ -138,6 +174,10 @@ func (h *ReviewHandler) UpdateReview(c *gin.Context) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 			return
 		}
+		if errors.Is(err, service.ErrEditWindowClosed) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Review can no longer be edited"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update review"})
 		return
 	}
@@ -145,6 +185,35 @@ func (h *ReviewHandler) UpdateReview(c *gin.Context) {
 	c.JSON(http.StatusOK, review)
 }
 
+// GetReviewHistory обрабатывает GET /reviews/{review_id}/history
+// Историю изменений видит автор отзыва и модераторы магазина (manager, admin)
+func (h *ReviewHandler) GetReviewHistory(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	role := c.GetString("role_name")
+	moderator := role == "manager" || role == "admin"
+
+	history, err := h.reviewService.GetReviewHistory(c.Request.Context(), c.Param("review_id"), userID, moderator)
+	if err != nil {
+		if errors.Is(err, service.ErrReviewNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+			return
+		}
+		if errors.Is(err, service.ErrUnauthorized) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get review history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
 // DeleteReview обрабатывает DELETE /reviews/{review_id}
 // Удаляет конкретный отзыв с проверкой прав доступа
 func (h *ReviewHandler) DeleteReview(c *gin.Context) {
@@ -186,13 +255,3 @@ func (h *ReviewHandler) DeleteReview(c *gin.Context) {
 		Message: "Review deleted successfully",
 	})
 }
-
-// formatValidationError форматирует ошибки валидации
-func formatValidationError(err error) string {
-	if validationErrors, ok := err.(validator.ValidationErrors); ok {
-		for _, fieldError := range validationErrors {
-			return fieldError.Field() + " is " + fieldError.Tag()
-		}
-	}
-	return "Validation failed"
-}
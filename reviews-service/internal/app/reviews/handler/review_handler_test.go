@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"augustberries/pkg/patch"
 	"augustberries/reviews-service/internal/app/reviews/entity"
 	"augustberries/reviews-service/internal/app/reviews/service"
 
@@ -32,12 +33,20 @@ func (m *MockReviewService) CreateReview(ctx context.Context, userID string, req
 	return args.Get(0).(*entity.Review), args.Error(1)
 }
 
-func (m *MockReviewService) GetReviewsByProduct(ctx context.Context, productID string) ([]entity.Review, error) {
+func (m *MockReviewService) GetReviewsByProduct(ctx context.Context, productID string, page entity.Page) (*entity.ReviewPage, error) {
+	args := m.Called(ctx, productID, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ReviewPage), args.Error(1)
+}
+
+func (m *MockReviewService) GetRatingSummary(ctx context.Context, productID string) (*entity.RatingSummary, error) {
 	args := m.Called(ctx, productID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]entity.Review), args.Error(1)
+	return args.Get(0).(*entity.RatingSummary), args.Error(1)
 }
 
 func (m *MockReviewService) GetReview(ctx context.Context, reviewID string) (*entity.Review, error) {
@@ -61,12 +70,20 @@ func (m *MockReviewService) DeleteReview(ctx context.Context, reviewID string, u
 	return args.Error(0)
 }
 
-func (m *MockReviewService) GetUserReviews(ctx context.Context, userID string) ([]entity.Review, error) {
-	args := m.Called(ctx, userID)
+func (m *MockReviewService) GetReviewHistory(ctx context.Context, reviewID string, userID string, moderator bool) (*entity.ReviewHistory, error) {
+	args := m.Called(ctx, reviewID, userID, moderator)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ReviewHistory), args.Error(1)
+}
+
+func (m *MockReviewService) GetUserReviews(ctx context.Context, userID string, page entity.Page) (*entity.ReviewPage, error) {
+	args := m.Called(ctx, userID, page)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]entity.Review), args.Error(1)
+	return args.Get(0).(*entity.ReviewPage), args.Error(1)
 }
 
 func setupTestRouter() *gin.Engine {
@@ -235,6 +252,30 @@ func TestCreateReviewHandler_ServiceError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
 
+func TestCreateReviewHandler_PurchaseRequired(t *testing.T) {
+	// Arrange
+	mockService := new(MockReviewService)
+	handler := NewReviewHandler(mockService)
+
+	router := setupTestRouter()
+	userID := "user-123"
+
+	mockService.On("CreateReview", mock.Anything, userID, mock.Anything).Return(nil, service.ErrPurchaseRequired)
+
+	router.POST("/reviews", authMiddleware(userID), handler.CreateReview)
+
+	// Act
+	body, _ := json.Marshal(entity.CreateReviewRequest{ProductID: "product-456", Rating: 5, Text: "Отличный товар!"})
+	req, _ := http.NewRequest(http.MethodPost, "/reviews", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
 // ===================== GetReviewsByProduct Tests =====================
 
 func TestGetReviewsByProductHandler_Success(t *testing.T) {
@@ -250,7 +291,7 @@ func TestGetReviewsByProductHandler_Success(t *testing.T) {
 		{ID: primitive.NewObjectID(), ProductID: productID, Rating: 4, Text: "Хорошо!"},
 	}
 
-	mockService.On("GetReviewsByProduct", mock.Anything, productID).Return(reviews, nil)
+	mockService.On("GetReviewsByProduct", mock.Anything, productID, entity.Page{Offset: 0, Limit: 20}).Return(&entity.ReviewPage{Reviews: reviews, Total: 2}, nil)
 
 	router.GET("/reviews/product/:product_id", handler.GetReviewsByProduct)
 
@@ -278,7 +319,7 @@ func TestGetReviewsByProductHandler_Empty(t *testing.T) {
 	router := setupTestRouter()
 	productID := "product-no-reviews"
 
-	mockService.On("GetReviewsByProduct", mock.Anything, productID).Return([]entity.Review{}, nil)
+	mockService.On("GetReviewsByProduct", mock.Anything, productID, mock.Anything).Return(&entity.ReviewPage{Reviews: []entity.Review{}}, nil)
 
 	router.GET("/reviews/product/:product_id", handler.GetReviewsByProduct)
 
@@ -295,6 +336,57 @@ func TestGetReviewsByProductHandler_Empty(t *testing.T) {
 	assert.Equal(t, 0, response.Total)
 }
 
+func TestGetReviewsByProductHandler_Pagination(t *testing.T) {
+	// Arrange
+	mockService := new(MockReviewService)
+	handler := NewReviewHandler(mockService)
+
+	router := setupTestRouter()
+	productID := "product-paged"
+
+	reviews := make([]entity.Review, 5)
+	for i := range reviews {
+		reviews[i] = entity.Review{ID: primitive.NewObjectID(), ProductID: productID, Rating: 5, Text: "Отличный товар"}
+	}
+
+	// Страницу выбирает репозиторий, handler передает смещение и размер страницы
+	mockService.On("GetReviewsByProduct", mock.Anything, productID, entity.Page{Offset: 2, Limit: 2}).
+		Return(&entity.ReviewPage{Reviews: reviews[2:4], Total: len(reviews)}, nil)
+
+	router.GET("/reviews/product/:product_id", handler.GetReviewsByProduct)
+
+	// Act
+	req, _ := http.NewRequest(http.MethodGet, "/reviews/product/"+productID+"?page=2&per_page=2", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response entity.ReviewListResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, 5, response.Total)
+	assert.Equal(t, 3, response.TotalPages)
+	assert.Equal(t, []entity.Review{reviews[2], reviews[3]}, response.Reviews)
+	assert.Equal(t, "/reviews/product/"+productID+"?page=3&per_page=2", response.Links.Next)
+	assert.Equal(t, "/reviews/product/"+productID+"?page=1&per_page=2", response.Links.Prev)
+}
+
+func TestGetReviewsByProductHandler_InvalidPage(t *testing.T) {
+	mockService := new(MockReviewService)
+	handler := NewReviewHandler(mockService)
+
+	router := setupTestRouter()
+	router.GET("/reviews/product/:product_id", handler.GetReviewsByProduct)
+
+	req, _ := http.NewRequest(http.MethodGet, "/reviews/product/p1?page=0", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "GetReviewsByProduct", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestGetReviewsByProductHandler_ServiceError(t *testing.T) {
 	// Arrange
 	mockService := new(MockReviewService)
@@ -303,7 +395,7 @@ func TestGetReviewsByProductHandler_ServiceError(t *testing.T) {
 	router := setupTestRouter()
 	productID := "product-456"
 
-	mockService.On("GetReviewsByProduct", mock.Anything, productID).Return(nil, errors.New("db error"))
+	mockService.On("GetReviewsByProduct", mock.Anything, productID, mock.Anything).Return(nil, errors.New("db error"))
 
 	router.GET("/reviews/product/:product_id", handler.GetReviewsByProduct)
 
@@ -316,6 +408,55 @@ func TestGetReviewsByProductHandler_ServiceError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
 
+// ===================== GetRatingSummary Tests =====================
+
+func TestGetRatingSummaryHandler_Success(t *testing.T) {
+	// Arrange
+	mockService := new(MockReviewService)
+	handler := NewReviewHandler(mockService)
+
+	router := setupTestRouter()
+	productID := "product-456"
+
+	summary := &entity.RatingSummary{
+		ProductID:    productID,
+		Count:        3,
+		Average:      4,
+		Distribution: map[int]int{1: 0, 2: 0, 3: 1, 4: 1, 5: 1},
+		Sentiment:    entity.SentimentSummary{Analyzed: 2, AverageScore: 0.5, Positive: 1, Neutral: 1},
+	}
+	mockService.On("GetRatingSummary", mock.Anything, productID).Return(summary, nil)
+
+	router.GET("/reviews/product/:product_id/summary", handler.GetRatingSummary)
+
+	// Act
+	req, _ := http.NewRequest(http.MethodGet, "/reviews/product/"+productID+"/summary", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response entity.RatingSummary
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, *summary, response)
+}
+
+func TestGetRatingSummaryHandler_ServiceError(t *testing.T) {
+	mockService := new(MockReviewService)
+	handler := NewReviewHandler(mockService)
+
+	router := setupTestRouter()
+	mockService.On("GetRatingSummary", mock.Anything, "p1").Return(nil, errors.New("db error"))
+	router.GET("/reviews/product/:product_id/summary", handler.GetRatingSummary)
+
+	req, _ := http.NewRequest(http.MethodGet, "/reviews/product/p1/summary", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
 // ===================== UpdateReview Tests =====================
 
 func TestUpdateReviewHandler_Success(t *testing.T) {
@@ -339,7 +480,7 @@ func TestUpdateReviewHandler_Success(t *testing.T) {
 	router.PATCH("/reviews/:review_id", authMiddleware(userID), handler.UpdateReview)
 
 	// Act
-	reqBody := entity.UpdateReviewRequest{Rating: 5, Text: "Обновлённый отзыв!"}
+	reqBody := entity.UpdateReviewRequest{Rating: patch.Of(5), Text: patch.Of("Обновлённый отзыв!")}
 	body, _ := json.Marshal(reqBody)
 
 	req, _ := http.NewRequest(http.MethodPatch, "/reviews/"+reviewID.Hex(), bytes.NewBuffer(body))
@@ -372,7 +513,7 @@ func TestUpdateReviewHandler_NotFound(t *testing.T) {
 	router.PATCH("/reviews/:review_id", authMiddleware(userID), handler.UpdateReview)
 
 	// Act
-	reqBody := entity.UpdateReviewRequest{Rating: 5}
+	reqBody := entity.UpdateReviewRequest{Rating: patch.Of(5)}
 	body, _ := json.Marshal(reqBody)
 
 	req, _ := http.NewRequest(http.MethodPatch, "/reviews/"+reviewID.Hex(), bytes.NewBuffer(body))
@@ -399,7 +540,7 @@ func TestUpdateReviewHandler_Forbidden(t *testing.T) {
 	router.PATCH("/reviews/:review_id", authMiddleware(userID), handler.UpdateReview)
 
 	// Act
-	reqBody := entity.UpdateReviewRequest{Rating: 1}
+	reqBody := entity.UpdateReviewRequest{Rating: patch.Of(1)}
 	body, _ := json.Marshal(reqBody)
 
 	req, _ := http.NewRequest(http.MethodPatch, "/reviews/"+reviewID.Hex(), bytes.NewBuffer(body))
@@ -424,7 +565,7 @@ func TestUpdateReviewHandler_Unauthorized(t *testing.T) {
 	router.PATCH("/reviews/:review_id", handler.UpdateReview)
 
 	// Act
-	reqBody := entity.UpdateReviewRequest{Rating: 5}
+	reqBody := entity.UpdateReviewRequest{Rating: patch.Of(5)}
 	body, _ := json.Marshal(reqBody)
 
 	req, _ := http.NewRequest(http.MethodPatch, "/reviews/"+reviewID.Hex(), bytes.NewBuffer(body))
@@ -437,6 +578,93 @@ func TestUpdateReviewHandler_Unauthorized(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
+func TestUpdateReviewHandler_EditWindowClosed(t *testing.T) {
+	// Arrange
+	mockService := new(MockReviewService)
+	handler := NewReviewHandler(mockService)
+
+	router := setupTestRouter()
+	userID := "user-123"
+	reviewID := primitive.NewObjectID()
+
+	mockService.On("UpdateReview", mock.Anything, reviewID.Hex(), userID, mock.Anything).Return(nil, service.ErrEditWindowClosed)
+
+	router.PATCH("/reviews/:review_id", authMiddleware(userID), handler.UpdateReview)
+
+	// Act
+	body, _ := json.Marshal(entity.UpdateReviewRequest{Rating: patch.Of(2)})
+	req, _ := http.NewRequest(http.MethodPatch, "/reviews/"+reviewID.Hex(), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "no longer be edited")
+}
+
+// ===================== GetReviewHistory Tests =====================
+
+func TestGetReviewHistoryHandler_Success(t *testing.T) {
+	// Arrange
+	mockService := new(MockReviewService)
+	handler := NewReviewHandler(mockService)
+
+	router := setupTestRouter()
+	reviewID := primitive.NewObjectID()
+	history := &entity.ReviewHistory{
+		ReviewID: reviewID.Hex(),
+		Current:  entity.ReviewVersion{Rating: 5, Text: "New text"},
+		Versions: []entity.ReviewVersion{{Rating: 2, Text: "Old text"}},
+	}
+
+	// Модератор видит историю чужого отзыва
+	mockService.On("GetReviewHistory", mock.Anything, reviewID.Hex(), "manager-1", true).Return(history, nil)
+
+	router.GET("/reviews/:review_id/history", func(c *gin.Context) {
+		c.Set("user_id", "manager-1")
+		c.Set("role_name", "manager")
+		c.Next()
+	}, handler.GetReviewHistory)
+
+	// Act
+	req, _ := http.NewRequest(http.MethodGet, "/reviews/"+reviewID.Hex()+"/history", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response entity.ReviewHistory
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response.Versions, 1)
+	assert.Equal(t, "Old text", response.Versions[0].Text)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetReviewHistoryHandler_Forbidden(t *testing.T) {
+	// Arrange
+	mockService := new(MockReviewService)
+	handler := NewReviewHandler(mockService)
+
+	router := setupTestRouter()
+	reviewID := primitive.NewObjectID()
+
+	mockService.On("GetReviewHistory", mock.Anything, reviewID.Hex(), "user-123", false).Return(nil, service.ErrUnauthorized)
+
+	router.GET("/reviews/:review_id/history", authMiddleware("user-123"), handler.GetReviewHistory)
+
+	// Act
+	req, _ := http.NewRequest(http.MethodGet, "/reviews/"+reviewID.Hex()+"/history", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
 // ===================== DeleteReview Tests =====================
 
 func TestDeleteReviewHandler_Success(t *testing.T) {
@@ -6,38 +6,78 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"augustberries/pkg/buildinfo"
+	"augustberries/pkg/featureflags"
+	"augustberries/pkg/httpmw"
+	"augustberries/pkg/impersonation"
 	"augustberries/pkg/metrics"
+	"augustberries/pkg/recovery"
+	"augustberries/pkg/tenant"
 )
 
 // SetupRoutes настраивает все маршруты Reviews Service с использованием Gin
-// Применяет Auth middleware для защиты эндпоинтов
-func SetupRoutes(reviewHandler *ReviewHandler, authMiddleware *AuthMiddleware) *gin.Engine {
-	router := gin.Default()
+// Применяет Auth middleware для защиты эндпоинтов и Tenant middleware для изоляции данных магазинов
+func SetupRoutes(reviewHandler *ReviewHandler, adminHandler *AdminHandler, reportHandler *ReportHandler, flagsHandler *featureflags.Handler, authMiddleware *AuthMiddleware, cors httpmw.CORSConfig, compression httpmw.CompressConfig) *gin.Engine {
+	router := gin.New()
+	router.Use(gin.Logger(), recovery.Middleware("reviews-service"), impersonation.AuditMiddleware("reviews-service"))
 
 	// Prometheus metrics middleware
 	router.Use(metrics.GinPrometheusMiddleware("reviews-service"))
 
+	// CORS для браузерных клиентов (CORS_ALLOWED_ORIGINS и др.), preflight запросы не доходят до маршрутов
+	router.Use(httpmw.CORS(cors))
+
+	// Сжатие ответов больших списков (gzip, zstd) по Accept-Encoding
+	compress := httpmw.Compress("reviews-service", compression)
+
 	// Health check endpoint - публичный, без аутентификации
+	build := buildinfo.Get("reviews-service")
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
 			"status":  "ok",
 			"service": "reviews-service",
+			"build":   build,
 		})
 	})
 
+	// Сведения о сборке (коммит, время сборки, версия Go) - публичный
+	router.GET("/version", gin.WrapF(buildinfo.Handler("reviews-service")))
+
 	// Prometheus metrics endpoint
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
 	// Reviews endpoints - все требуют аутентификации
 	reviews := router.Group("/reviews")
-	reviews.Use(authMiddleware.Authenticate()) // Все маршруты требуют JWT токен
+	reviews.Use(authMiddleware.Authenticate(), tenant.Middleware()) // Все маршруты требуют JWT токен
 	{
 		// Базовые операции с отзывами
-		reviews.POST("/", reviewHandler.CreateReview)                          // Создать отзыв
-		reviews.GET("/product/:product_id", reviewHandler.GetReviewsByProduct) // Получить все отзывы по товару (используется индекс)
-		reviews.PATCH("/:review_id", reviewHandler.UpdateReview)               // Обновить конкретный отзыв
-		reviews.DELETE("/:review_id", reviewHandler.DeleteReview)              // Удалить конкретный отзыв
+		reviews.POST("/", reviewHandler.CreateReview)                                    // Создать отзыв
+		reviews.GET("/product/:product_id", compress, reviewHandler.GetReviewsByProduct) // Получить все отзывы по товару (используется индекс)
+		reviews.GET("/product/:product_id/summary", reviewHandler.GetRatingSummary)      // Средняя оценка, распределение и тональность отзывов
+		reviews.PATCH("/:review_id", reviewHandler.UpdateReview)                         // Обновить конкретный отзыв
+		reviews.DELETE("/:review_id", reviewHandler.DeleteReview)                        // Удалить конкретный отзыв
+		reviews.GET("/:review_id/history", reviewHandler.GetReviewHistory)               // История изменений (автор и модераторы)
+		reviews.POST("/:review_id/report", reportHandler.ReportReview)                   // Пожаловаться на отзыв
+	}
+
+	// Admin endpoints - модерация отзывов всех пользователей магазина
+	admin := router.Group("/admin/reviews")
+	admin.Use(authMiddleware.Authenticate(), tenant.Middleware(), authMiddleware.RequireRole("manager", "admin"))
+	{
+		admin.GET("", compress, adminHandler.ListReviews)                                           // Отзывы по всем товарам с фильтрами
+		admin.GET("/reports", compress, reportHandler.ListReports)                                  // Очередь открытых жалоб
+		admin.POST("/reports/:report_id/resolve", reportHandler.ResolveReport)                      // Решение по жалобе для всех жалоб на отзыв
+		admin.POST("/bulk", adminHandler.BulkModerate)                                              // Массовая публикация, скрытие или удаление
+		admin.DELETE("/:review_id", authMiddleware.RequireRole("admin"), adminHandler.DeleteReview) // Удалить отзыв с причиной (только admin)
 	}
 
+	// Сводка модерации для панели администратора
+	router.GET("/admin/summary", authMiddleware.Authenticate(), tenant.Middleware(), authMiddleware.RequireRole("manager", "admin"), reportHandler.GetDashboardSummary)
+
+	// Флаги функциональности, в том числе выключатель внешней модерации (только admin)
+	flags := router.Group("/admin/flags")
+	flags.Use(authMiddleware.Authenticate(), authMiddleware.RequireRole("admin"))
+	flagsHandler.RegisterRoutes(flags)
+
 	return router
 }
@@ -0,0 +1,58 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"augustberries/pkg/clients"
+	"augustberries/pkg/clients/catalog"
+	"augustberries/reviews-service/internal/app/reviews/entity"
+
+	"github.com/google/uuid"
+)
+
+// HTTPCatalogClientConfig - настройки клиента внутреннего API Catalog Service
+type HTTPCatalogClientConfig struct {
+	BaseURL string
+	Token   string // Должен совпадать с INTERNAL_API_TOKEN Catalog Service
+	Timeout time.Duration
+}
+
+// HTTPCatalogClient вызывает внутренний API Catalog Service /internal/products/ratings
+// от имени магазина из контекста
+type HTTPCatalogClient struct {
+	catalog *catalog.Client
+}
+
+func NewHTTPCatalogClient(cfg HTTPCatalogClientConfig) *HTTPCatalogClient {
+	clientCfg := clients.DefaultConfig(cfg.BaseURL)
+	clientCfg.Timeout = cfg.Timeout
+	clientCfg.InternalToken = cfg.Token
+	return &HTTPCatalogClient{catalog: catalog.New(clientCfg)}
+}
+
+func (c *HTTPCatalogClient) GetProductRatings(ctx context.Context) ([]entity.ProductRating, error) {
+	ratings, err := c.catalog.GetProductRatings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]entity.ProductRating, len(ratings))
+	for i, rating := range ratings {
+		result[i] = entity.ProductRating{ProductID: rating.ProductID.String(), RatingAvg: rating.RatingAvg, RatingCount: rating.RatingCount}
+	}
+	return result, nil
+}
+
+func (c *HTTPCatalogClient) UpdateProductRatings(ctx context.Context, ratings []entity.ProductRating) error {
+	updates := make([]catalog.ProductRating, len(ratings))
+	for i, rating := range ratings {
+		productID, err := uuid.Parse(rating.ProductID)
+		if err != nil {
+			return fmt.Errorf("invalid product id %q: %w", rating.ProductID, err)
+		}
+		updates[i] = catalog.ProductRating{ProductID: productID, RatingAvg: rating.RatingAvg, RatingCount: rating.RatingCount}
+	}
+	return c.catalog.UpdateProductRatings(ctx, updates)
+}
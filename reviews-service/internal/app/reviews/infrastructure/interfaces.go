@@ -1,6 +1,10 @@
 package infrastructure
 
-import "context"
+import (
+	"context"
+
+	"augustberries/reviews-service/internal/app/reviews/entity"
+)
 
 // MessagePublisher интерфейс для отправки сообщений в очередь (Kafka)
 // Используется для dependency injection и упрощения тестирования
@@ -8,3 +12,29 @@ type MessagePublisher interface {
 	PublishMessage(ctx context.Context, key string, value []byte) error
 	Close() error
 }
+
+// SentimentAnalyzer оценивает тональность текста отзыва
+// Реализации: локальный словарный анализатор и внешний HTTP API
+type SentimentAnalyzer interface {
+	// Name возвращает имя анализатора, сохраняется вместе с оценкой
+	Name() string
+	// Analyze возвращает оценку от -1 (негативный текст) до 1 (позитивный)
+	Analyze(ctx context.Context, text string) (float64, error)
+}
+
+// ModerationProvider оценивает токсичность текста отзыва во внешнем сервисе модерации
+type ModerationProvider interface {
+	// Name возвращает имя сервиса, сохраняется вместе с оценкой
+	Name() string
+	// Toxicity возвращает вероятность того, что текст токсичен, от 0 до 1
+	Toxicity(ctx context.Context, text string) (float64, error)
+}
+
+// CatalogClient - внутренний API Catalog Service для сверки денормализованных оценок товаров
+// Магазин запроса берется из контекста
+type CatalogClient interface {
+	// GetProductRatings возвращает оценки всех товаров магазина, сохраненные в каталоге
+	GetProductRatings(ctx context.Context) ([]entity.ProductRating, error)
+	// UpdateProductRatings исправляет оценки товаров в каталоге
+	UpdateProductRatings(ctx context.Context, ratings []entity.ProductRating) error
+}
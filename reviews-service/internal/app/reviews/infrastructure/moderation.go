@@ -0,0 +1,77 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HTTPModerationProviderConfig - настройки внешнего API модерации
+type HTTPModerationProviderConfig struct {
+	URL     string
+	APIKey  string        // Передается в заголовке Authorization: Bearer, пустой - без авторизации
+	Timeout time.Duration // Таймаут одной попытки
+}
+
+// HTTPModerationProvider вызывает внешний API оценки токсичности (в духе Perspective API)
+// Запрос: POST {"text": "..."}; ответ: {"toxicity": 0..1}
+type HTTPModerationProvider struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+func NewHTTPModerationProvider(cfg HTTPModerationProviderConfig) *HTTPModerationProvider {
+	return &HTTPModerationProvider{
+		url:    cfg.URL,
+		apiKey: cfg.APIKey,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+func (p *HTTPModerationProvider) Name() string {
+	return "http"
+}
+
+func (p *HTTPModerationProvider) Toxicity(ctx context.Context, text string) (float64, error) {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal moderation request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call moderation API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return 0, fmt.Errorf("moderation API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var result struct {
+		Toxicity *float64 `json:"toxicity"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("failed to decode moderation response: %w", err)
+	}
+	if result.Toxicity == nil || *result.Toxicity < 0 || *result.Toxicity > 1 {
+		return 0, fmt.Errorf("moderation API returned invalid toxicity")
+	}
+	return *result.Toxicity, nil
+}
@@ -0,0 +1,81 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ==================== HTTP Moderation Provider Tests ====================
+
+func TestHTTPModerationProvider_Toxicity(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "Ужасный продавец", body["text"])
+		w.Write([]byte(`{"toxicity": 0.85}`))
+	}))
+	defer server.Close()
+
+	provider := NewHTTPModerationProvider(HTTPModerationProviderConfig{URL: server.URL, APIKey: "secret", Timeout: time.Second})
+
+	// Act
+	toxicity, err := provider.Toxicity(context.Background(), "Ужасный продавец")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 0.85, toxicity)
+}
+
+func TestHTTPModerationProvider_RejectsInvalidResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"server error", http.StatusServiceUnavailable, `{"error":"overloaded"}`},
+		{"toxicity out of range", http.StatusOK, `{"toxicity": 1.5}`},
+		{"missing toxicity", http.StatusOK, `{}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			provider := NewHTTPModerationProvider(HTTPModerationProviderConfig{URL: server.URL, Timeout: time.Second})
+			_, err := provider.Toxicity(context.Background(), "text")
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestHTTPModerationProvider_Timeout(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte(`{"toxicity": 0.1}`))
+	}))
+	defer server.Close()
+
+	provider := NewHTTPModerationProvider(HTTPModerationProviderConfig{URL: server.URL, Timeout: 20 * time.Millisecond})
+
+	// Act
+	_, err := provider.Toxicity(context.Background(), "text")
+
+	// Assert
+	assert.Error(t, err)
+}
@@ -0,0 +1,149 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Основы слов словарного анализатора (русский и английский)
+// Слово считается совпавшим, если начинается с основы
+var (
+	positiveStems = []string{
+		"отличн", "хорош", "прекрасн", "замечательн", "великолепн", "супер", "рекоменд", "доволен", "довольн",
+		"нрав", "понрав", "вкусн", "свеж", "качествен", "удобн", "быстр", "спасибо", "люблю", "лучш", "идеальн", "совету",
+		"good", "great", "excellent", "love", "perfect", "amazing", "recommend", "fresh", "tasty", "best",
+	}
+	negativeStems = []string{
+		"плох", "ужасн", "отвратительн", "разочаров", "гнил", "испорч", "брак", "сломан", "недовол", "невкусн",
+		"кошмар", "обман", "мят", "хуж", "возврат", "жалоб",
+		"bad", "terrible", "awful", "rotten", "broken", "disappoint", "worst", "refund", "poor",
+	}
+	// negations - частицы, меняющие тональность следующего слова
+	negations = map[string]bool{"не": true, "нет": true, "ни": true, "not": true, "no": true, "never": true}
+)
+
+// LexiconSentimentAnalyzer - локальный словарный анализатор без внешних зависимостей
+// Считает позитивные и негативные слова с учетом отрицания перед словом
+type LexiconSentimentAnalyzer struct{}
+
+func NewLexiconSentimentAnalyzer() *LexiconSentimentAnalyzer {
+	return &LexiconSentimentAnalyzer{}
+}
+
+func (a *LexiconSentimentAnalyzer) Name() string {
+	return "lexicon"
+}
+
+// Analyze возвращает (позитивные - негативные) / (позитивные + негативные), 0 - нет оценочных слов
+func (a *LexiconSentimentAnalyzer) Analyze(_ context.Context, text string) (float64, error) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var positive, negative int
+	for i, word := range words {
+		polarity := 0
+		if hasStem(word, positiveStems) {
+			polarity = 1
+		} else if hasStem(word, negativeStems) {
+			polarity = -1
+		}
+		if polarity == 0 {
+			continue
+		}
+		if i > 0 && negations[words[i-1]] {
+			polarity = -polarity
+		}
+		if polarity > 0 {
+			positive++
+		} else {
+			negative++
+		}
+	}
+
+	if positive+negative == 0 {
+		return 0, nil
+	}
+	return float64(positive-negative) / float64(positive+negative), nil
+}
+
+func hasStem(word string, stems []string) bool {
+	for _, stem := range stems {
+		if strings.HasPrefix(word, stem) {
+			return true
+		}
+	}
+	return false
+}
+
+// HTTPSentimentAnalyzerConfig - настройки внешнего API анализа тональности
+type HTTPSentimentAnalyzerConfig struct {
+	URL     string
+	APIKey  string // Передается в заголовке Authorization: Bearer, пустой - без авторизации
+	Timeout time.Duration
+}
+
+// HTTPSentimentAnalyzer вызывает внешний API анализа тональности
+// Запрос: POST {"text": "..."}; ответ: {"score": -1..1}
+type HTTPSentimentAnalyzer struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+func NewHTTPSentimentAnalyzer(cfg HTTPSentimentAnalyzerConfig) *HTTPSentimentAnalyzer {
+	return &HTTPSentimentAnalyzer{
+		url:    cfg.URL,
+		apiKey: cfg.APIKey,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+func (a *HTTPSentimentAnalyzer) Name() string {
+	return "http"
+}
+
+func (a *HTTPSentimentAnalyzer) Analyze(ctx context.Context, text string) (float64, error) {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal sentiment request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if a.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+a.apiKey)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call sentiment API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return 0, fmt.Errorf("sentiment API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var result struct {
+		Score *float64 `json:"score"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("failed to decode sentiment response: %w", err)
+	}
+	if result.Score == nil || *result.Score < -1 || *result.Score > 1 {
+		return 0, fmt.Errorf("sentiment API returned invalid score")
+	}
+	return *result.Score, nil
+}
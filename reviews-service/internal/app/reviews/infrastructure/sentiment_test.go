@@ -0,0 +1,90 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ==================== Lexicon Analyzer Tests ====================
+
+func TestLexiconSentimentAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want float64
+	}{
+		{"positive", "Отличная клубника, очень свежая и вкусная!", 1},
+		{"negative", "Ягоды пришли гнилые, ужасное качество упаковки", -1},
+		{"negation flips polarity", "Не понравилось, не советую", -1},
+		{"mixed", "Вкусно, но коробка мятая", 0},
+		{"no opinion words", "Заказ пришел во вторник", 0},
+		{"english", "Great taste, would recommend", 1},
+	}
+
+	analyzer := NewLexiconSentimentAnalyzer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, err := analyzer.Analyze(context.Background(), tt.text)
+
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want, score, 0.001)
+		})
+	}
+}
+
+// ==================== HTTP Analyzer Tests ====================
+
+func TestHTTPSentimentAnalyzer_Analyze(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "Отличный товар", body["text"])
+		w.Write([]byte(`{"score": 0.7}`))
+	}))
+	defer server.Close()
+
+	analyzer := NewHTTPSentimentAnalyzer(HTTPSentimentAnalyzerConfig{URL: server.URL, APIKey: "secret", Timeout: time.Second})
+
+	// Act
+	score, err := analyzer.Analyze(context.Background(), "Отличный товар")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 0.7, score)
+}
+
+func TestHTTPSentimentAnalyzer_RejectsInvalidResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"server error", http.StatusServiceUnavailable, `{"error":"overloaded"}`},
+		{"score out of range", http.StatusOK, `{"score": 3}`},
+		{"missing score", http.StatusOK, `{}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			analyzer := NewHTTPSentimentAnalyzer(HTTPSentimentAnalyzerConfig{URL: server.URL, Timeout: time.Second})
+			_, err := analyzer.Analyze(context.Background(), "text")
+
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"augustberries/pkg/kafka"
+	"augustberries/reviews-service/internal/app/reviews/entity"
+)
+
+// PurchaseRecorder применяет события заказов к доставленным покупкам
+type PurchaseRecorder interface {
+	ApplyOrderEvent(ctx context.Context, event *entity.OrderEvent) error
+}
+
+// OrderEventsConsumer читает топик order_events Orders Service и отмечает доставленные покупки
+type OrderEventsConsumer struct {
+	consumer  kafka.Consumer
+	purchases PurchaseRecorder
+	stopChan  chan struct{}
+	doneChan  chan struct{}
+}
+
+func NewOrderEventsConsumer(consumer kafka.Consumer, purchases PurchaseRecorder) *OrderEventsConsumer {
+	return &OrderEventsConsumer{
+		consumer:  consumer,
+		purchases: purchases,
+		stopChan:  make(chan struct{}),
+		doneChan:  make(chan struct{}),
+	}
+}
+
+func (c *OrderEventsConsumer) Start(ctx context.Context) {
+	runCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		<-c.stopChan
+		cancel()
+	}()
+
+	go func() {
+		defer close(c.doneChan)
+		handler := kafka.Retry(c.processMessage, kafka.DefaultRetryPolicy)
+		if err := c.consumer.Run(runCtx, handler); err != nil {
+			log.Printf("Order events consumer stopped with error: %v", err)
+		}
+	}()
+}
+
+func (c *OrderEventsConsumer) Stop() {
+	close(c.stopChan)
+	<-c.doneChan
+	c.consumer.Close()
+}
+
+func (c *OrderEventsConsumer) processMessage(ctx context.Context, message kafka.Message) error {
+	// В топике заказов есть и другие события, их не нужно разбирать
+	if meta := kafka.MetaFromHeaders(message.Headers); meta.Type != "" && meta.Type != entity.EventTypeOrderDelivered {
+		return nil
+	}
+
+	var event entity.OrderEvent
+	if err := kafka.Decode(kafka.JSONCodec{}, message, &event); err != nil {
+		return kafka.Permanent(fmt.Errorf("failed to unmarshal order event: %w", err))
+	}
+
+	return c.purchases.ApplyOrderEvent(ctx, &event)
+}
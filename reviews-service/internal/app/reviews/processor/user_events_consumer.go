@@ -0,0 +1,63 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"augustberries/pkg/kafka"
+	"augustberries/reviews-service/internal/app/reviews/entity"
+)
+
+// ProfileUpdater применяет события пользователей к локальным профилям авторов
+type ProfileUpdater interface {
+	ApplyUserEvent(ctx context.Context, event *entity.UserEvent) error
+}
+
+// UserEventsConsumer читает топик user_events Auth Service и обновляет профили авторов отзывов
+type UserEventsConsumer struct {
+	consumer kafka.Consumer
+	profiles ProfileUpdater
+	stopChan chan struct{}
+	doneChan chan struct{}
+}
+
+func NewUserEventsConsumer(consumer kafka.Consumer, profiles ProfileUpdater) *UserEventsConsumer {
+	return &UserEventsConsumer{
+		consumer: consumer,
+		profiles: profiles,
+		stopChan: make(chan struct{}),
+		doneChan: make(chan struct{}),
+	}
+}
+
+func (c *UserEventsConsumer) Start(ctx context.Context) {
+	runCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		<-c.stopChan
+		cancel()
+	}()
+
+	go func() {
+		defer close(c.doneChan)
+		handler := kafka.Retry(c.processMessage, kafka.DefaultRetryPolicy)
+		if err := c.consumer.Run(runCtx, handler); err != nil {
+			log.Printf("User events consumer stopped with error: %v", err)
+		}
+	}()
+}
+
+func (c *UserEventsConsumer) Stop() {
+	close(c.stopChan)
+	<-c.doneChan
+	c.consumer.Close()
+}
+
+func (c *UserEventsConsumer) processMessage(ctx context.Context, message kafka.Message) error {
+	var event entity.UserEvent
+	if err := kafka.Decode(kafka.JSONCodec{}, message, &event); err != nil {
+		return kafka.Permanent(fmt.Errorf("failed to unmarshal user event: %w", err))
+	}
+
+	return c.profiles.ApplyUserEvent(ctx, &event)
+}
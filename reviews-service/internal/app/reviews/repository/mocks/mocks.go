@@ -2,15 +2,24 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"augustberries/reviews-service/internal/app/reviews/entity"
 
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // MockReviewRepository мок для ReviewRepository
 type MockReviewRepository struct {
 	mock.Mock
+	Transactions int // Число вызовов WithTransaction
+}
+
+// WithTransaction вызывает fn напрямую и считает транзакции
+func (m *MockReviewRepository) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.Transactions++
+	return fn(ctx)
 }
 
 func (m *MockReviewRepository) Create(ctx context.Context, review *entity.Review) error {
@@ -18,12 +27,12 @@ func (m *MockReviewRepository) Create(ctx context.Context, review *entity.Review
 	return args.Error(0)
 }
 
-func (m *MockReviewRepository) GetByProductID(ctx context.Context, productID string) ([]entity.Review, error) {
-	args := m.Called(ctx, productID)
+func (m *MockReviewRepository) GetByProductID(ctx context.Context, productID string, page entity.Page) (*entity.ReviewPage, error) {
+	args := m.Called(ctx, productID, page)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]entity.Review), args.Error(1)
+	return args.Get(0).(*entity.ReviewPage), args.Error(1)
 }
 
 func (m *MockReviewRepository) GetByID(ctx context.Context, id string) (*entity.Review, error) {
@@ -34,8 +43,8 @@ func (m *MockReviewRepository) GetByID(ctx context.Context, id string) (*entity.
 	return args.Get(0).(*entity.Review), args.Error(1)
 }
 
-func (m *MockReviewRepository) Update(ctx context.Context, review *entity.Review) error {
-	args := m.Called(ctx, review)
+func (m *MockReviewRepository) Update(ctx context.Context, review *entity.Review, previous entity.ReviewVersion) error {
+	args := m.Called(ctx, review, previous)
 	return args.Error(0)
 }
 
@@ -44,14 +53,158 @@ func (m *MockReviewRepository) Delete(ctx context.Context, id string) error {
 	return args.Error(0)
 }
 
-func (m *MockReviewRepository) GetByUserID(ctx context.Context, userID string) ([]entity.Review, error) {
-	args := m.Called(ctx, userID)
+func (m *MockReviewRepository) GetByUserID(ctx context.Context, userID string, page entity.Page) (*entity.ReviewPage, error) {
+	args := m.Called(ctx, userID, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ReviewPage), args.Error(1)
+}
+
+func (m *MockReviewRepository) List(ctx context.Context, filter entity.ReviewFilter, page entity.Page) (*entity.ReviewPage, error) {
+	args := m.Called(ctx, filter, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ReviewPage), args.Error(1)
+}
+
+func (m *MockReviewRepository) UpdateModeration(ctx context.Context, review *entity.Review) error {
+	args := m.Called(ctx, review)
+	return args.Error(0)
+}
+
+func (m *MockReviewRepository) FlagPublished(ctx context.Context, review *entity.Review) error {
+	args := m.Called(ctx, review)
+	return args.Error(0)
+}
+
+func (m *MockReviewRepository) GetRatingSummary(ctx context.Context, productID string) (*entity.RatingSummary, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.RatingSummary), args.Error(1)
+}
+
+func (m *MockReviewRepository) ListTenants(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockReviewRepository) CountByStatus(ctx context.Context, status entity.ReviewStatus) (int64, error) {
+	args := m.Called(ctx, status)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockReviewRepository) GetRatingAggregates(ctx context.Context) ([]entity.ProductRating, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.ProductRating), args.Error(1)
+}
+
+func (m *MockReviewRepository) ListWithoutSentiment(ctx context.Context, limit int) ([]entity.Review, error) {
+	args := m.Called(ctx, limit)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]entity.Review), args.Error(1)
 }
 
+func (m *MockReviewRepository) SetSentiment(ctx context.Context, id primitive.ObjectID, updatedAt time.Time, sentiment *entity.ReviewSentiment) error {
+	args := m.Called(ctx, id, updatedAt, sentiment)
+	return args.Error(0)
+}
+
+func (m *MockReviewRepository) SetAutoModeration(ctx context.Context, id primitive.ObjectID, updatedAt time.Time, moderation *entity.AutoModeration) error {
+	args := m.Called(ctx, id, updatedAt, moderation)
+	return args.Error(0)
+}
+
+// MockReportRepository мок для ReportRepository
+type MockReportRepository struct {
+	mock.Mock
+}
+
+func (m *MockReportRepository) Create(ctx context.Context, report *entity.ReviewReport) error {
+	args := m.Called(ctx, report)
+	return args.Error(0)
+}
+
+func (m *MockReportRepository) GetByID(ctx context.Context, id string) (*entity.ReviewReport, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ReviewReport), args.Error(1)
+}
+
+func (m *MockReportRepository) CountByReporterSince(ctx context.Context, reporterID string, since time.Time) (int64, error) {
+	args := m.Called(ctx, reporterID, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockReportRepository) CountOpenByReview(ctx context.Context, reviewID string) (int64, error) {
+	args := m.Called(ctx, reviewID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockReportRepository) ListByStatus(ctx context.Context, status entity.ReportStatus) ([]entity.ReviewReport, error) {
+	args := m.Called(ctx, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.ReviewReport), args.Error(1)
+}
+
+func (m *MockReportRepository) CountByStatus(ctx context.Context, status entity.ReportStatus) (int64, error) {
+	args := m.Called(ctx, status)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockReportRepository) ResolveOpenByReview(ctx context.Context, reviewID string, status entity.ReportStatus, resolvedBy, note string) (int64, error) {
+	args := m.Called(ctx, reviewID, status, resolvedBy, note)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockProfileRepository мок для ProfileRepository
+type MockProfileRepository struct {
+	mock.Mock
+}
+
+func (m *MockProfileRepository) Upsert(ctx context.Context, profile *entity.UserProfile) error {
+	args := m.Called(ctx, profile)
+	return args.Error(0)
+}
+
+func (m *MockProfileRepository) GetByIDs(ctx context.Context, userIDs []string) (map[string]entity.UserProfile, error) {
+	args := m.Called(ctx, userIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]entity.UserProfile), args.Error(1)
+}
+
+// MockPurchaseRepository мок для PurchaseRepository
+type MockPurchaseRepository struct {
+	mock.Mock
+}
+
+func (m *MockPurchaseRepository) Record(ctx context.Context, purchases []entity.Purchase) error {
+	args := m.Called(ctx, purchases)
+	return args.Error(0)
+}
+
+func (m *MockPurchaseRepository) Exists(ctx context.Context, userID, productID string) (bool, error) {
+	args := m.Called(ctx, userID, productID)
+	return args.Bool(0), args.Error(1)
+}
+
 // MockMessagePublisher мок для Kafka MessagePublisher
 type MockMessagePublisher struct {
 	mock.Mock
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"augustberries/reviews-service/internal/app/reviews/entity"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type profileRepository struct {
+	collection *mongo.Collection
+}
+
+// NewProfileRepository создает репозиторий профилей авторов
+// ID пользователей из Auth Service уникальны во всех магазинах, поэтому профиль хранится по user_id
+func NewProfileRepository(db *mongo.Database) ProfileRepository {
+	return &profileRepository{collection: db.Collection("user_profiles")}
+}
+
+// Upsert заменяет профиль, только если сохраненный старше profile.UpdatedAt
+// Если сохраненный профиль новее, фильтр не совпадает, upsert пытается вставить документ
+// с тем же _id и получает ошибку дубликата - это означает устаревшее событие
+func (r *profileRepository) Upsert(ctx context.Context, profile *entity.UserProfile) error {
+	filter := bson.M{
+		"_id":        profile.UserID,
+		"updated_at": bson.M{"$lt": profile.UpdatedAt},
+	}
+
+	_, err := r.collection.ReplaceOne(ctx, filter, profile, options.Replace().SetUpsert(true))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to upsert user profile: %w", err)
+	}
+	return nil
+}
+
+// GetByIDs получает профили одним запросом
+func (r *profileRepository) GetByIDs(ctx context.Context, userIDs []string) (map[string]entity.UserProfile, error) {
+	profiles := make(map[string]entity.UserProfile, len(userIDs))
+	if len(userIDs) == 0 {
+		return profiles, nil
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": userIDs}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user profiles: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var profile entity.UserProfile
+		if err := cursor.Decode(&profile); err != nil {
+			return nil, fmt.Errorf("failed to decode user profile: %w", err)
+		}
+		profiles[profile.UserID] = profile
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate user profiles: %w", err)
+	}
+
+	return profiles, nil
+}
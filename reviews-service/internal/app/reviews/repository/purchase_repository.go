@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"augustberries/pkg/tenant"
+	"augustberries/reviews-service/internal/app/reviews/entity"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type purchaseRepository struct {
+	collection *mongo.Collection
+}
+
+// NewPurchaseRepository создает репозиторий доставленных покупок
+// Уникальный индекс по магазину, пользователю и товару: проверка при создании отзыва идет по нему
+func NewPurchaseRepository(db *mongo.Database) PurchaseRepository {
+	collection := db.Collection("purchases")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	index := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "tenant_id", Value: 1},
+			{Key: "user_id", Value: 1},
+			{Key: "product_id", Value: 1},
+		},
+		Options: options.Index().SetName("tenant_user_product_idx").SetUnique(true),
+	}
+	if _, err := collection.Indexes().CreateOne(ctx, index); err != nil {
+		fmt.Printf("Warning: failed to create purchase index: %v\n", err)
+	}
+
+	return &purchaseRepository{collection: collection}
+}
+
+// Record сохраняет покупки одним запросом через upsert с $setOnInsert
+// Повторная доставка события или нового заказа с тем же товаром не меняет сохраненную покупку
+func (r *purchaseRepository) Record(ctx context.Context, purchases []entity.Purchase) error {
+	if len(purchases) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, 0, len(purchases))
+	for _, purchase := range purchases {
+		filter := bson.M{
+			"tenant_id":  purchase.TenantID,
+			"user_id":    purchase.UserID,
+			"product_id": purchase.ProductID,
+		}
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(filter).
+			SetUpdate(bson.M{"$setOnInsert": purchase}).
+			SetUpsert(true))
+	}
+
+	_, err := r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		// Параллельный upsert той же покупки получает ошибку дубликата - покупка уже сохранена
+		var bulkErr mongo.BulkWriteException
+		if errors.As(err, &bulkErr) && bulkErr.WriteConcernError == nil && allDuplicateKeys(bulkErr.WriteErrors) {
+			return nil
+		}
+		return fmt.Errorf("failed to record purchases: %w", err)
+	}
+	return nil
+}
+
+// allDuplicateKeys проверяет, что все ошибки пакетной записи - ошибки дубликата
+func allDuplicateKeys(errs []mongo.BulkWriteError) bool {
+	for _, e := range errs {
+		if !mongo.IsDuplicateKeyError(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// Exists проверяет покупку в магазине из контекста
+func (r *purchaseRepository) Exists(ctx context.Context, userID, productID string) (bool, error) {
+	filter := bson.M{
+		"tenant_id":  tenant.FromContext(ctx),
+		"user_id":    userID,
+		"product_id": productID,
+	}
+
+	count, err := r.collection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("failed to check purchase: %w", err)
+	}
+	return count > 0, nil
+}
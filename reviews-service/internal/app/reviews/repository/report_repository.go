@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"augustberries/pkg/tenant"
+	"augustberries/reviews-service/internal/app/reviews/entity"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrReportNotFound      = errors.New("report not found")
+	ErrReportAlreadyExists = errors.New("review already reported by user")
+)
+
+type reportRepository struct {
+	collection *mongo.Collection
+}
+
+// NewReportRepository создает репозиторий жалоб на отзывы
+// Уникальный индекс не дает пользователю пожаловаться на один отзыв дважды
+func NewReportRepository(db *mongo.Database) ReportRepository {
+	collection := db.Collection("review_reports")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "review_id", Value: 1},
+				{Key: "reporter_id", Value: 1},
+			},
+			Options: options.Index().SetName("tenant_review_reporter_idx").SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "reporter_id", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+			Options: options.Index().SetName("reporter_created_idx"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "tenant_id", Value: 1},
+				{Key: "status", Value: 1},
+				{Key: "created_at", Value: 1},
+			},
+			Options: options.Index().SetName("tenant_status_idx"),
+		},
+	}
+
+	if _, err := collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		fmt.Printf("Warning: failed to create review report indexes: %v\n", err)
+	}
+
+	return &reportRepository{collection: collection}
+}
+
+// Create сохраняет новую открытую жалобу
+func (r *reportRepository) Create(ctx context.Context, report *entity.ReviewReport) error {
+	report.TenantID = tenant.FromContext(ctx)
+	report.Status = entity.ReportStatusOpen
+	report.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, report)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrReportAlreadyExists
+		}
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		report.ID = oid
+	}
+
+	return nil
+}
+
+// GetByID получает жалобу по ID
+func (r *reportRepository) GetByID(ctx context.Context, id string) (*entity.ReviewReport, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrReportNotFound
+	}
+
+	var report entity.ReviewReport
+	err = r.collection.FindOne(ctx, tenantFilter(ctx, bson.M{"_id": objectID})).Decode(&report)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrReportNotFound
+		}
+		return nil, fmt.Errorf("failed to get report: %w", err)
+	}
+
+	return &report, nil
+}
+
+// CountByReporterSince считает жалобы пользователя, поданные после since (во всех магазинах)
+func (r *reportRepository) CountByReporterSince(ctx context.Context, reporterID string, since time.Time) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{
+		"reporter_id": reporterID,
+		"created_at":  bson.M{"$gte": since},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count reports: %w", err)
+	}
+	return count, nil
+}
+
+// CountOpenByReview считает открытые жалобы на отзыв
+func (r *reportRepository) CountOpenByReview(ctx context.Context, reviewID string) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, tenantFilter(ctx, bson.M{
+		"review_id": reviewID,
+		"status":    entity.ReportStatusOpen,
+	}))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count reports: %w", err)
+	}
+	return count, nil
+}
+
+// CountByStatus считает жалобы магазина в статусе status
+func (r *reportRepository) CountByStatus(ctx context.Context, status entity.ReportStatus) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, tenantFilter(ctx, bson.M{"status": status}))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count reports: %w", err)
+	}
+	return count, nil
+}
+
+// ListByStatus получает жалобы магазина в статусе status, старые первыми
+func (r *reportRepository) ListByStatus(ctx context.Context, status entity.ReportStatus) ([]entity.ReviewReport, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, tenantFilter(ctx, bson.M{"status": status}), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find reports: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var reports []entity.ReviewReport
+	if err := cursor.All(ctx, &reports); err != nil {
+		return nil, fmt.Errorf("failed to decode reports: %w", err)
+	}
+
+	return reports, nil
+}
+
+// ResolveOpenByReview закрывает все открытые жалобы на отзыв и возвращает их число
+func (r *reportRepository) ResolveOpenByReview(ctx context.Context, reviewID string, status entity.ReportStatus, resolvedBy, note string) (int64, error) {
+	filter := tenantFilter(ctx, bson.M{
+		"review_id": reviewID,
+		"status":    entity.ReportStatusOpen,
+	})
+	update := bson.M{
+		"$set": bson.M{
+			"status":          status,
+			"resolved_by":     resolvedBy,
+			"resolution_note": note,
+			"resolved_at":     time.Now(),
+		},
+	}
+
+	result, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve reports: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
@@ -2,16 +2,74 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"augustberries/reviews-service/internal/app/reviews/entity"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // ReviewRepository определяет методы для работы с отзывами в MongoDB
 type ReviewRepository interface {
 	Create(ctx context.Context, review *entity.Review) error
-	GetByProductID(ctx context.Context, productID string) ([]entity.Review, error)
+	// GetByProductID возвращает страницу опубликованных отзывов товара, новые первыми, и их общее число
+	GetByProductID(ctx context.Context, productID string, page entity.Page) (*entity.ReviewPage, error)
 	GetByID(ctx context.Context, id string) (*entity.Review, error)
-	Update(ctx context.Context, review *entity.Review) error
+	// Update сохраняет оценку и текст отзыва, добавляя previous в историю изменений
+	Update(ctx context.Context, review *entity.Review, previous entity.ReviewVersion) error
 	Delete(ctx context.Context, id string) error
-	GetByUserID(ctx context.Context, userID string) ([]entity.Review, error)
+	// GetByUserID возвращает страницу отзывов пользователя, новые первыми, и их общее число
+	GetByUserID(ctx context.Context, userID string, page entity.Page) (*entity.ReviewPage, error)
+	// List возвращает страницу отзывов магазина по фильтру admin списка, новые первыми, и их общее число
+	List(ctx context.Context, filter entity.ReviewFilter, page entity.Page) (*entity.ReviewPage, error)
+	// UpdateModeration сохраняет статус отзыва и решение модератора
+	UpdateModeration(ctx context.Context, review *entity.Review) error
+	// FlagPublished сохраняет статус flagged, только если отзыв все еще опубликован;
+	// отзыв в другом статусе - ErrReviewNotFound
+	FlagPublished(ctx context.Context, review *entity.Review) error
+	// GetRatingSummary считает оценки и тональность опубликованных отзывов товара
+	GetRatingSummary(ctx context.Context, productID string) (*entity.RatingSummary, error)
+	// ListTenants возвращает магазины, в которых есть отзывы
+	ListTenants(ctx context.Context) ([]string, error)
+	// GetRatingAggregates считает среднюю оценку и число опубликованных отзывов каждого товара магазина
+	GetRatingAggregates(ctx context.Context) ([]entity.ProductRating, error)
+	// CountByStatus считает отзывы магазина в статусе status
+	CountByStatus(ctx context.Context, status entity.ReviewStatus) (int64, error)
+	// ListWithoutSentiment возвращает отзывы всех магазинов без оценки тональности, старые первыми
+	ListWithoutSentiment(ctx context.Context, limit int) ([]entity.Review, error)
+	// SetSentiment сохраняет тональность, если текст отзыва не менялся после updatedAt
+	SetSentiment(ctx context.Context, id primitive.ObjectID, updatedAt time.Time, sentiment *entity.ReviewSentiment) error
+	// SetAutoModeration сохраняет результат внешней модерации, если текст отзыва не менялся после updatedAt
+	SetAutoModeration(ctx context.Context, id primitive.ObjectID, updatedAt time.Time, moderation *entity.AutoModeration) error
+	// WithTransaction выполняет fn в транзакции MongoDB; операции репозитория с переданным в fn контекстом
+	// входят в транзакцию. При временных ошибках транзакция повторяется целиком
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// ReportRepository определяет методы для работы с жалобами на отзывы
+type ReportRepository interface {
+	// Create сохраняет открытую жалобу, повторная жалоба пользователя на отзыв - ErrReportAlreadyExists
+	Create(ctx context.Context, report *entity.ReviewReport) error
+	GetByID(ctx context.Context, id string) (*entity.ReviewReport, error)
+	CountByReporterSince(ctx context.Context, reporterID string, since time.Time) (int64, error)
+	CountOpenByReview(ctx context.Context, reviewID string) (int64, error)
+	ListByStatus(ctx context.Context, status entity.ReportStatus) ([]entity.ReviewReport, error)
+	CountByStatus(ctx context.Context, status entity.ReportStatus) (int64, error)
+	ResolveOpenByReview(ctx context.Context, reviewID string, status entity.ReportStatus, resolvedBy, note string) (int64, error)
+}
+
+// ProfileRepository хранит публичные профили авторов отзывов, построенные по событиям Auth Service
+type ProfileRepository interface {
+	// Upsert сохраняет профиль, если он новее сохраненного; устаревшее событие игнорируется
+	Upsert(ctx context.Context, profile *entity.UserProfile) error
+	// GetByIDs возвращает профили по ID пользователей, отсутствующие профили пропускаются
+	GetByIDs(ctx context.Context, userIDs []string) (map[string]entity.UserProfile, error)
+}
+
+// PurchaseRepository хранит доставленные покупки товаров, построенные по событиям Orders Service
+type PurchaseRepository interface {
+	// Record сохраняет покупки; уже сохраненная покупка пользователем товара не меняется
+	Record(ctx context.Context, purchases []entity.Purchase) error
+	// Exists проверяет, доставлялся ли пользователю товар в магазине из контекста
+	Exists(ctx context.Context, userID, productID string) (bool, error)
 }
@@ -6,12 +6,14 @@ import (
 	"fmt"
 	"time"
 
+	"augustberries/pkg/tenant"
 	"augustberries/reviews-service/internal/app/reviews/entity"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 var (
@@ -19,13 +21,62 @@ var (
 	ErrReviewNotFound = errors.New("review not found")
 )
 
+// tenantFilter ограничивает выборку отзывами магазина из контекста
+// Отзывы, созданные до введения тенантов, не содержат tenant_id и относятся к магазину по умолчанию
+func tenantFilter(ctx context.Context, filter bson.M) bson.M {
+	id := tenant.FromContext(ctx)
+	if id == tenant.DefaultID {
+		filter["tenant_id"] = bson.M{"$in": bson.A{id, nil}}
+	} else {
+		filter["tenant_id"] = id
+	}
+	return filter
+}
+
+// listProjection - поля отзыва в списках: история изменений нужна только отдельному запросу истории,
+// tenant_id не отдается клиентам
+var listProjection = bson.M{
+	"product_id":        1,
+	"user_id":           1,
+	"rating":            1,
+	"text":              1,
+	"status":            1,
+	"created_at":        1,
+	"updated_at":        1,
+	"edited":            1,
+	"moderated_by":      1,
+	"moderation_reason": 1,
+	"moderated_at":      1,
+	"verified_purchase": 1,
+	"sentiment":         1,
+	"auto_moderation":   1,
+}
+
+// Индексы страниц отзывов товара и пользователя: фильтр по магазину и сортировка по дате без сортировки в памяти
+const (
+	productPageIndex = "tenant_product_created_idx"
+	userPageIndex    = "tenant_user_created_idx"
+)
+
+// TransactionConfig - настройки транзакций MongoDB
+// Транзакции требуют replica set или sharded cluster; на standalone сервере их нужно отключить
+type TransactionConfig struct {
+	Enabled     bool // Выполнять WithTransaction в транзакции (иначе fn вызывается напрямую)
+	MaxAttempts int  // Сколько раз повторять транзакцию и commit при временных ошибках
+}
+
 type reviewRepository struct {
 	collection *mongo.Collection
+	tx         TransactionConfig
 }
 
 // NewReviewRepository создает новый репозиторий отзывов
 // Автоматически создает индекс по product_id для быстрой выборки
-func NewReviewRepository(db *mongo.Database) ReviewRepository {
+func NewReviewRepository(db *mongo.Database, tx TransactionConfig) ReviewRepository {
+	if tx.MaxAttempts < 1 {
+		tx.MaxAttempts = 1
+	}
+
 	collection := db.Collection("reviews")
 
 	// Создаем индекс по product_id для быстрого поиска отзывов по товару
@@ -58,13 +109,115 @@ func NewReviewRepository(db *mongo.Database) ReviewRepository {
 		fmt.Printf("Warning: failed to create index on user_id: %v\n", err)
 	}
 
+	// Составной индекс по магазину и товару: все выборки фильтруются по tenant_id
+	tenantIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "tenant_id", Value: 1},
+			{Key: "product_id", Value: 1},
+		},
+		Options: options.Index().SetName("tenant_product_idx"),
+	}
+
+	_, err = collection.Indexes().CreateOne(ctx, tenantIndexModel)
+	if err != nil {
+		fmt.Printf("Warning: failed to create index on tenant_id: %v\n", err)
+	}
+
+	// Индексы страниц списков; индекс для выборки выбирает планировщик MongoDB, без hint
+	// запросы продолжают работать и в коллекции, где индекс не удалось создать
+	pageIndexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "product_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName(productPageIndex),
+		},
+		{
+			Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName(userPageIndex),
+		},
+	}
+
+	_, err = collection.Indexes().CreateMany(ctx, pageIndexModels)
+	if err != nil {
+		fmt.Printf("Warning: failed to create review page indexes: %v\n", err)
+	}
+
 	return &reviewRepository{
 		collection: collection,
+		tx:         tx,
+	}
+}
+
+// WithTransaction выполняет fn в транзакции
+// Транзакция повторяется целиком при ошибке с меткой TransientTransactionError,
+// commit - при UnknownTransactionCommitResult, но не больше MaxAttempts раз
+func (r *reviewRepository) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !r.tx.Enabled {
+		return fn(ctx)
+	}
+
+	session, err := r.collection.Database().Client().StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(context.WithoutCancel(ctx))
+
+	// Внутри транзакции допустимо читать только с primary
+	txOpts := options.Transaction().SetReadPreference(readpref.Primary())
+
+	for attempt := 1; ; attempt++ {
+		err = mongo.WithSession(ctx, session, func(sc mongo.SessionContext) error {
+			if err := session.StartTransaction(txOpts); err != nil {
+				return fmt.Errorf("failed to start transaction: %w", err)
+			}
+
+			if err := fn(sc); err != nil {
+				if abortErr := session.AbortTransaction(context.WithoutCancel(sc)); abortErr != nil {
+					fmt.Printf("failed to abort transaction: %v\n", abortErr)
+				}
+				return err
+			}
+
+			return r.commitWithRetry(sc, session)
+		})
+
+		if err == nil || attempt >= r.tx.MaxAttempts || !hasErrorLabel(err, driverTransientTransactionError) {
+			return err
+		}
+		fmt.Printf("retrying transaction after transient error (attempt %d/%d): %v\n", attempt, r.tx.MaxAttempts, err)
 	}
 }
 
+// commitWithRetry фиксирует транзакцию, повторяя commit при неизвестном результате
+func (r *reviewRepository) commitWithRetry(ctx context.Context, session mongo.Session) error {
+	for attempt := 1; ; attempt++ {
+		err := session.CommitTransaction(ctx)
+		if err == nil || attempt >= r.tx.MaxAttempts || !hasErrorLabel(err, driverUnknownTransactionCommitResult) {
+			if err != nil {
+				return fmt.Errorf("failed to commit transaction: %w", err)
+			}
+			return nil
+		}
+	}
+}
+
+// Метки ошибок, по которым драйвер MongoDB рекомендует повторять транзакцию или commit
+const (
+	driverTransientTransactionError      = "TransientTransactionError"
+	driverUnknownTransactionCommitResult = "UnknownTransactionCommitResult"
+)
+
+// hasErrorLabel проверяет метку ошибки MongoDB с учетом обертки через %w
+func hasErrorLabel(err error, label string) bool {
+	var labeled mongo.LabeledError
+	return errors.As(err, &labeled) && labeled.HasErrorLabel(label)
+}
+
 // Create создает новый отзыв в MongoDB
 func (r *reviewRepository) Create(ctx context.Context, review *entity.Review) error {
+	review.TenantID = tenant.FromContext(ctx)
+	if review.Status == "" {
+		review.Status = entity.ReviewStatusPublished
+	}
 	review.CreatedAt = time.Now()
 	review.UpdatedAt = time.Now()
 
@@ -81,24 +234,58 @@ func (r *reviewRepository) Create(ctx context.Context, review *entity.Review) er
 	return nil
 }
 
-// GetByProductID получает все отзывы по ID товара
-// Использует индекс product_id_idx для быстрой выборки
-func (r *reviewRepository) GetByProductID(ctx context.Context, productID string) ([]entity.Review, error) {
-	filter := bson.M{"product_id": productID}
-	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+// GetByProductID получает страницу опубликованных отзывов по ID товара и их общее число
+// Использует индекс tenant_product_created_idx: выборка и сортировка по дате без чтения всех отзывов товара
+func (r *reviewRepository) GetByProductID(ctx context.Context, productID string, page entity.Page) (*entity.ReviewPage, error) {
+	filter := tenantFilter(ctx, bson.M{"product_id": productID, "status": statusFilter(entity.ReviewStatusPublished)})
+	return r.findPage(ctx, filter, page)
+}
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+// findPage выбирает страницу отзывов, новые первыми, и общее число подходящих отзывов за один запрос ($facet)
+// Индекс для $match и $sort выбирает MongoDB
+func (r *reviewRepository) findPage(ctx context.Context, filter bson.M, page entity.Page) (*entity.ReviewPage, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$sort", Value: bson.D{{Key: "created_at", Value: -1}}}},
+		{{Key: "$facet", Value: bson.M{
+			"reviews": bson.A{
+				bson.M{"$skip": page.Offset},
+				bson.M{"$limit": page.Limit},
+				bson.M{"$project": listProjection},
+			},
+			"total": bson.A{
+				bson.M{"$count": "count"},
+			},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find reviews: %w", err)
 	}
 	defer cursor.Close(ctx)
 
-	var reviews []entity.Review
-	if err := cursor.All(ctx, &reviews); err != nil {
+	var facets []struct {
+		Reviews []entity.Review `bson:"reviews"`
+		Total   []struct {
+			Count int `bson:"count"`
+		} `bson:"total"`
+	}
+	if err := cursor.All(ctx, &facets); err != nil {
 		return nil, fmt.Errorf("failed to decode reviews: %w", err)
 	}
 
-	return reviews, nil
+	result := &entity.ReviewPage{Reviews: []entity.Review{}}
+	if len(facets) > 0 {
+		if facets[0].Reviews != nil {
+			result.Reviews = facets[0].Reviews
+		}
+		if len(facets[0].Total) > 0 {
+			result.Total = facets[0].Total[0].Count
+		}
+	}
+
+	return result, nil
 }
 
 // GetByID получает отзыв по ID
@@ -108,7 +295,7 @@ func (r *reviewRepository) GetByID(ctx context.Context, id string) (*entity.Revi
 		return nil, fmt.Errorf("invalid review ID: %w", err)
 	}
 
-	filter := bson.M{"_id": objectID}
+	filter := tenantFilter(ctx, bson.M{"_id": objectID})
 
 	var review entity.Review
 	err = r.collection.FindOne(ctx, filter).Decode(&review)
@@ -122,17 +309,36 @@ func (r *reviewRepository) GetByID(ctx context.Context, id string) (*entity.Revi
 	return &review, nil
 }
 
-// Update обновляет отзыв в MongoDB
-func (r *reviewRepository) Update(ctx context.Context, review *entity.Review) error {
+// maxReviewHistory - сколько прежних версий отзыва хранится, более старые отбрасываются
+const maxReviewHistory = 50
+
+// Update обновляет отзыв в MongoDB и добавляет прежнюю версию в историю изменений
+func (r *reviewRepository) Update(ctx context.Context, review *entity.Review, previous entity.ReviewVersion) error {
 	review.UpdatedAt = time.Now()
+	review.Sentiment = nil
+	review.AutoModeration = nil
+	review.Edited = true
+
+	previous.ReplacedAt = review.UpdatedAt
+	review.History = append(review.History, previous)
+	if len(review.History) > maxReviewHistory {
+		review.History = review.History[len(review.History)-maxReviewHistory:]
+	}
 
-	filter := bson.M{"_id": review.ID}
+	filter := tenantFilter(ctx, bson.M{"_id": review.ID})
 	update := bson.M{
 		"$set": bson.M{
 			"rating":     review.Rating,
 			"text":       review.Text,
 			"updated_at": review.UpdatedAt,
+			"edited":     true,
+		},
+		// $push вместо перезаписи массива: параллельное изменение без транзакции не потеряет версию
+		"$push": bson.M{
+			"history": bson.M{"$each": bson.A{previous}, "$slice": -maxReviewHistory},
 		},
+		// Тональность и проверка модерации старого текста неактуальны, их пересчитают заново
+		"$unset": bson.M{"sentiment": "", "auto_moderation": ""},
 	}
 
 	result, err := r.collection.UpdateOne(ctx, filter, update)
@@ -154,7 +360,7 @@ func (r *reviewRepository) Delete(ctx context.Context, id string) error {
 		return fmt.Errorf("invalid review ID: %w", err)
 	}
 
-	filter := bson.M{"_id": objectID}
+	filter := tenantFilter(ctx, bson.M{"_id": objectID})
 
 	result, err := r.collection.DeleteOne(ctx, filter)
 	if err != nil {
@@ -168,15 +374,226 @@ func (r *reviewRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-// GetByUserID получает все отзывы пользователя
-// Использует индекс user_id_idx для быстрой выборки
-func (r *reviewRepository) GetByUserID(ctx context.Context, userID string) ([]entity.Review, error) {
-	filter := bson.M{"user_id": userID}
-	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+// GetByUserID получает страницу отзывов пользователя и их общее число
+// Использует индекс tenant_user_created_idx
+func (r *reviewRepository) GetByUserID(ctx context.Context, userID string, page entity.Page) (*entity.ReviewPage, error) {
+	return r.findPage(ctx, tenantFilter(ctx, bson.M{"user_id": userID}), page)
+}
+
+// statusFilter - условие на статус отзыва
+// Отзывы, созданные до введения модерации, не содержат status и считаются опубликованными
+func statusFilter(status entity.ReviewStatus) interface{} {
+	if status == entity.ReviewStatusPublished {
+		return bson.M{"$in": bson.A{status, nil}}
+	}
+	return status
+}
+
+// List получает страницу отзывов магазина по фильтру admin списка и их общее число
+// Фильтр по товару или пользователю выбирается по индексу страниц, остальные условия проверяются по найденным отзывам
+func (r *reviewRepository) List(ctx context.Context, filter entity.ReviewFilter, page entity.Page) (*entity.ReviewPage, error) {
+	query := bson.M{}
+	if filter.UserID != "" {
+		query["user_id"] = filter.UserID
+	}
+	if filter.ProductID != "" {
+		query["product_id"] = filter.ProductID
+	}
+	if filter.Status != "" {
+		query["status"] = statusFilter(filter.Status)
+	}
+	if filter.Rating > 0 {
+		query["rating"] = filter.Rating
+	}
+
+	return r.findPage(ctx, tenantFilter(ctx, query), page)
+}
+
+// CountByStatus считает отзывы магазина в статусе status
+func (r *reviewRepository) CountByStatus(ctx context.Context, status entity.ReviewStatus) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, tenantFilter(ctx, bson.M{"status": statusFilter(status)}))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count reviews: %w", err)
+	}
+	return count, nil
+}
+
+// UpdateModeration обновляет статус отзыва и решение модератора
+func (r *reviewRepository) UpdateModeration(ctx context.Context, review *entity.Review) error {
+	review.UpdatedAt = time.Now()
+
+	filter := tenantFilter(ctx, bson.M{"_id": review.ID})
+	update := bson.M{
+		"$set": bson.M{
+			"status":            review.Status,
+			"moderated_by":      review.ModeratedBy,
+			"moderation_reason": review.ModerationReason,
+			"moderated_at":      review.ModeratedAt,
+			"updated_at":        review.UpdatedAt,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to update review moderation: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrReviewNotFound
+	}
+
+	return nil
+}
+
+// FlagPublished скрывает отзыв до решения модератора
+// Статус проверяется в том же запросе: решение, принятое модератором после чтения отзыва, не перезаписывается
+func (r *reviewRepository) FlagPublished(ctx context.Context, review *entity.Review) error {
+	review.UpdatedAt = time.Now()
+
+	filter := tenantFilter(ctx, bson.M{"_id": review.ID, "status": statusFilter(entity.ReviewStatusPublished)})
+	update := bson.M{
+		"$set": bson.M{
+			"status":            entity.ReviewStatusFlagged,
+			"moderated_by":      review.ModeratedBy,
+			"moderation_reason": review.ModerationReason,
+			"moderated_at":      review.ModeratedAt,
+			"updated_at":        review.UpdatedAt,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to flag review: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrReviewNotFound
+	}
+
+	return nil
+}
+
+// GetRatingSummary считает число отзывов, среднюю оценку, распределение по оценкам и тональность одним запросом
+func (r *reviewRepository) GetRatingSummary(ctx context.Context, productID string) (*entity.RatingSummary, error) {
+	match := tenantFilter(ctx, bson.M{"product_id": productID, "status": statusFilter(entity.ReviewStatusPublished)})
+	countLabel := func(label entity.SentimentLabel) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$sentiment.label", label}}, 1, 0}}}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":             "$rating",
+			"count":           bson.M{"$sum": 1},
+			"sentiment_count": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$ifNull": bson.A{"$sentiment", false}}, 1, 0}}},
+			"sentiment_sum":   bson.M{"$sum": bson.M{"$ifNull": bson.A{"$sentiment.score", 0}}},
+			"positive":        countLabel(entity.SentimentPositive),
+			"neutral":         countLabel(entity.SentimentNeutral),
+			"negative":        countLabel(entity.SentimentNegative),
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate rating summary: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		Rating         int     `bson:"_id"`
+		Count          int     `bson:"count"`
+		SentimentCount int     `bson:"sentiment_count"`
+		SentimentSum   float64 `bson:"sentiment_sum"`
+		Positive       int     `bson:"positive"`
+		Neutral        int     `bson:"neutral"`
+		Negative       int     `bson:"negative"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, fmt.Errorf("failed to decode rating summary: %w", err)
+	}
+
+	summary := &entity.RatingSummary{ProductID: productID, Distribution: map[int]int{1: 0, 2: 0, 3: 0, 4: 0, 5: 0}}
+	var ratingSum, sentimentSum float64
+	for _, g := range groups {
+		summary.Count += g.Count
+		summary.Distribution[g.Rating] += g.Count
+		ratingSum += float64(g.Rating * g.Count)
+
+		summary.Sentiment.Analyzed += g.SentimentCount
+		summary.Sentiment.Positive += g.Positive
+		summary.Sentiment.Neutral += g.Neutral
+		summary.Sentiment.Negative += g.Negative
+		sentimentSum += g.SentimentSum
+	}
+	if summary.Count > 0 {
+		summary.Average = ratingSum / float64(summary.Count)
+	}
+	if summary.Sentiment.Analyzed > 0 {
+		summary.Sentiment.AverageScore = sentimentSum / float64(summary.Sentiment.Analyzed)
+	}
+
+	return summary, nil
+}
+
+// ListTenants получает магазины, в которых оставлены отзывы
+// Отзывы без tenant_id относятся к магазину по умолчанию
+func (r *reviewRepository) ListTenants(ctx context.Context) ([]string, error) {
+	values, err := r.collection.Distinct(ctx, "tenant_id", bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list review tenants: %w", err)
+	}
+
+	seen := make(map[string]bool, len(values))
+	tenants := make([]string, 0, len(values))
+	for _, value := range values {
+		id, _ := value.(string)
+		if id == "" {
+			id = tenant.DefaultID
+		}
+		if !seen[id] {
+			seen[id] = true
+			tenants = append(tenants, id)
+		}
+	}
+
+	return tenants, nil
+}
+
+// GetRatingAggregates считает оценки опубликованных отзывов по товарам магазина одним запросом
+func (r *reviewRepository) GetRatingAggregates(ctx context.Context) ([]entity.ProductRating, error) {
+	match := tenantFilter(ctx, bson.M{"status": statusFilter(entity.ReviewStatusPublished)})
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":          "$product_id",
+			"rating_avg":   bson.M{"$avg": "$rating"},
+			"rating_count": bson.M{"$sum": 1},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate product ratings: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var ratings []entity.ProductRating
+	if err := cursor.All(ctx, &ratings); err != nil {
+		return nil, fmt.Errorf("failed to decode product ratings: %w", err)
+	}
+
+	return ratings, nil
+}
+
+// ListWithoutSentiment получает отзывы без оценки тональности по всем магазинам
+// Используется фоновым заданием, поэтому выборка не ограничена магазином из контекста
+func (r *reviewRepository) ListWithoutSentiment(ctx context.Context, limit int) ([]entity.Review, error) {
+	filter := bson.M{"sentiment": bson.M{"$exists": false}}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(limit))
 
 	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find reviews: %w", err)
+		return nil, fmt.Errorf("failed to find reviews without sentiment: %w", err)
 	}
 	defer cursor.Close(ctx)
 
@@ -187,3 +604,39 @@ func (r *reviewRepository) GetByUserID(ctx context.Context, userID string) ([]en
 
 	return reviews, nil
 }
+
+// SetSentiment сохраняет тональность отзыва
+// Условие по updated_at не дает записать оценку старого текста, если отзыв отредактировали во время анализа
+func (r *reviewRepository) SetSentiment(ctx context.Context, id primitive.ObjectID, updatedAt time.Time, sentiment *entity.ReviewSentiment) error {
+	filter := bson.M{"_id": id, "updated_at": updatedAt}
+	update := bson.M{"$set": bson.M{"sentiment": sentiment}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to set review sentiment: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrReviewNotFound
+	}
+
+	return nil
+}
+
+// SetAutoModeration сохраняет результат внешней модерации, если текст отзыва не менялся после updatedAt
+// updated_at не меняется: оценка не считается изменением отзыва
+func (r *reviewRepository) SetAutoModeration(ctx context.Context, id primitive.ObjectID, updatedAt time.Time, moderation *entity.AutoModeration) error {
+	filter := tenantFilter(ctx, bson.M{"_id": id, "updated_at": updatedAt})
+	update := bson.M{"$set": bson.M{"auto_moderation": moderation}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to set review auto moderation: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrReviewNotFound
+	}
+
+	return nil
+}
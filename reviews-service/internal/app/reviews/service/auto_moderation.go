@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"augustberries/pkg/metrics"
+	"augustberries/pkg/recovery"
+	"augustberries/pkg/tenant"
+	"augustberries/reviews-service/internal/app/reviews/entity"
+	"augustberries/reviews-service/internal/app/reviews/infrastructure"
+	"augustberries/reviews-service/internal/app/reviews/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AutoModerationFlag - аварийный выключатель внешней модерации; субъект флага - магазин
+// Пока флаг не заведен, модерация включена
+const AutoModerationFlag = "reviews.auto_moderation"
+
+// FlagChecker вычисляет флаги функциональности (featureflags.Client)
+type FlagChecker interface {
+	Enabled(ctx context.Context, key, subject string, fallback bool) bool
+}
+
+// AutoModerationConfig - настройки проверки отзывов внешним сервисом модерации
+type AutoModerationConfig struct {
+	Workers      int           // Горутин, параллельно вызывающих сервис
+	QueueSize    int           // Отзывов в очереди; при переполнении новые отзывы не проверяются
+	Timeout      time.Duration // Таймаут одной попытки
+	MaxAttempts  int           // Попыток на отзыв, включая первую
+	RetryBackoff time.Duration // Пауза перед второй попыткой, дальше удваивается
+	Threshold    float64       // Токсичность, начиная с которой отзыв скрывается до решения модератора
+}
+
+// moderationJob - отзыв в очереди проверки; updatedAt отличает проверяемый текст от отредактированного позже
+type moderationJob struct {
+	tenantID  string
+	reviewID  primitive.ObjectID
+	text      string
+	updatedAt time.Time
+}
+
+// AutoModerator проверяет новые и измененные отзывы во внешнем сервисе модерации пулом горутин
+// Запрос создания отзыва не ждет проверки; токсичный отзыв скрывается (flagged) до решения модератора
+type AutoModerator struct {
+	provider infrastructure.ModerationProvider
+	reviews  *ReviewService
+	flags    FlagChecker
+	cfg      AutoModerationConfig
+	now      func() time.Time
+
+	jobs    chan moderationJob
+	mu      sync.RWMutex // Защищает закрытие jobs от параллельного Submit
+	stopped bool
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewAutoModerator создает пул проверки; flags может быть nil - тогда выключатель не проверяется
+func NewAutoModerator(provider infrastructure.ModerationProvider, reviews *ReviewService, flags FlagChecker, cfg AutoModerationConfig) *AutoModerator {
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	return &AutoModerator{
+		provider: provider,
+		reviews:  reviews,
+		flags:    flags,
+		cfg:      cfg,
+		now:      time.Now,
+		jobs:     make(chan moderationJob, cfg.QueueSize),
+	}
+}
+
+// Start запускает горутины проверки
+func (m *AutoModerator) Start(ctx context.Context) {
+	ctx, m.cancel = context.WithCancel(ctx)
+	log.Printf("Starting review auto moderation (provider: %s, workers: %d, threshold: %.2f)", m.provider.Name(), m.cfg.Workers, m.cfg.Threshold)
+
+	for i := 0; i < m.cfg.Workers; i++ {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			for job := range m.jobs {
+				recovery.Wrap("reviews-service", "auto_moderation", func() { m.process(ctx, job) })()
+			}
+		}()
+	}
+}
+
+// Stop прерывает текущие проверки и ждет завершения горутин
+// Непроверенные отзывы из очереди остаются опубликованными без оценки
+func (m *AutoModerator) Stop() {
+	log.Println("Stopping review auto moderation...")
+	m.mu.Lock()
+	m.stopped = true
+	close(m.jobs)
+	m.mu.Unlock()
+
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.wg.Wait()
+	log.Println("Review auto moderation stopped")
+}
+
+// Submit ставит отзыв в очередь проверки без ожидания
+// Возвращает false, если модерация выключена флагом, очередь переполнена или пул остановлен
+func (m *AutoModerator) Submit(ctx context.Context, review *entity.Review) bool {
+	tenantID := tenant.FromContext(ctx)
+	if !m.enabled(ctx, tenantID) {
+		metrics.ReviewsAutoModeration.WithLabelValues("disabled").Inc()
+		return false
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.stopped {
+		return false
+	}
+
+	// MongoDB хранит время с точностью до миллисекунд: иначе условие на updated_at не совпадет
+	job := moderationJob{tenantID: tenantID, reviewID: review.ID, text: review.Text, updatedAt: review.UpdatedAt.Truncate(time.Millisecond)}
+	select {
+	case m.jobs <- job:
+		return true
+	default:
+		fmt.Printf("auto moderation queue is full, review %s is not checked\n", review.ID.Hex())
+		metrics.ReviewsAutoModeration.WithLabelValues("dropped").Inc()
+		return false
+	}
+}
+
+func (m *AutoModerator) enabled(ctx context.Context, tenantID string) bool {
+	if m.flags == nil {
+		return true
+	}
+	return m.flags.Enabled(ctx, AutoModerationFlag, tenantID, true)
+}
+
+// process проверяет отзыв и сохраняет результат; отзыв, измененный или удаленный во время проверки, пропускается
+func (m *AutoModerator) process(ctx context.Context, job moderationJob) {
+	ctx = tenant.WithID(ctx, job.tenantID)
+	// Выключатель действует и на отзывы, уже стоящие в очереди
+	if !m.enabled(ctx, job.tenantID) {
+		metrics.ReviewsAutoModeration.WithLabelValues("disabled").Inc()
+		return
+	}
+
+	toxicity, attempts, err := m.check(ctx, job.text)
+	if ctx.Err() != nil {
+		return
+	}
+
+	result := &entity.AutoModeration{
+		Status:    entity.AutoModerationPassed,
+		Toxicity:  toxicity,
+		Provider:  m.provider.Name(),
+		Attempts:  attempts,
+		CheckedAt: m.now(),
+	}
+	switch {
+	case err != nil:
+		fmt.Printf("failed to moderate review %s after %d attempts: %v\n", job.reviewID.Hex(), attempts, err)
+		result.Status = entity.AutoModerationFailed
+	case toxicity >= m.cfg.Threshold:
+		result.Status = entity.AutoModerationFlagged
+	}
+
+	if err := m.reviews.reviewRepo.SetAutoModeration(ctx, job.reviewID, job.updatedAt, result); err != nil {
+		if !errors.Is(err, repository.ErrReviewNotFound) {
+			fmt.Printf("failed to save auto moderation of review %s: %v\n", job.reviewID.Hex(), err)
+		}
+		return
+	}
+	metrics.ReviewsAutoModeration.WithLabelValues(string(result.Status)).Inc()
+
+	if result.Status == entity.AutoModerationFlagged {
+		m.flag(ctx, job, toxicity)
+	}
+}
+
+// check вызывает сервис модерации с таймаутом на попытку и экспоненциальной паузой между попытками
+func (m *AutoModerator) check(ctx context.Context, text string) (float64, int, error) {
+	backoff := m.cfg.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, m.cfg.Timeout)
+		toxicity, err := m.provider.Toxicity(attemptCtx, text)
+		cancel()
+		if err == nil || attempt >= m.cfg.MaxAttempts {
+			return toxicity, attempt, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, attempt, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// flag скрывает токсичный отзыв, если он все еще опубликован: решение модератора не перезаписывается
+func (m *AutoModerator) flag(ctx context.Context, job moderationJob, toxicity float64) {
+	review, err := m.reviews.getReview(ctx, job.reviewID.Hex())
+	if err != nil {
+		if !errors.Is(err, ErrReviewNotFound) {
+			fmt.Printf("failed to get review %s for auto moderation: %v\n", job.reviewID.Hex(), err)
+		}
+		return
+	}
+	if review.Status != entity.ReviewStatusPublished && review.Status != "" {
+		return
+	}
+
+	reason := fmt.Sprintf("toxicity %.2f (%s)", toxicity, m.provider.Name())
+	if err := m.reviews.flagReview(ctx, review, reason); err != nil {
+		fmt.Printf("failed to flag review %s: %v\n", job.reviewID.Hex(), err)
+	}
+}
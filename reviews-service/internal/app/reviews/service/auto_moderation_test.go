@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"augustberries/pkg/tenant"
+	"augustberries/reviews-service/internal/app/reviews/entity"
+	"augustberries/reviews-service/internal/app/reviews/repository"
+	"augustberries/reviews-service/internal/app/reviews/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// stubModerationProvider возвращает ошибки из errs по очереди, затем toxicity
+type stubModerationProvider struct {
+	toxicity float64
+	errs     []error
+	calls    atomic.Int32
+}
+
+func (p *stubModerationProvider) Name() string { return "stub" }
+
+func (p *stubModerationProvider) Toxicity(_ context.Context, _ string) (float64, error) {
+	call := int(p.calls.Add(1))
+	if call <= len(p.errs) {
+		return 0, p.errs[call-1]
+	}
+	return p.toxicity, nil
+}
+
+// stubFlags возвращает одно значение для всех флагов
+type stubFlags struct {
+	enabled bool
+}
+
+func (f stubFlags) Enabled(_ context.Context, _, _ string, _ bool) bool { return f.enabled }
+
+var autoModerationNow = time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestAutoModerator(reviewRepo *mocks.MockReviewRepository, publisher *mocks.MockMessagePublisher, provider *stubModerationProvider, flags FlagChecker) *AutoModerator {
+	m := NewAutoModerator(provider, NewReviewService(reviewRepo, publisher, nil), flags, AutoModerationConfig{
+		Workers:      1,
+		QueueSize:    10,
+		Timeout:      time.Second,
+		MaxAttempts:  3,
+		RetryBackoff: time.Millisecond,
+		Threshold:    0.8,
+	})
+	m.now = func() time.Time { return autoModerationNow }
+	return m
+}
+
+func newModerationJob(review *entity.Review) moderationJob {
+	return moderationJob{tenantID: "shop-a", reviewID: review.ID, text: review.Text, updatedAt: review.UpdatedAt}
+}
+
+// ==================== process Tests ====================
+
+func TestAutoModerator_Process_PassedKeepsReviewPublished(t *testing.T) {
+	// Arrange
+	reviewRepo := new(mocks.MockReviewRepository)
+	provider := &stubModerationProvider{toxicity: 0.1}
+	m := newTestAutoModerator(reviewRepo, &mocks.MockMessagePublisher{}, provider, nil)
+
+	review := &entity.Review{ID: primitive.NewObjectID(), Text: "good", UpdatedAt: autoModerationNow.Add(-time.Minute)}
+	reviewRepo.On("SetAutoModeration", tenant.WithID(context.Background(), "shop-a"), review.ID, review.UpdatedAt, &entity.AutoModeration{
+		Status: entity.AutoModerationPassed, Toxicity: 0.1, Provider: "stub", Attempts: 1, CheckedAt: autoModerationNow,
+	}).Return(nil)
+
+	// Act
+	m.process(context.Background(), newModerationJob(review))
+
+	// Assert
+	reviewRepo.AssertExpectations(t)
+	reviewRepo.AssertNotCalled(t, "FlagPublished", mock.Anything, mock.Anything)
+}
+
+func TestAutoModerator_Process_FlagsToxicReview(t *testing.T) {
+	// Arrange
+	reviewRepo := new(mocks.MockReviewRepository)
+	publisher := &mocks.MockMessagePublisher{}
+	m := newTestAutoModerator(reviewRepo, publisher, &stubModerationProvider{toxicity: 0.93}, nil)
+
+	review := &entity.Review{ID: primitive.NewObjectID(), Text: "toxic", Status: entity.ReviewStatusPublished}
+	reviewRepo.On("SetAutoModeration", mock.Anything, review.ID, mock.Anything, mock.MatchedBy(func(result *entity.AutoModeration) bool {
+		return result.Status == entity.AutoModerationFlagged
+	})).Return(nil)
+	reviewRepo.On("GetByID", mock.Anything, review.ID.Hex()).Return(review, nil)
+	reviewRepo.On("FlagPublished", mock.Anything, review).Return(nil)
+	publisher.On("PublishMessage", mock.Anything, review.ID.Hex(), mock.Anything).Return(nil)
+
+	// Act
+	m.process(context.Background(), newModerationJob(review))
+
+	// Assert
+	reviewRepo.AssertExpectations(t)
+	assert.Equal(t, entity.ReviewStatusFlagged, review.Status)
+	assert.Equal(t, "toxicity 0.93 (stub)", review.ModerationReason)
+	assert.Empty(t, review.ModeratedBy)
+}
+
+func TestAutoModerator_Process_KeepsModeratorDecision(t *testing.T) {
+	// Arrange
+	reviewRepo := new(mocks.MockReviewRepository)
+	m := newTestAutoModerator(reviewRepo, &mocks.MockMessagePublisher{}, &stubModerationProvider{toxicity: 0.95}, nil)
+
+	// Модератор успел скрыть отзыв, пока шла проверка
+	review := &entity.Review{ID: primitive.NewObjectID(), Text: "toxic", Status: entity.ReviewStatusHidden}
+	reviewRepo.On("SetAutoModeration", mock.Anything, review.ID, mock.Anything, mock.Anything).Return(nil)
+	reviewRepo.On("GetByID", mock.Anything, review.ID.Hex()).Return(review, nil)
+
+	// Act
+	m.process(context.Background(), newModerationJob(review))
+
+	// Assert
+	assert.Equal(t, entity.ReviewStatusHidden, review.Status)
+	reviewRepo.AssertNotCalled(t, "FlagPublished", mock.Anything, mock.Anything)
+}
+
+func TestAutoModerator_Process_ModeratorDecisionAfterRead(t *testing.T) {
+	// Arrange
+	reviewRepo := new(mocks.MockReviewRepository)
+	publisher := &mocks.MockMessagePublisher{}
+	m := newTestAutoModerator(reviewRepo, publisher, &stubModerationProvider{toxicity: 0.95}, nil)
+
+	// Отзыв прочитан опубликованным, но модератор скрыл его до обновления: условное обновление ничего не находит
+	review := &entity.Review{ID: primitive.NewObjectID(), Text: "toxic", Status: entity.ReviewStatusPublished}
+	reviewRepo.On("SetAutoModeration", mock.Anything, review.ID, mock.Anything, mock.Anything).Return(nil)
+	reviewRepo.On("GetByID", mock.Anything, review.ID.Hex()).Return(review, nil)
+	reviewRepo.On("FlagPublished", mock.Anything, review).Return(repository.ErrReviewNotFound)
+
+	// Act
+	m.process(context.Background(), newModerationJob(review))
+
+	// Assert
+	reviewRepo.AssertExpectations(t)
+	assert.Equal(t, entity.ReviewStatusPublished, review.Status)
+	publisher.AssertNotCalled(t, "PublishMessage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAutoModerator_Process_SkipsEditedReview(t *testing.T) {
+	// Arrange
+	reviewRepo := new(mocks.MockReviewRepository)
+	m := newTestAutoModerator(reviewRepo, &mocks.MockMessagePublisher{}, &stubModerationProvider{toxicity: 0.99}, nil)
+
+	// Текст изменен во время проверки: updated_at не совпадает
+	review := &entity.Review{ID: primitive.NewObjectID(), Text: "toxic"}
+	reviewRepo.On("SetAutoModeration", mock.Anything, review.ID, mock.Anything, mock.Anything).Return(repository.ErrReviewNotFound)
+
+	// Act
+	m.process(context.Background(), newModerationJob(review))
+
+	// Assert
+	reviewRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	reviewRepo.AssertNotCalled(t, "FlagPublished", mock.Anything, mock.Anything)
+}
+
+func TestAutoModerator_Process_RetriesThenSucceeds(t *testing.T) {
+	// Arrange
+	reviewRepo := new(mocks.MockReviewRepository)
+	provider := &stubModerationProvider{toxicity: 0.2, errs: []error{context.DeadlineExceeded}}
+	m := newTestAutoModerator(reviewRepo, &mocks.MockMessagePublisher{}, provider, nil)
+
+	review := &entity.Review{ID: primitive.NewObjectID(), Text: "fine"}
+	reviewRepo.On("SetAutoModeration", mock.Anything, review.ID, mock.Anything, mock.MatchedBy(func(result *entity.AutoModeration) bool {
+		return result.Status == entity.AutoModerationPassed && result.Attempts == 2
+	})).Return(nil)
+
+	// Act
+	m.process(context.Background(), newModerationJob(review))
+
+	// Assert
+	reviewRepo.AssertExpectations(t)
+	assert.Equal(t, int32(2), provider.calls.Load())
+}
+
+func TestAutoModerator_Process_MarksFailedAfterMaxAttempts(t *testing.T) {
+	// Arrange
+	reviewRepo := new(mocks.MockReviewRepository)
+	unavailable := errors.New("moderation API returned status 503")
+	provider := &stubModerationProvider{errs: []error{unavailable, unavailable, unavailable, unavailable}}
+	m := newTestAutoModerator(reviewRepo, &mocks.MockMessagePublisher{}, provider, nil)
+
+	review := &entity.Review{ID: primitive.NewObjectID(), Text: "text"}
+	reviewRepo.On("SetAutoModeration", mock.Anything, review.ID, mock.Anything, mock.MatchedBy(func(result *entity.AutoModeration) bool {
+		return result.Status == entity.AutoModerationFailed && result.Attempts == 3
+	})).Return(nil)
+
+	// Act
+	m.process(context.Background(), newModerationJob(review))
+
+	// Assert
+	reviewRepo.AssertExpectations(t)
+	assert.Equal(t, int32(3), provider.calls.Load())
+	reviewRepo.AssertNotCalled(t, "FlagPublished", mock.Anything, mock.Anything)
+}
+
+func TestAutoModerator_Process_KillSwitch(t *testing.T) {
+	// Arrange
+	reviewRepo := new(mocks.MockReviewRepository)
+	provider := &stubModerationProvider{toxicity: 0.99}
+	m := newTestAutoModerator(reviewRepo, &mocks.MockMessagePublisher{}, provider, stubFlags{enabled: false})
+
+	review := &entity.Review{ID: primitive.NewObjectID(), Text: "toxic"}
+
+	// Act
+	m.process(context.Background(), newModerationJob(review))
+
+	// Assert
+	assert.Zero(t, provider.calls.Load())
+	reviewRepo.AssertNotCalled(t, "SetAutoModeration", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// ==================== Submit Tests ====================
+
+func TestAutoModerator_Submit_KillSwitch(t *testing.T) {
+	// Arrange
+	m := newTestAutoModerator(new(mocks.MockReviewRepository), &mocks.MockMessagePublisher{}, &stubModerationProvider{}, stubFlags{enabled: false})
+
+	// Act
+	submitted := m.Submit(context.Background(), &entity.Review{ID: primitive.NewObjectID()})
+
+	// Assert
+	assert.False(t, submitted)
+	assert.Empty(t, m.jobs)
+}
+
+func TestAutoModerator_Submit_DropsWhenQueueIsFull(t *testing.T) {
+	// Arrange
+	m := NewAutoModerator(&stubModerationProvider{}, nil, stubFlags{enabled: true}, AutoModerationConfig{QueueSize: 1})
+
+	// Act
+	first := m.Submit(context.Background(), &entity.Review{ID: primitive.NewObjectID()})
+	second := m.Submit(context.Background(), &entity.Review{ID: primitive.NewObjectID()})
+
+	// Assert
+	assert.True(t, first)
+	assert.False(t, second)
+}
+
+func TestAutoModerator_Submit_ProcessedByWorkers(t *testing.T) {
+	// Arrange
+	reviewRepo := new(mocks.MockReviewRepository)
+	m := newTestAutoModerator(reviewRepo, &mocks.MockMessagePublisher{}, &stubModerationProvider{toxicity: 0.3}, stubFlags{enabled: true})
+
+	ctx := tenant.WithID(context.Background(), "shop-a")
+	// Время в MongoDB хранится с точностью до миллисекунд
+	review := &entity.Review{ID: primitive.NewObjectID(), Text: "ok", UpdatedAt: time.Date(2026, 6, 1, 11, 0, 0, 123456789, time.UTC)}
+	saved := make(chan struct{})
+	reviewRepo.On("SetAutoModeration", mock.Anything, review.ID, time.Date(2026, 6, 1, 11, 0, 0, 123000000, time.UTC), mock.Anything).
+		Run(func(args mock.Arguments) {
+			assert.Equal(t, "shop-a", tenant.FromContext(args.Get(0).(context.Context)))
+			close(saved)
+		}).Return(nil)
+
+	m.Start(context.Background())
+	defer m.Stop()
+
+	// Act
+	require.True(t, m.Submit(ctx, review))
+
+	// Assert
+	select {
+	case <-saved:
+	case <-time.After(5 * time.Second):
+		t.Fatal("review was not moderated")
+	}
+}
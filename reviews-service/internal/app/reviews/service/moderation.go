@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"augustberries/pkg/tenant"
+	"augustberries/reviews-service/internal/app/reviews/entity"
+	"augustberries/reviews-service/internal/app/reviews/repository"
+)
+
+var (
+	ErrInvalidModerationAction = errors.New("invalid moderation action")
+	ErrModerationReasonMissing = errors.New("reason is required to hide or delete a review")
+)
+
+// ListReviews возвращает страницу отзывов магазина по всем товарам для модерации
+func (s *ReviewService) ListReviews(ctx context.Context, filter entity.ReviewFilter, page entity.Page) (*entity.ReviewPage, error) {
+	result, err := s.reviewRepo.List(ctx, filter, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+	s.attachAuthors(ctx, result.Reviews)
+	return result, nil
+}
+
+// ModerateReview публикует или скрывает отзыв от имени модератора
+func (s *ReviewService) ModerateReview(ctx context.Context, reviewID, moderatorID string, action entity.ModerationAction, reason string) (*entity.Review, error) {
+	var status entity.ReviewStatus
+	switch action {
+	case entity.ModerationActionApprove:
+		status = entity.ReviewStatusPublished
+	case entity.ModerationActionHide:
+		status = entity.ReviewStatusHidden
+		if reason == "" {
+			return nil, ErrModerationReasonMissing
+		}
+	default:
+		return nil, ErrInvalidModerationAction
+	}
+
+	var review *entity.Review
+	err := s.reviewRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		review, err = s.getReview(ctx, reviewID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		review.Status = status
+		review.ModeratedBy = moderatorID
+		review.ModerationReason = reason
+		review.ModeratedAt = &now
+
+		if err := s.reviewRepo.UpdateModeration(ctx, review); err != nil {
+			return fmt.Errorf("failed to moderate review: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishModerationEvent(ctx, entity.EventTypeReviewModerated, review, reason)
+	return review, nil
+}
+
+// AdminDeleteReview удаляет любой отзыв магазина с указанием причины
+// В отличие от DeleteReview автор отзыва не проверяется
+func (s *ReviewService) AdminDeleteReview(ctx context.Context, reviewID, moderatorID, reason string) error {
+	if reason == "" {
+		return ErrModerationReasonMissing
+	}
+
+	var review *entity.Review
+	err := s.reviewRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		review, err = s.getReview(ctx, reviewID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.reviewRepo.Delete(ctx, reviewID); err != nil {
+			return fmt.Errorf("failed to delete review: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("review %s deleted by moderator %s: %s\n", reviewID, moderatorID, reason)
+	s.publishModerationEvent(ctx, entity.EventTypeReviewDeleted, review, reason)
+	return nil
+}
+
+// BulkModerate применяет действие к каждому отзыву из запроса
+// Ошибка одного отзыва не останавливает остальные и попадает в отчет
+func (s *ReviewService) BulkModerate(ctx context.Context, moderatorID string, req *entity.BulkModerationRequest) *entity.BulkModerationResponse {
+	response := &entity.BulkModerationResponse{Results: make([]entity.BulkModerationResult, 0, len(req.ReviewIDs))}
+	seen := make(map[string]struct{}, len(req.ReviewIDs))
+
+	for _, reviewID := range req.ReviewIDs {
+		if _, ok := seen[reviewID]; ok {
+			continue
+		}
+		seen[reviewID] = struct{}{}
+
+		result := entity.BulkModerationResult{ReviewID: reviewID}
+		var err error
+		if req.Action == entity.ModerationActionDelete {
+			err = s.AdminDeleteReview(ctx, reviewID, moderatorID, req.Reason)
+		} else {
+			var review *entity.Review
+			if review, err = s.ModerateReview(ctx, reviewID, moderatorID, req.Action, req.Reason); err == nil {
+				result.Status = review.Status
+			}
+		}
+
+		if err != nil {
+			result.Error = err.Error()
+			response.Failed++
+		} else {
+			result.Success = true
+			response.Succeeded++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	return response
+}
+
+// flagReview скрывает опубликованный отзыв до решения модератора
+// Если отзыв уже не опубликован (модератор успел принять решение) или удален, ничего не делает
+func (s *ReviewService) flagReview(ctx context.Context, review *entity.Review, reason string) error {
+	now := time.Now()
+	review.ModeratedBy = ""
+	review.ModerationReason = reason
+	review.ModeratedAt = &now
+
+	if err := s.reviewRepo.FlagPublished(ctx, review); err != nil {
+		if errors.Is(err, repository.ErrReviewNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to flag review: %w", err)
+	}
+	review.Status = entity.ReviewStatusFlagged
+
+	s.publishModerationEvent(ctx, entity.EventTypeReviewModerated, review, reason)
+	return nil
+}
+
+// getReview читает отзыв и переводит ошибку репозитория в ошибку сервиса
+func (s *ReviewService) getReview(ctx context.Context, reviewID string) (*entity.Review, error) {
+	review, err := s.reviewRepo.GetByID(ctx, reviewID)
+	if err != nil {
+		if errors.Is(err, repository.ErrReviewNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	return review, nil
+}
+
+// publishModerationEvent сообщает о решении модератора, ошибка публикации не отменяет решение
+func (s *ReviewService) publishModerationEvent(ctx context.Context, eventType string, review *entity.Review, reason string) {
+	event := entity.ReviewEvent{
+		EventType: eventType,
+		TenantID:  tenant.FromContext(ctx),
+		ReviewID:  review.ID.Hex(),
+		ProductID: review.ProductID,
+		UserID:    review.UserID,
+		Rating:    review.Rating,
+		Reason:    reason,
+		Timestamp: time.Now(),
+	}
+	if eventType == entity.EventTypeReviewModerated {
+		event.Status = review.Status
+	}
+
+	if err := s.publishReviewEvent(ctx, event); err != nil {
+		fmt.Printf("failed to publish review moderation event: %v\n", err)
+	}
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"augustberries/reviews-service/internal/app/reviews/entity"
+	"augustberries/reviews-service/internal/app/reviews/repository"
+	"augustberries/reviews-service/internal/app/reviews/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ==================== ModerateReview Tests ====================
+
+func TestModerateReview_HideRecordsModerator(t *testing.T) {
+	// Arrange
+	reviewRepo := new(mocks.MockReviewRepository)
+	kafkaProducer := &mocks.MockMessagePublisher{}
+	service := NewReviewService(reviewRepo, kafkaProducer, nil)
+
+	ctx := context.Background()
+	review := &entity.Review{ID: primitive.NewObjectID(), ProductID: "product-1", UserID: "author", Rating: 1, Status: entity.ReviewStatusPublished}
+	reviewRepo.On("GetByID", ctx, review.ID.Hex()).Return(review, nil)
+	reviewRepo.On("UpdateModeration", ctx, review).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, review.ID.Hex(), mock.Anything).Return(nil)
+
+	// Act
+	result, err := service.ModerateReview(ctx, review.ID.Hex(), "moderator", entity.ModerationActionHide, "spam")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, entity.ReviewStatusHidden, result.Status)
+	assert.Equal(t, "moderator", result.ModeratedBy)
+	assert.Equal(t, "spam", result.ModerationReason)
+	assert.NotNil(t, result.ModeratedAt)
+
+	require.Len(t, kafkaProducer.Messages, 1)
+	var event entity.ReviewEvent
+	require.NoError(t, json.Unmarshal(kafkaProducer.Messages[0], &event))
+	assert.Equal(t, entity.EventTypeReviewModerated, event.EventType)
+	assert.Equal(t, entity.ReviewStatusHidden, event.Status)
+}
+
+func TestModerateReview_HideRequiresReason(t *testing.T) {
+	reviewRepo := new(mocks.MockReviewRepository)
+	service := NewReviewService(reviewRepo, &mocks.MockMessagePublisher{}, nil)
+
+	_, err := service.ModerateReview(context.Background(), "review-1", "moderator", entity.ModerationActionHide, "")
+
+	assert.ErrorIs(t, err, ErrModerationReasonMissing)
+	reviewRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestModerateReview_NotFound(t *testing.T) {
+	reviewRepo := new(mocks.MockReviewRepository)
+	service := NewReviewService(reviewRepo, &mocks.MockMessagePublisher{}, nil)
+
+	ctx := context.Background()
+	reviewRepo.On("GetByID", ctx, "missing").Return(nil, repository.ErrReviewNotFound)
+
+	_, err := service.ModerateReview(ctx, "missing", "moderator", entity.ModerationActionApprove, "")
+
+	assert.ErrorIs(t, err, ErrReviewNotFound)
+}
+
+// ==================== AdminDeleteReview Tests ====================
+
+func TestAdminDeleteReview_IgnoresAuthor(t *testing.T) {
+	// Arrange
+	reviewRepo := new(mocks.MockReviewRepository)
+	kafkaProducer := &mocks.MockMessagePublisher{}
+	service := NewReviewService(reviewRepo, kafkaProducer, nil)
+
+	ctx := context.Background()
+	review := &entity.Review{ID: primitive.NewObjectID(), ProductID: "product-1", UserID: "author"}
+	reviewRepo.On("GetByID", ctx, review.ID.Hex()).Return(review, nil)
+	reviewRepo.On("Delete", ctx, review.ID.Hex()).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, review.ID.Hex(), mock.Anything).Return(nil)
+
+	// Act
+	err := service.AdminDeleteReview(ctx, review.ID.Hex(), "admin-1", "offensive language")
+
+	// Assert
+	require.NoError(t, err)
+	var event entity.ReviewEvent
+	require.NoError(t, json.Unmarshal(kafkaProducer.Messages[0], &event))
+	assert.Equal(t, entity.EventTypeReviewDeleted, event.EventType)
+	assert.Equal(t, "offensive language", event.Reason)
+}
+
+// ==================== BulkModerate Tests ====================
+
+func TestBulkModerate_ReportsEachReview(t *testing.T) {
+	// Arrange
+	reviewRepo := new(mocks.MockReviewRepository)
+	kafkaProducer := &mocks.MockMessagePublisher{}
+	service := NewReviewService(reviewRepo, kafkaProducer, nil)
+
+	ctx := context.Background()
+	review := &entity.Review{ID: primitive.NewObjectID(), Status: entity.ReviewStatusHidden}
+	reviewRepo.On("GetByID", ctx, review.ID.Hex()).Return(review, nil)
+	reviewRepo.On("GetByID", ctx, "missing").Return(nil, repository.ErrReviewNotFound)
+	reviewRepo.On("GetByID", ctx, "broken").Return(nil, errors.New("connection reset"))
+	reviewRepo.On("UpdateModeration", ctx, review).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	// Act
+	response := service.BulkModerate(ctx, "moderator", &entity.BulkModerationRequest{
+		ReviewIDs: []string{review.ID.Hex(), "missing", review.ID.Hex(), "broken"},
+		Action:    entity.ModerationActionApprove,
+	})
+
+	// Assert
+	require.Len(t, response.Results, 3)
+	assert.Equal(t, 1, response.Succeeded)
+	assert.Equal(t, 2, response.Failed)
+	assert.True(t, response.Results[0].Success)
+	assert.Equal(t, entity.ReviewStatusPublished, response.Results[0].Status)
+	assert.Equal(t, ErrReviewNotFound.Error(), response.Results[1].Error)
+	assert.Equal(t, "broken", response.Results[2].ReviewID)
+	reviewRepo.AssertNumberOfCalls(t, "UpdateModeration", 1)
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"augustberries/reviews-service/internal/app/reviews/entity"
+	"augustberries/reviews-service/internal/app/reviews/repository"
+)
+
+// ProfileService строит локальные профили авторов по событиям Auth Service
+type ProfileService struct {
+	profileRepo repository.ProfileRepository
+}
+
+func NewProfileService(profileRepo repository.ProfileRepository) *ProfileService {
+	return &ProfileService{profileRepo: profileRepo}
+}
+
+// ApplyUserEvent обновляет профиль по USER_REGISTERED и USER_UPDATED, остальные события пропускаются
+// Сохраняется только публичное имя и аватар, email и роль в профиль не попадают
+func (s *ProfileService) ApplyUserEvent(ctx context.Context, event *entity.UserEvent) error {
+	if event.EventType != entity.EventTypeUserRegistered && event.EventType != entity.EventTypeUserUpdated {
+		return nil
+	}
+	if event.UserID == "" {
+		return nil
+	}
+
+	profile := &entity.UserProfile{
+		UserID:      event.UserID,
+		TenantID:    event.TenantID,
+		DisplayName: publicDisplayName(event.Name),
+		AvatarURL:   event.AvatarURL,
+		UpdatedAt:   event.Timestamp,
+	}
+
+	if err := s.profileRepo.Upsert(ctx, profile); err != nil {
+		return fmt.Errorf("failed to save user profile: %w", err)
+	}
+	return nil
+}
+
+// publicDisplayName сокращает полное имя до имени и инициала фамилии: "Иван Петров" -> "Иван П."
+func publicDisplayName(name string) string {
+	parts := strings.Fields(name)
+	switch len(parts) {
+	case 0:
+		return ""
+	case 1:
+		return parts[0]
+	}
+
+	initial, _ := utf8.DecodeRuneInString(parts[len(parts)-1])
+	return parts[0] + " " + string(initial) + "."
+}
+
+// attachAuthors заполняет публичный профиль авторов одним запросом к профилям
+// Ошибка чтения профилей не мешает выдаче отзывов: они возвращаются без автора
+func (s *ReviewService) attachAuthors(ctx context.Context, reviews []entity.Review) {
+	if s.profileRepo == nil || len(reviews) == 0 {
+		return
+	}
+
+	seen := make(map[string]bool, len(reviews))
+	userIDs := make([]string, 0, len(reviews))
+	for _, review := range reviews {
+		if !seen[review.UserID] {
+			seen[review.UserID] = true
+			userIDs = append(userIDs, review.UserID)
+		}
+	}
+
+	profiles, err := s.profileRepo.GetByIDs(ctx, userIDs)
+	if err != nil {
+		fmt.Printf("failed to get review authors: %v\n", err)
+		return
+	}
+
+	for i := range reviews {
+		if profile, ok := profiles[reviews[i].UserID]; ok && profile.DisplayName != "" {
+			reviews[i].Author = &entity.ReviewAuthor{
+				DisplayName: profile.DisplayName,
+				AvatarURL:   profile.AvatarURL,
+			}
+		}
+	}
+}
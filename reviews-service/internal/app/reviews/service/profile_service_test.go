@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"augustberries/reviews-service/internal/app/reviews/entity"
+	"augustberries/reviews-service/internal/app/reviews/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// ==================== ApplyUserEvent Tests ====================
+
+func TestApplyUserEvent_StoresPublicProfileOnly(t *testing.T) {
+	// Arrange
+	profileRepo := new(mocks.MockProfileRepository)
+	service := NewProfileService(profileRepo)
+
+	ctx := context.Background()
+	timestamp := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	profileRepo.On("Upsert", ctx, &entity.UserProfile{
+		UserID:      "user-1",
+		TenantID:    "shop-a",
+		DisplayName: "Иван П.",
+		AvatarURL:   "https://cdn.example.com/a.png",
+		UpdatedAt:   timestamp,
+	}).Return(nil)
+
+	// Act
+	err := service.ApplyUserEvent(ctx, &entity.UserEvent{
+		EventType: entity.EventTypeUserUpdated,
+		TenantID:  "shop-a",
+		UserID:    "user-1",
+		Name:      "Иван Петров",
+		AvatarURL: "https://cdn.example.com/a.png",
+		Timestamp: timestamp,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	profileRepo.AssertExpectations(t)
+}
+
+func TestApplyUserEvent_IgnoresOtherEvents(t *testing.T) {
+	// Arrange
+	profileRepo := new(mocks.MockProfileRepository)
+	service := NewProfileService(profileRepo)
+
+	// Act
+	err := service.ApplyUserEvent(context.Background(), &entity.UserEvent{EventType: "USER_LOGGED_IN", UserID: "user-1"})
+
+	// Assert
+	require.NoError(t, err)
+	profileRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestPublicDisplayName(t *testing.T) {
+	assert.Equal(t, "Иван П.", publicDisplayName("Иван Петров"))
+	assert.Equal(t, "Anna S.", publicDisplayName("  Anna Maria   Smith "))
+	assert.Equal(t, "Cher", publicDisplayName("Cher"))
+	assert.Empty(t, publicDisplayName("   "))
+}
+
+// ==================== Review Authors Tests ====================
+
+func TestGetReviewsByProduct_AttachesAuthors(t *testing.T) {
+	// Arrange
+	reviewRepo := new(mocks.MockReviewRepository)
+	profileRepo := new(mocks.MockProfileRepository)
+	service := NewReviewService(reviewRepo, &mocks.MockMessagePublisher{}, profileRepo)
+
+	ctx := context.Background()
+	reviews := []entity.Review{
+		{ProductID: "product-1", UserID: "user-1", Rating: 5},
+		{ProductID: "product-1", UserID: "user-2", Rating: 3},
+		{ProductID: "product-1", UserID: "user-1", Rating: 4},
+	}
+	page := entity.Page{Limit: 20}
+	reviewRepo.On("GetByProductID", ctx, "product-1", page).Return(&entity.ReviewPage{Reviews: reviews, Total: 3}, nil)
+	profileRepo.On("GetByIDs", ctx, []string{"user-1", "user-2"}).Return(map[string]entity.UserProfile{
+		"user-1": {UserID: "user-1", DisplayName: "Иван П.", AvatarURL: "https://cdn.example.com/a.png"},
+	}, nil)
+
+	// Act
+	result, err := service.GetReviewsByProduct(ctx, "product-1", page)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, result.Reviews, 3)
+	assert.Equal(t, &entity.ReviewAuthor{DisplayName: "Иван П.", AvatarURL: "https://cdn.example.com/a.png"}, result.Reviews[0].Author)
+	assert.Nil(t, result.Reviews[1].Author)
+	assert.Equal(t, "Иван П.", result.Reviews[2].Author.DisplayName)
+}
+
+func TestGetReviewsByProduct_ProfileErrorKeepsReviews(t *testing.T) {
+	// Arrange
+	reviewRepo := new(mocks.MockReviewRepository)
+	profileRepo := new(mocks.MockProfileRepository)
+	service := NewReviewService(reviewRepo, &mocks.MockMessagePublisher{}, profileRepo)
+
+	ctx := context.Background()
+	page := entity.Page{Limit: 20}
+	reviewRepo.On("GetByProductID", ctx, "product-1", page).Return(&entity.ReviewPage{Reviews: []entity.Review{{UserID: "user-1"}}, Total: 1}, nil)
+	profileRepo.On("GetByIDs", ctx, []string{"user-1"}).Return(nil, errors.New("mongo down"))
+
+	// Act
+	result, err := service.GetReviewsByProduct(ctx, "product-1", page)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, result.Reviews, 1)
+	assert.Nil(t, result.Reviews[0].Author)
+}
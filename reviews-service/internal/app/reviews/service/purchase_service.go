@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"augustberries/reviews-service/internal/app/reviews/entity"
+	"augustberries/reviews-service/internal/app/reviews/repository"
+)
+
+// PurchaseService отмечает доставленные покупки по событиям Orders Service
+// По ним CreateReview проверяет, что автор получил товар, без запросов в Orders Service
+type PurchaseService struct {
+	purchaseRepo repository.PurchaseRepository
+}
+
+func NewPurchaseService(purchaseRepo repository.PurchaseRepository) *PurchaseService {
+	return &PurchaseService{purchaseRepo: purchaseRepo}
+}
+
+// ApplyOrderEvent сохраняет покупки товаров по ORDER_DELIVERED, остальные события заказов пропускаются
+func (s *PurchaseService) ApplyOrderEvent(ctx context.Context, event *entity.OrderEvent) error {
+	if event.EventType != entity.EventTypeOrderDelivered {
+		return nil
+	}
+	if event.UserID == "" || len(event.ProductIDs) == 0 {
+		return nil
+	}
+
+	purchases := make([]entity.Purchase, 0, len(event.ProductIDs))
+	for _, productID := range event.ProductIDs {
+		purchases = append(purchases, entity.Purchase{
+			TenantID:    event.TenantID,
+			UserID:      event.UserID,
+			ProductID:   productID,
+			OrderID:     event.OrderID,
+			DeliveredAt: event.Timestamp,
+		})
+	}
+
+	if err := s.purchaseRepo.Record(ctx, purchases); err != nil {
+		return fmt.Errorf("failed to save purchases: %w", err)
+	}
+	return nil
+}
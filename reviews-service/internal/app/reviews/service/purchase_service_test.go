@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"augustberries/pkg/tenant"
+	"augustberries/reviews-service/internal/app/reviews/entity"
+	"augustberries/reviews-service/internal/app/reviews/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ==================== ApplyOrderEvent Tests ====================
+
+func TestApplyOrderEvent_RecordsDeliveredProducts(t *testing.T) {
+	// Arrange
+	purchaseRepo := new(mocks.MockPurchaseRepository)
+	service := NewPurchaseService(purchaseRepo)
+
+	ctx := context.Background()
+	deliveredAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	purchaseRepo.On("Record", ctx, []entity.Purchase{
+		{TenantID: "shop-a", UserID: "user-1", ProductID: "product-1", OrderID: "order-1", DeliveredAt: deliveredAt},
+		{TenantID: "shop-a", UserID: "user-1", ProductID: "product-2", OrderID: "order-1", DeliveredAt: deliveredAt},
+	}).Return(nil)
+
+	// Act
+	err := service.ApplyOrderEvent(ctx, &entity.OrderEvent{
+		EventType:  entity.EventTypeOrderDelivered,
+		TenantID:   "shop-a",
+		OrderID:    "order-1",
+		UserID:     "user-1",
+		ProductIDs: []string{"product-1", "product-2"},
+		Timestamp:  deliveredAt,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	purchaseRepo.AssertExpectations(t)
+}
+
+func TestApplyOrderEvent_IgnoresOtherEvents(t *testing.T) {
+	// Arrange
+	purchaseRepo := new(mocks.MockPurchaseRepository)
+	service := NewPurchaseService(purchaseRepo)
+
+	// Act
+	err := service.ApplyOrderEvent(context.Background(), &entity.OrderEvent{
+		EventType:  "ORDER_UPDATED",
+		UserID:     "user-1",
+		ProductIDs: []string{"product-1"},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	purchaseRepo.AssertNotCalled(t, "Record", mock.Anything, mock.Anything)
+}
+
+func TestApplyOrderEvent_RepoError(t *testing.T) {
+	// Arrange
+	purchaseRepo := new(mocks.MockPurchaseRepository)
+	service := NewPurchaseService(purchaseRepo)
+	purchaseRepo.On("Record", mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+	// Act
+	err := service.ApplyOrderEvent(context.Background(), &entity.OrderEvent{
+		EventType:  entity.EventTypeOrderDelivered,
+		UserID:     "user-1",
+		ProductIDs: []string{"product-1"},
+	})
+
+	// Assert: ошибка возвращается, чтобы consumer повторил событие
+	assert.Error(t, err)
+}
+
+// ==================== Verified Purchase Tests ====================
+
+func TestCreateReview_VerifiedPurchase(t *testing.T) {
+	tests := []struct {
+		name         string
+		purchased    bool
+		required     bool
+		wantErr      error
+		wantVerified bool
+	}{
+		{name: "purchased", purchased: true, wantVerified: true},
+		{name: "not purchased", purchased: false},
+		{name: "purchased, required", purchased: true, required: true, wantVerified: true},
+		{name: "not purchased, required", purchased: false, required: true, wantErr: ErrPurchaseRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			reviewRepo := new(mocks.MockReviewRepository)
+			purchaseRepo := new(mocks.MockPurchaseRepository)
+			kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+			service := NewReviewService(reviewRepo, kafkaProducer, nil)
+			service.SetPurchases(purchaseRepo, tt.required)
+
+			ctx := tenant.WithID(context.Background(), "shop-a")
+			purchaseRepo.On("Exists", ctx, "user-1", "product-1").Return(tt.purchased, nil)
+			reviewRepo.On("Create", ctx, mock.AnythingOfType("*entity.Review")).Return(nil).Run(func(args mock.Arguments) {
+				args.Get(1).(*entity.Review).ID = primitive.NewObjectID()
+			})
+			kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+			// Act
+			review, err := service.CreateReview(ctx, "user-1", &entity.CreateReviewRequest{ProductID: "product-1", Rating: 5, Text: "Отлично"})
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				reviewRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVerified, review.VerifiedPurchase)
+		})
+	}
+}
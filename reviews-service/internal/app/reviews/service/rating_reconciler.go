@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+
+	"augustberries/pkg/metrics"
+	"augustberries/pkg/recovery"
+	"augustberries/pkg/tenant"
+	"augustberries/reviews-service/internal/app/reviews/entity"
+	"augustberries/reviews-service/internal/app/reviews/infrastructure"
+	"augustberries/reviews-service/internal/app/reviews/repository"
+
+	"github.com/robfig/cron/v3"
+)
+
+// ratingRepairBatchSize - сколько исправленных оценок отправляется в каталог одним запросом
+const ratingRepairBatchSize = 500
+
+// RatingReconciler пересчитывает оценки товаров по отзывам и сверяет их с денормализованными
+// значениями Catalog Service. События отзывов могут теряться или применяться не по порядку,
+// поэтому сверка - источник истины для rating_avg и rating_count в каталоге
+type RatingReconciler struct {
+	reviewRepo repository.ReviewRepository
+	catalog    infrastructure.CatalogClient
+	repair     bool // false - расхождения только логируются
+}
+
+func NewRatingReconciler(reviewRepo repository.ReviewRepository, catalog infrastructure.CatalogClient, repair bool) *RatingReconciler {
+	return &RatingReconciler{
+		reviewRepo: reviewRepo,
+		catalog:    catalog,
+		repair:     repair,
+	}
+}
+
+// Reconcile сверяет оценки всех магазинов с отзывами; ошибка одного магазина не останавливает остальные
+func (r *RatingReconciler) Reconcile(ctx context.Context) (*entity.RatingReconcileResult, error) {
+	tenants, err := r.reviewRepo.ListTenants(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+
+	result := &entity.RatingReconcileResult{}
+	for _, id := range tenants {
+		if err := r.reconcileTenant(tenant.WithID(ctx, id), result); err != nil {
+			log.Printf("ERROR: Failed to reconcile ratings for tenant %s: %v", id, err)
+			continue
+		}
+		result.Tenants++
+	}
+
+	return result, nil
+}
+
+func (r *RatingReconciler) reconcileTenant(ctx context.Context, result *entity.RatingReconcileResult) error {
+	aggregates, err := r.reviewRepo.GetRatingAggregates(ctx)
+	if err != nil {
+		return err
+	}
+	expected := make(map[string]entity.ProductRating, len(aggregates))
+	for _, rating := range aggregates {
+		rating.RatingAvg = roundRating(rating.RatingAvg)
+		expected[rating.ProductID] = rating
+	}
+
+	current, err := r.catalog.GetProductRatings(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get catalog ratings: %w", err)
+	}
+
+	// Товары без опубликованных отзывов должны иметь нулевую оценку
+	// Отзывы на товары, удаленные из каталога, не сверяются
+	var repairs []entity.ProductRating
+	for _, actual := range current {
+		want, ok := expected[actual.ProductID]
+		if !ok {
+			want = entity.ProductRating{ProductID: actual.ProductID}
+		}
+		result.Checked++
+
+		if actual.RatingCount == want.RatingCount && roundRating(actual.RatingAvg) == want.RatingAvg {
+			continue
+		}
+
+		log.Printf("Rating discrepancy for product %s (tenant %s): catalog %.2f/%d, reviews %.2f/%d",
+			actual.ProductID, tenant.FromContext(ctx), actual.RatingAvg, actual.RatingCount, want.RatingAvg, want.RatingCount)
+		metrics.ReviewsRatingDiscrepancies.Inc()
+		result.Discrepancies++
+		repairs = append(repairs, want)
+	}
+
+	if !r.repair {
+		return nil
+	}
+
+	for start := 0; start < len(repairs); start += ratingRepairBatchSize {
+		end := min(start+ratingRepairBatchSize, len(repairs))
+		if err := r.catalog.UpdateProductRatings(ctx, repairs[start:end]); err != nil {
+			return fmt.Errorf("failed to repair catalog ratings: %w", err)
+		}
+		result.Repaired += end - start
+	}
+
+	return nil
+}
+
+// roundRating округляет среднюю оценку до точности каталога (decimal(3,2))
+func roundRating(avg float64) float64 {
+	return math.Round(avg*100) / 100
+}
+
+// RatingReconcileJob запускает сверку оценок по расписанию (по умолчанию ночью)
+type RatingReconcileJob struct {
+	cron       *cron.Cron
+	reconciler *RatingReconciler
+}
+
+// NewRatingReconcileJob создает задание; запуски не накладываются друг на друга
+func NewRatingReconcileJob(reconciler *RatingReconciler) *RatingReconcileJob {
+	c := cron.New(
+		cron.WithLogger(cron.VerbosePrintfLogger(log.Default())),
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)),
+	)
+
+	return &RatingReconcileJob{
+		cron:       c,
+		reconciler: reconciler,
+	}
+}
+
+// Start запускает задание по расписанию
+func (j *RatingReconcileJob) Start(ctx context.Context, schedule string) error {
+	log.Printf("Starting rating reconcile job with schedule: %s", schedule)
+
+	if _, err := j.cron.AddFunc(schedule, recovery.Wrap("reviews-service", "rating_reconcile_job", func() { j.run(ctx) })); err != nil {
+		return err
+	}
+
+	j.cron.Start()
+	return nil
+}
+
+// Stop останавливает задание и ждет завершения текущего запуска
+func (j *RatingReconcileJob) Stop() {
+	log.Println("Stopping rating reconcile job...")
+	<-j.cron.Stop().Done()
+	log.Println("Rating reconcile job stopped")
+}
+
+func (j *RatingReconcileJob) run(ctx context.Context) {
+	result, err := j.reconciler.Reconcile(ctx)
+	if err != nil {
+		log.Printf("ERROR: Failed to reconcile product ratings: %v", err)
+		return
+	}
+	log.Printf("Rating reconcile job: %d tenants, %d products checked, %d discrepancies, %d repaired",
+		result.Tenants, result.Checked, result.Discrepancies, result.Repaired)
+}
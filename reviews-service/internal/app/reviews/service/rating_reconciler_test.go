@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"augustberries/pkg/tenant"
+	"augustberries/reviews-service/internal/app/reviews/entity"
+	"augustberries/reviews-service/internal/app/reviews/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// stubCatalog хранит оценки каталога по магазинам и запоминает исправления
+type stubCatalog struct {
+	ratings map[string][]entity.ProductRating
+	updates map[string][]entity.ProductRating
+	err     error
+}
+
+func (c *stubCatalog) GetProductRatings(ctx context.Context) ([]entity.ProductRating, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.ratings[tenant.FromContext(ctx)], nil
+}
+
+func (c *stubCatalog) UpdateProductRatings(ctx context.Context, ratings []entity.ProductRating) error {
+	if c.updates == nil {
+		c.updates = make(map[string][]entity.ProductRating)
+	}
+	id := tenant.FromContext(ctx)
+	c.updates[id] = append(c.updates[id], ratings...)
+	return nil
+}
+
+// tenantCtx сопоставляет контекст с магазином
+func tenantCtx(id string) interface{} {
+	return mock.MatchedBy(func(ctx context.Context) bool { return tenant.FromContext(ctx) == id })
+}
+
+// ==================== Reconcile Tests ====================
+
+func TestRatingReconciler_Reconcile_RepairsDiscrepancies(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockReviewRepository)
+	mockRepo.On("ListTenants", mock.Anything).Return([]string{"default", "shop"}, nil)
+	mockRepo.On("GetRatingAggregates", tenantCtx("default")).Return([]entity.ProductRating{
+		{ProductID: "p1", RatingAvg: 4.333333, RatingCount: 3}, // Совпадает с каталогом после округления
+		{ProductID: "p2", RatingAvg: 5, RatingCount: 2},        // В каталоге устаревшее значение
+		{ProductID: "gone", RatingAvg: 1, RatingCount: 1},      // Товар удален из каталога
+	}, nil)
+	mockRepo.On("GetRatingAggregates", tenantCtx("shop")).Return([]entity.ProductRating{}, nil)
+
+	catalog := &stubCatalog{ratings: map[string][]entity.ProductRating{
+		"default": {
+			{ProductID: "p1", RatingAvg: 4.33, RatingCount: 3},
+			{ProductID: "p2", RatingAvg: 4, RatingCount: 1},
+			{ProductID: "p3", RatingAvg: 3, RatingCount: 1}, // Отзыв скрыт модератором
+		},
+		"shop": {{ProductID: "s1"}},
+	}}
+
+	reconciler := NewRatingReconciler(mockRepo, catalog, true)
+
+	// Act
+	result, err := reconciler.Reconcile(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, &entity.RatingReconcileResult{Tenants: 2, Checked: 4, Discrepancies: 2, Repaired: 2}, result)
+	assert.Equal(t, []entity.ProductRating{
+		{ProductID: "p2", RatingAvg: 5, RatingCount: 2},
+		{ProductID: "p3"},
+	}, catalog.updates["default"])
+	assert.Empty(t, catalog.updates["shop"])
+}
+
+func TestRatingReconciler_Reconcile_ReportOnly(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockReviewRepository)
+	mockRepo.On("ListTenants", mock.Anything).Return([]string{"default"}, nil)
+	mockRepo.On("GetRatingAggregates", mock.Anything).Return([]entity.ProductRating{{ProductID: "p1", RatingAvg: 5, RatingCount: 1}}, nil)
+
+	catalog := &stubCatalog{ratings: map[string][]entity.ProductRating{"default": {{ProductID: "p1"}}}}
+
+	reconciler := NewRatingReconciler(mockRepo, catalog, false)
+
+	// Act
+	result, err := reconciler.Reconcile(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Discrepancies)
+	assert.Zero(t, result.Repaired)
+	assert.Empty(t, catalog.updates)
+}
+
+func TestRatingReconciler_Reconcile_CatalogErrorSkipsTenant(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockReviewRepository)
+	mockRepo.On("ListTenants", mock.Anything).Return([]string{"default"}, nil)
+	mockRepo.On("GetRatingAggregates", mock.Anything).Return([]entity.ProductRating{}, nil)
+
+	reconciler := NewRatingReconciler(mockRepo, &stubCatalog{err: errors.New("catalog unavailable")}, true)
+
+	// Act
+	result, err := reconciler.Reconcile(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Zero(t, result.Tenants)
+}
+
+func TestRatingReconciler_Reconcile_ListTenantsError(t *testing.T) {
+	mockRepo := new(mocks.MockReviewRepository)
+	mockRepo.On("ListTenants", mock.Anything).Return(nil, errors.New("db error"))
+
+	_, err := NewRatingReconciler(mockRepo, &stubCatalog{}, true).Reconcile(context.Background())
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"augustberries/reviews-service/internal/app/reviews/entity"
+	"augustberries/reviews-service/internal/app/reviews/repository"
+)
+
+var (
+	ErrReportNotFound        = errors.New("report not found")
+	ErrReportAlreadyResolved = errors.New("report already resolved")
+	ErrAlreadyReported       = errors.New("review already reported")
+	ErrReportRateLimited     = errors.New("too many reports, try again later")
+	ErrCannotReportOwnReview = errors.New("cannot report own review")
+)
+
+// ReportConfig - пороги жалоб; нулевые значения отключают соответствующую проверку
+type ReportConfig struct {
+	FlagThreshold int // Открытых жалоб до автоматического скрытия отзыва
+	RateLimit     int // Жалоб от пользователя за RateWindow
+	RateWindow    time.Duration
+}
+
+// ReportService принимает жалобы на отзывы и ведет очередь модерации
+type ReportService struct {
+	reportRepo repository.ReportRepository
+	reviews    *ReviewService
+	cfg        ReportConfig
+	now        func() time.Time
+}
+
+// NewReportService создает сервис жалоб
+// Решения по жалобам выполняются через модерацию ReviewService
+func NewReportService(reportRepo repository.ReportRepository, reviews *ReviewService, cfg ReportConfig) *ReportService {
+	return &ReportService{
+		reportRepo: reportRepo,
+		reviews:    reviews,
+		cfg:        cfg,
+		now:        time.Now,
+	}
+}
+
+// ReportReview принимает жалобу пользователя на отзыв
+// Когда открытых жалоб набирается FlagThreshold, отзыв скрывается до решения модератора
+func (s *ReportService) ReportReview(ctx context.Context, reviewID, reporterID string, req *entity.ReportReviewRequest) (*entity.ReviewReport, error) {
+	if s.cfg.RateLimit > 0 {
+		count, err := s.reportRepo.CountByReporterSince(ctx, reporterID, s.now().Add(-s.cfg.RateWindow))
+		if err != nil {
+			return nil, err
+		}
+		if count >= int64(s.cfg.RateLimit) {
+			return nil, ErrReportRateLimited
+		}
+	}
+
+	review, err := s.reviews.getReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID == reporterID {
+		return nil, ErrCannotReportOwnReview
+	}
+
+	report := &entity.ReviewReport{
+		ReviewID:   reviewID,
+		ProductID:  review.ProductID,
+		ReporterID: reporterID,
+		Reason:     req.Reason,
+		Comment:    req.Comment,
+	}
+	if err := s.reportRepo.Create(ctx, report); err != nil {
+		if errors.Is(err, repository.ErrReportAlreadyExists) {
+			return nil, ErrAlreadyReported
+		}
+		return nil, err
+	}
+
+	// Скрытые модератором отзывы повторно не помечаем
+	if s.cfg.FlagThreshold > 0 && (review.Status == entity.ReviewStatusPublished || review.Status == "") {
+		open, err := s.reportRepo.CountOpenByReview(ctx, reviewID)
+		if err != nil {
+			fmt.Printf("failed to count reports for review %s: %v\n", reviewID, err)
+		} else if open >= int64(s.cfg.FlagThreshold) {
+			reason := fmt.Sprintf("%d open reports", open)
+			if err := s.reviews.flagReview(ctx, review, reason); err != nil {
+				fmt.Printf("failed to flag review %s: %v\n", reviewID, err)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// ListOpenReports возвращает очередь открытых жалоб магазина, старые первыми
+func (s *ReportService) ListOpenReports(ctx context.Context) ([]entity.ReviewReport, error) {
+	return s.reportRepo.ListByStatus(ctx, entity.ReportStatusOpen)
+}
+
+// GetDashboardSummary возвращает размер очереди модерации магазина
+func (s *ReportService) GetDashboardSummary(ctx context.Context) (*entity.DashboardSummary, error) {
+	summary := &entity.DashboardSummary{GeneratedAt: s.now().UTC()}
+
+	var err error
+	if summary.PendingModeration, err = s.reportRepo.CountByStatus(ctx, entity.ReportStatusOpen); err != nil {
+		return nil, err
+	}
+	if summary.FlaggedReviews, err = s.reviews.reviewRepo.CountByStatus(ctx, entity.ReviewStatusFlagged); err != nil {
+		return nil, err
+	}
+	if summary.HiddenReviews, err = s.reviews.reviewRepo.CountByStatus(ctx, entity.ReviewStatusHidden); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// ResolveReport применяет решение модератора к отзыву и закрывает все открытые жалобы на него
+// approve публикует отзыв и отклоняет жалобы, hide и delete закрывают жалобы как обоснованные
+func (s *ReportService) ResolveReport(ctx context.Context, reportID, moderatorID string, req *entity.ResolveReportRequest) (*entity.ResolveReportResponse, error) {
+	report, err := s.reportRepo.GetByID(ctx, reportID)
+	if err != nil {
+		if errors.Is(err, repository.ErrReportNotFound) {
+			return nil, ErrReportNotFound
+		}
+		return nil, err
+	}
+	if report.Status != entity.ReportStatusOpen {
+		return nil, ErrReportAlreadyResolved
+	}
+
+	// Без заметки модератора причиной скрытия или удаления считается категория жалобы
+	reason := req.Note
+	if reason == "" {
+		reason = string(report.Reason)
+	}
+
+	status := entity.ReportStatusActioned
+	switch req.Action {
+	case entity.ModerationActionApprove:
+		status = entity.ReportStatusDismissed
+		_, err = s.reviews.ModerateReview(ctx, report.ReviewID, moderatorID, req.Action, req.Note)
+	case entity.ModerationActionHide:
+		_, err = s.reviews.ModerateReview(ctx, report.ReviewID, moderatorID, req.Action, reason)
+	case entity.ModerationActionDelete:
+		err = s.reviews.AdminDeleteReview(ctx, report.ReviewID, moderatorID, reason)
+	default:
+		return nil, ErrInvalidModerationAction
+	}
+
+	// Отзыв уже удален автором: жалобы просто закрываются
+	if errors.Is(err, ErrReviewNotFound) {
+		status, err = entity.ReportStatusActioned, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, err := s.reportRepo.ResolveOpenByReview(ctx, report.ReviewID, status, moderatorID, req.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.ResolveReportResponse{
+		ReviewID: report.ReviewID,
+		Status:   status,
+		Resolved: resolved,
+	}, nil
+}
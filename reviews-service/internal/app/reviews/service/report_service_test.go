@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"augustberries/reviews-service/internal/app/reviews/entity"
+	"augustberries/reviews-service/internal/app/reviews/repository"
+	"augustberries/reviews-service/internal/app/reviews/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newTestReportService(cfg ReportConfig) (*ReportService, *mocks.MockReportRepository, *mocks.MockReviewRepository, *mocks.MockMessagePublisher) {
+	reportRepo := new(mocks.MockReportRepository)
+	reviewRepo := new(mocks.MockReviewRepository)
+	kafkaProducer := &mocks.MockMessagePublisher{}
+	svc := NewReportService(reportRepo, NewReviewService(reviewRepo, kafkaProducer, nil), cfg)
+	svc.now = func() time.Time { return time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC) }
+	return svc, reportRepo, reviewRepo, kafkaProducer
+}
+
+// ==================== ReportReview Tests ====================
+
+func TestReportReview_FlagsAfterThreshold(t *testing.T) {
+	// Arrange
+	svc, reportRepo, reviewRepo, kafkaProducer := newTestReportService(ReportConfig{FlagThreshold: 3, RateLimit: 10, RateWindow: time.Hour})
+
+	ctx := context.Background()
+	review := &entity.Review{ID: primitive.NewObjectID(), ProductID: "product-1", UserID: "author", Status: entity.ReviewStatusPublished}
+	reviewID := review.ID.Hex()
+
+	reportRepo.On("CountByReporterSince", ctx, "reporter", time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC)).Return(int64(2), nil)
+	reviewRepo.On("GetByID", ctx, reviewID).Return(review, nil)
+	reportRepo.On("Create", ctx, mock.AnythingOfType("*entity.ReviewReport")).Return(nil)
+	reportRepo.On("CountOpenByReview", ctx, reviewID).Return(int64(3), nil)
+	reviewRepo.On("FlagPublished", ctx, review).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, reviewID, mock.Anything).Return(nil)
+
+	// Act
+	report, err := svc.ReportReview(ctx, reviewID, "reporter", &entity.ReportReviewRequest{Reason: entity.ReportReasonSpam})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "product-1", report.ProductID)
+	assert.Equal(t, entity.ReportReasonSpam, report.Reason)
+	assert.Equal(t, entity.ReviewStatusFlagged, review.Status)
+	reviewRepo.AssertExpectations(t)
+}
+
+func TestReportReview_BelowThresholdKeepsReview(t *testing.T) {
+	svc, reportRepo, reviewRepo, _ := newTestReportService(ReportConfig{FlagThreshold: 3})
+
+	ctx := context.Background()
+	review := &entity.Review{ID: primitive.NewObjectID(), UserID: "author"}
+	reviewRepo.On("GetByID", ctx, review.ID.Hex()).Return(review, nil)
+	reportRepo.On("Create", ctx, mock.Anything).Return(nil)
+	reportRepo.On("CountOpenByReview", ctx, review.ID.Hex()).Return(int64(1), nil)
+
+	_, err := svc.ReportReview(ctx, review.ID.Hex(), "reporter", &entity.ReportReviewRequest{Reason: entity.ReportReasonFake})
+
+	require.NoError(t, err)
+	reviewRepo.AssertNotCalled(t, "FlagPublished", mock.Anything, mock.Anything)
+}
+
+func TestReportReview_RateLimited(t *testing.T) {
+	svc, reportRepo, reviewRepo, _ := newTestReportService(ReportConfig{RateLimit: 5, RateWindow: time.Hour})
+
+	reportRepo.On("CountByReporterSince", mock.Anything, "reporter", mock.Anything).Return(int64(5), nil)
+
+	_, err := svc.ReportReview(context.Background(), "review-1", "reporter", &entity.ReportReviewRequest{Reason: entity.ReportReasonSpam})
+
+	assert.ErrorIs(t, err, ErrReportRateLimited)
+	reviewRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestReportReview_OwnReview(t *testing.T) {
+	svc, _, reviewRepo, _ := newTestReportService(ReportConfig{})
+
+	review := &entity.Review{ID: primitive.NewObjectID(), UserID: "author"}
+	reviewRepo.On("GetByID", mock.Anything, review.ID.Hex()).Return(review, nil)
+
+	_, err := svc.ReportReview(context.Background(), review.ID.Hex(), "author", &entity.ReportReviewRequest{Reason: entity.ReportReasonOther})
+
+	assert.ErrorIs(t, err, ErrCannotReportOwnReview)
+}
+
+func TestReportReview_Duplicate(t *testing.T) {
+	svc, reportRepo, reviewRepo, _ := newTestReportService(ReportConfig{})
+
+	review := &entity.Review{ID: primitive.NewObjectID(), UserID: "author"}
+	reviewRepo.On("GetByID", mock.Anything, review.ID.Hex()).Return(review, nil)
+	reportRepo.On("Create", mock.Anything, mock.Anything).Return(repository.ErrReportAlreadyExists)
+
+	_, err := svc.ReportReview(context.Background(), review.ID.Hex(), "reporter", &entity.ReportReviewRequest{Reason: entity.ReportReasonSpam})
+
+	assert.ErrorIs(t, err, ErrAlreadyReported)
+}
+
+// ==================== ResolveReport Tests ====================
+
+func TestResolveReport_ApproveDismissesReports(t *testing.T) {
+	// Arrange
+	svc, reportRepo, reviewRepo, kafkaProducer := newTestReportService(ReportConfig{})
+
+	ctx := context.Background()
+	review := &entity.Review{ID: primitive.NewObjectID(), Status: entity.ReviewStatusFlagged}
+	report := &entity.ReviewReport{ID: primitive.NewObjectID(), ReviewID: review.ID.Hex(), Status: entity.ReportStatusOpen}
+
+	reportRepo.On("GetByID", ctx, report.ID.Hex()).Return(report, nil)
+	reviewRepo.On("GetByID", ctx, review.ID.Hex()).Return(review, nil)
+	reviewRepo.On("UpdateModeration", ctx, review).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	reportRepo.On("ResolveOpenByReview", ctx, review.ID.Hex(), entity.ReportStatusDismissed, "moderator", "").Return(int64(3), nil)
+
+	// Act
+	response, err := svc.ResolveReport(ctx, report.ID.Hex(), "moderator", &entity.ResolveReportRequest{Action: entity.ModerationActionApprove})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, entity.ReportStatusDismissed, response.Status)
+	assert.Equal(t, int64(3), response.Resolved)
+	assert.Equal(t, entity.ReviewStatusPublished, review.Status)
+}
+
+func TestResolveReport_HideUsesReportReason(t *testing.T) {
+	svc, reportRepo, reviewRepo, kafkaProducer := newTestReportService(ReportConfig{})
+
+	ctx := context.Background()
+	review := &entity.Review{ID: primitive.NewObjectID(), Status: entity.ReviewStatusFlagged}
+	report := &entity.ReviewReport{ID: primitive.NewObjectID(), ReviewID: review.ID.Hex(), Reason: entity.ReportReasonOffensive, Status: entity.ReportStatusOpen}
+
+	reportRepo.On("GetByID", ctx, report.ID.Hex()).Return(report, nil)
+	reviewRepo.On("GetByID", ctx, review.ID.Hex()).Return(review, nil)
+	reviewRepo.On("UpdateModeration", ctx, review).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	reportRepo.On("ResolveOpenByReview", ctx, review.ID.Hex(), entity.ReportStatusActioned, "moderator", "").Return(int64(1), nil)
+
+	_, err := svc.ResolveReport(ctx, report.ID.Hex(), "moderator", &entity.ResolveReportRequest{Action: entity.ModerationActionHide})
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.ReviewStatusHidden, review.Status)
+	assert.Equal(t, "offensive", review.ModerationReason)
+}
+
+func TestResolveReport_DeletedReviewClosesReports(t *testing.T) {
+	svc, reportRepo, reviewRepo, _ := newTestReportService(ReportConfig{})
+
+	ctx := context.Background()
+	report := &entity.ReviewReport{ID: primitive.NewObjectID(), ReviewID: "gone", Status: entity.ReportStatusOpen}
+
+	reportRepo.On("GetByID", ctx, report.ID.Hex()).Return(report, nil)
+	reviewRepo.On("GetByID", ctx, "gone").Return(nil, repository.ErrReviewNotFound)
+	reportRepo.On("ResolveOpenByReview", ctx, "gone", entity.ReportStatusActioned, "moderator", "").Return(int64(2), nil)
+
+	response, err := svc.ResolveReport(ctx, report.ID.Hex(), "moderator", &entity.ResolveReportRequest{Action: entity.ModerationActionApprove})
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.ReportStatusActioned, response.Status)
+}
+
+func TestResolveReport_AlreadyResolved(t *testing.T) {
+	svc, reportRepo, _, _ := newTestReportService(ReportConfig{})
+
+	report := &entity.ReviewReport{ID: primitive.NewObjectID(), Status: entity.ReportStatusDismissed}
+	reportRepo.On("GetByID", mock.Anything, report.ID.Hex()).Return(report, nil)
+
+	_, err := svc.ResolveReport(context.Background(), report.ID.Hex(), "moderator", &entity.ResolveReportRequest{Action: entity.ModerationActionHide})
+
+	assert.ErrorIs(t, err, ErrReportAlreadyResolved)
+}
+
+// ==================== Dashboard Summary Tests ====================
+
+func TestGetDashboardSummary_CountsModerationQueue(t *testing.T) {
+	svc, reportRepo, reviewRepo, _ := newTestReportService(ReportConfig{})
+
+	ctx := context.Background()
+	reportRepo.On("CountByStatus", ctx, entity.ReportStatusOpen).Return(int64(7), nil)
+	reviewRepo.On("CountByStatus", ctx, entity.ReviewStatusFlagged).Return(int64(2), nil)
+	reviewRepo.On("CountByStatus", ctx, entity.ReviewStatusHidden).Return(int64(4), nil)
+
+	summary, err := svc.GetDashboardSummary(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), summary.PendingModeration)
+	assert.Equal(t, int64(2), summary.FlaggedReviews)
+	assert.Equal(t, int64(4), summary.HiddenReviews)
+}
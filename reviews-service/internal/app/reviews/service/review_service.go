@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"time"
 
+	"augustberries/pkg/kafka"
 	"augustberries/pkg/metrics"
+	"augustberries/pkg/tenant"
 	"augustberries/reviews-service/internal/app/reviews/entity"
 	"augustberries/reviews-service/internal/app/reviews/infrastructure"
 	"augustberries/reviews-service/internal/app/reviews/repository"
@@ -16,23 +18,55 @@ import (
 var (
 	ErrReviewNotFound = errors.New("review not found")
 	ErrUnauthorized   = errors.New("unauthorized access to review")
+	// ErrEditWindowClosed - отзыв можно изменять только в течение окна после создания (SetEditWindow)
+	ErrEditWindowClosed = errors.New("review edit window has expired")
+	// ErrPurchaseRequired - отзыв может оставить только покупатель, которому товар доставлен (SetPurchases)
+	ErrPurchaseRequired = errors.New("review requires a delivered purchase")
 )
 
 type ReviewService struct {
 	reviewRepo    repository.ReviewRepository
 	kafkaProducer infrastructure.MessagePublisher
+	profileRepo   repository.ProfileRepository
+	editWindow    time.Duration // Сколько автор может изменять отзыв после создания, 0 - без ограничения
+	// purchaseRepo - доставленные покупки по событиям Orders Service, nil - покупка не проверяется
+	purchaseRepo    repository.PurchaseRepository
+	requirePurchase bool // Отзыв без доставленной покупки отклоняется, иначе только не получает отметку
+	// moderator - проверка текста внешним сервисом модерации, nil - отзывы не проверяются
+	moderator *AutoModerator
 }
 
+// NewReviewService создает сервис отзывов
+// profileRepo может быть nil - тогда отзывы выдаются без публичного профиля автора
 func NewReviewService(
 	reviewRepo repository.ReviewRepository,
 	kafkaProducer infrastructure.MessagePublisher,
+	profileRepo repository.ProfileRepository,
 ) *ReviewService {
 	return &ReviewService{
 		reviewRepo:    reviewRepo,
 		kafkaProducer: kafkaProducer,
+		profileRepo:   profileRepo,
 	}
 }
 
+// SetEditWindow ограничивает время, в течение которого автор может изменять отзыв, 0 - без ограничения
+func (s *ReviewService) SetEditWindow(window time.Duration) {
+	s.editWindow = window
+}
+
+// SetPurchases включает проверку доставленной покупки при создании отзыва
+// Отзыв покупателя получает отметку verified_purchase; с required отзыв без покупки отклоняется
+func (s *ReviewService) SetPurchases(purchaseRepo repository.PurchaseRepository, required bool) {
+	s.purchaseRepo = purchaseRepo
+	s.requirePurchase = required
+}
+
+// SetAutoModerator включает проверку новых и измененных отзывов внешним сервисом модерации
+func (s *ReviewService) SetAutoModerator(moderator *AutoModerator) {
+	s.moderator = moderator
+}
+
 func (s *ReviewService) CreateReview(ctx context.Context, userID string, req *entity.CreateReviewRequest) (*entity.Review, error) {
 	review := &entity.Review{
 		ProductID: req.ProductID,
@@ -41,12 +75,32 @@ func (s *ReviewService) CreateReview(ctx context.Context, userID string, req *en
 		Text:      req.Text,
 	}
 
-	if err := s.reviewRepo.Create(ctx, review); err != nil {
-		return nil, fmt.Errorf("failed to create review: %w", err)
+	if s.purchaseRepo != nil {
+		verified, err := s.purchaseRepo.Exists(ctx, userID, req.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check purchase: %w", err)
+		}
+		if !verified && s.requirePurchase {
+			return nil, ErrPurchaseRequired
+		}
+		review.VerifiedPurchase = verified
+	}
+
+	// Запись отзыва и связанных с ним данных выполняется в одной транзакции,
+	// событие публикуется только после commit
+	err := s.reviewRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.reviewRepo.Create(ctx, review); err != nil {
+			return fmt.Errorf("failed to create review: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	event := entity.ReviewEvent{
-		EventType: "REVIEW_CREATED",
+		EventType: entity.EventTypeReviewCreated,
+		TenantID:  tenant.FromContext(ctx),
 		ReviewID:  review.ID.Hex(),
 		ProductID: review.ProductID,
 		UserID:    review.UserID,
@@ -61,15 +115,30 @@ func (s *ReviewService) CreateReview(ctx context.Context, userID string, req *en
 	metrics.ReviewsCreated.Inc()
 	metrics.ReviewsRating.WithLabelValues().Observe(float64(review.Rating))
 
+	if s.moderator != nil {
+		s.moderator.Submit(ctx, review)
+	}
+
 	return review, nil
 }
 
-func (s *ReviewService) GetReviewsByProduct(ctx context.Context, productID string) ([]entity.Review, error) {
-	reviews, err := s.reviewRepo.GetByProductID(ctx, productID)
+// GetReviewsByProduct возвращает страницу опубликованных отзывов товара с авторами
+func (s *ReviewService) GetReviewsByProduct(ctx context.Context, productID string, page entity.Page) (*entity.ReviewPage, error) {
+	result, err := s.reviewRepo.GetByProductID(ctx, productID, page)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get reviews: %w", err)
 	}
-	return reviews, nil
+	s.attachAuthors(ctx, result.Reviews)
+	return result, nil
+}
+
+// GetRatingSummary возвращает сводку оценок и тональности опубликованных отзывов товара
+func (s *ReviewService) GetRatingSummary(ctx context.Context, productID string) (*entity.RatingSummary, error) {
+	summary, err := s.reviewRepo.GetRatingSummary(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rating summary: %w", err)
+	}
+	return summary, nil
 }
 
 func (s *ReviewService) GetReview(ctx context.Context, reviewID string) (*entity.Review, error) {
@@ -80,62 +149,131 @@ func (s *ReviewService) GetReview(ctx context.Context, reviewID string) (*entity
 		}
 		return nil, fmt.Errorf("failed to get review: %w", err)
 	}
-	return review, nil
+	enriched := []entity.Review{*review}
+	s.attachAuthors(ctx, enriched)
+	return &enriched[0], nil
 }
 
 func (s *ReviewService) UpdateReview(ctx context.Context, reviewID string, userID string, req *entity.UpdateReviewRequest) (*entity.Review, error) {
-	review, err := s.reviewRepo.GetByID(ctx, reviewID)
-	if err != nil {
-		if errors.Is(err, repository.ErrReviewNotFound) {
-			return nil, ErrReviewNotFound
+	var review *entity.Review
+	textChanged := false
+
+	// Чтение, проверка владельца и запись в одной транзакции, чтобы не перезаписать параллельное изменение
+	err := s.reviewRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		review, err = s.reviewRepo.GetByID(ctx, reviewID)
+		if err != nil {
+			if errors.Is(err, repository.ErrReviewNotFound) {
+				return ErrReviewNotFound
+			}
+			return fmt.Errorf("failed to get review: %w", err)
 		}
-		return nil, fmt.Errorf("failed to get review: %w", err)
-	}
 
-	if review.UserID != userID {
-		return nil, ErrUnauthorized
-	}
+		if review.UserID != userID {
+			return ErrUnauthorized
+		}
 
-	if req.Rating > 0 {
-		review.Rating = req.Rating
-	}
-	if req.Text != "" {
-		review.Text = req.Text
+		if s.editWindow > 0 && time.Since(review.CreatedAt) > s.editWindow {
+			return ErrEditWindowClosed
+		}
+
+		// Прежняя версия сохраняется в истории изменений
+		previous := entity.ReviewVersion{
+			Rating:    review.Rating,
+			Text:      review.Text,
+			WrittenAt: review.UpdatedAt,
+		}
+
+		if req.Rating.Set {
+			review.Rating = req.Rating.Value
+		}
+		if req.Text.Set {
+			review.Text = req.Text.Value
+		}
+
+		// Изменение без новых данных не создает версию в истории
+		if review.Rating == previous.Rating && review.Text == previous.Text {
+			return nil
+		}
+
+		if err := s.reviewRepo.Update(ctx, review, previous); err != nil {
+			return fmt.Errorf("failed to update review: %w", err)
+		}
+		textChanged = review.Text != previous.Text
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if err := s.reviewRepo.Update(ctx, review); err != nil {
-		return nil, fmt.Errorf("failed to update review: %w", err)
+	// Проверка прежнего текста сброшена при сохранении, новый текст проверяется заново
+	if textChanged && s.moderator != nil {
+		s.moderator.Submit(ctx, review)
 	}
 
 	return review, nil
 }
 
-func (s *ReviewService) DeleteReview(ctx context.Context, reviewID string, userID string) error {
+// GetReviewHistory возвращает прежние версии отзыва
+// Историю видит автор отзыва, а с moderator=true - любой модератор магазина
+func (s *ReviewService) GetReviewHistory(ctx context.Context, reviewID string, userID string, moderator bool) (*entity.ReviewHistory, error) {
 	review, err := s.reviewRepo.GetByID(ctx, reviewID)
 	if err != nil {
 		if errors.Is(err, repository.ErrReviewNotFound) {
-			return ErrReviewNotFound
+			return nil, ErrReviewNotFound
 		}
-		return fmt.Errorf("failed to get review: %w", err)
+		return nil, fmt.Errorf("failed to get review: %w", err)
 	}
 
-	if review.UserID != userID {
-		return ErrUnauthorized
+	if !moderator && review.UserID != userID {
+		return nil, ErrUnauthorized
 	}
 
-	if err := s.reviewRepo.Delete(ctx, reviewID); err != nil {
-		return fmt.Errorf("failed to delete review: %w", err)
+	versions := review.History
+	if versions == nil {
+		versions = []entity.ReviewVersion{}
 	}
 
-	return nil
+	return &entity.ReviewHistory{
+		ReviewID: review.ID.Hex(),
+		Current: entity.ReviewVersion{
+			Rating:    review.Rating,
+			Text:      review.Text,
+			WrittenAt: review.UpdatedAt,
+		},
+		Versions: versions,
+	}, nil
+}
+
+func (s *ReviewService) DeleteReview(ctx context.Context, reviewID string, userID string) error {
+	return s.reviewRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		review, err := s.reviewRepo.GetByID(ctx, reviewID)
+		if err != nil {
+			if errors.Is(err, repository.ErrReviewNotFound) {
+				return ErrReviewNotFound
+			}
+			return fmt.Errorf("failed to get review: %w", err)
+		}
+
+		if review.UserID != userID {
+			return ErrUnauthorized
+		}
+
+		if err := s.reviewRepo.Delete(ctx, reviewID); err != nil {
+			return fmt.Errorf("failed to delete review: %w", err)
+		}
+		return nil
+	})
 }
 
-func (s *ReviewService) GetUserReviews(ctx context.Context, userID string) ([]entity.Review, error) {
-	reviews, err := s.reviewRepo.GetByUserID(ctx, userID)
+// GetUserReviews возвращает страницу отзывов пользователя с авторами
+func (s *ReviewService) GetUserReviews(ctx context.Context, userID string, page entity.Page) (*entity.ReviewPage, error) {
+	result, err := s.reviewRepo.GetByUserID(ctx, userID, page)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user reviews: %w", err)
 	}
-	return reviews, nil
+	s.attachAuthors(ctx, result.Reviews)
+	return result, nil
 }
 
 func (s *ReviewService) publishReviewEvent(ctx context.Context, event entity.ReviewEvent) error {
@@ -144,6 +282,7 @@ func (s *ReviewService) publishReviewEvent(ctx context.Context, event entity.Rev
 		return fmt.Errorf("failed to marshal review event: %w", err)
 	}
 
+	ctx = kafka.WithEvent(ctx, event.EventType, entity.EventSchemaVersion)
 	if err := s.kafkaProducer.PublishMessage(ctx, event.ReviewID, eventData); err != nil {
 		return fmt.Errorf("failed to publish to kafka: %w", err)
 	}
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"augustberries/pkg/patch"
 	"augustberries/reviews-service/internal/app/reviews/entity"
 	"augustberries/reviews-service/internal/app/reviews/repository"
 	"augustberries/reviews-service/internal/app/reviews/repository/mocks"
@@ -18,7 +19,7 @@ import (
 func TestCreateReview_Success(t *testing.T) {
 	reviewRepo := new(mocks.MockReviewRepository)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
-	service := NewReviewService(reviewRepo, kafkaProducer)
+	service := NewReviewService(reviewRepo, kafkaProducer, nil)
 
 	ctx := context.Background()
 	userID := "user-123"
@@ -28,7 +29,7 @@ func TestCreateReview_Success(t *testing.T) {
 		review := args.Get(1).(*entity.Review)
 		review.ID = primitive.NewObjectID()
 	})
-	kafkaProducer.On("PublishMessage", ctx, mock.Anything, mock.Anything).Return(nil)
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	result, err := service.CreateReview(ctx, userID, req)
 
@@ -41,7 +42,7 @@ func TestCreateReview_Success(t *testing.T) {
 func TestCreateReview_RepoError(t *testing.T) {
 	reviewRepo := new(mocks.MockReviewRepository)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
-	service := NewReviewService(reviewRepo, kafkaProducer)
+	service := NewReviewService(reviewRepo, kafkaProducer, nil)
 
 	ctx := context.Background()
 	req := &entity.CreateReviewRequest{ProductID: "product-456", Rating: 4, Text: "Good product."}
@@ -57,7 +58,7 @@ func TestCreateReview_RepoError(t *testing.T) {
 func TestCreateReview_KafkaErrorIgnored(t *testing.T) {
 	reviewRepo := new(mocks.MockReviewRepository)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
-	service := NewReviewService(reviewRepo, kafkaProducer)
+	service := NewReviewService(reviewRepo, kafkaProducer, nil)
 
 	ctx := context.Background()
 	req := &entity.CreateReviewRequest{ProductID: "product-456", Rating: 3, Text: "Average product."}
@@ -66,7 +67,7 @@ func TestCreateReview_KafkaErrorIgnored(t *testing.T) {
 		review := args.Get(1).(*entity.Review)
 		review.ID = primitive.NewObjectID()
 	})
-	kafkaProducer.On("PublishMessage", ctx, mock.Anything, mock.Anything).Return(errors.New("kafka error"))
+	kafkaProducer.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("kafka error"))
 
 	result, err := service.CreateReview(ctx, "user-123", req)
 
@@ -77,7 +78,7 @@ func TestCreateReview_KafkaErrorIgnored(t *testing.T) {
 func TestGetReviewsByProduct_Success(t *testing.T) {
 	reviewRepo := new(mocks.MockReviewRepository)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
-	service := NewReviewService(reviewRepo, kafkaProducer)
+	service := NewReviewService(reviewRepo, kafkaProducer, nil)
 
 	ctx := context.Background()
 	productID := "product-456"
@@ -86,32 +87,36 @@ func TestGetReviewsByProduct_Success(t *testing.T) {
 		{ID: primitive.NewObjectID(), ProductID: productID, UserID: "user-2", Rating: 4},
 	}
 
-	reviewRepo.On("GetByProductID", ctx, productID).Return(reviews, nil)
+	page := entity.Page{Offset: 20, Limit: 20}
+	reviewRepo.On("GetByProductID", ctx, productID, page).Return(&entity.ReviewPage{Reviews: reviews, Total: 22}, nil)
 
-	result, err := service.GetReviewsByProduct(ctx, productID)
+	result, err := service.GetReviewsByProduct(ctx, productID, page)
 
 	assert.NoError(t, err)
-	assert.Len(t, result, 2)
+	assert.Len(t, result.Reviews, 2)
+	assert.Equal(t, 22, result.Total)
 }
 
 func TestGetReviewsByProduct_Empty(t *testing.T) {
 	reviewRepo := new(mocks.MockReviewRepository)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
-	service := NewReviewService(reviewRepo, kafkaProducer)
+	service := NewReviewService(reviewRepo, kafkaProducer, nil)
 
 	ctx := context.Background()
-	reviewRepo.On("GetByProductID", ctx, "no-reviews").Return([]entity.Review{}, nil)
+	page := entity.Page{Limit: 20}
+	reviewRepo.On("GetByProductID", ctx, "no-reviews", page).Return(&entity.ReviewPage{Reviews: []entity.Review{}}, nil)
 
-	result, err := service.GetReviewsByProduct(ctx, "no-reviews")
+	result, err := service.GetReviewsByProduct(ctx, "no-reviews", page)
 
 	assert.NoError(t, err)
-	assert.Empty(t, result)
+	assert.Empty(t, result.Reviews)
+	assert.Zero(t, result.Total)
 }
 
 func TestGetReview_Success(t *testing.T) {
 	reviewRepo := new(mocks.MockReviewRepository)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
-	service := NewReviewService(reviewRepo, kafkaProducer)
+	service := NewReviewService(reviewRepo, kafkaProducer, nil)
 
 	ctx := context.Background()
 	reviewID := primitive.NewObjectID()
@@ -128,7 +133,7 @@ func TestGetReview_Success(t *testing.T) {
 func TestGetReview_NotFound(t *testing.T) {
 	reviewRepo := new(mocks.MockReviewRepository)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
-	service := NewReviewService(reviewRepo, kafkaProducer)
+	service := NewReviewService(reviewRepo, kafkaProducer, nil)
 
 	ctx := context.Background()
 	reviewID := primitive.NewObjectID().Hex()
@@ -145,35 +150,124 @@ func TestGetReview_NotFound(t *testing.T) {
 func TestUpdateReview_Success(t *testing.T) {
 	reviewRepo := new(mocks.MockReviewRepository)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
-	service := NewReviewService(reviewRepo, kafkaProducer)
+	service := NewReviewService(reviewRepo, kafkaProducer, nil)
 
 	ctx := context.Background()
 	reviewID := primitive.NewObjectID()
 	userID := "user-123"
 	existing := &entity.Review{ID: reviewID, ProductID: "product-456", UserID: userID, Rating: 3, Text: "Old text"}
-	req := &entity.UpdateReviewRequest{Rating: 5, Text: "Updated text"}
+	req := &entity.UpdateReviewRequest{Rating: patch.Of(5), Text: patch.Of("Updated text")}
 
 	reviewRepo.On("GetByID", ctx, reviewID.Hex()).Return(existing, nil)
-	reviewRepo.On("Update", ctx, mock.AnythingOfType("*entity.Review")).Return(nil)
+	reviewRepo.On("Update", ctx, mock.AnythingOfType("*entity.Review"), mock.AnythingOfType("entity.ReviewVersion")).Return(nil)
 
 	result, err := service.UpdateReview(ctx, reviewID.Hex(), userID, req)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 5, result.Rating)
 	assert.Equal(t, "Updated text", result.Text)
+
+	// Прежняя версия передается репозиторию для истории изменений
+	previous := reviewRepo.Calls[1].Arguments.Get(2).(entity.ReviewVersion)
+	assert.Equal(t, 3, previous.Rating)
+	assert.Equal(t, "Old text", previous.Text)
+}
+
+func TestUpdateReview_NoChangesSkipsUpdate(t *testing.T) {
+	reviewRepo := new(mocks.MockReviewRepository)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+	service := NewReviewService(reviewRepo, kafkaProducer, nil)
+
+	ctx := context.Background()
+	reviewID := primitive.NewObjectID()
+	existing := &entity.Review{ID: reviewID, UserID: "user-123", Rating: 4, Text: "Same text"}
+
+	reviewRepo.On("GetByID", ctx, reviewID.Hex()).Return(existing, nil)
+
+	result, err := service.UpdateReview(ctx, reviewID.Hex(), "user-123", &entity.UpdateReviewRequest{Rating: patch.Of(4)})
+
+	assert.NoError(t, err)
+	assert.False(t, result.Edited)
+	reviewRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateReview_EditWindowClosed(t *testing.T) {
+	reviewRepo := new(mocks.MockReviewRepository)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+	service := NewReviewService(reviewRepo, kafkaProducer, nil)
+	service.SetEditWindow(24 * time.Hour)
+
+	ctx := context.Background()
+	reviewID := primitive.NewObjectID()
+	existing := &entity.Review{ID: reviewID, UserID: "user-123", Rating: 4, CreatedAt: time.Now().Add(-25 * time.Hour)}
+
+	reviewRepo.On("GetByID", ctx, reviewID.Hex()).Return(existing, nil)
+
+	result, err := service.UpdateReview(ctx, reviewID.Hex(), "user-123", &entity.UpdateReviewRequest{Rating: patch.Of(1)})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrEditWindowClosed)
+	reviewRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetReviewHistory(t *testing.T) {
+	reviewID := primitive.NewObjectID()
+	written := time.Now().Add(-2 * time.Hour)
+	review := &entity.Review{
+		ID:        reviewID,
+		UserID:    "owner-user",
+		Rating:    5,
+		Text:      "New text",
+		UpdatedAt: time.Now(),
+		Edited:    true,
+		History:   []entity.ReviewVersion{{Rating: 2, Text: "Old text", WrittenAt: written, ReplacedAt: time.Now()}},
+	}
+
+	tests := []struct {
+		name      string
+		userID    string
+		moderator bool
+		wantErr   error
+	}{
+		{name: "author", userID: "owner-user"},
+		{name: "moderator", userID: "manager-user", moderator: true},
+		{name: "other user", userID: "another-user", wantErr: ErrUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reviewRepo := new(mocks.MockReviewRepository)
+			service := NewReviewService(reviewRepo, &mocks.MockMessagePublisher{}, nil)
+			ctx := context.Background()
+			reviewRepo.On("GetByID", ctx, reviewID.Hex()).Return(review, nil)
+
+			history, err := service.GetReviewHistory(ctx, reviewID.Hex(), tt.userID, tt.moderator)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, history)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, reviewID.Hex(), history.ReviewID)
+			assert.Equal(t, "New text", history.Current.Text)
+			assert.Len(t, history.Versions, 1)
+			assert.Equal(t, "Old text", history.Versions[0].Text)
+		})
+	}
 }
 
 func TestUpdateReview_NotFound(t *testing.T) {
 	reviewRepo := new(mocks.MockReviewRepository)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
-	service := NewReviewService(reviewRepo, kafkaProducer)
+	service := NewReviewService(reviewRepo, kafkaProducer, nil)
 
 	ctx := context.Background()
 	reviewID := primitive.NewObjectID().Hex()
 
 	reviewRepo.On("GetByID", ctx, reviewID).Return(nil, repository.ErrReviewNotFound)
 
-	result, err := service.UpdateReview(ctx, reviewID, "user-123", &entity.UpdateReviewRequest{Rating: 5})
+	result, err := service.UpdateReview(ctx, reviewID, "user-123", &entity.UpdateReviewRequest{Rating: patch.Of(5)})
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -183,7 +277,7 @@ func TestUpdateReview_NotFound(t *testing.T) {
 func TestUpdateReview_Unauthorized(t *testing.T) {
 	reviewRepo := new(mocks.MockReviewRepository)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
-	service := NewReviewService(reviewRepo, kafkaProducer)
+	service := NewReviewService(reviewRepo, kafkaProducer, nil)
 
 	ctx := context.Background()
 	reviewID := primitive.NewObjectID()
@@ -191,7 +285,7 @@ func TestUpdateReview_Unauthorized(t *testing.T) {
 
 	reviewRepo.On("GetByID", ctx, reviewID.Hex()).Return(existing, nil)
 
-	result, err := service.UpdateReview(ctx, reviewID.Hex(), "another-user", &entity.UpdateReviewRequest{Rating: 1})
+	result, err := service.UpdateReview(ctx, reviewID.Hex(), "another-user", &entity.UpdateReviewRequest{Rating: patch.Of(1)})
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -201,7 +295,7 @@ func TestUpdateReview_Unauthorized(t *testing.T) {
 func TestDeleteReview_Success(t *testing.T) {
 	reviewRepo := new(mocks.MockReviewRepository)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
-	service := NewReviewService(reviewRepo, kafkaProducer)
+	service := NewReviewService(reviewRepo, kafkaProducer, nil)
 
 	ctx := context.Background()
 	reviewID := primitive.NewObjectID()
@@ -219,7 +313,7 @@ func TestDeleteReview_Success(t *testing.T) {
 func TestDeleteReview_NotFound(t *testing.T) {
 	reviewRepo := new(mocks.MockReviewRepository)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
-	service := NewReviewService(reviewRepo, kafkaProducer)
+	service := NewReviewService(reviewRepo, kafkaProducer, nil)
 
 	ctx := context.Background()
 	reviewID := primitive.NewObjectID().Hex()
@@ -235,7 +329,7 @@ func TestDeleteReview_NotFound(t *testing.T) {
 func TestDeleteReview_Unauthorized(t *testing.T) {
 	reviewRepo := new(mocks.MockReviewRepository)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
-	service := NewReviewService(reviewRepo, kafkaProducer)
+	service := NewReviewService(reviewRepo, kafkaProducer, nil)
 
 	ctx := context.Background()
 	reviewID := primitive.NewObjectID()
@@ -252,7 +346,7 @@ func TestDeleteReview_Unauthorized(t *testing.T) {
 func TestGetUserReviews_Success(t *testing.T) {
 	reviewRepo := new(mocks.MockReviewRepository)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
-	service := NewReviewService(reviewRepo, kafkaProducer)
+	service := NewReviewService(reviewRepo, kafkaProducer, nil)
 
 	ctx := context.Background()
 	userID := "user-123"
@@ -261,24 +355,64 @@ func TestGetUserReviews_Success(t *testing.T) {
 		{ID: primitive.NewObjectID(), UserID: userID, ProductID: "product-2", Rating: 4, CreatedAt: time.Now()},
 	}
 
-	reviewRepo.On("GetByUserID", ctx, userID).Return(reviews, nil)
+	page := entity.Page{Limit: 20}
+	reviewRepo.On("GetByUserID", ctx, userID, page).Return(&entity.ReviewPage{Reviews: reviews, Total: 2}, nil)
 
-	result, err := service.GetUserReviews(ctx, userID)
+	result, err := service.GetUserReviews(ctx, userID, page)
 
 	assert.NoError(t, err)
-	assert.Len(t, result, 2)
+	assert.Len(t, result.Reviews, 2)
 }
 
 func TestGetUserReviews_Empty(t *testing.T) {
 	reviewRepo := new(mocks.MockReviewRepository)
 	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
-	service := NewReviewService(reviewRepo, kafkaProducer)
+	service := NewReviewService(reviewRepo, kafkaProducer, nil)
 
 	ctx := context.Background()
-	reviewRepo.On("GetByUserID", ctx, "no-reviews-user").Return([]entity.Review{}, nil)
+	page := entity.Page{Limit: 20}
+	reviewRepo.On("GetByUserID", ctx, "no-reviews-user", page).Return(&entity.ReviewPage{Reviews: []entity.Review{}}, nil)
 
-	result, err := service.GetUserReviews(ctx, "no-reviews-user")
+	result, err := service.GetUserReviews(ctx, "no-reviews-user", page)
 
 	assert.NoError(t, err)
-	assert.Empty(t, result)
+	assert.Empty(t, result.Reviews)
+}
+
+func TestCreateReview_RepoErrorDoesNotPublish(t *testing.T) {
+	reviewRepo := new(mocks.MockReviewRepository)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+	service := NewReviewService(reviewRepo, kafkaProducer, nil)
+
+	ctx := context.Background()
+	req := &entity.CreateReviewRequest{ProductID: "product-456", Rating: 4, Text: "Good product."}
+
+	reviewRepo.On("Create", ctx, mock.Anything).Return(errors.New("transaction aborted"))
+
+	_, err := service.CreateReview(ctx, "user-123", req)
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, reviewRepo.Transactions)
+	kafkaProducer.AssertNotCalled(t, "PublishMessage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateAndDeleteReview_UseTransaction(t *testing.T) {
+	reviewRepo := new(mocks.MockReviewRepository)
+	kafkaProducer := &mocks.MockMessagePublisher{Messages: make([][]byte, 0)}
+	service := NewReviewService(reviewRepo, kafkaProducer, nil)
+
+	ctx := context.Background()
+	reviewID := primitive.NewObjectID()
+	review := &entity.Review{ID: reviewID, UserID: "user-123", Rating: 3}
+
+	reviewRepo.On("GetByID", ctx, reviewID.Hex()).Return(review, nil)
+	reviewRepo.On("Update", ctx, mock.Anything, mock.Anything).Return(nil)
+	reviewRepo.On("Delete", ctx, reviewID.Hex()).Return(nil)
+
+	_, err := service.UpdateReview(ctx, reviewID.Hex(), "user-123", &entity.UpdateReviewRequest{Rating: patch.Of(5)})
+	assert.NoError(t, err)
+	err = service.DeleteReview(ctx, reviewID.Hex(), "user-123")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, reviewRepo.Transactions)
 }
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"augustberries/pkg/recovery"
+	"augustberries/reviews-service/internal/app/reviews/entity"
+	"augustberries/reviews-service/internal/app/reviews/infrastructure"
+	"augustberries/reviews-service/internal/app/reviews/repository"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Границы оценки, начиная с которых отзыв считается позитивным или негативным
+const (
+	positiveSentimentThreshold = 0.25
+	negativeSentimentThreshold = -0.25
+)
+
+// SentimentService оценивает тональность отзывов, у которых ее еще нет
+type SentimentService struct {
+	reviewRepo repository.ReviewRepository
+	analyzer   infrastructure.SentimentAnalyzer
+	batchSize  int
+	now        func() time.Time
+}
+
+func NewSentimentService(reviewRepo repository.ReviewRepository, analyzer infrastructure.SentimentAnalyzer, batchSize int) *SentimentService {
+	if batchSize < 1 {
+		batchSize = 100
+	}
+	return &SentimentService{
+		reviewRepo: reviewRepo,
+		analyzer:   analyzer,
+		batchSize:  batchSize,
+		now:        time.Now,
+	}
+}
+
+// AnalyzePending оценивает одну пачку отзывов без тональности и возвращает число сохраненных оценок
+// Ошибка анализатора на одном отзыве не останавливает пачку: отзыв будет обработан в следующий запуск
+func (s *SentimentService) AnalyzePending(ctx context.Context) (int, error) {
+	reviews, err := s.reviewRepo.ListWithoutSentiment(ctx, s.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list reviews: %w", err)
+	}
+
+	analyzed := 0
+	for i := range reviews {
+		review := &reviews[i]
+
+		score, err := s.analyzer.Analyze(ctx, review.Text)
+		if err != nil {
+			fmt.Printf("failed to analyze sentiment of review %s: %v\n", review.ID.Hex(), err)
+			continue
+		}
+
+		sentiment := &entity.ReviewSentiment{
+			Score:      score,
+			Label:      sentimentLabel(score),
+			Analyzer:   s.analyzer.Name(),
+			AnalyzedAt: s.now(),
+		}
+		if err := s.reviewRepo.SetSentiment(ctx, review.ID, review.UpdatedAt, sentiment); err != nil {
+			// Отзыв удален или отредактирован во время анализа
+			if errors.Is(err, repository.ErrReviewNotFound) {
+				continue
+			}
+			return analyzed, fmt.Errorf("failed to save sentiment: %w", err)
+		}
+		analyzed++
+	}
+
+	return analyzed, nil
+}
+
+// sentimentLabel переводит оценку в метку тональности
+func sentimentLabel(score float64) entity.SentimentLabel {
+	switch {
+	case score >= positiveSentimentThreshold:
+		return entity.SentimentPositive
+	case score <= negativeSentimentThreshold:
+		return entity.SentimentNegative
+	default:
+		return entity.SentimentNeutral
+	}
+}
+
+// SentimentJob периодически запускает анализ тональности новых и отредактированных отзывов
+type SentimentJob struct {
+	cron    *cron.Cron
+	service *SentimentService
+}
+
+// NewSentimentJob создает задание; запуски не накладываются друг на друга
+func NewSentimentJob(service *SentimentService) *SentimentJob {
+	c := cron.New(
+		cron.WithLogger(cron.VerbosePrintfLogger(log.Default())),
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)),
+	)
+
+	return &SentimentJob{
+		cron:    c,
+		service: service,
+	}
+}
+
+// Start запускает задание по расписанию
+func (j *SentimentJob) Start(ctx context.Context, schedule string) error {
+	log.Printf("Starting sentiment job with schedule: %s", schedule)
+
+	if _, err := j.cron.AddFunc(schedule, recovery.Wrap("reviews-service", "sentiment_job", func() { j.run(ctx) })); err != nil {
+		return err
+	}
+
+	j.cron.Start()
+	return nil
+}
+
+// Stop останавливает задание и ждет завершения текущего запуска
+func (j *SentimentJob) Stop() {
+	log.Println("Stopping sentiment job...")
+	<-j.cron.Stop().Done()
+	log.Println("Sentiment job stopped")
+}
+
+func (j *SentimentJob) run(ctx context.Context) {
+	analyzed, err := j.service.AnalyzePending(ctx)
+	if err != nil {
+		log.Printf("ERROR: Failed to analyze review sentiment: %v", err)
+	}
+	if analyzed > 0 {
+		log.Printf("Sentiment job: analyzed %d reviews", analyzed)
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"augustberries/reviews-service/internal/app/reviews/entity"
+	"augustberries/reviews-service/internal/app/reviews/repository"
+	"augustberries/reviews-service/internal/app/reviews/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// stubAnalyzer возвращает заранее заданные оценки по тексту отзыва
+type stubAnalyzer struct {
+	scores map[string]float64
+}
+
+func (a *stubAnalyzer) Name() string { return "stub" }
+
+func (a *stubAnalyzer) Analyze(_ context.Context, text string) (float64, error) {
+	score, ok := a.scores[text]
+	if !ok {
+		return 0, errors.New("analyzer unavailable")
+	}
+	return score, nil
+}
+
+// ==================== AnalyzePending Tests ====================
+
+func TestSentimentService_AnalyzePending_SavesScoresAndLabels(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockReviewRepository)
+	analyzer := &stubAnalyzer{scores: map[string]float64{"great": 0.8, "meh": 0.1, "awful": -0.9}}
+	svc := NewSentimentService(mockRepo, analyzer, 10)
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	updatedAt := time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC)
+	reviews := []entity.Review{
+		{ID: primitive.NewObjectID(), Text: "great", UpdatedAt: updatedAt},
+		{ID: primitive.NewObjectID(), Text: "meh", UpdatedAt: updatedAt},
+		{ID: primitive.NewObjectID(), Text: "awful", UpdatedAt: updatedAt},
+	}
+	mockRepo.On("ListWithoutSentiment", mock.Anything, 10).Return(reviews, nil)
+
+	expected := []entity.SentimentLabel{entity.SentimentPositive, entity.SentimentNeutral, entity.SentimentNegative}
+	for i, review := range reviews {
+		label := expected[i]
+		score := analyzer.scores[review.Text]
+		mockRepo.On("SetSentiment", mock.Anything, review.ID, updatedAt, &entity.ReviewSentiment{
+			Score: score, Label: label, Analyzer: "stub", AnalyzedAt: now,
+		}).Return(nil)
+	}
+
+	// Act
+	analyzed, err := svc.AnalyzePending(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 3, analyzed)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSentimentService_AnalyzePending_SkipsAnalyzerErrors(t *testing.T) {
+	mockRepo := new(mocks.MockReviewRepository)
+	svc := NewSentimentService(mockRepo, &stubAnalyzer{scores: map[string]float64{"ok": 0.5}}, 10)
+
+	failed := entity.Review{ID: primitive.NewObjectID(), Text: "timeout"}
+	ok := entity.Review{ID: primitive.NewObjectID(), Text: "ok"}
+	mockRepo.On("ListWithoutSentiment", mock.Anything, 10).Return([]entity.Review{failed, ok}, nil)
+	mockRepo.On("SetSentiment", mock.Anything, ok.ID, mock.Anything, mock.Anything).Return(nil)
+
+	analyzed, err := svc.AnalyzePending(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, analyzed)
+	mockRepo.AssertNotCalled(t, "SetSentiment", mock.Anything, failed.ID, mock.Anything, mock.Anything)
+}
+
+func TestSentimentService_AnalyzePending_IgnoresEditedReview(t *testing.T) {
+	// Отзыв отредактирован во время анализа: оценка старого текста не сохраняется
+	mockRepo := new(mocks.MockReviewRepository)
+	svc := NewSentimentService(mockRepo, &stubAnalyzer{scores: map[string]float64{"old text": 1}}, 10)
+
+	review := entity.Review{ID: primitive.NewObjectID(), Text: "old text"}
+	mockRepo.On("ListWithoutSentiment", mock.Anything, 10).Return([]entity.Review{review}, nil)
+	mockRepo.On("SetSentiment", mock.Anything, review.ID, mock.Anything, mock.Anything).Return(repository.ErrReviewNotFound)
+
+	analyzed, err := svc.AnalyzePending(context.Background())
+
+	require.NoError(t, err)
+	assert.Zero(t, analyzed)
+}
+
+func TestSentimentService_AnalyzePending_ListError(t *testing.T) {
+	mockRepo := new(mocks.MockReviewRepository)
+	svc := NewSentimentService(mockRepo, &stubAnalyzer{}, 10)
+
+	mockRepo.On("ListWithoutSentiment", mock.Anything, 10).Return(nil, errors.New("db error"))
+
+	_, err := svc.AnalyzePending(context.Background())
+
+	assert.Error(t, err)
+}
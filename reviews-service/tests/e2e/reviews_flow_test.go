@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"augustberries/pkg/patch"
 	"augustberries/reviews-service/internal/app/reviews/entity"
 
 	"github.com/stretchr/testify/assert"
@@ -70,7 +71,7 @@ func TestFullReviewFlow(t *testing.T) {
 	assert.Equal(t, 1, listResp.Total)
 
 	// Update
-	updateReq := entity.UpdateReviewRequest{Rating: 5, Text: "Updated: excellent!"}
+	updateReq := entity.UpdateReviewRequest{Rating: patch.Of(5), Text: patch.Of("Updated: excellent!")}
 	body, _ = json.Marshal(updateReq)
 
 	req, _ = http.NewRequest(http.MethodPatch, BaseURL+"/reviews/"+reviewID, bytes.NewBuffer(body))
@@ -101,7 +102,7 @@ func TestGetNonExistentProductReviews(t *testing.T) {
 func TestUpdateNonExistentReview(t *testing.T) {
 	client := &http.Client{Timeout: 10 * time.Second}
 
-	updateReq := entity.UpdateReviewRequest{Rating: 5}
+	updateReq := entity.UpdateReviewRequest{Rating: patch.Of(5)}
 	body, _ := json.Marshal(updateReq)
 
 	req, _ := http.NewRequest(http.MethodPatch, BaseURL+"/reviews/"+primitive.NewObjectID().Hex(), bytes.NewBuffer(body))
@@ -211,7 +212,7 @@ func TestInvalidObjectID(t *testing.T) {
 
 	for _, invalidID := range invalidIDs {
 		t.Run("Update_"+invalidID, func(t *testing.T) {
-			updateReq := entity.UpdateReviewRequest{Rating: 5}
+			updateReq := entity.UpdateReviewRequest{Rating: patch.Of(5)}
 			body, _ := json.Marshal(updateReq)
 
 			req, _ := http.NewRequest(http.MethodPatch, BaseURL+"/reviews/"+invalidID, bytes.NewBuffer(body))
@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"augustberries/pkg/patch"
 	"augustberries/reviews-service/internal/app/reviews/entity"
 	"augustberries/reviews-service/internal/app/reviews/handler"
 	"augustberries/reviews-service/internal/app/reviews/repository"
@@ -68,7 +69,7 @@ func (s *ReviewsIntegrationTestSuite) SetupSuite() {
 
 	reviewRepo := repository.NewReviewRepository(s.db)
 	s.kafkaProducer = &MockKafkaProducer{Messages: make([][]byte, 0)}
-	s.reviewService = service.NewReviewService(reviewRepo, s.kafkaProducer)
+	s.reviewService = service.NewReviewService(reviewRepo, s.kafkaProducer, nil)
 
 	s.testUserID = "test-user-" + primitive.NewObjectID().Hex()
 	s.testProductID = "test-product-" + primitive.NewObjectID().Hex()
@@ -166,7 +167,7 @@ func (s *ReviewsIntegrationTestSuite) TestUpdateReview_Success() {
 	var created entity.Review
 	json.Unmarshal(w.Body.Bytes(), &created)
 
-	updateReq := entity.UpdateReviewRequest{Rating: 5, Text: "Updated: great product!"}
+	updateReq := entity.UpdateReviewRequest{Rating: patch.Of(5), Text: patch.Of("Updated: great product!")}
 	body, _ = json.Marshal(updateReq)
 	req, _ = http.NewRequest(http.MethodPatch, "/reviews/"+created.ID.Hex(), bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")